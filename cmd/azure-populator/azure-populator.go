@@ -0,0 +1,423 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	libclient "github.com/kubev2v/forklift/pkg/lib/client/azure"
+	"github.com/kubev2v/forklift/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/klog/v2"
+)
+
+type AppConfig struct {
+	endpoint    string
+	diskID      string
+	migration   string
+	crNamespace string
+	crName      string
+	secretName  string
+	ownerUID    string
+	pvcSize     int64
+	volumePath  string
+}
+
+const (
+	// Ranges read (and written) concurrently.
+	workers = 8
+	// Maximum length (bytes) of a range read.
+	chunkSize = 4 * 1024 * 1024
+	// Duration of the (SAS) access to a snapshot.
+	accessDuration = 4 * time.Hour
+)
+
+// Interval between the snapshot lookups.
+var pollInterval = libclient.PollInterval
+
+func main() {
+	config := &AppConfig{}
+	flag.StringVar(&config.endpoint, "endpoint", "", "Azure resource manager endpoint URL (https://management.azure.com)")
+	flag.StringVar(&config.secretName, "secret-name", "", "secret containing the Azure credentials")
+	flag.StringVar(&config.diskID, "disk-id", "", "Managed disk (resource) ID")
+	flag.StringVar(&config.migration, "migration", "", "Migration UID the snapshots are tagged with")
+	flag.StringVar(&config.volumePath, "volume-path", "", "Path to populate")
+	flag.StringVar(&config.crName, "cr-name", "", "Custom Resource instance name")
+	flag.StringVar(&config.crNamespace, "cr-namespace", "", "Custom Resource instance namespace")
+	flag.StringVar(&config.ownerUID, "owner-uid", "", "Owner UID (usually PVC UID)")
+	flag.Int64Var(&config.pvcSize, "pvc-size", 0, "Size of pvc (in bytes)")
+	flag.Parse()
+
+	if config.pvcSize <= 0 {
+		klog.Fatal("pvc-size must be greater than 0")
+	}
+
+	certsDirectory, err := os.MkdirTemp("", "certsdir")
+	if err != nil {
+		klog.Fatal(err)
+	}
+
+	metrics.StartPrometheusEndpoint(certsDirectory)
+
+	populate(config)
+}
+
+func populate(config *AppConfig) {
+	client := createClient(config)
+	copySnapshots(client, config, createProgressGauge())
+}
+
+func createClient(config *AppConfig) *libclient.Client {
+	return &libclient.Client{
+		URL:     config.endpoint,
+		Options: readOptions(),
+	}
+}
+
+// Copy the snapshots of the disk taken by the migration.
+// The first snapshot is copied in full and each following
+// (incremental) snapshot is copied as the diff with the previous one.
+// The copied snapshots are tagged so that a restarted populator
+// resumes with the first snapshot not yet copied. The population
+// is done once the final snapshot has been copied.
+func copySnapshots(client *libclient.Client, config *AppConfig, progress *prometheus.GaugeVec) {
+	klog.Info("Copying the snapshots of disk: ", config.diskID, " migration: ", config.migration)
+	file := openFile(config.volumePath)
+	defer file.Close()
+
+	for {
+		previous, next, err := findSnapshots(client, config)
+		if err != nil {
+			klog.Fatal(err)
+		}
+		if next == nil {
+			if previous != nil && previous.Tags[libclient.TagFinal] == "true" {
+				break
+			}
+			time.Sleep(pollInterval)
+			continue
+		}
+		if !next.Ready() {
+			klog.Info("Waiting for the snapshot: ", next.Name)
+			time.Sleep(pollInterval)
+			continue
+		}
+		copier := &Copier{
+			client: client,
+			file:   file,
+		}
+		done := make(chan bool)
+		finished := make(chan bool)
+		go func() {
+			reportProgress(done, copier, progress, config.ownerUID)
+			finished <- true
+		}()
+		err = copier.copy(previous, next, config.volumePath)
+		done <- true
+		<-finished
+		if err != nil {
+			klog.Fatal(err)
+		}
+		err = markCopied(client, next)
+		if err != nil {
+			klog.Fatal(err)
+		}
+		klog.Info("Copied the snapshot: ", next.Name)
+		if next.Tags[libclient.TagFinal] == "true" {
+			break
+		}
+	}
+
+	klog.Info("Finished populating the volume.")
+}
+
+// Find the last copied snapshot and the next snapshot to be copied.
+// The snapshots are ordered by the precopy they were taken by.
+func findSnapshots(client *libclient.Client, config *AppConfig) (previous, next *libclient.Snapshot, err error) {
+	list, err := client.FindSnapshots(
+		libclient.ResourceGroupOf(config.diskID),
+		map[string]string{
+			libclient.TagMigration: config.migration,
+			libclient.TagDisk:      libclient.ID(config.diskID),
+		})
+	if err != nil {
+		return
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return precopy(&list[i]) < precopy(&list[j])
+	})
+	for i := range list {
+		snapshot := &list[i]
+		if snapshot.Tags[libclient.TagCopied] == "true" {
+			previous = snapshot
+			continue
+		}
+		next = snapshot
+		break
+	}
+	return
+}
+
+// The precopy (number) the snapshot was taken by.
+func precopy(snapshot *libclient.Snapshot) (n int) {
+	n, _ = strconv.Atoi(snapshot.Tags[libclient.TagPrecopy])
+	return
+}
+
+// Tag the snapshot as copied.
+func markCopied(client *libclient.Client, snapshot *libclient.Snapshot) (err error) {
+	tags := map[string]string{}
+	for key, value := range snapshot.Tags {
+		tags[key] = value
+	}
+	tags[libclient.TagCopied] = "true"
+	err = client.TagSnapshot(snapshot.ID, tags)
+	return
+}
+
+// Copies the (changed) ranges of a snapshot to the volume.
+type Copier struct {
+	client *libclient.Client
+	file   *os.File
+	// Bytes to be copied.
+	total int64
+	// Bytes copied.
+	copied int64
+}
+
+// Copy the snapshot.
+// Only the ranges changed since the previous snapshot are copied
+// and the ranges cleared since are zeroed.
+func (r *Copier) copy(previous, next *libclient.Snapshot, volumePath string) (err error) {
+	sas, err := r.client.GrantAccess(next.ID, accessDuration)
+	if err != nil {
+		return
+	}
+	defer func() {
+		rErr := r.client.RevokeAccess(next.ID)
+		if rErr != nil {
+			klog.Error("Failed to revoke the access to snapshot: ", next.Name, " ", rErr)
+		}
+	}()
+	previousSAS := ""
+	if previous != nil {
+		previousSAS, err = r.client.GrantAccess(previous.ID, accessDuration)
+		if err != nil {
+			return
+		}
+		defer func() {
+			rErr := r.client.RevokeAccess(previous.ID)
+			if rErr != nil {
+				klog.Error("Failed to revoke the access to snapshot: ", previous.Name, " ", rErr)
+			}
+		}()
+	}
+	err = r.prepare(next.Properties.DiskSizeBytes, volumePath)
+	if err != nil {
+		return
+	}
+	ranges := []libclient.PageRange{}
+	cleared := []libclient.PageRange{}
+	marker := ""
+	for {
+		var list *libclient.PageList
+		list, err = r.client.GetPageRanges(sas, previousSAS, marker)
+		if err != nil {
+			return
+		}
+		ranges = append(ranges, list.PageRanges...)
+		cleared = append(cleared, list.ClearRanges...)
+		if list.NextMarker == "" {
+			break
+		}
+		marker = list.NextMarker
+	}
+	chunks := split(ranges)
+	for _, chunk := range chunks {
+		r.total += chunk.Length()
+	}
+	klog.Info("Copying the snapshot: ", next.Name, " ranges: ", len(chunks), " bytes: ", r.total)
+	err = r.clear(cleared)
+	if err != nil {
+		return
+	}
+	err = r.copyRanges(sas, chunks)
+	if err != nil {
+		return
+	}
+	err = r.file.Sync()
+	return
+}
+
+// Extend the (file) volume to the disk size.
+func (r *Copier) prepare(size int64, volumePath string) (err error) {
+	if size == 0 || !strings.HasSuffix(volumePath, "disk.img") {
+		return
+	}
+	info, err := r.file.Stat()
+	if err != nil {
+		return
+	}
+	if info.Size() < size {
+		err = r.file.Truncate(size)
+	}
+	return
+}
+
+// Zero the cleared ranges.
+func (r *Copier) clear(ranges []libclient.PageRange) (err error) {
+	zeros := make([]byte, chunkSize)
+	for _, chunk := range split(ranges) {
+		_, err = r.file.WriteAt(zeros[:chunk.Length()], chunk.Start)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Copy the ranges concurrently.
+func (r *Copier) copyRanges(sas string, ranges []libclient.PageRange) (err error) {
+	queue := make(chan libclient.PageRange)
+	errs := make(chan error, workers)
+	wg := sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for chunk := range queue {
+				wErr := r.write(sas, chunk)
+				if wErr != nil {
+					errs <- wErr
+					for range queue {
+					}
+					return
+				}
+			}
+		}()
+	}
+	for _, chunk := range ranges {
+		queue <- chunk
+	}
+	close(queue)
+	wg.Wait()
+	close(errs)
+	err = <-errs
+	return
+}
+
+// Read a range and write it to the volume.
+func (r *Copier) write(sas string, chunk libclient.PageRange) (err error) {
+	data, err := r.client.ReadRange(sas, chunk.Start, chunk.Length())
+	if err != nil {
+		return
+	}
+	_, err = r.file.WriteAt(data, chunk.Start)
+	if err != nil {
+		return
+	}
+	atomic.AddInt64(&r.copied, int64(len(data)))
+	return
+}
+
+// Percent of the snapshot copied.
+func (r *Copier) Percent() float64 {
+	if r.total == 0 {
+		return 0
+	}
+	return float64(atomic.LoadInt64(&r.copied)) / float64(r.total) * 100
+}
+
+// Split the ranges into chunks of (at most) the chunk size.
+func split(ranges []libclient.PageRange) (chunks []libclient.PageRange) {
+	for _, pageRange := range ranges {
+		for start := pageRange.Start; start <= pageRange.End; start += chunkSize {
+			chunks = append(
+				chunks,
+				libclient.PageRange{
+					Start: start,
+					End:   min(start+chunkSize-1, pageRange.End),
+				})
+		}
+	}
+	return
+}
+
+func createProgressGauge() *prometheus.GaugeVec {
+	progressVec := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "azure_populator_progress",
+			Help: "Progress of the snapshot copy",
+		},
+		[]string{"ownerUID"},
+	)
+
+	if err := prometheus.Register(progressVec); err != nil {
+		klog.Error("Prometheus progress gauge not registered:", err)
+	}
+
+	return progressVec
+}
+
+func openFile(volumePath string) *os.File {
+	flags := os.O_RDWR
+	if strings.HasSuffix(volumePath, "disk.img") {
+		flags |= os.O_CREATE
+	}
+	file, err := os.OpenFile(volumePath, flags, 0650)
+	if err != nil {
+		klog.Fatal(err)
+	}
+	return file
+}
+
+// Report the progress of the snapshot copy.
+// The progress restarts with each snapshot.
+func reportProgress(done chan bool, copier *Copier, progress *prometheus.GaugeVec, ownerUID string) {
+	progress.WithLabelValues(ownerUID).Set(0)
+	for {
+		select {
+		case <-done:
+			progress.WithLabelValues(ownerUID).Set(100)
+			return
+		default:
+			percent := copier.Percent()
+			progress.WithLabelValues(ownerUID).Set(percent)
+			klog.Info("Progress: ", int64(percent), "%")
+			time.Sleep(1 * time.Second)
+		}
+	}
+}
+
+func readOptions() map[string]string {
+	options := map[string]string{}
+
+	// List of options to read from environment variables
+	envOptions := []string{
+		libclient.TenantID,
+		libclient.ClientID,
+		libclient.ClientSecret,
+		libclient.SubscriptionID,
+		libclient.AuthorityHost,
+	}
+
+	klog.Info("Options:")
+	for _, option := range envOptions {
+		value := os.Getenv(option)
+		options[option] = value
+		if sensitiveInfo(option) {
+			value = strings.Repeat("*", len(value))
+		}
+		klog.Info(" - ", option, " = ", value)
+	}
+	return options
+}
+
+func sensitiveInfo(option string) bool {
+	return option == libclient.ClientSecret
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	libclient "github.com/kubev2v/forklift/pkg/lib/client/azure"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	diskID    = "/subscriptions/sub-1/resourceGroups/group-1/providers/Microsoft.Compute/disks/disk-1"
+	snapshots = "/subscriptions/sub-1/resourceGroups/group-1/providers/Microsoft.Compute/snapshots"
+	diskSize  = 2048
+)
+
+// Mock resource manager and blob server.
+// The initial snapshot has ranges 0-511 and 1024-1535 allocated. The final
+// snapshot changed the range 512-1023 and cleared the range 1024-1535.
+func setupMockServer(t *testing.T) (client *libclient.Client, tags map[string]map[string]string) {
+	disk := libclient.ID(diskID)
+	tags = map[string]map[string]string{
+		"snap-0": {
+			libclient.TagMigration: "migration-1",
+			libclient.TagDisk:      disk,
+			libclient.TagPrecopy:   "0",
+		},
+		"snap-1": {
+			libclient.TagMigration: "migration-1",
+			libclient.TagDisk:      disk,
+			libclient.TagPrecopy:   "1",
+			libclient.TagFinal:     "true",
+		},
+		"snap-other": {
+			libclient.TagMigration: "migration-2",
+			libclient.TagDisk:      disk,
+			libclient.TagPrecopy:   "0",
+		},
+	}
+	mutex := sync.Mutex{}
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	mux.HandleFunc("/tenant-1/oauth2/v2.0/token", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"access_token":"token-1","expires_in":3600}`))
+	})
+	mux.HandleFunc(snapshots, func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		list := []libclient.Snapshot{}
+		// Listed in reverse order.
+		for _, name := range []string{"snap-other", "snap-1", "snap-0"} {
+			snapshot := libclient.Snapshot{
+				ID:   snapshots + "/" + name,
+				Name: name,
+				Tags: tags[name],
+			}
+			snapshot.Properties.DiskSizeBytes = diskSize
+			snapshot.Properties.ProvisioningState = libclient.ProvisioningSucceeded
+			list = append(list, snapshot)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"value": list})
+	})
+	mux.HandleFunc(snapshots+"/", func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		path := strings.TrimPrefix(r.URL.Path, snapshots+"/")
+		name, action, _ := strings.Cut(path, "/")
+		switch {
+		case r.Method == http.MethodPatch:
+			in := struct {
+				Tags map[string]string `json:"tags"`
+			}{}
+			_ = json.NewDecoder(r.Body).Decode(&in)
+			tags[name] = in.Tags
+		case action == "beginGetAccess":
+			reply := map[string]string{"accessSAS": server.URL + "/blob/" + name + "?sig=secret"}
+			_ = json.NewEncoder(w).Encode(reply)
+		case action == "endGetAccess":
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	mux.HandleFunc("/blob/", func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, "/blob/")
+		if r.URL.Query().Get("comp") == "pagelist" {
+			list := libclient.PageList{}
+			switch name {
+			case "snap-0":
+				list.PageRanges = []libclient.PageRange{{Start: 0, End: 511}, {Start: 1024, End: 1535}}
+			case "snap-1":
+				if !strings.HasSuffix(r.Header.Get("x-ms-previous-snapshot-url"), "/blob/snap-0?sig=secret") {
+					t.Errorf("unexpected previous snapshot: %s", r.Header.Get("x-ms-previous-snapshot-url"))
+				}
+				list.PageRanges = []libclient.PageRange{{Start: 512, End: 1023}}
+				list.ClearRanges = []libclient.PageRange{{Start: 1024, End: 1535}}
+			}
+			_ = xml.NewEncoder(w).Encode(list)
+			return
+		}
+		var start, end int
+		_, _ = fmt.Sscanf(r.Header.Get("x-ms-range"), "bytes=%d-%d", &start, &end)
+		_, _ = w.Write(bytes.Repeat([]byte(name[len(name)-1:]), end-start+1))
+	})
+	client = &libclient.Client{
+		URL: server.URL,
+		Options: map[string]string{
+			libclient.TenantID:       "tenant-1",
+			libclient.SubscriptionID: "sub-1",
+			libclient.AuthorityHost:  server.URL,
+		},
+	}
+	return
+}
+
+func TestCopySnapshots(t *testing.T) {
+	client, tags := setupMockServer(t)
+	fileName := filepath.Join(t.TempDir(), "disk.img")
+	config := &AppConfig{
+		diskID:     diskID,
+		migration:  "migration-1",
+		ownerUID:   "test-uid",
+		pvcSize:    diskSize,
+		volumePath: fileName,
+	}
+	progress := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "progress"}, []string{"ownerUID"})
+	copySnapshots(client, config, progress)
+
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	if len(content) != diskSize {
+		t.Fatalf("Expected size %d, got %d", diskSize, len(content))
+	}
+	for index, expected := range []byte{'0', '1', 0, 0} {
+		chunk := content[index*512 : (index+1)*512]
+		if !bytes.Equal(chunk, bytes.Repeat([]byte{expected}, 512)) {
+			t.Errorf("Range %d: unexpected content", index)
+		}
+	}
+	for _, name := range []string{"snap-0", "snap-1"} {
+		if tags[name][libclient.TagCopied] != "true" {
+			t.Errorf("Expected snapshot %s to be tagged as copied", name)
+		}
+	}
+	if tags["snap-other"][libclient.TagCopied] != "" {
+		t.Errorf("Expected the snapshot of the other migration to not be copied")
+	}
+}
+
+func TestSplit(t *testing.T) {
+	chunks := split([]libclient.PageRange{{Start: 0, End: chunkSize*2 + 9}})
+	if len(chunks) != 3 {
+		t.Fatalf("Expected 3 chunks, got %d", len(chunks))
+	}
+	if chunks[1].Start != chunkSize || chunks[1].Length() != chunkSize || chunks[2].Length() != 10 {
+		t.Errorf("Unexpected chunks: %v", chunks)
+	}
+}
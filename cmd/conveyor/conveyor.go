@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"flag"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/kubev2v/forklift/pkg/lib/conveyor"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+)
+
+// Source-side conveyor appliance.
+// Runs inside the source datacenter and connects (outbound) to the
+// conveyor gateway served by the forklift inventory. The connections
+// to the source endpoints requested by the cluster are dialed locally.
+func main() {
+	log := logging.WithName("conveyor")
+	var url, name, token, ca, allowed string
+	var insecure bool
+	flag.StringVar(&url, "url", os.Getenv("CONVEYOR_URL"), "Gateway (tunnel) URL. Example: https://conveyor.apps.example.com/conveyor")
+	flag.StringVar(&name, "name", os.Getenv("CONVEYOR_NAME"), "Conveyor name used as the provider proxy user.")
+	flag.StringVar(&token, "token", os.Getenv("CONVEYOR_TOKEN"), "Token used to authenticate with the gateway.")
+	flag.StringVar(&ca, "ca", os.Getenv("CONVEYOR_CA"), "CA bundle (path) used to verify the gateway certificate.")
+	flag.StringVar(&allowed, "allow", os.Getenv("CONVEYOR_ALLOW"), "Allowed source addresses (comma separated): CIDRs, hosts and .domains.")
+	flag.BoolVar(&insecure, "insecure", os.Getenv("CONVEYOR_INSECURE") == "true", "Skip the verification of the gateway certificate.")
+	flag.Parse()
+	if url == "" || name == "" || token == "" {
+		log.Info("The url, name and token are required.")
+		os.Exit(2)
+	}
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: insecure,
+	}
+	if ca != "" {
+		pem, err := os.ReadFile(ca)
+		if err != nil {
+			log.Error(err, "CA bundle not read.", "path", ca)
+			os.Exit(1)
+		}
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+			log.Info("CA bundle not valid.", "path", ca)
+			os.Exit(1)
+		}
+	}
+	agent := &conveyor.Agent{
+		URL:     url,
+		Name:    name,
+		Token:   token,
+		TLS:     cfg,
+		Allowed: strings.Split(allowed, ","),
+	}
+	if allowed == "" {
+		log.Info("No source addresses are allowed.")
+	}
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	log.Info("Started.", "url", url, "name", name)
+	agent.Run(ctx)
+	log.Info("Stopped.")
+}
@@ -0,0 +1,348 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	libclient "github.com/kubev2v/forklift/pkg/lib/client/ec2"
+	"github.com/kubev2v/forklift/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"k8s.io/klog/v2"
+)
+
+type AppConfig struct {
+	endpoint    string
+	snapshotID  string
+	crNamespace string
+	crName      string
+	secretName  string
+	ownerUID    string
+	pvcSize     int64
+	volumePath  string
+	offset      int64
+}
+
+// Blocks read (and written) concurrently.
+const workers = 8
+
+func main() {
+	config := &AppConfig{}
+	flag.StringVar(&config.endpoint, "endpoint", "", "EC2 endpoint URL (https://ec2.us-east-1.amazonaws.com)")
+	flag.StringVar(&config.secretName, "secret-name", "", "secret containing the AWS credentials")
+	flag.StringVar(&config.snapshotID, "snapshot-id", "", "EBS snapshot ID")
+	flag.StringVar(&config.volumePath, "volume-path", "", "Path to populate")
+	flag.StringVar(&config.crName, "cr-name", "", "Custom Resource instance name")
+	flag.StringVar(&config.crNamespace, "cr-namespace", "", "Custom Resource instance namespace")
+	flag.StringVar(&config.ownerUID, "owner-uid", "", "Owner UID (usually PVC UID)")
+	flag.Int64Var(&config.pvcSize, "pvc-size", 0, "Size of pvc (in bytes)")
+	flag.Int64Var(&config.offset, "offset", 0, "Offset (in bytes) of the data already synced to the volume")
+	flag.Parse()
+
+	if config.pvcSize <= 0 {
+		klog.Fatal("pvc-size must be greater than 0")
+	}
+
+	certsDirectory, err := os.MkdirTemp("", "certsdir")
+	if err != nil {
+		klog.Fatal(err)
+	}
+
+	metrics.StartPrometheusEndpoint(certsDirectory)
+
+	populate(config)
+}
+
+func populate(config *AppConfig) {
+	client := createClient(config)
+	copySnapshot(client, config, createProgressCounter(), createOffsetGauge())
+}
+
+func createClient(config *AppConfig) *libclient.Client {
+	return &libclient.Client{
+		URL:     config.endpoint,
+		Options: readOptions(),
+	}
+}
+
+// Copy the (allocated) blocks of the snapshot to the volume.
+// The blocks are listed by page; the volume is synced after
+// each page and a restarted populator resumes from the synced offset.
+func copySnapshot(client *libclient.Client, config *AppConfig, progress *prometheus.CounterVec, offset *prometheus.GaugeVec) {
+	klog.Info("Copying the snapshot: ", config.snapshotID, " from offset: ", config.offset)
+	file := openFile(config.volumePath)
+	defer file.Close()
+
+	copier := &Copier{
+		client:     client,
+		file:       file,
+		snapshotID: config.snapshotID,
+		synced:     config.offset,
+		position:   config.offset,
+	}
+	done := make(chan bool)
+	finished := make(chan bool)
+	go func() {
+		reportProgress(done, copier, progress, offset, config)
+		finished <- true
+	}()
+
+	token := ""
+	for {
+		blocks, err := client.ListSnapshotBlocks(config.snapshotID, token)
+		if err != nil {
+			klog.Fatal(err)
+		}
+		if atomic.LoadInt64(&copier.total) == 0 {
+			copier.prepare(blocks, config.volumePath)
+		}
+		err = copier.copy(blocks)
+		if err != nil {
+			klog.Fatal(err)
+		}
+		if blocks.NextToken == "" {
+			break
+		}
+		token = blocks.NextToken
+	}
+	atomic.StoreInt64(&copier.position, atomic.LoadInt64(&copier.total))
+	done <- true
+	<-finished
+}
+
+// Copies snapshot blocks to the volume.
+type Copier struct {
+	client     *libclient.Client
+	file       *os.File
+	snapshotID string
+	// Block size (bytes).
+	blockSize int64
+	// Volume size (bytes).
+	total int64
+	// Offset of the data synced to the volume.
+	synced int64
+	// Offset of the last block written.
+	position int64
+}
+
+// Set the sizes reported with the first page.
+// The (file) volume is extended to the snapshot volume size.
+func (r *Copier) prepare(blocks *libclient.Blocks, volumePath string) {
+	r.blockSize = blocks.BlockSize
+	atomic.StoreInt64(&r.total, blocks.VolumeSize*(1<<30))
+	if !strings.HasSuffix(volumePath, "disk.img") {
+		return
+	}
+	info, err := r.file.Stat()
+	if err != nil {
+		klog.Fatal(err)
+	}
+	if info.Size() < r.total {
+		err = r.file.Truncate(r.total)
+		if err != nil {
+			klog.Fatal(err)
+		}
+	}
+}
+
+// Copy a page of blocks.
+// Blocks already synced are skipped.
+func (r *Copier) copy(blocks *libclient.Blocks) (err error) {
+	if len(blocks.Blocks) == 0 {
+		return
+	}
+	synced := r.Synced()
+	queue := make(chan libclient.Block)
+	errs := make(chan error, workers)
+	wg := sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for block := range queue {
+				wErr := r.write(block)
+				if wErr != nil {
+					errs <- wErr
+					for range queue {
+					}
+					return
+				}
+			}
+		}()
+	}
+	for _, block := range blocks.Blocks {
+		if (block.Index+1)*r.blockSize <= synced {
+			continue
+		}
+		queue <- block
+	}
+	close(queue)
+	wg.Wait()
+	close(errs)
+	err = <-errs
+	if err != nil {
+		return
+	}
+	err = r.file.Sync()
+	if err != nil {
+		return
+	}
+	last := blocks.Blocks[len(blocks.Blocks)-1]
+	atomic.StoreInt64(&r.synced, max(synced, (last.Index+1)*r.blockSize))
+	return
+}
+
+// Read a block and write it to the volume.
+func (r *Copier) write(block libclient.Block) (err error) {
+	data, err := r.client.ReadSnapshotBlock(r.snapshotID, block)
+	if err != nil {
+		return
+	}
+	offset := block.Index * r.blockSize
+	_, err = r.file.WriteAt(data, offset)
+	if err != nil {
+		return
+	}
+	end := offset + int64(len(data))
+	for {
+		position := atomic.LoadInt64(&r.position)
+		if end <= position || atomic.CompareAndSwapInt64(&r.position, position, end) {
+			break
+		}
+	}
+	return
+}
+
+// Synced offset.
+func (r *Copier) Synced() int64 {
+	return atomic.LoadInt64(&r.synced)
+}
+
+func createProgressCounter() *prometheus.CounterVec {
+	progressVec := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ec2_populator_progress",
+			Help: "Progress of volume population",
+		},
+		[]string{"ownerUID"},
+	)
+
+	if err := prometheus.Register(progressVec); err != nil {
+		klog.Error("Prometheus progress counter not registered:", err)
+	}
+
+	return progressVec
+}
+
+func createOffsetGauge() *prometheus.GaugeVec {
+	offsetVec := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "ec2_populator_offset",
+			Help: "Offset of the data synced to the volume",
+		},
+		[]string{"ownerUID"},
+	)
+
+	if err := prometheus.Register(offsetVec); err != nil {
+		klog.Error("Prometheus offset gauge not registered:", err)
+	}
+
+	return offsetVec
+}
+
+func openFile(volumePath string) *os.File {
+	flags := os.O_RDWR
+	if strings.HasSuffix(volumePath, "disk.img") {
+		flags |= os.O_CREATE
+	}
+	file, err := os.OpenFile(volumePath, flags, 0650)
+	if err != nil {
+		klog.Fatal(err)
+	}
+	return file
+}
+
+func reportProgress(done chan bool, copier *Copier, progress *prometheus.CounterVec, offset *prometheus.GaugeVec, config *AppConfig) {
+	for {
+		select {
+		case <-done:
+			offset.WithLabelValues(config.ownerUID).Set(float64(copier.Synced()))
+			finalizeProgress(progress, config.ownerUID)
+			return
+		default:
+			offset.WithLabelValues(config.ownerUID).Set(float64(copier.Synced()))
+			updateProgress(copier, progress, config.ownerUID)
+			time.Sleep(1 * time.Second)
+		}
+	}
+}
+
+func finalizeProgress(progress *prometheus.CounterVec, ownerUID string) {
+	currentVal := progress.WithLabelValues(ownerUID)
+
+	var metric dto.Metric
+	if err := currentVal.Write(&metric); err != nil {
+		klog.Error("Error reading current progress:", err)
+		return
+	}
+
+	if metric.Counter != nil {
+		remainingProgress := 100 - *metric.Counter.Value
+		if remainingProgress > 0 {
+			currentVal.Add(remainingProgress)
+		}
+	}
+
+	klog.Info("Finished populating the volume. Progress: 100%")
+}
+
+func updateProgress(copier *Copier, progress *prometheus.CounterVec, ownerUID string) {
+	total := atomic.LoadInt64(&copier.total)
+	if total <= 0 {
+		return
+	}
+
+	metric := &dto.Metric{}
+	if err := progress.WithLabelValues(ownerUID).Write(metric); err != nil {
+		klog.Errorf("updateProgress: failed to write metric; %v", err)
+	}
+
+	currentProgress := (float64(atomic.LoadInt64(&copier.position)) / float64(total)) * 100
+
+	if currentProgress > *metric.Counter.Value {
+		progress.WithLabelValues(ownerUID).Add(currentProgress - *metric.Counter.Value)
+	}
+
+	klog.Info("Progress: ", int64(currentProgress), "%")
+}
+
+func readOptions() map[string]string {
+	options := map[string]string{}
+
+	// List of options to read from environment variables
+	envOptions := []string{
+		libclient.AccessKeyID,
+		libclient.SecretAccessKey,
+		libclient.SessionToken,
+		libclient.Region,
+	}
+
+	klog.Info("Options:")
+	for _, option := range envOptions {
+		value := os.Getenv(option)
+		options[option] = value
+		if sensitiveInfo(option) {
+			value = strings.Repeat("*", len(value))
+		}
+		klog.Info(" - ", option, " = ", value)
+	}
+	return options
+}
+
+func sensitiveInfo(option string) bool {
+	return option == libclient.SecretAccessKey || option == libclient.SessionToken
+}
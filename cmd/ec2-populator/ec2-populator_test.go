@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	liburl "net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	libclient "github.com/kubev2v/forklift/pkg/lib/client/ec2"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const blockSize = 512 * 1024
+
+// Mock EBS direct API server.
+// The snapshot has blocks 0, 2 and 5 listed in two pages.
+func setupMockServer(t *testing.T) (client *libclient.Client, reads map[string]int) {
+	reads = map[string]int{}
+	mutex := sync.Mutex{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/snapshots/snap-1/blocks", func(w http.ResponseWriter, r *http.Request) {
+		reply := libclient.Blocks{
+			BlockSize:  blockSize,
+			VolumeSize: 1,
+		}
+		if r.URL.Query().Get("pageToken") == "" {
+			reply.Blocks = []libclient.Block{{Index: 0, Token: "t0"}, {Index: 2, Token: "t2"}}
+			reply.NextToken = "page-2"
+		} else {
+			reply.Blocks = []libclient.Block{{Index: 5, Token: "t5"}}
+		}
+		_ = json.NewEncoder(w).Encode(reply)
+	})
+	mux.HandleFunc("/snapshots/snap-1/blocks/", func(w http.ResponseWriter, r *http.Request) {
+		index := strings.TrimPrefix(r.URL.Path, "/snapshots/snap-1/blocks/")
+		mutex.Lock()
+		reads[index]++
+		mutex.Unlock()
+		_, _ = w.Write(bytes.Repeat([]byte(index), blockSize))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	client = &libclient.Client{
+		URL: "http://ec2.us-east-1.amazonaws.com",
+		Options: map[string]string{
+			libclient.AccessKeyID:     "AKIDEXAMPLE",
+			libclient.SecretAccessKey: "secret",
+		},
+		Proxy: func(*http.Request) (*liburl.URL, error) { return nil, nil },
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, server.Listener.Addr().String())
+		},
+	}
+	return
+}
+
+func newMetrics() (*prometheus.CounterVec, *prometheus.GaugeVec) {
+	progress := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "progress"}, []string{"ownerUID"})
+	offset := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "offset"}, []string{"ownerUID"})
+	return progress, offset
+}
+
+func TestCopySnapshot(t *testing.T) {
+	client, _ := setupMockServer(t)
+	fileName := filepath.Join(t.TempDir(), "disk.img")
+	config := &AppConfig{
+		snapshotID: "snap-1",
+		ownerUID:   "test-uid",
+		pvcSize:    1 << 30,
+		volumePath: fileName,
+	}
+	progress, offset := newMetrics()
+	copySnapshot(client, config, progress, offset)
+
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	if len(content) != 1<<30 {
+		t.Fatalf("Expected size %d, got %d", 1<<30, len(content))
+	}
+	for index, expected := range map[int]byte{0: '0', 1: 0, 2: '2', 5: '5', 6: 0} {
+		block := content[index*blockSize : (index+1)*blockSize]
+		if !bytes.Equal(block, bytes.Repeat([]byte{expected}, blockSize)) {
+			t.Errorf("Block %d: unexpected content", index)
+		}
+	}
+}
+
+func TestCopySnapshotResume(t *testing.T) {
+	client, reads := setupMockServer(t)
+	fileName := filepath.Join(t.TempDir(), "disk.img")
+	// The first page synced by a previous populator.
+	config := &AppConfig{
+		snapshotID: "snap-1",
+		ownerUID:   "test-uid",
+		pvcSize:    1 << 30,
+		volumePath: fileName,
+		offset:     3 * blockSize,
+	}
+	progress, offset := newMetrics()
+	copySnapshot(client, config, progress, offset)
+
+	if reads["0"] != 0 || reads["2"] != 0 {
+		t.Errorf("Expected the synced blocks to be skipped, got %v", reads)
+	}
+	if reads["5"] != 1 {
+		t.Errorf("Expected block 5 to be read once, got %v", reads)
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Output formats.
+const (
+	TableOutput = "table"
+	JsonOutput  = "json"
+)
+
+// Inventory VM concern.
+type Concern struct {
+	Label      string `json:"label"`
+	Category   string `json:"category"`
+	Assessment string `json:"assessment"`
+}
+
+// Assessment of a VM.
+type VMAssessment struct {
+	ID       string    `json:"id"`
+	Name     string    `json:"name"`
+	Concerns []Concern `json:"concerns"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// Assessment of a plan.
+type Assessment struct {
+	Plan       string             `json:"plan"`
+	Conditions []libcnd.Condition `json:"conditions"`
+	VMs        []VMAssessment     `json:"vms"`
+}
+
+// Report the plan assessment.
+func (r *CLI) Assessment(args []string) (err error) {
+	flags := flag.NewFlagSet("assessment", flag.ContinueOnError)
+	output := flags.String("output", TableOutput, "Output format (table|json).")
+	name, err := r.Parse(flags, args)
+	if err != nil {
+		return
+	}
+	if *output != TableOutput && *output != JsonOutput {
+		err = fmt.Errorf("output %q not supported", *output)
+		return
+	}
+	plan, err := r.plan(name)
+	if err != nil {
+		return
+	}
+	source := &api.Provider{}
+	err = r.Client.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: plan.Spec.Provider.Source.Namespace,
+			Name:      plan.Spec.Provider.Source.Name,
+		},
+		source)
+	if err != nil {
+		return
+	}
+	inventory, err := r.Inventory()
+	if err != nil {
+		return
+	}
+	assessment := Assessment{
+		Plan:       plan.Name,
+		Conditions: plan.Status.List,
+		VMs:        []VMAssessment{},
+	}
+	for _, vm := range plan.Spec.VMs {
+		vmAssessment := VMAssessment{
+			ID:       vm.ID,
+			Name:     vm.Name,
+			Concerns: []Concern{},
+		}
+		if vm.ID == "" {
+			vmAssessment.Error = "VM not resolved."
+		} else {
+			found := struct {
+				Name     string    `json:"name"`
+				Concerns []Concern `json:"concerns"`
+			}{}
+			getErr := inventory.Get(inventory.Path(source, "vms", vm.ID), &found)
+			if getErr != nil {
+				vmAssessment.Error = getErr.Error()
+			} else {
+				vmAssessment.Name = found.Name
+				vmAssessment.Concerns = append(vmAssessment.Concerns, found.Concerns...)
+			}
+		}
+		assessment.VMs = append(assessment.VMs, vmAssessment)
+	}
+	if *output == JsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(assessment)
+		return
+	}
+	PrintAssessment(os.Stdout, &assessment)
+	return
+}
+
+// Print the assessment tables.
+func PrintAssessment(out io.Writer, assessment *Assessment) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "PLAN %s\n\n", assessment.Plan)
+	fmt.Fprintln(w, "CATEGORY\tCONDITION\tMESSAGE")
+	for _, cnd := range assessment.Conditions {
+		message := cnd.Message
+		if len(cnd.Items) > 0 {
+			message += " [" + strings.Join(cnd.Items, ", ") + "]"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", cnd.Category, cnd.Type, message)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "VM\tCATEGORY\tCONCERN\tASSESSMENT")
+	for _, vm := range assessment.VMs {
+		if vm.Error != "" {
+			fmt.Fprintf(w, "%s\t%s\t\t%s\n", vm.Name, "Error", vm.Error)
+			continue
+		}
+		if len(vm.Concerns) == 0 {
+			fmt.Fprintf(w, "%s\t\t\t\n", vm.Name)
+			continue
+		}
+		for _, concern := range vm.Concerns {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", vm.Name, concern.Category, concern.Label, concern.Assessment)
+		}
+	}
+	_ = w.Flush()
+}
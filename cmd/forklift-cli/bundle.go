@@ -0,0 +1,119 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kubev2v/forklift/pkg/lib/bundle"
+)
+
+// Environment.
+const (
+	EnvBundleSigningKey = "FORKLIFT_BUNDLE_SIGNING_KEY"
+)
+
+// Export the plan (maps, hooks and redacted secrets) as a signed bundle.
+func (r *CLI) Export(args []string) (err error) {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	keyFile := flags.String("signing-key-file", "", "Signing key file. Defaults to $"+EnvBundleSigningKey+".")
+	file := flags.String("file", "", "Bundle file. Defaults to stdout.")
+	name, err := r.Parse(flags, args)
+	if err != nil {
+		return
+	}
+	key, err := signingKey(*keyFile)
+	if err != nil {
+		return
+	}
+	exported, err := bundle.Export(r.Client, r.Namespace, name, nil)
+	if err != nil {
+		return
+	}
+	err = exported.Sign(key)
+	if err != nil {
+		return
+	}
+	content, err := exported.Encode()
+	if err != nil {
+		return
+	}
+	if *file == "" {
+		_, err = os.Stdout.Write(content)
+		return
+	}
+	err = os.WriteFile(*file, content, 0600)
+	return
+}
+
+// Import the bundle as the named plan.
+func (r *CLI) Import(args []string) (err error) {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	keyFile := flags.String("signing-key-file", "", "Signing key file. Defaults to $"+EnvBundleSigningKey+".")
+	file := flags.String("file", "", "Bundle file.")
+	targetNamespace := flags.String("target-namespace", "", "Target namespace of the VMs.")
+	source := flags.String("source", "", "Source provider. Defaults to the exported provider.")
+	destination := flags.String("destination", "", "Destination provider. Defaults to the exported provider.")
+	name, err := r.Parse(flags, args)
+	if err != nil {
+		return
+	}
+	if *file == "" {
+		err = fmt.Errorf("--file required")
+		return
+	}
+	key, err := signingKey(*keyFile)
+	if err != nil {
+		return
+	}
+	content, err := os.ReadFile(*file)
+	if err != nil {
+		return
+	}
+	imported, err := bundle.Decode(content)
+	if err != nil {
+		return
+	}
+	err = imported.Verify(key)
+	if err != nil {
+		return
+	}
+	result, err := bundle.Import(
+		r.Client,
+		imported,
+		bundle.Options{
+			Namespace:           r.Namespace,
+			Name:                name,
+			TargetNamespace:     *targetNamespace,
+			SourceProvider:      *source,
+			DestinationProvider: *destination,
+		})
+	for _, created := range result.Created {
+		fmt.Printf("%s created\n", strings.ToLower(created))
+	}
+	for _, existing := range result.Existing {
+		fmt.Printf("%s exists (not replaced)\n", strings.ToLower(existing))
+	}
+	for _, redacted := range result.Redacted {
+		fmt.Printf("%s redacted: set the data before starting a migration\n", strings.ToLower(redacted))
+	}
+	return
+}
+
+// Read the signing key from the file or the environment.
+func signingKey(path string) (key []byte, err error) {
+	if path != "" {
+		key, err = os.ReadFile(path)
+		if err != nil {
+			return
+		}
+		key = []byte(strings.TrimSpace(string(key)))
+	} else {
+		key = []byte(os.Getenv(EnvBundleSigningKey))
+	}
+	if len(key) == 0 {
+		err = fmt.Errorf("signing key required: --signing-key-file or $%s", EnvBundleSigningKey)
+	}
+	return
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/kubev2v/forklift/pkg/apis"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Environment.
+const (
+	EnvInventoryURL = "FORKLIFT_INVENTORY_URL"
+)
+
+// Command.
+type Command struct {
+	// Usage (arguments).
+	Usage string
+	// Description.
+	Description string
+	// Run the command.
+	Run func(cli *CLI, args []string) error
+}
+
+// Commands by name.
+var commands = map[string]Command{
+	"create": {
+		Usage:       "create PLAN --source PROVIDER --vms PATTERN[,PATTERN]",
+		Description: "Create a plan for the source VMs matching the (glob) patterns.",
+		Run:         (*CLI).Create,
+	},
+	"start": {
+		Usage:       "start PLAN [--cutover TIME]",
+		Description: "Start a migration of the plan.",
+		Run:         (*CLI).Start,
+	},
+	"cutover": {
+		Usage:       "cutover PLAN [--at TIME]",
+		Description: "Set the cutover of the running (warm) migration.",
+		Run:         (*CLI).Cutover,
+	},
+	"cancel": {
+		Usage:       "cancel PLAN [--vms NAME[,NAME]]",
+		Description: "Cancel the running migration of the VMs (all by default).",
+		Run:         (*CLI).Cancel,
+	},
+	"approve": {
+		Usage:       "approve PLAN [--groups NAME[,NAME]] [--gates GATE[,GATE]]",
+		Description: "Approve migration groups and gates waiting for approval.",
+		Run:         (*CLI).Approve,
+	},
+	"watch": {
+		Usage:       "watch PLAN [--interval DURATION] [--once]",
+		Description: "Watch the migration progress of the plan VMs.",
+		Run:         (*CLI).Watch,
+	},
+	"assessment": {
+		Usage:       "assessment PLAN [--output table|json]",
+		Description: "Report the plan conditions and the concerns of the plan VMs.",
+		Run:         (*CLI).Assessment,
+	},
+	"export": {
+		Usage:       "export PLAN [--file FILE] [--signing-key-file FILE]",
+		Description: "Export the plan, maps, hooks and (redacted) secrets as a signed bundle.",
+		Run:         (*CLI).Export,
+	},
+	"import": {
+		Usage:       "import PLAN --file FILE [--signing-key-file FILE]",
+		Description: "Import the signed bundle as the plan in the namespace.",
+		Run:         (*CLI).Import,
+	},
+}
+
+// CLI.
+type CLI struct {
+	// Namespace.
+	Namespace string
+	// Inventory URL.
+	InventoryURL string
+	// Bearer token (inventory).
+	Token string
+	// Skip verification of the inventory certificate.
+	Insecure bool
+	// Cluster client.
+	Client client.Client
+	// REST configuration.
+	RestCfg *rest.Config
+}
+
+// Add the common flags.
+func (r *CLI) AddFlags(flags *flag.FlagSet) {
+	flags.StringVar(&r.Namespace, "n", r.Namespace, "Namespace.")
+	flags.StringVar(&r.InventoryURL, "inventory-url", os.Getenv(EnvInventoryURL), "Inventory service URL.")
+	flags.StringVar(&r.Token, "token", "", "Bearer token (inventory). Defaults to the kubeconfig token.")
+	flags.BoolVar(&r.Insecure, "insecure", false, "Skip verification of the inventory certificate.")
+}
+
+// Connect to the cluster using the kubeconfig.
+func (r *CLI) Connect() (err error) {
+	loader := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		clientcmd.NewDefaultClientConfigLoadingRules(),
+		&clientcmd.ConfigOverrides{})
+	r.RestCfg, err = loader.ClientConfig()
+	if err != nil {
+		return
+	}
+	r.Namespace, _, err = loader.Namespace()
+	if err != nil {
+		return
+	}
+	scheme := runtime.NewScheme()
+	err = apis.AddToScheme(scheme)
+	if err != nil {
+		return
+	}
+	r.Client, err = client.New(r.RestCfg, client.Options{Scheme: scheme})
+	return
+}
+
+// Parse the command flags and the (single) plan argument.
+func (r *CLI) Parse(flags *flag.FlagSet, args []string) (name string, err error) {
+	r.AddFlags(flags)
+	// flags may follow the plan name.
+	if len(args) > 0 && args[0] != "" && args[0][0] != '-' {
+		name = args[0]
+		args = args[1:]
+	}
+	err = flags.Parse(args)
+	if err != nil {
+		return
+	}
+	if name == "" && flags.NArg() > 0 {
+		name = flags.Arg(0)
+	}
+	if name == "" {
+		err = fmt.Errorf("plan name required")
+	}
+	return
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: forklift-cli COMMAND [PLAN] [flags]\n\nCommands:\n")
+	names := []string{}
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-60s %s\n", commands[name].Usage, commands[name].Description)
+	}
+	fmt.Fprintf(os.Stderr, "\nRun 'forklift-cli COMMAND -h' for the command flags.\n")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	command, found := commands[os.Args[1]]
+	if !found {
+		usage()
+		os.Exit(2)
+	}
+	cli := &CLI{}
+	err := cli.Connect()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+	err = command.Run(cli, os.Args[2:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	libitr "github.com/kubev2v/forklift/pkg/lib/itinerary"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMatchVMs(t *testing.T) {
+	list := []VM{
+		{ID: "vm-1", Name: "web-1"},
+		{ID: "vm-2", Name: "web-2"},
+		{ID: "vm-3", Name: "db-1"},
+	}
+	refs, err := MatchVMs(list, []string{"web-*", "web-1", " db-1 "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(refs) != 3 || refs[0].ID != "vm-1" || refs[1].ID != "vm-2" || refs[2].ID != "vm-3" {
+		t.Errorf("unexpected refs: %v", refs)
+	}
+	if _, err = MatchVMs(list, []string{"app-*"}); err == nil {
+		t.Errorf("expected error for unmatched pattern")
+	}
+	if _, err = MatchVMs(list, []string{"["}); err == nil {
+		t.Errorf("expected error for malformed pattern")
+	}
+}
+
+func TestPrintProgress(t *testing.T) {
+	started := meta.Now()
+	plan := &api.Plan{}
+	plan.Status.Migration.VMs = []*planapi.VMStatus{
+		{
+			VM:    planapi.VM{Ref: ref.Ref{ID: "vm-1", Name: "web-1"}},
+			Phase: "CopyDisks",
+			Timed: planapi.Timed{Started: &started},
+			Pipeline: []*planapi.Step{
+				{Task: planapi.Task{Name: "Initialize", Timed: planapi.Timed{Started: &started, Completed: &started}}},
+				{Task: planapi.Task{Name: "DiskTransfer", Timed: planapi.Timed{Started: &started}, Progress: libitr.Progress{Completed: 512, Total: 1024}}},
+				{Task: planapi.Task{Name: "ImageConversion"}},
+			},
+		},
+	}
+	out := &bytes.Buffer{}
+	PrintProgress(out, plan)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("unexpected table: %s", out.String())
+	}
+	for _, expected := range []string{"web-1", "CopyDisks", "DiskTransfer", "50% (512/1024)"} {
+		if !strings.Contains(lines[1], expected) {
+			t.Errorf("%q not in %q", expected, lines[1])
+		}
+	}
+}
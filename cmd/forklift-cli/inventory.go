@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+)
+
+// Inventory REST client.
+type Inventory struct {
+	// Base URL.
+	URL string
+	// Bearer token.
+	Token string
+	// HTTP client.
+	Client *http.Client
+}
+
+// Build the inventory client.
+func (r *CLI) Inventory() (inventory *Inventory, err error) {
+	if r.InventoryURL == "" {
+		err = fmt.Errorf("inventory URL required (--inventory-url or %s)", EnvInventoryURL)
+		return
+	}
+	token := r.Token
+	if token == "" {
+		token = r.RestCfg.BearerToken
+	}
+	if token == "" && r.RestCfg.BearerTokenFile != "" {
+		var b []byte
+		b, err = os.ReadFile(r.RestCfg.BearerTokenFile)
+		if err != nil {
+			return
+		}
+		token = strings.TrimSpace(string(b))
+	}
+	inventory = &Inventory{
+		URL:   strings.TrimRight(r.InventoryURL, "/"),
+		Token: token,
+		Client: &http.Client{
+			Timeout: time.Minute,
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: r.Insecure},
+			},
+		},
+	}
+	return
+}
+
+// Path of a provider resource (collection).
+func (r *Inventory) Path(provider *api.Provider, parts ...string) string {
+	return path.Join(
+		append(
+			[]string{
+				"/providers",
+				string(provider.Type()),
+				string(provider.UID),
+			},
+			parts...)...)
+}
+
+// GET the resource.
+func (r *Inventory) Get(path string, out interface{}) (err error) {
+	err = r.send(http.MethodGet, path, nil, out)
+	return
+}
+
+// POST the resource.
+func (r *Inventory) Post(path string, in, out interface{}) (err error) {
+	err = r.send(http.MethodPost, path, in, out)
+	return
+}
+
+// Send the request.
+func (r *Inventory) send(method, path string, in, out interface{}) (err error) {
+	var body io.Reader
+	if in != nil {
+		var b []byte
+		b, err = json.Marshal(in)
+		if err != nil {
+			return
+		}
+		body = bytes.NewReader(b)
+	}
+	request, err := http.NewRequest(method, r.URL+path, body)
+	if err != nil {
+		return
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if r.Token != "" {
+		request.Header.Set("Authorization", "Bearer "+r.Token)
+	}
+	response, err := r.Client.Do(request)
+	if err != nil {
+		return
+	}
+	defer response.Body.Close()
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		return
+	}
+	if response.StatusCode != http.StatusOK {
+		err = fmt.Errorf(
+			"%s %s: %s %s",
+			method,
+			path,
+			response.Status,
+			strings.TrimSpace(string(content)))
+		return
+	}
+	err = json.Unmarshal(content, out)
+	return
+}
@@ -0,0 +1,283 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Start a migration.
+func (r *CLI) Start(args []string) (err error) {
+	flags := flag.NewFlagSet("start", flag.ContinueOnError)
+	cutover := flags.String("cutover", "", "Cutover (RFC3339) of a warm migration.")
+	name, err := r.Parse(flags, args)
+	if err != nil {
+		return
+	}
+	plan, err := r.plan(name)
+	if err != nil {
+		return
+	}
+	running, err := r.running(plan)
+	if err != nil {
+		return
+	}
+	if running != nil {
+		err = fmt.Errorf("migration/%s of plan %s is running", running.Name, plan.Name)
+		return
+	}
+	migration := &api.Migration{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace:    plan.Namespace,
+			GenerateName: plan.Name + "-",
+		},
+		Spec: api.MigrationSpec{
+			Plan: core.ObjectReference{
+				Namespace: plan.Namespace,
+				Name:      plan.Name,
+				UID:       plan.UID,
+			},
+		},
+	}
+	if *cutover != "" {
+		migration.Spec.Cutover, err = parseTime(*cutover)
+		if err != nil {
+			return
+		}
+	}
+	err = r.Client.Create(context.TODO(), migration)
+	if err != nil {
+		return
+	}
+	fmt.Printf("migration/%s created\n", migration.Name)
+	return
+}
+
+// Set the cutover of the running migration.
+func (r *CLI) Cutover(args []string) (err error) {
+	flags := flag.NewFlagSet("cutover", flag.ContinueOnError)
+	at := flags.String("at", "", "Cutover (RFC3339). Defaults to now.")
+	name, err := r.Parse(flags, args)
+	if err != nil {
+		return
+	}
+	plan, err := r.plan(name)
+	if err != nil {
+		return
+	}
+	if !plan.Spec.Warm {
+		err = fmt.Errorf("plan %s is not a warm migration", plan.Name)
+		return
+	}
+	migration, err := r.mustBeRunning(plan)
+	if err != nil {
+		return
+	}
+	cutover := &meta.Time{Time: time.Now()}
+	if *at != "" {
+		cutover, err = parseTime(*at)
+		if err != nil {
+			return
+		}
+	}
+	migration.Spec.Cutover = cutover
+	err = r.Client.Update(context.TODO(), migration)
+	if err != nil {
+		return
+	}
+	fmt.Printf("migration/%s cutover at %s\n", migration.Name, cutover.Format(time.RFC3339))
+	return
+}
+
+// Cancel the migration of VMs.
+func (r *CLI) Cancel(args []string) (err error) {
+	flags := flag.NewFlagSet("cancel", flag.ContinueOnError)
+	vms := flags.String("vms", "", "Comma-separated VM names. Defaults to all VMs.")
+	name, err := r.Parse(flags, args)
+	if err != nil {
+		return
+	}
+	plan, err := r.plan(name)
+	if err != nil {
+		return
+	}
+	migration, err := r.mustBeRunning(plan)
+	if err != nil {
+		return
+	}
+	wanted := map[string]bool{}
+	for _, vmName := range strings.Split(*vms, ",") {
+		if vmName = strings.TrimSpace(vmName); vmName != "" {
+			wanted[vmName] = true
+		}
+	}
+	// the plan VMs until reported by the migration.
+	candidates := []ref.Ref{}
+	for _, vm := range migration.Status.VMs {
+		if vm.Completed == nil {
+			candidates = append(candidates, vm.Ref)
+		}
+	}
+	if len(migration.Status.VMs) == 0 {
+		for _, vm := range plan.Spec.VMs {
+			candidates = append(candidates, vm.Ref)
+		}
+	}
+	for _, vmRef := range candidates {
+		if len(wanted) > 0 && !wanted[vmRef.Name] {
+			continue
+		}
+		delete(wanted, vmRef.Name)
+		if migration.Spec.Canceled(vmRef) {
+			continue
+		}
+		migration.Spec.Cancel = append(
+			migration.Spec.Cancel,
+			ref.Ref{
+				ID:        vmRef.ID,
+				Name:      vmRef.Name,
+				Namespace: vmRef.Namespace,
+			})
+	}
+	for vmName := range wanted {
+		err = fmt.Errorf("VM %s not found (or completed) in migration/%s", vmName, migration.Name)
+		return
+	}
+	err = r.Client.Update(context.TODO(), migration)
+	if err != nil {
+		return
+	}
+	fmt.Printf("migration/%s canceled %d VMs\n", migration.Name, len(migration.Spec.Cancel))
+	return
+}
+
+// Approve groups and gates of the running migration.
+func (r *CLI) Approve(args []string) (err error) {
+	flags := flag.NewFlagSet("approve", flag.ContinueOnError)
+	groups := flags.String("groups", "", "Comma-separated group names.")
+	gates := flags.String("gates", "", "Comma-separated approval gates (PowerOff, Cutover).")
+	name, err := r.Parse(flags, args)
+	if err != nil {
+		return
+	}
+	if strings.TrimSpace(*groups) == "" && strings.TrimSpace(*gates) == "" {
+		err = fmt.Errorf("--groups or --gates required")
+		return
+	}
+	plan, err := r.plan(name)
+	if err != nil {
+		return
+	}
+	migration, err := r.mustBeRunning(plan)
+	if err != nil {
+		return
+	}
+	approved := 0
+	for _, group := range strings.Split(*groups, ",") {
+		group = strings.TrimSpace(group)
+		if group == "" || migration.Spec.GroupApproved(group) {
+			continue
+		}
+		found := false
+		for _, listed := range plan.Spec.Groups {
+			if listed.Name == group {
+				found = true
+				break
+			}
+		}
+		if !found {
+			err = fmt.Errorf("group %s not found in plan %s", group, plan.Name)
+			return
+		}
+		migration.Spec.Approved = append(migration.Spec.Approved, group)
+		approved++
+	}
+	for _, gate := range strings.Split(*gates, ",") {
+		gate = strings.TrimSpace(gate)
+		if gate == "" {
+			continue
+		}
+		if _, found := migration.Spec.GateApproval(gate); found {
+			continue
+		}
+		if plan.Spec.ApprovalGates == nil || !plan.Spec.ApprovalGates.Requires(gate) {
+			err = fmt.Errorf("gate %s not required by plan %s", gate, plan.Name)
+			return
+		}
+		// The approver is recorded by the admission webhook.
+		migration.Spec.GateApprovals = append(migration.Spec.GateApprovals, planapi.GateApproval{Gate: gate})
+		approved++
+	}
+	if approved > 0 {
+		err = r.Client.Update(context.TODO(), migration)
+		if err != nil {
+			return
+		}
+	}
+	if len(migration.Spec.Approved) > 0 {
+		fmt.Printf("migration/%s approved groups: %s\n", migration.Name, strings.Join(migration.Spec.Approved, ", "))
+	}
+	for _, approval := range migration.Spec.GateApprovals {
+		fmt.Printf("migration/%s approved gate: %s %s\n", migration.Name, approval.Gate, approval.ApprovedBy)
+	}
+	return
+}
+
+// Get the plan.
+func (r *CLI) plan(name string) (plan *api.Plan, err error) {
+	plan = &api.Plan{}
+	err = r.Client.Get(
+		context.TODO(),
+		client.ObjectKey{Namespace: r.Namespace, Name: name},
+		plan)
+	return
+}
+
+// Find the running migration of the plan.
+func (r *CLI) running(plan *api.Plan) (migration *api.Migration, err error) {
+	list := &api.MigrationList{}
+	err = r.Client.List(context.TODO(), list, client.InNamespace(plan.Namespace))
+	if err != nil {
+		return
+	}
+	for i := range list.Items {
+		m := &list.Items[i]
+		if !m.Match(plan) || m.Status.Completed != nil || m.DeletionTimestamp != nil {
+			continue
+		}
+		if migration == nil || migration.CreationTimestamp.Before(&m.CreationTimestamp) {
+			migration = m
+		}
+	}
+	return
+}
+
+// Find the running migration of the plan.
+// Returns an error when not found.
+func (r *CLI) mustBeRunning(plan *api.Plan) (migration *api.Migration, err error) {
+	migration, err = r.running(plan)
+	if err == nil && migration == nil {
+		err = fmt.Errorf("plan %s has no running migration", plan.Name)
+	}
+	return
+}
+
+// Parse an RFC3339 time.
+func parseTime(s string) (t *meta.Time, err error) {
+	parsed, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		err = fmt.Errorf("time %q not RFC3339: %w", s, err)
+		return
+	}
+	t = &meta.Time{Time: parsed}
+	return
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"path"
+	"strings"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/provider"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Inventory VM.
+type VM struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// Inventory map generation request.
+type MapsRequest struct {
+	VMs         []ref.Ref            `json:"vms"`
+	Destination core.ObjectReference `json:"destination"`
+	Namespace   string               `json:"namespace"`
+	Name        string               `json:"name"`
+}
+
+// Inventory generated maps.
+type GeneratedMaps struct {
+	NetworkMap *api.NetworkMap `json:"networkMap"`
+	StorageMap *api.StorageMap `json:"storageMap"`
+}
+
+// Create a plan.
+func (r *CLI) Create(args []string) (err error) {
+	flags := flag.NewFlagSet("create", flag.ContinueOnError)
+	source := flags.String("source", "", "Source provider.")
+	destination := flags.String("destination", "", "Destination provider. Defaults to the host provider.")
+	vms := flags.String("vms", "", "Comma-separated VM names or glob patterns.")
+	networkMap := flags.String("network-map", "", "Network map. Generated when not specified.")
+	storageMap := flags.String("storage-map", "", "Storage map. Generated when not specified.")
+	targetNamespace := flags.String("target-namespace", "", "Target namespace. Defaults to the plan namespace.")
+	warm := flags.Bool("warm", false, "Warm migration.")
+	name, err := r.Parse(flags, args)
+	if err != nil {
+		return
+	}
+	if *source == "" || *vms == "" {
+		err = fmt.Errorf("--source and --vms required")
+		return
+	}
+	sourceProvider := &api.Provider{}
+	err = r.Client.Get(
+		context.TODO(),
+		client.ObjectKey{Namespace: r.Namespace, Name: *source},
+		sourceProvider)
+	if err != nil {
+		return
+	}
+	destinationRef, err := r.destination(*destination)
+	if err != nil {
+		return
+	}
+	inventory, err := r.Inventory()
+	if err != nil {
+		return
+	}
+	list := []VM{}
+	err = inventory.Get(inventory.Path(sourceProvider, "vms"), &list)
+	if err != nil {
+		return
+	}
+	refs, err := MatchVMs(list, strings.Split(*vms, ","))
+	if err != nil {
+		return
+	}
+	if *networkMap == "" || *storageMap == "" {
+		generated := GeneratedMaps{}
+		err = inventory.Post(
+			inventory.Path(sourceProvider, "maps", "generate"),
+			MapsRequest{
+				VMs:         refs,
+				Destination: destinationRef,
+				Namespace:   r.Namespace,
+				Name:        name,
+			},
+			&generated)
+		if err != nil {
+			return
+		}
+		if *networkMap == "" {
+			err = r.Client.Create(context.TODO(), generated.NetworkMap)
+			if err != nil {
+				return
+			}
+			*networkMap = generated.NetworkMap.Name
+			fmt.Printf("networkmap/%s created\n", *networkMap)
+		}
+		if *storageMap == "" {
+			err = r.Client.Create(context.TODO(), generated.StorageMap)
+			if err != nil {
+				return
+			}
+			*storageMap = generated.StorageMap.Name
+			fmt.Printf("storagemap/%s created\n", *storageMap)
+		}
+	}
+	if *targetNamespace == "" {
+		*targetNamespace = r.Namespace
+	}
+	plan := &api.Plan{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: r.Namespace,
+			Name:      name,
+		},
+		Spec: api.PlanSpec{
+			TargetNamespace: *targetNamespace,
+			Warm:            *warm,
+			Provider: provider.Pair{
+				Source: core.ObjectReference{
+					Namespace: sourceProvider.Namespace,
+					Name:      sourceProvider.Name,
+				},
+				Destination: destinationRef,
+			},
+			Map: planapi.Map{
+				Network: core.ObjectReference{
+					Namespace: r.Namespace,
+					Name:      *networkMap,
+				},
+				Storage: core.ObjectReference{
+					Namespace: r.Namespace,
+					Name:      *storageMap,
+				},
+			},
+		},
+	}
+	for _, vmRef := range refs {
+		plan.Spec.VMs = append(plan.Spec.VMs, planapi.VM{Ref: vmRef})
+	}
+	err = r.Client.Create(context.TODO(), plan)
+	if err != nil {
+		return
+	}
+	fmt.Printf("plan/%s created with %d VMs\n", plan.Name, len(plan.Spec.VMs))
+	return
+}
+
+// Reference to the destination provider.
+// The host provider when not specified.
+func (r *CLI) destination(name string) (destination core.ObjectReference, err error) {
+	if name != "" {
+		destination = core.ObjectReference{Namespace: r.Namespace, Name: name}
+		return
+	}
+	list := &api.ProviderList{}
+	err = r.Client.List(context.TODO(), list, client.InNamespace(r.Namespace))
+	if err != nil {
+		return
+	}
+	for i := range list.Items {
+		p := &list.Items[i]
+		if p.IsHost() {
+			destination = core.ObjectReference{Namespace: p.Namespace, Name: p.Name}
+			return
+		}
+	}
+	err = fmt.Errorf("host provider not found in namespace %s, --destination required", r.Namespace)
+	return
+}
+
+// Match the VMs by name against (glob) patterns.
+// Each pattern must match at least one VM.
+func MatchVMs(list []VM, patterns []string) (refs []ref.Ref, err error) {
+	matched := map[string]bool{}
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		found := false
+		for _, vm := range list {
+			var match bool
+			match, err = path.Match(pattern, vm.Name)
+			if err != nil {
+				err = fmt.Errorf("pattern %q: %w", pattern, err)
+				return
+			}
+			if !match {
+				continue
+			}
+			found = true
+			if matched[vm.ID] {
+				continue
+			}
+			matched[vm.ID] = true
+			refs = append(
+				refs,
+				ref.Ref{
+					ID:        vm.ID,
+					Name:      vm.Name,
+					Namespace: vm.Namespace,
+				})
+		}
+		if !found {
+			err = fmt.Errorf("no VM matches %q", pattern)
+			return
+		}
+	}
+	if len(refs) == 0 {
+		err = fmt.Errorf("no VMs matched")
+	}
+	return
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Watch the migration progress.
+func (r *CLI) Watch(args []string) (err error) {
+	flags := flag.NewFlagSet("watch", flag.ContinueOnError)
+	interval := flags.Duration("interval", 5*time.Second, "Refresh interval.")
+	once := flags.Bool("once", false, "Print the progress once.")
+	name, err := r.Parse(flags, args)
+	if err != nil {
+		return
+	}
+	for {
+		var plan *api.Plan
+		plan, err = r.plan(name)
+		if err != nil {
+			return
+		}
+		PrintProgress(os.Stdout, plan)
+		migration := plan.Status.Migration
+		if *once || (migration.Started != nil && migration.Completed != nil) {
+			return
+		}
+		time.Sleep(*interval)
+		fmt.Println()
+	}
+}
+
+// Print the progress table of the plan VMs.
+func PrintProgress(out io.Writer, plan *api.Plan) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VM\tPHASE\tSTEP\tPROGRESS\tSTARTED\tCOMPLETED\tERROR")
+	for _, vm := range plan.Status.Migration.VMs {
+		step, progress := "", ""
+		if current := currentStep(vm); current != nil {
+			step = current.Name
+			if current.Progress.Total > 0 {
+				progress = fmt.Sprintf(
+					"%d%% (%d/%d)",
+					current.Progress.Completed*100/current.Progress.Total,
+					current.Progress.Completed,
+					current.Progress.Total)
+			}
+		}
+		reasons := ""
+		if vm.Error != nil {
+			reasons = strings.Join(vm.Error.Reasons, "; ")
+		}
+		fmt.Fprintf(
+			w,
+			"%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			vm.Name,
+			vm.Phase,
+			step,
+			progress,
+			timestamp(vm.Started),
+			timestamp(vm.Completed),
+			reasons)
+	}
+	_ = w.Flush()
+}
+
+// The current (started and not completed) step, else
+// the last started step of the VM pipeline.
+func currentStep(vm *planapi.VMStatus) (current *planapi.Step) {
+	for _, step := range vm.Pipeline {
+		if step.Started == nil {
+			continue
+		}
+		current = step
+		if step.Completed == nil {
+			break
+		}
+	}
+	return
+}
+
+// Format the timestamp.
+func timestamp(t *meta.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
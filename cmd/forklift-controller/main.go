@@ -17,6 +17,7 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"net/http"
 	_ "net/http/pprof"
 	"os"
@@ -26,7 +27,9 @@ import (
 	net "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
 	"github.com/kubev2v/forklift/pkg/apis"
 	"github.com/kubev2v/forklift/pkg/controller"
+	"github.com/kubev2v/forklift/pkg/controller/leader"
 	"github.com/kubev2v/forklift/pkg/lib/logging"
+	"github.com/kubev2v/forklift/pkg/lib/tracing"
 	"github.com/kubev2v/forklift/pkg/settings"
 	"github.com/kubev2v/forklift/pkg/webhook"
 	template "github.com/openshift/api/template/v1"
@@ -77,6 +80,19 @@ func main() {
 		}
 	}()
 
+	// Set up the OpenTelemetry tracing exporter.
+	shutdown, err := tracing.Setup(
+		context.Background(),
+		Settings.Tracing.Endpoint,
+		Settings.Tracing.Insecure,
+		Settings.Tracing.Service)
+	if err != nil {
+		log.Error(err, "proceeding without tracing")
+	}
+	defer func() {
+		_ = shutdown(context.Background())
+	}()
+
 	log.Info("setting up profiling endpoint :6060")
 	go func() {
 		err := http.ListenAndServe("localhost:6060", nil)
@@ -95,9 +111,30 @@ func main() {
 
 	// Create a new Cmd to provide shared dependencies and start components
 	log.Info("setting up manager")
-	mgr, err := manager.New(cfg, manager.Options{
+	options := manager.Options{
 		Metrics: server.Options{BindAddress: Settings.Metrics.Address()},
-	})
+	}
+	// Active-passive HA of the main controller.
+	election := Settings.Leader.Election && Settings.Role.Has(settings.MainRole)
+	if election {
+		leaseDuration := time.Duration(Settings.Leader.LeaseDuration) * time.Second
+		renewDeadline := time.Duration(Settings.Leader.RenewDeadline) * time.Second
+		retryPeriod := time.Duration(Settings.Leader.RetryPeriod) * time.Second
+		options.LeaderElection = true
+		options.LeaderElectionID = Settings.Leader.LeaseName
+		options.LeaderElectionNamespace = Settings.Leader.LeaseNamespace
+		options.LeaderElectionReleaseOnCancel = true
+		options.LeaseDuration = &leaseDuration
+		options.RenewDeadline = &renewDeadline
+		options.RetryPeriod = &retryPeriod
+		log.Info(
+			"leader election enabled",
+			"lease",
+			Settings.Leader.LeaseName,
+			"identity",
+			Settings.Leader.Identity)
+	}
+	mgr, err := manager.New(cfg, options)
 	if err != nil {
 		log.Error(err, "unable to set up overall controller manager")
 		os.Exit(1)
@@ -144,9 +181,23 @@ func main() {
 		log.Error(err, "unable to register webhooks to the manager")
 		os.Exit(1)
 	}
+	ctx, cancel := context.WithCancel(signals.SetupSignalHandler())
+	defer cancel()
+	if election {
+		log.Info("setting up leader takeover")
+		err = mgr.Add(&leader.Takeover{
+			Reader:  mgr.GetAPIReader(),
+			Writer:  mgr.GetClient(),
+			Release: cancel,
+		})
+		if err != nil {
+			log.Error(err, "unable to set up the leader takeover")
+			os.Exit(1)
+		}
+	}
 	// Start the Cmd
 	log.Info("Starting the Cmd.")
-	if err := mgr.Start(signals.SetupSignalHandler()); err != nil {
+	if err := mgr.Start(ctx); err != nil {
 		log.Error(err, "unable to run the manager")
 		os.Exit(1)
 	}
@@ -0,0 +1,496 @@
+package main
+
+import (
+	"encoding/xml"
+	"flag"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	libclient "github.com/kubev2v/forklift/pkg/lib/client/libvirt"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	"github.com/kubev2v/forklift/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/klog/v2"
+)
+
+type AppConfig struct {
+	url         string
+	domain      string
+	disk        string
+	migration   string
+	crNamespace string
+	crName      string
+	secretName  string
+	ownerUID    string
+	pvcSize     int64
+	volumePath  string
+}
+
+const (
+	// Extents read (and written) concurrently.
+	// Each worker has its own NBD connection.
+	workers = 4
+	// Maximum length (bytes) of a read.
+	chunkSize = 4 * 1024 * 1024
+	// Maximum length (bytes) of a block status request.
+	statusSize = 1024 * 1024 * 1024
+	// Metadata (XML) namespace key.
+	metadataKey = "forklift"
+)
+
+// Interval between the backup lookups.
+var pollInterval = libclient.PollInterval
+
+// The host operations used by the populator.
+type Host interface {
+	GetBackup(id string) (backup *libclient.DomainBackup, found bool, err error)
+	GetMetadata(id, uri string) (content []byte, found bool, err error)
+	SetMetadata(id, uri, key string, content []byte) (err error)
+	DialSocket(path string) (conn net.Conn, err error)
+}
+
+func main() {
+	config := &AppConfig{}
+	flag.StringVar(&config.url, "url", "", "Libvirt connection URL (qemu+ssh://root@host/system)")
+	flag.StringVar(&config.secretName, "secret-name", "", "secret containing the host credentials")
+	flag.StringVar(&config.domain, "domain", "", "Domain UUID")
+	flag.StringVar(&config.disk, "disk", "", "Disk target (vda)")
+	flag.StringVar(&config.migration, "migration", "", "Migration UID the backups are created by")
+	flag.StringVar(&config.volumePath, "volume-path", "", "Path to populate")
+	flag.StringVar(&config.crName, "cr-name", "", "Custom Resource instance name")
+	flag.StringVar(&config.crNamespace, "cr-namespace", "", "Custom Resource instance namespace")
+	flag.StringVar(&config.ownerUID, "owner-uid", "", "Owner UID (usually PVC UID)")
+	flag.Int64Var(&config.pvcSize, "pvc-size", 0, "Size of pvc (in bytes)")
+	flag.Parse()
+
+	if config.pvcSize <= 0 {
+		klog.Fatal("pvc-size must be greater than 0")
+	}
+
+	certsDirectory, err := os.MkdirTemp("", "certsdir")
+	if err != nil {
+		klog.Fatal(err)
+	}
+
+	metrics.StartPrometheusEndpoint(certsDirectory)
+
+	populate(config)
+}
+
+func populate(config *AppConfig) {
+	client := createClient(config)
+	defer client.Close()
+	copyBackups(client, config, createProgressGauge())
+}
+
+func createClient(config *AppConfig) *libclient.Client {
+	return &libclient.Client{
+		URL:     config.url,
+		Options: readOptions(),
+	}
+}
+
+// Copy the disk exported by the backups created by the migration.
+// The backup of the first precopy is copied in full and the backup of
+// each following precopy is copied as the blocks changed since the
+// checkpoint of the previous precopy (dirty bitmap). The copied precopy
+// is recorded in the domain metadata so that a restarted populator
+// resumes with the next backup. The population is done once the
+// final backup has been copied.
+func copyBackups(host Host, config *AppConfig, progress *prometheus.GaugeVec) {
+	klog.Info("Copying the backups of domain: ", config.domain, " disk: ", config.disk, " migration: ", config.migration)
+	file := openFile(config.volumePath)
+	defer file.Close()
+
+	for {
+		copied, found, err := getCopied(host, config)
+		if err != nil {
+			klog.Fatal(err)
+		}
+		if found && copied.Final {
+			break
+		}
+		backup, precopy, final, ready, err := findBackup(host, config)
+		if err != nil {
+			klog.Fatal(err)
+		}
+		if !ready || (found && precopy <= copied.Precopy) {
+			time.Sleep(pollInterval)
+			continue
+		}
+		incremental := found &&
+			backup.Incremental == libclient.CheckpointName(config.migration, copied.Precopy) &&
+			copied.Precopy == precopy-1
+		copier := &Copier{
+			host:   host,
+			file:   file,
+			socket: backup.Server.Socket,
+		}
+		copier.export, copier.context, err = exportOf(backup, config.disk, incremental)
+		if err != nil {
+			klog.Fatal(err)
+		}
+		klog.Info("Copying the backup of precopy: ", precopy, " incremental: ", incremental)
+		done := make(chan bool)
+		finished := make(chan bool)
+		go func() {
+			reportProgress(done, copier, progress, config.ownerUID)
+			finished <- true
+		}()
+		err = copier.copy(config.volumePath)
+		done <- true
+		<-finished
+		if err != nil {
+			klog.Fatal(err)
+		}
+		err = setCopied(host, config, libclient.Copied{Precopy: precopy, Final: final})
+		if err != nil {
+			klog.Fatal(err)
+		}
+		klog.Info("Copied the backup of precopy: ", precopy)
+		if final {
+			break
+		}
+	}
+
+	klog.Info("Finished populating the volume.")
+}
+
+// Find the (running) backup created by the migration.
+// Not ready when the domain has no backup job or the
+// job was not created by the migration.
+func findBackup(host Host, config *AppConfig) (backup *libclient.DomainBackup, precopy int, final bool, ready bool, err error) {
+	backup, found, err := host.GetBackup(config.domain)
+	if err != nil || !found || backup.Server == nil {
+		return
+	}
+	precopy, final, ready = libclient.ParseBackupSocket(backup.Server.Socket, config.migration)
+	return
+}
+
+// The NBD export and the meta context of the disk.
+// The dirty bitmap is used by incremental copies.
+func exportOf(backup *libclient.DomainBackup, name string, incremental bool) (export, context string, err error) {
+	disk, found := backup.FindDisk(name)
+	if !found || disk.Backup == "no" {
+		err = liberr.New("disk not exported by the backup.", "disk", name)
+		return
+	}
+	export = disk.ExportName
+	if export == "" {
+		export = disk.Name
+	}
+	context = libclient.BaseAllocation
+	if incremental {
+		bitmap := disk.ExportBitmap
+		if bitmap == "" {
+			bitmap = libclient.ExportBitmap(disk.Name)
+		}
+		context = libclient.DirtyBitmap + bitmap
+	}
+	return
+}
+
+// Get the precopy last copied (domain metadata).
+func getCopied(host Host, config *AppConfig) (copied libclient.Copied, found bool, err error) {
+	content, found, err := host.GetMetadata(config.domain, libclient.CopiedURI(config.migration, config.disk))
+	if err != nil || !found {
+		return
+	}
+	err = xml.Unmarshal(content, &copied)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+	return
+}
+
+// Record the precopy copied (domain metadata).
+func setCopied(host Host, config *AppConfig, copied libclient.Copied) (err error) {
+	content, err := xml.Marshal(copied)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	err = host.SetMetadata(
+		config.domain,
+		libclient.CopiedURI(config.migration, config.disk),
+		metadataKey,
+		content)
+	return
+}
+
+// Extent to be copied.
+type Extent struct {
+	libclient.Extent
+	// Zeroed (not read).
+	Zero bool
+}
+
+// Copies the (changed) extents of a backup to the volume.
+type Copier struct {
+	host Host
+	file *os.File
+	// NBD (unix) socket on the host.
+	socket string
+	// NBD export.
+	export string
+	// Meta context of the block status.
+	context string
+	// Bytes to be copied.
+	total int64
+	// Bytes copied.
+	copied int64
+}
+
+// Copy the backup.
+// A full copy reads the allocated extents and zeroes the others.
+// An incremental copy reads the dirty extents only.
+func (r *Copier) copy(volumePath string) (err error) {
+	nbd, err := r.open()
+	if err != nil {
+		return
+	}
+	size := nbd.Size()
+	extents, err := r.extents(nbd)
+	nbd.Close()
+	if err != nil {
+		return
+	}
+	err = r.prepare(size, volumePath)
+	if err != nil {
+		return
+	}
+	chunks := split(extents)
+	for _, chunk := range chunks {
+		r.total += chunk.Length
+	}
+	klog.Info("Copying the export: ", r.export, " extents: ", len(chunks), " bytes: ", r.total)
+	err = r.copyExtents(chunks)
+	if err != nil {
+		return
+	}
+	err = r.file.Sync()
+	return
+}
+
+// Open the NBD export.
+func (r *Copier) open() (nbd *libclient.NBD, err error) {
+	conn, err := r.host.DialSocket(r.socket)
+	if err != nil {
+		return
+	}
+	nbd, err = libclient.OpenNBD(conn, r.export, r.context)
+	return
+}
+
+// List the extents to be copied.
+func (r *Copier) extents(nbd *libclient.NBD) (extents []Extent, err error) {
+	incremental := r.context != libclient.BaseAllocation
+	for offset := int64(0); offset < nbd.Size(); {
+		var status []libclient.Extent
+		status, err = nbd.BlockStatus(offset, min(statusSize, nbd.Size()-offset))
+		if err != nil {
+			return
+		}
+		if len(status) == 0 {
+			err = liberr.New("nbd: empty block status.", "offset", offset)
+			return
+		}
+		for _, extent := range status {
+			extent.Length = min(extent.Length, nbd.Size()-extent.Offset)
+			offset = extent.Offset + extent.Length
+			switch {
+			case incremental:
+				if extent.Flags&libclient.DirtyFlag != 0 {
+					extents = append(extents, Extent{Extent: extent})
+				}
+			default:
+				extents = append(
+					extents,
+					Extent{
+						Extent: extent,
+						Zero:   extent.Flags&libclient.ZeroFlag != 0,
+					})
+			}
+		}
+	}
+	return
+}
+
+// Extend the (file) volume to the disk size.
+func (r *Copier) prepare(size int64, volumePath string) (err error) {
+	if size == 0 || !strings.HasSuffix(volumePath, "disk.img") {
+		return
+	}
+	info, err := r.file.Stat()
+	if err != nil {
+		return
+	}
+	if info.Size() < size {
+		err = r.file.Truncate(size)
+	}
+	return
+}
+
+// Copy the extents concurrently.
+func (r *Copier) copyExtents(extents []Extent) (err error) {
+	queue := make(chan Extent)
+	errs := make(chan error, workers)
+	wg := sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			wErr := r.worker(queue)
+			if wErr != nil {
+				errs <- wErr
+				for range queue {
+				}
+			}
+		}()
+	}
+	for _, extent := range extents {
+		queue <- extent
+	}
+	close(queue)
+	wg.Wait()
+	close(errs)
+	err = <-errs
+	return
+}
+
+// Copy the queued extents.
+// The export is opened when there is data to be read.
+func (r *Copier) worker(queue chan Extent) (err error) {
+	var nbd *libclient.NBD
+	defer func() {
+		if nbd != nil {
+			nbd.Close()
+		}
+	}()
+	buffer := make([]byte, chunkSize)
+	for extent := range queue {
+		data := buffer[:extent.Length]
+		if extent.Zero {
+			clear(data)
+		} else {
+			if nbd == nil {
+				nbd, err = r.open()
+				if err != nil {
+					return
+				}
+			}
+			err = nbd.Read(extent.Offset, data)
+			if err != nil {
+				return
+			}
+		}
+		_, err = r.file.WriteAt(data, extent.Offset)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		atomic.AddInt64(&r.copied, extent.Length)
+	}
+	return
+}
+
+// Percent of the backup copied.
+func (r *Copier) Percent() float64 {
+	if r.total == 0 {
+		return 0
+	}
+	return float64(atomic.LoadInt64(&r.copied)) / float64(r.total) * 100
+}
+
+// Split the extents into chunks of (at most) the chunk size.
+func split(extents []Extent) (chunks []Extent) {
+	for _, extent := range extents {
+		end := extent.Offset + extent.Length
+		for offset := extent.Offset; offset < end; offset += chunkSize {
+			chunk := extent
+			chunk.Offset = offset
+			chunk.Length = min(chunkSize, end-offset)
+			chunks = append(chunks, chunk)
+		}
+	}
+	return
+}
+
+func createProgressGauge() *prometheus.GaugeVec {
+	progressVec := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "libvirt_populator_progress",
+			Help: "Progress of the backup copy",
+		},
+		[]string{"ownerUID"},
+	)
+
+	if err := prometheus.Register(progressVec); err != nil {
+		klog.Error("Prometheus progress gauge not registered:", err)
+	}
+
+	return progressVec
+}
+
+func openFile(volumePath string) *os.File {
+	flags := os.O_RDWR
+	if strings.HasSuffix(volumePath, "disk.img") {
+		flags |= os.O_CREATE
+	}
+	file, err := os.OpenFile(volumePath, flags, 0650)
+	if err != nil {
+		klog.Fatal(err)
+	}
+	return file
+}
+
+// Report the progress of the backup copy.
+// The progress restarts with each backup.
+func reportProgress(done chan bool, copier *Copier, progress *prometheus.GaugeVec, ownerUID string) {
+	progress.WithLabelValues(ownerUID).Set(0)
+	for {
+		select {
+		case <-done:
+			progress.WithLabelValues(ownerUID).Set(100)
+			return
+		default:
+			percent := copier.Percent()
+			progress.WithLabelValues(ownerUID).Set(percent)
+			klog.Info("Progress: ", int64(percent), "%")
+			time.Sleep(1 * time.Second)
+		}
+	}
+}
+
+func readOptions() map[string]string {
+	options := map[string]string{}
+
+	// List of options to read from environment variables
+	envOptions := []string{
+		libclient.User,
+		libclient.Password,
+		libclient.PrivateKey,
+		libclient.Fingerprint,
+		libclient.Insecure,
+	}
+
+	klog.Info("Options:")
+	for _, option := range envOptions {
+		value := os.Getenv(option)
+		options[option] = value
+		if sensitiveInfo(option) {
+			value = strings.Repeat("*", len(value))
+		}
+		klog.Info(" - ", option, " = ", value)
+	}
+	return options
+}
+
+func sensitiveInfo(option string) bool {
+	return option == libclient.Password || option == libclient.PrivateKey
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	libclient "github.com/kubev2v/forklift/pkg/lib/client/libvirt"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	domainID = "a5f33d2c-7e3b-4f0a-9a0c-0f1b9d1c2e3f"
+	diskSize = 2048
+)
+
+// Export (block) of a precopy.
+type block struct {
+	data  byte
+	flags uint32
+}
+
+// Mock host.
+// The backup of precopy 0 has blocks 0 and 2 allocated. The (final)
+// backup of precopy 1 changed block 1 and cleared block 2.
+type mockHost struct {
+	t        *testing.T
+	mutex    sync.Mutex
+	precopy  int
+	metadata map[string][]byte
+	// Exports by meta context.
+	exports map[int]map[string][]block
+}
+
+func newMockHost(t *testing.T) *mockHost {
+	return &mockHost{
+		t:        t,
+		metadata: map[string][]byte{},
+		exports: map[int]map[string][]block{
+			0: {
+				libclient.BaseAllocation: {
+					{'0', 0},
+					{0, libclient.HoleFlag | libclient.ZeroFlag},
+					{'0', 0},
+					{0, libclient.HoleFlag | libclient.ZeroFlag},
+				},
+			},
+			1: {
+				libclient.DirtyBitmap + libclient.ExportBitmap("vda"): {
+					{'0', 0},
+					{'1', libclient.DirtyFlag},
+					{0, libclient.DirtyFlag},
+					{0, 0},
+				},
+			},
+		},
+	}
+}
+
+func (r *mockHost) GetBackup(id string) (backup *libclient.DomainBackup, found bool, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	backup = &libclient.DomainBackup{
+		Mode: "pull",
+		Server: &libclient.BackupServer{
+			Transport: "unix",
+			Socket:    libclient.BackupSocket("migration-1", r.precopy, r.precopy == 1),
+		},
+		Disks: []libclient.BackupDisk{
+			{Name: "vda", Backup: "yes"},
+			{Name: "vdb", Backup: "no"},
+		},
+	}
+	if r.precopy > 0 {
+		backup.Incremental = libclient.CheckpointName("migration-1", r.precopy-1)
+		backup.Disks[0].ExportBitmap = libclient.ExportBitmap("vda")
+	}
+	found = true
+	return
+}
+
+func (r *mockHost) GetMetadata(id, uri string) (content []byte, found bool, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	content, found = r.metadata[uri]
+	return
+}
+
+// The next precopy is started once the backup has been copied.
+func (r *mockHost) SetMetadata(id, uri, key string, content []byte) (err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.metadata[uri] = content
+	if r.precopy == 0 {
+		r.precopy = 1
+	}
+	return
+}
+
+func (r *mockHost) DialSocket(path string) (conn net.Conn, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	precopy, _, _ := libclient.ParseBackupSocket(path, "migration-1")
+	client, server := net.Pipe()
+	go r.serve(server, r.exports[precopy])
+	conn = client
+	return
+}
+
+// Serve the NBD (fixed newstyle) protocol.
+func (r *mockHost) serve(conn net.Conn, exports map[string][]block) {
+	defer conn.Close()
+	be := binary.BigEndian
+	blockSize := uint32(diskSize / 4)
+	greeting := be.AppendUint64(nil, 0x4e42444d41474943)
+	greeting = be.AppendUint64(greeting, 0x49484156454f5054)
+	greeting = be.AppendUint16(greeting, 3)
+	_, _ = conn.Write(greeting)
+	_, _ = io.ReadFull(conn, make([]byte, 4))
+	reply := func(option, kind uint32, payload []byte) {
+		header := be.AppendUint64(nil, 0x0003e889045565a9)
+		header = be.AppendUint32(header, option)
+		header = be.AppendUint32(header, kind)
+		header = be.AppendUint32(header, uint32(len(payload)))
+		_, _ = conn.Write(append(header, payload...))
+	}
+	var blocks []block
+	for {
+		header := make([]byte, 16)
+		_, err := io.ReadFull(conn, header)
+		if err != nil {
+			return
+		}
+		option := be.Uint32(header[8:])
+		payload := make([]byte, be.Uint32(header[12:]))
+		_, _ = io.ReadFull(conn, payload)
+		switch option {
+		case 8: // structured reply
+			reply(option, 1, nil)
+		case 10: // set meta context
+			n := be.Uint32(payload)
+			context := string(payload[n+12:])
+			found := false
+			blocks, found = exports[context]
+			if !found {
+				r.t.Errorf("unexpected meta context: %s", context)
+				return
+			}
+			reply(option, 4, append(be.AppendUint32(nil, 1), context...))
+			reply(option, 1, nil)
+		case 7: // go
+			info := be.AppendUint16(nil, 0)
+			info = be.AppendUint64(info, diskSize)
+			info = be.AppendUint16(info, 0)
+			reply(option, 3, info)
+			reply(option, 1, nil)
+			goto transmission
+		}
+	}
+transmission:
+	for {
+		header := make([]byte, 28)
+		_, err := io.ReadFull(conn, header)
+		if err != nil {
+			return
+		}
+		command := be.Uint16(header[6:])
+		cookie := be.Uint64(header[8:])
+		offset := be.Uint64(header[16:])
+		length := be.Uint32(header[24:])
+		chunk := func(kind uint16, payload []byte) {
+			header := be.AppendUint32(nil, 0x668e33ef)
+			header = be.AppendUint16(header, 1)
+			header = be.AppendUint16(header, kind)
+			header = be.AppendUint64(header, cookie)
+			header = be.AppendUint32(header, uint32(len(payload)))
+			_, _ = conn.Write(append(header, payload...))
+		}
+		switch command {
+		case 7: // block status
+			status := be.AppendUint32(nil, 1)
+			for _, b := range blocks {
+				status = be.AppendUint32(status, blockSize)
+				status = be.AppendUint32(status, b.flags)
+			}
+			chunk(5, status)
+		case 0: // read
+			content := be.AppendUint64(nil, offset)
+			for n := uint32(0); n < length; n++ {
+				content = append(content, blocks[(uint32(offset)+n)/blockSize].data)
+			}
+			chunk(1, content)
+		case 2: // disconnect
+			return
+		}
+	}
+}
+
+func TestCopyBackups(t *testing.T) {
+	host := newMockHost(t)
+	fileName := filepath.Join(t.TempDir(), "disk.img")
+	config := &AppConfig{
+		domain:     domainID,
+		disk:       "vda",
+		migration:  "migration-1",
+		ownerUID:   "test-uid",
+		pvcSize:    diskSize,
+		volumePath: fileName,
+	}
+	progress := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "progress"}, []string{"ownerUID"})
+	copyBackups(host, config, progress)
+
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	if len(content) != diskSize {
+		t.Fatalf("Expected size %d, got %d", diskSize, len(content))
+	}
+	for index, expected := range []byte{'0', '1', 0, 0} {
+		chunk := content[index*512 : (index+1)*512]
+		if !bytes.Equal(chunk, bytes.Repeat([]byte{expected}, 512)) {
+			t.Errorf("Block %d: unexpected content", index)
+		}
+	}
+	copied, found, err := getCopied(host, config)
+	if err != nil || !found {
+		t.Fatalf("Expected the copied metadata: %v", err)
+	}
+	if copied.Precopy != 1 || !copied.Final {
+		t.Errorf("Unexpected copied metadata: %+v", copied)
+	}
+}
+
+func TestExportOf(t *testing.T) {
+	backup := &libclient.DomainBackup{
+		Disks: []libclient.BackupDisk{
+			{Name: "vda", Backup: "yes", ExportName: "disk-a"},
+			{Name: "vdb", Backup: "no"},
+		},
+	}
+	export, context, err := exportOf(backup, "vda", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if export != "disk-a" || !strings.HasPrefix(context, libclient.DirtyBitmap) {
+		t.Errorf("Unexpected export: %s context: %s", export, context)
+	}
+	_, _, err = exportOf(backup, "vdb", false)
+	if err == nil {
+		t.Errorf("Expected the disk not exported to fail")
+	}
+}
+
+func TestSplit(t *testing.T) {
+	chunks := split([]Extent{{Extent: libclient.Extent{Offset: 0, Length: chunkSize*2 + 10}}})
+	if len(chunks) != 3 {
+		t.Fatalf("Expected 3 chunks, got %d", len(chunks))
+	}
+	if chunks[1].Offset != chunkSize || chunks[1].Length != chunkSize || chunks[2].Length != 10 {
+		t.Errorf("Unexpected chunks: %v", chunks)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"io"
 	"os"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	libclient "github.com/kubev2v/forklift/pkg/lib/client/openstack"
@@ -23,8 +24,12 @@ type AppConfig struct {
 	ownerUID         string
 	pvcSize          int64
 	volumePath       string
+	offset           int64
 }
 
+// Bytes written between volume syncs.
+const syncInterval = 64 * 1024 * 1024
+
 func main() {
 	config := &AppConfig{}
 	flag.StringVar(&config.identityEndpoint, "endpoint", "", "endpoint URL (https://openstack.example.com:5000/v2.0)")
@@ -35,6 +40,7 @@ func main() {
 	flag.StringVar(&config.crNamespace, "cr-namespace", "", "Custom Resource instance namespace")
 	flag.StringVar(&config.ownerUID, "owner-uid", "", "Owner UID (usually PVC UID)")
 	flag.Int64Var(&config.pvcSize, "pvc-size", 0, "Size of pvc (in bytes)")
+	flag.Int64Var(&config.offset, "offset", 0, "Offset (in bytes) of the data already synced to the volume")
 	flag.Parse()
 
 	if config.pvcSize <= 0 {
@@ -72,8 +78,8 @@ func createClient(config *AppConfig) *libclient.Client {
 }
 
 func downloadAndSaveImage(client *libclient.Client, config *AppConfig) {
-	klog.Info("Downloading the image: ", config.imageID)
-	imageReader, err := client.DownloadImage(config.imageID)
+	klog.Info("Downloading the image: ", config.imageID, " from offset: ", config.offset)
+	imageReader, err := client.DownloadImageFrom(config.imageID, config.offset)
 	if err != nil {
 		klog.Fatal(err)
 	}
@@ -82,9 +88,13 @@ func downloadAndSaveImage(client *libclient.Client, config *AppConfig) {
 
 	file := openFile(config.volumePath)
 	defer file.Close()
+	if _, err = file.Seek(config.offset, io.SeekStart); err != nil {
+		klog.Fatal(err)
+	}
 
 	progressVec := createProgressCounter()
-	writeData(imageReader, file, config, progressVec)
+	offsetVec := createOffsetGauge()
+	writeData(imageReader, file, config, progressVec, offsetVec)
 }
 
 func createProgressCounter() *prometheus.CounterVec {
@@ -103,6 +113,22 @@ func createProgressCounter() *prometheus.CounterVec {
 	return progressVec
 }
 
+func createOffsetGauge() *prometheus.GaugeVec {
+	offsetVec := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "openstack_populator_offset",
+			Help: "Offset of the data synced to the volume",
+		},
+		[]string{"ownerUID"},
+	)
+
+	if err := prometheus.Register(offsetVec); err != nil {
+		klog.Error("Prometheus offset gauge not registered:", err)
+	}
+
+	return offsetVec
+}
+
 func openFile(volumePath string) *os.File {
 	flags := os.O_RDWR
 	if strings.HasSuffix(volumePath, "disk.img") {
@@ -115,25 +141,32 @@ func openFile(volumePath string) *os.File {
 	return file
 }
 
-func writeData(reader io.ReadCloser, file *os.File, config *AppConfig, progress *prometheus.CounterVec) {
-	countingReader := &CountingReader{reader: reader, total: config.pvcSize, read: new(int64)}
+func writeData(reader io.ReadCloser, file *os.File, config *AppConfig, progress *prometheus.CounterVec, offset *prometheus.GaugeVec) {
+	read := config.offset
+	countingReader := &CountingReader{reader: reader, total: config.pvcSize, read: &read}
+	syncingWriter := &SyncingWriter{file: file, offset: config.offset, synced: config.offset}
 	done := make(chan bool)
 
-	go reportProgress(done, countingReader, progress, config)
+	go reportProgress(done, countingReader, syncingWriter, progress, offset, config)
 
-	if _, err := io.Copy(file, countingReader); err != nil {
+	if _, err := io.Copy(syncingWriter, countingReader); err != nil {
+		klog.Fatal(err)
+	}
+	if err := syncingWriter.Sync(); err != nil {
 		klog.Fatal(err)
 	}
 	done <- true
 }
 
-func reportProgress(done chan bool, countingReader *CountingReader, progress *prometheus.CounterVec, config *AppConfig) {
+func reportProgress(done chan bool, countingReader *CountingReader, syncingWriter *SyncingWriter, progress *prometheus.CounterVec, offset *prometheus.GaugeVec, config *AppConfig) {
 	for {
 		select {
 		case <-done:
+			offset.WithLabelValues(config.ownerUID).Set(float64(syncingWriter.Synced()))
 			finalizeProgress(progress, config.ownerUID)
 			return
 		default:
+			offset.WithLabelValues(config.ownerUID).Set(float64(syncingWriter.Synced()))
 			updateProgress(countingReader, progress, config.ownerUID)
 			time.Sleep(1 * time.Second)
 		}
@@ -217,3 +250,34 @@ func (cr *CountingReader) Read(p []byte) (int, error) {
 	*cr.read += int64(n)
 	return n, err
 }
+
+// Writes to the volume and syncs it periodically.
+// A restarted populator resumes from the synced offset.
+type SyncingWriter struct {
+	file   *os.File
+	offset int64
+	synced int64
+}
+
+func (sw *SyncingWriter) Write(p []byte) (int, error) {
+	n, err := sw.file.Write(p)
+	sw.offset += int64(n)
+	if err == nil && sw.offset-sw.Synced() >= syncInterval {
+		err = sw.Sync()
+	}
+	return n, err
+}
+
+// Sync the volume and advance the synced offset.
+func (sw *SyncingWriter) Sync() error {
+	if err := sw.file.Sync(); err != nil {
+		return err
+	}
+	atomic.StoreInt64(&sw.synced, sw.offset)
+	return nil
+}
+
+// Synced offset.
+func (sw *SyncingWriter) Synced() int64 {
+	return atomic.LoadInt64(&sw.synced)
+}
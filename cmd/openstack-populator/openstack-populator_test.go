@@ -179,3 +179,46 @@ func TestPopulate(t *testing.T) {
 
 	os.Remove(fileName)
 }
+
+func TestPopulateResume(t *testing.T) {
+	t.Setenv("username", "testuser")
+	t.Setenv("password", "testpassword")
+	t.Setenv("projectName", "Default")
+	t.Setenv("domainName", "Default")
+	t.Setenv("insecureSkipVerify", "true")
+	t.Setenv("availability", "public")
+	t.Setenv("regionName", "RegionOne")
+	t.Setenv("authType", "password")
+
+	server, identityServerURL, _, err := setupMockServer()
+	if err != nil {
+		t.Fatalf("Failed to start mock server: %v", err)
+	}
+	defer server.Close()
+
+	fileName := "disk.img"
+	// Data synced by a previous populator.
+	if err := os.WriteFile(fileName, []byte("mock_"), 0650); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	defer os.Remove(fileName)
+
+	config := &AppConfig{
+		identityEndpoint: identityServerURL,
+		secretName:       "test-secret",
+		imageID:          "test-image-id",
+		ownerUID:         "test-uid",
+		pvcSize:          100,
+		volumePath:       fileName,
+		offset:           5,
+	}
+	populate(config)
+
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	if string(content) != "mock_data\n" {
+		t.Errorf("Expected %s, got %s", "mock_data", string(content))
+	}
+}
@@ -53,6 +53,27 @@ var populators = map[string]populator{
 		imageVar:        "VSPHERE_XCOPY_VOLUME_POPULATOR_IMAGE",
 		metricsEndpoint: ":8082",
 	},
+	"ec2": {
+		kind:            "EC2VolumePopulator",
+		resource:        "ec2volumepopulators",
+		controllerFunc:  getEC2PopulatorPodArgs,
+		imageVar:        "EC2_POPULATOR_IMAGE",
+		metricsEndpoint: ":8083",
+	},
+	"azure": {
+		kind:            "AzureVolumePopulator",
+		resource:        "azurevolumepopulators",
+		controllerFunc:  getAzurePopulatorPodArgs,
+		imageVar:        "AZURE_POPULATOR_IMAGE",
+		metricsEndpoint: ":8084",
+	},
+	"libvirt": {
+		kind:            "LibvirtVolumePopulator",
+		resource:        "libvirtvolumepopulators",
+		controllerFunc:  getLibvirtPopulatorPodArgs,
+		imageVar:        "LIBVIRT_POPULATOR_IMAGE",
+		metricsEndpoint: ":8085",
+	},
 }
 
 func main() {
@@ -133,6 +154,60 @@ func getOpenstackPopulatorPodArgs(rawBlock bool, u *unstructured.Unstructured, _
 	return args, nil
 }
 
+func getEC2PopulatorPodArgs(rawBlock bool, u *unstructured.Unstructured, _ corev1.PersistentVolumeClaim) ([]string, error) {
+	var ec2Populator v1beta1.EC2VolumePopulator
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), &ec2Populator)
+	if nil != err {
+		return nil, err
+	}
+	args := []string{}
+	args = append(args, "--volume-path="+getVolumePath(rawBlock))
+	args = append(args, "--endpoint="+ec2Populator.Spec.EndpointURL)
+	args = append(args, "--secret-name="+ec2Populator.Spec.SecretName)
+	args = append(args, "--snapshot-id="+ec2Populator.Spec.SnapshotID)
+	args = append(args, "--cr-name="+ec2Populator.Name)
+	args = append(args, "--cr-namespace="+ec2Populator.Namespace)
+
+	return args, nil
+}
+
+func getAzurePopulatorPodArgs(rawBlock bool, u *unstructured.Unstructured, _ corev1.PersistentVolumeClaim) ([]string, error) {
+	var azurePopulator v1beta1.AzureVolumePopulator
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), &azurePopulator)
+	if nil != err {
+		return nil, err
+	}
+	args := []string{}
+	args = append(args, "--volume-path="+getVolumePath(rawBlock))
+	args = append(args, "--endpoint="+azurePopulator.Spec.EndpointURL)
+	args = append(args, "--secret-name="+azurePopulator.Spec.SecretName)
+	args = append(args, "--disk-id="+azurePopulator.Spec.DiskID)
+	args = append(args, "--migration="+azurePopulator.Spec.Migration)
+	args = append(args, "--cr-name="+azurePopulator.Name)
+	args = append(args, "--cr-namespace="+azurePopulator.Namespace)
+
+	return args, nil
+}
+
+func getLibvirtPopulatorPodArgs(rawBlock bool, u *unstructured.Unstructured, _ corev1.PersistentVolumeClaim) ([]string, error) {
+	var libvirtPopulator v1beta1.LibvirtVolumePopulator
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), &libvirtPopulator)
+	if nil != err {
+		return nil, err
+	}
+	args := []string{}
+	args = append(args, "--volume-path="+getVolumePath(rawBlock))
+	args = append(args, "--url="+libvirtPopulator.Spec.URL)
+	args = append(args, "--secret-name="+libvirtPopulator.Spec.SecretName)
+	args = append(args, "--domain="+libvirtPopulator.Spec.DomainID)
+	args = append(args, "--disk="+libvirtPopulator.Spec.Disk)
+	args = append(args, "--migration="+libvirtPopulator.Spec.Migration)
+	args = append(args, "--cr-name="+libvirtPopulator.Name)
+	args = append(args, "--cr-namespace="+libvirtPopulator.Namespace)
+
+	return args, nil
+}
+
 func getVXPopulatorPodArgs(_ bool, u *unstructured.Unstructured, pvc corev1.PersistentVolumeClaim) ([]string, error) {
 	var xcopy v1beta1.VSphereXcopyVolumePopulator
 	err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), &xcopy)
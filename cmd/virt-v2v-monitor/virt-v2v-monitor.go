@@ -2,11 +2,13 @@ package main
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"os"
 	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -17,6 +19,141 @@ var COPY_DISK_RE = regexp.MustCompile(`^.*Copying disk (\d+)/(\d+)`)
 var DISK_PROGRESS_RE = regexp.MustCompile(`.+ (\d+)% \[[*-]+\]`)
 var FINISHED_RE = regexp.MustCompile(`^\[[ .0-9]*\] Finishing off`)
 
+// Machine-readable (--machine-readable) progress of the disk copy.
+var MACHINE_PROGRESS_RE = regexp.MustCompile(`^(\d+)/(\d+)$`)
+
+// Human-readable progress message.
+var MESSAGE_RE = regexp.MustCompile(`^\[[ .0-9]*\] (.+)$`)
+
+// Conversion stages.
+const (
+	StageInspect        = "Inspect"
+	StageConvertDrivers = "ConvertDrivers"
+	StageBootloader     = "Bootloader"
+	StageCopyDisks      = "CopyDisks"
+	StageFinished       = "Finished"
+)
+
+// Conversion stage.
+type Stage struct {
+	// Name.
+	Name string
+	// Leading part of the virt-v2v messages starting the stage.
+	Messages []string
+	// Overall progress (percent) when the stage starts.
+	Start uint64
+	// Overall progress (percent) when the stage ends.
+	End uint64
+}
+
+// Conversion stages in the order reported by virt-v2v.
+var STAGES = []Stage{
+	{
+		Name:     StageInspect,
+		Messages: []string{"Setting up the source", "Opening the source", "Inspecting the source"},
+		Start:    0,
+		End:      10,
+	},
+	{
+		Name:     StageConvertDrivers,
+		Messages: []string{"Converting "},
+		Start:    10,
+		End:      30,
+	},
+	{
+		Name:     StageBootloader,
+		Messages: []string{"Assigning disks to buses", "Checking if the guest needs BIOS or UEFI to boot"},
+		Start:    30,
+		End:      35,
+	},
+	{
+		Name:     StageCopyDisks,
+		Messages: []string{"Copying disk "},
+		Start:    35,
+		End:      99,
+	},
+	{
+		Name:     StageFinished,
+		Messages: []string{"Creating output metadata", "Finishing off"},
+		Start:    100,
+		End:      100,
+	},
+}
+
+// Machine-readable virt-v2v message.
+type Message struct {
+	Message string `json:"message"`
+	Type    string `json:"type"`
+}
+
+// Parse the progress message of a virt-v2v output line.
+// Both the human-readable and the machine-readable (JSON)
+// messages are supported.
+func ParseMessage(line []byte) (message string, found bool) {
+	if len(line) > 0 && line[0] == '{' {
+		m := Message{}
+		if json.Unmarshal(line, &m) == nil && m.Type == "message" {
+			message, found = m.Message, true
+		}
+		return
+	}
+	if match := MESSAGE_RE.FindSubmatch(line); match != nil {
+		message, found = string(match[1]), true
+	}
+	return
+}
+
+// Find the stage started by the message.
+func FindStage(message string) (stage *Stage, found bool) {
+	for i := range STAGES {
+		for _, prefix := range STAGES[i].Messages {
+			if strings.HasPrefix(message, prefix) {
+				stage, found = &STAGES[i], true
+				return
+			}
+		}
+	}
+	return
+}
+
+// Overall conversion progress (percent) within the stage.
+// The progress of the disk copy is spread across the stage.
+func Progress(stage *Stage, disk, disks, progress uint64) uint64 {
+	if stage.Name != StageCopyDisks || disks == 0 || disk == 0 {
+		return stage.Start
+	}
+	done := float64(disk-1)/float64(disks) + float64(progress)/100/float64(disks)
+	return stage.Start + uint64(done*float64(stage.End-stage.Start))
+}
+
+// Conversion stage tracker.
+type Tracker struct {
+	// Current stage.
+	stage *Stage
+	// Current stage gauge.
+	stageGauge *prometheus.GaugeVec
+	// Overall progress gauge.
+	progressGauge prometheus.Gauge
+}
+
+// Update the stage and the overall progress.
+// Stages never move backwards.
+func (r *Tracker) Update(stage *Stage, disk, disks, progress uint64) {
+	if stage != nil && (r.stage == nil || stage.Start >= r.stage.Start) {
+		if r.stage != nil && r.stage.Name != stage.Name {
+			r.stageGauge.WithLabelValues(r.stage.Name).Set(0)
+		}
+		if r.stage == nil || r.stage.Name != stage.Name {
+			fmt.Printf("virt-v2v monitoring: Stage %s\n", stage.Name)
+		}
+		r.stage = stage
+		r.stageGauge.WithLabelValues(stage.Name).Set(1)
+	}
+	if r.stage != nil {
+		r.progressGauge.Set(float64(Progress(r.stage, disk, disks, progress)))
+	}
+}
+
 // Here is a scan function that imposes limit on returned line length. virt-v2v
 // writes some overly long lines that don't fit into the internal buffer of
 // Scanner. We could just provide bigger buffer, but it is hard to guess what
@@ -84,6 +221,30 @@ func main() {
 	}
 	fmt.Println("virt-v2v monitoring: Prometheus progress counter registered.")
 
+	tracker := &Tracker{
+		stageGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Subsystem: "v2v",
+				Name:      "conversion_stage",
+				Help:      "Current conversion stage",
+			},
+			[]string{"stage"},
+		),
+		progressGauge: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Subsystem: "v2v",
+				Name:      "conversion_progress",
+				Help:      "Percent of the conversion completed",
+			},
+		),
+	}
+	for _, collector := range []prometheus.Collector{tracker.stageGauge, tracker.progressGauge} {
+		if err := prometheus.Register(collector); err != nil {
+			// Stages are informative only.
+			fmt.Println("virt-v2v monitoring: Prometheus stage gauge not registered:", err)
+		}
+	}
+
 	var diskNumber uint64 = 0
 	var disks uint64 = 0
 	var progress uint64
@@ -100,6 +261,11 @@ func main() {
 			os.Exit(1)
 		}
 
+		var stage *Stage
+		if message, found := ParseMessage(line); found {
+			stage, _ = FindStage(message)
+		}
+
 		if match := COPY_DISK_RE.FindSubmatch(line); match != nil {
 			diskNumber, _ = strconv.ParseUint(string(match[1]), 10, 0)
 			disks, _ = strconv.ParseUint(string(match[2]), 10, 0)
@@ -110,7 +276,14 @@ func main() {
 			progress, _ = strconv.ParseUint(string(match[1]), 10, 0)
 			fmt.Printf("virt-v2v monitoring: Progress update, completed %d %%\n", progress)
 			err = updateProgress(progressCounter, diskNumber, progress)
-		} else if match := FINISHED_RE.Find(line); match != nil {
+		} else if match := MACHINE_PROGRESS_RE.FindSubmatch(line); match != nil && diskNumber > 0 {
+			position, _ := strconv.ParseUint(string(match[1]), 10, 0)
+			total, _ := strconv.ParseUint(string(match[2]), 10, 0)
+			if total > 0 {
+				progress = position * 100 / total
+				err = updateProgress(progressCounter, diskNumber, progress)
+			}
+		} else if match := FINISHED_RE.Find(line); match != nil || (stage != nil && stage.Name == StageFinished) {
 			// Make sure we flag conversion as finished. This is
 			// just in case we miss the last progress update for some reason.
 			fmt.Println("virt-v2v monitoring: Finished")
@@ -119,6 +292,8 @@ func main() {
 			}
 		}
 
+		tracker.Update(stage, diskNumber, disks, progress)
+
 		if err != nil {
 			// Don't make processing errors fatal.
 			fmt.Println("virt-v2v monitoring: Error updating progress: ", err)
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestParseMessage(t *testing.T) {
+	cases := []struct {
+		line    string
+		message string
+		found   bool
+	}{
+		{`[   1.2] Opening the source`, "Opening the source", true},
+		{`{ "message": "Inspecting the source", "timestamp": "2024-01-01T00:00:00.000000000+00:00", "type": "message" }`, "Inspecting the source", true},
+		{`{ "message": "guest is not fully shut down", "type": "warning" }`, "", false},
+		{`libguestfs: trace: launch`, "", false},
+	}
+	for _, c := range cases {
+		message, found := ParseMessage([]byte(c.line))
+		if message != c.message || found != c.found {
+			t.Errorf("ParseMessage(%q) = %q, %v", c.line, message, found)
+		}
+	}
+}
+
+func TestFindStage(t *testing.T) {
+	cases := map[string]string{
+		"Inspecting the source":                                     StageInspect,
+		"Converting Windows Server 2019 Standard to run on KVM":     StageConvertDrivers,
+		"Checking if the guest needs BIOS or UEFI to boot":          StageBootloader,
+		"Copying disk 1/2":                                          StageCopyDisks,
+		"Finishing off":                                             StageFinished,
+		"Mapping filesystem data to avoid copying unused and blank": "",
+	}
+	for message, name := range cases {
+		stage, found := FindStage(message)
+		if (name == "" && found) || (name != "" && (!found || stage.Name != name)) {
+			t.Errorf("FindStage(%q) = %v, %v", message, stage, found)
+		}
+	}
+}
+
+func TestProgress(t *testing.T) {
+	inspect, _ := FindStage("Opening the source")
+	copying, _ := FindStage("Copying disk 1/2")
+	finished, _ := FindStage("Finishing off")
+	cases := []struct {
+		stage                 *Stage
+		disk, disks, progress uint64
+		expected              uint64
+	}{
+		{inspect, 0, 0, 0, 0},
+		{copying, 1, 2, 0, 35},
+		{copying, 1, 2, 100, 67},
+		{copying, 2, 2, 100, 99},
+		{finished, 2, 2, 100, 100},
+	}
+	for _, c := range cases {
+		progress := Progress(c.stage, c.disk, c.disks, c.progress)
+		if progress != c.expected {
+			t.Errorf("Progress(%s, %d, %d, %d) = %d, expected %d", c.stage.Name, c.disk, c.disks, c.progress, progress, c.expected)
+		}
+	}
+}
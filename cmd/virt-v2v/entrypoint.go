@@ -1,14 +1,17 @@
 package main
 
 import (
+	"context"
 	_ "embed"
 	"fmt"
 	"os"
 
+	"github.com/kubev2v/forklift/pkg/lib/tracing"
 	"github.com/kubev2v/forklift/pkg/virt-v2v/config"
 	"github.com/kubev2v/forklift/pkg/virt-v2v/conversion"
 	"github.com/kubev2v/forklift/pkg/virt-v2v/server"
 	utils "github.com/kubev2v/forklift/pkg/virt-v2v/utils"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 func main() {
@@ -26,40 +29,57 @@ func main() {
 		fmt.Println("Failed to create v2v output dir", err)
 		os.Exit(1)
 	}
+	// Trace the conversion within the migration trace propagated by the controller.
+	ctx, shutdown, err := tracing.SetupFromEnv(context.Background(), "virt-v2v")
+	if err != nil {
+		fmt.Println("Proceeding without tracing", err)
+	}
+	exit := func(code int) {
+		_ = shutdown(context.Background())
+		os.Exit(code)
+	}
 	convert, err := conversion.NewConversion(env)
 	if err != nil {
 		fmt.Println("Failed prepare conversion", err)
-		os.Exit(1)
+		exit(1)
 	}
 
 	// virt-v2v or virt-v2v-in-place
+	_, span := tracing.Start(ctx, "virt-v2v", attribute.Bool("inPlace", convert.IsInPlace))
 	if convert.IsInPlace {
 		err = convert.RunVirtV2vInPlace()
 	} else {
 		err = convert.RunVirtV2v()
 	}
+	tracing.End(span, err)
 	if err != nil {
 		fmt.Println("Failed to execute virt-v2v command", err)
-		os.Exit(1)
+		exit(1)
 	}
 
 	// virt-v2v-inspector
+	_, span = tracing.Start(ctx, "virt-v2v-inspector")
 	err = convert.RunVirtV2VInspection()
+	tracing.End(span, err)
 	if err != nil {
 		fmt.Println("Failed to inspect the disk", err)
-		os.Exit(1)
+		exit(1)
 	}
 	inspection, err := utils.GetInspectionV2vFromFile(convert.InspectionOutputFile)
 	if err != nil {
 		fmt.Println("Failed to get inspection file", err)
-		os.Exit(1)
+		exit(1)
 	}
 
 	// virt-customize
+	_, span = tracing.Start(ctx, "virt-customize")
 	err = convert.RunCustomize(inspection.OS)
+	tracing.End(span, err)
 	if err != nil {
 		fmt.Println("Failed to customize the VM", err)
 	}
+	// Flush the spans, the server may block until the pod is terminated.
+	_ = shutdown(context.Background())
 	// In the remote migrations we can not connect to the conversion pod from the controller.
 	// This connection is needed for to get the additional configuration which is gathered either form virt-v2v or
 	// virt-v2v-inspector. We expose those parameters via server in this pod and once the controller gets the config
@@ -71,7 +91,7 @@ func main() {
 		err = s.Start()
 		if err != nil {
 			fmt.Println("failed to run the server", err)
-			os.Exit(1)
+			exit(1)
 		}
 	}
 }
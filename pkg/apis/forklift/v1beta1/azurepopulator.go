@@ -0,0 +1,51 @@
+package v1beta1
+
+import (
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var AzureVolumePopulatorKind = "AzureVolumePopulator"
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:shortName={azvp,azvps}
+type AzureVolumePopulator struct {
+	meta.TypeMeta   `json:",inline"`
+	meta.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec AzureVolumePopulatorSpec `json:"spec"`
+	// +optional
+	Status AzureVolumePopulatorStatus `json:"status"`
+}
+
+type AzureVolumePopulatorSpec struct {
+	// The Azure Resource Manager endpoint.
+	EndpointURL string `json:"endpointUrl"`
+	SecretName  string `json:"secretName"`
+	// The (ARM) resource ID of the managed disk.
+	DiskID string `json:"diskId"`
+	// The migration the disk snapshots are tagged with.
+	// The incremental snapshots are copied in order until
+	// the final snapshot has been copied.
+	Migration string `json:"migration"`
+	// The network attachment definition that should be used for disk transfer.
+	TransferNetwork *core.ObjectReference `json:"transferNetwork,omitempty"`
+}
+
+type AzureVolumePopulatorStatus struct {
+	// +optional
+	Progress string `json:"progress"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type AzureVolumePopulatorList struct {
+	meta.TypeMeta `json:",inline"`
+	meta.ListMeta `json:"metadata,omitempty"`
+	Items         []AzureVolumePopulator `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AzureVolumePopulator{}, &AzureVolumePopulatorList{})
+}
@@ -55,6 +55,7 @@ const (
 	PhaseStarted   = "Started"
 	PhasePreHook   = "PreHook"
 	PhasePostHook  = "PostHook"
+	PhaseRunTests  = "RunTests"
 	PhaseCompleted = "Completed"
 )
 
@@ -79,6 +80,7 @@ const (
 	PhaseRemoveFinalSnapshot               = "RemoveFinalSnapshot"
 	PhaseRemovePenultimateSnapshot         = "RemovePenultimateSnapshot"
 	PhaseRemovePreviousSnapshot            = "RemovePreviousSnapshot"
+	PhaseResyncDataVolumes                 = "ResyncDataVolumes"
 	PhaseStoreInitialSnapshotDeltas        = "StoreInitialSnapshotDeltas"
 	PhaseStorePowerState                   = "StorePowerState"
 	PhaseStoreSnapshotDeltas               = "StoreSnapshotDeltas"
@@ -93,6 +95,13 @@ const (
 	PhaseWaitForSnapshot                   = "WaitForSnapshot"
 )
 
+// Disk re-copy phases.
+const (
+	PhaseStopVM                = "StopVM"
+	PhaseDeleteRecopiedVolumes = "DeleteRecopiedVolumes"
+	PhaseStartVM               = "StartVM"
+)
+
 // Step/task phases.
 const (
 	StepStarted   = "Started"
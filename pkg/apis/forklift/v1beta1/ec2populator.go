@@ -0,0 +1,47 @@
+package v1beta1
+
+import (
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var EC2VolumePopulatorKind = "EC2VolumePopulator"
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:shortName={ec2vp,ec2vps}
+type EC2VolumePopulator struct {
+	meta.TypeMeta   `json:",inline"`
+	meta.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec EC2VolumePopulatorSpec `json:"spec"`
+	// +optional
+	Status EC2VolumePopulatorStatus `json:"status"`
+}
+
+type EC2VolumePopulatorSpec struct {
+	// The (regional) EC2 endpoint.
+	EndpointURL string `json:"endpointUrl"`
+	SecretName  string `json:"secretName"`
+	// The EBS snapshot read using the EBS direct APIs.
+	SnapshotID string `json:"snapshotId"`
+	// The network attachment definition that should be used for disk transfer.
+	TransferNetwork *core.ObjectReference `json:"transferNetwork,omitempty"`
+}
+
+type EC2VolumePopulatorStatus struct {
+	// +optional
+	Progress string `json:"progress"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type EC2VolumePopulatorList struct {
+	meta.TypeMeta `json:",inline"`
+	meta.ListMeta `json:"metadata,omitempty"`
+	Items         []EC2VolumePopulator `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&EC2VolumePopulator{}, &EC2VolumePopulatorList{})
+}
@@ -0,0 +1,53 @@
+package v1beta1
+
+import (
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var LibvirtVolumePopulatorKind = "LibvirtVolumePopulator"
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:shortName={lvp,lvps}
+type LibvirtVolumePopulator struct {
+	meta.TypeMeta   `json:",inline"`
+	meta.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec LibvirtVolumePopulatorSpec `json:"spec"`
+	// +optional
+	Status LibvirtVolumePopulatorStatus `json:"status"`
+}
+
+type LibvirtVolumePopulatorSpec struct {
+	// The libvirt connection URL. Example: qemu+ssh://root@host/system
+	URL        string `json:"url"`
+	SecretName string `json:"secretName"`
+	// The UUID of the domain.
+	DomainID string `json:"domainId"`
+	// The target (name) of the disk. Example: vda
+	Disk string `json:"disk"`
+	// The migration the disk backups are created by.
+	// The backup of each precopy is copied until the
+	// final backup has been copied.
+	Migration string `json:"migration"`
+	// The network attachment definition that should be used for disk transfer.
+	TransferNetwork *core.ObjectReference `json:"transferNetwork,omitempty"`
+}
+
+type LibvirtVolumePopulatorStatus struct {
+	// +optional
+	Progress string `json:"progress"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type LibvirtVolumePopulatorList struct {
+	meta.TypeMeta `json:",inline"`
+	meta.ListMeta `json:"metadata,omitempty"`
+	Items         []LibvirtVolumePopulator `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&LibvirtVolumePopulator{}, &LibvirtVolumePopulatorList{})
+}
@@ -18,11 +18,14 @@ package v1beta1
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
 
 	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/provider"
 	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
 	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
 	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -32,13 +35,19 @@ type DestinationNetwork struct {
 	// Valid values:
 	// - pod: Use the Kubernetes pod network
 	// - multus: Use a Multus additional network
+	// - sriov: Use an SR-IOV additional network
+	// - udn: Use an OVN-Kubernetes secondary user-defined network
 	// - ignored: Network is excluded from mapping
-	// +kubebuilder:validation:Enum=pod;multus;ignored
+	// +kubebuilder:validation:Enum=pod;multus;sriov;udn;ignored
 	Type string `json:"type"`
-	// The namespace (multus only).
+	// The namespace (multus, sriov and udn only).
 	Namespace string `json:"namespace,omitempty"`
 	// The name.
 	Name string `json:"name,omitempty"`
+	// The SR-IOV device resource name requested
+	// by the network attachment definition (sriov only).
+	// +optional
+	ResourceName string `json:"resourceName,omitempty"`
 }
 
 // Mapped network.
@@ -47,6 +56,76 @@ type NetworkPair struct {
 	Source ref.Ref `json:"source"`
 	// Destination network.
 	Destination DestinationNetwork `json:"destination"`
+	// Restrict the mapping to specific VMs and NICs. Scoped mappings
+	// take precedence over unscoped mappings of the same source network.
+	// +optional
+	Scope *NetworkPairScope `json:"scope,omitempty"`
+}
+
+// Network mapping scope.
+type NetworkPairScope struct {
+	// VMs the mapping applies to.
+	// +optional
+	VMs []ref.Ref `json:"vms,omitempty"`
+	// Indexes (in the source VM order) of the NICs the mapping applies to.
+	// +optional
+	NICs []int `json:"nics,omitempty"`
+}
+
+// Whether the mapping applies to the NIC of the VM.
+func (r *NetworkPair) AppliesTo(vmRef ref.Ref, nic int) bool {
+	if r.Scope == nil {
+		return true
+	}
+	if len(r.Scope.VMs) > 0 {
+		found := false
+		for _, scoped := range r.Scope.VMs {
+			if scoped.ID != "" && scoped.ID == vmRef.ID ||
+				scoped.ID == "" && scoped.Name != "" && scoped.Name == vmRef.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(r.Scope.NICs) > 0 {
+		found := false
+		for _, index := range r.Scope.NICs {
+			if index == nic {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Whether the source network has the name and namespace.
+func (r *NetworkPair) SourceNamed(namespace, name string) bool {
+	if r.Source.Namespace != "" {
+		return r.Source.Namespace == namespace && r.Source.Name == name
+	}
+	return r.Source.Name == fmt.Sprintf("%s/%s", namespace, name)
+}
+
+// Specificity of the mapping. Mappings scoped to VMs rank
+// above mappings scoped to NICs, which rank above unscoped mappings.
+func (r *NetworkPair) Specificity() (n int) {
+	if r.Scope == nil {
+		return
+	}
+	if len(r.Scope.VMs) > 0 {
+		n += 2
+	}
+	if len(r.Scope.NICs) > 0 {
+		n++
+	}
+	return
 }
 
 // OffloadPlugin is a storage plugin that acts on the storage allocation and copying
@@ -97,6 +176,103 @@ type DestinationStorage struct {
 	// Access mode.
 	// +kubebuilder:validation:Enum=ReadWriteOnce;ReadWriteMany;ReadOnlyMany
 	AccessMode core.PersistentVolumeAccessMode `json:"accessMode,omitempty"`
+	// Access modes, takes precedence over the access mode.
+	// +kubebuilder:validation:items:Enum=ReadWriteOnce;ReadWriteMany;ReadOnlyMany
+	// +optional
+	AccessModes []core.PersistentVolumeAccessMode `json:"accessModes,omitempty"`
+	// Annotations added to the created DataVolumes and PVCs.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Access modes pinned by the mapping. Empty when
+// the storage profile should decide.
+func (r *DestinationStorage) GetAccessModes() []core.PersistentVolumeAccessMode {
+	if len(r.AccessModes) > 0 {
+		return r.AccessModes
+	}
+	if r.AccessMode != "" {
+		return []core.PersistentVolumeAccessMode{r.AccessMode}
+	}
+	return nil
+}
+
+// Add the mapping annotations to the object annotations.
+// Annotations already set on the object are not replaced.
+func (r *DestinationStorage) Annotate(annotations map[string]string) map[string]string {
+	if len(r.Annotations) == 0 {
+		return annotations
+	}
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	for k, v := range r.Annotations {
+		if _, found := annotations[k]; !found {
+			annotations[k] = v
+		}
+	}
+	return annotations
+}
+
+// Rule based storage mapping.
+type StorageRule struct {
+	// Rules with a higher priority are evaluated first.
+	// +optional
+	Priority int `json:"priority,omitempty"`
+	// Source storage match.
+	Match StorageMatch `json:"match"`
+	// Destination storage.
+	Destination DestinationStorage `json:"destination"`
+	// Offload Plugin
+	// +optional
+	OffloadPlugin *OffloadPlugin `json:"offloadPlugin,omitempty"`
+}
+
+// Source storage match, all specified criteria must match.
+type StorageMatch struct {
+	// Regular expression matched against the storage name.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Storage tier. The datastore type (vSphere), storage
+	// type (oVirt) or volume type name (OpenStack).
+	// +optional
+	Tier string `json:"tier,omitempty"`
+	// Minimum capacity.
+	// +optional
+	MinCapacity *resource.Quantity `json:"minCapacity,omitempty"`
+	// Maximum capacity.
+	// +optional
+	MaxCapacity *resource.Quantity `json:"maxCapacity,omitempty"`
+}
+
+// Source storage attributes matched by the rules.
+type StorageAttributes struct {
+	// Storage name.
+	Name string
+	// Storage tier.
+	Tier string
+	// Capacity in bytes, zero when unknown.
+	Capacity int64
+}
+
+// Whether the storage matches.
+func (r *StorageMatch) Matches(storage StorageAttributes) bool {
+	if r.Name != "" {
+		matched, err := regexp.MatchString(r.Name, storage.Name)
+		if err != nil || !matched {
+			return false
+		}
+	}
+	if r.Tier != "" && !strings.EqualFold(r.Tier, storage.Tier) {
+		return false
+	}
+	if r.MinCapacity != nil && storage.Capacity < r.MinCapacity.Value() {
+		return false
+	}
+	if r.MaxCapacity != nil && (storage.Capacity == 0 || storage.Capacity > r.MaxCapacity.Value()) {
+		return false
+	}
+	return true
 }
 
 // Network map spec.
@@ -113,6 +289,9 @@ type StorageMapSpec struct {
 	Provider provider.Pair `json:"provider"`
 	// Map.
 	Map []StoragePair `json:"map"`
+	// Rules mapping the source storage not explicitly mapped.
+	// +optional
+	Rules []StorageRule `json:"rules,omitempty"`
 }
 
 // MapStatus defines the observed state of Maps.
@@ -154,6 +333,22 @@ func (r *NetworkMap) FindNetwork(networkID string) (pair NetworkPair, found bool
 	return
 }
 
+// Find the most specific network mapping that applies to the NIC
+// (by index) of the VM among the mappings for which match returns true.
+func (r *NetworkMap) FindNetworkForNIC(vmRef ref.Ref, nic int, match func(pair *NetworkPair) bool) (pair *NetworkPair) {
+	for i := range r.Spec.Map {
+		candidate := &r.Spec.Map[i]
+		if !match(candidate) || !candidate.AppliesTo(vmRef, nic) {
+			continue
+		}
+		if pair == nil || candidate.Specificity() > pair.Specificity() {
+			pair = candidate
+		}
+	}
+
+	return
+}
+
 // Find network map for source type.
 func (r *NetworkMap) FindNetworkByType(networkType string) (pair NetworkPair, found bool) {
 	for _, pair = range r.Spec.Map {
@@ -169,12 +364,7 @@ func (r *NetworkMap) FindNetworkByType(networkType string) (pair NetworkPair, fo
 // Find network map for source name and namespace.
 func (r *NetworkMap) FindNetworkByNameAndNamespace(namespace, name string) (pair NetworkPair, found bool) {
 	for _, pair = range r.Spec.Map {
-		if pair.Source.Namespace != "" {
-			if pair.Source.Namespace == namespace && pair.Source.Name == name {
-				found = true
-				break
-			}
-		} else if pair.Source.Name == fmt.Sprintf("%s/%s", namespace, name) {
+		if pair.SourceNamed(namespace, name) {
 			found = true
 			break
 		}
@@ -230,6 +420,23 @@ func (r *StorageMap) FindStorageByName(storageName string) (pair StoragePair, fo
 	return
 }
 
+// Find the highest priority rule matching the storage.
+// Rules with the same priority are evaluated in order.
+func (r *StorageMap) FindStorageRule(storage StorageAttributes) (rule *StorageRule, found bool) {
+	for i := range r.Spec.Rules {
+		candidate := &r.Spec.Rules[i]
+		if !candidate.Match.Matches(storage) {
+			continue
+		}
+		if !found || candidate.Priority > rule.Priority {
+			rule = candidate
+			found = true
+		}
+	}
+
+	return
+}
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 type StorageMapList struct {
 	meta.TypeMeta `json:",inline"`
@@ -33,6 +33,35 @@ type MigrationSpec struct {
 	// Date and time to finalize a warm migration.
 	// If present, this will override the value set on the Plan.
 	Cutover *meta.Time `json:"cutover,omitempty"`
+	// Plan groups (requiring approval) approved to be started.
+	Approved []string `json:"approved,omitempty"`
+	// Plan approval gates approved to be run.
+	GateApprovals []plan.GateApproval `json:"gateApprovals,omitempty"`
+	// Disks of migrated VMs to be copied again.
+	Recopy []plan.DiskRecopy `json:"recopy,omitempty"`
+}
+
+// GateApproval returns the approval of the gate.
+func (r *MigrationSpec) GateApproval(gate string) (approval *plan.GateApproval, found bool) {
+	for i := range r.GateApprovals {
+		if r.GateApprovals[i].Gate == gate {
+			approval = &r.GateApprovals[i]
+			found = true
+			break
+		}
+	}
+	return
+}
+
+// GroupApproved indicates whether the group has been approved.
+func (r *MigrationSpec) GroupApproved(name string) (found bool) {
+	for _, approved := range r.Approved {
+		if approved == name {
+			found = true
+			break
+		}
+	}
+	return
 }
 
 // Canceled indicates whether a VM ref is present
@@ -58,6 +87,19 @@ func (r *MigrationSpec) Canceled(ref ref.Ref) (found bool) {
 	return
 }
 
+// RecopiedPVCs returns the PVCs of the VM to be copied again.
+func (r *MigrationSpec) RecopiedPVCs(ref ref.Ref) (pvcs []string) {
+	if ref.ID == "" {
+		return
+	}
+	for _, recopy := range r.Recopy {
+		if recopy.VM.ID == ref.ID && recopy.PVC != "" {
+			pvcs = append(pvcs, recopy.PVC)
+		}
+	}
+	return
+}
+
 // MigrationStatus defines the observed state of Migration
 type MigrationStatus struct {
 	plan.Timed `json:",inline"`
@@ -33,12 +33,21 @@ type PlanSpec struct {
 	Description string `json:"description,omitempty"`
 	// Target namespace.
 	TargetNamespace string `json:"targetNamespace"`
+	// Template of the target namespace when created by the plan.
+	// +optional
+	TargetNamespaceTemplate *plan.NamespaceTemplate `json:"targetNamespaceTemplate,omitempty"`
 	// Providers.
 	Provider provider.Pair `json:"provider"`
 	// Resource mapping.
 	Map plan.Map `json:"map"`
 	// List of VMs.
 	VMs []plan.VM `json:"vms"`
+	// Migration groups. By default, groups are migrated in the order listed.
+	// +optional
+	Groups []plan.Group `json:"groups,omitempty"`
+	// Destructive phases requiring approval before they run.
+	// +optional
+	ApprovalGates *plan.ApprovalGates `json:"approvalGates,omitempty"`
 	// Whether this is a warm migration.
 	Warm bool `json:"warm,omitempty"`
 	// The network attachment definition that should be used for disk transfer.
@@ -49,6 +58,14 @@ type PlanSpec struct {
 	PreserveClusterCPUModel bool `json:"preserveClusterCpuModel,omitempty"`
 	// Preserve static IPs of VMs in vSphere
 	PreserveStaticIPs bool `json:"preserveStaticIPs,omitempty"`
+	// Preserve the dedicated CPU placement, NUMA topology and hugepages
+	// of the VMs (vSphere latency sensitivity, oVirt CPU pinning and
+	// OpenStack flavor extra specs).
+	PreserveCPUPlacement bool `json:"preserveCpuPlacement,omitempty"`
+	// Regenerate the first boot customization of the VMs (vSphere
+	// guestinfo, OpenStack user data and oVirt initialization) as a
+	// cloud-init NoCloud volume.
+	PreserveCloudInit bool `json:"preserveCloudInit,omitempty"`
 	// Deprecated: this field will be deprecated in 2.8.
 	DiskBus cnv.DiskBus `json:"diskBus,omitempty"`
 	// PVCNameTemplate is a template for generating PVC names for VM disks.
@@ -76,6 +93,10 @@ type PlanSpec struct {
 	// +optional
 	// +kubebuilder:default:=true
 	PVCNameTemplateUseGenerateName bool `json:"pvcNameTemplateUseGenerateName,omitempty"`
+	// Labels and annotations (templates) of the PVCs created for the
+	// VM disks. Set on the DataVolumes and populated PVCs.
+	// +optional
+	PVCMetadata *plan.VolumeMetadata `json:"pvcMetadata,omitempty"`
 	// VolumeNameTemplate is a template for generating volume interface names in the target virtual machine.
 	// It follows Go template syntax and has access to the following variables:
 	//   - .PVCName: name of the PVC mounted to the VM using this volume
@@ -132,6 +153,52 @@ type PlanSpec struct {
 	// Determines if the plan should skip the guest conversion.
 	// +kubebuilder:default:=false
 	SkipGuestConversion bool `json:"skipGuestConversion,omitempty"`
+	// Resources and node placement of the guest conversion pods.
+	// Heavy conversions may be pinned to dedicated migration nodes.
+	// +optional
+	ConversionPod *plan.PodSettings `json:"conversionPod,omitempty"`
+	// Guest conversion strategy. Converted by the
+	// pod in the destination cluster when not set.
+	// +optional
+	Conversion *plan.ConversionSettings `json:"conversion,omitempty"`
+	// Passthrough (GPU and PCI) device mapping.
+	// +optional
+	DeviceMap []plan.DeviceMapping `json:"deviceMap,omitempty"`
+	// CPU model of the VMs. Overrides the CPU model translated
+	// from the source (e.g. the vSphere cluster EVC mode).
+	// +optional
+	CPUModel *plan.CPUModel `json:"cpuModel,omitempty"`
+	// Node placement of the VMs.
+	// +optional
+	TargetPlacement *plan.VMPlacement `json:"targetPlacement,omitempty"`
+	// Config map with the post-migration test spec (`tests.yaml`).
+	// The checks are run once the migrated VMs have booted.
+	// +optional
+	TestSpec *core.ObjectReference `json:"testSpec,omitempty"`
+	// Source VM attributes mapped to the labels and
+	// annotations of the migrated VMs.
+	// +optional
+	AttributeMap []plan.AttributeMapping `json:"attributeMap,omitempty"`
+	// Handling of the CD-ROM (ISO) media. The CD-ROMs
+	// are detached by default.
+	// +optional
+	CDROMs *plan.CDROMSettings `json:"cdroms,omitempty"`
+	// Handling of the RDM (raw device mapping) disks. The VMs
+	// with RDM disks cannot be migrated when not set.
+	// +optional
+	RDMDisks *plan.RDMSettings `json:"rdmDisks,omitempty"`
+	// Sizing of the memory requests and limits. The full
+	// memory size is requested when not set.
+	// +optional
+	Memory *plan.MemorySettings `json:"memory,omitempty"`
+	// Continuous replication of the (warm) VMs. The target VMs
+	// are created on failover (cutover) only.
+	// +optional
+	Replication *plan.Replication `json:"replication,omitempty"`
+	// Backup of the migrated VMs. The target resources are
+	// labeled with the backup policy.
+	// +optional
+	Backup *plan.BackupSettings `json:"backup,omitempty"`
 }
 
 // Find a planned VM.
@@ -147,6 +214,19 @@ func (r *PlanSpec) FindVM(ref ref.Ref) (v *plan.VM, found bool) {
 	return
 }
 
+// Find the mapping of a source passthrough device profile.
+func (r *PlanSpec) FindDeviceMapping(profile string) (mapping *plan.DeviceMapping, found bool) {
+	for i := range r.DeviceMap {
+		if r.DeviceMap[i].Source == profile {
+			mapping = &r.DeviceMap[i]
+			found = true
+			return
+		}
+	}
+
+	return
+}
+
 // PlanStatus defines the observed state of Plan.
 type PlanStatus struct {
 	// Conditions.
@@ -195,7 +275,8 @@ func (p *Plan) ShouldUseV2vForTransfer() (bool, error) {
 	case VSphere:
 		// The virt-v2v transferes all disks attached to the VM. If we want to skip the shared disks so we don't transfer
 		// them multiple times we need to manage the transfer using KubeVirt CDI DataVolumes and v2v-in-place.
-		return !p.Spec.Warm && destination.IsHost() && p.Spec.MigrateSharedDisks && !p.Spec.SkipGuestConversion, nil
+		// The same goes for the RDM disks, which are never transferred.
+		return !p.Spec.Warm && destination.IsHost() && p.Spec.MigrateSharedDisks && !p.Spec.SkipGuestConversion && p.Spec.RDMDisks == nil, nil
 	case Ova:
 		return true, nil
 	default:
@@ -228,6 +309,8 @@ func (r *Plan) IsSourceProviderOCP() bool {
 
 func (r *Plan) IsSourceProviderVSphere() bool { return r.Provider.Source.Type() == VSphere }
 
+func (r *Plan) IsSourceProviderNutanix() bool { return r.Provider.Source.Type() == Nutanix }
+
 // PVCNameTemplateData contains fields used in naming templates.
 type PVCNameTemplateData struct {
 	VmName        string `json:"vmName"`
@@ -0,0 +1,16 @@
+package plan
+
+// Mapping of a source VM attribute to a label or annotation
+// of the migrated VM (e.g. the vSphere custom attribute
+// CostCenter to the costcenter label).
+type AttributeMapping struct {
+	// Source attribute name. Either the vSphere custom
+	// attribute or the OpenStack server metadata key.
+	Source string `json:"source"`
+	// Label key. Values not valid as label values are sanitized.
+	// +optional
+	Label string `json:"label,omitempty"`
+	// Annotation key.
+	// +optional
+	Annotation string `json:"annotation,omitempty"`
+}
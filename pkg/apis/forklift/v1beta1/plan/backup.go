@@ -0,0 +1,37 @@
+package plan
+
+import (
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Default Velero (OADP) namespace.
+const DefaultBackupNamespace = "openshift-adp"
+
+// Backup of the migrated VMs.
+type BackupSettings struct {
+	// Backup policy. The created target resources (VMs and
+	// volumes) are labeled with the backup policy label.
+	Policy string `json:"policy"`
+	// Trigger a Velero backup of the target namespace resources
+	// labeled with the policy once the migration completes.
+	// +optional
+	Trigger bool `json:"trigger,omitempty"`
+	// Velero (OADP) namespace.
+	// Default: openshift-adp.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Velero backup storage location.
+	// +optional
+	StorageLocation string `json:"storageLocation,omitempty"`
+	// Retention of the backup (Velero TTL).
+	// +optional
+	TTL *meta.Duration `json:"ttl,omitempty"`
+}
+
+// Velero namespace.
+func (r *BackupSettings) VeleroNamespace() string {
+	if r.Namespace != "" {
+		return r.Namespace
+	}
+	return DefaultBackupNamespace
+}
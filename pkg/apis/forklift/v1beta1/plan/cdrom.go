@@ -0,0 +1,35 @@
+package plan
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// CD-ROM media policies.
+const (
+	// The CD-ROMs are not migrated.
+	CDROMDetach = "Detach"
+	// The ISO media are copied to data volumes
+	// attached as CD-ROMs to the migrated VM.
+	CDROMCopy = "Copy"
+)
+
+// Handling of the source VM CD-ROM (ISO) media.
+type CDROMSettings struct {
+	// Policy. Detach (default) or Copy.
+	// +kubebuilder:validation:Enum=Detach;Copy
+	// +optional
+	Policy string `json:"policy,omitempty"`
+	// Storage class of the ISO volumes.
+	// Defaults to the default storage class.
+	// +optional
+	StorageClass string `json:"storageClass,omitempty"`
+	// Size of the ISO volumes. Must fit the largest ISO.
+	// Defaults to 10Gi.
+	// +optional
+	Size *resource.Quantity `json:"size,omitempty"`
+}
+
+// The ISO media are copied.
+func (r *CDROMSettings) Copied() bool {
+	return r != nil && r.Policy == CDROMCopy
+}
@@ -0,0 +1,69 @@
+package plan
+
+import (
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Compaction of an archived plan status.
+type Compaction struct {
+	// Time of compaction.
+	Compacted meta.Time `json:"compacted"`
+	// Number of history snapshots trimmed.
+	// +optional
+	TrimmedHistory int `json:"trimmedHistory,omitempty"`
+	// ConfigMap holding the full (gzipped) status before compaction.
+	// +optional
+	Export *core.ObjectReference `json:"export,omitempty"`
+}
+
+// Summary of a compacted VM migration pipeline.
+type PipelineSummary struct {
+	// Number of pipeline steps.
+	Steps int `json:"steps"`
+	// Number of completed steps.
+	Completed int `json:"completed"`
+	// The first step that failed.
+	// +optional
+	Failed string `json:"failed,omitempty"`
+	// Number of (warm) precopies.
+	// +optional
+	Precopies int `json:"precopies,omitempty"`
+}
+
+// Compact the VM status.
+// The pipeline and the precopies are replaced by a summary.
+func (r *VMStatus) Compact() {
+	summary := &PipelineSummary{Steps: len(r.Pipeline)}
+	for _, step := range r.Pipeline {
+		if step.MarkedCompleted() && !step.HasError() {
+			summary.Completed++
+		}
+		if step.HasError() && summary.Failed == "" {
+			summary.Failed = step.Name
+		}
+	}
+	if r.Warm != nil {
+		summary.Precopies = r.Warm.Iterations()
+		r.Warm.Precopies = nil
+	}
+	r.Summary = summary
+	r.Pipeline = []*Step{}
+}
+
+// Compact the migration status.
+// The VM statuses are compacted and the history is trimmed
+// to the specified number of (most recent) snapshots.
+func (r *MigrationStatus) Compact(history int) (trimmed int) {
+	for _, vm := range r.VMs {
+		vm.Compact()
+	}
+	if history < 1 {
+		history = 1
+	}
+	if len(r.History) > history {
+		trimmed = len(r.History) - history
+		r.History = r.History[trimmed:]
+	}
+	return
+}
@@ -0,0 +1,37 @@
+package plan
+
+import core "k8s.io/api/core/v1"
+
+// Guest conversion strategies.
+const (
+	// Converted by the (virt-v2v) pod in the destination cluster.
+	ConversionStrategyDestination = "Destination"
+	// Converted by a helper appliance, typically near the source
+	// where the (CPU) capacity exists.
+	ConversionStrategyAppliance = "Appliance"
+)
+
+// Guest conversion settings.
+type ConversionSettings struct {
+	// Strategy. Destination or Appliance.
+	// +kubebuilder:validation:Enum=Destination;Appliance
+	Strategy string `json:"strategy"`
+	// Conversion appliance. Required by the Appliance strategy.
+	// +optional
+	Appliance *ConversionAppliance `json:"appliance,omitempty"`
+}
+
+// Helper appliance converting the (already copied) disks of the VMs.
+type ConversionAppliance struct {
+	// Appliance API URL.
+	URL string `json:"url"`
+	// Secret with the API `token` and the `cacert`
+	// (or `insecureSkipVerify`).
+	// +optional
+	Secret *core.ObjectReference `json:"secret,omitempty"`
+}
+
+// The conversion is delegated to the appliance.
+func (r *ConversionSettings) Delegated() bool {
+	return r != nil && r.Strategy == ConversionStrategyAppliance
+}
@@ -0,0 +1,10 @@
+package plan
+
+// CPU model of the migrated VMs.
+type CPUModel struct {
+	// KubeVirt CPU model (e.g. host-model, host-passthrough or Haswell-noTSX).
+	Name string `json:"name"`
+	// CPU features required by the VMs (e.g. pcid).
+	// +optional
+	Features []string `json:"features,omitempty"`
+}
@@ -0,0 +1,14 @@
+package plan
+
+// Mapping of a source passthrough device to a destination device.
+type DeviceMapping struct {
+	// Source device profile. Either the vGPU profile (e.g. grid_t4-4q)
+	// or the PCI vendor and device IDs (e.g. 10de:1eb8).
+	Source string `json:"source"`
+	// Device resource name advertised by the destination nodes
+	// (e.g. nvidia.com/TU104GL_Tesla_T4).
+	ResourceName string `json:"resourceName"`
+	// Map to a mediated device (vGPU) rather than a (PCI) host device.
+	// +optional
+	Mediated bool `json:"mediated,omitempty"`
+}
@@ -0,0 +1,49 @@
+package plan
+
+import (
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Approval gates.
+// Destructive phases that may require approval before they run.
+const (
+	// The source VM is powered off.
+	GatePowerOff = "PowerOff"
+	// A warm migration is finalized.
+	GateCutover = "Cutover"
+)
+
+// Approval gates of the plan.
+type ApprovalGates struct {
+	// Gates requiring approval (listed in the migration) before they run.
+	// +kubebuilder:validation:items:Enum=PowerOff;Cutover
+	Gates []string `json:"gates"`
+	// Minutes to wait for an approval before the VM migration fails.
+	// Zero (default) waits indefinitely.
+	// +optional
+	Timeout int `json:"timeout,omitempty"`
+}
+
+// Find whether the gate requires approval.
+func (r *ApprovalGates) Requires(gate string) bool {
+	for _, g := range r.Gates {
+		if g == gate {
+			return true
+		}
+	}
+	return false
+}
+
+// Approval of a gate.
+type GateApproval struct {
+	// Gate name.
+	Gate string `json:"gate"`
+	// The user who approved the gate.
+	// Set by the admission webhook.
+	// +optional
+	ApprovedBy string `json:"approvedBy,omitempty"`
+	// Time of approval.
+	// Set by the admission webhook.
+	// +optional
+	Approved *meta.Time `json:"approved,omitempty"`
+}
@@ -0,0 +1,40 @@
+package plan
+
+import "time"
+
+// Default time window (seconds) of the consistent groups.
+const DefaultConsistencyWindow = 60
+
+// Migration group.
+// The VMs of a group are migrated once the groups
+// it depends on are completed.
+type Group struct {
+	// Group name.
+	Name string `json:"name"`
+	// Groups that must be completed before the group is started.
+	// Defaults to the previous group listed in the plan.
+	// +optional
+	DependsOn []string `json:"dependsOn,omitempty"`
+	// Pause for approval (listed in the migration) before the group is started.
+	// +optional
+	Approval bool `json:"approval,omitempty"`
+	// Keep the (warm migration) data of the group VMs mutually consistent.
+	// The precopy snapshots of the VMs are taken together once all of them
+	// are paused, and the VMs are cut over (powered off) together.
+	// +optional
+	Consistent bool `json:"consistent,omitempty"`
+	// Time window (seconds) in which the snapshots of the consistent
+	// group VMs are expected to be taken. Defaults to 60.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Window int `json:"window,omitempty"`
+}
+
+// Time window in which the snapshots of the consistent group are taken.
+func (r *Group) ConsistencyWindow() time.Duration {
+	window := r.Window
+	if window == 0 {
+		window = DefaultConsistencyWindow
+	}
+	return time.Duration(window) * time.Second
+}
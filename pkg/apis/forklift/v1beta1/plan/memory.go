@@ -0,0 +1,32 @@
+package plan
+
+// Memory sizing policies.
+const (
+	// The full memory size is requested.
+	MemoryFull = "Full"
+	// The source memory reservation is requested and
+	// the source memory limit is set as the limit.
+	MemoryReservation = "Reservation"
+)
+
+// Sizing of the target VM memory requests and limits.
+// The guest memory is always the source memory size.
+// The memory of VMs backed by hugepages or with the
+// reservation locked to the memory size is fully requested.
+type MemorySettings struct {
+	// Policy. Full or Reservation.
+	// +kubebuilder:validation:Enum=Full;Reservation
+	Policy string `json:"policy"`
+	// Overcommit headroom (percent). The memory request is
+	// reduced by the percentage of the memory size, but not
+	// below the reservation.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=90
+	// +optional
+	Overcommit int `json:"overcommit,omitempty"`
+}
+
+// The source memory reservation is requested.
+func (r *MemorySettings) Reserved() bool {
+	return r != nil && r.Policy == MemoryReservation
+}
@@ -37,6 +37,8 @@ type MigrationStatus struct {
 	History []Snapshot `json:"history,omitempty"`
 	// VM status
 	VMs []*VMStatus `json:"vms,omitempty"`
+	// Compaction of the (archived) status.
+	Compaction *Compaction `json:"compaction,omitempty"`
 }
 
 // The active snapshot.
@@ -80,6 +82,30 @@ func (r *MigrationStatus) FindVM(ref ref.Ref) (v *VMStatus, found bool) {
 	return
 }
 
+// Index the VM statuses by VM ID.
+func (r *MigrationStatus) Index() (index VMStatusIndex) {
+	index = make(VMStatusIndex, len(r.VMs))
+	for _, vm := range r.VMs {
+		if _, found := index[vm.ID]; !found {
+			index[vm.ID] = vm
+		}
+	}
+
+	return
+}
+
+// VM status index keyed by VM ID.
+// Built (per reconcile) for the lookups of plans with many
+// VMs and must be rebuilt when the VM status list changes.
+// +k8s:deepcopy-gen=false
+type VMStatusIndex map[string]*VMStatus
+
+// Find a VM status.
+func (r VMStatusIndex) Find(ref ref.Ref) (v *VMStatus, found bool) {
+	v, found = r[ref.ID]
+	return
+}
+
 // Pipeline step.
 type Step struct {
 	Task `json:",inline"`
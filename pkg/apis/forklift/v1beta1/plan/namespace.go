@@ -0,0 +1,40 @@
+package plan
+
+import (
+	core "k8s.io/api/core/v1"
+	net "k8s.io/api/networking/v1"
+)
+
+// Keys of the namespace template config map.
+// The values are YAML documents of the template fields.
+const (
+	NamespaceTemplateLabels        = "labels"
+	NamespaceTemplateAnnotations   = "annotations"
+	NamespaceTemplateResourceQuota = "resourceQuota"
+	NamespaceTemplateLimitRange    = "limitRange"
+	NamespaceTemplateNetworkPolicy = "networkPolicy"
+)
+
+// Template of the target namespace created by the plan.
+// Applied only to the namespace created when it does not exist.
+type NamespaceTemplate struct {
+	// Config map (in the plan namespace) defining the template.
+	// The fields defined in the plan take precedence.
+	// +optional
+	ConfigMap *core.LocalObjectReference `json:"configMap,omitempty"`
+	// Namespace labels.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Namespace annotations.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Resource quota of the namespace.
+	// +optional
+	ResourceQuota *core.ResourceQuotaSpec `json:"resourceQuota,omitempty"`
+	// Limit range of the namespace.
+	// +optional
+	LimitRange *core.LimitRangeSpec `json:"limitRange,omitempty"`
+	// Default network policy of the namespace.
+	// +optional
+	NetworkPolicy *net.NetworkPolicySpec `json:"networkPolicy,omitempty"`
+}
@@ -0,0 +1,26 @@
+package plan
+
+import (
+	core "k8s.io/api/core/v1"
+)
+
+// Node placement of the migrated VMs.
+// Lands the VMs of the plan on a set of nodes (e.g. a new node pool).
+type VMPlacement struct {
+	// Node selector. Merged with the selector of the VM template.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Tolerations.
+	// +optional
+	Tolerations []core.Toleration `json:"tolerations,omitempty"`
+	// Affinity. The node affinity replaces the node affinity of the VM
+	// template and the pod (anti-)affinity terms are added to the terms
+	// translated from the source cluster affinity rules.
+	// +optional
+	Affinity *core.Affinity `json:"affinity,omitempty"`
+	// Descheduler hint. When true, the descheduler may evict (live migrate)
+	// the VMs to rebalance the nodes. When false, the descheduler is
+	// requested not to evict the VMs.
+	// +optional
+	Evictable *bool `json:"evictable,omitempty"`
+}
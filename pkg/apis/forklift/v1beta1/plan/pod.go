@@ -0,0 +1,24 @@
+package plan
+
+import (
+	core "k8s.io/api/core/v1"
+)
+
+// Resources and node placement of the guest conversion (virt-v2v)
+// pods and the pods binding the disks to the conversion node.
+type PodSettings struct {
+	// Compute resources of the conversion container.
+	// Requests and limits not listed default to the controller settings.
+	// +optional
+	Resources *core.ResourceRequirements `json:"resources,omitempty"`
+	// Node selector. Merged with the selector required by the controller.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Tolerations.
+	// +optional
+	Tolerations []core.Toleration `json:"tolerations,omitempty"`
+	// Affinity. Replaces the default anti-affinity spreading the
+	// conversion pods across nodes.
+	// +optional
+	Affinity *core.Affinity `json:"affinity,omitempty"`
+}
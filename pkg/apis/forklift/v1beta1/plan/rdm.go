@@ -0,0 +1,30 @@
+package plan
+
+// RDM (raw device mapping) disk policies.
+const (
+	// The RDM disks are attached to pre-existing block
+	// persistent volumes presenting the same LUNs (WWN).
+	RDMMap = "Map"
+	// The RDM disks are not migrated.
+	RDMSkip = "Skip"
+)
+
+// Handling of the source VM RDM (raw device mapping) disks.
+// The RDM disks are never copied. A pre-existing PV is matched by
+// the WWN of its FC source or of the forklift.konveyor.io/wwn
+// annotation, and must be retained so the LUN outlives the claim.
+type RDMSettings struct {
+	// Policy. Map or Skip.
+	// +kubebuilder:validation:Enum=Map;Skip
+	Policy string `json:"policy"`
+}
+
+// The RDM disks are mapped to pre-existing PVs.
+func (r *RDMSettings) Mapped() bool {
+	return r != nil && r.Policy == RDMMap
+}
+
+// The RDM disks are skipped.
+func (r *RDMSettings) Skipped() bool {
+	return r != nil && r.Policy == RDMSkip
+}
@@ -0,0 +1,37 @@
+package plan
+
+import "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+
+// Disk of a migrated VM to be copied again.
+// Only the PVC is recreated and the disk transferred,
+// for example when found corrupted by the verification
+// of the target VM.
+type DiskRecopy struct {
+	// Source VM.
+	VM ref.Ref `json:"vm"`
+	// PVC of the disk on the target VM.
+	PVC string `json:"pvc"`
+}
+
+// Disks re-copied to the target VM.
+type Recopy struct {
+	// PVCs of the disks copied again.
+	PVCs []string `json:"pvcs"`
+	// Stable identifier of the disk of each PVC.
+	// Resolved before the PVCs are deleted.
+	// +optional
+	Disks map[string]string `json:"disks,omitempty"`
+	// Run strategy of the target VM before it was stopped.
+	// +optional
+	RunStrategy string `json:"runStrategy,omitempty"`
+}
+
+// The disk of the PVC is re-copied.
+func (r *Recopy) HasDisk(id string) bool {
+	for _, disk := range r.Disks {
+		if disk == id {
+			return true
+		}
+	}
+	return false
+}
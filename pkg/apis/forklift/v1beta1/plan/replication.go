@@ -0,0 +1,39 @@
+package plan
+
+import "time"
+
+// Continuous replication (pilot-light disaster recovery).
+// The warm migration precopies are scheduled indefinitely and
+// the target VMs are only created once a failover is requested
+// by the migration cutover. The source VMs are not powered off
+// and the disks are restored as of the last recovery point.
+type Replication struct {
+	// Interval (minutes) between precopies. Defaults
+	// to the controller precopy interval.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Interval int `json:"interval,omitempty"`
+	// RPO (minutes) above which the VMs are reported. Defaults
+	// to the controller RPO threshold.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RPOThreshold int `json:"rpoThreshold,omitempty"`
+}
+
+// Interval between precopies.
+func (r *Replication) PrecopyInterval(defaultInterval int) time.Duration {
+	interval := defaultInterval
+	if r != nil && r.Interval > 0 {
+		interval = r.Interval
+	}
+	return time.Duration(interval) * time.Minute
+}
+
+// RPO above which the VMs are reported. Not reported when zero.
+func (r *Replication) RPOLimit(defaultThreshold int) time.Duration {
+	threshold := defaultThreshold
+	if r != nil && r.RPOThreshold > 0 {
+		threshold = r.RPOThreshold
+	}
+	return time.Duration(threshold) * time.Minute
+}
@@ -0,0 +1,169 @@
+package plan
+
+import meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// Post-migration test (check) kinds.
+const (
+	CommandCheckKind = "Command"
+	HTTPCheckKind    = "HTTP"
+	PortCheckKind    = "Port"
+)
+
+// Post-migration test (check) phases.
+const (
+	TestPending = "Pending"
+	TestPassed  = "Passed"
+	TestFailed  = "Failed"
+)
+
+// Post-migration test spec.
+// Read from the config map referenced by the plan.
+// The checks are run once the migrated VM has booted.
+type TestSpec struct {
+	// Time (seconds) allowed for the VM to boot.
+	BootTimeout int `json:"bootTimeout,omitempty"`
+	// Checks.
+	Checks []TestCheck `json:"checks"`
+}
+
+// Post-migration check.
+// Exactly one of the command, http and port must be set.
+type TestCheck struct {
+	// Name (unique).
+	Name string `json:"name"`
+	// Time (seconds) allowed for the check to pass.
+	// The check is retried until then.
+	Timeout int `json:"timeout,omitempty"`
+	// Command run in the guest by the guest agent.
+	Command *CommandCheck `json:"command,omitempty"`
+	// HTTP request.
+	HTTP *HTTPCheck `json:"http,omitempty"`
+	// TCP port connection.
+	Port *PortCheck `json:"port,omitempty"`
+}
+
+// The check kind.
+func (r *TestCheck) Kind() (kind string) {
+	switch {
+	case r.Command != nil:
+		kind = CommandCheckKind
+	case r.HTTP != nil:
+		kind = HTTPCheckKind
+	case r.Port != nil:
+		kind = PortCheckKind
+	}
+	return
+}
+
+// Command run in the guest by the guest agent.
+type CommandCheck struct {
+	// Path of the executable.
+	Path string `json:"path"`
+	// Arguments.
+	Args []string `json:"args,omitempty"`
+	// Expected exit code.
+	ExitCode int `json:"exitCode,omitempty"`
+	// Expected to be contained in the output.
+	Output string `json:"output,omitempty"`
+}
+
+// HTTP request.
+type HTTPCheck struct {
+	// Host. Defaults to the VM IP address.
+	Host string `json:"host,omitempty"`
+	// Port. Defaults to the scheme port.
+	Port int `json:"port,omitempty"`
+	// Path.
+	Path string `json:"path,omitempty"`
+	// Use HTTPS. The certificate is not verified.
+	HTTPS bool `json:"https,omitempty"`
+	// Expected status code. Defaults to 200.
+	Status int `json:"status,omitempty"`
+	// Expected to be contained in the body.
+	Body string `json:"body,omitempty"`
+}
+
+// TCP port connection.
+type PortCheck struct {
+	// Host. Defaults to the VM IP address.
+	Host string `json:"host,omitempty"`
+	// Port.
+	Port int `json:"port"`
+}
+
+// Post-migration test report.
+type TestReport struct {
+	Timed `json:",inline"`
+	// Time the VM was found booted.
+	Booted *meta.Time `json:"booted,omitempty"`
+	// Reason the checks were not run.
+	Skipped string `json:"skipped,omitempty"`
+	// Result of each check.
+	Results []TestResult `json:"results,omitempty"`
+}
+
+// Find the result of a check.
+func (r *TestReport) FindResult(name string) (result *TestResult, found bool) {
+	for i := range r.Results {
+		result = &r.Results[i]
+		if result.Name == name {
+			found = true
+			return
+		}
+	}
+	result = nil
+	return
+}
+
+// Number of failed checks.
+func (r *TestReport) Failed() (n int) {
+	for _, result := range r.Results {
+		if result.Phase == TestFailed {
+			n++
+		}
+	}
+	return
+}
+
+// All checks have passed or failed.
+func (r *TestReport) Done() bool {
+	for _, result := range r.Results {
+		if result.Phase == TestPending {
+			return false
+		}
+	}
+	return true
+}
+
+// Post-migration check result.
+type TestResult struct {
+	// Check name.
+	Name string `json:"name"`
+	// Check kind.
+	Kind string `json:"kind"`
+	// Pending, Passed or Failed.
+	Phase string `json:"phase"`
+	// Outcome of the last attempt.
+	Message string `json:"message,omitempty"`
+	// Attempts.
+	Attempts int `json:"attempts,omitempty"`
+	// Time the check passed or failed.
+	Completed *meta.Time `json:"completed,omitempty"`
+}
+
+// Record the outcome of an attempt.
+// The check fails once the deadline is reached.
+func (r *TestResult) Record(passed bool, message string, deadline bool) {
+	r.Attempts++
+	r.Message = message
+	switch {
+	case passed:
+		r.Phase = TestPassed
+	case deadline:
+		r.Phase = TestFailed
+	default:
+		return
+	}
+	now := meta.Now()
+	r.Completed = &now
+}
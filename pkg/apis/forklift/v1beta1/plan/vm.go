@@ -10,6 +10,11 @@ import (
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// Hook run before each precopy snapshot of warm migrations.
+// The VM is running, the hook may prepare the applications
+// for the snapshot (e.g. flush the database tables).
+const HookPreSnapshot = "PreSnapshot"
+
 // Plan hook.
 type HookRef struct {
 	// Pipeline step.
@@ -36,6 +41,13 @@ type VM struct {
 	// Choose the primary disk the VM boots from
 	// +optional
 	RootDisk string `json:"rootDisk,omitempty"`
+	// Quiesce the guest when the (warm migration) snapshots are taken.
+	// The guest file systems are frozen by VMware Tools (vSphere) or
+	// the guest agent (oVirt), which run the guest freeze scripts.
+	// When set, the oVirt snapshots fail without the guest agent.
+	// When not set, the vSphere snapshots are quiesced.
+	// +optional
+	Quiesce *bool `json:"quiesce,omitempty"`
 	// Selected InstanceType that will override the VM properties.
 	// +optional
 	InstanceType string `json:"instanceType,omitempty"`
@@ -81,6 +93,10 @@ type VM struct {
 	//   "{{if eq .NetworkType "Pod"}}pod{{else}}multus-{{.NetworkIndex}}{{end}}"
 	// +optional
 	NetworkNameTemplate string `json:"networkNameTemplate,omitempty"`
+	// Migration group (listed in the plan groups).
+	// VMs not in a group are not ordered.
+	// +optional
+	Group string `json:"group,omitempty"`
 	// TargetName specifies a custom name for the VM in the target cluster.
 	// If not provided, the original VM name will be used and automatically adjusted to meet k8s DNS1123 requirements.
 	// If provided, this exact name will be used instead. The migration will fail if the name is not unique or already in use.
@@ -121,6 +137,20 @@ type VMStatus struct {
 	OperatingSystem string `json:"operatingSystem,omitempty"`
 	// The new name of the VM after matching DNS1123 requirements.
 	NewName string `json:"newName,omitempty"`
+	// Cancellation of the VM migration.
+	Cancellation *Cancellation `json:"cancellation,omitempty"`
+	// Summary of the compacted pipeline (archived plans).
+	Summary *PipelineSummary `json:"summary,omitempty"`
+	// Guest conversion progress reported by virt-v2v.
+	Conversion *ConversionProgress `json:"conversion,omitempty"`
+	// Last persisted checkpoint of the pipeline.
+	Checkpoint *Checkpoint `json:"checkpoint,omitempty"`
+	// Snapshots created on the source and not yet removed.
+	Snapshots []SourceSnapshot `json:"snapshots,omitempty"`
+	// Post-migration test report.
+	Tests *TestReport `json:"tests,omitempty"`
+	// Disks re-copied to the migrated VM.
+	Recopy *Recopy `json:"recopy,omitempty"`
 
 	// Conditions.
 	libcnd.Conditions `json:",inline"`
@@ -133,6 +163,150 @@ type Warm struct {
 	ConsecutiveFailures int        `json:"consecutiveFailures"`
 	NextPrecopyAt       *meta.Time `json:"nextPrecopyAt,omitempty"`
 	Precopies           []Precopy  `json:"precopies,omitempty"`
+	// Number of precopies pruned. The precopies of a
+	// (continuous) replication are pruned to the last.
+	Pruned int `json:"pruned,omitempty"`
+	// Changed block tracking checkpoint of the last precopy
+	// transferred. A failed cutover is re-synchronized from it.
+	Checkpoint *DiskCheckpoint `json:"checkpoint,omitempty"`
+	// Time of the snapshot of the last precopy transferred.
+	// The disks are restored as of then on failover.
+	RecoveryPoint *meta.Time `json:"recoveryPoint,omitempty"`
+	// RPO (seconds). Time since the recovery point, updated
+	// while the disks are precopied.
+	RPO int64 `json:"rpo,omitempty"`
+}
+
+// Number of precopies (iterations) including the pruned.
+func (r *Warm) Iterations() int {
+	return r.Pruned + len(r.Precopies)
+}
+
+// Prune the precopies to the last.
+func (r *Warm) Prune() {
+	if n := len(r.Precopies); n > 1 {
+		r.Pruned += n - 1
+		r.Precopies = r.Precopies[n-1:]
+	}
+}
+
+// The disks are re-synchronized from the checkpoint.
+func (r *Warm) Resync() bool {
+	return r.Checkpoint != nil && r.Checkpoint.Resync
+}
+
+// Changed block tracking checkpoint.
+type DiskCheckpoint struct {
+	// Snapshot of the precopy transferred.
+	Snapshot string `json:"snapshot"`
+	// Change ID of each disk.
+	Deltas []DiskDelta `json:"deltas,omitempty"`
+	// The cutover failed after the source was powered off.
+	// The disks are re-synchronized (by the next migration)
+	// rather than copied again.
+	Resync bool `json:"resync,omitempty"`
+}
+
+// Cancellation initiators.
+const (
+	// Canceled by the user.
+	CanceledByUser = "User"
+	// Canceled by the controller.
+	CanceledBySystem = "System"
+)
+
+// VM migration cancellation.
+type Cancellation struct {
+	// Canceled by the `User` or the `System`.
+	Initiator string `json:"initiator"`
+	// Machine-readable reason.
+	Reason string `json:"reason"`
+	// Time of the cancellation.
+	Time meta.Time `json:"time"`
+	// Number of attempts to clean up the migration resources.
+	CleanupAttempts int `json:"cleanupAttempts,omitempty"`
+	// The migration resources have been cleaned up.
+	CleanedUp bool `json:"cleanedUp,omitempty"`
+	// Errors of the last cleanup attempt.
+	CleanupErrors []string `json:"cleanupErrors,omitempty"`
+}
+
+// Snapshot created on the source by the migration.
+type SourceSnapshot struct {
+	// Snapshot ID.
+	ID string `json:"id"`
+	// Time the snapshot was created.
+	Created meta.Time `json:"created"`
+	// Failed attempts to remove the snapshot.
+	Attempts int `json:"attempts,omitempty"`
+	// Error of the last attempt.
+	Error string `json:"error,omitempty"`
+}
+
+// Track a snapshot created on the source.
+func (r *VMStatus) TrackSnapshot(id string) {
+	if id == "" {
+		return
+	}
+	for _, snapshot := range r.Snapshots {
+		if snapshot.ID == id {
+			return
+		}
+	}
+	r.Snapshots = append(
+		r.Snapshots,
+		SourceSnapshot{
+			ID:      id,
+			Created: meta.Now(),
+		})
+}
+
+// Untrack a snapshot removed from the source.
+func (r *VMStatus) UntrackSnapshot(id string) {
+	kept := []SourceSnapshot{}
+	for _, snapshot := range r.Snapshots {
+		if snapshot.ID != id {
+			kept = append(kept, snapshot)
+		}
+	}
+	if len(kept) == 0 {
+		kept = nil
+	}
+	r.Snapshots = kept
+}
+
+// Pipeline checkpoint.
+// Records the controller (leader) that reached the phase. Persisted
+// when the VM leaves a phase that creates resources, otherwise with
+// the phase by the plan status update.
+type Checkpoint struct {
+	// Phase reached.
+	Phase string `json:"phase"`
+	// Identity of the controller (leader) that reached the phase.
+	Holder string `json:"holder"`
+	// Warm migration precopy (iteration) reached.
+	Precopy int `json:"precopy,omitempty"`
+	// Time of the checkpoint.
+	Time meta.Time `json:"time"`
+}
+
+// Guest conversion stages.
+const (
+	ConversionInspect        = "Inspect"
+	ConversionConvertDrivers = "ConvertDrivers"
+	ConversionBootloader     = "Bootloader"
+	ConversionCopyDisks      = "CopyDisks"
+	ConversionFinished       = "Finished"
+)
+
+// Guest conversion progress.
+type ConversionProgress struct {
+	// Current stage.
+	Stage string `json:"stage"`
+	// Overall progress (percent).
+	Percent int `json:"percent"`
+	// Time of the last update.
+	Updated meta.Time `json:"updated"`
 }
 
 type VMPowerState string
@@ -0,0 +1,16 @@
+package plan
+
+// Metadata of the PVCs (and DataVolumes) created for the VM disks.
+// The values are Go templates with access to the PVC name template
+// variables, for example:
+//
+//	backup.example.com/policy: "{{if eq .DiskIndex .RootDiskIndex}}os{{else}}data{{end}}"
+//	example.com/source: "{{.PlanName}}/{{.VmName}}"
+type VolumeMetadata struct {
+	// Labels.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
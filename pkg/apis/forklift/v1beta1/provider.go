@@ -17,8 +17,13 @@ limitations under the License.
 package v1beta1
 
 import (
+	"fmt"
+	"math"
+	"net/url"
 	"os"
+	"sort"
 	"strconv"
+	"strings"
 
 	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
 	core "k8s.io/api/core/v1"
@@ -40,6 +45,18 @@ const (
 	OpenStack ProviderType = "openstack"
 	// OVA
 	Ova ProviderType = "ova"
+	// Nutanix AHV (Prism Central)
+	Nutanix ProviderType = "nutanix"
+	// AWS EC2
+	EC2 ProviderType = "ec2"
+	// Microsoft Azure
+	Azure ProviderType = "azure"
+	// Libvirt (KVM) host
+	Libvirt ProviderType = "libvirt"
+	// Disk image (files) share
+	Image ProviderType = "image"
+	// Disk image catalog (manifest)
+	Catalog ProviderType = "catalog"
 )
 
 var ProviderTypes = []ProviderType{
@@ -48,6 +65,12 @@ var ProviderTypes = []ProviderType{
 	OVirt,
 	OpenStack,
 	Ova,
+	Nutanix,
+	EC2,
+	Azure,
+	Libvirt,
+	Image,
+	Catalog,
 }
 
 func (t ProviderType) String() string {
@@ -56,8 +79,9 @@ func (t ProviderType) String() string {
 
 // Secret fields.
 const (
-	Insecure = "insecureSkipVerify"
-	Token    = "token"
+	Insecure   = "insecureSkipVerify"
+	Token      = "token"
+	Kubeconfig = "kubeconfig"
 )
 
 // Provider settings.
@@ -68,6 +92,24 @@ const (
 	ESXI                   = "esxi"
 	UseVddkAioOptimization = "useVddkAioOptimization"
 	VddkConfig             = "vddkConfig"
+	HostedCluster          = "hostedCluster"
+	HTTPProxy              = "httpProxy"
+	HTTPSProxy             = "httpsProxy"
+	NoProxy                = "noProxy"
+	CABundle               = "caBundle"
+	IPFamily               = "ipFamily"
+	APIQPS                 = "apiQPS"
+	APIBurst               = "apiBurst"
+	// Additional vCenters (SDK URLs, comma separated) enumerated by a
+	// vSphere provider. The vCenters are accessed using the provider secret.
+	VCenters = "vcenters"
+	// Enumerate the vCenters in the (enhanced) linked mode group
+	// of the provider vCenter.
+	LinkedMode = "linkedMode"
+	// Shape of the VMs imported from (disk image) files.
+	CPU      = "cpu"
+	Memory   = "memory"
+	Firmware = "firmware"
 )
 
 const OvaProviderFinalizer = "forklift/ova-provider"
@@ -140,7 +182,26 @@ func (p *Provider) Type() ProviderType {
 
 // This provider is the `host` cluster.
 func (p *Provider) IsHost() bool {
-	return p.Type() == OpenShift && p.Spec.URL == ""
+	return p.Type() == OpenShift && p.Spec.URL == "" && !p.IsHostedCluster()
+}
+
+// This provider is a hosted control plane (HCP) cluster
+// managed by the `host` cluster. The kubeconfig is resolved
+// from the HostedCluster rather than the provider secret.
+func (p *Provider) IsHostedCluster() bool {
+	return p.Type() == OpenShift && p.Spec.Settings[HostedCluster] != ""
+}
+
+// The namespace and name of the HostedCluster on the `host` cluster.
+// The setting is `namespace/name`, the provider namespace is used
+// when only the name is set.
+func (p *Provider) HostedClusterRef() (namespace, name string) {
+	ref := p.Spec.Settings[HostedCluster]
+	namespace, name, found := strings.Cut(ref, "/")
+	if !found {
+		namespace, name = p.Namespace, ref
+	}
+	return
 }
 
 // This provider is a `host` provider but it is not within the main forklift
@@ -155,9 +216,52 @@ func (p *Provider) HasReconciled() bool {
 	return p.Generation == p.Status.ObservedGeneration
 }
 
+// This provider is accessed through a proxy.
+func (p *Provider) HasProxy() bool {
+	return p.Spec.Settings[HTTPProxy] != "" || p.Spec.Settings[HTTPSProxy] != ""
+}
+
+// Proxy environment of pods accessing the provider.
+func (p *Provider) ProxyEnv() (env []core.EnvVar) {
+	if !p.HasProxy() {
+		return
+	}
+	for name, setting := range map[string]string{
+		"HTTP_PROXY":  HTTPProxy,
+		"HTTPS_PROXY": HTTPSProxy,
+		"NO_PROXY":    NoProxy,
+	} {
+		value := p.Spec.Settings[setting]
+		if value == "" {
+			continue
+		}
+		env = append(
+			env,
+			core.EnvVar{Name: name, Value: value},
+			core.EnvVar{Name: strings.ToLower(name), Value: value})
+	}
+	sort.Slice(env, func(i, j int) bool {
+		return env[i].Name < env[j].Name
+	})
+	return
+}
+
+// Network (address family) used to connect to the provider.
+// Set by the `ipFamily` setting (IPv4|IPv6), else either.
+func (p *Provider) Network() string {
+	switch core.IPFamily(p.Spec.Settings[IPFamily]) {
+	case core.IPv4Protocol:
+		return "tcp4"
+	case core.IPv6Protocol:
+		return "tcp6"
+	default:
+		return "tcp"
+	}
+}
+
 // This provider requires VM guest conversion.
 func (p *Provider) RequiresConversion() bool {
-	return p.Type() == VSphere || p.Type() == Ova
+	return p.Type() == VSphere || p.Type() == Ova || p.Type() == EC2 || p.Type() == Azure
 }
 
 // This provider support the vddk aio parameters.
@@ -172,3 +276,60 @@ func (p *Provider) UseVddkAioOptimization() bool {
 	}
 	return parseBool
 }
+
+// Rate limit of the vSphere API (SDK) calls set by the
+// `apiQPS` and `apiBurst` settings. Not limited when the
+// QPS is not set. The burst defaults to the QPS (rounded up).
+// The limit is the budget of the whole deployment: it is split
+// evenly between the inventory and the main (plan) processes.
+func (p *Provider) APIRateLimit() (qps float64, burst int, err error) {
+	if s := p.Spec.Settings[APIQPS]; s != "" {
+		qps, err = strconv.ParseFloat(s, 64)
+		if err != nil || qps <= 0 {
+			err = fmt.Errorf("%s must be a positive number", APIQPS)
+			return
+		}
+		burst = int(math.Ceil(qps))
+	}
+	if s := p.Spec.Settings[APIBurst]; s != "" {
+		burst, err = strconv.Atoi(s)
+		if err != nil || burst <= 0 {
+			err = fmt.Errorf("%s must be a positive integer", APIBurst)
+			return
+		}
+	}
+	return
+}
+
+// Additional vCenter (SDK) URLs set by the `vcenters` setting.
+// The URLs must be https and must not duplicate the provider URL.
+func (p *Provider) VCenterURLs() (list []string, err error) {
+	known := map[string]bool{}
+	if u, pErr := url.Parse(p.Spec.URL); pErr == nil {
+		known[u.Host] = true
+	}
+	for _, s := range strings.Split(p.Spec.Settings[VCenters], ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		u, pErr := url.Parse(s)
+		if pErr != nil || u.Scheme != "https" || u.Host == "" {
+			err = fmt.Errorf("%s: '%s' is not a valid https url", VCenters, s)
+			return
+		}
+		if known[u.Host] {
+			continue
+		}
+		known[u.Host] = true
+		list = append(list, s)
+	}
+	return
+}
+
+// The vCenters in the linked mode group of the provider
+// vCenter are enumerated.
+func (p *Provider) LinkedMode() bool {
+	enabled, err := strconv.ParseBool(p.Spec.Settings[LinkedMode])
+	return err == nil && enabled
+}
@@ -0,0 +1,81 @@
+package v1beta1
+
+import (
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Migration record results.
+const (
+	RecordSucceeded = "Succeeded"
+	RecordFailed    = "Failed"
+	RecordCanceled  = "Canceled"
+)
+
+// Reference to a (possibly deleted) resource.
+type RecordRef struct {
+	// Namespace.
+	Namespace string `json:"namespace,omitempty"`
+	// Name.
+	Name string `json:"name"`
+	// UID.
+	UID types.UID `json:"uid,omitempty"`
+}
+
+// Record of a completed VM migration.
+// Records are not owned by the plan and survive the archival and
+// deletion of the plan and the migration.
+type MigrationRecordSpec struct {
+	// Plan.
+	Plan RecordRef `json:"plan"`
+	// Migration.
+	Migration RecordRef `json:"migration"`
+	// Source provider.
+	Source RecordRef `json:"source"`
+	// Source provider type.
+	SourceType ProviderType `json:"sourceType,omitempty"`
+	// Migrated VM.
+	VM ref.Ref `json:"vm"`
+	// Migration timestamps.
+	plan.Timed `json:",inline"`
+	// Duration in seconds.
+	Duration int64 `json:"duration"`
+	// Bytes transferred.
+	Bytes int64 `json:"bytes"`
+	// Result.
+	// +kubebuilder:validation:Enum=Succeeded;Failed;Canceled
+	Result string `json:"result"`
+	// Error reasons.
+	// +optional
+	Errors []string `json:"errors,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// MigrationRecord is the Schema for the migration history API
+// +k8s:openapi-gen=true
+// +kubebuilder:printcolumn:name="VM",type=string,JSONPath=".spec.vm.name"
+// +kubebuilder:printcolumn:name="PLAN",type=string,JSONPath=".spec.plan.name"
+// +kubebuilder:printcolumn:name="RESULT",type=string,JSONPath=".spec.result"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+type MigrationRecord struct {
+	meta.TypeMeta   `json:",inline"`
+	meta.ObjectMeta `json:"metadata,omitempty"`
+	Spec            MigrationRecordSpec `json:"spec,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// MigrationRecordList contains a list of MigrationRecord
+type MigrationRecordList struct {
+	meta.TypeMeta `json:",inline"`
+	meta.ListMeta `json:"metadata,omitempty"`
+	Items         []MigrationRecord `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MigrationRecord{}, &MigrationRecordList{})
+}
@@ -27,6 +27,100 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureVolumePopulator) DeepCopyInto(out *AzureVolumePopulator) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureVolumePopulator.
+func (in *AzureVolumePopulator) DeepCopy() *AzureVolumePopulator {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureVolumePopulator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AzureVolumePopulator) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureVolumePopulatorList) DeepCopyInto(out *AzureVolumePopulatorList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AzureVolumePopulator, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureVolumePopulatorList.
+func (in *AzureVolumePopulatorList) DeepCopy() *AzureVolumePopulatorList {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureVolumePopulatorList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AzureVolumePopulatorList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureVolumePopulatorSpec) DeepCopyInto(out *AzureVolumePopulatorSpec) {
+	*out = *in
+	if in.TransferNetwork != nil {
+		in, out := &in.TransferNetwork, &out.TransferNetwork
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureVolumePopulatorSpec.
+func (in *AzureVolumePopulatorSpec) DeepCopy() *AzureVolumePopulatorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureVolumePopulatorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureVolumePopulatorStatus) DeepCopyInto(out *AzureVolumePopulatorStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureVolumePopulatorStatus.
+func (in *AzureVolumePopulatorStatus) DeepCopy() *AzureVolumePopulatorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureVolumePopulatorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DestinationNetwork) DeepCopyInto(out *DestinationNetwork) {
 	*out = *in
@@ -45,6 +139,18 @@ func (in *DestinationNetwork) DeepCopy() *DestinationNetwork {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DestinationStorage) DeepCopyInto(out *DestinationStorage) {
 	*out = *in
+	if in.AccessModes != nil {
+		in, out := &in.AccessModes, &out.AccessModes
+		*out = make([]v1.PersistentVolumeAccessMode, len(*in))
+		copy(*out, *in)
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DestinationStorage.
@@ -57,6 +163,100 @@ func (in *DestinationStorage) DeepCopy() *DestinationStorage {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EC2VolumePopulator) DeepCopyInto(out *EC2VolumePopulator) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EC2VolumePopulator.
+func (in *EC2VolumePopulator) DeepCopy() *EC2VolumePopulator {
+	if in == nil {
+		return nil
+	}
+	out := new(EC2VolumePopulator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EC2VolumePopulator) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EC2VolumePopulatorList) DeepCopyInto(out *EC2VolumePopulatorList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]EC2VolumePopulator, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EC2VolumePopulatorList.
+func (in *EC2VolumePopulatorList) DeepCopy() *EC2VolumePopulatorList {
+	if in == nil {
+		return nil
+	}
+	out := new(EC2VolumePopulatorList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EC2VolumePopulatorList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EC2VolumePopulatorSpec) DeepCopyInto(out *EC2VolumePopulatorSpec) {
+	*out = *in
+	if in.TransferNetwork != nil {
+		in, out := &in.TransferNetwork, &out.TransferNetwork
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EC2VolumePopulatorSpec.
+func (in *EC2VolumePopulatorSpec) DeepCopy() *EC2VolumePopulatorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EC2VolumePopulatorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EC2VolumePopulatorStatus) DeepCopyInto(out *EC2VolumePopulatorStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EC2VolumePopulatorStatus.
+func (in *EC2VolumePopulatorStatus) DeepCopy() *EC2VolumePopulatorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EC2VolumePopulatorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Hook) DeepCopyInto(out *Hook) {
 	*out = *in
@@ -241,6 +441,100 @@ func (in *HostStatus) DeepCopy() *HostStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LibvirtVolumePopulator) DeepCopyInto(out *LibvirtVolumePopulator) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LibvirtVolumePopulator.
+func (in *LibvirtVolumePopulator) DeepCopy() *LibvirtVolumePopulator {
+	if in == nil {
+		return nil
+	}
+	out := new(LibvirtVolumePopulator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LibvirtVolumePopulator) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LibvirtVolumePopulatorList) DeepCopyInto(out *LibvirtVolumePopulatorList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]LibvirtVolumePopulator, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LibvirtVolumePopulatorList.
+func (in *LibvirtVolumePopulatorList) DeepCopy() *LibvirtVolumePopulatorList {
+	if in == nil {
+		return nil
+	}
+	out := new(LibvirtVolumePopulatorList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LibvirtVolumePopulatorList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LibvirtVolumePopulatorSpec) DeepCopyInto(out *LibvirtVolumePopulatorSpec) {
+	*out = *in
+	if in.TransferNetwork != nil {
+		in, out := &in.TransferNetwork, &out.TransferNetwork
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LibvirtVolumePopulatorSpec.
+func (in *LibvirtVolumePopulatorSpec) DeepCopy() *LibvirtVolumePopulatorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LibvirtVolumePopulatorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LibvirtVolumePopulatorStatus) DeepCopyInto(out *LibvirtVolumePopulatorStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LibvirtVolumePopulatorStatus.
+func (in *LibvirtVolumePopulatorStatus) DeepCopy() *LibvirtVolumePopulatorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LibvirtVolumePopulatorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MapStatus) DeepCopyInto(out *MapStatus) {
 	*out = *in
@@ -317,6 +611,89 @@ func (in *MigrationList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigrationRecord) DeepCopyInto(out *MigrationRecord) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationRecord.
+func (in *MigrationRecord) DeepCopy() *MigrationRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MigrationRecord) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigrationRecordList) DeepCopyInto(out *MigrationRecordList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MigrationRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationRecordList.
+func (in *MigrationRecordList) DeepCopy() *MigrationRecordList {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationRecordList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MigrationRecordList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigrationRecordSpec) DeepCopyInto(out *MigrationRecordSpec) {
+	*out = *in
+	out.Plan = in.Plan
+	out.Migration = in.Migration
+	out.Source = in.Source
+	out.VM = in.VM
+	in.Timed.DeepCopyInto(&out.Timed)
+	if in.Errors != nil {
+		in, out := &in.Errors, &out.Errors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationRecordSpec.
+func (in *MigrationRecordSpec) DeepCopy() *MigrationRecordSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationRecordSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MigrationSpec) DeepCopyInto(out *MigrationSpec) {
 	*out = *in
@@ -330,6 +707,23 @@ func (in *MigrationSpec) DeepCopyInto(out *MigrationSpec) {
 		in, out := &in.Cutover, &out.Cutover
 		*out = (*in).DeepCopy()
 	}
+	if in.Approved != nil {
+		in, out := &in.Approved, &out.Approved
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.GateApprovals != nil {
+		in, out := &in.GateApprovals, &out.GateApprovals
+		*out = make([]plan.GateApproval, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Recopy != nil {
+		in, out := &in.Recopy, &out.Recopy
+		*out = make([]plan.DiskRecopy, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationSpec.
@@ -437,7 +831,9 @@ func (in *NetworkMapSpec) DeepCopyInto(out *NetworkMapSpec) {
 	if in.Map != nil {
 		in, out := &in.Map, &out.Map
 		*out = make([]NetworkPair, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -471,6 +867,11 @@ func (in *NetworkPair) DeepCopyInto(out *NetworkPair) {
 	*out = *in
 	out.Source = in.Source
 	out.Destination = in.Destination
+	if in.Scope != nil {
+		in, out := &in.Scope, &out.Scope
+		*out = new(NetworkPairScope)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPair.
@@ -483,6 +884,31 @@ func (in *NetworkPair) DeepCopy() *NetworkPair {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPairScope) DeepCopyInto(out *NetworkPairScope) {
+	*out = *in
+	if in.VMs != nil {
+		in, out := &in.VMs, &out.VMs
+		*out = make([]ref.Ref, len(*in))
+		copy(*out, *in)
+	}
+	if in.NICs != nil {
+		in, out := &in.NICs, &out.NICs
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPairScope.
+func (in *NetworkPairScope) DeepCopy() *NetworkPairScope {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPairScope)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OffloadPlugin) DeepCopyInto(out *OffloadPlugin) {
 	*out = *in
@@ -769,6 +1195,11 @@ func (in *PlanList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PlanSpec) DeepCopyInto(out *PlanSpec) {
 	*out = *in
+	if in.TargetNamespaceTemplate != nil {
+		in, out := &in.TargetNamespaceTemplate, &out.TargetNamespaceTemplate
+		*out = new(plan.NamespaceTemplate)
+		(*in).DeepCopyInto(*out)
+	}
 	out.Provider = in.Provider
 	out.Map = in.Map
 	if in.VMs != nil {
@@ -778,16 +1209,93 @@ func (in *PlanSpec) DeepCopyInto(out *PlanSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]plan.Group, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ApprovalGates != nil {
+		in, out := &in.ApprovalGates, &out.ApprovalGates
+		*out = new(plan.ApprovalGates)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.TransferNetwork != nil {
 		in, out := &in.TransferNetwork, &out.TransferNetwork
 		*out = new(v1.ObjectReference)
 		**out = **in
 	}
+	if in.PVCMetadata != nil {
+		in, out := &in.PVCMetadata, &out.PVCMetadata
+		*out = new(plan.VolumeMetadata)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.InstallLegacyDrivers != nil {
 		in, out := &in.InstallLegacyDrivers, &out.InstallLegacyDrivers
 		*out = new(bool)
 		**out = **in
 	}
+	if in.ConversionPod != nil {
+		in, out := &in.ConversionPod, &out.ConversionPod
+		*out = new(plan.PodSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conversion != nil {
+		in, out := &in.Conversion, &out.Conversion
+		*out = new(plan.ConversionSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeviceMap != nil {
+		in, out := &in.DeviceMap, &out.DeviceMap
+		*out = make([]plan.DeviceMapping, len(*in))
+		copy(*out, *in)
+	}
+	if in.CPUModel != nil {
+		in, out := &in.CPUModel, &out.CPUModel
+		*out = new(plan.CPUModel)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TargetPlacement != nil {
+		in, out := &in.TargetPlacement, &out.TargetPlacement
+		*out = new(plan.VMPlacement)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TestSpec != nil {
+		in, out := &in.TestSpec, &out.TestSpec
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
+	if in.AttributeMap != nil {
+		in, out := &in.AttributeMap, &out.AttributeMap
+		*out = make([]plan.AttributeMapping, len(*in))
+		copy(*out, *in)
+	}
+	if in.CDROMs != nil {
+		in, out := &in.CDROMs, &out.CDROMs
+		*out = new(plan.CDROMSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RDMDisks != nil {
+		in, out := &in.RDMDisks, &out.RDMDisks
+		*out = new(plan.RDMSettings)
+		**out = **in
+	}
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		*out = new(plan.MemorySettings)
+		**out = **in
+	}
+	if in.Replication != nil {
+		in, out := &in.Replication, &out.Replication
+		*out = new(plan.Replication)
+		**out = **in
+	}
+	if in.Backup != nil {
+		in, out := &in.Backup, &out.Backup
+		*out = new(plan.BackupSettings)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlanSpec.
@@ -920,6 +1428,36 @@ func (in *ProviderStatus) DeepCopy() *ProviderStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecordRef) DeepCopyInto(out *RecordRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecordRef.
+func (in *RecordRef) DeepCopy() *RecordRef {
+	if in == nil {
+		return nil
+	}
+	out := new(RecordRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageAttributes) DeepCopyInto(out *StorageAttributes) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageAttributes.
+func (in *StorageAttributes) DeepCopy() *StorageAttributes {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageAttributes)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageMap) DeepCopyInto(out *StorageMap) {
 	*out = *in
@@ -991,6 +1529,13 @@ func (in *StorageMapSpec) DeepCopyInto(out *StorageMapSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]StorageRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageMapSpec.
@@ -1003,11 +1548,36 @@ func (in *StorageMapSpec) DeepCopy() *StorageMapSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageMatch) DeepCopyInto(out *StorageMatch) {
+	*out = *in
+	if in.MinCapacity != nil {
+		in, out := &in.MinCapacity, &out.MinCapacity
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MaxCapacity != nil {
+		in, out := &in.MaxCapacity, &out.MaxCapacity
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageMatch.
+func (in *StorageMatch) DeepCopy() *StorageMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StoragePair) DeepCopyInto(out *StoragePair) {
 	*out = *in
 	out.Source = in.Source
-	out.Destination = in.Destination
+	in.Destination.DeepCopyInto(&out.Destination)
 	if in.OffloadPlugin != nil {
 		in, out := &in.OffloadPlugin, &out.OffloadPlugin
 		*out = new(OffloadPlugin)
@@ -1025,6 +1595,28 @@ func (in *StoragePair) DeepCopy() *StoragePair {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageRule) DeepCopyInto(out *StorageRule) {
+	*out = *in
+	in.Match.DeepCopyInto(&out.Match)
+	in.Destination.DeepCopyInto(&out.Destination)
+	if in.OffloadPlugin != nil {
+		in, out := &in.OffloadPlugin, &out.OffloadPlugin
+		*out = new(OffloadPlugin)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageRule.
+func (in *StorageRule) DeepCopy() *StorageRule {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VSphereXcopyPluginConfig) DeepCopyInto(out *VSphereXcopyPluginConfig) {
 	*out = *in
@@ -1,22 +1,26 @@
 package base
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"errors"
 	"fmt"
-	"net"
 	"net/url"
 	"strconv"
 	"time"
 
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
 	"github.com/kubev2v/forklift/pkg/controller/provider/web"
 	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
 	"github.com/kubev2v/forklift/pkg/lib/logging"
 	"github.com/kubev2v/forklift/pkg/lib/util"
+	"github.com/kubev2v/forklift/pkg/settings"
 	core "k8s.io/api/core/v1"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -27,11 +31,27 @@ const (
 	LongReQ = time.Second * 30
 )
 
+// Application settings.
+var Settings = &settings.Settings
+
+// Re-queue interval (milliseconds) with the
+// configured jitter.
+func ReQ(interval int) time.Duration {
+	reQ := time.Duration(interval) * time.Millisecond
+	if Settings.Requeue.Jitter > 0 {
+		reQ = wait.Jitter(reQ, float64(Settings.Requeue.Jitter)/100)
+	}
+	return reQ
+}
+
 // Base reconciler.
 type Reconciler struct {
 	record.EventRecorder
 	client.Client
 	Log logging.LevelLogger
+	// Re-queue interval (milliseconds) on error.
+	// Defaults to SlowReQ.
+	Interval int
 }
 
 // Reconcile started.
@@ -52,6 +72,9 @@ func (r *Reconciler) Ended(reQin time.Duration, err error) (reQ time.Duration) {
 		return
 	}
 	reQ = SlowReQ
+	if r.Interval > 0 {
+		reQ = ReQ(r.Interval)
+	}
 	if k8serr.IsConflict(err) {
 		r.Log.Info(err.Error())
 		return
@@ -149,10 +172,7 @@ func VerifyTLSConnection(rawURL string, secret *core.Secret) (*x509.Certificate,
 	tlsConfig.RootCAs.AddCert(cert)
 
 	// Ensure host:port
-	host := parsedURL.Host
-	if _, _, err := net.SplitHostPort(parsedURL.Host); err != nil {
-		host = parsedURL.Host + ":443"
-	}
+	host := util.HostPort(parsedURL.Host, "443")
 
 	// Dial TLS
 	conn, err := tls.Dial("tcp", host, tlsConfig)
@@ -163,3 +183,41 @@ func VerifyTLSConnection(rawURL string, secret *core.Secret) (*x509.Certificate,
 
 	return cert, nil
 }
+
+// Key of the CA bundle in the configmap.
+const CABundleKey = "ca-bundle.crt"
+
+// WithCABundle returns a copy of the provider secret with the
+// CA bundle (configmap) referenced by the provider `caBundle`
+// setting appended to the `cacert`.
+func WithCABundle(c client.Client, provider *api.Provider, secret *core.Secret) (*core.Secret, error) {
+	name := provider.Spec.Settings[api.CABundle]
+	if name == "" || secret == nil {
+		return secret, nil
+	}
+	configMap := &core.ConfigMap{}
+	err := c.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: provider.Namespace,
+			Name:      name,
+		},
+		configMap)
+	if err != nil {
+		return nil, liberr.Wrap(err, "configmap", name)
+	}
+	bundle, found := configMap.Data[CABundleKey]
+	if !found {
+		return nil, liberr.New("CA bundle not found.", "configmap", name, "key", CABundleKey)
+	}
+	secret = secret.DeepCopy()
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	cacert := secret.Data["cacert"]
+	if len(cacert) > 0 && cacert[len(cacert)-1] != '\n' {
+		cacert = append(cacert, '\n')
+	}
+	secret.Data["cacert"] = append(cacert, bundle...)
+	return secret, nil
+}
@@ -0,0 +1,43 @@
+package base
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+	"github.com/onsi/gomega"
+)
+
+func TestReQ(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	jitter := Settings.Requeue.Jitter
+	defer func() {
+		Settings.Requeue.Jitter = jitter
+	}()
+
+	Settings.Requeue.Jitter = 0
+	g.Expect(ReQ(3000)).To(gomega.Equal(3 * time.Second))
+
+	Settings.Requeue.Jitter = 10
+	for i := 0; i < 100; i++ {
+		reQ := ReQ(3000)
+		g.Expect(reQ).To(gomega.BeNumerically(">=", 3*time.Second))
+		g.Expect(reQ).To(gomega.BeNumerically("<", 3300*time.Millisecond))
+	}
+}
+
+func TestEnded(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	jitter := Settings.Requeue.Jitter
+	defer func() {
+		Settings.Requeue.Jitter = jitter
+	}()
+	Settings.Requeue.Jitter = 0
+
+	r := Reconciler{Log: logging.WithName("test")}
+	g.Expect(r.Ended(FastReQ, nil)).To(gomega.Equal(FastReQ))
+	g.Expect(r.Ended(FastReQ, errors.New("failed"))).To(gomega.Equal(SlowReQ))
+	r.Interval = 60000
+	g.Expect(r.Ended(FastReQ, errors.New("failed"))).To(gomega.Equal(time.Minute))
+}
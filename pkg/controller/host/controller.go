@@ -18,7 +18,6 @@ package host
 
 import (
 	"context"
-	"time"
 
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
 	"github.com/kubev2v/forklift/pkg/controller/base"
@@ -179,10 +178,10 @@ func (r Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (r
 	}
 
 	// Connection test failed.
-	// Determined that ESX host retry of 15 minutes
+	// Determined that ESX host retry of 15 minutes (default)
 	// will not trigger DOS response.
 	if host.Status.HasCondition(ConnectionTestFailed) {
-		result.RequeueAfter = time.Minute * 15
+		result.RequeueAfter = base.ReQ(Settings.Requeue.Host)
 	}
 
 	// Done
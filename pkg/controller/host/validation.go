@@ -14,6 +14,7 @@ import (
 	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
 	liberr "github.com/kubev2v/forklift/pkg/lib/error"
 	libref "github.com/kubev2v/forklift/pkg/lib/ref"
+	"github.com/kubev2v/forklift/pkg/lib/util"
 	core "k8s.io/api/core/v1"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -30,6 +31,7 @@ const (
 	ConnectionTestFailed    = "ConnectionTestFailed"
 	InMaintenance           = "InMaintenance"
 	NotHealthy              = "NotHealthy"
+	ThumbprintChanged       = "ThumbprintChanged"
 )
 
 // Categories
@@ -51,6 +53,7 @@ const (
 	Completed      = "Completed"
 	Tested         = "Tested"
 	StateEvaluated = "StateEvaluated"
+	Fetched        = "Fetched"
 )
 
 // Statuses
@@ -277,7 +280,7 @@ func (r *Reconciler) testConnection(host *api.Host) (err error) {
 	var testErr error
 	switch provider.Type() {
 	case api.VSphere:
-		url := fmt.Sprintf("https://%s/sdk", host.Spec.IpAddress)
+		url := fmt.Sprintf("https://%s/sdk", util.URLHost(host.Spec.IpAddress))
 		hostModel := &vsphere.Host{}
 		pErr := inventory.Find(hostModel, host.Spec.Ref)
 		if pErr != nil {
@@ -306,10 +309,12 @@ func (r *Reconciler) testConnection(host *api.Host) (err error) {
 				},
 			)
 		}
+		r.validateThumbprint(host, hostModel)
 		secret.Data["thumbprint"] = []byte(hostModel.Thumbprint)
 		h := adapter.EsxHost{
-			Secret: secret,
-			URL:    url,
+			Secret:   secret,
+			URL:      url,
+			Provider: provider,
 		}
 		r.Log.V(1).Info(
 			"Testing connection.",
@@ -351,3 +356,35 @@ func (r *Reconciler) testConnection(host *api.Host) (err error) {
 
 	return
 }
+
+// Warn when the certificate thumbprint served by the host
+// differs from the thumbprint reported by vCenter.
+func (r *Reconciler) validateThumbprint(host *api.Host, hostModel *vsphere.Host) {
+	if hostModel.Thumbprint == "" {
+		return
+	}
+	fetched, err := util.Thumbprints.Get(host.Spec.IpAddress)
+	if err != nil {
+		r.Log.V(1).Info(
+			"Host thumbprint not fetched.",
+			"address",
+			host.Spec.IpAddress,
+			"reason",
+			err.Error())
+		return
+	}
+	if fetched.Thumbprint == hostModel.Thumbprint {
+		return
+	}
+	host.Status.SetCondition(
+		libcnd.Condition{
+			Type:     ThumbprintChanged,
+			Status:   True,
+			Reason:   Fetched,
+			Category: Warn,
+			Message: fmt.Sprintf(
+				"The host certificate thumbprint %s differs from %s reported by vCenter.",
+				fetched.Thumbprint,
+				hostModel.Thumbprint),
+		})
+}
@@ -0,0 +1,106 @@
+package leader
+
+import (
+	"context"
+	"time"
+
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+	"github.com/kubev2v/forklift/pkg/settings"
+	coordination "k8s.io/api/coordination/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Package logger.
+var log = logging.WithName("leader")
+
+// Application settings.
+var Settings = &settings.Settings
+
+// Takeover requested by annotating the leader lease.
+// The value is informational (e.g. the requester or reason).
+//
+//	kubectl annotate lease forklift-controller-main \
+//	  forklift.konveyor.io/takeover=maintenance
+const AnnTakeover = "forklift.konveyor.io/takeover"
+
+// Takeover.
+// Watches the leader lease for a takeover requested by the
+// operators. The leader clears the request and steps down
+// (releases the lease) so that a passive replica takes over
+// and resumes the in-flight migrations from the checkpoints.
+// The takeover runs on the leader only.
+type Takeover struct {
+	// Uncached reader.
+	Reader client.Reader
+	// Writer.
+	Writer client.Writer
+	// Step down (stop the manager and release the lease).
+	Release func()
+}
+
+// Run until the context is done or the takeover requested.
+func (r *Takeover) Start(ctx context.Context) (err error) {
+	interval := time.Duration(Settings.Leader.RetryPeriod) * time.Second
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			requested, rErr := r.requested(ctx)
+			if rErr != nil {
+				log.Error(rErr, "Takeover request check failed.")
+				continue
+			}
+			if requested {
+				log.Info(
+					"Takeover requested, stepping down.",
+					"identity",
+					Settings.Leader.Identity)
+				r.Release()
+				return
+			}
+		}
+	}
+}
+
+// Determine whether the takeover has been requested.
+// The request is cleared so that the next leader
+// does not step down.
+func (r *Takeover) requested(ctx context.Context) (requested bool, err error) {
+	lease := &coordination.Lease{}
+	err = r.Reader.Get(
+		ctx,
+		client.ObjectKey{
+			Namespace: Settings.Leader.LeaseNamespace,
+			Name:      Settings.Leader.LeaseName,
+		},
+		lease)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	reason, requested := lease.Annotations[AnnTakeover]
+	if !requested {
+		return
+	}
+	delete(lease.Annotations, AnnTakeover)
+	err = r.Writer.Update(ctx, lease)
+	if err != nil {
+		requested = false
+		err = liberr.Wrap(err)
+		return
+	}
+	log.Info(
+		"Takeover request cleared.",
+		"reason",
+		reason)
+	return
+}
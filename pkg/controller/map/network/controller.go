@@ -53,6 +53,7 @@ func Add(mgr manager.Manager) error {
 			EventRecorder: mgr.GetEventRecorderFor(Name),
 			Client:        mgr.GetClient(),
 			Log:           log,
+			Interval:      Settings.Requeue.Map,
 		},
 	}
 	cnt, err := controller.New(
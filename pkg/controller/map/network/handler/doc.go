@@ -2,6 +2,12 @@ package handler
 
 import (
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/controller/map/network/handler/azure"
+	"github.com/kubev2v/forklift/pkg/controller/map/network/handler/catalog"
+	"github.com/kubev2v/forklift/pkg/controller/map/network/handler/ec2"
+	"github.com/kubev2v/forklift/pkg/controller/map/network/handler/image"
+	"github.com/kubev2v/forklift/pkg/controller/map/network/handler/libvirt"
+	"github.com/kubev2v/forklift/pkg/controller/map/network/handler/nutanix"
 	"github.com/kubev2v/forklift/pkg/controller/map/network/handler/ocp"
 	"github.com/kubev2v/forklift/pkg/controller/map/network/handler/openstack"
 	"github.com/kubev2v/forklift/pkg/controller/map/network/handler/ova"
@@ -53,6 +59,36 @@ func New(
 			client,
 			channel,
 			provider)
+	case api.Nutanix:
+		h, err = nutanix.New(
+			client,
+			channel,
+			provider)
+	case api.EC2:
+		h, err = ec2.New(
+			client,
+			channel,
+			provider)
+	case api.Azure:
+		h, err = azure.New(
+			client,
+			channel,
+			provider)
+	case api.Libvirt:
+		h, err = libvirt.New(
+			client,
+			channel,
+			provider)
+	case api.Image:
+		h, err = image.New(
+			client,
+			channel,
+			provider)
+	case api.Catalog:
+		h, err = catalog.New(
+			client,
+			channel,
+			provider)
 	default:
 		err = liberr.New("provider not supported.")
 	}
@@ -1,12 +1,15 @@
 package network
 
 import (
+	"encoding/json"
 	"errors"
 	"path"
 
+	net "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
 	refapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
 	"github.com/kubev2v/forklift/pkg/controller/provider/web"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/ocp"
 	"github.com/kubev2v/forklift/pkg/controller/validation"
 	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
 )
@@ -31,6 +34,7 @@ const (
 	NotSet    = "NotSet"
 	NotFound  = "NotFound"
 	Ambiguous = "Ambiguous"
+	NotValid  = "NotValid"
 )
 
 // Statuses
@@ -43,9 +47,17 @@ const (
 const (
 	Pod     = "pod"
 	Multus  = "multus"
+	SRIOV   = "sriov"
+	UDN     = "udn"
 	Ignored = "ignored"
 )
 
+// NAD annotation set by the SR-IOV network operator.
+const AnnResourceName = "k8s.v1.cni.cncf.io/resourceName"
+
+// CNI type of the OVN-Kubernetes user-defined networks.
+const OvnOverlayType = "ovn-k8s-cni-overlay"
+
 // Validate the mp resource.
 func (r *Reconciler) validate(mp *api.NetworkMap) error {
 	pv := validation.ProviderPair{Client: r}
@@ -149,12 +161,13 @@ func (r *Reconciler) validateDestination(mp *api.NetworkMap) (err error) {
 	list := mp.Spec.Map
 	notFound := []string{}
 	ambiguous := []string{}
+	notValid := []string{}
 next:
 	for _, entry := range list {
 		switch entry.Destination.Type {
 		case Ignored, Pod:
 			continue next
-		case Multus:
+		case Multus, SRIOV, UDN:
 			if entry.Destination.Namespace == "" {
 				ambiguous = append(
 					ambiguous,
@@ -166,7 +179,7 @@ next:
 			id := path.Join(
 				entry.Destination.Namespace,
 				entry.Destination.Name)
-			_, pErr := inventory.Network(&refapi.Ref{Name: id})
+			object, pErr := inventory.Network(&refapi.Ref{Name: id})
 			if pErr != nil {
 				if errors.As(pErr, &web.NotFoundError{}) {
 					notFound = append(
@@ -178,9 +191,25 @@ next:
 					err = pErr
 					return
 				}
+				continue
+			}
+			if nad, cast := object.(*ocp.NetworkAttachmentDefinition); cast {
+				if !nadMatches(&nad.Object, &entry.Destination) {
+					notValid = append(notValid, id)
+				}
 			}
 		}
 	}
+	if len(notValid) > 0 {
+		mp.Status.SetCondition(libcnd.Condition{
+			Type:     DestinationNetworkNotValid,
+			Status:   True,
+			Reason:   NotValid,
+			Category: Critical,
+			Message:  "Destination network (NAD) does not match the network type.",
+			Items:    notValid,
+		})
+	}
 	if len(notFound) > 0 {
 		mp.Status.SetCondition(libcnd.Condition{
 			Type:     DestinationNetworkNotValid,
@@ -204,3 +233,27 @@ next:
 
 	return
 }
+
+// Whether the NAD provides the destination network type.
+// SR-IOV NADs request a device resource (the mapped one when specified)
+// and user-defined network NADs are OVN-Kubernetes overlays.
+func nadMatches(nad *net.NetworkAttachmentDefinition, destination *api.DestinationNetwork) bool {
+	switch destination.Type {
+	case SRIOV:
+		resourceName, found := nad.Annotations[AnnResourceName]
+		if !found {
+			return false
+		}
+		return destination.ResourceName == "" || destination.ResourceName == resourceName
+	case UDN:
+		config := struct {
+			Type string `json:"type"`
+		}{}
+		err := json.Unmarshal([]byte(nad.Spec.Config), &config)
+		if err != nil {
+			return false
+		}
+		return config.Type == OvnOverlayType
+	}
+	return true
+}
@@ -55,6 +55,7 @@ func Add(mgr manager.Manager) error {
 			EventRecorder: mgr.GetEventRecorderFor(Name),
 			Client:        mgr.GetClient(),
 			Log:           log,
+			Interval:      Settings.Requeue.Map,
 		},
 	}
 	cnt, err := controller.New(
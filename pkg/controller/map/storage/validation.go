@@ -2,6 +2,7 @@ package storage
 
 import (
 	"errors"
+	"regexp"
 
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
 	refapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
@@ -14,6 +15,7 @@ import (
 const (
 	SourceStorageNotValid      = "SourceStorageNotValid"
 	DestinationStorageNotValid = "DestinationStorageNotValid"
+	StorageRuleNotValid        = "StorageRuleNotValid"
 )
 
 // Categories
@@ -30,6 +32,7 @@ const (
 	NotSet    = "NotSet"
 	NotFound  = "NotFound"
 	Ambiguous = "Ambiguous"
+	NotValid  = "NotValid"
 )
 
 // Statuses
@@ -63,10 +66,34 @@ func (r *Reconciler) validate(mp *api.StorageMap) error {
 	if err != nil {
 		return err
 	}
+	r.validateRules(mp)
 
 	return nil
 }
 
+// Validate rules.
+func (r *Reconciler) validateRules(mp *api.StorageMap) {
+	notValid := []string{}
+	for _, rule := range mp.Spec.Rules {
+		if rule.Match.Name == "" {
+			continue
+		}
+		if _, err := regexp.Compile(rule.Match.Name); err != nil {
+			notValid = append(notValid, rule.Match.Name)
+		}
+	}
+	if len(notValid) > 0 {
+		mp.Status.SetCondition(libcnd.Condition{
+			Type:     StorageRuleNotValid,
+			Status:   True,
+			Reason:   NotValid,
+			Category: Critical,
+			Message:  "Storage rule name is not a valid regular expression.",
+			Items:    notValid,
+		})
+	}
+}
+
 // Validate source refs.
 func (r *Reconciler) validateSource(mp *api.StorageMap) (err error) {
 	provider := mp.Referenced.Provider.Source
@@ -138,13 +165,19 @@ func (r *Reconciler) validateDestination(mp *api.StorageMap) (err error) {
 		return
 	}
 	notValid := []string{}
-	list := mp.Spec.Map
-	for _, entry := range list {
-		name := entry.Destination.StorageClass
+	list := []api.DestinationStorage{}
+	for _, entry := range mp.Spec.Map {
+		list = append(list, entry.Destination)
+	}
+	for _, rule := range mp.Spec.Rules {
+		list = append(list, rule.Destination)
+	}
+	for _, destination := range list {
+		name := destination.StorageClass
 		_, pErr := inventory.Storage(&refapi.Ref{Name: name})
 		if pErr != nil {
 			if errors.As(pErr, &web.NotFoundError{}) {
-				notValid = append(notValid, entry.Destination.StorageClass)
+				notValid = append(notValid, destination.StorageClass)
 			} else {
 				err = pErr
 				return
@@ -3,6 +3,7 @@ package migration
 import (
 	"context"
 	"errors"
+	"slices"
 
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
 	plancnt "github.com/kubev2v/forklift/pkg/controller/plan"
@@ -16,15 +17,16 @@ import (
 
 // Types
 const (
-	PlanNotValid = "PlanNotValid"
-	PlanNotReady = "PlanNotReady"
-	VMNotFound   = "VMNotFound"
-	VMNotUnique  = "VMNotUnique"
-	Running      = "Running"
-	Executing    = plancnt.Executing
-	Succeeded    = plancnt.Succeeded
-	Failed       = plancnt.Failed
-	Canceled     = plancnt.Canceled
+	PlanNotValid       = "PlanNotValid"
+	PlanNotReady       = "PlanNotReady"
+	VMNotFound         = "VMNotFound"
+	VMNotUnique        = "VMNotUnique"
+	RecopyNotSupported = "RecopyNotSupported"
+	Running            = "Running"
+	Executing          = plancnt.Executing
+	Succeeded          = plancnt.Succeeded
+	Failed             = plancnt.Failed
+	Canceled           = plancnt.Canceled
 )
 
 // Categories
@@ -38,9 +40,10 @@ const (
 
 // Reasons
 const (
-	NotSet    = "NotSet"
-	NotFound  = "NotFound"
-	Ambiguous = "Ambiguous"
+	NotSet       = "NotSet"
+	NotFound     = "NotFound"
+	Ambiguous    = "Ambiguous"
+	NotSupported = "NotSupported"
 )
 
 // Statuses
@@ -91,7 +94,20 @@ func (r *Reconciler) validate(migration *api.Migration) (plan *api.Plan, err err
 		return
 	}
 
-	// Validate the refs in the Cancel array
+	// Disks are copied again by cold migrations only.
+	if len(migration.Spec.Recopy) > 0 && plan.Spec.Warm {
+		migration.Status.SetCondition(
+			libcnd.Condition{
+				Type:     RecopyNotSupported,
+				Status:   True,
+				Reason:   NotSupported,
+				Category: Critical,
+				Message:  "Disks cannot be copied again by warm migrations.",
+			})
+		return
+	}
+
+	// Validate the refs in the Cancel and Recopy arrays
 	notFound := libcnd.Condition{
 		Type:     VMNotFound,
 		Status:   True,
@@ -124,7 +140,11 @@ func (r *Reconciler) validate(migration *api.Migration) (plan *api.Plan, err err
 	if err != nil {
 		return
 	}
-	for _, ref := range migration.Spec.Cancel {
+	refs := slices.Clone(migration.Spec.Cancel)
+	for _, recopy := range migration.Spec.Recopy {
+		refs = append(refs, recopy.VM)
+	}
+	for _, ref := range refs {
 		_, err = inventory.VM(&ref)
 		if err != nil {
 			if errors.As(err, &web.NotFoundError{}) {
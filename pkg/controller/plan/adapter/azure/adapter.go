@@ -0,0 +1,47 @@
+package azure
+
+import (
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/controller/plan/adapter/base"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+)
+
+// Azure adapter.
+type Adapter struct{}
+
+// Constructs an Azure builder.
+func (r *Adapter) Builder(ctx *plancontext.Context) (builder base.Builder, err error) {
+	builder = &Builder{Context: ctx}
+	return
+}
+
+// Constructs an Azure validator.
+func (r *Adapter) Validator(plan *api.Plan) (validator base.Validator, err error) {
+	v := &Validator{plan: plan}
+	err = v.Load()
+	if err != nil {
+		return
+	}
+	validator = v
+	return
+}
+
+// Constructs an Azure client.
+func (r *Adapter) Client(ctx *plancontext.Context) (client base.Client, err error) {
+	c := &Client{
+		Context: ctx,
+	}
+	c.Log = ctx.Log.WithName("client")
+	err = c.connect()
+	if err != nil {
+		return
+	}
+	client = c
+	return
+}
+
+// Constucts a destination client.
+func (r *Adapter) DestinationClient(ctx *plancontext.Context) (destinationClient base.DestinationClient, err error) {
+	destinationClient = &DestinationClient{Context: ctx}
+	return
+}
@@ -0,0 +1,82 @@
+package azure
+
+import (
+	"testing"
+
+	planbase "github.com/kubev2v/forklift/pkg/controller/plan/adapter/base"
+	azure "github.com/kubev2v/forklift/pkg/controller/provider/model/azure"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/web/azure"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cnv "kubevirt.io/api/core/v1"
+)
+
+func TestMapDisks(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	builder := &Builder{}
+	vm := &model.VM{
+		Disks: []azure.VMDisk{
+			{ID: "disk-2", Lun: 1, Managed: true},
+			{ID: "disk-1", Lun: 0, Managed: true},
+			{ID: "disk-0", OS: true, Managed: true},
+		},
+	}
+	pvc := func(id string) *core.PersistentVolumeClaim {
+		return &core.PersistentVolumeClaim{
+			ObjectMeta: meta.ObjectMeta{
+				Name:        "pvc-" + id,
+				Annotations: map[string]string{planbase.AnnDiskSource: id},
+			},
+		}
+	}
+	object := &cnv.VirtualMachineSpec{Template: &cnv.VirtualMachineInstanceTemplateSpec{}}
+	builder.mapDisks(vm, []*core.PersistentVolumeClaim{pvc("disk-1"), pvc("disk-2"), pvc("disk-0")}, object)
+
+	volumes := object.Template.Spec.Volumes
+	disks := object.Template.Spec.Domain.Devices.Disks
+	g.Expect(volumes).To(gomega.HaveLen(3))
+	g.Expect(volumes[0].PersistentVolumeClaim.ClaimName).To(gomega.Equal("pvc-disk-0"))
+	g.Expect(volumes[1].PersistentVolumeClaim.ClaimName).To(gomega.Equal("pvc-disk-1"))
+	g.Expect(volumes[2].PersistentVolumeClaim.ClaimName).To(gomega.Equal("pvc-disk-2"))
+	g.Expect(*disks[0].BootOrder).To(gomega.Equal(uint(1)))
+	g.Expect(disks[1].BootOrder).To(gomega.BeNil())
+}
+
+func TestMapFirmware(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	builder := &Builder{}
+	cases := map[string]bool{
+		HyperVGen1: false,
+		"":         false,
+		HyperVGen2: true,
+	}
+	for generation, efi := range cases {
+		vm := &model.VM{}
+		vm.ID = "vm-0123"
+		vm.HyperVGeneration = generation
+		object := &cnv.VirtualMachineSpec{Template: &cnv.VirtualMachineInstanceTemplateSpec{}}
+		builder.mapFirmware(vm, object)
+		firmware := object.Template.Spec.Domain.Firmware
+		g.Expect(firmware.Serial).To(gomega.Equal("vm-0123"))
+		if efi {
+			g.Expect(firmware.Bootloader.EFI).ToNot(gomega.BeNil())
+			g.Expect(*firmware.Bootloader.EFI.SecureBoot).To(gomega.BeFalse())
+		} else {
+			g.Expect(firmware.Bootloader.BIOS).ToNot(gomega.BeNil())
+		}
+	}
+}
+
+func TestMapCPU(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	builder := &Builder{}
+	vm := &model.VM{}
+	vm.VCPUs = 4
+	object := &cnv.VirtualMachineSpec{Template: &cnv.VirtualMachineInstanceTemplateSpec{}}
+	builder.mapCPU(vm, object)
+	cpu := object.Template.Spec.Domain.CPU
+	g.Expect(cpu.Sockets).To(gomega.Equal(uint32(1)))
+	g.Expect(cpu.Cores).To(gomega.Equal(uint32(4)))
+	g.Expect(cpu.Threads).To(gomega.Equal(uint32(1)))
+}
@@ -0,0 +1,352 @@
+package azure
+
+import (
+	"fmt"
+	"strconv"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	"github.com/kubev2v/forklift/pkg/controller/plan/util"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/web/azure"
+	libclient "github.com/kubev2v/forklift/pkg/lib/client/azure"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	libutil "github.com/kubev2v/forklift/pkg/lib/util"
+	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+)
+
+// Azure VM Client.
+// The managed disks are snapshot (incrementally) and the
+// snapshots are read by the Azure volume populator.
+type Client struct {
+	libclient.Client
+	Context *plancontext.Context
+}
+
+// Connect.
+func (r *Client) connect() (err error) {
+	r.URL = r.Context.Source.Provider.Spec.URL
+	r.Proxy = libutil.ProviderProxy(r.Context.Source.Provider)
+	r.Dial = libutil.ProviderDialer(r.Context.Source.Provider, nil)
+	r.LoadOptionsFromSecret(r.Context.Source.Secret)
+	err = r.Connect()
+	return
+}
+
+// Power on the source VM.
+func (r *Client) PowerOn(vmRef ref.Ref) (err error) {
+	vm, err := r.getVM(vmRef)
+	if err != nil {
+		return
+	}
+	err = r.StartVirtualMachine(vm.ResourceID)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+	return
+}
+
+// Power off the source VM.
+// The VM is deallocated.
+func (r *Client) PowerOff(vmRef ref.Ref) (err error) {
+	vm, err := r.getVM(vmRef)
+	if err != nil {
+		return
+	}
+	err = r.DeallocateVirtualMachine(vm.ResourceID)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+	return
+}
+
+// Return the source VM's power state.
+func (r *Client) PowerState(vmRef ref.Ref) (state planapi.VMPowerState, err error) {
+	state = planapi.VMPowerStateUnknown
+	vm, err := r.getVM(vmRef)
+	if err != nil {
+		return
+	}
+	object, err := r.GetVirtualMachine(vm.ResourceID)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	switch object.PowerState() {
+	case libclient.PowerStateRunning:
+		state = planapi.VMPowerStateOn
+	case libclient.PowerStateStopped, libclient.PowerStateDeallocated:
+		state = planapi.VMPowerStateOff
+	}
+	return
+}
+
+// Return whether the source VM is powered off.
+func (r *Client) PoweredOff(vmRef ref.Ref) (off bool, err error) {
+	state, err := r.PowerState(vmRef)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	off = state == planapi.VMPowerStateOff
+	return
+}
+
+// Create an (incremental) snapshot of each managed disk.
+// The snapshots are tagged with the precopy (number) which
+// is returned as the snapshot ID. The snapshots taken by the
+// final precopy are tagged as final.
+func (r *Client) CreateSnapshot(vmRef ref.Ref, hostsFunc util.HostsFunc) (snapshotId string, creationTaskId string, err error) {
+	vm, err := r.getVM(vmRef)
+	if err != nil {
+		return
+	}
+	vmStatus, found := r.Context.FindVM(vmRef)
+	if !found || vmStatus.Warm == nil {
+		err = liberr.New("the warm migration status of the VM was not found.", "vm", vmRef.String())
+		return
+	}
+	precopy := vmStatus.Warm.Iterations()
+	final := vmStatus.Phase == api.PhaseCreateFinalSnapshot
+	for _, vmDisk := range vm.Disks {
+		if !vmDisk.Managed {
+			continue
+		}
+		var disk *libclient.Disk
+		disk, err = r.getDisk(vmDisk.ID)
+		if err != nil {
+			return
+		}
+		tags := SnapshotTags(r.Context, vmDisk.ID, precopy, final)
+		name := SnapshotName(r.Context, vmDisk.ID, precopy)
+		_, err = r.Client.CreateSnapshot(disk, name, tags)
+		if err != nil {
+			err = liberr.Wrap(err, "disk", disk.ID)
+			return
+		}
+		r.Log.Info(
+			"Creating the disk snapshot.",
+			"vm",
+			vm.Name,
+			"disk",
+			disk.ID,
+			"snapshot",
+			name)
+	}
+	snapshotId = strconv.Itoa(precopy)
+	return
+}
+
+// Remove a snapshot. No-op for this provider.
+// The snapshots are deleted when the migration is finalized.
+func (r *Client) RemoveSnapshot(vmRef ref.Ref, snapshot string, hostsFunc util.HostsFunc) (removeTaskId string, err error) {
+	return
+}
+
+// Check if the snapshots of the precopy are ready to be copied.
+func (r *Client) CheckSnapshotReady(vmRef ref.Ref, precopy planapi.Precopy, hosts util.HostsFunc) (ready bool, snapshotId string, err error) {
+	vm, err := r.getVM(vmRef)
+	if err != nil {
+		return
+	}
+	n, err := strconv.Atoi(precopy.Snapshot)
+	if err != nil {
+		err = liberr.Wrap(err, "snapshot", precopy.Snapshot)
+		return
+	}
+	ready, err = r.snapshotsReady(vm, n)
+	return
+}
+
+// CheckSnapshotRemove implements base.Client
+func (r *Client) CheckSnapshotRemove(vmRef ref.Ref, precopy planapi.Precopy, hosts util.HostsFunc) (bool, error) {
+	return true, nil
+}
+
+// Get disk deltas for a VM snapshot. No-op for this provider.
+func (r *Client) GetSnapshotDeltas(vmRef ref.Ref, snapshot string, hostsFunc util.HostsFunc) (s map[string]string, err error) {
+	return
+}
+
+// Set DataVolume checkpoints.
+// No-op; the populator copies the snapshots in order.
+func (r *Client) SetCheckpoints(vmRef ref.Ref, precopies []planapi.Precopy, datavolumes []cdi.DataVolume, final bool, hostsFunc util.HostsFunc) (err error) {
+	return
+}
+
+// Close connections to the provider API.
+func (r *Client) Close() {
+}
+
+// Detach disks. No-op for this provider.
+func (r *Client) DetachDisks(vmRef ref.Ref) (err error) {
+	return
+}
+
+// Snapshot the VM disks (cold migration).
+// A single (final) snapshot of each disk is copied.
+// Ready when all of the snapshots have been completed.
+// The snapshots of a warm migration are created by the precopies.
+func (r *Client) PreTransferActions(vmRef ref.Ref) (ready bool, err error) {
+	if r.Context.Plan.Spec.Warm {
+		ready = true
+		return
+	}
+	vm, err := r.getVM(vmRef)
+	if err != nil {
+		return
+	}
+	for _, vmDisk := range vm.Disks {
+		if !vmDisk.Managed {
+			continue
+		}
+		var disk *libclient.Disk
+		disk, err = r.getDisk(vmDisk.ID)
+		if err != nil {
+			return
+		}
+		name := SnapshotName(r.Context, vmDisk.ID, 0)
+		_, err = r.GetSnapshot(r.SnapshotID(libclient.ResourceGroupOf(disk.ID), name))
+		if err == nil {
+			continue
+		}
+		if !r.IsNotFound(err) {
+			err = liberr.Wrap(err, "disk", disk.ID)
+			return
+		}
+		_, err = r.Client.CreateSnapshot(disk, name, SnapshotTags(r.Context, vmDisk.ID, 0, true))
+		if err != nil {
+			err = liberr.Wrap(err, "disk", disk.ID)
+			return
+		}
+		r.Log.Info(
+			"Creating the disk snapshot.",
+			"vm",
+			vm.Name,
+			"disk",
+			disk.ID,
+			"snapshot",
+			name)
+	}
+	ready, err = r.snapshotsReady(vm, 0)
+	return
+}
+
+// Delete the disk snapshots created for the migration.
+func (r *Client) Finalize(vms []*planapi.VMStatus, planName string) {
+	for _, vmStatus := range vms {
+		vm, err := r.getVM(vmStatus.Ref)
+		if err != nil {
+			r.Log.Error(err, "failed to find vm", "vm", vmStatus.Ref.String())
+			continue
+		}
+		for _, vmDisk := range vm.Disks {
+			if !vmDisk.Managed {
+				continue
+			}
+			disk, err := r.getDisk(vmDisk.ID)
+			if err != nil {
+				r.Log.Error(err, "failed to find the disk", "vm", vm.Name, "disk", vmDisk.ID)
+				continue
+			}
+			snapshots, err := r.FindSnapshots(
+				libclient.ResourceGroupOf(disk.ID),
+				map[string]string{
+					libclient.TagMigration: string(r.Context.Migration.UID),
+					libclient.TagDisk:      vmDisk.ID,
+				})
+			if err != nil {
+				r.Log.Error(err, "failed to find the disk snapshots", "vm", vm.Name, "disk", disk.ID)
+				continue
+			}
+			for _, snapshot := range snapshots {
+				err = r.DeleteSnapshot(snapshot.ID)
+				if err != nil {
+					r.Log.Error(err, "failed to delete the disk snapshot", "vm", vm.Name, "snapshot", snapshot.ID)
+				}
+			}
+		}
+	}
+}
+
+// Determine whether the snapshots of the precopy have been completed.
+func (r *Client) snapshotsReady(vm *model.VM, precopy int) (ready bool, err error) {
+	for _, vmDisk := range vm.Disks {
+		if !vmDisk.Managed {
+			continue
+		}
+		var disk *libclient.Disk
+		disk, err = r.getDisk(vmDisk.ID)
+		if err != nil {
+			return
+		}
+		name := SnapshotName(r.Context, vmDisk.ID, precopy)
+		var snapshot *libclient.Snapshot
+		snapshot, err = r.GetSnapshot(r.SnapshotID(libclient.ResourceGroupOf(disk.ID), name))
+		if err != nil {
+			err = liberr.Wrap(err, "disk", disk.ID, "snapshot", name)
+			return
+		}
+		if snapshot.Properties.ProvisioningState == libclient.ProvisioningFailed {
+			err = liberr.New(
+				"failed to create the disk snapshot.",
+				"vm",
+				vm.Name,
+				"disk",
+				disk.ID,
+				"snapshot",
+				name)
+			return
+		}
+		if !snapshot.Ready() {
+			return
+		}
+	}
+	ready = true
+	return
+}
+
+// Find the VM in the inventory.
+func (r *Client) getVM(vmRef ref.Ref) (vm *model.VM, err error) {
+	vm = &model.VM{}
+	err = r.Context.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+	}
+	return
+}
+
+// Get the (managed) disk by inventory ID.
+func (r *Client) getDisk(id string) (disk *libclient.Disk, err error) {
+	m := &model.Disk{}
+	err = r.Context.Source.Inventory.Find(m, ref.Ref{ID: id})
+	if err != nil {
+		err = liberr.Wrap(err, "disk", id)
+		return
+	}
+	disk, err = r.GetDisk(m.ResourceID)
+	if err != nil {
+		err = liberr.Wrap(err, "disk", m.ResourceID)
+	}
+	return
+}
+
+// The name of the snapshot of the disk taken by the precopy.
+func SnapshotName(ctx *plancontext.Context, diskID string, precopy int) string {
+	return fmt.Sprintf("%s-%s-%d", string(ctx.Migration.UID), diskID, precopy)
+}
+
+// The tags of the snapshot of the disk taken by the precopy.
+// The tags are used by the populator to find the snapshots.
+func SnapshotTags(ctx *plancontext.Context, diskID string, precopy int, final bool) (tags map[string]string) {
+	tags = map[string]string{
+		libclient.TagMigration: string(ctx.Migration.UID),
+		libclient.TagDisk:      diskID,
+		libclient.TagPrecopy:   strconv.Itoa(precopy),
+	}
+	if final {
+		tags[libclient.TagFinal] = "true"
+	}
+	return
+}
@@ -42,6 +42,11 @@ const (
 	// Add extra vddk configmap, in the Forklift used to pass AIO configuration to the VDDK.
 	// Related to https://github.com/kubevirt/containerized-data-importer/pull/3572
 	AnnVddkExtraArgs = "cdi.kubevirt.io/storage.pod.vddk.extraargs"
+
+	// The disk is excluded from snapshots and cannot be copied incrementally.
+	// In warm migrations, the DataVolume is created at cutover once the
+	// source VM is powered off.
+	AnnColdCopy = "forklift.konveyor.io/cold-copy"
 )
 
 var VolumePopulatorNotSupportedError = liberr.New("provider does not support volume populators")
@@ -98,6 +103,45 @@ type Builder interface {
 	GetPopulatorTaskName(pvc *core.PersistentVolumeClaim) (taskName string, err error)
 	// Get the virtual machine preference name
 	PreferenceName(vmRef ref.Ref, configMap *core.ConfigMap) (name string, err error)
+	// Build the cloud-init data carried over from the source VM.
+	// Nil when the source VM has no first boot customization.
+	CloudInit(vmRef ref.Ref) (cloudInit *CloudInit, err error)
+	// Get the source VM attributes (name: value) that may be
+	// mapped to the labels and annotations of the migrated VM.
+	Attributes(vmRef ref.Ref) (attributes map[string]string, err error)
+	// Get the provenance of the source VM annotated on the
+	// migrated VM. Nil when not known by the provider.
+	Provenance(vmRef ref.Ref) (provenance *Provenance, err error)
+	// Get the ISO media inserted in the source VM CD-ROMs.
+	// Nil when copying the media is not supported by the provider.
+	CDROMs(vmRef ref.Ref) (media []CDROM, err error)
+}
+
+// Cloud-init (NoCloud) data of the first boot customization.
+type CloudInit struct {
+	// User data.
+	UserData string
+	// Network configuration.
+	NetworkData string
+}
+
+// Provenance of the source VM.
+type Provenance struct {
+	// Description (notes).
+	Description string
+	// Inventory path (e.g. the VM folder path).
+	Path string
+	// Inventory path of the cluster.
+	Cluster string
+}
+
+// ISO media of a source VM CD-ROM.
+type CDROM struct {
+	// ISO file.
+	File string
+	// URL serving the ISO file (HTTPS), authenticated
+	// with the source provider credentials.
+	URL string
 }
 
 // Client API.
@@ -154,6 +198,49 @@ type Validator interface {
 	SharedDisks(vmRef ref.Ref, client client.Client) (ok bool, msg string, category string, err error)
 	// Validate that the vm has the change tracking enabled
 	ChangeTrackingEnabled(vmRef ref.Ref) (bool, error)
+	// Return the CPU model and the required CPU features the target VM
+	// will be created with, empty when the cluster default is used.
+	CPUModel(vmRef ref.Ref) (cpuModel string, features []string, err error)
+	// Return the identifiers (e.g. guest ID and name) of the VM guest OS.
+	GuestOS(vmRef ref.Ref) (ids []string, err error)
+	// Return the VM NICs with the destination networks they are mapped to.
+	NICs(vmRef ref.Ref) (nics []NIC, err error)
+	// Determine whether the target VM will be created with a persistent TPM device.
+	PersistentTPM(vmRef ref.Ref) (persistent bool, err error)
+	// Return the profiles of the VM passthrough (GPU and PCI) devices.
+	PassthroughDevices(vmRef ref.Ref) (profiles []string, err error)
+	// Return the VM RDM (raw device mapping) disks that cannot be
+	// migrated as set by the plan (skipped or mapped to PVs).
+	RDMDisks(vmRef ref.Ref, client client.Client) (unmapped []string, err error)
+	// Return the VM independent disks, which are excluded from
+	// snapshots and copied at cutover in warm migrations.
+	IndependentDisks(vmRef ref.Ref) (disks []string, err error)
+	// Return the devices in the source boot order that are
+	// not migrated (e.g. CD-ROM) when the boot order is preserved.
+	UnmigratedBootDevices(vmRef ref.Ref) (devices []string, err error)
+	// Return the affinity rules of the source cluster covering the VM.
+	AffinityRules(vmRef ref.Ref) (rules []util.AffinityRule, err error)
+	// Return the destination resources requested by the VM.
+	// Nil when not known.
+	Demand(vmRef ref.Ref) (demand *Demand, err error)
+}
+
+// Destination resources requested by the VM.
+type Demand struct {
+	// Number of vCPUs.
+	CPU int64
+	// Memory (bytes).
+	Memory int64
+	// Disk capacity (bytes) by destination storage class.
+	Storage map[string]int64
+}
+
+// Source VM NIC.
+type NIC struct {
+	// MAC address.
+	MAC string
+	// Mapped destination network.
+	Destination api.DestinationNetwork
 }
 
 // DestinationClient API.
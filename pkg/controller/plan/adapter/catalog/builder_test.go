@@ -0,0 +1,104 @@
+package catalog
+
+import (
+	"testing"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planbase "github.com/kubev2v/forklift/pkg/controller/plan/adapter/base"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/web/catalog"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cnv "kubevirt.io/api/core/v1"
+)
+
+func TestShapeOf(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	provider := &api.Provider{}
+	shape := ShapeOf(provider)
+	g.Expect(shape.CPU).To(gomega.Equal(int64(DefaultCPU)))
+	g.Expect(shape.Memory).To(gomega.Equal(int64(2 << 30)))
+	g.Expect(shape.Firmware).To(gomega.Equal(BIOS))
+	provider.Spec.Settings = map[string]string{
+		api.CPU:      "4",
+		api.Memory:   "8Gi",
+		api.Firmware: EFI,
+	}
+	shape = ShapeOf(provider)
+	g.Expect(shape.CPU).To(gomega.Equal(int64(4)))
+	g.Expect(shape.Memory).To(gomega.Equal(int64(8 << 30)))
+	g.Expect(shape.Firmware).To(gomega.Equal(EFI))
+	// Malformed settings are ignored.
+	provider.Spec.Settings = map[string]string{
+		api.CPU:    "0",
+		api.Memory: "lots",
+	}
+	shape = ShapeOf(provider)
+	g.Expect(shape.CPU).To(gomega.Equal(int64(DefaultCPU)))
+	g.Expect(shape.Memory).To(gomega.Equal(int64(2 << 30)))
+}
+
+func TestMapDisks(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	builder := &Builder{}
+	vm := &model.VM{}
+	vm.ID = "vm-1"
+	pvc := func(id string) *core.PersistentVolumeClaim {
+		return &core.PersistentVolumeClaim{
+			ObjectMeta: meta.ObjectMeta{
+				Name:        "pvc-" + id,
+				Annotations: map[string]string{planbase.AnnDiskSource: id},
+			},
+		}
+	}
+	object := &cnv.VirtualMachineSpec{Template: &cnv.VirtualMachineInstanceTemplateSpec{}}
+	builder.mapDisks(vm, []*core.PersistentVolumeClaim{pvc("vm-2"), pvc("vm-1")}, object)
+
+	volumes := object.Template.Spec.Volumes
+	disks := object.Template.Spec.Domain.Devices.Disks
+	g.Expect(volumes).To(gomega.HaveLen(1))
+	g.Expect(volumes[0].PersistentVolumeClaim.ClaimName).To(gomega.Equal("pvc-vm-1"))
+	g.Expect(disks[0].Disk.Bus).To(gomega.Equal(cnv.DiskBus(Virtio)))
+	g.Expect(*disks[0].BootOrder).To(gomega.Equal(uint(1)))
+}
+
+func TestMapFirmware(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	builder := &Builder{}
+	vm := &model.VM{}
+	vm.ID = "vm-1"
+	for _, firmware := range []string{BIOS, EFI} {
+		object := &cnv.VirtualMachineSpec{Template: &cnv.VirtualMachineInstanceTemplateSpec{}}
+		builder.mapFirmware(vm, Shape{Firmware: firmware}, object)
+		mapped := object.Template.Spec.Domain.Firmware
+		g.Expect(mapped.Serial).To(gomega.Equal(vm.ID))
+		if firmware == EFI {
+			g.Expect(mapped.Bootloader.EFI).ToNot(gomega.BeNil())
+			g.Expect(*mapped.Bootloader.EFI.SecureBoot).To(gomega.BeFalse())
+		} else {
+			g.Expect(mapped.Bootloader.BIOS).ToNot(gomega.BeNil())
+		}
+	}
+}
+
+func TestShapeOfVM(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	provider := &api.Provider{}
+	provider.Spec.Settings = map[string]string{
+		api.CPU:    "4",
+		api.Memory: "8Gi",
+	}
+	vm := &model.VM{}
+	shape := ShapeOfVM(provider, vm)
+	g.Expect(shape.CPU).To(gomega.Equal(int64(4)))
+	g.Expect(shape.Memory).To(gomega.Equal(int64(8 << 30)))
+	g.Expect(shape.Firmware).To(gomega.Equal(BIOS))
+	// The hints take precedence.
+	vm.CPU = 2
+	vm.Memory = 1 << 30
+	vm.Firmware = EFI
+	shape = ShapeOfVM(provider, vm)
+	g.Expect(shape.CPU).To(gomega.Equal(int64(2)))
+	g.Expect(shape.Memory).To(gomega.Equal(int64(1 << 30)))
+	g.Expect(shape.Firmware).To(gomega.Equal(EFI))
+}
@@ -2,7 +2,13 @@ package adapter
 
 import (
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/controller/plan/adapter/azure"
 	"github.com/kubev2v/forklift/pkg/controller/plan/adapter/base"
+	"github.com/kubev2v/forklift/pkg/controller/plan/adapter/catalog"
+	"github.com/kubev2v/forklift/pkg/controller/plan/adapter/ec2"
+	"github.com/kubev2v/forklift/pkg/controller/plan/adapter/image"
+	"github.com/kubev2v/forklift/pkg/controller/plan/adapter/libvirt"
+	"github.com/kubev2v/forklift/pkg/controller/plan/adapter/nutanix"
 	"github.com/kubev2v/forklift/pkg/controller/plan/adapter/ocp"
 	"github.com/kubev2v/forklift/pkg/controller/plan/adapter/openstack"
 	"github.com/kubev2v/forklift/pkg/controller/plan/adapter/ova"
@@ -31,6 +37,18 @@ func New(provider *api.Provider) (adapter Adapter, err error) {
 		adapter = &ocp.Adapter{}
 	case api.Ova:
 		adapter = &ova.Adapter{}
+	case api.Nutanix:
+		adapter = &nutanix.Adapter{}
+	case api.EC2:
+		adapter = &ec2.Adapter{}
+	case api.Azure:
+		adapter = &azure.Adapter{}
+	case api.Libvirt:
+		adapter = &libvirt.Adapter{}
+	case api.Image:
+		adapter = &image.Adapter{}
+	case api.Catalog:
+		adapter = &catalog.Adapter{}
 	default:
 		err = liberr.New("provider not supported.")
 	}
@@ -0,0 +1,778 @@
+package ec2
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	planbase "github.com/kubev2v/forklift/pkg/controller/plan/adapter/base"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	utils "github.com/kubev2v/forklift/pkg/controller/plan/util"
+	ec2 "github.com/kubev2v/forklift/pkg/controller/provider/model/ec2"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/web/ec2"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/ocp"
+	libclient "github.com/kubev2v/forklift/pkg/lib/client/ec2"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	libitr "github.com/kubev2v/forklift/pkg/lib/itinerary"
+	libutil "github.com/kubev2v/forklift/pkg/lib/util"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	cnv "kubevirt.io/api/core/v1"
+	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Boot modes.
+const (
+	LegacyBIOS = "legacy-bios"
+	UEFI       = "uefi"
+)
+
+// Bus types
+const (
+	Virtio = "virtio"
+)
+
+// Input types
+const (
+	Tablet = "tablet"
+)
+
+// Network types
+const (
+	Pod     = "pod"
+	Multus  = "multus"
+	SRIOV   = "sriov"
+	UDN     = "udn"
+	Ignored = "ignored"
+)
+
+// Template labels
+const (
+	TemplateOSLabel       = "os.template.kubevirt.io/%s"
+	TemplateWorkloadLabel = "workload.template.kubevirt.io/server"
+	TemplateFlavorLabel   = "flavor.template.kubevirt.io/medium"
+)
+
+// Operating Systems
+const (
+	Unknown = "unknown"
+)
+
+// Populator CR and PVC labels.
+const (
+	kVM         = "vmID"
+	kMigration  = "migration"
+	kSnapshotID = "snapshotID"
+)
+
+// EC2 builder.
+type Builder struct {
+	*plancontext.Context
+	// EC2 client.
+	client *libclient.Client
+	// MAC addresses already in use on the destination cluster. k=mac, v=vmName
+	macConflictsMap map[string]string
+}
+
+// Get list of destination VMs with mac addresses that would
+// conflict with this VM, if any exist.
+func (r *Builder) macConflicts(vm *model.VM) (conflictingVMs []string, err error) {
+	if r.macConflictsMap == nil {
+		list := []ocp.VM{}
+		err = r.Destination.Inventory.List(&list, base.Param{
+			Key:   base.DetailParam,
+			Value: "all",
+		})
+		if err != nil {
+			return
+		}
+
+		r.macConflictsMap = make(map[string]string)
+		for _, kVM := range list {
+			for _, iface := range kVM.Object.Spec.Template.Spec.Domain.Devices.Interfaces {
+				r.macConflictsMap[iface.MacAddress] = path.Join(kVM.Namespace, kVM.Name)
+			}
+		}
+	}
+
+	for _, nic := range vm.NICs {
+		if conflictingVm, found := r.macConflictsMap[nic.MAC]; found {
+			conflictingVMs = append(conflictingVMs, conflictingVm)
+		}
+	}
+
+	return
+}
+
+// Build the DataVolume certificate configmap.
+// No-op; the volumes are populated.
+func (r *Builder) ConfigMap(_ ref.Ref, _ *core.Secret, _ *core.ConfigMap) (err error) {
+	return
+}
+
+func (r *Builder) PodEnvironment(_ ref.Ref, _ *core.Secret) (env []core.EnvVar, err error) {
+	return
+}
+
+// Build the populator secret.
+// No-op; the provider secret is cloned.
+func (r *Builder) Secret(_ ref.Ref, in, object *core.Secret) (err error) {
+	return
+}
+
+// Create DataVolume specs for the VM.
+// No-op; the volumes are populated from the snapshots
+// created by the client (pre-transfer actions).
+func (r *Builder) DataVolumes(vmRef ref.Ref, secret *core.Secret, configMap *core.ConfigMap, dvTemplate *cdi.DataVolume, vddkConfigMap *core.ConfigMap) (dvs []cdi.DataVolume, err error) {
+	return
+}
+
+// Create the destination Kubevirt VM.
+func (r *Builder) VirtualMachine(vmRef ref.Ref, object *cnv.VirtualMachineSpec, persistentVolumeClaims []*core.PersistentVolumeClaim, usesInstanceType bool, sortVolumesByLibvirt bool) (err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+
+	var conflicts []string
+	conflicts, err = r.macConflicts(vm)
+	if err != nil {
+		return
+	}
+	if len(conflicts) > 0 {
+		err = liberr.New(
+			fmt.Sprintf("Source VM has a mac address conflict with one or more destination VMs: %s", conflicts))
+		return
+	}
+
+	if object.Template == nil {
+		object.Template = &cnv.VirtualMachineInstanceTemplateSpec{}
+	}
+	r.mapDisks(vm, persistentVolumeClaims, object)
+	r.mapFirmware(vm, object)
+	r.mapInput(object)
+	r.mapTpm(vm, object)
+	if !usesInstanceType {
+		r.mapCPU(vm, object)
+		r.mapMemory(vm, object)
+	}
+	err = r.mapNetworks(vm, object)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+func (r *Builder) mapNetworks(vm *model.VM, object *cnv.VirtualMachineSpec) (err error) {
+	var kNetworks []cnv.Network
+	var kInterfaces []cnv.Interface
+
+	numNetworks := 0
+	subnets, err := r.mappedSubnets()
+	if err != nil {
+		return
+	}
+	vmRef := ref.Ref{ID: vm.ID, Name: vm.Name}
+	netMapIn := r.Context.Map.Network.Spec.Map
+	for i := range netMapIn {
+		mapped := &netMapIn[i]
+
+		// Skip network mappings with destination type 'Ignored'
+		if mapped.Destination.Type == Ignored {
+			continue
+		}
+
+		needed := []ec2.NIC{}
+		for index, nic := range vm.NICs {
+			if nic.Subnet != subnets[mapped] {
+				continue
+			}
+			// Skip NICs with a more specific mapping.
+			best := r.Context.Map.Network.FindNetworkForNIC(vmRef, index, func(candidate *api.NetworkPair) bool {
+				id, found := subnets[candidate]
+				return found && id == nic.Subnet
+			})
+			if best == mapped {
+				needed = append(needed, nic)
+			}
+		}
+		for _, nic := range needed {
+			networkName := fmt.Sprintf("net-%v", numNetworks)
+			numNetworks++
+			kNetwork := cnv.Network{
+				Name: networkName,
+			}
+			kInterface := cnv.Interface{
+				Name:       networkName,
+				Model:      Virtio,
+				MacAddress: nic.MAC,
+			}
+			switch mapped.Destination.Type {
+			case Pod:
+				kNetwork.Pod = &cnv.PodNetwork{}
+				kInterface.Masquerade = &cnv.InterfaceMasquerade{}
+			case Multus, UDN:
+				kNetwork.Multus = &cnv.MultusNetwork{
+					NetworkName: path.Join(mapped.Destination.Namespace, mapped.Destination.Name),
+				}
+				kInterface.Bridge = &cnv.InterfaceBridge{}
+			case SRIOV:
+				kNetwork.Multus = &cnv.MultusNetwork{
+					NetworkName: path.Join(mapped.Destination.Namespace, mapped.Destination.Name),
+				}
+				kInterface.SRIOV = &cnv.InterfaceSRIOV{}
+			}
+			kNetworks = append(kNetworks, kNetwork)
+			kInterfaces = append(kInterfaces, kInterface)
+		}
+	}
+	object.Template.Spec.Networks = kNetworks
+	object.Template.Spec.Domain.Devices.Interfaces = kInterfaces
+	return
+}
+
+// Resolve the source subnet (ID) of each network mapping.
+func (r *Builder) mappedSubnets() (subnets map[*api.NetworkPair]string, err error) {
+	subnets = make(map[*api.NetworkPair]string)
+	netMapIn := r.Context.Map.Network.Spec.Map
+	for i := range netMapIn {
+		mapped := &netMapIn[i]
+		subnet := &model.Subnet{}
+		fErr := r.Source.Inventory.Find(subnet, mapped.Source)
+		if fErr != nil {
+			if mapped.Destination.Type == Ignored {
+				continue
+			}
+			err = fErr
+			return
+		}
+		subnets[mapped] = subnet.ID
+	}
+	return
+}
+
+func (r *Builder) mapInput(object *cnv.VirtualMachineSpec) {
+	tablet := cnv.Input{
+		Type: Tablet,
+		Name: Tablet,
+		Bus:  Virtio,
+	}
+	object.Template.Spec.Domain.Devices.Inputs = []cnv.Input{tablet}
+}
+
+func (r *Builder) mapMemory(vm *model.VM, object *cnv.VirtualMachineSpec) {
+	reservation := resource.NewQuantity(vm.MemoryMB*(1<<20), resource.BinarySI)
+	object.Template.Spec.Domain.Memory = &cnv.Memory{Guest: reservation}
+}
+
+// The instance cores are mapped to a single socket.
+func (r *Builder) mapCPU(vm *model.VM, object *cnv.VirtualMachineSpec) {
+	cores := vm.CoresPerSocket
+	threads := vm.ThreadsPerCore
+	if cores == 0 {
+		cores = vm.VCPUs
+		threads = 1
+	}
+	object.Template.Spec.Domain.CPU = &cnv.CPU{
+		Sockets: 1,
+		Cores:   uint32(max(cores, 1)),
+		Threads: uint32(max(threads, 1)),
+	}
+}
+
+func (r *Builder) mapFirmware(vm *model.VM, object *cnv.VirtualMachineSpec) {
+	firmware := &cnv.Firmware{
+		Serial: vm.ID,
+	}
+	switch vm.BootMode {
+	case UEFI:
+		// The secure boot is disabled; the UEFI variables
+		// of the instance are not migrated.
+		firmware.Bootloader = &cnv.Bootloader{
+			EFI: &cnv.EFI{
+				SecureBoot: ptr.To(false),
+			}}
+	default:
+		firmware.Bootloader = &cnv.Bootloader{BIOS: &cnv.BIOS{}}
+	}
+	object.Template.Spec.Domain.Firmware = firmware
+}
+
+func (r *Builder) mapTpm(vm *model.VM, object *cnv.VirtualMachineSpec) {
+	if vm.TPM {
+		persistData := true
+		object.Template.Spec.Domain.Devices.TPM = &cnv.TPMDevice{Persistent: &persistData}
+	}
+}
+
+// Map the disks (root device first, then by device name).
+// The first disk is the boot disk.
+func (r *Builder) mapDisks(vm *model.VM, persistentVolumeClaims []*core.PersistentVolumeClaim, object *cnv.VirtualMachineSpec) {
+	var kVolumes []cnv.Volume
+	var kDisks []cnv.Disk
+
+	pvcMap := make(map[string]*core.PersistentVolumeClaim)
+	for i := range persistentVolumeClaims {
+		pvc := persistentVolumeClaims[i]
+		if source, ok := pvc.Annotations[planbase.AnnDiskSource]; ok {
+			pvcMap[source] = pvc
+		}
+	}
+	disks := append([]ec2.Disk{}, vm.Disks...)
+	sort.SliceStable(disks, func(i, j int) bool {
+		if disks[i].Root != disks[j].Root {
+			return disks[i].Root
+		}
+		return disks[i].Device < disks[j].Device
+	})
+	for i, disk := range disks {
+		pvc, found := pvcMap[disk.ID]
+		if !found {
+			continue
+		}
+		volumeName := fmt.Sprintf("vol-%v", i)
+		volume := cnv.Volume{
+			Name: volumeName,
+			VolumeSource: cnv.VolumeSource{
+				PersistentVolumeClaim: &cnv.PersistentVolumeClaimVolumeSource{
+					PersistentVolumeClaimVolumeSource: core.PersistentVolumeClaimVolumeSource{
+						ClaimName: pvc.Name,
+					},
+				},
+			},
+		}
+		kubevirtDisk := cnv.Disk{
+			Name: volumeName,
+			DiskDevice: cnv.DiskDevice{
+				Disk: &cnv.DiskTarget{
+					Bus: Virtio,
+				},
+			},
+		}
+		if i == 0 {
+			var bootOrder uint = 1
+			kubevirtDisk.BootOrder = &bootOrder
+		}
+		kVolumes = append(kVolumes, volume)
+		kDisks = append(kDisks, kubevirtDisk)
+	}
+	object.Template.Spec.Volumes = kVolumes
+	object.Template.Spec.Domain.Devices.Disks = kDisks
+}
+
+// Build tasks.
+// A task for each volume.
+func (r *Builder) Tasks(vmRef ref.Ref) (list []*plan.Task, err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	for _, disk := range vm.Disks {
+		mB := disk.Size * 1024
+		list = append(
+			list,
+			&plan.Task{
+				Name: disk.ID,
+				Progress: libitr.Progress{
+					Total: mB,
+				},
+				Annotations: map[string]string{
+					"unit": "MB",
+				},
+			})
+	}
+
+	return
+}
+
+func (r *Builder) PreferenceName(vmRef ref.Ref, configMap *core.ConfigMap) (name string, err error) {
+	// The guest OS is not reported by EC2 so we cannot get the corresponding preference.
+	err = liberr.New("preferences are not used by this provider")
+	return
+}
+
+// Build the cloud-init data.
+// The instance user data is not migrated.
+func (r *Builder) CloudInit(vmRef ref.Ref) (cloudInit *planbase.CloudInit, err error) {
+	return
+}
+
+// Get the ISO media of the source VM CD-ROMs.
+// Not supported by the provider.
+func (r *Builder) CDROMs(vmRef ref.Ref) (media []planbase.CDROM, err error) {
+	return
+}
+
+// Get the provenance of the source VM.
+// Not supported by the provider.
+func (r *Builder) Provenance(vmRef ref.Ref) (provenance *planbase.Provenance, err error) {
+	return
+}
+
+// Get the source VM attributes.
+// Not supported by the provider.
+func (r *Builder) Attributes(vmRef ref.Ref) (attributes map[string]string, err error) {
+	return
+}
+
+func (r *Builder) TemplateLabels(vmRef ref.Ref) (labels map[string]string, err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+
+	os := Unknown
+
+	labels = make(map[string]string)
+	labels[fmt.Sprintf(TemplateOSLabel, os)] = "true"
+	labels[TemplateWorkloadLabel] = "true"
+	labels[TemplateFlavorLabel] = "true"
+
+	return
+}
+
+// Return a stable identifier for a DataVolume.
+func (r *Builder) ResolveDataVolumeIdentifier(dv *cdi.DataVolume) string {
+	return dv.ObjectMeta.Annotations[planbase.AnnDiskSource]
+}
+
+// Return a stable identifier for a PersistentDataVolume.
+func (r *Builder) ResolvePersistentVolumeClaimIdentifier(pvc *core.PersistentVolumeClaim) string {
+	return pvc.Annotations[planbase.AnnDiskSource]
+}
+
+// Build LUN PVs.
+func (r *Builder) LunPersistentVolumes(vmRef ref.Ref) (pvs []core.PersistentVolume, err error) {
+	// do nothing
+	return
+}
+
+// Build LUN PVCs.
+func (r *Builder) LunPersistentVolumeClaims(vmRef ref.Ref) (pvcs []core.PersistentVolumeClaim, err error) {
+	// do nothing
+	return
+}
+
+func (r *Builder) SupportsVolumePopulators() bool {
+	return true
+}
+
+// Create the populator CRs and PVCs of the volumes.
+// Only the volumes with completed snapshots are populated.
+func (r *Builder) PopulatorVolumes(vmRef ref.Ref, annotations map[string]string, secretName string) (pvcs []*core.PersistentVolumeClaim, err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	client, err := r.getClient()
+	if err != nil {
+		return
+	}
+	for _, disk := range vm.Disks {
+		tag := SnapshotTag(r.Context, disk.ID)
+		var snapshot *libclient.Snapshot
+		snapshot, err = FindSnapshot(client, tag)
+		if err != nil {
+			err = liberr.Wrap(err, "volume", disk.ID)
+			return
+		}
+		if snapshot == nil || snapshot.State != libclient.SnapshotCompleted {
+			r.Log.Info("the volume snapshot is not ready yet", "volume", disk.ID)
+			continue
+		}
+		var populatorCR *api.EC2VolumePopulator
+		populatorCR, err = r.ensureVolumePopulator(vm, snapshot.ID, secretName)
+		if err != nil {
+			return
+		}
+		var pvc *core.PersistentVolumeClaim
+		pvc, err = r.ensureVolumePopulatorPVC(vm, disk, snapshot.ID, annotations, populatorCR.Name)
+		if err != nil {
+			return
+		}
+		pvcs = append(pvcs, pvc)
+	}
+	return
+}
+
+func (r *Builder) ensureVolumePopulator(vm *model.VM, snapshotID, secretName string) (populatorCR *api.EC2VolumePopulator, err error) {
+	volumePopulatorCR, err := r.getVolumePopulatorCR(snapshotID)
+	if err != nil {
+		if !k8serr.IsNotFound(err) {
+			err = liberr.Wrap(err)
+			return
+		}
+		return r.createVolumePopulatorCR(vm.ID, snapshotID, secretName)
+	}
+	populatorCR = &volumePopulatorCR
+	return
+}
+
+func (r *Builder) createVolumePopulatorCR(vmID, snapshotID, secretName string) (populatorCR *api.EC2VolumePopulator, err error) {
+	populatorCR = &api.EC2VolumePopulator{
+		ObjectMeta: meta.ObjectMeta{
+			GenerateName: fmt.Sprintf("%s-", snapshotID),
+			Namespace:    r.Plan.Spec.TargetNamespace,
+			Labels: map[string]string{
+				kVM:         vmID,
+				kMigration:  string(r.Migration.UID),
+				kSnapshotID: snapshotID,
+			},
+		},
+		Spec: api.EC2VolumePopulatorSpec{
+			EndpointURL:     r.Source.Provider.Spec.URL,
+			SecretName:      secretName,
+			SnapshotID:      snapshotID,
+			TransferNetwork: r.Plan.Spec.TransferNetwork,
+		},
+	}
+	err = r.Context.Client.Create(context.TODO(), populatorCR, &client.CreateOptions{})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	return
+}
+
+// Get the EC2VolumePopulator CustomResource based on the snapshot ID.
+func (r *Builder) getVolumePopulatorCR(snapshotID string) (populatorCr api.EC2VolumePopulator, err error) {
+	populatorCrList := &api.EC2VolumePopulatorList{}
+	err = r.Destination.Client.List(context.TODO(), populatorCrList, &client.ListOptions{
+		Namespace: r.Plan.Spec.TargetNamespace,
+		LabelSelector: labels.SelectorFromSet(map[string]string{
+			kMigration:  string(r.Migration.UID),
+			kSnapshotID: snapshotID,
+		}),
+	})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if len(populatorCrList.Items) == 0 {
+		err = k8serr.NewNotFound(api.SchemeGroupVersion.WithResource("EC2VolumePopulator").GroupResource(), snapshotID)
+		return
+	}
+	if len(populatorCrList.Items) > 1 {
+		err = liberr.New("multiple EC2VolumePopulator CRs found for snapshot", "snapshotID", snapshotID)
+		return
+	}
+
+	populatorCr = populatorCrList.Items[0]
+
+	return
+}
+
+func (r *Builder) ensureVolumePopulatorPVC(vm *model.VM, disk ec2.Disk, snapshotID string, annotations map[string]string, populatorName string) (pvc *core.PersistentVolumeClaim, err error) {
+	pvcList := &core.PersistentVolumeClaimList{}
+	err = r.Destination.Client.List(context.TODO(), pvcList, &client.ListOptions{
+		Namespace: r.Plan.Spec.TargetNamespace,
+		LabelSelector: labels.SelectorFromSet(map[string]string{
+			kMigration:  string(r.Migration.UID),
+			kSnapshotID: snapshotID,
+		}),
+	})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if len(pvcList.Items) > 0 {
+		pvc = &pvcList.Items[0]
+		return
+	}
+	mapped, found := r.Context.Map.Storage.FindStorage(disk.VolumeType)
+	if !found {
+		mapped, found = r.Context.Map.Storage.FindStorageByName(disk.VolumeType)
+	}
+	if !found || mapped.Destination.StorageClass == "" {
+		err = liberr.New("no storage class map found for volume type", "volumeType", disk.VolumeType)
+		return
+	}
+	pvc, err = r.persistentVolumeClaimWithSourceRef(vm, disk, snapshotID, mapped.Destination, annotations, populatorName)
+	return
+}
+
+func (r *Builder) persistentVolumeClaimWithSourceRef(
+	vm *model.VM,
+	disk ec2.Disk,
+	snapshotID string,
+	destination api.DestinationStorage,
+	annotations map[string]string,
+	populatorName string) (pvc *core.PersistentVolumeClaim, err error) {
+
+	apiGroup := "forklift.konveyor.io"
+	storageClassName := destination.StorageClass
+
+	accessModes, volumeMode, err := r.getVolumeAndAccessMode(storageClassName)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	// The storage map takes precedence over the storage profile defaults.
+	if pinned := destination.GetAccessModes(); len(pinned) > 0 {
+		accessModes = pinned
+	}
+	if destination.VolumeMode != "" {
+		volumeMode = &destination.VolumeMode
+	}
+	sizing, err := utils.GetStorageClassSizing(r.Destination.Client, storageClassName)
+	if err != nil {
+		return
+	}
+	size := sizing.VolumeSize(disk.Size*(1<<30), utils.FormatRaw, volumeMode)
+
+	pvcAnnotations := make(map[string]string)
+	for k, v := range annotations {
+		pvcAnnotations[k] = v
+	}
+	pvcAnnotations[planbase.AnnDiskSource] = disk.ID
+	pvcAnnotations = destination.Annotate(pvcAnnotations)
+
+	pvc = &core.PersistentVolumeClaim{
+		ObjectMeta: meta.ObjectMeta{
+			GenerateName: fmt.Sprintf("%s-", disk.ID),
+			Namespace:    r.Plan.Spec.TargetNamespace,
+			Annotations:  pvcAnnotations,
+			Labels: map[string]string{
+				kMigration:  string(r.Migration.UID),
+				kSnapshotID: snapshotID,
+				kVM:         vm.ID,
+			},
+		},
+		Spec: core.PersistentVolumeClaimSpec{
+			AccessModes: accessModes,
+			Resources: core.VolumeResourceRequirements{
+				Requests: map[core.ResourceName]resource.Quantity{
+					core.ResourceStorage: *resource.NewQuantity(size, resource.BinarySI)},
+			},
+			StorageClassName: &storageClassName,
+			VolumeMode:       volumeMode,
+			DataSourceRef: &core.TypedObjectReference{
+				APIGroup: &apiGroup,
+				Kind:     api.EC2VolumePopulatorKind,
+				Name:     populatorName,
+			},
+		},
+	}
+
+	err = r.Client.Create(context.TODO(), pvc, &client.CreateOptions{})
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+	return
+}
+
+// Using CDI logic to set the Volume mode and Access mode of the PVC - https://github.com/kubevirt/containerized-data-importer/blob/v1.56.0/pkg/controller/datavolume/util.go#L154
+func (r *Builder) getVolumeAndAccessMode(storageClassName string) ([]core.PersistentVolumeAccessMode, *core.PersistentVolumeMode, error) {
+	filesystemMode := core.PersistentVolumeFilesystem
+	storageProfile := &cdi.StorageProfile{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: storageClassName}, storageProfile)
+	if err != nil {
+		return nil, nil, liberr.Wrap(err, "storageClassName", storageClassName)
+	}
+
+	if len(storageProfile.Status.ClaimPropertySets) > 0 &&
+		len(storageProfile.Status.ClaimPropertySets[0].AccessModes) > 0 {
+		accessModes := storageProfile.Status.ClaimPropertySets[0].AccessModes
+		volumeMode := storageProfile.Status.ClaimPropertySets[0].VolumeMode
+		if volumeMode == nil {
+			// volumeMode is an optional API parameter. Filesystem is the default mode used when volumeMode parameter is omitted.
+			volumeMode = &filesystemMode
+		}
+		return accessModes, volumeMode, nil
+	}
+
+	// no accessMode configured on storageProfile
+	return nil, nil, liberr.New("no accessMode defined on StorageProfile for StorageClass", "storageClassName", storageClassName)
+}
+
+// The bytes transferred, based on the progress (percent) reported by the populator.
+func (r *Builder) PopulatorTransferredBytes(persistentVolumeClaim *core.PersistentVolumeClaim) (transferredBytes int64, err error) {
+	populatorCr, err := r.getVolumePopulatorCR(persistentVolumeClaim.Labels[kSnapshotID])
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	progressPercentage, err := strconv.ParseInt(populatorCr.Status.Progress, 10, 64)
+	if err != nil {
+		transferredBytes = 0
+		err = nil
+		//nolint:nilerr
+		return
+	}
+
+	pvcSize := persistentVolumeClaim.Spec.Resources.Requests["storage"]
+	transferredBytes = (progressPercentage * pvcSize.Value()) / 100
+	return
+}
+
+// Label the populator CRs with the VM and the active migration.
+func (r *Builder) SetPopulatorDataSourceLabels(vmRef ref.Ref, pvcs []*core.PersistentVolumeClaim) (err error) {
+	migrationID := string(r.Plan.Status.Migration.ActiveSnapshot().Migration.UID)
+	for _, pvc := range pvcs {
+		populatorCr, gErr := r.getVolumePopulatorCR(pvc.Labels[kSnapshotID])
+		if gErr != nil {
+			continue
+		}
+		populatorCrCopy := populatorCr.DeepCopy()
+		if populatorCr.Labels == nil {
+			populatorCr.Labels = make(map[string]string)
+		}
+		populatorCr.Labels[kVM] = vmRef.ID
+		populatorCr.Labels[kMigration] = migrationID
+		patch := client.MergeFrom(populatorCrCopy)
+		pErr := r.Destination.Client.Patch(context.TODO(), &populatorCr, patch)
+		if pErr != nil {
+			r.Log.Error(pErr, "Couldn't update the Populator Custom Resource labels.",
+				"vmRef", vmRef, "migration", migrationID, "EC2VolumePopulator", populatorCr.Name)
+			continue
+		}
+	}
+	return
+}
+
+// The task is named by the (source) volume ID.
+func (r *Builder) GetPopulatorTaskName(pvc *core.PersistentVolumeClaim) (taskName string, err error) {
+	taskName = pvc.Annotations[planbase.AnnDiskSource]
+	if taskName == "" {
+		err = liberr.New("the PVC has no source volume", "pvc", path.Join(pvc.Namespace, pvc.Name))
+	}
+	return
+}
+
+// Get the EC2 client.
+func (r *Builder) getClient() (client *libclient.Client, err error) {
+	if r.client != nil {
+		client = r.client
+		return
+	}
+	client = &libclient.Client{
+		URL:   r.Source.Provider.Spec.URL,
+		Log:   r.Log.WithName("client"),
+		Proxy: libutil.ProviderProxy(r.Source.Provider),
+		Dial:  libutil.ProviderDialer(r.Source.Provider, nil),
+	}
+	client.LoadOptionsFromSecret(r.Source.Secret)
+	r.client = client
+	return
+}
@@ -0,0 +1,84 @@
+package ec2
+
+import (
+	"testing"
+
+	planbase "github.com/kubev2v/forklift/pkg/controller/plan/adapter/base"
+	ec2 "github.com/kubev2v/forklift/pkg/controller/provider/model/ec2"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/web/ec2"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cnv "kubevirt.io/api/core/v1"
+)
+
+func TestMapDisks(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	builder := &Builder{}
+	vm := &model.VM{
+		Disks: []ec2.Disk{
+			{ID: "vol-2", Device: "/dev/sdc"},
+			{ID: "vol-1", Device: "/dev/sdb"},
+			{ID: "vol-0", Device: "/dev/xvda", Root: true},
+		},
+	}
+	pvc := func(id string) *core.PersistentVolumeClaim {
+		return &core.PersistentVolumeClaim{
+			ObjectMeta: meta.ObjectMeta{
+				Name:        "pvc-" + id,
+				Annotations: map[string]string{planbase.AnnDiskSource: id},
+			},
+		}
+	}
+	object := &cnv.VirtualMachineSpec{Template: &cnv.VirtualMachineInstanceTemplateSpec{}}
+	builder.mapDisks(vm, []*core.PersistentVolumeClaim{pvc("vol-1"), pvc("vol-2"), pvc("vol-0")}, object)
+
+	volumes := object.Template.Spec.Volumes
+	disks := object.Template.Spec.Domain.Devices.Disks
+	g.Expect(volumes).To(gomega.HaveLen(3))
+	g.Expect(volumes[0].PersistentVolumeClaim.ClaimName).To(gomega.Equal("pvc-vol-0"))
+	g.Expect(volumes[1].PersistentVolumeClaim.ClaimName).To(gomega.Equal("pvc-vol-1"))
+	g.Expect(volumes[2].PersistentVolumeClaim.ClaimName).To(gomega.Equal("pvc-vol-2"))
+	g.Expect(*disks[0].BootOrder).To(gomega.Equal(uint(1)))
+	g.Expect(disks[1].BootOrder).To(gomega.BeNil())
+}
+
+func TestMapFirmware(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	builder := &Builder{}
+	cases := map[string]bool{
+		LegacyBIOS: false,
+		"":         false,
+		UEFI:       true,
+	}
+	for bootMode, efi := range cases {
+		vm := &model.VM{}
+		vm.ID = "i-0123"
+		vm.BootMode = bootMode
+		object := &cnv.VirtualMachineSpec{Template: &cnv.VirtualMachineInstanceTemplateSpec{}}
+		builder.mapFirmware(vm, object)
+		firmware := object.Template.Spec.Domain.Firmware
+		g.Expect(firmware.Serial).To(gomega.Equal("i-0123"))
+		if efi {
+			g.Expect(firmware.Bootloader.EFI).ToNot(gomega.BeNil())
+			g.Expect(*firmware.Bootloader.EFI.SecureBoot).To(gomega.BeFalse())
+		} else {
+			g.Expect(firmware.Bootloader.BIOS).ToNot(gomega.BeNil())
+		}
+	}
+}
+
+func TestMapCPU(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	builder := &Builder{}
+	vm := &model.VM{}
+	vm.VCPUs = 4
+	vm.CoresPerSocket = 2
+	vm.ThreadsPerCore = 2
+	object := &cnv.VirtualMachineSpec{Template: &cnv.VirtualMachineInstanceTemplateSpec{}}
+	builder.mapCPU(vm, object)
+	cpu := object.Template.Spec.Domain.CPU
+	g.Expect(cpu.Sockets).To(gomega.Equal(uint32(1)))
+	g.Expect(cpu.Cores).To(gomega.Equal(uint32(2)))
+	g.Expect(cpu.Threads).To(gomega.Equal(uint32(2)))
+}
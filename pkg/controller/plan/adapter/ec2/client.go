@@ -0,0 +1,229 @@
+package ec2
+
+import (
+	"fmt"
+
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	"github.com/kubev2v/forklift/pkg/controller/plan/util"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/web/ec2"
+	libclient "github.com/kubev2v/forklift/pkg/lib/client/ec2"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	libutil "github.com/kubev2v/forklift/pkg/lib/util"
+	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+)
+
+// EC2 VM Client.
+// The (EBS) volumes are snapshot and the snapshots
+// are read by the EC2 volume populator.
+type Client struct {
+	libclient.Client
+	Context *plancontext.Context
+}
+
+// Connect.
+func (r *Client) connect() (err error) {
+	r.URL = r.Context.Source.Provider.Spec.URL
+	r.Proxy = libutil.ProviderProxy(r.Context.Source.Provider)
+	r.Dial = libutil.ProviderDialer(r.Context.Source.Provider, nil)
+	r.LoadOptionsFromSecret(r.Context.Source.Secret)
+	err = r.Connect()
+	return
+}
+
+// Power on the source VM.
+func (r *Client) PowerOn(vmRef ref.Ref) (err error) {
+	err = r.StartInstance(vmRef.ID)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+	return
+}
+
+// Power off the source VM.
+func (r *Client) PowerOff(vmRef ref.Ref) (err error) {
+	err = r.StopInstance(vmRef.ID)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+	return
+}
+
+// Return the source VM's power state.
+func (r *Client) PowerState(vmRef ref.Ref) (state planapi.VMPowerState, err error) {
+	instance, err := r.GetInstance(vmRef.ID)
+	if err != nil {
+		err = liberr.Wrap(err)
+		state = planapi.VMPowerStateUnknown
+		return
+	}
+	switch instance.State.Name {
+	case libclient.InstanceRunning:
+		state = planapi.VMPowerStateOn
+	case libclient.InstanceStopped:
+		state = planapi.VMPowerStateOff
+	default:
+		state = planapi.VMPowerStateUnknown
+	}
+	return
+}
+
+// Return whether the source VM is powered off.
+func (r *Client) PoweredOff(vmRef ref.Ref) (off bool, err error) {
+	state, err := r.PowerState(vmRef)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	off = state == planapi.VMPowerStateOff
+	return
+}
+
+// Create a snapshot of the source VM.
+// No-op; warm migration is not supported.
+func (r *Client) CreateSnapshot(vmRef ref.Ref, hostsFunc util.HostsFunc) (snapshotId string, creationTaskId string, err error) {
+	return
+}
+
+// Remove a snapshot. No-op for this provider.
+func (r *Client) RemoveSnapshot(vmRef ref.Ref, snapshot string, hostsFunc util.HostsFunc) (removeTaskId string, err error) {
+	return
+}
+
+// Check if a snapshot is ready to transfer.
+func (r *Client) CheckSnapshotReady(vmRef ref.Ref, precopy planapi.Precopy, hosts util.HostsFunc) (ready bool, snapshotId string, err error) {
+	return
+}
+
+// CheckSnapshotRemove implements base.Client
+func (r *Client) CheckSnapshotRemove(vmRef ref.Ref, precopy planapi.Precopy, hosts util.HostsFunc) (bool, error) {
+	return false, nil
+}
+
+// Get disk deltas for a VM snapshot. No-op for this provider.
+func (r *Client) GetSnapshotDeltas(vmRef ref.Ref, snapshot string, hostsFunc util.HostsFunc) (s map[string]string, err error) {
+	return
+}
+
+// Set DataVolume checkpoints.
+func (r *Client) SetCheckpoints(vmRef ref.Ref, precopies []planapi.Precopy, datavolumes []cdi.DataVolume, final bool, hostsFunc util.HostsFunc) (err error) {
+	return
+}
+
+// Close connections to the provider API.
+func (r *Client) Close() {
+}
+
+// Detach disks. No-op for this provider.
+func (r *Client) DetachDisks(vmRef ref.Ref) (err error) {
+	return
+}
+
+// Snapshot the VM volumes.
+// Ready when all of the snapshots have been completed.
+func (r *Client) PreTransferActions(vmRef ref.Ref) (ready bool, err error) {
+	vm, err := r.getVM(vmRef)
+	if err != nil {
+		return
+	}
+	ready = true
+	for _, disk := range vm.Disks {
+		tag := SnapshotTag(r.Context, disk.ID)
+		var snapshot *libclient.Snapshot
+		snapshot, err = FindSnapshot(&r.Client, tag)
+		if err != nil {
+			err = liberr.Wrap(err, "volume", disk.ID)
+			return
+		}
+		if snapshot == nil {
+			var id string
+			id, err = r.Client.CreateSnapshot(disk.ID, tag)
+			if err != nil {
+				err = liberr.Wrap(err, "volume", disk.ID)
+				return
+			}
+			r.Log.Info(
+				"Creating the volume snapshot.",
+				"vm",
+				vm.Name,
+				"volume",
+				disk.ID,
+				"snapshot",
+				id)
+			ready = false
+			continue
+		}
+		switch snapshot.State {
+		case libclient.SnapshotCompleted:
+		case libclient.SnapshotError:
+			err = liberr.New(
+				"failed to create the volume snapshot.",
+				"vm",
+				vm.Name,
+				"volume",
+				disk.ID,
+				"snapshot",
+				snapshot.ID,
+				"message",
+				snapshot.Message)
+			return
+		default:
+			ready = false
+		}
+	}
+	return
+}
+
+// Delete the volume snapshots created for the migration.
+func (r *Client) Finalize(vms []*planapi.VMStatus, planName string) {
+	for _, vmStatus := range vms {
+		vm, err := r.getVM(vmStatus.Ref)
+		if err != nil {
+			r.Log.Error(err, "failed to find vm", "vm", vmStatus.Ref.String())
+			continue
+		}
+		for _, disk := range vm.Disks {
+			tag := SnapshotTag(r.Context, disk.ID)
+			snapshots, err := r.FindSnapshots(tag)
+			if err != nil {
+				r.Log.Error(err, "failed to find the volume snapshot", "vm", vm.Name, "volume", disk.ID)
+				continue
+			}
+			for _, snapshot := range snapshots {
+				err = r.DeleteSnapshot(snapshot.ID)
+				if err != nil {
+					r.Log.Error(err, "failed to delete the volume snapshot", "vm", vm.Name, "snapshot", snapshot.ID)
+				}
+			}
+		}
+	}
+}
+
+// Find the VM in the inventory.
+func (r *Client) getVM(vmRef ref.Ref) (vm *model.VM, err error) {
+	vm = &model.VM{}
+	err = r.Context.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+	}
+	return
+}
+
+// The tag of the snapshot of the volume created for the migration.
+func SnapshotTag(ctx *plancontext.Context, volumeID string) string {
+	return fmt.Sprintf("%s-%s", string(ctx.Migration.UID), volumeID)
+}
+
+// Find the snapshot with the tag.
+// Returns nil when not found.
+func FindSnapshot(client *libclient.Client, tag string) (snapshot *libclient.Snapshot, err error) {
+	list, err := client.FindSnapshots(tag)
+	if err != nil {
+		return
+	}
+	if len(list) > 0 {
+		snapshot = &list[0]
+	}
+	return
+}
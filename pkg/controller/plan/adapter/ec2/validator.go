@@ -0,0 +1,247 @@
+package ec2
+
+import (
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	planbase "github.com/kubev2v/forklift/pkg/controller/plan/adapter/base"
+	"github.com/kubev2v/forklift/pkg/controller/plan/util"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/web/ec2"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// EC2 validator.
+type Validator struct {
+	plan      *api.Plan
+	inventory web.Client
+}
+
+// Load.
+func (r *Validator) Load() (err error) {
+	r.inventory, err = web.NewClient(r.plan.Referenced.Provider.Source)
+	return
+}
+
+// Validate whether warm migration is supported from this provider type.
+func (r *Validator) WarmMigration() (ok bool) {
+	ok = false
+	return
+}
+
+// NOOP
+func (r *Validator) SharedDisks(vmRef ref.Ref, client client.Client) (ok bool, s string, s2 string, err error) {
+	ok = true
+	return
+}
+
+// Validate that a VM's networks have been mapped.
+func (r *Validator) NetworksMapped(vmRef ref.Ref) (ok bool, err error) {
+	if r.plan.Referenced.Map.Network == nil {
+		return
+	}
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+
+	for _, nic := range vm.NICs {
+		if !r.plan.Referenced.Map.Network.Status.Refs.Find(ref.Ref{ID: nic.Subnet}) {
+			return
+		}
+	}
+	ok = true
+	return
+}
+
+// Validate that no more than one of a VM's networks is mapped to the pod network.
+func (r *Validator) PodNetwork(vmRef ref.Ref) (ok bool, err error) {
+	if r.plan.Referenced.Map.Network == nil {
+		return
+	}
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+
+	podMapped := 0
+	for i, nic := range vm.NICs {
+		mapped, fErr := r.findNetworkMapping(vm, i, nic.Subnet)
+		if fErr != nil {
+			err = fErr
+			return
+		}
+		if mapped != nil && mapped.Destination.Type == Pod {
+			podMapped++
+		}
+	}
+
+	ok = podMapped <= 1
+	return
+}
+
+// Validate that a VM's disk backing storage has been mapped.
+func (r *Validator) StorageMapped(vmRef ref.Ref) (ok bool, err error) {
+	if r.plan.Referenced.Map.Storage == nil {
+		return
+	}
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+
+	for _, disk := range vm.Disks {
+		if !r.plan.Referenced.Map.Storage.Status.Refs.Find(ref.Ref{ID: disk.VolumeType}) {
+			return
+		}
+	}
+	ok = true
+	return
+}
+
+// NO-OP
+func (r *Validator) MaintenanceMode(vmRef ref.Ref) (ok bool, err error) {
+	ok = true
+	return
+}
+
+// NO-OP
+func (r *Validator) DirectStorage(vmRef ref.Ref) (bool, error) {
+	return true, nil
+}
+
+// NO-OP
+func (r *Validator) StaticIPs(vmRef ref.Ref) (bool, error) {
+	return true, nil
+}
+
+// NO-OP
+func (r *Validator) ChangeTrackingEnabled(vmRef ref.Ref) (bool, error) {
+	return true, nil
+}
+
+// NO-OP
+func (r *Validator) CPUModel(vmRef ref.Ref) (cpuModel string, features []string, err error) {
+	return
+}
+
+// NO-OP
+func (r *Validator) GuestOS(vmRef ref.Ref) (ids []string, err error) {
+	return
+}
+
+// Return the VM NICs with the destination networks they are mapped to.
+func (r *Validator) NICs(vmRef ref.Ref) (nics []planbase.NIC, err error) {
+	if r.plan.Referenced.Map.Network == nil {
+		return
+	}
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+
+	for i, nic := range vm.NICs {
+		mapped, fErr := r.findNetworkMapping(vm, i, nic.Subnet)
+		if fErr != nil {
+			err = fErr
+			return
+		}
+		if mapped != nil {
+			nics = append(nics, planbase.NIC{MAC: nic.MAC, Destination: mapped.Destination})
+		}
+	}
+	return
+}
+
+// Find the most specific network mapping of the VM NIC
+// attached to the subnet with the specified ID.
+func (r *Validator) findNetworkMapping(vm *model.VM, index int, subnetID string) (mapped *api.NetworkPair, err error) {
+	vmRef := ref.Ref{ID: vm.ID, Name: vm.Name}
+	mapped = r.plan.Referenced.Map.Network.FindNetworkForNIC(vmRef, index, func(candidate *api.NetworkPair) bool {
+		if err != nil {
+			return false
+		}
+		subnet := &model.Subnet{}
+		err = r.inventory.Find(subnet, candidate.Source)
+		if err != nil {
+			return false
+		}
+		return subnetID == subnet.ID
+	})
+	if err != nil {
+		mapped = nil
+	}
+	return
+}
+
+// Determine whether the target VM will be created with a persistent TPM device.
+// The TPM is mapped when supported by the source instance.
+func (r *Validator) PersistentTPM(vmRef ref.Ref) (persistent bool, err error) {
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	persistent = vm.TPM
+	return
+}
+
+// NO-OP
+func (r *Validator) PassthroughDevices(vmRef ref.Ref) (profiles []string, err error) {
+	return
+}
+
+// NO-OP
+func (r *Validator) RDMDisks(vmRef ref.Ref, client client.Client) (unmapped []string, err error) {
+	return
+}
+
+// NO-OP
+func (r *Validator) IndependentDisks(vmRef ref.Ref) (disks []string, err error) {
+	return
+}
+
+// NO-OP
+func (r *Validator) UnmigratedBootDevices(vmRef ref.Ref) (devices []string, err error) {
+	return
+}
+
+// NO-OP
+func (r *Validator) AffinityRules(vmRef ref.Ref) (rules []util.AffinityRule, err error) {
+	return
+}
+
+// Return the vCPU, memory and disk capacity (by mapped storage class) of the VM.
+func (r *Validator) Demand(vmRef ref.Ref) (demand *planbase.Demand, err error) {
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	demand = &planbase.Demand{
+		CPU:     int64(max(vm.VCPUs, 1)),
+		Memory:  vm.MemoryMB * (1 << 20),
+		Storage: map[string]int64{},
+	}
+	if r.plan.Referenced.Map.Storage == nil {
+		return
+	}
+	for _, disk := range vm.Disks {
+		mapped, found := r.plan.Referenced.Map.Storage.FindStorage(disk.VolumeType)
+		if !found || mapped.Destination.StorageClass == "" {
+			continue
+		}
+		demand.Storage[mapped.Destination.StorageClass] += disk.Size * (1 << 30)
+	}
+	return
+}
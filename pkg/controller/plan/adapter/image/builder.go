@@ -0,0 +1,474 @@
+package image
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	planbase "github.com/kubev2v/forklift/pkg/controller/plan/adapter/base"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	utils "github.com/kubev2v/forklift/pkg/controller/plan/util"
+	image "github.com/kubev2v/forklift/pkg/controller/provider/model/image"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/web/image"
+	libclient "github.com/kubev2v/forklift/pkg/lib/client/image"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	libitr "github.com/kubev2v/forklift/pkg/lib/itinerary"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+	cnv "kubevirt.io/api/core/v1"
+	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+)
+
+// Firmware types.
+const (
+	BIOS = "bios"
+	EFI  = "efi"
+)
+
+// Bus types
+const (
+	Virtio = "virtio"
+)
+
+// Input types
+const (
+	Tablet = "tablet"
+)
+
+// Network types
+const (
+	Pod     = "pod"
+	Multus  = "multus"
+	SRIOV   = "sriov"
+	UDN     = "udn"
+	Ignored = "ignored"
+)
+
+// Template labels
+const (
+	TemplateOSLabel       = "os.template.kubevirt.io/%s"
+	TemplateWorkloadLabel = "workload.template.kubevirt.io/server"
+	TemplateFlavorLabel   = "flavor.template.kubevirt.io/medium"
+)
+
+// Operating Systems
+const (
+	Unknown = "unknown"
+)
+
+// Shape used when not specified by the provider settings.
+const (
+	DefaultCPU    = 1
+	DefaultMemory = "2Gi"
+)
+
+// Shape of the VMs imported from the images.
+// Images have no VM configuration so the shape is
+// specified by the provider settings.
+type Shape struct {
+	// vCPU count.
+	CPU int64
+	// Memory (bytes).
+	Memory int64
+	// Firmware (bios|efi).
+	Firmware string
+}
+
+// The shape specified by the provider settings.
+// Malformed settings are reported by the provider
+// validation and the defaults are used.
+func ShapeOf(provider *api.Provider) (shape Shape) {
+	memory := resource.MustParse(DefaultMemory)
+	shape = Shape{
+		CPU:      DefaultCPU,
+		Memory:   memory.Value(),
+		Firmware: BIOS,
+	}
+	settings := provider.Spec.Settings
+	if n, err := strconv.ParseInt(settings[api.CPU], 10, 64); err == nil && n > 0 {
+		shape.CPU = n
+	}
+	if q, err := resource.ParseQuantity(settings[api.Memory]); err == nil && q.Sign() > 0 {
+		shape.Memory = q.Value()
+	}
+	if settings[api.Firmware] == EFI {
+		shape.Firmware = EFI
+	}
+	return
+}
+
+// Disk image builder.
+type Builder struct {
+	*plancontext.Context
+}
+
+// Build the DataVolume certificate configmap.
+func (r *Builder) ConfigMap(_ ref.Ref, in *core.Secret, object *core.ConfigMap) (err error) {
+	object.BinaryData["ca.pem"] = in.Data[libclient.CACert]
+	return
+}
+
+func (r *Builder) PodEnvironment(_ ref.Ref, _ *core.Secret) (env []core.EnvVar, err error) {
+	return
+}
+
+// Build the DataVolume credential secret.
+// The keys of the provider secret are used by CDI.
+func (r *Builder) Secret(_ ref.Ref, in, object *core.Secret) (err error) {
+	object.StringData = map[string]string{
+		libclient.AccessKeyID: string(in.Data[libclient.AccessKeyID]),
+		libclient.SecretKey:   string(in.Data[libclient.SecretKey]),
+	}
+	return
+}
+
+// Create DataVolume specs for the VM.
+// The image is imported (and converted) by CDI using the
+// http or (object storage) s3 source.
+func (r *Builder) DataVolumes(vmRef ref.Ref, secret *core.Secret, configMap *core.ConfigMap, dvTemplate *cdi.DataVolume, vddkConfigMap *core.ConfigMap) (dvs []cdi.DataVolume, err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	storage := &model.Storage{}
+	err = r.Source.Inventory.Find(storage, ref.Ref{ID: vm.Storage})
+	if err != nil {
+		err = liberr.Wrap(err, "storage", vm.Storage)
+		return
+	}
+	mapped, found := r.Context.Map.Storage.FindStorage(storage.ID)
+	if !found {
+		return
+	}
+	certConfigMap := ""
+	if configMap != nil && len(configMap.BinaryData["ca.pem"]) > 0 {
+		certConfigMap = configMap.Name
+	}
+	secretRef := ""
+	if len(r.Source.Secret.Data[libclient.AccessKeyID]) > 0 {
+		secretRef = secret.Name
+	}
+	source := &cdi.DataVolumeSource{}
+	if storage.ObjectStorage {
+		source.S3 = &cdi.DataVolumeSourceS3{
+			URL:           vm.URL,
+			SecretRef:     secretRef,
+			CertConfigMap: certConfigMap,
+		}
+	} else {
+		source.HTTP = &cdi.DataVolumeSourceHTTP{
+			URL:           vm.URL,
+			SecretRef:     secretRef,
+			CertConfigMap: certConfigMap,
+		}
+	}
+	storageClass := mapped.Destination.StorageClass
+	dvSpec := cdi.DataVolumeSpec{
+		Source: source,
+		Storage: &cdi.StorageSpec{
+			Resources: core.VolumeResourceRequirements{
+				Requests: core.ResourceList{
+					core.ResourceStorage: *resource.NewQuantity(utils.DataVolumeSize(vm.Capacity, utils.FormatRaw), resource.BinarySI),
+				},
+			},
+			StorageClassName: &storageClass,
+		},
+	}
+	// set the access mode and volume mode if they were specified in the storage map.
+	// otherwise, let the storage profile decide the default values.
+	if accessModes := mapped.Destination.GetAccessModes(); len(accessModes) > 0 {
+		dvSpec.Storage.AccessModes = accessModes
+	}
+	if mapped.Destination.VolumeMode != "" {
+		dvSpec.Storage.VolumeMode = &mapped.Destination.VolumeMode
+	}
+
+	dv := dvTemplate.DeepCopy()
+	dv.Spec = dvSpec
+	if dv.ObjectMeta.Annotations == nil {
+		dv.ObjectMeta.Annotations = make(map[string]string)
+	}
+	dv.ObjectMeta.Annotations[planbase.AnnDiskSource] = vm.ID
+	dv.ObjectMeta.Annotations = mapped.Destination.Annotate(dv.ObjectMeta.Annotations)
+	dvs = append(dvs, *dv)
+
+	return
+}
+
+// Create the destination Kubevirt VM.
+func (r *Builder) VirtualMachine(vmRef ref.Ref, object *cnv.VirtualMachineSpec, persistentVolumeClaims []*core.PersistentVolumeClaim, usesInstanceType bool, sortVolumesByLibvirt bool) (err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+
+	if object.Template == nil {
+		object.Template = &cnv.VirtualMachineInstanceTemplateSpec{}
+	}
+	shape := ShapeOf(r.Source.Provider)
+	r.mapDisks(vm, persistentVolumeClaims, object)
+	r.mapFirmware(vm, shape, object)
+	r.mapInput(object)
+	if !usesInstanceType {
+		r.mapCPU(shape, object)
+		r.mapMemory(shape, object)
+	}
+	err = r.mapNetworks(vm, object)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// Map the (single) network.
+// The VM has a NIC when the network is mapped
+// (most specifically for the VM) to a destination.
+func (r *Builder) mapNetworks(vm *model.VM, object *cnv.VirtualMachineSpec) (err error) {
+	var kNetworks []cnv.Network
+	var kInterfaces []cnv.Interface
+
+	vmRef := ref.Ref{ID: vm.ID, Name: vm.Name}
+	mapped := r.Context.Map.Network.FindNetworkForNIC(vmRef, 0, func(candidate *api.NetworkPair) bool {
+		if err != nil {
+			return false
+		}
+		network := &model.Network{}
+		err = r.Source.Inventory.Find(network, candidate.Source)
+		if err != nil {
+			return false
+		}
+		return network.ID == image.DefaultID
+	})
+	if err != nil {
+		return
+	}
+	if mapped != nil && mapped.Destination.Type != Ignored {
+		networkName := "net-0"
+		kNetwork := cnv.Network{
+			Name: networkName,
+		}
+		kInterface := cnv.Interface{
+			Name:  networkName,
+			Model: Virtio,
+		}
+		switch mapped.Destination.Type {
+		case Pod:
+			kNetwork.Pod = &cnv.PodNetwork{}
+			kInterface.Masquerade = &cnv.InterfaceMasquerade{}
+		case Multus, UDN:
+			kNetwork.Multus = &cnv.MultusNetwork{
+				NetworkName: path.Join(mapped.Destination.Namespace, mapped.Destination.Name),
+			}
+			kInterface.Bridge = &cnv.InterfaceBridge{}
+		case SRIOV:
+			kNetwork.Multus = &cnv.MultusNetwork{
+				NetworkName: path.Join(mapped.Destination.Namespace, mapped.Destination.Name),
+			}
+			kInterface.SRIOV = &cnv.InterfaceSRIOV{}
+		}
+		kNetworks = append(kNetworks, kNetwork)
+		kInterfaces = append(kInterfaces, kInterface)
+	}
+	object.Template.Spec.Networks = kNetworks
+	object.Template.Spec.Domain.Devices.Interfaces = kInterfaces
+	return
+}
+
+func (r *Builder) mapInput(object *cnv.VirtualMachineSpec) {
+	tablet := cnv.Input{
+		Type: Tablet,
+		Name: Tablet,
+		Bus:  Virtio,
+	}
+	object.Template.Spec.Domain.Devices.Inputs = []cnv.Input{tablet}
+}
+
+func (r *Builder) mapMemory(shape Shape, object *cnv.VirtualMachineSpec) {
+	reservation := resource.NewQuantity(shape.Memory, resource.BinarySI)
+	object.Template.Spec.Domain.Memory = &cnv.Memory{Guest: reservation}
+}
+
+func (r *Builder) mapCPU(shape Shape, object *cnv.VirtualMachineSpec) {
+	object.Template.Spec.Domain.CPU = &cnv.CPU{
+		Sockets: uint32(shape.CPU),
+		Cores:   1,
+		Threads: 1,
+	}
+}
+
+// Map the firmware.
+// The secure boot is disabled; the image has no NVRAM
+// with the enrolled keys.
+func (r *Builder) mapFirmware(vm *model.VM, shape Shape, object *cnv.VirtualMachineSpec) {
+	firmware := &cnv.Firmware{
+		Serial: vm.ID,
+	}
+	switch shape.Firmware {
+	case EFI:
+		firmware.Bootloader = &cnv.Bootloader{
+			EFI: &cnv.EFI{
+				SecureBoot: ptr.To(false),
+			}}
+	default:
+		firmware.Bootloader = &cnv.Bootloader{BIOS: &cnv.BIOS{}}
+	}
+	object.Template.Spec.Domain.Firmware = firmware
+}
+
+// Map the (boot) disk.
+func (r *Builder) mapDisks(vm *model.VM, persistentVolumeClaims []*core.PersistentVolumeClaim, object *cnv.VirtualMachineSpec) {
+	var kVolumes []cnv.Volume
+	var kDisks []cnv.Disk
+
+	for _, pvc := range persistentVolumeClaims {
+		if pvc.Annotations[planbase.AnnDiskSource] != vm.ID {
+			continue
+		}
+		volumeName := "vol-0"
+		volume := cnv.Volume{
+			Name: volumeName,
+			VolumeSource: cnv.VolumeSource{
+				PersistentVolumeClaim: &cnv.PersistentVolumeClaimVolumeSource{
+					PersistentVolumeClaimVolumeSource: core.PersistentVolumeClaimVolumeSource{
+						ClaimName: pvc.Name,
+					},
+				},
+			},
+		}
+		var bootOrder uint = 1
+		kubevirtDisk := cnv.Disk{
+			Name: volumeName,
+			DiskDevice: cnv.DiskDevice{
+				Disk: &cnv.DiskTarget{
+					Bus: cnv.DiskBus(Virtio),
+				},
+			},
+			BootOrder: &bootOrder,
+		}
+		kVolumes = append(kVolumes, volume)
+		kDisks = append(kDisks, kubevirtDisk)
+		break
+	}
+	object.Template.Spec.Volumes = kVolumes
+	object.Template.Spec.Domain.Devices.Disks = kDisks
+}
+
+// Build tasks.
+// The image is imported by a single task.
+func (r *Builder) Tasks(vmRef ref.Ref) (list []*plan.Task, err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	mB := vm.Capacity / 0x100000
+	list = append(
+		list,
+		&plan.Task{
+			Name: vm.ID,
+			Progress: libitr.Progress{
+				Total: mB,
+			},
+			Annotations: map[string]string{
+				"unit": "MB",
+			},
+		})
+
+	return
+}
+
+func (r *Builder) PreferenceName(vmRef ref.Ref, configMap *core.ConfigMap) (name string, err error) {
+	// The guest OS of an image is not known so we cannot get the corresponding preference.
+	err = liberr.New("preferences are not used by this provider")
+	return
+}
+
+// NO-OP
+func (r *Builder) CloudInit(vmRef ref.Ref) (cloudInit *planbase.CloudInit, err error) {
+	return
+}
+
+// Get the ISO media of the source VM CD-ROMs.
+// Not supported by the provider.
+func (r *Builder) CDROMs(vmRef ref.Ref) (media []planbase.CDROM, err error) {
+	return
+}
+
+// Get the provenance of the source VM.
+// Not supported by the provider.
+func (r *Builder) Provenance(vmRef ref.Ref) (provenance *planbase.Provenance, err error) {
+	return
+}
+
+// Get the source VM attributes.
+// Not supported by the provider.
+func (r *Builder) Attributes(vmRef ref.Ref) (attributes map[string]string, err error) {
+	return
+}
+
+func (r *Builder) TemplateLabels(vmRef ref.Ref) (labels map[string]string, err error) {
+	os := Unknown
+
+	labels = make(map[string]string)
+	labels[fmt.Sprintf(TemplateOSLabel, os)] = "true"
+	labels[TemplateWorkloadLabel] = "true"
+	labels[TemplateFlavorLabel] = "true"
+
+	return
+}
+
+// Return a stable identifier for a DataVolume.
+func (r *Builder) ResolveDataVolumeIdentifier(dv *cdi.DataVolume) string {
+	return dv.ObjectMeta.Annotations[planbase.AnnDiskSource]
+}
+
+// Return a stable identifier for a PersistentDataVolume.
+func (r *Builder) ResolvePersistentVolumeClaimIdentifier(pvc *core.PersistentVolumeClaim) string {
+	return pvc.Annotations[planbase.AnnDiskSource]
+}
+
+// Build LUN PVs.
+func (r *Builder) LunPersistentVolumes(vmRef ref.Ref) (pvs []core.PersistentVolume, err error) {
+	// do nothing
+	return
+}
+
+// Build LUN PVCs.
+func (r *Builder) LunPersistentVolumeClaims(vmRef ref.Ref) (pvcs []core.PersistentVolumeClaim, err error) {
+	// do nothing
+	return
+}
+
+func (r *Builder) SupportsVolumePopulators() bool {
+	return false
+}
+
+func (r *Builder) PopulatorVolumes(vmRef ref.Ref, annotations map[string]string, secretName string) (pvcs []*core.PersistentVolumeClaim, err error) {
+	err = planbase.VolumePopulatorNotSupportedError
+	return
+}
+
+func (r *Builder) PopulatorTransferredBytes(persistentVolumeClaim *core.PersistentVolumeClaim) (transferredBytes int64, err error) {
+	err = planbase.VolumePopulatorNotSupportedError
+	return
+}
+
+func (r *Builder) SetPopulatorDataSourceLabels(vmRef ref.Ref, pvcs []*core.PersistentVolumeClaim) (err error) {
+	err = planbase.VolumePopulatorNotSupportedError
+	return
+}
+
+func (r *Builder) GetPopulatorTaskName(pvc *core.PersistentVolumeClaim) (taskName string, err error) {
+	err = planbase.VolumePopulatorNotSupportedError
+	return
+}
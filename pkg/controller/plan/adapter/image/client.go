@@ -0,0 +1,103 @@
+package image
+
+import (
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	"github.com/kubev2v/forklift/pkg/controller/plan/util"
+	libclient "github.com/kubev2v/forklift/pkg/lib/client/image"
+	libutil "github.com/kubev2v/forklift/pkg/lib/util"
+	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+)
+
+// Disk image VM Client.
+// The images are not running VMs so there is
+// nothing to power off, snapshot or clean up. The
+// images are imported (downloaded) by CDI.
+type Client struct {
+	libclient.Client
+	Context *plancontext.Context
+}
+
+// Connect.
+func (r *Client) connect() (err error) {
+	r.URL = r.Context.Source.Provider.Spec.URL
+	r.Proxy = libutil.ProviderProxy(r.Context.Source.Provider)
+	r.Dial = libutil.ProviderDialer(r.Context.Source.Provider, nil)
+	r.LoadOptionsFromSecret(r.Context.Source.Secret)
+	err = r.Connect()
+	return
+}
+
+// NO-OP
+func (r *Client) PowerOn(vmRef ref.Ref) (err error) {
+	return
+}
+
+// NO-OP
+func (r *Client) PowerOff(vmRef ref.Ref) (err error) {
+	return
+}
+
+// Return the source VM's power state.
+// An image is always reported as off.
+func (r *Client) PowerState(vmRef ref.Ref) (state planapi.VMPowerState, err error) {
+	state = planapi.VMPowerStateOff
+	return
+}
+
+// Return whether the source VM is powered off.
+func (r *Client) PoweredOff(vmRef ref.Ref) (off bool, err error) {
+	off = true
+	return
+}
+
+// Create a snapshot of the source VM.
+// No-op; warm migration is not supported.
+func (r *Client) CreateSnapshot(vmRef ref.Ref, hostsFunc util.HostsFunc) (snapshotId string, creationTaskId string, err error) {
+	return
+}
+
+// Remove a snapshot. No-op for this provider.
+func (r *Client) RemoveSnapshot(vmRef ref.Ref, snapshot string, hostsFunc util.HostsFunc) (removeTaskId string, err error) {
+	return
+}
+
+// Check if a snapshot is ready to transfer.
+func (r *Client) CheckSnapshotReady(vmRef ref.Ref, precopy planapi.Precopy, hosts util.HostsFunc) (ready bool, snapshotId string, err error) {
+	return
+}
+
+// CheckSnapshotRemove implements base.Client
+func (r *Client) CheckSnapshotRemove(vmRef ref.Ref, precopy planapi.Precopy, hosts util.HostsFunc) (bool, error) {
+	return false, nil
+}
+
+// Get disk deltas for a VM snapshot. No-op for this provider.
+func (r *Client) GetSnapshotDeltas(vmRef ref.Ref, snapshot string, hostsFunc util.HostsFunc) (s map[string]string, err error) {
+	return
+}
+
+// Set DataVolume checkpoints.
+func (r *Client) SetCheckpoints(vmRef ref.Ref, precopies []planapi.Precopy, datavolumes []cdi.DataVolume, final bool, hostsFunc util.HostsFunc) (err error) {
+	return
+}
+
+// Close connections to the provider API.
+func (r *Client) Close() {
+}
+
+// Detach disks. No-op for this provider.
+func (r *Client) DetachDisks(vmRef ref.Ref) (err error) {
+	return
+}
+
+// NO-OP
+func (r *Client) PreTransferActions(vmRef ref.Ref) (ready bool, err error) {
+	ready = true
+	return
+}
+
+// NO-OP
+func (r *Client) Finalize(vms []*planapi.VMStatus, planName string) {
+}
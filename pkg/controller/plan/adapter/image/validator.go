@@ -0,0 +1,199 @@
+package image
+
+import (
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	planbase "github.com/kubev2v/forklift/pkg/controller/plan/adapter/base"
+	"github.com/kubev2v/forklift/pkg/controller/plan/util"
+	image "github.com/kubev2v/forklift/pkg/controller/provider/model/image"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/web/image"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Disk image validator.
+type Validator struct {
+	plan      *api.Plan
+	inventory web.Client
+}
+
+// Load.
+func (r *Validator) Load() (err error) {
+	r.inventory, err = web.NewClient(r.plan.Referenced.Provider.Source)
+	return
+}
+
+// Validate whether warm migration is supported from this provider type.
+func (r *Validator) WarmMigration() (ok bool) {
+	ok = false
+	return
+}
+
+// NOOP
+func (r *Validator) SharedDisks(vmRef ref.Ref, client client.Client) (ok bool, s string, s2 string, err error) {
+	ok = true
+	return
+}
+
+// Validate that the (single) network has been mapped.
+func (r *Validator) NetworksMapped(vmRef ref.Ref) (ok bool, err error) {
+	if r.plan.Referenced.Map.Network == nil {
+		return
+	}
+	ok = r.plan.Referenced.Map.Network.Status.Refs.Find(ref.Ref{ID: image.DefaultID})
+	return
+}
+
+// Validate that the VM is not mapped to the pod network more than once.
+// The VM has (at most) one NIC.
+func (r *Validator) PodNetwork(vmRef ref.Ref) (ok bool, err error) {
+	if r.plan.Referenced.Map.Network == nil {
+		return
+	}
+	ok = true
+	return
+}
+
+// Validate that the image storage has been mapped.
+func (r *Validator) StorageMapped(vmRef ref.Ref) (ok bool, err error) {
+	if r.plan.Referenced.Map.Storage == nil {
+		return
+	}
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	ok = r.plan.Referenced.Map.Storage.Status.Refs.Find(ref.Ref{ID: vm.Storage})
+	return
+}
+
+// NO-OP
+func (r *Validator) MaintenanceMode(vmRef ref.Ref) (ok bool, err error) {
+	ok = true
+	return
+}
+
+// NO-OP
+func (r *Validator) DirectStorage(vmRef ref.Ref) (bool, error) {
+	return true, nil
+}
+
+// NO-OP
+func (r *Validator) StaticIPs(vmRef ref.Ref) (bool, error) {
+	return true, nil
+}
+
+// NO-OP
+func (r *Validator) ChangeTrackingEnabled(vmRef ref.Ref) (bool, error) {
+	return true, nil
+}
+
+// NO-OP
+func (r *Validator) CPUModel(vmRef ref.Ref) (cpuModel string, features []string, err error) {
+	return
+}
+
+// NO-OP
+func (r *Validator) GuestOS(vmRef ref.Ref) (ids []string, err error) {
+	return
+}
+
+// Return the (single) VM NIC with the destination network it is mapped to.
+// The MAC address is assigned on the destination.
+func (r *Validator) NICs(vmRef ref.Ref) (nics []planbase.NIC, err error) {
+	if r.plan.Referenced.Map.Network == nil {
+		return
+	}
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	mapped, err := r.findNetworkMapping(vm)
+	if err != nil {
+		return
+	}
+	if mapped != nil {
+		nics = append(nics, planbase.NIC{Destination: mapped.Destination})
+	}
+	return
+}
+
+// Find the most specific network mapping of the VM NIC.
+func (r *Validator) findNetworkMapping(vm *model.VM) (mapped *api.NetworkPair, err error) {
+	vmRef := ref.Ref{ID: vm.ID, Name: vm.Name}
+	mapped = r.plan.Referenced.Map.Network.FindNetworkForNIC(vmRef, 0, func(candidate *api.NetworkPair) bool {
+		if err != nil {
+			return false
+		}
+		network := &model.Network{}
+		err = r.inventory.Find(network, candidate.Source)
+		if err != nil {
+			return false
+		}
+		return network.ID == image.DefaultID
+	})
+	if err != nil {
+		mapped = nil
+	}
+	return
+}
+
+// NO-OP
+func (r *Validator) PersistentTPM(vmRef ref.Ref) (persistent bool, err error) {
+	return
+}
+
+// NO-OP
+func (r *Validator) PassthroughDevices(vmRef ref.Ref) (profiles []string, err error) {
+	return
+}
+
+// NO-OP
+func (r *Validator) RDMDisks(vmRef ref.Ref, client client.Client) (unmapped []string, err error) {
+	return
+}
+
+// NO-OP
+func (r *Validator) IndependentDisks(vmRef ref.Ref) (disks []string, err error) {
+	return
+}
+
+// NO-OP
+func (r *Validator) UnmigratedBootDevices(vmRef ref.Ref) (devices []string, err error) {
+	return
+}
+
+// NO-OP
+func (r *Validator) AffinityRules(vmRef ref.Ref) (rules []util.AffinityRule, err error) {
+	return
+}
+
+// Return the CPU, memory (provider settings) and disk capacity
+// (by mapped storage class) of the VM.
+func (r *Validator) Demand(vmRef ref.Ref) (demand *planbase.Demand, err error) {
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	shape := ShapeOf(r.plan.Referenced.Provider.Source)
+	demand = &planbase.Demand{
+		CPU:     shape.CPU,
+		Memory:  shape.Memory,
+		Storage: map[string]int64{},
+	}
+	if r.plan.Referenced.Map.Storage == nil {
+		return
+	}
+	mapped, found := r.plan.Referenced.Map.Storage.FindStorage(vm.Storage)
+	if found && mapped.Destination.StorageClass != "" {
+		demand.Storage[mapped.Destination.StorageClass] += vm.Capacity
+	}
+	return
+}
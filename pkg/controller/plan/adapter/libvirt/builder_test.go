@@ -0,0 +1,107 @@
+package libvirt
+
+import (
+	"testing"
+
+	planbase "github.com/kubev2v/forklift/pkg/controller/plan/adapter/base"
+	libvirt "github.com/kubev2v/forklift/pkg/controller/provider/model/libvirt"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/web/libvirt"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cnv "kubevirt.io/api/core/v1"
+)
+
+func TestMapDisks(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	builder := &Builder{}
+	vm := &model.VM{
+		Disks: []libvirt.Disk{
+			{Target: "vdb", Bus: "virtio", Device: "disk"},
+			{Target: "sda", Bus: "ide", Device: "cdrom"},
+			{Target: "sdb", Bus: "sata", Device: "disk", BootOrder: 1},
+			{Target: "vda", Bus: "virtio", Device: "disk"},
+		},
+	}
+	pvc := func(target string) *core.PersistentVolumeClaim {
+		return &core.PersistentVolumeClaim{
+			ObjectMeta: meta.ObjectMeta{
+				Name:        "pvc-" + target,
+				Annotations: map[string]string{planbase.AnnDiskSource: target},
+			},
+		}
+	}
+	object := &cnv.VirtualMachineSpec{Template: &cnv.VirtualMachineInstanceTemplateSpec{}}
+	builder.mapDisks(vm, []*core.PersistentVolumeClaim{pvc("vda"), pvc("vdb"), pvc("sdb")}, object)
+
+	volumes := object.Template.Spec.Volumes
+	disks := object.Template.Spec.Domain.Devices.Disks
+	g.Expect(volumes).To(gomega.HaveLen(3))
+	g.Expect(volumes[0].PersistentVolumeClaim.ClaimName).To(gomega.Equal("pvc-sdb"))
+	g.Expect(volumes[1].PersistentVolumeClaim.ClaimName).To(gomega.Equal("pvc-vda"))
+	g.Expect(volumes[2].PersistentVolumeClaim.ClaimName).To(gomega.Equal("pvc-vdb"))
+	g.Expect(*disks[0].BootOrder).To(gomega.Equal(uint(1)))
+	g.Expect(disks[0].Disk.Bus).To(gomega.Equal(cnv.DiskBus(Sata)))
+	g.Expect(disks[1].BootOrder).To(gomega.BeNil())
+	g.Expect(disks[1].Disk.Bus).To(gomega.Equal(cnv.DiskBus(Virtio)))
+}
+
+func TestMapFirmware(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	builder := &Builder{}
+	cases := []struct {
+		firmware   string
+		secureBoot bool
+	}{
+		{firmware: BIOS},
+		{firmware: EFI},
+		{firmware: EFI, secureBoot: true},
+	}
+	for _, c := range cases {
+		vm := &model.VM{}
+		vm.ID = "4dea22b3-1d52-d8f3-2516-782e98ab3fa0"
+		vm.Firmware = c.firmware
+		vm.SecureBoot = c.secureBoot
+		object := &cnv.VirtualMachineSpec{Template: &cnv.VirtualMachineInstanceTemplateSpec{}}
+		builder.mapFirmware(vm, object)
+		firmware := object.Template.Spec.Domain.Firmware
+		g.Expect(string(firmware.UUID)).To(gomega.Equal(vm.ID))
+		if c.firmware == EFI {
+			g.Expect(firmware.Bootloader.EFI).ToNot(gomega.BeNil())
+			g.Expect(*firmware.Bootloader.EFI.SecureBoot).To(gomega.Equal(c.secureBoot))
+			g.Expect(object.Template.Spec.Domain.Features != nil).To(gomega.Equal(c.secureBoot))
+		} else {
+			g.Expect(firmware.Bootloader.BIOS).ToNot(gomega.BeNil())
+		}
+	}
+}
+
+func TestMapCPU(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	builder := &Builder{}
+	vm := &model.VM{}
+	vm.CPUSockets = 2
+	vm.CPUCores = 4
+	object := &cnv.VirtualMachineSpec{Template: &cnv.VirtualMachineInstanceTemplateSpec{}}
+	builder.mapCPU(vm, object)
+	cpu := object.Template.Spec.Domain.CPU
+	g.Expect(cpu.Sockets).To(gomega.Equal(uint32(2)))
+	g.Expect(cpu.Cores).To(gomega.Equal(uint32(4)))
+	g.Expect(cpu.Threads).To(gomega.Equal(uint32(1)))
+}
+
+func TestPreferenceOf(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	cases := map[string]string{
+		"http://fedoraproject.org/fedora/38": "fedora",
+		"http://redhat.com/rhel/9.2":         "rhel.9",
+		"http://centos.org/centos-stream/9":  "centos.stream9",
+		"http://microsoft.com/win/2k19":      "windows.2k19.virtio",
+		"http://microsoft.com/win/11":        "windows.11.virtio",
+		"http://microsoft.com/win/7":         "",
+		"":                                   "",
+	}
+	for id, preference := range cases {
+		g.Expect(preferenceOf(id)).To(gomega.Equal(preference), id)
+	}
+}
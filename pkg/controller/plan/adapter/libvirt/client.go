@@ -0,0 +1,372 @@
+package libvirt
+
+import (
+	"strconv"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	"github.com/kubev2v/forklift/pkg/controller/plan/util"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/web/libvirt"
+	libclient "github.com/kubev2v/forklift/pkg/lib/client/libvirt"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	libutil "github.com/kubev2v/forklift/pkg/lib/util"
+	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+)
+
+// Libvirt VM Client.
+// The disks are exported (over NBD) by (pull mode) backups
+// of the domain and read by the libvirt volume populator.
+// Each precopy creates a checkpoint used by the (incremental)
+// backup of the next precopy.
+type Client struct {
+	libclient.Client
+	Context *plancontext.Context
+}
+
+// Connect.
+func (r *Client) connect() (err error) {
+	r.URL = r.Context.Source.Provider.Spec.URL
+	r.Dial = libutil.ProviderDialer(r.Context.Source.Provider, nil)
+	r.LoadOptionsFromSecret(r.Context.Source.Secret)
+	err = r.Connect()
+	return
+}
+
+// Power on the source VM.
+// A domain paused (to be backed up) is restarted.
+func (r *Client) PowerOn(vmRef ref.Ref) (err error) {
+	vm, err := r.getVM(vmRef)
+	if err != nil {
+		return
+	}
+	state, err := r.DomainState(vm.ID)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	switch state {
+	case libclient.StateRunning:
+		return
+	case libclient.StatePaused:
+		err = r.AbortJob(vm.ID)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		err = r.DestroyDomain(vm.ID)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+	err = r.StartDomain(vm.ID, false)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+	return
+}
+
+// Power off the source VM.
+// The guest is shut down (ACPI).
+func (r *Client) PowerOff(vmRef ref.Ref) (err error) {
+	vm, err := r.getVM(vmRef)
+	if err != nil {
+		return
+	}
+	err = r.ShutdownDomain(vm.ID)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+	return
+}
+
+// Return the source VM's power state.
+// A paused domain is not running the guest and is reported as off.
+func (r *Client) PowerState(vmRef ref.Ref) (state planapi.VMPowerState, err error) {
+	state = planapi.VMPowerStateUnknown
+	vm, err := r.getVM(vmRef)
+	if err != nil {
+		return
+	}
+	domainState, err := r.DomainState(vm.ID)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	switch domainState {
+	case libclient.StateRunning, libclient.StateShutdown:
+		state = planapi.VMPowerStateOn
+	case libclient.StateShutOff, libclient.StateCrashed, libclient.StatePaused:
+		state = planapi.VMPowerStateOff
+	}
+	return
+}
+
+// Return whether the source VM is powered off.
+func (r *Client) PoweredOff(vmRef ref.Ref) (off bool, err error) {
+	state, err := r.PowerState(vmRef)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	off = state == planapi.VMPowerStateOff
+	return
+}
+
+// Begin the (incremental) backup of the precopy.
+// The backup of the previous precopy (already copied) is ended
+// and the domain is started (paused) when shut off. The precopy
+// (number) is returned as the snapshot ID.
+func (r *Client) CreateSnapshot(vmRef ref.Ref, hostsFunc util.HostsFunc) (snapshotId string, creationTaskId string, err error) {
+	vm, err := r.getVM(vmRef)
+	if err != nil {
+		return
+	}
+	vmStatus, found := r.Context.FindVM(vmRef)
+	if !found || vmStatus.Warm == nil {
+		err = liberr.New("the warm migration status of the VM was not found.", "vm", vmRef.String())
+		return
+	}
+	precopy := vmStatus.Warm.Iterations()
+	final := vmStatus.Phase == api.PhaseCreateFinalSnapshot
+	err = r.beginBackup(vm, precopy, final)
+	if err != nil {
+		return
+	}
+	snapshotId = strconv.Itoa(precopy)
+	return
+}
+
+// Remove a snapshot. No-op for this provider.
+// The checkpoints are deleted when the migration is finalized.
+func (r *Client) RemoveSnapshot(vmRef ref.Ref, snapshot string, hostsFunc util.HostsFunc) (removeTaskId string, err error) {
+	return
+}
+
+// Check if the backup of the precopy is being exported.
+func (r *Client) CheckSnapshotReady(vmRef ref.Ref, precopy planapi.Precopy, hosts util.HostsFunc) (ready bool, snapshotId string, err error) {
+	vm, err := r.getVM(vmRef)
+	if err != nil {
+		return
+	}
+	n, err := strconv.Atoi(precopy.Snapshot)
+	if err != nil {
+		err = liberr.Wrap(err, "snapshot", precopy.Snapshot)
+		return
+	}
+	ready, err = r.backupReady(vm, n)
+	return
+}
+
+// CheckSnapshotRemove implements base.Client
+func (r *Client) CheckSnapshotRemove(vmRef ref.Ref, precopy planapi.Precopy, hosts util.HostsFunc) (bool, error) {
+	return true, nil
+}
+
+// Get disk deltas for a VM snapshot. No-op for this provider.
+func (r *Client) GetSnapshotDeltas(vmRef ref.Ref, snapshot string, hostsFunc util.HostsFunc) (s map[string]string, err error) {
+	return
+}
+
+// Set DataVolume checkpoints.
+// No-op; the populator copies the backups in order.
+func (r *Client) SetCheckpoints(vmRef ref.Ref, precopies []planapi.Precopy, datavolumes []cdi.DataVolume, final bool, hostsFunc util.HostsFunc) (err error) {
+	return
+}
+
+// Close connections to the provider API.
+func (r *Client) Close() {
+	r.Client.Close()
+}
+
+// Detach disks. No-op for this provider.
+func (r *Client) DetachDisks(vmRef ref.Ref) (err error) {
+	return
+}
+
+// Begin the (full) backup of the VM disks (cold migration).
+// Ready when the backup is being exported.
+// The backups of a warm migration are created by the precopies.
+func (r *Client) PreTransferActions(vmRef ref.Ref) (ready bool, err error) {
+	if r.Context.Plan.Spec.Warm {
+		ready = true
+		return
+	}
+	vm, err := r.getVM(vmRef)
+	if err != nil {
+		return
+	}
+	ready, err = r.backupReady(vm, 0)
+	if err != nil || ready {
+		return
+	}
+	err = r.beginBackup(vm, 0, true)
+	if err != nil {
+		return
+	}
+	ready, err = r.backupReady(vm, 0)
+	return
+}
+
+// End the backups and delete the checkpoints created for the migration.
+// The domains started (paused) to be backed up are destroyed.
+func (r *Client) Finalize(vms []*planapi.VMStatus, planName string) {
+	migration := string(r.Context.Migration.UID)
+	for _, vmStatus := range vms {
+		vm, err := r.getVM(vmStatus.Ref)
+		if err != nil {
+			r.Log.Error(err, "failed to find vm", "vm", vmStatus.Ref.String())
+			continue
+		}
+		err = r.AbortJob(vm.ID)
+		if err != nil {
+			r.Log.Error(err, "failed to end the backup", "vm", vm.Name)
+		}
+		state, err := r.DomainState(vm.ID)
+		if err != nil {
+			r.Log.Error(err, "failed to get the domain state", "vm", vm.Name)
+			continue
+		}
+		if state == libclient.StatePaused {
+			err = r.DestroyDomain(vm.ID)
+			if err != nil {
+				r.Log.Error(err, "failed to destroy the paused domain", "vm", vm.Name)
+			}
+		}
+		checkpoints, err := r.ListCheckpoints(vm.ID)
+		if err != nil {
+			r.Log.Error(err, "failed to list the checkpoints", "vm", vm.Name)
+			continue
+		}
+		for _, name := range checkpoints {
+			if !libclient.IsCheckpointOf(name, migration) {
+				continue
+			}
+			err = r.DeleteCheckpoint(vm.ID, name)
+			if err != nil {
+				r.Log.Error(err, "failed to delete the checkpoint", "vm", vm.Name, "checkpoint", name)
+			}
+		}
+		for _, disk := range vm.Disks {
+			if !Migrated(disk) {
+				continue
+			}
+			err = r.RemoveMetadata(vm.ID, libclient.CopiedURI(migration, disk.Target))
+			if err != nil {
+				r.Log.Error(err, "failed to remove the metadata", "vm", vm.Name, "disk", disk.Target)
+			}
+		}
+	}
+}
+
+// Begin the backup of the precopy.
+// The backup is incremental (since the checkpoint created by
+// the previous precopy) except for the first precopy. A checkpoint
+// is created for the next precopy unless final.
+func (r *Client) beginBackup(vm *model.VM, precopy int, final bool) (err error) {
+	migration := string(r.Context.Migration.UID)
+	socket := libclient.BackupSocket(migration, precopy, final)
+	backup, found, err := r.GetBackup(vm.ID)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vm.Name)
+		return
+	}
+	if found && backup.Server != nil && backup.Server.Socket == socket {
+		return
+	}
+	err = r.AbortJob(vm.ID)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vm.Name)
+		return
+	}
+	state, err := r.DomainState(vm.ID)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vm.Name)
+		return
+	}
+	if state == libclient.StateShutOff || state == libclient.StateCrashed {
+		err = r.StartDomain(vm.ID, true)
+		if err != nil {
+			err = liberr.Wrap(err, "vm", vm.Name)
+			return
+		}
+	}
+	backup = &libclient.DomainBackup{
+		Mode: "pull",
+		Server: &libclient.BackupServer{
+			Transport: "unix",
+			Socket:    socket,
+		},
+	}
+	var checkpoint *libclient.DomainCheckpoint
+	if r.Context.Plan.Spec.Warm {
+		if precopy > 0 {
+			backup.Incremental = libclient.CheckpointName(migration, precopy-1)
+		}
+		if !final {
+			checkpoint = &libclient.DomainCheckpoint{
+				Name: libclient.CheckpointName(migration, precopy),
+			}
+		}
+	}
+	for _, disk := range vm.Disks {
+		if disk.Target == "" {
+			continue
+		}
+		backupDisk := libclient.BackupDisk{Name: disk.Target, Backup: "no"}
+		checkpointDisk := libclient.CheckpointDisk{Name: disk.Target, Checkpoint: "no"}
+		if Migrated(disk) {
+			backupDisk.Backup = "yes"
+			backupDisk.ExportName = disk.Target
+			if backup.Incremental != "" {
+				backupDisk.ExportBitmap = libclient.ExportBitmap(disk.Target)
+			}
+			checkpointDisk.Checkpoint = "bitmap"
+		}
+		backup.Disks = append(backup.Disks, backupDisk)
+		if checkpoint != nil {
+			checkpoint.Disks = append(checkpoint.Disks, checkpointDisk)
+		}
+	}
+	err = r.BeginBackup(vm.ID, backup, checkpoint)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vm.Name)
+		return
+	}
+	r.Log.Info(
+		"Began the disk backup.",
+		"vm",
+		vm.Name,
+		"precopy",
+		precopy,
+		"final",
+		final)
+	return
+}
+
+// Determine whether the backup of the precopy is being exported.
+func (r *Client) backupReady(vm *model.VM, precopy int) (ready bool, err error) {
+	backup, found, err := r.GetBackup(vm.ID)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vm.Name)
+		return
+	}
+	if !found || backup.Server == nil {
+		return
+	}
+	n, _, found := libclient.ParseBackupSocket(backup.Server.Socket, string(r.Context.Migration.UID))
+	ready = found && n == precopy
+	return
+}
+
+// Find the VM in the inventory.
+func (r *Client) getVM(vmRef ref.Ref) (vm *model.VM, err error) {
+	vm = &model.VM{}
+	err = r.Context.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+	}
+	return
+}
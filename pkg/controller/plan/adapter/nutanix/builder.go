@@ -0,0 +1,648 @@
+package nutanix
+
+import (
+	"encoding/base64"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	planbase "github.com/kubev2v/forklift/pkg/controller/plan/adapter/base"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	utils "github.com/kubev2v/forklift/pkg/controller/plan/util"
+	nutanix "github.com/kubev2v/forklift/pkg/controller/provider/model/nutanix"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/web/nutanix"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/ocp"
+	libclient "github.com/kubev2v/forklift/pkg/lib/client/nutanix"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	libitr "github.com/kubev2v/forklift/pkg/lib/itinerary"
+	libutil "github.com/kubev2v/forklift/pkg/lib/util"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+	cnv "kubevirt.io/api/core/v1"
+	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+)
+
+// Boot types.
+const (
+	Legacy     = "LEGACY"
+	UEFI       = "UEFI"
+	SecureBoot = "SECURE_BOOT"
+)
+
+// Disk adapter (source bus) types.
+const (
+	AdapterSCSI = "SCSI"
+	AdapterSATA = "SATA"
+	AdapterIDE  = "IDE"
+)
+
+// NIC models.
+const (
+	ModelE1000 = "E1000"
+)
+
+// Bus types
+const (
+	Virtio = "virtio"
+	Sata   = "sata"
+	Scsi   = "scsi"
+	E1000  = "e1000"
+)
+
+// Input types
+const (
+	Tablet = "tablet"
+)
+
+// Network types
+const (
+	Pod     = "pod"
+	Multus  = "multus"
+	SRIOV   = "sriov"
+	UDN     = "udn"
+	Ignored = "ignored"
+)
+
+// Template labels
+const (
+	TemplateOSLabel       = "os.template.kubevirt.io/%s"
+	TemplateWorkloadLabel = "workload.template.kubevirt.io/server"
+	TemplateFlavorLabel   = "flavor.template.kubevirt.io/medium"
+)
+
+// Operating Systems
+const (
+	Unknown = "unknown"
+)
+
+// Nutanix builder.
+type Builder struct {
+	*plancontext.Context
+	// Prism Central client.
+	client *libclient.Client
+	// MAC addresses already in use on the destination cluster. k=mac, v=vmName
+	macConflictsMap map[string]string
+}
+
+// Get list of destination VMs with mac addresses that would
+// conflict with this VM, if any exist.
+func (r *Builder) macConflicts(vm *model.VM) (conflictingVMs []string, err error) {
+	if r.macConflictsMap == nil {
+		list := []ocp.VM{}
+		err = r.Destination.Inventory.List(&list, base.Param{
+			Key:   base.DetailParam,
+			Value: "all",
+		})
+		if err != nil {
+			return
+		}
+
+		r.macConflictsMap = make(map[string]string)
+		for _, kVM := range list {
+			for _, iface := range kVM.Object.Spec.Template.Spec.Domain.Devices.Interfaces {
+				r.macConflictsMap[iface.MacAddress] = path.Join(kVM.Namespace, kVM.Name)
+			}
+		}
+	}
+
+	for _, nic := range vm.NICs {
+		if conflictingVm, found := r.macConflictsMap[nic.MAC]; found {
+			conflictingVMs = append(conflictingVMs, conflictingVm)
+		}
+	}
+
+	return
+}
+
+// Build the DataVolume certificate configmap.
+func (r *Builder) ConfigMap(_ ref.Ref, in *core.Secret, object *core.ConfigMap) (err error) {
+	object.BinaryData["ca.pem"] = in.Data[libclient.CACert]
+	return
+}
+
+func (r *Builder) PodEnvironment(_ ref.Ref, _ *core.Secret) (env []core.EnvVar, err error) {
+	return
+}
+
+// Build the DataVolume credential secret.
+func (r *Builder) Secret(_ ref.Ref, in, object *core.Secret) (err error) {
+	object.StringData = map[string]string{
+		"accessKeyId": string(in.Data[libclient.User]),
+		"secretKey":   string(in.Data[libclient.Password]),
+	}
+	return
+}
+
+// Create DataVolume specs for the VM.
+// The disks are imported from the images exported
+// by the client (pre-transfer actions).
+func (r *Builder) DataVolumes(vmRef ref.Ref, secret *core.Secret, configMap *core.ConfigMap, dvTemplate *cdi.DataVolume, vddkConfigMap *core.ConfigMap) (dvs []cdi.DataVolume, err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	client, err := r.getClient()
+	if err != nil {
+		return
+	}
+	certConfigMap := ""
+	if configMap != nil && len(configMap.BinaryData["ca.pem"]) > 0 {
+		certConfigMap = configMap.Name
+	}
+
+	storageMapIn := r.Context.Map.Storage.Spec.Map
+	for i := range storageMapIn {
+		mapped := &storageMapIn[i]
+		container := &model.StorageContainer{}
+		err = r.Source.Inventory.Find(container, mapped.Source)
+		if err != nil {
+			return
+		}
+		for _, disk := range vm.Disks {
+			if disk.DeviceType != DeviceDisk || disk.StorageContainer != container.ID {
+				continue
+			}
+			name := ImageName(vm.ID, disk.ID)
+			var image *libclient.Image
+			image, err = client.FindImage(name)
+			if err != nil {
+				err = liberr.Wrap(err, "image", name)
+				return
+			}
+			if image == nil {
+				err = liberr.New("disk image not found.", "vm", vm.Name, "image", name)
+				return
+			}
+			storageClass := mapped.Destination.StorageClass
+			dvSpec := cdi.DataVolumeSpec{
+				Source: &cdi.DataVolumeSource{
+					HTTP: &cdi.DataVolumeSourceHTTP{
+						URL:           client.ImageFileURL(image.Metadata.UUID),
+						SecretRef:     secret.Name,
+						CertConfigMap: certConfigMap,
+					},
+				},
+				Storage: &cdi.StorageSpec{
+					Resources: core.VolumeResourceRequirements{
+						Requests: core.ResourceList{
+							core.ResourceStorage: *resource.NewQuantity(utils.DataVolumeSize(disk.Capacity, utils.FormatRaw), resource.BinarySI),
+						},
+					},
+					StorageClassName: &storageClass,
+				},
+			}
+			// set the access mode and volume mode if they were specified in the storage map.
+			// otherwise, let the storage profile decide the default values.
+			if accessModes := mapped.Destination.GetAccessModes(); len(accessModes) > 0 {
+				dvSpec.Storage.AccessModes = accessModes
+			}
+			if mapped.Destination.VolumeMode != "" {
+				dvSpec.Storage.VolumeMode = &mapped.Destination.VolumeMode
+			}
+
+			dv := dvTemplate.DeepCopy()
+			dv.Spec = dvSpec
+			if dv.ObjectMeta.Annotations == nil {
+				dv.ObjectMeta.Annotations = make(map[string]string)
+			}
+			dv.ObjectMeta.Annotations[planbase.AnnDiskSource] = disk.ID
+			dv.ObjectMeta.Annotations = mapped.Destination.Annotate(dv.ObjectMeta.Annotations)
+			dvs = append(dvs, *dv)
+		}
+	}
+
+	return
+}
+
+// Create the destination Kubevirt VM.
+func (r *Builder) VirtualMachine(vmRef ref.Ref, object *cnv.VirtualMachineSpec, persistentVolumeClaims []*core.PersistentVolumeClaim, usesInstanceType bool, sortVolumesByLibvirt bool) (err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+
+	var conflicts []string
+	conflicts, err = r.macConflicts(vm)
+	if err != nil {
+		return
+	}
+	if len(conflicts) > 0 {
+		err = liberr.New(
+			fmt.Sprintf("Source VM has a mac address conflict with one or more destination VMs: %s", conflicts))
+		return
+	}
+
+	if object.Template == nil {
+		object.Template = &cnv.VirtualMachineInstanceTemplateSpec{}
+	}
+	r.mapDisks(vm, persistentVolumeClaims, object)
+	r.mapFirmware(vm, object)
+	r.mapInput(object)
+	r.mapTpm(vm, object)
+	if !usesInstanceType {
+		r.mapCPU(vm, object)
+		r.mapMemory(vm, object)
+	}
+	err = r.mapNetworks(vm, object)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+func (r *Builder) mapNetworks(vm *model.VM, object *cnv.VirtualMachineSpec) (err error) {
+	var kNetworks []cnv.Network
+	var kInterfaces []cnv.Interface
+
+	numNetworks := 0
+	subnets, err := r.mappedSubnets()
+	if err != nil {
+		return
+	}
+	vmRef := ref.Ref{ID: vm.ID, Name: vm.Name}
+	netMapIn := r.Context.Map.Network.Spec.Map
+	for i := range netMapIn {
+		mapped := &netMapIn[i]
+
+		// Skip network mappings with destination type 'Ignored'
+		if mapped.Destination.Type == Ignored {
+			continue
+		}
+
+		needed := []nutanix.NIC{}
+		for index, nic := range vm.NICs {
+			if nic.Subnet != subnets[mapped] {
+				continue
+			}
+			// Skip NICs with a more specific mapping.
+			best := r.Context.Map.Network.FindNetworkForNIC(vmRef, index, func(candidate *api.NetworkPair) bool {
+				id, found := subnets[candidate]
+				return found && id == nic.Subnet
+			})
+			if best == mapped {
+				needed = append(needed, nic)
+			}
+		}
+		for _, nic := range needed {
+			networkName := fmt.Sprintf("net-%v", numNetworks)
+			numNetworks++
+			kNetwork := cnv.Network{
+				Name: networkName,
+			}
+			kInterface := cnv.Interface{
+				Name:       networkName,
+				Model:      Virtio,
+				MacAddress: nic.MAC,
+			}
+			if strings.ToUpper(nic.Model) == ModelE1000 {
+				kInterface.Model = E1000
+			}
+			switch mapped.Destination.Type {
+			case Pod:
+				kNetwork.Pod = &cnv.PodNetwork{}
+				kInterface.Masquerade = &cnv.InterfaceMasquerade{}
+			case Multus, UDN:
+				kNetwork.Multus = &cnv.MultusNetwork{
+					NetworkName: path.Join(mapped.Destination.Namespace, mapped.Destination.Name),
+				}
+				kInterface.Bridge = &cnv.InterfaceBridge{}
+			case SRIOV:
+				kNetwork.Multus = &cnv.MultusNetwork{
+					NetworkName: path.Join(mapped.Destination.Namespace, mapped.Destination.Name),
+				}
+				kInterface.SRIOV = &cnv.InterfaceSRIOV{}
+			}
+			kNetworks = append(kNetworks, kNetwork)
+			kInterfaces = append(kInterfaces, kInterface)
+		}
+	}
+	object.Template.Spec.Networks = kNetworks
+	object.Template.Spec.Domain.Devices.Interfaces = kInterfaces
+	return
+}
+
+// Resolve the source subnet (ID) of each network mapping.
+func (r *Builder) mappedSubnets() (subnets map[*api.NetworkPair]string, err error) {
+	subnets = make(map[*api.NetworkPair]string)
+	netMapIn := r.Context.Map.Network.Spec.Map
+	for i := range netMapIn {
+		mapped := &netMapIn[i]
+		subnet := &model.Subnet{}
+		fErr := r.Source.Inventory.Find(subnet, mapped.Source)
+		if fErr != nil {
+			if mapped.Destination.Type == Ignored {
+				continue
+			}
+			err = fErr
+			return
+		}
+		subnets[mapped] = subnet.ID
+	}
+	return
+}
+
+func (r *Builder) mapInput(object *cnv.VirtualMachineSpec) {
+	tablet := cnv.Input{
+		Type: Tablet,
+		Name: Tablet,
+		Bus:  Virtio,
+	}
+	object.Template.Spec.Domain.Devices.Inputs = []cnv.Input{tablet}
+}
+
+func (r *Builder) mapMemory(vm *model.VM, object *cnv.VirtualMachineSpec) {
+	reservation := resource.NewQuantity(vm.MemoryMB*(1<<20), resource.BinarySI)
+	object.Template.Spec.Domain.Memory = &cnv.Memory{Guest: reservation}
+}
+
+func (r *Builder) mapCPU(vm *model.VM, object *cnv.VirtualMachineSpec) {
+	object.Template.Spec.Domain.CPU = &cnv.CPU{
+		Sockets: uint32(max(vm.Sockets, 1)),
+		Cores:   uint32(max(vm.CoresPerSocket, 1)),
+		Threads: uint32(max(vm.ThreadsPerCore, 1)),
+	}
+}
+
+func (r *Builder) mapFirmware(vm *model.VM, object *cnv.VirtualMachineSpec) {
+	firmware := &cnv.Firmware{
+		Serial: vm.ID,
+	}
+	switch vm.BootType {
+	case UEFI, SecureBoot:
+		// The secure boot is disabled; the guest OS won't be able
+		// to boot without getting the NVRAM data.
+		firmware.Bootloader = &cnv.Bootloader{
+			EFI: &cnv.EFI{
+				SecureBoot: ptr.To(false),
+			}}
+	default:
+		firmware.Bootloader = &cnv.Bootloader{BIOS: &cnv.BIOS{}}
+	}
+	object.Template.Spec.Domain.Firmware = firmware
+}
+
+func (r *Builder) mapTpm(vm *model.VM, object *cnv.VirtualMachineSpec) {
+	if vm.TPM {
+		persistData := true
+		object.Template.Spec.Domain.Devices.TPM = &cnv.TPMDevice{Persistent: &persistData}
+	}
+}
+
+// Map the disks (ordered by bus and index).
+// The first disk is the boot disk.
+func (r *Builder) mapDisks(vm *model.VM, persistentVolumeClaims []*core.PersistentVolumeClaim, object *cnv.VirtualMachineSpec) {
+	var kVolumes []cnv.Volume
+	var kDisks []cnv.Disk
+
+	pvcMap := make(map[string]*core.PersistentVolumeClaim)
+	for i := range persistentVolumeClaims {
+		pvc := persistentVolumeClaims[i]
+		if source, ok := pvc.Annotations[planbase.AnnDiskSource]; ok {
+			pvcMap[source] = pvc
+		}
+	}
+	disks := []nutanix.Disk{}
+	for _, disk := range vm.Disks {
+		if disk.DeviceType == DeviceDisk {
+			disks = append(disks, disk)
+		}
+	}
+	sort.SliceStable(disks, func(i, j int) bool {
+		if disks[i].Bus != disks[j].Bus {
+			return disks[i].Bus < disks[j].Bus
+		}
+		return disks[i].Index < disks[j].Index
+	})
+	for i, disk := range disks {
+		pvc, found := pvcMap[disk.ID]
+		if !found {
+			continue
+		}
+		volumeName := fmt.Sprintf("vol-%v", i)
+		volume := cnv.Volume{
+			Name: volumeName,
+			VolumeSource: cnv.VolumeSource{
+				PersistentVolumeClaim: &cnv.PersistentVolumeClaimVolumeSource{
+					PersistentVolumeClaimVolumeSource: core.PersistentVolumeClaimVolumeSource{
+						ClaimName: pvc.Name,
+					},
+				},
+			},
+		}
+		kubevirtDisk := cnv.Disk{
+			Name: volumeName,
+			DiskDevice: cnv.DiskDevice{
+				Disk: &cnv.DiskTarget{
+					Bus: cnv.DiskBus(diskBus(disk.Bus)),
+				},
+			},
+		}
+		if i == 0 {
+			var bootOrder uint = 1
+			kubevirtDisk.BootOrder = &bootOrder
+		}
+		kVolumes = append(kVolumes, volume)
+		kDisks = append(kDisks, kubevirtDisk)
+	}
+	object.Template.Spec.Volumes = kVolumes
+	object.Template.Spec.Domain.Devices.Disks = kDisks
+}
+
+// Build tasks.
+func (r *Builder) Tasks(vmRef ref.Ref) (list []*plan.Task, err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	for _, disk := range vm.Disks {
+		if disk.DeviceType != DeviceDisk {
+			continue
+		}
+		mB := disk.Capacity / 0x100000
+		list = append(
+			list,
+			&plan.Task{
+				Name: disk.ID,
+				Progress: libitr.Progress{
+					Total: mB,
+				},
+				Annotations: map[string]string{
+					"unit": "MB",
+				},
+			})
+	}
+
+	return
+}
+
+func (r *Builder) PreferenceName(vmRef ref.Ref, configMap *core.ConfigMap) (name string, err error) {
+	// The guest OS is not reported by Prism so we cannot get the corresponding preference.
+	err = liberr.New("preferences are not used by this provider")
+	return
+}
+
+// Build the cloud-init data of the VM guest customization.
+// The user data is reported base64 encoded.
+func (r *Builder) CloudInit(vmRef ref.Ref) (cloudInit *planbase.CloudInit, err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	if vm.UserData == "" {
+		return
+	}
+	userData := vm.UserData
+	if decoded, dErr := base64.StdEncoding.DecodeString(userData); dErr == nil {
+		userData = string(decoded)
+	}
+	cloudInit = &planbase.CloudInit{
+		UserData: userData,
+	}
+	return
+}
+
+// Get the ISO media of the source VM CD-ROMs.
+// Not supported by the provider.
+func (r *Builder) CDROMs(vmRef ref.Ref) (media []planbase.CDROM, err error) {
+	return
+}
+
+// Get the provenance of the source VM.
+// The VM description, path and cluster path.
+func (r *Builder) Provenance(vmRef ref.Ref) (provenance *planbase.Provenance, err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	provenance = &planbase.Provenance{
+		Description: vm.Description,
+		Path:        vm.Path,
+	}
+	if vm.Cluster == "" {
+		return
+	}
+	cluster := &model.Cluster{}
+	err = r.Source.Inventory.Get(cluster, vm.Cluster)
+	if err != nil {
+		err = liberr.Wrap(err, "cluster", vm.Cluster)
+		return
+	}
+	provenance.Cluster = cluster.Path
+	return
+}
+
+// Get the source VM attributes.
+// Not supported by the provider.
+func (r *Builder) Attributes(vmRef ref.Ref) (attributes map[string]string, err error) {
+	return
+}
+
+func (r *Builder) TemplateLabels(vmRef ref.Ref) (labels map[string]string, err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+
+	os := Unknown
+
+	labels = make(map[string]string)
+	labels[fmt.Sprintf(TemplateOSLabel, os)] = "true"
+	labels[TemplateWorkloadLabel] = "true"
+	labels[TemplateFlavorLabel] = "true"
+
+	return
+}
+
+// Return a stable identifier for a DataVolume.
+func (r *Builder) ResolveDataVolumeIdentifier(dv *cdi.DataVolume) string {
+	return dv.ObjectMeta.Annotations[planbase.AnnDiskSource]
+}
+
+// Return a stable identifier for a PersistentDataVolume.
+func (r *Builder) ResolvePersistentVolumeClaimIdentifier(pvc *core.PersistentVolumeClaim) string {
+	return pvc.Annotations[planbase.AnnDiskSource]
+}
+
+// Build LUN PVs.
+func (r *Builder) LunPersistentVolumes(vmRef ref.Ref) (pvs []core.PersistentVolume, err error) {
+	// do nothing
+	return
+}
+
+// Build LUN PVCs.
+func (r *Builder) LunPersistentVolumeClaims(vmRef ref.Ref) (pvcs []core.PersistentVolumeClaim, err error) {
+	// do nothing
+	return
+}
+
+func (r *Builder) SupportsVolumePopulators() bool {
+	return false
+}
+
+func (r *Builder) PopulatorVolumes(vmRef ref.Ref, annotations map[string]string, secretName string) (pvcs []*core.PersistentVolumeClaim, err error) {
+	err = planbase.VolumePopulatorNotSupportedError
+	return
+}
+
+func (r *Builder) PopulatorTransferredBytes(persistentVolumeClaim *core.PersistentVolumeClaim) (transferredBytes int64, err error) {
+	err = planbase.VolumePopulatorNotSupportedError
+	return
+}
+
+func (r *Builder) SetPopulatorDataSourceLabels(vmRef ref.Ref, pvcs []*core.PersistentVolumeClaim) (err error) {
+	err = planbase.VolumePopulatorNotSupportedError
+	return
+}
+
+func (r *Builder) GetPopulatorTaskName(pvc *core.PersistentVolumeClaim) (taskName string, err error) {
+	err = planbase.VolumePopulatorNotSupportedError
+	return
+}
+
+// Get the (Prism Central) client.
+func (r *Builder) getClient() (client *libclient.Client, err error) {
+	if r.client != nil {
+		client = r.client
+		return
+	}
+	client = &libclient.Client{
+		URL:   r.Source.Provider.Spec.URL,
+		Log:   r.Log.WithName("client"),
+		Proxy: libutil.ProviderProxy(r.Source.Provider),
+		Dial:  libutil.ProviderDialer(r.Source.Provider, nil),
+	}
+	client.LoadOptionsFromSecret(r.Source.Secret)
+	r.client = client
+	return
+}
+
+// The destination disk bus of the source disk adapter.
+func diskBus(adapter string) string {
+	switch strings.ToUpper(adapter) {
+	case AdapterSCSI:
+		return Scsi
+	case AdapterSATA, AdapterIDE:
+		return Sata
+	default:
+		return Virtio
+	}
+}
@@ -0,0 +1,72 @@
+package nutanix
+
+import (
+	"testing"
+
+	planbase "github.com/kubev2v/forklift/pkg/controller/plan/adapter/base"
+	nutanix "github.com/kubev2v/forklift/pkg/controller/provider/model/nutanix"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/web/nutanix"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cnv "kubevirt.io/api/core/v1"
+)
+
+func TestMapDisks(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	builder := &Builder{}
+	vm := &model.VM{
+		Disks: []nutanix.Disk{
+			{ID: "d2", DeviceType: DeviceDisk, Bus: AdapterSCSI, Index: 1},
+			{ID: "cd", DeviceType: "CDROM", Bus: AdapterIDE, Index: 0},
+			{ID: "d1", DeviceType: DeviceDisk, Bus: AdapterSCSI, Index: 0},
+			{ID: "d3", DeviceType: DeviceDisk, Bus: AdapterSATA, Index: 0},
+		},
+	}
+	pvc := func(id string) *core.PersistentVolumeClaim {
+		return &core.PersistentVolumeClaim{
+			ObjectMeta: meta.ObjectMeta{
+				Name:        "pvc-" + id,
+				Annotations: map[string]string{planbase.AnnDiskSource: id},
+			},
+		}
+	}
+	object := &cnv.VirtualMachineSpec{Template: &cnv.VirtualMachineInstanceTemplateSpec{}}
+	builder.mapDisks(vm, []*core.PersistentVolumeClaim{pvc("d1"), pvc("d2"), pvc("d3")}, object)
+
+	volumes := object.Template.Spec.Volumes
+	disks := object.Template.Spec.Domain.Devices.Disks
+	g.Expect(volumes).To(gomega.HaveLen(3))
+	g.Expect(volumes[0].PersistentVolumeClaim.ClaimName).To(gomega.Equal("pvc-d3"))
+	g.Expect(volumes[1].PersistentVolumeClaim.ClaimName).To(gomega.Equal("pvc-d1"))
+	g.Expect(volumes[2].PersistentVolumeClaim.ClaimName).To(gomega.Equal("pvc-d2"))
+	g.Expect(disks[0].Disk.Bus).To(gomega.Equal(cnv.DiskBus(Sata)))
+	g.Expect(*disks[0].BootOrder).To(gomega.Equal(uint(1)))
+	g.Expect(disks[1].Disk.Bus).To(gomega.Equal(cnv.DiskBus(Scsi)))
+	g.Expect(disks[1].BootOrder).To(gomega.BeNil())
+}
+
+func TestMapFirmware(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	builder := &Builder{}
+	cases := map[string]bool{
+		Legacy:     false,
+		"":         false,
+		UEFI:       true,
+		SecureBoot: true,
+	}
+	for bootType, efi := range cases {
+		vm := &model.VM{BootType: bootType}
+		vm.ID = "id"
+		object := &cnv.VirtualMachineSpec{Template: &cnv.VirtualMachineInstanceTemplateSpec{}}
+		builder.mapFirmware(vm, object)
+		firmware := object.Template.Spec.Domain.Firmware
+		g.Expect(firmware.Serial).To(gomega.Equal("id"))
+		if efi {
+			g.Expect(firmware.Bootloader.EFI).ToNot(gomega.BeNil())
+			g.Expect(*firmware.Bootloader.EFI.SecureBoot).To(gomega.BeFalse())
+		} else {
+			g.Expect(firmware.Bootloader.BIOS).ToNot(gomega.BeNil())
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package nutanix
+
+import (
+	"fmt"
+
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	"github.com/kubev2v/forklift/pkg/controller/plan/util"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/web/nutanix"
+	libclient "github.com/kubev2v/forklift/pkg/lib/client/nutanix"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	libutil "github.com/kubev2v/forklift/pkg/lib/util"
+	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+)
+
+// Disk device types.
+const (
+	DeviceDisk = "DISK"
+)
+
+// Nutanix VM Client.
+// The VM disks are exported as (disk) images which are
+// imported (downloaded) by CDI. Only cold migration is
+// supported so the snapshot (precopy) methods are no-ops.
+type Client struct {
+	libclient.Client
+	Context *plancontext.Context
+}
+
+// Connect.
+func (r *Client) connect() (err error) {
+	r.URL = r.Context.Source.Provider.Spec.URL
+	r.Proxy = libutil.ProviderProxy(r.Context.Source.Provider)
+	r.Dial = libutil.ProviderDialer(r.Context.Source.Provider, nil)
+	r.LoadOptionsFromSecret(r.Context.Source.Secret)
+	err = r.Connect()
+	return
+}
+
+// Power on the source VM.
+func (r *Client) PowerOn(vmRef ref.Ref) (err error) {
+	err = r.SetPowerState(vmRef.ID, libclient.PowerOn)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+	return
+}
+
+// Power off the source VM.
+func (r *Client) PowerOff(vmRef ref.Ref) (err error) {
+	err = r.SetPowerState(vmRef.ID, libclient.PowerOff)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+	return
+}
+
+// Return the source VM's power state.
+func (r *Client) PowerState(vmRef ref.Ref) (state planapi.VMPowerState, err error) {
+	vm, err := r.GetVM(vmRef.ID)
+	if err != nil {
+		err = liberr.Wrap(err)
+		state = planapi.VMPowerStateUnknown
+		return
+	}
+	switch vm.Status.Resources.PowerState {
+	case libclient.PowerOn:
+		state = planapi.VMPowerStateOn
+	case libclient.PowerOff:
+		state = planapi.VMPowerStateOff
+	default:
+		state = planapi.VMPowerStateUnknown
+	}
+	return
+}
+
+// Return whether the source VM is powered off.
+func (r *Client) PoweredOff(vmRef ref.Ref) (off bool, err error) {
+	state, err := r.PowerState(vmRef)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	off = state == planapi.VMPowerStateOff
+	return
+}
+
+// Create a snapshot of the source VM.
+// No-op; warm migration is not supported.
+func (r *Client) CreateSnapshot(vmRef ref.Ref, hostsFunc util.HostsFunc) (snapshotId string, creationTaskId string, err error) {
+	return
+}
+
+// Remove a snapshot. No-op for this provider.
+func (r *Client) RemoveSnapshot(vmRef ref.Ref, snapshot string, hostsFunc util.HostsFunc) (removeTaskId string, err error) {
+	return
+}
+
+// Check if a snapshot is ready to transfer.
+func (r *Client) CheckSnapshotReady(vmRef ref.Ref, precopy planapi.Precopy, hosts util.HostsFunc) (ready bool, snapshotId string, err error) {
+	return
+}
+
+// CheckSnapshotRemove implements base.Client
+func (r *Client) CheckSnapshotRemove(vmRef ref.Ref, precopy planapi.Precopy, hosts util.HostsFunc) (bool, error) {
+	return false, nil
+}
+
+// Get disk deltas for a VM snapshot. No-op for this provider.
+func (r *Client) GetSnapshotDeltas(vmRef ref.Ref, snapshot string, hostsFunc util.HostsFunc) (s map[string]string, err error) {
+	return
+}
+
+// Set DataVolume checkpoints. No-op for this provider.
+func (r *Client) SetCheckpoints(vmRef ref.Ref, precopies []planapi.Precopy, datavolumes []cdi.DataVolume, final bool, hostsFunc util.HostsFunc) (err error) {
+	return
+}
+
+// Close connections to the provider API.
+func (r *Client) Close() {
+}
+
+// Detach disks. No-op for this provider.
+func (r *Client) DetachDisks(vmRef ref.Ref) (err error) {
+	return
+}
+
+// Export the VM disks as images.
+// Ready when all of the images have been created.
+func (r *Client) PreTransferActions(vmRef ref.Ref) (ready bool, err error) {
+	vm, err := r.getVM(vmRef)
+	if err != nil {
+		return
+	}
+	ready = true
+	for _, disk := range vm.Disks {
+		if disk.DeviceType != DeviceDisk {
+			continue
+		}
+		name := ImageName(vm.ID, disk.ID)
+		var image *libclient.Image
+		image, err = r.FindImage(name)
+		if err != nil {
+			err = liberr.Wrap(err, "image", name)
+			return
+		}
+		if image == nil {
+			_, err = r.CreateImage(name, disk.ID)
+			if err != nil {
+				err = liberr.Wrap(err, "image", name)
+				return
+			}
+			r.Log.Info(
+				"Creating the disk image.",
+				"vm",
+				vm.Name,
+				"disk",
+				disk.ID,
+				"image",
+				name)
+			ready = false
+			continue
+		}
+		switch image.Status.State {
+		case libclient.ImageComplete:
+		case libclient.ImageError:
+			err = liberr.New(
+				"failed to create the disk image.",
+				"vm",
+				vm.Name,
+				"disk",
+				disk.ID,
+				"image",
+				name)
+			return
+		default:
+			ready = false
+		}
+	}
+	return
+}
+
+// Delete the disk images created for the migration.
+func (r *Client) Finalize(vms []*planapi.VMStatus, planName string) {
+	for _, vmStatus := range vms {
+		vm, err := r.getVM(vmStatus.Ref)
+		if err != nil {
+			r.Log.Error(err, "failed to find vm", "vm", vmStatus.Ref.String())
+			continue
+		}
+		for _, disk := range vm.Disks {
+			if disk.DeviceType != DeviceDisk {
+				continue
+			}
+			name := ImageName(vm.ID, disk.ID)
+			image, err := r.FindImage(name)
+			if err != nil {
+				r.Log.Error(err, "failed to find the disk image", "vm", vm.Name, "image", name)
+				continue
+			}
+			if image == nil {
+				continue
+			}
+			err = r.DeleteImage(image.Metadata.UUID)
+			if err != nil {
+				r.Log.Error(err, "failed to delete the disk image", "vm", vm.Name, "image", name)
+			}
+		}
+	}
+}
+
+// Find the VM in the inventory.
+func (r *Client) getVM(vmRef ref.Ref) (vm *model.VM, err error) {
+	vm = &model.VM{}
+	err = r.Context.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+	}
+	return
+}
+
+// The name of the image exported from the VM disk.
+func ImageName(vmID, diskID string) string {
+	return fmt.Sprintf("forklift-%s-%s", vmID, diskID)
+}
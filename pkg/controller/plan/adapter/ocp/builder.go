@@ -35,6 +35,8 @@ import (
 const (
 	Pod     = "pod"
 	Multus  = "multus"
+	SRIOV   = "sriov"
+	UDN     = "udn"
 	Ignored = "ignored"
 )
 
@@ -107,8 +109,9 @@ func (r *Builder) DataVolumes(vmRef ref.Ref, secret *v1.Secret, configMap *v1.Co
 		if url == "" {
 			return nil, liberr.Wrap(fmt.Errorf("failed to get export URL, available formats: %v", volume.Formats))
 		}
-		storageClassName := storageMap[*pvc.Spec.StorageClassName].StorageClass
-		dataVolume.Spec = *createDataVolumeSpec(size, storageClassName, url, configMap.Name, secret.Name)
+		destination := storageMap[*pvc.Spec.StorageClassName]
+		dataVolume.Spec = *createDataVolumeSpec(size, destination, url, configMap.Name, secret.Name)
+		dataVolume.Annotations = destination.Annotate(dataVolume.Annotations)
 
 		err = r.Destination.Client.Create(context.TODO(), dataVolume, &client.CreateOptions{})
 		if err != nil {
@@ -242,6 +245,30 @@ func (r *Builder) PreferenceName(vmRef ref.Ref, configMap *core.ConfigMap) (name
 	return
 }
 
+// CloudInit implements base.Builder
+func (r *Builder) CloudInit(vmRef ref.Ref) (cloudInit *planbase.CloudInit, err error) {
+	// The cloud-init volumes are part of the exported VM spec.
+	return
+}
+
+// Get the ISO media of the source VM CD-ROMs.
+// Not supported by the provider.
+func (r *Builder) CDROMs(vmRef ref.Ref) (media []planbase.CDROM, err error) {
+	return
+}
+
+// Get the provenance of the source VM.
+// Not supported by the provider.
+func (r *Builder) Provenance(vmRef ref.Ref) (provenance *planbase.Provenance, err error) {
+	return
+}
+
+// Get the source VM attributes.
+// Not supported by the provider.
+func (r *Builder) Attributes(vmRef ref.Ref) (attributes map[string]string, err error) {
+	return
+}
+
 // TemplateLabels implements base.Builder
 func (r *Builder) TemplateLabels(vmRef ref.Ref) (labels map[string]string, err error) {
 	// The VM is build from configuration, we don't need the label
@@ -483,7 +510,8 @@ func (r *Builder) mapNetworks(sourceVm *cnv.VirtualMachine, targetVmSpec *cnv.Vi
 
 	var kInterface *cnv.Interface
 
-	for _, network := range sourceVm.Spec.Template.Spec.Networks {
+	vmRef := ref.Ref{ID: string(sourceVm.UID), Name: sourceVm.Name}
+	for i, network := range sourceVm.Spec.Template.Spec.Networks {
 		targetNetwork := cnv.Network{Name: network.Name}
 
 		kInterface = interfacesMap[network.Name]
@@ -492,8 +520,10 @@ func (r *Builder) mapNetworks(sourceVm *cnv.VirtualMachine, targetVmSpec *cnv.Vi
 		switch {
 		case network.Multus != nil:
 			name, namespace := ocpclient.GetNetworkNameAndNamespace(network.Multus.NetworkName, &ref.Ref{Name: sourceVm.Name, Namespace: sourceVm.Namespace})
-			pair, found := r.Map.Network.FindNetworkByNameAndNamespace(namespace, name)
-			if !found {
+			pair := r.Map.Network.FindNetworkForNIC(vmRef, i, func(candidate *v1beta1.NetworkPair) bool {
+				return candidate.SourceNamed(namespace, name)
+			})
+			if pair == nil {
 				r.Log.Info("Network not found", "namespace", namespace, "name", name)
 				continue
 			}
@@ -513,10 +543,13 @@ func (r *Builder) mapNetworks(sourceVm *cnv.VirtualMachine, targetVmSpec *cnv.Vi
 			targetNetwork.Multus = &cnv.MultusNetwork{
 				NetworkName: fmt.Sprintf("%s/%s", pair.Destination.Namespace, pair.Destination.Name),
 			}
+			mapBinding(pair.Destination.Type, kInterface)
 
 		case network.Pod != nil:
-			pair, found := r.Map.Network.FindNetworkByType(Pod)
-			if !found {
+			pair := r.Map.Network.FindNetworkForNIC(vmRef, i, func(candidate *v1beta1.NetworkPair) bool {
+				return candidate.Source.Type == Pod
+			})
+			if pair == nil {
 				r.Log.Info("Network not found", "type", Pod)
 				continue
 			}
@@ -528,10 +561,12 @@ func (r *Builder) mapNetworks(sourceVm *cnv.VirtualMachine, targetVmSpec *cnv.Vi
 			}
 
 			// Check if the network is mapped to a multus network
-			if pair.Destination.Type == Multus {
+			switch pair.Destination.Type {
+			case Multus, SRIOV, UDN:
 				targetNetwork.Multus = &cnv.MultusNetwork{
 					NetworkName: fmt.Sprintf("%s/%s", pair.Destination.Namespace, pair.Destination.Name),
 				}
+				mapBinding(pair.Destination.Type, kInterface)
 				continue
 			}
 
@@ -549,6 +584,18 @@ func (r *Builder) mapNetworks(sourceVm *cnv.VirtualMachine, targetVmSpec *cnv.Vi
 	targetVmSpec.Template.Spec.Domain.Devices.Interfaces = interfaces
 }
 
+// Set the interface binding required by the destination network type.
+func mapBinding(networkType string, kInterface *cnv.Interface) {
+	switch networkType {
+	case SRIOV:
+		kInterface.InterfaceBindingMethod = cnv.InterfaceBindingMethod{SRIOV: &cnv.InterfaceSRIOV{}}
+		kInterface.Binding = nil
+	case UDN:
+		kInterface.InterfaceBindingMethod = cnv.InterfaceBindingMethod{Bridge: &cnv.InterfaceBridge{}}
+		kInterface.Binding = nil
+	}
+}
+
 func (r *Builder) getSourceVmFromDefinition(vme *export.VirtualMachineExport) (*cnv.VirtualMachine, error) {
 	var vmManifestUrl string
 	for _, manifest := range vme.Status.Links.External.Manifests {
@@ -639,8 +686,9 @@ func (r *Builder) getSourceVmFromDefinition(vme *export.VirtualMachineExport) (*
 	return nil, liberr.New("failed to find vm in manifest")
 }
 
-func createDataVolumeSpec(size resource.Quantity, storageClassName, url, configMap, secret string) *cdi.DataVolumeSpec {
-	return &cdi.DataVolumeSpec{
+func createDataVolumeSpec(size resource.Quantity, destination v1beta1.DestinationStorage, url, configMap, secret string) *cdi.DataVolumeSpec {
+	storageClassName := destination.StorageClass
+	dvSpec := &cdi.DataVolumeSpec{
 		Source: &cdi.DataVolumeSource{
 			HTTP: &cdi.DataVolumeSourceHTTP{
 				URL:                url,
@@ -657,6 +705,15 @@ func createDataVolumeSpec(size resource.Quantity, storageClassName, url, configM
 			StorageClassName: &storageClassName,
 		},
 	}
+	// set the access mode and volume mode if they were specified in the storage map.
+	// otherwise, let the storage profile decide the default values.
+	if accessModes := destination.GetAccessModes(); len(accessModes) > 0 {
+		dvSpec.Storage.AccessModes = accessModes
+	}
+	if destination.VolumeMode != "" {
+		dvSpec.Storage.VolumeMode = &destination.VolumeMode
+	}
+	return dvSpec
 }
 
 func pvcSourceName(namespace, name string) string {
@@ -2,6 +2,7 @@ package openstack
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"path"
@@ -209,6 +210,8 @@ const (
 	HwVideoRam           = "hw_video_ram"
 	HwRngModel           = "hw_rng_model"
 	VifMultiQueueEnabled = "hw_vif_multiqueue_enabled"
+	NumaNodes            = "hw_numa_nodes"
+	MemPageSize          = "hw_mem_page_size"
 )
 
 // Flavor ExtraSpecs
@@ -226,12 +229,23 @@ const (
 	FlavorVifMultiQueueEnabled = "hw:vif_multiqueue_enabled"
 	FlavorHwRng                = "hw_rng:allowed"
 	FlavorHwVideoRam           = "hw_video:ram_max_mb"
+	FlavorNumaNodes            = "hw:numa_nodes"
+	FlavorMemPageSize          = "hw:mem_page_size"
+)
+
+// Memory page sizes
+const (
+	MemPageSizeSmall = "small"
+	MemPageSizeAny   = "any"
+	MemPageSizeLarge = "large"
 )
 
 // Network types
 const (
 	Pod     = "pod"
 	Multus  = "multus"
+	SRIOV   = "sriov"
+	UDN     = "udn"
 	Ignored = "ignored"
 )
 
@@ -423,14 +437,81 @@ func (r *Builder) mapResources(vm *model.Workload, object *cnv.VirtualMachineSpe
 	}
 
 	// Set CPU Sockets/Cores/Threads and Memory requests
-	// TODO support NUMA, CPU pinning
 	object.Template.Spec.Domain.CPU.Sockets = r.getCpuCount(vm, CpuSockets)
 	object.Template.Spec.Domain.CPU.Cores = r.getCpuCount(vm, CpuCores)
 	object.Template.Spec.Domain.CPU.Threads = r.getCpuCount(vm, CpuThreads)
 
-	// TODO Support HugePages
 	memory := resource.NewQuantity(int64(vm.Flavor.RAM)*1024*1024, resource.BinarySI)
 	object.Template.Spec.Domain.Memory = &cnv.Memory{Guest: memory}
+
+	r.mapPlacement(vm, object, cpuPolicy)
+}
+
+// Map the NUMA topology and the memory page size when
+// the CPU placement is preserved by the plan.
+func (r *Builder) mapPlacement(vm *model.Workload, object *cnv.VirtualMachineSpec, cpuPolicy string) {
+	if !r.Plan.Spec.PreserveCPUPlacement {
+		return
+	}
+	placement := utils.CPUPlacement{
+		Dedicated: cpuPolicy == CpuPolicyDedicated,
+	}
+	if _, found := vm.Flavor.ExtraSpecs[FlavorNumaNodes]; found {
+		placement.NUMA = true
+	} else if _, found := vm.Image.Properties[NumaNodes]; found {
+		placement.NUMA = true
+	}
+	pageSize, found := vm.Flavor.ExtraSpecs[FlavorMemPageSize]
+	if !found {
+		pageSize, _ = vm.Image.Properties[MemPageSize].(string)
+	}
+	if pageSize != "" {
+		if size, parsed := memPageSize(pageSize); parsed {
+			placement.HugePageSize = size
+		} else {
+			r.Log.Info("Ignoring the memory page size.",
+				"vm",
+				vm.Name,
+				"pageSize",
+				pageSize)
+		}
+	}
+	placement.ApplyTo(object)
+}
+
+// Hugepage size of the memory page size. The page size is either
+// `small`, `any`, `large` or a size (in KiB unless suffixed by KB, MB or GB).
+// Small pages are not hugepages and the large pages are mapped to 2Mi pages.
+func memPageSize(pageSize string) (size string, parsed bool) {
+	switch strings.ToLower(pageSize) {
+	case MemPageSizeSmall, MemPageSizeAny:
+		parsed = true
+		return
+	case MemPageSizeLarge:
+		size = "2Mi"
+		parsed = true
+		return
+	}
+	kib := int64(1)
+	value := strings.ToUpper(pageSize)
+	for suffix, multiplier := range map[string]int64{"KB": 1, "MB": 1024, "GB": 1024 * 1024} {
+		if strings.HasSuffix(value, suffix) {
+			value = strings.TrimSuffix(value, suffix)
+			kib = multiplier
+			break
+		}
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n <= 0 {
+		return
+	}
+	kib *= n
+	// Pages up to 4KiB are not hugepages.
+	if kib > 4 {
+		size = utils.HugePageSize(kib)
+	}
+	parsed = true
+	return
 }
 
 func (r *Builder) getCpuCount(vm *model.Workload, imageCpuProperty string) (count uint32) {
@@ -606,17 +687,14 @@ func (r *Builder) mapNetworks(vm *model.Workload, object *cnv.VirtualMachineSpec
 						break
 					}
 				}
-				var networkPair *api.NetworkPair
-				networkMaps := r.Context.Map.Network.Spec.Map
-				found := false
-				for i := range networkMaps {
-					networkPair = &networkMaps[i]
-					if networkPair.Source.ID == vmNetworkID {
-						found = true
-						break
-					}
-				}
-				if !found {
+				// The addresses are not ordered, NIC scoped mappings do not apply.
+				networkPair := r.Context.Map.Network.FindNetworkForNIC(
+					ref.Ref{ID: vm.ID, Name: vm.Name},
+					-1,
+					func(candidate *api.NetworkPair) bool {
+						return candidate.Source.ID == vmNetworkID
+					})
+				if networkPair == nil {
 					err = liberr.New("no network map for vm network", "network", vmNetworkID)
 					return
 				}
@@ -666,13 +744,20 @@ func (r *Builder) mapNetworks(vm *model.Workload, object *cnv.VirtualMachineSpec
 				case Pod:
 					kNetwork.Pod = &cnv.PodNetwork{}
 					kInterface.Masquerade = &cnv.InterfaceMasquerade{}
-				case Multus:
+				case Multus, UDN:
 					kNetwork.Multus = &cnv.MultusNetwork{
 						NetworkName: path.Join(
 							networkPair.Destination.Namespace,
 							networkPair.Destination.Name),
 					}
 					kInterface.Bridge = &cnv.InterfaceBridge{}
+				case SRIOV:
+					kNetwork.Multus = &cnv.MultusNetwork{
+						NetworkName: path.Join(
+							networkPair.Destination.Namespace,
+							networkPair.Destination.Name),
+					}
+					kInterface.SRIOV = &cnv.InterfaceSRIOV{}
 				}
 				kNetworks = append(kNetworks, kNetwork)
 				kInterfaces = append(kInterfaces, kInterface)
@@ -771,6 +856,25 @@ func (r *Builder) PreferenceName(vmRef ref.Ref, configMap *core.ConfigMap) (name
 	return
 }
 
+// Build the cloud-init data of the (base64 encoded) server user data.
+func (r *Builder) CloudInit(vmRef ref.Ref) (cloudInit *planbase.CloudInit, err error) {
+	vm := &model.Workload{}
+	if err = r.Source.Inventory.Find(vm, vmRef); err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	if vm.UserData == "" {
+		return
+	}
+	userData, err := base64.StdEncoding.DecodeString(vm.UserData)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	cloudInit = &planbase.CloudInit{UserData: string(userData)}
+	return
+}
+
 func (r *Builder) getOs(vm *model.Workload) (os, version, distro string) {
 	if osDistro, ok := vm.Image.Properties[OsDistro]; ok {
 		distro = osDistro.(string)
@@ -849,6 +953,40 @@ func getTemplateOs(os, version, distro string) string {
 	return os
 }
 
+// Get the ISO media of the source VM CD-ROMs.
+// Not supported by the provider.
+func (r *Builder) CDROMs(vmRef ref.Ref) (media []planbase.CDROM, err error) {
+	return
+}
+
+// Get the provenance of the source VM.
+// The server path (project).
+func (r *Builder) Provenance(vmRef ref.Ref) (provenance *planbase.Provenance, err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	provenance = &planbase.Provenance{
+		Path: vm.Path,
+	}
+	return
+}
+
+// Get the source VM attributes.
+// The server metadata.
+func (r *Builder) Attributes(vmRef ref.Ref) (attributes map[string]string, err error) {
+	vm := &model.Workload{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	attributes = vm.Metadata
+	return
+}
+
 // Build tasks.
 func (r *Builder) TemplateLabels(vmRef ref.Ref) (labels map[string]string, err error) {
 	vm := &model.Workload{}
@@ -1014,20 +1152,20 @@ func (r *Builder) ensureVolumePopulatorPVC(workload *model.Workload, image *mode
 			return
 		}
 
-		var storageClassName string
+		var destination api.DestinationStorage
 
 		// VM is image based, look for a glance key in the mapping
 		if workload.ImageID != "" {
 			// At this point the StorageMap has been validated, and the VM has to be fully mapped
 			for _, storageMap := range mapList {
 				if storageMap.Source.Name == api.GlanceSource {
-					storageClassName = storageMap.Destination.StorageClass
+					destination = storageMap.Destination
 				}
 			}
 		} else {
 			// VM has a volume, look for the volume type in the mapping
 			if volumeType := r.getVolumeType(workload, originalVolumeDiskId); volumeType != "" {
-				storageClassName, err = r.getStorageClassName(workload, volumeType)
+				destination, err = r.getDestinationStorage(workload, volumeType)
 				if err != nil {
 					err = liberr.Wrap(err)
 					return
@@ -1035,7 +1173,7 @@ func (r *Builder) ensureVolumePopulatorPVC(workload *model.Workload, image *mode
 			}
 		}
 
-		if pvc, err = r.persistentVolumeClaimWithSourceRef(*image, storageClassName, populatorName, annotations, workload.ID); err != nil {
+		if pvc, err = r.persistentVolumeClaimWithSourceRef(*image, destination, populatorName, annotations, workload.ID); err != nil {
 			err = liberr.Wrap(err)
 			return
 		}
@@ -1121,7 +1259,7 @@ func (r *Builder) getVolumeType(workload *model.Workload, volumeID string) (volu
 	return
 }
 
-func (r *Builder) getStorageClassName(workload *model.Workload, volumeTypeName string) (storageClassName string, err error) {
+func (r *Builder) getDestinationStorage(workload *model.Workload, volumeTypeName string) (destination api.DestinationStorage, err error) {
 	var volumeTypeID string
 	for _, volumeType := range workload.VolumeTypes {
 		if volumeTypeName == volumeType.Name {
@@ -1135,10 +1273,10 @@ func (r *Builder) getStorageClassName(workload *model.Workload, volumeTypeName s
 	}
 	for _, storageMap := range r.Context.Map.Storage.Spec.Map {
 		if storageMap.Source.ID == volumeTypeID || storageMap.Source.Name == volumeTypeName {
-			storageClassName = storageMap.Destination.StorageClass
+			destination = storageMap.Destination
 		}
 	}
-	if storageClassName == "" {
+	if destination.StorageClass == "" {
 		err = liberr.New("no storage class map found for volume type", "volumeTypeID", volumeTypeID)
 		r.Log.Trace(err)
 		return
@@ -1227,12 +1365,13 @@ func (r *Builder) getVolumePopulatorPVC(imageID string) (populatorPvc *core.Pers
 }
 
 func (r *Builder) persistentVolumeClaimWithSourceRef(image model.Image,
-	storageClassName string,
+	destination api.DestinationStorage,
 	populatorName string,
 	annotations map[string]string,
 	vmID string) (pvc *core.PersistentVolumeClaim, err error) {
 
 	apiGroup := "forklift.konveyor.io"
+	storageClassName := destination.StorageClass
 	virtualSize := image.VirtualSize
 	// virtual_size may not always be available
 	if virtualSize == 0 {
@@ -1245,7 +1384,19 @@ func (r *Builder) persistentVolumeClaimWithSourceRef(image model.Image,
 		err = liberr.Wrap(err)
 		return
 	}
-	virtualSize = utils.CalculateSpaceWithOverhead(virtualSize, volumeMode)
+	// The storage map takes precedence over the storage profile defaults.
+	if pinned := destination.GetAccessModes(); len(pinned) > 0 {
+		accessModes = pinned
+	}
+	if destination.VolumeMode != "" {
+		volumeMode = &destination.VolumeMode
+	}
+	sizing, err := utils.GetStorageClassSizing(r.Destination.Client, storageClassName)
+	if err != nil {
+		return
+	}
+	// The populator writes the image as is, it is converted later.
+	virtualSize = sizing.VolumeSize(virtualSize, image.DiskFormat, volumeMode)
 
 	// The image might be a VM Snapshot Image and has no volume associated to it
 	if originalVolumeDiskId, ok := image.Properties["forklift_original_volume_id"]; ok {
@@ -1257,6 +1408,7 @@ func (r *Builder) persistentVolumeClaimWithSourceRef(image model.Image,
 	} else {
 		r.Log.Error(nil, "the image has no volume or vm snapshot associated to it", "image", image.Name)
 	}
+	annotations = destination.Annotate(annotations)
 
 	pvc = &core.PersistentVolumeClaim{
 		ObjectMeta: meta.ObjectMeta{
@@ -16,6 +16,21 @@ var _ = Describe("OpenStack builder", func() {
 		Entry("windows2022", Windows, "2022", Windows, "windows.2k22.virtio"),
 		Entry("ubuntu 22", Ubuntu, "22.04.3", Ubuntu, "ubuntu"),
 	)
+
+	DescribeTable("should map the memory page size", func(pageSize, size string, parsed bool) {
+		actual, ok := memPageSize(pageSize)
+		Expect(ok).To(Equal(parsed))
+		Expect(actual).To(Equal(size))
+	},
+		Entry("small", "small", "", true),
+		Entry("any", "any", "", true),
+		Entry("large", "large", "2Mi", true),
+		Entry("KiB", "2048", "2Mi", true),
+		Entry("MB", "2MB", "2Mi", true),
+		Entry("GB", "1GB", "1Gi", true),
+		Entry("4KiB", "4", "", true),
+		Entry("invalid", "huge", "", false),
+	)
 })
 
 var _ = Describe("OpenStack Glance const test", func() {
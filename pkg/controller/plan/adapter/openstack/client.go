@@ -12,6 +12,7 @@ import (
 	model "github.com/kubev2v/forklift/pkg/controller/provider/web/openstack"
 	libclient "github.com/kubev2v/forklift/pkg/lib/client/openstack"
 	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	libutil "github.com/kubev2v/forklift/pkg/lib/util"
 	"github.com/kubev2v/forklift/pkg/settings"
 	"k8s.io/apimachinery/pkg/util/wait"
 	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
@@ -48,6 +49,8 @@ type Client struct {
 // Connect.
 func (r *Client) connect() (err error) {
 	r.URL = r.Context.Source.Provider.Spec.URL
+	r.Proxy = libutil.ProviderProxy(r.Context.Source.Provider)
+	r.Dial = libutil.ProviderDialer(r.Context.Source.Provider, nil)
 	r.LoadOptionsFromSecret(r.Context.Source.Secret)
 	err = r.Connect()
 	return
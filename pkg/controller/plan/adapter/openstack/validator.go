@@ -1,8 +1,12 @@
 package openstack
 
 import (
+	"strings"
+
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
 	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	planbase "github.com/kubev2v/forklift/pkg/controller/plan/adapter/base"
+	"github.com/kubev2v/forklift/pkg/controller/plan/util"
 	"github.com/kubev2v/forklift/pkg/controller/provider/web"
 	model "github.com/kubev2v/forklift/pkg/controller/provider/web/openstack"
 	liberr "github.com/kubev2v/forklift/pkg/lib/error"
@@ -96,15 +100,11 @@ func (r *Validator) PodNetwork(vmRef ref.Ref) (ok bool, err error) {
 		return
 	}
 
-	mapping := r.plan.Referenced.Map.Network.Spec.Map
 	podMapped := 0
-	for i := range mapping {
-		mapped := &mapping[i]
-		ref := mapped.Source
-		for _, network := range vm.Networks {
-			if ref.ID == network.ID && mapped.Destination.Type == "Pod" {
-				podMapped++
-			}
+	for _, network := range vm.Networks {
+		mapped := r.findNetworkMapping(vm, network.ID)
+		if mapped != nil && mapped.Destination.Type == "Pod" {
+			podMapped++
 		}
 	}
 
@@ -127,3 +127,156 @@ func (r *Validator) StaticIPs(vmRef ref.Ref) (bool, error) {
 func (r *Validator) ChangeTrackingEnabled(vmRef ref.Ref) (bool, error) {
 	return true, nil
 }
+
+// NO-OP
+func (r *Validator) CPUModel(vmRef ref.Ref) (cpuModel string, features []string, err error) {
+	return
+}
+
+// Return the guest OS as "<os_distro> <os_version>" from the image properties.
+func (r *Validator) GuestOS(vmRef ref.Ref) (ids []string, err error) {
+	vm := &model.Workload{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	distro, _ := vm.Image.Properties[OsDistro].(string)
+	version, _ := vm.Image.Properties[OsVersion].(string)
+	if distro != "" {
+		ids = append(ids, strings.TrimSpace(distro+" "+version))
+	}
+	return
+}
+
+// Return the VM NICs with the destination networks they are mapped to.
+func (r *Validator) NICs(vmRef ref.Ref) (nics []planbase.NIC, err error) {
+	if r.plan.Referenced.Map.Network == nil {
+		return
+	}
+	vm := &model.Workload{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+
+	for vmNetworkName, vmAddresses := range vm.Addresses {
+		var mapped *api.NetworkPair
+		for _, network := range vm.Networks {
+			if network.Name == vmNetworkName {
+				mapped = r.findNetworkMapping(vm, network.ID)
+				break
+			}
+		}
+		if mapped == nil {
+			continue
+		}
+		addresses, ok := vmAddresses.([]interface{})
+		if !ok {
+			continue
+		}
+		for _, address := range addresses {
+			m, ok := address.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if ipType, ok := m["OS-EXT-IPS:type"].(string); ok && ipType == "floating" {
+				continue
+			}
+			if mac, ok := m["OS-EXT-IPS-MAC:mac_addr"].(string); ok {
+				nics = append(nics, planbase.NIC{MAC: mac, Destination: mapped.Destination})
+			}
+		}
+	}
+	return
+}
+
+// Find the most specific network mapping of the VM network.
+// The addresses are not ordered, NIC scoped mappings do not apply.
+func (r *Validator) findNetworkMapping(vm *model.Workload, networkID string) *api.NetworkPair {
+	return r.plan.Referenced.Map.Network.FindNetworkForNIC(
+		ref.Ref{ID: vm.ID, Name: vm.Name},
+		-1,
+		func(candidate *api.NetworkPair) bool {
+			return candidate.Source.ID == networkID
+		})
+}
+
+// NO-OP
+func (r *Validator) PersistentTPM(vmRef ref.Ref) (persistent bool, err error) {
+	return
+}
+
+// NO-OP
+func (r *Validator) PassthroughDevices(vmRef ref.Ref) (profiles []string, err error) {
+	return
+}
+
+// NO-OP
+func (r *Validator) RDMDisks(vmRef ref.Ref, client client.Client) (unmapped []string, err error) {
+	return
+}
+
+// NO-OP
+func (r *Validator) IndependentDisks(vmRef ref.Ref) (disks []string, err error) {
+	return
+}
+
+// NO-OP
+func (r *Validator) UnmigratedBootDevices(vmRef ref.Ref) (devices []string, err error) {
+	return
+}
+
+// NO-OP
+func (r *Validator) AffinityRules(vmRef ref.Ref) (rules []util.AffinityRule, err error) {
+	return
+}
+
+// Return the CPU, memory and disk capacity (by mapped storage class) of the VM.
+func (r *Validator) Demand(vmRef ref.Ref) (demand *planbase.Demand, err error) {
+	vm := &model.Workload{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	demand = &planbase.Demand{
+		CPU:     int64(vm.Flavor.VCPUs),
+		Memory:  int64(vm.Flavor.RAM) * 1024 * 1024,
+		Storage: map[string]int64{},
+	}
+	if r.plan.Referenced.Map.Storage == nil {
+		return
+	}
+	storageClass := func(id, name string) string {
+		for _, mapped := range r.plan.Referenced.Map.Storage.Spec.Map {
+			if (id != "" && mapped.Source.ID == id) || mapped.Source.Name == name {
+				return mapped.Destination.StorageClass
+			}
+		}
+		return ""
+	}
+	if vm.ImageID != "" {
+		size := vm.Image.VirtualSize
+		if size == 0 {
+			size = vm.Image.SizeBytes
+		}
+		if class := storageClass("", api.GlanceSource); class != "" {
+			demand.Storage[class] += size
+		}
+	}
+	for _, volume := range vm.Volumes {
+		typeID := ""
+		for _, volumeType := range vm.VolumeTypes {
+			if volumeType.Name == volume.VolumeType {
+				typeID = volumeType.ID
+				break
+			}
+		}
+		if class := storageClass(typeID, volume.VolumeType); class != "" {
+			demand.Storage[class] += int64(volume.Size) * 1024 * 1024 * 1024
+		}
+	}
+	return
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
 	planbase "github.com/kubev2v/forklift/pkg/controller/plan/adapter/base"
 	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	utils "github.com/kubev2v/forklift/pkg/controller/plan/util"
 	"github.com/kubev2v/forklift/pkg/controller/provider/model/ova"
 	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
 	"github.com/kubev2v/forklift/pkg/controller/provider/web/ocp"
@@ -47,6 +48,8 @@ const (
 const (
 	Pod     = "pod"
 	Multus  = "multus"
+	SRIOV   = "sriov"
+	UDN     = "udn"
 	Ignored = "ignored"
 )
 
@@ -194,7 +197,7 @@ func (r *Builder) mapDataVolume(disk ova.Disk, destination v1beta1.DestinationSt
 		Storage: &cdi.StorageSpec{
 			Resources: core.VolumeResourceRequirements{
 				Requests: core.ResourceList{
-					core.ResourceStorage: *resource.NewQuantity(diskSize, resource.BinarySI),
+					core.ResourceStorage: *resource.NewQuantity(utils.DataVolumeSize(diskSize, utils.FormatRaw), resource.BinarySI),
 				},
 			},
 			StorageClassName: &storageClass,
@@ -202,8 +205,8 @@ func (r *Builder) mapDataVolume(disk ova.Disk, destination v1beta1.DestinationSt
 	}
 	// set the access mode and volume mode if they were specified in the storage map.
 	// otherwise, let the storage profile decide the default values.
-	if destination.AccessMode != "" {
-		dvSpec.Storage.AccessModes = []core.PersistentVolumeAccessMode{destination.AccessMode}
+	if accessModes := destination.GetAccessModes(); len(accessModes) > 0 {
+		dvSpec.Storage.AccessModes = accessModes
 	}
 	if destination.VolumeMode != "" {
 		dvSpec.Storage.VolumeMode = &destination.VolumeMode
@@ -212,6 +215,7 @@ func (r *Builder) mapDataVolume(disk ova.Disk, destination v1beta1.DestinationSt
 	dv = dvTemplate.DeepCopy()
 	dv.Spec = dvSpec
 	updateDataVolumeAnnotations(dv, &disk)
+	dv.ObjectMeta.Annotations = destination.Annotate(dv.ObjectMeta.Annotations)
 	return
 }
 
@@ -268,6 +272,11 @@ func (r *Builder) mapNetworks(vm *model.VM, object *cnv.VirtualMachineSpec) (err
 	var kInterfaces []cnv.Interface
 
 	numNetworks := 0
+	networks, err := r.mappedNetworks()
+	if err != nil {
+		return
+	}
+	vmRef := ref.Ref{ID: vm.ID, Name: vm.Name}
 	netMapIn := r.Context.Map.Network.Spec.Map
 	for i := range netMapIn {
 		mapped := &netMapIn[i]
@@ -277,17 +286,17 @@ func (r *Builder) mapNetworks(vm *model.VM, object *cnv.VirtualMachineSpec) (err
 			continue
 		}
 
-		ref := mapped.Source
-		network := &model.Network{}
-		fErr := r.Source.Inventory.Find(network, ref)
-		if fErr != nil {
-			err = fErr
-			return
-		}
-
 		needed := []ova.NIC{}
-		for _, nic := range vm.NICs {
-			if nic.Network == network.Name {
+		for index, nic := range vm.NICs {
+			if nic.Network != networks[mapped] {
+				continue
+			}
+			// Skip NICs with a more specific mapping.
+			best := r.Context.Map.Network.FindNetworkForNIC(vmRef, index, func(candidate *v1beta1.NetworkPair) bool {
+				name, found := networks[candidate]
+				return found && name == nic.Network
+			})
+			if best == mapped {
 				needed = append(needed, nic)
 			}
 		}
@@ -309,11 +318,16 @@ func (r *Builder) mapNetworks(vm *model.VM, object *cnv.VirtualMachineSpec) (err
 			case Pod:
 				kNetwork.Pod = &cnv.PodNetwork{}
 				kInterface.Masquerade = &cnv.InterfaceMasquerade{}
-			case Multus:
+			case Multus, UDN:
 				kNetwork.Multus = &cnv.MultusNetwork{
 					NetworkName: path.Join(mapped.Destination.Namespace, mapped.Destination.Name),
 				}
 				kInterface.Bridge = &cnv.InterfaceBridge{}
+			case SRIOV:
+				kNetwork.Multus = &cnv.MultusNetwork{
+					NetworkName: path.Join(mapped.Destination.Namespace, mapped.Destination.Name),
+				}
+				kInterface.SRIOV = &cnv.InterfaceSRIOV{}
 			}
 			kNetworks = append(kNetworks, kNetwork)
 			kInterfaces = append(kInterfaces, kInterface)
@@ -324,6 +338,26 @@ func (r *Builder) mapNetworks(vm *model.VM, object *cnv.VirtualMachineSpec) (err
 	return
 }
 
+// Resolve the source network name of each network mapping.
+func (r *Builder) mappedNetworks() (networks map[*v1beta1.NetworkPair]string, err error) {
+	networks = make(map[*v1beta1.NetworkPair]string)
+	netMapIn := r.Context.Map.Network.Spec.Map
+	for i := range netMapIn {
+		mapped := &netMapIn[i]
+		network := &model.Network{}
+		fErr := r.Source.Inventory.Find(network, mapped.Source)
+		if fErr != nil {
+			if mapped.Destination.Type == Ignored {
+				continue
+			}
+			err = fErr
+			return
+		}
+		networks[mapped] = network.Name
+	}
+	return
+}
+
 func (r *Builder) mapInput(object *cnv.VirtualMachineSpec) {
 	tablet := cnv.Input{
 		Type: Tablet,
@@ -464,6 +498,38 @@ func (r *Builder) PreferenceName(vmRef ref.Ref, configMap *core.ConfigMap) (name
 	return
 }
 
+func (r *Builder) CloudInit(vmRef ref.Ref) (cloudInit *planbase.CloudInit, err error) {
+	// The OVA does not carry the guest customization.
+	return
+}
+
+// Get the ISO media of the source VM CD-ROMs.
+// Not supported by the provider.
+func (r *Builder) CDROMs(vmRef ref.Ref) (media []planbase.CDROM, err error) {
+	return
+}
+
+// Get the provenance of the source VM.
+// The path of the OVA file.
+func (r *Builder) Provenance(vmRef ref.Ref) (provenance *planbase.Provenance, err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	provenance = &planbase.Provenance{
+		Path: vm.OvaPath,
+	}
+	return
+}
+
+// Get the source VM attributes.
+// Not supported by the provider.
+func (r *Builder) Attributes(vmRef ref.Ref) (attributes map[string]string, err error) {
+	return
+}
+
 func (r *Builder) TemplateLabels(vmRef ref.Ref) (labels map[string]string, err error) {
 	vm := &model.VM{}
 	err = r.Source.Inventory.Find(vm, vmRef)
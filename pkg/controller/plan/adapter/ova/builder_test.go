@@ -0,0 +1,47 @@
+package ova
+
+import (
+	"testing"
+
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planbase "github.com/kubev2v/forklift/pkg/controller/plan/adapter/base"
+	"github.com/kubev2v/forklift/pkg/controller/provider/model/ova"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+)
+
+func TestMapDataVolume(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	builder := &Builder{}
+	disk := ova.Disk{FilePath: "/ova/vm", Capacity: 1}
+	disk.Name = "disk.vmdk"
+	template := &cdi.DataVolume{ObjectMeta: meta.ObjectMeta{Annotations: map[string]string{}}}
+
+	t.Run("storage profile defaults", func(t *testing.T) {
+		dv, err := builder.mapDataVolume(disk, v1beta1.DestinationStorage{StorageClass: "sc"}, template)
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		g.Expect(dv.Spec.Storage.AccessModes).To(gomega.BeEmpty())
+		g.Expect(dv.Spec.Storage.VolumeMode).To(gomega.BeNil())
+	})
+
+	t.Run("pinned by the mapping", func(t *testing.T) {
+		destination := v1beta1.DestinationStorage{
+			StorageClass: "sc",
+			VolumeMode:   core.PersistentVolumeBlock,
+			AccessMode:   core.ReadWriteOnce,
+			AccessModes:  []core.PersistentVolumeAccessMode{core.ReadWriteMany, core.ReadOnlyMany},
+			Annotations: map[string]string{
+				"example.com/tier":     "gold",
+				planbase.AnnDiskSource: "ignored",
+			},
+		}
+		dv, err := builder.mapDataVolume(disk, destination, template)
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		g.Expect(dv.Spec.Storage.AccessModes).To(gomega.Equal(destination.AccessModes))
+		g.Expect(*dv.Spec.Storage.VolumeMode).To(gomega.Equal(core.PersistentVolumeBlock))
+		g.Expect(dv.Annotations["example.com/tier"]).To(gomega.Equal("gold"))
+		g.Expect(dv.Annotations[planbase.AnnDiskSource]).To(gomega.Equal(getDiskFullPath(&disk)))
+	})
+}
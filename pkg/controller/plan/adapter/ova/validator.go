@@ -3,6 +3,8 @@ package ova
 import (
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
 	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	planbase "github.com/kubev2v/forklift/pkg/controller/plan/adapter/base"
+	"github.com/kubev2v/forklift/pkg/controller/plan/util"
 	"github.com/kubev2v/forklift/pkg/controller/provider/web"
 	model "github.com/kubev2v/forklift/pkg/controller/provider/web/ova"
 	liberr "github.com/kubev2v/forklift/pkg/lib/error"
@@ -66,22 +68,16 @@ func (r *Validator) PodNetwork(vmRef ref.Ref) (ok bool, err error) {
 		return
 	}
 
-	mapping := r.plan.Referenced.Map.Network.Spec.Map
 	podMapped := 0
-	for i := range mapping {
-		mapped := &mapping[i]
-		ref := mapped.Source
-		network := &model.Network{}
-		fErr := r.inventory.Find(network, ref)
+	for i, nic := range vm.NICs {
+		// TODO move from NIC name to NIC ID? ID should be unique
+		mapped, fErr := r.findNetworkMapping(vm, i, nic.Name)
 		if fErr != nil {
 			err = fErr
 			return
 		}
-		for _, nic := range vm.NICs {
-			// TODO move from NIC name to NIC ID? ID should be unique
-			if nic.Name == network.Name && mapped.Destination.Type == Pod {
-				podMapped++
-			}
+		if mapped != nil && mapped.Destination.Type == Pod {
+			podMapped++
 		}
 	}
 
@@ -131,3 +127,124 @@ func (r *Validator) ChangeTrackingEnabled(vmRef ref.Ref) (bool, error) {
 	// Validate that the vm has the change tracking enabled
 	return true, nil
 }
+
+// NO-OP
+func (r *Validator) CPUModel(vmRef ref.Ref) (cpuModel string, features []string, err error) {
+	return
+}
+
+// NO-OP
+func (r *Validator) GuestOS(vmRef ref.Ref) (ids []string, err error) {
+	return
+}
+
+// Return the VM NICs with the destination networks they are mapped to.
+func (r *Validator) NICs(vmRef ref.Ref) (nics []planbase.NIC, err error) {
+	if r.plan.Referenced.Map.Network == nil {
+		return
+	}
+	vm := &model.Workload{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+
+	for i, nic := range vm.NICs {
+		mapped, fErr := r.findNetworkMapping(vm, i, nic.Network)
+		if fErr != nil {
+			err = fErr
+			return
+		}
+		if mapped != nil {
+			nics = append(nics, planbase.NIC{MAC: nic.MAC, Destination: mapped.Destination})
+		}
+	}
+	return
+}
+
+// Find the most specific network mapping of the VM NIC
+// attached to the network with the specified name.
+func (r *Validator) findNetworkMapping(vm *model.Workload, index int, networkName string) (mapped *api.NetworkPair, err error) {
+	vmRef := ref.Ref{ID: vm.ID, Name: vm.Name}
+	mapped = r.plan.Referenced.Map.Network.FindNetworkForNIC(vmRef, index, func(candidate *api.NetworkPair) bool {
+		if err != nil {
+			return false
+		}
+		network := &model.Network{}
+		err = r.inventory.Find(network, candidate.Source)
+		if err != nil {
+			return false
+		}
+		return networkName == network.Name
+	})
+	if err != nil {
+		mapped = nil
+	}
+	return
+}
+
+// NO-OP
+func (r *Validator) PersistentTPM(vmRef ref.Ref) (persistent bool, err error) {
+	return
+}
+
+// NO-OP
+func (r *Validator) PassthroughDevices(vmRef ref.Ref) (profiles []string, err error) {
+	return
+}
+
+// NO-OP
+func (r *Validator) RDMDisks(vmRef ref.Ref, client client.Client) (unmapped []string, err error) {
+	return
+}
+
+// NO-OP
+func (r *Validator) IndependentDisks(vmRef ref.Ref) (disks []string, err error) {
+	return
+}
+
+// NO-OP
+func (r *Validator) UnmigratedBootDevices(vmRef ref.Ref) (devices []string, err error) {
+	return
+}
+
+// NO-OP
+func (r *Validator) AffinityRules(vmRef ref.Ref) (rules []util.AffinityRule, err error) {
+	return
+}
+
+// Return the CPU, memory and disk capacity (by mapped storage class) of the VM.
+func (r *Validator) Demand(vmRef ref.Ref) (demand *planbase.Demand, err error) {
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	memory, err := getResourceCapacity(int64(vm.MemoryMB), vm.MemoryUnits)
+	if err != nil {
+		return
+	}
+	demand = &planbase.Demand{
+		CPU:     int64(vm.CpuCount),
+		Memory:  memory,
+		Storage: map[string]int64{},
+	}
+	if r.plan.Referenced.Map.Storage == nil {
+		return
+	}
+	for _, disk := range vm.Disks {
+		mapped, found := r.plan.Referenced.Map.Storage.FindStorage(disk.ID)
+		if !found || mapped.Destination.StorageClass == "" {
+			continue
+		}
+		var capacity int64
+		capacity, err = getResourceCapacity(disk.Capacity, disk.CapacityAllocationUnits)
+		if err != nil {
+			return
+		}
+		demand.Storage[mapped.Destination.StorageClass] += capacity
+	}
+	return
+}
@@ -3,6 +3,7 @@ package ovirt
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/url"
 	"path"
 	"strconv"
@@ -21,6 +22,7 @@ import (
 	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
 	"github.com/kubev2v/forklift/pkg/controller/provider/web/ocp"
 	model "github.com/kubev2v/forklift/pkg/controller/provider/web/ovirt"
+	"github.com/kubev2v/forklift/pkg/controller/validation"
 	liberr "github.com/kubev2v/forklift/pkg/lib/error"
 	libitr "github.com/kubev2v/forklift/pkg/lib/itinerary"
 	core "k8s.io/api/core/v1"
@@ -30,6 +32,7 @@ import (
 	cnv "kubevirt.io/api/core/v1"
 	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
 )
 
 // BIOS types
@@ -53,13 +56,26 @@ const (
 	Tablet = "tablet"
 )
 
+// Cloud-init user data header.
+const (
+	CloudConfigHeader = "#cloud-config"
+)
+
 // Network types
 const (
 	Pod     = "pod"
 	Multus  = "multus"
+	SRIOV   = "sriov"
+	UDN     = "udn"
 	Ignored = "ignored"
 )
 
+// Custom properties
+const (
+	// Hugepage size (KiB) backing the VM memory.
+	HugePagesProperty = "hugepages"
+)
+
 // Template labels
 const (
 	TemplateOSLabel       = "os.template.kubevirt.io/%s"
@@ -215,7 +231,7 @@ func (r *Builder) DataVolumes(vmRef ref.Ref, secret *core.Secret, configMap *cor
 					Storage: &cdi.StorageSpec{
 						Resources: core.VolumeResourceRequirements{
 							Requests: core.ResourceList{
-								core.ResourceStorage: *resource.NewQuantity(size, resource.BinarySI),
+								core.ResourceStorage: *resource.NewQuantity(utils.DataVolumeSize(size, utils.FormatRaw), resource.BinarySI),
 							},
 						},
 						StorageClassName: &storageClass,
@@ -223,8 +239,8 @@ func (r *Builder) DataVolumes(vmRef ref.Ref, secret *core.Secret, configMap *cor
 				}
 				// set the access mode and volume mode if they were specified in the storage map.
 				// otherwise, let the storage profile decide the default values.
-				if mapped.Destination.AccessMode != "" {
-					dvSpec.Storage.AccessModes = []core.PersistentVolumeAccessMode{mapped.Destination.AccessMode}
+				if accessModes := mapped.Destination.GetAccessModes(); len(accessModes) > 0 {
+					dvSpec.Storage.AccessModes = accessModes
 				}
 				if mapped.Destination.VolumeMode != "" {
 					dvSpec.Storage.VolumeMode = &mapped.Destination.VolumeMode
@@ -236,6 +252,7 @@ func (r *Builder) DataVolumes(vmRef ref.Ref, secret *core.Secret, configMap *cor
 					dv.ObjectMeta.Annotations = make(map[string]string)
 				}
 				dv.ObjectMeta.Annotations[planbase.AnnDiskSource] = da.Disk.ID
+				dv.ObjectMeta.Annotations = mapped.Destination.Annotate(dv.ObjectMeta.Annotations)
 				dvs = append(dvs, *dv)
 			}
 		}
@@ -272,10 +289,15 @@ func (r *Builder) VirtualMachine(vmRef ref.Ref, object *cnv.VirtualMachineSpec,
 	if !usesInstanceType {
 		r.mapCPU(vm, object)
 		r.mapMemory(vm, object)
+		r.mapPlacement(vm, object)
 	}
 	r.mapClock(vm, object)
 	r.mapInput(object)
-	r.mapTpm(vm, object)
+	err = r.mapTpm(vm, object)
+	if err != nil {
+		return
+	}
+	utils.ApplyAffinity(object, r.Plan, vm.ID, clusterAffinityRules(&vm.Cluster, vm.ID))
 	err = r.mapNetworks(vm, object)
 	if err != nil {
 		return
@@ -289,6 +311,11 @@ func (r *Builder) mapNetworks(vm *model.Workload, object *cnv.VirtualMachineSpec
 	var kInterfaces []cnv.Interface
 
 	numNetworks := 0
+	networks, err := r.mappedNetworks()
+	if err != nil {
+		return
+	}
+	vmRef := ref.Ref{ID: vm.ID, Name: vm.Name}
 	netMapIn := r.Context.Map.Network.Spec.Map
 	for i := range netMapIn {
 		mapped := &netMapIn[i]
@@ -298,16 +325,17 @@ func (r *Builder) mapNetworks(vm *model.Workload, object *cnv.VirtualMachineSpec
 			continue
 		}
 
-		ref := mapped.Source
-		network := &model.Network{}
-		fErr := r.Source.Inventory.Find(network, ref)
-		if fErr != nil {
-			err = fErr
-			return
-		}
 		needed := []model.XNIC{}
-		for _, nic := range vm.NICs {
-			if nic.Profile.Network == network.ID {
+		for index, nic := range vm.NICs {
+			if nic.Profile.Network != networks[mapped] {
+				continue
+			}
+			// Skip NICs with a more specific mapping.
+			best := r.Context.Map.Network.FindNetworkForNIC(vmRef, index, func(candidate *api.NetworkPair) bool {
+				id, found := networks[candidate]
+				return found && id == nic.Profile.Network
+			})
+			if best == mapped {
 				needed = append(needed, nic)
 			}
 		}
@@ -329,15 +357,20 @@ func (r *Builder) mapNetworks(vm *model.Workload, object *cnv.VirtualMachineSpec
 			case Pod:
 				kNetwork.Pod = &cnv.PodNetwork{}
 				kInterface.Masquerade = &cnv.InterfaceMasquerade{}
-			case Multus:
+			case Multus, UDN:
 				kNetwork.Multus = &cnv.MultusNetwork{
 					NetworkName: path.Join(mapped.Destination.Namespace, mapped.Destination.Name),
 				}
-				if nic.Profile.PassThrough {
+				if nic.Profile.PassThrough && mapped.Destination.Type == Multus {
 					kInterface.SRIOV = &cnv.InterfaceSRIOV{}
 				} else {
 					kInterface.Bridge = &cnv.InterfaceBridge{}
 				}
+			case SRIOV:
+				kNetwork.Multus = &cnv.MultusNetwork{
+					NetworkName: path.Join(mapped.Destination.Namespace, mapped.Destination.Name),
+				}
+				kInterface.SRIOV = &cnv.InterfaceSRIOV{}
 			}
 			kNetworks = append(kNetworks, kNetwork)
 			kInterfaces = append(kInterfaces, kInterface)
@@ -348,6 +381,26 @@ func (r *Builder) mapNetworks(vm *model.Workload, object *cnv.VirtualMachineSpec
 	return
 }
 
+// Resolve the source network ID of each network mapping.
+func (r *Builder) mappedNetworks() (networks map[*api.NetworkPair]string, err error) {
+	networks = make(map[*api.NetworkPair]string)
+	netMapIn := r.Context.Map.Network.Spec.Map
+	for i := range netMapIn {
+		mapped := &netMapIn[i]
+		network := &model.Network{}
+		fErr := r.Source.Inventory.Find(network, mapped.Source)
+		if fErr != nil {
+			if mapped.Destination.Type == Ignored {
+				continue
+			}
+			err = fErr
+			return
+		}
+		networks[mapped] = network.ID
+	}
+	return
+}
+
 func (r *Builder) mapInput(object *cnv.VirtualMachineSpec) {
 	tablet := cnv.Input{
 		Type: Tablet,
@@ -384,11 +437,87 @@ func (r *Builder) mapCPU(vm *model.Workload, object *cnv.VirtualMachineSpec) {
 	if vm.CustomCpuModel != "" {
 		r.setCpuFlags(vm.CustomCpuModel, object)
 	} else if r.Plan.Spec.PreserveClusterCPUModel {
-		r.setCpuFlags(r.getClusterCpu(vm), object)
+		r.setCpuFlags(clusterCpu(vm), object)
 	}
 }
 
-func (r *Builder) getClusterCpu(vm *model.Workload) string {
+// Find the enabled affinity groups of the cluster covering the VM.
+// The VM rule of the group is (anti-)affinity by polarity and the
+// VM-host rule is reported separately.
+func clusterAffinityRules(cluster *model.Cluster, vmID string) (rules []utils.AffinityRule) {
+	for _, group := range cluster.AffinityGroups {
+		member := false
+		for _, id := range group.VMs {
+			if id == vmID {
+				member = true
+				break
+			}
+		}
+		if !member {
+			continue
+		}
+		if group.Enabled {
+			rule := utils.AffinityRule{
+				Name:      group.Name,
+				Kind:      utils.AntiAffinityKind,
+				Mandatory: group.Enforcing,
+				VMs:       group.VMs,
+			}
+			if group.Positive {
+				rule.Kind = utils.AffinityKind
+			}
+			rules = append(rules, rule)
+		}
+		if group.HostsEnabled {
+			rules = append(
+				rules,
+				utils.AffinityRule{
+					Name: group.Name,
+					Kind: utils.VMHostKind,
+					VMs:  group.VMs,
+				})
+		}
+	}
+	return
+}
+
+// Map the CPU placement when preserved by the plan.
+// CPU pinning is mapped to dedicated CPUs and the hugepages
+// custom property to hugepages.
+func (r *Builder) mapPlacement(vm *model.Workload, object *cnv.VirtualMachineSpec) {
+	if !r.Plan.Spec.PreserveCPUPlacement {
+		return
+	}
+	placement := utils.CPUPlacement{
+		Dedicated: len(vm.CpuAffinity) > 0 ||
+			(vm.CpuPinningPolicy != "" && vm.CpuPinningPolicy != model.None),
+		NUMA: len(vm.NumaNodeAffinity) > 0 || vm.CpuPinningPolicy == model.ResizeAndPin,
+	}
+	for _, property := range vm.Properties {
+		if property.Name != HugePagesProperty {
+			continue
+		}
+		if kib, err := strconv.ParseInt(property.Value, 10, 64); err == nil && kib > 0 {
+			placement.HugePageSize = utils.HugePageSize(kib)
+		} else {
+			r.Log.Info("Ignoring the hugepages custom property.",
+				"vm",
+				vm.Name,
+				"value",
+				property.Value)
+		}
+	}
+	placement.ApplyTo(object)
+}
+
+func (r *Builder) setCpuFlags(fullCpu string, object *cnv.VirtualMachineSpec) {
+	cpuModel, features := parseCpu(fullCpu)
+	object.Template.Spec.Domain.CPU.Model = cpuModel
+	object.Template.Spec.Domain.CPU.Features = append(object.Template.Spec.Domain.CPU.Features, features...)
+}
+
+// Find the CPU model and flags of the VM's cluster.
+func clusterCpu(vm *model.Workload) string {
 	var cpuAndFlags string
 	cpus := strings.Split(vm.ServerCpu.SystemOptionValue[0].Value, ";")
 	for _, values := range cpus {
@@ -400,18 +529,21 @@ func (r *Builder) getClusterCpu(vm *model.Workload) string {
 	return cpuAndFlags
 }
 
-func (r *Builder) setCpuFlags(fullCpu string, object *cnv.VirtualMachineSpec) {
+// Split an oVirt CPU definition (e.g. "Skylake-Client,+spec-ctrl,-hle")
+// into the CPU model and the list of feature flags.
+func parseCpu(fullCpu string) (cpuModel string, features []cnv.CPUFeature) {
 	cpuTypeAndFlags := strings.Split(fullCpu, ",")
-	object.Template.Spec.Domain.CPU.Model = cpuTypeAndFlags[0]
+	cpuModel = cpuTypeAndFlags[0]
 	for _, val := range cpuTypeAndFlags[1:] {
 		if flag, found := strings.CutPrefix(val, "+"); found {
-			object.Template.Spec.Domain.CPU.Features = append(object.Template.Spec.Domain.CPU.Features, cnv.CPUFeature{Name: flag, Policy: "require"})
+			features = append(features, cnv.CPUFeature{Name: flag, Policy: "require"})
 		} else if flag, found = strings.CutPrefix(val, "-"); found {
-			object.Template.Spec.Domain.CPU.Features = append(object.Template.Spec.Domain.CPU.Features, cnv.CPUFeature{Name: flag, Policy: "disable"})
+			features = append(features, cnv.CPUFeature{Name: flag, Policy: "disable"})
 		} else {
-			object.Template.Spec.Domain.CPU.Features = append(object.Template.Spec.Domain.CPU.Features, cnv.CPUFeature{Name: flag})
+			features = append(features, cnv.CPUFeature{Name: flag})
 		}
 	}
+	return
 }
 
 func (r *Builder) mapFirmware(vm *model.Workload, cluster *model.Cluster, object *cnv.VirtualMachineSpec) {
@@ -509,11 +641,17 @@ func (r *Builder) mapDisks(vm *model.Workload, persistentVolumeClaims []*core.Pe
 	object.Template.Spec.Domain.Devices.Disks = kDisks
 }
 
-func (r *Builder) mapTpm(vm *model.Workload, object *cnv.VirtualMachineSpec) {
-	if vm.OSType == "windows_2022" || vm.OSType == "windows_11" {
+func (r *Builder) mapTpm(vm *model.Workload, object *cnv.VirtualMachineSpec) (err error) {
+	overrides, err := validation.GuestOSOverrides(r.Client)
+	if err != nil {
+		return
+	}
+	guest := validation.FindGuestOS(api.OVirt, overrides, vm.OSType)
+	if guest.HasFlag(validation.GuestOSTpm) {
 		persistData := true
 		object.Template.Spec.Domain.Devices.TPM = &cnv.TPMDevice{Persistent: &persistData}
 	}
+	return
 }
 
 // Build tasks.
@@ -558,6 +696,104 @@ func (r *Builder) PreferenceName(vmRef ref.Ref, configMap *core.ConfigMap) (name
 	return
 }
 
+// Build the cloud-init data of the VM initialization.
+// The initialization of Windows VMs is a sysprep answer file
+// and is not carried over.
+func (r *Builder) CloudInit(vmRef ref.Ref) (cloudInit *planbase.CloudInit, err error) {
+	vm := &model.Workload{}
+	if err = r.Source.Inventory.Find(vm, vmRef); err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	if strings.Contains(vm.OSType, "windows") || vm.Initialization == (model.Initialization{}) {
+		return
+	}
+	userData, err := cloudConfig(&vm.Initialization)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	cloudInit = &planbase.CloudInit{UserData: userData}
+	return
+}
+
+// Cloud config of the initialization.
+// The custom script is merged the way oVirt does.
+func cloudConfig(initialization *model.Initialization) (userData string, err error) {
+	config := map[string]interface{}{}
+	if initialization.HostName != "" {
+		config["hostname"] = initialization.HostName
+	}
+	if initialization.UserName != "" {
+		config["user"] = initialization.UserName
+	}
+	if initialization.Timezone != "" {
+		config["timezone"] = initialization.Timezone
+	}
+	keys := []string{}
+	for _, key := range strings.Split(initialization.AuthorizedSSHKeys, "\n") {
+		key = strings.TrimSpace(key)
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) > 0 {
+		config["ssh_authorized_keys"] = keys
+	}
+	if initialization.CustomScript != "" {
+		err = yaml.Unmarshal([]byte(initialization.CustomScript), &config)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+	b, err := yaml.Marshal(config)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	userData = CloudConfigHeader + "\n" + string(b)
+	return
+}
+
+// Get the ISO media of the source VM CD-ROMs.
+// Not supported by the provider.
+func (r *Builder) CDROMs(vmRef ref.Ref) (media []planbase.CDROM, err error) {
+	return
+}
+
+// Get the provenance of the source VM.
+// The VM description, path and cluster path.
+func (r *Builder) Provenance(vmRef ref.Ref) (provenance *planbase.Provenance, err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	provenance = &planbase.Provenance{
+		Description: vm.Description,
+		Path:        vm.Path,
+	}
+	if vm.Cluster == "" {
+		return
+	}
+	cluster := &model.Cluster{}
+	err = r.Source.Inventory.Get(cluster, vm.Cluster)
+	if err != nil {
+		err = liberr.Wrap(err, "cluster", vm.Cluster)
+		return
+	}
+	provenance.Cluster = cluster.Path
+	return
+}
+
+// Get the source VM attributes.
+// Not supported by the provider.
+func (r *Builder) Attributes(vmRef ref.Ref) (attributes map[string]string, err error) {
+	return
+}
+
 func (r *Builder) TemplateLabels(vmRef ref.Ref) (labels map[string]string, err error) {
 	vm := &model.Workload{}
 	err = r.Source.Inventory.Find(vm, vmRef)
@@ -612,7 +848,7 @@ func (r *Builder) LunPersistentVolumes(vmRef ref.Ref) (pvs []core.PersistentVolu
 			if logicalUnit.Address != "" {
 				pvSource = core.PersistentVolumeSource{
 					ISCSI: &core.ISCSIPersistentVolumeSource{
-						TargetPortal: logicalUnit.Address + ":" + logicalUnit.Port,
+						TargetPortal: net.JoinHostPort(logicalUnit.Address, logicalUnit.Port),
 						IQN:          logicalUnit.Target,
 						Lun:          logicalUnit.LunMapping,
 						ReadOnly:     false,
@@ -721,7 +957,7 @@ func (r *Builder) PopulatorVolumes(vmRef ref.Ref, annotations map[string]string,
 		return
 	}
 
-	var sdToStorageClass map[string]string
+	var sdToStorage map[string]v1beta1.DestinationStorage
 	for _, diskAttachment := range workload.DiskAttachments {
 		if diskAttachment.Disk.StorageType == "lun" {
 			continue
@@ -739,16 +975,16 @@ func (r *Builder) PopulatorVolumes(vmRef ref.Ref, annotations map[string]string,
 				return
 			}
 			var pvc *core.PersistentVolumeClaim
-			if sdToStorageClass == nil {
-				if sdToStorageClass, err = r.mapStorageDomainToStorageClass(); err != nil {
+			if sdToStorage == nil {
+				if sdToStorage, err = r.mapStorageDomainToStorage(); err != nil {
 					return
 				}
 			}
-			storageClassName := sdToStorageClass[diskAttachment.Disk.StorageDomain]
-			pvc, err = r.persistentVolumeClaimWithSourceRef(diskAttachment, storageClassName, populatorName, annotations, vmRef.ID)
+			destination := sdToStorage[diskAttachment.Disk.StorageDomain]
+			pvc, err = r.persistentVolumeClaimWithSourceRef(diskAttachment, destination, populatorName, annotations, vmRef.ID)
 			if err != nil {
 				if !k8serr.IsAlreadyExists(err) {
-					err = liberr.Wrap(err, "disk attachment", diskAttachment.DiskAttachment.ID, "storage class", storageClassName, "populator", populatorName)
+					err = liberr.Wrap(err, "disk attachment", diskAttachment.DiskAttachment.ID, "storage class", destination.StorageClass, "populator", populatorName)
 					return
 				}
 				err = nil
@@ -760,16 +996,16 @@ func (r *Builder) PopulatorVolumes(vmRef ref.Ref, annotations map[string]string,
 	return
 }
 
-func (r *Builder) mapStorageDomainToStorageClass() (map[string]string, error) {
-	sdToStorageClass := make(map[string]string)
+func (r *Builder) mapStorageDomainToStorage() (map[string]v1beta1.DestinationStorage, error) {
+	sdToStorage := make(map[string]v1beta1.DestinationStorage)
 	for _, mapped := range r.Context.Map.Storage.Spec.Map {
 		sd := &model.StorageDomain{}
 		if err := r.Source.Inventory.Find(sd, mapped.Source); err != nil {
 			return nil, liberr.Wrap(err)
 		}
-		sdToStorageClass[sd.ID] = mapped.Destination.StorageClass
+		sdToStorage[sd.ID] = mapped.Destination
 	}
-	return sdToStorageClass, nil
+	return sdToStorage, nil
 }
 
 // Get the OvirtVolumePopulator CustomResource based on the disk ID.
@@ -863,11 +1099,12 @@ func (r *Builder) getDefaultVolumeAndAccessMode(storageClassName string) ([]core
 
 // Build a PersistentVolumeClaim with DataSourceRef for VolumePopulator
 func (r *Builder) persistentVolumeClaimWithSourceRef(diskAttachment model.XDiskAttachment,
-	storageClassName string,
+	destination v1beta1.DestinationStorage,
 	populatorName string,
 	annotations map[string]string,
 	vmID string) (pvc *core.PersistentVolumeClaim, err error) {
 	diskSize := diskAttachment.Disk.ProvisionedSize
+	storageClassName := destination.StorageClass
 	var accessModes []core.PersistentVolumeAccessMode
 	var volumeMode *core.PersistentVolumeMode
 	accessModes, volumeMode, err = r.getDefaultVolumeAndAccessMode(storageClassName)
@@ -875,13 +1112,25 @@ func (r *Builder) persistentVolumeClaimWithSourceRef(diskAttachment model.XDiskA
 		err = liberr.Wrap(err)
 		return
 	}
+	// The storage map takes precedence over the storage profile defaults.
+	if pinned := destination.GetAccessModes(); len(pinned) > 0 {
+		accessModes = pinned
+	}
+	if destination.VolumeMode != "" {
+		volumeMode = &destination.VolumeMode
+	}
 	// We add 10% overhead because of the fsOverhead in CDI, around 5% to ext4 and 5% for root partition.
-	// This value is configurable using `FILESYSTEM_OVERHEAD`
+	// This value is configurable using `FILESYSTEM_OVERHEAD` or per storage class.
 	// Encrypted Ceph RBD makes the pod see less space, this possible overhead needs to be taken into account.
-	// For Block the value is configurable using `BLOCK_OVERHEAD`
-	diskSize = utils.CalculateSpaceWithOverhead(diskSize, volumeMode)
+	// For Block the value is configurable using `BLOCK_OVERHEAD` or per storage class.
+	sizing, err := utils.GetStorageClassSizing(r.Destination.Client, storageClassName)
+	if err != nil {
+		return
+	}
+	diskSize = sizing.VolumeSize(diskSize, utils.FormatRaw, volumeMode)
 
 	annotations[planbase.AnnDiskSource] = diskAttachment.ID
+	annotations = destination.Annotate(annotations)
 
 	pvc = &core.PersistentVolumeClaim{
 		ObjectMeta: meta.ObjectMeta{
@@ -0,0 +1,33 @@
+package ovirt
+
+import (
+	model "github.com/kubev2v/forklift/pkg/controller/provider/web/ovirt"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ovirt builder tests", func() {
+	It("should build the cloud config of the initialization", func() {
+		userData, err := cloudConfig(&model.Initialization{
+			HostName:          "vm-1",
+			UserName:          "cloud-user",
+			AuthorizedSSHKeys: "ssh-rsa AAAA one\n\nssh-rsa BBBB two\n",
+			CustomScript:      "hostname: custom\npackages:\n- vim\n",
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(userData).To(Equal(CloudConfigHeader + `
+hostname: custom
+packages:
+- vim
+ssh_authorized_keys:
+- ssh-rsa AAAA one
+- ssh-rsa BBBB two
+user: cloud-user
+`))
+	})
+
+	It("should reject the custom script that is not a cloud config", func() {
+		_, err := cloudConfig(&model.Initialization{CustomScript: "- one\n- two\n"})
+		Expect(err).To(HaveOccurred())
+	})
+})
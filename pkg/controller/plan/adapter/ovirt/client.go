@@ -41,11 +41,22 @@ type Client struct {
 
 // Create a VM snapshot and return its ID.
 func (r *Client) CreateSnapshot(vmRef ref.Ref, hostsFunc util.HostsFunc) (snapshotId string, creationTaskId string, err error) {
-	_, vmService, err := r.getVM(vmRef)
+	ovirtVm, vmService, err := r.getVM(vmRef)
 	if err != nil {
 		err = liberr.Wrap(err)
 		return
 	}
+	// The file systems are frozen by the engine
+	// through the guest agent, when running.
+	if r.quiesce(vmRef) {
+		if _, running := ovirtVm.GuestOperatingSystem(); !running {
+			err = liberr.New(
+				"The guest agent is not running, the VM cannot be quiesced.",
+				"vm",
+				vmRef.String())
+			return
+		}
+	}
 	correlationID, err := r.getSnapshotCorrelationID(vmRef, nil)
 	if err != nil {
 		err = liberr.Wrap(err)
@@ -74,6 +85,13 @@ func (r *Client) CreateSnapshot(vmRef ref.Ref, hostsFunc util.HostsFunc) (snapsh
 	return
 }
 
+// Determine whether the guest must be quiesced
+// when the snapshot is taken.
+func (r *Client) quiesce(vmRef ref.Ref) bool {
+	vm, found := r.Plan.Spec.FindVM(vmRef)
+	return found && vm.Quiesce != nil && *vm.Quiesce
+}
+
 // CheckSnapshotRemove implements base.Client
 func (r *Client) CheckSnapshotRemove(vmRef ref.Ref, precopy planapi.Precopy, hosts util.HostsFunc) (bool, error) {
 	return false, nil
@@ -244,13 +262,13 @@ func (r *Client) getSnapshotCorrelationID(vmRef ref.Ref, snapshot *string) (corr
 		return
 	}
 
-	precopyIndex := len(vm.Warm.Precopies)
+	precopyIndex := vm.Warm.Iterations()
 	if snapshot != nil {
 		var precopySnapshot *planapi.Precopy
 		for index, precopy := range vm.Warm.Precopies {
 			if *snapshot == precopy.Snapshot {
 				precopySnapshot = &precopy
-				precopyIndex = index
+				precopyIndex = vm.Warm.Pruned + index
 				break
 			}
 		}
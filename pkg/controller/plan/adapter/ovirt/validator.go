@@ -5,9 +5,12 @@ import (
 
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
 	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	planbase "github.com/kubev2v/forklift/pkg/controller/plan/adapter/base"
+	"github.com/kubev2v/forklift/pkg/controller/plan/util"
 	"github.com/kubev2v/forklift/pkg/controller/provider/container"
 	"github.com/kubev2v/forklift/pkg/controller/provider/web"
 	model "github.com/kubev2v/forklift/pkg/controller/provider/web/ovirt"
+	"github.com/kubev2v/forklift/pkg/controller/validation"
 	liberr "github.com/kubev2v/forklift/pkg/lib/error"
 	"github.com/kubev2v/forklift/pkg/settings"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -70,21 +73,15 @@ func (r *Validator) PodNetwork(vmRef ref.Ref) (ok bool, err error) {
 		return
 	}
 
-	mapping := r.plan.Referenced.Map.Network.Spec.Map
 	podMapped := 0
-	for i := range mapping {
-		mapped := &mapping[i]
-		ref := mapped.Source
-		network := &model.Network{}
-		fErr := r.inventory.Find(network, ref)
+	for i, nic := range vm.NICs {
+		mapped, fErr := r.findNetworkMapping(vm, i, nic)
 		if fErr != nil {
 			err = fErr
 			return
 		}
-		for _, nic := range vm.NICs {
-			if nic.Profile.Network == network.ID && mapped.Destination.Type == Pod {
-				podMapped++
-			}
+		if mapped != nil && mapped.Destination.Type == Pod {
+			podMapped++
 		}
 	}
 
@@ -184,3 +181,161 @@ func (r *Validator) ChangeTrackingEnabled(vmRef ref.Ref) (bool, error) {
 	// Validate that the vm has the change tracking enabled
 	return true, nil
 }
+
+// Return the CPU model and required CPU features when the VM
+// has a custom CPU model or the cluster CPU model is preserved.
+func (r *Validator) CPUModel(vmRef ref.Ref) (cpuModel string, features []string, err error) {
+	vm := &model.Workload{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	var fullCpu string
+	if vm.CustomCpuModel != "" {
+		fullCpu = vm.CustomCpuModel
+	} else if r.plan.Spec.PreserveClusterCPUModel {
+		fullCpu = clusterCpu(vm)
+	}
+	if fullCpu == "" {
+		return
+	}
+	cpuModel, cpuFeatures := parseCpu(fullCpu)
+	for _, feature := range cpuFeatures {
+		if feature.Policy == "" || feature.Policy == "require" {
+			features = append(features, feature.Name)
+		}
+	}
+	return
+}
+
+// Return the guest OS type.
+func (r *Validator) GuestOS(vmRef ref.Ref) (ids []string, err error) {
+	vm := &model.Workload{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	ids = []string{vm.OSType}
+	return
+}
+
+// Return the VM NICs with the destination networks they are mapped to.
+func (r *Validator) NICs(vmRef ref.Ref) (nics []planbase.NIC, err error) {
+	if r.plan.Referenced.Map.Network == nil {
+		return
+	}
+	vm := &model.Workload{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+
+	for i, nic := range vm.NICs {
+		mapped, fErr := r.findNetworkMapping(vm, i, nic)
+		if fErr != nil {
+			err = fErr
+			return
+		}
+		if mapped != nil {
+			nics = append(nics, planbase.NIC{MAC: nic.MAC, Destination: mapped.Destination})
+		}
+	}
+	return
+}
+
+// Find the most specific network mapping of the VM NIC.
+func (r *Validator) findNetworkMapping(vm *model.Workload, index int, nic model.XNIC) (mapped *api.NetworkPair, err error) {
+	vmRef := ref.Ref{ID: vm.ID, Name: vm.Name}
+	mapped = r.plan.Referenced.Map.Network.FindNetworkForNIC(vmRef, index, func(candidate *api.NetworkPair) bool {
+		if err != nil {
+			return false
+		}
+		network := &model.Network{}
+		err = r.inventory.Find(network, candidate.Source)
+		if err != nil {
+			return false
+		}
+		return nic.Profile.Network == network.ID
+	})
+	if err != nil {
+		mapped = nil
+	}
+	return
+}
+
+// Determine whether the target VM will be created with a persistent TPM device.
+// The TPM is mapped for the guest OS requiring it.
+func (r *Validator) PersistentTPM(vmRef ref.Ref) (persistent bool, err error) {
+	vm := &model.Workload{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	guest := validation.FindGuestOS(api.OVirt, nil, vm.OSType)
+	persistent = guest.HasFlag(validation.GuestOSTpm)
+	return
+}
+
+// NO-OP
+func (r *Validator) PassthroughDevices(vmRef ref.Ref) (profiles []string, err error) {
+	return
+}
+
+// NO-OP
+func (r *Validator) RDMDisks(vmRef ref.Ref, client client.Client) (unmapped []string, err error) {
+	return
+}
+
+// NO-OP
+func (r *Validator) IndependentDisks(vmRef ref.Ref) (disks []string, err error) {
+	return
+}
+
+// NO-OP
+func (r *Validator) UnmigratedBootDevices(vmRef ref.Ref) (devices []string, err error) {
+	return
+}
+
+// Return the affinity groups of the VM cluster covering the VM.
+func (r *Validator) AffinityRules(vmRef ref.Ref) (rules []util.AffinityRule, err error) {
+	vm := &model.Workload{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	rules = clusterAffinityRules(&vm.Cluster, vm.ID)
+	return
+}
+
+// Return the CPU, memory and disk capacity (by mapped storage class) of the VM.
+func (r *Validator) Demand(vmRef ref.Ref) (demand *planbase.Demand, err error) {
+	vm := &model.Workload{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	demand = &planbase.Demand{
+		CPU:     int64(vm.CpuSockets) * int64(vm.CpuCores) * int64(vm.CpuThreads),
+		Memory:  vm.Memory,
+		Storage: map[string]int64{},
+	}
+	if r.plan.Referenced.Map.Storage == nil {
+		return
+	}
+	for _, da := range vm.DiskAttachments {
+		if da.Disk.StorageType == "lun" {
+			continue
+		}
+		mapped, found := r.plan.Referenced.Map.Storage.FindStorage(da.Disk.StorageDomain)
+		if found && mapped.Destination.StorageClass != "" {
+			demand.Storage[mapped.Destination.StorageClass] += da.Disk.ProvisionedSize
+		}
+	}
+	return
+}
@@ -1,9 +1,13 @@
 package vsphere
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	liburl "net/url"
 	"path"
@@ -28,10 +32,12 @@ import (
 	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
 	"github.com/kubev2v/forklift/pkg/controller/provider/web/ocp"
 	model "github.com/kubev2v/forklift/pkg/controller/provider/web/vsphere"
+	"github.com/kubev2v/forklift/pkg/controller/validation"
 	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
 	liberr "github.com/kubev2v/forklift/pkg/lib/error"
 	libitr "github.com/kubev2v/forklift/pkg/lib/itinerary"
 	libref "github.com/kubev2v/forklift/pkg/lib/ref"
+	libutil "github.com/kubev2v/forklift/pkg/lib/util"
 	"github.com/kubev2v/forklift/pkg/settings"
 	"github.com/kubev2v/forklift/pkg/templateutil"
 	"github.com/vmware/govmomi/vim25"
@@ -48,6 +54,7 @@ import (
 	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/yaml"
 )
 
 // BIOS types
@@ -67,13 +74,60 @@ const (
 	Tablet = "tablet"
 )
 
+// Device kinds
+const (
+	PCIPassthrough = "VirtualPCIPassthrough"
+)
+
+// Latency sensitivity levels
+const (
+	LatencySensitivityHigh = "high"
+)
+
+// Guestinfo encodings
+const (
+	GuestInfoBase64     = "base64"
+	GuestInfoB64        = "b64"
+	GuestInfoGzipBase64 = "gzip+base64"
+	GuestInfoGzB64      = "gz+b64"
+)
+
 // Network types
 const (
 	Pod     = "pod"
 	Multus  = "multus"
+	SRIOV   = "sriov"
+	UDN     = "udn"
 	Ignored = "ignored"
 )
 
+// KubeVirt CPU models of the vSphere EVC modes.
+var evcModels = map[string]string{
+	"intel-merom":           "Conroe",
+	"intel-penryn":          "Penryn",
+	"intel-nehalem":         "Nehalem",
+	"intel-westmere":        "Westmere",
+	"intel-sandybridge":     "SandyBridge",
+	"intel-ivybridge":       "IvyBridge",
+	"intel-haswell":         "Haswell-noTSX",
+	"intel-broadwell":       "Broadwell-noTSX",
+	"intel-skylake":         "Skylake-Server-noTSX-IBRS",
+	"intel-cascadelake":     "Cascadelake-Server-noTSX",
+	"intel-icelake":         "Icelake-Server-noTSX",
+	"intel-sapphirerapids":  "SapphireRapids",
+	"amd-rev-e":             "Opteron_G1",
+	"amd-rev-f":             "Opteron_G2",
+	"amd-greyhound-no3dnow": "Opteron_G3",
+	"amd-greyhound":         "Opteron_G3",
+	"amd-bulldozer":         "Opteron_G4",
+	"amd-piledriver":        "Opteron_G5",
+	"amd-steamroller":       "Opteron_G5",
+	"amd-zen":               "EPYC",
+	"amd-zen2":              "EPYC-Rome",
+	"amd-zen3":              "EPYC-Milan",
+	"amd-zen4":              "EPYC-Genoa",
+}
+
 // Template labels
 const (
 	TemplateOSLabel       = "os.template.kubevirt.io/%s"
@@ -99,10 +153,6 @@ const (
 	Shareable = "shareable"
 )
 
-const (
-	ManagementNetwork = "Management Network"
-)
-
 // Map of vmware guest ids to osinfo ids.
 var osMap = map[string]string{
 	"centos64Guest":              "centos5.11",
@@ -153,24 +203,6 @@ var osMap = map[string]string{
 	"windows2022srvNext_64Guest": "win2k22",
 }
 
-// Global list of legacy guest OS identifiers and names (OSes without native SHA-2 support)
-var legacyIdentifiers = []string{
-	"windows xp",
-	"winXPProGuest",
-	"server 2003",
-	"winNetEnterpriseGuest",
-	"winNetStandardGuest",
-	"winNetEnterprise64Guest",
-	"vista",
-	"windowsVistaGuest",
-	"server 2008",
-	"longhornGuest",
-	"windows 7",
-	"windows7Guest",
-	"server 2008 r2",
-	"windows7Server64Guest",
-}
-
 // Regex which matches the snapshot identifier suffix of a
 // vSphere disk backing file.
 var backingFilePattern = regexp.MustCompile(`-\d\d\d\d\d\d.vmdk`)
@@ -182,6 +214,8 @@ type Builder struct {
 	hosts map[string]*api.Host
 	// MAC addresses already in use on the destination cluster. k=mac, v=vmName
 	macConflictsMap map[string]string
+	// Storage arrays from the copy offload mapping.
+	arrays []StorageArray
 }
 
 // Get list of destination VMs with mac addresses that would
@@ -226,17 +260,9 @@ func (r *Builder) ConfigMap(_ ref.Ref, _ *core.Secret, _ *core.ConfigMap) (err e
 	return
 }
 
-func IsLegacyWindows(vm *model.VM) bool {
-
-	guestID := strings.ToLower(vm.GuestID)
-	guestName := strings.ToLower(vm.GuestName)
-
-	for _, id := range legacyIdentifiers {
-		if strings.Contains(guestID, id) || strings.Contains(guestName, id) {
-			return true
-		}
-	}
-	return false
+func IsLegacyWindows(vm *model.VM, overrides map[string]string) bool {
+	guest := validation.FindGuestOS(api.VSphere, overrides, vm.GuestID, vm.GuestName)
+	return guest.HasFlag(validation.GuestOSLegacyDrivers)
 }
 
 func (r *Builder) PodEnvironment(vmRef ref.Ref, sourceSecret *core.Secret) (env []core.EnvVar, err error) {
@@ -264,7 +290,12 @@ func (r *Builder) PodEnvironment(vmRef ref.Ref, sourceSecret *core.Secret) (env
 
 	useLegacyDrivers := false
 	if r.Plan.Spec.InstallLegacyDrivers == nil {
-		useLegacyDrivers = IsLegacyWindows(vm)
+		var overrides map[string]string
+		overrides, err = validation.GuestOSOverrides(r.Client)
+		if err != nil {
+			return
+		}
+		useLegacyDrivers = IsLegacyWindows(vm, overrides)
 	} else {
 		useLegacyDrivers = *r.Plan.Spec.InstallLegacyDrivers
 	}
@@ -361,13 +392,9 @@ func isWindows(vm *model.VM) bool {
 }
 
 // Retrieve the IP address of an ESXI host from its Management Network VNIC or fall back to the hostname.
-func getHostAddress(host *model.Host) string {
-	for _, vnic := range host.Network.VNICs {
-		if vnic.PortGroup == ManagementNetwork {
-			if vnic.IpAddress != "" && net.ParseIP(vnic.IpAddress) != nil {
-				return vnic.IpAddress // Return the IP address if found
-			}
-		}
+func getHostAddress(host *model.Host, network string) string {
+	if address, found := host.Network.ManagementAddress(network); found {
+		return address
 	}
 	// otherwise fall back to the host name
 	return host.Name
@@ -392,7 +419,7 @@ func (r *Builder) getSourceDetails(vm *model.VM, sourceSecret *core.Secret) (lib
 		}
 		libvirtURL = liburl.URL{
 			Scheme:   "esx",
-			Host:     hostDef.Spec.IpAddress,
+			Host:     libutil.URLHost(hostDef.Spec.IpAddress),
 			User:     liburl.User(string(hostSecret.Data["user"])),
 			Path:     "",
 			RawQuery: sslVerify,
@@ -402,12 +429,15 @@ func (r *Builder) getSourceDetails(vm *model.VM, sourceSecret *core.Secret) (lib
 			// so we take it from the provider instead
 			fingerprint = r.Source.Provider.Status.Fingerprint
 		} else {
-			fingerprint = host.Thumbprint
+			fingerprint, err = r.hostThumbprint(host, hostDef.Spec.IpAddress)
+			if err != nil {
+				return
+			}
 		}
 	} else if r.Source.Provider.Spec.Settings[api.SDK] == api.ESXI {
 		libvirtURL = liburl.URL{
 			Scheme:   "esx",
-			Host:     getHostAddress(host),
+			Host:     libutil.URLHost(getHostAddress(host, r.Source.Provider.Network())),
 			User:     liburl.User(string(sourceSecret.Data["user"])),
 			Path:     "",
 			RawQuery: sslVerify,
@@ -430,8 +460,7 @@ func (r *Builder) getSourceDetails(vm *model.VM, sourceSecret *core.Secret) (lib
 			}
 		}
 		var url *liburl.URL
-		if url, err = liburl.Parse(r.Source.Provider.Spec.URL); err != nil {
-			err = liberr.Wrap(err)
+		if url, fingerprint, err = vCenterOf(r.Source.Provider, sourceSecret, vm); err != nil {
 			return
 		}
 		libvirtURL = liburl.URL{
@@ -441,12 +470,28 @@ func (r *Builder) getSourceDetails(vm *model.VM, sourceSecret *core.Secret) (lib
 			Path:     path, // E.g.: /Datacenter/Cluster/host.example.com
 			RawQuery: sslVerify,
 		}
-		fingerprint = r.Source.Provider.Status.Fingerprint
 	}
 
 	return
 }
 
+// Thumbprint of the ESXi host certificate.
+// The thumbprint reported by vCenter when known, else
+// fetched from the host (address) and cached.
+func (r *Builder) hostThumbprint(host *model.Host, address string) (thumbprint string, err error) {
+	if host.Thumbprint != "" {
+		thumbprint = host.Thumbprint
+		return
+	}
+	fetched, err := libutil.Thumbprints.Get(address)
+	if err != nil {
+		err = liberr.Wrap(err, "host", host.ID)
+		return
+	}
+	thumbprint = fetched.Thumbprint
+	return
+}
+
 // Build the DataVolume credential secret.
 func (r *Builder) Secret(vmRef ref.Ref, in, object *core.Secret) (err error) {
 	hostID, err := r.hostID(vmRef)
@@ -504,8 +549,14 @@ func (r *Builder) DataVolumes(vmRef ref.Ref, secret *core.Secret, _ *core.Config
 	if !r.Context.Plan.Spec.MigrateSharedDisks {
 		vm.RemoveSharedDisks()
 	}
-	url := r.Source.Provider.Spec.URL
-	thumbprint := r.Source.Provider.Status.Fingerprint
+	if r.Context.Plan.Spec.RDMDisks != nil {
+		vm.RemoveRDMDisks()
+	}
+	vcenterURL, thumbprint, err := vCenterOf(r.Source.Provider, r.Source.Secret, vm)
+	if err != nil {
+		return
+	}
+	url := vcenterURL.String()
 	hostID, err := r.hostID(vmRef)
 	if err != nil {
 		return
@@ -513,7 +564,7 @@ func (r *Builder) DataVolumes(vmRef ref.Ref, secret *core.Secret, _ *core.Config
 	if hostDef, found := r.hosts[hostID]; found {
 		hostURL := liburl.URL{
 			Scheme: "https",
-			Host:   hostDef.Spec.IpAddress,
+			Host:   libutil.URLHost(hostDef.Spec.IpAddress),
 			Path:   vim25.Path,
 		}
 		url = hostURL.String()
@@ -522,7 +573,10 @@ func (r *Builder) DataVolumes(vmRef ref.Ref, secret *core.Secret, _ *core.Config
 			err = nErr
 			return
 		}
-		thumbprint = h.Thumbprint
+		thumbprint, err = r.hostThumbprint(h, hostDef.Spec.IpAddress)
+		if err != nil {
+			return
+		}
 	}
 
 	// Build datastore map for more efficient lookups
@@ -568,7 +622,7 @@ func (r *Builder) DataVolumes(vmRef ref.Ref, secret *core.Secret, _ *core.Config
 				},
 			}
 		}
-		alignedCapacity := utils.RoundUp(disk.Capacity, utils.DefaultAlignBlockSize)
+		alignedCapacity := utils.DataVolumeSize(disk.Capacity, utils.FormatRaw)
 		dvSpec := cdi.DataVolumeSpec{
 			Source: &dvSource,
 			Storage: &cdi.StorageSpec{
@@ -582,8 +636,8 @@ func (r *Builder) DataVolumes(vmRef ref.Ref, secret *core.Secret, _ *core.Config
 		}
 		// set the access mode and volume mode if they were specified in the storage map.
 		// otherwise, let the storage profile decide the default values.
-		if mapped.Destination.AccessMode != "" {
-			dvSpec.Storage.AccessModes = []core.PersistentVolumeAccessMode{mapped.Destination.AccessMode}
+		if accessModes := mapped.Destination.GetAccessModes(); len(accessModes) > 0 {
+			dvSpec.Storage.AccessModes = accessModes
 		} else {
 			// we expect the storage class for migration to support RWX for live migration to work.
 			// In case the override is needed, set it in the StorageMap mapping
@@ -602,11 +656,15 @@ func (r *Builder) DataVolumes(vmRef ref.Ref, secret *core.Secret, _ *core.Config
 		if disk.Shared {
 			dv.ObjectMeta.Labels[Shareable] = "true"
 		}
+		if r.Plan.Spec.Warm && disk.Independent() {
+			dv.ObjectMeta.Annotations[planbase.AnnColdCopy] = "true"
+		}
 
 		// Preserve the disk index as an annotation on the created DataVolume
 		// Note: this annotation will be used to match the PVC to the VM disks by
 		//       matching the disk and PVC index.
 		dv.ObjectMeta.Annotations[planbase.AnnDiskIndex] = fmt.Sprintf("%d", diskIndex)
+		dv.ObjectMeta.Annotations = mapped.Destination.Annotate(dv.ObjectMeta.Annotations)
 
 		// if exists, get the PVC generate name from the PlanSpec, generate the name
 		// and update the GenerateName field in the DataVolume object.
@@ -729,6 +787,10 @@ func (r *Builder) VirtualMachine(vmRef ref.Ref, object *cnv.VirtualMachineSpec,
 		return
 	}
 
+	if r.Context.Plan.Spec.RDMDisks.Skipped() {
+		vm.RemoveRDMDisks()
+	}
+
 	host, err := r.host(vm.Host)
 	if err != nil {
 		return
@@ -744,11 +806,22 @@ func (r *Builder) VirtualMachine(vmRef ref.Ref, object *cnv.VirtualMachineSpec,
 	r.mapFirmware(vm, object)
 	if !usesInstanceType {
 		r.mapCPU(vm, object)
+		err = r.mapCPUModel(host, object)
+		if err != nil {
+			return
+		}
 		r.mapMemory(vm, object)
+		r.mapPlacement(vm, object)
+		r.mapMemoryAllocation(vm, object)
 	}
 	r.mapClock(host, object)
 	r.mapInput(object)
 	r.mapTpm(vm, object)
+	r.mapDevices(vm, object)
+	err = r.mapAffinity(vm, host, object)
+	if err != nil {
+		return
+	}
 	err = r.mapNetworks(vm, object)
 	if err != nil {
 		return
@@ -762,10 +835,8 @@ func (r *Builder) mapNetworks(vm *model.VM, object *cnv.VirtualMachineSpec) (err
 	var kInterfaces []cnv.Interface
 
 	numNetworks := 0
-	netMapIn := r.Context.Map.Network.Spec.Map
-
-	for _, nic := range vm.NICs {
-		mapped := r.findNetworkMapping(nic, netMapIn)
+	for i, nic := range vm.NICs {
+		mapped := r.findNetworkMapping(vm, i, nic)
 
 		// Skip if no valid mapping found or the destination type is Ignored
 		if mapped == nil || mapped.Destination.Type == Ignored {
@@ -800,16 +871,24 @@ func (r *Builder) mapNetworks(vm *model.VM, object *cnv.VirtualMachineSpec) (err
 			Model:      interfaceModel,
 			MacAddress: nic.MAC,
 		}
+		if r.preserveBootOrder(vm) {
+			kInterface.BootOrder = sourceBootOrder(vm, vsphere.BootEthernet, nic.Key)
+		}
 
 		switch mapped.Destination.Type {
 		case Pod:
 			kNetwork.Pod = &cnv.PodNetwork{}
 			kInterface.Masquerade = &cnv.InterfaceMasquerade{}
-		case Multus:
+		case Multus, UDN:
 			kNetwork.Multus = &cnv.MultusNetwork{
 				NetworkName: path.Join(mapped.Destination.Namespace, mapped.Destination.Name),
 			}
 			kInterface.Bridge = &cnv.InterfaceBridge{}
+		case SRIOV:
+			kNetwork.Multus = &cnv.MultusNetwork{
+				NetworkName: path.Join(mapped.Destination.Namespace, mapped.Destination.Name),
+			}
+			kInterface.SRIOV = &cnv.InterfaceSRIOV{}
 		}
 
 		kNetworks = append(kNetworks, kNetwork)
@@ -821,20 +900,21 @@ func (r *Builder) mapNetworks(vm *model.VM, object *cnv.VirtualMachineSpec) (err
 	return
 }
 
-func (r *Builder) findNetworkMapping(nic vsphere.NIC, netMap []api.NetworkPair) *api.NetworkPair {
-	for i := range netMap {
-		candidate := &netMap[i]
+func (r *Builder) findNetworkMapping(vm *model.VM, index int, nic vsphere.NIC) *api.NetworkPair {
+	vmRef := ref.Ref{ID: vm.ID, Name: vm.Name}
+	return r.Context.Map.Network.FindNetworkForNIC(vmRef, index, func(candidate *api.NetworkPair) bool {
 		network := &model.Network{}
 		if err := r.Source.Inventory.Find(network, candidate.Source); err != nil {
-			continue
+			return false
 		}
+		return nicOnNetwork(nic, network)
+	})
+}
 
-		if (network.Variant == vsphere.NetDvPortGroup || network.Variant == vsphere.OpaqueNetwork) &&
-			nic.Network.ID == network.Key || nic.Network.ID == network.ID {
-			return candidate
-		}
-	}
-	return nil
+// Whether the NIC is attached to the network.
+func nicOnNetwork(nic vsphere.NIC, network *model.Network) bool {
+	return (network.Variant == vsphere.NetDvPortGroup || network.Variant == vsphere.OpaqueNetwork) &&
+		nic.Network.ID == network.Key || nic.Network.ID == network.ID
 }
 
 func (r *Builder) mapInput(object *cnv.VirtualMachineSpec) {
@@ -866,6 +946,23 @@ func (r *Builder) mapMemory(vm *model.VM, object *cnv.VirtualMachineSpec) {
 	object.Template.Spec.Domain.Memory = &cnv.Memory{Guest: reservation}
 }
 
+// Map the memory requests and limits as set by the plan.
+// Applied once the placement is mapped (hugepages).
+func (r *Builder) mapMemoryAllocation(vm *model.VM, object *cnv.VirtualMachineSpec) {
+	allocation := memoryAllocation(vm)
+	allocation.ApplyTo(object, r.Plan.Spec.Memory)
+}
+
+// Memory allocation of the VM.
+func memoryAllocation(vm *model.VM) utils.MemoryAllocation {
+	return utils.MemoryAllocation{
+		Size:        int64(vm.MemoryMB) * 1024 * 1024,
+		Reservation: vm.MemoryReservationMB * 1024 * 1024,
+		Limit:       vm.MemoryLimitMB * 1024 * 1024,
+		Locked:      vm.MemoryReservationLocked,
+	}
+}
+
 func (r *Builder) mapCPU(vm *model.VM, object *cnv.VirtualMachineSpec) {
 	object.Template.Spec.Domain.CPU = &cnv.CPU{
 		Sockets: uint32(vm.CpuCount / vm.CoresPerSocket),
@@ -886,6 +983,101 @@ func (r *Builder) mapCPU(vm *model.VM, object *cnv.VirtualMachineSpec) {
 	}
 }
 
+// Map the CPU model of the host cluster EVC mode.
+// The default (host-model) CPU model is kept without EVC.
+func (r *Builder) mapCPUModel(host *model.Host, object *cnv.VirtualMachineSpec) (err error) {
+	cpuModel, err := evcCPUModel(r.Source.Inventory, host)
+	if err != nil {
+		return
+	}
+	if cpuModel != "" {
+		object.Template.Spec.Domain.CPU.Model = cpuModel
+	}
+	return
+}
+
+// Find the KubeVirt CPU model of the host cluster EVC mode.
+func evcCPUModel(inventory web.Client, host *model.Host) (cpuModel string, err error) {
+	if host.Cluster == "" {
+		return
+	}
+	cluster := &model.Cluster{}
+	err = inventory.Get(cluster, host.Cluster)
+	if err != nil {
+		err = liberr.Wrap(err, "cluster", host.Cluster)
+		return
+	}
+	cpuModel = evcModels[cluster.EvcMode]
+	return
+}
+
+// Map the DRS rules of the host cluster to pod (anti-)affinity
+// with the VMs migrated by the plan.
+func (r *Builder) mapAffinity(vm *model.VM, host *model.Host, object *cnv.VirtualMachineSpec) (err error) {
+	rules, err := clusterAffinityRules(r.Source.Inventory, host, vm.ID)
+	if err != nil {
+		return
+	}
+	utils.ApplyAffinity(object, r.Plan, vm.ID, rules)
+	return
+}
+
+// Find the enabled DRS rules of the host cluster covering the VM.
+func clusterAffinityRules(inventory web.Client, host *model.Host, vmID string) (rules []utils.AffinityRule, err error) {
+	if host.Cluster == "" {
+		return
+	}
+	cluster := &model.Cluster{}
+	err = inventory.Get(cluster, host.Cluster)
+	if err != nil {
+		err = liberr.Wrap(err, "cluster", host.Cluster)
+		return
+	}
+	for _, rule := range cluster.Rules {
+		if !rule.Enabled {
+			continue
+		}
+		rule := utils.AffinityRule{
+			Name:      rule.Name,
+			Kind:      rule.Kind,
+			Mandatory: rule.Mandatory,
+			VMs:       refIDs(rule.VMs),
+		}
+		for _, id := range rule.VMs {
+			if id == vmID {
+				rules = append(rules, rule)
+				break
+			}
+		}
+	}
+	return
+}
+
+// IDs of the references.
+func refIDs(refs []vsphere.Ref) (ids []string) {
+	for _, ref := range refs {
+		ids = append(ids, ref.ID)
+	}
+	return
+}
+
+// Map the CPU placement when preserved by the plan.
+// High latency sensitivity and CPU affinity are mapped to dedicated CPUs
+// and the 1G large pages to hugepages.
+func (r *Builder) mapPlacement(vm *model.VM, object *cnv.VirtualMachineSpec) {
+	if !r.Plan.Spec.PreserveCPUPlacement {
+		return
+	}
+	placement := utils.CPUPlacement{
+		Dedicated: vm.LatencySensitivity == LatencySensitivityHigh || len(vm.CpuAffinity) > 0,
+		NUMA:      len(vm.NumaNodeAffinity) > 0,
+	}
+	if vm.HugePages1G {
+		placement.HugePageSize = "1Gi"
+	}
+	placement.ApplyTo(object)
+}
+
 func (r *Builder) mapFirmware(vm *model.VM, object *cnv.VirtualMachineSpec) {
 	firmware := &cnv.Firmware{
 		Serial: vm.UUID,
@@ -896,6 +1088,12 @@ func (r *Builder) mapFirmware(vm *model.VM, object *cnv.VirtualMachineSpec) {
 			EFI: &cnv.EFI{
 				SecureBoot: &vm.SecureBoot,
 			}}
+		// Persist the UEFI variables (boot entries and enrolled keys)
+		// required by the TPM state, the secure boot and the preserved
+		// source boot order.
+		if vm.TpmEnabled || vm.SecureBoot || r.preserveBootOrder(vm) {
+			firmware.Bootloader.EFI.Persistent = ptr.To(true)
+		}
 		if vm.SecureBoot {
 			object.Template.Spec.Domain.Features = &cnv.Features{
 				SMM: &cnv.FeatureState{
@@ -976,6 +1174,7 @@ func (r *Builder) mapDisks(vm *model.VM, vmRef ref.Ref, persistentVolumeClaims [
 			break
 		}
 	}
+	preserveBootOrder := r.preserveBootOrder(vm)
 
 	for i, disk := range disks {
 		// If the user creates in middle of migration snapshot the disk file name gets the snapshot suffix.
@@ -1038,10 +1237,21 @@ func (r *Builder) mapDisks(vm *model.VM, vmRef ref.Ref, persistentVolumeClaims [
 				},
 			},
 		}
+		if disk.RDM && disk.RDMMode == vsphere.RDMPhysical {
+			// Pass the SCSI commands through to the LUN.
+			kubevirtDisk.DiskDevice = cnv.DiskDevice{
+				LUN: &cnv.LunTarget{
+					Bus: cnv.DiskBusSCSI,
+				},
+			}
+		}
 		if disk.Shared {
 			kubevirtDisk.Shareable = ptr.To(true)
 			kubevirtDisk.Cache = cnv.CacheNone
 		}
+		if preserveBootOrder {
+			kubevirtDisk.BootOrder = sourceBootOrder(vm, vsphere.BootDisk, disk.Key)
+		}
 		// Disk serial numbers are only presented to the source guest if
 		// the disk is connected with SCSI and disk.EnableUUID is set to
 		// TRUE, so only save the serial number for those disks. If the
@@ -1062,6 +1272,8 @@ func (r *Builder) mapDisks(vm *model.VM, vmRef ref.Ref, persistentVolumeClaims [
 			r.Log.Info("Available PVC mapping", "diskKey", key, "pvcName", pvc.Name)
 		}
 		return fmt.Errorf("no disks were successfully mapped for VM %s", vm.Name)
+	} else if preserveBootOrder {
+		r.Log.V(1).Info("Preserving the source boot order", "vm", vm.Name, "bootOrder", vm.BootOrder)
 	} else if bootDisk < len(kDisks) {
 		// For multiboot VMs, if the selected boot device is the current disk,
 		// set it as the first in the boot order.
@@ -1075,6 +1287,45 @@ func (r *Builder) mapDisks(vm *model.VM, vmRef ref.Ref, persistentVolumeClaims [
 	return nil
 }
 
+// Determine whether the source boot order is preserved.
+// The source boot order is preserved when it includes a disk
+// and the root disk is not selected by the plan.
+func (r *Builder) preserveBootOrder(vm *model.VM) bool {
+	return preserveBootOrder(r.Plan, vm)
+}
+
+// Determine whether the source boot order of the VM is preserved.
+func preserveBootOrder(plan *api.Plan, vm *model.VM) bool {
+	for _, vmConf := range plan.Spec.VMs {
+		if vmConf.ID == vm.ID && vmConf.RootDisk != "" {
+			return false
+		}
+	}
+	for _, device := range vm.BootOrder {
+		if device.Kind == vsphere.BootDisk {
+			return true
+		}
+	}
+	return false
+}
+
+// Boot order of the (disk or ethernet) device in the source boot order.
+// The CD-ROM and floppy devices are not migrated and not counted.
+// See: Validator.UnmigratedBootDevices().
+func sourceBootOrder(vm *model.VM, kind string, key int32) *uint {
+	order := uint(0)
+	for _, device := range vm.BootOrder {
+		if device.Kind != vsphere.BootDisk && device.Kind != vsphere.BootEthernet {
+			continue
+		}
+		order++
+		if device.Kind == kind && device.Key == key {
+			return ptr.To(order)
+		}
+	}
+	return nil
+}
+
 func (r *Builder) mapTpm(vm *model.VM, object *cnv.VirtualMachineSpec) {
 	if vm.TpmEnabled {
 		persistData := true
@@ -1088,6 +1339,37 @@ func (r *Builder) mapTpm(vm *model.VM, object *cnv.VirtualMachineSpec) {
 	}
 }
 
+// Map the passthrough devices to the destination (mediated
+// or host) devices by the plan device map.
+// Devices not mapped are reported by the plan validation.
+func (r *Builder) mapDevices(vm *model.VM, object *cnv.VirtualMachineSpec) {
+	devices := &object.Template.Spec.Domain.Devices
+	for _, device := range vm.Devices {
+		if device.Kind != PCIPassthrough {
+			continue
+		}
+		mapping, found := r.Context.Plan.Spec.FindDeviceMapping(device.Profile)
+		if !found {
+			continue
+		}
+		if mapping.Mediated {
+			devices.GPUs = append(
+				devices.GPUs,
+				cnv.GPU{
+					Name:       fmt.Sprintf("gpu-%d", len(devices.GPUs)),
+					DeviceName: mapping.ResourceName,
+				})
+		} else {
+			devices.HostDevices = append(
+				devices.HostDevices,
+				cnv.HostDevice{
+					Name:       fmt.Sprintf("hostdevice-%d", len(devices.HostDevices)),
+					DeviceName: mapping.ResourceName,
+				})
+		}
+	}
+}
+
 // Build tasks.
 func (r *Builder) Tasks(vmRef ref.Ref) (list []*plan.Task, err error) {
 	vm := &model.VM{}
@@ -1099,6 +1381,9 @@ func (r *Builder) Tasks(vmRef ref.Ref) (list []*plan.Task, err error) {
 	if !r.Context.Plan.Spec.MigrateSharedDisks {
 		vm.RemoveSharedDisks()
 	}
+	if r.Context.Plan.Spec.RDMDisks != nil {
+		vm.RemoveRDMDisks()
+	}
 	for _, disk := range vm.Disks {
 		mB := utils.RoundUp(disk.Capacity, 0x100000) / 0x100000
 		list = append(
@@ -1132,6 +1417,228 @@ func (r *Builder) PreferenceName(vmRef ref.Ref, configMap *core.ConfigMap) (name
 	return
 }
 
+// Build the cloud-init data of the guestinfo variables.
+// The network configuration is carried over from the metadata.
+func (r *Builder) CloudInit(vmRef ref.Ref) (cloudInit *planbase.CloudInit, err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	userData, err := guestInfoData(vm.GuestInfo.UserData, vm.GuestInfo.UserDataEncoding)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	metaData, err := guestInfoData(vm.GuestInfo.MetaData, vm.GuestInfo.MetaDataEncoding)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	networkData, err := metaDataNetwork(metaData)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	if userData != "" || networkData != "" {
+		cloudInit = &planbase.CloudInit{
+			UserData:    userData,
+			NetworkData: networkData,
+		}
+	}
+	return
+}
+
+// Decode the (base64 or gzip+base64) encoded guestinfo variable.
+func guestInfoData(data, encoding string) (decoded string, err error) {
+	switch strings.ToLower(encoding) {
+	case "":
+		decoded = data
+	case GuestInfoBase64, GuestInfoB64:
+		var b []byte
+		b, err = base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		decoded = string(b)
+	case GuestInfoGzipBase64, GuestInfoGzB64:
+		var b []byte
+		b, err = base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		var reader *gzip.Reader
+		reader, err = gzip.NewReader(bytes.NewReader(b))
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		defer reader.Close()
+		b, err = io.ReadAll(reader)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		decoded = string(b)
+	default:
+		err = liberr.New("guestinfo encoding not supported.", "encoding", encoding)
+	}
+	return
+}
+
+// Network configuration of the (JSON or YAML) cloud-init metadata.
+func metaDataNetwork(metaData string) (network string, err error) {
+	if metaData == "" {
+		return
+	}
+	fields := map[string]interface{}{}
+	err = yaml.Unmarshal([]byte(metaData), &fields)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	switch value := fields["network"].(type) {
+	case nil:
+	case string:
+		encoding, _ := fields["network.encoding"].(string)
+		network, err = guestInfoData(value, encoding)
+	default:
+		var b []byte
+		b, err = yaml.Marshal(value)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		network = string(b)
+	}
+	return
+}
+
+// Get the ISO media of the source VM CD-ROMs.
+// The ISO files are served by the datastore file access of the
+// vCenter (or ESXi): /folder/<path>?dcPath=<datacenter>&dsName=<datastore>.
+// Only the media inserted in connected CD-ROMs are reported.
+func (r *Builder) CDROMs(vmRef ref.Ref) (media []planbase.CDROM, err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	endpoint, err := liburl.Parse(r.Source.Provider.Spec.URL)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for _, cdrom := range vm.CDROMs {
+		if !cdrom.Connected || cdrom.ISO == "" || cdrom.Datastore.ID == "" {
+			continue
+		}
+		dsName, filePath, parsed := datastoreFile(cdrom.ISO)
+		if !parsed {
+			continue
+		}
+		ds := &model.Datastore{}
+		err = r.Source.Inventory.Get(ds, cdrom.Datastore.ID)
+		if err != nil {
+			err = liberr.Wrap(err, "datastore", cdrom.Datastore.ID)
+			return
+		}
+		query := liburl.Values{}
+		query.Set("dcPath", datacenterPath(ds.Path))
+		query.Set("dsName", dsName)
+		url := liburl.URL{
+			Scheme:   "https",
+			Host:     endpoint.Host,
+			Path:     path.Join("/folder", filePath),
+			RawQuery: query.Encode(),
+		}
+		media = append(
+			media,
+			planbase.CDROM{
+				File: cdrom.ISO,
+				URL:  url.String(),
+			})
+	}
+	return
+}
+
+// Split a datastore file name ([datastore1] iso/rhel9.iso)
+// into the datastore name and the file path.
+func datastoreFile(fileName string) (dsName, filePath string, parsed bool) {
+	if !strings.HasPrefix(fileName, "[") {
+		return
+	}
+	dsName, filePath, parsed = strings.Cut(fileName[1:], "]")
+	filePath = strings.TrimSpace(filePath)
+	parsed = parsed && dsName != "" && filePath != ""
+	return
+}
+
+// The datacenter inventory path of a datastore path.
+// The datastores are listed in the datacenter 'datastore' folder.
+func datacenterPath(dsPath string) string {
+	dcPath := strings.TrimPrefix(dsPath, "/")
+	if i := strings.Index(dcPath, "/datastore/"); i != -1 {
+		dcPath = dcPath[:i]
+	}
+	return dcPath
+}
+
+// Get the provenance of the source VM.
+// The VM notes, folder path and host cluster path.
+func (r *Builder) Provenance(vmRef ref.Ref) (provenance *planbase.Provenance, err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	provenance = &planbase.Provenance{
+		Description: vm.Notes,
+		Path:        vm.Path,
+	}
+	if vm.Host == "" {
+		return
+	}
+	host, err := r.host(vm.Host)
+	if err != nil {
+		return
+	}
+	if host.Cluster == "" {
+		return
+	}
+	cluster := &model.Cluster{}
+	err = r.Source.Inventory.Get(cluster, host.Cluster)
+	if err != nil {
+		err = liberr.Wrap(err, "cluster", host.Cluster)
+		return
+	}
+	provenance.Cluster = cluster.Path
+	return
+}
+
+// Get the source VM attributes.
+// The custom attributes set on the VM.
+func (r *Builder) Attributes(vmRef ref.Ref) (attributes map[string]string, err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	attributes = map[string]string{}
+	for _, attribute := range vm.CustomAttributes {
+		if attribute.Name != "" && attribute.Value != "" {
+			attributes[attribute.Name] = attribute.Value
+		}
+	}
+	return
+}
+
 func (r *Builder) TemplateLabels(vmRef ref.Ref) (labels map[string]string, err error) {
 	var os string
 	for _, vmConf := range r.Migration.Status.VMs {
@@ -1271,21 +1778,89 @@ func (r *Builder) LunPersistentVolumes(vmRef ref.Ref) (pvs []core.PersistentVolu
 	return
 }
 
-// Build LUN PVCs.
+// Build the PVCs binding the RDM disks to the
+// pre-existing PVs presenting the same LUNs.
 func (r *Builder) LunPersistentVolumeClaims(vmRef ref.Ref) (pvcs []core.PersistentVolumeClaim, err error) {
-	// do nothing
+	if !r.Context.Plan.Spec.RDMDisks.Mapped() {
+		return
+	}
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	if !vm.HasRDMDisk() {
+		return
+	}
+	pvs, err := listPersistentVolumes(r.Destination.Client)
+	if err != nil {
+		return
+	}
+	namespace := r.Plan.Spec.TargetNamespace
+	for _, disk := range vm.Disks {
+		if !disk.RDM {
+			continue
+		}
+		pv := findRDMVolume(pvs, disk, namespace)
+		if pv == nil {
+			err = liberr.New(
+				"No available persistent volume matching the RDM disk LUN.",
+				"vm",
+				vmRef.String(),
+				"disk",
+				disk.File,
+				"wwn",
+				disk.WWN)
+			return
+		}
+		volumeMode := core.PersistentVolumeBlock
+		storageClass := pv.Spec.StorageClassName
+		name := fmt.Sprintf("%s-rdm-%d", r.getPlenVMSafeName(vm), disk.Key)
+		pvcs = append(
+			pvcs,
+			core.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: namespace,
+					Annotations: map[string]string{
+						planbase.AnnDiskSource: disk.File,
+						"lun":                  "true",
+					},
+					Labels: map[string]string{
+						"volume":    name,
+						"vmID":      vm.ID,
+						"plan":      string(r.Plan.UID),
+						"migration": string(r.Migration.UID),
+					},
+				},
+				Spec: core.PersistentVolumeClaimSpec{
+					AccessModes:      pv.Spec.AccessModes,
+					VolumeName:       pv.Name,
+					StorageClassName: &storageClass,
+					VolumeMode:       &volumeMode,
+					Resources: core.VolumeResourceRequirements{
+						Requests: core.ResourceList{
+							core.ResourceStorage: pv.Spec.Capacity[core.ResourceStorage],
+						},
+					},
+				},
+			})
+	}
 	return
 }
 
 // FIXME rgolan - the behaviour needs to be per disk hense this method is flawed. Needs a bigger change.
-// For now this method returns true, if there's a mapping (backend by copy-offload-mapping ConfigMap, that
-// maps StoragetClasses to Vsphere data stores
+// For now this method returns true, if there's an offload plugin on the storage map or a mapping
+// (backend by copy-offload-mapping ConfigMap) that maps StoragetClasses to Vsphere data stores
+// of the same storage array.
 func (r *Builder) SupportsVolumePopulators() bool {
 	if !settings.Settings.Features.CopyOffload || r.Plan.Spec.Warm {
 		return false
 	}
 	dsMapIn := r.Context.Map.Storage.Spec.Map
-	for _, m := range dsMapIn {
+	for i := range dsMapIn {
+		m := &dsMapIn[i]
 		ref := m.Source
 		ds := &model.Datastore{}
 		err := r.Source.Inventory.Find(ds, ref)
@@ -1294,8 +1869,13 @@ func (r *Builder) SupportsVolumePopulators() bool {
 			return false
 		}
 
-		if m.OffloadPlugin != nil && m.OffloadPlugin.VSphereXcopyPluginConfig != nil {
-			klog.V(2).Infof("found offload plugin: config %+v on ds map  %+v", m.OffloadPlugin.VSphereXcopyPluginConfig, dsMapIn)
+		plugin, err := r.offloadPlugin(m, ds)
+		if err != nil {
+			klog.Errorf("failed to get offload plugin to detect volume populators support: %s", err)
+			return false
+		}
+		if plugin != nil {
+			klog.V(2).Infof("found offload plugin: config %+v on ds map  %+v", plugin.VSphereXcopyPluginConfig, dsMapIn)
 			return true
 
 		}
@@ -1312,6 +1892,9 @@ func (r *Builder) PopulatorVolumes(vmRef ref.Ref, annotations map[string]string,
 		err = liberr.Wrap(err, "vm", vmRef.String())
 		return
 	}
+	if r.Context.Plan.Spec.RDMDisks != nil {
+		vm.RemoveRDMDisks()
+	}
 
 	dsMapIn := r.Context.Map.Storage.Spec.Map
 	for i := range dsMapIn {
@@ -1324,15 +1907,30 @@ func (r *Builder) PopulatorVolumes(vmRef ref.Ref, annotations map[string]string,
 			return
 		}
 
+		plugin, pErr := r.offloadPlugin(mapped, ds)
+		if pErr != nil {
+			err = pErr
+			return
+		}
+
 		pvblock := core.PersistentVolumeBlock
 		for _, disk := range vm.Disks {
 			if disk.Datastore.ID == ds.ID {
 				storageClass := mapped.Destination.StorageClass
+				sizing, sErr := utils.GetStorageClassSizing(r.Destination.Client, storageClass)
+				if sErr != nil {
+					return nil, sErr
+				}
+				if plugin == nil {
+					return nil, fmt.Errorf(
+						"datastore %s and storage class %s are not backed by the same storage array and no offload plugin is set. Can't continue with PVC and populator resources creation.",
+						ds.Name, storageClass)
+				}
 
 				r.Log.Info(fmt.Sprintf("getting storage mapping by storage class %q and datastore %v datastore name %s datastore", storageClass, disk.Datastore, disk.Datastore))
 				vsphereInstance := r.Context.Plan.Provider.Source.GetName()
-				storageVendorProduct := mapped.OffloadPlugin.VSphereXcopyPluginConfig.StorageVendorProduct
-				storageVendorSecretRef := mapped.OffloadPlugin.VSphereXcopyPluginConfig.SecretRef
+				storageVendorProduct := plugin.VSphereXcopyPluginConfig.StorageVendorProduct
+				storageVendorSecretRef := plugin.VSphereXcopyPluginConfig.SecretRef
 
 				r.Log.Info(fmt.Sprintf("vsphere provider %v storage vendor product %v storage secret name %v ", vsphereInstance, storageVendorProduct, storageVendorSecretRef))
 
@@ -1364,7 +1962,7 @@ func (r *Builder) PopulatorVolumes(vmRef ref.Ref, annotations map[string]string,
 						VolumeMode:       &pvblock,
 						Resources: core.VolumeResourceRequirements{
 							Requests: core.ResourceList{
-								core.ResourceStorage: *resource.NewQuantity(disk.Capacity, resource.BinarySI),
+								core.ResourceStorage: *resource.NewQuantity(sizing.VolumeSize(disk.Capacity, utils.FormatRaw, &pvblock), resource.BinarySI),
 							},
 						},
 						DataSourceRef: &core.TypedObjectReference{
@@ -1376,8 +1974,8 @@ func (r *Builder) PopulatorVolumes(vmRef ref.Ref, annotations map[string]string,
 				}
 				// set the access mode and volume mode if they were specified in the storage map.
 				// otherwise, let the storage profile decide the default values.
-				if mapped.Destination.AccessMode != "" {
-					pvc.Spec.AccessModes = []core.PersistentVolumeAccessMode{mapped.Destination.AccessMode}
+				if accessModes := mapped.Destination.GetAccessModes(); len(accessModes) > 0 {
+					pvc.Spec.AccessModes = accessModes
 				}
 
 				if annotations == nil {
@@ -1387,6 +1985,7 @@ func (r *Builder) PopulatorVolumes(vmRef ref.Ref, annotations map[string]string,
 				}
 				pvc.Annotations[planbase.AnnDiskSource] = baseVolume(disk.File, false)
 				pvc.Annotations["copy-offload"] = baseVolume(disk.File, false)
+				pvc.Annotations = mapped.Destination.Annotate(pvc.Annotations)
 				pvcs = append(pvcs, &pvc)
 
 				vp := api.VSphereXcopyVolumePopulator{
@@ -2,6 +2,8 @@ package vsphere
 
 import (
 	v1beta1 "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
 	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
 	container "github.com/kubev2v/forklift/pkg/controller/provider/container/vsphere"
 	"github.com/kubev2v/forklift/pkg/controller/provider/model/vsphere"
@@ -11,7 +13,11 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/vmware/govmomi/vim25/types"
 	v1 "k8s.io/api/apps/v1"
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	cnv "kubevirt.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -263,6 +269,191 @@ var _ = Describe("vSphere builder", func() {
 			},
 		),
 	)
+
+	It("should map the passthrough devices", func() {
+		builder := createBuilder()
+		builder.Context.Plan.Spec.DeviceMap = []plan.DeviceMapping{
+			{Source: "grid_t4-4q", ResourceName: "nvidia.com/GRID_T4-4Q", Mediated: true},
+			{Source: "10de:1eb8", ResourceName: "nvidia.com/TU104GL_Tesla_T4"},
+		}
+		vm := &model.VM{}
+		vm.Devices = []vsphere.Device{
+			{Kind: PCIPassthrough, Profile: "grid_t4-4q"},
+			{Kind: PCIPassthrough, Profile: "10de:1eb8"},
+			{Kind: PCIPassthrough, Profile: "8086:0d58"},
+			{Kind: "VirtualUSBController"},
+		}
+		object := &cnv.VirtualMachineSpec{Template: &cnv.VirtualMachineInstanceTemplateSpec{}}
+		builder.mapDevices(vm, object)
+		devices := object.Template.Spec.Domain.Devices
+		Expect(devices.GPUs).To(Equal([]cnv.GPU{{Name: "gpu-0", DeviceName: "nvidia.com/GRID_T4-4Q"}}))
+		Expect(devices.HostDevices).To(Equal([]cnv.HostDevice{{Name: "hostdevice-0", DeviceName: "nvidia.com/TU104GL_Tesla_T4"}}))
+	})
+
+	It("should preserve the source boot order", func() {
+		builder := createBuilder()
+		vm := &model.VM{}
+		vm.ID = "test"
+		vm.BootOrder = []vsphere.BootDevice{
+			{Kind: vsphere.BootCdrom},
+			{Kind: vsphere.BootEthernet, Key: 4000},
+			{Kind: vsphere.BootDisk, Key: 2001},
+		}
+		Expect(builder.preserveBootOrder(vm)).To(BeTrue())
+		Expect(sourceBootOrder(vm, vsphere.BootEthernet, 4000)).To(Equal(ptr.To(uint(1))))
+		Expect(sourceBootOrder(vm, vsphere.BootDisk, 2001)).To(Equal(ptr.To(uint(2))))
+		Expect(sourceBootOrder(vm, vsphere.BootDisk, 2000)).To(BeNil())
+
+		// The root disk selected by the plan takes precedence.
+		builder.Context.Plan.Spec.VMs = []plan.VM{{Ref: ref.Ref{ID: "test"}, RootDisk: "/dev/sdb"}}
+		Expect(builder.preserveBootOrder(vm)).To(BeFalse())
+
+		// The source boot order without disk is not preserved.
+		vm.BootOrder = []vsphere.BootDevice{{Kind: vsphere.BootEthernet, Key: 4000}}
+		builder.Context.Plan.Spec.VMs = nil
+		Expect(builder.preserveBootOrder(vm)).To(BeFalse())
+	})
+
+	It("should persist the UEFI variables", func() {
+		builder := createBuilder()
+		persistent := func(vm *model.VM) *bool {
+			object := &cnv.VirtualMachineSpec{Template: &cnv.VirtualMachineInstanceTemplateSpec{}}
+			builder.mapFirmware(vm, object)
+			return object.Template.Spec.Domain.Firmware.Bootloader.EFI.Persistent
+		}
+		vm := &model.VM{}
+		vm.ID = "test"
+		vm.Firmware = Efi
+		Expect(persistent(vm)).To(BeNil())
+		vm.SecureBoot = true
+		Expect(persistent(vm)).To(Equal(ptr.To(true)))
+		vm.SecureBoot = false
+		vm.BootOrder = []vsphere.BootDevice{{Kind: vsphere.BootDisk, Key: 2000}}
+		Expect(persistent(vm)).To(Equal(ptr.To(true)))
+		vm.BootOrder = nil
+		vm.TpmEnabled = true
+		Expect(persistent(vm)).To(Equal(ptr.To(true)))
+	})
+
+	arrays := []StorageArray{
+		{
+			Datastores:           []string{"ds-1", "ds-2"},
+			StorageClasses:       []string{"array-1"},
+			StorageVendorProduct: v1beta1.StorageVendorProductOntap,
+		},
+		{
+			Datastores:           []string{"ds-3"},
+			StorageClasses:       []string{"array-2", "array-2-fast"},
+			StorageVendorProduct: v1beta1.StorageVendorProductVantara,
+		},
+	}
+	DescribeTable("should decode the guestinfo", func(data, encoding, decoded string) {
+		actual, err := guestInfoData(data, encoding)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(actual).To(Equal(decoded))
+	},
+		Entry("plain", "#cloud-config\n", "", "#cloud-config\n"),
+		Entry("base64", "I2Nsb3VkLWNvbmZpZwo=", GuestInfoBase64, "#cloud-config\n"),
+		Entry("b64", "I2Nsb3VkLWNvbmZpZwo=", GuestInfoB64, "#cloud-config\n"),
+		Entry("gzip+base64", "H4sIAAAAAAACA1NOzskvTdFNzs9Ly0znAgAFVrO4DgAAAA==", GuestInfoGzipBase64, "#cloud-config\n"),
+	)
+
+	DescribeTable("should split the datastore file name", func(fileName, dsName, filePath string, parsed bool) {
+		actualDs, actualPath, actualParsed := datastoreFile(fileName)
+		Expect(actualParsed).To(Equal(parsed))
+		Expect(actualDs).To(Equal(dsName))
+		Expect(actualPath).To(Equal(filePath))
+	},
+		Entry("file", "[datastore1] iso/rhel9.iso", "datastore1", "iso/rhel9.iso", true),
+		Entry("spaces", "[NFS ISO] images/win 2022.iso", "NFS ISO", "images/win 2022.iso", true),
+		Entry("no datastore", "iso/rhel9.iso", "", "", false),
+		Entry("no path", "[datastore1]", "datastore1", "", false),
+	)
+
+	DescribeTable("should find the datacenter path", func(dsPath, dcPath string) {
+		Expect(datacenterPath(dsPath)).To(Equal(dcPath))
+	},
+		Entry("datacenter", "/DC1/datastore/ds1", "DC1"),
+		Entry("folders", "/East/DC1/datastore/iso/ds1", "East/DC1"),
+	)
+
+	DescribeTable("should normalize the WWN", func(wwn, normalized string) {
+		Expect(normalizedWWN(wwn)).To(Equal(normalized))
+	},
+		Entry("naa", "naa.600A0B800029E6F6000011A354E2B0A4", "600a0b800029e6f6000011a354e2b0a4"),
+		Entry("multipath", "3600a0b800029e6f6000011a354e2b0a4", "600a0b800029e6f6000011a354e2b0a4"),
+		Entry("naa 5", "35000c500a1b2c3d4", "5000c500a1b2c3d4"),
+		Entry("eui", "eui.0025385b71b16a11", "0025385b71b16a11"),
+	)
+
+	It("should find the RDM volume", func() {
+		block := core.PersistentVolumeBlock
+		filesystem := core.PersistentVolumeFilesystem
+		pv := func(name string, mode *core.PersistentVolumeMode, policy core.PersistentVolumeReclaimPolicy, wwid string) core.PersistentVolume {
+			return core.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Spec: core.PersistentVolumeSpec{
+					PersistentVolumeSource: core.PersistentVolumeSource{
+						FC: &core.FCVolumeSource{WWIDs: []string{wwid}},
+					},
+					VolumeMode:                    mode,
+					PersistentVolumeReclaimPolicy: policy,
+				},
+				Status: core.PersistentVolumeStatus{Phase: core.VolumeAvailable},
+			}
+		}
+		disk := vsphere.Disk{RDM: true, WWN: "600a0b800029e6f6000011a354e2b0a4"}
+		pvs := []core.PersistentVolume{
+			pv("other", &block, core.PersistentVolumeReclaimRetain, "3600a0b800029e6f6000011a354e2b0ff"),
+			pv("filesystem", &filesystem, core.PersistentVolumeReclaimRetain, "3600a0b800029e6f6000011a354e2b0a4"),
+			pv("deleted", &block, core.PersistentVolumeReclaimDelete, "3600a0b800029e6f6000011a354e2b0a4"),
+		}
+		Expect(findRDMVolume(pvs, disk, "target")).To(BeNil())
+		claimed := pv("claimed", &block, core.PersistentVolumeReclaimRetain, "3600a0b800029e6f6000011a354e2b0a4")
+		claimed.Spec.ClaimRef = &core.ObjectReference{Namespace: "other"}
+		claimed.Status.Phase = core.VolumeBound
+		pvs = append(pvs, claimed)
+		Expect(findRDMVolume(pvs, disk, "target")).To(BeNil())
+		Expect(findRDMVolume(pvs, disk, "other").Name).To(Equal("claimed"))
+		annotated := pv("annotated", &block, core.PersistentVolumeReclaimRetain, "")
+		annotated.Spec.FC = nil
+		annotated.Annotations = map[string]string{AnnWWN: "naa.600a0b800029e6f6000011a354e2b0a4"}
+		pvs = append(pvs, annotated)
+		Expect(findRDMVolume(pvs, disk, "target").Name).To(Equal("annotated"))
+		Expect(findRDMVolume(pvs, vsphere.Disk{RDM: true}, "target")).To(BeNil())
+	})
+
+	DescribeTable("should carry over the metadata network", func(metaData, network string) {
+		actual, err := metaDataNetwork(metaData)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(actual).To(Equal(network))
+	},
+		Entry("no metadata", "", ""),
+		Entry("no network", "instance-id: vm-1\n", ""),
+		Entry("yaml", "instance-id: vm-1\nnetwork:\n  version: 2\n", "version: 2\n"),
+		Entry("json", `{"instance-id": "vm-1", "network": {"version": 2}}`, "version: 2\n"),
+		Entry("encoded", "network: dmVyc2lvbjogMgo=\nnetwork.encoding: base64\n", "version: 2\n"),
+	)
+
+	It("should reject the unknown guestinfo encoding", func() {
+		_, err := guestInfoData("data", "rot13")
+		Expect(err).To(HaveOccurred())
+	})
+
+	DescribeTable("should find the shared storage array", func(datastore, storageClass string, vendor v1beta1.StorageVendorProduct) {
+		array := sharedArray(arrays, datastore, storageClass)
+		if vendor == "" {
+			Expect(array).To(BeNil())
+		} else {
+			Expect(array).ToNot(BeNil())
+			Expect(array.StorageVendorProduct).To(Equal(vendor))
+		}
+	},
+		Entry("same array", "ds-2", "array-1", v1beta1.StorageVendorProductOntap),
+		Entry("same array with several classes", "ds-3", "array-2-fast", v1beta1.StorageVendorProductVantara),
+		Entry("different arrays", "ds-1", "array-2", v1beta1.StorageVendorProduct("")),
+		Entry("unmapped datastore", "ds-4", "array-1", v1beta1.StorageVendorProduct("")),
+	)
 })
 
 //nolint:errcheck
@@ -11,8 +11,11 @@ import (
 	"github.com/kubev2v/forklift/pkg/controller/base"
 	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
 	"github.com/kubev2v/forklift/pkg/controller/plan/util"
+	container "github.com/kubev2v/forklift/pkg/controller/provider/container/vsphere"
 	model "github.com/kubev2v/forklift/pkg/controller/provider/web/vsphere"
 	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	libutil "github.com/kubev2v/forklift/pkg/lib/util"
+	metrics "github.com/kubev2v/forklift/pkg/monitoring/metrics/forklift-controller"
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/property"
@@ -38,6 +41,8 @@ type Client struct {
 	*plancontext.Context
 	client      *govmomi.Client
 	hostClients map[string]*govmomi.Client
+	// Clients for the additional vCenters by key.
+	vcenterClients map[string]*govmomi.Client
 }
 
 // Create a VM snapshot and return its ID.
@@ -47,7 +52,7 @@ func (r *Client) CreateSnapshot(vmRef ref.Ref, hostsFunc util.HostsFunc) (snapsh
 	if err != nil {
 		return
 	}
-	task, err := vm.CreateSnapshot(context.TODO(), snapshotName, snapshotDesc, false, true)
+	task, err := vm.CreateSnapshot(context.TODO(), snapshotName, snapshotDesc, false, r.quiesce(vmRef))
 	if err != nil {
 		err = liberr.Wrap(err)
 		return
@@ -55,6 +60,13 @@ func (r *Client) CreateSnapshot(vmRef ref.Ref, hostsFunc util.HostsFunc) (snapsh
 	return "", task.Reference().Value, nil
 }
 
+// Determine whether the guest is quiesced by VMware Tools
+// when the snapshot is taken. Quiesced unless disabled.
+func (r *Client) quiesce(vmRef ref.Ref) bool {
+	vm, found := r.Plan.Spec.FindVM(vmRef)
+	return !found || vm.Quiesce == nil || *vm.Quiesce
+}
+
 // Remove a VM snapshot.
 func (r *Client) RemoveSnapshot(vmRef ref.Ref, snapshot string, hosts util.HostsFunc) (taskId string, err error) {
 	r.Log.V(1).Info("RemoveSnapshot",
@@ -185,6 +197,11 @@ func (r *Client) Close() {
 		client.CloseIdleConnections()
 	}
 	r.hostClients = nil
+	for _, client := range r.vcenterClients {
+		_ = client.Logout(context.TODO())
+		client.CloseIdleConnections()
+	}
+	r.vcenterClients = nil
 }
 
 func (c *Client) Finalize(vms []*planapi.VMStatus, planName string) {
@@ -334,14 +351,14 @@ func (r *Client) getClient(vm *model.VM, hosts util.HostsFunc) (client *vim25.Cl
 	if useV2vForTransfer, vErr := r.Plan.ShouldUseV2vForTransfer(); vErr == nil && useV2vForTransfer {
 		// when virt-v2v runs the migration, forklift-controller should interact only
 		// with the component that serves the SDK endpoint of the provider
-		client = r.client.Client
+		client, err = r.vCenterClient(vm)
 		return
 	}
 
 	if r.Source.Provider.Spec.Settings[v1beta1.SDK] == v1beta1.ESXI {
 		// when migrating from ESXi host, we use the client of the SDK endpoint of the provider,
 		// there's no need in a different client (the ESXi host is the only component involved in the migration)
-		client = r.client.Client
+		client, err = r.vCenterClient(vm)
 		return
 	}
 
@@ -364,7 +381,7 @@ func (r *Client) getClient(vm *model.VM, hosts util.HostsFunc) (client *vim25.Cl
 		} else {
 			// there is no network defined for the ESXi host, so we will transfer the disk(s) from vCenter and
 			// thus there is no need in a client for the ESXi host but we use the client for vCenter instead
-			client = r.client.Client
+			client, err = r.vCenterClient(vm)
 		}
 	} else {
 		err = liberr.Wrap(hostsErr)
@@ -373,7 +390,7 @@ func (r *Client) getClient(vm *model.VM, hosts util.HostsFunc) (client *vim25.Cl
 }
 
 func (r *Client) getHostClient(hostDef *v1beta1.Host, host *model.Host) (client *vim25.Client, err error) {
-	url, err := liburl.Parse("https://" + hostDef.Spec.IpAddress + "/sdk")
+	url, err := liburl.Parse("https://" + libutil.URLHost(hostDef.Spec.IpAddress) + "/sdk")
 	if err != nil {
 		err = liberr.Wrap(err)
 		return
@@ -395,6 +412,8 @@ func (r *Client) getHostClient(hostDef *v1beta1.Host, host *model.Host) (client
 
 	url.User = liburl.UserPassword(string(secret.Data["user"]), string(secret.Data["password"]))
 	soapClient := soap.NewClient(url, base.GetInsecureSkipVerifyFlag(r.Source.Secret))
+	soapClient.DefaultTransport().Proxy = libutil.ProviderProxy(r.Source.Provider)
+	soapClient.DefaultTransport().DialContext = libutil.ProviderDialer(r.Source.Provider, nil)
 	soapClient.SetThumbprint(url.Host, host.Thumbprint)
 	vimClient, err := vim25.NewClient(context.TODO(), soapClient)
 	if err != nil {
@@ -480,11 +499,18 @@ func (r *Client) connect() error {
 	}
 	url.User = liburl.UserPassword(r.user(), r.password())
 	soapClient := soap.NewClient(url, base.GetInsecureSkipVerifyFlag(r.Source.Secret))
+	soapClient.DefaultTransport().Proxy = libutil.ProviderProxy(r.Source.Provider)
+	soapClient.DefaultTransport().DialContext = libutil.ProviderDialer(r.Source.Provider, nil)
 	soapClient.SetThumbprint(url.Host, r.thumbprint())
 	vimClient, err := vim25.NewClient(context.TODO(), soapClient)
 	if err != nil {
 		return liberr.Wrap(err)
 	}
+	vimClient.RoundTripper = &container.ThrottledRoundTripper{
+		RoundTripper: vimClient.RoundTripper,
+		Provider:     r.Source.Provider,
+		Throttled:    metrics.RecordThrottled,
+	}
 	r.client = &govmomi.Client{
 		SessionManager: session.NewManager(vimClient),
 		Client:         vimClient,
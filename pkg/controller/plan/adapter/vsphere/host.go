@@ -5,6 +5,7 @@ import (
 	liburl "net/url"
 	"time"
 
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
 	"github.com/kubev2v/forklift/pkg/controller/base"
 	model "github.com/kubev2v/forklift/pkg/controller/provider/web/vsphere"
 	liberr "github.com/kubev2v/forklift/pkg/lib/error"
@@ -23,6 +24,8 @@ type EsxHost struct {
 	URL string
 	// Host secret.
 	Secret *core.Secret
+	// Provider (proxy settings).
+	Provider *api.Provider
 	// Host client.
 	client *govmomi.Client
 	// Finder
@@ -86,6 +89,8 @@ func (r *EsxHost) connect(ctx context.Context) (err error) {
 	}
 
 	soapClient := soap.NewClient(url, skipVerifying)
+	soapClient.DefaultTransport().Proxy = util.ProviderProxy(r.Provider)
+	soapClient.DefaultTransport().DialContext = util.ProviderDialer(r.Provider, nil)
 	soapClient.SetThumbprint(url.Host, thumbprint)
 	vimClient, err := vim25.NewClient(ctx, soapClient)
 	if err != nil {
@@ -0,0 +1,117 @@
+package vsphere
+
+import (
+	"context"
+	"os"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/web/vsphere"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	"github.com/kubev2v/forklift/pkg/settings"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// Storage array backing both vSphere datastores and
+// destination storage classes. Disks on a datastore mapped to
+// a storage class of the same array are cloned by the array
+// (copy offload) instead of being copied over the network.
+type StorageArray struct {
+	// Names of the datastores backed by the array.
+	Datastores []string `json:"datastores"`
+	// Names of the storage classes backed by the array.
+	StorageClasses []string `json:"storageClasses"`
+	// Storage vendor product driving the array-level clone.
+	StorageVendorProduct api.StorageVendorProduct `json:"storageVendorProduct"`
+	// Secret with the array credentials, in the provider namespace.
+	SecretRef string `json:"secretRef"`
+}
+
+// Find the array shared by the datastore and the storage class.
+func sharedArray(arrays []StorageArray, datastore, storageClass string) (array *StorageArray) {
+	contains := func(list []string, name string) bool {
+		for _, listed := range list {
+			if listed == name {
+				return true
+			}
+		}
+		return false
+	}
+	for i := range arrays {
+		candidate := &arrays[i]
+		if contains(candidate.Datastores, datastore) && contains(candidate.StorageClasses, storageClass) {
+			array = candidate
+			return
+		}
+	}
+
+	return
+}
+
+// Load the storage arrays from the copy offload mapping config map.
+// Each entry of the config map describes one array.
+func (r *Builder) storageArrays() (arrays []StorageArray, err error) {
+	if r.arrays != nil {
+		arrays = r.arrays
+		return
+	}
+	name := settings.Settings.Migration.CopyOffloadMappingConfigMap
+	if name == "" {
+		return
+	}
+	configMap := &core.ConfigMap{}
+	err = r.Get(
+		context.TODO(),
+		client.ObjectKey{Name: name, Namespace: os.Getenv("POD_NAMESPACE")},
+		configMap)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	arrays = []StorageArray{}
+	for key, value := range configMap.Data {
+		array := StorageArray{}
+		err = yaml.Unmarshal([]byte(value), &array)
+		if err != nil {
+			err = liberr.Wrap(err, "configMap", name, "array", key)
+			return
+		}
+		arrays = append(arrays, array)
+	}
+	r.arrays = arrays
+
+	return
+}
+
+// Offload plugin for the storage pair. An offload plugin set on
+// the storage map takes precedence, otherwise the plugin is derived
+// from the copy offload mapping when the datastore and the storage
+// class are backed by the same storage array.
+func (r *Builder) offloadPlugin(mapped *api.StoragePair, ds *model.Datastore) (plugin *api.OffloadPlugin, err error) {
+	if mapped.OffloadPlugin != nil && mapped.OffloadPlugin.VSphereXcopyPluginConfig != nil {
+		plugin = mapped.OffloadPlugin
+		return
+	}
+	arrays, err := r.storageArrays()
+	if err != nil {
+		return
+	}
+	array := sharedArray(arrays, ds.Name, mapped.Destination.StorageClass)
+	if array == nil {
+		return
+	}
+	plugin = &api.OffloadPlugin{
+		VSphereXcopyPluginConfig: &api.VSphereXcopyPluginConfig{
+			SecretRef:            array.SecretRef,
+			StorageVendorProduct: array.StorageVendorProduct,
+		},
+	}
+
+	return
+}
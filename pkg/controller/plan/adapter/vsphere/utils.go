@@ -103,3 +103,76 @@ func findSharedPVCs(client client.Client, vm *model.VM, targetNamespace string)
 	}
 	return pvcs, missingDiskPVCs, err
 }
+
+// Annotation of the WWN of the LUN presented by a PV
+// without an FC source (e.g. iSCSI, CSI or local).
+const AnnWWN = "forklift.konveyor.io/wwn"
+
+// Return all PVs.
+func listPersistentVolumes(c client.Client) (pvs []core.PersistentVolume, err error) {
+	pvList := &core.PersistentVolumeList{}
+	err = c.List(context.TODO(), pvList)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	pvs = pvList.Items
+	return
+}
+
+// Find the pre-existing PV presenting the LUN of an RDM disk.
+// The PV must be a retained block volume, either available
+// or claimed in the target namespace (by a previous attempt).
+func findRDMVolume(pvs []core.PersistentVolume, disk vsphere.Disk, targetNamespace string) *core.PersistentVolume {
+	wwn := normalizedWWN(disk.WWN)
+	if wwn == "" {
+		return nil
+	}
+	for i := range pvs {
+		pv := &pvs[i]
+		if pv.Spec.VolumeMode == nil || *pv.Spec.VolumeMode != core.PersistentVolumeBlock {
+			continue
+		}
+		if pv.Spec.PersistentVolumeReclaimPolicy != core.PersistentVolumeReclaimRetain {
+			continue
+		}
+		claimed := pv.Spec.ClaimRef != nil
+		if claimed && pv.Spec.ClaimRef.Namespace != targetNamespace {
+			continue
+		}
+		if !claimed && pv.Status.Phase != core.VolumeAvailable {
+			continue
+		}
+		for _, volumeWWN := range volumeWWNs(pv) {
+			if normalizedWWN(volumeWWN) == wwn {
+				return pv
+			}
+		}
+	}
+	return nil
+}
+
+// WWNs of the LUN presented by a PV.
+func volumeWWNs(pv *core.PersistentVolume) (wwns []string) {
+	if pv.Spec.FC != nil {
+		wwns = append(wwns, pv.Spec.FC.WWIDs...)
+	}
+	if wwn, found := pv.Annotations[AnnWWN]; found {
+		wwns = append(wwns, wwn)
+	}
+	return
+}
+
+// Normalize a WWN to the NAA (or EUI) identifier in lower case.
+// The multipath WWIDs are prefixed by the identifier
+// type, e.g. 3600a0b80... for the NAA 600a0b80...
+func normalizedWWN(wwn string) string {
+	wwn = strings.ToLower(strings.TrimSpace(wwn))
+	for _, prefix := range []string{"naa.", "eui.", "0x"} {
+		wwn = strings.TrimPrefix(wwn, prefix)
+	}
+	if (len(wwn) == 33 || len(wwn) == 17) && (wwn[0] == '3' || wwn[0] == '2') {
+		wwn = wwn[1:]
+	}
+	return wwn
+}
@@ -6,6 +6,7 @@ import (
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
 	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
 	planbase "github.com/kubev2v/forklift/pkg/controller/plan/adapter/base"
+	"github.com/kubev2v/forklift/pkg/controller/plan/util"
 	"github.com/kubev2v/forklift/pkg/controller/provider/model/vsphere"
 	"github.com/kubev2v/forklift/pkg/controller/provider/web"
 	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
@@ -13,6 +14,7 @@ import (
 	"github.com/kubev2v/forklift/pkg/controller/validation"
 	liberr "github.com/kubev2v/forklift/pkg/lib/error"
 	"github.com/vmware/govmomi/vim25/types"
+	core "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -67,21 +69,15 @@ func (r *Validator) PodNetwork(vmRef ref.Ref) (ok bool, err error) {
 		return
 	}
 
-	mapping := r.plan.Referenced.Map.Network.Spec.Map
 	podMapped := 0
-	for i := range mapping {
-		mapped := &mapping[i]
-		ref := mapped.Source
-		network := &model.Network{}
-		fErr := r.inventory.Find(network, ref)
+	for i, nic := range vm.NICs {
+		mapped, fErr := r.findNetworkMapping(&vm.VM, i, nic)
 		if fErr != nil {
 			err = fErr
 			return
 		}
-		for _, nic := range vm.NICs {
-			if nic.Network.ID == network.ID && mapped.Destination.Type == Pod {
-				podMapped++
-			}
+		if mapped != nil && mapped.Destination.Type == Pod {
+			podMapped++
 		}
 	}
 
@@ -101,6 +97,9 @@ func (r *Validator) StorageMapped(vmRef ref.Ref) (ok bool, err error) {
 		return
 	}
 
+	if r.plan.Spec.RDMDisks != nil {
+		vm.RemoveRDMDisks()
+	}
 	for _, disk := range vm.Disks {
 		if !r.plan.Referenced.Map.Storage.Status.Refs.Find(ref.Ref{ID: disk.Datastore.ID}) {
 			return
@@ -338,3 +337,235 @@ func (r *Validator) ChangeTrackingEnabled(vmRef ref.Ref) (bool, error) {
 	}
 	return vm.ChangeTrackingEnabled, nil
 }
+
+// Return the CPU model of the VM host cluster EVC mode.
+func (r *Validator) CPUModel(vmRef ref.Ref) (cpuModel string, features []string, err error) {
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	if vm.Host == "" {
+		return
+	}
+	host := &model.Host{}
+	err = r.inventory.Get(host, vm.Host)
+	if err != nil {
+		err = liberr.Wrap(err, "host", vm.Host)
+		return
+	}
+	cpuModel, err = evcCPUModel(r.inventory, host)
+	return
+}
+
+// Return the guest ID and the guest name.
+func (r *Validator) GuestOS(vmRef ref.Ref) (ids []string, err error) {
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	ids = []string{vm.GuestID, vm.GuestName}
+	return
+}
+
+// Return the VM NICs with the destination networks they are mapped to.
+func (r *Validator) NICs(vmRef ref.Ref) (nics []planbase.NIC, err error) {
+	if r.plan.Referenced.Map.Network == nil {
+		return
+	}
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+
+	for i, nic := range vm.NICs {
+		mapped, fErr := r.findNetworkMapping(vm, i, nic)
+		if fErr != nil {
+			err = fErr
+			return
+		}
+		if mapped != nil {
+			nics = append(nics, planbase.NIC{MAC: nic.MAC, Destination: mapped.Destination})
+		}
+	}
+	return
+}
+
+// Find the most specific network mapping of the VM NIC.
+func (r *Validator) findNetworkMapping(vm *model.VM, index int, nic vsphere.NIC) (mapped *api.NetworkPair, err error) {
+	vmRef := ref.Ref{ID: vm.ID, Name: vm.Name}
+	mapped = r.plan.Referenced.Map.Network.FindNetworkForNIC(vmRef, index, func(candidate *api.NetworkPair) bool {
+		if err != nil {
+			return false
+		}
+		network := &model.Network{}
+		err = r.inventory.Find(network, candidate.Source)
+		if err != nil {
+			return false
+		}
+		return nicOnNetwork(nic, network)
+	})
+	if err != nil {
+		mapped = nil
+	}
+	return
+}
+
+// Determine whether the target VM will be created with a persistent TPM device.
+// The vTPM is not mapped for BIOS VMs.
+func (r *Validator) PersistentTPM(vmRef ref.Ref) (persistent bool, err error) {
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	persistent = vm.TpmEnabled && vm.Firmware != BIOS
+	return
+}
+
+// Return the VM RDM disks that cannot be migrated as set by the plan.
+// All of them when not set, and those with no pre-existing PV
+// presenting the same LUN when mapped.
+func (r *Validator) RDMDisks(vmRef ref.Ref, client client.Client) (unmapped []string, err error) {
+	if r.plan.Spec.RDMDisks.Skipped() {
+		return
+	}
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	if !vm.HasRDMDisk() {
+		return
+	}
+	var pvs []core.PersistentVolume
+	if r.plan.Spec.RDMDisks.Mapped() {
+		pvs, err = listPersistentVolumes(client)
+		if err != nil {
+			return
+		}
+	}
+	for _, disk := range vm.Disks {
+		if !disk.RDM {
+			continue
+		}
+		if findRDMVolume(pvs, disk, r.plan.Spec.TargetNamespace) == nil {
+			unmapped = append(unmapped, disk.File)
+		}
+	}
+	return
+}
+
+// Return the VM independent disks.
+// RDM disks skipped or mapped to PVs are not copied.
+func (r *Validator) IndependentDisks(vmRef ref.Ref) (disks []string, err error) {
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	for _, disk := range vm.Disks {
+		if disk.RDM && r.plan.Spec.RDMDisks != nil {
+			continue
+		}
+		if disk.Independent() {
+			disks = append(disks, disk.File)
+		}
+	}
+	return
+}
+
+// Return the (CD-ROM and floppy) devices in the preserved
+// source boot order, which are not migrated.
+func (r *Validator) UnmigratedBootDevices(vmRef ref.Ref) (devices []string, err error) {
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	if !preserveBootOrder(r.plan, vm) {
+		return
+	}
+	for _, device := range vm.BootOrder {
+		if device.Kind != vsphere.BootDisk && device.Kind != vsphere.BootEthernet {
+			devices = append(devices, device.Kind)
+		}
+	}
+	return
+}
+
+// Return the profiles of the VM PCI passthrough (GPU) devices.
+func (r *Validator) PassthroughDevices(vmRef ref.Ref) (profiles []string, err error) {
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	for _, device := range vm.Devices {
+		if device.Kind == PCIPassthrough {
+			profiles = append(profiles, device.Profile)
+		}
+	}
+	return
+}
+
+// Return the DRS rules of the VM host cluster covering the VM.
+func (r *Validator) AffinityRules(vmRef ref.Ref) (rules []util.AffinityRule, err error) {
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	if vm.Host == "" {
+		return
+	}
+	host := &model.Host{}
+	err = r.inventory.Get(host, vm.Host)
+	if err != nil {
+		err = liberr.Wrap(err, "host", vm.Host)
+		return
+	}
+	rules, err = clusterAffinityRules(r.inventory, host, vm.ID)
+	return
+}
+
+// Return the CPU, memory and disk capacity (by mapped storage class) of the VM.
+func (r *Validator) Demand(vmRef ref.Ref) (demand *planbase.Demand, err error) {
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	allocation := memoryAllocation(vm)
+	demand = &planbase.Demand{
+		CPU:     int64(vm.CpuCount),
+		Memory:  allocation.Size,
+		Storage: map[string]int64{},
+	}
+	// Backed by hugepages when the placement is preserved.
+	if !r.plan.Spec.PreserveCPUPlacement || !vm.HugePages1G {
+		demand.Memory = allocation.Request(r.plan.Spec.Memory)
+	}
+	if r.plan.Referenced.Map.Storage == nil {
+		return
+	}
+	for _, disk := range vm.Disks {
+		mapped, found := r.plan.Referenced.Map.Storage.FindStorage(disk.Datastore.ID)
+		if found && mapped.Destination.StorageClass != "" {
+			demand.Storage[mapped.Destination.StorageClass] += disk.Capacity
+		}
+	}
+	return
+}
@@ -40,6 +40,13 @@ func (m *mockInventory) Find(resource interface{}, ref ref.Ref) error {
 		if ref.Name == "missing_from_invetory" {
 			return base.NotFoundError{}
 		}
+	case *model.VM:
+		if ref.Name == "boot_cdrom" {
+			res.BootOrder = []vsphere.BootDevice{
+				{Kind: vsphere.BootCdrom},
+				{Kind: vsphere.BootDisk, Key: 2000},
+			}
+		}
 	}
 	return nil
 }
@@ -49,6 +56,12 @@ func (m *mockInventory) Finder() web.Finder {
 }
 
 func (m *mockInventory) Get(resource interface{}, id string) error {
+	switch res := resource.(type) {
+	case *model.Cluster:
+		if id == "evc" {
+			res.EvcMode = "intel-haswell"
+		}
+	}
 	return nil
 }
 
@@ -109,6 +122,36 @@ var _ = Describe("vsphere validation tests", func() {
 			Entry("when the vm doesn't exist", "missing_from_invetory", true, true),
 		)
 	})
+
+	Describe("UnmigratedBootDevices", func() {
+		It("should report the boot devices not migrated", func() {
+			validator := &Validator{
+				plan:      createPlan(),
+				inventory: &mockInventory{},
+			}
+			devices, err := validator.UnmigratedBootDevices(ref.Ref{Name: "boot_cdrom"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(devices).To(Equal([]string{vsphere.BootCdrom}))
+			devices, err = validator.UnmigratedBootDevices(ref.Ref{Name: "test"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(devices).To(BeEmpty())
+		})
+	})
+
+	Describe("evcCPUModel", func() {
+		DescribeTable("should translate the cluster EVC mode",
+			func(cluster string, cpuModel string) {
+				host := &model.Host{}
+				host.Cluster = cluster
+				translated, err := evcCPUModel(&mockInventory{}, host)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(translated).To(Equal(cpuModel))
+			},
+			Entry("when the host is not in a cluster", "", ""),
+			Entry("when the cluster has EVC enabled", "evc", "Haswell-noTSX"),
+			Entry("when the cluster has EVC disabled", "other", ""),
+		)
+	})
 })
 
 func createPlan() *v1beta1.Plan {
@@ -0,0 +1,93 @@
+package vsphere
+
+import (
+	"context"
+	liburl "net/url"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/controller/base"
+	container "github.com/kubev2v/forklift/pkg/controller/provider/container/vsphere"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/web/vsphere"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	libutil "github.com/kubev2v/forklift/pkg/lib/util"
+	metrics "github.com/kubev2v/forklift/pkg/monitoring/metrics/forklift-controller"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/session"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/soap"
+	core "k8s.io/api/core/v1"
+)
+
+// The SDK URL and certificate thumbprint of the vCenter
+// managing the VM. The provider vCenter unless the VM is
+// enumerated from an additional vCenter (linked mode).
+// The certificate of an additional vCenter is verified
+// unless the provider is insecure.
+func vCenterOf(provider *api.Provider, secret *core.Secret, vm *model.VM) (url *liburl.URL, thumbprint string, err error) {
+	url, err = liburl.Parse(provider.Spec.URL)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if vm.VCenter == "" {
+		thumbprint = provider.Status.Fingerprint
+		return
+	}
+	url.Host = vm.VCenter
+	if base.GetInsecureSkipVerifyFlag(secret) {
+		return
+	}
+	crt, err := base.VerifyTLSConnection(url.String(), secret)
+	if err != nil {
+		err = liberr.Wrap(err, "vcenter", vm.VCenter)
+		return
+	}
+	thumbprint = libutil.Fingerprint(crt)
+	return
+}
+
+// The client for the vCenter managing the VM.
+// The clients for the additional vCenters are cached.
+func (r *Client) vCenterClient(vm *model.VM) (client *vim25.Client, err error) {
+	if vm.VCenter == "" {
+		client = r.client.Client
+		return
+	}
+	if cachedClient, found := r.vcenterClients[vm.VCenter]; found {
+		client = cachedClient.Client
+		return
+	}
+	url, thumbprint, err := vCenterOf(r.Source.Provider, r.Source.Secret, vm)
+	if err != nil {
+		return
+	}
+	url.User = liburl.UserPassword(r.user(), r.password())
+	soapClient := soap.NewClient(url, base.GetInsecureSkipVerifyFlag(r.Source.Secret))
+	soapClient.DefaultTransport().Proxy = libutil.ProviderProxy(r.Source.Provider)
+	soapClient.DefaultTransport().DialContext = libutil.ProviderDialer(r.Source.Provider, nil)
+	soapClient.SetThumbprint(url.Host, thumbprint)
+	vimClient, err := vim25.NewClient(context.TODO(), soapClient)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	vimClient.RoundTripper = &container.ThrottledRoundTripper{
+		RoundTripper: vimClient.RoundTripper,
+		Provider:     r.Source.Provider,
+		Throttled:    metrics.RecordThrottled,
+	}
+	vcenterClient := &govmomi.Client{
+		SessionManager: session.NewManager(vimClient),
+		Client:         vimClient,
+	}
+	if err = vcenterClient.Login(context.TODO(), url.User); err != nil {
+		err = liberr.Wrap(err, "vcenter", vm.VCenter)
+		return
+	}
+	if r.vcenterClients == nil {
+		r.vcenterClients = make(map[string]*govmomi.Client)
+	}
+	r.vcenterClients[vm.VCenter] = vcenterClient
+	client = vcenterClient.Client
+	return
+}
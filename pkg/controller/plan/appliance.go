@@ -0,0 +1,189 @@
+package plan
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	"github.com/kubev2v/forklift/pkg/lib/util"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Conversion appliance.
+const (
+	// Appliance API request timeout.
+	ApplianceTimeout = time.Second * 30
+	// Secret key of the appliance API token.
+	ApplianceToken = "token"
+	// Step annotation of the conversion job ID.
+	ConversionJobAnnotation = "conversionJob"
+)
+
+// Conversion job phases.
+const (
+	JobPending   = "Pending"
+	JobRunning   = "Running"
+	JobSucceeded = "Succeeded"
+	JobFailed    = "Failed"
+)
+
+// Conversion submitted to the appliance.
+type ConversionRequest struct {
+	// Plan (namespace/name).
+	Plan string `json:"plan"`
+	// Source VM.
+	VM ref.Ref `json:"vm"`
+	// Namespace of the copied disks.
+	Namespace string `json:"namespace"`
+	// PVCs of the copied disks, ordered by disk index.
+	PVCs []string `json:"pvcs"`
+}
+
+// Conversion job reported by the appliance.
+type ConversionJob struct {
+	// Job ID.
+	ID string `json:"id"`
+	// Phase.
+	Phase string `json:"phase"`
+	// Progress (percent).
+	Percent int `json:"percent"`
+	// Message. The reason of a failure.
+	Message string `json:"message,omitempty"`
+}
+
+// Conversion appliance API client.
+type ApplianceClient struct {
+	// Appliance API URL.
+	URL string
+	// API token.
+	Token string
+	// HTTP client.
+	Client *http.Client
+}
+
+// Build the appliance client of the plan.
+func NewApplianceClient(ctx *plancontext.Context) (appliance *ApplianceClient, err error) {
+	settings := ctx.Plan.Spec.Conversion.Appliance
+	if settings == nil {
+		err = liberr.New("Conversion appliance not set.")
+		return
+	}
+	appliance = &ApplianceClient{
+		URL:    strings.TrimSuffix(settings.URL, "/"),
+		Client: &http.Client{Timeout: ApplianceTimeout},
+	}
+	if settings.Secret == nil {
+		return
+	}
+	secret := &core.Secret{}
+	key := client.ObjectKey{
+		Namespace: settings.Secret.Namespace,
+		Name:      settings.Secret.Name,
+	}
+	if key.Namespace == "" {
+		key.Namespace = ctx.Plan.Namespace
+	}
+	err = ctx.Get(context.TODO(), key, secret)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	appliance.Token = string(secret.Data[ApplianceToken])
+	cfg := &tls.Config{}
+	if util.InsecureProvider(secret) {
+		cfg.InsecureSkipVerify = true
+	} else if cacert, found := secret.Data["cacert"]; found {
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(cacert) {
+			err = liberr.New("failed to parse the specified certificate")
+			return
+		}
+	}
+	appliance.Client.Transport = &http.Transport{TLSClientConfig: cfg}
+	return
+}
+
+// Submit the conversion.
+func (r *ApplianceClient) Submit(request *ConversionRequest) (job *ConversionJob, err error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	job = &ConversionJob{}
+	err = r.do(http.MethodPost, "/conversions", body, job)
+	return
+}
+
+// Get the conversion job.
+func (r *ApplianceClient) Job(id string) (job *ConversionJob, err error) {
+	job = &ConversionJob{}
+	err = r.do(http.MethodGet, "/conversions/"+id, nil, job)
+	return
+}
+
+// Send the request and decode the reply.
+func (r *ApplianceClient) do(method, path string, body []byte, reply interface{}) (err error) {
+	request, err := http.NewRequest(method, r.URL+path, bytes.NewReader(body))
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if r.Token != "" {
+		request.Header.Set("Authorization", "Bearer "+r.Token)
+	}
+	response, err := r.Client.Do(request)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	defer response.Body.Close()
+	if response.StatusCode >= http.StatusBadRequest {
+		err = liberr.New("conversion appliance request failed.", "url", request.URL, "status", response.Status)
+		return
+	}
+	err = json.NewDecoder(response.Body).Decode(reply)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+	return
+}
+
+// Reflect the conversion job in the step and the VM conversion progress.
+func reflectConversionJob(vm *planapi.VMStatus, step *planapi.Step, job *ConversionJob) {
+	if vm.Conversion == nil {
+		vm.Conversion = &planapi.ConversionProgress{}
+	}
+	vm.Conversion.Stage = job.Phase
+	vm.Conversion.Percent = min(max(job.Percent, 0), 100)
+	vm.Conversion.Updated = meta.Now()
+	switch job.Phase {
+	case JobSucceeded:
+		step.MarkCompleted()
+		step.Progress.Completed = step.Progress.Total
+		vm.Conversion.Stage = planapi.ConversionFinished
+		vm.Conversion.Percent = 100
+	case JobFailed:
+		step.MarkCompleted()
+		message := "Guest conversion failed on the appliance."
+		if job.Message != "" {
+			message += " " + job.Message
+		}
+		step.AddError(message)
+	default:
+		step.Phase = api.StepRunning
+	}
+}
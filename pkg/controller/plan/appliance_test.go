@@ -0,0 +1,84 @@
+package plan
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	"github.com/onsi/gomega"
+)
+
+func TestApplianceClient(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	submitted := &ConversionRequest{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/conversions":
+			_ = json.NewDecoder(r.Body).Decode(submitted)
+			_ = json.NewEncoder(w).Encode(&ConversionJob{ID: "1", Phase: JobPending})
+		case r.Method == http.MethodGet && r.URL.Path == "/conversions/1":
+			_ = json.NewEncoder(w).Encode(&ConversionJob{ID: "1", Phase: JobRunning, Percent: 40})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	appliance := &ApplianceClient{URL: server.URL, Token: "secret", Client: server.Client()}
+	job, err := appliance.Submit(&ConversionRequest{
+		Plan:      "ns/plan",
+		VM:        ref.Ref{ID: "vm-1"},
+		Namespace: "target",
+		PVCs:      []string{"disk-0", "disk-1"},
+	})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(job.ID).To(gomega.Equal("1"))
+	g.Expect(submitted.VM.ID).To(gomega.Equal("vm-1"))
+	g.Expect(submitted.PVCs).To(gomega.Equal([]string{"disk-0", "disk-1"}))
+
+	job, err = appliance.Job("1")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(job.Phase).To(gomega.Equal(JobRunning))
+	g.Expect(job.Percent).To(gomega.Equal(40))
+
+	_, err = appliance.Job("2")
+	g.Expect(err).To(gomega.HaveOccurred())
+
+	appliance.Token = ""
+	_, err = appliance.Job("1")
+	g.Expect(err).To(gomega.HaveOccurred())
+}
+
+func TestReflectConversionJob(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	vm := &planapi.VMStatus{}
+	step := &planapi.Step{}
+	step.Progress.Total = 1
+
+	reflectConversionJob(vm, step, &ConversionJob{Phase: JobRunning, Percent: 40})
+	g.Expect(step.Phase).To(gomega.Equal(api.StepRunning))
+	g.Expect(step.MarkedCompleted()).To(gomega.BeFalse())
+	g.Expect(vm.Conversion.Percent).To(gomega.Equal(40))
+
+	reflectConversionJob(vm, step, &ConversionJob{Phase: JobSucceeded})
+	g.Expect(step.MarkedCompleted()).To(gomega.BeTrue())
+	g.Expect(step.HasError()).To(gomega.BeFalse())
+	g.Expect(step.Progress.Completed).To(gomega.Equal(int64(1)))
+	g.Expect(vm.Conversion.Stage).To(gomega.Equal(planapi.ConversionFinished))
+	g.Expect(vm.Conversion.Percent).To(gomega.Equal(100))
+
+	step = &planapi.Step{}
+	reflectConversionJob(vm, step, &ConversionJob{Phase: JobFailed, Message: "No bootable disk."})
+	g.Expect(step.MarkedCompleted()).To(gomega.BeTrue())
+	g.Expect(step.HasError()).To(gomega.BeTrue())
+}
@@ -0,0 +1,105 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Labels
+const (
+	// Backup policy label.
+	kBackupPolicy = "backupPolicy"
+)
+
+// Velero backup.
+var veleroBackupGVK = schema.GroupVersionKind{
+	Group:   "velero.io",
+	Version: "v1",
+	Kind:    "Backup",
+}
+
+// Label the target resource with the plan backup policy.
+func (r *KubeVirt) setBackupLabel(labels map[string]string) map[string]string {
+	backup := r.Plan.Spec.Backup
+	if backup == nil {
+		return labels
+	}
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[kBackupPolicy] = backup.Policy
+	return labels
+}
+
+// Trigger the Velero backup of the target namespace
+// resources labeled with the backup policy. A backup that
+// cannot be created is reported on the migration snapshot
+// and does not fail the migration.
+func (r *Migration) triggerBackup(snapshot *plan.Snapshot) {
+	backup := r.Plan.Spec.Backup
+	if backup == nil || !backup.Trigger {
+		return
+	}
+	object := r.newBackup()
+	err := r.Destination.Client.Create(context.TODO(), object)
+	if err != nil && !k8serr.IsAlreadyExists(err) {
+		err = liberr.Wrap(err, "backup", object.GetName())
+		r.Log.Error(err, "Backup not created.")
+		snapshot.SetCondition(
+			libcnd.Condition{
+				Type:     BackupNotCreated,
+				Status:   True,
+				Category: api.CategoryWarn,
+				Message: fmt.Sprintf(
+					"The Velero backup of the target namespace could not be created: %s",
+					err.Error()),
+				Durable: true,
+			})
+		return
+	}
+	r.Log.Info(
+		"Backup created.",
+		"backup",
+		object.GetNamespace()+"/"+object.GetName())
+}
+
+// Build the Velero backup of the migration.
+func (r *Migration) newBackup() (object *unstructured.Unstructured) {
+	backup := r.Plan.Spec.Backup
+	name := r.Plan.Name + "-" + string(r.Migration.UID)
+	if len(name) > validation.DNS1123LabelMaxLength {
+		name = string(r.Migration.UID)
+	}
+	spec := map[string]interface{}{
+		"includedNamespaces": []interface{}{r.Plan.Spec.TargetNamespace},
+		"labelSelector": map[string]interface{}{
+			"matchLabels": map[string]interface{}{
+				kBackupPolicy: backup.Policy,
+			},
+		},
+	}
+	if backup.StorageLocation != "" {
+		spec["storageLocation"] = backup.StorageLocation
+	}
+	if backup.TTL != nil {
+		spec["ttl"] = backup.TTL.Duration.String()
+	}
+	object = &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	object.SetGroupVersionKind(veleroBackupGVK)
+	object.SetNamespace(backup.VeleroNamespace())
+	object.SetName(name)
+	object.SetLabels(map[string]string{
+		kPlan:      string(r.Plan.UID),
+		kMigration: string(r.Migration.UID),
+	})
+	return
+}
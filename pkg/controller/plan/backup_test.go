@@ -0,0 +1,69 @@
+package plan
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	"github.com/onsi/gomega"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestNewBackup(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	p := &api.Plan{ObjectMeta: meta.ObjectMeta{Namespace: "ns", Name: "plan", UID: "p1"}}
+	p.Spec.TargetNamespace = "target"
+	p.Spec.Backup = &plan.BackupSettings{
+		Policy:          "daily",
+		Trigger:         true,
+		StorageLocation: "default",
+		TTL:             &meta.Duration{Duration: 72 * time.Hour},
+	}
+	migration := &api.Migration{ObjectMeta: meta.ObjectMeta{UID: "m1"}}
+	r := &Migration{Context: &plancontext.Context{Plan: p, Migration: migration}}
+
+	backup := r.newBackup()
+	g.Expect(backup.GetNamespace()).To(gomega.Equal(plan.DefaultBackupNamespace))
+	g.Expect(backup.GetName()).To(gomega.Equal("plan-m1"))
+	g.Expect(backup.GetLabels()).To(gomega.HaveKeyWithValue(kMigration, "m1"))
+	namespaces, _, _ := unstructured.NestedStringSlice(backup.Object, "spec", "includedNamespaces")
+	g.Expect(namespaces).To(gomega.Equal([]string{"target"}))
+	policy, _, _ := unstructured.NestedString(backup.Object, "spec", "labelSelector", "matchLabels", kBackupPolicy)
+	g.Expect(policy).To(gomega.Equal("daily"))
+	location, _, _ := unstructured.NestedString(backup.Object, "spec", "storageLocation")
+	g.Expect(location).To(gomega.Equal("default"))
+	ttl, _, _ := unstructured.NestedString(backup.Object, "spec", "ttl")
+	g.Expect(ttl).To(gomega.Equal("72h0m0s"))
+
+	// Created once.
+	destination := fake.NewClientBuilder().Build()
+	r.Destination.Client = destination
+	snapshot := &plan.Snapshot{}
+	r.Log = KubeVirtLog
+	r.triggerBackup(snapshot)
+	r.triggerBackup(snapshot)
+	g.Expect(snapshot.HasCondition(BackupNotCreated)).To(gomega.BeFalse())
+	created := &unstructured.Unstructured{}
+	created.SetGroupVersionKind(veleroBackupGVK)
+	key := client.ObjectKey{Namespace: plan.DefaultBackupNamespace, Name: "plan-m1"}
+	g.Expect(destination.Get(context.TODO(), key, created)).To(gomega.Succeed())
+}
+
+func TestSetBackupLabel(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	r := &KubeVirt{Context: &plancontext.Context{Plan: &api.Plan{}}}
+	g.Expect(r.setBackupLabel(nil)).To(gomega.BeNil())
+
+	r.Plan.Spec.Backup = &plan.BackupSettings{Policy: "daily"}
+	labels := r.setBackupLabel(map[string]string{kVM: "vm-1"})
+	g.Expect(labels).To(gomega.HaveKeyWithValue(kBackupPolicy, "daily"))
+	g.Expect(labels).To(gomega.HaveKeyWithValue(kVM, "vm-1"))
+}
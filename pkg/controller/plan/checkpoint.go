@@ -0,0 +1,94 @@
+package plan
+
+import (
+	"context"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Phases that create resources and are not idempotent.
+// The checkpoint is persisted as soon as the VM leaves one of them
+// so that the controller taking over (new leader) does not run
+// the phase again.
+var createPhases = map[string]bool{
+	api.PhaseCreateInitialSnapshot: true,
+	api.PhaseCreateSnapshot:        true,
+	api.PhaseCreateFinalSnapshot:   true,
+	api.PhaseCreateDataVolumes:     true,
+	api.PhaseCreateVM:              true,
+}
+
+// Execute the current phase of the VM and checkpoint the pipeline.
+func (r *Migration) step(vm *plan.VMStatus) (err error) {
+	executed := vm.Phase
+	err = r.execute(vm)
+	if err != nil {
+		return
+	}
+	err = r.checkpoint(vm, executed)
+	return
+}
+
+// Record the checkpoint of the VM pipeline.
+// The checkpoint is persisted along with the phase by the plan
+// status update at the end of the reconcile, except when the
+// executed phase created resources or a precopy (iteration) was
+// reached. Then the plan status is updated right away so that
+// the snapshot of the precopy is not created again after the
+// controller restarts. The VM reconciled by a controller other
+// than the holder (new leader) is logged as resumed.
+func (r *Migration) checkpoint(vm *plan.VMStatus, executed string) (err error) {
+	identity := Settings.Leader.Identity
+	precopy := 0
+	if vm.Warm != nil {
+		precopy = vm.Warm.Iterations()
+	}
+	last := vm.Checkpoint
+	if last != nil && last.Phase == vm.Phase && last.Precopy == precopy && last.Holder == identity {
+		return
+	}
+	lastPrecopy := 0
+	if last != nil {
+		lastPrecopy = last.Precopy
+	}
+	if last != nil && last.Holder != identity {
+		r.Log.Info(
+			"Migration [RESUMED]",
+			"vm",
+			vm.String(),
+			"phase",
+			vm.Phase,
+			"precopy",
+			precopy,
+			"holder",
+			last.Holder)
+	}
+	vm.Checkpoint = &plan.Checkpoint{
+		Phase:   vm.Phase,
+		Holder:  identity,
+		Precopy: precopy,
+		Time:    meta.Now(),
+	}
+	if (vm.Phase != executed && createPhases[executed]) || precopy != lastPrecopy {
+		err = r.persist()
+	}
+	return
+}
+
+// Persist the plan status.
+// The plan is copied to retain the staged conditions and the
+// referenced resources. The resource version is updated so the
+// status update at the end of the reconcile does not conflict.
+func (r *Migration) persist() (err error) {
+	planCopy := r.Plan.DeepCopy()
+	err = r.Status().Update(context.TODO(), planCopy)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	r.Plan.ResourceVersion = planCopy.ResourceVersion
+	return
+}
@@ -0,0 +1,188 @@
+package plan
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	"github.com/onsi/gomega"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeClient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// Migrator (stub).
+// The phases are executed in the order of the pipeline.
+type migratorStub struct {
+	phases   []string
+	executed []string
+}
+
+func (r *migratorStub) Init() error                              { return nil }
+func (r *migratorStub) Status(planapi.VM) *planapi.VMStatus      { return nil }
+func (r *migratorStub) Reset(*planapi.VMStatus, []*planapi.Step) {}
+func (r *migratorStub) Pipeline(planapi.VM) ([]*planapi.Step, error) {
+	return nil, nil
+}
+func (r *migratorStub) Step(*planapi.VMStatus) string               { return "" }
+func (r *migratorStub) Next(*planapi.VMStatus) string               { return "" }
+func (r *migratorStub) Cleanup(*planapi.VMStatus, bool) (err error) { return }
+func (r *migratorStub) ExecutePhase(vm *planapi.VMStatus) (bool, error) {
+	r.executed = append(r.executed, vm.Phase)
+	for i := range r.phases[:len(r.phases)-1] {
+		if r.phases[i] == vm.Phase {
+			vm.Phase = r.phases[i+1]
+			break
+		}
+	}
+	return true, nil
+}
+
+// Build a plan with a VM and the fake client.
+func checkpointPlan(phase string) (plan *api.Plan, fake client.Client) {
+	plan = &api.Plan{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "test",
+			Name:      "test",
+		},
+	}
+	plan.Status.Migration.VMs = []*planapi.VMStatus{{Phase: phase}}
+	scheme := runtime.NewScheme()
+	_ = api.SchemeBuilder.AddToScheme(scheme)
+	fake = fakeClient.NewClientBuilder().
+		WithScheme(scheme).
+		WithStatusSubresource(&api.Plan{}).
+		WithRuntimeObjects(plan).
+		Build()
+	_ = fake.Get(context.TODO(), client.ObjectKeyFromObject(plan), plan)
+	return
+}
+
+// Build a runner of the (persisted) plan.
+func checkpointRunner(fake client.Client, migrator *migratorStub) (runner *Migration, vm *planapi.VMStatus) {
+	plan := &api.Plan{}
+	_ = fake.Get(context.TODO(), client.ObjectKey{Namespace: "test", Name: "test"}, plan)
+	runner = &Migration{
+		Context: &plancontext.Context{
+			Client:    fake,
+			Plan:      plan,
+			Migration: &api.Migration{},
+			Log:       log,
+		},
+		migrator: migrator,
+	}
+	vm = plan.Status.Migration.VMs[0]
+	return
+}
+
+func TestCheckpoint(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	identity := Settings.Leader.Identity
+	defer func() {
+		Settings.Leader.Identity = identity
+	}()
+
+	plan, fake := checkpointPlan(api.PhaseCopyDisks)
+	vm := plan.Status.Migration.VMs[0]
+	persisted := func() *planapi.Checkpoint {
+		found := &api.Plan{}
+		err := fake.Get(context.TODO(), client.ObjectKeyFromObject(plan), found)
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		return found.Status.Migration.VMs[0].Checkpoint
+	}
+	runner := Migration{
+		Context: &plancontext.Context{
+			Client: fake,
+			Plan:   plan,
+			Log:    log,
+		},
+	}
+
+	// Recorded when the phase is reached.
+	Settings.Leader.Identity = "controller-a"
+	err := runner.checkpoint(vm, api.PhaseWaitForDataVolumesStatus)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(vm.Checkpoint.Phase).To(gomega.Equal(api.PhaseCopyDisks))
+	g.Expect(vm.Checkpoint.Holder).To(gomega.Equal("controller-a"))
+	g.Expect(persisted()).To(gomega.BeNil())
+	last := vm.Checkpoint
+
+	// Not recorded while in the same phase.
+	err = runner.checkpoint(vm, api.PhaseCopyDisks)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(vm.Checkpoint).To(gomega.BeIdenticalTo(last))
+
+	// Persisted when a create phase is left.
+	vm.Phase = api.PhaseConvertGuest
+	err = runner.checkpoint(vm, api.PhaseCreateVM)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(persisted().Phase).To(gomega.Equal(api.PhaseConvertGuest))
+	version := plan.ResourceVersion
+
+	// Taken over by the new leader.
+	Settings.Leader.Identity = "controller-b"
+	err = runner.checkpoint(vm, api.PhaseConvertGuest)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(vm.Checkpoint.Holder).To(gomega.Equal("controller-b"))
+	g.Expect(plan.ResourceVersion).To(gomega.Equal(version))
+
+	// Persisted when the precopy is reached.
+	vm.Warm = &planapi.Warm{
+		Precopies: []planapi.Precopy{{Snapshot: "snapshot-1"}},
+	}
+	err = runner.checkpoint(vm, api.PhaseConvertGuest)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(persisted().Precopy).To(gomega.Equal(1))
+	g.Expect(plan.Status.Migration.VMs[0].Warm.Precopies).To(gomega.HaveLen(1))
+}
+
+// The leader is lost after the data volumes are created and
+// before the plan status is updated at the end of the reconcile.
+// The new leader does not create the data volumes again.
+func TestCheckpointTakeover(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	identity := Settings.Leader.Identity
+	defer func() {
+		Settings.Leader.Identity = identity
+	}()
+	phases := []string{
+		api.PhaseCreateDataVolumes,
+		api.PhaseCopyDisks,
+		api.PhaseConvertGuest,
+		api.PhaseCreateVM,
+		api.PhasePostHook,
+	}
+	_, fake := checkpointPlan(api.PhaseCreateDataVolumes)
+
+	// Leader.
+	Settings.Leader.Identity = "controller-a"
+	leader := &migratorStub{phases: phases}
+	runner, vm := checkpointRunner(fake, leader)
+	err := runner.step(vm)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	err = runner.step(vm)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(leader.executed).To(gomega.Equal(phases[:2]))
+
+	// Taken over.
+	Settings.Leader.Identity = "controller-b"
+	taken := &migratorStub{phases: phases}
+	runner, vm = checkpointRunner(fake, taken)
+	g.Expect(vm.Phase).To(gomega.Equal(api.PhaseCopyDisks))
+	g.Expect(vm.Checkpoint.Holder).To(gomega.Equal("controller-a"))
+	for vm.Phase != api.PhasePostHook {
+		err = runner.step(vm)
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+	}
+	g.Expect(taken.executed).To(gomega.Equal(phases[1:4]))
+	g.Expect(vm.Checkpoint.Holder).To(gomega.Equal("controller-b"))
+
+	// Persisted after the VM is created.
+	_, vm = checkpointRunner(fake, taken)
+	g.Expect(vm.Phase).To(gomega.Equal(api.PhasePostHook))
+}
@@ -0,0 +1,127 @@
+package plan
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	k8sutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// Key of the exported (gzipped) status.
+	ExportedStatusKey = "status.json.gz"
+)
+
+// Compact the status of an archived plan once the retention elapsed.
+// The full status is exported to a ConfigMap (owned by the plan) before
+// the per-VM pipelines are summarized and the history is trimmed.
+// Returns the duration until the compaction is due.
+func (r *Reconciler) compact(plan *api.Plan) (reQ time.Duration, err error) {
+	if Settings.ArchiveRetention == 0 || plan.Status.Migration.Compaction != nil {
+		return
+	}
+	archived := plan.Status.FindCondition(Archived)
+	if archived == nil {
+		return
+	}
+	retention := time.Duration(Settings.ArchiveRetention) * time.Minute
+	due := archived.LastTransitionTime.Add(retention)
+	if now := time.Now(); now.Before(due) {
+		reQ = due.Sub(now)
+		return
+	}
+	export, err := r.exportStatus(plan)
+	if err != nil {
+		return
+	}
+	trimmed := plan.Status.Migration.Compact(Settings.ArchiveHistory)
+	plan.Status.Migration.Compaction = &planapi.Compaction{
+		Compacted:      meta.Now(),
+		TrimmedHistory: trimmed,
+		Export:         export,
+	}
+	err = r.Status().Update(context.TODO(), plan)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	r.Log.Info(
+		"Archived plan status compacted.",
+		"export",
+		export.Name,
+		"trimmedHistory",
+		trimmed)
+	return
+}
+
+// Export the plan status to a ConfigMap.
+func (r *Reconciler) exportStatus(plan *api.Plan) (ref *core.ObjectReference, err error) {
+	content, err := json.Marshal(plan.Status)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	buffer := &bytes.Buffer{}
+	writer := gzip.NewWriter(buffer)
+	_, err = writer.Write(content)
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	mp := &core.ConfigMap{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: plan.Namespace,
+			Name:      "plan-status-" + string(plan.UID),
+			Labels: map[string]string{
+				kPlan: string(plan.UID),
+			},
+		},
+		BinaryData: map[string][]byte{
+			ExportedStatusKey: buffer.Bytes(),
+		},
+	}
+	err = k8sutil.SetOwnerReference(plan, mp, r.Scheme())
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	err = r.Create(context.TODO(), mp)
+	if err != nil {
+		if !k8serr.IsAlreadyExists(err) {
+			err = liberr.Wrap(err)
+			return
+		}
+		// Exported by a previous (failed) compaction.
+		found := &core.ConfigMap{}
+		err = r.Get(context.TODO(), client.ObjectKeyFromObject(mp), found)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		found.BinaryData = mp.BinaryData
+		err = r.Update(context.TODO(), found)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+	ref = &core.ObjectReference{
+		Kind:      "ConfigMap",
+		Namespace: mp.Namespace,
+		Name:      mp.Name,
+	}
+	return
+}
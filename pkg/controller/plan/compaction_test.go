@@ -0,0 +1,116 @@
+package plan
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/controller/base"
+	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeClient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCompact(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	retention, history := Settings.ArchiveRetention, Settings.ArchiveHistory
+	Settings.ArchiveRetention, Settings.ArchiveHistory = 60, 1
+	defer func() {
+		Settings.ArchiveRetention, Settings.ArchiveHistory = retention, history
+	}()
+
+	plan := &api.Plan{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "test",
+			Name:      "test",
+			UID:       "plan",
+		},
+	}
+	plan.Spec.Archived = true
+	plan.Status.SetCondition(libcnd.Condition{Type: Archived, Status: libcnd.True})
+	plan.Status.Migration.History = []planapi.Snapshot{{}, {}, {}}
+	failed := &planapi.Step{Task: planapi.Task{Name: "DiskTransfer"}}
+	failed.MarkCompleted()
+	failed.AddError("failed")
+	completed := &planapi.Step{Task: planapi.Task{Name: "Initialize"}}
+	completed.MarkCompleted()
+	plan.Status.Migration.VMs = []*planapi.VMStatus{
+		{
+			Pipeline: []*planapi.Step{completed, failed, {Task: planapi.Task{Name: "VirtualMachineCreation"}}},
+			Warm:     &planapi.Warm{Precopies: []planapi.Precopy{{}, {}}},
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	_ = core.AddToScheme(scheme)
+	_ = api.SchemeBuilder.AddToScheme(scheme)
+	client := fakeClient.NewClientBuilder().
+		WithScheme(scheme).
+		WithStatusSubresource(&api.Plan{}).
+		WithRuntimeObjects(plan).
+		Build()
+	reconciler := &Reconciler{
+		base.Reconciler{
+			Client: client,
+			Log:    log,
+		},
+	}
+
+	// Not due.
+	reQ, err := reconciler.compact(plan)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(reQ).To(gomega.BeNumerically(">", 59*time.Minute))
+	g.Expect(plan.Status.Migration.Compaction).To(gomega.BeNil())
+
+	// Due.
+	archived := plan.Status.FindCondition(Archived)
+	archived.LastTransitionTime = meta.NewTime(time.Now().Add(-2 * time.Hour))
+	exported, _ := json.Marshal(plan.Status)
+	reQ, err = reconciler.compact(plan)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(reQ).To(gomega.BeZero())
+	compaction := plan.Status.Migration.Compaction
+	g.Expect(compaction).ToNot(gomega.BeNil())
+	g.Expect(compaction.TrimmedHistory).To(gomega.Equal(2))
+	g.Expect(plan.Status.Migration.History).To(gomega.HaveLen(1))
+	vm := plan.Status.Migration.VMs[0]
+	g.Expect(vm.Pipeline).To(gomega.BeEmpty())
+	g.Expect(vm.Warm.Precopies).To(gomega.BeEmpty())
+	g.Expect(*vm.Summary).To(gomega.Equal(planapi.PipelineSummary{
+		Steps:     3,
+		Completed: 1,
+		Failed:    "DiskTransfer",
+		Precopies: 2,
+	}))
+
+	// Exported.
+	mp := &core.ConfigMap{}
+	err = client.Get(context.TODO(), clientKey(compaction.Export), mp)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(mp.OwnerReferences).To(gomega.HaveLen(1))
+	reader, err := gzip.NewReader(bytes.NewReader(mp.BinaryData[ExportedStatusKey]))
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	content, err := io.ReadAll(reader)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(content).To(gomega.MatchJSON(exported))
+
+	// Compacted once.
+	reQ, err = reconciler.compact(plan)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(reQ).To(gomega.BeZero())
+}
+
+func clientKey(ref *core.ObjectReference) client.ObjectKey {
+	return client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}
+}
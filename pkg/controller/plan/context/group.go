@@ -0,0 +1,139 @@
+package context
+
+import (
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+)
+
+// Progress of the plan migration groups.
+type Groups struct {
+	// Groups as listed in the plan.
+	list []plan.Group
+	// Index by name.
+	index map[string]int
+	// Number of VMs by group.
+	vms map[string]int
+	// Number of completed VMs by group.
+	completed map[string]int
+	// Number of succeeded VMs by group.
+	succeeded map[string]int
+	// Groups with started VMs.
+	started map[string]bool
+	// Groups approved in the migration.
+	approved func(name string) bool
+}
+
+// Build the progress of the migration groups.
+func (r *Context) Groups() (groups *Groups) {
+	groups = &Groups{
+		list:      r.Plan.Spec.Groups,
+		index:     map[string]int{},
+		vms:       map[string]int{},
+		completed: map[string]int{},
+		succeeded: map[string]int{},
+		started:   map[string]bool{},
+		approved:  r.Migration.Spec.GroupApproved,
+	}
+	for i, group := range r.Plan.Spec.Groups {
+		groups.index[group.Name] = i
+	}
+	for _, vm := range r.Plan.Status.Migration.VMs {
+		if vm.Group == "" {
+			continue
+		}
+		groups.vms[vm.Group]++
+		if vm.MarkedStarted() {
+			groups.started[vm.Group] = true
+		}
+		if vm.MarkedCompleted() || vm.HasCondition(api.ConditionCanceled) {
+			groups.completed[vm.Group]++
+			if vm.HasCondition(api.ConditionSucceeded) {
+				groups.succeeded[vm.Group]++
+			}
+		}
+	}
+	return
+}
+
+// Groups that must succeed before the group is started.
+// Defaults to the previous group listed in the plan.
+func (r *Groups) Dependencies(name string) (dependencies []string) {
+	i, found := r.index[name]
+	if !found {
+		return
+	}
+	group := r.list[i]
+	if group.DependsOn != nil {
+		dependencies = group.DependsOn
+		return
+	}
+	if i > 0 {
+		dependencies = []string{r.list[i-1].Name}
+	}
+	return
+}
+
+// All VMs of the group have completed.
+func (r *Groups) Completed(name string) bool {
+	return r.completed[name] == r.vms[name]
+}
+
+// All VMs of the group have succeeded.
+func (r *Groups) Succeeded(name string) bool {
+	return r.succeeded[name] == r.vms[name]
+}
+
+// A dependency of the group has completed without
+// all of its VMs succeeding. The group cannot be started.
+func (r *Groups) Blocked(name string) (dependency string, blocked bool) {
+	for _, dependency = range r.Dependencies(name) {
+		if r.Completed(dependency) && !r.Succeeded(dependency) {
+			blocked = true
+			return
+		}
+	}
+	return
+}
+
+// The dependencies of the group have succeeded and
+// the group waits for approval to be started.
+func (r *Groups) PendingApproval(name string) bool {
+	i, found := r.index[name]
+	if !found || !r.list[i].Approval || r.started[name] || r.approved(name) {
+		return false
+	}
+	for _, dependency := range r.Dependencies(name) {
+		if !r.Succeeded(dependency) {
+			return false
+		}
+	}
+	return true
+}
+
+// The VMs of the group may be started.
+// VMs not in a (listed) group may always be started.
+func (r *Groups) Ready(name string) bool {
+	i, found := r.index[name]
+	if !found {
+		return true
+	}
+	for _, dependency := range r.Dependencies(name) {
+		if !r.Succeeded(dependency) {
+			return false
+		}
+	}
+	if r.list[i].Approval && !r.started[name] && !r.approved(name) {
+		return false
+	}
+	return true
+}
+
+// Names of the groups pending approval.
+func (r *Groups) PendingApprovals() (names []string) {
+	for _, group := range r.list {
+		if r.PendingApproval(group.Name) {
+			names = append(names, group.Name)
+		}
+	}
+	return
+}
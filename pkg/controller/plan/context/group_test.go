@@ -0,0 +1,68 @@
+package context
+
+import (
+	"testing"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
+	"github.com/onsi/gomega"
+)
+
+func TestGroups(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	vm := func(group string, conditions ...string) (status *plan.VMStatus) {
+		status = &plan.VMStatus{VM: plan.VM{Group: group}}
+		for _, cnd := range conditions {
+			status.SetCondition(libcnd.Condition{Type: cnd, Status: libcnd.True})
+			status.MarkCompleted()
+		}
+		return
+	}
+	ctx := &Context{
+		Plan:      &api.Plan{},
+		Migration: &api.Migration{},
+	}
+	ctx.Plan.Spec.Groups = []plan.Group{
+		{Name: "db"},
+		{Name: "app", Approval: true},
+		{Name: "web", DependsOn: []string{"db"}},
+	}
+	ctx.Plan.Status.Migration.VMs = []*plan.VMStatus{
+		vm("db"),
+		vm("app"),
+		vm("web"),
+		vm(""),
+	}
+	groups := ctx.Groups()
+	g.Expect(groups.Dependencies("app")).To(gomega.Equal([]string{"db"}))
+	g.Expect(groups.Dependencies("db")).To(gomega.BeEmpty())
+	g.Expect(groups.Ready("db")).To(gomega.BeTrue())
+	g.Expect(groups.Ready("app")).To(gomega.BeFalse())
+	g.Expect(groups.Ready("web")).To(gomega.BeFalse())
+	g.Expect(groups.Ready("")).To(gomega.BeTrue())
+	g.Expect(groups.PendingApprovals()).To(gomega.BeEmpty())
+
+	// db succeeded: web is ready and app waits for approval.
+	ctx.Plan.Status.Migration.VMs[0] = vm("db", api.ConditionSucceeded)
+	groups = ctx.Groups()
+	g.Expect(groups.Ready("web")).To(gomega.BeTrue())
+	g.Expect(groups.Ready("app")).To(gomega.BeFalse())
+	g.Expect(groups.PendingApprovals()).To(gomega.Equal([]string{"app"}))
+	ctx.Migration.Spec.Approved = []string{"app"}
+	groups = ctx.Groups()
+	g.Expect(groups.Ready("app")).To(gomega.BeTrue())
+	g.Expect(groups.PendingApprovals()).To(gomega.BeEmpty())
+
+	// db failed: app and web are blocked.
+	ctx.Plan.Status.Migration.VMs[0] = vm("db", api.ConditionFailed)
+	groups = ctx.Groups()
+	dependency, blocked := groups.Blocked("web")
+	g.Expect(blocked).To(gomega.BeTrue())
+	g.Expect(dependency).To(gomega.Equal("db"))
+	_, blocked = groups.Blocked("app")
+	g.Expect(blocked).To(gomega.BeTrue())
+	_, blocked = groups.Blocked("db")
+	g.Expect(blocked).To(gomega.BeFalse())
+}
@@ -5,11 +5,15 @@ import (
 	"path"
 
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	"github.com/kubev2v/forklift/pkg/controller/base"
 	"github.com/kubev2v/forklift/pkg/controller/provider/web"
 	ocp "github.com/kubev2v/forklift/pkg/lib/client/openshift"
 	liberr "github.com/kubev2v/forklift/pkg/lib/error"
 	"github.com/kubev2v/forklift/pkg/lib/logging"
 	core "k8s.io/api/core/v1"
+	"k8s.io/client-go/rest"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -62,6 +66,13 @@ type Context struct {
 	Hooks []*api.Hook
 	// Logger.
 	Log logging.LevelLogger
+	// VM status index.
+	statuses struct {
+		// Index.
+		index plan.VMStatusIndex
+		// Indexed list.
+		list []*plan.VMStatus
+	}
 }
 
 // Build.
@@ -105,6 +116,23 @@ func (r *Context) SetMigration(migration *api.Migration) {
 			migration.Name))
 }
 
+// Find a VM status.
+// The index is rebuilt when the status list of the plan
+// is replaced or resized.
+func (r *Context) FindVM(ref ref.Ref) (status *plan.VMStatus, found bool) {
+	list := r.Plan.Status.Migration.VMs
+	if len(list) == 0 {
+		return
+	}
+	indexed := r.statuses.list
+	if len(indexed) != len(list) || &indexed[0] != &list[0] {
+		r.statuses.index = r.Plan.Status.Migration.Index()
+		r.statuses.list = list
+	}
+	status, found = r.statuses.index.Find(ref)
+	return
+}
+
 // Source.
 type Source struct {
 	// Provider
@@ -126,7 +154,12 @@ func (r *Source) build(ctx *Context) (err error) {
 		return
 	}
 
-	if !r.Provider.IsHost() {
+	if r.Provider.IsHostedCluster() {
+		r.Secret, err = ocp.HostedClusterSecret(ctx, r.Provider)
+		if err != nil {
+			return
+		}
+	} else if !r.Provider.IsHost() {
 		ref := r.Provider.Spec.Secret
 		r.Secret = &core.Secret{}
 		err = ctx.Get(
@@ -140,6 +173,10 @@ func (r *Source) build(ctx *Context) (err error) {
 			err = liberr.Wrap(err)
 			return
 		}
+		r.Secret, err = base.WithCABundle(ctx, r.Provider, r.Secret)
+		if err != nil {
+			return
+		}
 	}
 
 	r.Inventory, err = web.NewClient(r.Provider)
@@ -155,6 +192,8 @@ func (r *Source) build(ctx *Context) (err error) {
 type Destination struct {
 	// Remote client.
 	k8sclient.Client
+	// Remote REST configuration.
+	RestCfg *rest.Config
 	// Provider.
 	Provider *api.Provider
 	// Provider API client.
@@ -171,7 +210,19 @@ func (r *Destination) build(ctx *Context) (err error) {
 		err = liberr.Wrap(NotEnoughDataError{})
 		return
 	}
-	if !r.Provider.IsHost() {
+	if r.Provider.IsHostedCluster() {
+		var secret *core.Secret
+		secret, err = ocp.HostedClusterSecret(ctx, r.Provider)
+		if err != nil {
+			return
+		}
+		r.Client, err = ocp.Client(r.Provider, secret)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		r.RestCfg = ocp.RestCfg(r.Provider, secret)
+	} else if !r.Provider.IsHost() {
 		ref := r.Provider.Spec.Secret
 		secret := &core.Secret{}
 		err = ctx.Get(
@@ -190,12 +241,14 @@ func (r *Destination) build(ctx *Context) (err error) {
 			err = liberr.Wrap(err)
 			return
 		}
+		r.RestCfg = ocp.RestCfg(r.Provider, secret)
 	} else {
 		r.Client, err = ocp.Client(r.Provider, nil)
 		if err != nil {
 			err = liberr.Wrap(err)
 			return
 		}
+		r.RestCfg = ocp.RestCfg(r.Provider, nil)
 	}
 	r.Inventory, err = web.NewClient(r.Provider)
 	if err != nil {
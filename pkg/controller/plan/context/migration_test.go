@@ -0,0 +1,78 @@
+package context
+
+import (
+	"fmt"
+	"testing"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	"github.com/onsi/gomega"
+)
+
+func TestFindVM(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	ctx := &Context{Plan: &api.Plan{}}
+	_, found := ctx.FindVM(ref.Ref{ID: "vm-1"})
+	g.Expect(found).To(gomega.BeFalse())
+
+	ctx.Plan.Status.Migration.VMs = statuses(3)
+	status, found := ctx.FindVM(ref.Ref{ID: "vm-1"})
+	g.Expect(found).To(gomega.BeTrue())
+	g.Expect(status).To(gomega.BeIdenticalTo(ctx.Plan.Status.Migration.VMs[1]))
+	_, found = ctx.FindVM(ref.Ref{ID: "vm-3"})
+	g.Expect(found).To(gomega.BeFalse())
+
+	// Appended.
+	ctx.Plan.Status.Migration.VMs = append(
+		ctx.Plan.Status.Migration.VMs,
+		&plan.VMStatus{VM: plan.VM{Ref: ref.Ref{ID: "vm-3"}}})
+	_, found = ctx.FindVM(ref.Ref{ID: "vm-3"})
+	g.Expect(found).To(gomega.BeTrue())
+
+	// Replaced.
+	replaced := statuses(4)
+	ctx.Plan.Status.Migration.VMs = replaced
+	status, found = ctx.FindVM(ref.Ref{ID: "vm-3"})
+	g.Expect(found).To(gomega.BeTrue())
+	g.Expect(status).To(gomega.BeIdenticalTo(replaced[3]))
+
+	// Deleted.
+	ctx.Plan.Status.Migration.VMs = replaced[1:]
+	_, found = ctx.FindVM(ref.Ref{ID: "vm-0"})
+	g.Expect(found).To(gomega.BeFalse())
+}
+
+func BenchmarkFindVM(b *testing.B) {
+	for _, n := range []int{10, 100, 1000} {
+		ctx := &Context{Plan: &api.Plan{}}
+		ctx.Plan.Status.Migration.VMs = statuses(n)
+		b.Run(fmt.Sprintf("scan/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, vm := range ctx.Plan.Status.Migration.VMs {
+					_, _ = ctx.Plan.Status.Migration.FindVM(vm.Ref)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("index/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, vm := range ctx.Plan.Status.Migration.VMs {
+					_, _ = ctx.FindVM(vm.Ref)
+				}
+			}
+		})
+	}
+}
+
+// Build VM statuses.
+func statuses(n int) (list []*plan.VMStatus) {
+	for i := 0; i < n; i++ {
+		list = append(
+			list,
+			&plan.VMStatus{
+				VM: plan.VM{Ref: ref.Ref{ID: fmt.Sprintf("vm-%d", i)}},
+			})
+	}
+	return
+}
@@ -30,8 +30,10 @@ import (
 	liberr "github.com/kubev2v/forklift/pkg/lib/error"
 	"github.com/kubev2v/forklift/pkg/lib/logging"
 	libref "github.com/kubev2v/forklift/pkg/lib/ref"
+	"github.com/kubev2v/forklift/pkg/lib/tracing"
 	metrics "github.com/kubev2v/forklift/pkg/monitoring/metrics/forklift-controller"
 	"github.com/kubev2v/forklift/pkg/settings"
+	"go.opentelemetry.io/otel/attribute"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apiserver/pkg/storage/names"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -63,6 +65,7 @@ func Add(mgr manager.Manager) error {
 			EventRecorder: mgr.GetEventRecorderFor(Name),
 			Client:        mgr.GetClient(),
 			Log:           log,
+			Interval:      Settings.Requeue.Plan,
 		},
 	}
 	cnt, err := controller.New(
@@ -148,6 +151,16 @@ func Add(mgr manager.Manager) error {
 		return err
 	}
 
+	// Garbage collector.
+	err = mgr.Add(&Collector{
+		Reconciler: reconciler,
+		Reader:     mgr.GetAPIReader(),
+	})
+	if err != nil {
+		log.Trace(err)
+		return err
+	}
+
 	// Gather migration Plan metrics
 	metrics.RecordPlanMetrics(mgr.GetClient())
 
@@ -176,6 +189,10 @@ func (r Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (r
 			err)
 		err = nil
 	}()
+	_, span := tracing.Start(ctx, "plan.reconcile", attribute.String("plan", request.String()))
+	defer func() {
+		tracing.End(span, err)
+	}()
 
 	// Fetch the CR.
 	plan := &api.Plan{}
@@ -183,6 +200,7 @@ func (r Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (r
 	if err != nil {
 		if k8serr.IsNotFound(err) {
 			r.Log.Info("Plan deleted.")
+			vmValidations.prune(request.NamespacedName.String(), nil)
 			err = nil
 		}
 		return
@@ -194,6 +212,7 @@ func (r Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (r
 	// Don't reconcile if the plan is archived.
 	if plan.Spec.Archived && plan.Status.HasCondition(Archived) {
 		r.Log.Info("Aborting reconcile of archived plan.")
+		result.RequeueAfter, err = r.compact(plan)
 		return
 	}
 
@@ -203,7 +222,7 @@ func (r Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (r
 		return
 	}
 	if postpone {
-		result.RequeueAfter = base.SlowReQ
+		result.RequeueAfter = base.ReQ(Settings.Requeue.Plan)
 		r.Log.Info("Plan Postponed.")
 	}
 
@@ -356,7 +375,7 @@ func (r *Reconciler) execute(plan *api.Plan) (reQ time.Duration, err error) {
 					plan.GetNamespace(),
 					plan.GetName()))
 
-			reQ = base.SlowReQ
+			reQ = base.ReQ(Settings.Requeue.Plan)
 		}
 		return
 	}
@@ -395,17 +414,14 @@ func (r *Reconciler) execute(plan *api.Plan) (reQ time.Duration, err error) {
 	snapshot := plan.Status.Migration.ActiveSnapshot()
 	if snapshot.HasCondition(Canceled) {
 		r.Log.Info("migration (active) marked as canceled.")
+		canceled := snapshot.FindCondition(Canceled)
+		initiator := planapi.CanceledBySystem
+		if canceled.Reason == Deleted {
+			initiator = planapi.CanceledByUser
+		}
 		for _, vm := range plan.Status.Migration.VMs {
 			if !vm.HasAnyCondition(Succeeded, Failed) {
-				vm.SetCondition(
-					libcnd.Condition{
-						Type:     Canceled,
-						Status:   True,
-						Category: api.CategoryAdvisory,
-						Reason:   Modified,
-						Message:  "The migration has been canceled.",
-						Durable:  true,
-					})
+				markCanceled(vm, initiator, canceled.Reason, canceled.Message)
 				r.Log.Info(
 					"Snapshot canceled condition copied to VM.",
 					"vm",
@@ -448,18 +464,32 @@ func (r *Reconciler) execute(plan *api.Plan) (reQ time.Duration, err error) {
 		r.Log.Info("No pending migrations found.")
 		plan.Status.DeleteCondition(Executing)
 		reQ = NoReQ
+		// Retry the cleanup of canceled VMs.
+		for _, vm := range plan.Status.Migration.VMs {
+			if vm.HasCondition(Canceled) && !vm.MarkedCompleted() {
+				reQ = base.ReQ(Settings.Requeue.Plan)
+				break
+			}
+		}
 		return
 	}
 
 	//
 	// Run the migration.
+	before := captureMilestones(plan)
 	snapshot.BeginStagingConditions()
 	runner = Migration{Context: ctx}
+	_, span := tracing.Start(
+		tracing.WithUID(context.TODO(), string(migration.UID)),
+		"migration.run",
+		attribute.String("plan", path.Join(plan.Namespace, plan.Name)))
 	reQ, err = runner.Run()
+	tracing.End(span, err)
 	if err != nil {
 		return
 	}
 	snapshot.EndStagingConditions()
+	r.recordMilestones(ctx, before)
 
 	// Reflect the active snapshot status on the plan.
 	for _, t := range []string{Executing, Succeeded, Failed, Canceled} {
@@ -482,7 +512,7 @@ func (r *Reconciler) execute(plan *api.Plan) (reQ time.Duration, err error) {
 			"Found pending migrations.",
 			"count",
 			len(pending))
-		reQ = base.FastReQ
+		reQ = base.ReQ(Settings.Requeue.PlanFast)
 	}
 
 	return
@@ -0,0 +1,191 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	"github.com/kubev2v/forklift/pkg/lib/notification"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// Plan milestone events.
+const (
+	EventVMPhaseChanged     = "VMPhaseChanged"
+	EventVMSucceeded        = "VMSucceeded"
+	EventVMFailed           = "VMFailed"
+	EventMigrationSucceeded = "MigrationSucceeded"
+	EventMigrationFailed    = "MigrationFailed"
+	EventMigrationCanceled  = "MigrationCanceled"
+)
+
+// Milestones of the active migration.
+type milestones struct {
+	// Migration outcome condition.
+	outcome string
+	// VM phase and outcome by VM ID.
+	vms map[string]vmMilestone
+}
+
+// VM milestone.
+type vmMilestone struct {
+	name    string
+	phase   string
+	outcome string
+	reasons []string
+}
+
+// Capture the milestones reached by the active migration.
+func captureMilestones(plan *api.Plan) (captured milestones) {
+	captured.vms = make(map[string]vmMilestone)
+	snapshot := plan.Status.Migration.ActiveSnapshot()
+	for _, t := range []string{Succeeded, Failed, Canceled} {
+		if snapshot.HasCondition(t) {
+			captured.outcome = t
+			break
+		}
+	}
+	for _, vm := range plan.Status.Migration.VMs {
+		milestone := vmMilestone{name: vm.Name, phase: vm.Phase}
+		switch {
+		case vm.HasCondition(Succeeded):
+			milestone.outcome = Succeeded
+		case vm.HasCondition(Failed):
+			milestone.outcome = Failed
+			if vm.Error != nil {
+				milestone.reasons = vm.Error.Reasons
+			}
+		}
+		captured.vms[vm.ID] = milestone
+	}
+	return
+}
+
+// Record the milestones reached since captured as events on the
+// migration and the plan, and notify the configured sinks.
+func (r *Reconciler) recordMilestones(ctx *plancontext.Context, before milestones) {
+	plan := ctx.Plan
+	after := captureMilestones(plan)
+	notifications := []*notification.Notification{}
+	notify := func(event, vm, message string) {
+		notifications = append(
+			notifications,
+			&notification.Notification{
+				Event:     event,
+				Namespace: plan.Namespace,
+				Plan:      plan.Name,
+				VM:        vm,
+				Message:   message,
+				Time:      time.Now(),
+			})
+	}
+	for id, vm := range after.vms {
+		previous := before.vms[id]
+		if vm.phase != previous.phase && previous.phase != "" {
+			r.EventRecorder.Eventf(
+				ctx.Migration,
+				core.EventTypeNormal,
+				EventVMPhaseChanged,
+				"VM %s phase changed: %s => %s.",
+				vm.name,
+				previous.phase,
+				vm.phase)
+		}
+		if vm.outcome == previous.outcome {
+			continue
+		}
+		switch vm.outcome {
+		case Succeeded:
+			message := fmt.Sprintf("VM %s cutover completed.", vm.name)
+			r.EventRecorder.Event(ctx.Migration, core.EventTypeNormal, EventVMSucceeded, message)
+			notify(EventVMSucceeded, vm.name, message)
+		case Failed:
+			message := fmt.Sprintf("VM %s migration failed: %v", vm.name, vm.reasons)
+			r.EventRecorder.Event(ctx.Migration, core.EventTypeWarning, EventVMFailed, message)
+			r.EventRecorder.Event(plan, core.EventTypeWarning, EventVMFailed, message)
+			notify(EventVMFailed, vm.name, message)
+		}
+	}
+	if after.outcome != before.outcome {
+		event, eventType := "", core.EventTypeNormal
+		switch after.outcome {
+		case Succeeded:
+			event = EventMigrationSucceeded
+		case Failed:
+			event, eventType = EventMigrationFailed, core.EventTypeWarning
+		case Canceled:
+			event = EventMigrationCanceled
+		}
+		if event != "" {
+			message := fmt.Sprintf("The plan migration has %s.", after.outcome)
+			r.EventRecorder.Event(ctx.Migration, eventType, event, message)
+			r.EventRecorder.Event(plan, eventType, event, message)
+			notify(event, "", message)
+		}
+	}
+	if len(notifications) > 0 {
+		r.notify(notifications)
+	}
+}
+
+// Send the notifications to the subscribed sinks.
+// Notifications are sent in the background and failures are logged.
+func (r *Reconciler) notify(notifications []*notification.Notification) {
+	sinks, err := r.notificationSinks()
+	if err != nil {
+		r.Log.Error(err, "Failed to load the notification sinks.")
+		return
+	}
+	for _, sink := range sinks {
+		for _, n := range notifications {
+			if !sink.Subscribed(n.Event) {
+				continue
+			}
+			go func(sink notification.Sink, n *notification.Notification) {
+				sErr := sink.Send(n)
+				if sErr != nil {
+					r.Log.Error(sErr, "Failed to send notification.", "event", n.Event, "plan", n.Plan)
+				}
+			}(sink, n)
+		}
+	}
+}
+
+// Load the notification sinks from the notification config map.
+// Each entry of the config map describes one sink.
+func (r *Reconciler) notificationSinks() (sinks []notification.Sink, err error) {
+	name := Settings.Migration.NotificationConfigMap
+	if name == "" {
+		return
+	}
+	configMap := &core.ConfigMap{}
+	err = r.Get(
+		context.TODO(),
+		client.ObjectKey{Name: name, Namespace: os.Getenv("POD_NAMESPACE")},
+		configMap)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	for key, value := range configMap.Data {
+		sink := notification.Sink{}
+		err = yaml.Unmarshal([]byte(value), &sink)
+		if err != nil {
+			err = liberr.Wrap(err, "configMap", name, "sink", key)
+			return
+		}
+		sinks = append(sinks, sink)
+	}
+	return
+}
@@ -0,0 +1,55 @@
+package plan
+
+import (
+	v1beta1 "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	"github.com/kubev2v/forklift/pkg/controller/base"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+	ginkgo "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/tools/record"
+)
+
+var eventLog = logging.WithName("event-test")
+
+var _ = ginkgo.Describe("plan milestones", func() {
+	ginkgo.It("should record VM and migration milestones", func() {
+		recorder := record.NewFakeRecorder(10)
+		reconciler := &Reconciler{
+			base.Reconciler{EventRecorder: recorder, Log: eventLog},
+		}
+		vm := &planapi.VMStatus{
+			VM:    planapi.VM{Ref: ref.Ref{ID: "vm-1", Name: "vm-1"}},
+			Phase: "CopyDisks",
+		}
+		plan := &v1beta1.Plan{}
+		plan.Name = "plan"
+		plan.Status.Migration.VMs = []*planapi.VMStatus{vm}
+		plan.Status.Migration.NewSnapshot(planapi.Snapshot{})
+		ctx := &plancontext.Context{Plan: plan, Migration: &v1beta1.Migration{}}
+
+		before := captureMilestones(plan)
+		vm.Phase = v1beta1.PhaseCompleted
+		vm.SetCondition(libcnd.Condition{Type: Succeeded, Status: True})
+		plan.Status.Migration.ActiveSnapshot().SetCondition(libcnd.Condition{Type: Succeeded, Status: True})
+		reconciler.recordMilestones(ctx, before)
+
+		events := []string{}
+		for len(recorder.Events) > 0 {
+			events = append(events, <-recorder.Events)
+		}
+		Expect(events).To(ConsistOf(
+			ContainSubstring(EventVMPhaseChanged),
+			ContainSubstring(EventVMSucceeded),
+			ContainSubstring(EventMigrationSucceeded),
+			ContainSubstring(EventMigrationSucceeded),
+		))
+
+		// Milestones are only recorded once.
+		reconciler.recordMilestones(ctx, captureMilestones(plan))
+		Expect(recorder.Events).To(BeEmpty())
+	})
+})
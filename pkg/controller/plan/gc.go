@@ -0,0 +1,395 @@
+package plan
+
+import (
+	"context"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+	metrics "github.com/kubev2v/forklift/pkg/monitoring/metrics/forklift-controller"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Kinds of reclaimed artifacts (metrics).
+const (
+	GCPod       = "Pod"
+	GCSecret    = "Secret"
+	GCConfigMap = "ConfigMap"
+	GCOvirt     = "OvirtVolumePopulator"
+	GCOpenstack = "OpenstackVolumePopulator"
+	GCVSphere   = "VSphereXcopyVolumePopulator"
+	GCEC2       = "EC2VolumePopulator"
+	GCAzure     = "AzureVolumePopulator"
+	GCLibvirt   = "LibvirtVolumePopulator"
+	GCSnapshot  = "Snapshot"
+)
+
+// Garbage collector.
+// Periodically deletes migration artifacts (transfer pods, temporary
+// secrets and configmaps, populator CRs and source snapshots) left
+// behind by failed, canceled, archived or deleted plans.
+// Only the pods, secrets and configmaps marked with the app
+// (forklift.app) label by the migrations are collected.
+type Collector struct {
+	*Reconciler
+	// Uncached reader used to list the labeled artifacts.
+	Reader client.Reader
+}
+
+// Run the collector until the context is done.
+// The collector runs on the leader only.
+func (r *Collector) Start(ctx context.Context) (err error) {
+	if Settings.GCInterval == 0 {
+		r.Log.Info("Garbage collection disabled.")
+		return
+	}
+	interval := time.Duration(Settings.GCInterval) * time.Minute
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.Collect()
+		}
+	}
+}
+
+// Collect orphaned artifacts.
+func (r *Collector) Collect() {
+	index, err := r.owners()
+	if err != nil {
+		r.Log.Error(err, "Garbage collection failed to index plans.")
+		return
+	}
+	sweeps := []func(*owners) error{
+		r.collectPods,
+		r.collectSecrets,
+		r.collectConfigMaps,
+		r.collectPopulators,
+		r.collectSnapshots,
+	}
+	for _, sweep := range sweeps {
+		err = sweep(index)
+		if err != nil {
+			r.Log.Error(err, "Garbage collection sweep failed.")
+		}
+	}
+}
+
+// Build the owner index.
+func (r *Collector) owners() (index *owners, err error) {
+	planList := &api.PlanList{}
+	err = r.Reader.List(context.TODO(), planList)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	migrationList := &api.MigrationList{}
+	err = r.Reader.List(context.TODO(), migrationList)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	index = newOwners(planList.Items, migrationList.Items)
+	return
+}
+
+// Delete orphaned transfer and populator pods.
+func (r *Collector) collectPods(owners *owners) (err error) {
+	list := &core.PodList{}
+	err = r.Reader.List(context.TODO(), list, client.HasLabels{kMigration, kApp})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for i := range list.Items {
+		pod := &list.Items[i]
+		switch pod.Labels[kApp] {
+		case AppConsumer, AppVirtV2v, AppPopulator:
+			r.collect(owners, pod, GCPod)
+		}
+	}
+	return
+}
+
+// Delete orphaned temporary secrets.
+func (r *Collector) collectSecrets(owners *owners) (err error) {
+	list := &core.SecretList{}
+	err = r.Reader.List(context.TODO(), list, client.HasLabels{kMigration, kPlan, kApp})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for i := range list.Items {
+		r.collect(owners, &list.Items[i], GCSecret)
+	}
+	return
+}
+
+// Delete orphaned temporary configmaps.
+func (r *Collector) collectConfigMaps(owners *owners) (err error) {
+	list := &core.ConfigMapList{}
+	err = r.Reader.List(context.TODO(), list, client.HasLabels{kMigration, kPlan, kApp})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for i := range list.Items {
+		r.collect(owners, &list.Items[i], GCConfigMap)
+	}
+	return
+}
+
+// Delete unreferenced populator CRs.
+func (r *Collector) collectPopulators(owners *owners) (err error) {
+	ovirtList := &api.OvirtVolumePopulatorList{}
+	err = r.Reader.List(context.TODO(), ovirtList, client.HasLabels{kMigration, kVM})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for i := range ovirtList.Items {
+		r.collect(owners, &ovirtList.Items[i], GCOvirt)
+	}
+	openstackList := &api.OpenstackVolumePopulatorList{}
+	err = r.Reader.List(context.TODO(), openstackList, client.HasLabels{kMigration, kVM})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for i := range openstackList.Items {
+		r.collect(owners, &openstackList.Items[i], GCOpenstack)
+	}
+	vsphereList := &api.VSphereXcopyVolumePopulatorList{}
+	err = r.Reader.List(context.TODO(), vsphereList, client.HasLabels{kMigration, kVM})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for i := range vsphereList.Items {
+		r.collect(owners, &vsphereList.Items[i], GCVSphere)
+	}
+	ec2List := &api.EC2VolumePopulatorList{}
+	err = r.Reader.List(context.TODO(), ec2List, client.HasLabels{kMigration, kVM})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for i := range ec2List.Items {
+		r.collect(owners, &ec2List.Items[i], GCEC2)
+	}
+	azureList := &api.AzureVolumePopulatorList{}
+	err = r.Reader.List(context.TODO(), azureList, client.HasLabels{kMigration, kVM})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for i := range azureList.Items {
+		r.collect(owners, &azureList.Items[i], GCAzure)
+	}
+	libvirtList := &api.LibvirtVolumePopulatorList{}
+	err = r.Reader.List(context.TODO(), libvirtList, client.HasLabels{kMigration, kVM})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for i := range libvirtList.Items {
+		r.collect(owners, &libvirtList.Items[i], GCLibvirt)
+	}
+	return
+}
+
+// Remove the snapshots created on the source by the plans
+// that are no longer executing. The snapshots that could not be
+// removed are retried by the next collection and reported.
+func (r *Collector) collectSnapshots(owners *owners) (err error) {
+	notRemoved := make(map[string]int)
+	for _, plan := range owners.plans {
+		if !snapshotsCollectable(plan) {
+			continue
+		}
+		err = r.removeSnapshots(plan)
+		if err != nil {
+			r.Log.Error(err, "Garbage collection failed to remove snapshots.", "plan", client.ObjectKeyFromObject(plan))
+			err = nil
+		}
+		for _, vm := range plan.Status.Migration.VMs {
+			if vm.HasCondition(SnapshotsNotRemoved) {
+				notRemoved[plan.Provider.Source.Type().String()] += len(vm.Snapshots)
+			}
+		}
+	}
+	metrics.RecordSnapshotsNotRemoved(notRemoved)
+	return
+}
+
+// Remove the stale snapshots of the plan and clear them from the plan status.
+func (r *Collector) removeSnapshots(plan *api.Plan) (err error) {
+	// The references are resolved on a copy to not persist
+	// the validation (staged) conditions.
+	planCopy := plan.DeepCopy()
+	err = r.validate(planCopy)
+	if err != nil {
+		return
+	}
+	ctx, err := plancontext.New(r, planCopy, logging.WithName("gc", "plan", client.ObjectKeyFromObject(plan)))
+	if err != nil {
+		return
+	}
+	runner := Migration{Context: ctx}
+	err = runner.init()
+	if err != nil {
+		return
+	}
+	original := plan.DeepCopy()
+	removed := 0
+	for _, vm := range plan.Status.Migration.VMs {
+		if !snapshotCollectable(vm) {
+			continue
+		}
+		// The last warm snapshot of the VMs migrated
+		// before the snapshots were tracked.
+		if len(vm.Snapshots) == 0 {
+			n := len(vm.Warm.Precopies)
+			vm.TrackSnapshot(vm.Warm.Precopies[n-1].Snapshot)
+		}
+		count := runner.removeSnapshots(vm)
+		for i := 0; i < count; i++ {
+			metrics.RecordReclaimed(GCSnapshot)
+		}
+		removed += count
+	}
+	if equality.Semantic.DeepEqual(original.Status, plan.Status) {
+		return
+	}
+	err = r.Status().Patch(context.TODO(), plan, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{}))
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if removed > 0 {
+		r.Log.Info("Garbage collected snapshots.", "plan", client.ObjectKeyFromObject(plan), "count", removed)
+	}
+	return
+}
+
+// Delete the artifact when collectable.
+func (r *Collector) collect(owners *owners, object client.Object, kind string) {
+	if !owners.collectable(object, time.Now()) {
+		return
+	}
+	err := r.Delete(context.TODO(), object, client.PropagationPolicy(meta.DeletePropagationBackground))
+	if err != nil {
+		if !k8serr.IsNotFound(err) {
+			r.Log.Error(err, "Garbage collection failed to delete artifact.", "kind", kind, "name", client.ObjectKeyFromObject(object))
+		}
+		return
+	}
+	metrics.RecordReclaimed(kind)
+	r.Log.Info("Garbage collected artifact.", "kind", kind, "name", client.ObjectKeyFromObject(object))
+}
+
+// Plans indexed by UID and by the UID of their migrations.
+type owners struct {
+	plans      map[types.UID]*api.Plan
+	migrations map[types.UID]*api.Plan
+}
+
+// Build the index.
+// Migrations are indexed by the plan history and by the migration
+// CRs so artifacts of a migration not yet in the history are owned.
+func newOwners(plans []api.Plan, migrations []api.Migration) (index *owners) {
+	index = &owners{
+		plans:      make(map[types.UID]*api.Plan),
+		migrations: make(map[types.UID]*api.Plan),
+	}
+	byName := make(map[types.NamespacedName]*api.Plan)
+	for i := range plans {
+		plan := &plans[i]
+		index.plans[plan.UID] = plan
+		byName[types.NamespacedName{Namespace: plan.Namespace, Name: plan.Name}] = plan
+		for _, snapshot := range plan.Status.Migration.History {
+			index.migrations[snapshot.Migration.UID] = plan
+		}
+	}
+	for i := range migrations {
+		migration := &migrations[i]
+		ref := migration.Spec.Plan
+		if plan, found := byName[types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}]; found {
+			index.migrations[migration.UID] = plan
+		}
+	}
+	return
+}
+
+// Find the plan owning the labeled artifact.
+func (r *owners) owner(labels map[string]string) (plan *api.Plan, found bool) {
+	if uid, labeled := labels[kPlan]; labeled {
+		plan, found = r.plans[types.UID(uid)]
+		return
+	}
+	plan, found = r.migrations[types.UID(labels[kMigration])]
+	return
+}
+
+// Determine whether the artifact is orphaned and older than the retention.
+// Orphaned artifacts belong to a deleted or archived plan or to a migration
+// of a plan not being executed, except for the artifacts of a succeeded
+// (active) migration.
+func (r *owners) collectable(object client.Object, now time.Time) bool {
+	retention := time.Duration(Settings.GCRetention) * time.Minute
+	if now.Sub(object.GetCreationTimestamp().Time) < retention {
+		return false
+	}
+	labels := object.GetLabels()
+	plan, found := r.owner(labels)
+	if !found || plan.Spec.Archived {
+		return true
+	}
+	if plan.Status.HasCondition(Executing) {
+		return false
+	}
+	snapshot := plan.Status.Migration.ActiveSnapshot()
+	return string(snapshot.Migration.UID) != labels[kMigration] ||
+		!snapshot.HasCondition(Succeeded)
+}
+
+// Determine whether the plan may have stale snapshots.
+func snapshotsCollectable(plan *api.Plan) bool {
+	if plan.Status.HasCondition(Executing) {
+		return false
+	}
+	if plan.Spec.Archived || plan.Status.HasAnyCondition(Failed, Canceled) {
+		return true
+	}
+	for _, vm := range plan.Status.Migration.VMs {
+		if len(vm.Snapshots) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Determine whether the VM has stale snapshots.
+// Tracked snapshots are stale once the plan is not executing.
+// Otherwise, the last warm snapshot of a VM that did not succeed.
+func snapshotCollectable(vm *planapi.VMStatus) bool {
+	if len(vm.Snapshots) > 0 {
+		return true
+	}
+	if vm.HasCondition(Succeeded) || vm.Warm == nil {
+		return false
+	}
+	n := len(vm.Warm.Precopies)
+	return n > 0 && vm.Warm.Precopies[n-1].Snapshot != ""
+}
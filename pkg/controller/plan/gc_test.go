@@ -0,0 +1,184 @@
+package plan
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/controller/base"
+	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeClient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCollectable(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	now := time.Now()
+	retention := Settings.GCRetention
+	Settings.GCRetention = 60
+	defer func() {
+		Settings.GCRetention = retention
+	}()
+	snapshot := func(uid types.UID, conditions ...string) (snapshot planapi.Snapshot) {
+		snapshot.Migration.UID = uid
+		for _, cnd := range conditions {
+			snapshot.SetCondition(libcnd.Condition{Type: cnd, Status: libcnd.True})
+		}
+		return
+	}
+	plan := func(uid types.UID, condition string, history ...planapi.Snapshot) (plan api.Plan) {
+		plan.Name = string(uid)
+		plan.UID = uid
+		if condition != "" {
+			plan.Status.SetCondition(libcnd.Condition{Type: condition, Status: libcnd.True})
+		}
+		plan.Status.Migration.History = history
+		return
+	}
+	artifact := func(age time.Duration, labels map[string]string) *core.Pod {
+		return &core.Pod{
+			ObjectMeta: meta.ObjectMeta{
+				CreationTimestamp: meta.NewTime(now.Add(-age)),
+				Labels:            labels,
+			},
+		}
+	}
+	archived := plan("archived", Succeeded, snapshot("m3", Succeeded))
+	archived.Spec.Archived = true
+	plans := []api.Plan{
+		plan("executing", Executing, snapshot("m1")),
+		plan("succeeded", Succeeded, snapshot("m0", Failed), snapshot("m2", Succeeded)),
+		archived,
+		plan("failed", Failed, snapshot("m4", Failed)),
+		plan("new", ""),
+	}
+	migrations := []api.Migration{
+		{
+			ObjectMeta: meta.ObjectMeta{UID: "m5"},
+			Spec:       api.MigrationSpec{Plan: core.ObjectReference{Name: "new"}},
+		},
+	}
+	owners := newOwners(plans, migrations)
+
+	// Retention.
+	g.Expect(owners.collectable(artifact(time.Minute, map[string]string{kMigration: "gone"}), now)).To(gomega.BeFalse())
+	g.Expect(owners.collectable(artifact(time.Hour, map[string]string{kMigration: "gone"}), now)).To(gomega.BeTrue())
+	// Executing plan.
+	g.Expect(owners.collectable(artifact(time.Hour, map[string]string{kMigration: "m1"}), now)).To(gomega.BeFalse())
+	g.Expect(owners.collectable(artifact(time.Hour, map[string]string{kMigration: "m1", kPlan: "executing"}), now)).To(gomega.BeFalse())
+	// Succeeded plan.
+	g.Expect(owners.collectable(artifact(time.Hour, map[string]string{kMigration: "m2"}), now)).To(gomega.BeFalse())
+	g.Expect(owners.collectable(artifact(time.Hour, map[string]string{kMigration: "m0"}), now)).To(gomega.BeTrue())
+	// Archived plan.
+	g.Expect(owners.collectable(artifact(time.Hour, map[string]string{kMigration: "m3"}), now)).To(gomega.BeTrue())
+	// Failed plan.
+	g.Expect(owners.collectable(artifact(time.Hour, map[string]string{kMigration: "m4", kPlan: "failed"}), now)).To(gomega.BeTrue())
+	// Deleted plan.
+	g.Expect(owners.collectable(artifact(time.Hour, map[string]string{kMigration: "m4", kPlan: "deleted"}), now)).To(gomega.BeTrue())
+	// Migration not yet in the plan history.
+	g.Expect(owners.collectable(artifact(time.Hour, map[string]string{kMigration: "m5"}), now)).To(gomega.BeTrue())
+	owners.plans["new"].Status.SetCondition(libcnd.Condition{Type: Executing, Status: libcnd.True})
+	g.Expect(owners.collectable(artifact(time.Hour, map[string]string{kMigration: "m5"}), now)).To(gomega.BeFalse())
+}
+
+func TestCollect(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	retention := Settings.GCRetention
+	Settings.GCRetention = 60
+	defer func() {
+		Settings.GCRetention = retention
+	}()
+	created := meta.NewTime(time.Now().Add(-2 * time.Hour))
+	objectMeta := func(name string, labels map[string]string) meta.ObjectMeta {
+		return meta.ObjectMeta{
+			Namespace:         "test",
+			Name:              name,
+			CreationTimestamp: created,
+			Labels:            labels,
+		}
+	}
+	objects := []runtime.Object{
+		// Artifacts of a deleted plan.
+		&core.Pod{ObjectMeta: objectMeta("v2v", map[string]string{kMigration: "gone", kVM: "vm-1", kApp: AppVirtV2v})},
+		&core.Pod{ObjectMeta: objectMeta("populate-1", map[string]string{kMigration: "gone", kApp: AppPopulator})},
+		&core.Secret{ObjectMeta: objectMeta("secret", map[string]string{kMigration: "gone", kPlan: "gone", kApp: AppForklift})},
+		&core.ConfigMap{ObjectMeta: objectMeta("configmap", map[string]string{kMigration: "gone", kPlan: "gone", kApp: AppForklift})},
+		// Unrelated workloads using the same label keys.
+		&core.Pod{ObjectMeta: objectMeta("app", map[string]string{kMigration: "gone", kVM: "vm-1"})},
+		&core.Pod{ObjectMeta: objectMeta("populate-2", map[string]string{kMigration: "gone"})},
+		&core.Pod{ObjectMeta: objectMeta("other", map[string]string{kMigration: "gone", kApp: "other"})},
+		&core.Secret{ObjectMeta: objectMeta("app-secret", map[string]string{kMigration: "gone", kPlan: "gone"})},
+		&core.ConfigMap{ObjectMeta: objectMeta("app-configmap", map[string]string{kMigration: "gone", kPlan: "gone"})},
+	}
+	scheme := runtime.NewScheme()
+	_ = core.AddToScheme(scheme)
+	_ = api.SchemeBuilder.AddToScheme(scheme)
+	fake := fakeClient.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(objects...).
+		Build()
+	collector := &Collector{
+		Reconciler: &Reconciler{
+			base.Reconciler{
+				Client: fake,
+				Log:    log,
+			},
+		},
+		Reader: fake,
+	}
+	collector.Collect()
+
+	exists := func(object client.Object) bool {
+		err := fake.Get(context.TODO(), client.ObjectKeyFromObject(object), object)
+		g.Expect(err == nil || k8serr.IsNotFound(err)).To(gomega.BeTrue())
+		return err == nil
+	}
+	for _, object := range objects[:4] {
+		g.Expect(exists(object.(client.Object))).To(gomega.BeFalse())
+	}
+	for _, object := range objects[4:] {
+		g.Expect(exists(object.(client.Object))).To(gomega.BeTrue())
+	}
+}
+
+func TestSnapshotCollectable(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	vm := &planapi.VMStatus{}
+	g.Expect(snapshotCollectable(vm)).To(gomega.BeFalse())
+	vm.Warm = &planapi.Warm{Precopies: []planapi.Precopy{{Snapshot: "s1"}}}
+	g.Expect(snapshotCollectable(vm)).To(gomega.BeTrue())
+	vm.SetCondition(libcnd.Condition{Type: Succeeded, Status: libcnd.True})
+	g.Expect(snapshotCollectable(vm)).To(gomega.BeFalse())
+
+	plan := &api.Plan{}
+	plan.Status.SetCondition(libcnd.Condition{Type: Failed, Status: libcnd.True})
+	g.Expect(snapshotsCollectable(plan)).To(gomega.BeTrue())
+	plan.Status.SetCondition(libcnd.Condition{Type: Executing, Status: libcnd.True})
+	g.Expect(snapshotsCollectable(plan)).To(gomega.BeFalse())
+
+	// Tracked snapshots.
+	vm.TrackSnapshot("s2")
+	vm.TrackSnapshot("s2")
+	g.Expect(vm.Snapshots).To(gomega.HaveLen(1))
+	g.Expect(snapshotCollectable(vm)).To(gomega.BeTrue())
+	plan = &api.Plan{}
+	plan.Status.SetCondition(libcnd.Condition{Type: Succeeded, Status: libcnd.True})
+	g.Expect(snapshotsCollectable(plan)).To(gomega.BeFalse())
+	plan.Status.Migration.VMs = []*planapi.VMStatus{vm}
+	g.Expect(snapshotsCollectable(plan)).To(gomega.BeTrue())
+	vm.UntrackSnapshot("s2")
+	g.Expect(vm.Snapshots).To(gomega.BeNil())
+	g.Expect(snapshotCollectable(vm)).To(gomega.BeFalse())
+	g.Expect(snapshotsCollectable(plan)).To(gomega.BeFalse())
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/base64"
 	"path"
+	"strconv"
 	"strings"
 
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
@@ -26,6 +27,8 @@ import (
 const (
 	// VM step label
 	kStep = "step"
+	// Precopy (pre-snapshot hook) label
+	kPrecopy = "precopy"
 )
 
 // Hook runner.
@@ -35,11 +38,16 @@ type HookRunner struct {
 	vm *planapi.VMStatus
 	// Hook.
 	hook *api.Hook
+	// Hook step.
+	step string
+	// Precopy (index) of the pre-snapshot hook.
+	precopy string
 }
 
 // Run.
 func (r *HookRunner) Run(vm *planapi.VMStatus) (err error) {
 	r.vm = vm
+	r.step = vm.Phase
 	step, found := vm.FindStep(vm.Phase)
 	if !found {
 		err = liberr.New("Step not found.")
@@ -62,6 +70,51 @@ func (r *HookRunner) Run(vm *planapi.VMStatus) (err error) {
 		return
 	}
 	step.MarkStarted()
+	failure, succeeded := r.outcome(job)
+	if failure != "" {
+		step.AddError(failure)
+		step.MarkCompleted()
+	} else if succeeded {
+		step.Progress.Completed = 1
+		step.MarkCompleted()
+	}
+
+	return
+}
+
+// Run the pre-snapshot hook before the precopy snapshot.
+// The hook is run once for each precopy. Done when the
+// hook is not set or has succeeded.
+func (r *HookRunner) RunPreSnapshot(vm *planapi.VMStatus, step *planapi.Step) (done bool, err error) {
+	r.vm = vm
+	r.step = planapi.HookPreSnapshot
+	r.precopy = strconv.Itoa(vm.Warm.Iterations())
+	ref, found := vm.FindHook(r.step)
+	if !found {
+		done = true
+		return
+	}
+	if r.hook, found = r.FindHook(ref.Hook); !found {
+		step.AddError("Hook not found.")
+		return
+	}
+	job, err := r.ensureJob()
+	if err != nil {
+		return
+	}
+	failure, succeeded := r.outcome(job)
+	if failure != "" {
+		step.AddError(failure)
+		return
+	}
+	done = succeeded
+	return
+}
+
+// The outcome of the job.
+// The failure is reported once the job failed
+// or the retry limit has been exceeded.
+func (r *HookRunner) outcome(job *batch.Job) (failure string, succeeded bool) {
 	conditions := libcnd.Conditions{}
 	for _, cnd := range job.Status.Conditions {
 		conditions.SetCondition(libcnd.Condition{
@@ -72,16 +125,12 @@ func (r *HookRunner) Run(vm *planapi.VMStatus) (err error) {
 		})
 	}
 	if conditions.HasCondition("Failed") {
-		step.AddError(conditions.FindCondition("Failed").Message)
-		step.MarkCompleted()
+		failure = conditions.FindCondition("Failed").Message
 	} else if int(job.Status.Failed) > Settings.Migration.HookRetry {
-		step.AddError("Retry limit exceeded.")
-		step.MarkCompleted()
+		failure = "Retry limit exceeded."
 	} else if job.Status.Succeeded > 0 {
-		step.Progress.Completed = 1
-		step.MarkCompleted()
+		succeeded = true
 	}
-
 	return
 }
 
@@ -157,7 +206,7 @@ func (r *HookRunner) job(mp *core.ConfigMap) (job *batch.Job, err error) {
 				strings.Join([]string{
 					r.Plan.Name,
 					r.vm.ID,
-					r.vm.Phase},
+					r.step},
 					"-") + "-"),
 			Labels: r.labels(),
 		},
@@ -294,13 +343,13 @@ func (r *HookRunner) configMap() (mp *core.ConfigMap, err error) {
 	}
 	mp = &core.ConfigMap{
 		ObjectMeta: meta.ObjectMeta{
-			Labels:    r.labels(),
+			Labels:    artifactLabels(r.labels(), AppHook),
 			Namespace: r.Plan.Namespace,
 			GenerateName: strings.ToLower(
 				strings.Join([]string{
 					r.Plan.Name,
 					r.vm.ID,
-					r.vm.Phase},
+					r.step},
 					"-")) + "-",
 		},
 		Data: map[string]string{
@@ -356,11 +405,15 @@ func (r *HookRunner) plan() (plan string, err error) {
 }
 
 // Labels for created resources.
-func (r *HookRunner) labels() map[string]string {
-	return map[string]string{
+func (r *HookRunner) labels() (labels map[string]string) {
+	labels = map[string]string{
 		kPlan:      string(r.Plan.UID),
 		kMigration: string(r.Migration.UID),
 		kVM:        r.vm.ID,
-		kStep:      r.vm.Phase,
+		kStep:      r.step,
+	}
+	if r.precopy != "" {
+		labels[kPrecopy] = r.precopy
 	}
+	return
 }
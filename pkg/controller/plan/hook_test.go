@@ -0,0 +1,64 @@
+package plan
+
+import (
+	"testing"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	"github.com/onsi/gomega"
+	batch "k8s.io/api/batch/v1"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRunPreSnapshot(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	ctx := &plancontext.Context{
+		Plan:      &api.Plan{ObjectMeta: meta.ObjectMeta{UID: "p1"}},
+		Migration: &api.Migration{ObjectMeta: meta.ObjectMeta{UID: "m1"}},
+	}
+	vm := &planapi.VMStatus{Warm: &planapi.Warm{Precopies: []planapi.Precopy{{}}}}
+	vm.ID = "vm-1"
+	step := &planapi.Step{}
+
+	// Not set.
+	runner := HookRunner{Context: ctx}
+	done, err := runner.RunPreSnapshot(vm, step)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(done).To(gomega.BeTrue())
+	g.Expect(runner.labels()).To(gomega.Equal(map[string]string{
+		kPlan:      "p1",
+		kMigration: "m1",
+		kVM:        "vm-1",
+		kStep:      planapi.HookPreSnapshot,
+		kPrecopy:   "1",
+	}))
+	// Not found.
+	vm.Hooks = []planapi.HookRef{
+		{Step: planapi.HookPreSnapshot, Hook: core.ObjectReference{Namespace: "ns", Name: "flush"}},
+	}
+	done, err = runner.RunPreSnapshot(vm, step)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(done).To(gomega.BeFalse())
+	g.Expect(step.HasError()).To(gomega.BeTrue())
+}
+
+func TestHookOutcome(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	runner := HookRunner{}
+	job := &batch.Job{}
+	failure, succeeded := runner.outcome(job)
+	g.Expect(failure).To(gomega.BeEmpty())
+	g.Expect(succeeded).To(gomega.BeFalse())
+	job.Status.Succeeded = 1
+	_, succeeded = runner.outcome(job)
+	g.Expect(succeeded).To(gomega.BeTrue())
+	job.Status.Conditions = []batch.JobCondition{
+		{Type: batch.JobFailed, Status: core.ConditionTrue, Message: "Playbook failed."},
+	}
+	failure, _ = runner.outcome(job)
+	g.Expect(failure).To(gomega.Equal("Playbook failed."))
+}
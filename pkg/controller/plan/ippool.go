@@ -0,0 +1,260 @@
+package plan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	k8snet "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	core "k8s.io/api/core/v1"
+	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Static IP pool of the transfer network.
+// Either a CIDR (all host addresses) or a range with
+// the prefix length: `192.168.10.100-192.168.10.150/24`.
+type IPPool struct {
+	// First address.
+	first net.IP
+	// Last address.
+	last net.IP
+	// Prefix length.
+	prefix int
+}
+
+// Parse the IP pool.
+func ParseIPPool(s string) (pool *IPPool, err error) {
+	s = strings.TrimSpace(s)
+	slash := strings.LastIndex(s, "/")
+	if slash == -1 {
+		err = liberr.New("IP pool prefix length not specified.", "pool", s)
+		return
+	}
+	if dash := strings.Index(s, "-"); dash != -1 {
+		first := net.ParseIP(s[:dash])
+		last := net.ParseIP(s[dash+1 : slash])
+		var prefix int
+		_, pErr := fmt.Sscanf(s[slash+1:], "%d", &prefix)
+		if first == nil || last == nil || pErr != nil {
+			err = liberr.New("IP pool range not valid.", "pool", s)
+			return
+		}
+		if (first.To4() == nil) != (last.To4() == nil) || ipInt(first).Cmp(ipInt(last)) > 0 {
+			err = liberr.New("IP pool range not valid.", "pool", s)
+			return
+		}
+		bits := 128
+		if first.To4() != nil {
+			bits = 32
+		}
+		if prefix < 0 || prefix > bits {
+			err = liberr.New("IP pool prefix length not valid.", "pool", s)
+			return
+		}
+		pool = &IPPool{first: first, last: last, prefix: prefix}
+		return
+	}
+	_, network, pErr := net.ParseCIDR(s)
+	if pErr != nil {
+		err = liberr.New("IP pool CIDR not valid.", "pool", s)
+		return
+	}
+	prefix, bits := network.Mask.Size()
+	first := ipInt(network.IP)
+	last := new(big.Int).Add(first, new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(bits-prefix)), big.NewInt(1)))
+	if bits == 32 && bits-prefix > 1 {
+		// exclude the network and broadcast addresses.
+		first.Add(first, big.NewInt(1))
+		last.Sub(last, big.NewInt(1))
+	}
+	pool = &IPPool{first: intIP(first, bits), last: intIP(last, bits), prefix: prefix}
+	return
+}
+
+// Allocate the first address not used.
+// Returns the address with the prefix length (CIDR notation).
+func (r *IPPool) Allocate(used map[string]bool) (address string, found bool) {
+	bits := 128
+	if r.first.To4() != nil {
+		bits = 32
+	}
+	last := ipInt(r.last)
+	for n := ipInt(r.first); n.Cmp(last) <= 0; n.Add(n, big.NewInt(1)) {
+		ip := intIP(n, bits).String()
+		if !used[ip] {
+			address = fmt.Sprintf("%s/%d", ip, r.prefix)
+			found = true
+			return
+		}
+	}
+	return
+}
+
+// IP as integer.
+func ipInt(ip net.IP) *big.Int {
+	if v4 := ip.To4(); v4 != nil {
+		return new(big.Int).SetBytes(v4)
+	}
+	return new(big.Int).SetBytes(ip.To16())
+}
+
+// Integer as IP.
+func intIP(n *big.Int, bits int) net.IP {
+	b := n.Bytes()
+	ip := make(net.IP, bits/8)
+	copy(ip[len(ip)-len(b):], b)
+	return ip
+}
+
+// Find the transfer network IPs in use by the pods (not terminated)
+// and the data volumes (not completed) on the destination.
+func (r *KubeVirt) transferNetworkIPs(network client.ObjectKey) (used map[string]bool, err error) {
+	used = map[string]bool{}
+	podList := &core.PodList{}
+	err = r.Destination.Client.List(context.TODO(), podList)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		switch pod.Status.Phase {
+		case core.PodSucceeded, core.PodFailed:
+			continue
+		}
+		addTransferNetworkIPs(pod.Annotations, network, used)
+	}
+	dvList := &cdi.DataVolumeList{}
+	err = r.Destination.Client.List(context.TODO(), dvList)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for i := range dvList.Items {
+		dv := &dvList.Items[i]
+		if dv.Status.Phase == cdi.Succeeded {
+			continue
+		}
+		addTransferNetworkIPs(dv.Annotations, network, used)
+	}
+	return
+}
+
+// Add the IPs of the network requested in the transfer network annotation to the set.
+func addTransferNetworkIPs(annotations map[string]string, network client.ObjectKey, ips map[string]bool) {
+	value, found := annotations[AnnTransferNetwork]
+	if !found {
+		return
+	}
+	elements := []k8snet.NetworkSelectionElement{}
+	if json.Unmarshal([]byte(value), &elements) != nil {
+		return
+	}
+	for _, nse := range elements {
+		if nse.Namespace != network.Namespace || nse.Name != network.Name {
+			continue
+		}
+		for _, address := range nse.IPRequest {
+			ip, _, pErr := net.ParseCIDR(address)
+			if pErr != nil {
+				ip = net.ParseIP(address)
+			}
+			if ip != nil {
+				ips[ip.String()] = true
+			}
+		}
+	}
+}
+
+// Allocate a static IP of the transfer network from the pool.
+// The IP is leased until listed on the destination.
+func (r *KubeVirt) allocateTransferNetworkIP(network client.ObjectKey, s string) (address string, err error) {
+	pool, err := ParseIPPool(s)
+	if err != nil {
+		return
+	}
+	used, err := r.transferNetworkIPs(network)
+	if err != nil {
+		return
+	}
+	transferIPLeases.addTo(network, used)
+	address, found := pool.Allocate(used)
+	if !found {
+		err = liberr.New(
+			"Transfer network IP pool exhausted.",
+			"network", network.String(),
+			"pool", s)
+		return
+	}
+	ip, _, _ := net.ParseCIDR(address)
+	transferIPLeases.add(network, ip.String())
+	return
+}
+
+// Release the transfer network IPs allocated for a pod
+// or data volume not created (already exists).
+func (r *KubeVirt) releaseTransferNetworkIPs(annotations map[string]string) {
+	if r.Plan.Spec.TransferNetwork == nil {
+		return
+	}
+	network := client.ObjectKey{
+		Namespace: r.Plan.Spec.TransferNetwork.Namespace,
+		Name:      r.Plan.Spec.TransferNetwork.Name,
+	}
+	ips := map[string]bool{}
+	addTransferNetworkIPs(annotations, network, ips)
+	for ip := range ips {
+		transferIPLeases.delete(network, ip)
+	}
+}
+
+// Duration of the transfer network IP leases.
+const ipLeaseDuration = 2 * time.Minute
+
+// Transfer network IPs recently allocated.
+// Covers the pods and data volumes created but not yet
+// listed by the (cached) client.
+var transferIPLeases = &ipLeases{leases: map[string]time.Time{}}
+
+// IP leases.
+type ipLeases struct {
+	mutex  sync.Mutex
+	leases map[string]time.Time
+}
+
+// Lease the IP of the network.
+func (r *ipLeases) add(network client.ObjectKey, ip string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.leases[path.Join(network.String(), ip)] = time.Now()
+}
+
+// Delete the lease of the IP of the network.
+func (r *ipLeases) delete(network client.ObjectKey, ip string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.leases, path.Join(network.String(), ip))
+}
+
+// Add the IPs of the network leased (not expired) to the set.
+func (r *ipLeases) addTo(network client.ObjectKey, used map[string]bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for key, leased := range r.leases {
+		if time.Since(leased) > ipLeaseDuration {
+			delete(r.leases, key)
+			continue
+		}
+		if path.Dir(key) == network.String() {
+			used[path.Base(key)] = true
+		}
+	}
+}
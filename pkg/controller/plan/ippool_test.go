@@ -0,0 +1,89 @@
+package plan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestIPPool(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// CIDR excludes the network and broadcast addresses.
+	pool, err := ParseIPPool("192.168.10.0/30")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	used := map[string]bool{}
+	address, found := pool.Allocate(used)
+	g.Expect(found).To(gomega.BeTrue())
+	g.Expect(address).To(gomega.Equal("192.168.10.1/30"))
+	used["192.168.10.1"] = true
+	address, found = pool.Allocate(used)
+	g.Expect(found).To(gomega.BeTrue())
+	g.Expect(address).To(gomega.Equal("192.168.10.2/30"))
+	used["192.168.10.2"] = true
+	_, found = pool.Allocate(used)
+	g.Expect(found).To(gomega.BeFalse())
+
+	// Range.
+	pool, err = ParseIPPool("192.168.10.254-192.168.11.1/16")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	address, found = pool.Allocate(map[string]bool{"192.168.10.254": true, "192.168.10.255": true})
+	g.Expect(found).To(gomega.BeTrue())
+	g.Expect(address).To(gomega.Equal("192.168.11.0/16"))
+
+	// IPv6.
+	pool, err = ParseIPPool("fd00::10-fd00::11/64")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	address, found = pool.Allocate(map[string]bool{"fd00::10": true})
+	g.Expect(found).To(gomega.BeTrue())
+	g.Expect(address).To(gomega.Equal("fd00::11/64"))
+
+	// Not valid.
+	for _, s := range []string{
+		"192.168.10.1",
+		"192.168.10.0/33",
+		"192.168.10.9-192.168.10.1/24",
+		"192.168.10.1-fd00::1/24",
+		"192.168.10.1-192.168.10.9/33",
+		"nope/24",
+	} {
+		_, err = ParseIPPool(s)
+		g.Expect(err).To(gomega.HaveOccurred(), s)
+	}
+}
+
+func TestIPLeases(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	network := client.ObjectKey{Namespace: "test", Name: "transfer"}
+	other := client.ObjectKey{Namespace: "test", Name: "other"}
+	annotations := map[string]string{
+		AnnTransferNetwork: `[{"name":"transfer","namespace":"test","ips":["10.0.0.5/24"]}]`,
+	}
+	ips := map[string]bool{}
+	addTransferNetworkIPs(annotations, network, ips)
+	g.Expect(ips).To(gomega.Equal(map[string]bool{"10.0.0.5": true}))
+	ips = map[string]bool{}
+	addTransferNetworkIPs(annotations, other, ips)
+	g.Expect(ips).To(gomega.BeEmpty())
+
+	leases := &ipLeases{leases: map[string]time.Time{}}
+	leases.add(network, "10.0.0.5")
+	used := map[string]bool{}
+	leases.addTo(network, used)
+	g.Expect(used).To(gomega.HaveKey("10.0.0.5"))
+	used = map[string]bool{}
+	leases.addTo(other, used)
+	g.Expect(used).To(gomega.BeEmpty())
+	leases.delete(network, "10.0.0.5")
+	leases.addTo(network, used)
+	g.Expect(used).To(gomega.BeEmpty())
+
+	// Expired.
+	leases.leases["test/transfer/10.0.0.6"] = time.Now().Add(-2 * ipLeaseDuration)
+	leases.addTo(network, used)
+	g.Expect(used).To(gomega.BeEmpty())
+	g.Expect(leases.leases).To(gomega.BeEmpty())
+}
@@ -24,6 +24,7 @@ import (
 	model "github.com/kubev2v/forklift/pkg/controller/provider/web/vsphere"
 	libref "github.com/kubev2v/forklift/pkg/lib/ref"
 	"github.com/kubev2v/forklift/pkg/settings"
+	"github.com/kubev2v/forklift/pkg/templateutil"
 	template "github.com/openshift/api/template/v1"
 	"github.com/openshift/library-go/pkg/template/generator"
 	"github.com/openshift/library-go/pkg/template/templateprocessing"
@@ -46,10 +47,14 @@ import (
 	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
 	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
 	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	"github.com/kubev2v/forklift/pkg/lib/tracing"
 	core "k8s.io/api/core/v1"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	k8smeta "k8s.io/apimachinery/pkg/api/meta"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
 	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	k8sutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -66,6 +71,12 @@ const (
 	// Annotation to specify the default route for the transfer network.
 	// To be set on the transfer network NAD by the end user.
 	AnnForkliftNetworkRoute = "forklift.konveyor.io/route"
+	// Annotation to specify the static IP pool of the transfer network.
+	// Either a CIDR or a range with the prefix length (192.168.10.100-192.168.10.150/24).
+	// To be set on the transfer network NAD (with static IPAM) by the end user.
+	AnnForkliftNetworkIPPool = "forklift.konveyor.io/ipPool"
+	// SR-IOV device plugin resource of the NAD.
+	AnnResourceName = "k8s.v1.cni.cncf.io/resourceName"
 	// Contains validations for a Kubevirt VM. Needs to be removed when
 	// creating a VM from a template.
 	AnnKubevirtValidations = "vm.kubevirt.io/validations"
@@ -75,8 +86,20 @@ const (
 	AnnDisplayName = "openshift.io/display-name"
 	//  Original VM name on source (value=vmOriginalID)
 	AnnOriginalID = "original-ID"
+	// Descheduler annotation allowing the eviction of the VM
+	AnnDeschedulerEvict = "descheduler.alpha.kubernetes.io/evict"
+	// Descheduler annotation requesting not to evict the VM
+	AnnDeschedulerPreferNoEviction = "descheduler.alpha.kubernetes.io/prefer-no-eviction"
 	// DV deletion on completion
 	AnnDeleteAfterCompletion = "cdi.kubevirt.io/storage.deleteAfterCompletion"
+	// Source VM description (notes).
+	AnnSourceDescription = "forklift.konveyor.io/source-description"
+	// Source VM inventory path (e.g. the VM folder path).
+	AnnSourcePath = "forklift.konveyor.io/source-path"
+	// Source VM cluster inventory path.
+	AnnSourceCluster = "forklift.konveyor.io/source-cluster"
+	// Max length of the source VM description annotation.
+	DescriptionMaxLength = 4096
 	// Max Length for vm name
 	NameMaxLength            = 63
 	VddkVolumeName           = "vddk-vol-mount"
@@ -100,6 +123,15 @@ const (
 	kUse = "use"
 )
 
+// Apps (kApp label) of the migration artifacts.
+const (
+	AppConsumer  = "consumer"
+	AppVirtV2v   = "virt-v2v"
+	AppPopulator = "populator"
+	AppHook      = "hook"
+	AppForklift  = "forklift"
+)
+
 // User
 const (
 	// Qemu user
@@ -123,6 +155,31 @@ const (
 	VddkAioBufCountDefault = "4"
 )
 
+// Cloud-init
+const (
+	// Suffix of the cloud-init secret (name) of the VM.
+	CloudInitSecretSuffix = "-cloudinit"
+	// Cloud-init volume (and disk) name.
+	CloudInitVolumeName = "cloudinitdisk"
+	// Secret keys of the cloud-init data.
+	CloudInitUserData    = "userdata"
+	CloudInitNetworkData = "networkdata"
+)
+
+// CD-ROM (ISO) media
+const (
+	// Suffix of the ISO media secret, config map and
+	// data volumes (followed by the index) of the VM.
+	CDROMSuffix = "-cdrom"
+	// Default size of the ISO media data volumes.
+	DefaultCDROMSize = "10Gi"
+	// Secret keys of the ISO media import credentials.
+	CDROMAccessKey = "accessKeyId"
+	CDROMSecretKey = "secretKey"
+	// Config map key of the ISO media import CA certificate.
+	CDROMCACert = "ca.pem"
+)
+
 // Map of VirtualMachines keyed by vmID.
 type VirtualMachineMap map[string]VirtualMachine
 
@@ -211,7 +268,7 @@ func (r *KubeVirt) ListVMs() ([]VirtualMachine, error) {
 
 // Ensure the namespace exists on the destination.
 func (r *KubeVirt) EnsureNamespace() error {
-	err := ensureNamespace(r.Plan, r.Destination.Client)
+	err := ensureNamespace(r.Plan, r.Client, r.Destination.Client)
 	if err == nil {
 		r.Log.Info(
 			"Created namespace.",
@@ -450,6 +507,12 @@ func (r *KubeVirt) EnsureVM(vm *plan.VMStatus) error {
 		if virtualMachine, err = r.virtualMachine(vm, false); err != nil {
 			return liberr.Wrap(err)
 		}
+		if err = r.ensureCloudInit(vm, virtualMachine); err != nil {
+			return liberr.Wrap(err)
+		}
+		if err = r.ensureCDROMs(vm, virtualMachine); err != nil {
+			return liberr.Wrap(err)
+		}
 		if err = r.Destination.Client.Create(context.TODO(), virtualMachine); err != nil {
 			return liberr.Wrap(err)
 		}
@@ -494,9 +557,295 @@ func (r *KubeVirt) EnsureVM(vm *plan.VMStatus) error {
 		}
 	}
 
+	// set the cloud-init secret owner reference so that it'll be
+	// cleaned up when the VirtualMachine is removed.
+	err = r.setCloudInitOwner(virtualMachine)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+
+	// set the ISO media owner references so that they'll be
+	// cleaned up when the VirtualMachine is removed.
+	err = r.setCDROMOwner(virtualMachine)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+
 	return nil
 }
 
+// Ensure the secret of the cloud-init data carried over from the
+// source VM and add the NoCloud volume to the VM.
+// The secret is not labeled as a migration artifact as it is
+// needed for as long as the VM exists.
+func (r *KubeVirt) ensureCloudInit(vm *plan.VMStatus, object *cnv.VirtualMachine) (err error) {
+	if !r.Plan.Spec.PreserveCloudInit || object.Spec.Template == nil {
+		return
+	}
+	cloudInit, err := r.Builder.CloudInit(vm.Ref)
+	if err != nil || cloudInit == nil {
+		return
+	}
+	data := map[string][]byte{}
+	if cloudInit.UserData != "" {
+		data[CloudInitUserData] = []byte(cloudInit.UserData)
+	}
+	if cloudInit.NetworkData != "" {
+		data[CloudInitNetworkData] = []byte(cloudInit.NetworkData)
+	}
+	secret := &core.Secret{}
+	key := client.ObjectKey{
+		Namespace: object.Namespace,
+		Name:      object.Name + CloudInitSecretSuffix,
+	}
+	err = r.Destination.Client.Get(context.TODO(), key, secret)
+	switch {
+	case err == nil:
+		secret.Data = data
+		err = r.Destination.Client.Update(context.TODO(), secret)
+	case k8serr.IsNotFound(err):
+		secret = &core.Secret{
+			ObjectMeta: meta.ObjectMeta{
+				Namespace: key.Namespace,
+				Name:      key.Name,
+			},
+			Data: data,
+		}
+		err = r.Destination.Client.Create(context.TODO(), secret)
+	}
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	setCloudInit(&object.Spec, secret)
+	r.Log.Info(
+		"Cloud-init carried over.",
+		"secret",
+		path.Join(
+			secret.Namespace,
+			secret.Name),
+		"vm",
+		vm.String())
+	return
+}
+
+// Set the VM owner reference on the cloud-init secret.
+func (r *KubeVirt) setCloudInitOwner(object *cnv.VirtualMachine) (err error) {
+	if !r.Plan.Spec.PreserveCloudInit {
+		return
+	}
+	secret := &core.Secret{}
+	err = r.Destination.Client.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: object.Namespace,
+			Name:      object.Name + CloudInitSecretSuffix,
+		},
+		secret)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			err = nil
+		}
+		return
+	}
+	if len(secret.OwnerReferences) > 0 {
+		return
+	}
+	secretCopy := secret.DeepCopy()
+	secret.OwnerReferences = []meta.OwnerReference{vmOwnerReference(object)}
+	err = r.Destination.Client.Patch(context.TODO(), secret, client.MergeFrom(secretCopy))
+	return
+}
+
+// Ensure the data volumes copying the ISO media of the source VM
+// CD-ROMs and add the CD-ROMs to the VM. The media are imported
+// (HTTPS) using the source provider credentials and CA certificate.
+// Like the cloud-init secret, the data volumes, secret and config
+// map are not labeled as migration artifacts.
+func (r *KubeVirt) ensureCDROMs(vm *plan.VMStatus, object *cnv.VirtualMachine) (err error) {
+	settings := r.Plan.Spec.CDROMs
+	if !settings.Copied() || object.Spec.Template == nil {
+		return
+	}
+	media, err := r.Builder.CDROMs(vm.Ref)
+	if err != nil || len(media) == 0 {
+		return
+	}
+	name := object.Name + CDROMSuffix
+	certConfigMap, err := r.ensureCDROMCredentials(object.Namespace, name)
+	if err != nil {
+		return
+	}
+	size := resource.MustParse(DefaultCDROMSize)
+	if settings.Size != nil {
+		size = *settings.Size
+	}
+	for i, medium := range media {
+		dv := &cdi.DataVolume{
+			ObjectMeta: meta.ObjectMeta{
+				Namespace: object.Namespace,
+				Name:      fmt.Sprintf("%s-%d", name, i),
+				Annotations: map[string]string{
+					planbase.AnnBindImmediate: "true",
+				},
+			},
+			Spec: cdi.DataVolumeSpec{
+				Source: &cdi.DataVolumeSource{
+					HTTP: &cdi.DataVolumeSourceHTTP{
+						URL:           medium.URL,
+						SecretRef:     name,
+						CertConfigMap: certConfigMap,
+					},
+				},
+				Storage: &cdi.StorageSpec{
+					Resources: core.VolumeResourceRequirements{
+						Requests: core.ResourceList{
+							core.ResourceStorage: size,
+						},
+					},
+				},
+			},
+		}
+		if settings.StorageClass != "" {
+			storageClass := settings.StorageClass
+			dv.Spec.Storage.StorageClassName = &storageClass
+		}
+		err = r.Destination.Client.Create(context.TODO(), dv)
+		if err != nil {
+			if !k8serr.IsAlreadyExists(err) {
+				err = liberr.Wrap(err)
+				return
+			}
+			err = nil
+		} else {
+			r.Log.Info(
+				"Created ISO media DataVolume.",
+				"dv",
+				path.Join(
+					dv.Namespace,
+					dv.Name),
+				"iso",
+				medium.File,
+				"vm",
+				vm.String())
+		}
+		setCDROM(&object.Spec, dv.Name)
+	}
+	return
+}
+
+// Ensure the secret (and config map) of the credentials (and
+// CA certificate) used to import the ISO media.
+// Returns the config map name, empty without CA certificate.
+func (r *KubeVirt) ensureCDROMCredentials(namespace, name string) (certConfigMap string, err error) {
+	key := client.ObjectKey{
+		Namespace: namespace,
+		Name:      name,
+	}
+	data := map[string][]byte{
+		CDROMAccessKey: r.Source.Secret.Data["user"],
+		CDROMSecretKey: r.Source.Secret.Data["password"],
+	}
+	secret := &core.Secret{}
+	err = r.Destination.Client.Get(context.TODO(), key, secret)
+	switch {
+	case err == nil:
+		secret.Data = data
+		err = r.Destination.Client.Update(context.TODO(), secret)
+	case k8serr.IsNotFound(err):
+		secret = &core.Secret{
+			ObjectMeta: meta.ObjectMeta{
+				Namespace: key.Namespace,
+				Name:      key.Name,
+			},
+			Data: data,
+		}
+		err = r.Destination.Client.Create(context.TODO(), secret)
+	}
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	cacert, found := r.Source.Secret.Data["cacert"]
+	if !found {
+		return
+	}
+	configMap := &core.ConfigMap{}
+	err = r.Destination.Client.Get(context.TODO(), key, configMap)
+	switch {
+	case err == nil:
+		configMap.Data = map[string]string{CDROMCACert: string(cacert)}
+		err = r.Destination.Client.Update(context.TODO(), configMap)
+	case k8serr.IsNotFound(err):
+		configMap = &core.ConfigMap{
+			ObjectMeta: meta.ObjectMeta{
+				Namespace: key.Namespace,
+				Name:      key.Name,
+			},
+			Data: map[string]string{CDROMCACert: string(cacert)},
+		}
+		err = r.Destination.Client.Create(context.TODO(), configMap)
+	}
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	certConfigMap = name
+	return
+}
+
+// Set the VM owner reference on the ISO media data
+// volumes, secret and config map.
+func (r *KubeVirt) setCDROMOwner(object *cnv.VirtualMachine) (err error) {
+	if !r.Plan.Spec.CDROMs.Copied() || object.Spec.Template == nil {
+		return
+	}
+	name := object.Name + CDROMSuffix
+	owned := []client.Object{}
+	for _, volume := range object.Spec.Template.Spec.Volumes {
+		if volume.DataVolume != nil && strings.HasPrefix(volume.DataVolume.Name, name+"-") {
+			owned = append(
+				owned,
+				&cdi.DataVolume{
+					ObjectMeta: meta.ObjectMeta{Name: volume.DataVolume.Name},
+				})
+		}
+	}
+	if len(owned) == 0 {
+		return
+	}
+	owned = append(
+		owned,
+		&core.Secret{ObjectMeta: meta.ObjectMeta{Name: name}},
+		&core.ConfigMap{ObjectMeta: meta.ObjectMeta{Name: name}})
+	for _, owner := range owned {
+		key := client.ObjectKey{
+			Namespace: object.Namespace,
+			Name:      owner.GetName(),
+		}
+		err = r.Destination.Client.Get(context.TODO(), key, owner)
+		if err != nil {
+			if k8serr.IsNotFound(err) {
+				err = nil
+				continue
+			}
+			err = liberr.Wrap(err)
+			return
+		}
+		if len(owner.GetOwnerReferences()) > 0 {
+			continue
+		}
+		patch := client.MergeFrom(owner.DeepCopyObject().(client.Object))
+		owner.SetOwnerReferences([]meta.OwnerReference{vmOwnerReference(object)})
+		err = r.Destination.Client.Patch(context.TODO(), owner, patch)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+	return
+}
+
 // Delete the Secret that was created for this VM.
 func (r *KubeVirt) DeleteSecret(vm *plan.VMStatus) (err error) {
 	vmLabels := r.vmAllButMigrationLabels(vm.Ref)
@@ -609,6 +958,7 @@ func (r *KubeVirt) DataVolumes(vm *plan.VMStatus) (dataVolumes []cdi.DataVolume,
 	if err != nil {
 		return
 	}
+	err = r.createLunDisks(vm.Ref)
 	return
 }
 
@@ -625,7 +975,97 @@ func (r *KubeVirt) PopulatorVolumes(vmRef ref.Ref) (pvcs []*core.PersistentVolum
 		err = liberr.Wrap(err)
 		return
 	}
-	return r.Builder.PopulatorVolumes(vmRef, annotations, secret.Name)
+	pvcs, err = r.Builder.PopulatorVolumes(vmRef, annotations, secret.Name)
+	if err != nil {
+		return
+	}
+	vm, _ := r.FindVM(vmRef)
+	if vm == nil {
+		vm = &plan.VMStatus{VM: plan.VM{Ref: vmRef}}
+	}
+	for i, pvc := range pvcs {
+		err = r.setVolumeMetadata(&pvc.ObjectMeta, r.volumeTemplateData(vm, i, pvc.Annotations))
+		if err != nil {
+			return
+		}
+		pvc.Labels = r.setBackupLabel(pvc.Labels)
+	}
+	return
+}
+
+// Template data of the volume of a VM disk.
+// The disk index is annotated by the builders
+// that know it, otherwise the volume index.
+func (r *KubeVirt) volumeTemplateData(vm *plan.VMStatus, index int, annotations map[string]string) (data *api.PVCNameTemplateData) {
+	data = &api.PVCNameTemplateData{
+		VmName:        r.getNewVMName(vm),
+		PlanName:      r.Plan.Name,
+		DiskIndex:     index,
+		RootDiskIndex: util.GetBootDiskNumber(vm.RootDisk),
+		FileName:      annotations[planbase.AnnDiskSource],
+	}
+	if n, err := strconv.Atoi(annotations[planbase.AnnDiskIndex]); err == nil {
+		data.DiskIndex = n
+	}
+	return
+}
+
+// Name the volume by the PVC name template of the VM or plan.
+// The volume keeps the generated name when the template
+// output is not a valid name.
+func (r *KubeVirt) setVolumeName(vm *plan.VMStatus, object *meta.ObjectMeta, data *api.PVCNameTemplateData) {
+	tmpl := vm.PVCNameTemplate
+	if tmpl == "" {
+		tmpl = r.Plan.Spec.PVCNameTemplate
+	}
+	if tmpl == "" {
+		return
+	}
+	name, err := templateutil.ExecuteTemplate(tmpl, data)
+	if err == nil && len(k8svalidation.IsDNS1123Label(name)) > 0 {
+		err = liberr.New("generated PVC name is not valid", "name", name)
+	}
+	if err != nil || name == "" {
+		r.Log.Info("Failed to generate PVC name using template", "template", tmpl, "error", err)
+		return
+	}
+	if r.Plan.Spec.PVCNameTemplateUseGenerateName {
+		object.GenerateName = strings.TrimSuffix(name, "-") + "-"
+	} else {
+		object.GenerateName = ""
+		object.Name = strings.Trim(name, "-")
+	}
+}
+
+// Set the labels and annotations of the plan PVC metadata.
+// The labels and annotations set by the controller are kept.
+func (r *KubeVirt) setVolumeMetadata(object *meta.ObjectMeta, data *api.PVCNameTemplateData) (err error) {
+	metadata := r.Plan.Spec.PVCMetadata
+	if metadata == nil {
+		return
+	}
+	render := func(templates map[string]string, values map[string]string) (map[string]string, error) {
+		if len(templates) > 0 && values == nil {
+			values = map[string]string{}
+		}
+		for key, tmpl := range templates {
+			if _, found := values[key]; found {
+				continue
+			}
+			value, tErr := templateutil.ExecuteTemplate(tmpl, data)
+			if tErr != nil {
+				return nil, liberr.Wrap(tErr, "key", key)
+			}
+			values[key] = value
+		}
+		return values, nil
+	}
+	object.Labels, err = render(metadata.Labels, object.Labels)
+	if err != nil {
+		return
+	}
+	object.Annotations, err = render(metadata.Annotations, object.Annotations)
+	return
 }
 
 // Ensure the DataVolumes exist on the destination.
@@ -657,11 +1097,132 @@ func (r *KubeVirt) EnsureDataVolumes(vm *plan.VMStatus, dataVolumes []cdi.DataVo
 					dv.Name),
 				"vm",
 				vm.String())
+		} else {
+			r.releaseTransferNetworkIPs(dv.Annotations)
 		}
 	}
 	return
 }
 
+// Split the DataVolumes of the disks copied at cutover
+// from those transferred incrementally in warm migrations.
+func splitColdCopy(dataVolumes []cdi.DataVolume) (warm, cold []cdi.DataVolume) {
+	for _, dv := range dataVolumes {
+		if coldCopy(&dv) {
+			cold = append(cold, dv)
+		} else {
+			warm = append(warm, dv)
+		}
+	}
+	return
+}
+
+// Determine whether the DataVolume is copied at cutover.
+func coldCopy(dv *cdi.DataVolume) bool {
+	_, found := dv.Annotations[planbase.AnnColdCopy]
+	return found
+}
+
+// Determine whether CDI is installed on the destination.
+func (r *KubeVirt) HasCDI() (found bool, err error) {
+	_, err = r.Destination.Client.RESTMapper().RESTMapping(
+		schema.GroupKind{Group: cdi.SchemeGroupVersion.Group, Kind: "DataVolume"},
+		cdi.SchemeGroupVersion.Version)
+	if err != nil {
+		if k8smeta.IsNoMatchError(err) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	found = true
+	return
+}
+
+// Ensure PVCs exist on the destination in place of the DataVolumes.
+// Used when CDI is not installed on the destination, in which case
+// only blank DataVolumes, populated by the migration, are supported.
+func (r *KubeVirt) EnsurePersistentVolumeClaims(vm *plan.VMStatus, dataVolumes []cdi.DataVolume) (err error) {
+	pvcs, err := r.getPVCs(vm.Ref)
+	if err != nil {
+		return
+	}
+	for i := range dataVolumes {
+		dv := &dataVolumes[i]
+		if dv.Spec.Source != nil && dv.Spec.Source.Blank == nil {
+			err = liberr.New(
+				"the disk transfer requires CDI, which is not installed on the destination.",
+				"vm",
+				vm.String())
+			return
+		}
+		exists := false
+		for _, pvc := range pvcs {
+			if r.Builder.ResolvePersistentVolumeClaimIdentifier(pvc) == r.Builder.ResolveDataVolumeIdentifier(dv) {
+				exists = true
+				break
+			}
+		}
+		if exists {
+			continue
+		}
+		pvc := persistentVolumeClaim(dv)
+		// Without CDI, the filesystem overhead is not added to the DataVolume size.
+		if pvc.Spec.StorageClassName != nil && pvc.Spec.Resources.Requests != nil {
+			var sizing util.Sizing
+			sizing, err = util.GetStorageClassSizing(r.Destination.Client, *pvc.Spec.StorageClassName)
+			if err != nil {
+				return
+			}
+			size := sizing.VolumeSize(pvc.Spec.Resources.Requests.Storage().Value(), util.FormatRaw, pvc.Spec.VolumeMode)
+			pvc.Spec.Resources.Requests[core.ResourceStorage] = *resource.NewQuantity(size, resource.BinarySI)
+		}
+		err = r.Destination.Client.Create(context.TODO(), pvc)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		r.Log.Info("Created PersistentVolumeClaim.",
+			"pvc",
+			path.Join(
+				pvc.Namespace,
+				pvc.Name),
+			"vm",
+			vm.String())
+	}
+	return
+}
+
+// Build the PVC described by a DataVolume.
+func persistentVolumeClaim(dv *cdi.DataVolume) (pvc *core.PersistentVolumeClaim) {
+	pvc = &core.PersistentVolumeClaim{
+		ObjectMeta: meta.ObjectMeta{
+			Name:         dv.Name,
+			GenerateName: dv.GenerateName,
+			Namespace:    dv.Namespace,
+			Labels:       dv.Labels,
+			Annotations:  dv.Annotations,
+		},
+	}
+	switch {
+	case dv.Spec.PVC != nil:
+		pvc.Spec = *dv.Spec.PVC.DeepCopy()
+	case dv.Spec.Storage != nil:
+		pvc.Spec = core.PersistentVolumeClaimSpec{
+			AccessModes:      dv.Spec.Storage.AccessModes,
+			VolumeMode:       dv.Spec.Storage.VolumeMode,
+			Resources:        dv.Spec.Storage.Resources,
+			StorageClassName: dv.Spec.Storage.StorageClassName,
+		}
+	}
+	// Without CDI, there are no storage profiles to provide the defaults.
+	if len(pvc.Spec.AccessModes) == 0 {
+		pvc.Spec.AccessModes = []core.PersistentVolumeAccessMode{core.ReadWriteOnce}
+	}
+	return
+}
+
 func (r *KubeVirt) vddkConfigMap(labels map[string]string) (*core.ConfigMap, error) {
 	data := make(map[string]string)
 	if r.Source.Provider.UseVddkAioOptimization() {
@@ -679,7 +1240,7 @@ func (r *KubeVirt) vddkConfigMap(labels map[string]string) (*core.ConfigMap, err
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: genVddkConfConfigMapName(r.Plan),
 			Namespace:    r.Plan.Spec.TargetNamespace,
-			Labels:       labels,
+			Labels:       artifactLabels(labels, AppForklift),
 		},
 	}
 	return &configMap, nil
@@ -770,12 +1331,17 @@ func (r *KubeVirt) getDVs(vm *plan.VMStatus) (edvs []ExtendedDataVolume, err err
 			Namespace:     r.Plan.Spec.TargetNamespace,
 		})
 
+	edvs = []ExtendedDataVolume{}
 	if err != nil {
-		err = liberr.Wrap(err)
+		// No DataVolumes without CDI on the destination.
+		if k8smeta.IsNoMatchError(err) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
 		return
 	}
 
-	edvs = []ExtendedDataVolume{}
 	for i := range dvsList.Items {
 		dv := &dvsList.Items[i]
 		edvs = append(edvs, ExtendedDataVolume{
@@ -915,6 +1481,7 @@ func (r *KubeVirt) createPodToBindPVCs(vm *plan.VMStatus, pvcNames []string) (er
 		},
 	}
 	// Align with the conversion pod request, to prevent breakage
+	r.setPodSettings(&pod.Spec)
 	r.setKvmOnPodSpec(&pod.Spec)
 
 	err = r.Client.Create(context.TODO(), pod, &client.CreateOptions{})
@@ -931,7 +1498,7 @@ func (r *KubeVirt) setKvmOnPodSpec(podSpec *core.PodSpec) {
 		return
 	}
 	switch *r.Plan.Provider.Source.Spec.Type {
-	case api.VSphere, api.Ova:
+	case api.VSphere, api.Ova, api.EC2, api.Azure:
 		if podSpec.NodeSelector == nil {
 			podSpec.NodeSelector = make(map[string]string)
 		}
@@ -949,6 +1516,52 @@ func (r *KubeVirt) setKvmOnPodSpec(podSpec *core.PodSpec) {
 	}
 }
 
+// Sets the resources and node placement requested by the plan
+// to the conversion pod (and container).
+// Resources not requested by the plan keep the controller defaults.
+// A default limit lower than the requested resource is raised to it.
+func (r *KubeVirt) setPodSettings(podSpec *core.PodSpec) {
+	podSettings := r.Plan.Spec.ConversionPod
+	if podSettings == nil {
+		return
+	}
+	if podSettings.Resources != nil {
+		container := &podSpec.Containers[0]
+		if container.Resources.Requests == nil {
+			container.Resources.Requests = make(map[core.ResourceName]resource.Quantity)
+		}
+		if container.Resources.Limits == nil {
+			container.Resources.Limits = make(map[core.ResourceName]resource.Quantity)
+		}
+		for name, quantity := range podSettings.Resources.Requests {
+			container.Resources.Requests[name] = quantity
+		}
+		for name, quantity := range podSettings.Resources.Limits {
+			container.Resources.Limits[name] = quantity
+		}
+		for name, quantity := range podSettings.Resources.Requests {
+			if _, set := podSettings.Resources.Limits[name]; set {
+				continue
+			}
+			if limit, found := container.Resources.Limits[name]; found && limit.Cmp(quantity) < 0 {
+				container.Resources.Limits[name] = quantity
+			}
+		}
+	}
+	if len(podSettings.NodeSelector) > 0 {
+		if podSpec.NodeSelector == nil {
+			podSpec.NodeSelector = make(map[string]string)
+		}
+		for key, value := range podSettings.NodeSelector {
+			podSpec.NodeSelector[key] = value
+		}
+	}
+	podSpec.Tolerations = append(podSpec.Tolerations, podSettings.Tolerations...)
+	if podSettings.Affinity != nil {
+		podSpec.Affinity = podSettings.Affinity.DeepCopy()
+	}
+}
+
 func (r *KubeVirt) getListOptionsNamespaced() (listOptions *client.ListOptions) {
 	return &client.ListOptions{
 		Namespace: r.Plan.Spec.TargetNamespace,
@@ -1002,6 +1615,8 @@ func (r *KubeVirt) EnsureGuestConversionPod(vm *plan.VMStatus, vmCr *VirtualMach
 				pod.Name),
 			"vm",
 			vm.String())
+	} else {
+		r.releaseTransferNetworkIPs(newPod.Annotations)
 	}
 
 	return
@@ -1082,7 +1697,7 @@ func (r *KubeVirt) getInspectionXml(pod *core.Pod) (string, error) {
 	if pod == nil {
 		return "", liberr.New("no pod found to get the inspection")
 	}
-	inspectionUrl := fmt.Sprintf("http://%s:8080/inspection", pod.Status.PodIP)
+	inspectionUrl := fmt.Sprintf("http://%s/inspection", net.JoinHostPort(pod.Status.PodIP, "8080"))
 	resp, err := http.Get(inspectionUrl)
 	if err != nil {
 		return "", liberr.Wrap(err)
@@ -1101,7 +1716,7 @@ func (r *KubeVirt) UpdateVmByConvertedConfig(vm *plan.VMStatus, pod *core.Pod, s
 		return nil
 	}
 
-	url := fmt.Sprintf("http://%s:8080/vm", pod.Status.PodIP)
+	url := fmt.Sprintf("http://%s/vm", net.JoinHostPort(pod.Status.PodIP, "8080"))
 
 	/* Due to the virt-v2v operation, the ovf file is only available after the command's execution,
 	meaning it appears following the copydisks phase.
@@ -1128,7 +1743,7 @@ func (r *KubeVirt) UpdateVmByConvertedConfig(vm *plan.VMStatus, pod *core.Pod, s
 		if vm.Firmware, err = util.GetFirmwareFromYaml(vmConf); err != nil {
 			return liberr.Wrap(err)
 		}
-	case api.VSphere:
+	case api.VSphere, api.EC2, api.Azure:
 		inspectionXML, err := r.getInspectionXml(pod)
 		if err != nil {
 			return liberr.Wrap(err)
@@ -1139,7 +1754,7 @@ func (r *KubeVirt) UpdateVmByConvertedConfig(vm *plan.VMStatus, pod *core.Pod, s
 		r.Log.Info("Setting the vm OS ", vm.OperatingSystem, "vmId", vm.ID)
 	}
 
-	shutdownURL := fmt.Sprintf("http://%s:8080/shutdown", pod.Status.PodIP)
+	shutdownURL := fmt.Sprintf("http://%s/shutdown", net.JoinHostPort(pod.Status.PodIP, "8080"))
 	resp, err = http.Post(shutdownURL, "application/json", nil)
 	if err == nil {
 		defer resp.Body.Close()
@@ -1367,12 +1982,6 @@ func (r *KubeVirt) dataVolumes(vm *plan.VMStatus, secret *core.Secret, configMap
 	if Settings.RetainPrecopyImporterPods {
 		annotations[planbase.AnnRetainAfterCompletion] = "true"
 	}
-	if r.Plan.Spec.TransferNetwork != nil {
-		err = r.setTransferNetwork(annotations)
-		if err != nil {
-			return
-		}
-	}
 
 	if r.Plan.Spec.Warm || !r.Destination.Provider.IsHost() || r.Plan.IsSourceProviderOCP() {
 		// Set annotation for WFFC storage classes. Note that we create data volumes while
@@ -1396,8 +2005,28 @@ func (r *KubeVirt) dataVolumes(vm *plan.VMStatus, secret *core.Secret, configMap
 	if err != nil {
 		return
 	}
-
-	err = r.createLunDisks(vm.Ref)
+	for i := range dataVolumes {
+		dv := &dataVolumes[i]
+		data := r.volumeTemplateData(vm, i, dv.Annotations)
+		// Named by the template unless named by the builder.
+		if dv.Name == "" && dv.GenerateName == dvTemplate.GenerateName {
+			r.setVolumeName(vm, &dv.ObjectMeta, data)
+		}
+		err = r.setVolumeMetadata(&dv.ObjectMeta, data)
+		if err != nil {
+			return
+		}
+		dv.Labels = r.setBackupLabel(dv.Labels)
+	}
+	// Set for each data volume to allocate distinct (static) IPs.
+	if r.Plan.Spec.TransferNetwork != nil {
+		for i := range dataVolumes {
+			err = r.setTransferNetwork(dataVolumes[i].Annotations)
+			if err != nil {
+				return
+			}
+		}
+	}
 
 	return
 }
@@ -1431,7 +2060,12 @@ func (r *KubeVirt) virtualMachine(vm *plan.VMStatus, sortVolumesByLibvirt bool)
 		err = liberr.Wrap(err)
 		return
 	}
+	object, err = r.buildVirtualMachine(vm, pvcs, sortVolumesByLibvirt)
+	return
+}
 
+// Build the Kubevirt VM CR using the PVCs of the VM disks.
+func (r *KubeVirt) buildVirtualMachine(vm *plan.VMStatus, pvcs []*core.PersistentVolumeClaim, sortVolumesByLibvirt bool) (object *cnv.VirtualMachine, err error) {
 	var ok bool
 	object, err = r.vmPreference(vm)
 	if err != nil {
@@ -1490,6 +2124,270 @@ func (r *KubeVirt) virtualMachine(vm *plan.VMStatus, sortVolumesByLibvirt bool)
 		return
 	}
 
+	r.setCPUModel(vm, object)
+
+	err = r.setProvenance(vm, object)
+	if err != nil {
+		return
+	}
+
+	err = r.setAttributes(vm, object)
+	if err != nil {
+		return
+	}
+
+	err = r.setSRIOVResources(&object.Spec)
+	if err != nil {
+		return
+	}
+
+	r.setPlacement(&object.Spec)
+
+	return
+}
+
+// Annotate the VM with the source VM description, path and
+// cluster path. The description is truncated when too long.
+func (r *KubeVirt) setProvenance(vm *plan.VMStatus, object *cnv.VirtualMachine) (err error) {
+	provenance, err := r.Builder.Provenance(vm.Ref)
+	if err != nil || provenance == nil {
+		return
+	}
+	annotations := map[string]string{
+		AnnSourceDescription: provenance.Description,
+		AnnSourcePath:        provenance.Path,
+		AnnSourceCluster:     provenance.Cluster,
+	}
+	if runes := []rune(provenance.Description); len(runes) > DescriptionMaxLength {
+		annotations[AnnSourceDescription] = string(runes[:DescriptionMaxLength])
+	}
+	for key, value := range annotations {
+		if value == "" {
+			continue
+		}
+		if object.ObjectMeta.Annotations == nil {
+			object.ObjectMeta.Annotations = map[string]string{}
+		}
+		object.ObjectMeta.Annotations[key] = value
+	}
+
+	return
+}
+
+// Set the labels and annotations mapped from the source VM attributes
+// by the plan attribute map. The labels are set on both the VM and
+// the VM template (pods) and the attributes not set are ignored.
+func (r *KubeVirt) setAttributes(vm *plan.VMStatus, object *cnv.VirtualMachine) (err error) {
+	if len(r.Plan.Spec.AttributeMap) == 0 {
+		return
+	}
+	attributes, err := r.Builder.Attributes(vm.Ref)
+	if err != nil {
+		return
+	}
+	for _, mapping := range r.Plan.Spec.AttributeMap {
+		value, found := attributes[mapping.Source]
+		if !found {
+			continue
+		}
+		if mapping.Annotation != "" {
+			if object.ObjectMeta.Annotations == nil {
+				object.ObjectMeta.Annotations = map[string]string{}
+			}
+			object.ObjectMeta.Annotations[mapping.Annotation] = value
+		}
+		if mapping.Label == "" {
+			continue
+		}
+		labelValue := util.LabelValue(value)
+		if labelValue == "" {
+			r.Log.Info(
+				"Attribute value not valid as a label value.",
+				"vm",
+				vm.String(),
+				"attribute",
+				mapping.Source)
+			continue
+		}
+		if object.ObjectMeta.Labels == nil {
+			object.ObjectMeta.Labels = map[string]string{}
+		}
+		object.ObjectMeta.Labels[mapping.Label] = labelValue
+		if object.Spec.Template != nil {
+			if object.Spec.Template.ObjectMeta.Labels == nil {
+				object.Spec.Template.ObjectMeta.Labels = map[string]string{}
+			}
+			object.Spec.Template.ObjectMeta.Labels[mapping.Label] = labelValue
+		}
+	}
+
+	return
+}
+
+// Set the node placement requested by the plan on the VM template.
+func (r *KubeVirt) setPlacement(spec *cnv.VirtualMachineSpec) {
+	placement := r.Plan.Spec.TargetPlacement
+	if placement == nil || spec.Template == nil {
+		return
+	}
+	template := spec.Template
+	if len(placement.NodeSelector) > 0 {
+		if template.Spec.NodeSelector == nil {
+			template.Spec.NodeSelector = make(map[string]string)
+		}
+		for key, value := range placement.NodeSelector {
+			template.Spec.NodeSelector[key] = value
+		}
+	}
+	template.Spec.Tolerations = append(template.Spec.Tolerations, placement.Tolerations...)
+	if placement.Affinity != nil {
+		if template.Spec.Affinity == nil {
+			template.Spec.Affinity = &core.Affinity{}
+		}
+		mergeAffinity(template.Spec.Affinity, placement.Affinity.DeepCopy())
+	}
+	if placement.Evictable != nil {
+		if template.ObjectMeta.Annotations == nil {
+			template.ObjectMeta.Annotations = make(map[string]string)
+		}
+		if *placement.Evictable {
+			template.ObjectMeta.Annotations[AnnDeschedulerEvict] = "true"
+		} else {
+			template.ObjectMeta.Annotations[AnnDeschedulerPreferNoEviction] = "true"
+		}
+	}
+}
+
+// Merge the affinity into the affinity of the VM template.
+// The node affinity is replaced and the pod (anti-)affinity terms are added.
+func mergeAffinity(affinity *core.Affinity, other *core.Affinity) {
+	if other.NodeAffinity != nil {
+		affinity.NodeAffinity = other.NodeAffinity
+	}
+	if other.PodAffinity != nil {
+		if affinity.PodAffinity == nil {
+			affinity.PodAffinity = &core.PodAffinity{}
+		}
+		affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(
+			affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
+			other.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution...)
+		affinity.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
+			affinity.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+			other.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution...)
+	}
+	if other.PodAntiAffinity != nil {
+		if affinity.PodAntiAffinity == nil {
+			affinity.PodAntiAffinity = &core.PodAntiAffinity{}
+		}
+		affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(
+			affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
+			other.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution...)
+		affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
+			affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+			other.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution...)
+	}
+}
+
+// Override the CPU model (and required features) by the plan.
+// The CPU of VMs with an instance type is not overridden.
+func (r *KubeVirt) setCPUModel(vm *plan.VMStatus, object *cnv.VirtualMachine) {
+	cpuModel := r.Plan.Spec.CPUModel
+	if cpuModel == nil || vm.InstanceType != "" || object.Spec.Template == nil {
+		return
+	}
+	domain := &object.Spec.Template.Spec.Domain
+	if domain.CPU == nil {
+		domain.CPU = &cnv.CPU{}
+	}
+	domain.CPU.Model = cpuModel.Name
+	domain.CPU.Features = nil
+	for _, feature := range cpuModel.Features {
+		domain.CPU.Features = append(
+			domain.CPU.Features,
+			cnv.CPUFeature{
+				Name:   feature,
+				Policy: "require",
+			})
+	}
+}
+
+// Request the SR-IOV device plugin resources of the SR-IOV interfaces.
+// The resource of the network map destination takes precedence over
+// the resource annotated on the NAD.
+func (r *KubeVirt) setSRIOVResources(spec *cnv.VirtualMachineSpec) (err error) {
+	if spec.Template == nil {
+		return
+	}
+	sriov := map[string]bool{}
+	for _, iface := range spec.Template.Spec.Domain.Devices.Interfaces {
+		if iface.SRIOV != nil {
+			sriov[iface.Name] = true
+		}
+	}
+	resources := map[core.ResourceName]int64{}
+	for _, network := range spec.Template.Spec.Networks {
+		if !sriov[network.Name] || network.Multus == nil {
+			continue
+		}
+		var resourceName string
+		resourceName, err = r.sriovResourceName(network.Multus.NetworkName)
+		if err != nil {
+			return
+		}
+		if resourceName != "" {
+			resources[core.ResourceName(resourceName)]++
+		}
+	}
+	if len(resources) == 0 {
+		return
+	}
+	requirements := &spec.Template.Spec.Domain.Resources
+	if requirements.Requests == nil {
+		requirements.Requests = core.ResourceList{}
+	}
+	if requirements.Limits == nil {
+		requirements.Limits = core.ResourceList{}
+	}
+	for name, count := range resources {
+		quantity := *resource.NewQuantity(count, resource.DecimalSI)
+		requirements.Requests[name] = quantity
+		requirements.Limits[name] = quantity
+	}
+	return
+}
+
+// Find the SR-IOV device plugin resource of the (namespaced) NAD.
+func (r *KubeVirt) sriovResourceName(networkName string) (resourceName string, err error) {
+	key := client.ObjectKey{
+		Namespace: r.Plan.Spec.TargetNamespace,
+		Name:      networkName,
+	}
+	if namespace, name, found := strings.Cut(networkName, "/"); found {
+		key.Namespace = namespace
+		key.Name = name
+	}
+	if r.Map.Network != nil {
+		for _, pair := range r.Map.Network.Spec.Map {
+			destination := &pair.Destination
+			if destination.Name == key.Name &&
+				(destination.Namespace == key.Namespace || destination.Namespace == "") &&
+				destination.ResourceName != "" {
+				resourceName = destination.ResourceName
+				return
+			}
+		}
+	}
+	nad := &k8snet.NetworkAttachmentDefinition{}
+	err = r.Destination.Client.Get(context.TODO(), key, nad)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	resourceName = nad.Annotations[AnnResourceName]
 	return
 }
 
@@ -1533,12 +2431,12 @@ func (r *KubeVirt) setInstanceType(vm *plan.VMStatus, object *cnv.VirtualMachine
 func (r *KubeVirt) setVmLabels(object *cnv.VirtualMachine) (err error) {
 	labels := object.ObjectMeta.Labels
 	if labels == nil {
-		object.ObjectMeta.Labels = map[string]string{}
+		labels = map[string]string{}
 	}
 	if r.Plan.Provider.Source.RequiresConversion() {
 		labels["guestConverted"] = strconv.FormatBool(!r.Plan.Spec.SkipGuestConversion)
 	}
-	object.ObjectMeta.Labels = labels
+	object.ObjectMeta.Labels = r.setBackupLabel(labels)
 	return
 }
 
@@ -1935,6 +2833,13 @@ func (r *KubeVirt) guestConversionPod(vm *plan.VMStatus, vmVolumes []cnv.Volume,
 			Value: strconv.FormatBool(r.Destination.Provider.IsHost()),
 		},
 	)
+	environment = append(environment, r.Source.Provider.ProxyEnv()...)
+	// trace the conversion within the migration trace
+	if r.Migration != nil {
+		environment = append(
+			environment,
+			tracing.Env(tracing.WithUID(context.TODO(), string(r.Migration.UID)))...)
+	}
 	// pod annotations
 	annotations := map[string]string{}
 	if r.Plan.Spec.TransferNetwork != nil {
@@ -1975,7 +2880,7 @@ func (r *KubeVirt) guestConversionPod(vm *plan.VMStatus, vmVolumes []cnv.Volume,
 										{
 											Key: kApp,
 											Values: []string{
-												"virt-v2v",
+												AppVirtV2v,
 											},
 											Operator: metav1.LabelSelectorOpIn,
 										},
@@ -2034,6 +2939,8 @@ func (r *KubeVirt) guestConversionPod(vm *plan.VMStatus, vmVolumes []cnv.Volume,
 			Volumes: volumes,
 		},
 	}
+	// Resources and node placement requested by the plan.
+	r.setPodSettings(&pod.Spec)
 	// Request access to /dev/kvm via Kubevirt's Device Manager
 	// That is to ensure the appliance virt-v2v uses would not
 	// run in emulation mode, which is significantly slower
@@ -2185,6 +3092,21 @@ func (r *KubeVirt) podVolumeMounts(vmVolumes []cnv.Volume, libvirtConfigMap *cor
 				},
 			)
 		}
+	case api.EC2, api.Azure:
+		mounts = append(mounts,
+			core.VolumeMount{
+				Name:      "libvirt-domain-xml",
+				MountPath: "/mnt/v2v",
+			},
+		)
+		if extraConfigMapExists {
+			mounts = append(mounts,
+				core.VolumeMount{
+					Name:      ExtraV2vConf,
+					MountPath: fmt.Sprintf("/mnt/%s", ExtraV2vConf),
+				},
+			)
+		}
 	}
 
 	_, exists, err := r.findConfigMapInNamespace(Settings.VirtCustomizeConfigMap, r.Plan.Spec.TargetNamespace)
@@ -2424,7 +3346,7 @@ func (r *KubeVirt) ensureLibvirtConfigMap(vmRef ref.Ref, vmCr *VirtualMachine, p
 func (r *KubeVirt) configMap(vmRef ref.Ref) (object *core.ConfigMap, err error) {
 	object = &core.ConfigMap{
 		ObjectMeta: meta.ObjectMeta{
-			Labels:    r.vmLabels(vmRef),
+			Labels:    artifactLabels(r.vmLabels(vmRef), AppForklift),
 			Namespace: r.Plan.Spec.TargetNamespace,
 			GenerateName: strings.Join(
 				[]string{
@@ -2527,7 +3449,7 @@ func (r *KubeVirt) ensureSecret(vmRef ref.Ref, setSecretData func(*core.Secret)
 func (r *KubeVirt) secret(vmRef ref.Ref, setSecretData func(*core.Secret) error, labels map[string]string) (secret *core.Secret, err error) {
 	secret = &core.Secret{
 		ObjectMeta: meta.ObjectMeta{
-			Labels:    labels,
+			Labels:    artifactLabels(labels, AppForklift),
 			Namespace: r.Plan.Spec.TargetNamespace,
 			GenerateName: strings.Join(
 				[]string{
@@ -2537,6 +3459,16 @@ func (r *KubeVirt) secret(vmRef ref.Ref, setSecretData func(*core.Secret) error,
 		},
 	}
 	err = setSecretData(secret)
+	if err != nil {
+		return
+	}
+	// transfer (populator) pods read the proxy from the secret.
+	for _, env := range r.Source.Provider.ProxyEnv() {
+		if secret.StringData == nil {
+			secret.StringData = map[string]string{}
+		}
+		secret.StringData[env.Name] = env.Value
+	}
 	return
 }
 
@@ -2555,7 +3487,7 @@ func (r *KubeVirt) consumerLabels(vmRef ref.Ref, filterOutMigrationLabel bool) (
 	} else {
 		labels = r.vmLabels(vmRef)
 	}
-	labels[kApp] = "consumer"
+	labels[kApp] = AppConsumer
 	return
 }
 
@@ -2566,7 +3498,7 @@ func (r *KubeVirt) conversionLabels(vmRef ref.Ref, filterOutMigrationLabel bool)
 	} else {
 		labels = r.vmLabels(vmRef)
 	}
-	labels[kApp] = "virt-v2v"
+	labels[kApp] = AppVirtV2v
 	return
 }
 
@@ -2585,6 +3517,21 @@ func (r *KubeVirt) vddkLabels() (labels map[string]string) {
 	return
 }
 
+// Labels of a migration artifact.
+// The artifacts are marked with the app so that only the artifacts
+// created by the migrations are garbage collected. The labels used
+// to select the artifacts are not changed.
+func artifactLabels(labels map[string]string, app string) (marked map[string]string) {
+	marked = make(map[string]string, len(labels)+1)
+	for k, v := range labels {
+		marked[k] = v
+	}
+	if _, found := marked[kApp]; !found {
+		marked[kApp] = app
+	}
+	return
+}
+
 // Labels for a VM on a plan without migration label.
 func (r *KubeVirt) vmAllButMigrationLabels(vmRef ref.Ref) (labels map[string]string) {
 	labels = r.vmLabels(vmRef)
@@ -2595,8 +3542,9 @@ func (r *KubeVirt) vmAllButMigrationLabels(vmRef ref.Ref) (labels map[string]str
 // setTransferNetwork sets the transfer network annotation on the DataVolume so
 // that it can be used by the importer pod. If the `forklift.konveyor.io/route` annotation
 // is present on the referenced NAD, then it will be used with the `k8s.v1.cni.cncf.io/networks` annotation
-// to set the default route. If not, this will fall back to setting the `v1.multus-cni.io/default-network` annotation
-// with the namespaced name of the NAD.
+// to set the default route. If the `forklift.konveyor.io/ipPool` annotation is present, a static IP
+// not in use by another pod is allocated from the pool. If neither, this will fall back to setting
+// the `v1.multus-cni.io/default-network` annotation with the namespaced name of the NAD.
 // FIXME: the codepath using the multus annotation should be phased out.
 func (r *KubeVirt) setTransferNetwork(annotations map[string]string) (err error) {
 	key := client.ObjectKey{
@@ -2610,12 +3558,17 @@ func (r *KubeVirt) setTransferNetwork(annotations map[string]string) (err error)
 		return
 	}
 
-	route, found := netAttachDef.Annotations[AnnForkliftNetworkRoute]
-	if found {
-		nse := k8snet.NetworkSelectionElement{
-			Namespace: key.Namespace,
-			Name:      key.Name,
-		}
+	route, hasRoute := netAttachDef.Annotations[AnnForkliftNetworkRoute]
+	ipPool, hasPool := netAttachDef.Annotations[AnnForkliftNetworkIPPool]
+	if !hasRoute && !hasPool {
+		annotations[AnnLegacyTransferNetwork] = path.Join(key.Namespace, key.Name)
+		return
+	}
+	nse := k8snet.NetworkSelectionElement{
+		Namespace: key.Namespace,
+		Name:      key.Name,
+	}
+	if hasRoute {
 		ip := net.ParseIP(route)
 		if ip != nil {
 			nse.GatewayRequest = []net.IP{ip}
@@ -2625,15 +3578,21 @@ func (r *KubeVirt) setTransferNetwork(annotations map[string]string) (err error)
 				"route", route)
 			return
 		}
-		transferNetwork, jErr := json.Marshal([]k8snet.NetworkSelectionElement{nse})
-		if jErr != nil {
-			err = liberr.Wrap(jErr)
+	}
+	if hasPool {
+		var address string
+		address, err = r.allocateTransferNetworkIP(key, ipPool)
+		if err != nil {
 			return
 		}
-		annotations[AnnTransferNetwork] = string(transferNetwork)
-	} else {
-		annotations[AnnLegacyTransferNetwork] = path.Join(key.Namespace, key.Name)
+		nse.IPRequest = []string{address}
 	}
+	transferNetwork, jErr := json.Marshal([]k8snet.NetworkSelectionElement{nse})
+	if jErr != nil {
+		err = liberr.Wrap(jErr)
+		return
+	}
+	annotations[AnnTransferNetwork] = string(transferNetwork)
 
 	return
 }
@@ -2711,6 +3670,61 @@ func (r *VirtualMachine) Conditions() (cnd *libcnd.Conditions) {
 }
 
 // Create an OwnerReference from a VM.
+// Add the cloud-init NoCloud volume (and disk) of the secret.
+func setCloudInit(spec *cnv.VirtualMachineSpec, secret *core.Secret) {
+	source := &cnv.CloudInitNoCloudSource{}
+	if _, found := secret.Data[CloudInitUserData]; found {
+		source.UserDataSecretRef = &core.LocalObjectReference{Name: secret.Name}
+	}
+	if _, found := secret.Data[CloudInitNetworkData]; found {
+		source.NetworkDataSecretRef = &core.LocalObjectReference{Name: secret.Name}
+	}
+	templateSpec := &spec.Template.Spec
+	templateSpec.Volumes = append(
+		templateSpec.Volumes,
+		cnv.Volume{
+			Name: CloudInitVolumeName,
+			VolumeSource: cnv.VolumeSource{
+				CloudInitNoCloud: source,
+			},
+		})
+	templateSpec.Domain.Devices.Disks = append(
+		templateSpec.Domain.Devices.Disks,
+		cnv.Disk{
+			Name: CloudInitVolumeName,
+			DiskDevice: cnv.DiskDevice{
+				Disk: &cnv.DiskTarget{
+					Bus: cnv.DiskBusVirtio,
+				},
+			},
+		})
+}
+
+// Add the CD-ROM of the ISO media data volume.
+func setCDROM(spec *cnv.VirtualMachineSpec, name string) {
+	templateSpec := &spec.Template.Spec
+	templateSpec.Volumes = append(
+		templateSpec.Volumes,
+		cnv.Volume{
+			Name: name,
+			VolumeSource: cnv.VolumeSource{
+				DataVolume: &cnv.DataVolumeSource{
+					Name: name,
+				},
+			},
+		})
+	templateSpec.Domain.Devices.Disks = append(
+		templateSpec.Domain.Devices.Disks,
+		cnv.Disk{
+			Name: name,
+			DiskDevice: cnv.DiskDevice{
+				CDRom: &cnv.CDRomTarget{
+					Bus: cnv.DiskBusSATA,
+				},
+			},
+		})
+}
+
 func vmOwnerReference(vm *cnv.VirtualMachine) (ref meta.OwnerReference) {
 	blockOwnerDeletion := true
 	isController := false
@@ -2731,6 +3745,7 @@ func (r *KubeVirt) setPopulatorPodLabels(pod core.Pod, migrationId string) (err
 		pod.Labels = make(map[string]string)
 	}
 	pod.Labels[kMigration] = migrationId
+	pod.Labels[kApp] = AppPopulator
 	patch := client.MergeFrom(podCopy)
 	err = r.Destination.Client.Patch(context.TODO(), &pod, patch)
 	return
@@ -2,15 +2,26 @@
 package plan
 
 import (
+	"context"
+	"strings"
+
+	k8snet "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
 	v1beta1 "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
 	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	adapter "github.com/kubev2v/forklift/pkg/controller/plan/adapter/base"
 	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
 	"github.com/kubev2v/forklift/pkg/lib/logging"
 	ginkgo "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	cnv "kubevirt.io/api/core/v1"
+	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -37,11 +48,584 @@ var _ = ginkgo.Describe("kubevirt tests", func() {
 		})
 	})
 
+	ginkgo.Describe("setPodSettings", func() {
+		podSpec := func() *v1.PodSpec {
+			return &v1.PodSpec{
+				Affinity: &v1.Affinity{PodAntiAffinity: &v1.PodAntiAffinity{}},
+				Containers: []v1.Container{
+					{
+						Resources: v1.ResourceRequirements{
+							Requests: v1.ResourceList{
+								v1.ResourceCPU:    resource.MustParse("1"),
+								v1.ResourceMemory: resource.MustParse("1Gi"),
+							},
+							Limits: v1.ResourceList{
+								v1.ResourceCPU:    resource.MustParse("4"),
+								v1.ResourceMemory: resource.MustParse("8Gi"),
+							},
+						},
+					},
+				},
+			}
+		}
+
+		ginkgo.It("should keep the defaults when not set", func() {
+			kubevirt := createKubeVirt()
+			kubevirt.Plan = &v1beta1.Plan{}
+			spec := podSpec()
+			kubevirt.setPodSettings(spec)
+			Expect(spec).To(Equal(podSpec()))
+		})
+
+		ginkgo.It("should apply the plan settings", func() {
+			kubevirt := createKubeVirt()
+			kubevirt.Plan = &v1beta1.Plan{}
+			kubevirt.Plan.Spec.ConversionPod = &planapi.PodSettings{
+				Resources: &v1.ResourceRequirements{
+					Requests: v1.ResourceList{
+						v1.ResourceCPU: resource.MustParse("8"),
+					},
+					Limits: v1.ResourceList{
+						v1.ResourceMemory: resource.MustParse("16Gi"),
+					},
+				},
+				NodeSelector: map[string]string{"node-role.kubernetes.io/migration": ""},
+				Tolerations: []v1.Toleration{
+					{Key: "migration", Operator: v1.TolerationOpExists, Effect: v1.TaintEffectNoSchedule},
+				},
+				Affinity: &v1.Affinity{NodeAffinity: &v1.NodeAffinity{}},
+			}
+			spec := podSpec()
+			kubevirt.setPodSettings(spec)
+			resources := spec.Containers[0].Resources
+			Expect(resources.Requests.Cpu().String()).To(Equal("8"))
+			Expect(resources.Requests.Memory().String()).To(Equal("1Gi"))
+			// the default limit is raised to the request.
+			Expect(resources.Limits.Cpu().String()).To(Equal("8"))
+			Expect(resources.Limits.Memory().String()).To(Equal("16Gi"))
+			Expect(spec.NodeSelector).To(HaveKey("node-role.kubernetes.io/migration"))
+			Expect(spec.Tolerations).To(HaveLen(1))
+			Expect(spec.Affinity.NodeAffinity).ToNot(BeNil())
+			Expect(spec.Affinity.PodAntiAffinity).To(BeNil())
+		})
+	})
+
+	ginkgo.Describe("setCPUModel", func() {
+		vmObject := func() *cnv.VirtualMachine {
+			return &cnv.VirtualMachine{
+				Spec: cnv.VirtualMachineSpec{
+					Template: &cnv.VirtualMachineInstanceTemplateSpec{
+						Spec: cnv.VirtualMachineInstanceSpec{
+							Domain: cnv.DomainSpec{
+								CPU: &cnv.CPU{
+									Model:    "Skylake-Client",
+									Features: []cnv.CPUFeature{{Name: "hle", Policy: "disable"}},
+								},
+							},
+						},
+					},
+				},
+			}
+		}
+
+		ginkgo.It("should keep the translated CPU model when not overridden", func() {
+			kubevirt := createKubeVirt()
+			kubevirt.Plan = &v1beta1.Plan{}
+			object := vmObject()
+			kubevirt.setCPUModel(&planapi.VMStatus{}, object)
+			Expect(object).To(Equal(vmObject()))
+		})
+
+		ginkgo.It("should override the CPU model and features", func() {
+			kubevirt := createKubeVirt()
+			kubevirt.Plan = &v1beta1.Plan{}
+			kubevirt.Plan.Spec.CPUModel = &planapi.CPUModel{Name: "Haswell-noTSX", Features: []string{"pcid"}}
+			object := vmObject()
+			kubevirt.setCPUModel(&planapi.VMStatus{}, object)
+			cpu := object.Spec.Template.Spec.Domain.CPU
+			Expect(cpu.Model).To(Equal("Haswell-noTSX"))
+			Expect(cpu.Features).To(Equal([]cnv.CPUFeature{{Name: "pcid", Policy: "require"}}))
+		})
+
+		ginkgo.It("should not override the CPU of VMs with an instance type", func() {
+			kubevirt := createKubeVirt()
+			kubevirt.Plan = &v1beta1.Plan{}
+			kubevirt.Plan.Spec.CPUModel = &planapi.CPUModel{Name: "Haswell-noTSX"}
+			object := vmObject()
+			vm := &planapi.VMStatus{}
+			vm.InstanceType = "u1.medium"
+			kubevirt.setCPUModel(vm, object)
+			Expect(object).To(Equal(vmObject()))
+		})
+	})
+
+	ginkgo.Describe("setSRIOVResources", func() {
+		nad := &k8snet.NetworkAttachmentDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "sriov",
+				Namespace:   "test",
+				Annotations: map[string]string{AnnResourceName: "openshift.io/sriov_nic"},
+			},
+		}
+		vmSpec := func() *cnv.VirtualMachineSpec {
+			return &cnv.VirtualMachineSpec{
+				Template: &cnv.VirtualMachineInstanceTemplateSpec{
+					Spec: cnv.VirtualMachineInstanceSpec{
+						Domain: cnv.DomainSpec{
+							Devices: cnv.Devices{
+								Interfaces: []cnv.Interface{
+									{Name: "net-0", InterfaceBindingMethod: cnv.InterfaceBindingMethod{SRIOV: &cnv.InterfaceSRIOV{}}},
+									{Name: "net-1", InterfaceBindingMethod: cnv.InterfaceBindingMethod{SRIOV: &cnv.InterfaceSRIOV{}}},
+									{Name: "net-2", InterfaceBindingMethod: cnv.InterfaceBindingMethod{Bridge: &cnv.InterfaceBridge{}}},
+								},
+							},
+						},
+						Networks: []cnv.Network{
+							{Name: "net-0", NetworkSource: cnv.NetworkSource{Multus: &cnv.MultusNetwork{NetworkName: "test/sriov"}}},
+							{Name: "net-1", NetworkSource: cnv.NetworkSource{Multus: &cnv.MultusNetwork{NetworkName: "test/sriov"}}},
+							{Name: "net-2", NetworkSource: cnv.NetworkSource{Multus: &cnv.MultusNetwork{NetworkName: "test/bridge"}}},
+						},
+					},
+				},
+			}
+		}
+
+		ginkgo.It("should request the resource annotated on the NAD", func() {
+			kubevirt := createKubeVirt(nad)
+			kubevirt.Plan = &v1beta1.Plan{}
+			spec := vmSpec()
+			Expect(kubevirt.setSRIOVResources(spec)).To(Succeed())
+			resources := spec.Template.Spec.Domain.Resources
+			Expect(resources.Requests).To(HaveLen(1))
+			Expect(resources.Requests.Name("openshift.io/sriov_nic", resource.DecimalSI).Value()).To(Equal(int64(2)))
+			Expect(resources.Limits.Name("openshift.io/sriov_nic", resource.DecimalSI).Value()).To(Equal(int64(2)))
+		})
+
+		ginkgo.It("should prefer the resource of the network map", func() {
+			kubevirt := createKubeVirt(nad)
+			kubevirt.Plan = &v1beta1.Plan{}
+			kubevirt.Map.Network = &v1beta1.NetworkMap{
+				Spec: v1beta1.NetworkMapSpec{
+					Map: []v1beta1.NetworkPair{
+						{Destination: v1beta1.DestinationNetwork{Type: "sriov", Namespace: "test", Name: "sriov", ResourceName: "intel.com/vf"}},
+					},
+				},
+			}
+			spec := vmSpec()
+			Expect(kubevirt.setSRIOVResources(spec)).To(Succeed())
+			Expect(spec.Template.Spec.Domain.Resources.Requests).To(HaveKey(v1.ResourceName("intel.com/vf")))
+			Expect(spec.Template.Spec.Domain.Resources.Requests).ToNot(HaveKey(v1.ResourceName("openshift.io/sriov_nic")))
+		})
+
+		ginkgo.It("should skip the NAD without resource", func() {
+			kubevirt := createKubeVirt()
+			kubevirt.Plan = &v1beta1.Plan{}
+			spec := vmSpec()
+			Expect(kubevirt.setSRIOVResources(spec)).To(Succeed())
+			Expect(spec.Template.Spec.Domain.Resources.Requests).To(BeEmpty())
+		})
+	})
+
+	ginkgo.Describe("setPlacement", func() {
+		vmSpec := func() *cnv.VirtualMachineSpec {
+			return &cnv.VirtualMachineSpec{
+				Template: &cnv.VirtualMachineInstanceTemplateSpec{
+					Spec: cnv.VirtualMachineInstanceSpec{
+						NodeSelector: map[string]string{"kubevirt.io/schedulable": "true"},
+						Affinity: &v1.Affinity{
+							PodAntiAffinity: &v1.PodAntiAffinity{
+								RequiredDuringSchedulingIgnoredDuringExecution: []v1.PodAffinityTerm{
+									{TopologyKey: "kubernetes.io/hostname"},
+								},
+							},
+						},
+					},
+				},
+			}
+		}
+
+		ginkgo.It("should merge the plan placement", func() {
+			kubevirt := createKubeVirt()
+			evictable := true
+			kubevirt.Plan = &v1beta1.Plan{}
+			kubevirt.Plan.Spec.TargetPlacement = &planapi.VMPlacement{
+				NodeSelector: map[string]string{"node-role.kubernetes.io/metal": ""},
+				Tolerations:  []v1.Toleration{{Key: "metal", Operator: v1.TolerationOpExists}},
+				Affinity: &v1.Affinity{
+					NodeAffinity: &v1.NodeAffinity{},
+					PodAntiAffinity: &v1.PodAntiAffinity{
+						RequiredDuringSchedulingIgnoredDuringExecution: []v1.PodAffinityTerm{
+							{TopologyKey: "topology.kubernetes.io/zone"},
+						},
+					},
+				},
+				Evictable: &evictable,
+			}
+			spec := vmSpec()
+			kubevirt.setPlacement(spec)
+			template := spec.Template
+			Expect(template.Spec.NodeSelector).To(HaveLen(2))
+			Expect(template.Spec.Tolerations).To(HaveLen(1))
+			Expect(template.Spec.Affinity.NodeAffinity).ToNot(BeNil())
+			Expect(template.Spec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution).To(HaveLen(2))
+			Expect(template.ObjectMeta.Annotations).To(HaveKeyWithValue(AnnDeschedulerEvict, "true"))
+		})
+
+		ginkgo.It("should not change the VM without plan placement", func() {
+			kubevirt := createKubeVirt()
+			kubevirt.Plan = &v1beta1.Plan{}
+			spec := vmSpec()
+			kubevirt.setPlacement(spec)
+			Expect(spec).To(Equal(vmSpec()))
+		})
+	})
+
+	ginkgo.Describe("EnsureNamespace", func() {
+		configMap := &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "template", Namespace: "plans"},
+			Data: map[string]string{
+				planapi.NamespaceTemplateLabels:        "team: infra\ntier: gold\n",
+				planapi.NamespaceTemplateResourceQuota: "hard:\n  pods: \"10\"\n",
+			},
+		}
+		plan := func() *v1beta1.Plan {
+			plan := &v1beta1.Plan{ObjectMeta: metav1.ObjectMeta{Name: "plan", Namespace: "plans"}}
+			plan.Spec.TargetNamespace = "target"
+			plan.Spec.TargetNamespaceTemplate = &planapi.NamespaceTemplate{
+				ConfigMap: &v1.LocalObjectReference{Name: "template"},
+				Labels:    map[string]string{"tier": "silver"},
+				LimitRange: &v1.LimitRangeSpec{
+					Limits: []v1.LimitRangeItem{{Type: v1.LimitTypeContainer}},
+				},
+				NetworkPolicy: &netv1.NetworkPolicySpec{
+					PolicyTypes: []netv1.PolicyType{netv1.PolicyTypeIngress},
+				},
+			}
+			return plan
+		}
+
+		ginkgo.It("should create the namespace from the template", func() {
+			kubevirt := createKubeVirt(configMap)
+			kubevirt.Plan = plan()
+			Expect(kubevirt.EnsureNamespace()).To(Succeed())
+			ns := &v1.Namespace{}
+			Expect(kubevirt.Destination.Client.Get(context.TODO(), client.ObjectKey{Name: "target"}, ns)).To(Succeed())
+			Expect(ns.Labels).To(Equal(map[string]string{"team": "infra", "tier": "silver"}))
+			Expect(ns.Annotations).To(HaveKeyWithValue(AnnNamespaceTemplate, "plans/plan"))
+			key := client.ObjectKey{Namespace: "target", Name: "plan"}
+			quota := &v1.ResourceQuota{}
+			Expect(kubevirt.Destination.Client.Get(context.TODO(), key, quota)).To(Succeed())
+			Expect(quota.Spec.Hard.Pods().Value()).To(Equal(int64(10)))
+			Expect(kubevirt.Destination.Client.Get(context.TODO(), key, &v1.LimitRange{})).To(Succeed())
+			Expect(kubevirt.Destination.Client.Get(context.TODO(), key, &netv1.NetworkPolicy{})).To(Succeed())
+			Expect(kubevirt.EnsureNamespace()).To(Succeed())
+		})
+
+		ginkgo.It("should not change the existing namespace", func() {
+			kubevirt := createKubeVirt(configMap, &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "target"}})
+			kubevirt.Plan = plan()
+			Expect(kubevirt.EnsureNamespace()).To(Succeed())
+			ns := &v1.Namespace{}
+			Expect(kubevirt.Destination.Client.Get(context.TODO(), client.ObjectKey{Name: "target"}, ns)).To(Succeed())
+			Expect(ns.Labels).To(BeEmpty())
+			quotas := &v1.ResourceQuotaList{}
+			Expect(kubevirt.Destination.Client.List(context.TODO(), quotas)).To(Succeed())
+			Expect(quotas.Items).To(BeEmpty())
+		})
+
+		ginkgo.It("should fail when the template config map is not found", func() {
+			kubevirt := createKubeVirt()
+			kubevirt.Plan = plan()
+			Expect(kubevirt.EnsureNamespace()).ToNot(Succeed())
+		})
+	})
+
+	ginkgo.Describe("ensureCloudInit", func() {
+		vm := &planapi.VMStatus{VM: planapi.VM{Ref: ref.Ref{ID: "vm-1"}}}
+		vmObject := func() *cnv.VirtualMachine {
+			return &cnv.VirtualMachine{
+				ObjectMeta: metav1.ObjectMeta{Name: "vm", Namespace: "test", UID: "vm-uid"},
+				Spec: cnv.VirtualMachineSpec{
+					Template: &cnv.VirtualMachineInstanceTemplateSpec{},
+				},
+			}
+		}
+		kubeVirt := func(cloudInit *adapter.CloudInit) *KubeVirt {
+			kubevirt := createKubeVirt()
+			kubevirt.Plan = &v1beta1.Plan{}
+			kubevirt.Plan.Spec.PreserveCloudInit = true
+			kubevirt.Builder = &cloudInitBuilder{cloudInit: cloudInit}
+			return kubevirt
+		}
+
+		ginkgo.It("should not carry over when not preserved", func() {
+			kubevirt := kubeVirt(&adapter.CloudInit{UserData: "#cloud-config"})
+			kubevirt.Plan.Spec.PreserveCloudInit = false
+			object := vmObject()
+			Expect(kubevirt.ensureCloudInit(vm, object)).To(Succeed())
+			Expect(object).To(Equal(vmObject()))
+		})
+
+		ginkgo.It("should not carry over without cloud-init", func() {
+			kubevirt := kubeVirt(nil)
+			object := vmObject()
+			Expect(kubevirt.ensureCloudInit(vm, object)).To(Succeed())
+			Expect(object).To(Equal(vmObject()))
+		})
+
+		ginkgo.It("should add the NoCloud volume of the secret", func() {
+			kubevirt := kubeVirt(&adapter.CloudInit{UserData: "#cloud-config", NetworkData: "version: 2"})
+			object := vmObject()
+			Expect(kubevirt.ensureCloudInit(vm, object)).To(Succeed())
+			secret := &v1.Secret{}
+			key := client.ObjectKey{Namespace: "test", Name: "vm" + CloudInitSecretSuffix}
+			Expect(kubevirt.Destination.Client.Get(context.TODO(), key, secret)).To(Succeed())
+			Expect(string(secret.Data[CloudInitUserData])).To(Equal("#cloud-config"))
+			Expect(string(secret.Data[CloudInitNetworkData])).To(Equal("version: 2"))
+			volumes := object.Spec.Template.Spec.Volumes
+			Expect(volumes).To(HaveLen(1))
+			Expect(volumes[0].CloudInitNoCloud.UserDataSecretRef.Name).To(Equal(key.Name))
+			Expect(volumes[0].CloudInitNoCloud.NetworkDataSecretRef.Name).To(Equal(key.Name))
+			Expect(object.Spec.Template.Spec.Domain.Devices.Disks).To(HaveLen(1))
+
+			// Updated on retry and owned by the VM.
+			kubevirt.Builder = &cloudInitBuilder{cloudInit: &adapter.CloudInit{UserData: "#cloud-config\n"}}
+			Expect(kubevirt.ensureCloudInit(vm, vmObject())).To(Succeed())
+			Expect(kubevirt.setCloudInitOwner(object)).To(Succeed())
+			Expect(kubevirt.Destination.Client.Get(context.TODO(), key, secret)).To(Succeed())
+			Expect(secret.Data).ToNot(HaveKey(CloudInitNetworkData))
+			Expect(secret.OwnerReferences).To(HaveLen(1))
+			Expect(secret.OwnerReferences[0].UID).To(Equal(object.UID))
+		})
+	})
+
+	ginkgo.Describe("setAttributes", func() {
+		vm := &planapi.VMStatus{VM: planapi.VM{Ref: ref.Ref{ID: "vm-1"}}}
+
+		ginkgo.It("should map the attributes to labels and annotations", func() {
+			kubevirt := createKubeVirt()
+			kubevirt.Plan = &v1beta1.Plan{}
+			kubevirt.Plan.Spec.AttributeMap = []planapi.AttributeMapping{
+				{Source: "CostCenter", Label: "costcenter"},
+				{Source: "Owner", Label: "owner", Annotation: "example.com/owner"},
+				{Source: "Notes", Annotation: "example.com/notes"},
+				{Source: "Missing", Label: "missing"},
+			}
+			kubevirt.Builder = &attributeBuilder{
+				attributes: map[string]string{
+					"CostCenter": "CC-1001",
+					"Owner":      "Jane Doe",
+					"Notes":      "Migrated from vSphere.",
+				},
+			}
+			object := &cnv.VirtualMachine{
+				Spec: cnv.VirtualMachineSpec{
+					Template: &cnv.VirtualMachineInstanceTemplateSpec{},
+				},
+			}
+			Expect(kubevirt.setAttributes(vm, object)).To(Succeed())
+			Expect(object.Labels).To(Equal(map[string]string{"costcenter": "CC-1001", "owner": "Jane-Doe"}))
+			Expect(object.Spec.Template.ObjectMeta.Labels).To(Equal(object.Labels))
+			Expect(object.Annotations).To(Equal(map[string]string{
+				"example.com/owner": "Jane Doe",
+				"example.com/notes": "Migrated from vSphere.",
+			}))
+		})
+	})
+	ginkgo.Describe("ensureCDROMs", func() {
+		vm := &planapi.VMStatus{VM: planapi.VM{Ref: ref.Ref{ID: "vm-1"}}}
+		vmObject := func() *cnv.VirtualMachine {
+			return &cnv.VirtualMachine{
+				ObjectMeta: metav1.ObjectMeta{Name: "vm", Namespace: "test", UID: "vm-uid"},
+				Spec: cnv.VirtualMachineSpec{
+					Template: &cnv.VirtualMachineInstanceTemplateSpec{},
+				},
+			}
+		}
+		kubeVirt := func(policy string) *KubeVirt {
+			kubevirt := createKubeVirt()
+			kubevirt.Plan = &v1beta1.Plan{}
+			kubevirt.Plan.Spec.CDROMs = &planapi.CDROMSettings{Policy: policy, StorageClass: "nfs"}
+			kubevirt.Source.Secret = &v1.Secret{
+				Data: map[string][]byte{
+					"user":     []byte("admin"),
+					"password": []byte("secret"),
+					"cacert":   []byte("ca"),
+				},
+			}
+			kubevirt.Builder = &cdromBuilder{
+				media: []adapter.CDROM{
+					{File: "[ds1] iso/rhel9.iso", URL: "https://vcenter/folder/iso/rhel9.iso?dcPath=dc&dsName=ds1"},
+				},
+			}
+			return kubevirt
+		}
+
+		ginkgo.It("should detach the CD-ROMs by default", func() {
+			kubevirt := kubeVirt(planapi.CDROMDetach)
+			object := vmObject()
+			Expect(kubevirt.ensureCDROMs(vm, object)).To(Succeed())
+			Expect(object).To(Equal(vmObject()))
+		})
+
+		ginkgo.It("should copy the ISO media", func() {
+			kubevirt := kubeVirt(planapi.CDROMCopy)
+			object := vmObject()
+			Expect(kubevirt.ensureCDROMs(vm, object)).To(Succeed())
+			dv := &cdi.DataVolume{}
+			key := client.ObjectKey{Namespace: "test", Name: "vm" + CDROMSuffix + "-0"}
+			Expect(kubevirt.Destination.Client.Get(context.TODO(), key, dv)).To(Succeed())
+			Expect(dv.Spec.Source.HTTP.URL).To(ContainSubstring("rhel9.iso"))
+			Expect(dv.Spec.Source.HTTP.SecretRef).To(Equal("vm" + CDROMSuffix))
+			Expect(dv.Spec.Source.HTTP.CertConfigMap).To(Equal("vm" + CDROMSuffix))
+			Expect(*dv.Spec.Storage.StorageClassName).To(Equal("nfs"))
+			Expect(dv.Spec.Storage.Resources.Requests.Storage().String()).To(Equal(DefaultCDROMSize))
+			secret := &v1.Secret{}
+			key.Name = "vm" + CDROMSuffix
+			Expect(kubevirt.Destination.Client.Get(context.TODO(), key, secret)).To(Succeed())
+			Expect(string(secret.Data[CDROMAccessKey])).To(Equal("admin"))
+			disks := object.Spec.Template.Spec.Domain.Devices.Disks
+			Expect(disks).To(HaveLen(1))
+			Expect(disks[0].CDRom).ToNot(BeNil())
+			Expect(object.Spec.Template.Spec.Volumes[0].DataVolume.Name).To(Equal(dv.Name))
+
+			// Retried and owned by the VM.
+			Expect(kubevirt.ensureCDROMs(vm, vmObject())).To(Succeed())
+			Expect(kubevirt.setCDROMOwner(object)).To(Succeed())
+			Expect(kubevirt.Destination.Client.Get(context.TODO(), key, secret)).To(Succeed())
+			Expect(secret.OwnerReferences).To(HaveLen(1))
+			key.Name = dv.Name
+			Expect(kubevirt.Destination.Client.Get(context.TODO(), key, dv)).To(Succeed())
+			Expect(dv.OwnerReferences[0].UID).To(Equal(object.UID))
+		})
+	})
+
+	ginkgo.Describe("setProvenance", func() {
+		vm := &planapi.VMStatus{VM: planapi.VM{Ref: ref.Ref{ID: "vm-1"}}}
+
+		ginkgo.It("should annotate the source VM provenance", func() {
+			kubevirt := createKubeVirt()
+			kubevirt.Builder = &provenanceBuilder{
+				provenance: &adapter.Provenance{
+					Description: strings.Repeat("x", DescriptionMaxLength+1),
+					Path:        "/Datacenter/vm/Finance/web",
+				},
+			}
+			object := &cnv.VirtualMachine{}
+			Expect(kubevirt.setProvenance(vm, object)).To(Succeed())
+			Expect(object.Annotations).To(HaveLen(2))
+			Expect(object.Annotations[AnnSourceDescription]).To(HaveLen(DescriptionMaxLength))
+			Expect(object.Annotations).To(HaveKeyWithValue(AnnSourcePath, "/Datacenter/vm/Finance/web"))
+		})
+
+		ginkgo.It("should not annotate without provenance", func() {
+			kubevirt := createKubeVirt()
+			kubevirt.Builder = &provenanceBuilder{}
+			object := &cnv.VirtualMachine{}
+			Expect(kubevirt.setProvenance(vm, object)).To(Succeed())
+			Expect(object.Annotations).To(BeNil())
+		})
+	})
+
+	ginkgo.Describe("setVolumeMetadata", func() {
+		data := &v1beta1.PVCNameTemplateData{VmName: "web", PlanName: "plan", DiskIndex: 1}
+
+		ginkgo.It("should render the plan PVC labels and annotations", func() {
+			kubevirt := createKubeVirt()
+			kubevirt.Plan = &v1beta1.Plan{}
+			kubevirt.Plan.Spec.PVCMetadata = &planapi.VolumeMetadata{
+				Labels:      map[string]string{"app": "{{.VmName}}", kVM: "other"},
+				Annotations: map[string]string{"example.com/disk": "{{.PlanName}}-{{.DiskIndex}}"},
+			}
+			object := &metav1.ObjectMeta{Labels: map[string]string{kVM: "vm-1"}}
+			Expect(kubevirt.setVolumeMetadata(object, data)).To(Succeed())
+			Expect(object.Labels).To(HaveKeyWithValue("app", "web"))
+			Expect(object.Labels).To(HaveKeyWithValue(kVM, "vm-1"))
+			Expect(object.Annotations).To(HaveKeyWithValue("example.com/disk", "plan-1"))
+		})
+
+		ginkgo.It("should fail on a template error", func() {
+			kubevirt := createKubeVirt()
+			kubevirt.Plan = &v1beta1.Plan{}
+			kubevirt.Plan.Spec.PVCMetadata = &planapi.VolumeMetadata{
+				Labels: map[string]string{"app": "{{.Undefined}}"},
+			}
+			Expect(kubevirt.setVolumeMetadata(&metav1.ObjectMeta{}, data)).ToNot(Succeed())
+		})
+	})
+
+	ginkgo.Describe("setVolumeName", func() {
+		vm := &planapi.VMStatus{}
+		data := &v1beta1.PVCNameTemplateData{VmName: "web", DiskIndex: 1}
+
+		ginkgo.It("should name the volume with the plan template", func() {
+			kubevirt := createKubeVirt()
+			kubevirt.Plan = &v1beta1.Plan{}
+			kubevirt.Plan.Spec.PVCNameTemplate = "{{.VmName}}-disk-{{.DiskIndex}}"
+			object := &metav1.ObjectMeta{GenerateName: "plan-vm-1-"}
+			kubevirt.setVolumeName(vm, object, data)
+			Expect(object.Name).To(Equal("web-disk-1"))
+			Expect(object.GenerateName).To(BeEmpty())
+		})
+
+		ginkgo.It("should keep the name on an invalid name", func() {
+			kubevirt := createKubeVirt()
+			kubevirt.Plan = &v1beta1.Plan{}
+			kubevirt.Plan.Spec.PVCNameTemplate = "{{.VmName}}_disk"
+			object := &metav1.ObjectMeta{GenerateName: "plan-vm-1-"}
+			kubevirt.setVolumeName(vm, object, data)
+			Expect(object.Name).To(BeEmpty())
+			Expect(object.GenerateName).To(Equal("plan-vm-1-"))
+		})
+	})
 })
 
+// Builder of the source VM ISO media.
+type cdromBuilder struct {
+	adapter.Builder
+	media []adapter.CDROM
+}
+
+func (r *cdromBuilder) CDROMs(ref.Ref) ([]adapter.CDROM, error) {
+	return r.media, nil
+}
+
+// Builder of the source VM provenance.
+type provenanceBuilder struct {
+	adapter.Builder
+	provenance *adapter.Provenance
+}
+
+func (r *provenanceBuilder) Provenance(ref.Ref) (*adapter.Provenance, error) {
+	return r.provenance, nil
+}
+
+// Builder of the source VM attributes.
+type attributeBuilder struct {
+	adapter.Builder
+	attributes map[string]string
+}
+
+func (r *attributeBuilder) Attributes(ref.Ref) (map[string]string, error) {
+	return r.attributes, nil
+}
+
+// Builder of the cloud-init data.
+type cloudInitBuilder struct {
+	adapter.Builder
+	cloudInit *adapter.CloudInit
+}
+
+func (r *cloudInitBuilder) CloudInit(ref.Ref) (*adapter.CloudInit, error) {
+	return r.cloudInit, nil
+}
+
 func createKubeVirt(objs ...runtime.Object) *KubeVirt {
 	scheme := runtime.NewScheme()
 	_ = v1.AddToScheme(scheme)
+	_ = k8snet.AddToScheme(scheme)
+	_ = netv1.AddToScheme(scheme)
+	_ = cdi.AddToScheme(scheme)
 	v1beta1.SchemeBuilder.AddToScheme(scheme)
 	client := fake.NewClientBuilder().
 		WithScheme(scheme).
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"path"
 	"regexp"
@@ -16,6 +17,7 @@ import (
 
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
 	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	basecontroller "github.com/kubev2v/forklift/pkg/controller/base"
 	"github.com/kubev2v/forklift/pkg/controller/plan/adapter"
 	"github.com/kubev2v/forklift/pkg/controller/plan/adapter/base"
 	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
@@ -25,7 +27,9 @@ import (
 
 	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
 	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	"github.com/kubev2v/forklift/pkg/lib/tracing"
 	"github.com/kubev2v/forklift/pkg/settings"
+	"go.opentelemetry.io/otel/attribute"
 	batchv1 "k8s.io/api/batch/v1"
 	core "k8s.io/api/core/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -38,14 +42,21 @@ import (
 
 // Requeue
 const (
-	NoReQ   = time.Duration(0)
-	PollReQ = time.Second * 3
+	NoReQ = time.Duration(0)
 )
 
 const (
 	TransferCompleted              = "Transfer completed."
 	PopulatorPodPrefix             = "populate-"
 	DvStatusCheckRetriesAnnotation = "dvStatusCheckRetries"
+	// Populator pod recreations, set on the PVC by the populator controller.
+	AnnPopulatorReCreations = "recreations"
+	// Populator pod recreations reported on the task.
+	AnnPopulatorRetries = "populatorRetries"
+	// Populator pod recreations before the transfer fails.
+	PopulatorMaxRetries = 3
+	// Cleanup attempts of a canceled VM migration before it is completed.
+	CancelCleanupAttempts = 20
 	// TODO: ImageConversion and DiskTransferV2v step names remain here
 	// until remaining cold/warm migration flow details can be
 	// moved into base migrators.
@@ -72,6 +83,18 @@ type Migration struct {
 	converter *adapter.Converter
 	// vm migrator
 	migrator migrator.Migrator
+	// disk transfer
+	transfer DiskTransfer
+	// consistent groups (by name).
+	consistent map[string]*consistentGroup
+}
+
+// Consistent group synchronization.
+type consistentGroup struct {
+	// All of the (active) VMs are paused.
+	paused bool
+	// Next precopy of the VMs.
+	nextPrecopyAt *meta.Time
 }
 
 // Type of migration.
@@ -86,7 +109,7 @@ func (r *Migration) Run() (reQ time.Duration, err error) {
 			r.provider.Close()
 		}
 	}()
-	reQ = PollReQ
+	reQ = basecontroller.ReQ(Settings.Requeue.Plan)
 	err = r.init()
 	if err != nil {
 		err = liberr.Wrap(err)
@@ -99,12 +122,15 @@ func (r *Migration) Run() (reQ time.Duration, err error) {
 	}
 
 	r.resolveCanceledRefs()
+	r.updateGroups()
+	r.syncGroups()
 
 	for _, vm := range r.runningVMs() {
-		err = r.execute(vm)
+		err = r.step(vm)
 		if err != nil {
 			return
 		}
+		r.updateRPO(vm)
 	}
 	for {
 		var hasNext bool
@@ -114,7 +140,7 @@ func (r *Migration) Run() (reQ time.Duration, err error) {
 			return
 		}
 		if hasNext {
-			err = r.execute(vm)
+			err = r.step(vm)
 			if err != nil {
 				return
 			}
@@ -123,6 +149,7 @@ func (r *Migration) Run() (reQ time.Duration, err error) {
 			break
 		}
 	}
+	r.updateGates()
 
 	completed, err := r.end()
 	if completed {
@@ -163,6 +190,10 @@ func (r *Migration) init() (err error) {
 	if err != nil {
 		return
 	}
+	r.transfer, err = selectDiskTransfer(r)
+	if err != nil {
+		return
+	}
 
 	return
 }
@@ -195,6 +226,10 @@ func (r *Migration) begin() (err error) {
 	}
 	//
 	// Delete
+	listed := map[string]bool{}
+	for _, vm := range r.Plan.Spec.VMs {
+		listed[vm.ID] = true
+	}
 	kept := []*plan.VMStatus{}
 	for _, status := range r.Plan.Status.Migration.VMs {
 
@@ -205,17 +240,24 @@ func (r *Migration) begin() (err error) {
 			return
 		}
 
-		if _, found := r.Plan.Spec.FindVM(status.Ref); found {
+		if listed[status.ID] {
 			kept = append(kept, status)
 		}
 	}
 	r.Plan.Status.Migration.VMs = kept
 	//
 	// Add/Update.
+	r.resolveRecopyRefs()
 	list := []*plan.VMStatus{}
 	for _, vm := range r.Plan.Spec.VMs {
 		status := r.migrator.Status(vm)
-		if status.Phase != api.PhaseCompleted || status.HasAnyCondition(api.ConditionCanceled, api.ConditionFailed) {
+		recopy := r.Migration.Spec.RecopiedPVCs(vm.Ref)
+		if len(recopy) > 0 && status.HasCondition(api.ConditionSucceeded) {
+			// Only the disks are copied again to the migrated VM.
+			status.DeleteCondition(api.ConditionSucceeded)
+			status.Recopy = &plan.Recopy{PVCs: recopy}
+		}
+		if status.Phase != api.PhaseCompleted || status.HasAnyCondition(api.ConditionCanceled, api.ConditionFailed) || len(recopy) > 0 {
 			pipeline, pErr := r.migrator.Pipeline(vm)
 			if pErr != nil {
 				err = liberr.Wrap(pErr)
@@ -270,6 +312,10 @@ func (r *Migration) Archive() {
 	}
 
 	for _, vm := range r.Plan.Status.Migration.VMs {
+		// Archived plans are not re-synchronized.
+		if vm.Warm != nil {
+			vm.Warm.Checkpoint = nil
+		}
 		dontFailOnError := func(err error) bool {
 			if err != nil {
 				r.Log.Error(liberr.Wrap(err),
@@ -324,35 +370,89 @@ func (r *Migration) Cancel() error {
 	if err := r.init(); err != nil {
 		return liberr.Wrap(err)
 	}
+	// The resources are labeled with the migration UID which
+	// is only known by the snapshot once the migration is deleted.
+	if r.Context.Migration.UID == "" {
+		r.Context.Migration.UID = r.Plan.Status.Migration.ActiveSnapshot().Migration.UID
+	}
 
 	for _, vm := range r.Plan.Status.Migration.VMs {
 		if vm.HasCondition(api.ConditionCanceled) && !vm.MarkedCompleted() {
-			dontFailOnError := func(err error) bool {
-				if err != nil {
-					r.Log.Error(liberr.Wrap(err),
-						"Couldn't clean up after canceled VM migration.",
-						"vm",
-						vm.String())
-				}
-				return false
-			}
-			_ = r.cleanup(vm, dontFailOnError)
-			if vm.RestorePowerState == plan.VMPowerStateOn {
-				if err := r.provider.PowerOn(vm.Ref); err != nil {
-					r.Log.Error(err,
-						"Couldn't restore the power state of the source VM.",
-						"vm",
-						vm.String())
-				}
-			}
-			vm.MarkCompleted()
-			markStartedStepsCompleted(vm)
+			r.cleanupCanceled(vm)
 		}
 	}
 
 	return nil
 }
 
+// Delete the resources of a canceled VM migration: the destination VM,
+// the partial DataVolumes and PVCs, the importer and conversion pods and
+// the source snapshot. The VM is marked completed once the cleanup has
+// succeeded or the attempts are exhausted. Otherwise, the cleanup is
+// retried on the next reconcile.
+func (r *Migration) cleanupCanceled(vm *plan.VMStatus) {
+	if vm.Cancellation == nil {
+		cnd := vm.FindCondition(api.ConditionCanceled)
+		markCanceled(vm, plan.CanceledBySystem, cnd.Reason, cnd.Message)
+	}
+	cancellation := vm.Cancellation
+	cancellation.CleanupAttempts++
+	// Canceled migrations are not re-synchronized.
+	if vm.Warm != nil {
+		vm.Warm.Checkpoint = nil
+	}
+	cancellation.CleanupErrors = nil
+	collectErrors := func(err error) bool {
+		if err != nil {
+			r.Log.Error(liberr.Wrap(err),
+				"Couldn't clean up after canceled VM migration.",
+				"vm",
+				vm.String())
+			cancellation.CleanupErrors = append(cancellation.CleanupErrors, err.Error())
+		}
+		return false
+	}
+	_ = r.cleanup(vm, collectErrors)
+	if !vm.HasCondition(api.ConditionSucceeded) {
+		collectErrors(r.kubevirt.DeletePopulatedPVCs(vm))
+	}
+	cancellation.CleanedUp = len(cancellation.CleanupErrors) == 0
+	if !cancellation.CleanedUp && cancellation.CleanupAttempts < CancelCleanupAttempts {
+		return
+	}
+	if vm.RestorePowerState == plan.VMPowerStateOn {
+		if err := r.provider.PowerOn(vm.Ref); err != nil {
+			r.Log.Error(err,
+				"Couldn't restore the power state of the source VM.",
+				"vm",
+				vm.String())
+		}
+	}
+	vm.MarkCompleted()
+	markStartedStepsCompleted(vm)
+}
+
+// Mark the VM migration canceled and record the
+// (machine-readable) reason and initiator.
+func markCanceled(vm *plan.VMStatus, initiator, reason, message string) {
+	vm.SetCondition(
+		libcnd.Condition{
+			Type:     api.ConditionCanceled,
+			Status:   True,
+			Category: api.CategoryAdvisory,
+			Reason:   reason,
+			Message:  message,
+			Durable:  true,
+		})
+	if vm.Cancellation == nil {
+		vm.Cancellation = &plan.Cancellation{
+			Initiator: initiator,
+			Reason:    reason,
+			Time:      meta.Now(),
+		}
+	}
+}
+
 // NextPhase transitions the VM to the next migration phase.
 // If this was the last phase in the current pipeline step, the pipeline step
 // is marked complete.
@@ -371,6 +471,7 @@ func (r *Migration) NextPhase(vm *plan.VMStatus) {
 		// looking for a following step.
 		currentStep.MarkCompleted()
 		currentStep.Phase = api.StepCompleted
+		r.traceStep(vm, currentStep)
 	default:
 		nextStep, found := vm.FindStep(r.migrator.Step(vm))
 		if !found {
@@ -380,10 +481,26 @@ func (r *Migration) NextPhase(vm *plan.VMStatus) {
 		if currentStep.Name != nextStep.Name {
 			currentStep.MarkCompleted()
 			currentStep.Phase = api.StepCompleted
+			r.traceStep(vm, currentStep)
 		}
 	}
 }
 
+// Record the completed pipeline step as a span of the migration trace.
+func (r *Migration) traceStep(vm *plan.VMStatus, step *plan.Step) {
+	if !step.MarkedStarted() || r.Migration == nil {
+		return
+	}
+	tracing.Record(
+		tracing.WithUID(context.TODO(), string(r.Migration.UID)),
+		step.Name,
+		step.Started.Time,
+		step.Completed.Time,
+		attribute.String("plan", path.Join(r.Plan.Namespace, r.Plan.Name)),
+		attribute.String("vm", vm.String()),
+		attribute.Bool("error", step.HasError()))
+}
+
 func markStartedStepsCompleted(vm *plan.VMStatus) {
 	for _, step := range vm.Pipeline {
 		if step.MarkedStarted() {
@@ -392,24 +509,25 @@ func markStartedStepsCompleted(vm *plan.VMStatus) {
 	}
 }
 
-func (r *Migration) deletePopulatorPVCs(vm *plan.VMStatus) (err error) {
-	if r.builder.SupportsVolumePopulators() {
-		err = r.kubevirt.DeletePopulatedPVCs(vm)
-	}
-	return
-}
-
 // Delete left over migration resources associated with a VM.
 func (r *Migration) cleanup(vm *plan.VMStatus, failOnErr func(error) bool) error {
 	if !vm.HasCondition(api.ConditionSucceeded) {
-		if err := r.kubevirt.DeleteVM(vm); failOnErr(err) {
-			return err
-		}
-		if err := r.deletePopulatorPVCs(vm); failOnErr(err) {
-			return err
-		}
-		if err := r.kubevirt.DeleteDataVolumes(vm); failOnErr(err) {
-			return err
+		if vm.Recopy != nil {
+			// The migrated VM and its volumes are retained, only
+			// the DataVolumes of the disks copied again are deleted.
+			if err := r.kubevirt.DeleteDataVolumes(vm); failOnErr(err) {
+				return err
+			}
+		} else {
+			if err := r.kubevirt.DeleteVM(vm); failOnErr(err) {
+				return err
+			}
+			// The volumes are retained to be re-synchronized.
+			if vm.Warm == nil || !vm.Warm.Resync() {
+				if err := r.transfer.DeleteVolumes(vm); failOnErr(err) {
+					return err
+				}
+			}
 		}
 	}
 	if err := r.deleteImporterPods(vm); failOnErr(err) {
@@ -442,12 +560,14 @@ func (r *Migration) cleanup(vm *plan.VMStatus, failOnErr func(error) bool) error
 		return err
 	}
 
-	r.removeLastWarmSnapshot(vm)
+	if err := r.removeLastWarmSnapshot(vm); failOnErr(err) {
+		return err
+	}
 
 	return nil
 }
 
-func (r *Migration) removeLastWarmSnapshot(vm *plan.VMStatus) {
+func (r *Migration) removeLastWarmSnapshot(vm *plan.VMStatus) (err error) {
 	if vm.Warm == nil {
 		return
 	}
@@ -456,12 +576,78 @@ func (r *Migration) removeLastWarmSnapshot(vm *plan.VMStatus) {
 		return
 	}
 	snapshot := vm.Warm.Precopies[n-1].Snapshot
-	if _, err := r.provider.RemoveSnapshot(vm.Ref, snapshot, r.kubevirt.loadHosts); err != nil {
+	if snapshot == "" {
+		return
+	}
+	if _, err = r.provider.RemoveSnapshot(vm.Ref, snapshot, r.kubevirt.loadHosts); err != nil {
 		r.Log.Error(
 			err,
 			"Failed to clean up warm migration snapshots.",
 			"vm", vm)
+		return
+	}
+	// Removed, not to be removed again by a retried cleanup
+	// or by the garbage collector.
+	vm.Warm.Precopies[n-1].Snapshot = ""
+	vm.UntrackSnapshot(snapshot)
+	return
+}
+
+// Remove the (tracked) snapshots created on the source.
+// The snapshots that could not be removed are retained with
+// the failed attempts and the error. The VM is flagged once
+// the attempts are exhausted. Returns the number removed.
+func (r *Migration) removeSnapshots(vm *plan.VMStatus) (removed int) {
+	kept := []plan.SourceSnapshot{}
+	exhausted := []string{}
+	for _, snapshot := range vm.Snapshots {
+		_, err := r.provider.RemoveSnapshot(vm.Ref, snapshot.ID, r.kubevirt.loadHosts)
+		if err != nil {
+			snapshot.Attempts++
+			snapshot.Error = err.Error()
+			kept = append(kept, snapshot)
+			if snapshot.Attempts >= Settings.SnapshotRemovalAttempts {
+				exhausted = append(exhausted, snapshot.ID)
+			}
+			r.Log.Error(
+				err,
+				"Failed to remove source snapshot.",
+				"vm",
+				vm.String(),
+				"snapshot",
+				snapshot.ID,
+				"attempts",
+				snapshot.Attempts)
+			continue
+		}
+		if vm.Warm != nil {
+			for i := range vm.Warm.Precopies {
+				if vm.Warm.Precopies[i].Snapshot == snapshot.ID {
+					vm.Warm.Precopies[i].Snapshot = ""
+				}
+			}
+		}
+		removed++
 	}
+	vm.Snapshots = nil
+	if len(kept) > 0 {
+		vm.Snapshots = kept
+	}
+	if len(exhausted) > 0 {
+		vm.SetCondition(
+			libcnd.Condition{
+				Type:     SnapshotsNotRemoved,
+				Status:   True,
+				Category: api.CategoryWarn,
+				Message: fmt.Sprintf(
+					"Snapshots created on the source could not be removed: %s",
+					strings.Join(exhausted, ", ")),
+				Durable: true,
+			})
+	} else {
+		vm.DeleteCondition(SnapshotsNotRemoved)
+	}
+	return
 }
 
 func (r *Migration) deleteImporterPods(vm *plan.VMStatus) (err error) {
@@ -579,6 +765,14 @@ func (r *Migration) deleteValidateVddkJob() (err error) {
 }
 
 // Best effort attempt to resolve canceled refs.
+func (r *Migration) resolveRecopyRefs() {
+	for i := range r.Context.Migration.Spec.Recopy {
+		// resolve the VM ref in place
+		ref := &r.Context.Migration.Spec.Recopy[i].VM
+		_, _ = r.Source.Inventory.VM(ref)
+	}
+}
+
 func (r *Migration) resolveCanceledRefs() {
 	for i := range r.Context.Migration.Spec.Cancel {
 		// resolve the VM ref in place
@@ -587,6 +781,191 @@ func (r *Migration) resolveCanceledRefs() {
 	}
 }
 
+// Cancel the VMs of groups with dependencies that did not
+// succeed and report the groups pending approval.
+func (r *Migration) updateGroups() {
+	groups := r.Context.Groups()
+	for _, vm := range r.Plan.Status.Migration.VMs {
+		if vm.MarkedStarted() || vm.HasCondition(api.ConditionCanceled) {
+			continue
+		}
+		dependency, blocked := groups.Blocked(vm.Group)
+		if !blocked {
+			continue
+		}
+		markCanceled(
+			vm,
+			plan.CanceledBySystem,
+			DependencyNotSucceeded,
+			fmt.Sprintf(
+				"The migration has been canceled: group %s did not succeed.",
+				dependency))
+		vm.Phase = api.PhaseCompleted
+		r.Log.Info(
+			"Migration [CANCELED]",
+			"vm",
+			vm.String(),
+			"group",
+			vm.Group,
+			"dependency",
+			dependency)
+	}
+	pending := groups.PendingApprovals()
+	if len(pending) > 0 {
+		r.Plan.Status.SetCondition(
+			libcnd.Condition{
+				Type:     GroupPendingApproval,
+				Status:   True,
+				Category: api.CategoryAdvisory,
+				Message:  "Migration groups pending approval (listed in the migration `approved`).",
+				Items:    pending,
+			})
+	} else {
+		r.Plan.Status.DeleteCondition(GroupPendingApproval)
+	}
+}
+
+// Synchronize the VMs of the consistent groups.
+// Built before the VMs are executed so that the paused VMs of
+// a group take the next precopy snapshot (or are cut over) together.
+// The groups with snapshots taken outside of the window are reported.
+func (r *Migration) syncGroups() {
+	r.consistent = make(map[string]*consistentGroup)
+	exceeded := make(map[string]bool)
+	if cnd := r.Plan.Status.FindCondition(ConsistencyWindowExceeded); cnd != nil {
+		for _, name := range cnd.Items {
+			exceeded[name] = true
+		}
+	}
+	for i := range r.Plan.Spec.Groups {
+		group := &r.Plan.Spec.Groups[i]
+		if !group.Consistent {
+			continue
+		}
+		sync := &consistentGroup{paused: true}
+		scheduled := true
+		var first, last *meta.Time
+		for _, vm := range r.Plan.Status.Migration.VMs {
+			if vm.Group != group.Name || vm.MarkedCompleted() || vm.HasCondition(api.ConditionCanceled) {
+				continue
+			}
+			if vm.Phase != api.PhaseCopyingPaused || vm.Warm == nil {
+				sync.paused = false
+				break
+			}
+			next := r.nextPrecopyAt(vm)
+			if next == nil {
+				scheduled = false
+			} else if sync.nextPrecopyAt == nil || next.After(sync.nextPrecopyAt.Time) {
+				sync.nextPrecopyAt = next
+			}
+			n := len(vm.Warm.Precopies)
+			if n == 0 || vm.Warm.Precopies[n-1].Start == nil {
+				continue
+			}
+			started := vm.Warm.Precopies[n-1].Start
+			if first == nil || started.Before(first) {
+				first = started
+			}
+			if last == nil || last.Before(started) {
+				last = started
+			}
+		}
+		if !scheduled {
+			sync.nextPrecopyAt = nil
+		}
+		if sync.paused && first != nil {
+			exceeded[group.Name] = last.Sub(first.Time) > group.ConsistencyWindow()
+		}
+		r.consistent[group.Name] = sync
+	}
+	items := []string{}
+	for i := range r.Plan.Spec.Groups {
+		name := r.Plan.Spec.Groups[i].Name
+		if exceeded[name] {
+			items = append(items, name)
+		}
+	}
+	if len(items) > 0 {
+		r.Plan.Status.SetCondition(
+			libcnd.Condition{
+				Type:     ConsistencyWindowExceeded,
+				Status:   True,
+				Category: api.CategoryWarn,
+				Message:  "The precopy snapshots of the consistent groups were not taken within the window.",
+				Items:    items,
+			})
+	} else {
+		r.Plan.Status.DeleteCondition(ConsistencyWindowExceeded)
+	}
+}
+
+// Determine whether the VM may pass the approval gate.
+// The VM waits (with the GatePendingApproval condition) until the gate
+// is approved in the migration. The VM migration fails when the
+// approval timeout has expired.
+func (r *Migration) gateApproved(vm *plan.VMStatus, gate string) (approved bool) {
+	gates := r.Plan.Spec.ApprovalGates
+	if gates == nil || !gates.Requires(gate) {
+		approved = true
+		return
+	}
+	approval, found := r.Context.Migration.Spec.GateApproval(gate)
+	if found {
+		vm.DeleteCondition(GatePendingApproval)
+		r.Log.Info(
+			"Approval gate passed.",
+			"vm",
+			vm.String(),
+			"gate",
+			gate,
+			"approvedBy",
+			approval.ApprovedBy)
+		approved = true
+		return
+	}
+	vm.SetCondition(
+		libcnd.Condition{
+			Type:     GatePendingApproval,
+			Status:   True,
+			Category: api.CategoryAdvisory,
+			Reason:   gate,
+			Message:  fmt.Sprintf("The %s phase is pending approval (listed in the migration `gateApprovals`).", gate),
+		})
+	if gates.Timeout > 0 {
+		waiting := vm.FindCondition(GatePendingApproval)
+		timeout := time.Duration(gates.Timeout) * time.Minute
+		if time.Since(waiting.LastTransitionTime.Time) > timeout {
+			vm.DeleteCondition(GatePendingApproval)
+			vm.AddError(fmt.Sprintf("The %s phase was not approved within %s.", gate, timeout))
+		}
+	}
+	return
+}
+
+// Update the plan condition listing the
+// VMs pending approval of a gate.
+func (r *Migration) updateGates() {
+	pending := []string{}
+	for _, vm := range r.Plan.Status.Migration.VMs {
+		if vm.HasCondition(GatePendingApproval) {
+			pending = append(pending, vm.String())
+		}
+	}
+	if len(pending) > 0 {
+		r.Plan.Status.SetCondition(
+			libcnd.Condition{
+				Type:     GatePendingApproval,
+				Status:   True,
+				Category: api.CategoryAdvisory,
+				Message:  "VMs pending approval of a gate (listed in the migration `gateApprovals`).",
+				Items:    pending,
+			})
+	} else {
+		r.Plan.Status.DeleteCondition(GatePendingApproval)
+	}
+}
+
 func (r *Migration) runningVMs() (vms []*plan.VMStatus) {
 	vms = make([]*plan.VMStatus, 0)
 	for i := range r.Plan.Status.Migration.VMs {
@@ -603,15 +982,7 @@ func (r *Migration) runningVMs() (vms []*plan.VMStatus) {
 func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 	// check whether the VM has been canceled by the user
 	if r.Context.Migration.Spec.Canceled(vm.Ref) {
-		vm.SetCondition(
-			libcnd.Condition{
-				Type:     api.ConditionCanceled,
-				Status:   True,
-				Category: api.CategoryAdvisory,
-				Reason:   UserRequested,
-				Message:  "The migration has been canceled.",
-				Durable:  true,
-			})
+		markCanceled(vm, plan.CanceledByUser, UserRequested, "The migration has been canceled.")
 		vm.Phase = api.PhaseCompleted
 		r.Log.Info(
 			"Migration [CANCELED]",
@@ -657,6 +1028,11 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 				err = nil
 				break
 			}
+			// The migrated VM keeps its name.
+			if vm.Recopy != nil {
+				r.NextPhase(vm)
+				break
+			}
 
 			// Check if user provided explicit a target virtual machine name
 			if vm.TargetName != "" {
@@ -706,6 +1082,19 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 			} else {
 				vm.Phase = api.PhaseCompleted
 			}
+		case api.PhaseRunTests:
+			runner := TestRunner{Context: r.Context, kubevirt: &r.kubevirt}
+			err = runner.Run(vm)
+			if err != nil {
+				return
+			}
+			if step, found := vm.FindStep(r.migrator.Step(vm)); found {
+				if step.MarkedCompleted() && step.Error == nil {
+					r.NextPhase(vm)
+				}
+			} else {
+				vm.Phase = api.PhaseCompleted
+			}
 		case api.PhaseCreateDataVolumes:
 			step, found := vm.FindStep(r.migrator.Step(vm))
 			if !found {
@@ -725,67 +1114,21 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 				}
 			}
 
-			if r.builder.SupportsVolumePopulators() {
-				var pvcs []*core.PersistentVolumeClaim
-				if pvcs, err = r.kubevirt.PopulatorVolumes(vm.Ref); err != nil {
-					if !errors.As(err, &web.ProviderNotReadyError{}) {
-						r.Log.Error(err, "error creating volumes", "vm", vm.Name)
-						step.AddError(err.Error())
-						err = nil
-						break
-					} else {
-						return
-					}
-				}
-				err = r.kubevirt.EnsurePopulatorVolumes(vm, pvcs)
-				if err != nil {
-					if !errors.As(err, &web.ProviderNotReadyError{}) {
-						step.AddError(err.Error())
-						err = nil
-						break
-					} else {
-						return
-					}
+			err = r.transfer.CreateVolumes(vm, ready)
+			if err != nil {
+				if !errors.As(err, &web.ProviderNotReadyError{}) {
+					r.Log.Error(err, "error creating volumes", "vm", vm.Name, "transfer", r.transfer.Name())
+					step.AddError(err.Error())
+					err = nil
+					break
+				} else {
+					return
 				}
 			}
-
 			if !ready {
 				r.Log.Info("PreTransferActions hook isn't ready yet")
 				return
 			}
-
-			if !r.builder.SupportsVolumePopulators() {
-				var dataVolumes []cdi.DataVolume
-				dataVolumes, err = r.kubevirt.DataVolumes(vm)
-				if err != nil {
-					if !errors.As(err, &web.ProviderNotReadyError{}) {
-						r.Log.Error(err, "error creating volumes", "vm", vm.Name)
-						step.AddError(err.Error())
-						err = nil
-						break
-					} else {
-						return
-					}
-				}
-				if vm.Warm != nil {
-					err = r.provider.SetCheckpoints(vm.Ref, vm.Warm.Precopies, dataVolumes, false, r.kubevirt.loadHosts)
-					if err != nil {
-						step.AddError(err.Error())
-						err = nil
-						break
-					}
-				}
-				err = r.kubevirt.EnsureDataVolumes(vm, dataVolumes)
-				if err != nil {
-					if !errors.As(err, &web.ProviderNotReadyError{}) {
-						step.AddError(err.Error())
-						err = nil
-						break
-					} else {
-						return
-					}
-				}
-			}
 			r.NextPhase(vm)
 		case api.PhaseCreateVM:
 			step, found := vm.FindStep(r.migrator.Step(vm))
@@ -846,25 +1189,22 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 			step.MarkStarted()
 			step.Phase = api.StepRunning
 
-			if r.builder.SupportsVolumePopulators() {
-				err = r.updatePopulatorCopyProgress(vm, step)
-			} else {
-				// Fallback to non-volume populator path
-				err = r.updateCopyProgress(vm, step)
-			}
+			err = r.transfer.UpdateProgress(vm, step)
 			if err != nil {
 				step.AddError(err.Error())
 				err = nil
 				break
 			}
 			if step.MarkedCompleted() && !step.HasError() {
-				if r.Plan.Spec.Warm {
+				if r.Plan.Spec.Warm && vm.Recopy == nil {
 					now := meta.Now()
-					next := meta.NewTime(now.Add(time.Duration(Settings.PrecopyInterval) * time.Minute))
+					next := meta.NewTime(now.Add(r.precopyInterval()))
 					n := len(vm.Warm.Precopies)
 					vm.Warm.Precopies[n-1].End = &now
+					vm.Warm.RecoveryPoint = vm.Warm.Precopies[n-1].Start
 					vm.Warm.NextPrecopyAt = &next
 					vm.Warm.Successes++
+					r.storeCheckpoint(vm)
 				}
 				r.NextPhase(vm)
 			}
@@ -920,9 +1260,26 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 				r.NextPhase(vm)
 			}
 		case api.PhaseCopyingPaused:
-			if r.Migration.Spec.Cutover != nil && !r.Migration.Spec.Cutover.After(time.Now()) {
-				vm.Phase = api.PhaseStorePowerState
-			} else if vm.Warm.NextPrecopyAt != nil && !vm.Warm.NextPrecopyAt.After(time.Now()) {
+			next := r.nextPrecopyAt(vm)
+			if group, consistent := r.consistent[vm.Group]; consistent {
+				if !group.paused {
+					// Wait for the other VMs of the group.
+					break
+				}
+				next = group.nextPrecopyAt
+			}
+			cutover := r.Migration.Spec.Cutover != nil && !r.Migration.Spec.Cutover.After(time.Now())
+			if cutover && r.gateApproved(vm, plan.GateCutover) {
+				if r.Plan.Spec.Replication != nil {
+					// Failover. The source VM is not powered off. The
+					// disks have been transferred up to the last precopy
+					// and its snapshot is removed once finalized.
+					vm.RestorePowerState = plan.VMPowerStateOn
+					vm.Phase = api.PhaseAddFinalCheckpoint
+				} else {
+					vm.Phase = api.PhaseStorePowerState
+				}
+			} else if next != nil && !next.After(time.Now()) {
 				r.NextPhase(vm)
 			}
 		case api.PhaseRemovePreviousSnapshot, api.PhaseRemovePenultimateSnapshot, api.PhaseRemoveFinalSnapshot:
@@ -955,6 +1312,7 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 				break
 			}
 			if ready {
+				vm.UntrackSnapshot(precopy.Snapshot)
 				r.NextPhase(vm)
 			}
 		case api.PhaseCreateInitialSnapshot, api.PhaseCreateSnapshot, api.PhaseCreateFinalSnapshot:
@@ -963,6 +1321,18 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 				vm.AddError(fmt.Sprintf("Step '%s' not found", r.migrator.Step(vm)))
 				break
 			}
+			// The source VM is powered off for the final snapshot.
+			if vm.Phase != api.PhaseCreateFinalSnapshot {
+				runner := HookRunner{Context: r.Context}
+				var done bool
+				done, err = runner.RunPreSnapshot(vm, step)
+				if err != nil {
+					return
+				}
+				if !done {
+					break
+				}
+			}
 			var snapshot, taskId string
 			if snapshot, taskId, err = r.provider.CreateSnapshot(vm.Ref, r.kubevirt.loadHosts); err != nil {
 				if errors.As(err, &web.ProviderNotReadyError{}) || errors.As(err, &web.ConflictError{}) {
@@ -974,7 +1344,8 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 			}
 			now := meta.Now()
 			precopy := plan.Precopy{Snapshot: snapshot, CreateTaskId: taskId, Start: &now}
-			vm.Warm.Precopies = append(vm.Warm.Precopies, precopy)
+			r.addPrecopy(vm, precopy)
+			vm.TrackSnapshot(snapshot)
 			r.resetPrecopyTasks(vm, step)
 			r.NextPhase(vm)
 		case api.PhaseWaitForInitialSnapshot, api.PhaseWaitForSnapshot, api.PhaseWaitForFinalSnapshot:
@@ -993,6 +1364,7 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 			if ready {
 				if snapshotId != "" {
 					vm.Warm.Precopies[len(vm.Warm.Precopies)-1].Snapshot = snapshotId
+					vm.TrackSnapshot(snapshotId)
 				}
 				r.NextPhase(vm)
 			}
@@ -1079,6 +1451,89 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 			case api.PhaseAddFinalCheckpoint:
 				vm.Phase = api.PhaseWaitForFinalDataVolumesStatus
 			}
+		case api.PhaseStopVM:
+			step, found := vm.FindStep(r.migrator.Step(vm))
+			if !found {
+				vm.AddError(fmt.Sprintf("Step '%s' not found", r.migrator.Step(vm)))
+				break
+			}
+			step.MarkStarted()
+			step.Phase = api.StepRunning
+			var stopped bool
+			stopped, err = r.kubevirt.StopVM(vm)
+			if err != nil {
+				step.AddError(err.Error())
+				err = nil
+				break
+			}
+			if !stopped {
+				r.Log.Info("Waiting for the target VM to stop.", "vm", vm.String())
+				return
+			}
+			r.NextPhase(vm)
+		case api.PhaseDeleteRecopiedVolumes:
+			step, found := vm.FindStep(r.migrator.Step(vm))
+			if !found {
+				vm.AddError(fmt.Sprintf("Step '%s' not found", r.migrator.Step(vm)))
+				break
+			}
+			if _, supported := r.transfer.(*DataVolumeTransfer); !supported {
+				step.AddError(fmt.Sprintf("Disk re-copy is not supported by the %s transfer.", r.transfer.Name()))
+				break
+			}
+			var deleted bool
+			deleted, err = r.kubevirt.DeleteRecopiedVolumes(vm)
+			if err != nil {
+				step.AddError(err.Error())
+				err = nil
+				break
+			}
+			if !deleted {
+				r.Log.Info("Waiting for the re-copied PVCs to be deleted.", "vm", vm.String())
+				return
+			}
+			r.NextPhase(vm)
+		case api.PhaseStartVM:
+			step, found := vm.FindStep(r.migrator.Step(vm))
+			if !found {
+				vm.AddError(fmt.Sprintf("Step '%s' not found", r.migrator.Step(vm)))
+				break
+			}
+			step.MarkStarted()
+			step.Phase = api.StepRunning
+			err = r.kubevirt.StartVM(vm)
+			if err != nil {
+				step.AddError(err.Error())
+				err = nil
+				break
+			}
+			err = r.kubevirt.DeleteDataVolumes(vm)
+			if err != nil {
+				step.AddError(err.Error())
+				err = nil
+				break
+			}
+			err = r.deleteImporterPods(vm)
+			if err != nil {
+				err = liberr.Wrap(err)
+				return
+			}
+			step.MarkCompleted()
+			step.Progress.Completed = step.Progress.Total
+			r.NextPhase(vm)
+		case api.PhaseResyncDataVolumes:
+			step, found := vm.FindStep(r.migrator.Step(vm))
+			if !found {
+				vm.AddError(fmt.Sprintf("Step '%s' not found", r.migrator.Step(vm)))
+				break
+			}
+			err = r.resyncDataVolumes(vm)
+			if err != nil {
+				step.AddError(err.Error())
+				err = nil
+				break
+			}
+			r.NextPhase(vm)
 		case api.PhaseStorePowerState:
 			step, found := vm.FindStep(r.migrator.Step(vm))
 			if !found {
@@ -1104,6 +1559,9 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 				vm.AddError(fmt.Sprintf("Step '%s' not found", r.migrator.Step(vm)))
 				break
 			}
+			if !r.gateApproved(vm, plan.GatePowerOff) {
+				break
+			}
 			err = r.provider.PowerOff(vm.Ref)
 			if err != nil {
 				if !errors.As(err, &web.ProviderNotReadyError{}) {
@@ -1141,7 +1599,7 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 				vm.AddError(fmt.Sprintf("Step '%s' not found", r.migrator.Step(vm)))
 				break
 			}
-			err = r.updateCopyProgress(vm, step)
+			err = r.transfer.UpdateProgress(vm, step)
 			if err != nil {
 				return
 			}
@@ -1158,6 +1616,15 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 			}
 			step.MarkStarted()
 			step.Phase = api.StepRunning
+			if r.Plan.Spec.Conversion.Delegated() {
+				if err = r.submitConversion(vm, step); err != nil {
+					step.AddError(err.Error())
+					err = nil
+					break
+				}
+				r.NextPhase(vm)
+				break
+			}
 			var ready bool
 			if ready, err = r.ensureGuestConversionPod(vm); err != nil {
 				step.AddError(err.Error())
@@ -1178,13 +1645,25 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 			step.MarkStarted()
 			step.Phase = api.StepRunning
 
+			if r.Plan.Spec.Conversion.Delegated() {
+				if err = r.updateDelegatedConversion(vm, step); err != nil {
+					step.AddError(err.Error())
+					err = nil
+					break
+				}
+				if step.MarkedCompleted() && !step.HasError() {
+					r.NextPhase(vm)
+				}
+				break
+			}
+
 			err = r.updateConversionProgress(vm, step)
 			if err != nil {
 				return
 			}
 
 			switch r.Source.Provider.Type() {
-			case api.Ova, api.VSphere:
+			case api.Ova, api.VSphere, api.EC2, api.Azure:
 				// fetch config from the conversion pod
 				pod, err := r.kubevirt.GetGuestConversionPod(vm)
 				if err != nil {
@@ -1260,6 +1739,7 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 			})
 
 	} else if vm.Error != nil {
+		r.markResync(vm)
 		vm.Phase = api.PhaseCompleted
 		vm.SetCondition(
 			libcnd.Condition{
@@ -1286,7 +1766,7 @@ func (r *Migration) hasPausedDv(dvs []ExtendedDataVolume) bool {
 func (r *Migration) resetPrecopyTasks(vm *plan.VMStatus, step *plan.Step) {
 	step.Completed = nil
 	for _, task := range step.Tasks {
-		task.Annotations["Precopy"] = fmt.Sprintf("%v", len(vm.Warm.Precopies))
+		task.Annotations["Precopy"] = fmt.Sprintf("%v", vm.Warm.Iterations())
 		task.MarkReset()
 		task.MarkStarted()
 	}
@@ -1307,10 +1787,17 @@ func (r *Migration) end() (completed bool, err error) {
 			succeeded++
 		}
 	}
+	err = r.recordVMs()
+	if err != nil {
+		return
+	}
 	go r.provider.Finalize(r.Plan.Status.Migration.VMs, r.Migration.Name)
 	r.Plan.Status.Migration.MarkCompleted()
 	snapshot := r.Plan.Status.Migration.ActiveSnapshot()
 	snapshot.DeleteCondition(Executing)
+	if succeeded > 0 {
+		r.triggerBackup(snapshot)
+	}
 
 	if failed > 0 {
 		// if any VMs failed, the migration failed.
@@ -1384,7 +1871,7 @@ func (r *Migration) ensureGuestConversionPod(vm *plan.VMStatus) (ready bool, err
 	switch r.Source.Provider.Type() {
 	case api.Ova:
 		ready, err = r.kubevirt.EnsureOVAVirtV2VPVCStatus(vm.ID)
-	case api.VSphere:
+	case api.VSphere, api.EC2, api.Azure:
 		ready = true
 	}
 
@@ -1570,6 +2057,67 @@ func (r *Migration) updateCopyProgress(vm *plan.VMStatus, step *plan.Step) (err
 	return
 }
 
+// Submit the guest conversion to the appliance.
+// The job ID is kept in the step annotations.
+func (r *Migration) submitConversion(vm *plan.VMStatus, step *plan.Step) (err error) {
+	if step.Annotations[ConversionJobAnnotation] != "" {
+		return
+	}
+	appliance, err := NewApplianceClient(r.Context)
+	if err != nil {
+		return
+	}
+	pvcs, err := r.kubevirt.getPVCs(vm.Ref)
+	if err != nil {
+		return
+	}
+	request := &ConversionRequest{
+		Plan:      path.Join(r.Plan.Namespace, r.Plan.Name),
+		VM:        vm.Ref,
+		Namespace: r.Plan.Spec.TargetNamespace,
+		PVCs:      []string{},
+	}
+	for _, pvc := range pvcs {
+		request.PVCs = append(request.PVCs, pvc.Name)
+	}
+	job, err := appliance.Submit(request)
+	if err != nil {
+		return
+	}
+	if step.Annotations == nil {
+		step.Annotations = map[string]string{}
+	}
+	step.Annotations[ConversionJobAnnotation] = job.ID
+	r.Log.Info(
+		"Guest conversion submitted to the appliance.",
+		"vm",
+		vm.String(),
+		"job",
+		job.ID)
+	return
+}
+
+// Update the guest conversion step from the appliance job.
+// The converted VM config is not reported by the appliance.
+func (r *Migration) updateDelegatedConversion(vm *plan.VMStatus, step *plan.Step) (err error) {
+	id := step.Annotations[ConversionJobAnnotation]
+	if id == "" {
+		step.MarkCompleted()
+		step.AddError("Guest conversion job not found")
+		return
+	}
+	appliance, err := NewApplianceClient(r.Context)
+	if err != nil {
+		return
+	}
+	job, err := appliance.Job(id)
+	if err != nil {
+		return
+	}
+	reflectConversionJob(vm, step, job)
+	return
+}
+
 // Wait for guest conversion to complete, and update the ImageConversion pipeline step.
 func (r *Migration) updateConversionProgress(vm *plan.VMStatus, step *plan.Step) error {
 	pod, err := r.kubevirt.GetGuestConversionPod(vm)
@@ -1586,6 +2134,10 @@ func (r *Migration) updateConversionProgress(vm *plan.VMStatus, step *plan.Step)
 	case core.PodSucceeded:
 		step.MarkCompleted()
 		step.Progress.Completed = step.Progress.Total
+		if vm.Conversion != nil {
+			vm.Conversion.Stage = plan.ConversionFinished
+			vm.Conversion.Percent = 100
+		}
 	case core.PodFailed:
 		step.MarkCompleted()
 		step.AddError("Guest conversion failed. See pod logs for details.")
@@ -1600,7 +2152,7 @@ func (r *Migration) updateConversionProgress(vm *plan.VMStatus, step *plan.Step)
 		case err != nil:
 			return liberr.Wrap(err)
 		case useV2vForTransfer:
-			if err := r.updateConversionProgressV2vMonitor(pod, step); err != nil {
+			if err := r.updateConversionProgressV2vMonitor(vm, pod, step); err != nil {
 				// Just log it. Missing progress is not fatal.
 				log.Error(err, "Failed to update conversion progress")
 			}
@@ -1610,9 +2162,9 @@ func (r *Migration) updateConversionProgress(vm *plan.VMStatus, step *plan.Step)
 	return nil
 }
 
-func (r *Migration) updateConversionProgressV2vMonitor(pod *core.Pod, step *plan.Step) (err error) {
+func (r *Migration) updateConversionProgressV2vMonitor(vm *plan.VMStatus, pod *core.Pod, step *plan.Step) (err error) {
 	var diskRegex = regexp.MustCompile(`v2v_disk_transfers\{disk_id="(\d+)"\} (\d{1,3}\.?\d*)`)
-	url := fmt.Sprintf("http://%s:2112/metrics", pod.Status.PodIP)
+	url := fmt.Sprintf("http://%s/metrics", net.JoinHostPort(pod.Status.PodIP, "2112"))
 	resp, err := http.Get(url)
 	switch {
 	case err == nil:
@@ -1627,6 +2179,7 @@ func (r *Migration) updateConversionProgressV2vMonitor(pod *core.Pod, step *plan
 	if err != nil {
 		return
 	}
+	updateConversionStage(vm, body)
 	matches := diskRegex.FindAllStringSubmatch(string(body), -1)
 	if matches == nil {
 		return
@@ -1663,27 +2216,272 @@ func (r *Migration) updateConversionProgressV2vMonitor(pod *core.Pod, step *plan
 	return
 }
 
+// Update the guest conversion stage and progress reported
+// by the virt-v2v-monitor metrics.
+func updateConversionStage(vm *plan.VMStatus, metrics []byte) {
+	var stageRegex = regexp.MustCompile(`v2v_conversion_stage\{stage="(\w+)"\} 1\b`)
+	var progressRegex = regexp.MustCompile(`v2v_conversion_progress (\d{1,3})`)
+	stage := stageRegex.FindSubmatch(metrics)
+	if stage == nil {
+		return
+	}
+	progress := progressRegex.FindSubmatch(metrics)
+	if vm.Conversion == nil {
+		vm.Conversion = &plan.ConversionProgress{}
+	}
+	percent := vm.Conversion.Percent
+	if progress != nil {
+		percent, _ = strconv.Atoi(string(progress[1]))
+		if percent > 100 {
+			percent = 100
+		}
+	}
+	if vm.Conversion.Stage != string(stage[1]) || vm.Conversion.Percent != percent {
+		vm.Conversion.Stage = string(stage[1])
+		vm.Conversion.Percent = percent
+		vm.Conversion.Updated = meta.Now()
+	}
+}
+
+// Add the precopy.
+// The precopies of a replication (scheduled indefinitely) are
+// pruned. Only the previous precopy is needed by the checkpoint.
+func (r *Migration) addPrecopy(vm *plan.VMStatus, precopy plan.Precopy) {
+	if r.Plan.Spec.Replication != nil {
+		vm.Warm.Prune()
+	}
+	vm.Warm.Precopies = append(vm.Warm.Precopies, precopy)
+}
+
+// Trim the DataVolume checkpoints.
+// The checkpoint added before the controller restarted (but the
+// phase was not persisted) is removed. The checkpoints of a
+// replication are trimmed to the last; the previous checkpoints
+// have been copied.
+func (r *Migration) trimCheckpoints(dv *cdi.DataVolume) {
+	checkpoints := dv.Spec.Checkpoints
+	if n := len(checkpoints); n > 1 && checkpoints[n-1] == checkpoints[n-2] {
+		checkpoints = checkpoints[:n-1]
+	}
+	if n := len(checkpoints); n > 1 && r.Plan.Spec.Replication != nil {
+		checkpoints = checkpoints[n-1:]
+	}
+	dv.Spec.Checkpoints = checkpoints
+}
+
 func (r *Migration) setDataVolumeCheckpoints(vm *plan.VMStatus) (err error) {
 	disks, err := r.kubevirt.getDVs(vm)
 	if err != nil {
 		return
 	}
+	final := vm.Phase == api.PhaseAddFinalCheckpoint
 	dvs := make([]cdi.DataVolume, 0)
 	for _, disk := range disks {
+		if coldCopy(disk.DataVolume) {
+			continue
+		}
 		dvs = append(dvs, *disk.DataVolume)
 	}
-	err = r.provider.SetCheckpoints(vm.Ref, vm.Warm.Precopies, dvs, vm.Phase == api.PhaseAddFinalCheckpoint, r.kubevirt.loadHosts)
-	if err != nil {
-		return
+	if final && r.Plan.Spec.Replication != nil {
+		// Failover. The checkpoint of the last precopy
+		// has been transferred and is marked final.
+		for i := range dvs {
+			dvs[i].Spec.FinalCheckpoint = true
+		}
+	} else {
+		err = r.provider.SetCheckpoints(vm.Ref, vm.Warm.Precopies, dvs, final, r.kubevirt.loadHosts)
+		if err != nil {
+			return
+		}
 	}
 	for i := range dvs {
+		r.trimCheckpoints(&dvs[i])
 		err = r.Destination.Client.Update(context.TODO(), &dvs[i])
 		if err != nil {
 			err = liberr.Wrap(err)
 			return
 		}
 	}
+	if final {
+		err = r.createColdCopyDataVolumes(vm)
+	}
+
+	return
+}
 
+// Create the DataVolumes of the disks excluded from snapshots.
+// Copied at cutover once the source VM is powered off.
+func (r *Migration) createColdCopyDataVolumes(vm *plan.VMStatus) (err error) {
+	dataVolumes, err := r.kubevirt.DataVolumes(vm)
+	if err != nil {
+		return
+	}
+	// The others have been created.
+	err = r.kubevirt.EnsureDataVolumes(vm, dataVolumes)
+	return
+}
+
+// The time of the next precopy. Scheduled when the last precopy
+// was transferred, otherwise the precopy interval after the end
+// of the last precopy. Not scheduled while the last precopy has
+// not ended.
+func (r *Migration) nextPrecopyAt(vm *plan.VMStatus) (next *meta.Time) {
+	if vm.Warm.NextPrecopyAt != nil {
+		next = vm.Warm.NextPrecopyAt
+		return
+	}
+	n := len(vm.Warm.Precopies)
+	if n == 0 || vm.Warm.Precopies[n-1].End == nil {
+		return
+	}
+	scheduled := meta.NewTime(vm.Warm.Precopies[n-1].End.Add(r.precopyInterval()))
+	vm.Warm.NextPrecopyAt = &scheduled
+	next = &scheduled
+	return
+}
+
+// Update the RPO of the VM while the disks are precopied.
+// The VM is reported when the RPO exceeds the threshold.
+func (r *Migration) updateRPO(vm *plan.VMStatus) {
+	if vm.Warm == nil {
+		return
+	}
+	switch vm.Phase {
+	case api.PhaseWaitForDataVolumesStatus,
+		api.PhaseCopyDisks,
+		api.PhaseCopyingPaused,
+		api.PhaseRemovePreviousSnapshot,
+		api.PhaseWaitForPreviousSnapshotRemoval,
+		api.PhaseCreateSnapshot,
+		api.PhaseWaitForSnapshot,
+		api.PhaseStoreSnapshotDeltas,
+		api.PhaseAddCheckpoint:
+	default:
+		vm.Warm.RPO = 0
+		vm.DeleteCondition(RPOThresholdExceeded)
+		return
+	}
+	if vm.Warm.RecoveryPoint == nil {
+		return
+	}
+	rpo := time.Since(vm.Warm.RecoveryPoint.Time)
+	vm.Warm.RPO = int64(rpo.Seconds())
+	threshold := r.Plan.Spec.Replication.RPOLimit(Settings.RPOThreshold)
+	if threshold > 0 && rpo > threshold {
+		vm.SetCondition(
+			libcnd.Condition{
+				Type:     RPOThresholdExceeded,
+				Status:   True,
+				Category: api.CategoryWarn,
+				Message:  fmt.Sprintf("The RPO exceeds the threshold (%s).", threshold),
+			})
+	} else {
+		vm.DeleteCondition(RPOThresholdExceeded)
+	}
+}
+
+// Interval between precopies.
+func (r *Migration) precopyInterval() time.Duration {
+	return r.Plan.Spec.Replication.PrecopyInterval(Settings.PrecopyInterval)
+}
+
+// Store the changed block tracking checkpoint.
+// The disks have been transferred up to the last precopy.
+func (r *Migration) storeCheckpoint(vm *plan.VMStatus) {
+	n := len(vm.Warm.Precopies)
+	if n == 0 {
+		return
+	}
+	precopy := vm.Warm.Precopies[n-1]
+	if len(precopy.Deltas) == 0 {
+		return
+	}
+	if vm.Warm.Checkpoint != nil && vm.Warm.Checkpoint.Snapshot == precopy.Snapshot {
+		return
+	}
+	vm.Warm.Checkpoint = &plan.DiskCheckpoint{
+		Snapshot: precopy.Snapshot,
+		Deltas:   precopy.Deltas,
+	}
+}
+
+// Mark the failed warm migration to be re-synchronized
+// from the checkpoint by the next migration. Only when the
+// cutover failed after the source was powered off and
+// before the disks were completed (or converted).
+func (r *Migration) markResync(vm *plan.VMStatus) {
+	if vm.Warm == nil || vm.Warm.Checkpoint == nil || r.Plan.Spec.Replication != nil {
+		return
+	}
+	switch vm.Phase {
+	case api.PhaseRemovePenultimateSnapshot,
+		api.PhaseWaitForPenultimateSnapshotRemoval,
+		api.PhaseCreateFinalSnapshot,
+		api.PhaseWaitForFinalSnapshot,
+		api.PhaseAddFinalCheckpoint,
+		api.PhaseWaitForFinalDataVolumesStatus,
+		api.PhaseFinalize:
+		vm.Warm.Checkpoint.Resync = true
+	default:
+		vm.Warm.Checkpoint.Resync = false
+	}
+	if vm.Warm.Checkpoint.Resync {
+		r.Log.Info(
+			"Cutover failed, the disks will be re-synchronized.",
+			"vm",
+			vm.String(),
+			"checkpoint",
+			vm.Warm.Checkpoint.Snapshot)
+	}
+}
+
+// Prepare the retained DataVolumes to be re-synchronized.
+// The checkpoints not transferred by the failed cutover are
+// dropped and the precopy (change IDs) of the checkpoint is
+// the previous checkpoint of the final snapshot.
+func (r *Migration) resyncDataVolumes(vm *plan.VMStatus) (err error) {
+	checkpoint := vm.Warm.Checkpoint
+	dvs, err := r.kubevirt.getDVs(vm)
+	if err != nil {
+		return
+	}
+	if len(dvs) == 0 {
+		err = liberr.New(
+			"DataVolumes to be re-synchronized not found.",
+			"vm",
+			vm.String())
+		return
+	}
+	for _, dv := range dvs {
+		// Copied again at the next cutover.
+		if coldCopy(dv.DataVolume) {
+			err = r.Destination.Client.Delete(context.TODO(), dv.DataVolume)
+			if err != nil && !k8serr.IsNotFound(err) {
+				err = liberr.Wrap(err)
+				return
+			}
+			err = nil
+			continue
+		}
+		checkpoints := []cdi.DataVolumeCheckpoint{}
+		for _, cp := range dv.Spec.Checkpoints {
+			checkpoints = append(checkpoints, cp)
+			if cp.Current == checkpoint.Snapshot {
+				break
+			}
+		}
+		dv.Spec.Checkpoints = checkpoints
+		dv.Spec.FinalCheckpoint = false
+		err = r.Destination.Client.Update(context.TODO(), dv.DataVolume)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+	// The snapshot has been removed.
+	vm.Warm.Precopies = []plan.Precopy{
+		{Deltas: checkpoint.Deltas},
+	}
 	return
 }
 
@@ -1710,6 +2508,15 @@ func (r *Migration) updatePopulatorCopyProgress(vm *plan.VMStatus, step *plan.St
 			continue
 		}
 
+		retries := 0
+		if recreations, found := pvc.Annotations[AnnPopulatorReCreations]; found {
+			retries, _ = strconv.Atoi(recreations)
+			if task.Annotations == nil {
+				task.Annotations = make(map[string]string)
+			}
+			task.Annotations[AnnPopulatorRetries] = recreations
+		}
+
 		if pvc.Status.Phase == core.ClaimBound {
 			task.Phase = api.StepCompleted
 			task.Reason = TransferCompleted
@@ -1723,14 +2530,25 @@ func (r *Migration) updatePopulatorCopyProgress(vm *plan.VMStatus, step *plan.St
 		if err != nil {
 			return
 		}
+		// The PVC of a warm migration is bound only once the populator
+		// has copied the final snapshot; each precopy is completed by
+		// the transfer of the volume.
+		if r.Plan.Spec.Warm && vm.Phase == api.PhaseCopyDisks {
+			requested := pvc.Spec.Resources.Requests[core.ResourceStorage]
+			if transferredBytes >= requested.Value() {
+				r.setTaskCompleted(task)
+				continue
+			}
+		}
 
 		percent := float64(transferredBytes/0x100000) / float64(task.Progress.Total)
 		newProgress := int64(percent * float64(task.Progress.Total))
 		if newProgress == task.Progress.Completed {
 			pvcId := string(pvc.UID)
 			populatorFailed := r.isPopulatorPodFailed(pvcId)
-			if populatorFailed {
-				return fmt.Errorf("populator pod failed for PVC %s. Please check the pod logs", pvcId)
+			// The populator controller recreates the failed pod until the retries are exhausted.
+			if populatorFailed && retries >= PopulatorMaxRetries {
+				return fmt.Errorf("populator pod failed for PVC %s after %d retries. Please check the pod logs", pvcId, retries)
 			}
 		}
 		task.Progress.Completed = newProgress
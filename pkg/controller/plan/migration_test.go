@@ -0,0 +1,276 @@
+package plan
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	"github.com/onsi/gomega"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+)
+
+func TestMarkCanceled(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	vm := &planapi.VMStatus{}
+	markCanceled(vm, planapi.CanceledByUser, UserRequested, "The migration has been canceled.")
+	g.Expect(vm.HasCondition(api.ConditionCanceled)).To(gomega.BeTrue())
+	g.Expect(vm.FindCondition(api.ConditionCanceled).Reason).To(gomega.Equal(UserRequested))
+	g.Expect(vm.Cancellation).ToNot(gomega.BeNil())
+	g.Expect(vm.Cancellation.Initiator).To(gomega.Equal(planapi.CanceledByUser))
+	g.Expect(vm.Cancellation.Reason).To(gomega.Equal(UserRequested))
+
+	// The first cancellation is recorded.
+	markCanceled(vm, planapi.CanceledBySystem, Deleted, "The migration has been deleted.")
+	g.Expect(vm.Cancellation.Initiator).To(gomega.Equal(planapi.CanceledByUser))
+	g.Expect(vm.Cancellation.Reason).To(gomega.Equal(UserRequested))
+}
+
+func TestUpdateConversionStage(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	vm := &planapi.VMStatus{}
+	updateConversionStage(vm, []byte("v2v_disk_transfers{disk_id=\"1\"} 0\n"))
+	g.Expect(vm.Conversion).To(gomega.BeNil())
+
+	metrics := "v2v_conversion_progress 10\n" +
+		"v2v_conversion_stage{stage=\"ConvertDrivers\"} 1\n" +
+		"v2v_conversion_stage{stage=\"Inspect\"} 0\n"
+	updateConversionStage(vm, []byte(metrics))
+	g.Expect(vm.Conversion).ToNot(gomega.BeNil())
+	g.Expect(vm.Conversion.Stage).To(gomega.Equal(planapi.ConversionConvertDrivers))
+	g.Expect(vm.Conversion.Percent).To(gomega.Equal(10))
+	g.Expect(vm.Conversion.Updated.IsZero()).To(gomega.BeFalse())
+}
+
+func TestResyncCheckpoint(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	runner := Migration{
+		Context: &plancontext.Context{Plan: &api.Plan{}, Log: log},
+	}
+	deltas := []planapi.DiskDelta{{Disk: "[ds] vm/vm.vmdk", DeltaID: "52 de-1/2"}}
+	vm := &planapi.VMStatus{
+		Phase: api.PhaseCopyingPaused,
+		Warm: &planapi.Warm{
+			Precopies: []planapi.Precopy{
+				{Snapshot: "snapshot-1", Deltas: deltas},
+			},
+		},
+	}
+
+	// Stored once the precopy has been transferred.
+	runner.storeCheckpoint(vm)
+	g.Expect(vm.Warm.Checkpoint).ToNot(gomega.BeNil())
+	g.Expect(vm.Warm.Checkpoint.Snapshot).To(gomega.Equal("snapshot-1"))
+	g.Expect(vm.Warm.Checkpoint.Deltas).To(gomega.Equal(deltas))
+
+	// Not stored without change IDs.
+	vm.Warm.Precopies = append(vm.Warm.Precopies, planapi.Precopy{Snapshot: "snapshot-2"})
+	runner.storeCheckpoint(vm)
+	g.Expect(vm.Warm.Checkpoint.Snapshot).To(gomega.Equal("snapshot-1"))
+
+	// Not re-synchronized when failed before the cutover.
+	runner.markResync(vm)
+	g.Expect(vm.Warm.Resync()).To(gomega.BeFalse())
+
+	// Re-synchronized when failed after the source was powered off.
+	vm.Phase = api.PhaseFinalize
+	runner.markResync(vm)
+	g.Expect(vm.Warm.Resync()).To(gomega.BeTrue())
+
+	// Not re-synchronized when failed once the disks are converted.
+	vm.Phase = api.PhaseConvertGuest
+	runner.markResync(vm)
+	g.Expect(vm.Warm.Resync()).To(gomega.BeFalse())
+}
+
+func TestNextPrecopyAt(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	interval := Settings.PrecopyInterval
+	defer func() {
+		Settings.PrecopyInterval = interval
+	}()
+	Settings.PrecopyInterval = 60
+
+	runner := Migration{
+		Context: &plancontext.Context{Plan: &api.Plan{}, Log: log},
+	}
+	vm := &planapi.VMStatus{
+		Warm: &planapi.Warm{
+			Precopies: []planapi.Precopy{{Snapshot: "snapshot-1"}},
+		},
+	}
+
+	// Not scheduled while the precopy has not ended.
+	g.Expect(runner.nextPrecopyAt(vm)).To(gomega.BeNil())
+
+	// Scheduled after the end of the last precopy.
+	end := meta.NewTime(time.Now().Add(-time.Hour * 2))
+	vm.Warm.Precopies[0].End = &end
+	next := runner.nextPrecopyAt(vm)
+	g.Expect(next).ToNot(gomega.BeNil())
+	g.Expect(next.Time).To(gomega.Equal(end.Add(time.Hour)))
+	g.Expect(vm.Warm.NextPrecopyAt).To(gomega.Equal(next))
+
+	// Scheduled (persisted) precopy.
+	scheduled := meta.NewTime(time.Now().Add(time.Minute))
+	vm.Warm.NextPrecopyAt = &scheduled
+	g.Expect(runner.nextPrecopyAt(vm)).To(gomega.Equal(&scheduled))
+
+	// Scheduled at the replication interval.
+	runner.Plan.Spec.Replication = &planapi.Replication{Interval: 5}
+	vm.Warm.NextPrecopyAt = nil
+	next = runner.nextPrecopyAt(vm)
+	g.Expect(next.Time).To(gomega.Equal(end.Add(time.Minute * 5)))
+}
+
+// The precopies and the DataVolume checkpoints of a
+// replication do not grow with the iterations.
+func TestReplicationPruned(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	runner := Migration{
+		Context: &plancontext.Context{Plan: &api.Plan{}, Log: log},
+	}
+	runner.Plan.Spec.Replication = &planapi.Replication{}
+	vm := &planapi.VMStatus{Warm: &planapi.Warm{}}
+	dv := &cdi.DataVolume{}
+	iterations := 1000
+	for i := 1; i <= iterations; i++ {
+		snapshot := fmt.Sprintf("snapshot-%d", i)
+		runner.addPrecopy(vm, planapi.Precopy{Snapshot: snapshot})
+		g.Expect(vm.Warm.Iterations()).To(gomega.Equal(i))
+		checkpoint := cdi.DataVolumeCheckpoint{Current: snapshot}
+		if n := len(vm.Warm.Precopies); n > 1 {
+			checkpoint.Previous = vm.Warm.Precopies[n-2].Snapshot
+		}
+		dv.Spec.Checkpoints = append(dv.Spec.Checkpoints, checkpoint)
+		runner.trimCheckpoints(dv)
+	}
+	g.Expect(vm.Warm.Precopies).To(gomega.HaveLen(2))
+	g.Expect(vm.Warm.Pruned).To(gomega.Equal(iterations - 2))
+	g.Expect(vm.Warm.Precopies[1].Snapshot).To(gomega.Equal("snapshot-1000"))
+	g.Expect(dv.Spec.Checkpoints).To(gomega.Equal(
+		[]cdi.DataVolumeCheckpoint{{Current: "snapshot-1000", Previous: "snapshot-999"}}))
+
+	// The checkpoint added again is removed.
+	dv.Spec.Checkpoints = append(dv.Spec.Checkpoints, dv.Spec.Checkpoints[0])
+	runner.trimCheckpoints(dv)
+	g.Expect(dv.Spec.Checkpoints).To(gomega.HaveLen(1))
+
+	// Not pruned by a (warm) migration.
+	runner.Plan.Spec.Replication = nil
+	vm = &planapi.VMStatus{Warm: &planapi.Warm{}}
+	dv = &cdi.DataVolume{}
+	for i := 1; i <= 3; i++ {
+		runner.addPrecopy(vm, planapi.Precopy{Snapshot: fmt.Sprintf("snapshot-%d", i)})
+		dv.Spec.Checkpoints = append(dv.Spec.Checkpoints, cdi.DataVolumeCheckpoint{Current: fmt.Sprintf("snapshot-%d", i)})
+		runner.trimCheckpoints(dv)
+	}
+	g.Expect(vm.Warm.Precopies).To(gomega.HaveLen(3))
+	g.Expect(vm.Warm.Iterations()).To(gomega.Equal(3))
+	g.Expect(dv.Spec.Checkpoints).To(gomega.HaveLen(3))
+}
+
+func TestSyncGroups(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	started := meta.NewTime(time.Now().Add(-time.Hour))
+	ended := meta.NewTime(time.Now().Add(-time.Minute))
+	scheduled := meta.NewTime(time.Now().Add(time.Minute))
+	paused := func(name string) *planapi.VMStatus {
+		vm := &planapi.VMStatus{
+			Phase: api.PhaseCopyingPaused,
+			Warm: &planapi.Warm{
+				Precopies: []planapi.Precopy{{Start: &started, End: &ended}},
+			},
+		}
+		vm.Name = name
+		vm.Group = "db"
+		return vm
+	}
+	app := paused("app")
+	db := paused("db")
+	db.Phase = api.PhaseCopyDisks
+	p := &api.Plan{}
+	p.Spec.Groups = []planapi.Group{{Name: "db", Consistent: true}}
+	p.Status.Migration.VMs = []*planapi.VMStatus{app, db}
+	runner := Migration{
+		Context: &plancontext.Context{Plan: p, Log: log},
+	}
+
+	// Not paused while a VM is copying.
+	runner.syncGroups()
+	g.Expect(runner.consistent["db"].paused).To(gomega.BeFalse())
+
+	// Paused, with the last scheduled precopy of the VMs.
+	db = paused("db")
+	db.Warm.NextPrecopyAt = &scheduled
+	p.Status.Migration.VMs[1] = db
+	runner.syncGroups()
+	g.Expect(runner.consistent["db"].paused).To(gomega.BeTrue())
+	g.Expect(runner.consistent["db"].nextPrecopyAt).To(gomega.Equal(&scheduled))
+	g.Expect(p.Status.HasCondition(ConsistencyWindowExceeded)).To(gomega.BeFalse())
+
+	// Snapshots taken outside of the window.
+	late := meta.NewTime(started.Add(time.Minute * 5))
+	db.Warm.Precopies[0].Start = &late
+	runner.syncGroups()
+	g.Expect(p.Status.FindCondition(ConsistencyWindowExceeded).Items).To(gomega.Equal([]string{"db"}))
+
+	// Reported until the snapshots are taken within the window.
+	db.Phase = api.PhaseCreateSnapshot
+	runner.syncGroups()
+	g.Expect(p.Status.HasCondition(ConsistencyWindowExceeded)).To(gomega.BeTrue())
+	db.Phase = api.PhaseCopyingPaused
+	db.Warm.Precopies[0].Start = &started
+	runner.syncGroups()
+	g.Expect(p.Status.HasCondition(ConsistencyWindowExceeded)).To(gomega.BeFalse())
+}
+
+func TestUpdateRPO(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	threshold := Settings.RPOThreshold
+	defer func() {
+		Settings.RPOThreshold = threshold
+	}()
+	Settings.RPOThreshold = 0
+
+	runner := Migration{
+		Context: &plancontext.Context{Plan: &api.Plan{}, Log: log},
+	}
+	recovered := meta.NewTime(time.Now().Add(-time.Minute * 30))
+	vm := &planapi.VMStatus{
+		Phase: api.PhaseCopyingPaused,
+		Warm:  &planapi.Warm{RecoveryPoint: &recovered},
+	}
+
+	// Not reported without a threshold.
+	runner.updateRPO(vm)
+	g.Expect(vm.Warm.RPO).To(gomega.BeNumerically("~", 1800, 5))
+	g.Expect(vm.HasCondition(RPOThresholdExceeded)).To(gomega.BeFalse())
+
+	// Reported above the (plan) threshold.
+	Settings.RPOThreshold = 60
+	runner.Plan.Spec.Replication = &planapi.Replication{RPOThreshold: 15}
+	runner.updateRPO(vm)
+	g.Expect(vm.HasCondition(RPOThresholdExceeded)).To(gomega.BeTrue())
+	runner.Plan.Spec.Replication = nil
+	runner.updateRPO(vm)
+	g.Expect(vm.HasCondition(RPOThresholdExceeded)).To(gomega.BeFalse())
+
+	// Not tracked once the precopies have ended.
+	runner.Plan.Spec.Replication = &planapi.Replication{RPOThreshold: 15}
+	runner.updateRPO(vm)
+	vm.Phase = api.PhaseCreateVM
+	runner.updateRPO(vm)
+	g.Expect(vm.Warm.RPO).To(gomega.BeZero())
+	g.Expect(vm.HasCondition(RPOThresholdExceeded)).To(gomega.BeFalse())
+}
@@ -15,6 +15,7 @@ var (
 	VirtV2vDiskCopy         libitr.Flag = 0x10
 	OpenstackImageMigration libitr.Flag = 0x20
 	VSphere                 libitr.Flag = 0x40
+	HasTests                libitr.Flag = 0x80
 )
 
 // Steps.
@@ -26,6 +27,7 @@ const (
 	ImageConversion = "ImageConversion"
 	DiskTransferV2v = "DiskTransferV2v"
 	VMCreation      = "VirtualMachineCreation"
+	VMUpdate        = "VirtualMachineUpdate"
 	Unknown         = "Unknown"
 )
 
@@ -47,6 +49,7 @@ var (
 			{Name: api.PhaseConvertOpenstackSnapshot, All: OpenstackImageMigration},
 			{Name: api.PhaseCreateVM},
 			{Name: api.PhasePostHook, All: HasPostHook},
+			{Name: api.PhaseRunTests, All: HasTests},
 			{Name: api.PhaseCompleted},
 		},
 	}
@@ -86,6 +89,85 @@ var (
 			{Name: api.PhaseConvertGuest, All: RequiresConversion},
 			{Name: api.PhaseCreateVM},
 			{Name: api.PhasePostHook, All: HasPostHook},
+			{Name: api.PhaseRunTests, All: HasTests},
+			{Name: api.PhaseCompleted},
+		},
+	}
+	// Continuous replication. The precopies continue until
+	// the failover (cutover) and the disks are restored as of
+	// the last precopy. The source VM is not powered off and
+	// the snapshot of the last precopy is removed on failover.
+	ReplicationItinerary = libitr.Itinerary{
+		Name: "Replication",
+		Pipeline: libitr.Pipeline{
+			{Name: api.PhaseStarted},
+			{Name: api.PhasePreHook, All: HasPreHook},
+			{Name: api.PhaseCreateInitialSnapshot},
+			{Name: api.PhaseWaitForInitialSnapshot},
+			{Name: api.PhaseStoreInitialSnapshotDeltas, All: VSphere},
+			{Name: api.PhaseCreateDataVolumes},
+			// Precopy loop start
+			{Name: api.PhaseWaitForDataVolumesStatus},
+			{Name: api.PhaseCopyDisks},
+			{Name: api.PhaseCopyingPaused},
+			{Name: api.PhaseRemovePreviousSnapshot, All: VSphere},
+			{Name: api.PhaseWaitForPreviousSnapshotRemoval, All: VSphere},
+			{Name: api.PhaseCreateSnapshot},
+			{Name: api.PhaseWaitForSnapshot},
+			{Name: api.PhaseStoreSnapshotDeltas, All: VSphere},
+			{Name: api.PhaseAddCheckpoint},
+			// Precopy loop end
+			{Name: api.PhaseAddFinalCheckpoint},
+			{Name: api.PhaseWaitForFinalDataVolumesStatus},
+			{Name: api.PhaseFinalize},
+			{Name: api.PhaseRemoveFinalSnapshot, All: VSphere},
+			{Name: api.PhaseWaitForFinalSnapshotRemoval, All: VSphere},
+			{Name: api.PhaseCreateGuestConversionPod, All: RequiresConversion},
+			{Name: api.PhaseConvertGuest, All: RequiresConversion},
+			{Name: api.PhaseCreateVM},
+			{Name: api.PhasePostHook, All: HasPostHook},
+			{Name: api.PhaseRunTests, All: HasTests},
+			{Name: api.PhaseCompleted},
+		},
+	}
+	// Re-synchronize the disks retained by a warm migration
+	// that failed after the source was powered off. Only the
+	// blocks changed since the checkpoint are transferred.
+	ResyncItinerary = libitr.Itinerary{
+		Name: "Resync",
+		Pipeline: libitr.Pipeline{
+			{Name: api.PhaseStarted},
+			{Name: api.PhasePreHook, All: HasPreHook},
+			{Name: api.PhaseResyncDataVolumes},
+			{Name: api.PhasePowerOffSource},
+			{Name: api.PhaseWaitForPowerOff},
+			{Name: api.PhaseCreateFinalSnapshot},
+			{Name: api.PhaseWaitForFinalSnapshot},
+			{Name: api.PhaseAddFinalCheckpoint},
+			{Name: api.PhaseWaitForFinalDataVolumesStatus},
+			{Name: api.PhaseFinalize},
+			{Name: api.PhaseRemoveFinalSnapshot, All: VSphere},
+			{Name: api.PhaseWaitForFinalSnapshotRemoval, All: VSphere},
+			{Name: api.PhaseCreateGuestConversionPod, All: RequiresConversion},
+			{Name: api.PhaseConvertGuest, All: RequiresConversion},
+			{Name: api.PhaseCreateVM},
+			{Name: api.PhasePostHook, All: HasPostHook},
+			{Name: api.PhaseRunTests, All: HasTests},
+			{Name: api.PhaseCompleted},
+		},
+	}
+	// Copy again disks of a migrated VM. The target VM is
+	// stopped while the PVCs are recreated and the disks
+	// transferred. The disks are not converted.
+	RecopyItinerary = libitr.Itinerary{
+		Name: "Recopy",
+		Pipeline: libitr.Pipeline{
+			{Name: api.PhaseStarted},
+			{Name: api.PhaseStopVM},
+			{Name: api.PhaseDeleteRecopiedVolumes},
+			{Name: api.PhaseCreateDataVolumes},
+			{Name: api.PhaseCopyDisks},
+			{Name: api.PhaseStartVM},
 			{Name: api.PhaseCompleted},
 		},
 	}
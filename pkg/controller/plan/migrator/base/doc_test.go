@@ -0,0 +1,65 @@
+package base
+
+import (
+	"testing"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	libitr "github.com/kubev2v/forklift/pkg/lib/itinerary"
+	"github.com/onsi/gomega"
+)
+
+// Predicate evaluating the flags set.
+type flags libitr.Flag
+
+func (r flags) Evaluate(flag libitr.Flag) (bool, error) {
+	return libitr.Flag(r)&flag != 0, nil
+}
+
+func (r flags) Count() int {
+	return 0x40
+}
+
+func TestReplicationFailover(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	itinerary := ReplicationItinerary
+	itinerary.Predicate = flags(VSphere | RequiresConversion)
+	list, err := itinerary.List()
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	for _, step := range list {
+		g.Expect(step.Name).ToNot(gomega.BeElementOf(
+			api.PhaseStorePowerState,
+			api.PhasePowerOffSource,
+			api.PhaseWaitForPowerOff,
+			api.PhaseCreateFinalSnapshot))
+	}
+
+	// The failover (cutover) continues at the final checkpoint.
+	phases := []string{}
+	phase := api.PhaseAddFinalCheckpoint
+	for {
+		next, done, err := itinerary.Next(phase)
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		if done {
+			break
+		}
+		phase = next.Name
+		phases = append(phases, phase)
+	}
+	g.Expect(phases).To(gomega.Equal([]string{
+		api.PhaseWaitForFinalDataVolumesStatus,
+		api.PhaseFinalize,
+		api.PhaseRemoveFinalSnapshot,
+		api.PhaseWaitForFinalSnapshotRemoval,
+		api.PhaseCreateGuestConversionPod,
+		api.PhaseConvertGuest,
+		api.PhaseCreateVM,
+		api.PhaseCompleted,
+	}))
+
+	// The snapshot is only removed on vSphere.
+	itinerary.Predicate = flags(0)
+	next, _, err := itinerary.Next(api.PhaseFinalize)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(next.Name).To(gomega.Equal(api.PhaseCreateVM))
+}
@@ -37,7 +37,7 @@ func (r *BaseMigrator) Cleanup(status *plan.VMStatus, successful bool) (err erro
 }
 
 func (r *BaseMigrator) Status(vm plan.VM) (status *plan.VMStatus) {
-	if current, found := r.Context.Plan.Status.Migration.FindVM(vm.Ref); !found {
+	if current, found := r.Context.FindVM(vm.Ref); !found {
 		status = &plan.VMStatus{VM: vm}
 		if r.Context.Plan.Spec.Warm {
 			status.Warm = &plan.Warm{}
@@ -56,8 +56,13 @@ func (r *BaseMigrator) Reset(status *plan.VMStatus, pipeline []*plan.Step) {
 	status.Phase = step.Name
 	status.Pipeline = pipeline
 	status.Error = nil
+	status.Cancellation = nil
 	if r.Context.Plan.Spec.Warm {
-		status.Warm = &plan.Warm{}
+		warm := &plan.Warm{}
+		if status.Warm != nil && status.Warm.Resync() {
+			warm.Checkpoint = status.Warm.Checkpoint
+		}
+		status.Warm = warm
 	}
 }
 
@@ -179,6 +184,17 @@ func (r *BaseMigrator) Pipeline(vm plan.VM) (pipeline []*plan.Step, err error) {
 						Phase:       api.StepPending,
 					},
 				})
+		case api.PhaseRunTests:
+			pipeline = append(
+				pipeline,
+				&plan.Step{
+					Task: plan.Task{
+						Name:        api.PhaseRunTests,
+						Description: "Run post-migration tests.",
+						Progress:    libitr.Progress{Total: 1},
+						Phase:       api.StepPending,
+					},
+				})
 		case api.PhaseCreateVM:
 			pipeline = append(
 				pipeline,
@@ -190,6 +206,17 @@ func (r *BaseMigrator) Pipeline(vm plan.VM) (pipeline []*plan.Step, err error) {
 						Progress:    libitr.Progress{Total: 1},
 					},
 				})
+		case api.PhaseStartVM:
+			pipeline = append(
+				pipeline,
+				&plan.Step{
+					Task: plan.Task{
+						Name:        VMUpdate,
+						Description: "Update the VM disks and start the VM.",
+						Phase:       api.StepPending,
+						Progress:    libitr.Progress{Total: 1},
+					},
+				})
 		}
 		next, done, _ := itinerary.Next(step.Name)
 		if !done {
@@ -211,11 +238,18 @@ func (r *BaseMigrator) Pipeline(vm plan.VM) (pipeline []*plan.Step, err error) {
 	return
 }
 
-func (r *BaseMigrator) Itinerary(predicate libitr.Predicate) (itinerary libitr.Itinerary) {
-	if r.Context.Plan.Spec.Warm {
-		itinerary = WarmItinerary
-	} else {
+func (r *BaseMigrator) Itinerary(predicate *BasePredicate) (itinerary libitr.Itinerary) {
+	switch {
+	case predicate.recopy():
+		itinerary = RecopyItinerary
+	case !r.Context.Plan.Spec.Warm:
 		itinerary = ColdItinerary
+	case predicate.resync():
+		itinerary = ResyncItinerary
+	case r.Context.Plan.Spec.Replication != nil:
+		itinerary = ReplicationItinerary
+	default:
+		itinerary = WarmItinerary
 	}
 	itinerary.Predicate = predicate
 	return
@@ -246,7 +280,8 @@ func (r *BaseMigrator) ExecutePhase(vm *plan.VMStatus) (ok bool, err error) {
 func (r *BaseMigrator) Step(status *plan.VMStatus) (step string) {
 	switch status.Phase {
 	case api.PhaseStarted, api.PhaseCreateInitialSnapshot, api.PhaseWaitForInitialSnapshot,
-		api.PhaseStoreInitialSnapshotDeltas, api.PhaseCreateDataVolumes:
+		api.PhaseStoreInitialSnapshotDeltas, api.PhaseCreateDataVolumes, api.PhaseStopVM,
+		api.PhaseDeleteRecopiedVolumes:
 		step = Initialize
 	case api.PhaseAllocateDisks:
 		step = DiskAllocation
@@ -254,7 +289,7 @@ func (r *BaseMigrator) Step(status *plan.VMStatus) (step string) {
 		api.PhaseCreateSnapshot, api.PhaseWaitForSnapshot, api.PhaseStoreSnapshotDeltas, api.PhaseAddCheckpoint,
 		api.PhaseConvertOpenstackSnapshot, api.PhaseWaitForDataVolumesStatus:
 		step = DiskTransfer
-	case api.PhaseRemovePenultimateSnapshot, api.PhaseWaitForPenultimateSnapshotRemoval, api.PhaseCreateFinalSnapshot,
+	case api.PhaseResyncDataVolumes, api.PhaseRemovePenultimateSnapshot, api.PhaseWaitForPenultimateSnapshotRemoval, api.PhaseCreateFinalSnapshot,
 		api.PhaseWaitForFinalSnapshot, api.PhaseAddFinalCheckpoint, api.PhaseFinalize, api.PhaseRemoveFinalSnapshot,
 		api.PhaseWaitForFinalSnapshotRemoval, api.PhaseWaitForFinalDataVolumesStatus:
 		step = Cutover
@@ -264,7 +299,9 @@ func (r *BaseMigrator) Step(status *plan.VMStatus) (step string) {
 		step = DiskTransferV2v
 	case api.PhaseCreateVM:
 		step = VMCreation
-	case api.PhasePreHook, api.PhasePostHook:
+	case api.PhaseStartVM:
+		step = VMUpdate
+	case api.PhasePreHook, api.PhasePostHook, api.PhaseRunTests:
 		step = status.Phase
 	case api.PhaseStorePowerState, api.PhasePowerOffSource, api.PhaseWaitForPowerOff:
 		if r.Context.Plan.Spec.Warm {
@@ -299,6 +336,8 @@ func (r *BasePredicate) Evaluate(flag libitr.Flag) (allowed bool, err error) {
 		_, allowed = r.vm.FindHook(api.PhasePreHook)
 	case HasPostHook:
 		_, allowed = r.vm.FindHook(api.PhasePostHook)
+	case HasTests:
+		allowed = r.context.Plan.Spec.TestSpec != nil
 	case RequiresConversion:
 		allowed = r.context.Source.Provider.RequiresConversion() && !r.context.Plan.Spec.SkipGuestConversion
 	case CDIDiskCopy:
@@ -314,6 +353,19 @@ func (r *BasePredicate) Evaluate(flag libitr.Flag) (allowed bool, err error) {
 	return
 }
 
+// The disks of the VM are re-synchronized
+// from the checkpoint (after a failed cutover).
+func (r *BasePredicate) resync() bool {
+	status, found := r.context.FindVM(r.vm.Ref)
+	return found && status.Warm != nil && status.Warm.Resync()
+}
+
+// Disks of the migrated VM are copied again.
+func (r *BasePredicate) recopy() bool {
+	status, found := r.context.FindVM(r.vm.Ref)
+	return found && status.Recopy != nil
+}
+
 func (r *BasePredicate) Count() int {
 	return 0x40
 }
@@ -0,0 +1,175 @@
+package plan
+
+import (
+	"context"
+	"path"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	core "k8s.io/api/core/v1"
+	net "k8s.io/api/networking/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// Namespace annotation of the plan (namespace/name) that created
+// the namespace from the namespace template.
+const AnnNamespaceTemplate = "forklift.konveyor.io/namespace-template"
+
+// Build the namespace template of the plan.
+// The template defined in the plan is completed by the referenced config map.
+func namespaceTemplate(plan *api.Plan, hostClient client.Client) (template *planapi.NamespaceTemplate, err error) {
+	if plan.Spec.TargetNamespaceTemplate == nil {
+		return
+	}
+	template = plan.Spec.TargetNamespaceTemplate.DeepCopy()
+	if template.ConfigMap == nil {
+		return
+	}
+	configMap := &core.ConfigMap{}
+	err = hostClient.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: plan.Namespace,
+			Name:      template.ConfigMap.Name,
+		},
+		configMap)
+	if err != nil {
+		err = liberr.Wrap(err, "configMap", template.ConfigMap.Name)
+		return
+	}
+	err = mergeNamespaceTemplate(template, configMap)
+	return
+}
+
+// Merge the namespace template defined by the config map into the template.
+// Labels and annotations are merged and the other fields are set only
+// when not already defined.
+func mergeNamespaceTemplate(template *planapi.NamespaceTemplate, configMap *core.ConfigMap) (err error) {
+	for key, merge := range map[string]func([]byte) error{
+		planapi.NamespaceTemplateLabels: func(data []byte) (err error) {
+			labels := map[string]string{}
+			err = yaml.Unmarshal(data, &labels)
+			if err != nil {
+				return
+			}
+			template.Labels = mergeStrings(labels, template.Labels)
+			return
+		},
+		planapi.NamespaceTemplateAnnotations: func(data []byte) (err error) {
+			annotations := map[string]string{}
+			err = yaml.Unmarshal(data, &annotations)
+			if err != nil {
+				return
+			}
+			template.Annotations = mergeStrings(annotations, template.Annotations)
+			return
+		},
+		planapi.NamespaceTemplateResourceQuota: func(data []byte) (err error) {
+			if template.ResourceQuota == nil {
+				template.ResourceQuota = &core.ResourceQuotaSpec{}
+				err = yaml.Unmarshal(data, template.ResourceQuota)
+			}
+			return
+		},
+		planapi.NamespaceTemplateLimitRange: func(data []byte) (err error) {
+			if template.LimitRange == nil {
+				template.LimitRange = &core.LimitRangeSpec{}
+				err = yaml.Unmarshal(data, template.LimitRange)
+			}
+			return
+		},
+		planapi.NamespaceTemplateNetworkPolicy: func(data []byte) (err error) {
+			if template.NetworkPolicy == nil {
+				template.NetworkPolicy = &net.NetworkPolicySpec{}
+				err = yaml.Unmarshal(data, template.NetworkPolicy)
+			}
+			return
+		},
+	} {
+		data, found := configMap.Data[key]
+		if !found {
+			continue
+		}
+		err = merge([]byte(data))
+		if err != nil {
+			err = liberr.Wrap(err, "configMap", configMap.Name, "key", key)
+			return
+		}
+	}
+	return
+}
+
+// Merge the maps. The values of the second map take precedence.
+func mergeStrings(in map[string]string, override map[string]string) (merged map[string]string) {
+	merged = map[string]string{}
+	for key, value := range in {
+		merged[key] = value
+	}
+	for key, value := range override {
+		merged[key] = value
+	}
+	return
+}
+
+// Ensure the namespace exists on the destination.
+// The namespace created by the plan is set up by the namespace template:
+// labeled, annotated and populated with the resource quota, limit range
+// and network policy. An existing namespace is not changed.
+func ensureNamespace(plan *api.Plan, hostClient client.Client, destClient client.Client) (err error) {
+	template, err := namespaceTemplate(plan, hostClient)
+	if err != nil {
+		return
+	}
+	owner := path.Join(plan.Namespace, plan.Name)
+	ns := &core.Namespace{
+		ObjectMeta: meta.ObjectMeta{
+			Name: plan.Spec.TargetNamespace,
+		},
+	}
+	if template != nil {
+		ns.Labels = template.Labels
+		ns.Annotations = mergeStrings(template.Annotations, map[string]string{AnnNamespaceTemplate: owner})
+	}
+	err = destClient.Create(context.TODO(), ns)
+	if err != nil {
+		if !k8serr.IsAlreadyExists(err) {
+			err = liberr.Wrap(err)
+			return
+		}
+		err = destClient.Get(context.TODO(), client.ObjectKey{Name: ns.Name}, ns)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+	if template == nil || ns.Annotations[AnnNamespaceTemplate] != owner {
+		return
+	}
+	var objects []client.Object
+	if template.ResourceQuota != nil {
+		objects = append(objects, &core.ResourceQuota{Spec: *template.ResourceQuota})
+	}
+	if template.LimitRange != nil {
+		objects = append(objects, &core.LimitRange{Spec: *template.LimitRange})
+	}
+	if template.NetworkPolicy != nil {
+		objects = append(objects, &net.NetworkPolicy{Spec: *template.NetworkPolicy})
+	}
+	for _, object := range objects {
+		object.SetNamespace(ns.Name)
+		object.SetName(plan.Name)
+		err = destClient.Create(context.TODO(), object)
+		if err != nil {
+			if !k8serr.IsAlreadyExists(err) {
+				err = liberr.Wrap(err)
+				return
+			}
+			err = nil
+		}
+	}
+	return
+}
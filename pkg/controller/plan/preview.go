@@ -0,0 +1,162 @@
+package plan
+
+import (
+	"fmt"
+	"path"
+	"sort"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	"github.com/kubev2v/forklift/pkg/controller/plan/adapter"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	core "k8s.io/api/core/v1"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
+	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Build the target specs of the VM migrated by the plan.
+// The plan need not exist (preview) but must reference the
+// providers and maps. Nothing is created: the secret and the
+// config map of the transfer are built in memory and the VM
+// uses the PVCs described by the DataVolumes, which are named
+// after the DataVolume (generated) name prefix.
+func TargetSpec(client client.Client, plan *api.Plan, vmRef ref.Ref) (spec *web.TargetSpec, err error) {
+	ctx, err := plancontext.New(
+		client,
+		plan,
+		log.WithValues(
+			"plan",
+			path.Join(plan.Namespace, plan.Name)))
+	if err != nil {
+		return
+	}
+	provider := ctx.Source.Provider
+	adapter, err := adapter.New(provider)
+	if err != nil {
+		return
+	}
+	builder, err := adapter.Builder(ctx)
+	if err != nil {
+		return
+	}
+	if builder.SupportsVolumePopulators() {
+		err = web.TargetSpecNotSupportedError{
+			Reason: fmt.Sprintf("the disks of %s VMs are populated.", provider.Type()),
+		}
+		return
+	}
+	kubevirt := &KubeVirt{
+		Context: ctx,
+		Builder: builder,
+	}
+	vm, err := kubevirt.previewVM(vmRef)
+	if err != nil {
+		return
+	}
+	dataVolumes, err := kubevirt.previewDataVolumes(vm)
+	if err != nil {
+		return
+	}
+	pvcs := []*core.PersistentVolumeClaim{}
+	for i := range dataVolumes {
+		dv := &dataVolumes[i]
+		dv.Name = fmt.Sprintf("%s%d", dv.GenerateName, i)
+		pvcs = append(pvcs, persistentVolumeClaim(dv))
+	}
+	sort.Slice(pvcs, func(i, j int) bool {
+		return getDiskIndex(pvcs[i]) < getDiskIndex(pvcs[j])
+	})
+	virtualMachine, err := kubevirt.buildVirtualMachine(vm, pvcs, false)
+	if err != nil {
+		return
+	}
+	virtualMachine.Namespace = plan.Spec.TargetNamespace
+	virtualMachine.Name = vm.Name
+	if vm.NewName != "" {
+		virtualMachine.Name = vm.NewName
+	}
+	spec = &web.TargetSpec{
+		VirtualMachine: virtualMachine,
+		DataVolumes:    dataVolumes,
+	}
+	return
+}
+
+// Size (bytes) of the VMs migrated by the plan.
+// The disks transferred as listed by the tasks of the
+// migration pipeline. The plan need not exist (preview).
+func VMSizes(client client.Client, plan *api.Plan, vms []ref.Ref) (sizes []int64, err error) {
+	ctx, err := plancontext.New(
+		client,
+		plan,
+		log.WithValues(
+			"plan",
+			path.Join(plan.Namespace, plan.Name)))
+	if err != nil {
+		return
+	}
+	adapter, err := adapter.New(ctx.Source.Provider)
+	if err != nil {
+		return
+	}
+	builder, err := adapter.Builder(ctx)
+	if err != nil {
+		return
+	}
+	for _, vmRef := range vms {
+		tasks, tErr := builder.Tasks(vmRef)
+		if tErr != nil {
+			err = tErr
+			return
+		}
+		size := int64(0)
+		for _, task := range tasks {
+			size += task.Progress.Total * 0x100000
+		}
+		sizes = append(sizes, size)
+	}
+	return
+}
+
+// Build the status of the VM as it would be
+// started by the migration.
+func (r *KubeVirt) previewVM(vmRef ref.Ref) (vm *plan.VMStatus, err error) {
+	vm = &plan.VMStatus{
+		VM: plan.VM{Ref: vmRef},
+	}
+	_, err = r.Source.Inventory.VM(&vm.Ref)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	if errs := k8svalidation.IsDNS1123Subdomain(vm.Name); len(errs) > 0 {
+		vm.NewName = changeVmName(vm.Name)
+	}
+	return
+}
+
+// Build the DataVolumes of the VM disks.
+func (r *KubeVirt) previewDataVolumes(vm *plan.VMStatus) (dataVolumes []cdi.DataVolume, err error) {
+	labels := r.vmLabels(vm.Ref)
+	secret, err := r.secret(vm.Ref, r.secretDataSetterForCDI(vm.Ref), labels)
+	if err != nil {
+		return
+	}
+	configMap, err := r.configMap(vm.Ref)
+	if err != nil {
+		return
+	}
+	var vddkConfigMap *core.ConfigMap
+	if r.Source.Provider.UseVddkAioOptimization() {
+		vddkConfigMap, err = r.vddkConfigMap(r.vddkLabels())
+		if err != nil {
+			return
+		}
+	}
+	dataVolumes, err = r.dataVolumes(vm, secret, configMap, vddkConfigMap)
+	return
+}
@@ -0,0 +1,243 @@
+package plan
+
+import (
+	"context"
+	"path"
+
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	cnv "kubevirt.io/api/core/v1"
+	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Get the VM created on the destination by a previous migration.
+func (r *KubeVirt) targetVM(vm *plan.VMStatus) (object *cnv.VirtualMachine, err error) {
+	list := &cnv.VirtualMachineList{}
+	err = r.Destination.Client.List(
+		context.TODO(),
+		list,
+		&client.ListOptions{
+			LabelSelector: k8slabels.SelectorFromSet(r.vmAllButMigrationLabels(vm.Ref)),
+			Namespace:     r.Plan.Spec.TargetNamespace,
+		},
+	)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if len(list.Items) == 0 {
+		err = liberr.New("Target VM not found.", "vm", vm.String())
+		return
+	}
+	object = &list.Items[0]
+	return
+}
+
+// Stop the target VM so the PVCs can be recreated.
+// The run strategy is kept to be restored once the
+// disks are copied. Stopped once the VMI is gone.
+func (r *KubeVirt) StopVM(vm *plan.VMStatus) (stopped bool, err error) {
+	object, err := r.targetVM(vm)
+	if err != nil {
+		return
+	}
+	halted := cnv.RunStrategyHalted
+	if vm.Recopy.RunStrategy == "" {
+		vm.Recopy.RunStrategy = string(halted)
+		if object.Spec.RunStrategy != nil {
+			vm.Recopy.RunStrategy = string(*object.Spec.RunStrategy)
+		} else if object.Spec.Running != nil && *object.Spec.Running {
+			vm.Recopy.RunStrategy = string(cnv.RunStrategyAlways)
+		}
+	}
+	if object.Spec.RunStrategy == nil || *object.Spec.RunStrategy != halted {
+		patch := client.MergeFrom(object.DeepCopy())
+		object.Spec.RunStrategy = &halted
+		object.Spec.Running = nil
+		err = r.Destination.Client.Patch(context.TODO(), object, patch)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		r.Log.Info(
+			"Stopped Kubevirt VM.",
+			"vm",
+			path.Join(
+				object.Namespace,
+				object.Name),
+			"source",
+			vm.String())
+	}
+	vmi := &cnv.VirtualMachineInstance{}
+	err = r.Destination.Client.Get(context.TODO(), client.ObjectKeyFromObject(object), vmi)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			err = nil
+			stopped = true
+		} else {
+			err = liberr.Wrap(err)
+		}
+	}
+	return
+}
+
+// Delete the PVCs (and DataVolumes) of the re-copied disks.
+// The disk of each PVC is resolved before it is deleted.
+// Deleted once the PVCs are gone.
+func (r *KubeVirt) DeleteRecopiedVolumes(vm *plan.VMStatus) (deleted bool, err error) {
+	recopy := vm.Recopy
+	if recopy.Disks == nil {
+		recopy.Disks = map[string]string{}
+	}
+	deleted = true
+	for _, name := range recopy.PVCs {
+		key := client.ObjectKey{
+			Namespace: r.Plan.Spec.TargetNamespace,
+			Name:      name,
+		}
+		pvc := &core.PersistentVolumeClaim{}
+		err = r.Destination.Client.Get(context.TODO(), key, pvc)
+		if err != nil {
+			if k8serr.IsNotFound(err) {
+				err = nil
+				if _, resolved := recopy.Disks[name]; !resolved {
+					err = liberr.New("PVC not found.", "pvc", key.String())
+					return
+				}
+				continue
+			}
+			err = liberr.Wrap(err)
+			return
+		}
+		if pvc.Labels[kVM] != vm.ID {
+			err = liberr.New("PVC not owned by the VM.", "pvc", key.String(), "vm", vm.String())
+			return
+		}
+		deleted = false
+		if _, resolved := recopy.Disks[name]; !resolved {
+			id := r.Builder.ResolvePersistentVolumeClaimIdentifier(pvc)
+			if id == "" {
+				err = liberr.New("Disk of the PVC not resolved.", "pvc", key.String())
+				return
+			}
+			recopy.Disks[name] = id
+		}
+		if pvc.DeletionTimestamp != nil {
+			continue
+		}
+		dv := &cdi.DataVolume{ObjectMeta: meta.ObjectMeta{Namespace: key.Namespace, Name: key.Name}}
+		err = r.Destination.Client.Delete(context.TODO(), dv)
+		if err != nil && !k8serr.IsNotFound(err) {
+			err = liberr.Wrap(err)
+			return
+		}
+		err = r.DeleteObject(pvc, vm, "Deleted re-copied PVC.", "pvc")
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Keep the DataVolumes of the re-copied disks.
+func (r *KubeVirt) recopiedDataVolumes(vm *plan.VMStatus, dataVolumes []cdi.DataVolume) (recopied []cdi.DataVolume) {
+	for _, dv := range dataVolumes {
+		if vm.Recopy.HasDisk(r.Builder.ResolveDataVolumeIdentifier(&dv)) {
+			recopied = append(recopied, dv)
+		} else {
+			r.releaseTransferNetworkIPs(dv.Annotations)
+		}
+	}
+	return
+}
+
+// Attach the recreated PVCs to the target VM and restore
+// its run strategy.
+func (r *KubeVirt) StartVM(vm *plan.VMStatus) (err error) {
+	object, err := r.targetVM(vm)
+	if err != nil {
+		return
+	}
+	pvcs, err := r.getPVCs(vm.Ref)
+	if err != nil {
+		return
+	}
+	claims := map[string]string{}
+	for _, pvc := range pvcs {
+		id := r.Builder.ResolvePersistentVolumeClaimIdentifier(pvc)
+		for name, disk := range vm.Recopy.Disks {
+			if disk == id {
+				claims[name] = pvc.Name
+			}
+		}
+	}
+	for _, name := range vm.Recopy.PVCs {
+		if _, found := claims[name]; !found {
+			err = liberr.New("Recreated PVC not found.", "pvc", name, "vm", vm.String())
+			return
+		}
+	}
+	for _, pvc := range pvcs {
+		pvcCopy := pvc.DeepCopy()
+		pvc.OwnerReferences = []meta.OwnerReference{vmOwnerReference(object)}
+		err = r.Destination.Client.Patch(context.TODO(), pvc, client.MergeFrom(pvcCopy))
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+	patch := client.MergeFrom(object.DeepCopy())
+	replaceClaims(object, claims)
+	runStrategy := cnv.VirtualMachineRunStrategy(vm.Recopy.RunStrategy)
+	if runStrategy == "" {
+		runStrategy = cnv.RunStrategyHalted
+	}
+	object.Spec.RunStrategy = &runStrategy
+	err = r.Destination.Client.Patch(context.TODO(), object, patch)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	r.Log.Info(
+		"Updated Kubevirt VM disks.",
+		"vm",
+		path.Join(
+			object.Namespace,
+			object.Name),
+		"source",
+		vm.String(),
+		"claims",
+		claims)
+	return
+}
+
+// Replace the claims of the VM volumes.
+func replaceClaims(object *cnv.VirtualMachine, claims map[string]string) {
+	if object.Spec.Template == nil {
+		return
+	}
+	volumes := object.Spec.Template.Spec.Volumes
+	for i := range volumes {
+		volume := &volumes[i]
+		switch {
+		case volume.PersistentVolumeClaim != nil:
+			if claim, found := claims[volume.PersistentVolumeClaim.ClaimName]; found {
+				volume.PersistentVolumeClaim.ClaimName = claim
+			}
+		case volume.DataVolume != nil:
+			if claim, found := claims[volume.DataVolume.Name]; found {
+				volume.DataVolume = nil
+				volume.PersistentVolumeClaim = &cnv.PersistentVolumeClaimVolumeSource{
+					PersistentVolumeClaimVolumeSource: core.PersistentVolumeClaimVolumeSource{
+						ClaimName: claim,
+					},
+				}
+			}
+		}
+	}
+}
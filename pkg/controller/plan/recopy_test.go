@@ -0,0 +1,76 @@
+package plan
+
+import (
+	"testing"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	cnv "kubevirt.io/api/core/v1"
+)
+
+func TestRecopiedPVCs(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	spec := api.MigrationSpec{
+		Recopy: []planapi.DiskRecopy{
+			{VM: ref.Ref{ID: "vm-1"}, PVC: "disk-0"},
+			{VM: ref.Ref{ID: "vm-2"}, PVC: "disk-1"},
+			{VM: ref.Ref{ID: "vm-1"}, PVC: "disk-2"},
+			// Not resolved.
+			{VM: ref.Ref{Name: "vm-3"}, PVC: "disk-3"},
+		},
+	}
+	g.Expect(spec.RecopiedPVCs(ref.Ref{ID: "vm-1"})).To(gomega.Equal([]string{"disk-0", "disk-2"}))
+	g.Expect(spec.RecopiedPVCs(ref.Ref{ID: "vm-3"})).To(gomega.BeEmpty())
+	g.Expect(spec.RecopiedPVCs(ref.Ref{Name: "vm-3"})).To(gomega.BeEmpty())
+
+	recopy := &planapi.Recopy{PVCs: []string{"disk-0"}, Disks: map[string]string{"disk-0": "[ds] vm/vm.vmdk"}}
+	g.Expect(recopy.HasDisk("[ds] vm/vm.vmdk")).To(gomega.BeTrue())
+	g.Expect(recopy.HasDisk("[ds] vm/vm_1.vmdk")).To(gomega.BeFalse())
+}
+
+func TestReplaceClaims(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	object := &cnv.VirtualMachine{
+		Spec: cnv.VirtualMachineSpec{
+			Template: &cnv.VirtualMachineInstanceTemplateSpec{
+				Spec: cnv.VirtualMachineInstanceSpec{
+					Volumes: []cnv.Volume{
+						{
+							Name: "vol-0",
+							VolumeSource: cnv.VolumeSource{
+								PersistentVolumeClaim: &cnv.PersistentVolumeClaimVolumeSource{
+									PersistentVolumeClaimVolumeSource: core.PersistentVolumeClaimVolumeSource{ClaimName: "disk-0"},
+								},
+							},
+						},
+						{
+							Name: "vol-1",
+							VolumeSource: cnv.VolumeSource{
+								DataVolume: &cnv.DataVolumeSource{Name: "disk-1"},
+							},
+						},
+						{
+							Name: "vol-2",
+							VolumeSource: cnv.VolumeSource{
+								PersistentVolumeClaim: &cnv.PersistentVolumeClaimVolumeSource{
+									PersistentVolumeClaimVolumeSource: core.PersistentVolumeClaimVolumeSource{ClaimName: "disk-2"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	replaceClaims(object, map[string]string{"disk-0": "disk-0-new", "disk-1": "disk-1-new"})
+	volumes := object.Spec.Template.Spec.Volumes
+	g.Expect(volumes[0].PersistentVolumeClaim.ClaimName).To(gomega.Equal("disk-0-new"))
+	g.Expect(volumes[1].DataVolume).To(gomega.BeNil())
+	g.Expect(volumes[1].PersistentVolumeClaim.ClaimName).To(gomega.Equal("disk-1-new"))
+	g.Expect(volumes[2].PersistentVolumeClaim.ClaimName).To(gomega.Equal("disk-2"))
+}
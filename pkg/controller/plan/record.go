@@ -0,0 +1,132 @@
+package plan
+
+import (
+	"context"
+	"strings"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	migbase "github.com/kubev2v/forklift/pkg/controller/plan/migrator/base"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Labels
+const (
+	// Record result label.
+	kResult = "result"
+)
+
+// Disk transfer steps reporting the progress in MB.
+var recordTransferSteps = []string{
+	migbase.DiskTransfer,
+	migbase.DiskTransferV2v,
+	migbase.Cutover,
+}
+
+// Record the completed VM migrations in the migration history.
+// Records are created in the plan namespace and are not owned
+// by the plan so they survive its archival and deletion.
+func (r *Migration) recordVMs() (err error) {
+	for _, vm := range r.Plan.Status.Migration.VMs {
+		record := r.newRecord(vm)
+		err = r.Create(context.TODO(), record)
+		if err != nil {
+			if k8serr.IsAlreadyExists(err) {
+				err = nil
+				continue
+			}
+			err = liberr.Wrap(err, "vm", vm.String())
+			return
+		}
+	}
+	return
+}
+
+// Build the migration record of the VM.
+func (r *Migration) newRecord(vm *plan.VMStatus) (record *api.MigrationRecord) {
+	migration := r.Plan.Status.Migration.ActiveSnapshot().Migration
+	record = &api.MigrationRecord{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: r.Plan.Namespace,
+			Name:      recordName(string(migration.UID), vm.ID),
+			Labels: map[string]string{
+				kPlan:      string(r.Plan.UID),
+				kMigration: string(migration.UID),
+			},
+		},
+		Spec: api.MigrationRecordSpec{
+			Plan: api.RecordRef{
+				Namespace: r.Plan.Namespace,
+				Name:      r.Plan.Name,
+				UID:       r.Plan.UID,
+			},
+			Migration: api.RecordRef{
+				Namespace: migration.Namespace,
+				Name:      migration.Name,
+				UID:       migration.UID,
+			},
+			VM:     vm.Ref,
+			Timed:  vm.Timed,
+			Result: recordResult(vm),
+		},
+	}
+	if provider := r.Plan.Referenced.Provider.Source; provider != nil {
+		record.Spec.Source = api.RecordRef{
+			Namespace: provider.Namespace,
+			Name:      provider.Name,
+			UID:       provider.UID,
+		}
+		record.Spec.SourceType = provider.Type()
+	}
+	if record.Spec.VM.Name == "" {
+		record.Spec.VM.Name = vm.Name
+	}
+	if vm.Started != nil && vm.Completed != nil {
+		record.Spec.Duration = int64(vm.Completed.Sub(vm.Started.Time).Seconds())
+	}
+	for _, name := range recordTransferSteps {
+		if step, found := vm.FindStep(name); found {
+			record.Spec.Bytes += step.Progress.Completed * 1024 * 1024
+		}
+	}
+	if vm.Error != nil {
+		record.Spec.Errors = vm.Error.Reasons
+	}
+	record.Labels[kResult] = record.Spec.Result
+	return
+}
+
+// Result of the VM migration.
+func recordResult(vm *plan.VMStatus) string {
+	switch {
+	case vm.HasCondition(api.ConditionSucceeded):
+		return api.RecordSucceeded
+	case vm.HasCondition(api.ConditionCanceled):
+		return api.RecordCanceled
+	default:
+		return api.RecordFailed
+	}
+}
+
+// Record name unique to the migration and VM.
+// The VM ID is reduced to the characters allowed in a name.
+func recordName(migration, vmID string) string {
+	id := strings.Map(
+		func(r rune) rune {
+			switch {
+			case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-':
+				return r
+			default:
+				return '-'
+			}
+		},
+		strings.ToLower(vmID))
+	name := strings.Trim(migration+"-"+id, "-")
+	if len(name) > validation.DNS1123SubdomainMaxLength {
+		name = strings.Trim(name[:validation.DNS1123SubdomainMaxLength], "-")
+	}
+	return name
+}
@@ -0,0 +1,54 @@
+package plan
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
+	libitr "github.com/kubev2v/forklift/pkg/lib/itinerary"
+	"github.com/onsi/gomega"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewRecord(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	p := &api.Plan{ObjectMeta: meta.ObjectMeta{Namespace: "ns", Name: "plan", UID: "p1"}}
+	p.Status.Migration.History = []plan.Snapshot{
+		{Migration: plan.SnapshotRef{Namespace: "ns", Name: "migration", UID: "m1"}},
+	}
+	started := meta.NewTime(time.Now().Add(-time.Minute))
+	completed := meta.NewTime(started.Add(time.Minute))
+	vm := &plan.VMStatus{
+		VM:    plan.VM{Ref: ref.Ref{ID: "vm-1", Name: "web"}},
+		Timed: plan.Timed{Started: &started, Completed: &completed},
+		Pipeline: []*plan.Step{
+			{Task: plan.Task{Name: "Initialize", Progress: libitr.Progress{Completed: 1}}},
+			{Task: plan.Task{Name: "DiskTransfer", Progress: libitr.Progress{Completed: 2}}},
+		},
+		Error: &plan.Error{Reasons: []string{"failed"}},
+	}
+	r := &Migration{Context: &plancontext.Context{Plan: p}}
+
+	record := r.newRecord(vm)
+	g.Expect(record.Name).To(gomega.Equal("m1-vm-1"))
+	g.Expect(record.Namespace).To(gomega.Equal("ns"))
+	g.Expect(record.Spec.Migration.Name).To(gomega.Equal("migration"))
+	g.Expect(record.Spec.VM.Name).To(gomega.Equal("web"))
+	g.Expect(record.Spec.Duration).To(gomega.Equal(int64(60)))
+	g.Expect(record.Spec.Bytes).To(gomega.Equal(int64(2 * 1024 * 1024)))
+	g.Expect(record.Spec.Result).To(gomega.Equal(api.RecordFailed))
+	g.Expect(record.Spec.Errors).To(gomega.Equal([]string{"failed"}))
+	g.Expect(record.Labels[kResult]).To(gomega.Equal(api.RecordFailed))
+
+	vm.SetCondition(libcnd.Condition{Type: api.ConditionSucceeded, Status: libcnd.True})
+	g.Expect(r.newRecord(vm).Spec.Result).To(gomega.Equal(api.RecordSucceeded))
+
+	g.Expect(recordName("m1", "Vm_42")).To(gomega.Equal("m1-vm-42"))
+	g.Expect(len(recordName("m1", strings.Repeat("x", 300)))).To(gomega.Equal(253))
+}
@@ -4,6 +4,12 @@ import (
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
 	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
 	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	"github.com/kubev2v/forklift/pkg/controller/plan/scheduler/azure"
+	"github.com/kubev2v/forklift/pkg/controller/plan/scheduler/catalog"
+	"github.com/kubev2v/forklift/pkg/controller/plan/scheduler/ec2"
+	"github.com/kubev2v/forklift/pkg/controller/plan/scheduler/image"
+	"github.com/kubev2v/forklift/pkg/controller/plan/scheduler/libvirt"
+	"github.com/kubev2v/forklift/pkg/controller/plan/scheduler/nutanix"
 	"github.com/kubev2v/forklift/pkg/controller/plan/scheduler/ocp"
 	"github.com/kubev2v/forklift/pkg/controller/plan/scheduler/openstack"
 	"github.com/kubev2v/forklift/pkg/controller/plan/scheduler/ova"
@@ -48,6 +54,36 @@ func New(ctx *plancontext.Context) (scheduler Scheduler, err error) {
 			Context:     ctx,
 			MaxInFlight: settings.Settings.MaxInFlight,
 		}
+	case api.Nutanix:
+		scheduler = &nutanix.Scheduler{
+			Context:     ctx,
+			MaxInFlight: settings.Settings.MaxInFlight,
+		}
+	case api.EC2:
+		scheduler = &ec2.Scheduler{
+			Context:     ctx,
+			MaxInFlight: settings.Settings.MaxInFlight,
+		}
+	case api.Azure:
+		scheduler = &azure.Scheduler{
+			Context:     ctx,
+			MaxInFlight: settings.Settings.MaxInFlight,
+		}
+	case api.Libvirt:
+		scheduler = &libvirt.Scheduler{
+			Context:     ctx,
+			MaxInFlight: settings.Settings.MaxInFlight,
+		}
+	case api.Image:
+		scheduler = &image.Scheduler{
+			Context:     ctx,
+			MaxInFlight: settings.Settings.MaxInFlight,
+		}
+	case api.Catalog:
+		scheduler = &catalog.Scheduler{
+			Context:     ctx,
+			MaxInFlight: settings.Settings.MaxInFlight,
+		}
 	default:
 		err = liberr.New("provider not supported.")
 	}
@@ -61,10 +61,14 @@ func (r *Scheduler) Next() (vm *plan.VMStatus, hasNext bool, err error) {
 		return
 	}
 
+	groups := r.Groups()
 	for _, vmStatus := range r.Plan.Status.Migration.VMs {
 		if vmStatus.HasCondition(Canceled) {
 			continue
 		}
+		if !groups.Ready(vmStatus.Group) {
+			continue
+		}
 		if !vmStatus.MarkedStarted() && !vmStatus.MarkedCompleted() {
 			vm = vmStatus
 			hasNext = true
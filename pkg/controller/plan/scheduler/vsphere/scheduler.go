@@ -180,6 +180,7 @@ func (r *Scheduler) buildInFlight() (err error) {
 func (r *Scheduler) buildPending() (err error) {
 	r.pending = make(map[string][]*pendingVM)
 
+	groups := r.Groups()
 	for _, vmStatus := range r.Plan.Status.Migration.VMs {
 		vm := &model.VM{}
 		err = r.Source.Inventory.Find(vm, vmStatus.Ref)
@@ -189,6 +190,9 @@ func (r *Scheduler) buildPending() (err error) {
 		if vmStatus.HasCondition(Canceled) {
 			continue
 		}
+		if !groups.Ready(vmStatus.Group) {
+			continue
+		}
 		if !vmStatus.MarkedStarted() && !vmStatus.MarkedCompleted() {
 			pending := &pendingVM{
 				status: vmStatus,
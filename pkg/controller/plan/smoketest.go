@@ -0,0 +1,579 @@
+package plan
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+	cnv "kubevirt.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// Post-migration tests.
+const (
+	// Config map key of the test spec.
+	TestSpecKey = "tests.yaml"
+	// Default time (seconds) allowed for the VM to boot.
+	DefaultBootTimeout = 600
+	// Default time (seconds) allowed for a check to pass.
+	DefaultCheckTimeout = 300
+	// Time allowed for a check attempt.
+	CheckAttemptTimeout = time.Second * 10
+	// VM condition reporting failed checks.
+	ConditionTestsFailed = "TestsFailed"
+)
+
+// Guest agent (virt-launcher).
+const (
+	LauncherContainer = "compute"
+	LibvirtURI        = "qemu+unix:///session?socket=/var/run/libvirt/virtqemud-sock"
+)
+
+// Runs a command in a pod container.
+type PodExecFunc func(pod *core.Pod, container string, command []string) (stdout string, err error)
+
+// Runs the post-migration checks of a VM.
+// The checks are run once the VM has booted: the VMI is
+// running, has an IP address when needed by the network checks
+// and the guest agent is connected when needed by the commands.
+// Failed attempts are retried until the check timeout.
+type TestRunner struct {
+	*plancontext.Context
+	// KubeVirt.
+	kubevirt *KubeVirt
+	// Pod exec. Defaults to the pods/exec subresource.
+	exec PodExecFunc
+}
+
+// Run.
+func (r *TestRunner) Run(vm *planapi.VMStatus) (err error) {
+	step, found := vm.FindStep(api.PhaseRunTests)
+	if !found {
+		err = liberr.New("Step not found.")
+		return
+	}
+	step.MarkStarted()
+	step.Phase = api.StepRunning
+	configMap, err := r.testSpec()
+	if err != nil {
+		return
+	}
+	spec, pErr := ParseTestSpec(configMap)
+	if pErr != nil {
+		step.AddError(pErr.Error())
+		step.MarkCompleted()
+		return
+	}
+	report := vm.Tests
+	if report == nil {
+		report = &planapi.TestReport{}
+		for _, check := range spec.Checks {
+			report.Results = append(
+				report.Results,
+				planapi.TestResult{
+					Name:  check.Name,
+					Kind:  check.Kind(),
+					Phase: planapi.TestPending,
+				})
+		}
+		report.MarkStarted()
+		vm.Tests = report
+	}
+	defer func() {
+		if err == nil && report.MarkedCompleted() {
+			r.complete(vm, step)
+		}
+	}()
+	if report.MarkedCompleted() {
+		return
+	}
+	virtualMachine, err := r.virtualMachine(vm)
+	if err != nil {
+		return
+	}
+	switch {
+	case virtualMachine == nil:
+		report.Skipped = "The VM was not found."
+	case !started(virtualMachine):
+		report.Skipped = "The VM is not started."
+	}
+	if report.Skipped != "" {
+		report.MarkCompleted()
+		return
+	}
+	vmi := &cnv.VirtualMachineInstance{}
+	err = r.Destination.Client.Get(context.TODO(), client.ObjectKeyFromObject(virtualMachine), vmi)
+	if err != nil {
+		if !k8serr.IsNotFound(err) {
+			err = liberr.Wrap(err)
+			return
+		}
+		err = nil
+		vmi = nil
+	}
+	if report.Booted == nil {
+		if vmi != nil && booted(vmi, spec) {
+			now := meta.Now()
+			report.Booted = &now
+		} else {
+			if expired(report.Started, spec.BootTimeout, DefaultBootTimeout) {
+				for i := range report.Results {
+					report.Results[i].Record(false, "The VM did not boot.", true)
+				}
+				report.MarkCompleted()
+			}
+			return
+		}
+	}
+	for _, check := range spec.Checks {
+		result, found := report.FindResult(check.Name)
+		if !found || result.Phase != planapi.TestPending {
+			continue
+		}
+		passed, message := r.check(vmi, &check)
+		result.Record(passed, message, expired(report.Booted, check.Timeout, DefaultCheckTimeout))
+	}
+	if report.Done() {
+		report.MarkCompleted()
+	}
+
+	return
+}
+
+// Complete the step.
+func (r *TestRunner) complete(vm *planapi.VMStatus, step *planapi.Step) {
+	report := vm.Tests
+	if failed := report.Failed(); failed > 0 {
+		vm.SetCondition(
+			libcnd.Condition{
+				Type:     ConditionTestsFailed,
+				Status:   True,
+				Category: api.CategoryWarn,
+				Message:  fmt.Sprintf("%d of %d post-migration checks failed.", failed, len(report.Results)),
+			})
+	}
+	step.Progress.Completed = 1
+	step.MarkCompleted()
+}
+
+// Get the test spec (config map) referenced by the plan.
+func (r *TestRunner) testSpec() (configMap *core.ConfigMap, err error) {
+	ref := r.Plan.Spec.TestSpec
+	configMap = &core.ConfigMap{}
+	key := client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}
+	if key.Namespace == "" {
+		key.Namespace = r.Plan.Namespace
+	}
+	err = r.Client.Get(context.TODO(), key, configMap)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+	return
+}
+
+// Find the migrated VM.
+func (r *TestRunner) virtualMachine(vm *planapi.VMStatus) (object *cnv.VirtualMachine, err error) {
+	list := &cnv.VirtualMachineList{}
+	err = r.Destination.Client.List(
+		context.TODO(),
+		list,
+		&client.ListOptions{
+			LabelSelector: k8slabels.SelectorFromSet(r.kubevirt.vmLabels(vm.Ref)),
+			Namespace:     r.Plan.Spec.TargetNamespace,
+		})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if len(list.Items) > 0 {
+		object = &list.Items[0]
+	}
+	return
+}
+
+// Run a check attempt.
+func (r *TestRunner) check(vmi *cnv.VirtualMachineInstance, check *planapi.TestCheck) (passed bool, message string) {
+	var err error
+	switch {
+	case check.Command != nil:
+		passed, message, err = r.command(vmi, check.Command)
+	case check.HTTP != nil:
+		passed, message, err = httpCheck(vmiAddress(vmi), check.HTTP)
+	case check.Port != nil:
+		passed, message, err = portCheck(vmiAddress(vmi), check.Port)
+	}
+	if err != nil {
+		passed = false
+		message = err.Error()
+	}
+	return
+}
+
+// Run the command in the guest using the guest agent.
+// The agent is reached through libvirt in the virt-launcher pod.
+func (r *TestRunner) command(vmi *cnv.VirtualMachineInstance, check *planapi.CommandCheck) (passed bool, message string, err error) {
+	pod, err := r.launcher(vmi)
+	if err != nil {
+		return
+	}
+	exec := r.exec
+	if exec == nil {
+		exec = r.podExec
+	}
+	agent := func(command string, arguments interface{}, reply interface{}) (err error) {
+		request, err := json.Marshal(
+			map[string]interface{}{
+				"execute":   command,
+				"arguments": arguments,
+			})
+		if err != nil {
+			return
+		}
+		stdout, err := exec(
+			pod,
+			LauncherContainer,
+			[]string{
+				"virsh",
+				"-c",
+				LibvirtURI,
+				"qemu-agent-command",
+				vmi.Namespace + "_" + vmi.Name,
+				string(request),
+			})
+		if err != nil {
+			return
+		}
+		err = json.Unmarshal([]byte(stdout), reply)
+		if err != nil {
+			err = liberr.Wrap(err, "reply", stdout)
+		}
+		return
+	}
+	execution := struct {
+		Return struct {
+			PID int `json:"pid"`
+		} `json:"return"`
+	}{}
+	err = agent(
+		"guest-exec",
+		map[string]interface{}{
+			"path":           check.Path,
+			"arg":            check.Args,
+			"capture-output": true,
+		},
+		&execution)
+	if err != nil {
+		return
+	}
+	status := struct {
+		Return struct {
+			Exited   bool   `json:"exited"`
+			ExitCode int    `json:"exitcode"`
+			Out      string `json:"out-data"`
+			Err      string `json:"err-data"`
+		} `json:"return"`
+	}{}
+	deadline := time.Now().Add(CheckAttemptTimeout)
+	for {
+		err = agent("guest-exec-status", map[string]interface{}{"pid": execution.Return.PID}, &status)
+		if err != nil || status.Return.Exited {
+			break
+		}
+		if time.Now().After(deadline) {
+			message = "The command did not exit."
+			return
+		}
+		time.Sleep(time.Second)
+	}
+	if err != nil {
+		return
+	}
+	output, _ := base64.StdEncoding.DecodeString(status.Return.Out)
+	passed, message = commandResult(check, status.Return.ExitCode, string(output))
+	return
+}
+
+// Find the virt-launcher pod of the VMI.
+func (r *TestRunner) launcher(vmi *cnv.VirtualMachineInstance) (pod *core.Pod, err error) {
+	list := &core.PodList{}
+	err = r.Destination.Client.List(
+		context.TODO(),
+		list,
+		&client.ListOptions{
+			LabelSelector: k8slabels.SelectorFromSet(map[string]string{cnv.CreatedByLabel: string(vmi.UID)}),
+			Namespace:     vmi.Namespace,
+		})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for i := range list.Items {
+		if list.Items[i].Status.Phase == core.PodRunning {
+			pod = &list.Items[i]
+			return
+		}
+	}
+	err = liberr.New("virt-launcher pod not found.", "vmi", vmi.Name)
+	return
+}
+
+// Run the command in the pod container.
+func (r *TestRunner) podExec(pod *core.Pod, container string, command []string) (stdout string, err error) {
+	cfg := r.Destination.RestCfg
+	if cfg == nil {
+		err = liberr.New("REST configuration not found.")
+		return
+	}
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	request := clientset.CoreV1().RESTClient().
+		Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("exec").
+		VersionedParams(
+			&core.PodExecOptions{
+				Container: container,
+				Command:   command,
+				Stdout:    true,
+				Stderr:    true,
+			},
+			scheme.ParameterCodec)
+	executor, err := remotecommand.NewSPDYExecutor(cfg, http.MethodPost, request.URL())
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.TODO(), CheckAttemptTimeout)
+	defer cancel()
+	out := &bytes.Buffer{}
+	errOut := &bytes.Buffer{}
+	err = executor.StreamWithContext(
+		ctx,
+		remotecommand.StreamOptions{
+			Stdout: out,
+			Stderr: errOut,
+		})
+	if err != nil {
+		err = liberr.Wrap(err, "stderr", errOut.String())
+		return
+	}
+	stdout = out.String()
+	return
+}
+
+// Parse the test spec in the config map.
+func ParseTestSpec(configMap *core.ConfigMap) (spec *planapi.TestSpec, err error) {
+	content, found := configMap.Data[TestSpecKey]
+	if !found {
+		err = liberr.New("Test spec not found.", "key", TestSpecKey)
+		return
+	}
+	spec = &planapi.TestSpec{}
+	err = yaml.UnmarshalStrict([]byte(content), spec)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	names := map[string]bool{}
+	for _, check := range spec.Checks {
+		reason := ""
+		kinds := 0
+		for _, set := range []bool{check.Command != nil, check.HTTP != nil, check.Port != nil} {
+			if set {
+				kinds++
+			}
+		}
+		switch {
+		case check.Name == "":
+			reason = "name required."
+		case names[check.Name]:
+			reason = "name not unique."
+		case kinds != 1:
+			reason = "exactly one of command, http and port required."
+		case check.Command != nil && check.Command.Path == "":
+			reason = "command path required."
+		case check.HTTP != nil && (check.HTTP.Port < 0 || check.HTTP.Port > 65535):
+			reason = "http port not valid."
+		case check.Port != nil && (check.Port.Port < 1 || check.Port.Port > 65535):
+			reason = "port not valid."
+		}
+		if reason != "" {
+			err = liberr.New("Check not valid: "+reason, "check", check.Name)
+			return
+		}
+		names[check.Name] = true
+	}
+	return
+}
+
+// The VM is started.
+func started(vm *cnv.VirtualMachine) bool {
+	if vm.Spec.RunStrategy != nil {
+		return *vm.Spec.RunStrategy != cnv.RunStrategyHalted
+	}
+	return vm.Spec.Running != nil && *vm.Spec.Running
+}
+
+// The VMI has booted as needed by the checks.
+func booted(vmi *cnv.VirtualMachineInstance, spec *planapi.TestSpec) bool {
+	if vmi.Status.Phase != cnv.Running {
+		return false
+	}
+	for _, check := range spec.Checks {
+		switch {
+		case check.Command != nil:
+			if !agentConnected(vmi) {
+				return false
+			}
+		case check.HTTP != nil && check.HTTP.Host == "",
+			check.Port != nil && check.Port.Host == "":
+			if vmiAddress(vmi) == "" {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// The guest agent is connected.
+func agentConnected(vmi *cnv.VirtualMachineInstance) bool {
+	for _, cnd := range vmi.Status.Conditions {
+		if cnd.Type == cnv.VirtualMachineInstanceAgentConnected {
+			return cnd.Status == core.ConditionTrue
+		}
+	}
+	return false
+}
+
+// The (first) IP address of the VMI.
+func vmiAddress(vmi *cnv.VirtualMachineInstance) string {
+	if vmi == nil {
+		return ""
+	}
+	for _, nic := range vmi.Status.Interfaces {
+		if nic.IP != "" {
+			return nic.IP
+		}
+	}
+	return ""
+}
+
+// The timeout (seconds) since the time has expired.
+func expired(since *meta.Time, timeout, defaultTimeout int) bool {
+	if since == nil {
+		return false
+	}
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return time.Since(since.Time) > time.Duration(timeout)*time.Second
+}
+
+// Evaluate the command result.
+func commandResult(check *planapi.CommandCheck, exitCode int, output string) (passed bool, message string) {
+	switch {
+	case exitCode != check.ExitCode:
+		message = fmt.Sprintf("Exit code %d, expected %d.", exitCode, check.ExitCode)
+	case !strings.Contains(output, check.Output):
+		message = "Expected output not found."
+	default:
+		passed = true
+		message = fmt.Sprintf("Exit code %d.", exitCode)
+	}
+	return
+}
+
+// Send the HTTP request.
+func httpCheck(address string, check *planapi.HTTPCheck) (passed bool, message string, err error) {
+	host := check.Host
+	if host == "" {
+		host = address
+	}
+	scheme := "http"
+	if check.HTTPS {
+		scheme = "https"
+	}
+	if check.Port != 0 {
+		host = net.JoinHostPort(host, strconv.Itoa(check.Port))
+	} else if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	path := check.Path
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	httpClient := &http.Client{
+		Timeout: CheckAttemptTimeout,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: true,
+			},
+		},
+	}
+	response, err := httpClient.Get(scheme + "://" + host + path)
+	if err != nil {
+		return
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(response.Body, 1<<20))
+	if err != nil {
+		return
+	}
+	wanted := check.Status
+	if wanted == 0 {
+		wanted = http.StatusOK
+	}
+	switch {
+	case response.StatusCode != wanted:
+		message = fmt.Sprintf("Status %d, expected %d.", response.StatusCode, wanted)
+	case !strings.Contains(string(body), check.Body):
+		message = "Expected body not found."
+	default:
+		passed = true
+		message = fmt.Sprintf("Status %d.", response.StatusCode)
+	}
+	return
+}
+
+// Connect to the TCP port.
+func portCheck(address string, check *planapi.PortCheck) (passed bool, message string, err error) {
+	host := check.Host
+	if host == "" {
+		host = address
+	}
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, strconv.Itoa(check.Port)), CheckAttemptTimeout)
+	if err != nil {
+		return
+	}
+	_ = conn.Close()
+	passed = true
+	message = "Connected."
+	return
+}
@@ -0,0 +1,235 @@
+package plan
+
+import (
+	"context"
+	"encoding/base64"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	cnv "kubevirt.io/api/core/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakeClient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestParseTestSpec(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	parse := func(content string) (*planapi.TestSpec, error) {
+		return ParseTestSpec(&core.ConfigMap{Data: map[string]string{TestSpecKey: content}})
+	}
+	spec, err := parse(`
+bootTimeout: 300
+checks:
+- name: nginx
+  http:
+    port: 8080
+    path: /healthz
+- name: ssh
+  port:
+    port: 22
+- name: service
+  command:
+    path: /usr/bin/systemctl
+    args: [is-active, nginx]
+    output: active
+`)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(spec.BootTimeout).To(gomega.Equal(300))
+	g.Expect(spec.Checks).To(gomega.HaveLen(3))
+	g.Expect(spec.Checks[0].Kind()).To(gomega.Equal(planapi.HTTPCheckKind))
+	g.Expect(spec.Checks[1].Kind()).To(gomega.Equal(planapi.PortCheckKind))
+	g.Expect(spec.Checks[2].Command.Args).To(gomega.Equal([]string{"is-active", "nginx"}))
+	// Not valid.
+	for _, content := range []string{
+		"checks: [{name: a, port: {port: 22}}, {name: a, port: {port: 23}}]",
+		"checks: [{name: a, port: {port: 22}, http: {port: 80}}]",
+		"checks: [{name: a}]",
+		"checks: [{port: {port: 22}}]",
+		"checks: [{name: a, port: {port: 0}}]",
+		"checks: [{name: a, command: {args: [x]}}]",
+		"checks: [{name: a, unknown: true}]",
+	} {
+		_, err = parse(content)
+		g.Expect(err).To(gomega.HaveOccurred(), content)
+	}
+	_, err = ParseTestSpec(&core.ConfigMap{})
+	g.Expect(err).To(gomega.HaveOccurred())
+}
+
+func TestNetworkChecks(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/healthz" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte("status: ok"))
+	}))
+	defer server.Close()
+	host, port, _ := net.SplitHostPort(strings.TrimPrefix(server.URL, "http://"))
+	portNumber, _ := strconv.Atoi(port)
+
+	passed, message, err := httpCheck(host, &planapi.HTTPCheck{Port: portNumber, Path: "healthz", Body: "ok"})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(passed).To(gomega.BeTrue(), message)
+	passed, message, err = httpCheck("", &planapi.HTTPCheck{Host: host, Port: portNumber, Path: "/other"})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(passed).To(gomega.BeFalse())
+	g.Expect(message).To(gomega.Equal("Status 404, expected 200."))
+	passed, _, err = httpCheck(host, &planapi.HTTPCheck{Port: portNumber, Path: "/healthz", Body: "ready"})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(passed).To(gomega.BeFalse())
+	passed, _, err = portCheck(host, &planapi.PortCheck{Port: portNumber})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(passed).To(gomega.BeTrue())
+}
+
+func TestRunTests(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	defer listener.Close()
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	p := &api.Plan{ObjectMeta: meta.ObjectMeta{Namespace: "ns", Name: "plan", UID: "p1"}}
+	p.Spec.TargetNamespace = "target"
+	p.Spec.TestSpec = &core.ObjectReference{Name: "tests"}
+	migration := &api.Migration{ObjectMeta: meta.ObjectMeta{UID: "m1"}}
+	configMap := &core.ConfigMap{
+		ObjectMeta: meta.ObjectMeta{Namespace: "ns", Name: "tests"},
+		Data: map[string]string{
+			TestSpecKey: `
+checks:
+- name: port
+  port:
+    port: ` + strconv.Itoa(port) + `
+- name: service
+  command:
+    path: /usr/bin/systemctl
+    args: [is-active, nginx]
+    output: active
+- name: closed
+  timeout: 1
+  port:
+    port: 1
+`,
+		},
+	}
+	runStrategy := cnv.RunStrategyAlways
+	vm := &cnv.VirtualMachine{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "target",
+			Name:      "web",
+			Labels:    map[string]string{kMigration: "m1", kPlan: "p1", kVM: "vm-1"},
+		},
+	}
+	vm.Spec.RunStrategy = &runStrategy
+	vmi := &cnv.VirtualMachineInstance{ObjectMeta: meta.ObjectMeta{Namespace: "target", Name: "web", UID: "i1"}}
+	vmi.Status.Phase = cnv.Running
+	vmi.Status.Interfaces = []cnv.VirtualMachineInstanceNetworkInterface{{IP: "127.0.0.1"}}
+	vmi.Status.Conditions = []cnv.VirtualMachineInstanceCondition{
+		{Type: cnv.VirtualMachineInstanceAgentConnected, Status: core.ConditionTrue},
+	}
+	launcher := &core.Pod{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "target",
+			Name:      "virt-launcher-web",
+			Labels:    map[string]string{cnv.CreatedByLabel: "i1"},
+		},
+		Status: core.PodStatus{Phase: core.PodRunning},
+	}
+	scheme := runtime.NewScheme()
+	_ = core.AddToScheme(scheme)
+	_ = cnv.AddToScheme(scheme)
+	client := fakeClient.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(configMap, vm, vmi, launcher).
+		Build()
+	ctx := &plancontext.Context{
+		Client:    client,
+		Plan:      p,
+		Migration: migration,
+		Log:       logging.WithName("test"),
+	}
+	ctx.Destination.Client = client
+	commands := []string{}
+	runner := TestRunner{
+		Context:  ctx,
+		kubevirt: &KubeVirt{Context: ctx},
+		exec: func(pod *core.Pod, container string, command []string) (stdout string, err error) {
+			g.Expect(pod.Name).To(gomega.Equal("virt-launcher-web"))
+			g.Expect(container).To(gomega.Equal(LauncherContainer))
+			g.Expect(command[4]).To(gomega.Equal("target_web"))
+			commands = append(commands, command[5])
+			if strings.Contains(command[5], "guest-exec-status") {
+				out := base64.StdEncoding.EncodeToString([]byte("active\n"))
+				stdout = `{"return":{"exited":true,"exitcode":0,"out-data":"` + out + `"}}`
+			} else {
+				stdout = `{"return":{"pid":42}}`
+			}
+			return
+		},
+	}
+	status := &planapi.VMStatus{
+		VM: planapi.VM{Ref: ref.Ref{ID: "vm-1"}},
+		Pipeline: []*planapi.Step{
+			{Task: planapi.Task{Name: api.PhaseRunTests}},
+		},
+	}
+
+	err = runner.Run(status)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	report := status.Tests
+	g.Expect(report).ToNot(gomega.BeNil())
+	g.Expect(report.Booted).ToNot(gomega.BeNil())
+	g.Expect(report.Results).To(gomega.HaveLen(3))
+	g.Expect(report.Results[0].Phase).To(gomega.Equal(planapi.TestPassed))
+	g.Expect(report.Results[1].Phase).To(gomega.Equal(planapi.TestPassed))
+	g.Expect(report.Results[1].Kind).To(gomega.Equal(planapi.CommandCheckKind))
+	g.Expect(commands).To(gomega.HaveLen(2))
+	g.Expect(commands[0]).To(gomega.ContainSubstring(`"path":"/usr/bin/systemctl"`))
+	g.Expect(commands[1]).To(gomega.ContainSubstring(`"pid":42`))
+	// Retried until the timeout.
+	g.Expect(report.Results[2].Phase).To(gomega.Equal(planapi.TestPending))
+	g.Expect(report.MarkedCompleted()).To(gomega.BeFalse())
+	booted := meta.NewTime(report.Booted.Add(-2 * time.Second))
+	report.Booted = &booted
+	err = runner.Run(status)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(report.Results[2].Phase).To(gomega.Equal(planapi.TestFailed))
+	g.Expect(report.Results[2].Attempts).To(gomega.Equal(2))
+	g.Expect(report.MarkedCompleted()).To(gomega.BeTrue())
+	g.Expect(commands).To(gomega.HaveLen(2))
+	step, _ := status.FindStep(api.PhaseRunTests)
+	g.Expect(step.MarkedCompleted()).To(gomega.BeTrue())
+	g.Expect(step.Error).To(gomega.BeNil())
+	g.Expect(status.HasCondition(ConditionTestsFailed)).To(gomega.BeTrue())
+
+	// Not started.
+	halted := cnv.RunStrategyHalted
+	g.Expect(client.Get(context.TODO(), k8sclient.ObjectKeyFromObject(vm), vm)).To(gomega.Succeed())
+	vm.Spec.RunStrategy = &halted
+	g.Expect(client.Update(context.TODO(), vm)).To(gomega.Succeed())
+	status.Tests = nil
+	status.DeleteCondition(ConditionTestsFailed)
+	err = runner.Run(status)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(status.Tests.Skipped).To(gomega.Equal("The VM is not started."))
+	g.Expect(status.HasCondition(ConditionTestsFailed)).To(gomega.BeFalse())
+}
@@ -0,0 +1,219 @@
+package plan
+
+import (
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+)
+
+// Disk transfer mechanism.
+// Populates the destination volumes of a VM.
+type DiskTransfer interface {
+	// Transfer name.
+	Name() string
+	// Create the destination volumes.
+	// The source is ready when the provider pre-transfer
+	// actions have completed.
+	CreateVolumes(vm *plan.VMStatus, ready bool) (err error)
+	// Update the disk copy progress of the step.
+	UpdateProgress(vm *plan.VMStatus, step *plan.Step) (err error)
+	// Delete the destination volumes.
+	DeleteVolumes(vm *plan.VMStatus) (err error)
+}
+
+// Disk transfer factory.
+// Returns nil when the transfer does not apply to the plan.
+type DiskTransferFactory func(r *Migration) (transfer DiskTransfer, err error)
+
+// Disk transfer factories, in order of precedence.
+// New transfer mechanisms are added here.
+var DiskTransfers = []DiskTransferFactory{
+	NewPopulatorTransfer,
+	NewPersistentVolumeClaimTransfer,
+	NewVirtV2vTransfer,
+	NewDataVolumeTransfer,
+}
+
+// Select the disk transfer for the plan.
+func selectDiskTransfer(r *Migration) (transfer DiskTransfer, err error) {
+	for _, factory := range DiskTransfers {
+		transfer, err = factory(r)
+		if err != nil || transfer != nil {
+			return
+		}
+	}
+	err = liberr.New("no disk transfer supported by the plan.")
+	return
+}
+
+// Disks imported by CDI DataVolumes.
+// Guest conversion is done in-place after the transfer.
+type DataVolumeTransfer struct {
+	*Migration
+}
+
+// Build the CDI DataVolume transfer, which applies to all plans.
+func NewDataVolumeTransfer(r *Migration) (transfer DiskTransfer, err error) {
+	transfer = &DataVolumeTransfer{Migration: r}
+	return
+}
+
+// Transfer name.
+func (r *DataVolumeTransfer) Name() string {
+	return "DataVolume"
+}
+
+// Create the DataVolumes once the source is ready.
+func (r *DataVolumeTransfer) CreateVolumes(vm *plan.VMStatus, ready bool) (err error) {
+	if !ready {
+		return
+	}
+	var dataVolumes []cdi.DataVolume
+	dataVolumes, err = r.kubevirt.DataVolumes(vm)
+	if err != nil {
+		return
+	}
+	if vm.Recopy != nil {
+		// Only the disks copied again.
+		dataVolumes = r.kubevirt.recopiedDataVolumes(vm, dataVolumes)
+	} else if vm.Warm != nil {
+		// The disks excluded from snapshots are copied at cutover.
+		var cold []cdi.DataVolume
+		dataVolumes, cold = splitColdCopy(dataVolumes)
+		for _, dv := range cold {
+			r.kubevirt.releaseTransferNetworkIPs(dv.Annotations)
+		}
+		err = r.provider.SetCheckpoints(vm.Ref, vm.Warm.Precopies, dataVolumes, false, r.kubevirt.loadHosts)
+		if err != nil {
+			return
+		}
+	}
+	err = r.kubevirt.EnsureDataVolumes(vm, dataVolumes)
+	return
+}
+
+// Update the copy progress from the DataVolumes.
+func (r *DataVolumeTransfer) UpdateProgress(vm *plan.VMStatus, step *plan.Step) (err error) {
+	err = r.updateCopyProgress(vm, step)
+	return
+}
+
+// Delete the DataVolumes.
+func (r *DataVolumeTransfer) DeleteVolumes(vm *plan.VMStatus) (err error) {
+	err = r.kubevirt.DeleteDataVolumes(vm)
+	return
+}
+
+// Disks copied by virt-v2v while converting the guest.
+// The DataVolumes only provide the destination volumes.
+type VirtV2vTransfer struct {
+	DataVolumeTransfer
+}
+
+// Build the virt-v2v transfer when the plan uses virt-v2v to copy the disks.
+func NewVirtV2vTransfer(r *Migration) (transfer DiskTransfer, err error) {
+	useV2vForTransfer, err := r.Context.Plan.ShouldUseV2vForTransfer()
+	if err != nil || !useV2vForTransfer {
+		return
+	}
+	transfer = &VirtV2vTransfer{DataVolumeTransfer{Migration: r}}
+	return
+}
+
+// Transfer name.
+func (r *VirtV2vTransfer) Name() string {
+	return "VirtV2v"
+}
+
+// Update the copy progress reported by the conversion pod.
+func (r *VirtV2vTransfer) UpdateProgress(vm *plan.VMStatus, step *plan.Step) (err error) {
+	err = r.updateConversionProgress(vm, step)
+	return
+}
+
+// Disks populated by volume populators.
+type PopulatorTransfer struct {
+	*Migration
+}
+
+// Build the volume populator transfer when supported by the builder.
+func NewPopulatorTransfer(r *Migration) (transfer DiskTransfer, err error) {
+	if !r.builder.SupportsVolumePopulators() {
+		return
+	}
+	transfer = &PopulatorTransfer{Migration: r}
+	return
+}
+
+// Transfer name.
+func (r *PopulatorTransfer) Name() string {
+	return "VolumePopulator"
+}
+
+// Create the populated PVCs. The populators wait
+// for the source, so they are created right away.
+func (r *PopulatorTransfer) CreateVolumes(vm *plan.VMStatus, ready bool) (err error) {
+	pvcs, err := r.kubevirt.PopulatorVolumes(vm.Ref)
+	if err != nil {
+		return
+	}
+	err = r.kubevirt.EnsurePopulatorVolumes(vm, pvcs)
+	return
+}
+
+// Update the copy progress from the populators.
+func (r *PopulatorTransfer) UpdateProgress(vm *plan.VMStatus, step *plan.Step) (err error) {
+	err = r.updatePopulatorCopyProgress(vm, step)
+	return
+}
+
+// Delete the populated PVCs and any DataVolumes.
+func (r *PopulatorTransfer) DeleteVolumes(vm *plan.VMStatus) (err error) {
+	err = r.kubevirt.DeletePopulatedPVCs(vm)
+	if err != nil {
+		return
+	}
+	err = r.kubevirt.DeleteDataVolumes(vm)
+	return
+}
+
+// Disks copied into plain PVCs on destinations without CDI.
+// The PVCs are built from the DataVolumes, which must be blank
+// as the disks are copied by virt-v2v.
+type PersistentVolumeClaimTransfer struct {
+	VirtV2vTransfer
+}
+
+// Build the PVC transfer when CDI is not installed on the destination.
+func NewPersistentVolumeClaimTransfer(r *Migration) (transfer DiskTransfer, err error) {
+	found, err := r.kubevirt.HasCDI()
+	if err != nil || found {
+		return
+	}
+	transfer = &PersistentVolumeClaimTransfer{VirtV2vTransfer{DataVolumeTransfer{Migration: r}}}
+	return
+}
+
+// Transfer name.
+func (r *PersistentVolumeClaimTransfer) Name() string {
+	return "PersistentVolumeClaim"
+}
+
+// Create the PVCs once the source is ready.
+func (r *PersistentVolumeClaimTransfer) CreateVolumes(vm *plan.VMStatus, ready bool) (err error) {
+	if !ready {
+		return
+	}
+	dataVolumes, err := r.kubevirt.DataVolumes(vm)
+	if err != nil {
+		return
+	}
+	err = r.kubevirt.EnsurePersistentVolumeClaims(vm, dataVolumes)
+	return
+}
+
+// Delete the PVCs.
+func (r *PersistentVolumeClaimTransfer) DeleteVolumes(vm *plan.VMStatus) (err error) {
+	err = r.kubevirt.DeletePopulatedPVCs(vm)
+	return
+}
@@ -0,0 +1,117 @@
+package plan
+
+import (
+	v1beta1 "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planbase "github.com/kubev2v/forklift/pkg/controller/plan/adapter/base"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	ginkgo "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = ginkgo.Describe("disk transfer", func() {
+	transfers := DiskTransfers
+	ginkgo.BeforeEach(func() {
+		// The populator transfer requires a builder.
+		DiskTransfers = []DiskTransferFactory{
+			NewVirtV2vTransfer,
+			NewDataVolumeTransfer,
+		}
+	})
+	ginkgo.AfterEach(func() {
+		DiskTransfers = transfers
+	})
+
+	migration := func(source v1beta1.ProviderType, warm bool) *Migration {
+		sourceType, destinationType := source, v1beta1.OpenShift
+		plan := &v1beta1.Plan{}
+		plan.Spec.Warm = warm
+		plan.Spec.MigrateSharedDisks = true
+		plan.Referenced.Provider.Source = &v1beta1.Provider{
+			Spec: v1beta1.ProviderSpec{Type: &sourceType},
+		}
+		plan.Referenced.Provider.Destination = &v1beta1.Provider{
+			Spec: v1beta1.ProviderSpec{Type: &destinationType},
+		}
+		return &Migration{Context: &plancontext.Context{Plan: plan}}
+	}
+
+	ginkgo.DescribeTable("should select", func(source v1beta1.ProviderType, warm bool, name string) {
+		transfer, err := selectDiskTransfer(migration(source, warm))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(transfer.Name()).To(Equal(name))
+	},
+		ginkgo.Entry("virt-v2v for ova", v1beta1.Ova, false, "VirtV2v"),
+		ginkgo.Entry("virt-v2v for cold vsphere", v1beta1.VSphere, false, "VirtV2v"),
+		ginkgo.Entry("data volumes for warm vsphere", v1beta1.VSphere, true, "DataVolume"),
+		ginkgo.Entry("data volumes for ovirt", v1beta1.OVirt, false, "DataVolume"),
+	)
+
+	ginkgo.It("should fail without a transfer", func() {
+		DiskTransfers = []DiskTransferFactory{NewVirtV2vTransfer}
+		_, err := selectDiskTransfer(migration(v1beta1.OVirt, false))
+		Expect(err).To(HaveOccurred())
+	})
+
+	ginkgo.It("should select PVCs without CDI on the destination", func() {
+		DiskTransfers = []DiskTransferFactory{
+			NewPersistentVolumeClaimTransfer,
+			NewDataVolumeTransfer,
+		}
+		for _, withCDI := range []bool{true, false} {
+			scheme := runtime.NewScheme()
+			_ = core.AddToScheme(scheme)
+			mapper := meta.NewDefaultRESTMapper(nil)
+			if withCDI {
+				_ = cdi.AddToScheme(scheme)
+				mapper.Add(cdi.SchemeGroupVersion.WithKind("DataVolume"), meta.RESTScopeNamespace)
+			}
+			m := migration(v1beta1.VSphere, false)
+			m.kubevirt = KubeVirt{Context: m.Context}
+			m.Destination.Client = fake.NewClientBuilder().WithScheme(scheme).WithRESTMapper(mapper).Build()
+			transfer, err := selectDiskTransfer(m)
+			Expect(err).ToNot(HaveOccurred())
+			if withCDI {
+				Expect(transfer.Name()).To(Equal("DataVolume"))
+			} else {
+				Expect(transfer.Name()).To(Equal("PersistentVolumeClaim"))
+			}
+		}
+	})
+
+	ginkgo.It("should build the PVC of a DataVolume", func() {
+		storageClass := "nfs"
+		dv := &cdi.DataVolume{}
+		dv.GenerateName = "plan-vm-1-"
+		dv.Labels = map[string]string{"vmID": "vm-1"}
+		dv.Spec.Storage = &cdi.StorageSpec{
+			StorageClassName: &storageClass,
+			Resources: core.VolumeResourceRequirements{
+				Requests: core.ResourceList{core.ResourceStorage: resource.MustParse("1Gi")},
+			},
+		}
+		pvc := persistentVolumeClaim(dv)
+		Expect(pvc.GenerateName).To(Equal(dv.GenerateName))
+		Expect(pvc.Labels).To(Equal(dv.Labels))
+		Expect(*pvc.Spec.StorageClassName).To(Equal(storageClass))
+		Expect(pvc.Spec.AccessModes).To(ConsistOf(core.ReadWriteOnce))
+		Expect(pvc.Spec.Resources.Requests.Storage().String()).To(Equal("1Gi"))
+	})
+
+	ginkgo.It("should defer the DataVolumes copied at cutover", func() {
+		dvs := []cdi.DataVolume{{}, {}}
+		dvs[0].Name = "warm"
+		dvs[1].Name = "cold"
+		dvs[1].Annotations = map[string]string{planbase.AnnColdCopy: "true"}
+		warm, cold := splitColdCopy(dvs)
+		Expect(warm).To(HaveLen(1))
+		Expect(warm[0].Name).To(Equal("warm"))
+		Expect(cold).To(HaveLen(1))
+		Expect(cold[0].Name).To(Equal("cold"))
+	})
+})
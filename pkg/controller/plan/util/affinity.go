@@ -0,0 +1,132 @@
+package util
+
+import (
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cnv "kubevirt.io/api/core/v1"
+)
+
+// Label of the source VM (ID) on the VM template.
+// Selected by the translated affinity rules.
+const SourceVMLabel = "forklift.konveyor.io/source-vm"
+
+// Topology of the translated affinity rules.
+const HostnameTopologyKey = "kubernetes.io/hostname"
+
+// Affinity rule kinds.
+const (
+	AffinityKind     = "affinity"
+	AntiAffinityKind = "anti-affinity"
+	VMHostKind       = "vm-host"
+	DependencyKind   = "dependency"
+)
+
+// VM affinity rule of the source cluster
+// (vSphere DRS rule or oVirt affinity group).
+type AffinityRule struct {
+	// Rule name.
+	Name string
+	// Rule kind.
+	Kind string
+	// Enforced by the scheduler.
+	Mandatory bool
+	// IDs of the member VMs.
+	VMs []string
+}
+
+// Whether the rule can be translated to pod (anti-)affinity.
+func (r *AffinityRule) Mappable() bool {
+	return r.Kind == AffinityKind || r.Kind == AntiAffinityKind
+}
+
+// Member VMs migrated by the plan other than the VM.
+func (r *AffinityRule) Peers(plan *api.Plan, vmID string) (peers []string) {
+	for _, id := range r.VMs {
+		if id == vmID {
+			continue
+		}
+		for _, vm := range plan.Spec.VMs {
+			if vm.ID == id {
+				peers = append(peers, id)
+				break
+			}
+		}
+	}
+	return
+}
+
+// Translate the affinity rules of the VM to pod (anti-)affinity
+// with the VMs migrated by the plan. Mandatory rules are required
+// during scheduling and the others are preferred. The VM is selected
+// by its own affinity so that the first VM of the rule is scheduled.
+func ApplyAffinity(object *cnv.VirtualMachineSpec, plan *api.Plan, vmID string, rules []AffinityRule) {
+	mapped := false
+	for i := range rules {
+		rule := &rules[i]
+		if !rule.Mappable() {
+			continue
+		}
+		peers := rule.Peers(plan, vmID)
+		if len(peers) == 0 {
+			continue
+		}
+		if object.Template.Spec.Affinity == nil {
+			object.Template.Spec.Affinity = &core.Affinity{}
+		}
+		affinity := object.Template.Spec.Affinity
+		switch rule.Kind {
+		case AffinityKind:
+			if affinity.PodAffinity == nil {
+				affinity.PodAffinity = &core.PodAffinity{}
+			}
+			term := affinityTerm(append([]string{vmID}, peers...))
+			if rule.Mandatory {
+				affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(
+					affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
+					term)
+			} else {
+				affinity.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
+					affinity.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+					core.WeightedPodAffinityTerm{Weight: 100, PodAffinityTerm: term})
+			}
+		case AntiAffinityKind:
+			if affinity.PodAntiAffinity == nil {
+				affinity.PodAntiAffinity = &core.PodAntiAffinity{}
+			}
+			term := affinityTerm(peers)
+			if rule.Mandatory {
+				affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(
+					affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
+					term)
+			} else {
+				affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
+					affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+					core.WeightedPodAffinityTerm{Weight: 100, PodAffinityTerm: term})
+			}
+		}
+		mapped = true
+	}
+	if mapped {
+		if object.Template.ObjectMeta.Labels == nil {
+			object.Template.ObjectMeta.Labels = map[string]string{}
+		}
+		object.Template.ObjectMeta.Labels[SourceVMLabel] = vmID
+	}
+}
+
+// Pod affinity term selecting the source VMs on the same host.
+func affinityTerm(vmIDs []string) core.PodAffinityTerm {
+	return core.PodAffinityTerm{
+		LabelSelector: &meta.LabelSelector{
+			MatchExpressions: []meta.LabelSelectorRequirement{
+				{
+					Key:      SourceVMLabel,
+					Operator: meta.LabelSelectorOpIn,
+					Values:   vmIDs,
+				},
+			},
+		},
+		TopologyKey: HostnameTopologyKey,
+	}
+}
@@ -0,0 +1,60 @@
+package util
+
+import (
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	cnv "kubevirt.io/api/core/v1"
+)
+
+var _ = Describe("Plan/affinity", func() {
+	migrated := func(ids ...string) *api.Plan {
+		p := &api.Plan{}
+		for _, id := range ids {
+			p.Spec.VMs = append(p.Spec.VMs, plan.VM{Ref: ref.Ref{ID: id}})
+		}
+		return p
+	}
+	vmSpec := func() *cnv.VirtualMachineSpec {
+		return &cnv.VirtualMachineSpec{Template: &cnv.VirtualMachineInstanceTemplateSpec{}}
+	}
+
+	It("should require anti-affinity with the migrated peers of a mandatory rule", func() {
+		spec := vmSpec()
+		rules := []AffinityRule{
+			{Name: "separate", Kind: AntiAffinityKind, Mandatory: true, VMs: []string{"vm-1", "vm-2", "vm-3"}},
+		}
+		ApplyAffinity(spec, migrated("vm-1", "vm-2"), "vm-1", rules)
+		affinity := spec.Template.Spec.Affinity
+		Expect(affinity.PodAffinity).To(BeNil())
+		terms := affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+		Expect(terms).To(HaveLen(1))
+		Expect(terms[0].LabelSelector.MatchExpressions[0].Values).To(Equal([]string{"vm-2"}))
+		Expect(terms[0].TopologyKey).To(Equal(HostnameTopologyKey))
+		Expect(spec.Template.ObjectMeta.Labels[SourceVMLabel]).To(Equal("vm-1"))
+	})
+
+	It("should prefer affinity with the VM and its peers of an optional rule", func() {
+		spec := vmSpec()
+		rules := []AffinityRule{
+			{Name: "together", Kind: AffinityKind, VMs: []string{"vm-1", "vm-2"}},
+		}
+		ApplyAffinity(spec, migrated("vm-1", "vm-2"), "vm-1", rules)
+		terms := spec.Template.Spec.Affinity.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+		Expect(terms).To(HaveLen(1))
+		Expect(terms[0].PodAffinityTerm.LabelSelector.MatchExpressions[0].Values).To(Equal([]string{"vm-1", "vm-2"}))
+	})
+
+	It("should not map the rules without peers or not mappable", func() {
+		spec := vmSpec()
+		rules := []AffinityRule{
+			{Name: "alone", Kind: AntiAffinityKind, VMs: []string{"vm-1", "vm-3"}},
+			{Name: "host", Kind: VMHostKind, VMs: []string{"vm-1", "vm-2"}},
+		}
+		ApplyAffinity(spec, migrated("vm-1", "vm-2"), "vm-1", rules)
+		Expect(spec.Template.Spec.Affinity).To(BeNil())
+		Expect(spec.Template.ObjectMeta.Labels).To(BeEmpty())
+	})
+})
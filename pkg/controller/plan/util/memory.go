@@ -0,0 +1,59 @@
+package util
+
+import (
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	cnv "kubevirt.io/api/core/v1"
+)
+
+// Memory allocation of the source VM.
+type MemoryAllocation struct {
+	// Memory size (bytes).
+	Size int64
+	// Reservation (bytes).
+	Reservation int64
+	// Limit (bytes). Zero when unlimited.
+	Limit int64
+	// The reservation is locked to the memory size.
+	Locked bool
+}
+
+// Apply the sizing policy to the (memory mapped) VM spec.
+// The memory is fully requested when backed by hugepages.
+// A limit below the memory size cannot be set since the
+// guest memory must fit in the limit.
+func (r *MemoryAllocation) ApplyTo(object *cnv.VirtualMachineSpec, settings *plan.MemorySettings) {
+	domain := &object.Template.Spec.Domain
+	if domain.Memory != nil && domain.Memory.Hugepages != nil {
+		return
+	}
+	if request := r.Request(settings); request < r.Size {
+		if domain.Resources.Requests == nil {
+			domain.Resources.Requests = core.ResourceList{}
+		}
+		domain.Resources.Requests[core.ResourceMemory] = *resource.NewQuantity(request, resource.BinarySI)
+	}
+	if settings.Reserved() && r.Limit >= r.Size {
+		if domain.Resources.Limits == nil {
+			domain.Resources.Limits = core.ResourceList{}
+		}
+		domain.Resources.Limits[core.ResourceMemory] = *resource.NewQuantity(r.Limit, resource.BinarySI)
+	}
+}
+
+// The memory request (bytes) as set by the sizing policy.
+// The memory is fully requested when the policy is not
+// set or the reservation is locked.
+func (r *MemoryAllocation) Request(settings *plan.MemorySettings) (request int64) {
+	request = r.Size
+	if settings == nil || r.Locked {
+		return
+	}
+	request = r.Size * int64(100-settings.Overcommit) / 100
+	if settings.Reserved() && r.Reservation > 0 {
+		request = r.Reservation
+	}
+	request = min(max(request, r.Reservation), r.Size)
+	return
+}
@@ -0,0 +1,73 @@
+package util
+
+import (
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	cnv "kubevirt.io/api/core/v1"
+)
+
+var _ = Describe("Plan/memory", func() {
+	const gi = int64(1024 * 1024 * 1024)
+	vmSpec := func() *cnv.VirtualMachineSpec {
+		guest := resource.MustParse("8Gi")
+		return &cnv.VirtualMachineSpec{
+			Template: &cnv.VirtualMachineInstanceTemplateSpec{
+				Spec: cnv.VirtualMachineInstanceSpec{
+					Domain: cnv.DomainSpec{
+						Memory: &cnv.Memory{Guest: &guest},
+					},
+				},
+			},
+		}
+	}
+
+	DescribeTable("should size the memory", func(allocation MemoryAllocation, settings *plan.MemorySettings, request, limit string) {
+		spec := vmSpec()
+		allocation.Size = 8 * gi
+		allocation.ApplyTo(spec, settings)
+		resources := spec.Template.Spec.Domain.Resources
+		if request == "" {
+			Expect(resources.Requests).ToNot(HaveKey(core.ResourceMemory))
+		} else {
+			Expect(resources.Requests.Memory().String()).To(Equal(request))
+		}
+		if limit == "" {
+			Expect(resources.Limits).ToNot(HaveKey(core.ResourceMemory))
+		} else {
+			Expect(resources.Limits.Memory().String()).To(Equal(limit))
+		}
+	},
+		Entry("not set", MemoryAllocation{Reservation: 2 * gi}, nil, "", ""),
+		Entry("full", MemoryAllocation{Reservation: 2 * gi}, &plan.MemorySettings{Policy: plan.MemoryFull}, "", ""),
+		Entry("full with overcommit",
+			MemoryAllocation{},
+			&plan.MemorySettings{Policy: plan.MemoryFull, Overcommit: 25}, "6Gi", ""),
+		Entry("overcommit not below the reservation",
+			MemoryAllocation{Reservation: 7 * gi},
+			&plan.MemorySettings{Policy: plan.MemoryFull, Overcommit: 50}, "7Gi", ""),
+		Entry("reservation",
+			MemoryAllocation{Reservation: 2 * gi, Limit: 16 * gi},
+			&plan.MemorySettings{Policy: plan.MemoryReservation}, "2Gi", "16Gi"),
+		Entry("reservation not set",
+			MemoryAllocation{},
+			&plan.MemorySettings{Policy: plan.MemoryReservation, Overcommit: 50}, "4Gi", ""),
+		Entry("limit below the memory size",
+			MemoryAllocation{Reservation: 2 * gi, Limit: 4 * gi},
+			&plan.MemorySettings{Policy: plan.MemoryReservation}, "2Gi", ""),
+		Entry("locked",
+			MemoryAllocation{Reservation: 8 * gi, Locked: true},
+			&plan.MemorySettings{Policy: plan.MemoryReservation, Overcommit: 50}, "", ""),
+	)
+
+	It("should fully request the memory backed by hugepages", func() {
+		spec := vmSpec()
+		placement := CPUPlacement{HugePageSize: "1Gi"}
+		placement.ApplyTo(spec)
+		allocation := MemoryAllocation{Size: 8 * gi, Reservation: 2 * gi}
+		allocation.ApplyTo(spec, &plan.MemorySettings{Policy: plan.MemoryReservation})
+		Expect(spec.Template.Spec.Domain.Resources.Requests).ToNot(HaveKey(core.ResourceMemory))
+	})
+})
@@ -0,0 +1,43 @@
+package util
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	cnv "kubevirt.io/api/core/v1"
+)
+
+// CPU placement of the source VM.
+type CPUPlacement struct {
+	// Dedicated (pinned) CPUs.
+	Dedicated bool
+	// NUMA topology defined.
+	NUMA bool
+	// Hugepage size (quantity). Empty when not backed by hugepages.
+	HugePageSize string
+}
+
+// Apply the placement to the (CPU and memory mapped) VM spec.
+// The NUMA topology is passed through only with dedicated CPUs
+// and hugepages, as required by KubeVirt.
+func (r *CPUPlacement) ApplyTo(object *cnv.VirtualMachineSpec) {
+	domain := &object.Template.Spec.Domain
+	if domain.CPU == nil {
+		domain.CPU = &cnv.CPU{}
+	}
+	if r.Dedicated {
+		domain.CPU.DedicatedCPUPlacement = true
+	}
+	if r.HugePageSize != "" {
+		if domain.Memory == nil {
+			domain.Memory = &cnv.Memory{}
+		}
+		domain.Memory.Hugepages = &cnv.Hugepages{PageSize: r.HugePageSize}
+	}
+	if r.NUMA && domain.CPU.DedicatedCPUPlacement && domain.Memory != nil && domain.Memory.Hugepages != nil {
+		domain.CPU.NUMA = &cnv.NUMA{GuestMappingPassthrough: &cnv.NUMAGuestMappingPassthrough{}}
+	}
+}
+
+// Hugepage size (quantity) of the page size in KiB.
+func HugePageSize(kib int64) string {
+	return resource.NewQuantity(kib*1024, resource.BinarySI).String()
+}
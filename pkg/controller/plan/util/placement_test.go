@@ -0,0 +1,51 @@
+package util
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/resource"
+	cnv "kubevirt.io/api/core/v1"
+)
+
+var _ = Describe("Plan/placement", func() {
+	vmSpec := func() *cnv.VirtualMachineSpec {
+		guest := resource.MustParse("4Gi")
+		return &cnv.VirtualMachineSpec{
+			Template: &cnv.VirtualMachineInstanceTemplateSpec{
+				Spec: cnv.VirtualMachineInstanceSpec{
+					Domain: cnv.DomainSpec{
+						CPU:    &cnv.CPU{Sockets: 2, Cores: 2},
+						Memory: &cnv.Memory{Guest: &guest},
+					},
+				},
+			},
+		}
+	}
+
+	It("should pass the NUMA topology through with dedicated CPUs and hugepages", func() {
+		spec := vmSpec()
+		placement := CPUPlacement{Dedicated: true, NUMA: true, HugePageSize: "1Gi"}
+		placement.ApplyTo(spec)
+		domain := spec.Template.Spec.Domain
+		Expect(domain.CPU.DedicatedCPUPlacement).To(BeTrue())
+		Expect(domain.Memory.Hugepages.PageSize).To(Equal("1Gi"))
+		Expect(domain.CPU.NUMA.GuestMappingPassthrough).ToNot(BeNil())
+	})
+
+	It("should not pass the NUMA topology through without hugepages", func() {
+		spec := vmSpec()
+		placement := CPUPlacement{Dedicated: true, NUMA: true}
+		placement.ApplyTo(spec)
+		domain := spec.Template.Spec.Domain
+		Expect(domain.CPU.DedicatedCPUPlacement).To(BeTrue())
+		Expect(domain.Memory.Hugepages).To(BeNil())
+		Expect(domain.CPU.NUMA).To(BeNil())
+	})
+
+	DescribeTable("hugepage size", func(kib int64, size string) {
+		Expect(HugePageSize(kib)).To(Equal(size))
+	},
+		Entry("2Mi", int64(2048), "2Mi"),
+		Entry("1Gi", int64(1048576), "1Gi"),
+	)
+})
@@ -0,0 +1,138 @@
+package util
+
+import (
+	"context"
+	"math"
+	"strconv"
+
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	"github.com/kubev2v/forklift/pkg/settings"
+	core "k8s.io/api/core/v1"
+	storage "k8s.io/api/storage/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Storage class annotations overriding the overhead settings.
+const (
+	// Filesystem overhead percentage.
+	AnnFilesystemOverhead = "forklift.konveyor.io/filesystem-overhead"
+	// Block overhead quantity.
+	AnnBlockOverhead = "forklift.konveyor.io/block-overhead"
+)
+
+// Disk image formats.
+const (
+	FormatRaw   = "raw"
+	FormatQcow2 = "qcow2"
+)
+
+// qcow2 layout used to estimate the metadata overhead.
+const (
+	qcow2ClusterSize = 64 * 1024
+	// L2 table entry and refcount entry per cluster.
+	qcow2ClusterMetadata = 8 + 2
+)
+
+// Sizing of target volumes.
+type Sizing struct {
+	// Filesystem overhead percentage.
+	FilesystemOverhead int
+	// Block overhead in bytes.
+	BlockOverhead int64
+}
+
+// Sizing from the migration settings.
+func DefaultSizing() Sizing {
+	return Sizing{
+		FilesystemOverhead: settings.Settings.FileSystemOverhead,
+		BlockOverhead:      settings.Settings.BlockOverhead,
+	}
+}
+
+// Sizing for the storage class. The storage class
+// annotations take precedence over the migration settings.
+func StorageClassSizing(storageClass *storage.StorageClass) (sizing Sizing, err error) {
+	sizing = DefaultSizing()
+	if storageClass == nil {
+		return
+	}
+	if value, found := storageClass.Annotations[AnnFilesystemOverhead]; found {
+		overhead, pErr := strconv.Atoi(value)
+		if pErr != nil || overhead < 0 || overhead >= 100 {
+			err = liberr.New(
+				"invalid filesystem overhead.",
+				"storageClass",
+				storageClass.Name,
+				"overhead",
+				value)
+			return
+		}
+		sizing.FilesystemOverhead = overhead
+	}
+	if value, found := storageClass.Annotations[AnnBlockOverhead]; found {
+		quantity, pErr := resource.ParseQuantity(value)
+		if pErr != nil || quantity.Sign() < 0 {
+			err = liberr.New(
+				"invalid block overhead.",
+				"storageClass",
+				storageClass.Name,
+				"overhead",
+				value)
+			return
+		}
+		sizing.BlockOverhead = quantity.Value()
+	}
+	return
+}
+
+// Sizing for the named storage class on the cluster.
+// The default sizing is used when the storage class is not found.
+func GetStorageClassSizing(c client.Client, name string) (sizing Sizing, err error) {
+	storageClass := &storage.StorageClass{}
+	err = c.Get(context.TODO(), client.ObjectKey{Name: name}, storageClass)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			err = nil
+			sizing = DefaultSizing()
+		} else {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	sizing, err = StorageClassSizing(storageClass)
+	return
+}
+
+// Size of a volume created by Forklift to hold a disk. The image
+// size accounts for the format metadata and is aligned, then the
+// filesystem or block overhead of the volume mode is added.
+// Filesystem is assumed when the volume mode is not set.
+func (r Sizing) VolumeSize(virtualSize int64, format string, volumeMode *core.PersistentVolumeMode) (size int64) {
+	size = ImageSize(virtualSize, format)
+	if volumeMode == nil || *volumeMode == core.PersistentVolumeFilesystem {
+		size = int64(math.Ceil(float64(size) / (1 - float64(r.FilesystemOverhead)/100)))
+	} else {
+		size += r.BlockOverhead
+	}
+	return
+}
+
+// Size of a DataVolume to hold a disk. CDI adds the filesystem
+// overhead when the DataVolume uses the storage API, so only the
+// format metadata and the alignment are accounted for.
+func DataVolumeSize(virtualSize int64, format string) int64 {
+	return ImageSize(virtualSize, format)
+}
+
+// Aligned size of a disk image in the format.
+func ImageSize(virtualSize int64, format string) (size int64) {
+	size = virtualSize
+	if format == FormatQcow2 {
+		clusters := RoundUp(virtualSize, qcow2ClusterSize) / qcow2ClusterSize
+		size += clusters*qcow2ClusterMetadata + DefaultAlignBlockSize
+	}
+	size = RoundUp(size, DefaultAlignBlockSize)
+	return
+}
@@ -0,0 +1,56 @@
+package util
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	storage "k8s.io/api/storage/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Plan/sizing", func() {
+	const (
+		MiB = 1024 * 1024
+		GiB = 1024 * MiB
+	)
+	filesystem := core.PersistentVolumeFilesystem
+	block := core.PersistentVolumeBlock
+	sizing := Sizing{FilesystemOverhead: 10, BlockOverhead: 2 * MiB}
+
+	DescribeTable("volume size", func(virtualSize int64, format string, volumeMode *core.PersistentVolumeMode, size int64) {
+		Expect(sizing.VolumeSize(virtualSize, format, volumeMode)).Should(Equal(size))
+	},
+		Entry("aligned raw on block", int64(GiB), FormatRaw, &block, int64(GiB+2*MiB)),
+		Entry("unaligned raw on block", int64(GiB+1), FormatRaw, &block, int64(GiB+3*MiB)),
+		Entry("raw on filesystem", int64(9*MiB), FormatRaw, &filesystem, int64(10*MiB)),
+		Entry("raw without volume mode", int64(9*MiB), FormatRaw, nil, int64(10*MiB)),
+		Entry("qcow2 on block", int64(GiB), FormatQcow2, &block, int64(GiB+4*MiB)),
+	)
+
+	DescribeTable("data volume size", func(virtualSize int64, format string, size int64) {
+		Expect(DataVolumeSize(virtualSize, format)).Should(Equal(size))
+	},
+		Entry("aligned raw", int64(GiB), FormatRaw, int64(GiB)),
+		Entry("unaligned raw", int64(GiB-1), FormatRaw, int64(GiB)),
+		Entry("qcow2", int64(GiB), FormatQcow2, int64(GiB+2*MiB)),
+	)
+
+	It("should honor the storage class overhead", func() {
+		storageClass := &storage.StorageClass{
+			ObjectMeta: meta.ObjectMeta{
+				Name: "ceph-rbd-encrypted",
+				Annotations: map[string]string{
+					AnnFilesystemOverhead: "20",
+					AnnBlockOverhead:      "16Mi",
+				},
+			},
+		}
+		classSizing, err := StorageClassSizing(storageClass)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(classSizing).To(Equal(Sizing{FilesystemOverhead: 20, BlockOverhead: 16 * MiB}))
+
+		storageClass.Annotations[AnnFilesystemOverhead] = "100"
+		_, err = StorageClassSizing(storageClass)
+		Expect(err).To(HaveOccurred())
+	})
+})
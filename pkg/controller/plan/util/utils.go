@@ -6,8 +6,7 @@ import (
 	"unicode"
 
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
-	"github.com/kubev2v/forklift/pkg/settings"
-	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 // Disk alignment size used to align FS overhead,
@@ -29,17 +28,6 @@ func RoundUp(requestedSpace, multiple int64) int64 {
 	return int64(partitions) * multiple
 }
 
-func CalculateSpaceWithOverhead(requestedSpace int64, volumeMode *core.PersistentVolumeMode) int64 {
-	alignedSize := RoundUp(requestedSpace, DefaultAlignBlockSize)
-	var spaceWithOverhead int64
-	if *volumeMode == core.PersistentVolumeFilesystem {
-		spaceWithOverhead = int64(math.Ceil(float64(alignedSize) / (1 - float64(settings.Settings.FileSystemOverhead)/100)))
-	} else {
-		spaceWithOverhead = alignedSize + settings.Settings.BlockOverhead
-	}
-	return spaceWithOverhead
-}
-
 func GetBootDiskNumber(deviceString string) int {
 	deviceNumber := GetDeviceNumber(deviceString)
 	if deviceNumber == 0 {
@@ -64,4 +52,29 @@ func GetDeviceNumber(deviceString string) int {
 	return 0
 }
 
+// Sanitize a value to be used as a label value: characters other than
+// alphanumerics, '-', '_' and '.' are replaced by '-', the value is
+// truncated to 63 characters and trimmed to begin and end with an
+// alphanumeric. Empty when nothing is left.
+func LabelValue(value string) string {
+	sanitized := []rune{}
+	for _, r := range value {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)),
+			r == '-', r == '_', r == '.':
+			sanitized = append(sanitized, r)
+		default:
+			sanitized = append(sanitized, '-')
+		}
+		if len(sanitized) == validation.LabelValueMaxLength {
+			break
+		}
+	}
+	return strings.TrimFunc(
+		string(sanitized),
+		func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+}
+
 type HostsFunc func() (map[string]*api.Host, error)
@@ -1,6 +1,8 @@
 package util
 
 import (
+	"strings"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -20,4 +22,14 @@ var _ = Describe("Plan/utils", func() {
 		Entry("sd", "/dev/sd", 0),
 		Entry("test", "test", 0),
 	)
+	DescribeTable("label value", func(value string, sanitized string) {
+		Expect(LabelValue(value)).Should(Equal(sanitized))
+	},
+		Entry("valid", "cc-1001_a.b", "cc-1001_a.b"),
+		Entry("spaces", "Finance Dept", "Finance-Dept"),
+		Entry("trimmed", " (R&D) ", "R-D"),
+		Entry("non ascii", "café", "caf"),
+		Entry("truncated", strings.Repeat("a", 70), strings.Repeat("a", 63)),
+		Entry("nothing left", "***", ""),
+	)
 })
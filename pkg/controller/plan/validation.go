@@ -8,14 +8,30 @@ import (
 	"fmt"
 	"net"
 	"path"
+	"sort"
 	"strconv"
+	"strings"
 
 	k8snet "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
 	refapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
 	"github.com/kubev2v/forklift/pkg/controller/plan/adapter"
+	planbase "github.com/kubev2v/forklift/pkg/controller/plan/adapter/base"
 	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
 	"github.com/kubev2v/forklift/pkg/controller/provider/web"
+	azureweb "github.com/kubev2v/forklift/pkg/controller/provider/web/azure"
+	webbase "github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	catalogweb "github.com/kubev2v/forklift/pkg/controller/provider/web/catalog"
+	ec2web "github.com/kubev2v/forklift/pkg/controller/provider/web/ec2"
+	imageweb "github.com/kubev2v/forklift/pkg/controller/provider/web/image"
+	libvirtweb "github.com/kubev2v/forklift/pkg/controller/provider/web/libvirt"
+	nutanixweb "github.com/kubev2v/forklift/pkg/controller/provider/web/nutanix"
+	ocpweb "github.com/kubev2v/forklift/pkg/controller/provider/web/ocp"
+	openstackweb "github.com/kubev2v/forklift/pkg/controller/provider/web/openstack"
+	ovaweb "github.com/kubev2v/forklift/pkg/controller/provider/web/ova"
+	ovirtweb "github.com/kubev2v/forklift/pkg/controller/provider/web/ovirt"
+	vsphereweb "github.com/kubev2v/forklift/pkg/controller/provider/web/vsphere"
 	"github.com/kubev2v/forklift/pkg/controller/validation"
 	ocp "github.com/kubev2v/forklift/pkg/lib/client/openshift"
 	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
@@ -26,13 +42,17 @@ import (
 	batchv1 "k8s.io/api/batch/v1"
 	core "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
+	storage "k8s.io/api/storage/v1"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	k8smeta "k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/utils/ptr"
+	cnv "kubevirt.io/api/core/v1"
+	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -40,7 +60,9 @@ import (
 const (
 	WarmMigrationNotReady         = "WarmMigrationNotReady"
 	NamespaceNotValid             = "NamespaceNotValid"
+	NamespaceTemplateNotValid     = "NamespaceTemplateNotValid"
 	TransferNetNotValid           = "TransferNetworkNotValid"
+	TestSpecNotValid              = "TestSpecNotValid"
 	NetRefNotValid                = "NetworkMapRefNotValid"
 	NetMapNotReady                = "NetworkMapNotReady"
 	DsMapNotReady                 = "StorageMapNotReady"
@@ -50,10 +72,15 @@ const (
 	VMAlreadyExists               = "VMAlreadyExists"
 	VMNetworksNotMapped           = "VMNetworksNotMapped"
 	VMStorageNotMapped            = "VMStorageNotMapped"
+	VMDevicesNotMapped            = "VMDevicesNotMapped"
+	VMRDMDisksNotMapped           = "VMRDMDisksNotMapped"
+	DeviceNotAvailable            = "DeviceNotAvailable"
 	VMStorageNotSupported         = "VMStorageNotSupported"
 	VMMultiplePodNetworkMappings  = "VMMultiplePodNetworkMappings"
 	VMMissingGuestIPs             = "VMMissingGuestIPs"
 	VMMissingChangedBlockTracking = "VMMissingChangedBlockTracking"
+	VMIndependentDisks            = "VMIndependentDisks"
+	VMBootDevicesNotMigrated      = "VMBootDevicesNotMigrated"
 	HostNotReady                  = "HostNotReady"
 	DuplicateVM                   = "DuplicateVM"
 	SharedDisks                   = "SharedDisks"
@@ -62,6 +89,18 @@ const (
 	HookNotValid                  = "HookNotValid"
 	HookNotReady                  = "HookNotReady"
 	HookStepNotValid              = "HookStepNotValid"
+	GroupNotValid                 = "GroupNotValid"
+	GroupPendingApproval          = "GroupPendingApproval"
+	ConsistencyWindowExceeded     = "ConsistencyWindowExceeded"
+	ReplicationNotValid           = "ReplicationNotValid"
+	RPOThresholdExceeded          = "RPOThresholdExceeded"
+	GatePendingApproval           = "GatePendingApproval"
+	ApprovalGateNotValid          = "ApprovalGateNotValid"
+	ConversionPodNotValid         = "ConversionPodNotValid"
+	ConversionNotValid            = "ConversionNotValid"
+	PVCMetadataNotValid           = "PVCMetadataNotValid"
+	BackupNotValid                = "BackupNotValid"
+	AttributeMapNotValid          = "AttributeMapNotValid"
 	Executing                     = "Executing"
 	Succeeded                     = "Succeeded"
 	Failed                        = "Failed"
@@ -74,8 +113,28 @@ const (
 	ValidatingVDDK                = "ValidatingVDDK"
 	VDDKInitImageNotReady         = "VDDKInitImageNotReady"
 	VDDKInitImageUnavailable      = "VDDKInitImageUnavailable"
+	StorageClassNotValid          = "StorageClassNotValid"
+	StorageClassNotSupported      = "StorageClassNotSupported"
+	StorageClassNotLiveMigratable = "StorageClassNotLiveMigratable"
+	NetAttachDefNotValid          = "NetworkAttachmentDefinitionNotValid"
+	VMCPUModelNotSupported        = "VMCPUModelNotSupported"
+	VMTPMNotSupported             = "VMTPMNotSupported"
+	VMGuestOSNotSupported         = "VMGuestOSNotSupported"
+	VMGuestOSNeedsFlags           = "VMGuestOSNeedsFlags"
+	VMGuestOSDeprecated           = "VMGuestOSDeprecated"
+	GuestOSOverridesNotValid      = "GuestOSOverridesNotValid"
+	VMMacConflicts                = "VMMacConflicts"
+	VMNetworksIgnored             = "VMNetworksIgnored"
+	VMAffinityRulesNotMapped      = "VMAffinityRulesNotMapped"
+	DestinationCapacityShortfall  = "DestinationCapacityShortfall"
+	TransferNetNotReachable       = "TransferNetworkNotReachable"
+	SnapshotsNotRemoved           = "SnapshotsNotRemoved"
+	BackupNotCreated              = "BackupNotCreated"
 )
 
+// KubeVirt feature gate enabling the persistent VM state (TPM and EFI).
+const PersistentStateFeatureGate = "VMPersistentState"
+
 // Categories
 const (
 	Required = libcnd.Required
@@ -96,9 +155,11 @@ const (
 	NotValid                    = "NotValid"
 	Modified                    = "Modified"
 	UserRequested               = "UserRequested"
+	DependencyNotSucceeded      = "DependencyNotSucceeded"
 	InMaintenanceMode           = "InMaintenanceMode"
 	MissingGuestInfo            = "MissingGuestInformation"
 	MissingChangedBlockTracking = "MissingChangedBlockTracking"
+	NotSufficient               = "NotSufficient"
 )
 
 // Statuses
@@ -111,6 +172,15 @@ const (
 	Shareable = "shareable"
 )
 
+// Network types
+const (
+	Pod     = "pod"
+	Multus  = "multus"
+	SRIOV   = "sriov"
+	UDN     = "udn"
+	Ignored = "ignored"
+)
+
 // Validate the plan resource.
 func (r *Reconciler) validate(plan *api.Plan) error {
 	// Provider.
@@ -136,6 +206,10 @@ func (r *Reconciler) validate(plan *api.Plan) error {
 		return err
 	}
 
+	if err := r.validateNamespaceTemplate(plan); err != nil {
+		return err
+	}
+
 	if err := r.validateNetworkMap(plan); err != nil {
 		return err
 	}
@@ -148,6 +222,10 @@ func (r *Reconciler) validate(plan *api.Plan) error {
 		return err
 	}
 
+	if err := r.validateReplication(plan); err != nil {
+		return err
+	}
+
 	if err := r.validateVM(plan); err != nil {
 		return err
 	}
@@ -156,10 +234,38 @@ func (r *Reconciler) validate(plan *api.Plan) error {
 		return err
 	}
 
+	if err := r.validateDestination(plan); err != nil {
+		return err
+	}
+
 	if err := r.validateHooks(plan); err != nil {
 		return err
 	}
 
+	if err := r.validateTestSpec(plan); err != nil {
+		return err
+	}
+
+	if err := r.validateGroups(plan); err != nil {
+		return err
+	}
+
+	if err := r.validateApprovalGates(plan); err != nil {
+		return err
+	}
+
+	if err := r.validateConversionPod(plan); err != nil {
+		return err
+	}
+
+	if err := r.validateConversion(plan); err != nil {
+		return err
+	}
+
+	if err := r.validateAttributeMap(plan); err != nil {
+		return err
+	}
+
 	if err := r.validateVddkImage(plan); err != nil {
 		return err
 	}
@@ -174,6 +280,14 @@ func (r *Reconciler) validate(plan *api.Plan) error {
 		return err
 	}
 
+	// Validate PVC metadata templates
+	if err := r.validatePVCMetadata(plan); err != nil {
+		return err
+	}
+
+	// Validate backup settings
+	r.validateBackup(plan)
+
 	// Validate volume name template
 	if err := r.validateVolumeNameTemplate(plan); err != nil {
 		return err
@@ -205,6 +319,80 @@ func (r *Reconciler) validatePVCNameTemplate(plan *api.Plan) error {
 	return nil
 }
 
+// Validate the PVC labels and annotations: the keys are
+// qualified names and the templates render valid values.
+func (r *Reconciler) validatePVCMetadata(plan *api.Plan) error {
+	metadata := plan.Spec.PVCMetadata
+	if metadata == nil {
+		return nil
+	}
+	notValid := libcnd.Condition{
+		Type:     PVCMetadataNotValid,
+		Status:   True,
+		Reason:   NotValid,
+		Category: api.CategoryCritical,
+		Message:  "PVC labels or annotations are not valid.",
+		Items:    []string{},
+	}
+	testData := api.PVCNameTemplateData{
+		VmName:        "test-vm",
+		PlanName:      "test-plan",
+		DiskIndex:     0,
+		RootDiskIndex: 0,
+		FileName:      "[test07_ds1] test_sp/test-000001.vmdk",
+	}
+	validate := func(kind string, templates map[string]string, isValidValue func(string) []string) {
+		for key, tmpl := range templates {
+			reasons := k8svalidation.IsQualifiedName(key)
+			value, err := templateutil.ExecuteTemplate(tmpl, testData)
+			if err != nil {
+				reasons = append(reasons, err.Error())
+			} else if isValidValue != nil {
+				reasons = append(reasons, isValidValue(value)...)
+			}
+			if len(reasons) > 0 {
+				notValid.Items = append(
+					notValid.Items,
+					fmt.Sprintf("%s %s: %s", kind, key, strings.Join(reasons, "; ")))
+			}
+		}
+	}
+	validate("label", metadata.Labels, k8svalidation.IsValidLabelValue)
+	validate("annotation", metadata.Annotations, nil)
+	if len(notValid.Items) > 0 {
+		sort.Strings(notValid.Items)
+		plan.Status.SetCondition(notValid)
+	}
+
+	return nil
+}
+
+// Validate the backup settings. The policy is
+// the value of the backup policy label.
+func (r *Reconciler) validateBackup(plan *api.Plan) {
+	backup := plan.Spec.Backup
+	if backup == nil {
+		return
+	}
+	reasons := k8svalidation.IsValidLabelValue(backup.Policy)
+	if backup.Policy == "" {
+		reasons = append(reasons, "policy must be specified")
+	}
+	if backup.TTL != nil && backup.TTL.Duration < 0 {
+		reasons = append(reasons, "ttl must not be negative")
+	}
+	if len(reasons) > 0 {
+		plan.Status.SetCondition(libcnd.Condition{
+			Type:     BackupNotValid,
+			Status:   True,
+			Reason:   NotValid,
+			Category: api.CategoryCritical,
+			Message:  "Backup settings are not valid.",
+			Items:    reasons,
+		})
+	}
+}
+
 func (r *Reconciler) validateVolumeNameTemplate(plan *api.Plan) error {
 	if err := r.IsValidVolumeNameTemplate(plan.Spec.VolumeNameTemplate); err != nil {
 		invalidPVCNameTemplate := libcnd.Condition{
@@ -320,6 +508,20 @@ func (r *Reconciler) validateWarmMigration(plan *api.Plan) (err error) {
 	return
 }
 
+// Validate that the replicated VMs are warm migrated.
+func (r *Reconciler) validateReplication(plan *api.Plan) (err error) {
+	if plan.Spec.Replication != nil && !plan.Spec.Warm {
+		plan.Status.SetCondition(libcnd.Condition{
+			Type:     ReplicationNotValid,
+			Status:   True,
+			Category: api.CategoryCritical,
+			Reason:   NotValid,
+			Message:  "Replication is supported by warm migrations only.",
+		})
+	}
+	return
+}
+
 // Validate the target namespace.
 func (r *Reconciler) validateTargetNamespace(plan *api.Plan) (err error) {
 	newCnd := libcnd.Condition{
@@ -340,6 +542,45 @@ func (r *Reconciler) validateTargetNamespace(plan *api.Plan) (err error) {
 	return
 }
 
+// Validate the config map of the target namespace template.
+func (r *Reconciler) validateNamespaceTemplate(plan *api.Plan) (err error) {
+	template := plan.Spec.TargetNamespaceTemplate
+	if template == nil || template.ConfigMap == nil {
+		return
+	}
+	newCnd := libcnd.Condition{
+		Type:     NamespaceTemplateNotValid,
+		Status:   True,
+		Category: api.CategoryCritical,
+		Message:  "Target namespace template is not valid.",
+		Items:    []string{template.ConfigMap.Name},
+	}
+	configMap := &core.ConfigMap{}
+	err = r.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: plan.Namespace,
+			Name:      template.ConfigMap.Name,
+		},
+		configMap)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			err = nil
+			newCnd.Reason = NotFound
+			plan.Status.SetCondition(newCnd)
+			return
+		}
+		err = liberr.Wrap(err)
+		return
+	}
+	if mErr := mergeNamespaceTemplate(template.DeepCopy(), configMap); mErr != nil {
+		newCnd.Reason = NotValid
+		newCnd.Message = "Target namespace template is not valid: " + mErr.Error()
+		plan.Status.SetCondition(newCnd)
+	}
+	return
+}
+
 // Validate network mapping ref.
 func (r *Reconciler) validateNetworkMap(plan *api.Plan) (err error) {
 	ref := plan.Spec.Map.Network
@@ -422,12 +663,245 @@ func (r *Reconciler) validateStorageMap(plan *api.Plan) (err error) {
 			Message:  "Map.Storage does not have Ready condition.",
 		})
 	}
+	if len(mp.Spec.Rules) > 0 && plan.Referenced.Provider.Source != nil {
+		var list []sourceStorage
+		list, err = r.sourceStorage(plan.Referenced.Provider.Source)
+		if err != nil {
+			return
+		}
+		expandStorageRules(mp, list)
+	}
 
 	plan.Referenced.Map.Storage = mp
 
 	return
 }
 
+// Expand the storage map rules into pairs for the
+// source storage not explicitly mapped.
+func expandStorageRules(mp *api.StorageMap, list []sourceStorage) {
+	for _, storage := range list {
+		if _, found := mp.FindStorage(storage.ref.ID); found {
+			continue
+		}
+		if _, found := mp.FindStorageByName(storage.ref.Name); found {
+			continue
+		}
+		rule, found := mp.FindStorageRule(storage.attributes)
+		if !found {
+			continue
+		}
+		mp.Spec.Map = append(
+			mp.Spec.Map,
+			api.StoragePair{
+				Source:        storage.ref,
+				Destination:   rule.Destination,
+				OffloadPlugin: rule.OffloadPlugin,
+			})
+		mp.Status.Refs.List = append(mp.Status.Refs.List, storage.ref)
+	}
+}
+
+// Source storage matched by storage map rules.
+type sourceStorage struct {
+	ref        refapi.Ref
+	attributes api.StorageAttributes
+}
+
+// List the source storage with the attributes matched by
+// storage map rules.
+func (r *Reconciler) sourceStorage(provider *api.Provider) (list []sourceStorage, err error) {
+	inventory, err := web.NewClient(provider)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	switch provider.Type() {
+	case api.VSphere:
+		datastores := []vsphereweb.Datastore{}
+		err = inventory.List(&datastores, webbase.Param{
+			Key:   webbase.DetailParam,
+			Value: "all",
+		})
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		for _, ds := range datastores {
+			list = append(
+				list,
+				sourceStorage{
+					ref:        refapi.Ref{ID: ds.ID, Name: ds.Name},
+					attributes: api.StorageAttributes{Name: ds.Name, Tier: ds.Type, Capacity: ds.Capacity},
+				})
+		}
+	case api.OVirt:
+		domains := []ovirtweb.StorageDomain{}
+		err = inventory.List(&domains, webbase.Param{
+			Key:   webbase.DetailParam,
+			Value: "all",
+		})
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		for _, sd := range domains {
+			list = append(
+				list,
+				sourceStorage{
+					ref:        refapi.Ref{ID: sd.ID, Name: sd.Name},
+					attributes: api.StorageAttributes{Name: sd.Name, Tier: sd.Storage.Type, Capacity: sd.Capacity},
+				})
+		}
+	case api.OpenStack:
+		types := []openstackweb.VolumeType{}
+		err = inventory.List(&types, webbase.Param{
+			Key:   webbase.DetailParam,
+			Value: "all",
+		})
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		for _, vt := range types {
+			list = append(
+				list,
+				sourceStorage{
+					ref:        refapi.Ref{ID: vt.ID, Name: vt.Name},
+					attributes: api.StorageAttributes{Name: vt.Name, Tier: vt.Name},
+				})
+		}
+	case api.Ova:
+		storage := []ovaweb.Storage{}
+		err = inventory.List(&storage, webbase.Param{
+			Key:   webbase.DetailParam,
+			Value: "all",
+		})
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		for _, st := range storage {
+			list = append(
+				list,
+				sourceStorage{
+					ref:        refapi.Ref{ID: st.ID, Name: st.Name},
+					attributes: api.StorageAttributes{Name: st.Name},
+				})
+		}
+	case api.Nutanix:
+		containers := []nutanixweb.StorageContainer{}
+		err = inventory.List(&containers, webbase.Param{
+			Key:   webbase.DetailParam,
+			Value: "all",
+		})
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		for _, sc := range containers {
+			list = append(
+				list,
+				sourceStorage{
+					ref:        refapi.Ref{ID: sc.ID, Name: sc.Name},
+					attributes: api.StorageAttributes{Name: sc.Name, Capacity: sc.Capacity},
+				})
+		}
+	case api.EC2:
+		types := []ec2web.VolumeType{}
+		err = inventory.List(&types, webbase.Param{
+			Key:   webbase.DetailParam,
+			Value: "all",
+		})
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		for _, vt := range types {
+			list = append(
+				list,
+				sourceStorage{
+					ref:        refapi.Ref{ID: vt.ID, Name: vt.Name},
+					attributes: api.StorageAttributes{Name: vt.Name, Tier: vt.Name},
+				})
+		}
+	case api.Azure:
+		skus := []azureweb.DiskSku{}
+		err = inventory.List(&skus, webbase.Param{
+			Key:   webbase.DetailParam,
+			Value: "all",
+		})
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		for _, sku := range skus {
+			list = append(
+				list,
+				sourceStorage{
+					ref:        refapi.Ref{ID: sku.ID, Name: sku.Name},
+					attributes: api.StorageAttributes{Name: sku.Name, Tier: sku.Name},
+				})
+		}
+	case api.Libvirt:
+		pools := []libvirtweb.Pool{}
+		err = inventory.List(&pools, webbase.Param{
+			Key:   webbase.DetailParam,
+			Value: "all",
+		})
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		for _, pool := range pools {
+			list = append(
+				list,
+				sourceStorage{
+					ref:        refapi.Ref{ID: pool.ID, Name: pool.Name},
+					attributes: api.StorageAttributes{Name: pool.Name, Capacity: pool.Capacity},
+				})
+		}
+	case api.Image:
+		storages := []imageweb.Storage{}
+		err = inventory.List(&storages, webbase.Param{
+			Key:   webbase.DetailParam,
+			Value: "all",
+		})
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		for _, storage := range storages {
+			list = append(
+				list,
+				sourceStorage{
+					ref:        refapi.Ref{ID: storage.ID, Name: storage.Name},
+					attributes: api.StorageAttributes{Name: storage.Name},
+				})
+		}
+	case api.Catalog:
+		storages := []catalogweb.Storage{}
+		err = inventory.List(&storages, webbase.Param{
+			Key:   webbase.DetailParam,
+			Value: "all",
+		})
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		for _, storage := range storages {
+			list = append(
+				list,
+				sourceStorage{
+					ref:        refapi.Ref{ID: storage.ID, Name: storage.Name},
+					attributes: api.StorageAttributes{Name: storage.Name},
+				})
+		}
+	}
+
+	return
+}
+
 // Validate listed VMs.
 func (r *Reconciler) validateVM(plan *api.Plan) error {
 	if plan.Status.HasCondition(Executing) {
@@ -473,6 +947,22 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 		Message:  "Target VM already exists.",
 		Items:    []string{},
 	}
+	networksIgnored := libcnd.Condition{
+		Type:     VMNetworksIgnored,
+		Status:   True,
+		Reason:   NotSupported,
+		Category: api.CategoryWarn,
+		Message:  "VM NICs mapped to ignored networks will not be migrated.",
+		Items:    []string{},
+	}
+	macConflicts := libcnd.Condition{
+		Type:     VMMacConflicts,
+		Status:   True,
+		Reason:   NotUnique,
+		Category: api.CategoryCritical,
+		Message:  "VM MAC address already in use on the destination network.",
+		Items:    []string{},
+	}
 	unmappedNetwork := libcnd.Condition{
 		Type:     VMNetworksNotMapped,
 		Status:   True,
@@ -529,6 +1019,22 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 		Message:  "Changed Block Tracking (CBT) has not been enabled on some VM. This feature is a prerequisite for VM warm migration.",
 		Items:    []string{},
 	}
+	independentDisks := libcnd.Condition{
+		Type:     VMIndependentDisks,
+		Status:   True,
+		Reason:   NotSupported,
+		Category: api.CategoryWarn,
+		Message:  "Independent disks are excluded from snapshots and cannot be copied incrementally. They will be copied once the source VM is powered off during the cutover, extending the downtime.",
+		Items:    []string{},
+	}
+	bootDevicesNotMigrated := libcnd.Condition{
+		Type:     VMBootDevicesNotMigrated,
+		Status:   True,
+		Reason:   NotSupported,
+		Category: api.CategoryWarn,
+		Message:  "VM boot order includes devices (CD-ROM or floppy) that will not be migrated. The VM will boot from the remaining devices in the source boot order.",
+		Items:    []string{},
+	}
 	pvcNameInvalid := libcnd.Condition{
 		Type:     NotValid,
 		Status:   True,
@@ -565,9 +1071,90 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 		Message:  "Duplicate targetName.",
 		Items:    []string{},
 	}
+	guestOSNotSupported := libcnd.Condition{
+		Type:     VMGuestOSNotSupported,
+		Status:   True,
+		Reason:   NotSupported,
+		Category: api.CategoryCritical,
+		Message:  "VM guest operating system is not supported. It may be accepted in the guest OS support config map.",
+		Items:    []string{},
+	}
+	guestOSNeedsFlags := libcnd.Condition{
+		Type:     VMGuestOSNeedsFlags,
+		Status:   True,
+		Reason:   NotSupported,
+		Category: api.CategoryAdvisory,
+		Message:  "VM guest operating system requires additional conversion options.",
+		Items:    []string{},
+	}
+	guestOSDeprecated := libcnd.Condition{
+		Type:     VMGuestOSDeprecated,
+		Status:   True,
+		Reason:   NotSupported,
+		Category: api.CategoryWarn,
+		Message:  "VM guest operating system is no longer supported by the conversion and may not boot. It may be blocked in the guest OS support config map.",
+		Items:    []string{},
+	}
+	guestOSOverrides, err := validation.GuestOSOverrides(r.Client)
+	if err != nil {
+		return err
+	}
+	if invalid := validation.InvalidGuestOSOverrides(guestOSOverrides); len(invalid) > 0 {
+		plan.Status.SetCondition(libcnd.Condition{
+			Type:     GuestOSOverridesNotValid,
+			Status:   True,
+			Reason:   NotValid,
+			Category: api.CategoryWarn,
+			Message: fmt.Sprintf(
+				"Guest OS support overrides ignored. The support level must be one of: %s.",
+				strings.Join([]string{
+					validation.GuestOSSupported,
+					validation.GuestOSUnsupported,
+					validation.GuestOSDeprecated,
+					validation.GuestOSNeedsFlags,
+				}, ", ")),
+			Items: invalid,
+		})
+	}
+	var macs map[macKey]string
 	var sharedDisksConditions []libcnd.Condition
 	setOf := map[string]bool{}
 	setOfTargetName := map[string]bool{}
+	provider := plan.Referenced.Provider.Source
+	if provider == nil {
+		return nil
+	}
+	destination := plan.Referenced.Provider.Destination
+	if destination == nil {
+		return nil
+	}
+	pAdapter, err := adapter.New(provider)
+	if err != nil {
+		return err
+	}
+	validator, err := pAdapter.Validator(plan)
+	if err != nil {
+		return err
+	}
+	ctx, err := plancontext.New(r, plan, r.Log)
+	if err != nil {
+		return err
+	}
+	vmValidator := &vmValidator{
+		plan:      plan,
+		statuses:  plan.Status.Migration.Index(),
+		validator: validator,
+		client:    ctx.Destination.Client,
+	}
+	vmValidator.source, err = web.NewClient(provider)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	vmValidator.destination, err = web.NewClient(destination)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	results := vmValidator.run(Settings.Migration.ValidationWorkers)
 	//
 	// Referenced VMs.
 	for i := range plan.Spec.VMs {
@@ -583,26 +1170,17 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 			})
 			continue
 		}
-		// Source.
-		provider := plan.Referenced.Provider.Source
-		if provider == nil {
-			return nil
+		result := results[i]
+		if result.err != nil {
+			return result.err
 		}
-		inventory, pErr := web.NewClient(provider)
-		if pErr != nil {
-			return liberr.Wrap(pErr)
+		if result.notFound {
+			notFound.Items = append(notFound.Items, ref.String())
+			continue
 		}
-		_, pErr = inventory.VM(ref)
-		if pErr != nil {
-			if errors.As(pErr, &web.NotFoundError{}) {
-				notFound.Items = append(notFound.Items, ref.String())
-				continue
-			}
-			if errors.As(pErr, &web.RefNotUniqueError{}) {
-				ambiguous.Items = append(ambiguous.Items, ref.String())
-				continue
-			}
-			return liberr.Wrap(pErr)
+		if result.ambiguous {
+			ambiguous.Items = append(ambiguous.Items, ref.String())
+			continue
 		}
 		if vm.TargetName == "" {
 			if len(k8svalidation.IsDNS1123Subdomain(ref.Name)) > 0 {
@@ -629,82 +1207,46 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 				setOfTargetName[vm.TargetName] = true
 			}
 		}
-		pAdapter, err := adapter.New(provider)
-		if err != nil {
-			return err
+		if !result.networksMapped {
+			unmappedNetwork.Items = append(unmappedNetwork.Items, ref.String())
 		}
-		validator, err := pAdapter.Validator(plan)
-		if err != nil {
-			return err
+		if !result.podNetwork {
+			multiplePodNetworkMappings.Items = append(multiplePodNetworkMappings.Items, ref.String())
 		}
-		if plan.Referenced.Map.Network != nil {
-			ok, err := validator.NetworksMapped(*ref)
-			if err != nil {
-				return err
-			}
-			if !ok {
-				unmappedNetwork.Items = append(unmappedNetwork.Items, ref.String())
-			}
-			ok, err = validator.PodNetwork(*ref)
-			if err != nil {
-				return err
-			}
-			if !ok {
-				multiplePodNetworkMappings.Items = append(multiplePodNetworkMappings.Items, ref.String())
-			}
+		if !result.storageMapped {
+			unmappedStorage.Items = append(unmappedStorage.Items, ref.String())
 		}
-		if plan.Referenced.Map.Storage != nil {
-			ok, err := validator.StorageMapped(*ref)
-			if err != nil {
-				return err
-			}
-			if !ok {
-				unmappedStorage.Items = append(unmappedStorage.Items, ref.String())
-			}
-			ok, err = validator.DirectStorage(*ref)
-			if err != nil {
-				return err
-			}
-			if !ok {
-				unsupportedStorage.Items = append(unsupportedStorage.Items, ref.String())
-			}
+		if !result.directStorage {
+			unsupportedStorage.Items = append(unsupportedStorage.Items, ref.String())
 		}
-		ok, err := validator.MaintenanceMode(*ref)
-		if err != nil {
-			return err
-		}
-		if !ok {
+		if !result.hostReady {
 			maintenanceMode.Items = append(maintenanceMode.Items, ref.String())
 		}
-		ok, err = validator.StaticIPs(*ref)
-		if err != nil {
-			return err
-		}
-		if !ok {
+		if !result.staticIPs {
 			missingStaticIPs.Items = append(missingStaticIPs.Items, ref.String())
 		}
-
-		var ctx *plancontext.Context
-		ctx, err = plancontext.New(r, plan, r.Log)
-		if err != nil {
-			return err
+		guestOS := validation.FindGuestOS(provider.Type(), guestOSOverrides, result.guestOS...)
+		if !guestOS.Supported() {
+			guestOSNotSupported.Items = append(guestOSNotSupported.Items, ref.String())
+		} else if guestOS.Deprecated() {
+			guestOSDeprecated.Items = append(guestOSDeprecated.Items, ref.String())
+		} else if len(guestOS.Flags) > 0 {
+			guestOSNeedsFlags.Items = append(
+				guestOSNeedsFlags.Items,
+				fmt.Sprintf("%s flags: %s", ref.String(), strings.Join(guestOS.Flags, ",")))
 		}
-		ok, msg, category, err := validator.SharedDisks(*ref, ctx.Destination.Client)
-		if err != nil {
-			return err
-		}
-		if !ok {
+		if !result.sharedDisks {
 			sharedDisks := libcnd.Condition{
 				Type:     SharedWarnDisks,
 				Status:   True,
-				Category: category,
+				Category: result.sharedDisksCategory,
 				Message:  "VMs with shared disk can not be migrated.", // This should be set by the provider validator
 				Items:    []string{ref.String()},
 			}
-			if msg != "" {
-				sharedDisks.Message = msg
+			if result.sharedDisksMessage != "" {
+				sharedDisks.Message = result.sharedDisksMessage
 			}
-			if category == validation.Warn {
+			if result.sharedDisksCategory == validation.Warn {
 				sharedDisks.Type = SharedWarnDisks
 			} else {
 				sharedDisks.Type = SharedDisks
@@ -713,46 +1255,75 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 			sharedDisksConditions = append(sharedDisksConditions, sharedDisks)
 		}
 		// Destination.
-		provider = plan.Referenced.Provider.Destination
-		if provider == nil {
-			return nil
+		if result.existing != "" {
+			alreadyExists.Items = append(
+				alreadyExists.Items,
+				fmt.Sprintf("%s conflicts with: %s", ref.String(), result.existing))
 		}
-		inventory, pErr = web.NewClient(provider)
-		if pErr != nil {
-			return liberr.Wrap(pErr)
+		nics := result.nics
+		ignored := 0
+		for _, nic := range nics {
+			if nic.Destination.Type == Ignored {
+				ignored++
+			}
 		}
-		id := path.Join(
-			plan.Spec.TargetNamespace,
-			ref.Name)
-		if vm.TargetName != "" {
-			// if target name is provided, use it to look for existing VMs
-			id = path.Join(
-				plan.Spec.TargetNamespace,
-				vm.TargetName)
-		}
-		_, pErr = inventory.VM(&refapi.Ref{Name: id})
-		if pErr == nil {
-			if _, found := plan.Status.Migration.FindVM(*ref); !found {
-				// This VM is preexisting or is being managed by a
-				// different migration plan.
-				alreadyExists.Items = append(
-					alreadyExists.Items,
-					ref.String())
+		if ignored > 0 {
+			networksIgnored.Items = append(
+				networksIgnored.Items,
+				fmt.Sprintf("%s nics: %d", ref.String(), ignored))
+		}
+		// MAC addresses.
+		if _, found := vmValidator.statuses.Find(*ref); !found {
+			if macs == nil {
+				list := []ocpweb.VM{}
+				pErr := vmValidator.destination.List(&list, webbase.Param{
+					Key:   webbase.DetailParam,
+					Value: "all",
+				})
+				if pErr != nil {
+					return liberr.Wrap(pErr)
+				}
+				macs = destinationMACs(list)
 			}
-		} else {
-			if !errors.As(pErr, &web.NotFoundError{}) {
-				return liberr.Wrap(pErr)
+			conflicts := []string{}
+			for _, nic := range nics {
+				network := ""
+				switch nic.Destination.Type {
+				case Pod:
+					network = Pod
+				case Multus, SRIOV, UDN:
+					namespace := nic.Destination.Namespace
+					if namespace == "" {
+						namespace = plan.Spec.TargetNamespace
+					}
+					network = nadKey(namespace, nic.Destination.Name)
+				default:
+					continue
+				}
+				key := macKey{network: network, mac: strings.ToLower(nic.MAC)}
+				if conflicting, found := macs[key]; found && nic.MAC != "" {
+					conflicts = append(conflicts, conflicting)
+				}
+			}
+			if len(conflicts) > 0 {
+				macConflicts.Items = append(
+					macConflicts.Items,
+					fmt.Sprintf("%s conflicts with: %s", ref.String(), strings.Join(conflicts, ",")))
 			}
 		}
 		// Warm migration.
-		if plan.Spec.Warm {
-			enabled, err := validator.ChangeTrackingEnabled(*ref)
-			if err != nil {
-				return err
-			}
-			if !enabled {
-				missingCbtForWarm.Items = append(missingCbtForWarm.Items, ref.String())
-			}
+		if !result.changeTracking {
+			missingCbtForWarm.Items = append(missingCbtForWarm.Items, ref.String())
+		}
+		if len(result.independentDisks) > 0 {
+			independentDisks.Items = append(
+				independentDisks.Items,
+				fmt.Sprintf("%s disks: %s", ref.String(), strings.Join(result.independentDisks, ",")))
+		}
+		if len(result.unmigratedBootDevices) > 0 {
+			bootDevicesNotMigrated.Items = append(
+				bootDevicesNotMigrated.Items,
+				fmt.Sprintf("%s devices: %s", ref.String(), strings.Join(result.unmigratedBootDevices, ",")))
 		}
 		// is valid vm pvc name template
 		if vm.PVCNameTemplate != "" {
@@ -766,69 +1337,927 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 				volumeNameInvalid.Items = append(volumeNameInvalid.Items, ref.String())
 			}
 		}
-		// is valid vm pvc name template
-		if vm.NetworkNameTemplate != "" {
-			if err := r.IsValidNetworkNameTemplate(vm.NetworkNameTemplate); err != nil {
-				networkNameInvalid.Items = append(networkNameInvalid.Items, ref.String())
-			}
+		// is valid vm pvc name template
+		if vm.NetworkNameTemplate != "" {
+			if err := r.IsValidNetworkNameTemplate(vm.NetworkNameTemplate); err != nil {
+				networkNameInvalid.Items = append(networkNameInvalid.Items, ref.String())
+			}
+		}
+	}
+	if len(notFound.Items) > 0 {
+		plan.Status.SetCondition(notFound)
+	}
+	if len(notUnique.Items) > 0 {
+		plan.Status.SetCondition(notUnique)
+	}
+	if len(alreadyExists.Items) > 0 {
+		plan.Status.SetCondition(alreadyExists)
+	}
+	if len(macConflicts.Items) > 0 {
+		plan.Status.SetCondition(macConflicts)
+	}
+	if len(networksIgnored.Items) > 0 {
+		plan.Status.SetCondition(networksIgnored)
+	}
+	if len(nameNotValid.Items) > 0 {
+		plan.Status.SetCondition(nameNotValid)
+	}
+	if len(ambiguous.Items) > 0 {
+		plan.Status.SetCondition(ambiguous)
+	}
+	if len(unmappedNetwork.Items) > 0 {
+		plan.Status.SetCondition(unmappedNetwork)
+	}
+	if len(unmappedStorage.Items) > 0 {
+		plan.Status.SetCondition(unmappedStorage)
+	}
+	if len(unsupportedStorage.Items) > 0 {
+		plan.Status.SetCondition(unsupportedStorage)
+	}
+	if len(maintenanceMode.Items) > 0 {
+		plan.Status.SetCondition(maintenanceMode)
+	}
+	if len(multiplePodNetworkMappings.Items) > 0 {
+		plan.Status.SetCondition(multiplePodNetworkMappings)
+	}
+	if len(missingStaticIPs.Items) > 0 {
+		plan.Status.SetCondition(missingStaticIPs)
+	}
+	if len(guestOSNotSupported.Items) > 0 {
+		plan.Status.SetCondition(guestOSNotSupported)
+	}
+	if len(guestOSDeprecated.Items) > 0 {
+		plan.Status.SetCondition(guestOSDeprecated)
+	}
+	if len(guestOSNeedsFlags.Items) > 0 {
+		plan.Status.SetCondition(guestOSNeedsFlags)
+	}
+	if len(sharedDisksConditions) > 0 {
+		plan.Status.SetCondition(sharedDisksConditions...)
+	}
+	if len(missingCbtForWarm.Items) > 0 {
+		plan.Status.SetCondition(missingCbtForWarm)
+	}
+	if len(independentDisks.Items) > 0 {
+		plan.Status.SetCondition(independentDisks)
+	}
+	if len(bootDevicesNotMigrated.Items) > 0 {
+		plan.Status.SetCondition(bootDevicesNotMigrated)
+	}
+	if len(pvcNameInvalid.Items) > 0 {
+		plan.Status.SetCondition(pvcNameInvalid)
+	}
+	if len(volumeNameInvalid.Items) > 0 {
+		plan.Status.SetCondition(volumeNameInvalid)
+	}
+	if len(networkNameInvalid.Items) > 0 {
+		plan.Status.SetCondition(networkNameInvalid)
+	}
+	if len(targetNameInvalid.Items) > 0 {
+		plan.Status.SetCondition(targetNameInvalid)
+	}
+	if len(targetNameNotUnique.Items) > 0 {
+		plan.Status.SetCondition(targetNameNotUnique)
+	}
+
+	return nil
+}
+
+// Destination network and MAC address of a VM interface.
+type macKey struct {
+	network string
+	mac     string
+}
+
+// Map the interfaces of the destination VMs to
+// the (namespace/name) of the VM they belong to.
+func destinationMACs(vms []ocpweb.VM) (macs map[macKey]string) {
+	macs = make(map[macKey]string)
+	for i := range vms {
+		vm := &vms[i]
+		if vm.Object.Spec.Template == nil {
+			continue
+		}
+		spec := &vm.Object.Spec.Template.Spec
+		networks := make(map[string]string)
+		for _, network := range spec.Networks {
+			switch {
+			case network.Multus != nil:
+				networks[network.Name] = nadKey(vm.Namespace, network.Multus.NetworkName)
+			case network.Pod != nil:
+				networks[network.Name] = Pod
+			}
+		}
+		for _, iface := range spec.Domain.Devices.Interfaces {
+			network, found := networks[iface.Name]
+			if !found || iface.MacAddress == "" {
+				continue
+			}
+			key := macKey{network: network, mac: strings.ToLower(iface.MacAddress)}
+			macs[key] = path.Join(vm.Namespace, vm.Name)
+		}
+	}
+	return
+}
+
+// Qualify a NAD name with the namespace when not already qualified.
+func nadKey(namespace, name string) string {
+	if strings.Contains(name, "/") {
+		return name
+	}
+	return path.Join(namespace, name)
+}
+
+// Validate that the destination provides the resources
+// referenced by the maps and required by the VM CPU models.
+func (r *Reconciler) validateDestination(plan *api.Plan) (err error) {
+	if plan.Status.HasCondition(Executing) {
+		return
+	}
+	if plan.Referenced.Provider.Source == nil || plan.Referenced.Provider.Destination == nil {
+		return
+	}
+	ctx, err := plancontext.New(r, plan, r.Log)
+	if err != nil {
+		return
+	}
+	if err = r.validateDestinationStorage(ctx); err != nil {
+		return
+	}
+	if err = r.validateDestinationNetworks(ctx); err != nil {
+		return
+	}
+	if err = r.validateHostedTransferNetwork(ctx); err != nil {
+		return
+	}
+	if err = r.validateDestinationCPU(ctx); err != nil {
+		return
+	}
+	if err = r.validateDestinationTPM(ctx); err != nil {
+		return
+	}
+	if err = r.validateDeviceMap(ctx); err != nil {
+		return
+	}
+	if err = r.validateRDMDisks(ctx); err != nil {
+		return
+	}
+	if err = r.validateAffinityRules(ctx); err != nil {
+		return
+	}
+	err = r.validateDestinationCapacity(ctx)
+	return
+}
+
+// Validate the transfer network of a hosted cluster destination.
+// The transfer pods run on the hosted cluster, so the network attachment
+// definition must exist there, and the hosted cluster nodes (infra cluster
+// VMs) must be attached to additional infra networks for the transfer
+// network to reach the source.
+func (r *Reconciler) validateHostedTransferNetwork(ctx *plancontext.Context) (err error) {
+	plan := ctx.Plan
+	destination := ctx.Destination.Provider
+	if plan.Spec.TransferNetwork == nil || !destination.IsHostedCluster() {
+		return
+	}
+	key := client.ObjectKey{
+		Namespace: plan.Spec.TransferNetwork.Namespace,
+		Name:      plan.Spec.TransferNetwork.Name,
+	}
+	netAttachDef := &k8snet.NetworkAttachmentDefinition{}
+	err = ctx.Destination.Client.Get(context.TODO(), key, netAttachDef)
+	if k8serr.IsNotFound(err) {
+		err = nil
+		plan.Status.SetCondition(libcnd.Condition{
+			Type:     TransferNetNotValid,
+			Status:   True,
+			Category: api.CategoryCritical,
+			Reason:   NotFound,
+			Message:  "Transfer network not found on the hosted cluster.",
+		})
+		return
+	}
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	found, err := ocp.HostedClusterHasAdditionalNetworks(r, destination)
+	if err != nil {
+		return
+	}
+	if !found {
+		plan.Status.SetCondition(libcnd.Condition{
+			Type:     TransferNetNotReachable,
+			Status:   True,
+			Category: api.CategoryWarn,
+			Reason:   NotValid,
+			Message:  "The hosted cluster node pools are not attached to additional networks, the transfer network may not reach the source provider.",
+		})
+	}
+	return
+}
+
+// Validate that the mapped storage classes exist on the destination
+// and support the requested volume and access modes.
+func (r *Reconciler) validateDestinationStorage(ctx *plancontext.Context) (err error) {
+	plan := ctx.Plan
+	if plan.Referenced.Map.Storage == nil {
+		return
+	}
+	notFound := libcnd.Condition{
+		Type:     StorageClassNotValid,
+		Status:   True,
+		Reason:   NotFound,
+		Category: api.CategoryCritical,
+		Message:  "Mapped storage class not found on the destination.",
+		Items:    []string{},
+	}
+	notSupported := libcnd.Condition{
+		Type:     StorageClassNotSupported,
+		Status:   True,
+		Reason:   NotSupported,
+		Category: api.CategoryCritical,
+		Message:  "Mapped storage class does not support the requested volume mode or access mode.",
+		Items:    []string{},
+	}
+	notLiveMigratable := libcnd.Condition{
+		Type:     StorageClassNotLiveMigratable,
+		Status:   True,
+		Reason:   NotSupported,
+		Category: api.CategoryWarn,
+		Message:  "Mapped storage class does not support ReadWriteMany, the migrated VMs will not be live migratable.",
+		Items:    []string{},
+	}
+	checked := map[string]bool{}
+	for _, entry := range plan.Referenced.Map.Storage.Spec.Map {
+		destination := entry.Destination
+		accessModes := destination.GetAccessModes()
+		key := fmt.Sprintf("%s/%s/%v", destination.StorageClass, destination.VolumeMode, accessModes)
+		if checked[key] {
+			continue
+		}
+		checked[key] = true
+		_, pErr := ctx.Destination.Inventory.Storage(&refapi.Ref{Name: destination.StorageClass})
+		if pErr != nil {
+			if errors.As(pErr, &web.NotFoundError{}) {
+				notFound.Items = append(notFound.Items, destination.StorageClass)
+				continue
+			}
+			err = liberr.Wrap(pErr)
+			return
+		}
+		profile := &cdi.StorageProfile{}
+		pErr = ctx.Destination.Client.Get(context.TODO(), client.ObjectKey{Name: destination.StorageClass}, profile)
+		if pErr != nil {
+			if k8serr.IsNotFound(pErr) || k8smeta.IsNoMatchError(pErr) {
+				// Capabilities are unknown without CDI.
+				continue
+			}
+			err = liberr.Wrap(pErr)
+			return
+		}
+		claimPropertySets := profile.Status.ClaimPropertySets
+		if len(claimPropertySets) == 0 {
+			continue
+		}
+		supported := claimPropertySupported(claimPropertySets, destination.VolumeMode, "")
+		for _, accessMode := range accessModes {
+			if !claimPropertySupported(claimPropertySets, destination.VolumeMode, accessMode) {
+				supported = false
+			}
+		}
+		if !supported {
+			notSupported.Items = append(notSupported.Items, destination.StorageClass)
+		}
+		readWriteMany := len(accessModes) == 0
+		for _, accessMode := range accessModes {
+			if accessMode == core.ReadWriteMany {
+				readWriteMany = true
+			}
+		}
+		if !readWriteMany ||
+			!claimPropertySupported(claimPropertySets, destination.VolumeMode, core.ReadWriteMany) {
+			notLiveMigratable.Items = append(notLiveMigratable.Items, destination.StorageClass)
+		}
+	}
+	for _, cnd := range []libcnd.Condition{notFound, notSupported, notLiveMigratable} {
+		if len(cnd.Items) > 0 {
+			plan.Status.SetCondition(cnd)
+		}
+	}
+
+	return
+}
+
+// Determine whether any of the storage profile claim property
+// sets supports the volume mode and access mode. Empty modes match any.
+func claimPropertySupported(sets []cdi.ClaimPropertySet, volumeMode core.PersistentVolumeMode, accessMode core.PersistentVolumeAccessMode) bool {
+	for _, set := range sets {
+		if volumeMode != "" {
+			mode := core.PersistentVolumeFilesystem
+			if set.VolumeMode != nil {
+				mode = *set.VolumeMode
+			}
+			if mode != volumeMode {
+				continue
+			}
+		}
+		if accessMode == "" {
+			return true
+		}
+		for _, mode := range set.AccessModes {
+			if mode == accessMode {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Validate that the mapped network attachment definitions
+// exist on the destination.
+func (r *Reconciler) validateDestinationNetworks(ctx *plancontext.Context) (err error) {
+	plan := ctx.Plan
+	if plan.Referenced.Map.Network == nil {
+		return
+	}
+	notFound := libcnd.Condition{
+		Type:     NetAttachDefNotValid,
+		Status:   True,
+		Reason:   NotFound,
+		Category: api.CategoryCritical,
+		Message:  "Mapped network attachment definition not found on the destination.",
+		Items:    []string{},
+	}
+	for _, entry := range plan.Referenced.Map.Network.Spec.Map {
+		destination := entry.Destination
+		switch destination.Type {
+		case Multus, SRIOV, UDN:
+		default:
+			continue
+		}
+		id := path.Join(destination.Namespace, destination.Name)
+		_, pErr := ctx.Destination.Inventory.Network(&refapi.Ref{Name: id})
+		if pErr != nil {
+			if errors.As(pErr, &web.NotFoundError{}) {
+				notFound.Items = append(notFound.Items, id)
+				continue
+			}
+			err = liberr.Wrap(pErr)
+			return
+		}
+	}
+	if len(notFound.Items) > 0 {
+		plan.Status.SetCondition(notFound)
+	}
+
+	return
+}
+
+// Validate that the destination has at least one node able
+// to run the CPU model and features requested for each VM.
+func (r *Reconciler) validateDestinationCPU(ctx *plancontext.Context) (err error) {
+	plan := ctx.Plan
+	notSupported := libcnd.Condition{
+		Type:     VMCPUModelNotSupported,
+		Status:   True,
+		Reason:   NotSupported,
+		Category: api.CategoryCritical,
+		Message:  "No destination node supports the VM CPU model or required CPU features.",
+		Items:    []string{},
+	}
+	pAdapter, err := adapter.New(plan.Referenced.Provider.Source)
+	if err != nil {
+		return
+	}
+	validator, err := pAdapter.Validator(plan)
+	if err != nil {
+		return
+	}
+	var nodes *core.NodeList
+	for _, vm := range plan.Spec.VMs {
+		if vm.Ref.NotSet() {
+			continue
+		}
+		var cpuModel string
+		var features []string
+		if override := plan.Spec.CPUModel; override != nil {
+			cpuModel, features = override.Name, override.Features
+		} else {
+			var vErr error
+			cpuModel, features, vErr = validator.CPUModel(vm.Ref)
+			if vErr != nil {
+				if errors.As(vErr, &web.NotFoundError{}) || errors.As(vErr, &web.RefNotUniqueError{}) {
+					// Reported by the VM validation.
+					continue
+				}
+				err = vErr
+				return
+			}
+		}
+		if cpuModel == "" && len(features) == 0 {
+			continue
+		}
+		if nodes == nil {
+			nodes = &core.NodeList{}
+			err = ctx.Destination.Client.List(context.TODO(), nodes)
+			if err != nil {
+				err = liberr.Wrap(err)
+				return
+			}
+		}
+		supported := false
+		for i := range nodes.Items {
+			if nodeSupportsCPU(&nodes.Items[i], cpuModel, features) {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			notSupported.Items = append(notSupported.Items, vm.Ref.String())
+		}
+	}
+	if len(notSupported.Items) > 0 {
+		plan.Status.SetCondition(notSupported)
+	}
+
+	return
+}
+
+// Validate the destination supports the persistent TPM device
+// the target VMs (with a vTPM) are created with.
+func (r *Reconciler) validateDestinationTPM(ctx *plancontext.Context) (err error) {
+	plan := ctx.Plan
+	notSupported := libcnd.Condition{
+		Type:     VMTPMNotSupported,
+		Status:   True,
+		Reason:   NotSupported,
+		Category: api.CategoryCritical,
+		Message:  "The VM requires a persistent TPM device, the destination KubeVirt `" + PersistentStateFeatureGate + "` feature gate is not enabled.",
+		Items:    []string{},
+	}
+	pAdapter, err := adapter.New(plan.Referenced.Provider.Source)
+	if err != nil {
+		return
+	}
+	validator, err := pAdapter.Validator(plan)
+	if err != nil {
+		return
+	}
+	var supported *bool
+	for _, vm := range plan.Spec.VMs {
+		if vm.Ref.NotSet() {
+			continue
+		}
+		persistent, vErr := validator.PersistentTPM(vm.Ref)
+		if vErr != nil {
+			if errors.As(vErr, &web.NotFoundError{}) || errors.As(vErr, &web.RefNotUniqueError{}) {
+				// Reported by the VM validation.
+				continue
+			}
+			err = vErr
+			return
+		}
+		if !persistent {
+			continue
+		}
+		if supported == nil {
+			var found bool
+			found, err = persistentStateEnabled(ctx.Destination.Client)
+			if err != nil {
+				return
+			}
+			supported = &found
+		}
+		if !*supported {
+			notSupported.Items = append(notSupported.Items, vm.Ref.String())
+		}
+	}
+	if len(notSupported.Items) > 0 {
+		plan.Status.SetCondition(notSupported)
+	}
+
+	return
+}
+
+// Validate the VM passthrough devices are mapped and the
+// destination nodes advertise the mapped device resources.
+func (r *Reconciler) validateDeviceMap(ctx *plancontext.Context) (err error) {
+	plan := ctx.Plan
+	notMapped := libcnd.Condition{
+		Type:     VMDevicesNotMapped,
+		Status:   True,
+		Reason:   NotValid,
+		Category: api.CategoryCritical,
+		Message:  "VM has passthrough (GPU or PCI) devices not mapped in the plan device map.",
+		Items:    []string{},
+	}
+	notAvailable := libcnd.Condition{
+		Type:     DeviceNotAvailable,
+		Status:   True,
+		Reason:   NotFound,
+		Category: api.CategoryCritical,
+		Message:  "Device resource mapped in the plan device map not advertised by any destination node.",
+		Items:    []string{},
+	}
+	pAdapter, err := adapter.New(plan.Referenced.Provider.Source)
+	if err != nil {
+		return
+	}
+	validator, err := pAdapter.Validator(plan)
+	if err != nil {
+		return
+	}
+	for _, vm := range plan.Spec.VMs {
+		if vm.Ref.NotSet() {
+			continue
+		}
+		profiles, vErr := validator.PassthroughDevices(vm.Ref)
+		if vErr != nil {
+			if errors.As(vErr, &web.NotFoundError{}) || errors.As(vErr, &web.RefNotUniqueError{}) {
+				// Reported by the VM validation.
+				continue
+			}
+			err = vErr
+			return
+		}
+		for _, profile := range profiles {
+			if _, found := plan.Spec.FindDeviceMapping(profile); !found {
+				notMapped.Items = append(notMapped.Items, vm.Ref.String())
+				break
+			}
+		}
+	}
+	if len(plan.Spec.DeviceMap) > 0 {
+		nodes := &core.NodeList{}
+		err = ctx.Destination.Client.List(context.TODO(), nodes)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		for _, mapping := range plan.Spec.DeviceMap {
+			if !deviceAdvertised(nodes.Items, mapping.ResourceName) {
+				notAvailable.Items = append(notAvailable.Items, mapping.ResourceName)
+			}
+		}
+	}
+	if len(notMapped.Items) > 0 {
+		plan.Status.SetCondition(notMapped)
+	}
+	if len(notAvailable.Items) > 0 {
+		plan.Status.SetCondition(notAvailable)
+	}
+
+	return
+}
+
+// Validate the VM RDM (raw device mapping) disks are handled by
+// the plan: skipped or mapped to pre-existing PVs presenting the LUNs.
+func (r *Reconciler) validateRDMDisks(ctx *plancontext.Context) (err error) {
+	plan := ctx.Plan
+	notMapped := libcnd.Condition{
+		Type:     VMRDMDisksNotMapped,
+		Status:   True,
+		Reason:   NotSet,
+		Category: api.CategoryCritical,
+		Message:  "VM has RDM disks. The plan must map the RDM disks to pre-existing block PVs or skip them.",
+		Items:    []string{},
+	}
+	if plan.Spec.RDMDisks.Mapped() {
+		notMapped.Reason = NotFound
+		notMapped.Message = "VM has RDM disks with no available and retained block PV presenting the same LUN (WWN)."
+	}
+	pAdapter, err := adapter.New(plan.Referenced.Provider.Source)
+	if err != nil {
+		return
+	}
+	validator, err := pAdapter.Validator(plan)
+	if err != nil {
+		return
+	}
+	for _, vm := range plan.Spec.VMs {
+		if vm.Ref.NotSet() {
+			continue
+		}
+		unmapped, vErr := validator.RDMDisks(vm.Ref, ctx.Destination.Client)
+		if vErr != nil {
+			if errors.As(vErr, &web.NotFoundError{}) || errors.As(vErr, &web.RefNotUniqueError{}) {
+				// Reported by the VM validation.
+				continue
+			}
+			err = vErr
+			return
+		}
+		if len(unmapped) > 0 {
+			notMapped.Items = append(notMapped.Items, vm.Ref.String())
+		}
+	}
+	if len(notMapped.Items) > 0 {
+		plan.Status.SetCondition(notMapped)
+	}
+
+	return
+}
+
+// Report the source cluster affinity rules covering the VMs
+// that cannot be translated to pod (anti-)affinity on the destination:
+// VM-host and dependency rules, and rules with no other VM in the plan.
+func (r *Reconciler) validateAffinityRules(ctx *plancontext.Context) (err error) {
+	plan := ctx.Plan
+	notMapped := libcnd.Condition{
+		Type:     VMAffinityRulesNotMapped,
+		Status:   True,
+		Reason:   NotSupported,
+		Category: api.CategoryWarn,
+		Message:  "Affinity rules of the source cluster cannot be translated to the destination and will not be enforced.",
+		Items:    []string{},
+	}
+	pAdapter, err := adapter.New(plan.Referenced.Provider.Source)
+	if err != nil {
+		return
+	}
+	validator, err := pAdapter.Validator(plan)
+	if err != nil {
+		return
+	}
+	reported := map[string]bool{}
+	for _, vm := range plan.Spec.VMs {
+		if vm.Ref.NotSet() {
+			continue
+		}
+		rules, vErr := validator.AffinityRules(vm.Ref)
+		if vErr != nil {
+			if errors.As(vErr, &web.NotFoundError{}) || errors.As(vErr, &web.RefNotUniqueError{}) {
+				// Reported by the VM validation.
+				continue
+			}
+			err = vErr
+			return
+		}
+		for i := range rules {
+			rule := &rules[i]
+			if rule.Mappable() && len(rule.Peers(plan, vm.ID)) > 0 {
+				continue
+			}
+			if !reported[rule.Name] {
+				reported[rule.Name] = true
+				notMapped.Items = append(notMapped.Items, rule.Name)
+			}
+		}
+	}
+	if len(notMapped.Items) > 0 {
+		plan.Status.SetCondition(notMapped)
+	}
+
+	return
+}
+
+// Validate the destination has the capacity for the VMs not yet migrated.
+// The CPU and memory requested by the VMs are compared with the allocatable
+// capacity of the schedulable nodes not requested by the running pods, and
+// the disk capacity by storage class with the capacity reported by the CSI
+// driver (CSI storage capacity tracking). The storage classes without
+// reported capacity are not validated.
+func (r *Reconciler) validateDestinationCapacity(ctx *plancontext.Context) (err error) {
+	plan := ctx.Plan
+	shortfall := libcnd.Condition{
+		Type:     DestinationCapacityShortfall,
+		Status:   True,
+		Reason:   NotSufficient,
+		Category: api.CategoryCritical,
+		Message:  "The destination does not have the capacity requested by the VMs.",
+		Items:    []string{},
+	}
+	pAdapter, err := adapter.New(plan.Referenced.Provider.Source)
+	if err != nil {
+		return
+	}
+	validator, err := pAdapter.Validator(plan)
+	if err != nil {
+		return
+	}
+	requested := &capacity{Storage: map[string]*resource.Quantity{}}
+	demanded := false
+	statuses := plan.Status.Migration.Index()
+	for _, vm := range plan.Spec.VMs {
+		if vm.Ref.NotSet() {
+			continue
+		}
+		if status, found := statuses.Find(vm.Ref); found && status.HasCondition(Succeeded) {
+			continue
+		}
+		demand, vErr := validator.Demand(vm.Ref)
+		if vErr != nil {
+			if errors.As(vErr, &web.NotFoundError{}) || errors.As(vErr, &web.RefNotUniqueError{}) {
+				// Reported by the VM validation.
+				continue
+			}
+			err = vErr
+			return
+		}
+		if demand == nil {
+			continue
 		}
+		demanded = true
+		requested.add(demand)
 	}
-	if len(notFound.Items) > 0 {
-		plan.Status.SetCondition(notFound)
+	if !demanded {
+		return
 	}
-	if len(notUnique.Items) > 0 {
-		plan.Status.SetCondition(notUnique)
+	available, err := destinationCapacity(ctx.Destination.Client)
+	if err != nil {
+		return
 	}
-	if len(alreadyExists.Items) > 0 {
-		plan.Status.SetCondition(alreadyExists)
+	shortfall.Items = requested.shortfall(available)
+	if len(shortfall.Items) > 0 {
+		plan.Status.SetCondition(shortfall)
 	}
-	if len(nameNotValid.Items) > 0 {
-		plan.Status.SetCondition(nameNotValid)
+
+	return
+}
+
+// Compute capacity.
+type capacity struct {
+	// CPU.
+	CPU resource.Quantity
+	// Memory.
+	Memory resource.Quantity
+	// Disk capacity by storage class.
+	Storage map[string]*resource.Quantity
+}
+
+// Add the demand of a VM.
+func (r *capacity) add(demand *planbase.Demand) {
+	r.CPU.Add(*resource.NewQuantity(demand.CPU, resource.DecimalSI))
+	r.Memory.Add(*resource.NewQuantity(demand.Memory, resource.BinarySI))
+	for class, bytes := range demand.Storage {
+		r.addStorage(class, *resource.NewQuantity(bytes, resource.BinarySI))
 	}
-	if len(ambiguous.Items) > 0 {
-		plan.Status.SetCondition(ambiguous)
+}
+
+// Add the disk capacity of a storage class.
+func (r *capacity) addStorage(class string, quantity resource.Quantity) {
+	total, found := r.Storage[class]
+	if !found {
+		total = resource.NewQuantity(0, resource.BinarySI)
+		r.Storage[class] = total
 	}
-	if len(unmappedNetwork.Items) > 0 {
-		plan.Status.SetCondition(unmappedNetwork)
+	total.Add(quantity)
+}
+
+// Breakdown of the requested capacity exceeding the available capacity.
+// Storage classes without available capacity are skipped.
+func (r *capacity) shortfall(available *capacity) (items []string) {
+	report := func(name string, requested, available resource.Quantity) {
+		if requested.Cmp(available) > 0 {
+			items = append(
+				items,
+				fmt.Sprintf(
+					"%s: requested %s, available %s",
+					name,
+					requested.String(),
+					available.String()))
+		}
 	}
-	if len(unmappedStorage.Items) > 0 {
-		plan.Status.SetCondition(unmappedStorage)
+	report("cpu", r.CPU, available.CPU)
+	report("memory", r.Memory, available.Memory)
+	classes := []string{}
+	for class := range r.Storage {
+		classes = append(classes, class)
 	}
-	if len(unsupportedStorage.Items) > 0 {
-		plan.Status.SetCondition(unsupportedStorage)
+	sort.Strings(classes)
+	for _, class := range classes {
+		if free, found := available.Storage[class]; found {
+			report("storage class "+class, *r.Storage[class], *free)
+		}
 	}
-	if len(maintenanceMode.Items) > 0 {
-		plan.Status.SetCondition(maintenanceMode)
+	return
+}
+
+// Capacity of the destination available to the VMs.
+func destinationCapacity(destination client.Client) (available *capacity, err error) {
+	available = &capacity{Storage: map[string]*resource.Quantity{}}
+	nodes := &core.NodeList{}
+	err = destination.List(context.TODO(), nodes)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
 	}
-	if len(multiplePodNetworkMappings.Items) > 0 {
-		plan.Status.SetCondition(multiplePodNetworkMappings)
+	schedulable := map[string]bool{}
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if node.Spec.Unschedulable {
+			continue
+		}
+		schedulable[node.Name] = true
+		available.CPU.Add(*node.Status.Allocatable.Cpu())
+		available.Memory.Add(*node.Status.Allocatable.Memory())
 	}
-	if len(missingStaticIPs.Items) > 0 {
-		plan.Status.SetCondition(missingStaticIPs)
+	pods := &core.PodList{}
+	err = destination.List(context.TODO(), pods)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
 	}
-	if len(sharedDisksConditions) > 0 {
-		plan.Status.SetCondition(sharedDisksConditions...)
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !schedulable[pod.Spec.NodeName] ||
+			pod.Status.Phase == core.PodSucceeded ||
+			pod.Status.Phase == core.PodFailed {
+			continue
+		}
+		for _, container := range pod.Spec.Containers {
+			available.CPU.Sub(*container.Resources.Requests.Cpu())
+			available.Memory.Sub(*container.Resources.Requests.Memory())
+		}
 	}
-	if len(missingCbtForWarm.Items) > 0 {
-		plan.Status.SetCondition(missingCbtForWarm)
+	capacities := &storage.CSIStorageCapacityList{}
+	err = destination.List(context.TODO(), capacities)
+	if err != nil {
+		if k8serr.IsForbidden(err) || k8smeta.IsNoMatchError(err) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+		return
 	}
-	if len(pvcNameInvalid.Items) > 0 {
-		plan.Status.SetCondition(pvcNameInvalid)
+	for i := range capacities.Items {
+		reported := &capacities.Items[i]
+		if reported.Capacity != nil {
+			available.addStorage(reported.StorageClassName, *reported.Capacity)
+		}
 	}
-	if len(volumeNameInvalid.Items) > 0 {
-		plan.Status.SetCondition(volumeNameInvalid)
+	return
+}
+
+// Determine whether a (schedulable) node advertises the device resource.
+func deviceAdvertised(nodes []core.Node, resourceName string) bool {
+	for i := range nodes {
+		node := &nodes[i]
+		if node.Spec.Unschedulable {
+			continue
+		}
+		quantity, found := node.Status.Allocatable[core.ResourceName(resourceName)]
+		if found && !quantity.IsZero() {
+			return true
+		}
 	}
-	if len(networkNameInvalid.Items) > 0 {
-		plan.Status.SetCondition(networkNameInvalid)
+	return false
+}
+
+// Determine whether the persistent VM state (TPM and EFI) is enabled
+// by the KubeVirt feature gate on the destination. Reported enabled
+// when the KubeVirt CR cannot be read (not installed or forbidden).
+func persistentStateEnabled(destination client.Client) (enabled bool, err error) {
+	list := &cnv.KubeVirtList{}
+	err = destination.List(context.TODO(), list)
+	if err != nil {
+		if k8serr.IsForbidden(err) || k8smeta.IsNoMatchError(err) {
+			enabled = true
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+		return
 	}
-	if len(targetNameInvalid.Items) > 0 {
-		plan.Status.SetCondition(targetNameInvalid)
+	if len(list.Items) == 0 {
+		enabled = true
+		return
 	}
-	if len(targetNameNotUnique.Items) > 0 {
-		plan.Status.SetCondition(targetNameNotUnique)
+	for i := range list.Items {
+		developer := list.Items[i].Spec.Configuration.DeveloperConfiguration
+		if developer == nil {
+			continue
+		}
+		for _, gate := range developer.FeatureGates {
+			if gate == PersistentStateFeatureGate {
+				enabled = true
+				return
+			}
+		}
 	}
+	return
+}
 
-	return nil
+// Determine whether the node advertises (through the KubeVirt node
+// labeller) the CPU model and all of the CPU features.
+func nodeSupportsCPU(node *core.Node, cpuModel string, features []string) bool {
+	if node.Spec.Unschedulable {
+		return false
+	}
+	switch cpuModel {
+	case "", cnv.CPUModeHostModel, cnv.CPUModeHostPassthrough:
+	default:
+		if node.Labels[cnv.CPUModelLabel+cpuModel] != "true" {
+			return false
+		}
+	}
+	for _, feature := range features {
+		if node.Labels[cnv.CPUFeatureLabel+feature] != "true" {
+			return false
+		}
+	}
+	return true
 }
 
 // Validate transfer network selection.
@@ -836,6 +2265,11 @@ func (r *Reconciler) validateTransferNetwork(plan *api.Plan) (err error) {
 	if plan.Spec.TransferNetwork == nil {
 		return
 	}
+	// Validated on the hosted cluster with the destination.
+	destination := plan.Referenced.Provider.Destination
+	if destination != nil && destination.IsHostedCluster() {
+		return
+	}
 	notFound := libcnd.Condition{
 		Type:     TransferNetNotValid,
 		Status:   True,
@@ -866,11 +2300,60 @@ func (r *Reconciler) validateTransferNetwork(plan *api.Plan) (err error) {
 		return
 	}
 	route, found := netAttachDef.Annotations[AnnForkliftNetworkRoute]
-	if !found {
+	if found {
+		ip := net.ParseIP(route)
+		if ip == nil {
+			plan.Status.SetCondition(notValid)
+			return
+		}
+	}
+	ipPool, found := netAttachDef.Annotations[AnnForkliftNetworkIPPool]
+	if found {
+		_, pErr := ParseIPPool(ipPool)
+		if pErr != nil {
+			notValid.Message = "Transfer network IP pool annotation is not a valid CIDR or IP range."
+			plan.Status.SetCondition(notValid)
+		}
+	}
+
+	return
+}
+
+// Validate the post-migration test spec.
+func (r *Reconciler) validateTestSpec(plan *api.Plan) (err error) {
+	ref := plan.Spec.TestSpec
+	if ref == nil {
+		return
+	}
+	notValid := libcnd.Condition{
+		Type:     TestSpecNotValid,
+		Status:   True,
+		Category: api.CategoryCritical,
+		Reason:   NotFound,
+		Message:  "Post-migration test spec (config map) is not valid.",
+	}
+	key := client.ObjectKey{
+		Namespace: ref.Namespace,
+		Name:      ref.Name,
+	}
+	if key.Namespace == "" {
+		key.Namespace = plan.Namespace
+	}
+	configMap := &core.ConfigMap{}
+	err = r.Get(context.TODO(), key, configMap)
+	if k8serr.IsNotFound(err) {
+		err = nil
+		plan.Status.SetCondition(notValid)
+		return
+	}
+	if err != nil {
+		err = liberr.Wrap(err)
 		return
 	}
-	ip := net.ParseIP(route)
-	if ip == nil {
+	_, pErr := ParseTestSpec(configMap)
+	if pErr != nil {
+		notValid.Reason = NotValid
+		notValid.Message = "Post-migration test spec is not valid: " + pErr.Error()
 		plan.Status.SetCondition(notValid)
 	}
 
@@ -911,10 +2394,15 @@ func (r *Reconciler) validateHooks(plan *api.Plan) (err error) {
 		Message:  "Hook step not valid.",
 		Items:    []string{},
 	}
+	steps := map[string]int{api.PhasePreHook: 1, api.PhasePostHook: 1}
+	// Snapshots are taken by warm migrations.
+	if plan.Spec.Warm {
+		steps[planapi.HookPreSnapshot] = 1
+	}
 	for _, vm := range plan.Spec.VMs {
 		for _, ref := range vm.Hooks {
 			// Step not valid.
-			if _, found := map[string]int{api.PhasePreHook: 1, api.PhasePostHook: 1}[ref.Step]; !found {
+			if _, found := steps[ref.Step]; !found {
 				description := fmt.Sprintf(
 					"VM: %s step: %s",
 					vm.String(),
@@ -979,6 +2467,234 @@ func (r *Reconciler) validateHooks(plan *api.Plan) (err error) {
 	return
 }
 
+// Validate the migration groups.
+func (r *Reconciler) validateGroups(plan *api.Plan) (err error) {
+	notValid := libcnd.Condition{
+		Type:     GroupNotValid,
+		Status:   True,
+		Reason:   NotValid,
+		Category: api.CategoryCritical,
+		Message:  "Migration groups not valid.",
+		Items:    groupErrors(plan),
+	}
+	if len(notValid.Items) > 0 {
+		plan.Status.SetCondition(notValid)
+	}
+
+	return
+}
+
+// Validate the approval gates.
+func (r *Reconciler) validateApprovalGates(plan *api.Plan) (err error) {
+	gates := plan.Spec.ApprovalGates
+	if gates == nil {
+		return
+	}
+	notValid := libcnd.Condition{
+		Type:     ApprovalGateNotValid,
+		Status:   True,
+		Reason:   NotValid,
+		Category: api.CategoryCritical,
+		Message:  "Approval gates not valid.",
+		Items:    []string{},
+	}
+	for _, gate := range gates.Gates {
+		switch gate {
+		case planapi.GatePowerOff, planapi.GateCutover:
+		default:
+			notValid.Items = append(notValid.Items, gate)
+		}
+	}
+	if gates.Timeout < 0 {
+		notValid.Items = append(notValid.Items, fmt.Sprintf("timeout: %d", gates.Timeout))
+	}
+	if len(notValid.Items) > 0 {
+		plan.Status.SetCondition(notValid)
+	}
+
+	return
+}
+
+func (r *Reconciler) validateConversionPod(plan *api.Plan) (err error) {
+	podSettings := plan.Spec.ConversionPod
+	if podSettings == nil || podSettings.Resources == nil {
+		return
+	}
+	notValid := libcnd.Condition{
+		Type:     ConversionPodNotValid,
+		Status:   True,
+		Reason:   NotValid,
+		Category: api.CategoryCritical,
+		Message:  "Conversion pod resource requests exceed the limits.",
+		Items:    []string{},
+	}
+	for name, request := range podSettings.Resources.Requests {
+		limit, found := podSettings.Resources.Limits[name]
+		if found && request.Cmp(limit) > 0 {
+			notValid.Items = append(
+				notValid.Items,
+				fmt.Sprintf("%s: request %s limit %s", name, request.String(), limit.String()))
+		}
+	}
+	if len(notValid.Items) > 0 {
+		sort.Strings(notValid.Items)
+		plan.Status.SetCondition(notValid)
+	}
+
+	return
+}
+
+// Validate the conversion strategy. The appliance converts the
+// disks copied by CDI, not those copied by virt-v2v.
+func (r *Reconciler) validateConversion(plan *api.Plan) (err error) {
+	conversion := plan.Spec.Conversion
+	if !conversion.Delegated() {
+		return
+	}
+	if conversion.Appliance == nil || conversion.Appliance.URL == "" {
+		plan.Status.SetCondition(libcnd.Condition{
+			Type:     ConversionNotValid,
+			Status:   True,
+			Reason:   NotValid,
+			Category: api.CategoryCritical,
+			Message:  "Conversion appliance URL not set.",
+		})
+		return
+	}
+	useV2vForTransfer, vErr := plan.ShouldUseV2vForTransfer()
+	if vErr == nil && useV2vForTransfer {
+		plan.Status.SetCondition(libcnd.Condition{
+			Type:     ConversionNotValid,
+			Status:   True,
+			Reason:   NotSupported,
+			Category: api.CategoryCritical,
+			Message:  "Conversion appliance not supported when the disks are copied by virt-v2v.",
+		})
+	}
+
+	return
+}
+
+// Validate the attribute map: the source is set, the label
+// and annotation keys are valid and each key is mapped once.
+func (r *Reconciler) validateAttributeMap(plan *api.Plan) (err error) {
+	notValid := libcnd.Condition{
+		Type:     AttributeMapNotValid,
+		Status:   True,
+		Reason:   NotValid,
+		Category: api.CategoryCritical,
+		Message:  "Attribute map is not valid.",
+		Items:    []string{},
+	}
+	labels := map[string]bool{}
+	annotations := map[string]bool{}
+	for i, mapping := range plan.Spec.AttributeMap {
+		reasons := []string{}
+		if mapping.Source == "" {
+			reasons = append(reasons, "source not set")
+		}
+		if mapping.Label == "" && mapping.Annotation == "" {
+			reasons = append(reasons, "label or annotation not set")
+		}
+		if mapping.Label != "" {
+			reasons = append(reasons, k8svalidation.IsQualifiedName(mapping.Label)...)
+			if labels[mapping.Label] {
+				reasons = append(reasons, "label not unique")
+			}
+			labels[mapping.Label] = true
+		}
+		if mapping.Annotation != "" {
+			reasons = append(reasons, k8svalidation.IsQualifiedName(mapping.Annotation)...)
+			if annotations[mapping.Annotation] {
+				reasons = append(reasons, "annotation not unique")
+			}
+			annotations[mapping.Annotation] = true
+		}
+		if len(reasons) > 0 {
+			notValid.Items = append(
+				notValid.Items,
+				fmt.Sprintf("[%d] %s: %s", i, mapping.Source, strings.Join(reasons, ", ")))
+		}
+	}
+	if len(notValid.Items) > 0 {
+		plan.Status.SetCondition(notValid)
+	}
+
+	return
+}
+
+// Describe the errors of the migration groups: names
+// not unique, references to groups not listed, cycles
+// and consistent groups in cold migrations.
+func groupErrors(plan *api.Plan) (items []string) {
+	dependencies := map[string][]string{}
+	for i, group := range plan.Spec.Groups {
+		if _, found := dependencies[group.Name]; found {
+			items = append(items, fmt.Sprintf("group: %s not unique", group.Name))
+			continue
+		}
+		if group.Name == "" {
+			items = append(items, fmt.Sprintf("group: [%d] name not set", i))
+			continue
+		}
+		if group.Consistent && !plan.Spec.Warm {
+			items = append(items, fmt.Sprintf("group: %s consistent in a cold migration", group.Name))
+		}
+		dependencies[group.Name] = group.DependsOn
+		if group.DependsOn == nil && i > 0 {
+			dependencies[group.Name] = []string{plan.Spec.Groups[i-1].Name}
+		}
+	}
+	for _, group := range plan.Spec.Groups {
+		for _, dependency := range group.DependsOn {
+			if _, found := dependencies[dependency]; !found {
+				items = append(items, fmt.Sprintf("group: %s dependency: %s not found", group.Name, dependency))
+			}
+		}
+	}
+	for _, vm := range plan.Spec.VMs {
+		if vm.Group == "" {
+			continue
+		}
+		if _, found := dependencies[vm.Group]; !found {
+			items = append(items, fmt.Sprintf("VM: %s group: %s not found", vm.String(), vm.Group))
+		}
+	}
+	// cycles.
+	visiting := map[string]bool{}
+	visited := map[string]bool{}
+	var cyclic func(name string) bool
+	cyclic = func(name string) bool {
+		if visiting[name] {
+			return true
+		}
+		if visited[name] {
+			return false
+		}
+		visiting[name] = true
+		for _, dependency := range dependencies[name] {
+			if cyclic(dependency) {
+				return true
+			}
+		}
+		visiting[name] = false
+		visited[name] = true
+		return false
+	}
+	for _, group := range plan.Spec.Groups {
+		if !visited[group.Name] && cyclic(group.Name) {
+			items = append(items, fmt.Sprintf("group: %s dependency cycle", group.Name))
+			// report each cycle once.
+			for name := range visiting {
+				visited[name] = true
+			}
+			visiting = map[string]bool{}
+		}
+	}
+
+	return
+}
+
 func (r *Reconciler) validateVddkImage(plan *api.Plan) (err error) {
 	source := plan.Referenced.Provider.Source
 	if source == nil {
@@ -1195,7 +2911,7 @@ func (r *Reconciler) ensureVddkImageValidationJob(plan *api.Plan) (*batchv1.Job,
 }
 
 func (r *Reconciler) ensureNamespace(ctx *plancontext.Context) error {
-	err := ensureNamespace(ctx.Plan, ctx.Destination.Client)
+	err := ensureNamespace(ctx.Plan, r.Client, ctx.Destination.Client)
 	if err == nil {
 		r.Log.Info(
 			"Created namespace.",
@@ -1314,6 +3030,10 @@ func createVddkCheckJob(plan *api.Plan) *batchv1.Job {
 }
 
 func (r *Reconciler) setupSecret(plan *api.Plan) (err error) {
+	if plan.Referenced.Provider.Source.IsHostedCluster() {
+		plan.Referenced.Secret, err = ocp.HostedClusterSecret(r, plan.Referenced.Provider.Source)
+		return
+	}
 	key := client.ObjectKey{
 		Namespace: plan.Referenced.Provider.Source.Spec.Secret.Namespace,
 		Name:      plan.Referenced.Provider.Source.Spec.Secret.Name,
@@ -5,20 +5,28 @@ import (
 
 	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
 	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/provider"
+	refapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
 	"github.com/kubev2v/forklift/pkg/controller/base"
+	planbase "github.com/kubev2v/forklift/pkg/controller/plan/adapter/base"
+	ocpweb "github.com/kubev2v/forklift/pkg/controller/provider/web/ocp"
 	"github.com/kubev2v/forklift/pkg/lib/condition"
 	"github.com/kubev2v/forklift/pkg/lib/logging"
 	ginkgo "github.com/onsi/ginkgo/v2"
 	"github.com/onsi/gomega"
 	core "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/version"
 	discovery "k8s.io/client-go/discovery/fake"
 	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/utils/ptr"
+	cnv "kubevirt.io/api/core/v1"
+	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 	fakeClient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -163,6 +171,59 @@ var _ = ginkgo.Describe("Plan Validations", func() {
 		)
 	})
 
+	ginkgo.Describe("validatePVCMetadata", func() {
+		var reconciler *Reconciler
+
+		ginkgo.BeforeEach(func() {
+			reconciler = &Reconciler{
+				Reconciler: base.Reconciler{
+					Log: planValidationLog,
+				},
+			}
+		})
+
+		source := createProvider(sourceName, sourceNamespace, "", v1beta1.OpenShift, &core.ObjectReference{Name: sourceSecretName, Namespace: sourceNamespace})
+		destination := createProvider(destName, destNamespace, "https://destination", v1beta1.OpenShift, &core.ObjectReference{})
+
+		ginkgo.DescribeTable("should validate the PVC metadata",
+			func(labels, annotations map[string]string, shouldBeValid bool) {
+				plan := createPlan(testPlanName, testNamespace, source, destination)
+				plan.Spec.PVCMetadata = &planapi.VolumeMetadata{Labels: labels, Annotations: annotations}
+				gomega.Expect(reconciler.validatePVCMetadata(plan)).To(gomega.Succeed())
+				gomega.Expect(plan.Status.HasCondition(PVCMetadataNotValid)).To(gomega.Equal(!shouldBeValid))
+			},
+			ginkgo.Entry("empty", nil, nil, true),
+			ginkgo.Entry("valid templates",
+				map[string]string{"app": "{{.VmName}}", "example.com/disk": "disk-{{.DiskIndex}}"},
+				map[string]string{"example.com/source": "{{.FileName}}"},
+				true),
+			ginkgo.Entry("invalid label key", map[string]string{"bad key": "value"}, nil, false),
+			ginkgo.Entry("invalid label value", map[string]string{"app": "{{.FileName}}"}, nil, false),
+			ginkgo.Entry("invalid annotation key", nil, map[string]string{"/source": "{{.VmName}}"}, false),
+			ginkgo.Entry("invalid annotation template", nil, map[string]string{"source": "{{.Undefined}}"}, false),
+		)
+	})
+
+	ginkgo.Describe("validateBackup", func() {
+		reconciler := &Reconciler{}
+		source := createProvider(sourceName, sourceNamespace, "", v1beta1.OpenShift, &core.ObjectReference{Name: sourceSecretName, Namespace: sourceNamespace})
+		destination := createProvider(destName, destNamespace, "https://destination", v1beta1.OpenShift, &core.ObjectReference{})
+
+		ginkgo.DescribeTable("should validate the backup settings",
+			func(backup *planapi.BackupSettings, shouldBeValid bool) {
+				plan := createPlan(testPlanName, testNamespace, source, destination)
+				plan.Spec.Backup = backup
+				reconciler.validateBackup(plan)
+				gomega.Expect(plan.Status.HasCondition(BackupNotValid)).To(gomega.Equal(!shouldBeValid))
+			},
+			ginkgo.Entry("not set", nil, true),
+			ginkgo.Entry("valid policy", &planapi.BackupSettings{Policy: "daily", Trigger: true}, true),
+			ginkgo.Entry("missing policy", &planapi.BackupSettings{Trigger: true}, false),
+			ginkgo.Entry("invalid policy", &planapi.BackupSettings{Policy: "daily backup"}, false),
+			ginkgo.Entry("negative ttl", &planapi.BackupSettings{Policy: "daily", TTL: &meta.Duration{Duration: -1}}, false),
+		)
+	})
+
 	ginkgo.Describe("IsValidPVCNameTemplate", func() {
 		var reconciler *Reconciler
 
@@ -198,6 +259,367 @@ var _ = ginkgo.Describe("Plan Validations", func() {
 			ginkgo.Entry("template with uppercase characters (invalid K8s name)", "DISK-{{.VmName}}", false),
 		)
 	})
+
+	ginkgo.Describe("nodeSupportsCPU", func() {
+		node := &core.Node{
+			ObjectMeta: meta.ObjectMeta{
+				Labels: map[string]string{
+					cnv.CPUModelLabel + "Skylake-Client-IBRS": "true",
+					cnv.CPUFeatureLabel + "spec-ctrl":         "true",
+				},
+			},
+		}
+
+		ginkgo.DescribeTable("should match node CPU labels",
+			func(cpuModel string, features []string, unschedulable, supported bool) {
+				n := node.DeepCopy()
+				n.Spec.Unschedulable = unschedulable
+				gomega.Expect(nodeSupportsCPU(n, cpuModel, features)).To(gomega.Equal(supported))
+			},
+			ginkgo.Entry("model and feature advertised", "Skylake-Client-IBRS", []string{"spec-ctrl"}, false, true),
+			ginkgo.Entry("model not advertised", "Cascadelake-Server", nil, false, false),
+			ginkgo.Entry("feature not advertised", "Skylake-Client-IBRS", []string{"avx512f"}, false, false),
+			ginkgo.Entry("host model with advertised feature", cnv.CPUModeHostModel, []string{"spec-ctrl"}, false, true),
+			ginkgo.Entry("unschedulable node", "Skylake-Client-IBRS", nil, true, false),
+		)
+	})
+
+	ginkgo.Describe("deviceAdvertised", func() {
+		node := func(unschedulable bool, quantity string) core.Node {
+			n := core.Node{}
+			n.Spec.Unschedulable = unschedulable
+			n.Status.Allocatable = core.ResourceList{
+				"nvidia.com/TU104GL_Tesla_T4": resource.MustParse(quantity),
+			}
+			return n
+		}
+
+		ginkgo.DescribeTable("should check the node allocatable resources",
+			func(nodes []core.Node, resourceName string, advertised bool) {
+				gomega.Expect(deviceAdvertised(nodes, resourceName)).To(gomega.Equal(advertised))
+			},
+			ginkgo.Entry("advertised", []core.Node{node(false, "1")}, "nvidia.com/TU104GL_Tesla_T4", true),
+			ginkgo.Entry("not advertised", []core.Node{node(false, "1")}, "nvidia.com/GRID_T4-4Q", false),
+			ginkgo.Entry("none allocatable", []core.Node{node(false, "0")}, "nvidia.com/TU104GL_Tesla_T4", false),
+			ginkgo.Entry("unschedulable node", []core.Node{node(true, "1")}, "nvidia.com/TU104GL_Tesla_T4", false),
+		)
+	})
+
+	ginkgo.Describe("destinationCapacity", func() {
+		ginkgo.It("should report the shortfall of the destination capacity", func() {
+			node := &core.Node{ObjectMeta: meta.ObjectMeta{Name: "node"}}
+			node.Status.Allocatable = core.ResourceList{
+				core.ResourceCPU:    resource.MustParse("8"),
+				core.ResourceMemory: resource.MustParse("32Gi"),
+			}
+			cordoned := node.DeepCopy()
+			cordoned.Name = "cordoned"
+			cordoned.Spec.Unschedulable = true
+			pod := &core.Pod{
+				ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "pod"},
+				Spec: core.PodSpec{
+					NodeName: "node",
+					Containers: []core.Container{
+						{
+							Resources: core.ResourceRequirements{
+								Requests: core.ResourceList{core.ResourceCPU: resource.MustParse("2")},
+							},
+						},
+					},
+				},
+			}
+			storageCapacity := &storagev1.CSIStorageCapacity{
+				ObjectMeta:       meta.ObjectMeta{Namespace: "test", Name: "capacity"},
+				StorageClassName: "ceph-rbd",
+				Capacity:         ptr.To(resource.MustParse("100Gi")),
+			}
+			scheme := runtime.NewScheme()
+			_ = core.AddToScheme(scheme)
+			_ = storagev1.AddToScheme(scheme)
+			client := fakeClient.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(node, cordoned, pod, storageCapacity).Build()
+			available, err := destinationCapacity(client)
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(available.CPU.Value()).To(gomega.Equal(int64(6)))
+
+			requested := &capacity{Storage: map[string]*resource.Quantity{}}
+			requested.add(&planbase.Demand{
+				CPU:     4,
+				Memory:  64 * 1024 * 1024 * 1024,
+				Storage: map[string]int64{"ceph-rbd": 200 * 1024 * 1024 * 1024, "nfs": 1024},
+			})
+			gomega.Expect(requested.shortfall(available)).To(gomega.Equal([]string{
+				"memory: requested 64Gi, available 32Gi",
+				"storage class ceph-rbd: requested 200Gi, available 100Gi",
+			}))
+		})
+	})
+
+	ginkgo.Describe("persistentStateEnabled", func() {
+		kubeVirt := func(gates ...string) runtime.Object {
+			return &cnv.KubeVirt{
+				ObjectMeta: meta.ObjectMeta{Namespace: "kubevirt", Name: "kubevirt"},
+				Spec: cnv.KubeVirtSpec{
+					Configuration: cnv.KubeVirtConfiguration{
+						DeveloperConfiguration: &cnv.DeveloperConfiguration{FeatureGates: gates},
+					},
+				},
+			}
+		}
+
+		ginkgo.DescribeTable("should check the KubeVirt feature gate",
+			func(objects []runtime.Object, enabled bool) {
+				scheme := runtime.NewScheme()
+				_ = cnv.AddToScheme(scheme)
+				client := fakeClient.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objects...).Build()
+				found, err := persistentStateEnabled(client)
+				gomega.Expect(err).ToNot(gomega.HaveOccurred())
+				gomega.Expect(found).To(gomega.Equal(enabled))
+			},
+			ginkgo.Entry("gate enabled", []runtime.Object{kubeVirt("Snapshot", PersistentStateFeatureGate)}, true),
+			ginkgo.Entry("gate not enabled", []runtime.Object{kubeVirt("Snapshot")}, false),
+			ginkgo.Entry("KubeVirt not found", []runtime.Object{}, true),
+		)
+	})
+
+	ginkgo.Describe("claimPropertySupported", func() {
+		block := core.PersistentVolumeBlock
+		sets := []cdi.ClaimPropertySet{
+			{
+				AccessModes: []core.PersistentVolumeAccessMode{core.ReadWriteMany},
+				VolumeMode:  &block,
+			},
+			{
+				AccessModes: []core.PersistentVolumeAccessMode{core.ReadWriteOnce},
+			},
+		}
+
+		ginkgo.DescribeTable("should match storage profile claim property sets",
+			func(volumeMode core.PersistentVolumeMode, accessMode core.PersistentVolumeAccessMode, supported bool) {
+				gomega.Expect(claimPropertySupported(sets, volumeMode, accessMode)).To(gomega.Equal(supported))
+			},
+			ginkgo.Entry("any mode", core.PersistentVolumeMode(""), core.PersistentVolumeAccessMode(""), true),
+			ginkgo.Entry("block RWX", core.PersistentVolumeBlock, core.ReadWriteMany, true),
+			ginkgo.Entry("filesystem RWO", core.PersistentVolumeFilesystem, core.ReadWriteOnce, true),
+			ginkgo.Entry("filesystem RWX", core.PersistentVolumeFilesystem, core.ReadWriteMany, false),
+			ginkgo.Entry("block RWO", core.PersistentVolumeBlock, core.ReadWriteOnce, false),
+		)
+	})
+
+	ginkgo.Describe("destinationMACs", func() {
+		vm := ocpweb.VM{}
+		vm.Namespace = "ns"
+		vm.Name = "existing"
+		vm.Object.Spec.Template = &cnv.VirtualMachineInstanceTemplateSpec{}
+		vm.Object.Spec.Template.Spec.Networks = []cnv.Network{
+			{Name: "net-0", NetworkSource: cnv.NetworkSource{Pod: &cnv.PodNetwork{}}},
+			{Name: "net-1", NetworkSource: cnv.NetworkSource{Multus: &cnv.MultusNetwork{NetworkName: "nad"}}},
+		}
+		vm.Object.Spec.Template.Spec.Domain.Devices.Interfaces = []cnv.Interface{
+			{Name: "net-0", MacAddress: "00:50:56:AA:BB:01"},
+			{Name: "net-1", MacAddress: "00:50:56:aa:bb:02"},
+		}
+		macs := destinationMACs([]ocpweb.VM{vm})
+
+		ginkgo.DescribeTable("should map interfaces by network and MAC",
+			func(network, mac string, found bool) {
+				_, ok := macs[macKey{network: network, mac: mac}]
+				gomega.Expect(ok).To(gomega.Equal(found))
+			},
+			ginkgo.Entry("pod network", Pod, "00:50:56:aa:bb:01", true),
+			ginkgo.Entry("nad qualified by namespace", "ns/nad", "00:50:56:aa:bb:02", true),
+			ginkgo.Entry("same MAC on another nad", "other/nad", "00:50:56:aa:bb:02", false),
+			ginkgo.Entry("unknown MAC", Pod, "00:50:56:aa:bb:03", false),
+		)
+	})
+
+	ginkgo.Describe("expandStorageRules", func() {
+		large := resource.MustParse("1Ti")
+		mp := &api.StorageMap{}
+		mp.Spec.Map = []api.StoragePair{
+			{
+				Source:      refapi.Ref{ID: "ds-0"},
+				Destination: api.DestinationStorage{StorageClass: "explicit"},
+			},
+		}
+		mp.Spec.Rules = []api.StorageRule{
+			{
+				Match:       api.StorageMatch{Name: "^nfs-"},
+				Destination: api.DestinationStorage{StorageClass: "nfs"},
+			},
+			{
+				Priority:    1,
+				Match:       api.StorageMatch{Tier: "VMFS", MinCapacity: &large},
+				Destination: api.DestinationStorage{StorageClass: "large"},
+			},
+		}
+		storage := func(id, name, tier string, capacity int64) sourceStorage {
+			return sourceStorage{
+				ref:        refapi.Ref{ID: id, Name: name},
+				attributes: api.StorageAttributes{Name: name, Tier: tier, Capacity: capacity},
+			}
+		}
+		expandStorageRules(mp, []sourceStorage{
+			storage("ds-0", "nfs-0", "NFS", 0),
+			storage("ds-1", "nfs-1", "NFS", 0),
+			storage("ds-2", "nfs-2", "VMFS", large.Value()),
+			storage("ds-3", "local", "VMFS", 1024),
+		})
+
+		ginkgo.DescribeTable("should map the source storage",
+			func(id string, found bool, storageClass string) {
+				pair, ok := mp.FindStorage(id)
+				gomega.Expect(ok).To(gomega.Equal(found))
+				if found {
+					gomega.Expect(pair.Destination.StorageClass).To(gomega.Equal(storageClass))
+				}
+			},
+			ginkgo.Entry("explicitly mapped", "ds-0", true, "explicit"),
+			ginkgo.Entry("matched by name", "ds-1", true, "nfs"),
+			ginkgo.Entry("matched by priority", "ds-2", true, "large"),
+			ginkgo.Entry("not matched", "ds-3", false, ""),
+		)
+	})
+
+	ginkgo.Describe("groupErrors", func() {
+		ginkgo.DescribeTable("should report the group errors",
+			func(groups []planapi.Group, vmGroup string, expected []string) {
+				plan := &api.Plan{}
+				plan.Spec.Groups = groups
+				plan.Spec.VMs = []planapi.VM{{Ref: refapi.Ref{Name: "vm"}, Group: vmGroup}}
+				gomega.Expect(groupErrors(plan)).To(gomega.Equal(expected))
+			},
+			ginkgo.Entry("valid",
+				[]planapi.Group{{Name: "db"}, {Name: "app"}, {Name: "web", DependsOn: []string{"db"}}},
+				"app",
+				nil),
+			ginkgo.Entry("not unique",
+				[]planapi.Group{{Name: "db"}, {Name: "db"}},
+				"",
+				[]string{"group: db not unique"}),
+			ginkgo.Entry("not found",
+				[]planapi.Group{{Name: "app", DependsOn: []string{"db"}}},
+				"web",
+				[]string{"group: app dependency: db not found", "VM:  id: name:'vm'  group: web not found"}),
+			ginkgo.Entry("cycle",
+				[]planapi.Group{{Name: "db", DependsOn: []string{"app"}}, {Name: "app"}},
+				"",
+				[]string{"group: db dependency cycle"}),
+			ginkgo.Entry("consistent in a cold migration",
+				[]planapi.Group{{Name: "db", Consistent: true}},
+				"",
+				[]string{"group: db consistent in a cold migration"}),
+		)
+	})
+
+	ginkgo.Describe("validateReplication", func() {
+		ginkgo.DescribeTable("should validate the replication",
+			func(replication *planapi.Replication, warm bool, valid bool) {
+				reconciler := createFakeReconciler()
+				plan := &api.Plan{}
+				plan.Spec.Replication = replication
+				plan.Spec.Warm = warm
+				gomega.Expect(reconciler.validateReplication(plan)).To(gomega.Succeed())
+				gomega.Expect(plan.Status.HasCondition(ReplicationNotValid)).To(gomega.Equal(!valid))
+			},
+			ginkgo.Entry("not set", nil, false, true),
+			ginkgo.Entry("warm", &planapi.Replication{Interval: 15}, true, true),
+			ginkgo.Entry("cold", &planapi.Replication{}, false, false),
+		)
+	})
+
+	ginkgo.Describe("validateApprovalGates", func() {
+		ginkgo.DescribeTable("should validate the approval gates",
+			func(gates *planapi.ApprovalGates, valid bool) {
+				reconciler := createFakeReconciler()
+				plan := &api.Plan{}
+				plan.Spec.ApprovalGates = gates
+				gomega.Expect(reconciler.validateApprovalGates(plan)).To(gomega.Succeed())
+				gomega.Expect(plan.Status.HasCondition(ApprovalGateNotValid)).To(gomega.Equal(!valid))
+			},
+			ginkgo.Entry("not set", nil, true),
+			ginkgo.Entry("valid", &planapi.ApprovalGates{Gates: []string{planapi.GatePowerOff, planapi.GateCutover}, Timeout: 30}, true),
+			ginkgo.Entry("unknown gate", &planapi.ApprovalGates{Gates: []string{"Delete"}}, false),
+			ginkgo.Entry("negative timeout", &planapi.ApprovalGates{Gates: []string{planapi.GatePowerOff}, Timeout: -1}, false),
+		)
+	})
+
+	ginkgo.Describe("validateConversionPod", func() {
+		resources := func(request, limit string) *planapi.PodSettings {
+			requirements := &core.ResourceRequirements{
+				Requests: core.ResourceList{core.ResourceCPU: resource.MustParse(request)},
+			}
+			if limit != "" {
+				requirements.Limits = core.ResourceList{core.ResourceCPU: resource.MustParse(limit)}
+			}
+			return &planapi.PodSettings{Resources: requirements}
+		}
+		ginkgo.DescribeTable("should validate the conversion pod resources",
+			func(podSettings *planapi.PodSettings, valid bool) {
+				reconciler := createFakeReconciler()
+				plan := &api.Plan{}
+				plan.Spec.ConversionPod = podSettings
+				gomega.Expect(reconciler.validateConversionPod(plan)).To(gomega.Succeed())
+				gomega.Expect(plan.Status.HasCondition(ConversionPodNotValid)).To(gomega.Equal(!valid))
+			},
+			ginkgo.Entry("not set", nil, true),
+			ginkgo.Entry("request only", resources("8", ""), true),
+			ginkgo.Entry("request within limit", resources("2", "4"), true),
+			ginkgo.Entry("request exceeds limit", resources("8", "4"), false),
+		)
+	})
+
+	ginkgo.Describe("validateConversion", func() {
+		ginkgo.DescribeTable("should validate the conversion strategy",
+			func(conversion *planapi.ConversionSettings, source api.ProviderType, valid bool) {
+				reconciler := createFakeReconciler()
+				plan := &api.Plan{}
+				plan.Spec.Conversion = conversion
+				plan.Referenced.Provider.Source = &api.Provider{Spec: api.ProviderSpec{Type: &source}}
+				plan.Referenced.Provider.Destination = &api.Provider{}
+				gomega.Expect(reconciler.validateConversion(plan)).To(gomega.Succeed())
+				gomega.Expect(plan.Status.HasCondition(ConversionNotValid)).To(gomega.Equal(!valid))
+			},
+			ginkgo.Entry("not set", nil, api.VSphere, true),
+			ginkgo.Entry("destination", &planapi.ConversionSettings{
+				Strategy: planapi.ConversionStrategyDestination,
+			}, api.VSphere, true),
+			ginkgo.Entry("appliance", &planapi.ConversionSettings{
+				Strategy:  planapi.ConversionStrategyAppliance,
+				Appliance: &planapi.ConversionAppliance{URL: "https://appliance.example.com"},
+			}, api.VSphere, true),
+			ginkgo.Entry("appliance without URL", &planapi.ConversionSettings{
+				Strategy: planapi.ConversionStrategyAppliance,
+			}, api.VSphere, false),
+			ginkgo.Entry("appliance with disks copied by virt-v2v", &planapi.ConversionSettings{
+				Strategy:  planapi.ConversionStrategyAppliance,
+				Appliance: &planapi.ConversionAppliance{URL: "https://appliance.example.com"},
+			}, api.Ova, false),
+		)
+	})
+
+	ginkgo.Describe("validateAttributeMap", func() {
+		ginkgo.DescribeTable("should validate the attribute map",
+			func(attributeMap []planapi.AttributeMapping, valid bool) {
+				reconciler := createFakeReconciler()
+				plan := &api.Plan{}
+				plan.Spec.AttributeMap = attributeMap
+				gomega.Expect(reconciler.validateAttributeMap(plan)).To(gomega.Succeed())
+				gomega.Expect(plan.Status.HasCondition(AttributeMapNotValid)).To(gomega.Equal(!valid))
+			},
+			ginkgo.Entry("not set", nil, true),
+			ginkgo.Entry("valid", []planapi.AttributeMapping{
+				{Source: "CostCenter", Label: "costcenter"},
+				{Source: "Owner", Label: "example.com/owner", Annotation: "example.com/owner"},
+			}, true),
+			ginkgo.Entry("source not set", []planapi.AttributeMapping{{Label: "costcenter"}}, false),
+			ginkgo.Entry("key not set", []planapi.AttributeMapping{{Source: "CostCenter"}}, false),
+			ginkgo.Entry("label not valid", []planapi.AttributeMapping{{Source: "CostCenter", Label: "Cost Center"}}, false),
+			ginkgo.Entry("label not unique", []planapi.AttributeMapping{
+				{Source: "CostCenter", Label: "costcenter"},
+				{Source: "Department", Label: "costcenter"},
+			}, false),
+		)
+	})
 })
 
 //nolint:errcheck
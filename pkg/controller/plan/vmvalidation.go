@@ -0,0 +1,330 @@
+package plan
+
+import (
+	"errors"
+	"path"
+	"reflect"
+	"sync"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	refapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	planbase "github.com/kubev2v/forklift/pkg/controller/plan/adapter/base"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Source validations of the plan VMs.
+// Reused by the reconciles until the VM (revision), the
+// policy or the plan and its maps (generation) change.
+var vmValidations = &vmValidationCache{entries: map[vmKey]*vmValidation{}}
+
+// Cache key of a VM validation.
+type vmKey struct {
+	// Plan (namespace/name).
+	plan string
+	// VM ID.
+	vm string
+}
+
+// Inventory stamp of a VM validation.
+type vmStamp struct {
+	// Plan UID.
+	plan types.UID
+	// Plan generation.
+	generation int64
+	// Network map generation.
+	networkMap int64
+	// Storage map generation.
+	storageMap int64
+	// VM revision.
+	revision int64
+	// Validation policy version.
+	policyVersion int
+}
+
+// Source validation of a VM.
+type vmValidation struct {
+	// Stamp the validation is current with.
+	stamp vmStamp
+	// The VM networks are mapped.
+	networksMapped bool
+	// At most one NIC mapped to the pod network.
+	podNetwork bool
+	// The VM storage is mapped.
+	storageMapped bool
+	// The VM storage is supported.
+	directStorage bool
+	// The guest static IPs are known.
+	staticIPs bool
+	// Guest OS identifiers.
+	guestOS []string
+	// NICs.
+	nics []planbase.NIC
+	// Change tracking enabled (warm).
+	changeTracking bool
+	// Independent disks (warm).
+	independentDisks []string
+	// Boot devices not migrated.
+	unmigratedBootDevices []string
+}
+
+// Validation of a VM against the inventories.
+type vmResult struct {
+	*vmValidation
+	// Not found in the source inventory.
+	notFound bool
+	// Ambiguous reference.
+	ambiguous bool
+	// Host not in maintenance mode.
+	hostReady bool
+	// Shared disks are valid.
+	sharedDisks bool
+	// Shared disks message.
+	sharedDisksMessage string
+	// Shared disks category.
+	sharedDisksCategory string
+	// Existing target VM (namespace/name).
+	existing string
+	// Error.
+	err error
+}
+
+// Cache of the VM validations.
+type vmValidationCache struct {
+	mutex   sync.Mutex
+	entries map[vmKey]*vmValidation
+}
+
+// Get the validation of the VM current with the stamp.
+func (r *vmValidationCache) get(plan, vm string, stamp vmStamp) (validation *vmValidation, found bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	validation, found = r.entries[vmKey{plan: plan, vm: vm}]
+	if found && validation.stamp != stamp {
+		validation = nil
+		found = false
+	}
+	return
+}
+
+// Add the validation of the VM.
+func (r *vmValidationCache) add(plan, vm string, validation *vmValidation) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.entries[vmKey{plan: plan, vm: vm}] = validation
+}
+
+// Delete the validations of the plan VMs
+// that are not in the (kept) set.
+func (r *vmValidationCache) prune(plan string, kept map[string]bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for key := range r.entries {
+		if key.plan == plan && !kept[key.vm] {
+			delete(r.entries, key)
+		}
+	}
+}
+
+// Validator of the plan VMs against the inventories.
+type vmValidator struct {
+	// Plan.
+	plan *api.Plan
+	// VM statuses of the plan.
+	statuses planapi.VMStatusIndex
+	// Source inventory.
+	source web.Client
+	// Destination inventory.
+	destination web.Client
+	// Provider validator.
+	validator planbase.Validator
+	// Destination client.
+	client client.Client
+}
+
+// Validate the VMs using a pool of workers.
+// The results are indexed as the plan VMs.
+func (r *vmValidator) run(workers int) (results []*vmResult) {
+	vms := r.plan.Spec.VMs
+	results = make([]*vmResult, len(vms))
+	indexes := make(chan int)
+	wg := sync.WaitGroup{}
+	for n := 0; n < min(max(workers, 1), len(vms)); n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = r.validate(&vms[i])
+			}
+		}()
+	}
+	for i := range vms {
+		if vms[i].Ref.NotSet() {
+			continue
+		}
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	kept := map[string]bool{}
+	for i := range vms {
+		kept[vms[i].ID] = true
+	}
+	vmValidations.prune(r.key(), kept)
+	return
+}
+
+// Validate the VM.
+// The reference is resolved by the source inventory.
+func (r *vmValidator) validate(vm *planapi.VM) (result *vmResult) {
+	result = &vmResult{}
+	ref := &vm.Ref
+	object, err := r.source.VM(ref)
+	if err != nil {
+		switch {
+		case errors.As(err, &web.NotFoundError{}):
+			result.notFound = true
+		case errors.As(err, &web.RefNotUniqueError{}):
+			result.ambiguous = true
+		default:
+			result.err = liberr.Wrap(err)
+		}
+		return
+	}
+	stamp := r.stamp(object)
+	validation, found := vmValidations.get(r.key(), ref.ID, stamp)
+	if !found {
+		validation, err = r.validateSource(*ref)
+		if err != nil {
+			result.err = err
+			return
+		}
+		validation.stamp = stamp
+		if stamp.revision > 0 {
+			vmValidations.add(r.key(), ref.ID, validation)
+		}
+	}
+	result.vmValidation = validation
+	result.hostReady, err = r.validator.MaintenanceMode(*ref)
+	if err != nil {
+		result.err = err
+		return
+	}
+	result.sharedDisks, result.sharedDisksMessage, result.sharedDisksCategory, err = r.validator.SharedDisks(*ref, r.client)
+	if err != nil {
+		result.err = err
+		return
+	}
+	id := path.Join(
+		r.plan.Spec.TargetNamespace,
+		ref.Name)
+	if vm.TargetName != "" {
+		// if target name is provided, use it to look for existing VMs
+		id = path.Join(
+			r.plan.Spec.TargetNamespace,
+			vm.TargetName)
+	}
+	_, err = r.destination.VM(&refapi.Ref{Name: id})
+	if err == nil {
+		if _, found := r.statuses.Find(*ref); !found {
+			// This VM is preexisting or is being managed by a
+			// different migration plan.
+			result.existing = id
+		}
+	} else if !errors.As(err, &web.NotFoundError{}) {
+		result.err = liberr.Wrap(err)
+	}
+	return
+}
+
+// Validate the VM against the source inventory.
+func (r *vmValidator) validateSource(ref refapi.Ref) (validation *vmValidation, err error) {
+	validation = &vmValidation{
+		networksMapped: true,
+		podNetwork:     true,
+		storageMapped:  true,
+		directStorage:  true,
+		changeTracking: true,
+	}
+	if r.plan.Referenced.Map.Network != nil {
+		validation.networksMapped, err = r.validator.NetworksMapped(ref)
+		if err != nil {
+			return
+		}
+		validation.podNetwork, err = r.validator.PodNetwork(ref)
+		if err != nil {
+			return
+		}
+	}
+	if r.plan.Referenced.Map.Storage != nil {
+		validation.storageMapped, err = r.validator.StorageMapped(ref)
+		if err != nil {
+			return
+		}
+		validation.directStorage, err = r.validator.DirectStorage(ref)
+		if err != nil {
+			return
+		}
+	}
+	validation.staticIPs, err = r.validator.StaticIPs(ref)
+	if err != nil {
+		return
+	}
+	validation.guestOS, err = r.validator.GuestOS(ref)
+	if err != nil {
+		return
+	}
+	validation.nics, err = r.validator.NICs(ref)
+	if err != nil {
+		return
+	}
+	validation.unmigratedBootDevices, err = r.validator.UnmigratedBootDevices(ref)
+	if err != nil {
+		return
+	}
+	if r.plan.Spec.Warm {
+		validation.changeTracking, err = r.validator.ChangeTrackingEnabled(ref)
+		if err != nil {
+			return
+		}
+		validation.independentDisks, err = r.validator.IndependentDisks(ref)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Stamp of the inventory VM.
+// The revision is zero when not reported by the inventory.
+func (r *vmValidator) stamp(object interface{}) (stamp vmStamp) {
+	stamp = vmStamp{
+		plan:       r.plan.UID,
+		generation: r.plan.Generation,
+	}
+	if mp := r.plan.Referenced.Map.Network; mp != nil {
+		stamp.networkMap = mp.Generation
+	}
+	if mp := r.plan.Referenced.Map.Storage; mp != nil {
+		stamp.storageMap = mp.Generation
+	}
+	v := reflect.Indirect(reflect.ValueOf(object))
+	if v.Kind() != reflect.Struct {
+		return
+	}
+	if f := v.FieldByName("Revision"); f.IsValid() && f.CanInt() {
+		stamp.revision = f.Int()
+	}
+	if f := v.FieldByName("PolicyVersion"); f.IsValid() && f.CanInt() {
+		stamp.policyVersion = int(f.Int())
+	}
+	return
+}
+
+// Cache key of the plan.
+func (r *vmValidator) key() string {
+	return path.Join(r.plan.Namespace, r.plan.Name)
+}
@@ -0,0 +1,127 @@
+package plan
+
+import (
+	"sync/atomic"
+	"testing"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	planbase "github.com/kubev2v/forklift/pkg/controller/plan/adapter/base"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web"
+	vsphereweb "github.com/kubev2v/forklift/pkg/controller/provider/web/vsphere"
+	"github.com/onsi/gomega"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestVMValidator(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	plan := &api.Plan{ObjectMeta: meta.ObjectMeta{Namespace: "ns", Name: "validated", UID: "p1"}}
+	plan.Spec.TargetNamespace = "target"
+	for _, id := range []string{"vm-1", "vm-2", "vm-3", "missing"} {
+		plan.Spec.VMs = append(plan.Spec.VMs, planapi.VM{Ref: ref.Ref{ID: id}})
+	}
+	plan.Spec.VMs = append(plan.Spec.VMs, planapi.VM{})
+	source := &vmInventory{revisions: map[string]int64{"vm-1": 1, "vm-2": 1, "vm-3": 1}}
+	validator := &vmValidatorStub{}
+	r := &vmValidator{
+		plan:        plan,
+		source:      source,
+		destination: &vmInventory{existing: map[string]bool{"target/vm-2": true}},
+		validator:   validator,
+	}
+
+	results := r.run(2)
+	g.Expect(results).To(gomega.HaveLen(5))
+	g.Expect(results[0].err).ToNot(gomega.HaveOccurred())
+	g.Expect(results[0].guestOS).To(gomega.Equal([]string{"vm-1"}))
+	g.Expect(results[1].existing).To(gomega.Equal("target/vm-2"))
+	g.Expect(results[3].notFound).To(gomega.BeTrue())
+	g.Expect(results[4]).To(gomega.BeNil())
+	g.Expect(validator.guestOS.Load()).To(gomega.Equal(int32(3)))
+
+	// Cached.
+	results = r.run(2)
+	g.Expect(results[0].guestOS).To(gomega.Equal([]string{"vm-1"}))
+	g.Expect(validator.guestOS.Load()).To(gomega.Equal(int32(3)))
+
+	// Invalidated by the VM revision and the plan generation.
+	source.revisions["vm-1"] = 2
+	r.run(2)
+	g.Expect(validator.guestOS.Load()).To(gomega.Equal(int32(4)))
+	plan.Generation++
+	r.run(2)
+	g.Expect(validator.guestOS.Load()).To(gomega.Equal(int32(7)))
+
+	// Pruned.
+	plan.Spec.VMs = plan.Spec.VMs[:1]
+	r.run(2)
+	_, found := vmValidations.get("ns/validated", "vm-2", r.stamp(&vsphereweb.VM{}))
+	g.Expect(found).To(gomega.BeFalse())
+	vmValidations.prune("ns/validated", nil)
+	_, found = vmValidations.get("ns/validated", "vm-1", r.stamp(source.vm("vm-1")))
+	g.Expect(found).To(gomega.BeFalse())
+}
+
+// Inventory of the validated VMs.
+type vmInventory struct {
+	web.Client
+	// Source VM revisions.
+	revisions map[string]int64
+	// Destination VMs.
+	existing map[string]bool
+}
+
+func (r *vmInventory) vm(id string) *vsphereweb.VM {
+	vm := &vsphereweb.VM{}
+	vm.ID = id
+	vm.Revision = r.revisions[id]
+	return vm
+}
+
+func (r *vmInventory) VM(ref *ref.Ref) (object interface{}, err error) {
+	if _, found := r.revisions[ref.ID]; found {
+		ref.Name = ref.ID
+		object = r.vm(ref.ID)
+		return
+	}
+	if r.existing[ref.Name] {
+		object = &vsphereweb.VM{}
+		return
+	}
+	err = web.NotFoundError{}
+	return
+}
+
+// Validator counting the guest OS validations.
+type vmValidatorStub struct {
+	planbase.Validator
+	guestOS atomic.Int32
+}
+
+func (r *vmValidatorStub) StaticIPs(ref.Ref) (bool, error) {
+	return true, nil
+}
+
+func (r *vmValidatorStub) GuestOS(vmRef ref.Ref) ([]string, error) {
+	r.guestOS.Add(1)
+	return []string{vmRef.ID}, nil
+}
+
+func (r *vmValidatorStub) NICs(ref.Ref) ([]planbase.NIC, error) {
+	return nil, nil
+}
+
+func (r *vmValidatorStub) UnmigratedBootDevices(ref.Ref) ([]string, error) {
+	return nil, nil
+}
+
+func (r *vmValidatorStub) MaintenanceMode(ref.Ref) (bool, error) {
+	return true, nil
+}
+
+func (r *vmValidatorStub) SharedDisks(ref.Ref, client.Client) (bool, string, string, error) {
+	return true, "", "", nil
+}
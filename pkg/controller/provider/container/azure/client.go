@@ -0,0 +1,15 @@
+package azure
+
+import (
+	libclient "github.com/kubev2v/forklift/pkg/lib/client/azure"
+)
+
+// Client struct
+type Client struct {
+	libclient.Client
+}
+
+// The client of the adapter context.
+func client(ctx *Context) *Client {
+	return ctx.Client.(*Client)
+}
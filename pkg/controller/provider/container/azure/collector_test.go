@@ -0,0 +1,351 @@
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/controller/provider/container/base"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/azure"
+	libclient "github.com/kubev2v/forklift/pkg/lib/client/azure"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Resource ID prefix.
+const prefix = "/subscriptions/sub-1/resourceGroups/group-1/providers/"
+
+// Fake resource manager API.
+type inventory struct {
+	groups   []interface{}
+	networks []interface{}
+	nics     []interface{}
+	disks    []interface{}
+	vms      []interface{}
+	sizes    []interface{}
+}
+
+// Serve the inventory.
+func (r *inventory) serve(t *testing.T) (server *httptest.Server) {
+	list := func(entities *[]interface{}) http.HandlerFunc {
+		return func(w http.ResponseWriter, _ *http.Request) {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"value": *entities})
+		}
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/tenant-1/oauth2/v2.0/token", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"access_token":"token-1","expires_in":3600}`))
+	})
+	mux.HandleFunc("/subscriptions/sub-1", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"subscriptionId":"sub-1"}`))
+	})
+	mux.HandleFunc("/subscriptions/sub-1/resourcegroups", list(&r.groups))
+	mux.HandleFunc("/subscriptions/sub-1/providers/Microsoft.Network/virtualNetworks", list(&r.networks))
+	mux.HandleFunc("/subscriptions/sub-1/providers/Microsoft.Network/networkInterfaces", list(&r.nics))
+	mux.HandleFunc("/subscriptions/sub-1/providers/Microsoft.Compute/disks", list(&r.disks))
+	mux.HandleFunc("/subscriptions/sub-1/providers/Microsoft.Compute/virtualMachines", list(&r.vms))
+	mux.HandleFunc("/subscriptions/sub-1/providers/Microsoft.Compute/locations/eastus/vmSizes", list(&r.sizes))
+	server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return
+}
+
+// Build a VM.
+func vmResource(name, powerState string) map[string]interface{} {
+	return map[string]interface{}{
+		"id":       prefix + "Microsoft.Compute/virtualMachines/" + name,
+		"name":     name,
+		"location": "eastus",
+		"properties": map[string]interface{}{
+			"hardwareProfile": map[string]string{"vmSize": "Standard_D2s_v3"},
+			"storageProfile": map[string]interface{}{
+				"osDisk": map[string]interface{}{
+					"name":        name + "-os",
+					"osType":      "Linux",
+					"managedDisk": map[string]string{"id": prefix + "Microsoft.Compute/disks/" + name + "-os"},
+				},
+			},
+			"networkProfile": map[string]interface{}{
+				"networkInterfaces": []map[string]interface{}{
+					{
+						"id":         prefix + "Microsoft.Network/networkInterfaces/" + name + "-nic",
+						"properties": map[string]bool{"primary": true},
+					},
+				},
+			},
+			"instanceView": map[string]interface{}{
+				"statuses": []map[string]string{{"code": "PowerState/" + powerState}},
+			},
+		},
+	}
+}
+
+// Build a managed disk.
+func diskResource(name, vm string) map[string]interface{} {
+	return map[string]interface{}{
+		"id":        prefix + "Microsoft.Compute/disks/" + name,
+		"name":      name,
+		"location":  "eastus",
+		"managedBy": prefix + "Microsoft.Compute/virtualMachines/" + vm,
+		"sku":       map[string]string{"name": "Premium_LRS"},
+		"properties": map[string]interface{}{
+			"diskSizeGB":       30,
+			"hyperVGeneration": "V2",
+		},
+	}
+}
+
+// Build a network interface.
+func nicResource(name string) map[string]interface{} {
+	return map[string]interface{}{
+		"id":   prefix + "Microsoft.Network/networkInterfaces/" + name,
+		"name": name,
+		"properties": map[string]interface{}{
+			"macAddress": "00-0D-3A-00-00-01",
+			"ipConfigurations": []map[string]interface{}{
+				{
+					"properties": map[string]interface{}{
+						"privateIPAddress": "10.0.0.5",
+						"subnet":           map[string]string{"id": prefix + "Microsoft.Network/virtualNetworks/vnet-1/subnets/default"},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Build a virtual network.
+func networkResource(subnets ...string) map[string]interface{} {
+	list := []map[string]interface{}{}
+	for _, name := range subnets {
+		list = append(
+			list,
+			map[string]interface{}{
+				"id":         prefix + "Microsoft.Network/virtualNetworks/vnet-1/subnets/" + name,
+				"name":       name,
+				"properties": map[string]string{"addressPrefix": "10.0.0.0/24"},
+			})
+	}
+	return map[string]interface{}{
+		"id":         prefix + "Microsoft.Network/virtualNetworks/vnet-1",
+		"name":       "vnet-1",
+		"location":   "eastus",
+		"properties": map[string]interface{}{"subnets": list},
+	}
+}
+
+// Build the client and the collector and open the DB.
+func newCollector(t *testing.T, url string) (collector *base.Collector, ctx *Context) {
+	provider := &api.Provider{
+		ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "azure", UID: "azure-uid"},
+		Spec:       api.ProviderSpec{URL: url},
+	}
+	secret := &core.Secret{
+		Data: map[string][]byte{
+			libclient.TenantID:       []byte("tenant-1"),
+			libclient.ClientID:       []byte("client-1"),
+			libclient.ClientSecret:   []byte("secret"),
+			libclient.SubscriptionID: []byte("sub-1"),
+			libclient.AuthorityHost:  []byte(url),
+		},
+	}
+	db := libmodel.New(filepath.Join(t.TempDir(), "azure.db"), model.All()...)
+	err := db.Open(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = db.Close(true)
+	})
+	collector = New(db, provider, secret)
+	client := &Client{}
+	client.URL = url
+	client.LoadOptionsFromSecret(secret)
+	ctx = base.NewContext(context.TODO(), client, db, logging.WithName("test"))
+	return
+}
+
+// Load the inventory using the adapters.
+func load(ctx *Context) (err error) {
+	for _, adapter := range adapterList {
+		itr, lErr := adapter.List(ctx, nil)
+		if lErr != nil {
+			err = lErr
+			return
+		}
+		for {
+			object, hasNext := itr.Next()
+			if !hasNext {
+				break
+			}
+			err = ctx.DB.Insert(object.(libmodel.Model))
+			if err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// Refresh the inventory using the adapters.
+func refresh(ctx *Context) (err error) {
+	for _, adapter := range adapterList {
+		updates, uErr := adapter.GetUpdates(ctx)
+		if uErr != nil {
+			err = uErr
+			return
+		}
+		err = ctx.DB.With(func(tx *libmodel.Tx) (err error) {
+			for _, updater := range updates {
+				err = updater(tx)
+				if err != nil {
+					return
+				}
+			}
+			return
+		})
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// The inventory is loaded and refreshed. The validation
+// of the VMs is retained and the models no longer listed
+// are deleted.
+func TestCollector(t *testing.T) {
+	fake := &inventory{
+		groups: []interface{}{
+			map[string]interface{}{"id": "/subscriptions/sub-1/resourceGroups/group-1", "name": "group-1", "location": "eastus"},
+		},
+		networks: []interface{}{networkResource("default", "private")},
+		nics:     []interface{}{nicResource("web-nic")},
+		disks:    []interface{}{diskResource("web-os", "web")},
+		vms:      []interface{}{vmResource("web", libclient.PowerStateRunning)},
+		sizes: []interface{}{
+			map[string]interface{}{"name": "Standard_D2s_v3", "numberOfCores": 2, "memoryInMB": 8192},
+		},
+	}
+	server := fake.serve(t)
+	collector, ctx := newCollector(t, server.URL)
+	if collector.Name() != strings.TrimPrefix(server.URL, "http://") {
+		t.Errorf("unexpected name: %s", collector.Name())
+	}
+	status, err := collector.Test()
+	if err != nil || status != 0 {
+		t.Fatalf("unexpected connection test: %d %v", status, err)
+	}
+
+	// Load.
+	err = load(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	subnets := []model.Subnet{}
+	err = ctx.DB.List(&subnets, model.ListOptions{Detail: model.MaxDetail})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(subnets) != 2 || subnets[0].VirtualNetwork != "vnet-1" || subnets[0].CIDR != "10.0.0.0/24" {
+		t.Errorf("unexpected subnets: %+v", subnets)
+	}
+	skus := []model.DiskSku{}
+	err = ctx.DB.List(&skus, model.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(skus) != len(libclient.DiskSkus) {
+		t.Errorf("unexpected disk skus: %v", skus)
+	}
+	vmID := libclient.ID(prefix + "Microsoft.Compute/virtualMachines/web")
+	vm := &model.VM{Base: model.Base{ID: vmID}}
+	err = ctx.DB.Get(vm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vm.Name != "web" || vm.ResourceGroup != "group-1" || vm.VCPUs != 2 || vm.MemoryMB != 8192 ||
+		vm.HyperVGeneration != "V2" ||
+		len(vm.Disks) != 1 || !vm.Disks[0].OS || vm.Disks[0].Size != 30 ||
+		len(vm.NICs) != 1 || vm.NICs[0].MAC != "00:0d:3a:00:00:01" || vm.NICs[0].IPs[0] != "10.0.0.5" {
+		t.Errorf("unexpected vm: %+v", vm)
+	}
+	disk := &model.Disk{Base: model.Base{ID: libclient.ID(prefix + "Microsoft.Compute/disks/web-os")}}
+	err = ctx.DB.Get(disk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if disk.VM != vmID {
+		t.Errorf("unexpected disk: %+v", disk)
+	}
+
+	// Validated.
+	vm.PolicyVersion = 1
+	vm.RevisionValidated = vm.Revision
+	vm.Revision--
+	err = ctx.DB.Update(vm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Refresh.
+	fake.vms = []interface{}{
+		vmResource("web", libclient.PowerStateDeallocated),
+		vmResource("db", libclient.PowerStateRunning),
+	}
+	fake.networks = []interface{}{networkResource("default")}
+	err = refresh(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm = &model.VM{Base: model.Base{ID: vmID}}
+	err = ctx.DB.Get(vm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vm.PowerState != libclient.PowerStateDeallocated || vm.PolicyVersion != 1 || vm.Validated() {
+		t.Errorf("unexpected vm: %+v", vm)
+	}
+	err = ctx.DB.Get(&model.VM{Base: model.Base{ID: libclient.ID(prefix + "Microsoft.Compute/virtualMachines/db")}})
+	if err != nil {
+		t.Errorf("expected the created vm: %v", err)
+	}
+	subnetID := libclient.ID(prefix + "Microsoft.Network/virtualNetworks/vnet-1/subnets/private")
+	err = ctx.DB.Get(&model.Subnet{Base: model.Base{ID: subnetID}})
+	if !errors.Is(err, model.NotFound) {
+		t.Errorf("expected the subnet to be deleted: %v", err)
+	}
+
+	// Workload.
+	object, err := workload(ctx.DB, &api.Provider{ObjectMeta: meta.ObjectMeta{UID: "azure-uid"}}, vmID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := json.Marshal(object)
+	if !strings.Contains(string(raw), `"name":"vnet-1/default"`) {
+		t.Errorf("unexpected workload: %s", raw)
+	}
+}
+
+// The connection test reports the rejected credentials.
+func TestCollectorUnauthorized(t *testing.T) {
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"invalid_client"}`))
+		}))
+	defer server.Close()
+	collector, _ := newCollector(t, server.URL)
+	status, err := collector.Test()
+	if err == nil || status != http.StatusUnauthorized {
+		t.Errorf("unexpected connection test: %d %v", status, err)
+	}
+}
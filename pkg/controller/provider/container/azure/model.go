@@ -0,0 +1,384 @@
+package azure
+
+import (
+	"strings"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/controller/provider/container/base"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/azure"
+	libclient "github.com/kubev2v/forklift/pkg/lib/client/azure"
+	fb "github.com/kubev2v/forklift/pkg/lib/filebacked"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+)
+
+// All adapters.
+var adapterList []Adapter
+
+func init() {
+	adapterList = []Adapter{
+		&ResourceGroupAdapter{},
+		&SubnetAdapter{},
+		&DiskSkuAdapter{},
+		&DiskAdapter{},
+		&VMAdapter{},
+	}
+}
+
+// Types
+type Updater = base.Updater
+type Context = base.Context
+type Adapter = base.Adapter
+type BaseAdapter = base.BaseAdapter
+
+// Resource group adapter.
+type ResourceGroupAdapter struct {
+	BaseAdapter
+}
+
+// List the collection.
+func (r *ResourceGroupAdapter) List(ctx *Context, provider *api.Provider) (itr fb.Iterator, err error) {
+	resources, err := r.list(ctx)
+	if err != nil {
+		return
+	}
+	list := fb.NewList()
+	for _, resource := range resources {
+		m := &model.ResourceGroup{
+			Base: model.Base{ID: libclient.ID(resource.ID)},
+		}
+		resource.ApplyTo(m)
+		list.Append(m)
+	}
+
+	itr = list.Iter()
+	return
+}
+
+// Get updates since last refresh.
+func (r *ResourceGroupAdapter) GetUpdates(ctx *Context) (updates []Updater, err error) {
+	resources, err := r.list(ctx)
+	if err != nil {
+		return
+	}
+	listed := map[string]bool{}
+	for _, resource := range resources {
+		m := &model.ResourceGroup{
+			Base: model.Base{ID: libclient.ID(resource.ID)},
+		}
+		listed[m.ID] = true
+		updates = append(
+			updates,
+			r.Upsert(m, func() {
+				resource.ApplyTo(m)
+			}))
+	}
+	deletions, err := r.DeleteUnexisting(
+		ctx,
+		&model.ResourceGroup{},
+		listed,
+		func(id string) libmodel.Model {
+			return &model.ResourceGroup{Base: model.Base{ID: id}}
+		})
+	updates = append(updates, deletions...)
+	return
+}
+
+// List the resource groups.
+func (r *ResourceGroupAdapter) list(ctx *Context) (list []*ResourceGroup, err error) {
+	groupList, err := client(ctx).ListResourceGroups()
+	if err != nil {
+		return
+	}
+	for i := range groupList {
+		list = append(list, &ResourceGroup{ResourceGroup: groupList[i]})
+	}
+	return
+}
+
+// Subnet adapter.
+type SubnetAdapter struct {
+	BaseAdapter
+}
+
+// List the collection.
+func (r *SubnetAdapter) List(ctx *Context, provider *api.Provider) (itr fb.Iterator, err error) {
+	resources, err := r.list(ctx)
+	if err != nil {
+		return
+	}
+	list := fb.NewList()
+	for _, resource := range resources {
+		m := &model.Subnet{
+			Base: model.Base{ID: libclient.ID(resource.ID)},
+		}
+		resource.ApplyTo(m)
+		list.Append(m)
+	}
+
+	itr = list.Iter()
+	return
+}
+
+// Get updates since last refresh.
+func (r *SubnetAdapter) GetUpdates(ctx *Context) (updates []Updater, err error) {
+	resources, err := r.list(ctx)
+	if err != nil {
+		return
+	}
+	listed := map[string]bool{}
+	for _, resource := range resources {
+		m := &model.Subnet{
+			Base: model.Base{ID: libclient.ID(resource.ID)},
+		}
+		listed[m.ID] = true
+		updates = append(
+			updates,
+			r.Upsert(m, func() {
+				resource.ApplyTo(m)
+			}))
+	}
+	deletions, err := r.DeleteUnexisting(
+		ctx,
+		&model.Subnet{},
+		listed,
+		func(id string) libmodel.Model {
+			return &model.Subnet{Base: model.Base{ID: id}}
+		})
+	updates = append(updates, deletions...)
+	return
+}
+
+// List the subnets of the virtual networks.
+func (r *SubnetAdapter) list(ctx *Context) (list []*Subnet, err error) {
+	networkList, err := client(ctx).ListVirtualNetworks()
+	if err != nil {
+		return
+	}
+	for i := range networkList {
+		network := &networkList[i]
+		for _, subnet := range network.Properties.Subnets {
+			list = append(list, &Subnet{Subnet: subnet, network: network})
+		}
+	}
+	return
+}
+
+// Disk SKU adapter.
+// The (managed) disk SKUs are not listed by the API.
+type DiskSkuAdapter struct {
+	BaseAdapter
+}
+
+// List the collection.
+func (r *DiskSkuAdapter) List(ctx *Context, provider *api.Provider) (itr fb.Iterator, err error) {
+	list := fb.NewList()
+	for _, name := range libclient.DiskSkus {
+		m := &model.DiskSku{
+			Base: model.Base{ID: name, Name: name},
+		}
+		list.Append(m)
+	}
+
+	itr = list.Iter()
+	return
+}
+
+// Get updates since last refresh.
+func (r *DiskSkuAdapter) GetUpdates(ctx *Context) (updates []Updater, err error) {
+	listed := map[string]bool{}
+	for _, name := range libclient.DiskSkus {
+		m := &model.DiskSku{
+			Base: model.Base{ID: name},
+		}
+		listed[m.ID] = true
+		updates = append(
+			updates,
+			r.Upsert(m, func() {
+				m.Name = m.ID
+			}))
+	}
+	deletions, err := r.DeleteUnexisting(
+		ctx,
+		&model.DiskSku{},
+		listed,
+		func(id string) libmodel.Model {
+			return &model.DiskSku{Base: model.Base{ID: id}}
+		})
+	updates = append(updates, deletions...)
+	return
+}
+
+// Managed disk adapter.
+type DiskAdapter struct {
+	BaseAdapter
+}
+
+// List the collection.
+func (r *DiskAdapter) List(ctx *Context, provider *api.Provider) (itr fb.Iterator, err error) {
+	resources, err := r.list(ctx)
+	if err != nil {
+		return
+	}
+	list := fb.NewList()
+	for _, resource := range resources {
+		m := &model.Disk{
+			Base: model.Base{ID: libclient.ID(resource.ID)},
+		}
+		resource.ApplyTo(m)
+		list.Append(m)
+	}
+
+	itr = list.Iter()
+	return
+}
+
+// Get updates since last refresh.
+func (r *DiskAdapter) GetUpdates(ctx *Context) (updates []Updater, err error) {
+	resources, err := r.list(ctx)
+	if err != nil {
+		return
+	}
+	listed := map[string]bool{}
+	for _, resource := range resources {
+		m := &model.Disk{
+			Base: model.Base{ID: libclient.ID(resource.ID)},
+		}
+		listed[m.ID] = true
+		updates = append(
+			updates,
+			r.Upsert(m, func() {
+				resource.ApplyTo(m)
+			}))
+	}
+	deletions, err := r.DeleteUnexisting(
+		ctx,
+		&model.Disk{},
+		listed,
+		func(id string) libmodel.Model {
+			return &model.Disk{Base: model.Base{ID: id}}
+		})
+	updates = append(updates, deletions...)
+	return
+}
+
+// List the managed disks.
+func (r *DiskAdapter) list(ctx *Context) (list []*Disk, err error) {
+	diskList, err := client(ctx).ListDisks()
+	if err != nil {
+		return
+	}
+	for i := range diskList {
+		list = append(list, &Disk{Disk: diskList[i]})
+	}
+	return
+}
+
+// VM adapter.
+type VMAdapter struct {
+	BaseAdapter
+}
+
+// List the collection.
+func (r *VMAdapter) List(ctx *Context, provider *api.Provider) (itr fb.Iterator, err error) {
+	resources, err := r.list(ctx)
+	if err != nil {
+		return
+	}
+	list := fb.NewList()
+	for _, resource := range resources {
+		m := &model.VM{
+			Base: model.Base{ID: libclient.ID(resource.ID)},
+		}
+		resource.ApplyTo(m)
+		list.Append(m)
+	}
+
+	itr = list.Iter()
+	return
+}
+
+// Get updates since last refresh.
+func (r *VMAdapter) GetUpdates(ctx *Context) (updates []Updater, err error) {
+	resources, err := r.list(ctx)
+	if err != nil {
+		return
+	}
+	listed := map[string]bool{}
+	for _, resource := range resources {
+		m := &model.VM{
+			Base: model.Base{ID: libclient.ID(resource.ID)},
+		}
+		listed[m.ID] = true
+		updates = append(
+			updates,
+			r.Upsert(m, func() {
+				resource.ApplyTo(m)
+			}))
+	}
+	deletions, err := r.DeleteUnexisting(
+		ctx,
+		&model.VM{},
+		listed,
+		func(id string) libmodel.Model {
+			return &model.VM{Base: model.Base{ID: id}}
+		})
+	updates = append(updates, deletions...)
+	return
+}
+
+// List the VMs.
+// The VMs are joined with the (attached) disks, the
+// network interfaces and the VM sizes of the location.
+func (r *VMAdapter) list(ctx *Context) (list []*VM, err error) {
+	vmList, err := client(ctx).ListVirtualMachines()
+	if err != nil {
+		return
+	}
+	diskList, err := client(ctx).ListDisks()
+	if err != nil {
+		return
+	}
+	disks := map[string]*libclient.Disk{}
+	for i := range diskList {
+		disk := &diskList[i]
+		disks[strings.ToLower(disk.ID)] = disk
+	}
+	nicList, err := client(ctx).ListNetworkInterfaces()
+	if err != nil {
+		return
+	}
+	nics := map[string]*libclient.NetworkInterface{}
+	for i := range nicList {
+		nic := &nicList[i]
+		nics[strings.ToLower(nic.ID)] = nic
+	}
+	sizes := map[string]map[string]*libclient.VMSize{}
+	for i := range vmList {
+		location := vmList[i].Location
+		if _, listed := sizes[location]; listed {
+			continue
+		}
+		var sizeList []libclient.VMSize
+		sizeList, err = client(ctx).ListVMSizes(location)
+		if err != nil {
+			return
+		}
+		sizes[location] = map[string]*libclient.VMSize{}
+		for n := range sizeList {
+			size := &sizeList[n]
+			sizes[location][size.Name] = size
+		}
+	}
+	for i := range vmList {
+		vm := &vmList[i]
+		list = append(
+			list,
+			&VM{
+				VirtualMachine: *vm,
+				disks:          disks,
+				nics:           nics,
+				size:           sizes[vm.Location][vm.Properties.HardwareProfile.VMSize],
+			})
+	}
+	return
+}
@@ -0,0 +1,183 @@
+package azure
+
+import (
+	"sort"
+	"strings"
+
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/azure"
+	libclient "github.com/kubev2v/forklift/pkg/lib/client/azure"
+)
+
+// Resource group.
+type ResourceGroup struct {
+	libclient.ResourceGroup
+}
+
+// Apply to (update) the model.
+func (r *ResourceGroup) ApplyTo(m *model.ResourceGroup) {
+	m.Name = r.Name
+	m.ResourceID = r.ID
+	m.Location = r.Location
+	m.State = r.Properties.ProvisioningState
+}
+
+// Subnet.
+type Subnet struct {
+	libclient.Subnet
+	// The virtual network.
+	network *libclient.VirtualNetwork
+}
+
+// Apply to (update) the model.
+// Subnets are named <vnet>/<subnet>.
+func (r *Subnet) ApplyTo(m *model.Subnet) {
+	m.Name = r.network.Name + "/" + r.Name
+	m.ResourceID = r.ID
+	m.ResourceGroup = libclient.ResourceGroupOf(r.ID)
+	m.VirtualNetwork = r.network.Name
+	m.Location = r.network.Location
+	m.CIDR = r.CIDR()
+}
+
+// Managed disk.
+type Disk struct {
+	libclient.Disk
+}
+
+// Apply to (update) the model.
+func (r *Disk) ApplyTo(m *model.Disk) {
+	m.Name = r.Name
+	m.ResourceID = r.ID
+	m.ResourceGroup = libclient.ResourceGroupOf(r.ID)
+	m.Location = r.Location
+	m.Size = r.Properties.DiskSizeGB
+	m.SizeBytes = r.Properties.DiskSizeBytes
+	m.Sku = r.Sku.Name
+	m.State = r.Properties.DiskState
+	m.OSType = r.Properties.OSType
+	m.HyperVGeneration = r.Properties.HyperVGeneration
+	m.VM = ""
+	if r.ManagedBy != "" {
+		m.VM = libclient.ID(r.ManagedBy)
+	}
+}
+
+// Virtual machine.
+type VM struct {
+	libclient.VirtualMachine
+	// Disks by (lowercase) resource ID.
+	disks map[string]*libclient.Disk
+	// NICs by (lowercase) resource ID.
+	nics map[string]*libclient.NetworkInterface
+	// The VM size.
+	size *libclient.VMSize
+}
+
+// Apply to (update) the model.
+func (r *VM) ApplyTo(m *model.VM) {
+	m.Name = r.Name
+	m.ResourceID = r.ID
+	m.ResourceGroup = libclient.ResourceGroupOf(r.ID)
+	m.Location = r.Location
+	m.PowerState = r.PowerState()
+	m.VMSize = r.Properties.HardwareProfile.VMSize
+	m.OSType = r.Properties.StorageProfile.OSDisk.OSType
+	m.HyperVGeneration = ""
+	m.Architecture = ""
+	m.SecurityType = ""
+	m.SecureBoot = false
+	m.TPM = false
+	if view := r.Properties.InstanceView; view != nil {
+		m.HyperVGeneration = view.HyperVGeneration
+	}
+	if profile := r.Properties.SecurityProfile; profile != nil {
+		m.SecurityType = profile.SecurityType
+		if profile.UefiSettings != nil {
+			m.SecureBoot = profile.UefiSettings.SecureBootEnabled
+			m.TPM = profile.UefiSettings.VTpmEnabled
+		}
+	}
+	m.VCPUs = 0
+	m.MemoryMB = 0
+	if r.size != nil {
+		m.VCPUs = r.size.NumberOfCores
+		m.MemoryMB = r.size.MemoryInMB
+	}
+	r.addDisks(m)
+	r.addNICs(m)
+}
+
+// Add disks.
+// The OS disk is listed first followed by the data disks by LUN.
+// The generation and architecture are reported by the OS disk.
+func (r *VM) addDisks(m *model.VM) {
+	m.Disks = []model.VMDisk{}
+	profile := r.Properties.StorageProfile
+	add := func(disk *libclient.VMDisk, os bool) {
+		d := model.VMDisk{
+			Name: disk.Name,
+			Lun:  disk.Lun,
+			OS:   os,
+			Size: disk.DiskSizeGB,
+		}
+		if disk.ManagedDisk != nil {
+			d.Managed = true
+			d.ID = libclient.ID(disk.ManagedDisk.ID)
+			d.Sku = disk.ManagedDisk.StorageAccountType
+			if managed, found := r.disks[strings.ToLower(disk.ManagedDisk.ID)]; found {
+				d.Size = managed.Properties.DiskSizeGB
+				d.Sku = managed.Sku.Name
+				if os {
+					if m.HyperVGeneration == "" {
+						m.HyperVGeneration = managed.Properties.HyperVGeneration
+					}
+					if managed.Properties.SupportedCapabilities != nil {
+						m.Architecture = managed.Properties.SupportedCapabilities.Architecture
+					}
+				}
+			}
+		}
+		m.Disks = append(m.Disks, d)
+	}
+	add(&profile.OSDisk, true)
+	dataDisks := append([]libclient.VMDisk{}, profile.DataDisks...)
+	sort.SliceStable(
+		dataDisks,
+		func(i, j int) bool {
+			return dataDisks[i].Lun < dataDisks[j].Lun
+		})
+	for i := range dataDisks {
+		add(&dataDisks[i], false)
+	}
+}
+
+// Add NICs.
+// The primary NIC is listed first.
+func (r *VM) addNICs(m *model.VM) {
+	m.NICs = []model.NIC{}
+	for _, ref := range r.Properties.NetworkProfile.NetworkInterfaces {
+		n := model.NIC{
+			ID:      libclient.ID(ref.ID),
+			Name:    libclient.NameOf(ref.ID),
+			Primary: ref.Properties.Primary,
+			IPs:     []string{},
+		}
+		if nic, found := r.nics[strings.ToLower(ref.ID)]; found {
+			n.MAC = nic.MAC()
+			for _, config := range nic.Properties.IPConfigurations {
+				if config.Properties.PrivateIPAddress != "" {
+					n.IPs = append(n.IPs, config.Properties.PrivateIPAddress)
+				}
+				if n.Subnet == "" && config.Properties.Subnet != nil {
+					n.Subnet = libclient.ID(config.Properties.Subnet.ID)
+				}
+			}
+		}
+		m.NICs = append(m.NICs, n)
+	}
+	sort.SliceStable(
+		m.NICs,
+		func(i, j int) bool {
+			return m.NICs[i].Primary && !m.NICs[j].Primary
+		})
+}
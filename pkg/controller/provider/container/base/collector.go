@@ -0,0 +1,380 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	liburl "net/url"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+	"github.com/kubev2v/forklift/pkg/lib/tracing"
+	"github.com/kubev2v/forklift/pkg/settings"
+	"go.opentelemetry.io/otel/attribute"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Application settings.
+var Settings = &settings.Settings
+
+// Settings
+const (
+	// Retry interval.
+	RetryInterval = 5 * time.Second
+	// Refresh interval.
+	RefreshInterval = 1 * time.Minute
+)
+
+// Phases
+const (
+	Started = ""
+	Load    = "load"
+	Loaded  = "loaded"
+	Parity  = "parity"
+	Refresh = "refresh"
+)
+
+// Data collector.
+// The provider collections are listed by the adapters (in order)
+// and compared with the DB. Used by the providers without a
+// change (event) stream.
+type Collector struct {
+	// Provider
+	provider *api.Provider
+	// DB client.
+	db libmodel.DB
+	// Logger.
+	log logging.LevelLogger
+	// has parity.
+	parity bool
+	// Provider API client.
+	client Client
+	// The URL of the provider API.
+	url string
+	// Model adapters.
+	adapters []Adapter
+	// VM validation.
+	validation *Validation
+	// cancel function.
+	cancel func()
+	// Start Time
+	startTime time.Time
+	// Phase
+	phase string
+	// List of watches.
+	watches []*libmodel.Watch
+}
+
+// New collector.
+// The client is closed on shutdown when it implements Close().
+func New(
+	db libmodel.DB,
+	provider *api.Provider,
+	log logging.LevelLogger,
+	url string,
+	client Client,
+	adapters []Adapter,
+	validation *Validation) (r *Collector) {
+	r = &Collector{
+		provider:   provider,
+		db:         db,
+		log:        log,
+		url:        url,
+		client:     client,
+		adapters:   adapters,
+		validation: validation,
+	}
+
+	return
+}
+
+// The name.
+func (r *Collector) Name() string {
+	url, err := liburl.Parse(r.url)
+	if err == nil {
+		return url.Host
+	}
+
+	return r.url
+}
+
+// The owner.
+func (r *Collector) Owner() meta.Object {
+	return r.provider
+}
+
+// Get the DB.
+func (r *Collector) DB() libmodel.DB {
+	return r.db
+}
+
+// Reset.
+func (r *Collector) Reset() {
+	r.parity = false
+}
+
+// Reset.
+func (r *Collector) HasParity() bool {
+	return r.parity
+}
+
+// Test connect/logout.
+func (r *Collector) Test() (status int, err error) {
+	err = r.client.Connect()
+	if r.client.IsUnauthorized(err) {
+		status = http.StatusUnauthorized
+	}
+	return
+}
+
+// NO-OP
+func (r *Collector) Version() (_, _, _, _ string, err error) {
+	return
+}
+
+// Follow link
+func (r *Collector) Follow(moRef interface{}, p []string, dst interface{}) error {
+	return fmt.Errorf("not implemented")
+}
+
+// Start the collector.
+func (r *Collector) Start() error {
+	ctx := &Context{
+		Client: r.client,
+		DB:     r.db,
+		Log:    r.log,
+	}
+	ctx.ctx, r.cancel = context.WithCancel(context.Background())
+	start := func() {
+		defer func() {
+			r.endWatch()
+			r.log.Info("Stopped.")
+		}()
+		for {
+			if !ctx.Canceled() {
+				_ = r.run(ctx)
+			} else {
+				return
+			}
+		}
+	}
+
+	go start()
+
+	return nil
+}
+
+// Run the current phase.
+func (r *Collector) run(ctx *Context) (err error) {
+	r.log.V(3).Info(
+		"Running.",
+		"phase",
+		r.phase)
+	switch r.phase {
+	case Started:
+		err = r.client.Connect()
+		if err != nil {
+			return
+		}
+		r.startTime = time.Now()
+		r.phase = Load
+	case Load:
+		err = r.load(ctx)
+		if err == nil {
+			r.phase = Loaded
+		}
+	case Loaded:
+		err = r.refresh(ctx)
+		if err == nil {
+			r.phase = Parity
+		}
+	case Parity:
+		r.endWatch()
+		err = r.beginWatch()
+		if err == nil {
+			r.phase = Refresh
+			r.parity = true
+		}
+	case Refresh:
+		err = r.refresh(ctx)
+		if err == nil {
+			r.parity = true
+			time.Sleep(RefreshInterval)
+		} else {
+			r.parity = false
+		}
+	default:
+		err = liberr.New("Phase unknown.")
+	}
+	if err != nil {
+		r.log.Error(
+			err,
+			"Failed.",
+			"phase",
+			r.phase)
+		time.Sleep(RetryInterval)
+	}
+
+	return
+}
+
+// Shutdown the collector.
+func (r *Collector) Shutdown() {
+	r.log.Info("Shutdown.")
+	if r.cancel != nil {
+		r.cancel()
+	}
+	if closer, cast := r.client.(interface{ Close() }); cast {
+		closer.Close()
+	}
+}
+
+// Load the inventory.
+func (r *Collector) load(ctx *Context) (err error) {
+	mark := time.Now()
+	for _, adapter := range r.adapters {
+		if ctx.Canceled() {
+			return
+		}
+		err = r.create(ctx, adapter)
+		if err != nil {
+			return
+		}
+	}
+	r.log.Info(
+		"Initial Parity.",
+		"duration",
+		time.Since(mark))
+	tracing.Record(
+		tracing.WithUID(context.TODO(), string(r.provider.UID)),
+		"inventory.load",
+		mark,
+		time.Now(),
+		attribute.String("provider", r.provider.Namespace+"/"+r.provider.Name))
+
+	return
+}
+
+// List and create resources using the adapter.
+// The models are inserted in batches.
+func (r *Collector) create(ctx *Context, adapter Adapter) (err error) {
+	itr, aErr := adapter.List(ctx, r.provider)
+
+	if aErr != nil {
+		err = aErr
+		return
+	}
+	batch := &libmodel.Batch{
+		DB:   r.db,
+		Size: Settings.Inventory.BatchSize,
+	}
+	defer func() {
+		_ = batch.End()
+	}()
+	for {
+		object, hasNext := itr.Next()
+		if !hasNext {
+			break
+		}
+		if ctx.Canceled() {
+			return
+		}
+		m := object.(libmodel.Model)
+		err = batch.With(func(tx *libmodel.Tx) error {
+			return tx.Insert(m)
+		})
+		if err != nil {
+			return
+		}
+	}
+	err = batch.Commit()
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// Add model watches.
+func (r *Collector) beginWatch() (err error) {
+	defer func() {
+		if err != nil {
+			r.endWatch()
+		}
+	}()
+	w, err := r.db.Watch(
+		r.validation.VM(""),
+		&VMEventHandler{
+			Provider:   r.provider,
+			DB:         r.db,
+			Validation: r.validation,
+			log:        r.log,
+		})
+
+	if err == nil {
+		r.watches = append(r.watches, w)
+	} else {
+		return
+	}
+	return
+}
+
+// End watches.
+func (r *Collector) endWatch() {
+	for _, watch := range r.watches {
+		watch.End()
+	}
+}
+
+// Refresh the inventory.
+//   - List the collections.
+//   - Build the changeSet.
+//   - Apply the changeSet.
+//
+// The two-phased approach ensures we do not hold the
+// DB transaction while using the provider API which
+// can block or be slow.
+func (r *Collector) refresh(ctx *Context) (err error) {
+	var updates []Updater
+	mark := time.Now()
+	for _, adapter := range r.adapters {
+		if ctx.Canceled() {
+			return
+		}
+		updates, err = adapter.GetUpdates(ctx)
+		if err != nil {
+			return
+		}
+		err = r.apply(updates)
+		if err != nil {
+			return
+		}
+	}
+	r.log.V(3).Info(
+		"Refresh finished.",
+		"duration",
+		time.Since(mark))
+	return
+}
+
+// Apply the changeSet.
+func (r *Collector) apply(changeSet []Updater) (err error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = tx.End()
+	}()
+	for _, updater := range changeSet {
+		err = updater(tx)
+		if err != nil {
+			return
+		}
+	}
+	err = tx.Commit()
+	return
+}
@@ -0,0 +1,242 @@
+package base
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	refapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/base"
+	"github.com/kubev2v/forklift/pkg/controller/validation/policy"
+	fb "github.com/kubev2v/forklift/pkg/lib/filebacked"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+	. "github.com/onsi/gomega"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var errUnauthorized = errors.New("unauthorized")
+
+// VM model.
+type TestVM struct {
+	ID                string          `sql:"pk"`
+	Name              string          `sql:"d0"`
+	Revision          int64           `sql:"incremented,d0,index(revision)"`
+	RevisionValidated int64           `sql:"d0,index(revisionValidated)"`
+	PolicyVersion     int             `sql:"d0,index(policyVersion)"`
+	Concerns          []model.Concern `sql:""`
+}
+
+func (m *TestVM) Pk() string              { return m.ID }
+func (m *TestVM) String() string          { return m.ID }
+func (m *TestVM) Labels() libmodel.Labels { return nil }
+func (m *TestVM) GetRevision() int64      { return m.Revision }
+func (m *TestVM) Validated() bool         { return m.RevisionValidated == m.Revision }
+func (m *TestVM) Validate(revision int64, version int, concerns []model.Concern) bool {
+	if m.Revision != revision {
+		return false
+	}
+	m.PolicyVersion = version
+	m.RevisionValidated = revision
+	m.Concerns = concerns
+	m.Revision--
+	return true
+}
+
+// Provider API client.
+type testClient struct {
+	// VM names listed by ID.
+	vms map[string]string
+	// Connect error.
+	err error
+	// Closed.
+	closed bool
+}
+
+func (r *testClient) Connect() error                { return r.err }
+func (r *testClient) IsUnauthorized(err error) bool { return errors.Is(err, errUnauthorized) }
+func (r *testClient) Close()                        { r.closed = true }
+
+// VM adapter.
+type testAdapter struct {
+	BaseAdapter
+}
+
+func (r *testAdapter) List(ctx *Context, provider *api.Provider) (itr fb.Iterator, err error) {
+	list := fb.NewList()
+	for id, name := range ctx.Client.(*testClient).vms {
+		list.Append(&TestVM{ID: id, Name: name})
+	}
+	itr = list.Iter()
+	return
+}
+
+func (r *testAdapter) GetUpdates(ctx *Context) (updates []Updater, err error) {
+	listed := map[string]bool{}
+	for id, name := range ctx.Client.(*testClient).vms {
+		m := &TestVM{ID: id}
+		listed[id] = true
+		updates = append(
+			updates,
+			r.Upsert(m, func() {
+				m.Name = name
+			}))
+	}
+	deletions, err := r.DeleteUnexisting(
+		ctx,
+		&TestVM{},
+		listed,
+		func(id string) libmodel.Model {
+			return &TestVM{ID: id}
+		})
+	updates = append(updates, deletions...)
+	return
+}
+
+// VM validation.
+var testValidation = &Validation{
+	Endpoint: "/v1/data/io/konveyor/forklift/test/",
+	VM: func(id string) VM {
+		return &TestVM{ID: id}
+	},
+	Workload: func(db libmodel.DB, provider *api.Provider, vmID string) (object interface{}, err error) {
+		vm := &TestVM{ID: vmID}
+		err = db.Get(vm)
+		object = vm
+		return
+	},
+}
+
+// Build the collector and open the DB.
+func newCollector(t *testing.T, client *testClient) (collector *Collector, ctx *Context) {
+	provider := &api.Provider{
+		ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "test", UID: "test-uid"},
+	}
+	db := libmodel.New(filepath.Join(t.TempDir(), "test.db"), &TestVM{})
+	err := db.Open(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = db.Close(true)
+	})
+	log := logging.WithName("test")
+	collector = New(db, provider, log, "https://host.example.com:8443/api", client, []Adapter{&testAdapter{}}, testValidation)
+	ctx = NewContext(context.TODO(), client, db, log)
+	return
+}
+
+// The inventory is loaded and refreshed. The validation
+// of the VMs is retained and the models no longer listed
+// are deleted.
+func TestCollector(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	client := &testClient{vms: map[string]string{"vm-1": "vm-1", "vm-2": "vm-2"}}
+	collector, ctx := newCollector(t, client)
+	g.Expect(collector.Name()).To(Equal("host.example.com:8443"))
+	g.Expect(collector.Owner().GetName()).To(Equal("test"))
+
+	// Load.
+	g.Expect(collector.load(ctx)).To(Succeed())
+	vms := []TestVM{}
+	g.Expect(collector.DB().List(&vms, libmodel.ListOptions{})).To(Succeed())
+	g.Expect(vms).To(HaveLen(2))
+
+	// Validated.
+	vm := &TestVM{ID: "vm-1"}
+	g.Expect(collector.DB().Get(vm)).To(Succeed())
+	handler := &VMEventHandler{
+		Provider:   collector.provider,
+		DB:         collector.DB(),
+		Validation: testValidation,
+		log:        logging.WithName("test"),
+	}
+	concerns := []model.Concern{{Label: "TPM", Category: "Warning"}}
+	handler.validated(
+		[]*policy.Task{
+			{Ref: refapi.Ref{ID: "vm-1"}, Revision: vm.Revision, Version: 2, Concerns: concerns},
+		})
+	g.Expect(collector.DB().Get(vm)).To(Succeed())
+	g.Expect(vm.Validated()).To(BeTrue())
+	g.Expect(vm.PolicyVersion).To(Equal(2))
+
+	// Refresh.
+	client.vms = map[string]string{"vm-1": "renamed", "vm-3": "vm-3"}
+	g.Expect(collector.refresh(ctx)).To(Succeed())
+	vm = &TestVM{ID: "vm-1"}
+	g.Expect(collector.DB().Get(vm)).To(Succeed())
+	g.Expect(vm.Name).To(Equal("renamed"))
+	g.Expect(vm.PolicyVersion).To(Equal(2))
+	g.Expect(vm.Concerns).To(HaveLen(1))
+	g.Expect(vm.Validated()).To(BeFalse())
+	g.Expect(collector.DB().Get(&TestVM{ID: "vm-3"})).To(Succeed())
+	g.Expect(collector.DB().Get(&TestVM{ID: "vm-2"})).To(MatchError(libmodel.NotFound))
+
+	// Workload.
+	object, err := handler.workload("vm-1")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(object.(*TestVM).Name).To(Equal("renamed"))
+}
+
+// The validation results are applied to the VMs not
+// updated since they were submitted.
+func TestValidated(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	client := &testClient{vms: map[string]string{"vm-1": "vm-1", "vm-2": "vm-2"}}
+	collector, ctx := newCollector(t, client)
+	g.Expect(collector.load(ctx)).To(Succeed())
+	stale := &TestVM{ID: "vm-2"}
+	g.Expect(collector.DB().Get(stale)).To(Succeed())
+	revision := stale.Revision
+	stale.Name = "updated"
+	g.Expect(collector.DB().Update(stale)).To(Succeed())
+	handler := &VMEventHandler{
+		Provider:   collector.provider,
+		DB:         collector.DB(),
+		Validation: testValidation,
+		log:        logging.WithName("test"),
+	}
+	handler.validated(
+		[]*policy.Task{
+			{Ref: refapi.Ref{ID: "vm-1"}, Revision: revision, Version: 2},
+			{Ref: refapi.Ref{ID: "vm-2"}, Revision: revision, Version: 2},
+			{Ref: refapi.Ref{ID: "vm-gone"}, Revision: revision, Version: 2},
+		})
+	vm := &TestVM{ID: "vm-1"}
+	g.Expect(collector.DB().Get(vm)).To(Succeed())
+	g.Expect(vm.Validated()).To(BeTrue())
+	g.Expect(vm.Revision).To(Equal(revision))
+	vm = &TestVM{ID: "vm-2"}
+	g.Expect(collector.DB().Get(vm)).To(Succeed())
+	g.Expect(vm.Validated()).To(BeFalse())
+	g.Expect(vm.PolicyVersion).To(Equal(0))
+}
+
+// The collector reaches parity and the client is
+// closed on shutdown.
+func TestStart(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	client := &testClient{vms: map[string]string{"vm-1": "vm-1"}}
+	collector, _ := newCollector(t, client)
+	status, err := collector.Test()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(status).To(Equal(0))
+	g.Expect(collector.Start()).To(Succeed())
+	g.Eventually(collector.HasParity, 5*time.Second).Should(BeTrue())
+	g.Expect(collector.DB().Get(&TestVM{ID: "vm-1"})).To(Succeed())
+	collector.Shutdown()
+	g.Expect(client.closed).To(BeTrue())
+
+	// Unauthorized.
+	client.err = errUnauthorized
+	status, err = collector.Test()
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(status).To(Equal(http.StatusUnauthorized))
+}
@@ -0,0 +1,122 @@
+package base
+
+import (
+	"context"
+	"errors"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	fb "github.com/kubev2v/forklift/pkg/lib/filebacked"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+)
+
+// Updates the DB based on
+// changes described by an Event.
+type Updater func(tx *libmodel.Tx) error
+
+// Provider API client.
+type Client interface {
+	// Connect.
+	// Validates the URL and the credentials.
+	Connect() error
+	// The error is unauthorized.
+	IsUnauthorized(err error) bool
+}
+
+// Adapter context.
+type Context struct {
+	// Context.
+	ctx context.Context
+	// Provider API client.
+	Client Client
+	// Log.
+	Log logging.LevelLogger
+	// DB client.
+	DB libmodel.DB
+}
+
+// New adapter context.
+func NewContext(ctx context.Context, client Client, db libmodel.DB, log logging.LevelLogger) *Context {
+	return &Context{
+		ctx:    ctx,
+		Client: client,
+		DB:     db,
+		Log:    log,
+	}
+}
+
+// The adapter request is canceled.
+func (r *Context) Canceled() (done bool) {
+	select {
+	case <-r.ctx.Done():
+		done = true
+	default:
+	}
+
+	return
+}
+
+// Model adapter.
+// Provides integration between the provider resource
+// model and the inventory model.
+type Adapter interface {
+	// List the collection.
+	List(ctx *Context, provider *api.Provider) (itr fb.Iterator, err error)
+	// Get the updates (and deletions) since the last refresh.
+	// The provider has no change (event) stream so the
+	// collection is listed and compared with the DB.
+	GetUpdates(ctx *Context) (updates []Updater, err error)
+}
+
+// Base adapter.
+type BaseAdapter struct {
+}
+
+// Build the updater that creates or updates the model.
+// The model is applied after it's fetched so the fields
+// not reported by the provider (e.g. validation) are retained.
+func (r *BaseAdapter) Upsert(m libmodel.Model, apply func()) Updater {
+	return func(tx *libmodel.Tx) (err error) {
+		err = tx.Get(m)
+		if err != nil {
+			if errors.Is(err, libmodel.NotFound) {
+				apply()
+				err = tx.Insert(m)
+			}
+			return
+		}
+		apply()
+		err = tx.Update(m)
+		return
+	}
+}
+
+// Build the updaters that delete the models (of the kind)
+// no longer reported by the provider.
+func (r *BaseAdapter) DeleteUnexisting(
+	ctx *Context,
+	kind libmodel.Model,
+	listed map[string]bool,
+	build func(id string) libmodel.Model) (deletions []Updater, err error) {
+	itr, err := ctx.DB.Find(kind, libmodel.ListOptions{})
+	if err != nil {
+		return
+	}
+	for {
+		object, hasNext := itr.Next()
+		if !hasNext {
+			break
+		}
+		id := object.(libmodel.Model).Pk()
+		if listed[id] {
+			continue
+		}
+		m := build(id)
+		deletions = append(
+			deletions,
+			func(tx *libmodel.Tx) error {
+				return tx.Delete(m)
+			})
+	}
+	return
+}
@@ -0,0 +1,353 @@
+package base
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	refapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/base"
+	"github.com/kubev2v/forklift/pkg/controller/validation/policy"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+)
+
+const (
+	// The (max) number of batched task results.
+	MaxBatch = 1024
+	// Transaction label.
+	ValidationLabel = "VM-validated"
+)
+
+// Validated VM model.
+type VM interface {
+	libmodel.Model
+	// The revision.
+	GetRevision() int64
+	// The VM has been validated.
+	Validated() bool
+	// Apply the validation (policy) results of the revision.
+	// False when the VM has been updated since.
+	Validate(revision int64, version int, concerns []model.Concern) bool
+}
+
+// VM validation.
+type Validation struct {
+	// The (policy) endpoint of the provider type.
+	// Example: /v1/data/io/konveyor/forklift/ec2/
+	Endpoint string
+	// Build the VM model with the ID.
+	VM func(id string) VM
+	// Build the workload of the VM.
+	Workload func(db libmodel.DB, provider *api.Provider, vmID string) (interface{}, error)
+}
+
+// The rules version endpoint.
+func (r *Validation) VersionEndpoint() string {
+	return r.Endpoint + "rules_version"
+}
+
+// The validation endpoint.
+func (r *Validation) ValidationEndpoint() string {
+	return r.Endpoint + "validate"
+}
+
+// Watch for VM changes and validate as needed.
+type VMEventHandler struct {
+	libmodel.StockEventHandler
+	// Provider.
+	Provider *api.Provider
+	// DB.
+	DB libmodel.DB
+	// VM validation.
+	Validation *Validation
+	// Validation event latch.
+	latch chan int8
+	// Last search.
+	lastSearch time.Time
+	// Logger.
+	log logging.LevelLogger
+	// Context
+	context context.Context
+	// Context cancel.
+	cancel context.CancelFunc
+	// Task result
+	taskResult chan *policy.Task
+}
+
+// Reset.
+func (r *VMEventHandler) reset() {
+	r.lastSearch = time.Now()
+}
+
+// Watch ended.
+func (r *VMEventHandler) Started(uint64) {
+	r.log.Info("Started.")
+	r.taskResult = make(chan *policy.Task)
+	r.latch = make(chan int8, 1)
+	r.context, r.cancel = context.WithCancel(context.Background())
+	go r.run()
+	go r.harvest()
+}
+
+// VM Created.
+// The VM is scheduled (and reported as scheduled).
+// This is best-effort.  If the validate() fails, it wil be
+// picked up in the next search().
+func (r *VMEventHandler) Created(event libmodel.Event) {
+	if r.canceled() {
+		return
+	}
+	if vm, cast := event.Model.(VM); cast {
+		if !vm.Validated() {
+			r.tripLatch()
+		}
+	}
+}
+
+// VM Updated.
+// The VM is scheduled (and reported as scheduled).
+// This is best-effort.  If the validate() fails, it wil be
+// picked up in the next search().
+func (r *VMEventHandler) Updated(event libmodel.Event) {
+	if r.canceled() {
+		return
+	}
+	if event.HasLabel(ValidationLabel) {
+		return
+	}
+	if vm, cast := event.Updated.(VM); cast {
+		if !vm.Validated() {
+			r.tripLatch()
+		}
+	}
+}
+
+// Report errors.
+func (r *VMEventHandler) Error(err error) {
+	r.log.Error(liberr.Wrap(err), err.Error())
+}
+
+// Watch ended.
+func (r *VMEventHandler) End() {
+	r.log.Info("Ended.")
+	r.cancel()
+	close(r.latch)
+	close(r.taskResult)
+}
+
+// Trip the validation event latch.
+func (r *VMEventHandler) tripLatch() {
+	defer func() {
+		_ = recover()
+	}()
+	select {
+	case r.latch <- 1:
+		// trip.
+	default:
+		// tripped.
+	}
+}
+
+// Run.
+// Periodically search for VMs that need to be validated.
+func (r *VMEventHandler) run() {
+	r.log.Info("Run started.")
+	defer r.log.Info("Run stopped.")
+	interval := time.Second * time.Duration(
+		Settings.PolicyAgent.SearchInterval)
+	r.list()
+	r.reset()
+	for {
+		select {
+		case <-time.After(interval):
+			r.list()
+			r.reset()
+		case _, open := <-r.latch:
+			if open {
+				r.list()
+				r.reset()
+			} else {
+				return
+			}
+		}
+	}
+}
+
+// Harvest validation task results and update VMs.
+// Collect completed tasks in batches. Apply the batch
+// to VMs when one of:
+//   - The batch is full.
+//   - No tasks have been received within
+//     the delay period.
+func (r *VMEventHandler) harvest() {
+	r.log.Info("Harvest started.")
+	defer r.log.Info("Harvest stopped.")
+	long := time.Hour
+	short := time.Second
+	delay := long
+	batch := []*policy.Task{}
+	mark := time.Now()
+	for {
+		select {
+		case <-time.After(delay):
+		case task, open := <-r.taskResult:
+			if open {
+				batch = append(batch, task)
+				delay = short
+			} else {
+				return
+			}
+		}
+		if time.Since(mark) > delay || len(batch) > MaxBatch {
+			r.validated(batch)
+			batch = []*policy.Task{}
+			delay = long
+			mark = time.Now()
+		}
+	}
+}
+
+// List for VMs to be validated.
+// VMs that have been reported through the model event
+// watch are ignored.
+func (r *VMEventHandler) list() {
+	r.log.V(3).Info("List VMs that need to be validated.")
+	version, err := policy.Agent.Version(r.Validation.VersionEndpoint())
+	if err != nil {
+		r.log.Error(err, err.Error())
+		return
+	}
+	if r.canceled() {
+		return
+	}
+	itr, err := r.DB.Find(
+		r.Validation.VM(""),
+		libmodel.ListOptions{
+			Predicate: libmodel.Or(
+				libmodel.Neq("Revision", libmodel.Field{Name: "RevisionValidated"}),
+				libmodel.Neq("PolicyVersion", version)),
+		})
+	if err != nil {
+		r.log.Error(err, "List VM failed.")
+		return
+	}
+	if itr.Len() > 0 {
+		r.log.V(3).Info(
+			"List (unvalidated) VMs found.",
+			"count",
+			itr.Len())
+	}
+	for {
+		vm := r.Validation.VM("")
+		hasNext := itr.NextWith(vm)
+		if !hasNext || r.canceled() {
+			break
+		}
+		_ = r.validate(vm)
+	}
+}
+
+// Handler canceled.
+func (r *VMEventHandler) canceled() bool {
+	select {
+	case <-r.context.Done():
+		return true
+	default:
+		return false
+	}
+}
+
+// Analyze the VM.
+func (r *VMEventHandler) validate(vm VM) (err error) {
+	task := &policy.Task{
+		Path:     r.Validation.ValidationEndpoint(),
+		Context:  r.context,
+		Workload: r.workload,
+		Result:   r.taskResult,
+		Revision: vm.GetRevision(),
+		Ref: refapi.Ref{
+			ID: vm.Pk(),
+		},
+	}
+	r.log.V(4).Info(
+		"Validate VM.",
+		"VMID",
+		vm.Pk())
+	err = policy.Agent.Submit(task)
+	if err != nil {
+		r.log.Error(err, "VM task (submit) failed.")
+	}
+
+	return
+}
+
+// VMs validated.
+func (r *VMEventHandler) validated(batch []*policy.Task) {
+	if len(batch) == 0 {
+		return
+	}
+	r.log.V(3).Info(
+		"VM (batch) completed.",
+		"count",
+		len(batch))
+	tx, err := r.DB.Begin(ValidationLabel)
+	if err != nil {
+		r.log.Error(err, "Begin tx failed.")
+		return
+	}
+	defer func() {
+		_ = tx.End()
+	}()
+	for _, task := range batch {
+		if task.Error != nil {
+			r.log.Error(
+				task.Error, "VM validation failed.")
+
+			if len(task.Concerns) == 0 {
+				continue
+			}
+			// If there are concerns we need to update and commit the changes
+		}
+		latest := r.Validation.VM(task.Ref.ID)
+		err = tx.Get(latest)
+		if err != nil {
+			r.log.Error(err, "VM (get) failed.")
+			continue
+		}
+		if !latest.Validate(task.Revision, task.Version, task.Concerns) {
+			continue
+		}
+		err = tx.Update(latest, libmodel.Eq("Revision", task.Revision))
+		if errors.Is(err, libmodel.NotFound) {
+			continue
+		}
+		if err != nil {
+			r.log.Error(err, "VM update failed.")
+			continue
+		}
+		if task.Error == nil {
+			r.log.V(3).Info(
+				"VM validated.",
+				"vmID",
+				latest.Pk(),
+				"revision",
+				task.Revision,
+				"duration",
+				task.Duration())
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		r.log.Error(err, "Tx commit failed.")
+		return
+	}
+}
+
+// Build the workload.
+func (r *VMEventHandler) workload(vmID string) (object interface{}, err error) {
+	object, err = r.Validation.Workload(r.DB, r.Provider, vmID)
+	return
+}
@@ -0,0 +1,23 @@
+package catalog
+
+import (
+	libclient "github.com/kubev2v/forklift/pkg/lib/client/image"
+)
+
+// Client struct
+type Client struct {
+	libclient.Client
+}
+
+// Connect.
+// Validates the URL and the credentials. The
+// catalog (manifest) is read.
+func (r *Client) Connect() (err error) {
+	_, err = r.ReadCatalog()
+	return
+}
+
+// The client of the adapter context.
+func client(ctx *Context) *Client {
+	return ctx.Client.(*Client)
+}
@@ -0,0 +1,341 @@
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/controller/provider/container/base"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/catalog"
+	libclient "github.com/kubev2v/forklift/pkg/lib/client/image"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Fake (http) share.
+// Serves the catalog (manifest) and the images.
+type share struct {
+	mutex sync.Mutex
+	// Password (of the admin user).
+	password string
+	// Content by path.
+	files map[string][]byte
+	// Number of requests by path.
+	requested map[string]int
+}
+
+// Set the content of a file.
+// The file is deleted when the content is nil.
+func (r *share) set(path string, content []byte) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if content == nil {
+		delete(r.files, path)
+		return
+	}
+	r.files[path] = content
+}
+
+// Number of requests for the file.
+func (r *share) count(path string) int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.requested[path]
+}
+
+// Serve the share.
+func (r *share) serve(t *testing.T) (server *httptest.Server) {
+	r.requested = map[string]int{}
+	server = httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, request *http.Request) {
+			user, password, _ := request.BasicAuth()
+			if user != "admin" || password != r.password {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			r.mutex.Lock()
+			defer r.mutex.Unlock()
+			path := request.URL.Path
+			r.requested[path]++
+			content, found := r.files[path]
+			if !found {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			http.ServeContent(w, request, path, time.Unix(0, 0), bytes.NewReader(content))
+		}))
+	t.Cleanup(server.Close)
+	return
+}
+
+// Build a qcow2 image.
+func qcow2(capacity uint64) (content []byte) {
+	content = make([]byte, 1024)
+	copy(content, "QFI\xfb")
+	binary.BigEndian.PutUint64(content[24:], capacity)
+	return
+}
+
+// The ID of the (named) VM.
+func vmID(name string) string {
+	vm := &VM{}
+	vm.Name = name
+	return vm.ID()
+}
+
+// Build the client and the collector and open the DB.
+func newCollector(t *testing.T, url string) (collector *base.Collector, ctx *Context) {
+	provider := &api.Provider{
+		ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "catalog", UID: "catalog-uid"},
+		Spec:       api.ProviderSpec{URL: url},
+	}
+	secret := &core.Secret{
+		Data: map[string][]byte{
+			libclient.AccessKeyID: []byte("admin"),
+			libclient.SecretKey:   []byte("secret"),
+		},
+	}
+	db := libmodel.New(filepath.Join(t.TempDir(), "catalog.db"), model.All()...)
+	err := db.Open(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = db.Close(true)
+	})
+	collector = New(db, provider, secret)
+	client := &Client{}
+	client.URL = url
+	client.LoadOptionsFromSecret(secret)
+	ctx = base.NewContext(context.TODO(), client, db, logging.WithName("test"))
+	return
+}
+
+// Load the inventory using the adapters.
+func load(ctx *Context) (err error) {
+	for _, adapter := range adapterList {
+		itr, lErr := adapter.List(ctx, nil)
+		if lErr != nil {
+			err = lErr
+			return
+		}
+		for {
+			object, hasNext := itr.Next()
+			if !hasNext {
+				break
+			}
+			err = ctx.DB.Insert(object.(libmodel.Model))
+			if err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// Refresh the inventory using the adapters.
+func refresh(ctx *Context) (err error) {
+	for _, adapter := range adapterList {
+		updates, uErr := adapter.GetUpdates(ctx)
+		if uErr != nil {
+			err = uErr
+			return
+		}
+		err = ctx.DB.With(func(tx *libmodel.Tx) (err error) {
+			for _, updater := range updates {
+				err = updater(tx)
+				if err != nil {
+					return
+				}
+			}
+			return
+		})
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// The inventory is loaded and refreshed. The images are
+// probed once, the duplicate names are skipped and the
+// entries that are not valid are listed with the error.
+// The validation of the VMs is retained and the models
+// no longer listed are deleted.
+func TestCollector(t *testing.T) {
+	fake := &share{
+		password: "secret",
+		files: map[string][]byte{
+			"/catalogs/catalog.yaml": []byte(`images:
+- name: web
+  image: ../disks/rhel9.qcow2
+  count: 2
+  cpu: 2
+  memory: 4Gi
+  firmware: EFI
+  os: rhel9
+- name: web-1
+  image: ../disks/other.qcow2
+- name: db
+  image: ../disks/gone.img
+- name: bad
+  image: ../disks/rhel9.qcow2
+  firmware: uefi
+`),
+			"/disks/rhel9.qcow2": qcow2(10 << 30),
+		},
+	}
+	server := fake.serve(t)
+	url := server.URL + "/catalogs/catalog.yaml"
+	collector, ctx := newCollector(t, url)
+	if collector.Name() != strings.TrimPrefix(server.URL, "http://") {
+		t.Errorf("unexpected name: %s", collector.Name())
+	}
+	status, err := collector.Test()
+	if err != nil || status != 0 {
+		t.Fatalf("unexpected connection test: %d %v", status, err)
+	}
+
+	// Load.
+	err = load(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	storage := &model.Storage{Base: model.Base{ID: model.DefaultID}}
+	err = ctx.DB.Get(storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if storage.URL != url || storage.ObjectStorage {
+		t.Errorf("unexpected storage: %+v", storage)
+	}
+	err = ctx.DB.Get(&model.Network{Base: model.Base{ID: model.DefaultID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	vms := []model.VM{}
+	err = ctx.DB.List(&vms, model.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vms) != 4 {
+		t.Errorf("unexpected vms: %+v", vms)
+	}
+	for _, name := range []string{"web-1", "web-2"} {
+		vm := &model.VM{Base: model.Base{ID: vmID(name)}}
+		err = ctx.DB.Get(vm)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if vm.Name != name || vm.URL != server.URL+"/disks/rhel9.qcow2" || vm.Format != libclient.Qcow2 ||
+			vm.Capacity != 10<<30 || vm.CPU != 2 || vm.Memory != 4<<30 || vm.Firmware != libclient.EFI ||
+			vm.OS != "rhel9" || vm.CatalogError != "" || vm.Storage != model.DefaultID {
+			t.Errorf("unexpected vm: %+v", vm)
+		}
+	}
+	if n := fake.count("/disks/rhel9.qcow2"); n != 1 {
+		t.Errorf("expected the image to be probed once: %d", n)
+	}
+	if n := fake.count("/disks/other.qcow2"); n != 0 {
+		t.Errorf("expected the duplicate name to be skipped: %d", n)
+	}
+	vm := &model.VM{Base: model.Base{ID: vmID("db")}}
+	err = ctx.DB.Get(vm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vm.Format != "" || vm.CatalogError != "" {
+		t.Errorf("expected the vm without the format: %+v", vm)
+	}
+	vm = &model.VM{Base: model.Base{ID: vmID("bad")}}
+	err = ctx.DB.Get(vm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vm.CatalogError == "" || vm.Format != "" {
+		t.Errorf("expected the vm with the error: %+v", vm)
+	}
+
+	// Validated.
+	vm = &model.VM{Base: model.Base{ID: vmID("web-1")}}
+	err = ctx.DB.Get(vm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.PolicyVersion = 1
+	vm.RevisionValidated = vm.Revision
+	vm.Revision--
+	err = ctx.DB.Update(vm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Refresh.
+	fake.set("/catalogs/catalog.yaml", []byte(`images:
+- name: web
+  image: ../disks/rhel9.qcow2
+  cpu: 4
+- name: db
+  image: ../disks/gone.img
+`))
+	fake.set("/disks/gone.img", make([]byte, 2048))
+	err = refresh(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"web-1", "web-2", "bad"} {
+		err = ctx.DB.Get(&model.VM{Base: model.Base{ID: vmID(name)}})
+		if !errors.Is(err, model.NotFound) {
+			t.Errorf("expected the vm (%s) to be deleted: %v", name, err)
+		}
+	}
+	vm = &model.VM{Base: model.Base{ID: vmID("web")}}
+	err = ctx.DB.Get(vm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vm.CPU != 4 || vm.Format != libclient.Qcow2 {
+		t.Errorf("unexpected vm: %+v", vm)
+	}
+	vm = &model.VM{Base: model.Base{ID: vmID("db")}}
+	err = ctx.DB.Get(vm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vm.Format != libclient.Raw || vm.Capacity != 2048 || vm.PolicyVersion != 0 {
+		t.Errorf("unexpected vm: %+v", vm)
+	}
+
+	// Workload.
+	object, err := workload(ctx.DB, &api.Provider{ObjectMeta: meta.ObjectMeta{UID: "catalog-uid"}}, vmID("web"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := json.Marshal(object)
+	if !strings.Contains(string(raw), `"storages":[{"id":"`+model.DefaultID+`"`) {
+		t.Errorf("unexpected workload: %s", raw)
+	}
+}
+
+// The connection test reports the rejected credentials.
+func TestCollectorUnauthorized(t *testing.T) {
+	fake := &share{password: "other", files: map[string][]byte{}}
+	server := fake.serve(t)
+	collector, _ := newCollector(t, server.URL+"/catalogs/catalog.yaml")
+	status, err := collector.Test()
+	if err == nil || status != http.StatusUnauthorized {
+		t.Errorf("unexpected connection test: %d %v", status, err)
+	}
+}
@@ -0,0 +1,206 @@
+package catalog
+
+import (
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/controller/provider/container/base"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/catalog"
+	libclient "github.com/kubev2v/forklift/pkg/lib/client/image"
+	fb "github.com/kubev2v/forklift/pkg/lib/filebacked"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+)
+
+// All adapters.
+var adapterList []Adapter
+
+func init() {
+	adapterList = []Adapter{
+		&StorageAdapter{},
+		&NetworkAdapter{},
+		&VMAdapter{},
+	}
+}
+
+// Types
+type Updater = base.Updater
+type Context = base.Context
+type Adapter = base.Adapter
+type BaseAdapter = base.BaseAdapter
+
+// Storage adapter.
+// The (single) storage is the share.
+type StorageAdapter struct {
+	BaseAdapter
+}
+
+// List the collection.
+func (r *StorageAdapter) List(ctx *Context, provider *api.Provider) (itr fb.Iterator, err error) {
+	list := fb.NewList()
+	m := &model.Storage{
+		Base: model.Base{ID: model.DefaultID},
+	}
+	r.resource(ctx).ApplyTo(m)
+	list.Append(m)
+
+	itr = list.Iter()
+	return
+}
+
+// Get updates since last refresh.
+func (r *StorageAdapter) GetUpdates(ctx *Context) (updates []Updater, err error) {
+	m := &model.Storage{
+		Base: model.Base{ID: model.DefaultID},
+	}
+	updates = append(
+		updates,
+		r.Upsert(m, func() {
+			r.resource(ctx).ApplyTo(m)
+		}))
+	return
+}
+
+// The storage (resource).
+func (r *StorageAdapter) resource(ctx *Context) *Storage {
+	return &Storage{
+		URL:           client(ctx).URL,
+		ObjectStorage: client(ctx).IsObjectStorage(),
+	}
+}
+
+// Network adapter.
+// The (single) network has nothing attached and is only
+// reported so it can be mapped.
+type NetworkAdapter struct {
+	BaseAdapter
+}
+
+// List the collection.
+func (r *NetworkAdapter) List(ctx *Context, provider *api.Provider) (itr fb.Iterator, err error) {
+	list := fb.NewList()
+	m := &model.Network{
+		Base: model.Base{ID: model.DefaultID},
+	}
+	resource := &Network{}
+	resource.ApplyTo(m)
+	list.Append(m)
+
+	itr = list.Iter()
+	return
+}
+
+// Get updates since last refresh.
+func (r *NetworkAdapter) GetUpdates(ctx *Context) (updates []Updater, err error) {
+	m := &model.Network{
+		Base: model.Base{ID: model.DefaultID},
+	}
+	resource := &Network{}
+	updates = append(
+		updates,
+		r.Upsert(m, func() {
+			resource.ApplyTo(m)
+		}))
+	return
+}
+
+// VM adapter.
+type VMAdapter struct {
+	BaseAdapter
+}
+
+// List the collection.
+func (r *VMAdapter) List(ctx *Context, provider *api.Provider) (itr fb.Iterator, err error) {
+	resources, err := r.list(ctx)
+	if err != nil {
+		return
+	}
+	list := fb.NewList()
+	for _, resource := range resources {
+		m := &model.VM{
+			Base: model.Base{ID: resource.ID()},
+		}
+		resource.ApplyTo(m)
+		list.Append(m)
+	}
+
+	itr = list.Iter()
+	return
+}
+
+// Get updates since last refresh.
+func (r *VMAdapter) GetUpdates(ctx *Context) (updates []Updater, err error) {
+	resources, err := r.list(ctx)
+	if err != nil {
+		return
+	}
+	listed := map[string]bool{}
+	for _, resource := range resources {
+		m := &model.VM{
+			Base: model.Base{ID: resource.ID()},
+		}
+		listed[m.ID] = true
+		updates = append(
+			updates,
+			r.Upsert(m, func() {
+				resource.ApplyTo(m)
+			}))
+	}
+	deletions, err := r.DeleteUnexisting(
+		ctx,
+		&model.VM{},
+		listed,
+		func(id string) libmodel.Model {
+			return &model.VM{Base: model.Base{ID: id}}
+		})
+	updates = append(updates, deletions...)
+	return
+}
+
+// List the catalog items (VMs) with the probed images.
+// The images are probed once; an image is usually listed
+// by many items. The items with a duplicate name are skipped
+// and the items with an image that is not found are listed
+// without the format.
+func (r *VMAdapter) list(ctx *Context) (list []*VM, err error) {
+	items, err := client(ctx).ListCatalog()
+	if err != nil {
+		return
+	}
+	probed := map[string]*libclient.Image{}
+	listed := map[string]bool{}
+	for i := range items {
+		if ctx.Canceled() {
+			return
+		}
+		vm := &VM{CatalogItem: items[i]}
+		if listed[vm.Name] {
+			ctx.Log.Info(
+				"Duplicate catalog (VM) name skipped.",
+				"name",
+				vm.Name)
+			continue
+		}
+		listed[vm.Name] = true
+		if vm.Error != "" {
+			list = append(list, vm)
+			continue
+		}
+		image, found := probed[vm.URL]
+		if !found {
+			image = &libclient.Image{
+				Path: vm.Path,
+				URL:  vm.URL,
+			}
+			err = client(ctx).Probe(image)
+			if err != nil {
+				if !client(ctx).IsNotFound(err) {
+					return
+				}
+				err = nil
+				image.Format = ""
+			}
+			probed[vm.URL] = image
+		}
+		vm.Image = *image
+		list = append(list, vm)
+	}
+	return
+}
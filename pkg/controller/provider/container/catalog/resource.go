@@ -0,0 +1,63 @@
+package catalog
+
+import (
+	"github.com/google/uuid"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/catalog"
+	libclient "github.com/kubev2v/forklift/pkg/lib/client/image"
+)
+
+// Storage.
+// The share (or bucket) containing the catalog.
+type Storage struct {
+	// Catalog URL.
+	URL string
+	// Object storage.
+	ObjectStorage bool
+}
+
+// Apply to (update) the model.
+func (r *Storage) ApplyTo(m *model.Storage) {
+	m.Name = model.DefaultID
+	m.URL = r.URL
+	m.ObjectStorage = r.ObjectStorage
+}
+
+// Network.
+type Network struct {
+}
+
+// Apply to (update) the model.
+func (r *Network) ApplyTo(m *model.Network) {
+	m.Name = model.DefaultID
+}
+
+// Virtual machine.
+// The catalog item with the (probed) image.
+type VM struct {
+	libclient.CatalogItem
+}
+
+// The ID.
+// Stable (name based) UUID of the VM name. The name is
+// unique within the catalog.
+func (r *VM) ID() string {
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte(r.Name)).String()
+}
+
+// Apply to (update) the model.
+func (r *VM) ApplyTo(m *model.VM) {
+	m.Name = r.Name
+	m.Description = r.Description
+	m.Path = r.Path
+	m.URL = r.URL
+	m.Format = r.Format
+	m.VmdkKind = r.VmdkKind
+	m.Capacity = r.Capacity
+	m.Size = r.Size
+	m.CPU = r.CPU
+	m.Memory = r.Memory
+	m.Firmware = r.Firmware
+	m.OS = r.OS
+	m.CatalogError = r.Error
+	m.Storage = model.DefaultID
+}
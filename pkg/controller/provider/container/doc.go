@@ -2,6 +2,12 @@ package container
 
 import (
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/controller/provider/container/azure"
+	"github.com/kubev2v/forklift/pkg/controller/provider/container/catalog"
+	"github.com/kubev2v/forklift/pkg/controller/provider/container/ec2"
+	"github.com/kubev2v/forklift/pkg/controller/provider/container/image"
+	"github.com/kubev2v/forklift/pkg/controller/provider/container/libvirt"
+	"github.com/kubev2v/forklift/pkg/controller/provider/container/nutanix"
 	"github.com/kubev2v/forklift/pkg/controller/provider/container/ocp"
 	"github.com/kubev2v/forklift/pkg/controller/provider/container/openstack"
 	"github.com/kubev2v/forklift/pkg/controller/provider/container/ova"
@@ -29,6 +35,18 @@ func Build(
 		return openstack.New(db, provider, secret)
 	case api.Ova:
 		return ova.New(db, provider, secret)
+	case api.Nutanix:
+		return nutanix.New(db, provider, secret)
+	case api.EC2:
+		return ec2.New(db, provider, secret)
+	case api.Azure:
+		return azure.New(db, provider, secret)
+	case api.Libvirt:
+		return libvirt.New(db, provider, secret)
+	case api.Image:
+		return image.New(db, provider, secret)
+	case api.Catalog:
+		return catalog.New(db, provider, secret)
 	}
 
 	return nil
@@ -0,0 +1,15 @@
+package ec2
+
+import (
+	libclient "github.com/kubev2v/forklift/pkg/lib/client/ec2"
+)
+
+// Client struct
+type Client struct {
+	libclient.Client
+}
+
+// The client of the adapter context.
+func client(ctx *Context) *Client {
+	return ctx.Client.(*Client)
+}
@@ -0,0 +1,273 @@
+package ec2
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/controller/provider/container/base"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/ec2"
+	libclient "github.com/kubev2v/forklift/pkg/lib/client/ec2"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Fake EC2 (query) API.
+// The (XML) replies by action.
+type inventory struct {
+	subnets   string
+	volumes   string
+	instances string
+	types     string
+}
+
+// Serve the inventory.
+func (r *inventory) serve(t *testing.T) (server *httptest.Server) {
+	server = httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, request *http.Request) {
+			_ = request.ParseForm()
+			switch request.Form.Get("Action") {
+			case "DescribeAvailabilityZones":
+				_, _ = w.Write([]byte("<DescribeAvailabilityZonesResponse/>"))
+			case "DescribeSubnets":
+				_, _ = w.Write([]byte("<DescribeSubnetsResponse><subnetSet>" + r.subnets + "</subnetSet></DescribeSubnetsResponse>"))
+			case "DescribeVolumes":
+				_, _ = w.Write([]byte("<DescribeVolumesResponse><volumeSet>" + r.volumes + "</volumeSet></DescribeVolumesResponse>"))
+			case "DescribeInstances":
+				_, _ = w.Write([]byte(
+					"<DescribeInstancesResponse><reservationSet><item><instancesSet>" +
+						r.instances +
+						"</instancesSet></item></reservationSet></DescribeInstancesResponse>"))
+			case "DescribeInstanceTypes":
+				_, _ = w.Write([]byte("<DescribeInstanceTypesResponse><instanceTypeSet>" + r.types + "</instanceTypeSet></DescribeInstanceTypesResponse>"))
+			default:
+				w.WriteHeader(http.StatusBadRequest)
+			}
+		}))
+	t.Cleanup(server.Close)
+	return
+}
+
+// Build an instance (item).
+func instance(id, name, state string) string {
+	return `<item>
+<instanceId>` + id + `</instanceId>
+<instanceState><code>16</code><name>` + state + `</name></instanceState>
+<instanceType>m5.large</instanceType>
+<placement><availabilityZone>us-east-1a</availabilityZone></placement>
+<rootDeviceName>/dev/xvda</rootDeviceName>
+<blockDeviceMapping>
+<item><deviceName>/dev/xvdb</deviceName><ebs><volumeId>vol-2</volumeId></ebs></item>
+<item><deviceName>/dev/xvda</deviceName><ebs><volumeId>vol-1</volumeId></ebs></item>
+</blockDeviceMapping>
+<networkInterfaceSet>
+<item>
+<networkInterfaceId>eni-1</networkInterfaceId>
+<subnetId>subnet-1</subnetId>
+<macAddress>02:00:00:00:00:01</macAddress>
+<privateIpAddress>10.0.0.5</privateIpAddress>
+<attachment><deviceIndex>0</deviceIndex></attachment>
+</item>
+</networkInterfaceSet>
+<tagSet><item><key>Name</key><value>` + name + `</value></item></tagSet>
+</item>`
+}
+
+// Build the client and the collector and open the DB.
+func newCollector(t *testing.T, url string) (collector *base.Collector, ctx *Context) {
+	provider := &api.Provider{
+		ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "ec2", UID: "ec2-uid"},
+		Spec:       api.ProviderSpec{URL: url},
+	}
+	secret := &core.Secret{
+		Data: map[string][]byte{
+			libclient.AccessKeyID:     []byte("key"),
+			libclient.SecretAccessKey: []byte("secret"),
+			libclient.Region:          []byte("us-east-1"),
+		},
+	}
+	db := libmodel.New(filepath.Join(t.TempDir(), "ec2.db"), model.All()...)
+	err := db.Open(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = db.Close(true)
+	})
+	collector = New(db, provider, secret)
+	client := &Client{}
+	client.URL = url
+	client.LoadOptionsFromSecret(secret)
+	ctx = base.NewContext(context.TODO(), client, db, logging.WithName("test"))
+	return
+}
+
+// Load the inventory using the adapters.
+func load(ctx *Context) (err error) {
+	for _, adapter := range adapterList {
+		itr, lErr := adapter.List(ctx, nil)
+		if lErr != nil {
+			err = lErr
+			return
+		}
+		for {
+			object, hasNext := itr.Next()
+			if !hasNext {
+				break
+			}
+			err = ctx.DB.Insert(object.(libmodel.Model))
+			if err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// Refresh the inventory using the adapters.
+func refresh(ctx *Context) (err error) {
+	for _, adapter := range adapterList {
+		updates, uErr := adapter.GetUpdates(ctx)
+		if uErr != nil {
+			err = uErr
+			return
+		}
+		err = ctx.DB.With(func(tx *libmodel.Tx) (err error) {
+			for _, updater := range updates {
+				err = updater(tx)
+				if err != nil {
+					return
+				}
+			}
+			return
+		})
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// The inventory is loaded and refreshed. The validation
+// of the VMs is retained and the models no longer listed
+// are deleted.
+func TestCollector(t *testing.T) {
+	fake := &inventory{
+		subnets: `
+<item><subnetId>subnet-1</subnetId><vpcId>vpc-1</vpcId><cidrBlock>10.0.0.0/24</cidrBlock></item>
+<item><subnetId>subnet-2</subnetId><vpcId>vpc-1</vpcId><tagSet><item><key>Name</key><value>private</value></item></tagSet></item>`,
+		volumes: `
+<item><volumeId>vol-1</volumeId><size>8</size><volumeType>gp3</volumeType></item>
+<item><volumeId>vol-2</volumeId><size>100</size><volumeType>io2</volumeType></item>`,
+		instances: instance("i-1", "web", libclient.InstanceRunning),
+		types: `
+<item><instanceType>m5.large</instanceType><vCpuInfo><defaultVCpus>2</defaultVCpus></vCpuInfo><memoryInfo><sizeInMiB>8192</sizeInMiB></memoryInfo></item>`,
+	}
+	server := fake.serve(t)
+	collector, ctx := newCollector(t, server.URL)
+	if collector.Name() != strings.TrimPrefix(server.URL, "http://") {
+		t.Errorf("unexpected name: %s", collector.Name())
+	}
+	status, err := collector.Test()
+	if err != nil || status != 0 {
+		t.Fatalf("unexpected connection test: %d %v", status, err)
+	}
+
+	// Load.
+	err = load(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	subnet := &model.Subnet{Base: model.Base{ID: "subnet-1"}}
+	err = ctx.DB.Get(subnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subnet.Name != "subnet-1" || subnet.CIDR != "10.0.0.0/24" {
+		t.Errorf("unexpected subnet: %+v", subnet)
+	}
+	volumeTypes := []model.VolumeType{}
+	err = ctx.DB.List(&volumeTypes, model.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(volumeTypes) != len(libclient.VolumeTypes) {
+		t.Errorf("unexpected volume types: %v", volumeTypes)
+	}
+	vm := &model.VM{Base: model.Base{ID: "i-1"}}
+	err = ctx.DB.Get(vm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vm.Name != "web" || vm.VCPUs != 2 || vm.MemoryMB != 8192 ||
+		len(vm.Disks) != 2 || !vm.Disks[0].Root || vm.Disks[0].VolumeType != "gp3" ||
+		len(vm.NICs) != 1 || vm.NICs[0].Subnet != "subnet-1" || vm.NICs[0].IPs[0] != "10.0.0.5" {
+		t.Errorf("unexpected vm: %+v", vm)
+	}
+
+	// Validated.
+	vm.PolicyVersion = 1
+	vm.RevisionValidated = vm.Revision
+	vm.Revision--
+	err = ctx.DB.Update(vm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Refresh.
+	fake.instances = instance("i-1", "web", libclient.InstanceStopped) + instance("i-2", "db", libclient.InstanceRunning)
+	fake.subnets = `<item><subnetId>subnet-1</subnetId><vpcId>vpc-1</vpcId></item>`
+	err = refresh(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm = &model.VM{Base: model.Base{ID: "i-1"}}
+	err = ctx.DB.Get(vm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vm.State != libclient.InstanceStopped || vm.PolicyVersion != 1 || vm.Validated() {
+		t.Errorf("unexpected vm: %+v", vm)
+	}
+	err = ctx.DB.Get(&model.VM{Base: model.Base{ID: "i-2"}})
+	if err != nil {
+		t.Errorf("expected the created vm: %v", err)
+	}
+	err = ctx.DB.Get(&model.Subnet{Base: model.Base{ID: "subnet-2"}})
+	if !errors.Is(err, model.NotFound) {
+		t.Errorf("expected the subnet to be deleted: %v", err)
+	}
+
+	// Workload.
+	object, err := workload(ctx.DB, &api.Provider{ObjectMeta: meta.ObjectMeta{UID: "ec2-uid"}}, "i-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := json.Marshal(object)
+	if !strings.Contains(string(raw), `"subnets":[{"id":"subnet-1"`) {
+		t.Errorf("unexpected workload: %s", raw)
+	}
+}
+
+// The connection test reports the rejected credentials.
+func TestCollectorUnauthorized(t *testing.T) {
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte("<Response><Errors><Error><Code>AuthFailure</Code></Error></Errors></Response>"))
+		}))
+	defer server.Close()
+	collector, _ := newCollector(t, server.URL)
+	status, err := collector.Test()
+	if err == nil || status != http.StatusUnauthorized {
+		t.Errorf("unexpected connection test: %d %v", status, err)
+	}
+}
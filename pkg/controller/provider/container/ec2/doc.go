@@ -0,0 +1 @@
+package ec2
@@ -0,0 +1,283 @@
+package ec2
+
+import (
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/controller/provider/container/base"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/ec2"
+	libclient "github.com/kubev2v/forklift/pkg/lib/client/ec2"
+	fb "github.com/kubev2v/forklift/pkg/lib/filebacked"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+)
+
+// All adapters.
+var adapterList []Adapter
+
+func init() {
+	adapterList = []Adapter{
+		&SubnetAdapter{},
+		&VolumeTypeAdapter{},
+		&VolumeAdapter{},
+		&VMAdapter{},
+	}
+}
+
+// Types
+type Updater = base.Updater
+type Context = base.Context
+type Adapter = base.Adapter
+type BaseAdapter = base.BaseAdapter
+
+// Subnet adapter.
+type SubnetAdapter struct {
+	BaseAdapter
+}
+
+// List the collection.
+func (r *SubnetAdapter) List(ctx *Context, provider *api.Provider) (itr fb.Iterator, err error) {
+	subnetList, err := client(ctx).ListSubnets()
+	if err != nil {
+		return
+	}
+	list := fb.NewList()
+	for i := range subnetList {
+		subnet := &Subnet{Subnet: subnetList[i]}
+		m := &model.Subnet{
+			Base: model.Base{ID: subnet.ID},
+		}
+		subnet.ApplyTo(m)
+		list.Append(m)
+	}
+
+	itr = list.Iter()
+	return
+}
+
+// Get updates since last refresh.
+func (r *SubnetAdapter) GetUpdates(ctx *Context) (updates []Updater, err error) {
+	subnetList, err := client(ctx).ListSubnets()
+	if err != nil {
+		return
+	}
+	listed := map[string]bool{}
+	for i := range subnetList {
+		subnet := &Subnet{Subnet: subnetList[i]}
+		m := &model.Subnet{
+			Base: model.Base{ID: subnet.ID},
+		}
+		listed[m.ID] = true
+		updates = append(
+			updates,
+			r.Upsert(m, func() {
+				subnet.ApplyTo(m)
+			}))
+	}
+	deletions, err := r.DeleteUnexisting(
+		ctx,
+		&model.Subnet{},
+		listed,
+		func(id string) libmodel.Model {
+			return &model.Subnet{Base: model.Base{ID: id}}
+		})
+	updates = append(updates, deletions...)
+	return
+}
+
+// Volume type adapter.
+// The (EBS) volume types are not listed by the API.
+type VolumeTypeAdapter struct {
+	BaseAdapter
+}
+
+// List the collection.
+func (r *VolumeTypeAdapter) List(ctx *Context, provider *api.Provider) (itr fb.Iterator, err error) {
+	list := fb.NewList()
+	for _, name := range libclient.VolumeTypes {
+		m := &model.VolumeType{
+			Base: model.Base{ID: name, Name: name},
+		}
+		list.Append(m)
+	}
+
+	itr = list.Iter()
+	return
+}
+
+// Get updates since last refresh.
+func (r *VolumeTypeAdapter) GetUpdates(ctx *Context) (updates []Updater, err error) {
+	listed := map[string]bool{}
+	for _, name := range libclient.VolumeTypes {
+		m := &model.VolumeType{
+			Base: model.Base{ID: name},
+		}
+		listed[m.ID] = true
+		updates = append(
+			updates,
+			r.Upsert(m, func() {
+				m.Name = m.ID
+			}))
+	}
+	deletions, err := r.DeleteUnexisting(
+		ctx,
+		&model.VolumeType{},
+		listed,
+		func(id string) libmodel.Model {
+			return &model.VolumeType{Base: model.Base{ID: id}}
+		})
+	updates = append(updates, deletions...)
+	return
+}
+
+// Volume adapter.
+type VolumeAdapter struct {
+	BaseAdapter
+}
+
+// List the collection.
+func (r *VolumeAdapter) List(ctx *Context, provider *api.Provider) (itr fb.Iterator, err error) {
+	volumeList, err := client(ctx).ListVolumes()
+	if err != nil {
+		return
+	}
+	list := fb.NewList()
+	for i := range volumeList {
+		volume := &Volume{Volume: volumeList[i]}
+		m := &model.Volume{
+			Base: model.Base{ID: volume.ID},
+		}
+		volume.ApplyTo(m)
+		list.Append(m)
+	}
+
+	itr = list.Iter()
+	return
+}
+
+// Get updates since last refresh.
+func (r *VolumeAdapter) GetUpdates(ctx *Context) (updates []Updater, err error) {
+	volumeList, err := client(ctx).ListVolumes()
+	if err != nil {
+		return
+	}
+	listed := map[string]bool{}
+	for i := range volumeList {
+		volume := &Volume{Volume: volumeList[i]}
+		m := &model.Volume{
+			Base: model.Base{ID: volume.ID},
+		}
+		listed[m.ID] = true
+		updates = append(
+			updates,
+			r.Upsert(m, func() {
+				volume.ApplyTo(m)
+			}))
+	}
+	deletions, err := r.DeleteUnexisting(
+		ctx,
+		&model.Volume{},
+		listed,
+		func(id string) libmodel.Model {
+			return &model.Volume{Base: model.Base{ID: id}}
+		})
+	updates = append(updates, deletions...)
+	return
+}
+
+// VM (instance) adapter.
+type VMAdapter struct {
+	BaseAdapter
+}
+
+// List the collection.
+func (r *VMAdapter) List(ctx *Context, provider *api.Provider) (itr fb.Iterator, err error) {
+	vmList, err := r.list(ctx)
+	if err != nil {
+		return
+	}
+	list := fb.NewList()
+	for _, vm := range vmList {
+		m := &model.VM{
+			Base: model.Base{ID: vm.ID},
+		}
+		vm.ApplyTo(m)
+		list.Append(m)
+	}
+
+	itr = list.Iter()
+	return
+}
+
+// Get updates since last refresh.
+func (r *VMAdapter) GetUpdates(ctx *Context) (updates []Updater, err error) {
+	vmList, err := r.list(ctx)
+	if err != nil {
+		return
+	}
+	listed := map[string]bool{}
+	for _, vm := range vmList {
+		m := &model.VM{
+			Base: model.Base{ID: vm.ID},
+		}
+		listed[m.ID] = true
+		updates = append(
+			updates,
+			r.Upsert(m, func() {
+				vm.ApplyTo(m)
+			}))
+	}
+	deletions, err := r.DeleteUnexisting(
+		ctx,
+		&model.VM{},
+		listed,
+		func(id string) libmodel.Model {
+			return &model.VM{Base: model.Base{ID: id}}
+		})
+	updates = append(updates, deletions...)
+	return
+}
+
+// List the instances.
+// The instances are joined with the (attached) volumes
+// and the (described) instance types.
+func (r *VMAdapter) list(ctx *Context) (list []*VM, err error) {
+	instanceList, err := client(ctx).ListInstances()
+	if err != nil {
+		return
+	}
+	volumeList, err := client(ctx).ListVolumes()
+	if err != nil {
+		return
+	}
+	volumes := map[string]*libclient.Volume{}
+	for i := range volumeList {
+		volume := &volumeList[i]
+		volumes[volume.ID] = volume
+	}
+	names := []string{}
+	described := map[string]bool{}
+	for i := range instanceList {
+		name := instanceList[i].InstanceType
+		if !described[name] {
+			described[name] = true
+			names = append(names, name)
+		}
+	}
+	typeList, err := client(ctx).ListInstanceTypes(names)
+	if err != nil {
+		return
+	}
+	types := map[string]*libclient.InstanceType{}
+	for i := range typeList {
+		instanceType := &typeList[i]
+		types[instanceType.Name] = instanceType
+	}
+	for i := range instanceList {
+		list = append(
+			list,
+			&VM{
+				Instance:     instanceList[i],
+				volumes:      volumes,
+				instanceType: types[instanceList[i].InstanceType],
+			})
+	}
+	return
+}
@@ -0,0 +1,146 @@
+package ec2
+
+import (
+	"sort"
+
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/ec2"
+	libclient "github.com/kubev2v/forklift/pkg/lib/client/ec2"
+)
+
+// Subnet.
+type Subnet struct {
+	libclient.Subnet
+}
+
+// Apply to (update) the model.
+// Unnamed subnets are named by ID.
+func (r *Subnet) ApplyTo(m *model.Subnet) {
+	m.Name = r.Tags.Name()
+	if m.Name == "" {
+		m.Name = r.ID
+	}
+	m.VPC = r.VPC
+	m.CIDR = r.CIDR
+	m.AvailabilityZone = r.AvailabilityZone
+	m.State = r.State
+	m.DefaultForAz = r.DefaultForAz
+}
+
+// Volume.
+type Volume struct {
+	libclient.Volume
+}
+
+// Apply to (update) the model.
+// Unnamed volumes are named by ID.
+func (r *Volume) ApplyTo(m *model.Volume) {
+	m.Name = r.Tags.Name()
+	if m.Name == "" {
+		m.Name = r.ID
+	}
+	m.Size = r.Size
+	m.VolumeType = r.VolumeType
+	m.IOPS = r.IOPS
+	m.AvailabilityZone = r.AvailabilityZone
+	m.State = r.State
+	m.Encrypted = r.Encrypted
+}
+
+// VM (instance).
+type VM struct {
+	libclient.Instance
+	// Volumes by ID.
+	volumes map[string]*libclient.Volume
+	// The (described) instance type.
+	instanceType *libclient.InstanceType
+}
+
+// Apply to (update) the model.
+// Unnamed instances are named by ID.
+func (r *VM) ApplyTo(m *model.VM) {
+	m.Name = r.Tags.Name()
+	if m.Name == "" {
+		m.Name = r.ID
+	}
+	m.State = r.State.Name
+	m.InstanceType = r.InstanceType
+	m.AvailabilityZone = r.Placement.AvailabilityZone
+	m.Platform = r.Platform
+	m.PlatformDetails = r.PlatformDetails
+	m.Architecture = r.Architecture
+	m.BootMode = r.CurrentBootMode
+	if m.BootMode == "" {
+		m.BootMode = r.BootMode
+	}
+	m.TPM = r.TPMSupport == "v2.0"
+	m.RootDevice = r.RootDeviceName
+	m.VPC = r.VPC
+	m.CoresPerSocket = r.CPUOptions.CoreCount
+	m.ThreadsPerCore = r.CPUOptions.ThreadsPerCore
+	m.VCPUs = r.CPUOptions.CoreCount * r.CPUOptions.ThreadsPerCore
+	m.MemoryMB = 0
+	if r.instanceType != nil {
+		if m.VCPUs == 0 {
+			m.VCPUs = r.instanceType.VCPUs.Default
+		}
+		m.MemoryMB = r.instanceType.Memory.SizeMiB
+	}
+	r.addDisks(m)
+	r.addNICs(m)
+}
+
+// Add disks.
+// The root disk is listed first. Only EBS volumes are
+// listed; instance store volumes are not migrated.
+func (r *VM) addDisks(m *model.VM) {
+	m.Disks = []model.Disk{}
+	for _, device := range r.BlockDevices {
+		if device.EBS.Volume == "" {
+			continue
+		}
+		d := model.Disk{
+			ID:     device.EBS.Volume,
+			Device: device.DeviceName,
+			Root:   device.DeviceName == r.RootDeviceName,
+		}
+		if volume, found := r.volumes[d.ID]; found {
+			d.Size = volume.Size
+			d.VolumeType = volume.VolumeType
+		}
+		m.Disks = append(m.Disks, d)
+	}
+	sort.SliceStable(
+		m.Disks,
+		func(i, j int) bool {
+			if m.Disks[i].Root != m.Disks[j].Root {
+				return m.Disks[i].Root
+			}
+			return m.Disks[i].Device < m.Disks[j].Device
+		})
+}
+
+// Add NICs.
+func (r *VM) addNICs(m *model.VM) {
+	m.NICs = []model.NIC{}
+	for _, nic := range r.NetworkInterfaces {
+		n := model.NIC{
+			ID:     nic.ID,
+			Index:  nic.Attachment.DeviceIndex,
+			MAC:    nic.MAC,
+			Subnet: nic.Subnet,
+			IPs:    []string{},
+		}
+		for _, address := range nic.Addresses {
+			n.IPs = append(n.IPs, address.PrivateIP)
+		}
+		if len(n.IPs) == 0 && nic.PrivateIP != "" {
+			n.IPs = append(n.IPs, nic.PrivateIP)
+		}
+		m.NICs = append(m.NICs, n)
+	}
+	sort.SliceStable(
+		m.NICs,
+		func(i, j int) bool {
+			return m.NICs[i].Index < m.NICs[j].Index
+		})
+}
@@ -0,0 +1,288 @@
+package image
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/controller/provider/container/base"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/image"
+	libclient "github.com/kubev2v/forklift/pkg/lib/client/image"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Fake (http) share.
+// The files are listed by the directory index.
+type share struct {
+	mutex sync.Mutex
+	// Password (of the admin user).
+	password string
+	// Content by file name.
+	files map[string][]byte
+}
+
+// Set the content of a file.
+// The file is deleted when the content is nil.
+func (r *share) set(name string, content []byte) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if content == nil {
+		delete(r.files, name)
+		return
+	}
+	r.files[name] = content
+}
+
+// Serve the share.
+func (r *share) serve(t *testing.T) (server *httptest.Server) {
+	server = httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, request *http.Request) {
+			user, password, _ := request.BasicAuth()
+			if user != "admin" || password != r.password {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			r.mutex.Lock()
+			defer r.mutex.Unlock()
+			name := strings.TrimPrefix(request.URL.Path, "/disks/")
+			if name == "" {
+				names := []string{}
+				for name := range r.files {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+				for _, name := range names {
+					_, _ = w.Write([]byte(`<a href="` + name + `">` + name + "</a>\n"))
+				}
+				return
+			}
+			content, found := r.files[name]
+			if !found {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			http.ServeContent(w, request, name, time.Unix(0, 0), bytes.NewReader(content))
+		}))
+	t.Cleanup(server.Close)
+	return
+}
+
+// Build a qcow2 image.
+func qcow2(capacity uint64) (content []byte) {
+	content = make([]byte, 1024)
+	copy(content, "QFI\xfb")
+	binary.BigEndian.PutUint64(content[24:], capacity)
+	return
+}
+
+// Build the client and the collector and open the DB.
+func newCollector(t *testing.T, url string) (collector *base.Collector, ctx *Context) {
+	provider := &api.Provider{
+		ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "image", UID: "image-uid"},
+		Spec:       api.ProviderSpec{URL: url},
+	}
+	secret := &core.Secret{
+		Data: map[string][]byte{
+			libclient.AccessKeyID: []byte("admin"),
+			libclient.SecretKey:   []byte("secret"),
+		},
+	}
+	db := libmodel.New(filepath.Join(t.TempDir(), "image.db"), model.All()...)
+	err := db.Open(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = db.Close(true)
+	})
+	collector = New(db, provider, secret)
+	client := &Client{}
+	client.URL = url
+	client.LoadOptionsFromSecret(secret)
+	ctx = base.NewContext(context.TODO(), client, db, logging.WithName("test"))
+	return
+}
+
+// Load the inventory using the adapters.
+func load(ctx *Context) (err error) {
+	for _, adapter := range adapterList {
+		itr, lErr := adapter.List(ctx, nil)
+		if lErr != nil {
+			err = lErr
+			return
+		}
+		for {
+			object, hasNext := itr.Next()
+			if !hasNext {
+				break
+			}
+			err = ctx.DB.Insert(object.(libmodel.Model))
+			if err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// Refresh the inventory using the adapters.
+func refresh(ctx *Context) (err error) {
+	for _, adapter := range adapterList {
+		updates, uErr := adapter.GetUpdates(ctx)
+		if uErr != nil {
+			err = uErr
+			return
+		}
+		err = ctx.DB.With(func(tx *libmodel.Tx) (err error) {
+			for _, updater := range updates {
+				err = updater(tx)
+				if err != nil {
+					return
+				}
+			}
+			return
+		})
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// The inventory is loaded and refreshed. The validation
+// of the VMs is retained and the models no longer listed
+// are deleted.
+func TestCollector(t *testing.T) {
+	fake := &share{
+		password: "secret",
+		files: map[string][]byte{
+			"web.qcow2": qcow2(10 << 30),
+			"db.img":    make([]byte, 2048),
+		},
+	}
+	server := fake.serve(t)
+	url := server.URL + "/disks/"
+	collector, ctx := newCollector(t, url)
+	if collector.Name() != strings.TrimPrefix(server.URL, "http://") {
+		t.Errorf("unexpected name: %s", collector.Name())
+	}
+	status, err := collector.Test()
+	if err != nil || status != 0 {
+		t.Fatalf("unexpected connection test: %d %v", status, err)
+	}
+
+	// Load.
+	err = load(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	storage := &model.Storage{Base: model.Base{ID: model.DefaultID}}
+	err = ctx.DB.Get(storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if storage.URL != url || storage.ObjectStorage {
+		t.Errorf("unexpected storage: %+v", storage)
+	}
+	err = ctx.DB.Get(&model.Network{Base: model.Base{ID: model.DefaultID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	webID := (&VM{Image: libclient.Image{URL: url + "web.qcow2"}}).ID()
+	vm := &model.VM{Base: model.Base{ID: webID}}
+	err = ctx.DB.Get(vm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vm.Name != "web.qcow2" || vm.Format != libclient.Qcow2 || vm.Capacity != 10<<30 || vm.Size != 1024 ||
+		vm.Storage != model.DefaultID {
+		t.Errorf("unexpected vm: %+v", vm)
+	}
+	dbID := (&VM{Image: libclient.Image{URL: url + "db.img"}}).ID()
+	vm = &model.VM{Base: model.Base{ID: dbID}}
+	err = ctx.DB.Get(vm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vm.Format != libclient.Raw || vm.Capacity != 2048 {
+		t.Errorf("unexpected vm: %+v", vm)
+	}
+
+	// Validated.
+	vm = &model.VM{Base: model.Base{ID: webID}}
+	err = ctx.DB.Get(vm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.PolicyVersion = 1
+	vm.RevisionValidated = vm.Revision
+	vm.Revision--
+	err = ctx.DB.Update(vm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Refresh.
+	fake.set("web.qcow2", qcow2(20<<30))
+	fake.set("db.img", nil)
+	fake.set("app.vmdk", []byte("# Disk DescriptorFile\nversion=1\n"))
+	err = refresh(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm = &model.VM{Base: model.Base{ID: webID}}
+	err = ctx.DB.Get(vm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vm.Capacity != 20<<30 || vm.PolicyVersion != 1 || vm.Validated() {
+		t.Errorf("unexpected vm: %+v", vm)
+	}
+	vm = &model.VM{Base: model.Base{ID: (&VM{Image: libclient.Image{URL: url + "app.vmdk"}}).ID()}}
+	err = ctx.DB.Get(vm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vm.Format != libclient.Vmdk || vm.VmdkKind != libclient.VmdkDescriptor {
+		t.Errorf("unexpected vm: %+v", vm)
+	}
+	err = ctx.DB.Get(&model.VM{Base: model.Base{ID: dbID}})
+	if !errors.Is(err, model.NotFound) {
+		t.Errorf("expected the vm to be deleted: %v", err)
+	}
+
+	// Workload.
+	object, err := workload(ctx.DB, &api.Provider{ObjectMeta: meta.ObjectMeta{UID: "image-uid"}}, webID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := json.Marshal(object)
+	if !strings.Contains(string(raw), `"storages":[{"id":"`+model.DefaultID+`"`) {
+		t.Errorf("unexpected workload: %s", raw)
+	}
+}
+
+// The connection test reports the rejected credentials.
+func TestCollectorUnauthorized(t *testing.T) {
+	fake := &share{password: "other", files: map[string][]byte{}}
+	server := fake.serve(t)
+	collector, _ := newCollector(t, server.URL+"/disks/")
+	status, err := collector.Test()
+	if err == nil || status != http.StatusUnauthorized {
+		t.Errorf("unexpected connection test: %d %v", status, err)
+	}
+}
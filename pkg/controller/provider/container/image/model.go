@@ -0,0 +1,210 @@
+package image
+
+import (
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/controller/provider/container/base"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/image"
+	fb "github.com/kubev2v/forklift/pkg/lib/filebacked"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+)
+
+// All adapters.
+var adapterList []Adapter
+
+func init() {
+	adapterList = []Adapter{
+		&StorageAdapter{},
+		&NetworkAdapter{},
+		&VMAdapter{},
+	}
+}
+
+// Types
+type Updater = base.Updater
+type Context = base.Context
+type Adapter = base.Adapter
+type BaseAdapter = base.BaseAdapter
+
+// Storage adapter.
+// The (single) storage is the share.
+type StorageAdapter struct {
+	BaseAdapter
+}
+
+// List the collection.
+func (r *StorageAdapter) List(ctx *Context, provider *api.Provider) (itr fb.Iterator, err error) {
+	list := fb.NewList()
+	m := &model.Storage{
+		Base: model.Base{ID: model.DefaultID},
+	}
+	r.resource(ctx).ApplyTo(m)
+	list.Append(m)
+
+	itr = list.Iter()
+	return
+}
+
+// Get updates since last refresh.
+func (r *StorageAdapter) GetUpdates(ctx *Context) (updates []Updater, err error) {
+	m := &model.Storage{
+		Base: model.Base{ID: model.DefaultID},
+	}
+	updates = append(
+		updates,
+		r.Upsert(m, func() {
+			r.resource(ctx).ApplyTo(m)
+		}))
+	return
+}
+
+// The storage (resource).
+func (r *StorageAdapter) resource(ctx *Context) *Storage {
+	return &Storage{
+		URL:           client(ctx).URL,
+		ObjectStorage: client(ctx).IsObjectStorage(),
+	}
+}
+
+// Network adapter.
+// The (single) network has nothing attached and is only
+// reported so it can be mapped.
+type NetworkAdapter struct {
+	BaseAdapter
+}
+
+// List the collection.
+func (r *NetworkAdapter) List(ctx *Context, provider *api.Provider) (itr fb.Iterator, err error) {
+	list := fb.NewList()
+	m := &model.Network{
+		Base: model.Base{ID: model.DefaultID},
+	}
+	resource := &Network{}
+	resource.ApplyTo(m)
+	list.Append(m)
+
+	itr = list.Iter()
+	return
+}
+
+// Get updates since last refresh.
+func (r *NetworkAdapter) GetUpdates(ctx *Context) (updates []Updater, err error) {
+	m := &model.Network{
+		Base: model.Base{ID: model.DefaultID},
+	}
+	resource := &Network{}
+	updates = append(
+		updates,
+		r.Upsert(m, func() {
+			resource.ApplyTo(m)
+		}))
+	return
+}
+
+// VM adapter.
+type VMAdapter struct {
+	BaseAdapter
+}
+
+// List the collection.
+func (r *VMAdapter) List(ctx *Context, provider *api.Provider) (itr fb.Iterator, err error) {
+	resources, err := r.list(ctx)
+	if err != nil {
+		return
+	}
+	list := fb.NewList()
+	for _, resource := range resources {
+		m := &model.VM{
+			Base: model.Base{ID: resource.ID()},
+		}
+		resource.ApplyTo(m)
+		list.Append(m)
+	}
+
+	itr = list.Iter()
+	return
+}
+
+// Get updates since last refresh.
+func (r *VMAdapter) GetUpdates(ctx *Context) (updates []Updater, err error) {
+	resources, err := r.list(ctx)
+	if err != nil {
+		return
+	}
+	listed := map[string]bool{}
+	for _, resource := range resources {
+		m := &model.VM{
+			Base: model.Base{ID: resource.ID()},
+		}
+		listed[m.ID] = true
+		updates = append(
+			updates,
+			r.Upsert(m, func() {
+				resource.ApplyTo(m)
+			}))
+	}
+	deletions, err := r.DeleteUnexisting(
+		ctx,
+		&model.VM{},
+		listed,
+		func(id string) libmodel.Model {
+			return &model.VM{Base: model.Base{ID: id}}
+		})
+	updates = append(updates, deletions...)
+	return
+}
+
+// List the (probed) images.
+// An image listed with the same size and modified time as
+// the one in the DB is not probed again. The images deleted
+// while listed are skipped.
+func (r *VMAdapter) list(ctx *Context) (list []*VM, err error) {
+	images, err := client(ctx).ListImages()
+	if err != nil {
+		return
+	}
+	for i := range images {
+		if ctx.Canceled() {
+			return
+		}
+		vm := &VM{Image: images[i]}
+		if r.unchanged(ctx, vm) {
+			list = append(list, vm)
+			continue
+		}
+		err = client(ctx).Probe(&vm.Image)
+		if err != nil {
+			if client(ctx).IsNotFound(err) {
+				err = nil
+				continue
+			}
+			return
+		}
+		list = append(list, vm)
+	}
+	return
+}
+
+// The listed image is unchanged.
+// The (probed) format and capacity are copied from the DB.
+// The size (and modified time) is listed only by object storage.
+func (r *VMAdapter) unchanged(ctx *Context, vm *VM) (unchanged bool) {
+	if vm.Size == 0 || vm.Modified.IsZero() {
+		return
+	}
+	m := &model.VM{
+		Base: model.Base{ID: vm.ID()},
+	}
+	err := ctx.DB.Get(m)
+	if err != nil {
+		return
+	}
+	unchanged = m.Format != "" &&
+		m.Size == vm.Size &&
+		m.Modified.Equal(vm.Modified)
+	if unchanged {
+		vm.Format = m.Format
+		vm.VmdkKind = m.VmdkKind
+		vm.Capacity = m.Capacity
+	}
+	return
+}
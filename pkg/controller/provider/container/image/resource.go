@@ -0,0 +1,57 @@
+package image
+
+import (
+	"github.com/google/uuid"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/image"
+	libclient "github.com/kubev2v/forklift/pkg/lib/client/image"
+)
+
+// Storage.
+// The share (or bucket) containing the images.
+type Storage struct {
+	// Share URL.
+	URL string
+	// Object storage.
+	ObjectStorage bool
+}
+
+// Apply to (update) the model.
+func (r *Storage) ApplyTo(m *model.Storage) {
+	m.Name = model.DefaultID
+	m.URL = r.URL
+	m.ObjectStorage = r.ObjectStorage
+}
+
+// Network.
+type Network struct {
+}
+
+// Apply to (update) the model.
+func (r *Network) ApplyTo(m *model.Network) {
+	m.Name = model.DefaultID
+}
+
+// Virtual machine.
+// The (probed) image.
+type VM struct {
+	libclient.Image
+}
+
+// The ID.
+// Stable (name based) UUID of the image URL.
+func (r *VM) ID() string {
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(r.URL)).String()
+}
+
+// Apply to (update) the model.
+func (r *VM) ApplyTo(m *model.VM) {
+	m.Name = r.Name()
+	m.Path = r.Path
+	m.URL = r.URL
+	m.Format = r.Format
+	m.VmdkKind = r.VmdkKind
+	m.Capacity = r.Capacity
+	m.Size = r.Size
+	m.Modified = r.Modified
+	m.Storage = model.DefaultID
+}
@@ -0,0 +1,15 @@
+package libvirt
+
+import (
+	libclient "github.com/kubev2v/forklift/pkg/lib/client/libvirt"
+)
+
+// Client struct
+type Client struct {
+	libclient.Client
+}
+
+// The client of the adapter context.
+func client(ctx *Context) *Client {
+	return ctx.Client.(*Client)
+}
@@ -0,0 +1,76 @@
+package libvirt
+
+import (
+	"net"
+	libpath "path"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/controller/provider/container/base"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/libvirt"
+	web "github.com/kubev2v/forklift/pkg/controller/provider/web/libvirt"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+	"github.com/kubev2v/forklift/pkg/lib/util"
+	core "k8s.io/api/core/v1"
+)
+
+// Endpoints.
+const (
+	BaseEndpoint = "/v1/data/io/konveyor/forklift/libvirt/"
+)
+
+// VM validation.
+var validation = &base.Validation{
+	Endpoint: BaseEndpoint,
+	VM: func(id string) base.VM {
+		return &model.VM{Base: model.Base{ID: id}}
+	},
+	Workload: workload,
+}
+
+// New Libvirt data collector.
+func New(db libmodel.DB, provider *api.Provider, secret *core.Secret) (r *base.Collector) {
+	log := logging.WithName("collector|libvirt").WithValues(
+		"provider",
+		libpath.Join(
+			provider.GetNamespace(),
+			provider.GetName()))
+
+	client := &Client{}
+	client.URL = provider.Spec.URL
+	client.Log = log
+	client.Dial = util.ProviderDialer(
+		provider,
+		&net.Dialer{
+			Timeout:   10 * time.Second,
+			KeepAlive: 10 * time.Second,
+		})
+	client.LoadOptionsFromSecret(secret)
+
+	r = base.New(db, provider, log, client.URL, client, adapterList, validation)
+
+	return
+}
+
+// Build the workload.
+func workload(db libmodel.DB, provider *api.Provider, vmID string) (object interface{}, err error) {
+	vm := &model.VM{
+		Base: model.Base{ID: vmID},
+	}
+	err = db.Get(vm)
+	if err != nil {
+		return
+	}
+	workload := web.Workload{}
+	workload.With(vm)
+	err = workload.Expand(db)
+	if err != nil {
+		return
+	}
+
+	workload.Link(provider)
+	object = workload
+
+	return
+}
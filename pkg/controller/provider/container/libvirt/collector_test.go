@@ -0,0 +1,357 @@
+package libvirt
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/controller/provider/container/base"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/libvirt"
+	libclient "github.com/kubev2v/forklift/pkg/lib/client/libvirt"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+	"golang.org/x/crypto/ssh"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// UUIDs.
+const (
+	poolID    = "6c5d1a38-8f64-4a8e-9a64-3a1c7c1e0a01"
+	networkID = "6c5d1a38-8f64-4a8e-9a64-3a1c7c1e0a02"
+	webID     = "6c5d1a38-8f64-4a8e-9a64-3a1c7c1e0a03"
+	dbID      = "6c5d1a38-8f64-4a8e-9a64-3a1c7c1e0a04"
+)
+
+// Fake (SSH) host.
+// The virsh commands are answered by the (unquoted) arguments.
+type host struct {
+	mutex sync.Mutex
+	// Output by command.
+	out map[string]string
+}
+
+// Set the output of the command.
+func (r *host) set(command, out string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.out[command] = out
+}
+
+// Run the command line.
+// Example: 'virsh' '-q' '-c' 'qemu:///system' 'list' '--all' '--uuid'
+func (r *host) run(command string) (out string, found bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	args := []string{}
+	for _, arg := range strings.Fields(command) {
+		args = append(args, strings.Trim(arg, "'"))
+	}
+	if len(args) > 4 && args[0] == "virsh" {
+		args = args[4:]
+	}
+	out, found = r.out[strings.Join(args, " ")]
+	return
+}
+
+// Serve SSH connections.
+// The password is: secret.
+func (r *host) serve(t *testing.T) (address string) {
+	_, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(_ ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if string(password) != "secret" {
+				return nil, errors.New("denied")
+			}
+			return nil, nil
+		},
+	}
+	config.AddHostKey(signer)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+	go func() {
+		for {
+			conn, aErr := listener.Accept()
+			if aErr != nil {
+				return
+			}
+			go r.session(conn, config)
+		}
+	}()
+	address = listener.Addr().String()
+	return
+}
+
+// Handle the exec requests of a connection.
+func (r *host) session(conn net.Conn, config *ssh.ServerConfig) {
+	_, channels, requests, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		_ = conn.Close()
+		return
+	}
+	go ssh.DiscardRequests(requests)
+	for newChannel := range channels {
+		channel, channelRequests, aErr := newChannel.Accept()
+		if aErr != nil {
+			continue
+		}
+		go func() {
+			defer func() {
+				_ = channel.Close()
+			}()
+			for request := range channelRequests {
+				if request.Type != "exec" || len(request.Payload) < 4 {
+					_ = request.Reply(false, nil)
+					continue
+				}
+				_ = request.Reply(true, nil)
+				status := uint32(0)
+				out, found := r.run(string(request.Payload[4:]))
+				if found {
+					_, _ = channel.Write([]byte(out))
+				} else {
+					_, _ = channel.Stderr().Write([]byte("error: failed to get domain"))
+					status = 1
+				}
+				_, _ = channel.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{status}))
+				return
+			}
+		}()
+	}
+}
+
+// Build a domain (XML).
+func domain(id, name string) string {
+	return `<domain type='kvm'>
+<name>` + name + `</name>
+<uuid>` + id + `</uuid>
+<memory unit='KiB'>2097152</memory>
+<vcpu>2</vcpu>
+<os><type arch='x86_64' machine='q35'>hvm</type><loader type='pflash'>/usr/share/OVMF/OVMF_CODE.fd</loader></os>
+<devices>
+<disk type='file' device='disk'>
+<driver name='qemu' type='qcow2'/>
+<source file='/var/lib/libvirt/images/` + name + `.qcow2'/>
+<target dev='vda' bus='virtio'/>
+</disk>
+<interface type='network'>
+<mac address='52:54:00:00:00:01'/>
+<source network='default'/>
+<model type='virtio'/>
+</interface>
+</devices>
+</domain>`
+}
+
+// Build the client and the collector and open the DB.
+func newCollector(t *testing.T, address, password string) (collector *base.Collector, ctx *Context) {
+	url := "qemu+ssh://root@" + address + "/system"
+	provider := &api.Provider{
+		ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "libvirt", UID: "libvirt-uid"},
+		Spec:       api.ProviderSpec{URL: url},
+	}
+	secret := &core.Secret{
+		Data: map[string][]byte{
+			libclient.Password: []byte(password),
+			libclient.Insecure: []byte("true"),
+		},
+	}
+	db := libmodel.New(filepath.Join(t.TempDir(), "libvirt.db"), model.All()...)
+	err := db.Open(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = db.Close(true)
+	})
+	collector = New(db, provider, secret)
+	t.Cleanup(collector.Shutdown)
+	client := &Client{}
+	client.URL = url
+	client.LoadOptionsFromSecret(secret)
+	t.Cleanup(client.Close)
+	ctx = base.NewContext(context.TODO(), client, db, logging.WithName("test"))
+	return
+}
+
+// Load the inventory using the adapters.
+func load(ctx *Context) (err error) {
+	for _, adapter := range adapterList {
+		itr, lErr := adapter.List(ctx, nil)
+		if lErr != nil {
+			err = lErr
+			return
+		}
+		for {
+			object, hasNext := itr.Next()
+			if !hasNext {
+				break
+			}
+			err = ctx.DB.Insert(object.(libmodel.Model))
+			if err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// Refresh the inventory using the adapters.
+func refresh(ctx *Context) (err error) {
+	for _, adapter := range adapterList {
+		updates, uErr := adapter.GetUpdates(ctx)
+		if uErr != nil {
+			err = uErr
+			return
+		}
+		err = ctx.DB.With(func(tx *libmodel.Tx) (err error) {
+			for _, updater := range updates {
+				err = updater(tx)
+				if err != nil {
+					return
+				}
+			}
+			return
+		})
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// The inventory is loaded and refreshed. The validation
+// of the VMs is retained and the models no longer listed
+// are deleted.
+func TestCollector(t *testing.T) {
+	fake := &host{
+		out: map[string]string{
+			"uri":                    "qemu:///system\n",
+			"pool-list --all --name": "default\n",
+			"pool-dumpxml default": `<pool type='dir'><name>default</name><uuid>` + poolID + `</uuid>
+<capacity unit='bytes'>1000</capacity><allocation unit='bytes'>10</allocation>
+<target><path>/var/lib/libvirt/images/</path></target></pool>`,
+			"net-list --all --name": "default\n",
+			"net-dumpxml default": `<network><name>default</name><uuid>` + networkID + `</uuid>
+<forward mode='nat'/><bridge name='virbr0'/></network>`,
+			"list --all --uuid":            webID + "\n",
+			"dumpxml " + webID:             domain(webID, "web"),
+			"domstate " + webID:            "running\n",
+			"domblkinfo " + webID + " vda": "Capacity:       10737418240\nAllocation:     1048576\nPhysical:       1048576\n",
+		},
+	}
+	address := fake.serve(t)
+	collector, ctx := newCollector(t, address, "secret")
+	if collector.Name() != address {
+		t.Errorf("unexpected name: %s", collector.Name())
+	}
+	status, err := collector.Test()
+	if err != nil || status != 0 {
+		t.Fatalf("unexpected connection test: %d %v", status, err)
+	}
+
+	// Load.
+	err = load(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := &model.Pool{Base: model.Base{ID: poolID}}
+	err = ctx.DB.Get(pool)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pool.Name != "default" || pool.Path != "/var/lib/libvirt/images" || pool.Capacity != 1000 {
+		t.Errorf("unexpected pool: %+v", pool)
+	}
+	vm := &model.VM{Base: model.Base{ID: webID}}
+	err = ctx.DB.Get(vm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vm.Name != "web" || vm.State != "running" || vm.Firmware != EFI || vm.CPUCount != 2 || vm.MemoryMB != 2048 ||
+		len(vm.Disks) != 1 || vm.Disks[0].Pool != poolID || vm.Disks[0].Capacity != 10737418240 ||
+		len(vm.NICs) != 1 || vm.NICs[0].Network != networkID {
+		t.Errorf("unexpected vm: %+v", vm)
+	}
+
+	// Validated.
+	vm.PolicyVersion = 1
+	vm.RevisionValidated = vm.Revision
+	vm.Revision--
+	err = ctx.DB.Update(vm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Refresh.
+	fake.set("domstate "+webID, "shut off\n")
+	fake.set("list --all --uuid", webID+"\n"+dbID+"\n")
+	fake.set("dumpxml "+dbID, domain(dbID, "db"))
+	fake.set("domstate "+dbID, "running\n")
+	fake.set("net-list --all --name", "")
+	err = refresh(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm = &model.VM{Base: model.Base{ID: webID}}
+	err = ctx.DB.Get(vm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vm.State != "shut off" || vm.PolicyVersion != 1 || vm.Validated() {
+		t.Errorf("unexpected vm: %+v", vm)
+	}
+	vm = &model.VM{Base: model.Base{ID: dbID}}
+	err = ctx.DB.Get(vm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vm.Disks) != 1 || vm.Disks[0].Capacity != 0 {
+		t.Errorf("expected the disk without (block) info: %+v", vm)
+	}
+	err = ctx.DB.Get(&model.Network{Base: model.Base{ID: networkID}})
+	if !errors.Is(err, model.NotFound) {
+		t.Errorf("expected the network to be deleted: %v", err)
+	}
+
+	// Workload.
+	object, err := workload(ctx.DB, &api.Provider{ObjectMeta: meta.ObjectMeta{UID: "libvirt-uid"}}, webID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := json.Marshal(object)
+	if !strings.Contains(string(raw), `"pools":[{"id":"`+poolID+`"`) {
+		t.Errorf("unexpected workload: %s", raw)
+	}
+}
+
+// The connection test reports the rejected credentials.
+func TestCollectorUnauthorized(t *testing.T) {
+	fake := &host{out: map[string]string{}}
+	address := fake.serve(t)
+	collector, _ := newCollector(t, address, "wrong")
+	status, err := collector.Test()
+	if err == nil || status != http.StatusUnauthorized {
+		t.Errorf("unexpected connection test: %d %v", status, err)
+	}
+}
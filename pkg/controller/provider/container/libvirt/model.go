@@ -0,0 +1,301 @@
+package libvirt
+
+import (
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/controller/provider/container/base"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/libvirt"
+	libclient "github.com/kubev2v/forklift/pkg/lib/client/libvirt"
+	fb "github.com/kubev2v/forklift/pkg/lib/filebacked"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+)
+
+// All adapters.
+var adapterList []Adapter
+
+func init() {
+	adapterList = []Adapter{
+		&PoolAdapter{},
+		&NetworkAdapter{},
+		&VMAdapter{},
+	}
+}
+
+// Types
+type Updater = base.Updater
+type Context = base.Context
+type Adapter = base.Adapter
+type BaseAdapter = base.BaseAdapter
+
+// Pool adapter.
+type PoolAdapter struct {
+	BaseAdapter
+}
+
+// List the collection.
+func (r *PoolAdapter) List(ctx *Context, provider *api.Provider) (itr fb.Iterator, err error) {
+	resources, err := listPools(ctx)
+	if err != nil {
+		return
+	}
+	list := fb.NewList()
+	for _, resource := range resources {
+		m := &model.Pool{
+			Base: model.Base{ID: resource.UUID},
+		}
+		resource.ApplyTo(m)
+		list.Append(m)
+	}
+
+	itr = list.Iter()
+	return
+}
+
+// Get updates since last refresh.
+func (r *PoolAdapter) GetUpdates(ctx *Context) (updates []Updater, err error) {
+	resources, err := listPools(ctx)
+	if err != nil {
+		return
+	}
+	listed := map[string]bool{}
+	for _, resource := range resources {
+		m := &model.Pool{
+			Base: model.Base{ID: resource.UUID},
+		}
+		listed[m.ID] = true
+		updates = append(
+			updates,
+			r.Upsert(m, func() {
+				resource.ApplyTo(m)
+			}))
+	}
+	deletions, err := r.DeleteUnexisting(
+		ctx,
+		&model.Pool{},
+		listed,
+		func(id string) libmodel.Model {
+			return &model.Pool{Base: model.Base{ID: id}}
+		})
+	updates = append(updates, deletions...)
+	return
+}
+
+// Network adapter.
+type NetworkAdapter struct {
+	BaseAdapter
+}
+
+// List the collection.
+func (r *NetworkAdapter) List(ctx *Context, provider *api.Provider) (itr fb.Iterator, err error) {
+	resources, err := listNetworks(ctx)
+	if err != nil {
+		return
+	}
+	list := fb.NewList()
+	for _, resource := range resources {
+		m := &model.Network{
+			Base: model.Base{ID: resource.UUID},
+		}
+		resource.ApplyTo(m)
+		list.Append(m)
+	}
+
+	itr = list.Iter()
+	return
+}
+
+// Get updates since last refresh.
+func (r *NetworkAdapter) GetUpdates(ctx *Context) (updates []Updater, err error) {
+	resources, err := listNetworks(ctx)
+	if err != nil {
+		return
+	}
+	listed := map[string]bool{}
+	for _, resource := range resources {
+		m := &model.Network{
+			Base: model.Base{ID: resource.UUID},
+		}
+		listed[m.ID] = true
+		updates = append(
+			updates,
+			r.Upsert(m, func() {
+				resource.ApplyTo(m)
+			}))
+	}
+	deletions, err := r.DeleteUnexisting(
+		ctx,
+		&model.Network{},
+		listed,
+		func(id string) libmodel.Model {
+			return &model.Network{Base: model.Base{ID: id}}
+		})
+	updates = append(updates, deletions...)
+	return
+}
+
+// VM adapter.
+type VMAdapter struct {
+	BaseAdapter
+}
+
+// List the collection.
+func (r *VMAdapter) List(ctx *Context, provider *api.Provider) (itr fb.Iterator, err error) {
+	resources, err := r.list(ctx)
+	if err != nil {
+		return
+	}
+	list := fb.NewList()
+	for _, resource := range resources {
+		m := &model.VM{
+			Base: model.Base{ID: resource.UUID},
+		}
+		resource.ApplyTo(m)
+		list.Append(m)
+	}
+
+	itr = list.Iter()
+	return
+}
+
+// Get updates since last refresh.
+func (r *VMAdapter) GetUpdates(ctx *Context) (updates []Updater, err error) {
+	resources, err := r.list(ctx)
+	if err != nil {
+		return
+	}
+	listed := map[string]bool{}
+	for _, resource := range resources {
+		m := &model.VM{
+			Base: model.Base{ID: resource.UUID},
+		}
+		listed[m.ID] = true
+		updates = append(
+			updates,
+			r.Upsert(m, func() {
+				resource.ApplyTo(m)
+			}))
+	}
+	deletions, err := r.DeleteUnexisting(
+		ctx,
+		&model.VM{},
+		listed,
+		func(id string) libmodel.Model {
+			return &model.VM{Base: model.Base{ID: id}}
+		})
+	updates = append(updates, deletions...)
+	return
+}
+
+// List the domains.
+// The domains undefined while listed are skipped.
+func (r *VMAdapter) list(ctx *Context) (list []*VM, err error) {
+	pools, err := listPools(ctx)
+	if err != nil {
+		return
+	}
+	networks, err := listNetworks(ctx)
+	if err != nil {
+		return
+	}
+	idList, err := client(ctx).ListDomains()
+	if err != nil {
+		return
+	}
+	for _, id := range idList {
+		if ctx.Canceled() {
+			return
+		}
+		var vm *VM
+		vm, err = r.get(ctx, id)
+		if err != nil {
+			if client(ctx).IsNotFound(err) {
+				err = nil
+				continue
+			}
+			return
+		}
+		vm.pools = pools
+		vm.networks = networks
+		list = append(list, vm)
+	}
+	return
+}
+
+// Get the domain, its state and the (block) info of its disks.
+// The disks without info (e.g. empty cdrom) are reported without capacity.
+func (r *VMAdapter) get(ctx *Context, id string) (vm *VM, err error) {
+	domain, err := client(ctx).GetDomain(id)
+	if err != nil {
+		return
+	}
+	state, err := client(ctx).DomainState(id)
+	if err != nil {
+		return
+	}
+	vm = &VM{
+		Domain:    *domain,
+		state:     state,
+		blockInfo: map[string]libclient.BlockInfo{},
+	}
+	if domain.Devices == nil {
+		return
+	}
+	for _, disk := range domain.Devices.Disks {
+		if disk.Target == nil || disk.Source == nil {
+			continue
+		}
+		info, bErr := client(ctx).BlockInfo(id, disk.Target.Dev)
+		if bErr != nil {
+			ctx.Log.V(3).Info(
+				"Disk (block) info not reported.",
+				"domain",
+				id,
+				"disk",
+				disk.Target.Dev,
+				"error",
+				bErr.Error())
+			continue
+		}
+		vm.blockInfo[disk.Target.Dev] = info
+	}
+	return
+}
+
+// List the storage pools.
+func listPools(ctx *Context) (list []*Pool, err error) {
+	names, err := client(ctx).ListPools()
+	if err != nil {
+		return
+	}
+	for _, name := range names {
+		pool, gErr := client(ctx).GetPool(name)
+		if gErr != nil {
+			if client(ctx).IsNotFound(gErr) {
+				continue
+			}
+			err = gErr
+			return
+		}
+		list = append(list, &Pool{StoragePool: *pool})
+	}
+	return
+}
+
+// List the networks.
+func listNetworks(ctx *Context) (list []*Network, err error) {
+	names, err := client(ctx).ListNetworks()
+	if err != nil {
+		return
+	}
+	for _, name := range names {
+		network, gErr := client(ctx).GetNetwork(name)
+		if gErr != nil {
+			if client(ctx).IsNotFound(gErr) {
+				continue
+			}
+			err = gErr
+			return
+		}
+		list = append(list, &Network{Network: *network})
+	}
+	return
+}
@@ -0,0 +1,304 @@
+package libvirt
+
+import (
+	"encoding/xml"
+	"path"
+	"strings"
+
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/libvirt"
+	libclient "github.com/kubev2v/forklift/pkg/lib/client/libvirt"
+	libvirtxml "libvirt.org/libvirt-go-xml"
+)
+
+// Firmware.
+const (
+	BIOS = "bios"
+	EFI  = "efi"
+)
+
+// Storage pool.
+type Pool struct {
+	libvirtxml.StoragePool
+}
+
+// Apply to (update) the model.
+func (r *Pool) ApplyTo(m *model.Pool) {
+	m.Name = r.Name
+	m.Type = r.Type
+	m.Path = ""
+	if r.Target != nil {
+		m.Path = path.Clean(r.Target.Path)
+	}
+	m.Capacity = poolSize(r.Capacity)
+	m.Allocation = poolSize(r.Allocation)
+}
+
+// Network.
+type Network struct {
+	libvirtxml.Network
+}
+
+// Apply to (update) the model.
+func (r *Network) ApplyTo(m *model.Network) {
+	m.Name = r.Name
+	m.Forward = ""
+	m.Bridge = ""
+	if r.Forward != nil {
+		m.Forward = r.Forward.Mode
+	}
+	if r.Bridge != nil {
+		m.Bridge = r.Bridge.Name
+	}
+}
+
+// Virtual machine (domain).
+type VM struct {
+	libvirtxml.Domain
+	// State. Example: running.
+	state string
+	// Disk (block) info by target.
+	blockInfo map[string]libclient.BlockInfo
+	// Pools.
+	pools []*Pool
+	// Networks.
+	networks []*Network
+}
+
+// Apply to (update) the model.
+func (r *VM) ApplyTo(m *model.VM) {
+	m.Name = r.Name
+	m.State = r.state
+	m.Type = r.Type
+	m.Architecture = ""
+	m.Machine = ""
+	m.MemoryMB = 0
+	if r.Memory != nil {
+		m.MemoryMB = int64(memoryBytes(uint64(r.Memory.Value), r.Memory.Unit) / (1024 * 1024))
+	}
+	r.addOS(m)
+	r.addCPU(m)
+	r.addDisks(m)
+	r.addNICs(m)
+}
+
+// Add the OS and firmware.
+// The firmware is EFI when either auto-selected or the
+// loader is pflash. The secure boot is enabled by the
+// secure loader.
+func (r *VM) addOS(m *model.VM) {
+	m.Firmware = BIOS
+	m.SecureBoot = false
+	m.TPM = false
+	m.OS = r.osInfo()
+	if r.OS != nil {
+		if r.OS.Type != nil {
+			m.Architecture = r.OS.Type.Arch
+			m.Machine = r.OS.Type.Machine
+		}
+		if r.OS.Firmware == EFI {
+			m.Firmware = EFI
+		}
+		if loader := r.OS.Loader; loader != nil {
+			if loader.Type == "pflash" || strings.Contains(strings.ToLower(loader.Path), "ovmf") {
+				m.Firmware = EFI
+			}
+			m.SecureBoot = loader.Secure == "yes"
+		}
+	}
+	if r.Devices != nil {
+		m.TPM = len(r.Devices.TPMs) > 0
+	}
+}
+
+// Add the CPU (topology).
+// The topology defaults to a socket for each vCPU.
+func (r *VM) addCPU(m *model.VM) {
+	m.CPUCount = 0
+	if r.VCPU != nil {
+		m.CPUCount = int32(r.VCPU.Value)
+	}
+	m.CPUSockets = m.CPUCount
+	m.CPUCores = 1
+	m.CPUThreads = 1
+	if r.CPU != nil && r.CPU.Topology != nil {
+		topology := r.CPU.Topology
+		m.CPUSockets = int32(max(topology.Sockets, 1) * max(topology.Dies, 1))
+		m.CPUCores = int32(max(topology.Cores, 1))
+		m.CPUThreads = int32(max(topology.Threads, 1))
+	}
+}
+
+// Add disks.
+func (r *VM) addDisks(m *model.VM) {
+	m.Disks = []model.Disk{}
+	if r.Devices == nil {
+		return
+	}
+	for i := range r.Devices.Disks {
+		disk := &r.Devices.Disks[i]
+		d := model.Disk{
+			Device:   disk.Device,
+			ReadOnly: disk.ReadOnly != nil,
+			Shared:   disk.Shareable != nil,
+		}
+		if d.Device == "" {
+			d.Device = "disk"
+		}
+		if disk.Target != nil {
+			d.Target = disk.Target.Dev
+			d.Bus = disk.Target.Bus
+		}
+		if disk.Driver != nil {
+			d.Format = disk.Driver.Type
+		}
+		if disk.Boot != nil {
+			d.BootOrder = disk.Boot.Order
+		}
+		if source := disk.Source; source != nil {
+			switch {
+			case source.File != nil:
+				d.Path = source.File.File
+				d.Pool = r.poolOf(d.Path)
+			case source.Block != nil:
+				d.Path = source.Block.Dev
+				d.Pool = r.poolOf(d.Path)
+			case source.Volume != nil:
+				d.Pool = r.poolNamed(source.Volume.Pool)
+			}
+		}
+		if info, found := r.blockInfo[d.Target]; found {
+			d.Capacity = info.Capacity
+		}
+		m.Disks = append(m.Disks, d)
+	}
+}
+
+// Add NICs.
+// Bridge interfaces are matched with the
+// network (bridge) by the bridge name.
+func (r *VM) addNICs(m *model.VM) {
+	m.NICs = []model.NIC{}
+	if r.Devices == nil {
+		return
+	}
+	for i := range r.Devices.Interfaces {
+		nic := &r.Devices.Interfaces[i]
+		n := model.NIC{}
+		if nic.MAC != nil {
+			n.MAC = nic.MAC.Address
+		}
+		if nic.Model != nil {
+			n.Model = nic.Model.Type
+		}
+		if source := nic.Source; source != nil {
+			switch {
+			case source.Network != nil:
+				n.Type = "network"
+				n.Source = source.Network.Network
+				for _, network := range r.networks {
+					if network.Name == n.Source {
+						n.Network = network.UUID
+						break
+					}
+				}
+			case source.Bridge != nil:
+				n.Type = "bridge"
+				n.Source = source.Bridge.Bridge
+				for _, network := range r.networks {
+					if network.Bridge != nil && network.Bridge.Name == n.Source {
+						n.Network = network.UUID
+						break
+					}
+				}
+			case source.Direct != nil:
+				n.Type = "direct"
+				n.Source = source.Direct.Dev
+			case source.User != nil:
+				n.Type = "user"
+			}
+		}
+		m.NICs = append(m.NICs, n)
+	}
+}
+
+// The pool (ID) containing the path.
+// The pool with the longest (matching) target path is selected.
+func (r *VM) poolOf(diskPath string) (id string) {
+	matched := ""
+	for _, pool := range r.pools {
+		if pool.Target == nil || pool.Target.Path == "" {
+			continue
+		}
+		target := path.Clean(pool.Target.Path)
+		if path.Dir(diskPath) == target || strings.HasPrefix(diskPath, target+"/") {
+			if len(target) > len(matched) {
+				matched = target
+				id = pool.UUID
+			}
+		}
+	}
+	return
+}
+
+// The pool (ID) by name.
+func (r *VM) poolNamed(name string) (id string) {
+	for _, pool := range r.pools {
+		if pool.Name == name {
+			id = pool.UUID
+			break
+		}
+	}
+	return
+}
+
+// The operating system (libosinfo) ID.
+func (r *VM) osInfo() (id string) {
+	if r.Metadata == nil {
+		return
+	}
+	metadata := struct {
+		OS struct {
+			ID string `xml:"id,attr"`
+		} `xml:"libosinfo>os"`
+	}{}
+	err := xml.Unmarshal([]byte("<metadata>"+r.Metadata.XML+"</metadata>"), &metadata)
+	if err == nil {
+		id = metadata.OS.ID
+	}
+	return
+}
+
+// Pool size (bytes).
+func poolSize(size *libvirtxml.StoragePoolSize) int64 {
+	if size == nil {
+		return 0
+	}
+	return int64(memoryBytes(size.Value, size.Unit))
+}
+
+// Convert the value of the (libvirt) unit to bytes.
+// The unit defaults to KiB.
+func memoryBytes(value uint64, unit string) uint64 {
+	n := value
+	switch strings.ToLower(unit) {
+	case "b", "bytes":
+		return n
+	case "", "k", "kib":
+		return n * 1024
+	case "kb":
+		return n * 1000
+	case "m", "mib":
+		return n * 1024 * 1024
+	case "mb":
+		return n * 1000 * 1000
+	case "g", "gib":
+		return n * 1024 * 1024 * 1024
+	case "gb":
+		return n * 1000 * 1000 * 1000
+	case "t", "tib":
+		return n * 1024 * 1024 * 1024 * 1024
+	case "tb":
+		return n * 1000 * 1000 * 1000 * 1000
+	}
+	return n * 1024
+}
@@ -0,0 +1,10 @@
+package nutanix
+
+import (
+	libclient "github.com/kubev2v/forklift/pkg/lib/client/nutanix"
+)
+
+// Client struct
+type Client struct {
+	libclient.Client
+}
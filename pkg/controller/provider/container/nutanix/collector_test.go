@@ -0,0 +1,339 @@
+package nutanix
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	refapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/nutanix"
+	"github.com/kubev2v/forklift/pkg/controller/validation/policy"
+	libclient "github.com/kubev2v/forklift/pkg/lib/client/nutanix"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Fake Prism Central inventory.
+type inventory struct {
+	clusters   []interface{}
+	subnets    []interface{}
+	vms        []interface{}
+	containers []libclient.StorageContainer
+}
+
+// Serve the inventory.
+func (r *inventory) serve(t *testing.T) (server *httptest.Server) {
+	list := func(kind string, entities *[]interface{}) http.HandlerFunc {
+		return func(w http.ResponseWriter, _ *http.Request) {
+			_ = json.NewEncoder(w).Encode(
+				map[string]interface{}{
+					"entities": *entities,
+					"metadata": libclient.ListMetadata{Kind: kind, TotalMatches: len(*entities)},
+				})
+		}
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(libclient.V3Path+"/clusters/list", list(libclient.ClusterKind, &r.clusters))
+	mux.HandleFunc(libclient.V3Path+"/subnets/list", list(libclient.SubnetKind, &r.subnets))
+	mux.HandleFunc(libclient.V3Path+"/vms/list", list(libclient.VMKind, &r.vms))
+	mux.HandleFunc(libclient.ClusterMgmtPath+"/storage-containers", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(
+			map[string]interface{}{
+				"data":     r.containers,
+				"metadata": map[string]int{"totalAvailableResults": len(r.containers)},
+			})
+	})
+	server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return
+}
+
+// Build an entity.
+func entity(kind, id string, status map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"metadata": map[string]string{"kind": kind, "uuid": id},
+		"status":   status,
+	}
+}
+
+// Build a VM entity.
+func vmEntity(id, name, powerState string) map[string]interface{} {
+	return entity(
+		libclient.VMKind,
+		id,
+		map[string]interface{}{
+			"name":              name,
+			"cluster_reference": map[string]string{"kind": libclient.ClusterKind, "uuid": "cluster-1"},
+			"resources": map[string]interface{}{
+				"power_state": powerState,
+				"num_sockets": 2,
+				"nic_list": []map[string]interface{}{
+					{
+						"uuid":             "nic-1",
+						"mac_address":      "00:50:56:00:00:01",
+						"subnet_reference": map[string]string{"kind": libclient.SubnetKind, "uuid": "subnet-1"},
+						"ip_endpoint_list": []map[string]string{{"ip": "10.0.0.5"}},
+					},
+				},
+				"disk_list": []map[string]interface{}{
+					{
+						"uuid":            "disk-1",
+						"disk_size_bytes": 1024,
+						"device_properties": map[string]interface{}{
+							"device_type":  "DISK",
+							"disk_address": map[string]interface{}{"adapter_type": "SCSI", "device_index": 0},
+						},
+						"storage_config": map[string]interface{}{
+							"storage_container_reference": map[string]string{"kind": "storage_container", "uuid": "sc-1"},
+						},
+					},
+				},
+			},
+		})
+}
+
+// Build the collector and open the DB.
+func newCollector(t *testing.T, url string) (collector *Collector, ctx *Context) {
+	provider := &api.Provider{
+		ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "nutanix", UID: "nutanix-uid"},
+		Spec:       api.ProviderSpec{URL: url},
+	}
+	secret := &core.Secret{
+		Data: map[string][]byte{
+			libclient.User:     []byte("admin"),
+			libclient.Password: []byte("secret"),
+		},
+	}
+	db := libmodel.New(filepath.Join(t.TempDir(), "nutanix.db"), model.All()...)
+	err := db.Open(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = db.Close(true)
+	})
+	collector = New(db, provider, secret)
+	ctx = &Context{
+		ctx:    context.TODO(),
+		client: collector.client,
+		db:     db,
+		log:    collector.log,
+	}
+	return
+}
+
+// The inventory is loaded and refreshed. The validation
+// of the VMs is retained and the models no longer listed
+// are deleted.
+func TestCollector(t *testing.T) {
+	batchSize := Settings.Inventory.BatchSize
+	defer func() {
+		Settings.Inventory.BatchSize = batchSize
+	}()
+	Settings.Inventory.BatchSize = 10
+	fake := &inventory{
+		clusters: []interface{}{
+			entity(
+				libclient.ClusterKind,
+				"cluster-1",
+				map[string]interface{}{
+					"name": "cluster-1",
+					"resources": map[string]interface{}{
+						"config": map[string]interface{}{
+							"software_map": map[string]interface{}{"NOS": map[string]string{"version": "6.8"}},
+						},
+					},
+				}),
+			entity(
+				libclient.ClusterKind,
+				"pc",
+				map[string]interface{}{
+					"name": "pc",
+					"resources": map[string]interface{}{
+						"config": map[string]interface{}{"service_list": []string{"PRISM_CENTRAL"}},
+					},
+				}),
+		},
+		subnets: []interface{}{
+			entity(
+				libclient.SubnetKind,
+				"subnet-1",
+				map[string]interface{}{
+					"name": "vlan-10",
+					"resources": map[string]interface{}{
+						"subnet_type": "VLAN",
+						"vlan_id":     10,
+						"ip_config":   map[string]interface{}{"subnet_ip": "10.0.0.0", "prefix_length": 24},
+					},
+				}),
+			entity(libclient.SubnetKind, "subnet-2", map[string]interface{}{"name": "vlan-20"}),
+		},
+		vms: []interface{}{
+			vmEntity("vm-1", "vm-1", libclient.PowerOn),
+		},
+		containers: []libclient.StorageContainer{
+			{ContainerExtID: "sc-1", Name: "default", ClusterExtID: "cluster-1", ReplicationFactor: 2},
+		},
+	}
+	server := fake.serve(t)
+	collector, ctx := newCollector(t, server.URL)
+	if collector.Name() != strings.TrimPrefix(server.URL, "http://") {
+		t.Errorf("unexpected name: %s", collector.Name())
+	}
+	status, err := collector.Test()
+	if err != nil || status != 0 {
+		t.Fatalf("unexpected connection test: %d %v", status, err)
+	}
+
+	// Load.
+	err = collector.load(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clusters := []model.Cluster{}
+	err = collector.DB().List(&clusters, model.ListOptions{Detail: model.MaxDetail})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clusters) != 1 || clusters[0].Version != "6.8" {
+		t.Errorf("unexpected clusters: %v", clusters)
+	}
+	subnet := &model.Subnet{Base: model.Base{ID: "subnet-1"}}
+	err = collector.DB().Get(subnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subnet.CIDR != "10.0.0.0/24" || subnet.VlanID != 10 {
+		t.Errorf("unexpected subnet: %+v", subnet)
+	}
+	vm := &model.VM{Base: model.Base{ID: "vm-1"}}
+	err = collector.DB().Get(vm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vm.Cluster != "cluster-1" ||
+		len(vm.NICs) != 1 || vm.NICs[0].Subnet != "subnet-1" || vm.NICs[0].IPs[0] != "10.0.0.5" ||
+		len(vm.Disks) != 1 || vm.Disks[0].StorageContainer != "sc-1" {
+		t.Errorf("unexpected vm: %+v", vm)
+	}
+
+	// Validated.
+	vm.PolicyVersion = 1
+	vm.RevisionValidated = vm.Revision
+	vm.Revision--
+	err = collector.DB().Update(vm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Refresh.
+	fake.vms = []interface{}{
+		vmEntity("vm-1", "vm-1", libclient.PowerOff),
+		vmEntity("vm-2", "vm-2", libclient.PowerOn),
+	}
+	fake.subnets = fake.subnets[:1]
+	err = collector.refresh(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm = &model.VM{Base: model.Base{ID: "vm-1"}}
+	err = collector.DB().Get(vm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vm.PowerState != libclient.PowerOff || vm.PolicyVersion != 1 || vm.Validated() {
+		t.Errorf("unexpected vm: %+v", vm)
+	}
+	err = collector.DB().Get(&model.VM{Base: model.Base{ID: "vm-2"}})
+	if err != nil {
+		t.Errorf("expected the created vm: %v", err)
+	}
+	err = collector.DB().Get(&model.Subnet{Base: model.Base{ID: "subnet-2"}})
+	if !errors.Is(err, model.NotFound) {
+		t.Errorf("expected the subnet to be deleted: %v", err)
+	}
+}
+
+// The connection test reports the rejected credentials.
+func TestCollectorUnauthorized(t *testing.T) {
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+	defer server.Close()
+	collector, _ := newCollector(t, server.URL)
+	status, err := collector.Test()
+	if err == nil || status != http.StatusUnauthorized {
+		t.Errorf("unexpected connection test: %d %v", status, err)
+	}
+}
+
+// The validation results are applied to the VMs not
+// updated since they were submitted.
+func TestVMEventHandlerValidated(t *testing.T) {
+	collector, _ := newCollector(t, "https://pc.example.com:9440")
+	db := collector.DB()
+	for _, id := range []string{"vm-1", "vm-2"} {
+		err := db.Insert(&model.VM{Base: model.Base{ID: id, Name: id}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	stale := &model.VM{Base: model.Base{ID: "vm-2"}}
+	err := db.Get(stale)
+	if err != nil {
+		t.Fatal(err)
+	}
+	revision := stale.Revision
+	stale.PowerState = "OFF"
+	err = db.Update(stale)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := &VMEventHandler{
+		Provider: collector.provider,
+		DB:       db,
+		log:      logging.WithName("test"),
+	}
+	concerns := []model.Concern{{Label: "TPM", Category: "Warning"}}
+	handler.validated(
+		[]*policy.Task{
+			{Ref: refapi.Ref{ID: "vm-1"}, Revision: revision, Version: 2, Concerns: concerns},
+			{Ref: refapi.Ref{ID: "vm-2"}, Revision: revision, Version: 2, Concerns: concerns},
+		})
+
+	vm := &model.VM{Base: model.Base{ID: "vm-1"}}
+	err = db.Get(vm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !vm.Validated() || vm.PolicyVersion != 2 || len(vm.Concerns) != 1 {
+		t.Errorf("expected the vm to be validated: %+v", vm)
+	}
+	vm = &model.VM{Base: model.Base{ID: "vm-2"}}
+	err = db.Get(vm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vm.Validated() || vm.PolicyVersion != 0 {
+		t.Errorf("expected the (updated) vm to not be validated: %+v", vm)
+	}
+
+	// Workload.
+	object, err := handler.workload("vm-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := json.Marshal(object)
+	if !strings.Contains(string(raw), `"id":"vm-1"`) {
+		t.Errorf("unexpected workload: %s", raw)
+	}
+}
@@ -0,0 +1,336 @@
+package nutanix
+
+import (
+	"context"
+	"errors"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/nutanix"
+	fb "github.com/kubev2v/forklift/pkg/lib/filebacked"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+)
+
+// All adapters.
+var adapterList []Adapter
+
+func init() {
+	adapterList = []Adapter{
+		&ClusterAdapter{},
+		&SubnetAdapter{},
+		&StorageContainerAdapter{},
+		&VMAdapter{},
+	}
+}
+
+// Updates the DB based on
+// changes described by an Event.
+type Updater func(tx *libmodel.Tx) error
+
+// Adapter context.
+type Context struct {
+	// Context.
+	ctx context.Context
+	// Prism Central client.
+	client *Client
+	// Log.
+	log logging.LevelLogger
+	// DB client.
+	db libmodel.DB
+}
+
+// The adapter request is canceled.
+func (r *Context) canceled() (done bool) {
+	select {
+	case <-r.ctx.Done():
+		done = true
+	default:
+	}
+
+	return
+}
+
+// Model adapter.
+// Provides integration between the REST resource
+// model and the inventory model.
+type Adapter interface {
+	// List REST collections.
+	List(ctx *Context, provider *api.Provider) (itr fb.Iterator, err error)
+	// Get the updates (and deletions) since the last refresh.
+	// The Prism API has no change (event) stream so the
+	// collection is listed and compared with the DB.
+	GetUpdates(ctx *Context) (updates []Updater, err error)
+}
+
+// Base adapter.
+type BaseAdapter struct {
+}
+
+// Build the updater that creates or updates the model.
+// The model is applied after it's fetched so the fields
+// not reported by the API (e.g. validation) are retained.
+func (r *BaseAdapter) upsert(m libmodel.Model, apply func()) Updater {
+	return func(tx *libmodel.Tx) (err error) {
+		err = tx.Get(m)
+		if err != nil {
+			if errors.Is(err, libmodel.NotFound) {
+				apply()
+				err = tx.Insert(m)
+			}
+			return
+		}
+		apply()
+		err = tx.Update(m)
+		return
+	}
+}
+
+// Build the updaters that delete the models (of the kind)
+// no longer reported by the API.
+func (r *BaseAdapter) deleteUnexisting(
+	ctx *Context,
+	kind libmodel.Model,
+	listed map[string]bool,
+	build func(id string) libmodel.Model) (deletions []Updater, err error) {
+	itr, err := ctx.db.Find(kind, libmodel.ListOptions{})
+	if err != nil {
+		return
+	}
+	for {
+		object, hasNext := itr.Next()
+		if !hasNext {
+			break
+		}
+		id := object.(libmodel.Model).Pk()
+		if listed[id] {
+			continue
+		}
+		m := build(id)
+		deletions = append(
+			deletions,
+			func(tx *libmodel.Tx) error {
+				return tx.Delete(m)
+			})
+	}
+	return
+}
+
+// Cluster adapter.
+type ClusterAdapter struct {
+	BaseAdapter
+}
+
+// List the collection.
+func (r *ClusterAdapter) List(ctx *Context, provider *api.Provider) (itr fb.Iterator, err error) {
+	clusterList, err := ctx.client.ListClusters()
+	if err != nil {
+		return
+	}
+	list := fb.NewList()
+	for i := range clusterList {
+		cluster := &Cluster{Cluster: clusterList[i]}
+		m := &model.Cluster{
+			Base: model.Base{ID: cluster.Metadata.UUID},
+		}
+		cluster.ApplyTo(m)
+		list.Append(m)
+	}
+
+	itr = list.Iter()
+	return
+}
+
+// Get updates since last refresh.
+func (r *ClusterAdapter) GetUpdates(ctx *Context) (updates []Updater, err error) {
+	clusterList, err := ctx.client.ListClusters()
+	if err != nil {
+		return
+	}
+	listed := map[string]bool{}
+	for i := range clusterList {
+		cluster := &Cluster{Cluster: clusterList[i]}
+		m := &model.Cluster{
+			Base: model.Base{ID: cluster.Metadata.UUID},
+		}
+		listed[m.ID] = true
+		updates = append(
+			updates,
+			r.upsert(m, func() {
+				cluster.ApplyTo(m)
+			}))
+	}
+	deletions, err := r.deleteUnexisting(
+		ctx,
+		&model.Cluster{},
+		listed,
+		func(id string) libmodel.Model {
+			return &model.Cluster{Base: model.Base{ID: id}}
+		})
+	updates = append(updates, deletions...)
+	return
+}
+
+// Subnet adapter.
+type SubnetAdapter struct {
+	BaseAdapter
+}
+
+// List the collection.
+func (r *SubnetAdapter) List(ctx *Context, provider *api.Provider) (itr fb.Iterator, err error) {
+	subnetList, err := ctx.client.ListSubnets()
+	if err != nil {
+		return
+	}
+	list := fb.NewList()
+	for i := range subnetList {
+		subnet := &Subnet{Subnet: subnetList[i]}
+		m := &model.Subnet{
+			Base: model.Base{ID: subnet.Metadata.UUID},
+		}
+		subnet.ApplyTo(m)
+		list.Append(m)
+	}
+
+	itr = list.Iter()
+	return
+}
+
+// Get updates since last refresh.
+func (r *SubnetAdapter) GetUpdates(ctx *Context) (updates []Updater, err error) {
+	subnetList, err := ctx.client.ListSubnets()
+	if err != nil {
+		return
+	}
+	listed := map[string]bool{}
+	for i := range subnetList {
+		subnet := &Subnet{Subnet: subnetList[i]}
+		m := &model.Subnet{
+			Base: model.Base{ID: subnet.Metadata.UUID},
+		}
+		listed[m.ID] = true
+		updates = append(
+			updates,
+			r.upsert(m, func() {
+				subnet.ApplyTo(m)
+			}))
+	}
+	deletions, err := r.deleteUnexisting(
+		ctx,
+		&model.Subnet{},
+		listed,
+		func(id string) libmodel.Model {
+			return &model.Subnet{Base: model.Base{ID: id}}
+		})
+	updates = append(updates, deletions...)
+	return
+}
+
+// Storage container adapter.
+type StorageContainerAdapter struct {
+	BaseAdapter
+}
+
+// List the collection.
+func (r *StorageContainerAdapter) List(ctx *Context, provider *api.Provider) (itr fb.Iterator, err error) {
+	containerList, err := ctx.client.ListStorageContainers()
+	if err != nil {
+		return
+	}
+	list := fb.NewList()
+	for i := range containerList {
+		container := &StorageContainer{StorageContainer: containerList[i]}
+		m := &model.StorageContainer{
+			Base: model.Base{ID: container.ContainerExtID},
+		}
+		container.ApplyTo(m)
+		list.Append(m)
+	}
+
+	itr = list.Iter()
+	return
+}
+
+// Get updates since last refresh.
+func (r *StorageContainerAdapter) GetUpdates(ctx *Context) (updates []Updater, err error) {
+	containerList, err := ctx.client.ListStorageContainers()
+	if err != nil {
+		return
+	}
+	listed := map[string]bool{}
+	for i := range containerList {
+		container := &StorageContainer{StorageContainer: containerList[i]}
+		m := &model.StorageContainer{
+			Base: model.Base{ID: container.ContainerExtID},
+		}
+		listed[m.ID] = true
+		updates = append(
+			updates,
+			r.upsert(m, func() {
+				container.ApplyTo(m)
+			}))
+	}
+	deletions, err := r.deleteUnexisting(
+		ctx,
+		&model.StorageContainer{},
+		listed,
+		func(id string) libmodel.Model {
+			return &model.StorageContainer{Base: model.Base{ID: id}}
+		})
+	updates = append(updates, deletions...)
+	return
+}
+
+// VM adapter.
+type VMAdapter struct {
+	BaseAdapter
+}
+
+// List the collection.
+func (r *VMAdapter) List(ctx *Context, provider *api.Provider) (itr fb.Iterator, err error) {
+	vmList, err := ctx.client.ListVMs()
+	if err != nil {
+		return
+	}
+	list := fb.NewList()
+	for i := range vmList {
+		vm := &VM{VM: vmList[i]}
+		m := &model.VM{
+			Base: model.Base{ID: vm.Metadata.UUID},
+		}
+		vm.ApplyTo(m)
+		list.Append(m)
+	}
+
+	itr = list.Iter()
+	return
+}
+
+// Get updates since last refresh.
+func (r *VMAdapter) GetUpdates(ctx *Context) (updates []Updater, err error) {
+	vmList, err := ctx.client.ListVMs()
+	if err != nil {
+		return
+	}
+	listed := map[string]bool{}
+	for i := range vmList {
+		vm := &VM{VM: vmList[i]}
+		m := &model.VM{
+			Base: model.Base{ID: vm.Metadata.UUID},
+		}
+		listed[m.ID] = true
+		updates = append(
+			updates,
+			r.upsert(m, func() {
+				vm.ApplyTo(m)
+			}))
+	}
+	deletions, err := r.deleteUnexisting(
+		ctx,
+		&model.VM{},
+		listed,
+		func(id string) libmodel.Model {
+			return &model.VM{Base: model.Base{ID: id}}
+		})
+	updates = append(updates, deletions...)
+	return
+}
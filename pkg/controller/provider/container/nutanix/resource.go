@@ -0,0 +1,138 @@
+package nutanix
+
+import (
+	"fmt"
+
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/nutanix"
+	libclient "github.com/kubev2v/forklift/pkg/lib/client/nutanix"
+)
+
+// Cluster.
+type Cluster struct {
+	libclient.Cluster
+}
+
+// Apply to (update) the model.
+func (r *Cluster) ApplyTo(m *model.Cluster) {
+	m.Name = r.Status.Name
+	m.ExternalIP = r.Status.Resources.Network.ExternalIP
+	if software, found := r.Status.Resources.Config.SoftwareMap["NOS"]; found {
+		m.Version = software.Version
+	}
+}
+
+// Subnet.
+type Subnet struct {
+	libclient.Subnet
+}
+
+// Apply to (update) the model.
+func (r *Subnet) ApplyTo(m *model.Subnet) {
+	m.Name = r.Status.Name
+	m.Cluster = ""
+	if r.Status.ClusterReference != nil {
+		m.Cluster = r.Status.ClusterReference.UUID
+	}
+	resources := r.Status.Resources
+	m.SubnetType = resources.SubnetType
+	m.VlanID = resources.VlanID
+	m.VSwitch = resources.VswitchName
+	m.CIDR = ""
+	if resources.IPConfig.SubnetIP != "" {
+		m.CIDR = fmt.Sprintf(
+			"%s/%d",
+			resources.IPConfig.SubnetIP,
+			resources.IPConfig.PrefixLength)
+	}
+}
+
+// Storage container.
+type StorageContainer struct {
+	libclient.StorageContainer
+}
+
+// Apply to (update) the model.
+func (r *StorageContainer) ApplyTo(m *model.StorageContainer) {
+	m.Name = r.Name
+	m.Cluster = r.ClusterExtID
+	m.Capacity = r.MaxCapacity
+	m.ReplicationFactor = r.ReplicationFactor
+}
+
+// VM.
+type VM struct {
+	libclient.VM
+}
+
+// Apply to (update) the model.
+func (r *VM) ApplyTo(m *model.VM) {
+	m.Name = r.Status.Name
+	m.Description = r.Status.Description
+	m.State = r.Status.State
+	m.Cluster = ""
+	if r.Status.ClusterReference != nil {
+		m.Cluster = r.Status.ClusterReference.UUID
+	}
+	resources := r.Status.Resources
+	m.PowerState = resources.PowerState
+	m.Sockets = resources.NumSockets
+	m.CoresPerSocket = resources.NumVcpusPerSocket
+	m.ThreadsPerCore = resources.NumThreadsPerCore
+	m.MemoryMB = resources.MemorySizeMib
+	m.MachineType = resources.MachineType
+	m.BootType = ""
+	if resources.BootConfig != nil {
+		m.BootType = resources.BootConfig.BootType
+	}
+	m.TPM = resources.VTPMConfig != nil && resources.VTPMConfig.VTPMEnabled
+	m.GuestTools = ""
+	if resources.GuestTools != nil && resources.GuestTools.NutanixGuestTools != nil {
+		m.GuestTools = resources.GuestTools.NutanixGuestTools.State
+	}
+	m.UserData = ""
+	if resources.GuestCustomization != nil && resources.GuestCustomization.CloudInit != nil {
+		m.UserData = resources.GuestCustomization.CloudInit.UserData
+	}
+	r.addDisks(m)
+	r.addNICs(m)
+}
+
+// Add disks.
+func (r *VM) addDisks(m *model.VM) {
+	m.Disks = []model.Disk{}
+	for _, disk := range r.Status.Resources.DiskList {
+		d := model.Disk{
+			ID:         disk.UUID,
+			DeviceType: disk.DeviceProperties.DeviceType,
+			Bus:        disk.DeviceProperties.DiskAddress.AdapterType,
+			Index:      disk.DeviceProperties.DiskAddress.DeviceIndex,
+			Capacity:   disk.DiskSizeBytes,
+		}
+		if disk.StorageConfig != nil && disk.StorageConfig.StorageContainerReference != nil {
+			d.StorageContainer = disk.StorageConfig.StorageContainerReference.UUID
+		}
+		m.Disks = append(m.Disks, d)
+	}
+}
+
+// Add NICs.
+func (r *VM) addNICs(m *model.VM) {
+	m.NICs = []model.NIC{}
+	for _, nic := range r.Status.Resources.NicList {
+		n := model.NIC{
+			ID:        nic.UUID,
+			MAC:       nic.MacAddress,
+			Model:     nic.Model,
+			Type:      nic.NicType,
+			Connected: nic.IsConnected,
+			IPs:       []string{},
+		}
+		if nic.SubnetReference != nil {
+			n.Subnet = nic.SubnetReference.UUID
+		}
+		for _, endpoint := range nic.IPEndpointList {
+			n.IPs = append(n.IPs, endpoint.IP)
+		}
+		m.NICs = append(m.NICs, n)
+	}
+}
@@ -3,6 +3,7 @@ package openstack
 import (
 	"context"
 	"fmt"
+	"net"
 	liburl "net/url"
 	libpath "path"
 	"time"
@@ -12,6 +13,9 @@ import (
 	liberr "github.com/kubev2v/forklift/pkg/lib/error"
 	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
 	"github.com/kubev2v/forklift/pkg/lib/logging"
+	"github.com/kubev2v/forklift/pkg/lib/tracing"
+	"github.com/kubev2v/forklift/pkg/lib/util"
+	"go.opentelemetry.io/otel/attribute"
 	core "k8s.io/api/core/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -66,6 +70,13 @@ func New(db libmodel.DB, provider *api.Provider, secret *core.Secret) (r *Collec
 	client := &Client{}
 	client.URL = provider.Spec.URL
 	client.Log = log
+	client.Proxy = util.ProviderProxy(provider)
+	client.Dial = util.ProviderDialer(
+		provider,
+		&net.Dialer{
+			Timeout:   10 * time.Second,
+			KeepAlive: 10 * time.Second,
+		})
 	client.LoadOptionsFromSecret(secret)
 
 	r = &Collector{
@@ -229,11 +240,18 @@ func (r *Collector) load(ctx *Context) (err error) {
 		"Initial Parity.",
 		"duration",
 		time.Since(mark))
+	tracing.Record(
+		tracing.WithUID(context.TODO(), string(r.provider.UID)),
+		"inventory.load",
+		mark,
+		time.Now(),
+		attribute.String("provider", r.provider.Namespace+"/"+r.provider.Name))
 
 	return
 }
 
 // List and create resources using the adapter.
+// The models are inserted in batches.
 func (r *Collector) create(ctx *Context, adapter Adapter) (err error) {
 	itr, aErr := adapter.List(ctx)
 
@@ -241,12 +259,12 @@ func (r *Collector) create(ctx *Context, adapter Adapter) (err error) {
 		err = aErr
 		return
 	}
-	tx, err := r.db.Begin()
-	if err != nil {
-		return
+	batch := &libmodel.Batch{
+		DB:   r.db,
+		Size: Settings.Inventory.BatchSize,
 	}
 	defer func() {
-		_ = tx.End()
+		_ = batch.End()
 	}()
 	for {
 		object, hasNext := itr.Next()
@@ -257,12 +275,14 @@ func (r *Collector) create(ctx *Context, adapter Adapter) (err error) {
 			return
 		}
 		m := object.(libmodel.Model)
-		err = tx.Insert(m)
+		err = batch.With(func(tx *libmodel.Tx) error {
+			return tx.Insert(m)
+		})
 		if err != nil {
 			return
 		}
 	}
-	err = tx.Commit()
+	err = batch.Commit()
 	if err != nil {
 		return
 	}
@@ -480,6 +480,10 @@ func (r *VMAdapter) List(ctx *Context) (itr fb.Iterator, err error) {
 	if err != nil {
 		return
 	}
+	ports, err := r.ports(ctx, &libclient.PortListOpts{})
+	if err != nil {
+		return
+	}
 	list := fb.NewList()
 	for _, vm := range vmList {
 		m := &model.VM{
@@ -487,7 +491,7 @@ func (r *VMAdapter) List(ctx *Context) (itr fb.Iterator, err error) {
 				ID:   vm.ID,
 				Name: vm.Name},
 		}
-		v := &VM{vm}
+		v := &VM{VM: vm, Ports: ports[vm.ID]}
 		v.ApplyTo(m)
 		list.Append(m)
 	}
@@ -496,6 +500,20 @@ func (r *VMAdapter) List(ctx *Context) (itr fb.Iterator, err error) {
 	return
 }
 
+// List the ports indexed by device (VM) ID.
+func (r *VMAdapter) ports(ctx *Context, opts *libclient.PortListOpts) (ports map[string][]libclient.Port, err error) {
+	portList := []libclient.Port{}
+	err = ctx.client.List(&portList, opts)
+	if err != nil {
+		return
+	}
+	ports = make(map[string][]libclient.Port)
+	for _, port := range portList {
+		ports[port.DeviceID] = append(ports[port.DeviceID], port)
+	}
+	return
+}
+
 // Get updates since last sync.
 func (r *VMAdapter) GetUpdates(ctx *Context) (updates []Updater, err error) {
 	opts := &libclient.VMListOpts{}
@@ -508,7 +526,7 @@ func (r *VMAdapter) GetUpdates(ctx *Context) (updates []Updater, err error) {
 		return
 	}
 	for i := range vmList {
-		vm := &VM{vmList[i]}
+		vm := &VM{VM: vmList[i]}
 		switch vm.Status {
 		case libclient.VmStatusDeleted, libclient.VmStatusSoftDeleted:
 			updater := func(tx *libmodel.Tx) (err error) {
@@ -525,6 +543,14 @@ func (r *VMAdapter) GetUpdates(ctx *Context) (updates []Updater, err error) {
 			updates = append(updates, updater)
 
 		default:
+			var ports map[string][]libclient.Port
+			opts := &libclient.PortListOpts{}
+			opts.DeviceID = vm.ID
+			ports, err = r.ports(ctx, opts)
+			if err != nil {
+				return
+			}
+			vm.Ports = ports[vm.ID]
 			updater := func(tx *libmodel.Tx) (err error) {
 				m := &model.VM{
 					Base: model.Base{ID: vm.ID},
@@ -256,6 +256,8 @@ func (r *Fault) equalsTo(m *model.Fault) bool {
 
 type VM struct {
 	libclient.VM
+	// Network ports of the VM.
+	Ports []libclient.Port
 }
 
 type VMListOpts struct {
@@ -283,9 +285,19 @@ func (r *VM) ApplyTo(m *model.VM) {
 	m.AdminPass = r.AdminPass
 	m.SecurityGroups = r.SecurityGroups
 	r.addAttachedVolumes(m)
+	r.addPorts(m)
 	r.addFault(m)
 	m.Tags = r.Tags
 	m.ServerGroups = r.ServerGroups
+	m.ConfigDrive = r.HasConfigDrive()
+	r.addUserData(m)
+}
+
+func (r *VM) addUserData(m *model.VM) {
+	m.UserData = ""
+	if r.UserData != nil {
+		m.UserData = *r.UserData
+	}
 }
 
 func (r *VM) addImageID(m *model.VM) {
@@ -313,6 +325,45 @@ func (r *VM) addAttachedVolumes(m *model.VM) {
 	}
 }
 
+func (r *VM) addPorts(m *model.VM) {
+	m.Ports = []model.Port{}
+	for i := range r.Ports {
+		port := &r.Ports[i]
+		m.Ports = append(
+			m.Ports,
+			model.Port{
+				ID:        port.ID,
+				NetworkID: port.NetworkID,
+				MAC:       port.MACAddress,
+				VNICType:  port.VNICType,
+				SRIOV:     port.SRIOV(),
+			})
+	}
+}
+
+func (r *VM) equalPorts(m *model.VM) bool {
+	if len(r.Ports) != len(m.Ports) {
+		return false
+	}
+	for i := range r.Ports {
+		port := &r.Ports[i]
+		found := false
+		for _, mPort := range m.Ports {
+			if mPort.ID == port.ID &&
+				mPort.NetworkID == port.NetworkID &&
+				mPort.MAC == port.MACAddress &&
+				mPort.VNICType == port.VNICType {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
 func (r *VM) equalAttachedVolumes(m *model.VM) bool {
 	if len(r.AttachedVolumes) != len(m.AttachedVolumes) {
 		return false
@@ -349,6 +400,9 @@ func (r *VM) equalsTo(m *model.VM) bool {
 	if !r.equalAttachedVolumes(m) {
 		return false
 	}
+	if !r.equalPorts(m) {
+		return false
+	}
 	return m.Name == r.Name &&
 		m.ID == r.ID &&
 		m.TenantID == r.TenantID &&
@@ -366,7 +420,8 @@ func (r *VM) equalsTo(m *model.VM) bool {
 		m.AdminPass == r.AdminPass &&
 		reflect.DeepEqual(m.SecurityGroups, r.SecurityGroups) &&
 		reflect.DeepEqual(m.Tags, r.Tags) &&
-		reflect.DeepEqual(m.ServerGroups, r.ServerGroups)
+		reflect.DeepEqual(m.ServerGroups, r.ServerGroups) &&
+		m.ConfigDrive == r.HasConfigDrive()
 }
 
 type AttachedVolume struct {
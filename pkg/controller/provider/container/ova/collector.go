@@ -12,6 +12,8 @@ import (
 	liberr "github.com/kubev2v/forklift/pkg/lib/error"
 	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
 	"github.com/kubev2v/forklift/pkg/lib/logging"
+	"github.com/kubev2v/forklift/pkg/lib/tracing"
+	"go.opentelemetry.io/otel/attribute"
 	core "k8s.io/api/core/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -233,11 +235,18 @@ func (r *Collector) load(ctx *Context) (err error) {
 		"Initial Parity.",
 		"duration",
 		time.Since(mark))
+	tracing.Record(
+		tracing.WithUID(context.TODO(), string(r.provider.UID)),
+		"inventory.load",
+		mark,
+		time.Now(),
+		attribute.String("provider", r.provider.Namespace+"/"+r.provider.Name))
 
 	return
 }
 
 // List and create resources using the adapter.
+// The models are inserted in batches.
 func (r *Collector) create(ctx *Context, adapter Adapter) (err error) {
 	itr, aErr := adapter.List(ctx, r.provider)
 
@@ -245,12 +254,12 @@ func (r *Collector) create(ctx *Context, adapter Adapter) (err error) {
 		err = aErr
 		return
 	}
-	tx, err := r.db.Begin()
-	if err != nil {
-		return
+	batch := &libmodel.Batch{
+		DB:   r.db,
+		Size: Settings.Inventory.BatchSize,
 	}
 	defer func() {
-		_ = tx.End()
+		_ = batch.End()
 	}()
 	for {
 		object, hasNext := itr.Next()
@@ -261,12 +270,14 @@ func (r *Collector) create(ctx *Context, adapter Adapter) (err error) {
 			return
 		}
 		m := object.(libmodel.Model)
-		err = tx.Insert(m)
+		err = batch.With(func(tx *libmodel.Tx) error {
+			return tx.Insert(m)
+		})
 		if err != nil {
 			return
 		}
 	}
-	err = tx.Commit()
+	err = batch.Commit()
 	if err != nil {
 		return
 	}
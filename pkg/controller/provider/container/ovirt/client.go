@@ -1,6 +1,7 @@
 package ovirt
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"net"
@@ -32,7 +33,11 @@ type Client struct {
 	// Raw client.
 	client *libweb.Client
 	// Secret.
-	secret                *core.Secret
+	secret *core.Secret
+	// Proxy.
+	proxy func(*http.Request) (*liburl.URL, error)
+	// Dial.
+	dial                  func(context.Context, string, string) (net.Conn, error)
 	clientExpiration      time.Time
 	clientTimeout         time.Duration
 	accessTokenExpiration time.Time
@@ -78,11 +83,8 @@ func (r *Client) connect() (status int, err error) {
 	r.url = strings.TrimRight(r.url, "/")
 	client := &libweb.Client{
 		Transport: &http.Transport{
-			Proxy: http.ProxyFromEnvironment,
-			DialContext: (&net.Dialer{
-				Timeout:   10 * time.Second,
-				KeepAlive: 10 * time.Second,
-			}).DialContext,
+			Proxy:                 r.proxy,
+			DialContext:           r.dial,
 			MaxIdleConns:          10,
 			IdleConnTimeout:       10 * time.Second,
 			TLSHandshakeTimeout:   10 * time.Second,
@@ -3,6 +3,7 @@ package ovirt
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	liburl "net/url"
 	libpath "path"
@@ -16,6 +17,9 @@ import (
 	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
 	libweb "github.com/kubev2v/forklift/pkg/lib/inventory/web"
 	"github.com/kubev2v/forklift/pkg/lib/logging"
+	"github.com/kubev2v/forklift/pkg/lib/tracing"
+	"github.com/kubev2v/forklift/pkg/lib/util"
+	"go.opentelemetry.io/otel/attribute"
 	core "k8s.io/api/core/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -85,8 +89,15 @@ func New(db libmodel.DB, provider *api.Provider, secret *core.Secret) (r *Collec
 	}
 	r = &Collector{
 		client: &Client{
-			url:           provider.Spec.URL,
-			secret:        secret,
+			url:    provider.Spec.URL,
+			secret: secret,
+			proxy:  util.ProviderProxy(provider),
+			dial: util.ProviderDialer(
+				provider,
+				&net.Dialer{
+					Timeout:   10 * time.Second,
+					KeepAlive: 10 * time.Second,
+				}),
 			log:           clientLog,
 			clientTimeout: clientTimeout,
 		},
@@ -306,23 +317,30 @@ func (r *Collector) load(ctx *Context) (err error) {
 		"Initial Parity.",
 		"duration",
 		time.Since(mark))
+	tracing.Record(
+		tracing.WithUID(context.TODO(), string(r.provider.UID)),
+		"inventory.load",
+		mark,
+		time.Now(),
+		attribute.String("provider", r.provider.Namespace+"/"+r.provider.Name))
 
 	return
 }
 
 // List and create resources using the adapter.
+// The models are inserted in batches.
 func (r *Collector) create(ctx *Context, adapter Adapter) (err error) {
 	itr, aErr := adapter.List(ctx)
 	if aErr != nil {
 		err = aErr
 		return
 	}
-	tx, err := r.db.Begin()
-	if err != nil {
-		return
+	batch := &libmodel.Batch{
+		DB:   r.db,
+		Size: Settings.Inventory.BatchSize,
 	}
 	defer func() {
-		_ = tx.End()
+		_ = batch.End()
 	}()
 	for {
 		object, hasNext := itr.Next()
@@ -333,12 +351,14 @@ func (r *Collector) create(ctx *Context, adapter Adapter) (err error) {
 			return
 		}
 		m := object.(libmodel.Model)
-		err = tx.Insert(m)
+		err = batch.With(func(tx *libmodel.Tx) error {
+			return tx.Insert(m)
+		})
 		if err != nil {
 			return
 		}
 	}
-	err = tx.Commit()
+	err = batch.Commit()
 	if err != nil {
 		return
 	}
@@ -43,6 +43,10 @@ const (
 	USER_ADD_CLUSTER    = 809
 	USER_UPDATE_CLUSTER = 811
 	USER_REMOVE_CLUSTER = 813
+	// Affinity Group
+	USER_ADDED_AFFINITY_GROUP   = 10350
+	USER_UPDATED_AFFINITY_GROUP = 10352
+	USER_REMOVED_AFFINITY_GROUP = 10354
 	// Host
 	USER_ADD_VDS                        = 42
 	USER_UPDATE_VDS                     = 43
@@ -577,13 +581,16 @@ func (r *ClusterAdapter) Event() []int {
 		USER_ADD_CLUSTER,
 		USER_UPDATE_CLUSTER,
 		USER_REMOVE_CLUSTER,
+		USER_ADDED_AFFINITY_GROUP,
+		USER_UPDATED_AFFINITY_GROUP,
+		USER_REMOVED_AFFINITY_GROUP,
 	}
 }
 
 // List the collection.
 func (r *ClusterAdapter) List(ctx *Context) (itr fb.Iterator, err error) {
 	clusterList := ClusterList{}
-	err = ctx.client.list("clusters", &clusterList)
+	err = ctx.client.list("clusters", &clusterList, r.follow())
 	if err != nil {
 		return
 	}
@@ -618,7 +625,7 @@ func (r *ClusterAdapter) Apply(ctx *Context, event *Event) (updater Updater, err
 	switch event.code() {
 	case USER_ADD_CLUSTER:
 		object := &Cluster{}
-		err = ctx.client.get(event.Cluster.Ref, object)
+		err = ctx.client.get(event.Cluster.Ref, object, r.follow())
 		if err != nil {
 			break
 		}
@@ -630,9 +637,12 @@ func (r *ClusterAdapter) Apply(ctx *Context, event *Event) (updater Updater, err
 			err = tx.Insert(m)
 			return
 		}
-	case USER_UPDATE_CLUSTER:
+	case USER_UPDATE_CLUSTER,
+		USER_ADDED_AFFINITY_GROUP,
+		USER_UPDATED_AFFINITY_GROUP,
+		USER_REMOVED_AFFINITY_GROUP:
 		object := &Cluster{}
-		err = ctx.client.get(event.Cluster.Ref, object)
+		err = ctx.client.get(event.Cluster.Ref, object, r.follow())
 		if err != nil {
 			break
 		}
@@ -663,6 +673,12 @@ func (r *ClusterAdapter) Apply(ctx *Context, event *Event) (updater Updater, err
 	return
 }
 
+// Build follow parameter.
+func (r *ClusterAdapter) follow() libweb.Param {
+	return r.BaseAdapter.follow(
+		"affinity_groups")
+}
+
 // ServerCPUAdapter adapter.
 type ServerCPUAdapter struct {
 	BaseAdapter
@@ -84,6 +84,28 @@ type Cluster struct {
 		Minor string `json:"minor"`
 		Major string `json:"major"`
 	} `json:"version"`
+	AffinityGroups struct {
+		List []struct {
+			Name string `json:"name"`
+			// Legacy VM rule.
+			Positive  string `json:"positive"`
+			Enforcing string `json:"enforcing"`
+			VmsRule   struct {
+				Enabled   string `json:"enabled"`
+				Positive  string `json:"positive"`
+				Enforcing string `json:"enforcing"`
+			} `json:"vms_rule"`
+			HostsRule struct {
+				Enabled string `json:"enabled"`
+			} `json:"hosts_rule"`
+			Hosts struct {
+				List []Ref `json:"host"`
+			} `json:"hosts"`
+			VMs struct {
+				List []Ref `json:"vm"`
+			} `json:"vms"`
+		} `json:"affinity_group"`
+	} `json:"affinity_groups"`
 }
 
 // Apply to (update) the model.
@@ -97,6 +119,32 @@ func (r *Cluster) ApplyTo(m *model.Cluster) {
 	m.CPU.Type = r.CPU.Type
 	m.Version.Minor = r.Version.Minor
 	m.Version.Major = r.Version.Major
+	r.addAffinityGroups(m)
+}
+
+// The legacy VM rule is used when the
+// VM rule is not reported (oVirt < 4.1).
+func (r *Cluster) addAffinityGroups(m *model.Cluster) {
+	m.AffinityGroups = []model.AffinityGroup{}
+	for _, g := range r.AffinityGroups.List {
+		group := model.AffinityGroup{
+			Name:         g.Name,
+			Enabled:      true,
+			Positive:     r.bool(g.Positive),
+			Enforcing:    r.bool(g.Enforcing),
+			HostsEnabled: r.bool(g.HostsRule.Enabled) && len(g.Hosts.List) > 0,
+			VMs:          []string{},
+		}
+		if g.VmsRule.Enabled != "" {
+			group.Enabled = r.bool(g.VmsRule.Enabled)
+			group.Positive = r.bool(g.VmsRule.Positive)
+			group.Enforcing = r.bool(g.VmsRule.Enforcing)
+		}
+		for _, vm := range g.VMs.List {
+			group.VMs = append(group.VMs, vm.ID)
+		}
+		m.AffinityGroups = append(m.AffinityGroups, group)
+	}
 }
 
 // Cluster (list).
@@ -262,7 +310,14 @@ type VM struct {
 		} `json:"boot_menu"`
 	} `json:"bios"`
 	CustomCpuModel string `json:"custom_cpu_model"`
-	Display        struct {
+	Initialization struct {
+		HostName          string `json:"host_name"`
+		UserName          string `json:"user_name"`
+		AuthorizedSSHKeys string `json:"authorized_ssh_keys"`
+		Timezone          string `json:"timezone"`
+		CustomScript      string `json:"custom_script"`
+	} `json:"initialization"`
+	Display struct {
 		Type string `json:"type"`
 	} `json:"display"`
 	HasIllegalImages string `json:"has_illegal_images"`
@@ -383,6 +438,13 @@ func (r *VM) ApplyTo(m *model.VM) {
 	m.HaEnabled = r.bool(r.HA.Enabled)
 	m.IOThreads = r.int16(r.IO.Threads)
 	m.CustomCpuModel = r.CustomCpuModel
+	m.Initialization = model.Initialization{
+		HostName:          r.Initialization.HostName,
+		UserName:          r.Initialization.UserName,
+		AuthorizedSSHKeys: r.Initialization.AuthorizedSSHKeys,
+		Timezone:          r.Initialization.Timezone,
+		CustomScript:      r.Initialization.CustomScript,
+	}
 	r.addCpuAffinity(m)
 	r.addNICs(m)
 	r.addDiskAttachment(m)
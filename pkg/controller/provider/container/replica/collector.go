@@ -0,0 +1,384 @@
+package replica
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	libpath "path"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+	"github.com/kubev2v/forklift/pkg/settings"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+// Application settings.
+var Settings = &settings.Settings
+
+// Settings
+const (
+	// Retry interval.
+	RetryInterval = 5 * time.Second
+)
+
+// Read replica collector.
+// The inventory of the provider is served from the snapshot
+// of the provider DB periodically downloaded from the writer.
+// The snapshot is applied to the (replica) DB so the watches
+// are kept and notified of the changes.
+type Collector struct {
+	// Provider
+	provider *api.Provider
+	// Directory of the snapshots.
+	dir string
+	// Models of the provider DB.
+	models []interface{}
+	// Logger.
+	log logging.LevelLogger
+	// HTTP client.
+	client *http.Client
+	// HTTP header.
+	header http.Header
+	// Mutex.
+	mutex sync.RWMutex
+	// DB client. Opened (empty) on start and
+	// populated by the synchronized snapshots.
+	db libmodel.DB
+	// has parity.
+	parity bool
+	// cancel function.
+	cancel func()
+}
+
+// New collector.
+func New(provider *api.Provider, dir string, models ...interface{}) (r *Collector) {
+	log := logging.WithName("collector|replica").WithValues(
+		"provider",
+		libpath.Join(
+			provider.GetNamespace(),
+			provider.GetName()))
+
+	r = &Collector{
+		provider: provider,
+		dir:      dir,
+		models:   models,
+		log:      log,
+	}
+
+	return
+}
+
+// The name.
+func (r *Collector) Name() string {
+	return Settings.Inventory.Replica.WriterURL
+}
+
+// The owner.
+func (r *Collector) Owner() meta.Object {
+	return r.provider
+}
+
+// Get the DB.
+func (r *Collector) DB() libmodel.DB {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.db
+}
+
+// NO-OP
+func (r *Collector) Reset() {
+}
+
+// Has parity.
+// Achieved once the first snapshot has been synchronized.
+func (r *Collector) HasParity() bool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.parity
+}
+
+// NO-OP
+// The connection is tested by the writer.
+func (r *Collector) Test() (_ int, err error) {
+	return
+}
+
+// NO-OP
+func (r *Collector) Version() (_, _, _, _ string, err error) {
+	return
+}
+
+// Follow link
+func (r *Collector) Follow(moRef interface{}, p []string, dst interface{}) error {
+	return fmt.Errorf("not supported")
+}
+
+// Start the collector.
+func (r *Collector) Start() (err error) {
+	err = r.buildClient()
+	if err != nil {
+		return
+	}
+	r.db = libmodel.New(r.path(), r.models...)
+	err = r.db.Open(true)
+	if err != nil {
+		return
+	}
+	var ctx context.Context
+	ctx, r.cancel = context.WithCancel(context.Background())
+	start := func() {
+		defer func() {
+			r.log.Info("Stopped.")
+		}()
+		interval := time.Duration(Settings.Inventory.Replica.SyncInterval) * time.Second
+		for {
+			wait := interval
+			err := r.sync(ctx)
+			if err != nil {
+				r.log.Error(
+					err,
+					"Synchronization failed.")
+				wait = RetryInterval
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(wait):
+			}
+		}
+	}
+
+	go start()
+
+	return
+}
+
+// Shutdown the collector.
+func (r *Collector) Shutdown() {
+	r.log.Info("Shutdown.")
+	if r.cancel != nil {
+		r.cancel()
+	}
+}
+
+// Download the snapshot of the provider DB from the writer
+// and apply it to the DB. The snapshot is deleted once applied.
+func (r *Collector) sync(ctx context.Context) (err error) {
+	mark := time.Now()
+	url := Settings.Inventory.Replica.WriterURL + base.Link(
+		web.SnapshotRoot,
+		base.Params{
+			base.ProviderParam: string(r.provider.UID),
+		})
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	request.Header = r.header
+	response, err := r.client.Do(request)
+	if err != nil {
+		err = liberr.Wrap(err, "url", url)
+		return
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+	if response.StatusCode != http.StatusOK {
+		err = liberr.New(
+			http.StatusText(response.StatusCode),
+			"url",
+			url)
+		return
+	}
+	path := r.path()
+	err = r.download(response.Body, path)
+	if err != nil {
+		_ = os.Remove(path)
+		return
+	}
+	snapshot, err := r.open(path)
+	if err != nil {
+		_ = os.Remove(path)
+		return
+	}
+	defer func() {
+		_ = snapshot.Close(true)
+	}()
+	err = r.apply(snapshot)
+	if err != nil {
+		return
+	}
+	r.mutex.Lock()
+	r.parity = true
+	r.mutex.Unlock()
+
+	r.log.V(1).Info(
+		"Snapshot synchronized.",
+		"path",
+		path,
+		"duration",
+		time.Since(mark))
+
+	return
+}
+
+// Apply the snapshot to the DB.
+// The models are created, updated and deleted in a single
+// transaction. Only the changed models are written (reported).
+func (r *Collector) apply(snapshot libmodel.DB) (err error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = tx.End()
+	}()
+	for _, m := range r.models {
+		err = r.applyModels(tx, snapshot, m)
+		if err != nil {
+			return
+		}
+	}
+	err = tx.Commit()
+	return
+}
+
+// Apply the models of the kind.
+func (r *Collector) applyModels(tx *libmodel.Tx, snapshot libmodel.DB, kind interface{}) (err error) {
+	listType := reflect.SliceOf(reflect.TypeOf(kind).Elem())
+	current := reflect.New(listType).Interface()
+	err = tx.List(current, libmodel.ListOptions{Detail: libmodel.MaxDetail})
+	if err != nil {
+		return
+	}
+	wanted := reflect.New(listType).Interface()
+	err = snapshot.List(wanted, libmodel.ListOptions{Detail: libmodel.MaxDetail})
+	if err != nil {
+		return
+	}
+	before := r.index(current)
+	after := r.index(wanted)
+	for pk, m := range after {
+		if _, found := before[pk]; found {
+			err = tx.Update(m)
+		} else {
+			err = tx.Insert(m)
+		}
+		if err != nil {
+			return
+		}
+	}
+	for pk, m := range before {
+		if _, found := after[pk]; !found {
+			err = tx.Delete(m)
+			if err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// Index the listed models by primary key.
+func (r *Collector) index(list interface{}) (models map[string]libmodel.Model) {
+	models = map[string]libmodel.Model{}
+	lv := reflect.ValueOf(list).Elem()
+	for i := 0; i < lv.Len(); i++ {
+		m := lv.Index(i).Addr().Interface().(libmodel.Model)
+		models[m.Pk()] = m
+	}
+	return
+}
+
+// Unique path of a DB file.
+func (r *Collector) path() string {
+	return filepath.Join(
+		r.dir,
+		string(r.provider.UID)+"-"+rand.String(8)+".db")
+}
+
+// Open the snapshot.
+// The DB panics when the file is not a (valid) DB.
+func (r *Collector) open(path string) (snapshot libmodel.DB, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = liberr.New(fmt.Sprint(p), "path", path)
+		}
+	}()
+	snapshot = libmodel.New(path, r.models...)
+	err = snapshot.Open(false)
+	return
+}
+
+// Write the snapshot to the file.
+func (r *Collector) download(reader io.Reader, path string) (err error) {
+	file, err := os.Create(path)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	_, err = io.Copy(file, reader)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	return
+}
+
+// Build the HTTP client.
+// The writer certificate is verified using the inventory CA
+// and the replica authenticated by the service account token.
+func (r *Collector) buildClient() (err error) {
+	r.header = http.Header{}
+	if cfg, cErr := config.GetConfig(); cErr == nil {
+		r.header.Set("Authorization", "Bearer "+cfg.BearerToken)
+	}
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   10 * time.Second,
+			KeepAlive: 10 * time.Second,
+		}).DialContext,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       10 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+	if Settings.Inventory.TLS.CA != "" {
+		pool := x509.NewCertPool()
+		ca, xErr := os.ReadFile(Settings.Inventory.TLS.CA)
+		if xErr != nil {
+			err = liberr.Wrap(xErr)
+			return
+		}
+		pool.AppendCertsFromPEM(ca)
+		transport.TLSClientConfig = &tls.Config{
+			RootCAs: pool,
+		}
+	} else if Settings.Development {
+		transport.TLSClientConfig = &tls.Config{
+			InsecureSkipVerify: true,
+		}
+	}
+	r.client = &http.Client{Transport: transport}
+	return
+}
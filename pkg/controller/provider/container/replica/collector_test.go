@@ -0,0 +1,197 @@
+package replica
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/ova"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSync(t *testing.T) {
+	dir := t.TempDir()
+	writer := libmodel.New(filepath.Join(dir, "writer.db"), model.All()...)
+	err := writer.Open(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = writer.Close(true)
+	}()
+	err = writer.Insert(&model.Network{Base: model.Base{ID: "net-1", Name: "VM Network"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/snapshots/p-1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		path := filepath.Join(dir, "snapshot.db")
+		if err := writer.Snapshot(path); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		http.ServeFile(w, r, path)
+	}))
+	defer server.Close()
+	Settings.Inventory.Replica.WriterURL = server.URL
+	defer func() {
+		Settings.Inventory.Replica.WriterURL = ""
+	}()
+
+	provider := &api.Provider{ObjectMeta: meta.ObjectMeta{UID: "p-1"}}
+	collector := newCollector(t, provider, dir)
+	err = collector.sync(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !collector.HasParity() {
+		t.Errorf("expected parity after the first sync")
+	}
+	network := &model.Network{Base: model.Base{ID: "net-1"}}
+	err = collector.DB().Get(network)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if network.Name != "VM Network" {
+		t.Errorf("unexpected network: %s", network.Name)
+	}
+
+	// The watch survives the sync and is notified of the changes.
+	handler := &eventHandler{events: make(chan libmodel.Event, 10)}
+	watch, err := collector.DB().Watch(&model.Network{}, handler)
+	if err != nil {
+		t.Fatal(err)
+	}
+	event := handler.next(t)
+	if event.Action != libmodel.Created {
+		t.Fatalf("expected the network (snapshot) reported")
+	}
+	err = writer.Update(&model.Network{Base: model.Base{ID: "net-1", Name: "Changed"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = writer.Insert(&model.Network{Base: model.Base{ID: "net-2", Name: "Added"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = collector.sync(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+	actions := map[string]uint8{}
+	for i := 0; i < 2; i++ {
+		event = handler.next(t)
+		actions[event.Model.Pk()] = event.Action
+	}
+	if actions["net-1"] != libmodel.Updated || actions["net-2"] != libmodel.Created {
+		t.Errorf("unexpected events: %v", actions)
+	}
+	if !watch.Alive() || handler.ended {
+		t.Errorf("expected the watch not ended")
+	}
+	watch.End()
+	_ = collector.DB().Close(true)
+
+	provider.UID = "p-2"
+	collector = newCollector(t, provider, dir)
+	err = collector.sync(context.TODO())
+	if err == nil {
+		t.Errorf("expected the unknown provider rejected by the writer")
+	}
+	if collector.HasParity() {
+		t.Errorf("expected no parity")
+	}
+}
+
+// Build the collector with the DB opened.
+func newCollector(t *testing.T, provider *api.Provider, dir string) (collector *Collector) {
+	collector = New(provider, dir, model.All()...)
+	err := collector.buildClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	collector.db = libmodel.New(collector.path(), model.All()...)
+	err = collector.db.Open(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return
+}
+
+// Event handler.
+type eventHandler struct {
+	libmodel.StockEventHandler
+	events chan libmodel.Event
+	ended  bool
+}
+
+func (r *eventHandler) Options() libmodel.WatchOptions {
+	return libmodel.WatchOptions{Snapshot: true}
+}
+
+func (r *eventHandler) Created(event libmodel.Event) {
+	r.events <- event
+}
+
+func (r *eventHandler) Updated(event libmodel.Event) {
+	r.events <- event
+}
+
+func (r *eventHandler) Deleted(event libmodel.Event) {
+	r.events <- event
+}
+
+func (r *eventHandler) End() {
+	r.ended = true
+}
+
+// The next event.
+func (r *eventHandler) next(t *testing.T) (event libmodel.Event) {
+	select {
+	case event = <-r.events:
+	case <-time.After(5 * time.Second):
+		t.Fatal("event not received")
+	}
+	return
+}
+
+// The snapshot that cannot be opened is deleted.
+func TestSyncCorrupt(t *testing.T) {
+	dir := t.TempDir()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("not a database"))
+	}))
+	defer server.Close()
+	Settings.Inventory.Replica.WriterURL = server.URL
+	defer func() {
+		Settings.Inventory.Replica.WriterURL = ""
+	}()
+
+	provider := &api.Provider{ObjectMeta: meta.ObjectMeta{UID: "p-1"}}
+	collector := newCollector(t, provider, dir)
+	defer func() {
+		_ = collector.DB().Close(true)
+	}()
+	err := collector.sync(context.TODO())
+	if err == nil {
+		t.Fatalf("expected the snapshot rejected")
+	}
+	if collector.HasParity() {
+		t.Errorf("expected no parity")
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Errorf("expected only the replica DB: %v", files)
+	}
+}
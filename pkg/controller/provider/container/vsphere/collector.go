@@ -8,6 +8,7 @@ import (
 	"path"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/kubev2v/forklift/pkg/lib/util"
@@ -18,6 +19,8 @@ import (
 	liberr "github.com/kubev2v/forklift/pkg/lib/error"
 	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
 	"github.com/kubev2v/forklift/pkg/lib/logging"
+	"github.com/kubev2v/forklift/pkg/lib/tracing"
+	metrics "github.com/kubev2v/forklift/pkg/monitoring/metrics/forklift-controller"
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/property"
 	"github.com/vmware/govmomi/session"
@@ -25,6 +28,7 @@ import (
 	"github.com/vmware/govmomi/vim25/methods"
 	"github.com/vmware/govmomi/vim25/soap"
 	"github.com/vmware/govmomi/vim25/types"
+	"go.opentelemetry.io/otel/attribute"
 	core "k8s.io/api/core/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -73,6 +77,7 @@ const (
 	fDrsEnabled    = "configuration.drsConfig.enabled"
 	fDrsVmBehavior = "configuration.drsConfig.defaultVmBehavior"
 	fDrsVmCfg      = "configuration.drsVmConfig"
+	fConfigEx      = "configurationEx"
 	// Host
 	fVm             = "vm"
 	fOverallStatus  = "overallStatus"
@@ -121,9 +126,12 @@ const (
 	fNumCpu                   = "config.hardware.numCPU"
 	fNumCoresPerSocket        = "config.hardware.numCoresPerSocket"
 	fMemorySize               = "config.hardware.memoryMB"
+	fMemoryAllocation         = "config.memoryAllocation"
+	fMemoryReservationLocked  = "config.memoryReservationLockedToMax"
 	fDevices                  = "config.hardware.device"
 	fExtraConfig              = "config.extraConfig"
 	fNestedHVEnabled          = "config.nestedHVEnabled"
+	fLatencySensitivity       = "config.latencySensitivity"
 	fChangeTracking           = "config.changeTrackingEnabled"
 	fGuestName                = "summary.config.guestFullName"
 	fGuestNameFromVmwareTools = "guest.guestFullName"
@@ -140,6 +148,9 @@ const (
 	fGuestNet                 = "guest.net"
 	fGuestIpStack             = "guest.ipStack"
 	fHostName                 = "guest.hostName"
+	fAvailableField           = "availableField"
+	fCustomValue              = "customValue"
+	fAnnotation               = "config.annotation"
 )
 
 // Selections
@@ -276,6 +287,14 @@ type Collector struct {
 	cancel func()
 	// has parity.
 	parity bool
+	// The vCenter key when collecting an additional vCenter.
+	vcenter string
+	// Collectors for the additional vCenters.
+	vcenters []*Collector
+	// The additional vCenters have been discovered (and started).
+	discovered bool
+	// Protect the additional vCenters.
+	mutex sync.Mutex
 }
 
 // New collector.
@@ -317,11 +336,33 @@ func (r *Collector) DB() libmodel.DB {
 // Reset.
 func (r *Collector) Reset() {
 	r.parity = false
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, collector := range r.vcenters {
+		collector.Reset()
+	}
 }
 
 // Reset.
+// Parity with all of the vCenters.
 func (r *Collector) HasParity() bool {
-	return r.parity
+	if !r.parity {
+		return false
+	}
+	if r.vcenter != "" {
+		return true
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if !r.discovered {
+		return false
+	}
+	for _, collector := range r.vcenters {
+		if !collector.HasParity() {
+			return false
+		}
+	}
+	return true
 }
 
 // Follow
@@ -330,6 +371,10 @@ func (r *Collector) Follow(moRef interface{}, p []string, dst interface{}) error
 	if !ok {
 		return fmt.Errorf("reference must be of type ManagedObjectReference")
 	}
+	if collector, unprefixed := r.vCenterCollector(ref.Value); collector != r {
+		ref.Value = unprefixed
+		return collector.Follow(ref, p, dst)
+	}
 
 	ctx := context.Background()
 	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
@@ -388,6 +433,16 @@ func (r *Collector) Start() error {
 
 	go start()
 
+	if r.vcenter == "" {
+		if r.provider.LinkedMode() || r.provider.Spec.Settings[api.VCenters] != "" {
+			go r.startVCenters(ctx)
+		} else {
+			r.mutex.Lock()
+			r.discovered = true
+			r.mutex.Unlock()
+		}
+	}
+
 	return nil
 }
 
@@ -397,6 +452,11 @@ func (r *Collector) Shutdown() {
 	if r.cancel != nil {
 		r.cancel()
 	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, collector := range r.vcenters {
+		collector.Shutdown()
+	}
 }
 
 // Get object updates.
@@ -413,6 +473,11 @@ func (r *Collector) getUpdates(ctx context.Context) error {
 	about := r.client.ServiceContent.About
 	err = r.db.Insert(
 		&model.About{
+			Base: model.Base{
+				ID:      r.vcenter,
+				VCenter: r.vcenter,
+			},
+			URL:          r.url,
 			APIVersion:   about.ApiVersion,
 			Product:      about.LicenseProductName,
 			InstanceUuid: about.InstanceUuid,
@@ -442,19 +507,12 @@ func (r *Collector) getUpdates(ctx context.Context) error {
 		This:    pc.Reference(),
 		Options: filter.Options,
 	}
-	var tx *libmodel.Tx
 	watchList := []*libmodel.Watch{}
 	defer func() {
 		r.parity = false
 		for _, w := range watchList {
 			w.End()
 		}
-		if tx != nil {
-			err := tx.End()
-			if err != nil {
-				r.log.Error(err, "tx end failed.")
-			}
-		}
 	}()
 	for {
 		response, err := methods.WaitForUpdatesEx(ctx, r.client, &req)
@@ -475,30 +533,19 @@ func (r *Collector) getUpdates(ctx context.Context) error {
 		if updateSet == nil {
 			continue
 		}
-		req.Version = updateSet.Version
-		tx, err = r.db.Begin()
-		if err != nil {
-			return err
-		}
-		for _, fs := range updateSet.FilterSet {
-			err = r.apply(ctx, tx, fs.ObjectSet)
-			if err != nil {
-				r.log.Error(
-					err,
-					"apply changes failed.")
-				break
-			}
-		}
-		if err == nil {
-			err = tx.Commit()
-		} else {
-			err = tx.End()
-		}
+		err = r.applySet(ctx, updateSet)
 		if err != nil {
+			// The version is not advanced and
+			// the update set is received again.
 			r.log.Error(
 				err,
-				"tx commit failed.")
+				"apply changes failed.",
+				"retry",
+				RetryDelay)
+			time.Sleep(RetryDelay)
+			continue
 		}
+		req.Version = updateSet.Version
 		if updateSet.Truncated == nil || !*updateSet.Truncated {
 			if !r.parity {
 				r.parity = true
@@ -506,7 +553,15 @@ func (r *Collector) getUpdates(ctx context.Context) error {
 					"Initial parity.",
 					"duration",
 					time.Since(mark))
-				watchList = r.watch()
+				tracing.Record(
+					tracing.WithUID(context.TODO(), string(r.provider.UID)),
+					"inventory.load",
+					mark,
+					time.Now(),
+					attribute.String("provider", r.provider.Namespace+"/"+r.provider.Name))
+				if r.vcenter == "" {
+					watchList = r.watch()
+				}
 			}
 		}
 	}
@@ -598,11 +653,18 @@ func (r *Collector) buildClient(ctx context.Context) (*govmomi.Client, error) {
 	}
 
 	soapClient := soap.NewClient(url, skipVerifying)
+	soapClient.DefaultTransport().Proxy = util.ProviderProxy(r.provider)
+	soapClient.DefaultTransport().DialContext = util.ProviderDialer(r.provider, nil)
 	soapClient.SetThumbprint(url.Host, thumbprint)
 	vimClient, err := vim25.NewClient(ctx, soapClient)
 	if err != nil {
 		return nil, liberr.Wrap(err)
 	}
+	vimClient.RoundTripper = &ThrottledRoundTripper{
+		RoundTripper: vimClient.RoundTripper,
+		Provider:     r.provider,
+		Throttled:    metrics.RecordThrottled,
+	}
 	client := &govmomi.Client{
 		SessionManager: session.NewManager(vimClient),
 		Client:         vimClient,
@@ -657,7 +719,7 @@ func (r *Collector) filter(pc *property.Collector) *property.WaitFilter {
 			},
 		},
 		WaitOptions: property.WaitOptions{Options: &types.WaitOptions{
-			MaxObjectUpdates: MaxObjectUpdates}},
+			MaxObjectUpdates: int32(min(Settings.Inventory.BatchSize, MaxObjectUpdates))}},
 	}
 }
 
@@ -711,6 +773,8 @@ func (r *Collector) propertySpec() []types.PropertySpec {
 				fDrsEnabled,
 				fDrsVmBehavior,
 				fDrsVmCfg,
+				fConfigEx,
+				fSummary,
 				fHost,
 				fNetwork,
 				fDatastore,
@@ -812,10 +876,13 @@ func (r *Collector) vmPathSet() []string {
 		fNumCpu,
 		fNumCoresPerSocket,
 		fMemorySize,
+		fMemoryAllocation,
+		fMemoryReservationLocked,
 		fDevices,
 		fGuestNet,
 		fExtraConfig,
 		fNestedHVEnabled,
+		fLatencySensitivity,
 		fGuestName,
 		fGuestNameFromVmwareTools,
 		fGuestID,
@@ -832,6 +899,9 @@ func (r *Collector) vmPathSet() []string {
 		fChangeTracking,
 		fGuestIpStack,
 		fHostName,
+		fAvailableField,
+		fCustomValue,
+		fAnnotation,
 	}
 
 	apiVer := strings.Split(r.client.ServiceContent.About.ApiVersion, ".")
@@ -843,6 +913,31 @@ func (r *Collector) vmPathSet() []string {
 	return pathSet
 }
 
+// Apply the update set.
+// The update set is committed in a single transaction so the
+// version is only advanced once the whole set has been applied.
+// The size of the update set is bounded by the batch size.
+func (r *Collector) applySet(ctx context.Context, updateSet *types.UpdateSet) (err error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return
+	}
+	for _, fs := range updateSet.FilterSet {
+		r.namespace(fs.ObjectSet)
+		err = r.apply(ctx, tx, fs.ObjectSet)
+		if err != nil {
+			_ = tx.End()
+			return
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+
+	return
+}
+
 // Apply updates.
 func (r *Collector) apply(ctx context.Context, tx *libmodel.Tx, updates []types.ObjectUpdate) (err error) {
 	for _, u := range updates {
@@ -871,7 +966,8 @@ func (r *Collector) selectAdapter(u types.ObjectUpdate) (Adapter, bool) {
 		adapter = &FolderAdapter{
 			model: model.Folder{
 				Base: model.Base{
-					ID: u.Obj.Value,
+					ID:      u.Obj.Value,
+					VCenter: r.vcenter,
 				},
 			},
 		}
@@ -879,7 +975,8 @@ func (r *Collector) selectAdapter(u types.ObjectUpdate) (Adapter, bool) {
 		adapter = &DatacenterAdapter{
 			model: model.Datacenter{
 				Base: model.Base{
-					ID: u.Obj.Value,
+					ID:      u.Obj.Value,
+					VCenter: r.vcenter,
 				},
 			},
 		}
@@ -889,6 +986,7 @@ func (r *Collector) selectAdapter(u types.ObjectUpdate) (Adapter, bool) {
 				Base: model.Base{
 					Variant: model.ComputeResource,
 					ID:      u.Obj.Value,
+					VCenter: r.vcenter,
 				},
 			},
 		}
@@ -896,7 +994,8 @@ func (r *Collector) selectAdapter(u types.ObjectUpdate) (Adapter, bool) {
 		adapter = &ClusterAdapter{
 			model: model.Cluster{
 				Base: model.Base{
-					ID: u.Obj.Value,
+					ID:      u.Obj.Value,
+					VCenter: r.vcenter,
 				},
 			},
 		}
@@ -904,7 +1003,8 @@ func (r *Collector) selectAdapter(u types.ObjectUpdate) (Adapter, bool) {
 		adapter = &HostAdapter{
 			model: model.Host{
 				Base: model.Base{
-					ID: u.Obj.Value,
+					ID:      u.Obj.Value,
+					VCenter: r.vcenter,
 				},
 			},
 		}
@@ -914,6 +1014,7 @@ func (r *Collector) selectAdapter(u types.ObjectUpdate) (Adapter, bool) {
 				Base: model.Base{
 					Variant: model.NetStandard,
 					ID:      u.Obj.Value,
+					VCenter: r.vcenter,
 				},
 			},
 		}
@@ -923,6 +1024,7 @@ func (r *Collector) selectAdapter(u types.ObjectUpdate) (Adapter, bool) {
 				Base: model.Base{
 					Variant: model.OpaqueNetwork,
 					ID:      u.Obj.Value,
+					VCenter: r.vcenter,
 				},
 			},
 		}
@@ -932,6 +1034,7 @@ func (r *Collector) selectAdapter(u types.ObjectUpdate) (Adapter, bool) {
 				Base: model.Base{
 					Variant: model.NetDvPortGroup,
 					ID:      u.Obj.Value,
+					VCenter: r.vcenter,
 				},
 			},
 		}
@@ -941,6 +1044,7 @@ func (r *Collector) selectAdapter(u types.ObjectUpdate) (Adapter, bool) {
 				Base: model.Base{
 					Variant: model.NetDvSwitch,
 					ID:      u.Obj.Value,
+					VCenter: r.vcenter,
 				},
 			},
 		}
@@ -955,7 +1059,8 @@ func (r *Collector) selectAdapter(u types.ObjectUpdate) (Adapter, bool) {
 		adapter = &DatastoreAdapter{
 			model: model.Datastore{
 				Base: model.Base{
-					ID: datastoreId,
+					ID:      datastoreId,
+					VCenter: r.vcenter,
 				},
 			},
 		}
@@ -963,7 +1068,8 @@ func (r *Collector) selectAdapter(u types.ObjectUpdate) (Adapter, bool) {
 		adapter = &VmAdapter{
 			model: model.VM{
 				Base: model.Base{
-					ID: u.Obj.Value,
+					ID:      u.Obj.Value,
+					VCenter: r.vcenter,
 				},
 			},
 		}
@@ -976,7 +1082,7 @@ func (r *Collector) selectAdapter(u types.ObjectUpdate) (Adapter, bool) {
 }
 
 // Object created.
-func (r Collector) applyEnter(tx *libmodel.Tx, u types.ObjectUpdate) error {
+func (r *Collector) applyEnter(tx *libmodel.Tx, u types.ObjectUpdate) error {
 	adapter, selected := r.selectAdapter(u)
 	if !selected {
 		return nil
@@ -992,7 +1098,7 @@ func (r Collector) applyEnter(tx *libmodel.Tx, u types.ObjectUpdate) error {
 }
 
 // Object modified.
-func (r Collector) applyModify(tx *libmodel.Tx, u types.ObjectUpdate) error {
+func (r *Collector) applyModify(tx *libmodel.Tx, u types.ObjectUpdate) error {
 	adapter, selected := r.selectAdapter(u)
 	if !selected {
 		return nil
@@ -1012,7 +1118,7 @@ func (r Collector) applyModify(tx *libmodel.Tx, u types.ObjectUpdate) error {
 }
 
 // Object deleted.
-func (r Collector) applyLeave(tx *libmodel.Tx, u types.ObjectUpdate) error {
+func (r *Collector) applyLeave(tx *libmodel.Tx, u types.ObjectUpdate) error {
 	var deleted model.Model
 	switch u.Obj.Type {
 	case Folder:
@@ -0,0 +1,41 @@
+package vsphere
+
+import (
+	modelVsphere "github.com/kubev2v/forklift/pkg/controller/provider/model/vsphere"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+var _ = Describe("VM custom attributes", func() {
+	fields := []types.CustomFieldDef{
+		{Key: 101, Name: "CostCenter"},
+		{Key: 102, Name: "Owner"},
+	}
+	values := []types.BaseCustomFieldValue{
+		&types.CustomFieldStringValue{CustomFieldValue: types.CustomFieldValue{Key: 101}, Value: "CC-1001"},
+	}
+
+	It("should resolve the values reported before the definitions", func() {
+		adapter := &VmAdapter{}
+		adapter.updateCustomValues(values)
+		adapter.updateCustomFields(fields)
+		Expect(adapter.model.CustomAttributes).To(Equal([]modelVsphere.CustomAttribute{
+			{Key: 101, Name: "CostCenter", Value: "CC-1001"},
+			{Key: 102, Name: "Owner"},
+		}))
+	})
+
+	It("should keep the definitions when the values are updated", func() {
+		adapter := &VmAdapter{}
+		adapter.updateCustomFields(fields)
+		adapter.updateCustomValues(values)
+		adapter.updateCustomValues([]types.BaseCustomFieldValue{
+			&types.CustomFieldStringValue{CustomFieldValue: types.CustomFieldValue{Key: 102}, Value: "jane"},
+		})
+		Expect(adapter.model.CustomAttributes).To(Equal([]modelVsphere.CustomAttribute{
+			{Key: 101, Name: "CostCenter"},
+			{Key: 102, Name: "Owner", Value: "jane"},
+		}))
+	})
+})
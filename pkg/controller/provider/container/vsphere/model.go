@@ -2,7 +2,9 @@ package vsphere
 
 import (
 	"fmt"
+	"net"
 	"net/url"
+	"path"
 	"sort"
 	"strconv"
 	"strings"
@@ -225,11 +227,64 @@ func (v *ClusterAdapter) Apply(u types.ObjectUpdate) {
 				if b, cast := p.Val.(types.DrsBehavior); cast {
 					v.model.DrsBehavior = string(b)
 				}
+			case fSummary:
+				if s, cast := p.Val.(types.ClusterComputeResourceSummary); cast {
+					v.model.EvcMode = s.CurrentEVCModeKey
+				}
+			case fConfigEx:
+				if c, cast := p.Val.(types.ClusterConfigInfoEx); cast {
+					v.model.Rules = v.rules(&c)
+				}
 			}
 		}
 	}
 }
 
+// VM rules of the cluster configuration.
+// The VMs of the VM-host and dependency rules are
+// the VMs of the rule VM groups.
+func (v *ClusterAdapter) rules(config *types.ClusterConfigInfoEx) (rules []model.ClusterRule) {
+	groups := map[string][]types.ManagedObjectReference{}
+	for _, group := range config.Group {
+		if vmGroup, cast := group.(*types.ClusterVmGroup); cast {
+			groups[vmGroup.Name] = vmGroup.Vm
+		}
+	}
+	rules = []model.ClusterRule{}
+	for _, rule := range config.Rule {
+		info := rule.GetClusterRuleInfo()
+		m := model.ClusterRule{
+			Name:      info.Name,
+			Enabled:   info.Enabled != nil && *info.Enabled,
+			Mandatory: info.Mandatory != nil && *info.Mandatory,
+		}
+		var vms []types.ManagedObjectReference
+		switch r := rule.(type) {
+		case *types.ClusterAffinityRuleSpec:
+			m.Kind = model.RuleAffinity
+			vms = r.Vm
+		case *types.ClusterAntiAffinityRuleSpec:
+			m.Kind = model.RuleAntiAffinity
+			vms = r.Vm
+		case *types.ClusterVmHostRuleInfo:
+			m.Kind = model.RuleVmHost
+			vms = groups[r.VmGroupName]
+		case *types.ClusterDependencyRuleInfo:
+			m.Kind = model.RuleDependency
+			vms = append(vms, groups[r.VmGroup]...)
+			vms = append(vms, groups[r.DependsOnVmGroup]...)
+		default:
+			continue
+		}
+		m.VMs = []model.Ref{}
+		for _, vm := range vms {
+			m.VMs = append(m.VMs, v.Ref(vm))
+		}
+		rules = append(rules, m)
+	}
+	return
+}
+
 // Host model adapter.
 type HostAdapter struct {
 	Base
@@ -354,15 +409,29 @@ func (v *HostAdapter) Apply(u types.ObjectUpdate) {
 							}
 							return
 						}
+						ipV6 := func() (list []string) {
+							config := nic.Spec.Ip.IpV6Config
+							if config == nil {
+								return
+							}
+							for _, address := range config.IpV6Address {
+								ip := net.ParseIP(address.IpAddress)
+								if ip != nil && !ip.IsLinkLocalUnicast() {
+									list = append(list, address.IpAddress)
+								}
+							}
+							return
+						}
 						network.VNICs = append(
 							network.VNICs,
 							model.VNIC{
-								Key:        nic.Key,
-								PortGroup:  nic.Portgroup,
-								DPortGroup: dGroup(),
-								IpAddress:  nic.Spec.Ip.IpAddress,
-								SubnetMask: nic.Spec.Ip.SubnetMask,
-								MTU:        nic.Spec.Mtu,
+								Key:           nic.Key,
+								PortGroup:     nic.Portgroup,
+								DPortGroup:    dGroup(),
+								IpAddress:     nic.Spec.Ip.IpAddress,
+								IpV6Addresses: ipV6(),
+								SubnetMask:    nic.Spec.Ip.SubnetMask,
+								MTU:           nic.Spec.Mtu,
 							})
 					}
 					sort.Slice(
@@ -678,6 +747,7 @@ func (v *VmAdapter) Apply(u types.ObjectUpdate) {
 					if a.EfiSecureBootEnabled != nil {
 						v.model.SecureBoot = *a.EfiSecureBootEnabled
 					}
+					v.model.BootOrder = bootOrder(a.BootOrder)
 				}
 			case fCpuHotAddEnabled:
 				if b, cast := p.Val.(bool); cast {
@@ -703,6 +773,14 @@ func (v *VmAdapter) Apply(u types.ObjectUpdate) {
 				if n, cast := p.Val.(int32); cast {
 					v.model.MemoryMB = n
 				}
+			case fMemoryAllocation:
+				if a, cast := p.Val.(types.ResourceAllocationInfo); cast {
+					v.model.MemoryReservationMB, v.model.MemoryLimitMB = memoryAllocation(a)
+				}
+			case fMemoryReservationLocked:
+				if b, cast := p.Val.(bool); cast {
+					v.model.MemoryReservationLocked = b
+				}
 			case fStorageUsed:
 				if n, cast := p.Val.(int64); cast {
 					v.model.StorageUsed = n
@@ -743,6 +821,22 @@ func (v *VmAdapter) Apply(u types.ObjectUpdate) {
 				if s, cast := p.Val.(string); cast {
 					v.model.IpAddress = s
 				}
+			case fLatencySensitivity:
+				if l, cast := p.Val.(types.LatencySensitivity); cast {
+					v.model.LatencySensitivity = string(l.Level)
+				}
+			case fAnnotation:
+				if s, cast := p.Val.(string); cast {
+					v.model.Notes = s
+				}
+			case fAvailableField:
+				if fields, cast := p.Val.(types.ArrayOfCustomFieldDef); cast {
+					v.updateCustomFields(fields.CustomFieldDef)
+				}
+			case fCustomValue:
+				if values, cast := p.Val.(types.ArrayOfCustomFieldValue); cast {
+					v.updateCustomValues(values.CustomFieldValue)
+				}
 			case fFtInfo:
 				if _, cast := p.Val.(types.FaultToleranceConfigInfo); cast {
 					v.model.FaultToleranceEnabled = true
@@ -751,10 +845,13 @@ func (v *VmAdapter) Apply(u types.ObjectUpdate) {
 				v.model.Networks = v.RefList(p.Val)
 			case fExtraConfig:
 				if options, cast := p.Val.(types.ArrayOfOptionValue); cast {
+					v.model.GuestInfo = model.GuestInfo{}
 					for _, val := range options.OptionValue {
 						opt := val.GetOptionValue()
 
-						if opt.Key == "numa.nodeAffinity" {
+						if strings.HasPrefix(opt.Key, "guestinfo.") {
+							v.guestInfo(opt.Key, opt.Value)
+						} else if opt.Key == "numa.nodeAffinity" {
 							if s, cast := opt.Value.(string); cast {
 								v.model.NumaNodeAffinity = strings.Split(s, ",")
 							}
@@ -766,6 +863,14 @@ func (v *VmAdapter) Apply(u types.ObjectUpdate) {
 								}
 								v.model.ChangeTrackingEnabled = boolVal
 							}
+						} else if opt.Key == "sched.mem.lpage.enable1GPage" {
+							if s, cast := opt.Value.(string); cast {
+								boolVal, err := strconv.ParseBool(s)
+								if err != nil {
+									return
+								}
+								v.model.HugePages1G = boolVal
+							}
 						} else if opt.Key == "disk.EnableUUID" {
 							if s, cast := opt.Value.(string); cast {
 								boolVal, err := strconv.ParseBool(s)
@@ -858,13 +963,25 @@ func (v *VmAdapter) Apply(u types.ObjectUpdate) {
 				if devArray, cast := p.Val.(types.ArrayOfVirtualDevice); cast {
 					devList := []model.Device{}
 					nicList := []model.NIC{}
+					cdromList := []model.CDROM{}
 					nicsIndex := 0
 					for _, dev := range devArray.VirtualDevice {
 						var nic *types.VirtualEthernetCard
+						sriov := false
 						switch device := dev.(type) {
-						case *types.VirtualSriovEthernetCard,
-							*types.VirtualPCIPassthrough,
-							*types.VirtualSCSIPassthrough,
+						case *types.VirtualPCIPassthrough:
+							devList = append(
+								devList,
+								model.Device{
+									Kind:    libref.ToKind(dev),
+									Profile: v.passthroughProfile(device),
+								})
+						case *types.VirtualSriovEthernetCard:
+							nic = &device.VirtualEthernetCard
+							sriov = true
+						case *types.VirtualCdrom:
+							cdromList = append(cdromList, v.cdrom(device))
+						case *types.VirtualSCSIPassthrough,
 							*types.VirtualUSBController:
 							devList = append(
 								devList,
@@ -905,8 +1022,10 @@ func (v *VmAdapter) Apply(u types.ObjectUpdate) {
 							nicList = append(
 								nicList,
 								model.NIC{
+									Key:   nic.Key,
 									MAC:   strings.ToLower(nic.MacAddress),
 									Index: nicsIndex,
+									SRIOV: sriov,
 									Network: model.Ref{
 										Kind: model.NetKind,
 										ID:   network,
@@ -917,6 +1036,7 @@ func (v *VmAdapter) Apply(u types.ObjectUpdate) {
 					}
 					v.model.Devices = devList
 					v.model.NICs = nicList
+					v.model.CDROMs = cdromList
 					v.updateControllers(&devArray)
 					v.updateDisks(&devArray)
 
@@ -958,6 +1078,72 @@ func isCBTEnabledForDisks(ctkPerDisk map[string]bool, disks []model.Disk) {
 	}
 }
 
+// Build the CD-ROM.
+// The ISO file is reported only for datastore ISO media.
+func (v *VmAdapter) cdrom(device *types.VirtualCdrom) (cdrom model.CDROM) {
+	cdrom.Key = device.Key
+	if connectable := device.Connectable; connectable != nil {
+		cdrom.Connected = connectable.Connected || connectable.StartConnected
+	}
+	if backing, cast := device.Backing.(*types.VirtualCdromIsoBackingInfo); cast {
+		cdrom.ISO = backing.FileName
+		if backing.Datastore != nil {
+			cdrom.Datastore = model.Ref{
+				Kind: model.DsKind,
+				ID:   backing.Datastore.Value,
+			}
+		}
+	}
+	return
+}
+
+// Update the custom attributes defined for the VM.
+// The values of the attributes still defined are kept.
+func (v *VmAdapter) updateCustomFields(fields []types.CustomFieldDef) {
+	values := map[int32]string{}
+	for _, attribute := range v.model.CustomAttributes {
+		values[attribute.Key] = attribute.Value
+	}
+	attributes := []model.CustomAttribute{}
+	for _, field := range fields {
+		attributes = append(
+			attributes,
+			model.CustomAttribute{
+				Key:   field.Key,
+				Name:  field.Name,
+				Value: values[field.Key],
+			})
+	}
+	v.model.CustomAttributes = attributes
+}
+
+// Update the custom attribute values.
+// The definitions may be reported by a later update.
+func (v *VmAdapter) updateCustomValues(values []types.BaseCustomFieldValue) {
+	set := map[int32]string{}
+	for _, value := range values {
+		if s, cast := value.(*types.CustomFieldStringValue); cast {
+			set[s.Key] = s.Value
+		}
+	}
+	for i := range v.model.CustomAttributes {
+		attribute := &v.model.CustomAttributes[i]
+		attribute.Value = set[attribute.Key]
+		delete(set, attribute.Key)
+	}
+	for key, value := range set {
+		v.model.CustomAttributes = append(
+			v.model.CustomAttributes,
+			model.CustomAttribute{
+				Key:   key,
+				Value: value,
+			})
+	}
+	sort.Slice(v.model.CustomAttributes, func(i, j int) bool {
+		return v.model.CustomAttributes[i].Key < v.model.CustomAttributes[j].Key
+	})
+}
+
 // Update virtual disk devices.
 func (v *VmAdapter) updateControllers(devArray *types.ArrayOfVirtualDevice) {
 	controllers := []model.Controller{}
@@ -1027,6 +1213,23 @@ func (v *VmAdapter) getDiskController(key int32) *model.Controller {
 	return nil
 }
 
+// Profile of the PCI passthrough device.
+// The vGPU profile or the PCI vendor and device IDs (vendor:device).
+func (v *VmAdapter) passthroughProfile(device *types.VirtualPCIPassthrough) (profile string) {
+	switch backing := device.Backing.(type) {
+	case *types.VirtualPCIPassthroughVmiopBackingInfo:
+		profile = backing.Vgpu
+	case *types.VirtualPCIPassthroughDeviceBackingInfo:
+		profile = fmt.Sprintf("%04x:%04s", uint16(backing.VendorId), strings.ToLower(backing.DeviceId))
+	case *types.VirtualPCIPassthroughDynamicBackingInfo:
+		if len(backing.AllowedDevice) > 0 {
+			allowed := backing.AllowedDevice[0]
+			profile = fmt.Sprintf("%04x:%04x", uint16(allowed.VendorId), uint16(allowed.DeviceId))
+		}
+	}
+	return
+}
+
 // Update virtual disk devices.
 func (v *VmAdapter) updateDisks(devArray *types.ArrayOfVirtualDevice) {
 	disks := []model.Disk{}
@@ -1084,6 +1287,8 @@ func (v *VmAdapter) updateDisks(devArray *types.ArrayOfVirtualDevice) {
 					Shared:        backing.Sharing != "sharingNone" && backing.Sharing != "",
 					Mode:          backing.DiskMode,
 					RDM:           true,
+					RDMMode:       rdmMode(backing.CompatibilityMode),
+					WWN:           lunWWN(backing.DeviceName, backing.LunUuid),
 					Bus:           controller.Bus,
 					Serial:        backing.Uuid,
 				}
@@ -1104,6 +1309,7 @@ func (v *VmAdapter) updateDisks(devArray *types.ArrayOfVirtualDevice) {
 					Capacity:      disk.CapacityInBytes,
 					Shared:        backing.Sharing != "sharingNone" && backing.Sharing != "",
 					RDM:           true,
+					WWN:           lunWWN(backing.DeviceName, ""),
 					Bus:           controller.Bus,
 				}
 				disks = append(disks, md)
@@ -1113,3 +1319,80 @@ func (v *VmAdapter) updateDisks(devArray *types.ArrayOfVirtualDevice) {
 
 	v.model.Disks = disks
 }
+
+// Memory reservation and limit (MB).
+// The limit is 0 when unlimited (-1).
+func memoryAllocation(allocation types.ResourceAllocationInfo) (reservation, limit int64) {
+	if allocation.Reservation != nil {
+		reservation = *allocation.Reservation
+	}
+	if allocation.Limit != nil && *allocation.Limit > 0 {
+		limit = *allocation.Limit
+	}
+	return
+}
+
+// RDM compatibility mode.
+func rdmMode(compatibilityMode string) (mode string) {
+	switch compatibilityMode {
+	case string(types.VirtualDiskCompatibilityModePhysicalMode):
+		mode = model.RDMPhysical
+	case string(types.VirtualDiskCompatibilityModeVirtualMode):
+		mode = model.RDMVirtual
+	}
+	return
+}
+
+// WWN of an RDM LUN.
+// Found in the (NAA or EUI) device name, e.g. /vmfs/devices/disks/naa.600a0b80...
+// and otherwise in the LUN UUID, which embeds the NAA identifier
+// after the 10 digit prefix, e.g. 0200000000600a0b80...
+func lunWWN(deviceName, lunUUID string) (wwn string) {
+	name := path.Base(deviceName)
+	for _, prefix := range []string{"naa.", "eui."} {
+		if strings.HasPrefix(name, prefix) {
+			wwn = strings.ToLower(strings.TrimPrefix(name, prefix))
+			return
+		}
+	}
+	if len(lunUUID) >= 42 {
+		wwn = strings.ToLower(lunUUID[10:42])
+	}
+	return
+}
+
+// Boot order of the bootable devices.
+func bootOrder(devices []types.BaseVirtualMachineBootOptionsBootableDevice) (order []model.BootDevice) {
+	order = []model.BootDevice{}
+	for _, device := range devices {
+		switch d := device.(type) {
+		case *types.VirtualMachineBootOptionsBootableDiskDevice:
+			order = append(order, model.BootDevice{Kind: model.BootDisk, Key: d.DeviceKey})
+		case *types.VirtualMachineBootOptionsBootableEthernetDevice:
+			order = append(order, model.BootDevice{Kind: model.BootEthernet, Key: d.DeviceKey})
+		case *types.VirtualMachineBootOptionsBootableCdromDevice:
+			order = append(order, model.BootDevice{Kind: model.BootCdrom})
+		case *types.VirtualMachineBootOptionsBootableFloppyDevice:
+			order = append(order, model.BootDevice{Kind: model.BootFloppy})
+		}
+	}
+	return
+}
+
+// Update the cloud-init guestinfo variable.
+func (v *VmAdapter) guestInfo(key string, value interface{}) {
+	s, cast := value.(string)
+	if !cast {
+		return
+	}
+	switch key {
+	case "guestinfo.userdata":
+		v.model.GuestInfo.UserData = s
+	case "guestinfo.userdata.encoding":
+		v.model.GuestInfo.UserDataEncoding = s
+	case "guestinfo.metadata":
+		v.model.GuestInfo.MetaData = s
+	case "guestinfo.metadata.encoding":
+		v.model.GuestInfo.MetaDataEncoding = s
+	}
+}
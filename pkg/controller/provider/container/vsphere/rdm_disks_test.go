@@ -0,0 +1,27 @@
+package vsphere
+
+import (
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/vsphere"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RDM disks", func() {
+	DescribeTable("should find the LUN WWN", func(deviceName, lunUUID, wwn string) {
+		Expect(lunWWN(deviceName, lunUUID)).To(Equal(wwn))
+	},
+		Entry("naa device", "/vmfs/devices/disks/naa.600A0B800029E6F6000011A354E2B0A4", "", "600a0b800029e6f6000011a354e2b0a4"),
+		Entry("eui device", "/vmfs/devices/disks/eui.0025385b71b16a11", "", "0025385b71b16a11"),
+		Entry("lun uuid", "vml.0200000000600a0b8", "0200000000600a0b800029e6f6000011a354e2b0a4524149442035", "600a0b800029e6f6000011a354e2b0a4"),
+		Entry("unknown", "/vmfs/devices/disks/t10.ATA_disk", "", ""),
+	)
+
+	DescribeTable("should find the compatibility mode", func(compatibilityMode, mode string) {
+		Expect(rdmMode(compatibilityMode)).To(Equal(mode))
+	},
+		Entry("physical", "physicalMode", model.RDMPhysical),
+		Entry("virtual", "virtualMode", model.RDMVirtual),
+		Entry("unknown", "", ""),
+	)
+})
@@ -0,0 +1,116 @@
+package vsphere
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/settings"
+	"github.com/vmware/govmomi/vim25/soap"
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Calls delayed less than the threshold are not reported as throttled.
+const throttleThreshold = 5 * time.Millisecond
+
+// Rate limiters of the vSphere API calls by provider UID.
+var apiLimiters = &providerLimiters{
+	content: map[types.UID]*rate.Limiter{},
+	share:   roleShare,
+}
+
+// Provider rate limiters.
+type providerLimiters struct {
+	mutex   sync.Mutex
+	content map[types.UID]*rate.Limiter
+	// The share of the provider budget used by the process.
+	share func() float64
+}
+
+// Get the limiter of the provider.
+// The limit of the existing limiter is updated to the
+// provider settings. Nil when the provider is not limited.
+func (r *providerLimiters) get(provider *api.Provider) (limiter *rate.Limiter) {
+	qps, burst, err := provider.APIRateLimit()
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if err != nil || qps == 0 {
+		delete(r.content, provider.UID)
+		return
+	}
+	share := r.share()
+	limit := rate.Limit(qps * share)
+	burst = max(int(math.Ceil(float64(burst)*share)), 1)
+	limiter, found := r.content[provider.UID]
+	if !found {
+		limiter = rate.NewLimiter(limit, burst)
+		r.content[provider.UID] = limiter
+		return
+	}
+	if limiter.Limit() != limit {
+		limiter.SetLimit(limit)
+	}
+	if limiter.Burst() != burst {
+		limiter.SetBurst(burst)
+	}
+	return
+}
+
+// The share of the provider budget used by the process.
+// The inventory collector (inventory role) and the plan-time
+// lookups (main role) are deployed as separate processes and
+// each is given half of the budget. The process running both
+// roles is given the whole budget.
+func roleShare() (share float64) {
+	for _, role := range []string{settings.InventoryRole, settings.MainRole} {
+		if Settings.Role.Has(role) {
+			share += 0.5
+		}
+	}
+	if share == 0 {
+		share = 1
+	}
+	return
+}
+
+// Throttled vSphere SDK round tripper.
+// Each SDK call waits for the rate limit set by the `apiQPS` and
+// `apiBurst` provider settings. The limiter is shared by the SDK
+// clients of the provider within the process. See: roleShare().
+type ThrottledRoundTripper struct {
+	soap.RoundTripper
+	// The provider.
+	Provider *api.Provider
+	// Optional. Called with the delay of the throttled calls.
+	Throttled func(provider *api.Provider, wait time.Duration)
+}
+
+// Round trip after the provider rate limit permits the call.
+func (r *ThrottledRoundTripper) RoundTrip(ctx context.Context, req, res soap.HasFault) (err error) {
+	err = r.wait(ctx)
+	if err != nil {
+		return
+	}
+	err = r.RoundTripper.RoundTrip(ctx, req, res)
+	return
+}
+
+// Wait until the call is permitted by the provider rate limit.
+func (r *ThrottledRoundTripper) wait(ctx context.Context) (err error) {
+	limiter := apiLimiters.get(r.Provider)
+	if limiter == nil {
+		return
+	}
+	mark := time.Now()
+	err = limiter.Wait(ctx)
+	if err != nil {
+		return
+	}
+	if wait := time.Since(mark); wait > throttleThreshold && r.Throttled != nil {
+		r.Throttled(r.Provider, wait)
+	}
+	return
+}
@@ -0,0 +1,79 @@
+package vsphere
+
+import (
+	"context"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/settings"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/time/rate"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("API rate limit", func() {
+	limiters := func(share float64) *providerLimiters {
+		return &providerLimiters{
+			content: map[types.UID]*rate.Limiter{},
+			share:   func() float64 { return share },
+		}
+	}
+
+	It("should limit the provider", func() {
+		limiters := limiters(1)
+		provider := &api.Provider{ObjectMeta: meta.ObjectMeta{UID: "provider"}}
+		Expect(limiters.get(provider)).To(BeNil())
+		provider.Spec.Settings = map[string]string{api.APIQPS: "2.5"}
+		limiter := limiters.get(provider)
+		Expect(limiter).ToNot(BeNil())
+		Expect(limiter.Limit()).To(Equal(rate.Limit(2.5)))
+		Expect(limiter.Burst()).To(Equal(3))
+		provider.Spec.Settings[api.APIBurst] = "10"
+		Expect(limiters.get(provider)).To(BeIdenticalTo(limiter))
+		Expect(limiter.Burst()).To(Equal(10))
+		provider.Spec.Settings[api.APIQPS] = "-1"
+		Expect(limiters.get(provider)).To(BeNil())
+	})
+
+	It("should split the budget between the roles", func() {
+		limiters := limiters(0.5)
+		provider := &api.Provider{ObjectMeta: meta.ObjectMeta{UID: "provider"}}
+		provider.Spec.Settings = map[string]string{api.APIQPS: "10", api.APIBurst: "5"}
+		limiter := limiters.get(provider)
+		Expect(limiter.Limit()).To(Equal(rate.Limit(5)))
+		Expect(limiter.Burst()).To(Equal(3))
+		provider.Spec.Settings[api.APIBurst] = "1"
+		Expect(limiters.get(provider).Burst()).To(Equal(1))
+	})
+
+	It("should give each role half of the budget", func() {
+		roles := Settings.Role.Roles
+		defer func() {
+			Settings.Role.Roles = roles
+		}()
+		Settings.Role.Roles = map[string]bool{settings.InventoryRole: true}
+		Expect(roleShare()).To(Equal(0.5))
+		Settings.Role.Roles = map[string]bool{settings.MainRole: true}
+		Expect(roleShare()).To(Equal(0.5))
+		Settings.Role.Roles = map[string]bool{settings.InventoryRole: true, settings.MainRole: true}
+		Expect(roleShare()).To(Equal(1.0))
+	})
+
+	It("should report the throttled calls", func() {
+		provider := &api.Provider{ObjectMeta: meta.ObjectMeta{UID: "throttled"}}
+		provider.Spec.Settings = map[string]string{api.APIQPS: "20", api.APIBurst: "1"}
+		throttled := 0
+		rt := &ThrottledRoundTripper{
+			Provider: provider,
+			Throttled: func(_ *api.Provider, wait time.Duration) {
+				throttled++
+			},
+		}
+		for i := 0; i < 2; i++ {
+			Expect(rt.wait(context.TODO())).To(Succeed())
+		}
+		Expect(throttled).To(Equal(1))
+	})
+})
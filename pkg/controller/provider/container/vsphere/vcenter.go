@@ -0,0 +1,219 @@
+package vsphere
+
+import (
+	"context"
+	liburl "net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	"github.com/vmware/govmomi/lookup"
+	lookuptypes "github.com/vmware/govmomi/lookup/types"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// Lookup service (linked mode) registration filter.
+// Matches the vCenter SDK (vim) endpoints.
+var vCenterFilter = &lookuptypes.LookupServiceRegistrationFilter{
+	ServiceType: &lookuptypes.LookupServiceRegistrationServiceType{
+		Product: "com.vmware.cis",
+		Type:    "vcenterserver",
+	},
+	EndpointType: &lookuptypes.LookupServiceRegistrationEndpointType{
+		Protocol: "vmomi",
+		Type:     "com.vmware.vim",
+	},
+}
+
+// The reflected managed object reference type.
+var moRefType = reflect.TypeOf(types.ManagedObjectReference{})
+
+// The vCenter key for the SDK URL.
+// The host with the default (https) port omitted.
+func VCenterKey(url string) (key string) {
+	u, err := liburl.Parse(url)
+	if err != nil {
+		return
+	}
+	key = strings.TrimSuffix(u.Host, ":443")
+	return
+}
+
+// The object ID prefixed with the vCenter key.
+func VCenterID(vcenter, id string) string {
+	if vcenter == "" || strings.HasPrefix(id, vcenter+":") {
+		return id
+	}
+	return vcenter + ":" + id
+}
+
+// Start the collectors for the additional vCenters.
+// The vCenters listed by the provider `vcenters` setting and
+// the vCenters (discovered) in the linked mode group.
+// Retried until successful or canceled.
+func (r *Collector) startVCenters(ctx context.Context) {
+	for {
+		urls, err := r.vCenterURLs(ctx)
+		if err == nil {
+			r.mutex.Lock()
+			for _, url := range urls {
+				collector := New(r.db, r.provider, r.secret)
+				collector.url = url
+				collector.vcenter = VCenterKey(url)
+				collector.log = r.log.WithValues("vcenter", collector.vcenter)
+				_ = collector.Start()
+				r.vcenters = append(r.vcenters, collector)
+			}
+			r.discovered = true
+			r.mutex.Unlock()
+			r.log.Info(
+				"Additional vCenters started.",
+				"vcenters",
+				urls)
+			return
+		}
+		r.log.Error(
+			err,
+			"vCenter discovery failed.",
+			"retry",
+			RetryDelay)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(RetryDelay):
+		}
+	}
+}
+
+// The URLs of the additional vCenters.
+func (r *Collector) vCenterURLs(ctx context.Context) (urls []string, err error) {
+	urls, err = r.provider.VCenterURLs()
+	if err != nil || !r.provider.LinkedMode() {
+		return
+	}
+	known := map[string]bool{VCenterKey(r.url): true}
+	for _, url := range urls {
+		known[VCenterKey(url)] = true
+	}
+	client, err := r.buildClient(ctx)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = client.Logout(context.TODO())
+		client.CloseIdleConnections()
+	}()
+	lookupClient, err := lookup.NewClient(ctx, client.Client)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	infoList, err := lookupClient.List(ctx, vCenterFilter)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for _, info := range infoList {
+		for _, endpoint := range info.ServiceEndpoints {
+			key := VCenterKey(endpoint.Url)
+			if key == "" || known[key] {
+				continue
+			}
+			known[key] = true
+			urls = append(urls, endpoint.Url)
+		}
+	}
+	return
+}
+
+// Namespace the object updates reported by an additional vCenter.
+// The managed object references and the (DV) portgroup keys are
+// prefixed with the vCenter key so the IDs are unique within
+// the inventory.
+func (r *Collector) namespace(updates []types.ObjectUpdate) {
+	if r.vcenter == "" {
+		return
+	}
+	for i := range updates {
+		u := &updates[i]
+		r.prefix(reflect.ValueOf(u).Elem())
+		if u.Obj.Type != DVPortGroup {
+			continue
+		}
+		for j := range u.ChangeSet {
+			p := &u.ChangeSet[j]
+			if s, cast := p.Val.(string); cast && p.Name == fKey {
+				p.Val = VCenterID(r.vcenter, s)
+			}
+		}
+	}
+}
+
+// Prefix the managed object references (and portgroup keys)
+// found within the (addressable) value.
+func (r *Collector) prefix(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			r.prefix(v.Elem())
+		}
+	case reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		e := v.Elem()
+		if e.Kind() == reflect.Ptr {
+			r.prefix(e)
+			return
+		}
+		if !v.CanSet() {
+			return
+		}
+		copied := reflect.New(e.Type()).Elem()
+		copied.Set(e)
+		r.prefix(copied)
+		v.Set(copied)
+	case reflect.Struct:
+		if v.Type() == moRefType {
+			value := v.FieldByName("Value")
+			value.SetString(VCenterID(r.vcenter, value.String()))
+			return
+		}
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			f := v.Field(i)
+			if field.Name == "PortgroupKey" && f.Kind() == reflect.String {
+				f.SetString(VCenterID(r.vcenter, f.String()))
+				continue
+			}
+			r.prefix(f)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			r.prefix(v.Index(i))
+		}
+	}
+}
+
+// The collector for the vCenter of the (prefixed) object ID
+// and the unprefixed ID. The provider vCenter when not found.
+func (r *Collector) vCenterCollector(id string) (collector *Collector, unprefixed string) {
+	collector = r
+	unprefixed = id
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, other := range r.vcenters {
+		prefix := other.vcenter + ":"
+		if strings.HasPrefix(id, prefix) {
+			collector = other
+			unprefixed = strings.TrimPrefix(id, prefix)
+			break
+		}
+	}
+	return
+}
@@ -0,0 +1,88 @@
+package vsphere
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+var _ = Describe("vCenter namespace", func() {
+	It("should build the vCenter key", func() {
+		Expect(VCenterKey("https://vc2.example.com/sdk")).To(Equal("vc2.example.com"))
+		Expect(VCenterKey("https://vc2.example.com:443/sdk")).To(Equal("vc2.example.com"))
+		Expect(VCenterKey("https://vc2.example.com:8443/sdk")).To(Equal("vc2.example.com:8443"))
+	})
+
+	It("should not namespace the provider vCenter", func() {
+		collector := &Collector{}
+		updates := []types.ObjectUpdate{
+			{Obj: types.ManagedObjectReference{Type: VirtualMachine, Value: "vm-1"}},
+		}
+		collector.namespace(updates)
+		Expect(updates[0].Obj.Value).To(Equal("vm-1"))
+	})
+
+	It("should prefix the references of an additional vCenter", func() {
+		collector := &Collector{vcenter: "vc2.example.com"}
+		host := types.ManagedObjectReference{Type: Host, Value: "host-9"}
+		nic := &types.VirtualVmxnet3{}
+		nic.Backing = &types.VirtualEthernetCardDistributedVirtualPortBackingInfo{
+			Port: types.DistributedVirtualSwitchPortConnection{PortgroupKey: "dvportgroup-3"},
+		}
+		updates := []types.ObjectUpdate{
+			{
+				Obj: types.ManagedObjectReference{Type: VirtualMachine, Value: "vm-1"},
+				ChangeSet: []types.PropertyChange{
+					{Name: fName, Val: "vm-1"},
+					{Name: fRuntimeHost, Val: host},
+					{
+						Name: fDatastore,
+						Val: types.ArrayOfManagedObjectReference{
+							ManagedObjectReference: []types.ManagedObjectReference{
+								{Type: Datastore, Value: "datastore-7"},
+							},
+						},
+					},
+					{
+						Name: fDevices,
+						Val: types.ArrayOfVirtualDevice{
+							VirtualDevice: []types.BaseVirtualDevice{nic},
+						},
+					},
+				},
+			},
+			{
+				Obj: types.ManagedObjectReference{Type: DVPortGroup, Value: "dvportgroup-3"},
+				ChangeSet: []types.PropertyChange{
+					{Name: fKey, Val: "dvportgroup-3"},
+				},
+			},
+		}
+		collector.namespace(updates)
+		changes := updates[0].ChangeSet
+		Expect(updates[0].Obj.Value).To(Equal("vc2.example.com:vm-1"))
+		Expect(changes[0].Val).To(Equal("vm-1"))
+		Expect(changes[1].Val.(types.ManagedObjectReference).Value).To(Equal("vc2.example.com:host-9"))
+		Expect(host.Value).To(Equal("host-9"))
+		datastores := changes[2].Val.(types.ArrayOfManagedObjectReference).ManagedObjectReference
+		Expect(datastores[0].Value).To(Equal("vc2.example.com:datastore-7"))
+		backing := nic.Backing.(*types.VirtualEthernetCardDistributedVirtualPortBackingInfo)
+		Expect(backing.Port.PortgroupKey).To(Equal("vc2.example.com:dvportgroup-3"))
+		Expect(updates[1].Obj.Value).To(Equal("vc2.example.com:dvportgroup-3"))
+		Expect(updates[1].ChangeSet[0].Val).To(Equal("vc2.example.com:dvportgroup-3"))
+		// Idempotent.
+		collector.namespace(updates)
+		Expect(updates[0].Obj.Value).To(Equal("vc2.example.com:vm-1"))
+	})
+
+	It("should route the prefixed references", func() {
+		other := &Collector{vcenter: "vc2.example.com"}
+		collector := &Collector{vcenters: []*Collector{other}}
+		found, id := collector.vCenterCollector("vc2.example.com:host-9")
+		Expect(found).To(BeIdenticalTo(other))
+		Expect(id).To(Equal("host-9"))
+		found, id = collector.vCenterCollector("host-9")
+		Expect(found).To(BeIdenticalTo(collector))
+		Expect(id).To(Equal("host-9"))
+	})
+})
@@ -24,12 +24,19 @@ import (
 	"sync"
 	"time"
 
+	"github.com/gin-gonic/gin"
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
 	"github.com/kubev2v/forklift/pkg/controller/base"
+	"github.com/kubev2v/forklift/pkg/controller/plan"
 	"github.com/kubev2v/forklift/pkg/controller/provider/container"
+	"github.com/kubev2v/forklift/pkg/controller/provider/container/replica"
+	"github.com/kubev2v/forklift/pkg/controller/provider/health"
 	"github.com/kubev2v/forklift/pkg/controller/provider/model"
 	"github.com/kubev2v/forklift/pkg/controller/provider/web"
+	webbase "github.com/kubev2v/forklift/pkg/controller/provider/web/base"
 	"github.com/kubev2v/forklift/pkg/controller/validation/policy"
+	"github.com/kubev2v/forklift/pkg/lib/audit"
+	ocp "github.com/kubev2v/forklift/pkg/lib/client/openshift"
 	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
 	liberr "github.com/kubev2v/forklift/pkg/lib/error"
 	libfb "github.com/kubev2v/forklift/pkg/lib/filebacked"
@@ -38,15 +45,22 @@ import (
 	libweb "github.com/kubev2v/forklift/pkg/lib/inventory/web"
 	"github.com/kubev2v/forklift/pkg/lib/logging"
 	libref "github.com/kubev2v/forklift/pkg/lib/ref"
+	"github.com/kubev2v/forklift/pkg/lib/tracing"
+	metrics "github.com/kubev2v/forklift/pkg/monitoring/metrics/forklift-controller"
 	"github.com/kubev2v/forklift/pkg/settings"
+	"go.opentelemetry.io/otel/attribute"
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apiserver/pkg/storage/names"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	k8sutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -70,12 +84,61 @@ var Settings = &settings.Settings
 func Add(mgr manager.Manager) error {
 	libfb.WorkingDir = Settings.WorkingDir
 	container := libcontainer.New()
-	web := libweb.New(container, web.All(container)...)
+	handlers := web.All(container)
+	middleware := []gin.HandlerFunc{}
+	if Settings.Inventory.Audit.Path != "" {
+		auditLog := &audit.Log{
+			Path:       Settings.Inventory.Audit.Path,
+			MaxSize:    int64(Settings.Inventory.Audit.MaxSize) * 1024 * 1024,
+			MaxBackups: Settings.Inventory.Audit.MaxBackups,
+		}
+		handlers = append(handlers, &web.AuditHandler{Log: auditLog})
+		middleware = append(middleware, web.Audit(auditLog))
+	}
+	config := rest.CopyConfig(mgr.GetConfig())
+	config.Wrap(libweb.WrapRequestID)
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		log.Trace(err)
+		return err
+	}
+	handlers = append(
+		handlers,
+		&web.ReportHandler{Client: mgr.GetAPIReader()},
+		&web.PlanHandler{Client: mgr.GetClient()},
+		&web.TargetSpecHandler{
+			Handler: webbase.Handler{Container: container},
+			Client:  mgr.GetClient(),
+			Build:   plan.TargetSpec,
+		},
+		&web.SimulationHandler{
+			TargetSpecHandler: web.TargetSpecHandler{
+				Handler: webbase.Handler{Container: container},
+				Client:  mgr.GetClient(),
+			},
+			Records: mgr.GetAPIReader(),
+			Size:    plan.VMSizes,
+		},
+		&web.ConversionLogHandler{
+			Client:    mgr.GetAPIReader(),
+			Clientset: clientset,
+		},
+		&web.DiagnosticsHandler{
+			Client:    mgr.GetAPIReader(),
+			Clientset: clientset,
+			Namespace: Settings.Inventory.Namespace,
+		})
+	libweb.Metrics = &metrics.WatchMetrics{}
+	web := libweb.New(container, handlers...)
 	web.Port = Settings.Inventory.Port
+	web.Sunset = Settings.Inventory.V1Sunset
 	if Settings.Inventory.TLS.Key != "" {
 		web.TLS.Enabled = true
 		web.TLS.Certificate = Settings.Inventory.TLS.Certificate
 		web.TLS.Key = Settings.Inventory.TLS.Key
+		web.TLS.ClientCA = Settings.Inventory.TLS.ClientCA
+		web.TLS.MinVersion = Settings.Inventory.TLS.MinVersion
+		web.TLS.CipherSuites = Settings.Inventory.TLS.CipherSuites
 		web.AllowedOrigins = Settings.Inventory.CORS.AllowedOrigins
 	}
 	reconciler := &Reconciler{
@@ -83,13 +146,48 @@ func Add(mgr manager.Manager) error {
 			EventRecorder: mgr.GetEventRecorderFor(Name),
 			Client:        mgr.GetClient(),
 			Log:           log,
+			Interval:      Settings.Requeue.Provider,
 		},
 		catalog:   &Catalog{},
 		container: container,
 		web:       web,
 	}
 
-	web.Start()
+	web.Start(middleware...)
+
+	if Settings.Inventory.IsReplica() {
+		log.Info(
+			"Read replica.",
+			"writer",
+			Settings.Inventory.Replica.WriterURL)
+	}
+
+	checker := &HealthChecker{
+		Interval:  time.Duration(Settings.Inventory.Health.Interval) * time.Second,
+		Window:    time.Duration(Settings.Inventory.Health.ExpiryWarning) * 24 * time.Hour,
+		Providers: reconciler.providers,
+		Secret:    reconciler.getSecret,
+		Changed:   make(chan event.TypedGenericEvent[*api.Provider]),
+	}
+	if !Settings.Inventory.IsReplica() {
+		checker.Start()
+	}
+
+	if Settings.Inventory.Conveyor.Port > 0 && !Settings.Inventory.IsReplica() {
+		gateway := &ConveyorGateway{
+			Port:        Settings.Inventory.Conveyor.Port,
+			ProxyPort:   Settings.Inventory.Conveyor.ProxyPort,
+			Token:       Settings.Inventory.Conveyor.Token,
+			Certificate: Settings.Inventory.TLS.Certificate,
+			Key:         Settings.Inventory.TLS.Key,
+			MinVersion:  Settings.Inventory.TLS.MinVersion,
+		}
+		err = gateway.Start()
+		if err != nil {
+			log.Trace(err)
+			return err
+		}
+	}
 
 	policy.Agent.Start()
 
@@ -113,6 +211,15 @@ func Add(mgr manager.Manager) error {
 		log.Trace(err)
 		return err
 	}
+	// Health changed.
+	err = cnt.Watch(
+		source.Channel(
+			checker.Changed,
+			&handler.TypedEnqueueRequestForObject[*api.Provider]{}))
+	if err != nil {
+		log.Trace(err)
+		return err
+	}
 	// References.
 	err = cnt.Watch(
 		source.Kind(mgr.GetCache(), &v1.Secret{},
@@ -150,6 +257,10 @@ func (r Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (r
 			err)
 		err = nil
 	}()
+	_, span := tracing.Start(ctx, "provider.reconcile", attribute.String("provider", request.String()))
+	defer func() {
+		tracing.End(span, err)
+	}()
 
 	// Fetch the CR.
 	provider := &api.Provider{}
@@ -159,6 +270,8 @@ func (r Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (r
 			r.Log.Info("Provider deleted.")
 			err = nil
 			if deleted, found := r.catalog.get(request); found {
+				r.catalog.delete(request, deleted)
+				health.Providers.Delete(deleted.UID)
 				if r, found := r.container.Delete(deleted); found {
 					r.Shutdown()
 					_ = r.DB().Close(true)
@@ -192,6 +305,12 @@ func (r Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (r
 		}
 	}
 
+	// Read replica.
+	if Settings.Inventory.IsReplica() {
+		err = r.updateReplica(provider)
+		return
+	}
+
 	if provider.Type() == api.Ova && provider.DeletionTimestamp == nil {
 
 		deploymentName := fmt.Sprintf("%s-deployment-%s", ovaServer, provider.Name)
@@ -295,7 +414,7 @@ func (r Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (r
 	if !provider.Status.HasCondition(ConnectionTestSucceeded, InventoryCreated) {
 		r.Log.Info(
 			"Waiting connection tested or inventory created.")
-		result.RequeueAfter = base.SlowReQ
+		result.RequeueAfter = base.ReQ(Settings.Requeue.Provider)
 	}
 
 	// Done
@@ -329,7 +448,7 @@ func (r *Reconciler) updateProvider(provider *api.Provider) (err error) {
 // Update the container.
 func (r *Reconciler) updateContainer(provider *api.Provider) (err error) {
 	if _, found := r.container.Get(provider); found {
-		if provider.HasReconciled() {
+		if provider.HasReconciled() && !r.credentialsRotated(provider) {
 			r.Log.V(1).Info(
 				"Provider not reconciled, postponing.")
 			return
@@ -342,8 +461,7 @@ func (r *Reconciler) updateContainer(provider *api.Provider) (err error) {
 		return
 	}
 	log.Info("Update container.")
-	if current, found := r.container.Get(provider); found {
-		current.Shutdown()
+	if current, found := r.container.Delete(provider); found {
 		_ = current.DB().Close(true)
 		r.Log.V(2).Info(
 			"Shutdown found collector.")
@@ -363,6 +481,7 @@ func (r *Reconciler) updateContainer(provider *api.Provider) (err error) {
 	if err != nil {
 		return
 	}
+	r.catalog.setSecretVersion(provider, secret.ResourceVersion)
 
 	r.Log.V(2).Info(
 		"Data collector added/started.")
@@ -370,6 +489,61 @@ func (r *Reconciler) updateContainer(provider *api.Provider) (err error) {
 	return
 }
 
+// Update the container of the read replica.
+// The provider is validated and its status updated by the
+// writer. The inventory is synchronized from the writer except
+// for OpenShift providers which are not stored in a DB.
+func (r *Reconciler) updateReplica(provider *api.Provider) (err error) {
+	if _, found := r.container.Get(provider); found {
+		err = r.updateProvider(provider)
+		return
+	}
+	var collector libcontainer.Collector
+	if provider.Type() == api.OpenShift {
+		secret, gErr := r.getSecret(provider)
+		if gErr != nil {
+			err = gErr
+			return
+		}
+		collector = container.Build(nil, provider, secret)
+	} else {
+		dir := filepath.Join(
+			Settings.Inventory.WorkingDir,
+			provider.Namespace,
+			provider.Name)
+		_ = os.MkdirAll(dir, 0755)
+		collector = replica.New(
+			provider,
+			dir,
+			model.Models(provider)...)
+	}
+	err = r.container.Add(collector)
+	if err != nil {
+		return
+	}
+
+	r.Log.V(2).Info(
+		"Replica collector added/started.")
+
+	return
+}
+
+// Determine whether the credentials (secret) used by the
+// collector have been rotated. The collector is rebuilt with the
+// rotated credentials, without re-creating the provider.
+func (r *Reconciler) credentialsRotated(provider *api.Provider) (rotated bool) {
+	secret, err := r.getSecret(provider)
+	if err != nil {
+		return
+	}
+	version, found := r.catalog.secretVersion(provider)
+	rotated = found && version != secret.ResourceVersion
+	if rotated {
+		r.Log.Info("Provider credentials rotated.")
+	}
+	return
+}
+
 // Build DB for provider.
 func (r *Reconciler) getDB(provider *api.Provider) (db libmodel.DB) {
 	dir := Settings.Inventory.WorkingDir
@@ -389,12 +563,25 @@ func (r *Reconciler) getDB(provider *api.Provider) (db libmodel.DB) {
 	return
 }
 
+// List the providers in the container.
+func (r *Reconciler) providers() (list []*api.Provider) {
+	for _, collector := range r.container.List() {
+		if p, cast := collector.Owner().(*api.Provider); cast {
+			list = append(list, p)
+		}
+	}
+	return
+}
+
 // Get the secret referenced by the provider.
 func (r *Reconciler) getSecret(provider *api.Provider) (*v1.Secret, error) {
 	secret := &v1.Secret{}
 	if provider.IsHost() {
 		return secret, nil
 	}
+	if provider.IsHostedCluster() {
+		return ocp.HostedClusterSecret(r, provider)
+	}
 	ref := provider.Spec.Secret
 	key := client.ObjectKey{
 		Namespace: ref.Namespace,
@@ -405,13 +592,15 @@ func (r *Reconciler) getSecret(provider *api.Provider) (*v1.Secret, error) {
 		return nil, liberr.Wrap(err)
 	}
 
-	return secret, nil
+	return base.WithCABundle(r, provider, secret)
 }
 
 // Provider catalog.
 type Catalog struct {
 	mutex   sync.Mutex
 	content map[reconcile.Request]*api.Provider
+	// Version of the secret used by the collectors.
+	secrets map[types.UID]string
 }
 
 // Add a provider to the catalog.
@@ -435,6 +624,32 @@ func (r *Catalog) get(request reconcile.Request) (p *api.Provider, found bool) {
 	return
 }
 
+// Set the version of the secret used by the provider collector.
+func (r *Catalog) setSecretVersion(p *api.Provider, version string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.secrets == nil {
+		r.secrets = make(map[types.UID]string)
+	}
+	r.secrets[p.UID] = version
+}
+
+// Get the version of the secret used by the provider collector.
+func (r *Catalog) secretVersion(p *api.Provider) (version string, found bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	version, found = r.secrets[p.UID]
+	return
+}
+
+// Delete a provider from the catalog.
+func (r *Catalog) delete(request reconcile.Request, p *api.Provider) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.content, request)
+	delete(r.secrets, p.UID)
+}
+
 func (r *Reconciler) removeVolumeOfOVAServer(provider *api.Provider) error {
 	labelSelector := labels.SelectorFromSet(labels.Set{
 		"subapp":   "ova-server",
@@ -0,0 +1,130 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/controller/base"
+	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
+	libcontainer "github.com/kubev2v/forklift/pkg/lib/inventory/container"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeClient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCredentialsRotated(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	workingDir := Settings.Inventory.WorkingDir
+	defer func() {
+		Settings.Inventory.WorkingDir = workingDir
+	}()
+	Settings.Inventory.WorkingDir = t.TempDir()
+
+	ova := api.Ova
+	provider := &api.Provider{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace:  "test",
+			Name:       "ova",
+			UID:        "ova-uid",
+			Generation: 1,
+		},
+		Spec: api.ProviderSpec{
+			Type:   &ova,
+			URL:    "127.0.0.1:1/ova",
+			Secret: core.ObjectReference{Namespace: "test", Name: "secret"},
+		},
+	}
+	provider.Status.ObservedGeneration = 1
+	provider.Status.SetCondition(libcnd.Condition{
+		Type:     ConnectionTestSucceeded,
+		Status:   libcnd.True,
+		Category: libcnd.Required,
+	})
+	secret := &core.Secret{
+		ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "secret"},
+		Data:       map[string][]byte{"password": []byte("old")},
+	}
+	scheme := runtime.NewScheme()
+	_ = core.AddToScheme(scheme)
+	_ = api.SchemeBuilder.AddToScheme(scheme)
+	fake := fakeClient.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(secret).
+		Build()
+	reconciler := &Reconciler{
+		Reconciler: base.Reconciler{
+			Client: fake,
+			Log:    logging.WithName("test"),
+		},
+		catalog:   &Catalog{},
+		container: libcontainer.New(),
+	}
+	defer func() {
+		for _, collector := range reconciler.container.List() {
+			collector.Shutdown()
+			_ = collector.DB().Close(true)
+		}
+	}()
+	collector := func() libcontainer.Collector {
+		collector, found := reconciler.container.Get(provider)
+		g.Expect(found).To(gomega.BeTrue())
+		return collector
+	}
+	rotate := func(password string) string {
+		err := fake.Get(context.TODO(), client.ObjectKeyFromObject(secret), secret)
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		secret.Data["password"] = []byte(password)
+		err = fake.Update(context.TODO(), secret)
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		return secret.ResourceVersion
+	}
+
+	// Built.
+	err := reconciler.updateContainer(provider)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	built := collector()
+	version, found := reconciler.catalog.secretVersion(provider)
+	g.Expect(found).To(gomega.BeTrue())
+	g.Expect(reconciler.credentialsRotated(provider)).To(gomega.BeFalse())
+
+	// Secret unchanged.
+	err = reconciler.updateContainer(provider)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(collector()).To(gomega.BeIdenticalTo(built))
+
+	// Secret rotated, connection test failed.
+	rotated := rotate("new")
+	g.Expect(rotated).ToNot(gomega.Equal(version))
+	g.Expect(reconciler.credentialsRotated(provider)).To(gomega.BeTrue())
+	provider.Status.DeleteCondition(ConnectionTestSucceeded)
+	provider.Status.SetCondition(libcnd.Condition{
+		Type:     ConnectionTestFailed,
+		Status:   libcnd.True,
+		Category: libcnd.Critical,
+	})
+	err = reconciler.updateContainer(provider)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(collector()).To(gomega.BeIdenticalTo(built))
+	version, _ = reconciler.catalog.secretVersion(provider)
+	g.Expect(version).ToNot(gomega.Equal(rotated))
+
+	// Secret rotated, connection test succeeded.
+	provider.Status.DeleteCondition(ConnectionTestFailed)
+	provider.Status.SetCondition(libcnd.Condition{
+		Type:     ConnectionTestSucceeded,
+		Status:   libcnd.True,
+		Category: libcnd.Required,
+	})
+	err = reconciler.updateContainer(provider)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(collector()).ToNot(gomega.BeIdenticalTo(built))
+	g.Expect(reconciler.container.List()).To(gomega.HaveLen(1))
+	version, _ = reconciler.catalog.secretVersion(provider)
+	g.Expect(version).To(gomega.Equal(rotated))
+	g.Expect(reconciler.credentialsRotated(provider)).To(gomega.BeFalse())
+}
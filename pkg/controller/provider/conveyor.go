@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+
+	"github.com/kubev2v/forklift/pkg/lib/conveyor"
+)
+
+// Gateway of the conveyors running in the source datacenters.
+// Serves the (TLS) tunnel connected by the conveyors and the
+// (in-cluster) proxy used by the providers.
+type ConveyorGateway struct {
+	// Tunnel port.
+	Port int
+	// Proxy port.
+	ProxyPort int
+	// Conveyor token.
+	Token string
+	// TLS certificate path.
+	Certificate string
+	// TLS key path.
+	Key string
+	// TLS minimum version.
+	MinVersion uint16
+}
+
+// Start the gateway.
+func (r *ConveyorGateway) Start() (err error) {
+	if r.Key == "" {
+		err = fmt.Errorf("the conveyor tunnel requires TLS")
+		return
+	}
+	gateway := conveyor.NewGateway(r.Token)
+	mux := http.NewServeMux()
+	mux.Handle(conveyor.TunnelPath, gateway.Tunnel())
+	tunnel := &http.Server{
+		Addr:    fmt.Sprintf(":%d", r.Port),
+		Handler: mux,
+		TLSConfig: &tls.Config{
+			MinVersion: r.MinVersion,
+		},
+		// The tunnel is upgraded from HTTP/1.1.
+		TLSNextProto: map[string]func(*http.Server, *tls.Conn, http.Handler){},
+	}
+	proxy := &http.Server{
+		Addr:    fmt.Sprintf(":%d", r.ProxyPort),
+		Handler: gateway.Proxy(),
+	}
+	go func() {
+		err := tunnel.ListenAndServeTLS(r.Certificate, r.Key)
+		log.Error(err, "conveyor tunnel stopped.")
+	}()
+	go func() {
+		err := proxy.ListenAndServe()
+		log.Error(err, "conveyor proxy stopped.")
+	}()
+	log.Info(
+		"Conveyor gateway started.",
+		"port",
+		r.Port,
+		"proxy",
+		r.ProxyPort)
+	return
+}
@@ -0,0 +1,110 @@
+package provider
+
+import (
+	"net/http"
+	liburl "net/url"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/controller/provider/container"
+	"github.com/kubev2v/forklift/pkg/controller/provider/health"
+	"github.com/kubev2v/forklift/pkg/lib/util"
+	core "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+// Provider health checker.
+// Periodically probes the providers and triggers the
+// reconcile of the providers whose health has changed.
+type HealthChecker struct {
+	// Probe interval.
+	Interval time.Duration
+	// Expiry warning window.
+	Window time.Duration
+	// List the providers to probe.
+	Providers func() []*api.Provider
+	// Get the secret of a provider.
+	Secret func(*api.Provider) (*core.Secret, error)
+	// Providers with changed health.
+	Changed chan event.TypedGenericEvent[*api.Provider]
+}
+
+// Start the checker.
+func (r *HealthChecker) Start() {
+	go func() {
+		for {
+			time.Sleep(r.Interval)
+			r.check()
+		}
+	}()
+}
+
+// Probe the providers.
+func (r *HealthChecker) check() {
+	for _, provider := range r.Providers() {
+		if provider.DeletionTimestamp != nil {
+			continue
+		}
+		secret, err := r.Secret(provider)
+		if err != nil {
+			log.V(3).Info("Provider secret not found.", "provider", provider.Name, "error", err.Error())
+			continue
+		}
+		// Avoid locking the account with credentials already
+		// known to be invalid until the secret is updated.
+		previous, found := health.Providers.Get(provider.UID)
+		if found && !previous.Authenticated && previous.Reachable &&
+			previous.SecretVersion == secret.ResourceVersion {
+			continue
+		}
+		probed := probe(provider, secret)
+		health.Providers.Set(provider.UID, probed)
+		if !found {
+			previous = health.Health{Reachable: true, Authenticated: true}
+		}
+		if probed.Changed(&previous, r.Window) {
+			log.Info(
+				"Provider health changed.",
+				"provider",
+				provider.Namespace+"/"+provider.Name,
+				"reachable",
+				probed.Reachable,
+				"authenticated",
+				probed.Authenticated)
+			r.Changed <- event.TypedGenericEvent[*api.Provider]{Object: provider}
+		}
+	}
+}
+
+// Probe the provider API reachability, the credentials and
+// the expiry of the API certificate and (token) credentials.
+func probe(provider *api.Provider, secret *core.Secret) (probed health.Health) {
+	probed.Checked = time.Now()
+	probed.SecretVersion = secret.ResourceVersion
+	if provider.IsHost() {
+		probed.Reachable = true
+		probed.Authenticated = true
+		return
+	}
+	status, err := container.Build(nil, provider, secret).Test()
+	switch {
+	case err == nil:
+		probed.Reachable = true
+		probed.Authenticated = true
+	// Providing bad credentials when requesting the token results in 400, and not 401.
+	case status == http.StatusUnauthorized || status == http.StatusBadRequest:
+		probed.Reachable = true
+		probed.Error = err.Error()
+	default:
+		probed.Error = err.Error()
+	}
+	probed.CredentialsExpiry = health.TokenExpiry(secret)
+	url, pErr := liburl.Parse(provider.Spec.URL)
+	if pErr == nil && url.Scheme == "https" {
+		cert, cErr := util.GetTlsCertificate(url, secret)
+		if cErr == nil {
+			probed.CertificateExpiry = &cert.NotAfter
+		}
+	}
+	return
+}
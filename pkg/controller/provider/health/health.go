@@ -0,0 +1,108 @@
+package health
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Health of the providers by UID.
+var Providers = &Registry{}
+
+// Provider health.
+type Health struct {
+	// Probe timestamp.
+	Checked time.Time `json:"checked"`
+	// The provider API is reachable.
+	Reachable bool `json:"reachable"`
+	// The credentials are valid.
+	Authenticated bool `json:"authenticated"`
+	// Expiry of the (token) credentials.
+	CredentialsExpiry *time.Time `json:"credentialsExpiry,omitempty"`
+	// Expiry of the provider API certificate.
+	CertificateExpiry *time.Time `json:"certificateExpiry,omitempty"`
+	// Probe error.
+	Error string `json:"error,omitempty"`
+	// Resource version of the probed secret.
+	SecretVersion string `json:"-"`
+}
+
+// The credentials expire within the window.
+func (r *Health) CredentialsExpiring(window time.Duration) bool {
+	return r.CredentialsExpiry != nil && time.Until(*r.CredentialsExpiry) < window
+}
+
+// The certificate expires within the window.
+func (r *Health) CertificateExpiring(window time.Duration) bool {
+	return r.CertificateExpiry != nil && time.Until(*r.CertificateExpiry) < window
+}
+
+// Determine whether the health (as reflected by
+// the provider conditions) has changed.
+func (r *Health) Changed(other *Health, window time.Duration) bool {
+	return r.Reachable != other.Reachable ||
+		r.Authenticated != other.Authenticated ||
+		r.CredentialsExpiring(window) != other.CredentialsExpiring(window) ||
+		r.CertificateExpiring(window) != other.CertificateExpiring(window)
+}
+
+// Registry of provider health.
+type Registry struct {
+	// Mutex.
+	mutex sync.Mutex
+	// Health by provider UID.
+	content map[types.UID]Health
+}
+
+// Get the health of a provider.
+func (r *Registry) Get(uid types.UID) (health Health, found bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	health, found = r.content[uid]
+	return
+}
+
+// Set the health of a provider.
+func (r *Registry) Set(uid types.UID, health Health) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.content == nil {
+		r.content = make(map[types.UID]Health)
+	}
+	r.content[uid] = health
+}
+
+// Delete the health of a provider.
+func (r *Registry) Delete(uid types.UID) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.content, uid)
+}
+
+// Expiry of the token (JWT) credentials.
+func TokenExpiry(secret *core.Secret) (expiry *time.Time) {
+	token := string(secret.Data["token"])
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return
+	}
+	claims := struct {
+		Exp int64 `json:"exp"`
+	}{}
+	err = json.Unmarshal(payload, &claims)
+	if err != nil || claims.Exp == 0 {
+		return
+	}
+	exp := time.Unix(claims.Exp, 0)
+	expiry = &exp
+	return
+}
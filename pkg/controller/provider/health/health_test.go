@@ -0,0 +1,42 @@
+package health
+
+import (
+	"encoding/base64"
+	"fmt"
+	"testing"
+	"time"
+
+	core "k8s.io/api/core/v1"
+)
+
+func TestTokenExpiry(t *testing.T) {
+	exp := time.Now().Add(time.Hour).Unix()
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"sub":"sa","exp":%d}`, exp)))
+	secret := &core.Secret{Data: map[string][]byte{"token": []byte("header." + payload + ".signature")}}
+	expiry := TokenExpiry(secret)
+	if expiry == nil || expiry.Unix() != exp {
+		t.Errorf("unexpected expiry: %v", expiry)
+	}
+	secret.Data["token"] = []byte("opaque")
+	if expiry = TokenExpiry(secret); expiry != nil {
+		t.Errorf("expected no expiry, got: %v", expiry)
+	}
+}
+
+func TestChanged(t *testing.T) {
+	window := time.Hour * 24
+	soon := time.Now().Add(time.Hour)
+	later := time.Now().Add(window * 2)
+	healthy := Health{Reachable: true, Authenticated: true, CertificateExpiry: &later}
+	expiring := Health{Reachable: true, Authenticated: true, CertificateExpiry: &soon}
+	unreachable := Health{}
+	if healthy.Changed(&healthy, window) {
+		t.Errorf("expected unchanged")
+	}
+	if !expiring.Changed(&healthy, window) || !expiring.CertificateExpiring(window) {
+		t.Errorf("expected certificate expiring")
+	}
+	if !unreachable.Changed(&healthy, window) {
+		t.Errorf("expected unreachable")
+	}
+}
@@ -0,0 +1,17 @@
+package azure
+
+import (
+	"github.com/kubev2v/forklift/pkg/controller/provider/model/ocp"
+)
+
+// Build all models.
+func All() []interface{} {
+	return []interface{}{
+		&ocp.Provider{},
+		&ResourceGroup{},
+		&Subnet{},
+		&DiskSku{},
+		&Disk{},
+		&VM{},
+	}
+}
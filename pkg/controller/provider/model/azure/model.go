@@ -0,0 +1,163 @@
+package azure
+
+import (
+	"github.com/kubev2v/forklift/pkg/controller/provider/model/base"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+)
+
+// Errors
+var NotFound = libmodel.NotFound
+
+type InvalidRefError = base.InvalidRefError
+
+const (
+	MaxDetail = base.MaxDetail
+)
+
+// Types
+type Model = base.Model
+type ListOptions = base.ListOptions
+type Concern = base.Concern
+type Ref = base.Ref
+
+// Base Azure model.
+type Base struct {
+	// ID (derived from the resource ID).
+	ID string `sql:"pk"`
+	// Name.
+	Name string `sql:"d0,index(name)"`
+	// Revision
+	Revision int64 `sql:"incremented,d0,index(revision)"`
+}
+
+// Get the PK.
+func (m *Base) Pk() string {
+	return m.ID
+}
+
+// Get the revision.
+func (m *Base) GetRevision() int64 {
+	return m.Revision
+}
+
+// String representation.
+func (m *Base) String() string {
+	return m.ID
+}
+
+// Get labels.
+func (m *Base) Labels() libmodel.Labels {
+	return nil
+}
+
+// Resource group.
+type ResourceGroup struct {
+	Base
+	ResourceID string `sql:""`
+	Location   string `sql:""`
+	State      string `sql:""`
+}
+
+// Virtual network subnet.
+// The name is <vnet>/<subnet>.
+type Subnet struct {
+	Base
+	ResourceID     string `sql:""`
+	ResourceGroup  string `sql:"d0,index(resourceGroup)"`
+	VirtualNetwork string `sql:""`
+	Location       string `sql:""`
+	CIDR           string `sql:""`
+}
+
+// Managed disk SKU.
+// The ID and name are the SKU. Example: Premium_LRS.
+type DiskSku struct {
+	Base
+}
+
+// Managed disk.
+type Disk struct {
+	Base
+	ResourceID    string `sql:""`
+	ResourceGroup string `sql:"d0,index(resourceGroup)"`
+	Location      string `sql:""`
+	// Size (GiB).
+	Size int64 `sql:""`
+	// Size (bytes).
+	SizeBytes        int64  `sql:""`
+	Sku              string `sql:"d0,index(sku)"`
+	State            string `sql:""`
+	OSType           string `sql:""`
+	HyperVGeneration string `sql:""`
+	// Managed by (VM ID).
+	VM string `sql:""`
+}
+
+// Virtual machine.
+type VM struct {
+	Base
+	RevisionValidated int64     `sql:"d0,index(revisionValidated)"`
+	PolicyVersion     int       `sql:"d0,index(policyVersion)"`
+	ResourceID        string    `sql:""`
+	ResourceGroup     string    `sql:"d0,index(resourceGroup)"`
+	Location          string    `sql:""`
+	PowerState        string    `sql:""`
+	VMSize            string    `sql:""`
+	OSType            string    `sql:""`
+	HyperVGeneration  string    `sql:""`
+	SecurityType      string    `sql:""`
+	SecureBoot        bool      `sql:""`
+	TPM               bool      `sql:""`
+	Architecture      string    `sql:""`
+	VCPUs             int32     `sql:""`
+	MemoryMB          int64     `sql:""`
+	Disks             []VMDisk  `sql:""`
+	NICs              []NIC     `sql:""`
+	Concerns          []Concern `sql:""`
+}
+
+// Determine if current revision has been validated.
+func (m *VM) Validated() bool {
+	return m.RevisionValidated == m.Revision
+}
+
+// Apply the validation (policy) results of the revision.
+// False when the VM has been updated since. The revision
+// is not incremented by the update.
+func (m *VM) Validate(revision int64, version int, concerns []Concern) bool {
+	if m.Revision != revision {
+		return false
+	}
+	m.PolicyVersion = version
+	m.RevisionValidated = revision
+	m.Concerns = concerns
+	m.Revision--
+	return true
+}
+
+// Attached disk.
+type VMDisk struct {
+	// Disk ID.
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Logical unit number (data disks).
+	Lun int `json:"lun"`
+	// OS (boot) disk.
+	OS bool `json:"os"`
+	// Size (GiB).
+	Size int64  `json:"size"`
+	Sku  string `json:"sku"`
+	// Managed disk.
+	// Unmanaged (storage account) disks are not supported.
+	Managed bool `json:"managed"`
+}
+
+// Network interface.
+type NIC struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	MAC     string   `json:"mac"`
+	Subnet  string   `json:"subnet"`
+	IPs     []string `json:"ips"`
+	Primary bool     `json:"primary"`
+}
@@ -0,0 +1,14 @@
+package azure
+
+import (
+	libref "github.com/kubev2v/forklift/pkg/lib/ref"
+)
+
+// Kinds
+var (
+	ResourceGroupKind = libref.ToKind(ResourceGroup{})
+	SubnetKind        = libref.ToKind(Subnet{})
+	DiskSkuKind       = libref.ToKind(DiskSku{})
+	DiskKind          = libref.ToKind(Disk{})
+	VmKind            = libref.ToKind(VM{})
+)
@@ -0,0 +1,15 @@
+package catalog
+
+import (
+	"github.com/kubev2v/forklift/pkg/controller/provider/model/ocp"
+)
+
+// Build all models.
+func All() []interface{} {
+	return []interface{}{
+		&ocp.Provider{},
+		&Storage{},
+		&Network{},
+		&VM{},
+	}
+}
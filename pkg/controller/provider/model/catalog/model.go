@@ -0,0 +1,128 @@
+package catalog
+
+import (
+	"github.com/kubev2v/forklift/pkg/controller/provider/model/base"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+)
+
+// Errors
+var NotFound = libmodel.NotFound
+
+type InvalidRefError = base.InvalidRefError
+
+const (
+	MaxDetail = base.MaxDetail
+)
+
+// ID of the (single) storage and network.
+const DefaultID = "default"
+
+// Types
+type Model = base.Model
+type ListOptions = base.ListOptions
+type Concern = base.Concern
+type Ref = base.Ref
+
+// Base image catalog model.
+type Base struct {
+	// ID.
+	ID string `sql:"pk"`
+	// Name.
+	Name string `sql:"d0,index(name)"`
+	// Revision
+	Revision int64 `sql:"incremented,d0,index(revision)"`
+}
+
+// Get the PK.
+func (m *Base) Pk() string {
+	return m.ID
+}
+
+// Get the revision.
+func (m *Base) GetRevision() int64 {
+	return m.Revision
+}
+
+// String representation.
+func (m *Base) String() string {
+	return m.ID
+}
+
+// Get labels.
+func (m *Base) Labels() libmodel.Labels {
+	return nil
+}
+
+// Storage.
+// The (single) share or bucket containing the catalog.
+type Storage struct {
+	Base
+	// Catalog (manifest) URL.
+	URL string `sql:""`
+	// Object storage.
+	ObjectStorage bool `sql:""`
+}
+
+// Network.
+// Images have no network so a (single) network is
+// reported to be mapped to the destination network
+// of the NIC added to each VM.
+type Network struct {
+	Base
+}
+
+// Virtual machine.
+// Each VM listed by the catalog is created with
+// the image as the (only) disk.
+type VM struct {
+	Base
+	RevisionValidated int64 `sql:"d0,index(revisionValidated)"`
+	PolicyVersion     int   `sql:"d0,index(policyVersion)"`
+	// Description.
+	Description string `sql:""`
+	// Image path (as listed by the catalog).
+	// Example: disks/rhel9.qcow2
+	Path string `sql:""`
+	// Image (data) URL.
+	URL string `sql:""`
+	// Format (raw|qcow2|vmdk).
+	Format string `sql:""`
+	// VMDK kind (sparse|descriptor).
+	VmdkKind string `sql:""`
+	// Virtual size (bytes).
+	Capacity int64 `sql:""`
+	// File size (bytes).
+	Size int64 `sql:""`
+	// vCPU count (hint).
+	CPU int64 `sql:""`
+	// Memory (hint) bytes.
+	Memory int64 `sql:""`
+	// Firmware (hint).
+	Firmware string `sql:""`
+	// Guest OS (hint).
+	OS string `sql:""`
+	// The catalog entry is not valid.
+	CatalogError string `sql:""`
+	// Storage (ID).
+	Storage  string    `sql:""`
+	Concerns []Concern `sql:""`
+}
+
+// Determine if current revision has been validated.
+func (m *VM) Validated() bool {
+	return m.RevisionValidated == m.Revision
+}
+
+// Apply the validation (policy) results of the revision.
+// False when the VM has been updated since. The revision
+// is not incremented by the update.
+func (m *VM) Validate(revision int64, version int, concerns []Concern) bool {
+	if m.Revision != revision {
+		return false
+	}
+	m.PolicyVersion = version
+	m.RevisionValidated = revision
+	m.Concerns = concerns
+	m.Revision--
+	return true
+}
@@ -2,6 +2,12 @@ package model
 
 import (
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/controller/provider/model/azure"
+	"github.com/kubev2v/forklift/pkg/controller/provider/model/catalog"
+	"github.com/kubev2v/forklift/pkg/controller/provider/model/ec2"
+	"github.com/kubev2v/forklift/pkg/controller/provider/model/image"
+	"github.com/kubev2v/forklift/pkg/controller/provider/model/libvirt"
+	"github.com/kubev2v/forklift/pkg/controller/provider/model/nutanix"
 	"github.com/kubev2v/forklift/pkg/controller/provider/model/ocp"
 	"github.com/kubev2v/forklift/pkg/controller/provider/model/openstack"
 	"github.com/kubev2v/forklift/pkg/controller/provider/model/ova"
@@ -32,6 +38,30 @@ func Models(provider *api.Provider) (all []interface{}) {
 		all = append(
 			all,
 			ova.All()...)
+	case api.Nutanix:
+		all = append(
+			all,
+			nutanix.All()...)
+	case api.EC2:
+		all = append(
+			all,
+			ec2.All()...)
+	case api.Azure:
+		all = append(
+			all,
+			azure.All()...)
+	case api.Libvirt:
+		all = append(
+			all,
+			libvirt.All()...)
+	case api.Image:
+		all = append(
+			all,
+			image.All()...)
+	case api.Catalog:
+		all = append(
+			all,
+			catalog.All()...)
 	}
 
 	return
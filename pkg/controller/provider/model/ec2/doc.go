@@ -0,0 +1,16 @@
+package ec2
+
+import (
+	"github.com/kubev2v/forklift/pkg/controller/provider/model/ocp"
+)
+
+// Build all models.
+func All() []interface{} {
+	return []interface{}{
+		&ocp.Provider{},
+		&Subnet{},
+		&VolumeType{},
+		&Volume{},
+		&VM{},
+	}
+}
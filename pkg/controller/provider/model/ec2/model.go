@@ -0,0 +1,144 @@
+package ec2
+
+import (
+	"github.com/kubev2v/forklift/pkg/controller/provider/model/base"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+)
+
+// Errors
+var NotFound = libmodel.NotFound
+
+type InvalidRefError = base.InvalidRefError
+
+const (
+	MaxDetail = base.MaxDetail
+)
+
+// Types
+type Model = base.Model
+type ListOptions = base.ListOptions
+type Concern = base.Concern
+type Ref = base.Ref
+
+// Base EC2 model.
+type Base struct {
+	// Resource ID.
+	ID string `sql:"pk"`
+	// Name (tag).
+	Name string `sql:"d0,index(name)"`
+	// Revision
+	Revision int64 `sql:"incremented,d0,index(revision)"`
+}
+
+// Get the PK.
+func (m *Base) Pk() string {
+	return m.ID
+}
+
+// Get the revision.
+func (m *Base) GetRevision() int64 {
+	return m.Revision
+}
+
+// String representation.
+func (m *Base) String() string {
+	return m.ID
+}
+
+// Get labels.
+func (m *Base) Labels() libmodel.Labels {
+	return nil
+}
+
+// VPC subnet.
+type Subnet struct {
+	Base
+	VPC              string `sql:"d0,index(vpc)"`
+	CIDR             string `sql:""`
+	AvailabilityZone string `sql:""`
+	State            string `sql:""`
+	DefaultForAz     bool   `sql:""`
+}
+
+// EBS volume type.
+// The ID and name are the type. Example: gp3.
+type VolumeType struct {
+	Base
+}
+
+// EBS volume.
+type Volume struct {
+	Base
+	// Size (GiB).
+	Size             int64  `sql:""`
+	VolumeType       string `sql:"d0,index(volumeType)"`
+	IOPS             int64  `sql:""`
+	AvailabilityZone string `sql:""`
+	State            string `sql:""`
+	Encrypted        bool   `sql:""`
+}
+
+// EC2 instance.
+type VM struct {
+	Base
+	RevisionValidated int64     `sql:"d0,index(revisionValidated)"`
+	PolicyVersion     int       `sql:"d0,index(policyVersion)"`
+	State             string    `sql:""`
+	InstanceType      string    `sql:""`
+	AvailabilityZone  string    `sql:""`
+	Platform          string    `sql:""`
+	PlatformDetails   string    `sql:""`
+	Architecture      string    `sql:""`
+	BootMode          string    `sql:""`
+	TPM               bool      `sql:""`
+	VCPUs             int32     `sql:""`
+	CoresPerSocket    int32     `sql:""`
+	ThreadsPerCore    int32     `sql:""`
+	MemoryMB          int64     `sql:""`
+	RootDevice        string    `sql:""`
+	VPC               string    `sql:""`
+	Disks             []Disk    `sql:""`
+	NICs              []NIC     `sql:""`
+	Concerns          []Concern `sql:""`
+}
+
+// Determine if current revision has been validated.
+func (m *VM) Validated() bool {
+	return m.RevisionValidated == m.Revision
+}
+
+// Apply the validation (policy) results of the revision.
+// False when the VM has been updated since. The revision
+// is not incremented by the update.
+func (m *VM) Validate(revision int64, version int, concerns []Concern) bool {
+	if m.Revision != revision {
+		return false
+	}
+	m.PolicyVersion = version
+	m.RevisionValidated = revision
+	m.Concerns = concerns
+	m.Revision--
+	return true
+}
+
+// Attached EBS volume.
+type Disk struct {
+	// Volume ID.
+	ID string `json:"id"`
+	// Device name. Example: /dev/xvda.
+	Device string `json:"device"`
+	// Root (boot) device.
+	Root bool `json:"root"`
+	// Size (GiB).
+	Size       int64  `json:"size"`
+	VolumeType string `json:"volumeType"`
+}
+
+// Network interface.
+type NIC struct {
+	ID     string   `json:"id"`
+	Index  int      `json:"index"`
+	MAC    string   `json:"mac"`
+	Subnet string   `json:"subnet"`
+	IPs    []string `json:"ips"`
+}
@@ -0,0 +1,13 @@
+package ec2
+
+import (
+	libref "github.com/kubev2v/forklift/pkg/lib/ref"
+)
+
+// Kinds
+var (
+	SubnetKind     = libref.ToKind(Subnet{})
+	VolumeTypeKind = libref.ToKind(VolumeType{})
+	VolumeKind     = libref.ToKind(Volume{})
+	VmKind         = libref.ToKind(VM{})
+)
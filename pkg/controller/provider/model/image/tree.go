@@ -0,0 +1,12 @@
+package image
+
+import (
+	libref "github.com/kubev2v/forklift/pkg/lib/ref"
+)
+
+// Kinds
+var (
+	StorageKind = libref.ToKind(Storage{})
+	NetworkKind = libref.ToKind(Network{})
+	VmKind      = libref.ToKind(VM{})
+)
@@ -0,0 +1,15 @@
+package libvirt
+
+import (
+	"github.com/kubev2v/forklift/pkg/controller/provider/model/ocp"
+)
+
+// Build all models.
+func All() []interface{} {
+	return []interface{}{
+		&ocp.Provider{},
+		&Pool{},
+		&Network{},
+		&VM{},
+	}
+}
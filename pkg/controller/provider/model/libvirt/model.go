@@ -0,0 +1,154 @@
+package libvirt
+
+import (
+	"github.com/kubev2v/forklift/pkg/controller/provider/model/base"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+)
+
+// Errors
+var NotFound = libmodel.NotFound
+
+type InvalidRefError = base.InvalidRefError
+
+const (
+	MaxDetail = base.MaxDetail
+)
+
+// Types
+type Model = base.Model
+type ListOptions = base.ListOptions
+type Concern = base.Concern
+type Ref = base.Ref
+
+// Base libvirt model.
+type Base struct {
+	// ID (UUID).
+	ID string `sql:"pk"`
+	// Name.
+	Name string `sql:"d0,index(name)"`
+	// Revision
+	Revision int64 `sql:"incremented,d0,index(revision)"`
+}
+
+// Get the PK.
+func (m *Base) Pk() string {
+	return m.ID
+}
+
+// Get the revision.
+func (m *Base) GetRevision() int64 {
+	return m.Revision
+}
+
+// String representation.
+func (m *Base) String() string {
+	return m.ID
+}
+
+// Get labels.
+func (m *Base) Labels() libmodel.Labels {
+	return nil
+}
+
+// Storage pool.
+type Pool struct {
+	Base
+	// Pool type. Example: dir.
+	Type string `sql:""`
+	// Target path. Example: /var/lib/libvirt/images
+	Path string `sql:""`
+	// Capacity (bytes).
+	Capacity int64 `sql:""`
+	// Allocation (bytes).
+	Allocation int64 `sql:""`
+}
+
+// Network.
+type Network struct {
+	Base
+	// Forward mode. Example: nat.
+	Forward string `sql:""`
+	// Bridge (name).
+	Bridge string `sql:""`
+}
+
+// Virtual machine (domain).
+type VM struct {
+	Base
+	RevisionValidated int64  `sql:"d0,index(revisionValidated)"`
+	PolicyVersion     int    `sql:"d0,index(policyVersion)"`
+	State             string `sql:""`
+	// Domain type. Example: kvm.
+	Type         string `sql:""`
+	Architecture string `sql:""`
+	Machine      string `sql:""`
+	// Firmware (bios|efi).
+	Firmware   string `sql:""`
+	SecureBoot bool   `sql:""`
+	TPM        bool   `sql:""`
+	// Operating system (libosinfo) ID.
+	// Example: http://fedoraproject.org/fedora/38
+	OS         string    `sql:""`
+	CPUCount   int32     `sql:""`
+	CPUSockets int32     `sql:""`
+	CPUCores   int32     `sql:""`
+	CPUThreads int32     `sql:""`
+	MemoryMB   int64     `sql:""`
+	Disks      []Disk    `sql:""`
+	NICs       []NIC     `sql:""`
+	Concerns   []Concern `sql:""`
+}
+
+// Determine if current revision has been validated.
+func (m *VM) Validated() bool {
+	return m.RevisionValidated == m.Revision
+}
+
+// Apply the validation (policy) results of the revision.
+// False when the VM has been updated since. The revision
+// is not incremented by the update.
+func (m *VM) Validate(revision int64, version int, concerns []Concern) bool {
+	if m.Revision != revision {
+		return false
+	}
+	m.PolicyVersion = version
+	m.RevisionValidated = revision
+	m.Concerns = concerns
+	m.Revision--
+	return true
+}
+
+// Disk.
+type Disk struct {
+	// Target. Example: vda.
+	Target string `json:"target"`
+	// Bus. Example: virtio.
+	Bus string `json:"bus"`
+	// Device (disk|cdrom|floppy|lun).
+	Device string `json:"device"`
+	// Source (file or block device) path.
+	Path string `json:"path"`
+	// Format. Example: qcow2.
+	Format string `json:"format"`
+	// Capacity (bytes).
+	Capacity int64 `json:"capacity"`
+	// Storage pool (ID).
+	Pool     string `json:"pool"`
+	ReadOnly bool   `json:"readOnly"`
+	Shared   bool   `json:"shared"`
+	// Boot order.
+	BootOrder uint `json:"bootOrder"`
+}
+
+// Network interface.
+type NIC struct {
+	MAC string `json:"mac"`
+	// Model. Example: virtio.
+	Model string `json:"model"`
+	// Interface type (network|bridge|direct|user).
+	Type string `json:"type"`
+	// Network (ID).
+	Network string `json:"network"`
+	// Source (network or bridge) name.
+	Source string `json:"source"`
+}
@@ -0,0 +1,12 @@
+package libvirt
+
+import (
+	libref "github.com/kubev2v/forklift/pkg/lib/ref"
+)
+
+// Kinds
+var (
+	PoolKind    = libref.ToKind(Pool{})
+	NetworkKind = libref.ToKind(Network{})
+	VmKind      = libref.ToKind(VM{})
+)
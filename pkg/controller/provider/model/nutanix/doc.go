@@ -0,0 +1,16 @@
+package nutanix
+
+import (
+	"github.com/kubev2v/forklift/pkg/controller/provider/model/ocp"
+)
+
+// Build all models.
+func All() []interface{} {
+	return []interface{}{
+		&ocp.Provider{},
+		&Cluster{},
+		&Subnet{},
+		&StorageContainer{},
+		&VM{},
+	}
+}
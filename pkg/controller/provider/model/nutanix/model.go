@@ -0,0 +1,122 @@
+package nutanix
+
+import (
+	"github.com/kubev2v/forklift/pkg/controller/provider/model/base"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+)
+
+// Errors
+var NotFound = libmodel.NotFound
+
+type InvalidRefError = base.InvalidRefError
+
+const (
+	MaxDetail = base.MaxDetail
+)
+
+// Types
+type Model = base.Model
+type ListOptions = base.ListOptions
+type Concern = base.Concern
+type Ref = base.Ref
+
+// Base Nutanix model.
+type Base struct {
+	// Entity (UUID) ID.
+	ID string `sql:"pk"`
+	// Name
+	Name string `sql:"d0,index(name)"`
+	// Revision
+	Revision int64 `sql:"incremented,d0,index(revision)"`
+}
+
+// Get the PK.
+func (m *Base) Pk() string {
+	return m.ID
+}
+
+// String representation.
+func (m *Base) String() string {
+	return m.ID
+}
+
+// Get labels.
+func (m *Base) Labels() libmodel.Labels {
+	return nil
+}
+
+// AHV cluster.
+type Cluster struct {
+	Base
+	// AOS version.
+	Version string `sql:""`
+	// External (virtual) IP.
+	ExternalIP string `sql:""`
+}
+
+// Subnet.
+type Subnet struct {
+	Base
+	Cluster    string `sql:"d0,index(cluster)"`
+	SubnetType string `sql:""`
+	VlanID     int    `sql:""`
+	VSwitch    string `sql:""`
+	CIDR       string `sql:""`
+}
+
+// Storage container.
+type StorageContainer struct {
+	Base
+	Cluster           string `sql:"d0,index(cluster)"`
+	Capacity          int64  `sql:""`
+	ReplicationFactor int    `sql:""`
+}
+
+// VM.
+type VM struct {
+	Base
+	Description       string    `sql:""`
+	Cluster           string    `sql:"d0,index(cluster)"`
+	RevisionValidated int64     `sql:"d0,index(revisionValidated)"`
+	PolicyVersion     int       `sql:"d0,index(policyVersion)"`
+	PowerState        string    `sql:""`
+	State             string    `sql:""`
+	Sockets           int32     `sql:""`
+	CoresPerSocket    int32     `sql:""`
+	ThreadsPerCore    int32     `sql:""`
+	MemoryMB          int64     `sql:""`
+	MachineType       string    `sql:""`
+	BootType          string    `sql:""`
+	TPM               bool      `sql:""`
+	GuestTools        string    `sql:""`
+	UserData          string    `sql:""`
+	Disks             []Disk    `sql:""`
+	NICs              []NIC     `sql:""`
+	Concerns          []Concern `sql:""`
+}
+
+// Determine if current revision has been validated.
+func (m *VM) Validated() bool {
+	return m.RevisionValidated == m.Revision
+}
+
+// VM disk.
+type Disk struct {
+	ID               string `json:"id"`
+	DeviceType       string `json:"deviceType"`
+	Bus              string `json:"bus"`
+	Index            int    `json:"index"`
+	Capacity         int64  `json:"capacity"`
+	StorageContainer string `json:"storageContainer"`
+}
+
+// VM NIC.
+type NIC struct {
+	ID        string   `json:"id"`
+	MAC       string   `json:"mac"`
+	Model     string   `json:"model"`
+	Type      string   `json:"type"`
+	Connected bool     `json:"connected"`
+	Subnet    string   `json:"subnet"`
+	IPs       []string `json:"ips"`
+}
@@ -0,0 +1,13 @@
+package nutanix
+
+import (
+	libref "github.com/kubev2v/forklift/pkg/lib/ref"
+)
+
+// Kinds
+var (
+	ClusterKind          = libref.ToKind(Cluster{})
+	SubnetKind           = libref.ToKind(Subnet{})
+	StorageContainerKind = libref.ToKind(StorageContainer{})
+	VmKind               = libref.ToKind(VM{})
+)
@@ -99,6 +99,16 @@ type AttachedVolume struct {
 	ID string `sql:"d0,fk(volume)"`
 }
 
+// Network port.
+type Port struct {
+	ID        string `sql:""`
+	NetworkID string `sql:""`
+	MAC       string `sql:""`
+	VNICType  string `sql:""`
+	// SR-IOV (passthrough) port.
+	SRIOV bool `sql:""`
+}
+
 type Fault struct {
 	Code    int       `sql:""`
 	Created time.Time `sql:""`
@@ -127,9 +137,12 @@ type VM struct {
 	AdminPass         string                   `sql:""`
 	SecurityGroups    []map[string]interface{} `sql:""`
 	AttachedVolumes   []AttachedVolume         `sql:""`
+	Ports             []Port                   `sql:""`
 	Fault             Fault                    `sql:""`
 	Tags              *[]string                `sql:""`
 	ServerGroups      *[]string                `sql:""`
+	ConfigDrive       bool                     `sql:""`
+	UserData          string                   `sql:""`
 	Concerns          []Concern                `sql:"" eq:"-"`
 }
 
@@ -48,12 +48,26 @@ type DataCenter struct {
 
 type Cluster struct {
 	Base
-	DataCenter    string  `sql:"d0,fk(dataCenter +cascade)"`
-	HaReservation bool    `sql:""`
-	KsmEnabled    bool    `sql:""`
-	BiosType      string  `sql:""`
-	CPU           CPU     `sql:""`
-	Version       Version `sql:""`
+	DataCenter     string          `sql:"d0,fk(dataCenter +cascade)"`
+	HaReservation  bool            `sql:""`
+	KsmEnabled     bool            `sql:""`
+	BiosType       string          `sql:""`
+	CPU            CPU             `sql:""`
+	Version        Version         `sql:""`
+	AffinityGroups []AffinityGroup `sql:""`
+}
+
+// Affinity group.
+type AffinityGroup struct {
+	Name string `json:"name"`
+	// VM rule.
+	Enabled   bool `json:"enabled"`
+	Positive  bool `json:"positive"`
+	Enforcing bool `json:"enforcing"`
+	// VM-host rule.
+	HostsEnabled bool `json:"hostsEnabled"`
+	// Member VMs.
+	VMs []string `json:"vms"`
 }
 
 type ServerCpu struct {
@@ -179,6 +193,7 @@ type VM struct {
 	Guest                       Guest            `sql:""`
 	OSType                      string           `sql:""`
 	CustomCpuModel              string           `sql:""`
+	Initialization              Initialization   `sql:""`
 }
 
 // Determine if current revision has been validated.
@@ -243,6 +258,15 @@ type Property struct {
 	Value string `json:"value"`
 }
 
+// Initialization (cloud-init) of the first boot.
+type Initialization struct {
+	HostName          string `json:"hostName,omitempty"`
+	UserName          string `json:"userName,omitempty"`
+	AuthorizedSSHKeys string `json:"authorizedSshKeys,omitempty"`
+	Timezone          string `json:"timezone,omitempty"`
+	CustomScript      string `json:"customScript,omitempty"`
+}
+
 type Guest struct {
 	Distribution string `json:"distribution"`
 	FullVersion  string `json:"fullVersion"`
@@ -1,8 +1,11 @@
 package vsphere
 
 import (
+	"net"
+
 	"github.com/kubev2v/forklift/pkg/controller/provider/model/base"
 	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	"github.com/kubev2v/forklift/pkg/lib/util"
 )
 
 type ProtocolType string
@@ -16,6 +19,8 @@ const (
 	NetDvSwitch    = "DvSwitch"
 	// Cluster.
 	ComputeResource = "ComputeResource"
+	// Host management network (port group).
+	ManagementNetwork = "Management Network"
 	// Storage Protocol Type
 	ProtocolUnknown      ProtocolType = "Unknown"      // Unrecognized or unsupported
 	ProtocolFibreChannel ProtocolType = "FibreChannel" // High-speed network tech
@@ -61,6 +66,9 @@ type Base struct {
 	Parent Ref `sql:"d0,index(parent)"`
 	// Revision
 	Revision int64 `sql:"incremented,d0,index(revision)"`
+	// The vCenter (host) when enumerated in addition to
+	// the provider vCenter. The ID is prefixed `<vcenter>:`.
+	VCenter string `sql:"d0,index(vcenter)"`
 }
 
 // Get the PK.
@@ -101,8 +109,11 @@ func (m *Base) GetName() string {
 	return m.Name
 }
 
+// About the vCenter.
+// The ID is the vCenter (host) for the additional vCenters.
 type About struct {
 	Base
+	URL          string `sql:""`
 	APIVersion   string `sql:""`
 	Product      string `sql:""`
 	InstanceUuid string `sql:""`
@@ -125,15 +136,34 @@ type Datacenter struct {
 
 type Cluster struct {
 	Base
-	Folder      string `sql:"d0,index(folder)"`
-	Hosts       []Ref  `sql:""`
-	Networks    []Ref  `sql:""`
-	Datastores  []Ref  `sql:""`
-	DasEnabled  bool   `sql:""`
-	DasVms      []Ref  `sql:""`
-	DrsEnabled  bool   `sql:""`
-	DrsBehavior string `sql:""`
-	DrsVms      []Ref  `sql:""`
+	Folder      string        `sql:"d0,index(folder)"`
+	Hosts       []Ref         `sql:""`
+	Networks    []Ref         `sql:""`
+	Datastores  []Ref         `sql:""`
+	DasEnabled  bool          `sql:""`
+	DasVms      []Ref         `sql:""`
+	DrsEnabled  bool          `sql:""`
+	DrsBehavior string        `sql:""`
+	DrsVms      []Ref         `sql:""`
+	EvcMode     string        `sql:""`
+	Rules       []ClusterRule `sql:""`
+}
+
+// Cluster (DRS) VM rule kinds.
+const (
+	RuleAffinity     = "affinity"
+	RuleAntiAffinity = "anti-affinity"
+	RuleVmHost       = "vm-host"
+	RuleDependency   = "dependency"
+)
+
+// Cluster (DRS) VM rule.
+type ClusterRule struct {
+	Name      string `json:"name"`
+	Kind      string `json:"kind"`
+	Enabled   bool   `json:"enabled"`
+	Mandatory bool   `json:"mandatory"`
+	VMs       []Ref  `json:"vms"`
 }
 
 type Host struct {
@@ -220,6 +250,31 @@ func (n *HostNetwork) PortGroup(name string) (portGroup *PortGroup, found bool)
 	return
 }
 
+// IP address of the management network VNIC.
+// The address of the family is preferred when the network
+// (tcp4|tcp6) is specified.
+func (n *HostNetwork) ManagementAddress(network string) (address string, found bool) {
+	addresses := []string{}
+	for _, vnic := range n.VNICs {
+		if vnic.PortGroup == ManagementNetwork {
+			if vnic.IpAddress != "" && net.ParseIP(vnic.IpAddress) != nil {
+				addresses = append(addresses, vnic.IpAddress)
+			}
+			for _, ip := range vnic.IpV6Addresses {
+				if net.ParseIP(ip) != nil {
+					addresses = append(addresses, ip)
+				}
+			}
+		}
+	}
+	if len(addresses) > 0 {
+		address = util.SelectAddress(addresses, network)
+		found = true
+	}
+
+	return
+}
+
 func (n *HostNetwork) PNIC(key string) (nic *PNIC, found bool) {
 	for _, object := range n.PNICs {
 		if key == object.Key {
@@ -244,6 +299,8 @@ type VNIC struct {
 	IpAddress  string `json:"ipAddress"`
 	SubnetMask string `json:"subnetMask"`
 	MTU        int32  `json:"mtu"`
+	// IPv6 addresses.
+	IpV6Addresses []string `json:"ipV6Addresses,omitempty"`
 }
 
 type PortGroup struct {
@@ -285,45 +342,55 @@ type Datastore struct {
 
 type VM struct {
 	Base
-	Folder                   string         `sql:"d0,index(folder)"`
-	Host                     string         `sql:"d0,index(host)"`
-	RevisionValidated        int64          `sql:"d0,index(revisionValidated)"`
-	PolicyVersion            int            `sql:"d0,index(policyVersion)"`
-	UUID                     string         `sql:""`
-	Firmware                 string         `sql:""`
-	PowerState               string         `sql:""`
-	ConnectionState          string         `sql:""`
-	CpuAffinity              []int32        `sql:""`
-	CpuHotAddEnabled         bool           `sql:""`
-	CpuHotRemoveEnabled      bool           `sql:""`
-	MemoryHotAddEnabled      bool           `sql:""`
-	FaultToleranceEnabled    bool           `sql:""`
-	CpuCount                 int32          `sql:""`
-	CoresPerSocket           int32          `sql:""`
-	MemoryMB                 int32          `sql:""`
-	GuestName                string         `sql:""`
-	GuestNameFromVmwareTools string         `sql:""`
-	HostName                 string         `sql:""`
-	GuestID                  string         `sql:""`
-	BalloonedMemory          int32          `sql:""`
-	IpAddress                string         `sql:""`
-	NumaNodeAffinity         []string       `sql:""`
-	StorageUsed              int64          `sql:""`
-	Snapshot                 Ref            `sql:""`
-	IsTemplate               bool           `sql:""`
-	ChangeTrackingEnabled    bool           `sql:""`
-	TpmEnabled               bool           `sql:""`
-	Devices                  []Device       `sql:""`
-	NICs                     []NIC          `sql:""`
-	Disks                    []Disk         `sql:""`
-	Controllers              []Controller   `sql:""`
-	Networks                 []Ref          `sql:""`
-	Concerns                 []Concern      `sql:""`
-	GuestNetworks            []GuestNetwork `sql:""`
-	GuestIpStacks            []GuestIpStack `sql:""`
-	SecureBoot               bool           `sql:""`
-	DiskEnableUuid           bool           `sql:""`
-	NestedHVEnabled          bool           `sql:""`
+	Folder                   string            `sql:"d0,index(folder)"`
+	Host                     string            `sql:"d0,index(host)"`
+	RevisionValidated        int64             `sql:"d0,index(revisionValidated)"`
+	PolicyVersion            int               `sql:"d0,index(policyVersion)"`
+	UUID                     string            `sql:""`
+	Firmware                 string            `sql:""`
+	PowerState               string            `sql:""`
+	ConnectionState          string            `sql:""`
+	CpuAffinity              []int32           `sql:""`
+	CpuHotAddEnabled         bool              `sql:""`
+	CpuHotRemoveEnabled      bool              `sql:""`
+	MemoryHotAddEnabled      bool              `sql:""`
+	FaultToleranceEnabled    bool              `sql:""`
+	CpuCount                 int32             `sql:""`
+	CoresPerSocket           int32             `sql:""`
+	MemoryMB                 int32             `sql:""`
+	MemoryReservationMB      int64             `sql:""`
+	MemoryLimitMB            int64             `sql:""`
+	MemoryReservationLocked  bool              `sql:""`
+	GuestName                string            `sql:""`
+	GuestNameFromVmwareTools string            `sql:""`
+	HostName                 string            `sql:""`
+	GuestID                  string            `sql:""`
+	BalloonedMemory          int32             `sql:""`
+	IpAddress                string            `sql:""`
+	NumaNodeAffinity         []string          `sql:""`
+	StorageUsed              int64             `sql:""`
+	Snapshot                 Ref               `sql:""`
+	IsTemplate               bool              `sql:""`
+	ChangeTrackingEnabled    bool              `sql:""`
+	TpmEnabled               bool              `sql:""`
+	Devices                  []Device          `sql:""`
+	NICs                     []NIC             `sql:""`
+	Disks                    []Disk            `sql:""`
+	Controllers              []Controller      `sql:""`
+	Networks                 []Ref             `sql:""`
+	Concerns                 []Concern         `sql:""`
+	GuestNetworks            []GuestNetwork    `sql:""`
+	GuestIpStacks            []GuestIpStack    `sql:""`
+	SecureBoot               bool              `sql:""`
+	DiskEnableUuid           bool              `sql:""`
+	NestedHVEnabled          bool              `sql:""`
+	LatencySensitivity       string            `sql:""`
+	HugePages1G              bool              `sql:""`
+	BootOrder                []BootDevice      `sql:""`
+	GuestInfo                GuestInfo         `sql:""`
+	CustomAttributes         []CustomAttribute `sql:""`
+	Notes                    string            `sql:""`
+	CDROMs                   []CDROM           `sql:""`
 }
 
 // Determine if current revision has been validated.
@@ -352,18 +419,91 @@ type Disk struct {
 	Mode                  string `json:"mode,omitempty"`
 	Serial                string `json:"serial,omitempty"`
 	ChangeTrackingEnabled bool   `json:"changeTrackingEnabled"`
+	// RDM compatibility mode (physical or virtual).
+	RDMMode string `json:"rdmMode,omitempty"`
+	// WWN of the RDM LUN.
+	WWN string `json:"wwn,omitempty"`
+}
+
+// RDM compatibility modes.
+const (
+	RDMPhysical = "physical"
+	RDMVirtual  = "virtual"
+)
+
+// Disk modes.
+const (
+	DiskModePersistent               = "persistent"
+	DiskModeIndependentPersistent    = "independent_persistent"
+	DiskModeIndependentNonPersistent = "independent_nonpersistent"
+)
+
+// Independent disks are excluded from snapshots.
+func (d *Disk) Independent() bool {
+	return d.Mode == DiskModeIndependentPersistent ||
+		d.Mode == DiskModeIndependentNonPersistent
 }
 
 // Virtual Device.
 type Device struct {
 	Kind string `json:"kind"`
+	// Passthrough device profile. Either the vGPU profile
+	// or the PCI vendor and device IDs (vendor:device).
+	Profile string `json:"profile,omitempty"`
+}
+
+// Boot device kinds.
+const (
+	BootDisk     = "disk"
+	BootEthernet = "ethernet"
+	BootCdrom    = "cdrom"
+	BootFloppy   = "floppy"
+)
+
+// Bootable device.
+type BootDevice struct {
+	Kind string `json:"kind"`
+	// Device key of the disk or ethernet card.
+	Key int32 `json:"key,omitempty"`
+}
+
+// Cloud-init data delivered to the guest
+// through the guestinfo variables.
+type GuestInfo struct {
+	UserData         string `json:"userData,omitempty"`
+	UserDataEncoding string `json:"userDataEncoding,omitempty"`
+	MetaData         string `json:"metaData,omitempty"`
+	MetaDataEncoding string `json:"metaDataEncoding,omitempty"`
+}
+
+// Virtual CD-ROM.
+type CDROM struct {
+	Key int32 `json:"key"`
+	// ISO file (e.g. [datastore1] iso/rhel9.iso), empty
+	// when no ISO media is inserted.
+	ISO string `json:"iso,omitempty"`
+	// Datastore of the ISO file.
+	Datastore Ref `json:"datastore,omitempty"`
+	// Connected (at power on).
+	Connected bool `json:"connected"`
+}
+
+// Custom attribute defined for the VM.
+// The value is empty when not set.
+type CustomAttribute struct {
+	Key   int32  `json:"key"`
+	Name  string `json:"name"`
+	Value string `json:"value,omitempty"`
 }
 
 // Virtual ethernet card.
 type NIC struct {
+	Key     int32  `json:"key"`
 	Network Ref    `json:"network"`
 	MAC     string `json:"mac"`
 	Index   int    `json:"order"`
+	// SR-IOV passthrough adapter.
+	SRIOV bool `json:"sriov,omitempty"`
 }
 
 // Guest network.
@@ -7,16 +7,22 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
+	"strconv"
+	"strings"
+	"time"
 
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
 	"github.com/kubev2v/forklift/pkg/controller/base"
 	"github.com/kubev2v/forklift/pkg/controller/provider/container"
+	"github.com/kubev2v/forklift/pkg/controller/provider/health"
+	ocp "github.com/kubev2v/forklift/pkg/lib/client/openshift"
 	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
 	liberr "github.com/kubev2v/forklift/pkg/lib/error"
 	libref "github.com/kubev2v/forklift/pkg/lib/ref"
 	"github.com/kubev2v/forklift/pkg/lib/util"
 	core "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -33,6 +39,11 @@ const (
 	InventoryCreated        = "InventoryCreated"
 	LoadInventory           = "LoadInventory"
 	ConnectionInsecure      = "ConnectionInsecure"
+	HostedClusterNotReady   = "HostedClusterNotReady"
+	Unreachable             = "Unreachable"
+	CredentialsExpiring     = "CredentialsExpiring"
+	CertificateExpiring     = "CertificateExpiring"
+	ThumbprintChanged       = "ThumbprintChanged"
 )
 
 // Categories
@@ -55,6 +66,7 @@ const (
 	Tested              = "Tested"
 	Started             = "Started"
 	SkipTLSVerification = "SkipTLSVerification"
+	CertificateChanged  = "CertificateChanged"
 )
 
 // Phases
@@ -85,10 +97,12 @@ func (r *Reconciler) validate(provider *api.Provider) error {
 	if err != nil {
 		return liberr.Wrap(err)
 	}
+	r.validateSettings(provider)
 	err = r.testConnection(provider, secret)
 	if err != nil {
 		return liberr.Wrap(err)
 	}
+	r.validateHealth(provider)
 	err = r.inventoryCreated(provider)
 	if err != nil {
 		return liberr.Wrap(err)
@@ -130,7 +144,8 @@ func (r *Reconciler) validateType(provider *api.Provider) error {
 
 // Validate the URL.
 func (r *Reconciler) validateURL(provider *api.Provider) error {
-	if provider.IsHost() {
+	// The URL of a hosted cluster is in the kubeconfig.
+	if provider.IsHost() || provider.IsHostedCluster() {
 		return nil
 	}
 	if provider.Spec.URL == "" {
@@ -205,6 +220,10 @@ func (r *Reconciler) validateSecret(provider *api.Provider) (secret *core.Secret
 	if provider.IsHost() {
 		return
 	}
+	if provider.IsHostedCluster() {
+		secret, err = r.validateHostedCluster(provider)
+		return
+	}
 	// NotSet
 	newCnd := libcnd.Condition{
 		Type:     SecretNotValid,
@@ -269,7 +288,9 @@ func (r *Reconciler) validateSecret(provider *api.Provider) (secret *core.Secret
 			})
 			return
 		}
-		provider.Status.Fingerprint = util.Fingerprint(crt)
+		fingerprint := util.Fingerprint(crt)
+		r.validateThumbprint(provider, fingerprint)
+		provider.Status.Fingerprint = fingerprint
 	case api.OVirt:
 		keyList = []string{
 			"user",
@@ -291,6 +312,63 @@ func (r *Reconciler) validateSecret(provider *api.Provider) (secret *core.Secret
 		keyList = []string{
 			"url",
 		}
+	case api.Nutanix:
+		keyList = []string{
+			"user",
+			"password",
+		}
+		if base.GetInsecureSkipVerifyFlag(secret) {
+			provider.Status.SetCondition(libcnd.Condition{
+				Type:     ConnectionInsecure,
+				Status:   True,
+				Reason:   SkipTLSVerification,
+				Category: Warn,
+				Message:  "TLS is susceptible to machine-in-the-middle attacks when certificate verification is skipped.",
+			})
+		}
+	case api.EC2:
+		keyList = []string{
+			"accessKeyId",
+			"secretAccessKey",
+		}
+	case api.Azure:
+		keyList = []string{
+			"tenantId",
+			"clientId",
+			"clientSecret",
+			"subscriptionId",
+		}
+	case api.Libvirt:
+		keyList = []string{}
+		if _, found := secret.Data["privateKey"]; !found {
+			keyList = append(keyList, "password")
+		}
+		if base.GetInsecureSkipVerifyFlag(secret) {
+			provider.Status.SetCondition(libcnd.Condition{
+				Type:     ConnectionInsecure,
+				Status:   True,
+				Reason:   SkipTLSVerification,
+				Category: Warn,
+				Message:  "SSH is susceptible to machine-in-the-middle attacks when host key verification is skipped.",
+			})
+		} else {
+			keyList = append(keyList, "fingerprint")
+		}
+	case api.Image, api.Catalog:
+		// Public (http) shares need no credentials.
+		keyList = []string{}
+		if strings.HasPrefix(provider.Spec.URL, "s3://") {
+			keyList = append(keyList, "accessKeyId", "secretKey")
+		}
+		if base.GetInsecureSkipVerifyFlag(secret) {
+			provider.Status.SetCondition(libcnd.Condition{
+				Type:     ConnectionInsecure,
+				Status:   True,
+				Reason:   SkipTLSVerification,
+				Category: Warn,
+				Message:  "TLS is susceptible to machine-in-the-middle attacks when certificate verification is skipped.",
+			})
+		}
 	}
 	for _, key := range keyList {
 		if _, found := secret.Data[key]; !found {
@@ -306,6 +384,102 @@ func (r *Reconciler) validateSecret(provider *api.Provider) (secret *core.Secret
 	return
 }
 
+// Validate the settings.
+// The shape of the VMs imported from (disk image) files.
+// The catalog hints take precedence over the settings.
+// The additional vCenters enumerated by a vSphere provider.
+func (r *Reconciler) validateSettings(provider *api.Provider) {
+	newCnd := libcnd.Condition{
+		Type:     SettingsNotValid,
+		Status:   True,
+		Reason:   Malformed,
+		Category: Critical,
+		Message:  "The `settings` are not valid.",
+	}
+	settings := provider.Spec.Settings
+	switch provider.Type() {
+	case api.Image, api.Catalog:
+		if s, found := settings[api.CPU]; found {
+			if n, err := strconv.Atoi(s); err != nil || n < 1 {
+				newCnd.Items = append(newCnd.Items, api.CPU)
+			}
+		}
+		if s, found := settings[api.Memory]; found {
+			if q, err := resource.ParseQuantity(s); err != nil || q.Sign() < 1 {
+				newCnd.Items = append(newCnd.Items, api.Memory)
+			}
+		}
+		if s, found := settings[api.Firmware]; found {
+			if s != "bios" && s != "efi" {
+				newCnd.Items = append(newCnd.Items, api.Firmware)
+			}
+		}
+	case api.VSphere:
+		esxi := settings[api.SDK] == api.ESXI
+		if _, found := settings[api.VCenters]; found {
+			if _, err := provider.VCenterURLs(); err != nil || esxi {
+				newCnd.Items = append(newCnd.Items, api.VCenters)
+			}
+		}
+		if s, found := settings[api.LinkedMode]; found {
+			if _, err := strconv.ParseBool(s); err != nil || esxi {
+				newCnd.Items = append(newCnd.Items, api.LinkedMode)
+			}
+		}
+	default:
+		return
+	}
+	if len(newCnd.Items) > 0 {
+		provider.Status.Phase = ValidationFailed
+		provider.Status.SetCondition(newCnd)
+	}
+}
+
+// Warn when the provider certificate thumbprint has changed.
+// The (durable) condition is cleared once the provider spec
+// has been updated.
+func (r *Reconciler) validateThumbprint(provider *api.Provider, fingerprint string) {
+	if provider.Status.ObservedGeneration < provider.Generation {
+		provider.Status.DeleteCondition(ThumbprintChanged)
+	}
+	previous := provider.Status.Fingerprint
+	if previous == "" || previous == fingerprint {
+		return
+	}
+	provider.Status.SetCondition(libcnd.Condition{
+		Type:     ThumbprintChanged,
+		Status:   True,
+		Reason:   CertificateChanged,
+		Category: Warn,
+		Durable:  true,
+		Message: fmt.Sprintf(
+			"The certificate thumbprint changed from %s to %s.",
+			previous,
+			fingerprint),
+	})
+}
+
+// Validate the HostedCluster referenced by a hosted cluster provider
+// and resolve the admin kubeconfig secret published by the HostedCluster.
+func (r *Reconciler) validateHostedCluster(provider *api.Provider) (secret *core.Secret, err error) {
+	secret, err = ocp.HostedClusterSecret(r, provider)
+	if err != nil {
+		provider.Status.Phase = ValidationFailed
+		provider.Status.SetCondition(
+			libcnd.Condition{
+				Type:     HostedClusterNotReady,
+				Status:   True,
+				Reason:   NotFound,
+				Category: Critical,
+				Message:  fmt.Sprintf("The hosted cluster is not ready: %s", liberr.Unwrap(err).Error()),
+			})
+		secret = nil
+		err = nil
+	}
+
+	return
+}
+
 // Test connection.
 func (r *Reconciler) testConnection(provider *api.Provider, secret *core.Secret) error {
 	if provider.Status.HasBlockerCondition() {
@@ -361,6 +535,51 @@ func (r *Reconciler) testConnection(provider *api.Provider, secret *core.Secret)
 	return nil
 }
 
+// Validate the health reported by the periodic probes.
+func (r *Reconciler) validateHealth(provider *api.Provider) {
+	probed, found := health.Providers.Get(provider.UID)
+	if !found {
+		return
+	}
+	window := time.Duration(Settings.Inventory.Health.ExpiryWarning) * 24 * time.Hour
+	if !probed.Reachable && !provider.Status.HasCondition(ConnectionTestSucceeded) {
+		provider.Status.SetCondition(
+			libcnd.Condition{
+				Type:     Unreachable,
+				Status:   True,
+				Reason:   Tested,
+				Category: Warn,
+				Message: fmt.Sprintf(
+					"The provider API is not reachable: %s",
+					probed.Error),
+			})
+	}
+	if probed.CredentialsExpiring(window) {
+		provider.Status.SetCondition(
+			libcnd.Condition{
+				Type:     CredentialsExpiring,
+				Status:   True,
+				Reason:   Tested,
+				Category: Warn,
+				Message: fmt.Sprintf(
+					"The provider credentials expire at %s.",
+					probed.CredentialsExpiry.Format(time.RFC3339)),
+			})
+	}
+	if probed.CertificateExpiring(window) {
+		provider.Status.SetCondition(
+			libcnd.Condition{
+				Type:     CertificateExpiring,
+				Status:   True,
+				Reason:   Tested,
+				Category: Warn,
+				Message: fmt.Sprintf(
+					"The provider API certificate expires at %s.",
+					probed.CertificateExpiry.Format(time.RFC3339)),
+			})
+	}
+}
+
 // Validate inventory created.
 func (r *Reconciler) inventoryCreated(provider *api.Provider) error {
 	if provider.Status.HasBlockerCondition() {
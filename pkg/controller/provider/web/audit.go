@@ -0,0 +1,95 @@
+package web
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	"github.com/kubev2v/forklift/pkg/lib/audit"
+)
+
+// Routes.
+const (
+	AuditRoot = "/audit"
+)
+
+// Params.
+const (
+	LimitParam = "limit"
+)
+
+// Audit middleware.
+// Records the subject, timing and outcome of state-changing
+// (not read-only) requests in the audit log.
+func Audit(auditLog *audit.Log) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		switch ctx.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			ctx.Next()
+			return
+		}
+		started := time.Now()
+		ctx.Next()
+		subject, err := base.DefaultAuth.Subject(ctx)
+		if err != nil {
+			log.Error(err, "Audit subject not resolved.")
+		}
+		status := ctx.Writer.Status()
+		record := &audit.Record{
+			Time:      started,
+			Subject:   subject,
+			Method:    ctx.Request.Method,
+			Path:      ctx.Request.URL.Path,
+			Operation: ctx.FullPath(),
+			Status:    status,
+			Outcome:   audit.Outcome(status),
+			Duration:  time.Since(started).Milliseconds(),
+		}
+		err = auditLog.Write(record)
+		if err != nil {
+			log.Error(err, "Audit record not written.", "path", record.Path)
+		}
+	}
+}
+
+// Audit handler.
+// Lists the most recent audit records to administrators.
+type AuditHandler struct {
+	// Audit log.
+	Log *audit.Log
+}
+
+// Add routes to the `gin` router.
+func (h *AuditHandler) AddRoutes(e *gin.Engine) {
+	e.GET(AuditRoot, h.List)
+}
+
+// List the audit records, newest first.
+func (h AuditHandler) List(ctx *gin.Context) {
+	status, err := base.DefaultAuth.Admin(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	limit := 0
+	if s := ctx.Query(LimitParam); s != "" {
+		limit, err = strconv.Atoi(s)
+		if err != nil || limit < 0 {
+			base.ReplyError(ctx, http.StatusBadRequest, err)
+			return
+		}
+	}
+	records, err := h.Log.Read(limit)
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, records)
+}
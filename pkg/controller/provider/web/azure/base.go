@@ -0,0 +1,78 @@
+package azure
+
+import (
+	"strings"
+
+	pathlib "path"
+
+	"github.com/gin-gonic/gin"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/azure"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+)
+
+// Package logger.
+var log = logging.WithName("web|azure")
+
+// Fields.
+const (
+	DetailParam = base.DetailParam
+	NameParam   = base.NameParam
+)
+
+// Base handler.
+type Handler struct {
+	base.Handler
+}
+
+// Build list predicate.
+func (h Handler) Predicate(ctx *gin.Context) (p libmodel.Predicate) {
+	q := ctx.Request.URL.Query()
+	name := q.Get(NameParam)
+	if len(name) > 0 {
+		path := strings.Split(name, "/")
+		name := path[len(path)-1]
+		p = libmodel.Eq(NameParam, name)
+	}
+
+	return
+}
+
+// Build list options.
+func (h Handler) ListOptions(ctx *gin.Context) libmodel.ListOptions {
+	detail := h.Detail
+	if detail > 0 {
+		detail = model.MaxDetail
+	}
+	return libmodel.ListOptions{
+		Predicate: h.Predicate(ctx),
+		Detail:    detail,
+		Page:      &h.Page,
+	}
+}
+
+// Path builder.
+// Subnets, disks and VMs are prefixed with the resource group.
+type PathBuilder struct {
+	// Database.
+	DB libmodel.DB
+}
+
+// Build the path.
+func (r *PathBuilder) Path(m model.Model) (path string) {
+	switch m := m.(type) {
+	case *model.ResourceGroup:
+		path = pathlib.Join("/", m.Name)
+	case *model.Subnet:
+		path = pathlib.Join("/", m.ResourceGroup, m.Name)
+	case *model.DiskSku:
+		path = pathlib.Join("/", m.Name)
+	case *model.Disk:
+		path = pathlib.Join("/", m.ResourceGroup, m.Name)
+	case *model.VM:
+		path = pathlib.Join("/", m.ResourceGroup, m.Name)
+	}
+
+	return
+}
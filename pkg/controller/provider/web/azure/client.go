@@ -0,0 +1,329 @@
+package azure
+
+import (
+	"strings"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+)
+
+// Errors.
+type ResourceNotResolvedError = base.ResourceNotResolvedError
+type RefNotUniqueError = base.RefNotUniqueError
+type NotFoundError = base.NotFoundError
+
+// API path resolver.
+type Resolver struct {
+	*api.Provider
+}
+
+// Build the URL path.
+func (r *Resolver) Path(resource interface{}, id string) (path string, err error) {
+	provider := r.Provider
+	switch res := resource.(type) {
+	case *Provider:
+		res.UID = id
+		res.Link()
+		path = res.SelfLink
+	case *ResourceGroup:
+		res.ID = id
+		res.Link(provider)
+		path = res.SelfLink
+	case *Subnet:
+		res.ID = id
+		res.Link(provider)
+		path = res.SelfLink
+	case *VM:
+		res.ID = id
+		res.Link(provider)
+		path = res.SelfLink
+	case *Workload:
+		res.ID = id
+		res.Link(provider)
+		path = res.SelfLink
+	case *DiskSku:
+		res.ID = id
+		res.Link(provider)
+		path = res.SelfLink
+	case *Disk:
+		res.ID = id
+		res.Link(provider)
+		path = res.SelfLink
+	default:
+		err = liberr.Wrap(
+			base.ResourceNotResolvedError{
+				Object: resource,
+			},
+		)
+	}
+
+	path = strings.TrimRight(path, "/")
+
+	return
+}
+
+// Resource finder.
+type Finder struct {
+	base.Client
+}
+
+// With client.
+func (r *Finder) With(client base.Client) base.Finder {
+	r.Client = client
+	return r
+}
+
+// Find a resource by ref.
+// Returns:
+//
+//	ProviderNotSupportedErr
+//	ProviderNotReadyErr
+//	NotFoundErr
+//	RefNotUniqueErr
+func (r *Finder) ByRef(resource interface{}, ref base.Ref) (err error) {
+	switch res := resource.(type) {
+	case *Subnet:
+		id := ref.ID
+		if id != "" {
+			err = r.Get(resource, id)
+			return
+		}
+		name := ref.Name
+		if name != "" {
+			list := []Subnet{}
+			err = r.List(
+				&list,
+				base.Param{
+					Key:   DetailParam,
+					Value: "all",
+				},
+				base.Param{
+					Key:   NameParam,
+					Value: name,
+				})
+			if err != nil {
+				break
+			}
+			if len(list) == 0 {
+				err = liberr.Wrap(NotFoundError{Ref: ref})
+				break
+			}
+			if len(list) > 1 {
+				err = liberr.Wrap(RefNotUniqueError{Ref: ref})
+				break
+			}
+			*res = list[0]
+		}
+	case *VM:
+		id := ref.ID
+		if id != "" {
+			err = r.Get(resource, id)
+			return
+		}
+		name := ref.Name
+		if name != "" {
+			list := []VM{}
+			err = r.List(
+				&list,
+				base.Param{
+					Key:   DetailParam,
+					Value: "all",
+				},
+				base.Param{
+					Key:   NameParam,
+					Value: name,
+				})
+			if err != nil {
+				break
+			}
+			if len(list) == 0 {
+				err = liberr.Wrap(NotFoundError{Ref: ref})
+				break
+			}
+			if len(list) > 1 {
+				err = liberr.Wrap(RefNotUniqueError{Ref: ref})
+				break
+			}
+			*res = list[0]
+		}
+	case *Workload:
+		id := ref.ID
+		if id != "" {
+			err = r.Get(resource, id)
+			return
+		}
+		name := ref.Name
+		if name != "" {
+			list := []Workload{}
+			err = r.List(
+				&list,
+				base.Param{
+					Key:   DetailParam,
+					Value: "all",
+				},
+				base.Param{
+					Key:   NameParam,
+					Value: name,
+				})
+			if err != nil {
+				break
+			}
+			if len(list) == 0 {
+				err = liberr.Wrap(NotFoundError{Ref: ref})
+				break
+			}
+			if len(list) > 1 {
+				err = liberr.Wrap(RefNotUniqueError{Ref: ref})
+				break
+			}
+			*res = list[0]
+		}
+	case *DiskSku:
+		id := ref.ID
+		if id != "" {
+			err = r.Get(resource, id)
+			return
+		}
+		name := ref.Name
+		if name != "" {
+			list := []DiskSku{}
+			err = r.List(
+				&list,
+				base.Param{
+					Key:   DetailParam,
+					Value: "all",
+				},
+				base.Param{
+					Key:   NameParam,
+					Value: name,
+				})
+			if err != nil {
+				break
+			}
+			if len(list) == 0 {
+				err = liberr.Wrap(NotFoundError{Ref: ref})
+				break
+			}
+			if len(list) > 1 {
+				err = liberr.Wrap(RefNotUniqueError{Ref: ref})
+				break
+			}
+			*res = list[0]
+		}
+	case *Disk:
+		id := ref.ID
+		if id != "" {
+			err = r.Get(resource, id)
+			return
+		}
+		name := ref.Name
+		if name != "" {
+			list := []Disk{}
+			err = r.List(
+				&list,
+				base.Param{
+					Key:   DetailParam,
+					Value: "all",
+				},
+				base.Param{
+					Key:   NameParam,
+					Value: name,
+				})
+			if err != nil {
+				break
+			}
+			if len(list) == 0 {
+				err = liberr.Wrap(NotFoundError{Ref: ref})
+				break
+			}
+			if len(list) > 1 {
+				err = liberr.Wrap(RefNotUniqueError{Ref: ref})
+				break
+			}
+			*res = list[0]
+		}
+	default:
+		err = liberr.Wrap(
+			ResourceNotResolvedError{
+				Object: resource,
+			})
+	}
+
+	return
+}
+
+// Find a VM by ref.
+// Returns the matching resource and:
+//
+//	ProviderNotSupportedErr
+//	ProviderNotReadyErr
+//	NotFoundErr
+//	RefNotUniqueErr
+func (r *Finder) VM(ref *base.Ref) (object interface{}, err error) {
+	vm := &VM{}
+	err = r.ByRef(vm, *ref)
+	if err == nil {
+		ref.ID = vm.ID
+		ref.Name = vm.Name
+		object = vm
+	}
+
+	return
+}
+
+// Find a Network (subnet) by ref.
+// Returns the matching resource and:
+//
+//	ProviderNotSupportedErr
+//	ProviderNotReadyErr
+//	NotFoundErr
+//	RefNotUniqueErr
+func (r *Finder) Network(ref *base.Ref) (object interface{}, err error) {
+	subnet := &Subnet{}
+	err = r.ByRef(subnet, *ref)
+	if err == nil {
+		ref.ID = subnet.ID
+		ref.Name = subnet.Name
+		object = subnet
+	}
+
+	return
+}
+
+// Find a Storage (disk SKU) by ref.
+// Returns the matching resource and:
+//
+//	ProviderNotSupportedErr
+//	ProviderNotReadyErr
+//	NotFoundErr
+//	RefNotUniqueErr
+func (r *Finder) Storage(ref *base.Ref) (object interface{}, err error) {
+	sku := &DiskSku{}
+	err = r.ByRef(sku, *ref)
+	if err == nil {
+		ref.ID = sku.ID
+		ref.Name = sku.Name
+		object = sku
+	}
+	return
+}
+
+// Find workload by ref.
+// Returns the matching resource and:
+//
+//	ProviderNotSupportedErr
+//	ProviderNotReadyErr
+//	NotFoundErr
+//	RefNotUniqueErr
+func (r *Finder) Workload(ref *base.Ref) (object interface{}, err error) {
+	workload := &Workload{}
+	err = r.ByRef(workload, *ref)
+	if err == nil {
+		ref.ID = workload.ID
+		ref.Name = workload.Name
+		object = workload
+	}
+
+	return
+}
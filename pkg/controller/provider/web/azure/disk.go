@@ -0,0 +1,220 @@
+package azure
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/azure"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+)
+
+// Routes.
+const (
+	DiskParam      = "disk"
+	DiskCollection = "disks"
+	DisksRoot      = ProviderRoot + "/" + DiskCollection
+	DiskRoot       = DisksRoot + "/:" + DiskParam
+)
+
+// Disk handler.
+type DiskHandler struct {
+	Handler
+}
+
+// Add routes to the `gin` router.
+func (h *DiskHandler) AddRoutes(e *gin.Engine) {
+	e.GET(DisksRoot, h.List)
+	e.GET(DisksRoot+"/", h.List)
+	e.GET(DiskRoot, h.Get)
+}
+
+// List resources in a REST collection.
+// A GET onn the collection that includes the `X-Watch`
+// header will negotiate an upgrade of the connection
+// to a websocket and push watch events.
+func (h DiskHandler) List(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	if h.WatchRequest {
+		h.watch(ctx)
+		return
+	}
+	defer func() {
+		if err != nil {
+			log.Trace(
+				err,
+				"url",
+				ctx.Request.URL)
+			base.ReplyError(ctx, http.StatusInternalServerError, err)
+		}
+	}()
+	db := h.Collector.DB()
+	list := []model.Disk{}
+	err = db.List(&list, h.ListOptions(ctx))
+	if err != nil {
+		return
+	}
+	err = h.filter(ctx, &list)
+	if err != nil {
+		return
+	}
+	pb := PathBuilder{DB: db}
+	content := []interface{}{}
+	for _, m := range list {
+		r := &Disk{}
+		r.With(&m)
+		r.Link(h.Provider)
+		r.Path = pb.Path(&m)
+		content = append(content, r.Content(h.Detail))
+	}
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+// Get a specific REST resource.
+func (h DiskHandler) Get(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	m := &model.Disk{
+		Base: model.Base{
+			ID: ctx.Param(DiskParam),
+		},
+	}
+	db := h.Collector.DB()
+	err = db.Get(m)
+	if errors.Is(err, model.NotFound) {
+		base.ReplyError(ctx, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	pb := PathBuilder{DB: db}
+	r := &Disk{}
+	r.With(m)
+	r.Link(h.Provider)
+	r.Path = pb.Path(m)
+	content := r.Content(model.MaxDetail)
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+// Watch.
+func (h *DiskHandler) watch(ctx *gin.Context) {
+	db := h.Collector.DB()
+	err := h.Watch(
+		ctx,
+		db,
+		&model.Disk{},
+		func(in libmodel.Model) (r interface{}) {
+			pb := PathBuilder{DB: db}
+			m := in.(*model.Disk)
+			storageContainer := &Disk{}
+			storageContainer.With(m)
+			storageContainer.Link(h.Provider)
+			storageContainer.Path = pb.Path(m)
+			r = storageContainer
+			return
+		})
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
+	}
+}
+
+// Filter result set.
+// Filter by path for `name` query.
+func (h *DiskHandler) filter(ctx *gin.Context, list *[]model.Disk) (err error) {
+	if len(*list) < 2 {
+		return
+	}
+	q := ctx.Request.URL.Query()
+	name := q.Get(NameParam)
+	if len(name) == 0 {
+		return
+	}
+	if len(strings.Split(name, "/")) < 2 {
+		return
+	}
+	db := h.Collector.DB()
+	pb := PathBuilder{DB: db}
+	kept := []model.Disk{}
+	for _, m := range *list {
+		path := pb.Path(&m)
+		if h.PathMatchRoot(path, name) {
+			kept = append(kept, m)
+		}
+	}
+
+	*list = kept
+
+	return
+}
+
+// REST Resource.
+type Disk struct {
+	Resource
+	ResourceID    string `json:"resourceId"`
+	ResourceGroup string `json:"resourceGroup"`
+	Location      string `json:"location"`
+	// Size (GiB).
+	Size             int64  `json:"size"`
+	SizeBytes        int64  `json:"sizeBytes"`
+	Sku              string `json:"sku"`
+	State            string `json:"state"`
+	OSType           string `json:"osType"`
+	HyperVGeneration string `json:"hyperVGeneration"`
+	VM               string `json:"vm"`
+}
+
+// Build the resource using the model.
+func (r *Disk) With(m *model.Disk) {
+	r.Resource.With(&m.Base)
+	r.ResourceID = m.ResourceID
+	r.ResourceGroup = m.ResourceGroup
+	r.Location = m.Location
+	r.Size = m.Size
+	r.SizeBytes = m.SizeBytes
+	r.Sku = m.Sku
+	r.State = m.State
+	r.OSType = m.OSType
+	r.HyperVGeneration = m.HyperVGeneration
+	r.VM = m.VM
+}
+
+// Build self link (URI).
+func (r *Disk) Link(p *api.Provider) {
+	r.SelfLink = base.Link(
+		DiskRoot,
+		base.Params{
+			base.ProviderParam: string(p.UID),
+			DiskParam:          r.ID,
+		})
+}
+
+// As content.
+func (r *Disk) Content(detail int) interface{} {
+	if detail == 0 {
+		return r.Resource
+	}
+
+	return r
+}
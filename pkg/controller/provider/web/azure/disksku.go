@@ -0,0 +1,199 @@
+package azure
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/azure"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+)
+
+// Routes.
+const (
+	DiskSkuParam      = "disksku"
+	DiskSkuCollection = "diskskus"
+	DiskSkusRoot      = ProviderRoot + "/" + DiskSkuCollection
+	DiskSkuRoot       = DiskSkusRoot + "/:" + DiskSkuParam
+)
+
+// DiskSku handler.
+type DiskSkuHandler struct {
+	Handler
+}
+
+// Add routes to the `gin` router.
+func (h *DiskSkuHandler) AddRoutes(e *gin.Engine) {
+	e.GET(DiskSkusRoot, h.List)
+	e.GET(DiskSkusRoot+"/", h.List)
+	e.GET(DiskSkuRoot, h.Get)
+}
+
+// List resources in a REST collection.
+// A GET onn the collection that includes the `X-Watch`
+// header will negotiate an upgrade of the connection
+// to a websocket and push watch events.
+func (h DiskSkuHandler) List(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	if h.WatchRequest {
+		h.watch(ctx)
+		return
+	}
+	defer func() {
+		if err != nil {
+			log.Trace(
+				err,
+				"url",
+				ctx.Request.URL)
+			base.ReplyError(ctx, http.StatusInternalServerError, err)
+		}
+	}()
+	db := h.Collector.DB()
+	list := []model.DiskSku{}
+	err = db.List(&list, h.ListOptions(ctx))
+	if err != nil {
+		return
+	}
+	err = h.filter(ctx, &list)
+	if err != nil {
+		return
+	}
+	pb := PathBuilder{DB: db}
+	content := []interface{}{}
+	for _, m := range list {
+		r := &DiskSku{}
+		r.With(&m)
+		r.Link(h.Provider)
+		r.Path = pb.Path(&m)
+		content = append(content, r.Content(h.Detail))
+	}
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+// Get a specific REST resource.
+func (h DiskSkuHandler) Get(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	m := &model.DiskSku{
+		Base: model.Base{
+			ID: ctx.Param(DiskSkuParam),
+		},
+	}
+	db := h.Collector.DB()
+	err = db.Get(m)
+	if errors.Is(err, model.NotFound) {
+		base.ReplyError(ctx, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	pb := PathBuilder{DB: db}
+	r := &DiskSku{}
+	r.With(m)
+	r.Link(h.Provider)
+	r.Path = pb.Path(m)
+	content := r.Content(model.MaxDetail)
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+// Watch.
+func (h *DiskSkuHandler) watch(ctx *gin.Context) {
+	db := h.Collector.DB()
+	err := h.Watch(
+		ctx,
+		db,
+		&model.DiskSku{},
+		func(in libmodel.Model) (r interface{}) {
+			pb := PathBuilder{DB: db}
+			m := in.(*model.DiskSku)
+			storageContainer := &DiskSku{}
+			storageContainer.With(m)
+			storageContainer.Link(h.Provider)
+			storageContainer.Path = pb.Path(m)
+			r = storageContainer
+			return
+		})
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
+	}
+}
+
+// Filter result set.
+// Filter by path for `name` query.
+func (h *DiskSkuHandler) filter(ctx *gin.Context, list *[]model.DiskSku) (err error) {
+	if len(*list) < 2 {
+		return
+	}
+	q := ctx.Request.URL.Query()
+	name := q.Get(NameParam)
+	if len(name) == 0 {
+		return
+	}
+	if len(strings.Split(name, "/")) < 2 {
+		return
+	}
+	db := h.Collector.DB()
+	pb := PathBuilder{DB: db}
+	kept := []model.DiskSku{}
+	for _, m := range *list {
+		path := pb.Path(&m)
+		if h.PathMatchRoot(path, name) {
+			kept = append(kept, m)
+		}
+	}
+
+	*list = kept
+
+	return
+}
+
+// REST Resource.
+type DiskSku struct {
+	Resource
+}
+
+// Build the resource using the model.
+func (r *DiskSku) With(m *model.DiskSku) {
+	r.Resource.With(&m.Base)
+}
+
+// Build self link (URI).
+func (r *DiskSku) Link(p *api.Provider) {
+	r.SelfLink = base.Link(
+		DiskSkuRoot,
+		base.Params{
+			base.ProviderParam: string(p.UID),
+			DiskSkuParam:       r.ID,
+		})
+}
+
+// As content.
+func (r *DiskSku) Content(detail int) interface{} {
+	if detail == 0 {
+		return r.Resource
+	}
+
+	return r
+}
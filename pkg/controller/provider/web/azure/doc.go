@@ -0,0 +1,76 @@
+package azure
+
+import (
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	"github.com/kubev2v/forklift/pkg/lib/inventory/container"
+	libweb "github.com/kubev2v/forklift/pkg/lib/inventory/web"
+)
+
+// Routes
+const (
+	Root = base.ProvidersRoot + "/" + string(api.Azure)
+)
+
+// Build all handlers.
+func Handlers(container *container.Container) []libweb.RequestHandler {
+	return []libweb.RequestHandler{
+		&ProviderHandler{
+			Handler: base.Handler{
+				Container: container,
+			},
+		},
+		&base.HealthHandler{
+			Handler: base.Handler{
+				Container: container,
+			},
+			Kind: api.Azure,
+		},
+		&base.ParityHandler{
+			Handler: base.Handler{
+				Container: container,
+			},
+			Kind: api.Azure,
+		},
+		&ResourceGroupHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
+		&SubnetHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
+		&DiskSkuHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
+		&DiskHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
+		&VMHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
+		&WorkloadHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
+		&SearchHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
+		&MapHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
+	}
+}
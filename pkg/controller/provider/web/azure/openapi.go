@@ -0,0 +1,39 @@
+package azure
+
+import (
+	"net/http"
+
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	libweb "github.com/kubev2v/forklift/pkg/lib/inventory/web"
+)
+
+// Route (OpenAPI) schemas.
+func Schemas() []libweb.RouteSchema {
+	return []libweb.RouteSchema{
+		{Method: http.MethodGet, Path: ProvidersRoot, Response: []Provider{}},
+		{Method: http.MethodGet, Path: ProviderRoot, Response: Provider{}},
+		{Method: http.MethodGet, Path: ResourceGroupsRoot, Response: []ResourceGroup{}},
+		{Method: http.MethodGet, Path: ResourceGroupRoot, Response: ResourceGroup{}},
+		{Method: http.MethodGet, Path: SubnetsRoot, Response: []Subnet{}},
+		{Method: http.MethodGet, Path: SubnetRoot, Response: Subnet{}},
+		{Method: http.MethodGet, Path: DiskSkusRoot, Response: []DiskSku{}},
+		{Method: http.MethodGet, Path: DiskSkuRoot, Response: DiskSku{}},
+		{Method: http.MethodGet, Path: DisksRoot, Response: []Disk{}},
+		{Method: http.MethodGet, Path: DiskRoot, Response: Disk{}},
+		{Method: http.MethodGet, Path: VMsRoot, Response: []VM{}},
+		{Method: http.MethodGet, Path: VMRoot, Response: VM{}},
+		{Method: http.MethodGet, Path: WorkloadRoot, Response: Workload{}},
+		{
+			Method:   http.MethodGet,
+			Path:     SearchRoot,
+			Query:    []string{base.SearchParam, base.LimitParam},
+			Response: []base.SearchHit{},
+		},
+		{
+			Method:   http.MethodPost,
+			Path:     MapsGenerateRoot,
+			Request:  base.MapsRequest{},
+			Response: base.GeneratedMaps{},
+		},
+	}
+}
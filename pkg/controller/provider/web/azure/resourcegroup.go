@@ -0,0 +1,205 @@
+package azure
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/azure"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+)
+
+// Routes.
+const (
+	ResourceGroupParam      = "resourcegroup"
+	ResourceGroupCollection = "resourcegroups"
+	ResourceGroupsRoot      = ProviderRoot + "/" + ResourceGroupCollection
+	ResourceGroupRoot       = ResourceGroupsRoot + "/:" + ResourceGroupParam
+)
+
+// ResourceGroup handler.
+type ResourceGroupHandler struct {
+	Handler
+}
+
+// Add routes to the `gin` router.
+func (h *ResourceGroupHandler) AddRoutes(e *gin.Engine) {
+	e.GET(ResourceGroupsRoot, h.List)
+	e.GET(ResourceGroupsRoot+"/", h.List)
+	e.GET(ResourceGroupRoot, h.Get)
+}
+
+// List resources in a REST collection.
+// A GET onn the collection that includes the `X-Watch`
+// header will negotiate an upgrade of the connection
+// to a websocket and push watch events.
+func (h ResourceGroupHandler) List(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	if h.WatchRequest {
+		h.watch(ctx)
+		return
+	}
+	defer func() {
+		if err != nil {
+			log.Trace(
+				err,
+				"url",
+				ctx.Request.URL)
+			base.ReplyError(ctx, http.StatusInternalServerError, err)
+		}
+	}()
+	db := h.Collector.DB()
+	list := []model.ResourceGroup{}
+	err = db.List(&list, h.ListOptions(ctx))
+	if err != nil {
+		return
+	}
+	err = h.filter(ctx, &list)
+	if err != nil {
+		return
+	}
+	pb := PathBuilder{DB: db}
+	content := []interface{}{}
+	for _, m := range list {
+		r := &ResourceGroup{}
+		r.With(&m)
+		r.Link(h.Provider)
+		r.Path = pb.Path(&m)
+		content = append(content, r.Content(h.Detail))
+	}
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+// Get a specific REST resource.
+func (h ResourceGroupHandler) Get(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	m := &model.ResourceGroup{
+		Base: model.Base{
+			ID: ctx.Param(ResourceGroupParam),
+		},
+	}
+	db := h.Collector.DB()
+	err = db.Get(m)
+	if errors.Is(err, model.NotFound) {
+		base.ReplyError(ctx, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	pb := PathBuilder{DB: db}
+	r := &ResourceGroup{}
+	r.With(m)
+	r.Link(h.Provider)
+	r.Path = pb.Path(m)
+	content := r.Content(model.MaxDetail)
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+// Watch.
+func (h *ResourceGroupHandler) watch(ctx *gin.Context) {
+	db := h.Collector.DB()
+	err := h.Watch(
+		ctx,
+		db,
+		&model.ResourceGroup{},
+		func(in libmodel.Model) (r interface{}) {
+			pb := PathBuilder{DB: db}
+			m := in.(*model.ResourceGroup)
+			storageContainer := &ResourceGroup{}
+			storageContainer.With(m)
+			storageContainer.Link(h.Provider)
+			storageContainer.Path = pb.Path(m)
+			r = storageContainer
+			return
+		})
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
+	}
+}
+
+// Filter result set.
+// Filter by path for `name` query.
+func (h *ResourceGroupHandler) filter(ctx *gin.Context, list *[]model.ResourceGroup) (err error) {
+	if len(*list) < 2 {
+		return
+	}
+	q := ctx.Request.URL.Query()
+	name := q.Get(NameParam)
+	if len(name) == 0 {
+		return
+	}
+	if len(strings.Split(name, "/")) < 2 {
+		return
+	}
+	db := h.Collector.DB()
+	pb := PathBuilder{DB: db}
+	kept := []model.ResourceGroup{}
+	for _, m := range *list {
+		path := pb.Path(&m)
+		if h.PathMatchRoot(path, name) {
+			kept = append(kept, m)
+		}
+	}
+
+	*list = kept
+
+	return
+}
+
+// REST Resource.
+type ResourceGroup struct {
+	Resource
+	ResourceID string `json:"resourceId"`
+	Location   string `json:"location"`
+	State      string `json:"state"`
+}
+
+// Build the resource using the model.
+func (r *ResourceGroup) With(m *model.ResourceGroup) {
+	r.Resource.With(&m.Base)
+	r.ResourceID = m.ResourceID
+	r.Location = m.Location
+	r.State = m.State
+}
+
+// Build self link (URI).
+func (r *ResourceGroup) Link(p *api.Provider) {
+	r.SelfLink = base.Link(
+		ResourceGroupRoot,
+		base.Params{
+			base.ProviderParam: string(p.UID),
+			ResourceGroupParam: r.ID,
+		})
+}
+
+// As content.
+func (r *ResourceGroup) Content(detail int) interface{} {
+	if detail == 0 {
+		return r.Resource
+	}
+
+	return r
+}
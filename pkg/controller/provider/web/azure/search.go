@@ -0,0 +1,104 @@
+package azure
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/azure"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+)
+
+// Routes.
+const (
+	SearchRoot = ProviderRoot + "/" + base.SearchCollection
+)
+
+// Search handler.
+type SearchHandler struct {
+	Handler
+}
+
+// Add routes to the `gin` router.
+func (h *SearchHandler) AddRoutes(e *gin.Engine) {
+	e.GET(SearchRoot, h.Search)
+}
+
+// Search the names, IDs, IPs and MACs of the provider inventory.
+func (h SearchHandler) Search(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	base.Search(ctx, h.Provider, h.entries)
+}
+
+// Searched resources.
+func (h SearchHandler) entries() (entries []base.SearchEntry, err error) {
+	db := h.Collector.DB()
+	options := model.ListOptions{Detail: model.MaxDetail}
+	subnets := []model.Subnet{}
+	err = db.List(&subnets, options)
+	if err != nil {
+		return
+	}
+	for i := range subnets {
+		m := &subnets[i]
+		r := &Subnet{}
+		r.With(m)
+		r.Link(h.Provider)
+		entries = append(
+			entries,
+			base.SearchEntry{
+				Kind:     model.SubnetKind,
+				ID:       m.ID,
+				Name:     m.Name,
+				SelfLink: r.SelfLink,
+			})
+	}
+	disks := []model.Disk{}
+	err = db.List(&disks, options)
+	if err != nil {
+		return
+	}
+	for i := range disks {
+		m := &disks[i]
+		r := &Disk{}
+		r.With(m)
+		r.Link(h.Provider)
+		entries = append(
+			entries,
+			base.SearchEntry{
+				Kind:     model.DiskKind,
+				ID:       m.ID,
+				Name:     m.Name,
+				SelfLink: r.SelfLink,
+			})
+	}
+	vms := []model.VM{}
+	err = db.List(&vms, options)
+	if err != nil {
+		return
+	}
+	for i := range vms {
+		m := &vms[i]
+		r := &VM{}
+		r.With(m)
+		r.Link(h.Provider)
+		entry := base.SearchEntry{
+			Kind:     model.VmKind,
+			ID:       m.ID,
+			Name:     m.Name,
+			SelfLink: r.SelfLink,
+		}
+		for _, nic := range m.NICs {
+			entry.IPs = append(entry.IPs, nic.IPs...)
+			if nic.MAC != "" {
+				entry.MACs = append(entry.MACs, nic.MAC)
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	return
+}
@@ -0,0 +1,257 @@
+package azure
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/azure"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+)
+
+// Routes.
+const (
+	VMParam      = "vm"
+	VMCollection = "vms"
+	VMsRoot      = ProviderRoot + "/" + VMCollection
+	VMRoot       = VMsRoot + "/:" + VMParam
+)
+
+// Virtual Machine handler.
+type VMHandler struct {
+	Handler
+}
+
+// Add routes to the `gin` router.
+func (h *VMHandler) AddRoutes(e *gin.Engine) {
+	e.GET(VMsRoot, h.List)
+	e.GET(VMsRoot+"/", h.List)
+	e.GET(VMRoot, h.Get)
+}
+
+// List resources in a REST collection.
+// A GET onn the collection that includes the `X-Watch`
+// header will negotiate an upgrade of the connection
+// to a websocket and push watch events.
+func (h VMHandler) List(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	if h.WatchRequest {
+		h.watch(ctx)
+		return
+	}
+	defer func() {
+		if err != nil {
+			log.Trace(
+				err,
+				"url",
+				ctx.Request.URL)
+			base.ReplyError(ctx, http.StatusInternalServerError, err)
+		}
+	}()
+	db := h.Collector.DB()
+	list := []model.VM{}
+	err = db.List(&list, h.ListOptions(ctx))
+	if err != nil {
+		return
+	}
+	content := []interface{}{}
+	err = h.filter(ctx, &list)
+	if err != nil {
+		return
+	}
+	pb := PathBuilder{DB: db}
+	for _, m := range list {
+		r := &VM{}
+		r.With(&m)
+		r.Link(h.Provider)
+		r.Path = pb.Path(&m)
+		content = append(content, r.Content(h.Detail))
+	}
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+// Get a specific REST resource.
+func (h VMHandler) Get(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	m := &model.VM{
+		Base: model.Base{
+			ID: ctx.Param(VMParam),
+		},
+	}
+	db := h.Collector.DB()
+	err = db.Get(m)
+	if errors.Is(err, model.NotFound) {
+		base.ReplyError(ctx, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	pb := PathBuilder{DB: db}
+	r := &VM{}
+	r.With(m)
+	r.Link(h.Provider)
+	r.Path = pb.Path(m)
+	content := r.Content(model.MaxDetail)
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+// Watch.
+func (h *VMHandler) watch(ctx *gin.Context) {
+	db := h.Collector.DB()
+	err := h.Watch(
+		ctx,
+		db,
+		&model.VM{},
+		func(in libmodel.Model) (r interface{}) {
+			pb := PathBuilder{DB: db}
+			m := in.(*model.VM)
+			vm := &VM{}
+			vm.With(m)
+			vm.Link(h.Provider)
+			vm.Path = pb.Path(m)
+			r = vm
+			return
+		})
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
+	}
+}
+
+// Filter result set.
+// Filter by path for `name` query.
+func (h *VMHandler) filter(ctx *gin.Context, list *[]model.VM) (err error) {
+	if len(*list) < 2 {
+		return
+	}
+	q := ctx.Request.URL.Query()
+	name := q.Get(NameParam)
+	if len(name) == 0 {
+		return
+	}
+	if len(strings.Split(name, "/")) < 2 {
+		return
+	}
+	db := h.Collector.DB()
+	pb := PathBuilder{DB: db}
+	kept := []model.VM{}
+	for _, m := range *list {
+		path := pb.Path(&m)
+		if h.PathMatch(path, name) {
+			kept = append(kept, m)
+		}
+	}
+
+	*list = kept
+
+	return
+}
+
+// VM detail=0
+type VM0 = Resource
+
+// VM detail=1
+type VM1 struct {
+	VM0
+	RevisionValidated int64           `json:"revisionValidated"`
+	ResourceGroup     string          `json:"resourceGroup"`
+	PowerState        string          `json:"powerState"`
+	Concerns          []model.Concern `json:"concerns"`
+}
+
+// Build the resource using the model.
+func (r *VM1) With(m *model.VM) {
+	r.VM0.With(&m.Base)
+	r.RevisionValidated = m.RevisionValidated
+	r.ResourceGroup = m.ResourceGroup
+	r.PowerState = m.PowerState
+	r.Concerns = m.Concerns
+}
+
+// As content.
+func (r *VM1) Content(detail int) interface{} {
+	if detail < 1 {
+		return &r.VM0
+	}
+
+	return r
+}
+
+// VM full detail.
+type VM struct {
+	VM1
+	PolicyVersion    int            `json:"policyVersion"`
+	ResourceID       string         `json:"resourceId"`
+	Location         string         `json:"location"`
+	VMSize           string         `json:"vmSize"`
+	OSType           string         `json:"osType"`
+	HyperVGeneration string         `json:"hyperVGeneration"`
+	SecurityType     string         `json:"securityType"`
+	SecureBoot       bool           `json:"secureBoot"`
+	TPM              bool           `json:"tpm"`
+	Architecture     string         `json:"architecture"`
+	VCPUs            int32          `json:"vcpus"`
+	MemoryMB         int64          `json:"memoryMB"`
+	Disks            []model.VMDisk `json:"disks"`
+	NICs             []model.NIC    `json:"nics"`
+}
+
+// Build the resource using the model.
+func (r *VM) With(m *model.VM) {
+	r.VM1.With(m)
+	r.PolicyVersion = m.PolicyVersion
+	r.ResourceID = m.ResourceID
+	r.Location = m.Location
+	r.VMSize = m.VMSize
+	r.OSType = m.OSType
+	r.HyperVGeneration = m.HyperVGeneration
+	r.SecurityType = m.SecurityType
+	r.SecureBoot = m.SecureBoot
+	r.TPM = m.TPM
+	r.Architecture = m.Architecture
+	r.VCPUs = m.VCPUs
+	r.MemoryMB = m.MemoryMB
+	r.Disks = m.Disks
+	r.NICs = m.NICs
+}
+
+// Build self link (URI).
+func (r *VM) Link(p *api.Provider) {
+	r.SelfLink = base.Link(
+		VMRoot,
+		base.Params{
+			base.ProviderParam: string(p.UID),
+			VMParam:            r.ID,
+		})
+}
+
+// As content.
+func (r *VM) Content(detail int) interface{} {
+	if detail < 2 {
+		return r.VM1.Content(detail)
+	}
+
+	return r
+}
@@ -0,0 +1,61 @@
+package azure
+
+import (
+	"path/filepath"
+	"testing"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/azure"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	. "github.com/onsi/gomega"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestWorkload(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	db := libmodel.New(filepath.Join(t.TempDir(), "azure.db"), model.All()...)
+	g.Expect(db.Open(true)).To(Succeed())
+	defer func() {
+		_ = db.Close(true)
+	}()
+	vm := &model.VM{
+		Base:          model.Base{ID: "vm-1", Name: "web"},
+		ResourceGroup: "group-1",
+		NICs: []model.NIC{
+			{ID: "nic-1", Subnet: "subnet-1"},
+			{ID: "nic-2", Subnet: "subnet-1"},
+			{ID: "nic-3", Subnet: "subnet-gone"},
+		},
+		Disks: []model.VMDisk{
+			{ID: "disk-1", Managed: true, OS: true},
+			{ID: "disk-2", Managed: true},
+			{Name: "unmanaged"},
+		},
+	}
+	for _, m := range []libmodel.Model{
+		&model.Subnet{Base: model.Base{ID: "subnet-1", Name: "vnet-1/default"}, ResourceGroup: "group-1"},
+		&model.DiskSku{Base: model.Base{ID: "Premium_LRS", Name: "Premium_LRS"}},
+		&model.Disk{Base: model.Base{ID: "disk-1", Name: "web-os"}, Sku: "Premium_LRS"},
+		&model.Disk{Base: model.Base{ID: "disk-2", Name: "web-data"}, Sku: "Premium_LRS"},
+		vm,
+	} {
+		g.Expect(db.Insert(m)).To(Succeed())
+	}
+
+	// Expanded.
+	workload := Workload{}
+	workload.With(vm)
+	g.Expect(workload.Expand(db)).To(Succeed())
+	workload.Link(&api.Provider{ObjectMeta: meta.ObjectMeta{UID: "p-1"}})
+	g.Expect(workload.Subnets).To(HaveLen(1))
+	g.Expect(workload.Subnets[0].SelfLink).To(ContainSubstring("p-1"))
+	g.Expect(workload.ManagedDisks).To(HaveLen(2))
+	g.Expect(workload.DiskSkus).To(HaveLen(1))
+	g.Expect(workload.SelfLink).To(HaveSuffix("/workloads/vm-1"))
+
+	// Path.
+	pb := PathBuilder{DB: db}
+	g.Expect(pb.Path(vm)).To(Equal("/group-1/web"))
+	g.Expect(pb.Path(&model.DiskSku{Base: model.Base{Name: "Premium_LRS"}})).To(Equal("/Premium_LRS"))
+}
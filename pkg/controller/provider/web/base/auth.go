@@ -12,20 +12,28 @@ import (
 	"github.com/gin-gonic/gin"
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
 	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	"github.com/kubev2v/forklift/pkg/lib/ref"
 	auth "k8s.io/api/authentication/v1"
 	auth2 "k8s.io/api/authorization/v1"
 	"k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/client/config"
 )
 
+// Pseudo verb of routes requiring only an authenticated token.
+// The content of such routes is filtered by visibility.
+const Authenticated = "authenticated"
+
 // Default auth provider.
 var DefaultAuth = Auth{
 	TTL: time.Second * 10,
 }
 
-// Authorized by k8s bearer token SAR.
-// Token must have "*" on the provider CR.
+// Authorized by k8s SAR of the bearer token or the verified client
+// certificate. The certificate subject is mapped as by the API server:
+// the CN is the user and the O are the groups. The user must have "get"
+// on the provider CR referenced by the request or "list" on provider CRs
+// in the requested namespace. Routes may require a different verb (e.g.
+// "update" for routes that change state).
 type Auth struct {
 	// k8s API writer.
 	Writer client.Writer
@@ -35,73 +43,121 @@ type Auth struct {
 	mutex sync.Mutex
 	// Token cache.
 	cache map[string]time.Time
+	// Verbs required by route.
+	routes map[string]string
+}
+
+// Require the verb on provider CRs for the route.
+func (r *Auth) Require(route, verb string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.routes == nil {
+		r.routes = make(map[string]string)
+	}
+	r.routes[r.route(route)] = verb
 }
 
 // Authenticate token.
+// Authorize the verb required by the route.
 func (r *Auth) Permit(ctx *gin.Context, p *api.Provider) (int, error) {
 	r.mutex.Lock()
-	ns := ""
 	defer r.mutex.Unlock()
+	return r.authorize(ctx, r.verb(ctx, p), p)
+}
+
+// Authenticate token.
+// Authorize the verb on the (forklift) CR. The verb is
+// required in the requested namespace when the CR has
+// no UID. Used by routes serving CRs other than providers.
+func (r *Auth) PermitObject(ctx *gin.Context, object client.Object, verb string) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.authorize(ctx, verb, object)
+}
+
+// Determine whether the provider is visible to the token.
+// The token must have "get" on the provider CR.
+func (r *Auth) Visible(ctx *gin.Context, p *api.Provider) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	status, _ := r.authorize(ctx, "get", p)
+	return status == http.StatusOK
+}
+
+// Authenticate token and authorize the verb.
+func (r *Auth) authorize(ctx *gin.Context, verb string, p client.Object) (int, error) {
+	ns := ""
 	if r.cache == nil {
 		r.cache = make(map[string]time.Time)
 	}
 	r.prune()
+	user, certified := r.certified(ctx)
 	token := r.Token(ctx)
-	if token == "" {
+	credential := token
+	if certified {
+		// A NUL cannot be in a (header) token so the keys
+		// of certificates and tokens cannot collide.
+		credential = "\x00" + user.Username + ":" + strings.Join(user.Groups, ",")
+	}
+	if credential == "" {
 		return http.StatusUnauthorized, nil
 	}
-	key := r.key(token, p)
+	if p.GetUID() == "" {
+		q := ctx.Request.URL.Query()
+		ns = q.Get(NsParam)
+	}
+	key := r.key(credential, verb, ns, p)
 	if t, found := r.cache[key]; found {
 		if time.Since(t) <= r.TTL {
 			return http.StatusOK, nil
 		}
 	}
-	if p.ObjectMeta.UID == "" {
-		q := ctx.Request.URL.Query()
-		ns = q.Get(NsParam)
+	var status int
+	var err error
+	if certified {
+		status, err = r.review(user, verb, ns, p)
+	} else {
+		status, err = r.permit(token, verb, ns, p)
 	}
-	status, err := r.permit(token, ns, p)
 	if err != nil {
-		log.Error(err, "Authorization failed.")
+		if status == http.StatusForbidden {
+			log.V(3).Info(err.Error())
+		} else {
+			log.Error(err, "Authorization failed.")
+		}
 		return status, err
 	}
 	if status == http.StatusOK {
 		r.cache[key] = time.Now()
 		return http.StatusOK, nil
 	} else {
-		delete(r.cache, token)
+		delete(r.cache, key)
 		log.Info(
 			http.StatusText(status),
 			"token",
-			token)
+			token,
+			"user",
+			user.Username)
 		return status, nil
 	}
 }
 
-// Authenticate token.
-func (r *Auth) permit(token string, ns string, p *api.Provider) (int, error) {
-	tr := &auth.TokenReview{
-		Spec: auth.TokenReviewSpec{
-			Token: token,
-		},
-	}
-	w, err := r.writer()
+// Authenticate token and authorize the verb.
+func (r *Auth) permit(token, verb, ns string, p client.Object) (int, error) {
+	user, authenticated, err := r.authenticate(token)
 	if err != nil {
-		return http.StatusInternalServerError, liberr.Wrap(err)
-	}
-	err = w.Create(context.TODO(), tr)
-	if err != nil {
-		return http.StatusInternalServerError, liberr.Wrap(err)
+		return http.StatusInternalServerError, err
 	}
-	if !tr.Status.Authenticated {
+	if !authenticated {
 		return http.StatusUnauthorized, nil
 	}
-	user := tr.Status.User
-	extra := map[string]auth2.ExtraValue{}
-	for k, v := range user.Extra {
-		extra[k] = append(
-			auth2.ExtraValue{},
-			v...)
+	return r.review(user, verb, ns, p)
+}
+
+// Authorize the verb for the user using a SAR.
+func (r *Auth) review(user auth.UserInfo, verb, ns string, p client.Object) (int, error) {
+	if verb == Authenticated {
+		return http.StatusOK, nil
 	}
 	// Users should be able to query information on providers from the inventory
 	// only if they have permissions for list/get 'providers' in the K8s API
@@ -109,13 +165,9 @@ func (r *Auth) permit(token string, ns string, p *api.Provider) (int, error) {
 	if err != nil {
 		return http.StatusInternalServerError, liberr.Wrap(err)
 	}
-	var verb, namespace string
-	if p.ObjectMeta.UID != "" {
-		verb = "get"
-		namespace = p.Namespace
-	} else {
-		verb = "list"
-		namespace = ns
+	namespace := ns
+	if p.GetUID() != "" {
+		namespace = p.GetNamespace()
 	}
 	review := &auth2.SubjectAccessReview{
 		Spec: auth2.SubjectAccessReviewSpec{
@@ -123,28 +175,144 @@ func (r *Auth) permit(token string, ns string, p *api.Provider) (int, error) {
 				Group:     gr.Group,
 				Resource:  gr.Resource,
 				Namespace: namespace,
-				Name:      p.Name,
+				Name:      p.GetName(),
 				Verb:      verb,
 			},
-			Extra:  extra,
+			Extra:  r.extra(user),
 			Groups: user.Groups,
 			User:   user.Username,
 			UID:    user.UID,
 		},
 	}
+	w, err := r.writer()
+	if err != nil {
+		return http.StatusInternalServerError, liberr.Wrap(err)
+	}
 	err = w.Create(context.TODO(), review)
 	if err != nil {
 		return http.StatusInternalServerError, liberr.Wrap(err)
 	}
 
 	if !review.Status.Allowed {
-		err = fmt.Errorf("%s is forbidden: User %q cannot %s resource %q in API group %q in the namespace %q (%v)",
-			gr, user.Username, verb, gr.Resource, gr.Group, namespace, p)
+		err = fmt.Errorf("%s is forbidden: User %q cannot %s resource %q in API group %q in the namespace %q (%s)",
+			gr, user.Username, verb, gr.Resource, gr.Group, namespace, p.GetName())
 		return http.StatusForbidden, liberr.Wrap(err)
 	}
 	return http.StatusOK, nil
 }
 
+// Subject (username) of the bearer token.
+// Empty when the token is missing or not authenticated.
+func (r *Auth) Subject(ctx *gin.Context) (subject string, err error) {
+	if user, certified := r.certified(ctx); certified {
+		subject = "cert:" + user.Username
+		return
+	}
+	token := r.Token(ctx)
+	if token == "" {
+		return
+	}
+	user, authenticated, err := r.authenticate(token)
+	if err != nil || !authenticated {
+		return
+	}
+	subject = user.Username
+	return
+}
+
+// Authorize administrators.
+// The token must have "*" on all resources cluster-wide.
+func (r *Auth) Admin(ctx *gin.Context) (int, error) {
+	token := r.Token(ctx)
+	if token == "" {
+		return http.StatusUnauthorized, nil
+	}
+	user, authenticated, err := r.authenticate(token)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if !authenticated {
+		return http.StatusUnauthorized, nil
+	}
+	review := &auth2.SubjectAccessReview{
+		Spec: auth2.SubjectAccessReviewSpec{
+			ResourceAttributes: &auth2.ResourceAttributes{
+				Group:    "*",
+				Resource: "*",
+				Verb:     "*",
+			},
+			Extra:  r.extra(user),
+			Groups: user.Groups,
+			User:   user.Username,
+			UID:    user.UID,
+		},
+	}
+	w, err := r.writer()
+	if err != nil {
+		return http.StatusInternalServerError, liberr.Wrap(err)
+	}
+	err = w.Create(context.TODO(), review)
+	if err != nil {
+		return http.StatusInternalServerError, liberr.Wrap(err)
+	}
+	if !review.Status.Allowed {
+		return http.StatusForbidden, nil
+	}
+	return http.StatusOK, nil
+}
+
+// Authenticate the token using a TokenReview.
+func (r *Auth) authenticate(token string) (user auth.UserInfo, authenticated bool, err error) {
+	tr := &auth.TokenReview{
+		Spec: auth.TokenReviewSpec{
+			Token: token,
+		},
+	}
+	w, err := r.writer()
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	err = w.Create(context.TODO(), tr)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	user = tr.Status.User
+	authenticated = tr.Status.Authenticated
+	return
+}
+
+// SAR extra of the user.
+func (r *Auth) extra(user auth.UserInfo) (extra map[string]auth2.ExtraValue) {
+	extra = map[string]auth2.ExtraValue{}
+	for k, v := range user.Extra {
+		extra[k] = append(
+			auth2.ExtraValue{},
+			v...)
+	}
+	return
+}
+
+// User of the verified client certificate.
+// Client certificates (verified against the client CA by the
+// TLS server) authenticate service-to-service calls. The CN is
+// the user and the O are the groups.
+func (r *Auth) certified(ctx *gin.Context) (user auth.UserInfo, certified bool) {
+	state := ctx.Request.TLS
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return
+	}
+	subject := state.VerifiedChains[0][0].Subject
+	if subject.CommonName == "" {
+		return
+	}
+	user.Username = subject.CommonName
+	user.Groups = append([]string{}, subject.Organization...)
+	certified = true
+	return
+}
+
 // Extract token.
 func (r *Auth) Token(ctx *gin.Context) (token string) {
 	header := ctx.GetHeader("Authorization")
@@ -166,12 +334,33 @@ func (r *Auth) prune() {
 	}
 }
 
+// Verb required for the request.
+func (r *Auth) verb(ctx *gin.Context, p *api.Provider) (verb string) {
+	if verb = r.routes[r.route(ctx.FullPath())]; verb != "" {
+		return
+	}
+	if p.ObjectMeta.UID != "" {
+		verb = "get"
+	} else {
+		verb = "list"
+	}
+	return
+}
+
+// Normalized route.
+func (r *Auth) route(route string) string {
+	return "/" + strings.Trim(route, "/")
+}
+
 // Cache key.
-func (r *Auth) key(token string, p *api.Provider) string {
+func (r *Auth) key(token, verb, ns string, p client.Object) string {
 	return path.Join(
 		token,
-		p.Namespace,
-		p.Name)
+		verb,
+		ns,
+		ref.ToKind(p),
+		p.GetNamespace(),
+		p.GetName())
 }
 
 // Build API writer.
@@ -180,7 +369,7 @@ func (r *Auth) writer() (w client.Writer, err error) {
 		w = r.Writer
 		return
 	}
-	cfg, err := config.GetConfig()
+	cfg, err := ClusterConfig()
 	if err != nil {
 		return
 	}
@@ -2,7 +2,11 @@ package base
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
 	"time"
@@ -20,6 +24,12 @@ type fakeWriter struct {
 	allowed bool
 	trCount int
 	arCount int
+	verbs   []string
+	// Users (and groups) of the SARs.
+	users  []string
+	groups [][]string
+	// Namespace in which access is denied.
+	denied string
 }
 
 func (r *fakeWriter) Create(
@@ -34,7 +44,13 @@ func (r *fakeWriter) Create(
 	}
 	if ar, cast := object.(*auth2.SubjectAccessReview); cast {
 		ar.Status.Allowed = r.allowed
+		if r.denied != "" && ar.Spec.ResourceAttributes.Namespace == r.denied {
+			ar.Status.Allowed = false
+		}
 		r.arCount++
+		r.verbs = append(r.verbs, ar.Spec.ResourceAttributes.Verb)
+		r.users = append(r.users, ar.Spec.User)
+		r.groups = append(r.groups, ar.Spec.Groups)
 		return
 	}
 
@@ -125,3 +141,137 @@ func TestAuth(t *testing.T) {
 	auth.prune()
 	g.Expect(0).To(gomega.Equal(len(auth.cache)))
 }
+
+func TestAuthRouteVerb(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	gin.SetMode(gin.TestMode)
+	writer := &fakeWriter{allowed: true}
+	auth := Auth{
+		Writer: writer,
+		TTL:    time.Minute,
+	}
+	auth.Require("/build", "update")
+	provider := &api.Provider{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "konveyor-forklift",
+			Name:      "test",
+			UID:       "uid",
+		},
+	}
+	router := gin.New()
+	permit := func(ctx *gin.Context) {
+		status, _ := auth.Permit(ctx, provider)
+		ctx.Status(status)
+	}
+	router.GET("/providers", permit)
+	router.POST("/build", permit)
+	request := func(method, route string) int {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(method, route, nil)
+		req.Header.Set("Authorization", "Bearer 12345")
+		router.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+	// Read.
+	g.Expect(request(http.MethodGet, "/providers")).To(gomega.Equal(http.StatusOK))
+	g.Expect(writer.verbs).To(gomega.Equal([]string{"get"}))
+	// Route requiring update is not satisfied by the cached read.
+	g.Expect(request(http.MethodPost, "/build")).To(gomega.Equal(http.StatusOK))
+	g.Expect(writer.verbs).To(gomega.Equal([]string{"get", "update"}))
+	// Denied.
+	writer.allowed = false
+	auth.cache = nil
+	g.Expect(request(http.MethodPost, "/build")).ToNot(gomega.Equal(http.StatusOK))
+}
+
+func TestAuthVisible(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	gin.SetMode(gin.TestMode)
+	writer := &fakeWriter{allowed: true, denied: "tenant-b"}
+	auth := Auth{
+		Writer: writer,
+		TTL:    time.Minute,
+	}
+	auth.Require("/providers/", Authenticated)
+	providers := []*api.Provider{
+		{ObjectMeta: meta.ObjectMeta{Namespace: "tenant-a", Name: "a", UID: "a"}},
+		{ObjectMeta: meta.ObjectMeta{Namespace: "tenant-b", Name: "b", UID: "b"}},
+	}
+	visible := []string{}
+	router := gin.New()
+	router.GET("/providers", func(ctx *gin.Context) {
+		status, _ := auth.Permit(ctx, &api.Provider{})
+		if status != http.StatusOK {
+			ctx.Status(status)
+			return
+		}
+		for _, p := range providers {
+			if auth.Visible(ctx, p) {
+				visible = append(visible, p.Name)
+			}
+		}
+		ctx.Status(http.StatusOK)
+	})
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/providers", nil)
+	req.Header.Set("Authorization", "Bearer 12345")
+	router.ServeHTTP(recorder, req)
+	// Listing requires authentication only and is
+	// filtered by the namespaces of the providers.
+	g.Expect(recorder.Code).To(gomega.Equal(http.StatusOK))
+	g.Expect(visible).To(gomega.Equal([]string{"a"}))
+	g.Expect(writer.verbs).To(gomega.Equal([]string{"get", "get"}))
+}
+
+func TestAuthCertificate(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	gin.SetMode(gin.TestMode)
+	writer := &fakeWriter{allowed: true}
+	auth := Auth{
+		Writer: writer,
+		TTL:    time.Minute,
+	}
+	auth.Require("/build", "update")
+	provider := &api.Provider{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "konveyor-forklift",
+			Name:      "test",
+			UID:       "uid",
+		},
+	}
+	router := gin.New()
+	router.GET("/providers", func(ctx *gin.Context) {
+		status, _ := auth.Permit(ctx, provider)
+		ctx.Status(status)
+	})
+	router.POST("/build", func(ctx *gin.Context) {
+		status, _ := auth.Permit(ctx, provider)
+		ctx.Status(status)
+	})
+	request := func(method, route string, subject pkix.Name) int {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(method, route, nil)
+		req.TLS = &tls.ConnectionState{
+			VerifiedChains: [][]*x509.Certificate{{{Subject: subject}}},
+		}
+		router.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+	subject := pkix.Name{CommonName: "forklift-ui", Organization: []string{"forklift"}}
+	// The subject is authorized by SAR.
+	g.Expect(request(http.MethodGet, "/providers", subject)).To(gomega.Equal(http.StatusOK))
+	g.Expect(writer.trCount).To(gomega.Equal(0))
+	g.Expect(writer.verbs).To(gomega.Equal([]string{"get"}))
+	g.Expect(writer.users).To(gomega.Equal([]string{"forklift-ui"}))
+	g.Expect(writer.groups).To(gomega.Equal([][]string{{"forklift"}}))
+	// Cached.
+	g.Expect(request(http.MethodGet, "/providers", subject)).To(gomega.Equal(http.StatusOK))
+	g.Expect(writer.arCount).To(gomega.Equal(1))
+	// The verb of the route is required.
+	writer.allowed = false
+	g.Expect(request(http.MethodPost, "/build", subject)).To(gomega.Equal(http.StatusForbidden))
+	g.Expect(writer.verbs).To(gomega.Equal([]string{"get", "update"}))
+	// The subject without a CN is not authenticated.
+	g.Expect(request(http.MethodGet, "/providers", pkix.Name{})).To(gomega.Equal(http.StatusUnauthorized))
+	g.Expect(writer.arCount).To(gomega.Equal(2))
+}
@@ -0,0 +1,160 @@
+package base
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	libweb "github.com/kubev2v/forklift/pkg/lib/inventory/web"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+// Client cache settings.
+var (
+	// Max age of cached clients and configs.
+	ClientTTL = time.Minute * 10
+)
+
+// Cache keys.
+const (
+	InClusterConfigKey = "config|in-cluster"
+	ClusterConfigKey   = "config|cluster"
+)
+
+// Shared client cache.
+// Used by handlers to reuse the (expensive to build)
+// API clients and configs across requests.
+var Clients = &ClientCache{}
+
+// Build the cached object.
+type ClientBuilder func() (object interface{}, err error)
+
+// Cached object.
+type cachedClient struct {
+	// The (built) object.
+	object interface{}
+	// Built.
+	built time.Time
+}
+
+// Client (and config) cache.
+// Objects are (re)built when not found or older than the TTL.
+type ClientCache struct {
+	// Max age. Default: ClientTTL.
+	TTL   time.Duration
+	mutex sync.Mutex
+	// Cached objects by key.
+	content map[string]*cachedClient
+}
+
+// Get the cached object.
+// The object is built when not found or expired.
+// Build errors are not cached.
+func (r *ClientCache) Get(key string, build ClientBuilder) (object interface{}, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.content == nil {
+		r.content = map[string]*cachedClient{}
+	}
+	r.prune()
+	if cached, found := r.content[key]; found {
+		object = cached.object
+		return
+	}
+	object, err = build()
+	if err != nil {
+		return
+	}
+	r.content[key] = &cachedClient{
+		object: object,
+		built:  time.Now(),
+	}
+
+	return
+}
+
+// Delete the cached object.
+// Used to force a rebuild when the object is found to be stale.
+func (r *ClientCache) Delete(key string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.content, key)
+}
+
+// Delete all cached objects.
+func (r *ClientCache) Reset() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.content = map[string]*cachedClient{}
+}
+
+// Prune the cache.
+// Evacuate expired objects.
+func (r *ClientCache) prune() {
+	ttl := r.TTL
+	if ttl == 0 {
+		ttl = ClientTTL
+	}
+	for key, cached := range r.content {
+		if time.Since(cached.built) > ttl {
+			delete(r.content, key)
+		}
+	}
+}
+
+// Cache key for a (bearer) token.
+// The token is hashed so it's not retained in the cache.
+func TokenKey(prefix, token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return prefix + "|" + hex.EncodeToString(sum[:])
+}
+
+// The (cached) in-cluster config.
+// The request ID is propagated by the config transport.
+// Returns a copy that may be modified by the caller.
+func InClusterConfig() (cfg *rest.Config, err error) {
+	object, err := Clients.Get(
+		InClusterConfigKey,
+		func() (object interface{}, err error) {
+			cfg, err := rest.InClusterConfig()
+			if err != nil {
+				err = liberr.Wrap(err)
+				return
+			}
+			cfg.Wrap(libweb.WrapRequestID)
+			object = cfg
+			return
+		})
+	if err != nil {
+		return
+	}
+	cfg = rest.CopyConfig(object.(*rest.Config))
+	return
+}
+
+// The (cached) cluster config.
+// Loaded using the kubeconfig (when specified) or in-cluster.
+// The request ID is propagated by the config transport.
+// Returns a copy that may be modified by the caller.
+func ClusterConfig() (cfg *rest.Config, err error) {
+	object, err := Clients.Get(
+		ClusterConfigKey,
+		func() (object interface{}, err error) {
+			cfg, err := config.GetConfig()
+			if err != nil {
+				err = liberr.Wrap(err)
+				return
+			}
+			cfg.Wrap(libweb.WrapRequestID)
+			object = cfg
+			return
+		})
+	if err != nil {
+		return
+	}
+	cfg = rest.CopyConfig(object.(*rest.Config))
+	return
+}
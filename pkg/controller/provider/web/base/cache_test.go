@@ -0,0 +1,50 @@
+package base
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+)
+
+func TestClientCache(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	built := 0
+	build := func() (interface{}, error) {
+		built++
+		return built, nil
+	}
+	cache := &ClientCache{TTL: time.Hour}
+	object, err := cache.Get("a", build)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(object).To(gomega.Equal(1))
+	object, err = cache.Get("a", build)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(object).To(gomega.Equal(1))
+	// Deleted.
+	cache.Delete("a")
+	object, _ = cache.Get("a", build)
+	g.Expect(object).To(gomega.Equal(2))
+	// Expired.
+	cache.TTL = time.Nanosecond
+	time.Sleep(time.Millisecond)
+	object, _ = cache.Get("a", build)
+	g.Expect(object).To(gomega.Equal(3))
+	// Errors not cached.
+	cache.Reset()
+	_, err = cache.Get(
+		"b",
+		func() (interface{}, error) {
+			return nil, errors.New("failed")
+		})
+	g.Expect(err).To(gomega.HaveOccurred())
+	cache.TTL = time.Hour
+	object, err = cache.Get("b", build)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(object).To(gomega.Equal(4))
+	// Token keys.
+	g.Expect(TokenKey("user", "x")).ToNot(gomega.ContainSubstring("x"))
+	g.Expect(TokenKey("user", "x")).ToNot(gomega.Equal(TokenKey("user", "y")))
+}
@@ -9,6 +9,7 @@ import (
 	liburl "net/url"
 	"os"
 	"reflect"
+	"strconv"
 	"time"
 
 	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
@@ -16,7 +17,6 @@ import (
 	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
 	libweb "github.com/kubev2v/forklift/pkg/lib/inventory/web"
 	"github.com/kubev2v/forklift/pkg/settings"
-	"sigs.k8s.io/controller-runtime/pkg/client/config"
 )
 
 // Application settings.
@@ -321,7 +321,10 @@ func (c *RestClient) buildTransport() (err error) {
 
 // Build header.
 func (c *RestClient) buildHeader() {
-	cfg, _ := config.GetConfig()
+	cfg, err := ClusterConfig()
+	if err != nil {
+		return
+	}
 	c.Header = http.Header{
 		"Authorization": []string{
 			fmt.Sprintf("Bearer %s", cfg.BearerToken),
@@ -332,10 +335,9 @@ func (c *RestClient) buildHeader() {
 // Build the URL.
 func (c *RestClient) url(path string) string {
 	if c.Host == "" {
-		c.Host = fmt.Sprintf(
-			"%s:%d",
+		c.Host = net.JoinHostPort(
 			Settings.Inventory.Host,
-			Settings.Inventory.Port)
+			strconv.Itoa(Settings.Inventory.Port))
 	}
 	path = (&Handler{}).Link(path, c.Params)
 	url, _ := liburl.Parse(path)
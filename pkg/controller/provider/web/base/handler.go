@@ -27,7 +27,7 @@ const (
 // Reply Header.
 const (
 	// Explains reason behind status code.
-	ReasonHeader = "X-Reason"
+	ReasonHeader = libweb.ReasonHeader
 	// Explains 404 caused by provider not found in
 	// inventory as opposed to the requested resource
 	// not found within the provider in the inventory.
@@ -156,6 +156,17 @@ func (h *Handler) permit(ctx *gin.Context) (status int, err error) {
 	return
 }
 
+// Determine whether the provider is visible to the caller.
+// Used to filter listed providers by the namespaces the
+// caller can access.
+func (h *Handler) Visible(ctx *gin.Context, p *api.Provider) bool {
+	if Settings.AuthRequired {
+		return DefaultAuth.Visible(ctx, p)
+	}
+
+	return true
+}
+
 // Match (compare) paths.
 // Determine if the relative path is contained
 // in the absolute path.
@@ -0,0 +1,46 @@
+package base
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/controller/provider/health"
+)
+
+// Routes.
+const (
+	HealthCollection = "health"
+)
+
+// Provider health handler.
+type HealthHandler struct {
+	Handler
+	// Provider type.
+	Kind api.ProviderType
+}
+
+// Add routes to the `gin` router.
+func (h *HealthHandler) AddRoutes(e *gin.Engine) {
+	e.GET(ProvidersRoot+"/"+string(h.Kind)+"/:"+ProviderParam+"/"+HealthCollection, h.Get)
+}
+
+// Get the health reported by the latest probe of the provider.
+func (h HealthHandler) Get(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ReplyError(ctx, status, err)
+		return
+	}
+	if h.Provider.Type() != h.Kind {
+		ReplyError(ctx, http.StatusNotFound, err)
+		return
+	}
+	probed, found := health.Providers.Get(h.Provider.UID)
+	if !found {
+		ReplyError(ctx, http.StatusNotFound, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, probed)
+}
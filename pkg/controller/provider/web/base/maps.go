@@ -0,0 +1,334 @@
+package base
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/provider"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	ocp "github.com/kubev2v/forklift/pkg/controller/provider/model/ocp"
+	libcontainer "github.com/kubev2v/forklift/pkg/lib/inventory/container"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Routes.
+const (
+	MapsGenerateRoot = "maps/generate"
+)
+
+// Network types.
+const (
+	Pod     = "pod"
+	Multus  = "multus"
+	Ignored = "ignored"
+)
+
+// Default storage class annotations.
+const (
+	AnnDefaultStorageClass     = "storageclass.kubernetes.io/is-default-class"
+	AnnDefaultVirtStorageClass = "storageclass.kubevirt.io/is-default-virt-class"
+)
+
+// Map generation request.
+type MapsRequest struct {
+	// Source VMs (by ID or name).
+	VMs []ref.Ref `json:"vms"`
+	// Destination provider. Defaults to the host provider.
+	Destination core.ObjectReference `json:"destination"`
+	// Namespace of the generated maps.
+	Namespace string `json:"namespace"`
+	// Name of the generated maps, generated when empty.
+	Name string `json:"name"`
+}
+
+// Source resources used by a VM.
+type MapSources struct {
+	Networks []ref.Ref
+	Storage  []ref.Ref
+}
+
+// Resolves the source resources used by a VM.
+type MapSourcesFunc func(vmRef ref.Ref) (sources MapSources, err error)
+
+// Generated maps.
+type GeneratedMaps struct {
+	NetworkMap *api.NetworkMap `json:"networkMap"`
+	StorageMap *api.StorageMap `json:"storageMap"`
+	// Source resources without a destination matched by name.
+	Unmatched struct {
+		Networks []ref.Ref `json:"networks"`
+		Storage  []ref.Ref `json:"storage"`
+	} `json:"unmatched"`
+}
+
+// Generates network and storage maps for a set of VMs.
+// Source networks and storage are matched by name against
+// the network attachment definitions and storage classes
+// of the destination provider.
+type MapGenerator struct {
+	// Container
+	Container *libcontainer.Container
+	// Source provider.
+	Provider *api.Provider
+}
+
+// Handle the map generation request.
+func (r *MapGenerator) Generate(ctx *gin.Context, sourcesOf MapSourcesFunc) {
+	request := MapsRequest{}
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ReplyError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if len(request.VMs) == 0 {
+		ReplyError(ctx, http.StatusBadRequest, errors.New("at least one VM is required."))
+		return
+	}
+	destination, status, err := Destination(ctx, r.Container, request.Destination)
+	if status != http.StatusOK {
+		ReplyError(ctx, status, err)
+		return
+	}
+	var networks, storage []ref.Ref
+	for _, vmRef := range request.VMs {
+		var sources MapSources
+		sources, err = sourcesOf(vmRef)
+		if err != nil {
+			if errors.As(err, &NotFoundError{}) {
+				ReplyError(ctx, http.StatusNotFound, err)
+				return
+			}
+			if errors.As(err, &RefNotUniqueError{}) {
+				ReplyError(ctx, http.StatusConflict, err)
+				return
+			}
+			log.Trace(err, "url", ctx.Request.URL)
+			ReplyError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+		networks = appendRefs(networks, sources.Networks)
+		storage = appendRefs(storage, sources.Storage)
+	}
+	generated, err := r.build(request, destination, networks, storage)
+	if err != nil {
+		log.Trace(err, "url", ctx.Request.URL)
+		ReplyError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, generated)
+}
+
+// Find the destination (OpenShift) provider collector.
+// The host provider when the reference is empty.
+func Destination(ctx *gin.Context, container *libcontainer.Container, wanted core.ObjectReference) (collector libcontainer.Collector, status int, err error) {
+	for _, candidate := range container.List() {
+		p, cast := candidate.Owner().(*api.Provider)
+		if !cast || p.Type() != api.OpenShift {
+			continue
+		}
+		if wanted.Name == "" && p.IsHost() ||
+			wanted.Name == p.Name && (wanted.Namespace == "" || wanted.Namespace == p.Namespace) {
+			collector = candidate
+			break
+		}
+	}
+	if collector == nil {
+		ctx.Header(ReasonHeader, UnknownProvider)
+		status = http.StatusNotFound
+		return
+	}
+	status = http.StatusOK
+	if Settings.AuthRequired {
+		status, err = DefaultAuth.Permit(ctx, collector.Owner().(*api.Provider))
+	}
+
+	return
+}
+
+// Build the maps.
+func (r *MapGenerator) build(request MapsRequest, destination libcontainer.Collector, networks, storage []ref.Ref) (generated GeneratedMaps, err error) {
+	db := destination.DB()
+	nads := []ocp.NetworkAttachmentDefinition{}
+	err = db.List(&nads, ocp.ListOptions{Detail: ocp.MaxDetail})
+	if err != nil {
+		return
+	}
+	storageClasses := []ocp.StorageClass{}
+	err = db.List(&storageClasses, ocp.ListOptions{Detail: ocp.MaxDetail})
+	if err != nil {
+		return
+	}
+	namespace := request.Namespace
+	if namespace == "" {
+		namespace = r.Provider.Namespace
+	}
+	objectMeta := meta.ObjectMeta{
+		Namespace: namespace,
+		Name:      request.Name,
+	}
+	if objectMeta.Name == "" {
+		objectMeta.GenerateName = r.Provider.Name + "-"
+	}
+	pair := provider.Pair{
+		Source: core.ObjectReference{
+			Namespace: r.Provider.Namespace,
+			Name:      r.Provider.Name,
+		},
+		Destination: core.ObjectReference{
+			Namespace: destination.Owner().GetNamespace(),
+			Name:      destination.Owner().GetName(),
+		},
+	}
+	generated.NetworkMap = &api.NetworkMap{
+		TypeMeta: meta.TypeMeta{
+			APIVersion: api.SchemeGroupVersion.String(),
+			Kind:       "NetworkMap",
+		},
+		ObjectMeta: objectMeta,
+		Spec: api.NetworkMapSpec{
+			Provider: pair,
+			Map:      []api.NetworkPair{},
+		},
+	}
+	generated.StorageMap = &api.StorageMap{
+		TypeMeta: meta.TypeMeta{
+			APIVersion: api.SchemeGroupVersion.String(),
+			Kind:       "StorageMap",
+		},
+		ObjectMeta: objectMeta,
+		Spec: api.StorageMapSpec{
+			Provider: pair,
+			Map:      []api.StoragePair{},
+		},
+	}
+	generated.Unmatched.Networks = []ref.Ref{}
+	generated.Unmatched.Storage = []ref.Ref{}
+	podMapped := false
+	for _, source := range networks {
+		destination := api.DestinationNetwork{Type: Ignored}
+		if nad := matchNetwork(source.Name, namespace, nads); nad != nil {
+			destination = api.DestinationNetwork{
+				Type:      Multus,
+				Namespace: nad.Namespace,
+				Name:      nad.Name,
+			}
+		} else {
+			// The pod network may be mapped only once.
+			if !podMapped {
+				destination.Type = Pod
+				podMapped = true
+			}
+			generated.Unmatched.Networks = append(generated.Unmatched.Networks, source)
+		}
+		generated.NetworkMap.Spec.Map = append(
+			generated.NetworkMap.Spec.Map,
+			api.NetworkPair{
+				Source:      source,
+				Destination: destination,
+			})
+	}
+	defaultClass := defaultStorageClass(storageClasses)
+	for _, source := range storage {
+		class := matchStorage(source.Name, storageClasses)
+		if class == "" {
+			generated.Unmatched.Storage = append(generated.Unmatched.Storage, source)
+			class = defaultClass
+		}
+		if class == "" {
+			continue
+		}
+		generated.StorageMap.Spec.Map = append(
+			generated.StorageMap.Spec.Map,
+			api.StoragePair{
+				Source: source,
+				Destination: api.DestinationStorage{
+					StorageClass: class,
+				},
+			})
+	}
+
+	return
+}
+
+// Find the network attachment definition matching the
+// source network name. Definitions in the namespace are preferred.
+func matchNetwork(name, namespace string, nads []ocp.NetworkAttachmentDefinition) (matched *ocp.NetworkAttachmentDefinition) {
+	wanted := normalizedName(name)
+	for i := range nads {
+		nad := &nads[i]
+		if normalizedName(nad.Name) != wanted {
+			continue
+		}
+		if nad.Namespace == namespace {
+			matched = nad
+			break
+		}
+		if matched == nil {
+			matched = nad
+		}
+	}
+
+	return
+}
+
+// Find the storage class matching the source storage name.
+func matchStorage(name string, storageClasses []ocp.StorageClass) (matched string) {
+	wanted := normalizedName(name)
+	for _, class := range storageClasses {
+		if normalizedName(class.Name) == wanted {
+			matched = class.Name
+			break
+		}
+	}
+
+	return
+}
+
+// Find the default storage class. The default
+// virtualization storage class is preferred.
+func defaultStorageClass(storageClasses []ocp.StorageClass) (name string) {
+	for _, class := range storageClasses {
+		annotations := class.Object.Annotations
+		if annotations[AnnDefaultVirtStorageClass] == "true" {
+			name = class.Name
+			return
+		}
+		if annotations[AnnDefaultStorageClass] == "true" {
+			name = class.Name
+		}
+	}
+
+	return
+}
+
+// Characters not valid in a DNS-1123 label.
+var notDNS1123 = regexp.MustCompile("[^a-z0-9]+")
+
+// Normalize the name for comparison.
+func normalizedName(name string) string {
+	name = notDNS1123.ReplaceAllString(strings.ToLower(name), "-")
+	return strings.Trim(name, "-")
+}
+
+// Append the refs not already listed.
+func appendRefs(list []ref.Ref, refs []ref.Ref) []ref.Ref {
+	for _, r := range refs {
+		found := false
+		for _, listed := range list {
+			if listed.ID == r.ID && listed.Name == r.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, r)
+		}
+	}
+	return list
+}
@@ -0,0 +1,45 @@
+package base
+
+import (
+	"testing"
+
+	ocp "github.com/kubev2v/forklift/pkg/controller/provider/model/ocp"
+	"github.com/onsi/gomega"
+)
+
+func TestMapMatching(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	nad := func(namespace, name string) (m ocp.NetworkAttachmentDefinition) {
+		m.Namespace = namespace
+		m.Name = name
+		return
+	}
+	nads := []ocp.NetworkAttachmentDefinition{
+		nad("other", "vm-network"),
+		nad("target", "vm-network"),
+		nad("target", "storage"),
+	}
+	matched := matchNetwork("VM Network", "target", nads)
+	g.Expect(matched).ToNot(gomega.BeNil())
+	g.Expect(matched.Namespace).To(gomega.Equal("target"))
+	matched = matchNetwork("VM_Network", "none", nads)
+	g.Expect(matched).ToNot(gomega.BeNil())
+	g.Expect(matched.Namespace).To(gomega.Equal("other"))
+	g.Expect(matchNetwork("Management", "target", nads)).To(gomega.BeNil())
+
+	class := func(name string, annotations map[string]string) (m ocp.StorageClass) {
+		m.Name = name
+		m.Object.Annotations = annotations
+		return
+	}
+	classes := []ocp.StorageClass{
+		class("nfs", nil),
+		class("ceph-rbd", map[string]string{AnnDefaultStorageClass: "true"}),
+	}
+	g.Expect(matchStorage("NFS", classes)).To(gomega.Equal("nfs"))
+	g.Expect(matchStorage("datastore1", classes)).To(gomega.BeEmpty())
+	g.Expect(defaultStorageClass(classes)).To(gomega.Equal("ceph-rbd"))
+	classes = append(classes, class("ceph-virt", map[string]string{AnnDefaultVirtStorageClass: "true"}))
+	g.Expect(defaultStorageClass(classes)).To(gomega.Equal("ceph-virt"))
+}
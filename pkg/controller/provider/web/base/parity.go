@@ -0,0 +1,87 @@
+package base
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Routes.
+const (
+	ParityCollection = "parity"
+)
+
+// Inventory parity.
+// Distinguishes an empty inventory from an inventory
+// that has not (yet) been loaded.
+type ParityStatus struct {
+	// The inventory has reached (initial) parity.
+	Parity bool `json:"parity"`
+	// Last successful update (commit) of the inventory.
+	LastUpdated *time.Time `json:"lastUpdated,omitempty"`
+	// Age (seconds) of the last successful update.
+	Age *int64 `json:"age,omitempty"`
+}
+
+// Set the parity and the (last) update.
+func (r *ParityStatus) With(parity bool, updated time.Time) {
+	r.Parity = parity
+	if updated.IsZero() {
+		return
+	}
+	updated = updated.UTC()
+	age := int64(time.Since(updated).Seconds())
+	r.LastUpdated = &updated
+	r.Age = &age
+}
+
+// Provider inventory parity handler.
+type ParityHandler struct {
+	Handler
+	// Provider type.
+	Kind api.ProviderType
+}
+
+// Add routes to the `gin` router.
+func (h *ParityHandler) AddRoutes(e *gin.Engine) {
+	e.GET(ProvidersRoot+"/"+string(h.Kind)+"/:"+ProviderParam+"/"+ParityCollection, h.Get)
+}
+
+// Get the inventory parity of the provider.
+// Replied without waiting for parity.
+func (h ParityHandler) Get(ctx *gin.Context) {
+	var found bool
+	h.Provider = &api.Provider{
+		ObjectMeta: meta.ObjectMeta{
+			UID: types.UID(ctx.Param(ProviderParam)),
+		},
+	}
+	h.Collector, found = h.Container.Get(h.Provider)
+	if !found {
+		ctx.Header(ReasonHeader, UnknownProvider)
+		ReplyError(ctx, http.StatusNotFound, nil)
+		return
+	}
+	h.Provider = h.Collector.Owner().(*api.Provider)
+	if h.Provider.Type() != h.Kind {
+		ReplyError(ctx, http.StatusNotFound, nil)
+		return
+	}
+	status, err := h.permit(ctx)
+	if status != http.StatusOK {
+		ReplyError(ctx, status, err)
+		return
+	}
+	r := ParityStatus{}
+	updated := time.Time{}
+	if db := h.Collector.DB(); db != nil {
+		updated = db.LastCommit()
+	}
+	r.With(h.Collector.HasParity(), updated)
+
+	ctx.JSON(http.StatusOK, r)
+}
@@ -0,0 +1,25 @@
+package base
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+)
+
+func TestParityStatus(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Not loaded.
+	r := ParityStatus{}
+	r.With(false, time.Time{})
+	g.Expect(r.Parity).To(gomega.BeFalse())
+	g.Expect(r.LastUpdated).To(gomega.BeNil())
+	g.Expect(r.Age).To(gomega.BeNil())
+	// Loaded.
+	r = ParityStatus{}
+	r.With(true, time.Now().Add(-time.Minute))
+	g.Expect(r.Parity).To(gomega.BeTrue())
+	g.Expect(r.LastUpdated).ToNot(gomega.BeNil())
+	g.Expect(*r.Age).To(gomega.BeNumerically(">=", 60))
+}
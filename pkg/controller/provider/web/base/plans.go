@@ -0,0 +1,205 @@
+package base
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/provider"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	libcontainer "github.com/kubev2v/forklift/pkg/lib/inventory/container"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Routes.
+const (
+	PlansGenerateRoot = "plans/generate"
+)
+
+// Default number of VMs in a wave.
+const DefaultWaveSize = 20
+
+// Selects source VMs.
+// Each (not empty) criteria must be matched.
+type VMSelector struct {
+	// Folder (ID or path prefix).
+	Folder string `json:"folder,omitempty"`
+	// Tag.
+	Tag string `json:"tag,omitempty"`
+	// Name regex.
+	Name string `json:"name,omitempty"`
+	// Cluster (ID or name).
+	Cluster string `json:"cluster,omitempty"`
+}
+
+// Plan generation request.
+type PlansRequest struct {
+	// Source VM selector.
+	Selector VMSelector `json:"selector"`
+	// Number of VMs in each plan (wave).
+	WaveSize int `json:"waveSize"`
+	// Network map.
+	NetworkMap core.ObjectReference `json:"networkMap"`
+	// Storage map.
+	StorageMap core.ObjectReference `json:"storageMap"`
+	// Destination provider. Defaults to the host provider.
+	Destination core.ObjectReference `json:"destination"`
+	// Namespace of the generated plans.
+	Namespace string `json:"namespace"`
+	// Prefix of the plan names. Defaults to the provider name.
+	Name string `json:"name"`
+	// Target namespace of the VMs.
+	TargetNamespace string `json:"targetNamespace"`
+	// Warm migration.
+	Warm bool `json:"warm"`
+}
+
+// Selector criteria not supported by the provider.
+type SelectorNotSupportedError struct {
+	Criteria string
+}
+
+func (r SelectorNotSupportedError) Error() string {
+	return fmt.Sprintf("Selector %s not supported by the provider.", r.Criteria)
+}
+
+// Selects the source VMs. The name regex is compiled
+// by the generator and nil when not specified.
+type SelectVMsFunc func(selector VMSelector, name *regexp.Regexp) (vms []ref.Ref, err error)
+
+// Generated plans.
+type GeneratedPlans struct {
+	// Plans (waves).
+	Plans []*api.Plan `json:"plans"`
+	// Number of VMs selected.
+	VMs int `json:"vms"`
+}
+
+// Generates plans for the VMs matching a selector,
+// split into waves of (at most) the wave size.
+type PlanGenerator struct {
+	// Container
+	Container *libcontainer.Container
+	// Source provider.
+	Provider *api.Provider
+}
+
+// Handle the plan generation request.
+func (r *PlanGenerator) Generate(ctx *gin.Context, selectVMs SelectVMsFunc) {
+	request := PlansRequest{}
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ReplyError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if request.WaveSize == 0 {
+		request.WaveSize = DefaultWaveSize
+	}
+	if request.WaveSize < 0 {
+		ReplyError(ctx, http.StatusBadRequest, errors.New("waveSize must be positive."))
+		return
+	}
+	if request.NetworkMap.Name == "" || request.StorageMap.Name == "" {
+		ReplyError(ctx, http.StatusBadRequest, errors.New("networkMap and storageMap are required."))
+		return
+	}
+	var name *regexp.Regexp
+	if request.Selector.Name != "" {
+		name, err = regexp.Compile(request.Selector.Name)
+		if err != nil {
+			ReplyError(ctx, http.StatusBadRequest, err)
+			return
+		}
+	}
+	destination, status, err := Destination(ctx, r.Container, request.Destination)
+	if status != http.StatusOK {
+		ReplyError(ctx, status, err)
+		return
+	}
+	vms, err := selectVMs(request.Selector, name)
+	if err != nil {
+		if errors.As(err, &SelectorNotSupportedError{}) {
+			ReplyError(ctx, http.StatusBadRequest, err)
+			return
+		}
+		log.Trace(err, "url", ctx.Request.URL)
+		ReplyError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	pair := provider.Pair{
+		Source: core.ObjectReference{
+			Namespace: r.Provider.Namespace,
+			Name:      r.Provider.Name,
+		},
+		Destination: core.ObjectReference{
+			Namespace: destination.Owner().GetNamespace(),
+			Name:      destination.Owner().GetName(),
+		},
+	}
+
+	ctx.JSON(http.StatusOK, r.build(request, pair, vms))
+}
+
+// Build the plans.
+func (r *PlanGenerator) build(request PlansRequest, pair provider.Pair, vms []ref.Ref) (generated GeneratedPlans) {
+	namespace := request.Namespace
+	if namespace == "" {
+		namespace = r.Provider.Namespace
+	}
+	prefix := request.Name
+	if prefix == "" {
+		prefix = r.Provider.Name
+	}
+	targetNamespace := request.TargetNamespace
+	if targetNamespace == "" {
+		targetNamespace = namespace
+	}
+	networkMap := request.NetworkMap
+	if networkMap.Namespace == "" {
+		networkMap.Namespace = namespace
+	}
+	storageMap := request.StorageMap
+	if storageMap.Namespace == "" {
+		storageMap.Namespace = namespace
+	}
+	generated.Plans = []*api.Plan{}
+	generated.VMs = len(vms)
+	for wave := 0; wave*request.WaveSize < len(vms); wave++ {
+		begin := wave * request.WaveSize
+		end := begin + request.WaveSize
+		if end > len(vms) {
+			end = len(vms)
+		}
+		plan := &api.Plan{
+			TypeMeta: meta.TypeMeta{
+				APIVersion: api.SchemeGroupVersion.String(),
+				Kind:       "Plan",
+			},
+			ObjectMeta: meta.ObjectMeta{
+				Namespace: namespace,
+				Name:      fmt.Sprintf("%s-wave-%d", prefix, wave+1),
+			},
+			Spec: api.PlanSpec{
+				TargetNamespace: targetNamespace,
+				Provider:        pair,
+				Map: planapi.Map{
+					Network: networkMap,
+					Storage: storageMap,
+				},
+				Warm: request.Warm,
+				VMs:  []planapi.VM{},
+			},
+		}
+		for _, vmRef := range vms[begin:end] {
+			plan.Spec.VMs = append(plan.Spec.VMs, planapi.VM{Ref: vmRef})
+		}
+		generated.Plans = append(generated.Plans, plan)
+	}
+
+	return
+}
@@ -0,0 +1,51 @@
+package base
+
+import (
+	"testing"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/provider"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPlanWaves(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	generator := PlanGenerator{
+		Provider: &api.Provider{
+			ObjectMeta: meta.ObjectMeta{Namespace: "mtv", Name: "vcenter"},
+		},
+	}
+	vms := []ref.Ref{}
+	for _, id := range []string{"vm-1", "vm-2", "vm-3", "vm-4", "vm-5"} {
+		vms = append(vms, ref.Ref{ID: id})
+	}
+	request := PlansRequest{
+		WaveSize:   2,
+		NetworkMap: core.ObjectReference{Name: "network"},
+		StorageMap: core.ObjectReference{Namespace: "maps", Name: "storage"},
+	}
+	generated := generator.build(request, provider.Pair{}, vms)
+	g.Expect(generated.VMs).To(gomega.Equal(5))
+	g.Expect(generated.Plans).To(gomega.HaveLen(3))
+	g.Expect(generated.Plans[0].Name).To(gomega.Equal("vcenter-wave-1"))
+	g.Expect(generated.Plans[2].Name).To(gomega.Equal("vcenter-wave-3"))
+	g.Expect(generated.Plans[0].Spec.VMs).To(gomega.HaveLen(2))
+	g.Expect(generated.Plans[2].Spec.VMs).To(gomega.HaveLen(1))
+	g.Expect(generated.Plans[2].Spec.VMs[0].ID).To(gomega.Equal("vm-5"))
+	g.Expect(generated.Plans[0].Namespace).To(gomega.Equal("mtv"))
+	g.Expect(generated.Plans[0].Spec.TargetNamespace).To(gomega.Equal("mtv"))
+	g.Expect(generated.Plans[0].Spec.Map.Network.Namespace).To(gomega.Equal("mtv"))
+	g.Expect(generated.Plans[0].Spec.Map.Storage.Namespace).To(gomega.Equal("maps"))
+
+	request.Name = "app"
+	request.WaveSize = 10
+	generated = generator.build(request, provider.Pair{}, vms)
+	g.Expect(generated.Plans).To(gomega.HaveLen(1))
+	g.Expect(generated.Plans[0].Name).To(gomega.Equal("app-wave-1"))
+	generated = generator.build(request, provider.Pair{}, nil)
+	g.Expect(generated.Plans).To(gomega.BeEmpty())
+}
@@ -0,0 +1,245 @@
+package base
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Routes.
+const (
+	SearchCollection = "search"
+	SearchParam      = "q"
+	LimitParam       = "limit"
+)
+
+// Matched fields.
+const (
+	SearchName = "name"
+	SearchID   = "id"
+	SearchIP   = "ip"
+	SearchMAC  = "mac"
+)
+
+// Search settings.
+var (
+	// Max age of the provider search index.
+	SearchIndexTTL = time.Second * 30
+	// Default max number of hits.
+	SearchLimit = 100
+)
+
+// Search index of the providers.
+var searchIndexes = &SearchIndexes{content: map[types.UID]*SearchIndex{}}
+
+// Search hit.
+type SearchHit struct {
+	// Resource kind.
+	Kind string `json:"kind"`
+	// Resource ID.
+	ID string `json:"id"`
+	// Resource name.
+	Name string `json:"name"`
+	// Matched field (name|id|ip|mac).
+	Field string `json:"field"`
+	// Matched value.
+	Match string `json:"match"`
+	// Resource (route) link.
+	SelfLink string `json:"selfLink"`
+}
+
+// Searched resource.
+type SearchEntry struct {
+	// Resource kind.
+	Kind string
+	// Resource ID.
+	ID string
+	// Resource name.
+	Name string
+	// Resource (route) link.
+	SelfLink string
+	// IP addresses.
+	IPs []string
+	// MAC addresses.
+	MACs []string
+}
+
+// Build the searched resources of the provider.
+type SearchIndexBuilder func() (entries []SearchEntry, err error)
+
+// Indexed term.
+type searchTerm struct {
+	// Lower-cased value.
+	value string
+	// Value.
+	original string
+	// Field.
+	field string
+	// Index of the entry.
+	entry int
+}
+
+// Search index.
+// The terms are sorted for prefix matching.
+type SearchIndex struct {
+	// Searched resources.
+	entries []SearchEntry
+	// Sorted terms.
+	terms []searchTerm
+	// Built.
+	built time.Time
+}
+
+// Build the index.
+func (r *SearchIndex) With(entries []SearchEntry) {
+	r.entries = entries
+	r.terms = []searchTerm{}
+	add := func(value, field string, entry int) {
+		if value == "" {
+			return
+		}
+		r.terms = append(
+			r.terms,
+			searchTerm{
+				value:    strings.ToLower(value),
+				original: value,
+				field:    field,
+				entry:    entry,
+			})
+	}
+	for i := range entries {
+		entry := &entries[i]
+		add(entry.Name, SearchName, i)
+		add(entry.ID, SearchID, i)
+		for _, ip := range entry.IPs {
+			add(ip, SearchIP, i)
+		}
+		for _, mac := range entry.MACs {
+			add(mac, SearchMAC, i)
+		}
+	}
+	sort.Slice(
+		r.terms,
+		func(i, j int) bool {
+			return r.terms[i].value < r.terms[j].value
+		})
+	r.built = time.Now()
+}
+
+// Search the (case-insensitive) query.
+// The prefix matches are listed before the substring matches.
+// A resource is listed once (best match).
+func (r *SearchIndex) Search(query string, limit int) (hits []SearchHit) {
+	hits = []SearchHit{}
+	query = strings.ToLower(query)
+	matched := map[int]bool{}
+	add := func(term *searchTerm) bool {
+		if matched[term.entry] {
+			return true
+		}
+		if len(hits) >= limit {
+			return false
+		}
+		matched[term.entry] = true
+		entry := &r.entries[term.entry]
+		hits = append(
+			hits,
+			SearchHit{
+				Kind:     entry.Kind,
+				ID:       entry.ID,
+				Name:     entry.Name,
+				Field:    term.field,
+				Match:    term.original,
+				SelfLink: entry.SelfLink,
+			})
+		return true
+	}
+	first := sort.Search(
+		len(r.terms),
+		func(i int) bool {
+			return r.terms[i].value >= query
+		})
+	for i := first; i < len(r.terms); i++ {
+		term := &r.terms[i]
+		if !strings.HasPrefix(term.value, query) {
+			break
+		}
+		if !add(term) {
+			return
+		}
+	}
+	for i := range r.terms {
+		term := &r.terms[i]
+		if strings.HasPrefix(term.value, query) {
+			continue
+		}
+		if strings.Contains(term.value, query) {
+			if !add(term) {
+				return
+			}
+		}
+	}
+
+	return
+}
+
+// Search indexes by provider UID.
+type SearchIndexes struct {
+	mutex   sync.Mutex
+	content map[types.UID]*SearchIndex
+}
+
+// Get the index of the provider.
+// The index is (re)built when not found or older than the TTL.
+func (r *SearchIndexes) Get(provider *api.Provider, build SearchIndexBuilder) (index *SearchIndex, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	index, found := r.content[provider.UID]
+	if found && time.Since(index.built) < SearchIndexTTL {
+		return
+	}
+	entries, err := build()
+	if err != nil {
+		return
+	}
+	index = &SearchIndex{}
+	index.With(entries)
+	r.content[provider.UID] = index
+	return
+}
+
+// Search the inventory of the provider.
+// Handles the `q` (required) and `limit` parameters.
+func Search(ctx *gin.Context, provider *api.Provider, build SearchIndexBuilder) {
+	q := ctx.Request.URL.Query()
+	query := strings.TrimSpace(q.Get(SearchParam))
+	if query == "" {
+		ReplyError(ctx, http.StatusBadRequest, errors.New("the `q` parameter is required."))
+		return
+	}
+	limit := SearchLimit
+	if s := q.Get(LimitParam); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 {
+			ReplyError(ctx, http.StatusBadRequest, errors.New("the `limit` parameter must be a positive integer."))
+			return
+		}
+		limit = n
+	}
+	index, err := searchIndexes.Get(provider, build)
+	if err != nil {
+		log.Trace(err, "url", ctx.Request.URL)
+		ReplyError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, index.Search(query, limit))
+}
@@ -0,0 +1,61 @@
+package base
+
+import (
+	"testing"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/onsi/gomega"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestSearchIndex(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	index := &SearchIndex{}
+	index.With([]SearchEntry{
+		{Kind: "VM", ID: "vm-1", Name: "web-01", IPs: []string{"10.0.0.5"}, MACs: []string{"00:50:56:AA:BB:01"}},
+		{Kind: "VM", ID: "vm-2", Name: "db-web", IPs: []string{"10.0.1.7"}},
+		{Kind: "Host", ID: "host-1", Name: "esx-01", IPs: []string{"10.0.0.1"}},
+	})
+
+	// Prefix matches listed first.
+	hits := index.Search("WEB", 10)
+	g.Expect(hits).To(gomega.HaveLen(2))
+	g.Expect(hits[0].ID).To(gomega.Equal("vm-1"))
+	g.Expect(hits[0].Field).To(gomega.Equal(SearchName))
+	g.Expect(hits[1].ID).To(gomega.Equal("vm-2"))
+	// IP and MAC.
+	hits = index.Search("10.0.0.", 10)
+	g.Expect(hits).To(gomega.HaveLen(2))
+	g.Expect(hits[0].Field).To(gomega.Equal(SearchIP))
+	hits = index.Search("aa:bb", 10)
+	g.Expect(hits).To(gomega.HaveLen(1))
+	g.Expect(hits[0].Field).To(gomega.Equal(SearchMAC))
+	g.Expect(hits[0].Kind).To(gomega.Equal("VM"))
+	// A resource is listed once.
+	hits = index.Search("01", 10)
+	g.Expect(hits).To(gomega.HaveLen(2))
+	// Limited.
+	hits = index.Search("-", 1)
+	g.Expect(hits).To(gomega.HaveLen(1))
+	// No match.
+	g.Expect(index.Search("nothing", 10)).To(gomega.BeEmpty())
+}
+
+func TestSearchIndexes(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	provider := &api.Provider{ObjectMeta: meta.ObjectMeta{UID: "search"}}
+	built := 0
+	build := func() ([]SearchEntry, error) {
+		built++
+		return []SearchEntry{{Kind: "VM", ID: "vm-1", Name: "web-01"}}, nil
+	}
+	indexes := &SearchIndexes{content: map[types.UID]*SearchIndex{}}
+	_, err := indexes.Get(provider, build)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	_, err = indexes.Get(provider, build)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(built).To(gomega.Equal(1))
+}
@@ -0,0 +1,376 @@
+package base
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	ocp "github.com/kubev2v/forklift/pkg/controller/provider/model/ocp"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	libcontainer "github.com/kubev2v/forklift/pkg/lib/inventory/container"
+	core "k8s.io/api/core/v1"
+	k8smeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Routes.
+const (
+	StorageMatrixRoot = "storage/matrix"
+	DestinationParam  = "destination"
+)
+
+// Default volume snapshot class annotation.
+const AnnDefaultSnapshotClass = "snapshot.storage.kubernetes.io/is-default-class"
+
+// Volume snapshot classes.
+var SnapshotClassList = schema.GroupVersionKind{
+	Group:   "snapshot.storage.k8s.io",
+	Version: "v1",
+	Kind:    "VolumeSnapshotClassList",
+}
+
+// Source storage (datastore, storage domain, pool, ...).
+type StorageSource struct {
+	ref.Ref
+	// Storage type reported by the provider.
+	Type string `json:"type,omitempty"`
+	// Block backed and best mapped to block volumes.
+	Block bool `json:"block"`
+}
+
+// Lists the source storage of the provider.
+type StorageSourcesFunc func() (sources []StorageSource, err error)
+
+// Capabilities of a destination storage class.
+// The volume and access modes are reported by the CDI
+// storage profile and are unknown without one.
+type StorageClassCapabilities struct {
+	Name string `json:"name"`
+	// Default (virt) storage class.
+	Default bool `json:"default"`
+	// The CDI storage profile is found.
+	Profiled bool `json:"profiled"`
+	// Block volumes are supported.
+	Block bool `json:"block"`
+	// Filesystem volumes are supported.
+	Filesystem bool `json:"filesystem"`
+	// ReadWriteMany volumes are supported.
+	ReadWriteMany bool `json:"readWriteMany"`
+	// Volume snapshot class, empty when not available.
+	SnapshotClass string `json:"snapshotClass,omitempty"`
+	// Supported claim properties.
+	claimPropertySets []cdi.ClaimPropertySet
+}
+
+// Source storage / storage class pair.
+type StorageMatch struct {
+	StorageClass string `json:"storageClass"`
+	// Names match (as when generating maps).
+	NameMatched bool `json:"nameMatched"`
+	// Volume mode best fitting the source, empty when unknown.
+	VolumeMode core.PersistentVolumeMode `json:"volumeMode,omitempty"`
+	// The volume mode fitting the source is supported.
+	VolumeModeMatched bool `json:"volumeModeMatched"`
+	// ReadWriteMany is supported in the volume mode, needed
+	// for the migrated VMs to be live migratable.
+	LiveMigratable bool `json:"liveMigratable"`
+	// A volume snapshot class is available.
+	Snapshots bool `json:"snapshots"`
+	// Best match of the source.
+	Recommended bool `json:"recommended"`
+}
+
+// Matrix row.
+type StorageMatrixRow struct {
+	Source StorageSource `json:"source"`
+	// Ordered as the storage classes.
+	Matches []StorageMatch `json:"matches"`
+}
+
+// Source storage vs destination storage class matrix.
+type StorageMatrix struct {
+	Destination    core.ObjectReference       `json:"destination"`
+	StorageClasses []StorageClassCapabilities `json:"storageClasses"`
+	Rows           []StorageMatrixRow         `json:"rows"`
+}
+
+// Volume snapshot class.
+type SnapshotClass struct {
+	Name    string
+	Driver  string
+	Default bool
+}
+
+// Handle the storage matrix request.
+// The destination is referenced by `name` or `namespace/name`
+// and defaults to the host provider.
+func (r *MapGenerator) Matrix(ctx *gin.Context, sourcesOf StorageSourcesFunc) {
+	wanted := core.ObjectReference{}
+	if key := ctx.Query(DestinationParam); key != "" {
+		wanted.Name = key
+		if namespace, name, found := strings.Cut(key, "/"); found {
+			wanted.Namespace = namespace
+			wanted.Name = name
+		}
+	}
+	destination, status, err := Destination(ctx, r.Container, wanted)
+	if status != http.StatusOK {
+		ReplyError(ctx, status, err)
+		return
+	}
+	sources, err := sourcesOf()
+	if err != nil {
+		log.Trace(err, "url", ctx.Request.URL)
+		ReplyError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	classes, err := r.capabilities(destination)
+	if err != nil {
+		log.Trace(err, "url", ctx.Request.URL)
+		ReplyError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	matrix := BuildStorageMatrix(sources, classes)
+	matrix.Destination = core.ObjectReference{
+		Namespace: destination.Owner().GetNamespace(),
+		Name:      destination.Owner().GetName(),
+	}
+
+	ctx.JSON(http.StatusOK, matrix)
+}
+
+// Capabilities of the destination storage classes.
+// The storage profiles and snapshot classes are read using the
+// destination client, when the collector provides one.
+func (r *MapGenerator) capabilities(destination libcontainer.Collector) (classes []StorageClassCapabilities, err error) {
+	storageClasses := []ocp.StorageClass{}
+	err = destination.DB().List(&storageClasses, ocp.ListOptions{Detail: ocp.MaxDetail})
+	if err != nil {
+		return
+	}
+	profiles := []cdi.StorageProfile{}
+	snapshotClasses := []SnapshotClass{}
+	if owner, cast := destination.(interface{ Client() client.Client }); cast && owner.Client() != nil {
+		profiles, err = storageProfiles(owner.Client())
+		if err != nil {
+			return
+		}
+		snapshotClasses, err = volumeSnapshotClasses(owner.Client())
+		if err != nil {
+			return
+		}
+	}
+	classes = StorageClasses(storageClasses, profiles, snapshotClasses)
+	return
+}
+
+// List the CDI storage profiles.
+// None when CDI is not installed.
+func storageProfiles(client client.Client) (profiles []cdi.StorageProfile, err error) {
+	list := &cdi.StorageProfileList{}
+	err = client.List(context.TODO(), list)
+	if err != nil {
+		if k8smeta.IsNoMatchError(err) || runtime.IsNotRegisteredError(err) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	profiles = list.Items
+	return
+}
+
+// List the volume snapshot classes.
+// None when the snapshot API is not installed.
+func volumeSnapshotClasses(client client.Client) (classes []SnapshotClass, err error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(SnapshotClassList)
+	err = client.List(context.TODO(), list)
+	if err != nil {
+		if k8smeta.IsNoMatchError(err) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	for _, item := range list.Items {
+		driver, _, _ := unstructured.NestedString(item.Object, "driver")
+		classes = append(
+			classes,
+			SnapshotClass{
+				Name:    item.GetName(),
+				Driver:  driver,
+				Default: item.GetAnnotations()[AnnDefaultSnapshotClass] == "true",
+			})
+	}
+	return
+}
+
+// Build the capabilities of the storage classes.
+func StorageClasses(storageClasses []ocp.StorageClass, profiles []cdi.StorageProfile, snapshotClasses []SnapshotClass) (classes []StorageClassCapabilities) {
+	defaultClass := defaultStorageClass(storageClasses)
+	classes = []StorageClassCapabilities{}
+	for _, storageClass := range storageClasses {
+		class := StorageClassCapabilities{
+			Name:    storageClass.Name,
+			Default: storageClass.Name == defaultClass,
+		}
+		for i := range profiles {
+			profile := &profiles[i]
+			if profile.Name != storageClass.Name {
+				continue
+			}
+			class.Profiled = true
+			class.claimPropertySets = profile.Status.ClaimPropertySets
+			if profile.Status.SnapshotClass != nil {
+				class.SnapshotClass = *profile.Status.SnapshotClass
+			}
+			break
+		}
+		for _, set := range class.claimPropertySets {
+			if volumeMode(set) == core.PersistentVolumeBlock {
+				class.Block = true
+			} else {
+				class.Filesystem = true
+			}
+			for _, accessMode := range set.AccessModes {
+				if accessMode == core.ReadWriteMany {
+					class.ReadWriteMany = true
+				}
+			}
+		}
+		if class.SnapshotClass == "" {
+			class.SnapshotClass = snapshotClass(storageClass.Object.Provisioner, snapshotClasses)
+		}
+		classes = append(classes, class)
+	}
+
+	return
+}
+
+// Build the matrix.
+func BuildStorageMatrix(sources []StorageSource, classes []StorageClassCapabilities) (matrix StorageMatrix) {
+	matrix.StorageClasses = classes
+	matrix.Rows = []StorageMatrixRow{}
+	for _, source := range sources {
+		row := StorageMatrixRow{
+			Source:  source,
+			Matches: []StorageMatch{},
+		}
+		best := -1
+		bestScore := 0
+		for _, class := range classes {
+			match := StorageMatch{
+				StorageClass: class.Name,
+				NameMatched:  normalizedName(source.Name) == normalizedName(class.Name),
+				Snapshots:    class.SnapshotClass != "",
+			}
+			if class.Profiled {
+				wanted := core.PersistentVolumeFilesystem
+				if source.Block {
+					wanted = core.PersistentVolumeBlock
+				}
+				match.VolumeModeMatched = class.supports(wanted, "")
+				match.VolumeMode = wanted
+				if !match.VolumeModeMatched {
+					match.VolumeMode = ""
+					if len(class.claimPropertySets) > 0 {
+						match.VolumeMode = volumeMode(class.claimPropertySets[0])
+					}
+				}
+				if match.VolumeMode != "" {
+					match.LiveMigratable = class.supports(match.VolumeMode, core.ReadWriteMany)
+				}
+			}
+			row.Matches = append(row.Matches, match)
+			if score := match.score(class.Default); score > bestScore {
+				best = len(row.Matches) - 1
+				bestScore = score
+			}
+		}
+		if best != -1 {
+			row.Matches[best].Recommended = true
+		}
+		matrix.Rows = append(matrix.Rows, row)
+	}
+
+	return
+}
+
+// The volume and access modes are supported.
+// Any access mode when empty.
+func (r *StorageClassCapabilities) supports(mode core.PersistentVolumeMode, accessMode core.PersistentVolumeAccessMode) bool {
+	for _, set := range r.claimPropertySets {
+		if volumeMode(set) != mode {
+			continue
+		}
+		if accessMode == "" {
+			return true
+		}
+		for _, supported := range set.AccessModes {
+			if supported == accessMode {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Score used to recommend a storage class.
+// Matched names weigh the most, then the volume
+// mode, live migration, snapshots and the default.
+func (r *StorageMatch) score(isDefault bool) (score int) {
+	score = 1
+	if r.NameMatched {
+		score += 16
+	}
+	if r.VolumeModeMatched {
+		score += 8
+	}
+	if r.LiveMigratable {
+		score += 4
+	}
+	if r.Snapshots {
+		score += 2
+	}
+	if isDefault {
+		score += 1
+	}
+	return
+}
+
+// Volume mode of the claim properties.
+// Filesystem when not specified.
+func volumeMode(set cdi.ClaimPropertySet) core.PersistentVolumeMode {
+	if set.VolumeMode == nil {
+		return core.PersistentVolumeFilesystem
+	}
+	return *set.VolumeMode
+}
+
+// Find the volume snapshot class of the provisioner.
+// The default class is preferred.
+func snapshotClass(provisioner string, snapshotClasses []SnapshotClass) (name string) {
+	for _, class := range snapshotClasses {
+		if class.Driver != provisioner {
+			continue
+		}
+		if class.Default {
+			name = class.Name
+			break
+		}
+		if name == "" {
+			name = class.Name
+		}
+	}
+
+	return
+}
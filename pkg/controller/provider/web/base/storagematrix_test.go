@@ -0,0 +1,89 @@
+package base
+
+import (
+	"testing"
+
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	ocp "github.com/kubev2v/forklift/pkg/controller/provider/model/ocp"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+)
+
+func TestStorageMatrix(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	block := core.PersistentVolumeBlock
+	class := func(name, provisioner string, annotations map[string]string) (m ocp.StorageClass) {
+		m.Name = name
+		m.Object.Provisioner = provisioner
+		m.Object.Annotations = annotations
+		return
+	}
+	profile := func(name string, sets ...cdi.ClaimPropertySet) (m cdi.StorageProfile) {
+		m.Name = name
+		m.Status.ClaimPropertySets = sets
+		return
+	}
+	classes := StorageClasses(
+		[]ocp.StorageClass{
+			class("nfs", "nfs.csi", nil),
+			class("ceph-rbd", "rbd.csi", map[string]string{AnnDefaultVirtStorageClass: "true"}),
+			class("local", "local", nil),
+		},
+		[]cdi.StorageProfile{
+			profile("nfs", cdi.ClaimPropertySet{AccessModes: []core.PersistentVolumeAccessMode{core.ReadWriteMany}}),
+			profile(
+				"ceph-rbd",
+				cdi.ClaimPropertySet{
+					AccessModes: []core.PersistentVolumeAccessMode{core.ReadWriteMany},
+					VolumeMode:  &block,
+				},
+				cdi.ClaimPropertySet{
+					AccessModes: []core.PersistentVolumeAccessMode{core.ReadWriteOnce},
+				}),
+		},
+		[]SnapshotClass{
+			{Name: "rbd-other", Driver: "rbd.csi"},
+			{Name: "rbd", Driver: "rbd.csi", Default: true},
+		})
+	g.Expect(classes).To(gomega.HaveLen(3))
+	g.Expect(classes[0].Profiled).To(gomega.BeTrue())
+	g.Expect(classes[0].Filesystem).To(gomega.BeTrue())
+	g.Expect(classes[0].Block).To(gomega.BeFalse())
+	g.Expect(classes[0].ReadWriteMany).To(gomega.BeTrue())
+	g.Expect(classes[0].SnapshotClass).To(gomega.BeEmpty())
+	g.Expect(classes[1].Default).To(gomega.BeTrue())
+	g.Expect(classes[1].Block).To(gomega.BeTrue())
+	g.Expect(classes[1].Filesystem).To(gomega.BeTrue())
+	g.Expect(classes[1].SnapshotClass).To(gomega.Equal("rbd"))
+	g.Expect(classes[2].Profiled).To(gomega.BeFalse())
+
+	matrix := BuildStorageMatrix(
+		[]StorageSource{
+			{Ref: ref.Ref{ID: "ds-1", Name: "NFS"}, Type: "NFS"},
+			{Ref: ref.Ref{ID: "ds-2", Name: "datastore1"}, Type: "VMFS", Block: true},
+		},
+		classes)
+	g.Expect(matrix.Rows).To(gomega.HaveLen(2))
+	// Name matched.
+	row := matrix.Rows[0]
+	g.Expect(row.Matches).To(gomega.HaveLen(3))
+	g.Expect(row.Matches[0].NameMatched).To(gomega.BeTrue())
+	g.Expect(row.Matches[0].VolumeMode).To(gomega.Equal(core.PersistentVolumeFilesystem))
+	g.Expect(row.Matches[0].LiveMigratable).To(gomega.BeTrue())
+	g.Expect(row.Matches[0].Recommended).To(gomega.BeTrue())
+	g.Expect(row.Matches[1].VolumeModeMatched).To(gomega.BeTrue())
+	g.Expect(row.Matches[1].LiveMigratable).To(gomega.BeFalse())
+	g.Expect(row.Matches[1].Recommended).To(gomega.BeFalse())
+	g.Expect(row.Matches[2].VolumeMode).To(gomega.BeEmpty())
+	// Block.
+	row = matrix.Rows[1]
+	g.Expect(row.Matches[0].VolumeModeMatched).To(gomega.BeFalse())
+	g.Expect(row.Matches[0].VolumeMode).To(gomega.Equal(core.PersistentVolumeFilesystem))
+	g.Expect(row.Matches[1].VolumeModeMatched).To(gomega.BeTrue())
+	g.Expect(row.Matches[1].VolumeMode).To(gomega.Equal(core.PersistentVolumeBlock))
+	g.Expect(row.Matches[1].LiveMigratable).To(gomega.BeTrue())
+	g.Expect(row.Matches[1].Snapshots).To(gomega.BeTrue())
+	g.Expect(row.Matches[1].Recommended).To(gomega.BeTrue())
+}
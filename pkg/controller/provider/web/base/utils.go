@@ -2,11 +2,20 @@ package base
 
 import (
 	"github.com/gin-gonic/gin"
+	libweb "github.com/kubev2v/forklift/pkg/lib/inventory/web"
 )
 
-func SetForkliftError(ctx *gin.Context, err error) {
+// Error message header.
+const (
+	ErrorMessageHeader = "forklift-error-message"
+)
+
+// Reply with the error object.
+// The error message is set in the header for (v1) clients
+// not reading the body.
+func ReplyError(ctx *gin.Context, status int, err error) {
 	if err != nil {
-		ctx.Header("forklift-error-message", err.Error())
-		_ = ctx.Error(err)
+		ctx.Header(ErrorMessageHeader, err.Error())
 	}
+	libweb.ReplyError(ctx, status, err)
 }
@@ -0,0 +1,76 @@
+package catalog
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/catalog"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+)
+
+// Routes.
+const (
+	MapsGenerateRoot = ProviderRoot + "/" + base.MapsGenerateRoot
+)
+
+// Map generation handler.
+type MapHandler struct {
+	Handler
+}
+
+// Add routes to the `gin` router.
+func (h *MapHandler) AddRoutes(e *gin.Engine) {
+	e.POST(MapsGenerateRoot, h.Generate)
+}
+
+// Generate network and storage maps for the VMs.
+func (h MapHandler) Generate(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	generator := base.MapGenerator{
+		Container: h.Container,
+		Provider:  h.Provider,
+	}
+	generator.Generate(ctx, h.sources)
+}
+
+// Networks and storages used by the VM.
+// Each VM uses the (single) network and storage.
+func (h MapHandler) sources(vmRef ref.Ref) (sources base.MapSources, err error) {
+	vm, err := h.findVM(h.Collector.DB(), vmRef)
+	if err != nil {
+		return
+	}
+	sources.Networks = append(sources.Networks, ref.Ref{ID: model.DefaultID, Name: model.DefaultID})
+	sources.Storage = append(sources.Storage, ref.Ref{ID: vm.Storage, Name: model.DefaultID})
+
+	return
+}
+
+// Find the VM by ID or name.
+func (h MapHandler) findVM(db libmodel.DB, vmRef ref.Ref) (vm *model.VM, err error) {
+	predicate := libmodel.Eq("id", vmRef.ID)
+	if vmRef.ID == "" {
+		predicate = libmodel.Eq(NameParam, vmRef.Name)
+	}
+	list := []model.VM{}
+	err = db.List(&list, model.ListOptions{Predicate: predicate, Detail: model.MaxDetail})
+	if err != nil {
+		return
+	}
+	switch len(list) {
+	case 0:
+		err = base.NotFoundError{Ref: vmRef}
+	case 1:
+		vm = &list[0]
+	default:
+		err = base.RefNotUniqueError{Ref: vmRef}
+	}
+
+	return
+}
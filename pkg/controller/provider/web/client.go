@@ -6,7 +6,13 @@ import (
 	"strings"
 
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/azure"
 	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/catalog"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/ec2"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/image"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/libvirt"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/nutanix"
 	"github.com/kubev2v/forklift/pkg/controller/provider/web/ocp"
 	"github.com/kubev2v/forklift/pkg/controller/provider/web/openstack"
 	"github.com/kubev2v/forklift/pkg/controller/provider/web/ova"
@@ -95,6 +101,54 @@ func NewClient(provider *api.Provider) (client Client, err error) {
 				Resolver: &ova.Resolver{Provider: provider},
 			},
 		}
+	case api.Nutanix:
+		client = &ProviderClient{
+			provider: provider,
+			finder:   &nutanix.Finder{},
+			restClient: base.RestClient{
+				Resolver: &nutanix.Resolver{Provider: provider},
+			},
+		}
+	case api.EC2:
+		client = &ProviderClient{
+			provider: provider,
+			finder:   &ec2.Finder{},
+			restClient: base.RestClient{
+				Resolver: &ec2.Resolver{Provider: provider},
+			},
+		}
+	case api.Azure:
+		client = &ProviderClient{
+			provider: provider,
+			finder:   &azure.Finder{},
+			restClient: base.RestClient{
+				Resolver: &azure.Resolver{Provider: provider},
+			},
+		}
+	case api.Libvirt:
+		client = &ProviderClient{
+			provider: provider,
+			finder:   &libvirt.Finder{},
+			restClient: base.RestClient{
+				Resolver: &libvirt.Resolver{Provider: provider},
+			},
+		}
+	case api.Image:
+		client = &ProviderClient{
+			provider: provider,
+			finder:   &image.Finder{},
+			restClient: base.RestClient{
+				Resolver: &image.Resolver{Provider: provider},
+			},
+		}
+	case api.Catalog:
+		client = &ProviderClient{
+			provider: provider,
+			finder:   &catalog.Finder{},
+			restClient: base.RestClient{
+				Resolver: &catalog.Resolver{Provider: provider},
+			},
+		}
 	default:
 		err = liberr.Wrap(
 			ProviderNotSupportedError{
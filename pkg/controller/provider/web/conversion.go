@@ -0,0 +1,150 @@
+package web
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Routes.
+const (
+	VMParam               = "vm"
+	PlanConversionLogRoot = "/plans/:" + PlanParam + "/vms/:" + VMParam + "/conversion-log"
+	FollowParam           = "follow"
+)
+
+// Conversion pod.
+const (
+	// Label of the VM (ID) on the conversion pod.
+	VMLabel = "vmID"
+	// App label.
+	AppLabel = "forklift.app"
+	// App of the conversion pod.
+	ConversionApp = "virt-v2v"
+	// Container running virt-v2v.
+	ConversionContainer = "virt-v2v"
+	// Size of the buffer used to stream the log.
+	ConversionLogBuffer = 4096
+)
+
+// Conversion log handler.
+// Streams the (virt-v2v) log of the guest conversion pod of a plan VM.
+type ConversionLogHandler struct {
+	// Reader of the plan and the conversion pods.
+	Client client.Reader
+	// Clientset used to stream the pod log.
+	Clientset kubernetes.Interface
+}
+
+// Add routes to the `gin` router.
+func (h *ConversionLogHandler) AddRoutes(e *gin.Engine) {
+	e.GET(PlanConversionLogRoot, h.Get)
+}
+
+// Stream the conversion log of the VM.
+// The log is followed until the pod terminates unless
+// `follow=false` is requested.
+func (h ConversionLogHandler) Get(ctx *gin.Context) {
+	status, err := base.DefaultAuth.Admin(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	namespace := ctx.Query(NamespaceParam)
+	if namespace == "" {
+		base.ReplyError(ctx, http.StatusBadRequest, liberr.New("the `namespace` parameter is required."))
+		return
+	}
+	plan := &api.Plan{}
+	err = h.Client.Get(
+		context.TODO(),
+		client.ObjectKey{Namespace: namespace, Name: ctx.Param(PlanParam)},
+		plan)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			base.ReplyError(ctx, http.StatusNotFound, err)
+			return
+		}
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	pod, err := h.conversionPod(plan, ctx.Param(VMParam))
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if pod == nil {
+		base.ReplyError(ctx, http.StatusNotFound, err)
+		return
+	}
+	request := h.Clientset.CoreV1().Pods(pod.Namespace).GetLogs(
+		pod.Name,
+		&core.PodLogOptions{
+			Container: ConversionContainer,
+			Follow:    ctx.Query(FollowParam) != "false",
+		})
+	stream, err := request.Stream(ctx.Request.Context())
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	defer stream.Close()
+	ctx.Header("Content-Type", "text/plain; charset=utf-8")
+	ctx.Status(http.StatusOK)
+	buffer := make([]byte, ConversionLogBuffer)
+	ctx.Stream(func(w io.Writer) bool {
+		n, err := stream.Read(buffer)
+		if n > 0 {
+			_, wErr := w.Write(buffer[:n])
+			if wErr != nil {
+				return false
+			}
+		}
+		return err == nil
+	})
+}
+
+// Find the (most recent) conversion pod of the VM.
+func (h *ConversionLogHandler) conversionPod(plan *api.Plan, vmID string) (pod *core.Pod, err error) {
+	list := &core.PodList{}
+	err = h.Client.List(
+		context.TODO(),
+		list,
+		client.InNamespace(plan.Spec.TargetNamespace),
+		client.MatchingLabels{
+			PlanLabel: string(plan.UID),
+			VMLabel:   vmID,
+			AppLabel:  ConversionApp,
+		})
+	if err != nil {
+		return
+	}
+	for i := range list.Items {
+		candidate := &list.Items[i]
+		if pod == nil || pod.CreationTimestamp.Before(&candidate.CreationTimestamp) {
+			pod = candidate
+		}
+	}
+	return
+}
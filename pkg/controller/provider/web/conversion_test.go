@@ -0,0 +1,55 @@
+package web
+
+import (
+	"testing"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeClient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestConversionPod(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	p := &api.Plan{ObjectMeta: meta.ObjectMeta{Namespace: "ns", Name: "plan", UID: "p1"}}
+	p.Spec.TargetNamespace = "target"
+	pod := func(name, vmID string, created time.Time) *core.Pod {
+		return &core.Pod{ObjectMeta: meta.ObjectMeta{
+			Namespace:         "target",
+			Name:              name,
+			CreationTimestamp: meta.NewTime(created),
+			Labels: map[string]string{
+				PlanLabel: "p1",
+				VMLabel:   vmID,
+				AppLabel:  ConversionApp,
+			},
+		}}
+	}
+	now := time.Now()
+	scheme := runtime.NewScheme()
+	_ = core.AddToScheme(scheme)
+	_ = api.SchemeBuilder.AddToScheme(scheme)
+	handler := &ConversionLogHandler{
+		Client: fakeClient.NewClientBuilder().
+			WithScheme(scheme).
+			WithRuntimeObjects(
+				p,
+				pod("plan-vm-1-old", "vm-1", now.Add(-time.Hour)),
+				pod("plan-vm-1-new", "vm-1", now),
+				pod("plan-vm-2", "vm-2", now)).
+			Build(),
+	}
+
+	found, err := handler.conversionPod(p, "vm-1")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(found).ToNot(gomega.BeNil())
+	g.Expect(found.Name).To(gomega.Equal("plan-vm-1-new"))
+
+	found, err = handler.conversionPod(p, "vm-3")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(found).To(gomega.BeNil())
+}
@@ -0,0 +1,344 @@
+package web
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// Routes.
+const (
+	PlanParam           = "plan"
+	PlanDiagnosticsRoot = "/plans/:" + PlanParam + "/diagnostics"
+	NamespaceParam      = "namespace"
+)
+
+// Diagnostics.
+const (
+	// Container of the controller.
+	ControllerContainer = "main"
+	// Controller log lines searched for the plan.
+	ControllerLogLines = int64(20000)
+	// Log lines of the migration pods.
+	PodLogLines = int64(2000)
+	// Label of the plan (UID) on the migration pods and PVCs.
+	PlanLabel = "plan"
+	// CDI importer pod prefix.
+	ImporterPodPrefix = "importer-"
+	// Volume populator pod prefix.
+	PopulatorPodPrefix = "populate-"
+)
+
+// Find a VM in the provider inventory.
+type FindVMFunc func(provider *api.Provider, vmRef ref.Ref) (interface{}, error)
+
+// Diagnostics handler.
+// Bundles the plan and migration CRs, the controller log lines
+// mentioning the plan, the logs of the migration (conversion,
+// importer and populator) pods and the inventory of the plan VMs
+// into a tar.gz to be attached to support cases.
+type DiagnosticsHandler struct {
+	// Reader of the plan and related resources.
+	Client client.Reader
+	// Clientset used to read the pod logs.
+	Clientset kubernetes.Interface
+	// Find inventory VMs. Defaults to the provider API client.
+	FindVM FindVMFunc
+	// Namespace of the controller (and inventory) pod.
+	Namespace string
+}
+
+// Add routes to the `gin` router.
+func (h *DiagnosticsHandler) AddRoutes(e *gin.Engine) {
+	e.GET(PlanDiagnosticsRoot, h.Get)
+}
+
+// Get the diagnostics bundle of the plan.
+func (h DiagnosticsHandler) Get(ctx *gin.Context) {
+	status, err := base.DefaultAuth.Admin(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	namespace := ctx.Query(NamespaceParam)
+	if namespace == "" {
+		base.ReplyError(ctx, http.StatusBadRequest, liberr.New("the `namespace` parameter is required."))
+		return
+	}
+	plan := &api.Plan{}
+	err = h.Client.Get(
+		context.TODO(),
+		client.ObjectKey{Namespace: namespace, Name: ctx.Param(PlanParam)},
+		plan)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			base.ReplyError(ctx, http.StatusNotFound, err)
+			return
+		}
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	content, err := h.Bundle(plan)
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	ctx.Header(
+		"Content-Disposition",
+		fmt.Sprintf("attachment; filename=%s-%s-diagnostics.tar.gz", plan.Namespace, plan.Name))
+	ctx.Data(http.StatusOK, "application/gzip", content)
+}
+
+// Build the (tar.gz) diagnostics bundle.
+// Collection is best-effort: failures are reported in `errors.txt`
+// instead of failing the bundle.
+func (h *DiagnosticsHandler) Bundle(plan *api.Plan) (content []byte, err error) {
+	buffer := &bytes.Buffer{}
+	gz := gzip.NewWriter(buffer)
+	archive := &diagnostics{writer: tar.NewWriter(gz)}
+	archive.addYaml("plan.yaml", plan)
+	h.addMigrations(archive, plan)
+	h.addControllerLog(archive, plan)
+	h.addPodLogs(archive, plan)
+	h.addInventory(archive, plan)
+	if len(archive.errors) > 0 {
+		archive.add("errors.txt", []byte(strings.Join(archive.errors, "\n")+"\n"))
+	}
+	err = archive.writer.Close()
+	if err == nil {
+		err = gz.Close()
+	}
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	content = buffer.Bytes()
+	return
+}
+
+// Add the migrations of the plan.
+func (h *DiagnosticsHandler) addMigrations(archive *diagnostics, plan *api.Plan) {
+	list := &api.MigrationList{}
+	err := h.Client.List(context.TODO(), list, client.InNamespace(plan.Namespace))
+	if err != nil {
+		archive.failed("migrations", err)
+		return
+	}
+	for i := range list.Items {
+		migration := &list.Items[i]
+		if migration.Match(plan) {
+			archive.addYaml(path.Join("migrations", migration.Name+".yaml"), migration)
+		}
+	}
+}
+
+// Add the controller log lines mentioning the plan.
+// The controller runs in the same pod as the inventory.
+func (h *DiagnosticsHandler) addControllerLog(archive *diagnostics, plan *api.Plan) {
+	pod, err := os.Hostname()
+	if err != nil {
+		archive.failed("controller log", err)
+		return
+	}
+	content, err := h.podLog(h.Namespace, pod, ControllerContainer, ControllerLogLines)
+	if err != nil {
+		archive.failed("controller log", err)
+		return
+	}
+	archive.add(path.Join("logs", "controller.log"), PlanLines(content, plan))
+}
+
+// Add the logs of the migration pods in the target namespace.
+func (h *DiagnosticsHandler) addPodLogs(archive *diagnostics, plan *api.Plan) {
+	namespace := plan.Spec.TargetNamespace
+	pvcList := &core.PersistentVolumeClaimList{}
+	err := h.Client.List(
+		context.TODO(),
+		pvcList,
+		client.InNamespace(namespace),
+		client.MatchingLabels{PlanLabel: string(plan.UID)})
+	if err != nil {
+		archive.failed("pvcs", err)
+		return
+	}
+	podList := &core.PodList{}
+	err = h.Client.List(context.TODO(), podList, client.InNamespace(namespace))
+	if err != nil {
+		archive.failed("pods", err)
+		return
+	}
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if !MigrationPod(pod, plan, pvcList.Items) {
+			continue
+		}
+		for _, container := range pod.Spec.Containers {
+			content, err := h.podLog(pod.Namespace, pod.Name, container.Name, PodLogLines)
+			if err != nil {
+				archive.failed(path.Join(pod.Name, container.Name), err)
+				continue
+			}
+			archive.add(path.Join("logs", pod.Namespace, pod.Name, container.Name+".log"), content)
+		}
+	}
+}
+
+// Add the source inventory of the plan VMs.
+func (h *DiagnosticsHandler) addInventory(archive *diagnostics, plan *api.Plan) {
+	provider := &api.Provider{}
+	err := h.Client.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: plan.Spec.Provider.Source.Namespace,
+			Name:      plan.Spec.Provider.Source.Name,
+		},
+		provider)
+	if err != nil {
+		archive.failed("source provider", err)
+		return
+	}
+	find := h.FindVM
+	if find == nil {
+		find = findVM
+	}
+	for _, vm := range plan.Spec.VMs {
+		model, err := find(provider, vm.Ref)
+		if err != nil {
+			archive.failed(path.Join("vm", vm.String()), err)
+			continue
+		}
+		name := vm.ID
+		if name == "" {
+			name = vm.Name
+		}
+		archive.addJson(path.Join("inventory", "vms", name+".json"), model)
+	}
+}
+
+// Read the tail of a container log.
+func (h *DiagnosticsHandler) podLog(namespace, name, container string, lines int64) (content []byte, err error) {
+	request := h.Clientset.CoreV1().Pods(namespace).GetLogs(
+		name,
+		&core.PodLogOptions{
+			Container: container,
+			TailLines: &lines,
+		})
+	content, err = request.DoRaw(context.TODO())
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+	return
+}
+
+// Find the VM using the provider API client.
+func findVM(provider *api.Provider, vmRef ref.Ref) (model interface{}, err error) {
+	pClient, err := NewClient(provider)
+	if err != nil {
+		return
+	}
+	model, err = pClient.VM(&vmRef)
+	return
+}
+
+// Determine whether the pod belongs to the plan migration.
+// Conversion pods are labeled with the plan and the importer
+// and populator pods are named after the plan PVCs.
+func MigrationPod(pod *core.Pod, plan *api.Plan, pvcs []core.PersistentVolumeClaim) bool {
+	if pod.Labels[PlanLabel] == string(plan.UID) {
+		return true
+	}
+	for i := range pvcs {
+		pvc := &pvcs[i]
+		if pod.Name == ImporterPodPrefix+pvc.Name || pod.Name == PopulatorPodPrefix+string(pvc.UID) {
+			return true
+		}
+	}
+	return false
+}
+
+// Select the log lines mentioning the plan (by quoted name or UID).
+func PlanLines(content []byte, plan *api.Plan) []byte {
+	name := []byte(`"` + plan.Name + `"`)
+	uid := []byte(plan.UID)
+	selected := &bytes.Buffer{}
+	for _, line := range bytes.SplitAfter(content, []byte("\n")) {
+		if bytes.Contains(line, name) || (len(uid) > 0 && bytes.Contains(line, uid)) {
+			selected.Write(line)
+		}
+	}
+	return selected.Bytes()
+}
+
+// Diagnostics archive.
+type diagnostics struct {
+	writer *tar.Writer
+	errors []string
+}
+
+// Add a file.
+func (r *diagnostics) add(name string, content []byte) {
+	err := r.writer.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(content)),
+		ModTime: time.Now(),
+	})
+	if err == nil {
+		_, err = r.writer.Write(content)
+	}
+	if err != nil {
+		r.failed(name, err)
+	}
+}
+
+// Add an object as YAML.
+func (r *diagnostics) addYaml(name string, object interface{}) {
+	content, err := yaml.Marshal(object)
+	if err != nil {
+		r.failed(name, err)
+		return
+	}
+	r.add(name, content)
+}
+
+// Add an object as JSON.
+func (r *diagnostics) addJson(name string, object interface{}) {
+	content, err := json.MarshalIndent(object, "", "  ")
+	if err != nil {
+		r.failed(name, err)
+		return
+	}
+	r.add(name, content)
+}
+
+// Report a collection failure.
+func (r *diagnostics) failed(what string, err error) {
+	r.errors = append(r.errors, fmt.Sprintf("%s: %s", what, err.Error()))
+}
@@ -0,0 +1,112 @@
+package web
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"testing"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeClientset "k8s.io/client-go/kubernetes/fake"
+	fakeClient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDiagnosticsBundle(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	p := &api.Plan{ObjectMeta: meta.ObjectMeta{Namespace: "ns", Name: "plan", UID: "p1"}}
+	p.Spec.TargetNamespace = "target"
+	p.Spec.Provider.Source = core.ObjectReference{Namespace: "ns", Name: "vcenter"}
+	p.Spec.VMs = []plan.VM{
+		{Ref: ref.Ref{ID: "vm-1"}},
+		{Ref: ref.Ref{ID: "vm-2"}},
+	}
+	migration := &api.Migration{ObjectMeta: meta.ObjectMeta{Namespace: "ns", Name: "m1"}}
+	migration.Spec.Plan = core.ObjectReference{Namespace: "ns", Name: "plan"}
+	other := &api.Migration{ObjectMeta: meta.ObjectMeta{Namespace: "ns", Name: "m2"}}
+	other.Spec.Plan = core.ObjectReference{Namespace: "ns", Name: "other"}
+	pvc := &core.PersistentVolumeClaim{ObjectMeta: meta.ObjectMeta{
+		Namespace: "target",
+		Name:      "disk",
+		UID:       "d1",
+		Labels:    map[string]string{PlanLabel: "p1"},
+	}}
+	pod := func(name string, labels map[string]string) *core.Pod {
+		return &core.Pod{
+			ObjectMeta: meta.ObjectMeta{Namespace: "target", Name: name, Labels: labels},
+			Spec:       core.PodSpec{Containers: []core.Container{{Name: "main"}}},
+		}
+	}
+	scheme := runtime.NewScheme()
+	_ = core.AddToScheme(scheme)
+	_ = api.SchemeBuilder.AddToScheme(scheme)
+	handler := &DiagnosticsHandler{
+		Client: fakeClient.NewClientBuilder().
+			WithScheme(scheme).
+			WithRuntimeObjects(
+				p,
+				migration,
+				other,
+				pvc,
+				&api.Provider{ObjectMeta: meta.ObjectMeta{Namespace: "ns", Name: "vcenter"}},
+				pod("plan-vm-1-abc", map[string]string{PlanLabel: "p1"}),
+				pod("importer-disk", nil),
+				pod("populate-d1", nil),
+				pod("unrelated", nil)).
+			Build(),
+		Clientset: fakeClientset.NewSimpleClientset(),
+		FindVM: func(provider *api.Provider, vmRef ref.Ref) (interface{}, error) {
+			if vmRef.ID == "vm-2" {
+				return nil, errors.New("not found")
+			}
+			return map[string]string{"id": vmRef.ID}, nil
+		},
+		Namespace: "konveyor-forklift",
+	}
+
+	content, err := handler.Bundle(p)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	gz, err := gzip.NewReader(bytes.NewReader(content))
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	files := map[string]string{}
+	reader := tar.NewReader(gz)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		b, _ := io.ReadAll(reader)
+		files[header.Name] = string(b)
+	}
+	g.Expect(files).To(gomega.HaveKey("plan.yaml"))
+	g.Expect(files).To(gomega.HaveKey("migrations/m1.yaml"))
+	g.Expect(files).ToNot(gomega.HaveKey("migrations/m2.yaml"))
+	g.Expect(files).To(gomega.HaveKey("logs/target/plan-vm-1-abc/main.log"))
+	g.Expect(files).To(gomega.HaveKey("logs/target/importer-disk/main.log"))
+	g.Expect(files).To(gomega.HaveKey("logs/target/populate-d1/main.log"))
+	g.Expect(files).ToNot(gomega.HaveKey("logs/target/unrelated/main.log"))
+	g.Expect(files).To(gomega.HaveKey("inventory/vms/vm-1.json"))
+	g.Expect(files["errors.txt"]).To(gomega.ContainSubstring("vm-2"))
+}
+
+func TestPlanLines(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	p := &api.Plan{ObjectMeta: meta.ObjectMeta{Name: "web", UID: "p1"}}
+	content := []byte(
+		`{"msg":"a","plan":{"name":"web"}}` + "\n" +
+			`{"msg":"b","plan":{"name":"webserver"}}` + "\n" +
+			`{"msg":"c","uid":"p1"}` + "\n")
+	g.Expect(string(PlanLines(content, p))).To(gomega.Equal(
+		`{"msg":"a","plan":{"name":"web"}}` + "\n" +
+			`{"msg":"c","uid":"p1"}` + "\n"))
+}
@@ -1,7 +1,13 @@
 package web
 
 import (
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/azure"
 	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/catalog"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/ec2"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/image"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/libvirt"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/nutanix"
 	"github.com/kubev2v/forklift/pkg/controller/provider/web/ocp"
 	"github.com/kubev2v/forklift/pkg/controller/provider/web/openstack"
 	"github.com/kubev2v/forklift/pkg/controller/provider/web/ova"
@@ -15,11 +21,26 @@ import (
 func All(container *container.Container) (all []libweb.RequestHandler) {
 	all = []libweb.RequestHandler{
 		&libweb.SchemaHandler{},
+		&libweb.OpenAPIHandler{
+			Title:   APITitle,
+			Version: APIVersion,
+			Schemas: Schemas(),
+		},
+		&HealthHandler{
+			Handler: base.Handler{
+				Container: container,
+			},
+		},
 		&ProviderHandler{
 			Handler: base.Handler{
 				Container: container,
 			},
 		},
+		&SnapshotHandler{
+			Handler: base.Handler{
+				Container: container,
+			},
+		},
 	}
 	all = append(
 		all,
@@ -36,5 +57,23 @@ func All(container *container.Container) (all []libweb.RequestHandler) {
 	all = append(
 		all,
 		ova.Handlers(container)...)
+	all = append(
+		all,
+		nutanix.Handlers(container)...)
+	all = append(
+		all,
+		ec2.Handlers(container)...)
+	all = append(
+		all,
+		azure.Handlers(container)...)
+	all = append(
+		all,
+		libvirt.Handlers(container)...)
+	all = append(
+		all,
+		image.Handlers(container)...)
+	all = append(
+		all,
+		catalog.Handlers(container)...)
 	return
 }
@@ -0,0 +1,76 @@
+package ec2
+
+import (
+	"strings"
+
+	pathlib "path"
+
+	"github.com/gin-gonic/gin"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/ec2"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+)
+
+// Package logger.
+var log = logging.WithName("web|ec2")
+
+// Fields.
+const (
+	DetailParam = base.DetailParam
+	NameParam   = base.NameParam
+)
+
+// Base handler.
+type Handler struct {
+	base.Handler
+}
+
+// Build list predicate.
+func (h Handler) Predicate(ctx *gin.Context) (p libmodel.Predicate) {
+	q := ctx.Request.URL.Query()
+	name := q.Get(NameParam)
+	if len(name) > 0 {
+		path := strings.Split(name, "/")
+		name := path[len(path)-1]
+		p = libmodel.Eq(NameParam, name)
+	}
+
+	return
+}
+
+// Build list options.
+func (h Handler) ListOptions(ctx *gin.Context) libmodel.ListOptions {
+	detail := h.Detail
+	if detail > 0 {
+		detail = model.MaxDetail
+	}
+	return libmodel.ListOptions{
+		Predicate: h.Predicate(ctx),
+		Detail:    detail,
+		Page:      &h.Page,
+	}
+}
+
+// Path builder.
+// Subnets and instances are prefixed with the VPC.
+type PathBuilder struct {
+	// Database.
+	DB libmodel.DB
+}
+
+// Build the path.
+func (r *PathBuilder) Path(m model.Model) (path string) {
+	switch m := m.(type) {
+	case *model.Subnet:
+		path = pathlib.Join("/", m.VPC, m.Name)
+	case *model.VolumeType:
+		path = pathlib.Join("/", m.Name)
+	case *model.Volume:
+		path = pathlib.Join("/", m.Name)
+	case *model.VM:
+		path = pathlib.Join("/", m.VPC, m.Name)
+	}
+
+	return
+}
@@ -0,0 +1,71 @@
+package ec2
+
+import (
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	"github.com/kubev2v/forklift/pkg/lib/inventory/container"
+	libweb "github.com/kubev2v/forklift/pkg/lib/inventory/web"
+)
+
+// Routes
+const (
+	Root = base.ProvidersRoot + "/" + string(api.EC2)
+)
+
+// Build all handlers.
+func Handlers(container *container.Container) []libweb.RequestHandler {
+	return []libweb.RequestHandler{
+		&ProviderHandler{
+			Handler: base.Handler{
+				Container: container,
+			},
+		},
+		&base.HealthHandler{
+			Handler: base.Handler{
+				Container: container,
+			},
+			Kind: api.EC2,
+		},
+		&base.ParityHandler{
+			Handler: base.Handler{
+				Container: container,
+			},
+			Kind: api.EC2,
+		},
+		&SubnetHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
+		&VolumeTypeHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
+		&VolumeHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
+		&VMHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
+		&WorkloadHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
+		&SearchHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
+		&MapHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
+	}
+}
@@ -0,0 +1,37 @@
+package ec2
+
+import (
+	"net/http"
+
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	libweb "github.com/kubev2v/forklift/pkg/lib/inventory/web"
+)
+
+// Route (OpenAPI) schemas.
+func Schemas() []libweb.RouteSchema {
+	return []libweb.RouteSchema{
+		{Method: http.MethodGet, Path: ProvidersRoot, Response: []Provider{}},
+		{Method: http.MethodGet, Path: ProviderRoot, Response: Provider{}},
+		{Method: http.MethodGet, Path: SubnetsRoot, Response: []Subnet{}},
+		{Method: http.MethodGet, Path: SubnetRoot, Response: Subnet{}},
+		{Method: http.MethodGet, Path: VolumeTypesRoot, Response: []VolumeType{}},
+		{Method: http.MethodGet, Path: VolumeTypeRoot, Response: VolumeType{}},
+		{Method: http.MethodGet, Path: VolumesRoot, Response: []Volume{}},
+		{Method: http.MethodGet, Path: VolumeRoot, Response: Volume{}},
+		{Method: http.MethodGet, Path: VMsRoot, Response: []VM{}},
+		{Method: http.MethodGet, Path: VMRoot, Response: VM{}},
+		{Method: http.MethodGet, Path: WorkloadRoot, Response: Workload{}},
+		{
+			Method:   http.MethodGet,
+			Path:     SearchRoot,
+			Query:    []string{base.SearchParam, base.LimitParam},
+			Response: []base.SearchHit{},
+		},
+		{
+			Method:   http.MethodPost,
+			Path:     MapsGenerateRoot,
+			Request:  base.MapsRequest{},
+			Response: base.GeneratedMaps{},
+		},
+	}
+}
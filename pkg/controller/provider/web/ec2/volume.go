@@ -0,0 +1,212 @@
+package ec2
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/ec2"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+)
+
+// Routes.
+const (
+	VolumeParam      = "volume"
+	VolumeCollection = "volumes"
+	VolumesRoot      = ProviderRoot + "/" + VolumeCollection
+	VolumeRoot       = VolumesRoot + "/:" + VolumeParam
+)
+
+// Volume handler.
+type VolumeHandler struct {
+	Handler
+}
+
+// Add routes to the `gin` router.
+func (h *VolumeHandler) AddRoutes(e *gin.Engine) {
+	e.GET(VolumesRoot, h.List)
+	e.GET(VolumesRoot+"/", h.List)
+	e.GET(VolumeRoot, h.Get)
+}
+
+// List resources in a REST collection.
+// A GET onn the collection that includes the `X-Watch`
+// header will negotiate an upgrade of the connection
+// to a websocket and push watch events.
+func (h VolumeHandler) List(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	if h.WatchRequest {
+		h.watch(ctx)
+		return
+	}
+	defer func() {
+		if err != nil {
+			log.Trace(
+				err,
+				"url",
+				ctx.Request.URL)
+			base.ReplyError(ctx, http.StatusInternalServerError, err)
+		}
+	}()
+	db := h.Collector.DB()
+	list := []model.Volume{}
+	err = db.List(&list, h.ListOptions(ctx))
+	if err != nil {
+		return
+	}
+	err = h.filter(ctx, &list)
+	if err != nil {
+		return
+	}
+	pb := PathBuilder{DB: db}
+	content := []interface{}{}
+	for _, m := range list {
+		r := &Volume{}
+		r.With(&m)
+		r.Link(h.Provider)
+		r.Path = pb.Path(&m)
+		content = append(content, r.Content(h.Detail))
+	}
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+// Get a specific REST resource.
+func (h VolumeHandler) Get(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	m := &model.Volume{
+		Base: model.Base{
+			ID: ctx.Param(VolumeParam),
+		},
+	}
+	db := h.Collector.DB()
+	err = db.Get(m)
+	if errors.Is(err, model.NotFound) {
+		base.ReplyError(ctx, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	pb := PathBuilder{DB: db}
+	r := &Volume{}
+	r.With(m)
+	r.Link(h.Provider)
+	r.Path = pb.Path(m)
+	content := r.Content(model.MaxDetail)
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+// Watch.
+func (h *VolumeHandler) watch(ctx *gin.Context) {
+	db := h.Collector.DB()
+	err := h.Watch(
+		ctx,
+		db,
+		&model.Volume{},
+		func(in libmodel.Model) (r interface{}) {
+			pb := PathBuilder{DB: db}
+			m := in.(*model.Volume)
+			storageContainer := &Volume{}
+			storageContainer.With(m)
+			storageContainer.Link(h.Provider)
+			storageContainer.Path = pb.Path(m)
+			r = storageContainer
+			return
+		})
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
+	}
+}
+
+// Filter result set.
+// Filter by path for `name` query.
+func (h *VolumeHandler) filter(ctx *gin.Context, list *[]model.Volume) (err error) {
+	if len(*list) < 2 {
+		return
+	}
+	q := ctx.Request.URL.Query()
+	name := q.Get(NameParam)
+	if len(name) == 0 {
+		return
+	}
+	if len(strings.Split(name, "/")) < 2 {
+		return
+	}
+	db := h.Collector.DB()
+	pb := PathBuilder{DB: db}
+	kept := []model.Volume{}
+	for _, m := range *list {
+		path := pb.Path(&m)
+		if h.PathMatchRoot(path, name) {
+			kept = append(kept, m)
+		}
+	}
+
+	*list = kept
+
+	return
+}
+
+// REST Resource.
+type Volume struct {
+	Resource
+	// Size (GiB).
+	Size             int64  `json:"size"`
+	VolumeType       string `json:"volumeType"`
+	IOPS             int64  `json:"iops"`
+	AvailabilityZone string `json:"availabilityZone"`
+	State            string `json:"state"`
+	Encrypted        bool   `json:"encrypted"`
+}
+
+// Build the resource using the model.
+func (r *Volume) With(m *model.Volume) {
+	r.Resource.With(&m.Base)
+	r.Size = m.Size
+	r.VolumeType = m.VolumeType
+	r.IOPS = m.IOPS
+	r.AvailabilityZone = m.AvailabilityZone
+	r.State = m.State
+	r.Encrypted = m.Encrypted
+}
+
+// Build self link (URI).
+func (r *Volume) Link(p *api.Provider) {
+	r.SelfLink = base.Link(
+		VolumeRoot,
+		base.Params{
+			base.ProviderParam: string(p.UID),
+			VolumeParam:        r.ID,
+		})
+}
+
+// As content.
+func (r *Volume) Content(detail int) interface{} {
+	if detail == 0 {
+		return r.Resource
+	}
+
+	return r
+}
@@ -0,0 +1,173 @@
+package ec2
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/ec2"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+)
+
+// Routes.
+const (
+	WorkloadCollection = "workloads"
+	WorkloadsRoot      = ProviderRoot + "/" + WorkloadCollection
+	WorkloadRoot       = WorkloadsRoot + "/:" + VMParam
+)
+
+// Virtual Machine handler.
+type WorkloadHandler struct {
+	Handler
+}
+
+// Add routes to the `gin` router.
+func (h *WorkloadHandler) AddRoutes(e *gin.Engine) {
+	e.GET(WorkloadRoot, h.Get)
+}
+
+// List resources in a REST collection.
+func (h WorkloadHandler) List(ctx *gin.Context) {
+}
+
+// Get a specific REST resource.
+func (h WorkloadHandler) Get(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	m := &model.VM{
+		Base: model.Base{
+			ID: ctx.Param(VMParam),
+		},
+	}
+	db := h.Collector.DB()
+	err = db.Get(m)
+	if errors.Is(err, model.NotFound) {
+		base.ReplyError(ctx, http.StatusNotFound, err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			log.Trace(
+				err,
+				"url",
+				ctx.Request.URL)
+			base.ReplyError(ctx, http.StatusInternalServerError, err)
+		}
+	}()
+	if err != nil {
+		return
+	}
+	r := Workload{}
+	r.With(m)
+	err = r.Expand(db)
+	if err != nil {
+		return
+	}
+	r.Link(h.Provider)
+	content := r
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+// Workload
+type Workload struct {
+	SelfLink string `json:"selfLink"`
+	VM
+	Subnets     []Subnet     `json:"subnets"`
+	Volumes     []Volume     `json:"volumes"`
+	VolumeTypes []VolumeType `json:"volumeTypes"`
+}
+
+func (r *Workload) With(m *model.VM) {
+	r.VM.With(m)
+}
+
+// Build self link (URI).
+func (r *Workload) Link(p *api.Provider) {
+	r.SelfLink = base.Link(
+		WorkloadRoot,
+		base.Params{
+			base.ProviderParam: string(p.UID),
+			VMParam:            r.ID,
+		})
+	for i := range r.Subnets {
+		r.Subnets[i].Link(p)
+	}
+	for i := range r.Volumes {
+		r.Volumes[i].Link(p)
+	}
+	for i := range r.VolumeTypes {
+		r.VolumeTypes[i].Link(p)
+	}
+}
+
+// Expand the resource.
+// The subnets, volumes and volume types
+// referenced by the VM are added.
+func (r *Workload) Expand(db libmodel.DB) (err error) {
+	r.Subnets = []Subnet{}
+	added := map[string]bool{}
+	for _, nic := range r.NICs {
+		if nic.Subnet == "" || added[nic.Subnet] {
+			continue
+		}
+		added[nic.Subnet] = true
+		subnet := &model.Subnet{
+			Base: model.Base{ID: nic.Subnet},
+		}
+		err = db.Get(subnet)
+		if err != nil {
+			if errors.Is(err, model.NotFound) {
+				err = nil
+				continue
+			}
+			return
+		}
+		resource := Subnet{}
+		resource.With(subnet)
+		r.Subnets = append(r.Subnets, resource)
+	}
+	r.Volumes = []Volume{}
+	r.VolumeTypes = []VolumeType{}
+	for _, disk := range r.Disks {
+		volume := &model.Volume{
+			Base: model.Base{ID: disk.ID},
+		}
+		err = db.Get(volume)
+		if err != nil {
+			if errors.Is(err, model.NotFound) {
+				err = nil
+				continue
+			}
+			return
+		}
+		resource := Volume{}
+		resource.With(volume)
+		r.Volumes = append(r.Volumes, resource)
+		if volume.VolumeType == "" || added[volume.VolumeType] {
+			continue
+		}
+		added[volume.VolumeType] = true
+		volumeType := &model.VolumeType{
+			Base: model.Base{ID: volume.VolumeType},
+		}
+		err = db.Get(volumeType)
+		if err != nil {
+			if errors.Is(err, model.NotFound) {
+				err = nil
+				continue
+			}
+			return
+		}
+		typeResource := VolumeType{}
+		typeResource.With(volumeType)
+		r.VolumeTypes = append(r.VolumeTypes, typeResource)
+	}
+
+	return
+}
@@ -0,0 +1,63 @@
+package ec2
+
+import (
+	"path/filepath"
+	"testing"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/ec2"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	. "github.com/onsi/gomega"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestWorkload(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	db := libmodel.New(filepath.Join(t.TempDir(), "ec2.db"), model.All()...)
+	g.Expect(db.Open(true)).To(Succeed())
+	defer func() {
+		_ = db.Close(true)
+	}()
+	vm := &model.VM{
+		Base: model.Base{ID: "i-1", Name: "web"},
+		VPC:  "vpc-1",
+		NICs: []model.NIC{
+			{ID: "eni-1", Subnet: "subnet-1"},
+			{ID: "eni-2", Subnet: "subnet-1"},
+			{ID: "eni-3", Subnet: "subnet-gone"},
+		},
+		Disks: []model.Disk{
+			{ID: "vol-1", Root: true},
+			{ID: "vol-2"},
+			{ID: "vol-gone"},
+		},
+	}
+	for _, m := range []libmodel.Model{
+		&model.Subnet{Base: model.Base{ID: "subnet-1", Name: "public"}, VPC: "vpc-1", DefaultForAz: true},
+		&model.VolumeType{Base: model.Base{ID: "gp3", Name: "gp3"}},
+		&model.Volume{Base: model.Base{ID: "vol-1", Name: "root"}, VolumeType: "gp3"},
+		&model.Volume{Base: model.Base{ID: "vol-2", Name: "data"}, VolumeType: "gp3"},
+		vm,
+	} {
+		g.Expect(db.Insert(m)).To(Succeed())
+	}
+
+	// Expanded.
+	workload := Workload{}
+	workload.With(vm)
+	g.Expect(workload.Expand(db)).To(Succeed())
+	workload.Link(&api.Provider{ObjectMeta: meta.ObjectMeta{UID: "p-1"}})
+	g.Expect(workload.Subnets).To(HaveLen(1))
+	g.Expect(workload.Subnets[0].Default).To(BeTrue())
+	g.Expect(workload.Subnets[0].SelfLink).To(ContainSubstring("p-1"))
+	g.Expect(workload.Volumes).To(HaveLen(2))
+	g.Expect(workload.VolumeTypes).To(HaveLen(1))
+	g.Expect(workload.VolumeTypes[0].Name).To(Equal("gp3"))
+	g.Expect(workload.SelfLink).To(HaveSuffix("/workloads/i-1"))
+
+	// Path.
+	pb := PathBuilder{DB: db}
+	g.Expect(pb.Path(vm)).To(Equal("/vpc-1/web"))
+	g.Expect(pb.Path(&model.Volume{Base: model.Base{Name: "root"}})).To(Equal("/root"))
+}
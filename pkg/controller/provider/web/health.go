@@ -0,0 +1,67 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+)
+
+// Routes.
+const (
+	HealthzRoot = "/healthz"
+	ReadyzRoot  = "/readyz"
+)
+
+// Web service health.
+type Health struct {
+	// Status: ok.
+	Status string `json:"status"`
+}
+
+// Web service readiness.
+type Readiness struct {
+	// All provider inventories have reached (initial) parity.
+	Ready bool `json:"ready"`
+	// Number of providers.
+	Providers int `json:"providers"`
+	// Number of provider inventories with parity.
+	Parity int `json:"parity"`
+}
+
+// Health (and readiness) handler.
+// Unauthenticated; intended for probes and scripts.
+type HealthHandler struct {
+	base.Handler
+}
+
+// Add routes to the `gin` router.
+func (h *HealthHandler) AddRoutes(e *gin.Engine) {
+	e.GET(HealthzRoot, h.Healthz)
+	e.GET(ReadyzRoot, h.Readyz)
+}
+
+// The web service is alive.
+func (h HealthHandler) Healthz(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, Health{Status: "ok"})
+}
+
+// The web service is ready.
+// Replies 503 until all provider inventories have
+// reached (initial) parity.
+func (h HealthHandler) Readyz(ctx *gin.Context) {
+	r := Readiness{}
+	for _, collector := range h.Container.List() {
+		r.Providers++
+		if collector.HasParity() {
+			r.Parity++
+		}
+	}
+	r.Ready = r.Parity == r.Providers
+	if !r.Ready {
+		ctx.JSON(http.StatusServiceUnavailable, r)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, r)
+}
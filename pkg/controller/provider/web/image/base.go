@@ -0,0 +1,75 @@
+package image
+
+import (
+	"strings"
+
+	pathlib "path"
+
+	"github.com/gin-gonic/gin"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/image"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+)
+
+// Package logger.
+var log = logging.WithName("web|image")
+
+// Fields.
+const (
+	DetailParam = base.DetailParam
+	NameParam   = base.NameParam
+)
+
+// Base handler.
+type Handler struct {
+	base.Handler
+}
+
+// Build list predicate.
+func (h Handler) Predicate(ctx *gin.Context) (p libmodel.Predicate) {
+	q := ctx.Request.URL.Query()
+	name := q.Get(NameParam)
+	if len(name) > 0 {
+		path := strings.Split(name, "/")
+		name := path[len(path)-1]
+		p = libmodel.Eq(NameParam, name)
+	}
+
+	return
+}
+
+// Build list options.
+func (h Handler) ListOptions(ctx *gin.Context) libmodel.ListOptions {
+	detail := h.Detail
+	if detail > 0 {
+		detail = model.MaxDetail
+	}
+	return libmodel.ListOptions{
+		Predicate: h.Predicate(ctx),
+		Detail:    detail,
+		Page:      &h.Page,
+	}
+}
+
+// Path builder.
+// The storage and network are named "default" and the
+// VMs are named by the image file name.
+type PathBuilder struct {
+	// Database.
+	DB libmodel.DB
+}
+
+// Build the path.
+func (r *PathBuilder) Path(m model.Model) (path string) {
+	switch m := m.(type) {
+	case *model.Storage:
+		path = pathlib.Join("/", m.Name)
+	case *model.Network:
+		path = pathlib.Join("/", m.Name)
+	case *model.VM:
+		path = pathlib.Join("/", m.Path)
+	}
+
+	return
+}
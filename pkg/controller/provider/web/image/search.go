@@ -0,0 +1,99 @@
+package image
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/image"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+)
+
+// Routes.
+const (
+	SearchRoot = ProviderRoot + "/" + base.SearchCollection
+)
+
+// Search handler.
+type SearchHandler struct {
+	Handler
+}
+
+// Add routes to the `gin` router.
+func (h *SearchHandler) AddRoutes(e *gin.Engine) {
+	e.GET(SearchRoot, h.Search)
+}
+
+// Search the names and IDs of the provider inventory.
+func (h SearchHandler) Search(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	base.Search(ctx, h.Provider, h.entries)
+}
+
+// Searched resources.
+func (h SearchHandler) entries() (entries []base.SearchEntry, err error) {
+	db := h.Collector.DB()
+	options := model.ListOptions{Detail: model.MaxDetail}
+	networks := []model.Network{}
+	err = db.List(&networks, options)
+	if err != nil {
+		return
+	}
+	for i := range networks {
+		m := &networks[i]
+		r := &Network{}
+		r.With(m)
+		r.Link(h.Provider)
+		entries = append(
+			entries,
+			base.SearchEntry{
+				Kind:     model.NetworkKind,
+				ID:       m.ID,
+				Name:     m.Name,
+				SelfLink: r.SelfLink,
+			})
+	}
+	storages := []model.Storage{}
+	err = db.List(&storages, options)
+	if err != nil {
+		return
+	}
+	for i := range storages {
+		m := &storages[i]
+		r := &Storage{}
+		r.With(m)
+		r.Link(h.Provider)
+		entries = append(
+			entries,
+			base.SearchEntry{
+				Kind:     model.StorageKind,
+				ID:       m.ID,
+				Name:     m.Name,
+				SelfLink: r.SelfLink,
+			})
+	}
+	vms := []model.VM{}
+	err = db.List(&vms, options)
+	if err != nil {
+		return
+	}
+	for i := range vms {
+		m := &vms[i]
+		r := &VM{}
+		r.With(m)
+		r.Link(h.Provider)
+		entries = append(
+			entries,
+			base.SearchEntry{
+				Kind:     model.VmKind,
+				ID:       m.ID,
+				Name:     m.Name,
+				SelfLink: r.SelfLink,
+			})
+	}
+
+	return
+}
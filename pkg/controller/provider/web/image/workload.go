@@ -0,0 +1,136 @@
+package image
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/image"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+)
+
+// Routes.
+const (
+	WorkloadCollection = "workloads"
+	WorkloadsRoot      = ProviderRoot + "/" + WorkloadCollection
+	WorkloadRoot       = WorkloadsRoot + "/:" + VMParam
+)
+
+// Virtual Machine handler.
+type WorkloadHandler struct {
+	Handler
+}
+
+// Add routes to the `gin` router.
+func (h *WorkloadHandler) AddRoutes(e *gin.Engine) {
+	e.GET(WorkloadRoot, h.Get)
+}
+
+// List resources in a REST collection.
+func (h WorkloadHandler) List(ctx *gin.Context) {
+}
+
+// Get a specific REST resource.
+func (h WorkloadHandler) Get(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	m := &model.VM{
+		Base: model.Base{
+			ID: ctx.Param(VMParam),
+		},
+	}
+	db := h.Collector.DB()
+	err = db.Get(m)
+	if errors.Is(err, model.NotFound) {
+		base.ReplyError(ctx, http.StatusNotFound, err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			log.Trace(
+				err,
+				"url",
+				ctx.Request.URL)
+			base.ReplyError(ctx, http.StatusInternalServerError, err)
+		}
+	}()
+	if err != nil {
+		return
+	}
+	r := Workload{}
+	r.With(m)
+	err = r.Expand(db)
+	if err != nil {
+		return
+	}
+	r.Link(h.Provider)
+	content := r
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+// Workload
+type Workload struct {
+	SelfLink string `json:"selfLink"`
+	VM
+	Networks []Network `json:"networks"`
+	Storages []Storage `json:"storages"`
+}
+
+func (r *Workload) With(m *model.VM) {
+	r.VM.With(m)
+}
+
+// Build self link (URI).
+func (r *Workload) Link(p *api.Provider) {
+	r.SelfLink = base.Link(
+		WorkloadRoot,
+		base.Params{
+			base.ProviderParam: string(p.UID),
+			VMParam:            r.ID,
+		})
+	for i := range r.Networks {
+		r.Networks[i].Link(p)
+	}
+	for i := range r.Storages {
+		r.Storages[i].Link(p)
+	}
+}
+
+// Expand the resource.
+// The (single) network and the storage of the image are added.
+func (r *Workload) Expand(db libmodel.DB) (err error) {
+	r.Networks = []Network{}
+	network := &model.Network{
+		Base: model.Base{ID: model.DefaultID},
+	}
+	err = db.Get(network)
+	if err == nil {
+		resource := Network{}
+		resource.With(network)
+		r.Networks = append(r.Networks, resource)
+	} else if errors.Is(err, model.NotFound) {
+		err = nil
+	} else {
+		return
+	}
+	r.Storages = []Storage{}
+	storage := &model.Storage{
+		Base: model.Base{ID: r.Storage},
+	}
+	err = db.Get(storage)
+	if err == nil {
+		resource := Storage{}
+		resource.With(storage)
+		r.Storages = append(r.Storages, resource)
+	} else if errors.Is(err, model.NotFound) {
+		err = nil
+	}
+
+	return
+}
@@ -0,0 +1,58 @@
+package image
+
+import (
+	"path/filepath"
+	"testing"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/image"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	. "github.com/onsi/gomega"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestWorkload(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	db := libmodel.New(filepath.Join(t.TempDir(), "image.db"), model.All()...)
+	g.Expect(db.Open(true)).To(Succeed())
+	defer func() {
+		_ = db.Close(true)
+	}()
+	vm := &model.VM{
+		Base:    model.Base{ID: "vm-1", Name: "web.qcow2"},
+		Path:    "images/web.qcow2",
+		Format:  "qcow2",
+		Storage: model.DefaultID,
+	}
+	for _, m := range []libmodel.Model{
+		&model.Network{Base: model.Base{ID: model.DefaultID, Name: "default"}},
+		&model.Storage{Base: model.Base{ID: model.DefaultID, Name: "share"}, URL: "http://share/disks/"},
+		vm,
+	} {
+		g.Expect(db.Insert(m)).To(Succeed())
+	}
+
+	// Expanded.
+	workload := Workload{}
+	workload.With(vm)
+	g.Expect(workload.Expand(db)).To(Succeed())
+	workload.Link(&api.Provider{ObjectMeta: meta.ObjectMeta{UID: "p-1"}})
+	g.Expect(workload.Networks).To(HaveLen(1))
+	g.Expect(workload.Networks[0].SelfLink).To(ContainSubstring("p-1"))
+	g.Expect(workload.Storages).To(HaveLen(1))
+	g.Expect(workload.Storages[0].Name).To(Equal("share"))
+	g.Expect(workload.SelfLink).To(HaveSuffix("/workloads/vm-1"))
+
+	// Storage not found.
+	vm.Storage = "gone"
+	workload = Workload{}
+	workload.With(vm)
+	g.Expect(workload.Expand(db)).To(Succeed())
+	g.Expect(workload.Storages).To(BeEmpty())
+
+	// Path.
+	pb := PathBuilder{DB: db}
+	g.Expect(pb.Path(vm)).To(Equal("/images/web.qcow2"))
+	g.Expect(pb.Path(&model.Storage{Base: model.Base{Name: "share"}})).To(Equal("/share"))
+}
@@ -0,0 +1,146 @@
+package libvirt
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/libvirt"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+)
+
+// Routes.
+const (
+	MapsGenerateRoot  = ProviderRoot + "/" + base.MapsGenerateRoot
+	StorageMatrixRoot = ProviderRoot + "/" + base.StorageMatrixRoot
+)
+
+// Map generation handler.
+type MapHandler struct {
+	Handler
+}
+
+// Add routes to the `gin` router.
+func (h *MapHandler) AddRoutes(e *gin.Engine) {
+	e.POST(MapsGenerateRoot, h.Generate)
+	e.GET(StorageMatrixRoot, h.StorageMatrix)
+}
+
+// Generate network and storage maps for the VMs.
+func (h MapHandler) Generate(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	generator := base.MapGenerator{
+		Container: h.Container,
+		Provider:  h.Provider,
+	}
+	generator.Generate(ctx, h.sources)
+}
+
+// Source storage vs destination storage class matrix.
+func (h MapHandler) StorageMatrix(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	generator := base.MapGenerator{
+		Container: h.Container,
+		Provider:  h.Provider,
+	}
+	generator.Matrix(ctx, h.storage)
+}
+
+// Networks and pools used by the VM.
+func (h MapHandler) sources(vmRef ref.Ref) (sources base.MapSources, err error) {
+	vm, err := h.findVM(h.Collector.DB(), vmRef)
+	if err != nil {
+		return
+	}
+	db := h.Collector.DB()
+	added := map[string]bool{}
+	for _, nic := range vm.NICs {
+		if nic.Network == "" || added[nic.Network] {
+			continue
+		}
+		added[nic.Network] = true
+		network := &model.Network{Base: model.Base{ID: nic.Network}}
+		err = db.Get(network)
+		if err != nil {
+			return
+		}
+		sources.Networks = append(sources.Networks, ref.Ref{ID: network.ID, Name: network.Name})
+	}
+	for _, disk := range vm.Disks {
+		if disk.Pool == "" || added[disk.Pool] {
+			continue
+		}
+		added[disk.Pool] = true
+		pool := &model.Pool{Base: model.Base{ID: disk.Pool}}
+		err = db.Get(pool)
+		if err != nil {
+			return
+		}
+		sources.Storage = append(sources.Storage, ref.Ref{ID: pool.ID, Name: pool.Name})
+	}
+
+	return
+}
+
+// Find the VM by ID or name.
+func (h MapHandler) findVM(db libmodel.DB, vmRef ref.Ref) (vm *model.VM, err error) {
+	predicate := libmodel.Eq("id", vmRef.ID)
+	if vmRef.ID == "" {
+		predicate = libmodel.Eq(NameParam, vmRef.Name)
+	}
+	list := []model.VM{}
+	err = db.List(&list, model.ListOptions{Predicate: predicate, Detail: model.MaxDetail})
+	if err != nil {
+		return
+	}
+	switch len(list) {
+	case 0:
+		err = base.NotFoundError{Ref: vmRef}
+	case 1:
+		vm = &list[0]
+	default:
+		err = base.RefNotUniqueError{Ref: vmRef}
+	}
+
+	return
+}
+
+// Storage pools.
+func (h MapHandler) storage() (sources []base.StorageSource, err error) {
+	list := []model.Pool{}
+	err = h.Collector.DB().List(&list, model.ListOptions{Detail: model.MaxDetail})
+	if err != nil {
+		return
+	}
+	for _, pool := range list {
+		sources = append(
+			sources,
+			base.StorageSource{
+				Ref:   ref.Ref{ID: pool.ID, Name: pool.Name},
+				Type:  pool.Type,
+				Block: blockPool[pool.Type],
+			})
+	}
+
+	return
+}
+
+// Block backed pool types.
+var blockPool = map[string]bool{
+	"logical":      true,
+	"disk":         true,
+	"iscsi":        true,
+	"iscsi-direct": true,
+	"scsi":         true,
+	"mpath":        true,
+	"rbd":          true,
+}
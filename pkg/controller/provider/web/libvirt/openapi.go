@@ -0,0 +1,41 @@
+package libvirt
+
+import (
+	"net/http"
+
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	libweb "github.com/kubev2v/forklift/pkg/lib/inventory/web"
+)
+
+// Route (OpenAPI) schemas.
+func Schemas() []libweb.RouteSchema {
+	return []libweb.RouteSchema{
+		{Method: http.MethodGet, Path: ProvidersRoot, Response: []Provider{}},
+		{Method: http.MethodGet, Path: ProviderRoot, Response: Provider{}},
+		{Method: http.MethodGet, Path: PoolsRoot, Response: []Pool{}},
+		{Method: http.MethodGet, Path: PoolRoot, Response: Pool{}},
+		{Method: http.MethodGet, Path: NetworksRoot, Response: []Network{}},
+		{Method: http.MethodGet, Path: NetworkRoot, Response: Network{}},
+		{Method: http.MethodGet, Path: VMsRoot, Response: []VM{}},
+		{Method: http.MethodGet, Path: VMRoot, Response: VM{}},
+		{Method: http.MethodGet, Path: WorkloadRoot, Response: Workload{}},
+		{
+			Method:   http.MethodGet,
+			Path:     SearchRoot,
+			Query:    []string{base.SearchParam, base.LimitParam},
+			Response: []base.SearchHit{},
+		},
+		{
+			Method:   http.MethodPost,
+			Path:     MapsGenerateRoot,
+			Request:  base.MapsRequest{},
+			Response: base.GeneratedMaps{},
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     StorageMatrixRoot,
+			Query:    []string{base.DestinationParam},
+			Response: base.StorageMatrix{},
+		},
+	}
+}
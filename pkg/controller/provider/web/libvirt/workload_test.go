@@ -0,0 +1,58 @@
+package libvirt
+
+import (
+	"path/filepath"
+	"testing"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/libvirt"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	. "github.com/onsi/gomega"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestWorkload(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	db := libmodel.New(filepath.Join(t.TempDir(), "libvirt.db"), model.All()...)
+	g.Expect(db.Open(true)).To(Succeed())
+	defer func() {
+		_ = db.Close(true)
+	}()
+	vm := &model.VM{
+		Base: model.Base{ID: "vm-1", Name: "web"},
+		NICs: []model.NIC{
+			{MAC: "52:54:00:00:00:01", Network: "net-1"},
+			{MAC: "52:54:00:00:00:02", Network: "net-1"},
+			{MAC: "52:54:00:00:00:03", Type: "direct"},
+		},
+		Disks: []model.Disk{
+			{Target: "vda", Pool: "pool-1"},
+			{Target: "vdb", Pool: "pool-1"},
+			{Target: "sda", Device: "cdrom"},
+		},
+	}
+	for _, m := range []libmodel.Model{
+		&model.Network{Base: model.Base{ID: "net-1", Name: "default"}, Bridge: "virbr0"},
+		&model.Pool{Base: model.Base{ID: "pool-1", Name: "default"}, Path: "/var/lib/libvirt/images"},
+		vm,
+	} {
+		g.Expect(db.Insert(m)).To(Succeed())
+	}
+
+	// Expanded.
+	workload := Workload{}
+	workload.With(vm)
+	g.Expect(workload.Expand(db)).To(Succeed())
+	workload.Link(&api.Provider{ObjectMeta: meta.ObjectMeta{UID: "p-1"}})
+	g.Expect(workload.Networks).To(HaveLen(1))
+	g.Expect(workload.Networks[0].SelfLink).To(ContainSubstring("p-1"))
+	g.Expect(workload.Pools).To(HaveLen(1))
+	g.Expect(workload.Pools[0].Name).To(Equal("default"))
+	g.Expect(workload.SelfLink).To(HaveSuffix("/workloads/vm-1"))
+
+	// Path.
+	pb := PathBuilder{DB: db}
+	g.Expect(pb.Path(vm)).To(Equal("/web"))
+	g.Expect(pb.Path(&model.Pool{Base: model.Base{Name: "default"}})).To(Equal("/default"))
+}
@@ -0,0 +1,104 @@
+package nutanix
+
+import (
+	"strings"
+
+	pathlib "path"
+
+	"github.com/gin-gonic/gin"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/nutanix"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+)
+
+// Package logger.
+var log = logging.WithName("web|nutanix")
+
+// Fields.
+const (
+	DetailParam = base.DetailParam
+	NameParam   = base.NameParam
+)
+
+// Base handler.
+type Handler struct {
+	base.Handler
+}
+
+// Build list predicate.
+func (h Handler) Predicate(ctx *gin.Context) (p libmodel.Predicate) {
+	q := ctx.Request.URL.Query()
+	name := q.Get(NameParam)
+	if len(name) > 0 {
+		path := strings.Split(name, "/")
+		name := path[len(path)-1]
+		p = libmodel.Eq(NameParam, name)
+	}
+
+	return
+}
+
+// Build list options.
+func (h Handler) ListOptions(ctx *gin.Context) libmodel.ListOptions {
+	detail := h.Detail
+	if detail > 0 {
+		detail = model.MaxDetail
+	}
+	return libmodel.ListOptions{
+		Predicate: h.Predicate(ctx),
+		Detail:    detail,
+		Page:      &h.Page,
+	}
+}
+
+// Path builder.
+// Clustered resources are prefixed with the cluster name.
+type PathBuilder struct {
+	// Database.
+	DB libmodel.DB
+	// Cached cluster names.
+	cache map[string]string
+}
+
+// Build the path.
+func (r *PathBuilder) Path(m model.Model) (path string) {
+	switch m := m.(type) {
+	case *model.Cluster:
+		path = pathlib.Join("/", m.Name)
+	case *model.Subnet:
+		path = pathlib.Join("/", r.cluster(m.Cluster), m.Name)
+	case *model.StorageContainer:
+		path = pathlib.Join("/", r.cluster(m.Cluster), m.Name)
+	case *model.VM:
+		path = pathlib.Join("/", r.cluster(m.Cluster), m.Name)
+	}
+
+	return
+}
+
+// The (cached) cluster name.
+func (r *PathBuilder) cluster(id string) (name string) {
+	if r.cache == nil {
+		r.cache = map[string]string{}
+	}
+	name, found := r.cache[id]
+	if found || id == "" {
+		return
+	}
+	cluster := &model.Cluster{
+		Base: model.Base{ID: id},
+	}
+	err := r.DB.Get(cluster)
+	if err != nil {
+		log.Error(
+			err,
+			"path builder failed.",
+			"model",
+			libmodel.Describe(cluster))
+		return
+	}
+	name = cluster.Name
+	r.cache[id] = name
+	return
+}
@@ -0,0 +1,205 @@
+package nutanix
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/nutanix"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+)
+
+// Routes.
+const (
+	StorageContainerParam      = "storagecontainer"
+	StorageContainerCollection = "storagecontainers"
+	StorageContainersRoot      = ProviderRoot + "/" + StorageContainerCollection
+	StorageContainerRoot       = StorageContainersRoot + "/:" + StorageContainerParam
+)
+
+// StorageContainer handler.
+type StorageContainerHandler struct {
+	Handler
+}
+
+// Add routes to the `gin` router.
+func (h *StorageContainerHandler) AddRoutes(e *gin.Engine) {
+	e.GET(StorageContainersRoot, h.List)
+	e.GET(StorageContainersRoot+"/", h.List)
+	e.GET(StorageContainerRoot, h.Get)
+}
+
+// List resources in a REST collection.
+// A GET onn the collection that includes the `X-Watch`
+// header will negotiate an upgrade of the connection
+// to a websocket and push watch events.
+func (h StorageContainerHandler) List(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	if h.WatchRequest {
+		h.watch(ctx)
+		return
+	}
+	defer func() {
+		if err != nil {
+			log.Trace(
+				err,
+				"url",
+				ctx.Request.URL)
+			base.ReplyError(ctx, http.StatusInternalServerError, err)
+		}
+	}()
+	db := h.Collector.DB()
+	list := []model.StorageContainer{}
+	err = db.List(&list, h.ListOptions(ctx))
+	if err != nil {
+		return
+	}
+	err = h.filter(ctx, &list)
+	if err != nil {
+		return
+	}
+	pb := PathBuilder{DB: db}
+	content := []interface{}{}
+	for _, m := range list {
+		r := &StorageContainer{}
+		r.With(&m)
+		r.Link(h.Provider)
+		r.Path = pb.Path(&m)
+		content = append(content, r.Content(h.Detail))
+	}
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+// Get a specific REST resource.
+func (h StorageContainerHandler) Get(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	m := &model.StorageContainer{
+		Base: model.Base{
+			ID: ctx.Param(StorageContainerParam),
+		},
+	}
+	db := h.Collector.DB()
+	err = db.Get(m)
+	if errors.Is(err, model.NotFound) {
+		base.ReplyError(ctx, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	pb := PathBuilder{DB: db}
+	r := &StorageContainer{}
+	r.With(m)
+	r.Link(h.Provider)
+	r.Path = pb.Path(m)
+	content := r.Content(model.MaxDetail)
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+// Watch.
+func (h *StorageContainerHandler) watch(ctx *gin.Context) {
+	db := h.Collector.DB()
+	err := h.Watch(
+		ctx,
+		db,
+		&model.StorageContainer{},
+		func(in libmodel.Model) (r interface{}) {
+			pb := PathBuilder{DB: db}
+			m := in.(*model.StorageContainer)
+			storageContainer := &StorageContainer{}
+			storageContainer.With(m)
+			storageContainer.Link(h.Provider)
+			storageContainer.Path = pb.Path(m)
+			r = storageContainer
+			return
+		})
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
+	}
+}
+
+// Filter result set.
+// Filter by path for `name` query.
+func (h *StorageContainerHandler) filter(ctx *gin.Context, list *[]model.StorageContainer) (err error) {
+	if len(*list) < 2 {
+		return
+	}
+	q := ctx.Request.URL.Query()
+	name := q.Get(NameParam)
+	if len(name) == 0 {
+		return
+	}
+	if len(strings.Split(name, "/")) < 2 {
+		return
+	}
+	db := h.Collector.DB()
+	pb := PathBuilder{DB: db}
+	kept := []model.StorageContainer{}
+	for _, m := range *list {
+		path := pb.Path(&m)
+		if h.PathMatchRoot(path, name) {
+			kept = append(kept, m)
+		}
+	}
+
+	*list = kept
+
+	return
+}
+
+// REST Resource.
+type StorageContainer struct {
+	Resource
+	Cluster           string `json:"cluster"`
+	Capacity          int64  `json:"capacity"`
+	ReplicationFactor int    `json:"replicationFactor"`
+}
+
+// Build the resource using the model.
+func (r *StorageContainer) With(m *model.StorageContainer) {
+	r.Resource.With(&m.Base)
+	r.Cluster = m.Cluster
+	r.Capacity = m.Capacity
+	r.ReplicationFactor = m.ReplicationFactor
+}
+
+// Build self link (URI).
+func (r *StorageContainer) Link(p *api.Provider) {
+	r.SelfLink = base.Link(
+		StorageContainerRoot,
+		base.Params{
+			base.ProviderParam:    string(p.UID),
+			StorageContainerParam: r.ID,
+		})
+}
+
+// As content.
+func (r *StorageContainer) Content(detail int) interface{} {
+	if detail == 0 {
+		return r.Resource
+	}
+
+	return r
+}
@@ -0,0 +1,209 @@
+package nutanix
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/nutanix"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+)
+
+// Routes.
+const (
+	SubnetParam      = "subnet"
+	SubnetCollection = "subnets"
+	SubnetsRoot      = ProviderRoot + "/" + SubnetCollection
+	SubnetRoot       = SubnetsRoot + "/:" + SubnetParam
+)
+
+// Subnet handler.
+type SubnetHandler struct {
+	Handler
+}
+
+// Add routes to the `gin` router.
+func (h *SubnetHandler) AddRoutes(e *gin.Engine) {
+	e.GET(SubnetsRoot, h.List)
+	e.GET(SubnetsRoot+"/", h.List)
+	e.GET(SubnetRoot, h.Get)
+}
+
+// List resources in a REST collection.
+// A GET onn the collection that includes the `X-Watch`
+// header will negotiate an upgrade of the connection
+// to a websocket and push watch events.
+func (h SubnetHandler) List(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	if h.WatchRequest {
+		h.watch(ctx)
+		return
+	}
+	defer func() {
+		if err != nil {
+			log.Trace(
+				err,
+				"url",
+				ctx.Request.URL)
+			base.ReplyError(ctx, http.StatusInternalServerError, err)
+		}
+	}()
+	db := h.Collector.DB()
+	list := []model.Subnet{}
+	err = db.List(&list, h.ListOptions(ctx))
+	if err != nil {
+		return
+	}
+	err = h.filter(ctx, &list)
+	if err != nil {
+		return
+	}
+	pb := PathBuilder{DB: db}
+	content := []interface{}{}
+	for _, m := range list {
+		r := &Subnet{}
+		r.With(&m)
+		r.Link(h.Provider)
+		r.Path = pb.Path(&m)
+		content = append(content, r.Content(h.Detail))
+	}
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+// Get a specific REST resource.
+func (h SubnetHandler) Get(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	m := &model.Subnet{
+		Base: model.Base{
+			ID: ctx.Param(SubnetParam),
+		},
+	}
+	db := h.Collector.DB()
+	err = db.Get(m)
+	if errors.Is(err, model.NotFound) {
+		base.ReplyError(ctx, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	pb := PathBuilder{DB: db}
+	r := &Subnet{}
+	r.With(m)
+	r.Link(h.Provider)
+	r.Path = pb.Path(m)
+	content := r.Content(model.MaxDetail)
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+// Watch.
+func (h *SubnetHandler) watch(ctx *gin.Context) {
+	db := h.Collector.DB()
+	err := h.Watch(
+		ctx,
+		db,
+		&model.Subnet{},
+		func(in libmodel.Model) (r interface{}) {
+			pb := PathBuilder{DB: db}
+			m := in.(*model.Subnet)
+			subnet := &Subnet{}
+			subnet.With(m)
+			subnet.Link(h.Provider)
+			subnet.Path = pb.Path(m)
+			r = subnet
+			return
+		})
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
+	}
+}
+
+// Filter result set.
+// Filter by path for `name` query.
+func (h *SubnetHandler) filter(ctx *gin.Context, list *[]model.Subnet) (err error) {
+	if len(*list) < 2 {
+		return
+	}
+	q := ctx.Request.URL.Query()
+	name := q.Get(NameParam)
+	if len(name) == 0 {
+		return
+	}
+	if len(strings.Split(name, "/")) < 2 {
+		return
+	}
+	db := h.Collector.DB()
+	pb := PathBuilder{DB: db}
+	kept := []model.Subnet{}
+	for _, m := range *list {
+		path := pb.Path(&m)
+		if h.PathMatchRoot(path, name) {
+			kept = append(kept, m)
+		}
+	}
+
+	*list = kept
+
+	return
+}
+
+// REST Resource.
+type Subnet struct {
+	Resource
+	Cluster    string `json:"cluster"`
+	SubnetType string `json:"subnetType"`
+	VlanID     int    `json:"vlanID"`
+	VSwitch    string `json:"vswitch"`
+	CIDR       string `json:"cidr"`
+}
+
+// Build the resource using the model.
+func (r *Subnet) With(m *model.Subnet) {
+	r.Resource.With(&m.Base)
+	r.Cluster = m.Cluster
+	r.SubnetType = m.SubnetType
+	r.VlanID = m.VlanID
+	r.VSwitch = m.VSwitch
+	r.CIDR = m.CIDR
+}
+
+// Build self link (URI).
+func (r *Subnet) Link(p *api.Provider) {
+	r.SelfLink = base.Link(
+		SubnetRoot,
+		base.Params{
+			base.ProviderParam: string(p.UID),
+			SubnetParam:        r.ID,
+		})
+}
+
+// As content.
+func (r *Subnet) Content(detail int) interface{} {
+	if detail == 0 {
+		return r.Resource
+	}
+
+	return r
+}
@@ -0,0 +1,61 @@
+package nutanix
+
+import (
+	"path/filepath"
+	"testing"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/nutanix"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	. "github.com/onsi/gomega"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestWorkload(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	db := libmodel.New(filepath.Join(t.TempDir(), "nutanix.db"), model.All()...)
+	g.Expect(db.Open(true)).To(Succeed())
+	defer func() {
+		_ = db.Close(true)
+	}()
+	vm := &model.VM{
+		Base:    model.Base{ID: "vm-1", Name: "vm-1"},
+		Cluster: "cluster-1",
+		NICs: []model.NIC{
+			{ID: "nic-1", Subnet: "subnet-1"},
+			{ID: "nic-2", Subnet: "subnet-1"},
+			{ID: "nic-3", Subnet: "subnet-gone"},
+		},
+		Disks: []model.Disk{
+			{ID: "disk-1", StorageContainer: "sc-1"},
+			{ID: "disk-2", StorageContainer: "sc-1"},
+		},
+	}
+	for _, m := range []libmodel.Model{
+		&model.Cluster{Base: model.Base{ID: "cluster-1", Name: "cluster-1"}},
+		&model.Subnet{Base: model.Base{ID: "subnet-1", Name: "vlan-10"}, Cluster: "cluster-1"},
+		&model.StorageContainer{Base: model.Base{ID: "sc-1", Name: "default"}, Cluster: "cluster-1"},
+		vm,
+	} {
+		g.Expect(db.Insert(m)).To(Succeed())
+	}
+
+	// Expanded.
+	workload := Workload{}
+	workload.With(vm)
+	g.Expect(workload.Expand(db)).To(Succeed())
+	workload.Link(&api.Provider{ObjectMeta: meta.ObjectMeta{UID: "p-1"}})
+	g.Expect(workload.ClusterInfo).ToNot(BeNil())
+	g.Expect(workload.ClusterInfo.Name).To(Equal("cluster-1"))
+	g.Expect(workload.Subnets).To(HaveLen(1))
+	g.Expect(workload.Subnets[0].SelfLink).To(ContainSubstring("p-1"))
+	g.Expect(workload.StorageContainers).To(HaveLen(1))
+	g.Expect(workload.SelfLink).To(HaveSuffix("/workloads/vm-1"))
+
+	// Path.
+	pb := PathBuilder{DB: db}
+	g.Expect(pb.Path(vm)).To(Equal("/cluster-1/vm-1"))
+	g.Expect(pb.Path(&model.Cluster{Base: model.Base{Name: "cluster-1"}})).To(Equal("/cluster-1"))
+	g.Expect(pb.Path(&model.Subnet{Base: model.Base{Name: "vlan-20"}})).To(Equal("/vlan-20"))
+}
@@ -19,16 +19,17 @@ import (
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
-	"k8s.io/client-go/rest"
 	cnv "kubevirt.io/api/core/v1"
 	instancetype "kubevirt.io/api/instancetype/v1beta1"
 	ocpclient "sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/client/config"
 )
 
 // Package logger.
 var log = logging.WithName("web|ocp")
 
+// Client cache key (prefix) of user clients.
+const UserClientKey = "ocp|user-client"
+
 // Params.
 const (
 	NsParam     = base.NsParam
@@ -124,30 +125,40 @@ func (h Handler) UserClient(ctx *gin.Context) (cl ocpclient.Client, err error) {
 		// should use the controller service account which essentially has access to the
 		// whole cluster. Any 'host' providers created in other namespaces are limited to
 		// accessing resources within their namespace
-		var cfg *rest.Config
-		cfg, err = config.GetConfig()
-		if err != nil {
+		token := h.Token(ctx)
+		if token == "" {
+			err = liberr.New("No authentication token found")
 			return
 		}
+		var object interface{}
+		object, err = base.Clients.Get(
+			base.TokenKey(UserClientKey, token),
+			func() (object interface{}, err error) {
+				cfg, err := base.ClusterConfig()
+				if err != nil {
+					return
+				}
 
-		log.Info("Creating a new ocp client with user token")
+				log.Info("Creating a new ocp client with user token")
 
-		// clear the service account token and use the token provided with the http request.
-		cfg.BearerTokenFile = ""
-		cfg.BearerToken = h.Token(ctx)
-		if cfg.BearerToken == "" {
-			err = liberr.New("No authentication token found")
-			return
-		}
-		// build a new client with the user's token from the http request
-		if cl, err = ocpclient.New(
-			cfg,
-			ocpclient.Options{
-				Scheme: scheme.Scheme,
-			}); err != nil {
-			err = liberr.New("Couldn't create a client for the user token")
+				// clear the service account token and use the token provided with the http request.
+				cfg.BearerTokenFile = ""
+				cfg.BearerToken = token
+				// build a new client with the user's token from the http request
+				object, err = ocpclient.New(
+					cfg,
+					ocpclient.Options{
+						Scheme: scheme.Scheme,
+					})
+				if err != nil {
+					err = liberr.New("Couldn't create a client for the user token")
+				}
+				return
+			})
+		if err != nil {
 			return
 		}
+		cl = object.(ocpclient.Client)
 	} else {
 		log.Info("Getting ocp client from collector")
 		ocpcollector, cast := h.Collector.(*ocpcontainer.Collector)
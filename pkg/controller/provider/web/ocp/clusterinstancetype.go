@@ -36,12 +36,11 @@ func (h *ClusterInstanceHandler) AddRoutes(e *gin.Engine) {
 func (h ClusterInstanceHandler) List(ctx *gin.Context) {
 	status, err := h.Prepare(ctx)
 	if status != http.StatusOK {
-		ctx.Status(status)
-		base.SetForkliftError(ctx, err)
+		base.ReplyError(ctx, status, err)
 		return
 	}
 	if h.WatchRequest {
-		ctx.Status(http.StatusNotImplemented)
+		base.ReplyError(ctx, http.StatusNotImplemented, err)
 		return
 	}
 	clusterinstances, err := h.ClusterInstanceTypes(ctx)
@@ -50,7 +49,7 @@ func (h ClusterInstanceHandler) List(ctx *gin.Context) {
 			err,
 			"url",
 			ctx.Request.URL)
-		ctx.Status(http.StatusInternalServerError)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -70,8 +69,7 @@ func (h ClusterInstanceHandler) List(ctx *gin.Context) {
 func (h ClusterInstanceHandler) Get(ctx *gin.Context) {
 	status, err := h.Prepare(ctx)
 	if status != http.StatusOK {
-		ctx.Status(status)
-		base.SetForkliftError(ctx, err)
+		base.ReplyError(ctx, status, err)
 		return
 	}
 	clusterinstances, err := h.ClusterInstanceTypes(ctx)
@@ -80,7 +78,7 @@ func (h ClusterInstanceHandler) Get(ctx *gin.Context) {
 			err,
 			"url",
 			ctx.Request.URL)
-		ctx.Status(http.StatusInternalServerError)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 	for _, m := range clusterinstances {
@@ -95,7 +93,7 @@ func (h ClusterInstanceHandler) Get(ctx *gin.Context) {
 
 		}
 	}
-	ctx.Status(http.StatusNotFound)
+	base.ReplyError(ctx, http.StatusNotFound, err)
 }
 
 // REST Resource.
@@ -20,6 +20,18 @@ func Handlers(container *container.Container) []libweb.RequestHandler {
 				base.Handler{Container: container},
 			},
 		},
+		&base.HealthHandler{
+			Handler: base.Handler{
+				Container: container,
+			},
+			Kind: api.OpenShift,
+		},
+		&base.ParityHandler{
+			Handler: base.Handler{
+				Container: container,
+			},
+			Kind: api.OpenShift,
+		},
 		&NamespaceHandler{
 			Handler: Handler{
 				base.Handler{Container: container},
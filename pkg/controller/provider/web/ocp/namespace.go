@@ -36,12 +36,11 @@ func (h *NamespaceHandler) AddRoutes(e *gin.Engine) {
 func (h NamespaceHandler) List(ctx *gin.Context) {
 	status, err := h.Prepare(ctx)
 	if status != http.StatusOK {
-		ctx.Status(status)
-		base.SetForkliftError(ctx, err)
+		base.ReplyError(ctx, status, err)
 		return
 	}
 	if h.WatchRequest {
-		ctx.Status(http.StatusNotImplemented)
+		base.ReplyError(ctx, http.StatusNotImplemented, err)
 		return
 	}
 
@@ -51,7 +50,7 @@ func (h NamespaceHandler) List(ctx *gin.Context) {
 			err,
 			"url",
 			ctx.Request.URL)
-		ctx.Status(http.StatusInternalServerError)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -71,8 +70,7 @@ func (h NamespaceHandler) List(ctx *gin.Context) {
 func (h NamespaceHandler) Get(ctx *gin.Context) {
 	status, err := h.Prepare(ctx)
 	if status != http.StatusOK {
-		ctx.Status(status)
-		base.SetForkliftError(ctx, err)
+		base.ReplyError(ctx, status, err)
 		return
 	}
 	namespaces, err := h.Namespaces(ctx, h.Provider)
@@ -81,7 +79,7 @@ func (h NamespaceHandler) Get(ctx *gin.Context) {
 			err,
 			"url",
 			ctx.Request.URL)
-		ctx.Status(http.StatusInternalServerError)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 	uid := types.UID(ctx.Param(NsParam))
@@ -96,7 +94,7 @@ func (h NamespaceHandler) Get(ctx *gin.Context) {
 		}
 	}
 
-	ctx.Status(http.StatusNotFound)
+	base.ReplyError(ctx, http.StatusNotFound, err)
 }
 
 // REST Resource.
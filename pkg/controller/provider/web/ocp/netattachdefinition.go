@@ -36,12 +36,11 @@ func (h *NadHandler) AddRoutes(e *gin.Engine) {
 func (h NadHandler) List(ctx *gin.Context) {
 	status, err := h.Prepare(ctx)
 	if status != http.StatusOK {
-		ctx.Status(status)
-		base.SetForkliftError(ctx, err)
+		base.ReplyError(ctx, status, err)
 		return
 	}
 	if h.WatchRequest {
-		ctx.Status(http.StatusNotImplemented)
+		base.ReplyError(ctx, http.StatusNotImplemented, err)
 		return
 	}
 	nads, err := h.NetworkAttachmentDefinitions(ctx, h.Provider)
@@ -50,7 +49,7 @@ func (h NadHandler) List(ctx *gin.Context) {
 			err,
 			"url",
 			ctx.Request.URL)
-		ctx.Status(http.StatusInternalServerError)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -70,8 +69,7 @@ func (h NadHandler) List(ctx *gin.Context) {
 func (h NadHandler) Get(ctx *gin.Context) {
 	status, err := h.Prepare(ctx)
 	if status != http.StatusOK {
-		ctx.Status(status)
-		base.SetForkliftError(ctx, err)
+		base.ReplyError(ctx, status, err)
 		return
 	}
 	nads, err := h.NetworkAttachmentDefinitions(ctx, h.Provider)
@@ -80,7 +78,7 @@ func (h NadHandler) Get(ctx *gin.Context) {
 			err,
 			"url",
 			ctx.Request.URL)
-		ctx.Status(http.StatusInternalServerError)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 	for _, m := range nads {
@@ -94,7 +92,7 @@ func (h NadHandler) Get(ctx *gin.Context) {
 			return
 		}
 	}
-	ctx.Status(http.StatusNotFound)
+	base.ReplyError(ctx, http.StatusNotFound, err)
 }
 
 // REST Resource.
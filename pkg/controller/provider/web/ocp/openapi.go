@@ -0,0 +1,28 @@
+package ocp
+
+import (
+	"net/http"
+
+	libweb "github.com/kubev2v/forklift/pkg/lib/inventory/web"
+)
+
+// Route (OpenAPI) schemas.
+func Schemas() []libweb.RouteSchema {
+	return []libweb.RouteSchema{
+		{Method: http.MethodGet, Path: ProvidersRoot, Response: []Provider{}},
+		{Method: http.MethodGet, Path: ProviderRoot, Response: Provider{}},
+		{Method: http.MethodGet, Path: TreeNamespaceRoot, Response: TreeNode{}},
+		{Method: http.MethodGet, Path: NamespacesRoot, Response: []Namespace{}},
+		{Method: http.MethodGet, Path: NamespaceRoot, Response: Namespace{}},
+		{Method: http.MethodGet, Path: StorageClassesRoot, Response: []StorageClass{}},
+		{Method: http.MethodGet, Path: StorageClassRoot, Response: StorageClass{}},
+		{Method: http.MethodGet, Path: NadsRoot, Response: []NetworkAttachmentDefinition{}},
+		{Method: http.MethodGet, Path: NadRoot, Response: NetworkAttachmentDefinition{}},
+		{Method: http.MethodGet, Path: InstancesRoot, Response: []InstanceType{}},
+		{Method: http.MethodGet, Path: InstanceRoot, Response: InstanceType{}},
+		{Method: http.MethodGet, Path: ClusterInstancesRoot, Response: []ClusterInstanceType{}},
+		{Method: http.MethodGet, Path: ClusterInstanceRoot, Response: ClusterInstanceType{}},
+		{Method: http.MethodGet, Path: VMsRoot, Response: []VM{}},
+		{Method: http.MethodGet, Path: VMRoot, Response: VM{}},
+	}
+}
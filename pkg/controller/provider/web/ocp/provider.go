@@ -26,6 +26,7 @@ type ProviderHandler struct {
 func (h *ProviderHandler) AddRoutes(e *gin.Engine) {
 	e.GET(ProvidersRoot, h.List)
 	e.GET(ProvidersRoot+"/", h.List)
+	base.DefaultAuth.Require(ProvidersRoot, base.Authenticated)
 	e.GET(ProviderRoot, h.Get)
 }
 
@@ -33,8 +34,7 @@ func (h *ProviderHandler) AddRoutes(e *gin.Engine) {
 func (h ProviderHandler) List(ctx *gin.Context) {
 	status, err := h.Prepare(ctx)
 	if status != http.StatusOK {
-		ctx.Status(status)
-		base.SetForkliftError(ctx, err)
+		base.ReplyError(ctx, status, err)
 		return
 	}
 	content, err := h.ListContent(ctx)
@@ -43,7 +43,7 @@ func (h ProviderHandler) List(ctx *gin.Context) {
 			err,
 			"url",
 			ctx.Request.URL)
-		ctx.Status(http.StatusInternalServerError)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -54,12 +54,11 @@ func (h ProviderHandler) List(ctx *gin.Context) {
 func (h ProviderHandler) Get(ctx *gin.Context) {
 	status, err := h.Prepare(ctx)
 	if status != http.StatusOK {
-		ctx.Status(status)
-		base.SetForkliftError(ctx, err)
+		base.ReplyError(ctx, status, err)
 		return
 	}
 	if h.Provider.Type() != api.OpenShift {
-		ctx.Status(http.StatusNotFound)
+		base.ReplyError(ctx, http.StatusNotFound, err)
 		return
 	}
 	h.Detail = model.MaxDetail
@@ -73,7 +72,7 @@ func (h ProviderHandler) Get(ctx *gin.Context) {
 			err,
 			"url",
 			ctx.Request.URL)
-		ctx.Status(http.StatusInternalServerError)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 	r.Link()
@@ -96,6 +95,9 @@ func (h *ProviderHandler) ListContent(ctx *gin.Context) (content []interface{},
 			if ns != "" && ns != p.Namespace {
 				continue
 			}
+			if !h.Visible(ctx, p) {
+				continue
+			}
 			if collector, found := h.Container.Get(p); found {
 				h.Collector = collector
 			} else {
@@ -36,12 +36,11 @@ func (h *StorageClassHandler) AddRoutes(e *gin.Engine) {
 func (h StorageClassHandler) List(ctx *gin.Context) {
 	status, err := h.Prepare(ctx)
 	if status != http.StatusOK {
-		ctx.Status(status)
-		base.SetForkliftError(ctx, err)
+		base.ReplyError(ctx, status, err)
 		return
 	}
 	if h.WatchRequest {
-		ctx.Status(http.StatusNotImplemented)
+		base.ReplyError(ctx, http.StatusNotImplemented, err)
 		return
 	}
 	storageclasses, err := h.StorageClasses(ctx)
@@ -50,7 +49,7 @@ func (h StorageClassHandler) List(ctx *gin.Context) {
 			err,
 			"url",
 			ctx.Request.URL)
-		ctx.Status(http.StatusInternalServerError)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 	content := []interface{}{}
@@ -69,8 +68,7 @@ func (h StorageClassHandler) List(ctx *gin.Context) {
 func (h StorageClassHandler) Get(ctx *gin.Context) {
 	status, err := h.Prepare(ctx)
 	if status != http.StatusOK {
-		ctx.Status(status)
-		base.SetForkliftError(ctx, err)
+		base.ReplyError(ctx, status, err)
 		return
 	}
 	storageclasses, err := h.StorageClasses(ctx)
@@ -79,7 +77,7 @@ func (h StorageClassHandler) Get(ctx *gin.Context) {
 			err,
 			"url",
 			ctx.Request.URL)
-		ctx.Status(http.StatusInternalServerError)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 	for _, sc := range storageclasses {
@@ -92,7 +90,7 @@ func (h StorageClassHandler) Get(ctx *gin.Context) {
 			return
 		}
 	}
-	ctx.Status(http.StatusNotFound)
+	base.ReplyError(ctx, http.StatusNotFound, err)
 }
 
 // REST Resource.
@@ -37,20 +37,19 @@ func (h *TreeHandler) AddRoutes(e *gin.Engine) {
 
 // List not supported.
 func (h TreeHandler) List(ctx *gin.Context) {
-	ctx.Status(http.StatusMethodNotAllowed)
+	base.ReplyError(ctx, http.StatusMethodNotAllowed, nil)
 }
 
 // Get not supported.
 func (h TreeHandler) Get(ctx *gin.Context) {
-	ctx.Status(http.StatusMethodNotAllowed)
+	base.ReplyError(ctx, http.StatusMethodNotAllowed, nil)
 }
 
 // Prepare to handle the request.
 func (h *TreeHandler) Prepare(ctx *gin.Context) int {
 	status, err := h.Handler.Prepare(ctx)
 	if status != http.StatusOK {
-		ctx.Status(status)
-		base.SetForkliftError(ctx, err)
+		base.ReplyError(ctx, status, err)
 		return status
 	}
 	vms, err := h.VMs(ctx, h.Provider)
@@ -59,6 +58,7 @@ func (h *TreeHandler) Prepare(ctx *gin.Context) int {
 			err,
 			"url",
 			ctx.Request.URL)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
 		return http.StatusInternalServerError
 	}
 
@@ -74,6 +74,7 @@ func (h *TreeHandler) Prepare(ctx *gin.Context) int {
 			err,
 			"url",
 			ctx.Request.URL)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
 		return http.StatusInternalServerError
 	}
 
@@ -95,11 +96,10 @@ func (h *TreeHandler) Prepare(ctx *gin.Context) int {
 func (h TreeHandler) Tree(ctx *gin.Context) {
 	status := h.Prepare(ctx)
 	if status != http.StatusOK {
-		ctx.Status(status)
 		return
 	}
 	if h.WatchRequest {
-		ctx.Status(http.StatusNotImplemented)
+		base.ReplyError(ctx, http.StatusNotImplemented, nil)
 		return
 	}
 
@@ -130,7 +130,7 @@ func (h TreeHandler) Tree(ctx *gin.Context) {
 				err,
 				"url",
 				ctx.Request.URL)
-			ctx.Status(http.StatusInternalServerError)
+			base.ReplyError(ctx, http.StatusInternalServerError, err)
 			return
 		}
 		r := Namespace{}
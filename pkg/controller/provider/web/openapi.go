@@ -0,0 +1,120 @@
+package web
+
+import (
+	"net/http"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/azure"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/catalog"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/ec2"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/image"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/libvirt"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/nutanix"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/ocp"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/openstack"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/ova"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/ovirt"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/vsphere"
+	"github.com/kubev2v/forklift/pkg/lib/audit"
+	libweb "github.com/kubev2v/forklift/pkg/lib/inventory/web"
+)
+
+// OpenAPI document.
+const (
+	APITitle = "Forklift Inventory API"
+	// Version of the API.
+	// Must be incremented when routes or resources are changed.
+	APIVersion = "1.5.0"
+)
+
+// Route (OpenAPI) schemas.
+func Schemas() (schemas []libweb.RouteSchema) {
+	schemas = []libweb.RouteSchema{
+		{Method: http.MethodGet, Path: HealthzRoot, Response: Health{}},
+		{Method: http.MethodGet, Path: ReadyzRoot, Response: Readiness{}},
+		{Method: http.MethodGet, Path: ProvidersRoot, Response: map[string][]Provider{}},
+		{
+			Method:   http.MethodGet,
+			Path:     ReportsMigrationsRoot,
+			Query:    []string{FromParam, ToParam, FormatParam},
+			Response: []MigrationReport{},
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     AuditRoot,
+			Query:    []string{LimitParam},
+			Response: []audit.Record{},
+		},
+		{Method: http.MethodGet, Path: PlanConversionLogRoot, Query: []string{NamespaceParam, FollowParam}},
+		{Method: http.MethodGet, Path: PlanDiagnosticsRoot, Query: []string{NamespaceParam}},
+		{
+			Method:   http.MethodGet,
+			Path:     PlansRoot,
+			Query:    []string{NamespaceParam, NameParam},
+			Response: []Plan{},
+		},
+		{Method: http.MethodGet, Path: PlanRoot, Query: []string{NamespaceParam}, Response: Plan{}},
+		{
+			Method:   http.MethodGet,
+			Path:     PlanVMsRoot,
+			Query:    []string{NamespaceParam, NameParam},
+			Response: []PlanVM{},
+		},
+		{Method: http.MethodGet, Path: PlanVMRoot, Query: []string{NamespaceParam}, Response: PlanVM{}},
+		{
+			Method:   http.MethodGet,
+			Path:     PlanMigrationsRoot,
+			Query:    []string{NamespaceParam},
+			Response: []Migration{},
+		},
+	}
+	for _, kind := range api.ProviderTypes {
+		schemas = append(
+			schemas,
+			libweb.RouteSchema{
+				Method: http.MethodGet,
+				Path:   TargetSpecRoot(kind),
+				Query: []string{
+					NetworkMapParam,
+					StorageMapParam,
+					TargetNsParam,
+					DestinationParam,
+					WarmParam,
+				},
+				Response: TargetSpec{},
+			},
+			libweb.RouteSchema{
+				Method: http.MethodPost,
+				Path:   SimulationRoot(kind),
+				Query: []string{
+					TargetNsParam,
+					DestinationParam,
+					WarmParam,
+				},
+				Request:  SimulationRequest{},
+				Response: Simulation{},
+			},
+			libweb.RouteSchema{
+				Method: http.MethodGet,
+				Path:   ExportRoot(kind),
+			},
+			libweb.RouteSchema{
+				Method:   http.MethodGet,
+				Path:     ExportDiffRoot(kind),
+				Query:    []string{FromParam, ToParam},
+				Response: SnapshotDiff{},
+			})
+	}
+	schemas = append(schemas, ocp.Schemas()...)
+	schemas = append(schemas, vsphere.Schemas()...)
+	schemas = append(schemas, ovirt.Schemas()...)
+	schemas = append(schemas, openstack.Schemas()...)
+	schemas = append(schemas, ova.Schemas()...)
+	schemas = append(schemas, nutanix.Schemas()...)
+	schemas = append(schemas, ec2.Schemas()...)
+	schemas = append(schemas, azure.Schemas()...)
+	schemas = append(schemas, libvirt.Schemas()...)
+	schemas = append(schemas, image.Schemas()...)
+	schemas = append(schemas, catalog.Schemas()...)
+	return
+}
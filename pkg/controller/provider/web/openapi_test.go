@@ -0,0 +1,62 @@
+package web
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/vsphere"
+	libweb "github.com/kubev2v/forklift/pkg/lib/inventory/web"
+	"github.com/onsi/gomega"
+)
+
+func TestOpenAPI(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handlers := All(nil)
+	handlers = append(
+		handlers,
+		&vsphere.VddkHandler{},
+		&AuditHandler{},
+		&ReportHandler{},
+		&ConversionLogHandler{},
+		&DiagnosticsHandler{},
+		&PlanHandler{},
+		&TargetSpecHandler{},
+		&SimulationHandler{})
+	for _, h := range handlers {
+		h.AddRoutes(router)
+	}
+	openapi := &libweb.OpenAPIHandler{
+		Title:   APITitle,
+		Version: APIVersion,
+		Schemas: Schemas(),
+	}
+	doc := openapi.Document(router.Routes())
+	g.Expect(doc.Info.Version).To(gomega.Equal(APIVersion))
+	// Each described route is registered.
+	for _, schema := range Schemas() {
+		path := "/" + strings.TrimPrefix(schema.Path, "/")
+		for _, p := range strings.Split(path, "/") {
+			if strings.HasPrefix(p, ":") {
+				path = strings.Replace(path, p, "{"+p[1:]+"}", 1)
+			}
+		}
+		item, found := doc.Paths[path]
+		g.Expect(found).To(gomega.BeTrue(), path)
+		op, found := (*item)[strings.ToLower(schema.Method)]
+		g.Expect(found).To(gomega.BeTrue(), path)
+		if schema.Response != nil {
+			g.Expect(op.Responses["200"].Content).ToNot(gomega.BeEmpty(), path)
+		}
+	}
+	vm := doc.Components.Schemas["web.vsphere.VM"]
+	g.Expect(vm).ToNot(gomega.BeNil())
+	g.Expect(vm.Properties).To(gomega.HaveKey("selfLink"))
+	g.Expect(vm.Properties).To(gomega.HaveKey("powerState"))
+	_, err := json.Marshal(doc)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+}
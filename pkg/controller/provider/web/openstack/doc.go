@@ -20,6 +20,18 @@ func Handlers(container *container.Container) []libweb.RequestHandler {
 				Container: container,
 			},
 		},
+		&base.HealthHandler{
+			Handler: base.Handler{
+				Container: container,
+			},
+			Kind: api.OpenStack,
+		},
+		&base.ParityHandler{
+			Handler: base.Handler{
+				Container: container,
+			},
+			Kind: api.OpenStack,
+		},
 		&RegionHandler{
 			Handler{
 				base.Handler{Container: container},
@@ -80,5 +92,20 @@ func Handlers(container *container.Container) []libweb.RequestHandler {
 				base.Handler{Container: container},
 			},
 		},
+		&SearchHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
+		&MapHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
+		&PlanHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
 	}
 }
@@ -92,8 +92,7 @@ func (r *Image) With(m *model.Image) {
 func (h ImageHandler) List(ctx *gin.Context) {
 	status, err := h.Prepare(ctx)
 	if status != http.StatusOK {
-		ctx.Status(status)
-		base.SetForkliftError(ctx, err)
+		base.ReplyError(ctx, status, err)
 		return
 	}
 	if h.WatchRequest {
@@ -108,7 +107,7 @@ func (h ImageHandler) List(ctx *gin.Context) {
 			err,
 			"url",
 			ctx.Request.URL)
-		ctx.Status(http.StatusInternalServerError)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 	content := []interface{}{}
@@ -126,8 +125,7 @@ func (h ImageHandler) List(ctx *gin.Context) {
 func (h ImageHandler) Get(ctx *gin.Context) {
 	status, err := h.Prepare(ctx)
 	if status != http.StatusOK {
-		ctx.Status(status)
-		base.SetForkliftError(ctx, err)
+		base.ReplyError(ctx, status, err)
 		return
 	}
 	h.Detail = model.MaxDetail
@@ -139,7 +137,7 @@ func (h ImageHandler) Get(ctx *gin.Context) {
 	db := h.Collector.DB()
 	err = db.Get(m)
 	if errors.Is(err, model.NotFound) {
-		ctx.Status(http.StatusNotFound)
+		base.ReplyError(ctx, http.StatusNotFound, err)
 		return
 	}
 	if err != nil {
@@ -147,7 +145,7 @@ func (h ImageHandler) Get(ctx *gin.Context) {
 			err,
 			"url",
 			ctx.Request.URL)
-		ctx.Status(http.StatusInternalServerError)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 	r := &Image{}
@@ -178,7 +176,7 @@ func (h *ImageHandler) watch(ctx *gin.Context) {
 			err,
 			"url",
 			ctx.Request.URL)
-		ctx.Status(http.StatusInternalServerError)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
 	}
 }
 
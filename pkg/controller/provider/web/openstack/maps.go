@@ -0,0 +1,130 @@
+package openstack
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/openstack"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+)
+
+// Routes.
+const (
+	MapsGenerateRoot  = ProviderRoot + "/" + base.MapsGenerateRoot
+	StorageMatrixRoot = ProviderRoot + "/" + base.StorageMatrixRoot
+)
+
+// Map generation handler.
+type MapHandler struct {
+	Handler
+}
+
+// Add routes to the `gin` router.
+func (h *MapHandler) AddRoutes(e *gin.Engine) {
+	e.POST(MapsGenerateRoot, h.Generate)
+	e.GET(StorageMatrixRoot, h.StorageMatrix)
+}
+
+// Generate network and storage maps for the VMs.
+func (h MapHandler) Generate(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	generator := base.MapGenerator{
+		Container: h.Container,
+		Provider:  h.Provider,
+	}
+	generator.Generate(ctx, h.sources)
+}
+
+// Source storage vs destination storage class matrix.
+func (h MapHandler) StorageMatrix(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	generator := base.MapGenerator{
+		Container: h.Container,
+		Provider:  h.Provider,
+	}
+	generator.Matrix(ctx, h.storage)
+}
+
+// Networks and volume types used by the VM. Image based
+// VMs also use the glance storage.
+func (h MapHandler) sources(vmRef ref.Ref) (sources base.MapSources, err error) {
+	db := h.Collector.DB()
+	vm, err := h.findVM(db, vmRef)
+	if err != nil {
+		return
+	}
+	expanded := XVM{}
+	expanded.VM.With(vm)
+	err = expanded.Expand(db)
+	if err != nil {
+		return
+	}
+	for _, network := range expanded.Networks {
+		sources.Networks = append(sources.Networks, ref.Ref{ID: network.ID, Name: network.Name})
+	}
+	for _, volumeType := range expanded.VolumeTypes {
+		sources.Storage = append(sources.Storage, ref.Ref{ID: volumeType.ID, Name: volumeType.Name})
+	}
+	if vm.ImageID != "" {
+		sources.Storage = append(sources.Storage, ref.Ref{Name: api.GlanceSource})
+	}
+
+	return
+}
+
+// Find the VM by ID or name.
+func (h MapHandler) findVM(db libmodel.DB, vmRef ref.Ref) (vm *model.VM, err error) {
+	predicate := libmodel.Eq("id", vmRef.ID)
+	if vmRef.ID == "" {
+		predicate = libmodel.Eq(NameParam, vmRef.Name)
+	}
+	list := []model.VM{}
+	err = db.List(&list, model.ListOptions{Predicate: predicate, Detail: model.MaxDetail})
+	if err != nil {
+		return
+	}
+	switch len(list) {
+	case 0:
+		err = base.NotFoundError{Ref: vmRef}
+	case 1:
+		vm = &list[0]
+	default:
+		err = base.RefNotUniqueError{Ref: vmRef}
+	}
+
+	return
+}
+
+// Volume types (Cinder volumes are block devices).
+func (h MapHandler) storage() (sources []base.StorageSource, err error) {
+	list := []model.VolumeType{}
+	err = h.Collector.DB().List(&list, model.ListOptions{Detail: model.MaxDetail})
+	if err != nil {
+		return
+	}
+	for _, volumeType := range list {
+		sources = append(
+			sources,
+			base.StorageSource{
+				Ref:   ref.Ref{ID: volumeType.ID, Name: volumeType.Name},
+				Type:  volumeType.ExtraSpecs[VolumeBackendName],
+				Block: true,
+			})
+	}
+
+	return
+}
+
+// Volume type backend extra spec.
+const VolumeBackendName = "volume_backend_name"
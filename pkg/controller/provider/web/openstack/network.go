@@ -40,8 +40,7 @@ func (h *NetworkHandler) AddRoutes(e *gin.Engine) {
 func (h NetworkHandler) List(ctx *gin.Context) {
 	status, err := h.Prepare(ctx)
 	if status != http.StatusOK {
-		ctx.Status(status)
-		base.SetForkliftError(ctx, err)
+		base.ReplyError(ctx, status, err)
 		return
 	}
 	if h.WatchRequest {
@@ -54,7 +53,7 @@ func (h NetworkHandler) List(ctx *gin.Context) {
 				err,
 				"url",
 				ctx.Request.URL)
-			ctx.Status(http.StatusInternalServerError)
+			base.ReplyError(ctx, http.StatusInternalServerError, err)
 		}
 	}()
 	db := h.Collector.DB()
@@ -84,8 +83,7 @@ func (h NetworkHandler) List(ctx *gin.Context) {
 func (h NetworkHandler) Get(ctx *gin.Context) {
 	status, err := h.Prepare(ctx)
 	if status != http.StatusOK {
-		ctx.Status(status)
-		base.SetForkliftError(ctx, err)
+		base.ReplyError(ctx, status, err)
 		return
 	}
 	m := &model.Network{
@@ -96,7 +94,7 @@ func (h NetworkHandler) Get(ctx *gin.Context) {
 	db := h.Collector.DB()
 	err = db.Get(m)
 	if errors.Is(err, model.NotFound) {
-		ctx.Status(http.StatusNotFound)
+		base.ReplyError(ctx, http.StatusNotFound, err)
 		return
 	}
 	if err != nil {
@@ -104,7 +102,7 @@ func (h NetworkHandler) Get(ctx *gin.Context) {
 			err,
 			"url",
 			ctx.Request.URL)
-		ctx.Status(http.StatusInternalServerError)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
 	}
 	pb := PathBuilder{DB: db}
 	r := &Network{}
@@ -138,7 +136,7 @@ func (h *NetworkHandler) watch(ctx *gin.Context) {
 			err,
 			"url",
 			ctx.Request.URL)
-		ctx.Status(http.StatusInternalServerError)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
 	}
 }
 
@@ -0,0 +1,62 @@
+package openstack
+
+import (
+	"net/http"
+
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	libweb "github.com/kubev2v/forklift/pkg/lib/inventory/web"
+)
+
+// Route (OpenAPI) schemas.
+func Schemas() []libweb.RouteSchema {
+	return []libweb.RouteSchema{
+		{Method: http.MethodGet, Path: ProvidersRoot, Response: []Provider{}},
+		{Method: http.MethodGet, Path: ProviderRoot, Response: Provider{}},
+		{Method: http.MethodGet, Path: TreeProjectRoot, Response: TreeNode{}},
+		{Method: http.MethodGet, Path: RegionsRoot, Response: []Region{}},
+		{Method: http.MethodGet, Path: RegionRoot, Response: Region{}},
+		{Method: http.MethodGet, Path: ProjectsRoot, Response: []Project{}},
+		{Method: http.MethodGet, Path: ProjectRoot, Response: Project{}},
+		{Method: http.MethodGet, Path: ImagesRoot, Response: []Image{}},
+		{Method: http.MethodGet, Path: ImageRoot, Response: Image{}},
+		{Method: http.MethodGet, Path: FlavorsRoot, Response: []Flavor{}},
+		{Method: http.MethodGet, Path: FlavorRoot, Response: Flavor{}},
+		{Method: http.MethodGet, Path: VolumesRoot, Response: []Volume{}},
+		{Method: http.MethodGet, Path: VolumeRoot, Response: Volume{}},
+		{Method: http.MethodGet, Path: VolumeTypesRoot, Response: []VolumeType{}},
+		{Method: http.MethodGet, Path: VolumeTypeRoot, Response: VolumeType{}},
+		{Method: http.MethodGet, Path: SnapshotsRoot, Response: []Snapshot{}},
+		{Method: http.MethodGet, Path: SnapshotRoot, Response: Snapshot{}},
+		{Method: http.MethodGet, Path: NetworksRoot, Response: []Network{}},
+		{Method: http.MethodGet, Path: NetworkRoot, Response: Network{}},
+		{Method: http.MethodGet, Path: SubnetsRoot, Response: []Subnet{}},
+		{Method: http.MethodGet, Path: SubnetRoot, Response: Subnet{}},
+		{Method: http.MethodGet, Path: VMsRoot, Response: []VM{}},
+		{Method: http.MethodGet, Path: VMRoot, Response: VM{}},
+		{Method: http.MethodGet, Path: WorkloadRoot, Response: Workload{}},
+		{
+			Method:   http.MethodGet,
+			Path:     SearchRoot,
+			Query:    []string{base.SearchParam, base.LimitParam},
+			Response: []base.SearchHit{},
+		},
+		{
+			Method:   http.MethodPost,
+			Path:     MapsGenerateRoot,
+			Request:  base.MapsRequest{},
+			Response: base.GeneratedMaps{},
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     StorageMatrixRoot,
+			Query:    []string{base.DestinationParam},
+			Response: base.StorageMatrix{},
+		},
+		{
+			Method:   http.MethodPost,
+			Path:     PlansGenerateRoot,
+			Request:  base.PlansRequest{},
+			Response: base.GeneratedPlans{},
+		},
+	}
+}
@@ -0,0 +1,87 @@
+package openstack
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/openstack"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+)
+
+// Routes.
+const (
+	PlansGenerateRoot = ProviderRoot + "/" + base.PlansGenerateRoot
+)
+
+// Plan generation handler.
+type PlanHandler struct {
+	Handler
+}
+
+// Add routes to the `gin` router.
+func (h *PlanHandler) AddRoutes(e *gin.Engine) {
+	e.POST(PlansGenerateRoot, h.Generate)
+}
+
+// Generate plans for the VMs matching the selector.
+func (h PlanHandler) Generate(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	generator := base.PlanGenerator{
+		Container: h.Container,
+		Provider:  h.Provider,
+	}
+	generator.Generate(ctx, h.selectVMs)
+}
+
+// VMs matching the selector, sorted by name.
+func (h PlanHandler) selectVMs(selector base.VMSelector, name *regexp.Regexp) (vms []ref.Ref, err error) {
+	if selector.Folder != "" {
+		err = base.SelectorNotSupportedError{Criteria: "folder"}
+		return
+	}
+	if selector.Cluster != "" {
+		err = base.SelectorNotSupportedError{Criteria: "cluster"}
+		return
+	}
+	db := h.Collector.DB()
+	list := []model.VM{}
+	err = db.List(&list, model.ListOptions{Detail: model.MaxDetail})
+	if err != nil {
+		return
+	}
+	for i := range list {
+		vm := &list[i]
+		if name != nil && !name.MatchString(vm.Name) {
+			continue
+		}
+		if selector.Tag != "" && !hasTag(vm, selector.Tag) {
+			continue
+		}
+		vms = append(vms, ref.Ref{ID: vm.ID, Name: vm.Name})
+	}
+	sort.Slice(vms, func(i, j int) bool {
+		return vms[i].Name < vms[j].Name
+	})
+
+	return
+}
+
+// The VM has the tag.
+func hasTag(vm *model.VM, tag string) bool {
+	if vm.Tags == nil {
+		return false
+	}
+	for _, t := range *vm.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
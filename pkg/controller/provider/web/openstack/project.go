@@ -39,8 +39,7 @@ func (h *ProjectHandler) AddRoutes(e *gin.Engine) {
 func (h ProjectHandler) List(ctx *gin.Context) {
 	status, err := h.Prepare(ctx)
 	if status != http.StatusOK {
-		ctx.Status(status)
-		base.SetForkliftError(ctx, err)
+		base.ReplyError(ctx, status, err)
 		return
 	}
 	if h.WatchRequest {
@@ -53,7 +52,7 @@ func (h ProjectHandler) List(ctx *gin.Context) {
 				err,
 				"url",
 				ctx.Request.URL)
-			ctx.Status(http.StatusInternalServerError)
+			base.ReplyError(ctx, http.StatusInternalServerError, err)
 		}
 	}()
 	db := h.Collector.DB()
@@ -83,8 +82,7 @@ func (h ProjectHandler) List(ctx *gin.Context) {
 func (h ProjectHandler) Get(ctx *gin.Context) {
 	status, err := h.Prepare(ctx)
 	if status != http.StatusOK {
-		ctx.Status(status)
-		base.SetForkliftError(ctx, err)
+		base.ReplyError(ctx, status, err)
 		return
 	}
 	m := &model.Project{
@@ -95,7 +93,7 @@ func (h ProjectHandler) Get(ctx *gin.Context) {
 	db := h.Collector.DB()
 	err = db.Get(m)
 	if errors.Is(err, model.NotFound) {
-		ctx.Status(http.StatusNotFound)
+		base.ReplyError(ctx, http.StatusNotFound, err)
 		return
 	}
 	if err != nil {
@@ -103,7 +101,7 @@ func (h ProjectHandler) Get(ctx *gin.Context) {
 			err,
 			"url",
 			ctx.Request.URL)
-		ctx.Status(http.StatusInternalServerError)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
 	}
 	pb := PathBuilder{DB: db}
 	r := &Project{}
@@ -137,7 +135,7 @@ func (h *ProjectHandler) watch(ctx *gin.Context) {
 			err,
 			"url",
 			ctx.Request.URL)
-		ctx.Status(http.StatusInternalServerError)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
 	}
 }
 
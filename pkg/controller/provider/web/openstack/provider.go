@@ -27,21 +27,19 @@ type ProviderHandler struct {
 func (h *ProviderHandler) AddRoutes(e *gin.Engine) {
 	e.GET(ProvidersRoot, h.List)
 	e.GET(ProvidersRoot+"/", h.List)
+	base.DefaultAuth.Require(ProvidersRoot, base.Authenticated)
 	e.GET(ProviderRoot, h.Get)
 }
 
 // List resources in a REST collection.
 func (h ProviderHandler) List(ctx *gin.Context) {
 	status, err := h.Prepare(ctx)
-	if err != nil {
-		return
-	}
 	if status != http.StatusOK {
-		ctx.Status(status)
+		base.ReplyError(ctx, status, err)
 		return
 	}
 	if h.WatchRequest {
-		ctx.Status(http.StatusBadRequest)
+		base.ReplyError(ctx, http.StatusBadRequest, err)
 		return
 	}
 	content, err := h.ListContent(ctx)
@@ -50,7 +48,7 @@ func (h ProviderHandler) List(ctx *gin.Context) {
 			err,
 			"url",
 			ctx.Request.URL)
-		ctx.Status(http.StatusInternalServerError)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -60,15 +58,12 @@ func (h ProviderHandler) List(ctx *gin.Context) {
 // Get a specific REST resource.
 func (h ProviderHandler) Get(ctx *gin.Context) {
 	status, err := h.Prepare(ctx)
-	if err != nil {
-		return
-	}
 	if status != http.StatusOK {
-		ctx.Status(status)
+		base.ReplyError(ctx, status, err)
 		return
 	}
 	if h.Provider.Type() != api.OpenStack {
-		ctx.Status(http.StatusNotFound)
+		base.ReplyError(ctx, http.StatusNotFound, err)
 		return
 	}
 	h.Detail = model.MaxDetail
@@ -82,7 +77,7 @@ func (h ProviderHandler) Get(ctx *gin.Context) {
 			err,
 			"url",
 			ctx.Request.URL)
-		ctx.Status(http.StatusInternalServerError)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 	r.Link()
@@ -102,6 +97,9 @@ func (h *ProviderHandler) ListContent(ctx *gin.Context) (content []interface{},
 			if p.Type() != api.OpenStack || (ns != "" && ns != p.Namespace) {
 				continue
 			}
+			if !h.Visible(ctx, p) {
+				continue
+			}
 			collector, found := h.Container.Get(p)
 			if !found {
 				continue
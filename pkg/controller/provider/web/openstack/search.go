@@ -0,0 +1,249 @@
+package openstack
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/openstack"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+)
+
+// Routes.
+const (
+	SearchRoot = ProviderRoot + "/" + base.SearchCollection
+)
+
+// Search handler.
+type SearchHandler struct {
+	Handler
+}
+
+// Add routes to the `gin` router.
+func (h *SearchHandler) AddRoutes(e *gin.Engine) {
+	e.GET(SearchRoot, h.Search)
+}
+
+// Search the names, IDs, IPs and MACs of the provider inventory.
+func (h SearchHandler) Search(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	base.Search(ctx, h.Provider, h.entries)
+}
+
+// Searched resources.
+func (h SearchHandler) entries() (entries []base.SearchEntry, err error) {
+	db := h.Collector.DB()
+	options := model.ListOptions{Detail: model.MaxDetail}
+	regions := []model.Region{}
+	err = db.List(&regions, options)
+	if err != nil {
+		return
+	}
+	for i := range regions {
+		m := &regions[i]
+		r := &Region{}
+		r.With(m)
+		r.Link(h.Provider)
+		entries = append(
+			entries,
+			base.SearchEntry{
+				Kind:     model.RegionKind,
+				ID:       m.ID,
+				Name:     m.Name,
+				SelfLink: r.SelfLink,
+			})
+	}
+	projects := []model.Project{}
+	err = db.List(&projects, options)
+	if err != nil {
+		return
+	}
+	for i := range projects {
+		m := &projects[i]
+		r := &Project{}
+		r.With(m)
+		r.Link(h.Provider)
+		entries = append(
+			entries,
+			base.SearchEntry{
+				Kind:     model.ProjectKind,
+				ID:       m.ID,
+				Name:     m.Name,
+				SelfLink: r.SelfLink,
+			})
+	}
+	images := []model.Image{}
+	err = db.List(&images, options)
+	if err != nil {
+		return
+	}
+	for i := range images {
+		m := &images[i]
+		r := &Image{}
+		r.With(m)
+		r.Link(h.Provider)
+		entries = append(
+			entries,
+			base.SearchEntry{
+				Kind:     model.ImageKind,
+				ID:       m.ID,
+				Name:     m.Name,
+				SelfLink: r.SelfLink,
+			})
+	}
+	flavors := []model.Flavor{}
+	err = db.List(&flavors, options)
+	if err != nil {
+		return
+	}
+	for i := range flavors {
+		m := &flavors[i]
+		r := &Flavor{}
+		r.With(m)
+		r.Link(h.Provider)
+		entries = append(
+			entries,
+			base.SearchEntry{
+				Kind:     model.FlavorKind,
+				ID:       m.ID,
+				Name:     m.Name,
+				SelfLink: r.SelfLink,
+			})
+	}
+	volumes := []model.Volume{}
+	err = db.List(&volumes, options)
+	if err != nil {
+		return
+	}
+	for i := range volumes {
+		m := &volumes[i]
+		r := &Volume{}
+		r.With(m)
+		r.Link(h.Provider)
+		entries = append(
+			entries,
+			base.SearchEntry{
+				Kind:     model.VolumeKind,
+				ID:       m.ID,
+				Name:     m.Name,
+				SelfLink: r.SelfLink,
+			})
+	}
+	volumeTypes := []model.VolumeType{}
+	err = db.List(&volumeTypes, options)
+	if err != nil {
+		return
+	}
+	for i := range volumeTypes {
+		m := &volumeTypes[i]
+		r := &VolumeType{}
+		r.With(m)
+		r.Link(h.Provider)
+		entries = append(
+			entries,
+			base.SearchEntry{
+				Kind:     model.VolumeTypeKind,
+				ID:       m.ID,
+				Name:     m.Name,
+				SelfLink: r.SelfLink,
+			})
+	}
+	snapshots := []model.Snapshot{}
+	err = db.List(&snapshots, options)
+	if err != nil {
+		return
+	}
+	for i := range snapshots {
+		m := &snapshots[i]
+		r := &Snapshot{}
+		r.With(m)
+		r.Link(h.Provider)
+		entries = append(
+			entries,
+			base.SearchEntry{
+				Kind:     model.SnapshotKind,
+				ID:       m.ID,
+				Name:     m.Name,
+				SelfLink: r.SelfLink,
+			})
+	}
+	networks := []model.Network{}
+	err = db.List(&networks, options)
+	if err != nil {
+		return
+	}
+	for i := range networks {
+		m := &networks[i]
+		r := &Network{}
+		r.With(m)
+		r.Link(h.Provider)
+		entries = append(
+			entries,
+			base.SearchEntry{
+				Kind:     model.NetworkKind,
+				ID:       m.ID,
+				Name:     m.Name,
+				SelfLink: r.SelfLink,
+			})
+	}
+	subnets := []model.Subnet{}
+	err = db.List(&subnets, options)
+	if err != nil {
+		return
+	}
+	for i := range subnets {
+		m := &subnets[i]
+		r := &Subnet{}
+		r.With(m)
+		r.Link(h.Provider)
+		entries = append(
+			entries,
+			base.SearchEntry{
+				Kind:     model.SubnetKind,
+				ID:       m.ID,
+				Name:     m.Name,
+				SelfLink: r.SelfLink,
+			})
+	}
+	vms := []model.VM{}
+	err = db.List(&vms, options)
+	if err != nil {
+		return
+	}
+	for i := range vms {
+		m := &vms[i]
+		r := &VM{}
+		r.With(m)
+		r.Link(h.Provider)
+		entry := base.SearchEntry{
+			Kind:     model.VMKind,
+			ID:       m.ID,
+			Name:     m.Name,
+			SelfLink: r.SelfLink,
+		}
+		for _, vmAddresses := range m.Addresses {
+			addresses, cast := vmAddresses.([]interface{})
+			if !cast {
+				continue
+			}
+			for _, address := range addresses {
+				a, cast := address.(map[string]interface{})
+				if !cast {
+					continue
+				}
+				if ip, cast := a["addr"].(string); cast && ip != "" {
+					entry.IPs = append(entry.IPs, ip)
+				}
+				if mac, cast := a["OS-EXT-IPS-MAC:mac_addr"].(string); cast && mac != "" {
+					entry.MACs = append(entry.MACs, mac)
+				}
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	return
+}
@@ -36,8 +36,7 @@ func (h *TreeHandler) AddRoutes(e *gin.Engine) {
 func (h *TreeHandler) Prepare(ctx *gin.Context) int {
 	status, err := h.Handler.Prepare(ctx)
 	if status != http.StatusOK {
-		ctx.Status(status)
-		base.SetForkliftError(ctx, err)
+		base.ReplyError(ctx, status, err)
 		return status
 	}
 	db := h.Collector.DB()
@@ -51,6 +50,7 @@ func (h *TreeHandler) Prepare(ctx *gin.Context) int {
 			err,
 			"url",
 			ctx.Request.URL)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
 		return http.StatusInternalServerError
 	}
 
@@ -59,23 +59,22 @@ func (h *TreeHandler) Prepare(ctx *gin.Context) int {
 
 // List not supported.
 func (h TreeHandler) List(ctx *gin.Context) {
-	ctx.Status(http.StatusMethodNotAllowed)
+	base.ReplyError(ctx, http.StatusMethodNotAllowed, nil)
 }
 
 // Get not supported.
 func (h TreeHandler) Get(ctx *gin.Context) {
-	ctx.Status(http.StatusMethodNotAllowed)
+	base.ReplyError(ctx, http.StatusMethodNotAllowed, nil)
 }
 
 // Tree.
 func (h TreeHandler) Tree(ctx *gin.Context) {
 	status := h.Prepare(ctx)
 	if status != http.StatusOK {
-		ctx.Status(status)
 		return
 	}
 	if h.WatchRequest {
-		ctx.Status(http.StatusBadRequest)
+		base.ReplyError(ctx, http.StatusBadRequest, nil)
 		return
 	}
 	db := h.Collector.DB()
@@ -102,7 +101,7 @@ func (h TreeHandler) Tree(ctx *gin.Context) {
 				err,
 				"url",
 				ctx.Request.URL)
-			ctx.Status(http.StatusInternalServerError)
+			base.ReplyError(ctx, http.StatusInternalServerError, err)
 			return
 		}
 		r := Project{}
@@ -39,11 +39,8 @@ func (h *VMHandler) AddRoutes(e *gin.Engine) {
 // to a websocket and push watch events.
 func (h VMHandler) List(ctx *gin.Context) {
 	status, err := h.Prepare(ctx)
-	if err != nil {
-		return
-	}
 	if status != http.StatusOK {
-		ctx.Status(status)
+		base.ReplyError(ctx, status, err)
 		return
 	}
 	if h.WatchRequest {
@@ -56,7 +53,7 @@ func (h VMHandler) List(ctx *gin.Context) {
 				err,
 				"url",
 				ctx.Request.URL)
-			ctx.Status(http.StatusInternalServerError)
+			base.ReplyError(ctx, http.StatusInternalServerError, err)
 		}
 	}()
 	db := h.Collector.DB()
@@ -85,11 +82,8 @@ func (h VMHandler) List(ctx *gin.Context) {
 // Get a specific REST resource.
 func (h VMHandler) Get(ctx *gin.Context) {
 	status, err := h.Prepare(ctx)
-	if err != nil {
-		return
-	}
 	if status != http.StatusOK {
-		ctx.Status(status)
+		base.ReplyError(ctx, status, err)
 		return
 	}
 	m := &model.VM{
@@ -101,7 +95,7 @@ func (h VMHandler) Get(ctx *gin.Context) {
 	db := h.Collector.DB()
 	err = db.Get(m)
 	if errors.Is(err, model.NotFound) {
-		ctx.Status(http.StatusNotFound)
+		base.ReplyError(ctx, http.StatusNotFound, err)
 		return
 	}
 	if err != nil {
@@ -109,7 +103,7 @@ func (h VMHandler) Get(ctx *gin.Context) {
 			err,
 			"url",
 			ctx.Request.URL)
-		ctx.Status(http.StatusInternalServerError)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 	pb := PathBuilder{DB: db}
@@ -144,7 +138,7 @@ func (h *VMHandler) watch(ctx *gin.Context) {
 			err,
 			"url",
 			ctx.Request.URL)
-		ctx.Status(http.StatusInternalServerError)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
 	}
 }
 
@@ -191,6 +185,7 @@ type VM1 struct {
 	FlavorID          string                 `json:"flavorID"`
 	Addresses         map[string]interface{} `json:"addresses"`
 	AttachedVolumes   []AttachedVolume       `json:"attachedVolumes,omitempty"`
+	Ports             []Port                 `json:"ports,omitempty"`
 	Concerns          []Concern              `json:"concerns"`
 }
 
@@ -205,6 +200,7 @@ func (r *VM1) With(m *model.VM) {
 	r.FlavorID = m.FlavorID
 	r.Addresses = m.Addresses
 	r.AttachedVolumes = m.AttachedVolumes
+	r.Ports = m.Ports
 	r.Concerns = m.Concerns
 }
 
@@ -233,9 +229,12 @@ type VM struct {
 	Fault          Fault                    `json:"fault"`
 	Tags           *[]string                `json:"tags,omitempty"`
 	ServerGroups   *[]string                `json:"serverGroups,omitempty"`
+	ConfigDrive    bool                     `json:"configDrive,omitempty"`
+	UserData       string                   `json:"userData,omitempty"`
 }
 
 type AttachedVolume = model.AttachedVolume
+type Port = model.Port
 type Concern = model.Concern
 type Fault = model.Fault
 
@@ -255,6 +254,8 @@ func (r *VM) With(m *model.VM) {
 	r.Fault = m.Fault
 	r.Tags = m.Tags
 	r.ServerGroups = m.ServerGroups
+	r.ConfigDrive = m.ConfigDrive
+	r.UserData = m.UserData
 }
 
 // Build self link (URI).
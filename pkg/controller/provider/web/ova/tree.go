@@ -35,8 +35,7 @@ func (h *TreeHandler) AddRoutes(e *gin.Engine) {
 func (h *TreeHandler) Prepare(ctx *gin.Context) int {
 	status, err := h.Handler.Prepare(ctx)
 	if status != http.StatusOK {
-		ctx.Status(status)
-		base.SetForkliftError(ctx, err)
+		base.ReplyError(ctx, status, err)
 		return status
 	}
 	db := h.Collector.DB()
@@ -58,12 +57,12 @@ func (h *TreeHandler) Prepare(ctx *gin.Context) int {
 
 // List not supported.
 func (h TreeHandler) List(ctx *gin.Context) {
-	ctx.Status(http.StatusMethodNotAllowed)
+	base.ReplyError(ctx, http.StatusMethodNotAllowed, nil)
 }
 
 // Get not supported.
 func (h TreeHandler) Get(ctx *gin.Context) {
-	ctx.Status(http.StatusMethodNotAllowed)
+	base.ReplyError(ctx, http.StatusMethodNotAllowed, nil)
 }
 
 // Tree.
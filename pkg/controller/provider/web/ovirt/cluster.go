@@ -39,8 +39,7 @@ func (h *ClusterHandler) AddRoutes(e *gin.Engine) {
 func (h ClusterHandler) List(ctx *gin.Context) {
 	status, err := h.Prepare(ctx)
 	if status != http.StatusOK {
-		ctx.Status(status)
-		base.SetForkliftError(ctx, err)
+		base.ReplyError(ctx, status, err)
 		return
 	}
 	if h.WatchRequest {
@@ -53,7 +52,7 @@ func (h ClusterHandler) List(ctx *gin.Context) {
 				err,
 				"url",
 				ctx.Request.URL)
-			ctx.Status(http.StatusInternalServerError)
+			base.ReplyError(ctx, http.StatusInternalServerError, err)
 		}
 	}()
 	db := h.Collector.DB()
@@ -83,8 +82,7 @@ func (h ClusterHandler) List(ctx *gin.Context) {
 func (h ClusterHandler) Get(ctx *gin.Context) {
 	status, err := h.Prepare(ctx)
 	if status != http.StatusOK {
-		ctx.Status(status)
-		base.SetForkliftError(ctx, err)
+		base.ReplyError(ctx, status, err)
 		return
 	}
 	m := &model.Cluster{
@@ -95,7 +93,7 @@ func (h ClusterHandler) Get(ctx *gin.Context) {
 	db := h.Collector.DB()
 	err = db.Get(m)
 	if errors.Is(err, model.NotFound) {
-		ctx.Status(http.StatusNotFound)
+		base.ReplyError(ctx, http.StatusNotFound, err)
 		return
 	}
 	if err != nil {
@@ -103,7 +101,7 @@ func (h ClusterHandler) Get(ctx *gin.Context) {
 			err,
 			"url",
 			ctx.Request.URL)
-		ctx.Status(http.StatusInternalServerError)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 	pb := PathBuilder{DB: db}
@@ -138,7 +136,7 @@ func (h *ClusterHandler) watch(ctx *gin.Context) {
 			err,
 			"url",
 			ctx.Request.URL)
-		ctx.Status(http.StatusInternalServerError)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
 	}
 }
 
@@ -174,16 +172,18 @@ func (h *ClusterHandler) filter(ctx *gin.Context, list *[]model.Cluster) (err er
 // REST Resource.
 type Cluster struct {
 	Resource
-	DataCenter    string  `json:"dataCenter"`
-	HaReservation bool    `json:"haReservation"`
-	KsmEnabled    bool    `json:"ksmEnabled"`
-	BiosType      string  `json:"biosType"`
-	CPU           CPU     `json:"cpu"`
-	Version       Version `json:"version"`
+	DataCenter     string          `json:"dataCenter"`
+	HaReservation  bool            `json:"haReservation"`
+	KsmEnabled     bool            `json:"ksmEnabled"`
+	BiosType       string          `json:"biosType"`
+	CPU            CPU             `json:"cpu"`
+	Version        Version         `json:"version"`
+	AffinityGroups []AffinityGroup `json:"affinityGroups,omitempty"`
 }
 
 type CPU = model.CPU
 type Version = model.Version
+type AffinityGroup = model.AffinityGroup
 
 // Build the resource using the model.
 func (r *Cluster) With(m *model.Cluster) {
@@ -194,6 +194,7 @@ func (r *Cluster) With(m *model.Cluster) {
 	r.BiosType = m.BiosType
 	r.CPU = m.CPU
 	r.Version = m.Version
+	r.AffinityGroups = m.AffinityGroups
 }
 
 // Build self link (URI).
@@ -0,0 +1,148 @@
+package ovirt
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/ovirt"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+)
+
+// Routes.
+const (
+	MapsGenerateRoot  = ProviderRoot + "/" + base.MapsGenerateRoot
+	StorageMatrixRoot = ProviderRoot + "/" + base.StorageMatrixRoot
+)
+
+// Map generation handler.
+type MapHandler struct {
+	Handler
+}
+
+// Add routes to the `gin` router.
+func (h *MapHandler) AddRoutes(e *gin.Engine) {
+	e.POST(MapsGenerateRoot, h.Generate)
+	e.GET(StorageMatrixRoot, h.StorageMatrix)
+}
+
+// Generate network and storage maps for the VMs.
+func (h MapHandler) Generate(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	generator := base.MapGenerator{
+		Container: h.Container,
+		Provider:  h.Provider,
+	}
+	generator.Generate(ctx, h.sources)
+}
+
+// Source storage vs destination storage class matrix.
+func (h MapHandler) StorageMatrix(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	generator := base.MapGenerator{
+		Container: h.Container,
+		Provider:  h.Provider,
+	}
+	generator.Matrix(ctx, h.storage)
+}
+
+// Networks and storage domains used by the VM.
+func (h MapHandler) sources(vmRef ref.Ref) (sources base.MapSources, err error) {
+	db := h.Collector.DB()
+	vm, err := h.findVM(db, vmRef)
+	if err != nil {
+		return
+	}
+	for _, nic := range vm.NICs {
+		if nic.Profile == "" {
+			continue
+		}
+		profile := &model.NICProfile{Base: model.Base{ID: nic.Profile}}
+		err = db.Get(profile)
+		if err != nil {
+			return
+		}
+		network := &model.Network{Base: model.Base{ID: profile.Network}}
+		err = db.Get(network)
+		if err != nil {
+			return
+		}
+		sources.Networks = append(sources.Networks, ref.Ref{ID: network.ID, Name: network.Name})
+	}
+	for _, da := range vm.DiskAttachments {
+		disk := &model.Disk{Base: model.Base{ID: da.Disk}}
+		err = db.Get(disk)
+		if err != nil {
+			return
+		}
+		if disk.StorageType == "lun" {
+			continue
+		}
+		sd := &model.StorageDomain{Base: model.Base{ID: disk.StorageDomain}}
+		err = db.Get(sd)
+		if err != nil {
+			return
+		}
+		sources.Storage = append(sources.Storage, ref.Ref{ID: sd.ID, Name: sd.Name})
+	}
+
+	return
+}
+
+// Find the VM by ID or name.
+func (h MapHandler) findVM(db libmodel.DB, vmRef ref.Ref) (vm *model.VM, err error) {
+	predicate := libmodel.Eq("id", vmRef.ID)
+	if vmRef.ID == "" {
+		predicate = libmodel.Eq(NameParam, vmRef.Name)
+	}
+	list := []model.VM{}
+	err = db.List(&list, model.ListOptions{Predicate: predicate, Detail: model.MaxDetail})
+	if err != nil {
+		return
+	}
+	switch len(list) {
+	case 0:
+		err = base.NotFoundError{Ref: vmRef}
+	case 1:
+		vm = &list[0]
+	default:
+		err = base.RefNotUniqueError{Ref: vmRef}
+	}
+
+	return
+}
+
+// Storage domains.
+func (h MapHandler) storage() (sources []base.StorageSource, err error) {
+	list := []model.StorageDomain{}
+	err = h.Collector.DB().List(&list, model.ListOptions{Detail: model.MaxDetail})
+	if err != nil {
+		return
+	}
+	for _, sd := range list {
+		sources = append(
+			sources,
+			base.StorageSource{
+				Ref:   ref.Ref{ID: sd.ID, Name: sd.Name},
+				Type:  sd.Storage.Type,
+				Block: blockStorageDomain[sd.Storage.Type],
+			})
+	}
+
+	return
+}
+
+// Block backed storage domain types.
+var blockStorageDomain = map[string]bool{
+	"iscsi": true,
+	"fcp":   true,
+}
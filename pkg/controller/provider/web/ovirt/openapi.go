@@ -0,0 +1,62 @@
+package ovirt
+
+import (
+	"net/http"
+
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	libweb "github.com/kubev2v/forklift/pkg/lib/inventory/web"
+)
+
+// Route (OpenAPI) schemas.
+func Schemas() []libweb.RouteSchema {
+	return []libweb.RouteSchema{
+		{Method: http.MethodGet, Path: ProvidersRoot, Response: []Provider{}},
+		{Method: http.MethodGet, Path: ProviderRoot, Response: Provider{}},
+		{Method: http.MethodGet, Path: TreeClusterRoot, Response: TreeNode{}},
+		{Method: http.MethodGet, Path: DataCentersRoot, Response: []DataCenter{}},
+		{Method: http.MethodGet, Path: DataCenterRoot, Response: DataCenter{}},
+		{Method: http.MethodGet, Path: ClustersRoot, Response: []Cluster{}},
+		{Method: http.MethodGet, Path: ClusterRoot, Response: Cluster{}},
+		{Method: http.MethodGet, Path: ServerCpusRoot, Response: []ServerCpu{}},
+		{Method: http.MethodGet, Path: ServerCpuRoot, Response: ServerCpu{}},
+		{Method: http.MethodGet, Path: HostsRoot, Response: []Host{}},
+		{Method: http.MethodGet, Path: HostRoot, Response: Host{}},
+		{Method: http.MethodGet, Path: NetworksRoot, Response: []Network{}},
+		{Method: http.MethodGet, Path: NetworkRoot, Response: Network{}},
+		{Method: http.MethodGet, Path: NICProfilesRoot, Response: []NICProfile{}},
+		{Method: http.MethodGet, Path: NICProfileRoot, Response: NICProfile{}},
+		{Method: http.MethodGet, Path: DiskProfilesRoot, Response: []DiskProfile{}},
+		{Method: http.MethodGet, Path: DiskProfileRoot, Response: DiskProfile{}},
+		{Method: http.MethodGet, Path: StorageDomainsRoot, Response: []StorageDomain{}},
+		{Method: http.MethodGet, Path: StorageDomainRoot, Response: StorageDomain{}},
+		{Method: http.MethodGet, Path: DisksRoot, Response: []Disk{}},
+		{Method: http.MethodGet, Path: DiskRoot, Response: Disk{}},
+		{Method: http.MethodGet, Path: VMsRoot, Response: []VM{}},
+		{Method: http.MethodGet, Path: VMRoot, Response: VM{}},
+		{Method: http.MethodGet, Path: WorkloadRoot, Response: Workload{}},
+		{
+			Method:   http.MethodGet,
+			Path:     SearchRoot,
+			Query:    []string{base.SearchParam, base.LimitParam},
+			Response: []base.SearchHit{},
+		},
+		{
+			Method:   http.MethodPost,
+			Path:     MapsGenerateRoot,
+			Request:  base.MapsRequest{},
+			Response: base.GeneratedMaps{},
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     StorageMatrixRoot,
+			Query:    []string{base.DestinationParam},
+			Response: base.StorageMatrix{},
+		},
+		{
+			Method:   http.MethodPost,
+			Path:     PlansGenerateRoot,
+			Request:  base.PlansRequest{},
+			Response: base.GeneratedPlans{},
+		},
+	}
+}
@@ -0,0 +1,87 @@
+package ovirt
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/ovirt"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+)
+
+// Routes.
+const (
+	PlansGenerateRoot = ProviderRoot + "/" + base.PlansGenerateRoot
+)
+
+// Plan generation handler.
+type PlanHandler struct {
+	Handler
+}
+
+// Add routes to the `gin` router.
+func (h *PlanHandler) AddRoutes(e *gin.Engine) {
+	e.POST(PlansGenerateRoot, h.Generate)
+}
+
+// Generate plans for the VMs matching the selector.
+func (h PlanHandler) Generate(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	generator := base.PlanGenerator{
+		Container: h.Container,
+		Provider:  h.Provider,
+	}
+	generator.Generate(ctx, h.selectVMs)
+}
+
+// VMs matching the selector, sorted by name.
+func (h PlanHandler) selectVMs(selector base.VMSelector, name *regexp.Regexp) (vms []ref.Ref, err error) {
+	if selector.Folder != "" {
+		err = base.SelectorNotSupportedError{Criteria: "folder"}
+		return
+	}
+	if selector.Tag != "" {
+		err = base.SelectorNotSupportedError{Criteria: "tag"}
+		return
+	}
+	db := h.Collector.DB()
+	list := []model.VM{}
+	err = db.List(&list, model.ListOptions{Detail: model.MaxDetail})
+	if err != nil {
+		return
+	}
+	clusters := map[string]string{}
+	if selector.Cluster != "" {
+		clusterList := []model.Cluster{}
+		err = db.List(&clusterList, model.ListOptions{})
+		if err != nil {
+			return
+		}
+		for _, cluster := range clusterList {
+			clusters[cluster.ID] = cluster.Name
+		}
+	}
+	for i := range list {
+		vm := &list[i]
+		if name != nil && !name.MatchString(vm.Name) {
+			continue
+		}
+		if selector.Cluster != "" &&
+			vm.Cluster != selector.Cluster &&
+			clusters[vm.Cluster] != selector.Cluster {
+			continue
+		}
+		vms = append(vms, ref.Ref{ID: vm.ID, Name: vm.Name})
+	}
+	sort.Slice(vms, func(i, j int) bool {
+		return vms[i].Name < vms[j].Name
+	})
+
+	return
+}
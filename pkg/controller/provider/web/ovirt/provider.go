@@ -27,6 +27,7 @@ type ProviderHandler struct {
 func (h *ProviderHandler) AddRoutes(e *gin.Engine) {
 	e.GET(ProvidersRoot, h.List)
 	e.GET(ProvidersRoot+"/", h.List)
+	base.DefaultAuth.Require(ProvidersRoot, base.Authenticated)
 	e.GET(ProviderRoot, h.Get)
 }
 
@@ -34,12 +35,11 @@ func (h *ProviderHandler) AddRoutes(e *gin.Engine) {
 func (h ProviderHandler) List(ctx *gin.Context) {
 	status, err := h.Prepare(ctx)
 	if status != http.StatusOK {
-		ctx.Status(status)
-		base.SetForkliftError(ctx, err)
+		base.ReplyError(ctx, status, err)
 		return
 	}
 	if h.WatchRequest {
-		ctx.Status(http.StatusBadRequest)
+		base.ReplyError(ctx, http.StatusBadRequest, err)
 		return
 	}
 	content, err := h.ListContent(ctx)
@@ -48,7 +48,7 @@ func (h ProviderHandler) List(ctx *gin.Context) {
 			err,
 			"url",
 			ctx.Request.URL)
-		ctx.Status(http.StatusInternalServerError)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -59,12 +59,11 @@ func (h ProviderHandler) List(ctx *gin.Context) {
 func (h ProviderHandler) Get(ctx *gin.Context) {
 	status, err := h.Prepare(ctx)
 	if status != http.StatusOK {
-		ctx.Status(status)
-		base.SetForkliftError(ctx, err)
+		base.ReplyError(ctx, status, err)
 		return
 	}
 	if h.Provider.Type() != api.OVirt {
-		ctx.Status(http.StatusNotFound)
+		base.ReplyError(ctx, http.StatusNotFound, err)
 		return
 	}
 	h.Detail = model.MaxDetail
@@ -78,7 +77,7 @@ func (h ProviderHandler) Get(ctx *gin.Context) {
 			err,
 			"url",
 			ctx.Request.URL)
-		ctx.Status(http.StatusInternalServerError)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 	r.Link()
@@ -98,6 +97,9 @@ func (h *ProviderHandler) ListContent(ctx *gin.Context) (content []interface{},
 			if p.Type() != api.OVirt || (ns != "" && ns != p.Namespace) {
 				continue
 			}
+			if !h.Visible(ctx, p) {
+				continue
+			}
 			collector, found := h.Container.Get(p)
 			if !found {
 				continue
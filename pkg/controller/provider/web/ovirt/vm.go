@@ -50,8 +50,7 @@ func (h *VMHandler) AddRoutes(e *gin.Engine) {
 func (h VMHandler) List(ctx *gin.Context) {
 	status, err := h.Prepare(ctx)
 	if status != http.StatusOK {
-		ctx.Status(status)
-		base.SetForkliftError(ctx, err)
+		base.ReplyError(ctx, status, err)
 		return
 	}
 	if h.WatchRequest {
@@ -64,7 +63,7 @@ func (h VMHandler) List(ctx *gin.Context) {
 				err,
 				"url",
 				ctx.Request.URL)
-			ctx.Status(http.StatusInternalServerError)
+			base.ReplyError(ctx, http.StatusInternalServerError, err)
 		}
 	}()
 	db := h.Collector.DB()
@@ -94,8 +93,7 @@ func (h VMHandler) List(ctx *gin.Context) {
 func (h VMHandler) Get(ctx *gin.Context) {
 	status, err := h.Prepare(ctx)
 	if status != http.StatusOK {
-		ctx.Status(status)
-		base.SetForkliftError(ctx, err)
+		base.ReplyError(ctx, status, err)
 		return
 	}
 	m := &model.VM{
@@ -107,7 +105,7 @@ func (h VMHandler) Get(ctx *gin.Context) {
 	db := h.Collector.DB()
 	err = db.Get(m)
 	if errors.Is(err, model.NotFound) {
-		ctx.Status(http.StatusNotFound)
+		base.ReplyError(ctx, http.StatusNotFound, err)
 		return
 	}
 	if err != nil {
@@ -115,7 +113,7 @@ func (h VMHandler) Get(ctx *gin.Context) {
 			err,
 			"url",
 			ctx.Request.URL)
-		ctx.Status(http.StatusInternalServerError)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 	pb := PathBuilder{DB: db}
@@ -150,7 +148,7 @@ func (h *VMHandler) watch(ctx *gin.Context) {
 			err,
 			"url",
 			ctx.Request.URL)
-		ctx.Status(http.StatusInternalServerError)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
 	}
 }
 
@@ -254,6 +252,7 @@ type VM struct {
 	Guest                       Guest            `json:"guest"`
 	OSType                      string           `json:"osType"`
 	CustomCpuModel              string           `json:"customCpuModel"`
+	Initialization              Initialization   `json:"initialization"`
 }
 
 type VNIC = model.NIC
@@ -267,6 +266,7 @@ type Property = model.Property
 type Snapshot = model.Snapshot
 type Concern = model.Concern
 type Guest = model.Guest
+type Initialization = model.Initialization
 
 // Build the resource using the model.
 func (r *VM) With(m *model.VM) {
@@ -305,6 +305,7 @@ func (r *VM) With(m *model.VM) {
 	r.Guest = m.Guest
 	r.OSType = m.OSType
 	r.CustomCpuModel = m.CustomCpuModel
+	r.Initialization = m.Initialization
 }
 
 // Build self link (URI).
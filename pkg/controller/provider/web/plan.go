@@ -0,0 +1,541 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	libweb "github.com/kubev2v/forklift/pkg/lib/inventory/web"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Routes.
+const (
+	PlansRoot          = "/plans"
+	PlanRoot           = PlansRoot + "/:" + PlanParam
+	PlanVMsRoot        = PlanRoot + "/vms"
+	PlanVMRoot         = PlanVMsRoot + "/:" + VMParam
+	PlanMigrationsRoot = PlanRoot + "/migrations"
+	NameParam          = "name"
+)
+
+// Plan and VM states.
+const (
+	StateNotReady  = "NotReady"
+	StateReady     = libcnd.Ready
+	StateExecuting = api.ConditionExecuting
+	StatePending   = api.ConditionPending
+	StateRunning   = api.ConditionRunning
+	StateSucceeded = api.ConditionSucceeded
+	StateFailed    = api.ConditionFailed
+	StateCanceled  = api.ConditionCanceled
+)
+
+// Plan handler.
+// Serves the plans, their migrations and the status (pipeline)
+// of the plan VMs so that UIs do not need to read the CRs. The
+// collections are paged, filtered by the `namespace` and `name`
+// params and watched (X-Watch) by polling the reader.
+type PlanHandler struct {
+	libweb.Paged
+	libweb.Watched
+	// Reader of the plans and migrations. Watches poll
+	// the reader so it is expected to be cached.
+	Client client.Reader
+}
+
+// Add routes to the `gin` router.
+func (h *PlanHandler) AddRoutes(e *gin.Engine) {
+	e.GET(PlansRoot, h.List)
+	e.GET(PlansRoot+"/", h.List)
+	e.GET(PlanRoot, h.Get)
+	e.GET(PlanVMsRoot, h.VMs)
+	e.GET(PlanVMRoot, h.VM)
+	e.GET(PlanMigrationsRoot, h.Migrations)
+}
+
+// Prepare to handle the request.
+func (h *PlanHandler) Prepare(ctx *gin.Context) int {
+	status := h.Paged.Prepare(ctx)
+	if status != http.StatusOK {
+		return status
+	}
+	return h.Watched.Prepare(ctx)
+}
+
+// List the plans.
+// The token must have "list" on plans in the requested
+// namespace (or cluster-wide).
+func (h PlanHandler) List(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	status, err := base.DefaultAuth.PermitObject(ctx, &api.Plan{}, "list")
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	// The context is not valid once the (watch) handler returns.
+	namespace := ctx.Query(NamespaceParam)
+	name := ctx.Query(NameParam)
+	list := func() (resources map[string]interface{}, err error) {
+		plans, err := h.plans(namespace, name)
+		if err != nil {
+			return
+		}
+		resources = make(map[string]interface{})
+		for i := range plans {
+			resources[string(plans[i].UID)] = plans[i]
+		}
+		return
+	}
+	if h.WatchRequest {
+		h.watch(ctx, list)
+		return
+	}
+	plans, err := h.plans(namespace, name)
+	if err != nil {
+		h.failed(ctx, err)
+		return
+	}
+	h.Page.Slice(&plans)
+
+	ctx.JSON(http.StatusOK, plans)
+}
+
+// Get a plan.
+func (h PlanHandler) Get(ctx *gin.Context) {
+	plan, status, err := h.plan(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	r := &Plan{}
+	r.With(plan)
+
+	ctx.JSON(http.StatusOK, r)
+}
+
+// List the VMs (status) of a plan.
+// The VMs are those of the active (or last) migration.
+func (h PlanHandler) VMs(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	plan, status, err := h.plan(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	name := ctx.Query(NameParam)
+	if h.WatchRequest {
+		key := client.ObjectKeyFromObject(plan)
+		h.watch(ctx, func() (resources map[string]interface{}, err error) {
+			plan := &api.Plan{}
+			err = h.Client.Get(context.TODO(), key, plan)
+			if err != nil {
+				err = liberr.Wrap(err)
+				return
+			}
+			resources = make(map[string]interface{})
+			for _, vm := range PlanVMs(plan, name) {
+				resources[vm.ID] = vm
+			}
+			return
+		})
+		return
+	}
+	vms := PlanVMs(plan, name)
+	h.Page.Slice(&vms)
+
+	ctx.JSON(http.StatusOK, vms)
+}
+
+// Get the status of a plan VM.
+func (h PlanHandler) VM(ctx *gin.Context) {
+	plan, status, err := h.plan(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	for _, vm := range PlanVMs(plan, "") {
+		if vm.ID == ctx.Param(VMParam) {
+			ctx.JSON(http.StatusOK, vm)
+			return
+		}
+	}
+
+	ctx.Status(http.StatusNotFound)
+}
+
+// List the migrations of a plan.
+// The token must have "list" on migrations in the namespace.
+func (h PlanHandler) Migrations(ctx *gin.Context) {
+	status := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		return
+	}
+	plan, status, err := h.plan(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	status, err = base.DefaultAuth.PermitObject(ctx, &api.Migration{}, "list")
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	list := func() (resources map[string]interface{}, err error) {
+		migrations, err := h.migrations(plan)
+		if err != nil {
+			return
+		}
+		resources = make(map[string]interface{})
+		for i := range migrations {
+			resources[string(migrations[i].UID)] = migrations[i]
+		}
+		return
+	}
+	if h.WatchRequest {
+		h.watch(ctx, list)
+		return
+	}
+	migrations, err := h.migrations(plan)
+	if err != nil {
+		h.failed(ctx, err)
+		return
+	}
+	h.Page.Slice(&migrations)
+
+	ctx.JSON(http.StatusOK, migrations)
+}
+
+// The plan referenced by the request.
+// The token must have "get" on the plan.
+func (h *PlanHandler) plan(ctx *gin.Context) (plan *api.Plan, status int, err error) {
+	namespace := ctx.Query(NamespaceParam)
+	if namespace == "" {
+		status = http.StatusBadRequest
+		err = liberr.New("the `namespace` parameter is required.")
+		return
+	}
+	plan = &api.Plan{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: namespace,
+			Name:      ctx.Param(PlanParam),
+		},
+	}
+	status, err = base.DefaultAuth.PermitObject(ctx, plan, "get")
+	if status != http.StatusOK {
+		return
+	}
+	err = h.Client.Get(context.TODO(), client.ObjectKeyFromObject(plan), plan)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			status = http.StatusNotFound
+			return
+		}
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		status = http.StatusInternalServerError
+		return
+	}
+	status = http.StatusOK
+	return
+}
+
+// The plans in the namespace (all when empty) filtered
+// by (an optional) name and ordered by namespace and name.
+func (h *PlanHandler) plans(namespace, name string) (plans []Plan, err error) {
+	list := &api.PlanList{}
+	options := []client.ListOption{}
+	if namespace != "" {
+		options = append(options, client.InNamespace(namespace))
+	}
+	err = h.Client.List(context.TODO(), list, options...)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	plans = []Plan{}
+	for i := range list.Items {
+		plan := &list.Items[i]
+		if name != "" && plan.Name != name {
+			continue
+		}
+		r := Plan{}
+		r.With(plan)
+		plans = append(plans, r)
+	}
+	sort.Slice(plans, func(i, j int) bool {
+		return path.Join(plans[i].Namespace, plans[i].Name) < path.Join(plans[j].Namespace, plans[j].Name)
+	})
+	return
+}
+
+// The migrations of the plan ordered by creation.
+func (h *PlanHandler) migrations(plan *api.Plan) (migrations []Migration, err error) {
+	list := &api.MigrationList{}
+	err = h.Client.List(context.TODO(), list, client.InNamespace(plan.Namespace))
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	migrations = []Migration{}
+	for i := range list.Items {
+		migration := &list.Items[i]
+		if !migration.Match(plan) {
+			continue
+		}
+		r := Migration{}
+		r.With(migration)
+		migrations = append(migrations, r)
+	}
+	sort.SliceStable(migrations, func(i, j int) bool {
+		return migrations[i].Created.Before(&migrations[j].Created)
+	})
+	return
+}
+
+// Watch the listed resources.
+func (h *PlanHandler) watch(ctx *gin.Context, list PollFunc) {
+	watch := &PolledWatch{
+		Options: h.WatchOptions(),
+		List:    list,
+	}
+	err := watch.Start(ctx)
+	if err != nil {
+		h.failed(ctx, err)
+	}
+}
+
+// Reply the failure.
+func (h *PlanHandler) failed(ctx *gin.Context, err error) {
+	log.Trace(
+		err,
+		"url",
+		ctx.Request.URL)
+	base.ReplyError(ctx, http.StatusInternalServerError, err)
+}
+
+// VM counts by state.
+type VMCounts struct {
+	Total     int `json:"total"`
+	Pending   int `json:"pending"`
+	Running   int `json:"running"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+	Canceled  int `json:"canceled"`
+}
+
+// Count the VM (status).
+func (r *VMCounts) Add(vm *planapi.VMStatus) {
+	r.Total++
+	switch VMState(vm) {
+	case StatePending:
+		r.Pending++
+	case StateRunning:
+		r.Running++
+	case StateSucceeded:
+		r.Succeeded++
+	case StateFailed:
+		r.Failed++
+	case StateCanceled:
+		r.Canceled++
+	}
+}
+
+// Plan resource.
+type Plan struct {
+	// Object UID.
+	UID string `json:"uid"`
+	// Namespace.
+	Namespace string `json:"namespace"`
+	// Name.
+	Name string `json:"name"`
+	// Self link.
+	SelfLink string `json:"selfLink"`
+	// Created timestamp.
+	Created meta.Time `json:"created"`
+	// Generation.
+	Generation int64 `json:"generation"`
+	// Generation observed by the controller.
+	ObservedGeneration int64 `json:"observedGeneration"`
+	// Source provider (namespace/name).
+	Source string `json:"source"`
+	// Destination provider (namespace/name).
+	Destination string `json:"destination"`
+	// Target namespace.
+	TargetNamespace string `json:"targetNamespace"`
+	// Warm migration.
+	Warm bool `json:"warm"`
+	// Archived.
+	Archived bool `json:"archived"`
+	// State.
+	State string `json:"state"`
+	// Active (or last) migration.
+	Migration string `json:"migration,omitempty"`
+	// Started timestamp.
+	Started *meta.Time `json:"started,omitempty"`
+	// Completed timestamp.
+	Completed *meta.Time `json:"completed,omitempty"`
+	// VM counts.
+	VMs VMCounts `json:"vms"`
+	// Conditions.
+	Conditions []libcnd.Condition `json:"conditions"`
+}
+
+// Build the resource using the plan.
+func (r *Plan) With(plan *api.Plan) {
+	provider := &plan.Spec.Provider
+	migration := &plan.Status.Migration
+	r.UID = string(plan.UID)
+	r.Namespace = plan.Namespace
+	r.Name = plan.Name
+	r.SelfLink = base.Link(PlanRoot, base.Params{PlanParam: plan.Name}) + "?namespace=" + plan.Namespace
+	r.Created = plan.CreationTimestamp
+	r.Generation = plan.Generation
+	r.ObservedGeneration = plan.Status.ObservedGeneration
+	r.Source = path.Join(provider.Source.Namespace, provider.Source.Name)
+	r.Destination = path.Join(provider.Destination.Namespace, provider.Destination.Name)
+	r.TargetNamespace = plan.Spec.TargetNamespace
+	r.Warm = plan.Spec.Warm
+	r.Archived = plan.Spec.Archived
+	r.State = PlanState(plan)
+	r.Migration = migration.ActiveSnapshot().Migration.Name
+	r.Started = migration.Started
+	r.Completed = migration.Completed
+	r.VMs = VMCounts{}
+	for _, vm := range migration.VMs {
+		r.VMs.Add(vm)
+	}
+	r.Conditions = plan.Status.List
+	if r.Conditions == nil {
+		r.Conditions = []libcnd.Condition{}
+	}
+}
+
+// Plan VM resource.
+// The VM status (pipeline) with the derived state.
+type PlanVM struct {
+	planapi.VMStatus `json:",inline"`
+	// State.
+	State string `json:"state"`
+}
+
+// Migration resource.
+type Migration struct {
+	// Object UID.
+	UID string `json:"uid"`
+	// Namespace.
+	Namespace string `json:"namespace"`
+	// Name.
+	Name string `json:"name"`
+	// Created timestamp.
+	Created meta.Time `json:"created"`
+	// Plan name.
+	Plan string `json:"plan"`
+	// Cutover time.
+	Cutover *meta.Time `json:"cutover,omitempty"`
+	// State.
+	State string `json:"state"`
+	// Started timestamp.
+	Started *meta.Time `json:"started,omitempty"`
+	// Completed timestamp.
+	Completed *meta.Time `json:"completed,omitempty"`
+	// VM counts.
+	VMs VMCounts `json:"vms"`
+	// Conditions.
+	Conditions []libcnd.Condition `json:"conditions"`
+}
+
+// Build the resource using the migration.
+func (r *Migration) With(migration *api.Migration) {
+	r.UID = string(migration.UID)
+	r.Namespace = migration.Namespace
+	r.Name = migration.Name
+	r.Created = migration.CreationTimestamp
+	r.Plan = migration.Spec.Plan.Name
+	r.Cutover = migration.Spec.Cutover
+	r.State = conditionState(&migration.Status.Conditions, migration.Status.Running())
+	r.Started = migration.Status.Started
+	r.Completed = migration.Status.Completed
+	r.VMs = VMCounts{}
+	for _, vm := range migration.Status.VMs {
+		r.VMs.Add(vm)
+	}
+	r.Conditions = migration.Status.List
+	if r.Conditions == nil {
+		r.Conditions = []libcnd.Condition{}
+	}
+}
+
+// The VMs (status) of the plan filtered by (an optional) name.
+// Ordered as listed in the plan.
+func PlanVMs(plan *api.Plan, name string) (vms []PlanVM) {
+	vms = []PlanVM{}
+	for _, vm := range plan.Status.Migration.VMs {
+		if name != "" && vm.Name != name {
+			continue
+		}
+		vms = append(
+			vms,
+			PlanVM{
+				VMStatus: *vm,
+				State:    VMState(vm),
+			})
+	}
+	return
+}
+
+// The state of the plan.
+func PlanState(plan *api.Plan) (state string) {
+	state = conditionState(&plan.Status.Conditions, false)
+	if state == StatePending {
+		state = StateNotReady
+		if plan.Status.IsReady() {
+			state = StateReady
+		}
+	}
+	return
+}
+
+// The state of the VM.
+func VMState(vm *planapi.VMStatus) string {
+	return conditionState(&vm.Conditions, vm.Running())
+}
+
+// The state reflected by the conditions.
+func conditionState(conditions *libcnd.Conditions, running bool) (state string) {
+	switch {
+	case conditions.HasCondition(api.ConditionExecuting):
+		state = StateExecuting
+	case conditions.HasCondition(api.ConditionCanceled):
+		state = StateCanceled
+	case conditions.HasCondition(api.ConditionFailed):
+		state = StateFailed
+	case conditions.HasCondition(api.ConditionSucceeded):
+		state = StateSucceeded
+	case running:
+		state = StateRunning
+	default:
+		state = StatePending
+	}
+	return
+}
@@ -0,0 +1,153 @@
+package web
+
+import (
+	"testing"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeClient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPlanResources(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	started := meta.Now()
+	vm := func(id string, running bool, conditions ...string) *plan.VMStatus {
+		status := &plan.VMStatus{VM: plan.VM{Ref: ref.Ref{ID: id, Name: "name-" + id}}}
+		if running {
+			status.Started = &started
+		}
+		for _, cnd := range conditions {
+			status.SetCondition(libcnd.Condition{Type: cnd, Status: libcnd.True})
+		}
+		return status
+	}
+	p := &api.Plan{ObjectMeta: meta.ObjectMeta{Namespace: "ns", Name: "b", UID: "p1"}}
+	p.Spec.Provider.Source = core.ObjectReference{Namespace: "ns", Name: "vcenter"}
+	p.Spec.Provider.Destination = core.ObjectReference{Namespace: "ns", Name: "host"}
+	p.Status.SetCondition(libcnd.Condition{Type: api.ConditionExecuting, Status: libcnd.True})
+	p.Status.Migration.VMs = []*plan.VMStatus{
+		vm("vm-1", false),
+		vm("vm-2", true),
+		vm("vm-3", true, api.ConditionSucceeded),
+		vm("vm-4", true, api.ConditionFailed),
+		vm("vm-5", true, api.ConditionCanceled),
+	}
+	ready := &api.Plan{ObjectMeta: meta.ObjectMeta{Namespace: "ns", Name: "a", UID: "p2"}}
+	ready.Status.SetCondition(libcnd.Condition{Type: libcnd.Ready, Status: libcnd.True})
+	other := &api.Plan{ObjectMeta: meta.ObjectMeta{Namespace: "other", Name: "c", UID: "p3"}}
+	first := &api.Migration{ObjectMeta: meta.ObjectMeta{
+		Namespace:         "ns",
+		Name:              "m1",
+		UID:               "m1",
+		CreationTimestamp: meta.NewTime(started.Add(-time.Hour)),
+	}}
+	first.Spec.Plan = core.ObjectReference{Namespace: "ns", Name: "b"}
+	first.Status.SetCondition(libcnd.Condition{Type: api.ConditionFailed, Status: libcnd.True})
+	second := &api.Migration{ObjectMeta: meta.ObjectMeta{
+		Namespace:         "ns",
+		Name:              "m2",
+		UID:               "m2",
+		CreationTimestamp: started,
+	}}
+	second.Spec.Plan = core.ObjectReference{Namespace: "ns", Name: "b"}
+	second.Status.Started = &started
+	unrelated := &api.Migration{ObjectMeta: meta.ObjectMeta{Namespace: "ns", Name: "m3", UID: "m3"}}
+	unrelated.Spec.Plan = core.ObjectReference{Namespace: "ns", Name: "a"}
+	scheme := runtime.NewScheme()
+	_ = api.SchemeBuilder.AddToScheme(scheme)
+	handler := &PlanHandler{
+		Client: fakeClient.NewClientBuilder().
+			WithScheme(scheme).
+			WithRuntimeObjects(p, ready, other, first, second, unrelated).
+			Build(),
+	}
+
+	// Plans.
+	plans, err := handler.plans("", "")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(plans).To(gomega.HaveLen(3))
+	g.Expect(plans[0].Name).To(gomega.Equal("a"))
+	g.Expect(plans[0].State).To(gomega.Equal(StateReady))
+	g.Expect(plans[0].Conditions).To(gomega.HaveLen(1))
+	g.Expect(plans[1].Name).To(gomega.Equal("b"))
+	g.Expect(plans[1].SelfLink).To(gomega.Equal("/plans/b?namespace=ns"))
+	g.Expect(plans[1].Source).To(gomega.Equal("ns/vcenter"))
+	g.Expect(plans[1].State).To(gomega.Equal(StateExecuting))
+	g.Expect(plans[1].VMs).To(gomega.Equal(VMCounts{
+		Total:     5,
+		Pending:   1,
+		Running:   1,
+		Succeeded: 1,
+		Failed:    1,
+		Canceled:  1,
+	}))
+	g.Expect(plans[2].State).To(gomega.Equal(StateNotReady))
+	g.Expect(plans[2].Conditions).ToNot(gomega.BeNil())
+	plans, err = handler.plans("ns", "")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(plans).To(gomega.HaveLen(2))
+	plans, err = handler.plans("", "b")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(plans).To(gomega.HaveLen(1))
+	g.Expect(plans[0].UID).To(gomega.Equal("p1"))
+
+	// VMs.
+	vms := PlanVMs(p, "")
+	g.Expect(vms).To(gomega.HaveLen(5))
+	g.Expect(vms[0].ID).To(gomega.Equal("vm-1"))
+	g.Expect(vms[0].State).To(gomega.Equal(StatePending))
+	g.Expect(vms[1].State).To(gomega.Equal(StateRunning))
+	g.Expect(vms[4].State).To(gomega.Equal(StateCanceled))
+	vms = PlanVMs(p, "name-vm-3")
+	g.Expect(vms).To(gomega.HaveLen(1))
+	g.Expect(vms[0].State).To(gomega.Equal(StateSucceeded))
+
+	// Migrations.
+	migrations, err := handler.migrations(p)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(migrations).To(gomega.HaveLen(2))
+	g.Expect(migrations[0].Name).To(gomega.Equal("m1"))
+	g.Expect(migrations[0].State).To(gomega.Equal(StateFailed))
+	g.Expect(migrations[1].Name).To(gomega.Equal("m2"))
+	g.Expect(migrations[1].State).To(gomega.Equal(StateRunning))
+}
+
+func TestPolledChanges(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	current := map[string]interface{}{
+		"a": Plan{Name: "a"},
+		"b": Plan{Name: "b"},
+		"c": Plan{Name: "c"},
+	}
+	next := map[string]interface{}{
+		"a": Plan{Name: "a"},
+		"b": Plan{Name: "b", State: StateExecuting},
+		"d": Plan{Name: "d"},
+	}
+	events := Changes(current, next)
+	g.Expect(events).To(gomega.HaveLen(3))
+	g.Expect(events[0].Action).To(gomega.Equal(libmodel.Updated))
+	g.Expect(events[0].Resource).To(gomega.Equal(current["b"]))
+	g.Expect(events[0].Updated).To(gomega.Equal(next["b"]))
+	g.Expect(events[1].Action).To(gomega.Equal(libmodel.Created))
+	g.Expect(events[1].Resource).To(gomega.Equal(next["d"]))
+	g.Expect(events[2].Action).To(gomega.Equal(libmodel.Deleted))
+	g.Expect(events[2].Resource).To(gomega.Equal(current["c"]))
+	// Snapshot.
+	events = Changes(map[string]interface{}{}, next)
+	g.Expect(events).To(gomega.HaveLen(3))
+	for _, event := range events {
+		g.Expect(event.Action).To(gomega.Equal(libmodel.Created))
+	}
+	g.Expect(Changes(next, next)).To(gomega.BeEmpty())
+}
@@ -0,0 +1,230 @@
+package web
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Routes.
+const (
+	ReportsMigrationsRoot = "/reports/migrations"
+)
+
+// Params.
+const (
+	FromParam   = "from"
+	ToParam     = "to"
+	FormatParam = "format"
+)
+
+// Report formats.
+const (
+	FormatCSV = "csv"
+)
+
+// Columns of the CSV report.
+var reportColumns = []string{
+	"namespace",
+	"plan",
+	"migration",
+	"source",
+	"sourceType",
+	"vm",
+	"vmID",
+	"started",
+	"completed",
+	"duration",
+	"bytes",
+	"result",
+	"errors",
+}
+
+// Completed VM migration.
+type MigrationReport struct {
+	Namespace  string     `json:"namespace"`
+	Plan       string     `json:"plan"`
+	Migration  string     `json:"migration"`
+	Source     string     `json:"source"`
+	SourceType string     `json:"sourceType"`
+	VM         string     `json:"vm"`
+	VMID       string     `json:"vmID"`
+	Started    *time.Time `json:"started,omitempty"`
+	Completed  *time.Time `json:"completed,omitempty"`
+	Duration   int64      `json:"duration"`
+	Bytes      int64      `json:"bytes"`
+	Result     string     `json:"result"`
+	Errors     []string   `json:"errors,omitempty"`
+}
+
+// Build the report of a migration record.
+func (r *MigrationReport) With(record *api.MigrationRecord) {
+	spec := &record.Spec
+	r.Namespace = record.Namespace
+	r.Plan = spec.Plan.Name
+	r.Migration = spec.Migration.Name
+	r.Source = spec.Source.Namespace + "/" + spec.Source.Name
+	r.SourceType = string(spec.SourceType)
+	r.VM = spec.VM.Name
+	r.VMID = spec.VM.ID
+	if spec.Started != nil {
+		started := spec.Started.Time.UTC()
+		r.Started = &started
+	}
+	if spec.Completed != nil {
+		completed := spec.Completed.Time.UTC()
+		r.Completed = &completed
+	}
+	r.Duration = spec.Duration
+	r.Bytes = spec.Bytes
+	r.Result = spec.Result
+	r.Errors = spec.Errors
+}
+
+// CSV row.
+func (r *MigrationReport) Row() []string {
+	timestamp := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.Format(time.RFC3339)
+	}
+	return []string{
+		r.Namespace,
+		r.Plan,
+		r.Migration,
+		r.Source,
+		r.SourceType,
+		r.VM,
+		r.VMID,
+		timestamp(r.Started),
+		timestamp(r.Completed),
+		strconv.FormatInt(r.Duration, 10),
+		strconv.FormatInt(r.Bytes, 10),
+		r.Result,
+		strings.Join(r.Errors, "; "),
+	}
+}
+
+// Report handler.
+// Reports the completed VM migrations recorded in the migration
+// history to administrators.
+type ReportHandler struct {
+	// Reader of the migration records.
+	Client client.Reader
+}
+
+// Add routes to the `gin` router.
+func (h *ReportHandler) AddRoutes(e *gin.Engine) {
+	e.GET(ReportsMigrationsRoot, h.Migrations)
+}
+
+// List the VM migrations completed within the (optional)
+// `from` and `to` (RFC3339) time range, oldest first.
+// Exported as CSV when requested by the `format` param.
+func (h ReportHandler) Migrations(ctx *gin.Context) {
+	status, err := base.DefaultAuth.Admin(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	from, err := timeParam(ctx, FromParam)
+	if err != nil {
+		base.ReplyError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	to, err := timeParam(ctx, ToParam)
+	if err != nil {
+		base.ReplyError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	list := &api.MigrationRecordList{}
+	err = h.Client.List(context.TODO(), list)
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	reports := MigrationReports(list.Items, from, to)
+	if ctx.Query(FormatParam) == FormatCSV {
+		ctx.Header("Content-Type", "text/csv")
+		ctx.Header("Content-Disposition", "attachment; filename=migrations.csv")
+		ctx.Status(http.StatusOK)
+		err = WriteCSV(ctx.Writer, reports)
+		if err != nil {
+			log.Trace(err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, reports)
+}
+
+// Parse an (optional) RFC3339 time param.
+func timeParam(ctx *gin.Context, name string) (t time.Time, err error) {
+	if s := ctx.Query(name); s != "" {
+		t, err = time.Parse(time.RFC3339, s)
+	}
+	return
+}
+
+// Build the reports of the records completed within the time range.
+// A zero `from` or `to` leaves the range open.
+func MigrationReports(records []api.MigrationRecord, from, to time.Time) (reports []MigrationReport) {
+	reports = []MigrationReport{}
+	for i := range records {
+		record := &records[i]
+		completed := record.CreationTimestamp.Time
+		if record.Spec.Completed != nil {
+			completed = record.Spec.Completed.Time
+		}
+		if !from.IsZero() && completed.Before(from) {
+			continue
+		}
+		if !to.IsZero() && !completed.Before(to) {
+			continue
+		}
+		report := MigrationReport{}
+		report.With(record)
+		if report.Completed == nil {
+			completed = completed.UTC()
+			report.Completed = &completed
+		}
+		reports = append(reports, report)
+	}
+	sort.SliceStable(reports, func(i, j int) bool {
+		return reports[i].Completed.Before(*reports[j].Completed)
+	})
+	return
+}
+
+// Write the reports as CSV (with a header).
+func WriteCSV(w io.Writer, reports []MigrationReport) (err error) {
+	writer := csv.NewWriter(w)
+	err = writer.Write(reportColumns)
+	if err != nil {
+		return
+	}
+	for i := range reports {
+		err = writer.Write(reports[i].Row())
+		if err != nil {
+			return
+		}
+	}
+	writer.Flush()
+	err = writer.Error()
+	return
+}
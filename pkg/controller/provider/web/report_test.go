@@ -0,0 +1,64 @@
+package web
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	"github.com/onsi/gomega"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMigrationReports(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	day := func(n int) time.Time {
+		return time.Date(2025, 1, n, 0, 0, 0, 0, time.UTC)
+	}
+	record := func(name string, completed time.Time) api.MigrationRecord {
+		timestamp := meta.NewTime(completed)
+		record := api.MigrationRecord{
+			ObjectMeta: meta.ObjectMeta{Namespace: "ns", Name: name},
+			Spec: api.MigrationRecordSpec{
+				Plan:       api.RecordRef{Name: "plan"},
+				Migration:  api.RecordRef{Name: "migration"},
+				Source:     api.RecordRef{Namespace: "ns", Name: "vcenter"},
+				SourceType: api.VSphere,
+				VM:         ref.Ref{ID: "vm-" + name, Name: name},
+				Duration:   3600,
+				Bytes:      1024,
+				Result:     api.RecordFailed,
+				Errors:     []string{"a", "b"},
+			},
+		}
+		record.Spec.Completed = &timestamp
+		return record
+	}
+	records := []api.MigrationRecord{
+		record("c", day(3)),
+		record("a", day(1)),
+		record("b", day(2)),
+	}
+	// Completion defaults to the creation.
+	records[0].Spec.Completed = nil
+	records[0].CreationTimestamp = meta.NewTime(day(4))
+
+	reports := MigrationReports(records, time.Time{}, time.Time{})
+	g.Expect(reports).To(gomega.HaveLen(3))
+	g.Expect(reports[0].VM).To(gomega.Equal("a"))
+	g.Expect(reports[2].VM).To(gomega.Equal("c"))
+	g.Expect(*reports[2].Completed).To(gomega.Equal(day(4)))
+
+	reports = MigrationReports(records, day(2), day(4))
+	g.Expect(reports).To(gomega.HaveLen(1))
+	g.Expect(reports[0].VM).To(gomega.Equal("b"))
+
+	buffer := &bytes.Buffer{}
+	err := WriteCSV(buffer, reports[:1])
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(buffer.String()).To(gomega.Equal(
+		"namespace,plan,migration,source,sourceType,vm,vmID,started,completed,duration,bytes,result,errors\n" +
+			"ns,plan,migration,ns/vcenter,vsphere,b,vm-b,,2025-01-02T00:00:00Z,3600,1024,Failed,a; b\n"))
+}
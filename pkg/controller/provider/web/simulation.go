@@ -0,0 +1,321 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Routes.
+const (
+	SimulationCollection = "plans/simulate"
+)
+
+// Simulation route of the provider type.
+func SimulationRoot(kind api.ProviderType) string {
+	return "/" + base.ProvidersRoot + "/" + string(kind) +
+		"/:" + base.ProviderParam +
+		"/" + SimulationCollection
+}
+
+// Wave bottlenecks.
+const (
+	// The aggregate throughput of the source provider.
+	BottleneckThroughput = "Throughput"
+	// The transfer of the largest VM.
+	BottleneckVM = "VM"
+	// The number of VMs migrated concurrently.
+	BottleneckConcurrency = "Concurrency"
+)
+
+// Plan simulation request.
+type SimulationRequest struct {
+	// Proposed plans (waves) migrated in order.
+	// As generated by the plans/generate route.
+	Plans []api.Plan `json:"plans"`
+	// Max VMs migrated concurrently.
+	// Defaults to the controller max in-flight.
+	MaxInFlight int `json:"maxInFlight,omitempty"`
+}
+
+// Simulated migration of the proposed plans.
+type Simulation struct {
+	// Migration records the throughput is based on.
+	Records int `json:"records"`
+	// Throughput (bytes per second) of a VM migration.
+	VMThroughput float64 `json:"vmThroughput"`
+	// Aggregate throughput (bytes per second) of the source provider.
+	Throughput float64 `json:"throughput"`
+	// Predicted duration (seconds).
+	Duration int64 `json:"duration"`
+	// Simulated waves.
+	Waves []WaveSimulation `json:"waves"`
+}
+
+// Simulated migration of a wave.
+type WaveSimulation struct {
+	// Plan name.
+	Name string `json:"name"`
+	// Number of VMs.
+	VMs int `json:"vms"`
+	// Bytes transferred.
+	Bytes int64 `json:"bytes"`
+	// Predicted duration (seconds).
+	Duration int64 `json:"duration"`
+	// Bottleneck resource.
+	Bottleneck string `json:"bottleneck"`
+}
+
+// No migration history of the source provider (type).
+type HistoryNotFoundError struct {
+}
+
+func (r HistoryNotFoundError) Error() string {
+	return "No successful migration recorded for the provider type."
+}
+
+// Size (bytes) of the VMs migrated by the (in-memory) plan.
+type SizeVMsFunc func(client client.Client, plan *api.Plan, vms []ref.Ref) (sizes []int64, err error)
+
+// Simulation handler.
+// Predicts the duration of proposed plans (waves) based on the
+// throughput of the VM migrations recorded in the migration
+// history, preferably of the same source provider. The in-memory
+// plan used to size the VMs is built as for the target specs.
+type SimulationHandler struct {
+	TargetSpecHandler
+	// Reader of the migration records.
+	Records client.Reader
+	// Size the VMs.
+	Size SizeVMsFunc
+}
+
+// Add routes to the `gin` router.
+func (h *SimulationHandler) AddRoutes(e *gin.Engine) {
+	for _, kind := range api.ProviderTypes {
+		e.POST(SimulationRoot(kind), h.Simulate)
+	}
+}
+
+// Simulate the migration of the proposed plans.
+func (h SimulationHandler) Simulate(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	if SimulationRoot(h.Provider.Type()) != ctx.FullPath() {
+		ctx.Header(base.ReasonHeader, base.UnknownProvider)
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	request := SimulationRequest{}
+	err = ctx.ShouldBindJSON(&request)
+	if err != nil {
+		base.ReplyError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if request.MaxInFlight < 0 {
+		base.ReplyError(ctx, http.StatusBadRequest, errors.New("maxInFlight must be positive."))
+		return
+	}
+	if request.MaxInFlight == 0 {
+		request.MaxInFlight = base.Settings.Migration.MaxInFlight
+	}
+	plan, status, err := h.plan(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	vms := []ref.Ref{}
+	for i := range request.Plans {
+		for _, vm := range request.Plans[i].Spec.VMs {
+			vms = append(vms, vm.Ref)
+		}
+	}
+	sizes, err := h.Size(h.Client, plan, vms)
+	if err != nil {
+		if errors.As(err, &base.NotFoundError{}) {
+			base.ReplyError(ctx, http.StatusNotFound, err)
+			return
+		}
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	list := &api.MigrationRecordList{}
+	err = h.Records.List(context.TODO(), list)
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	waves := []Wave{}
+	for i := range request.Plans {
+		n := len(request.Plans[i].Spec.VMs)
+		waves = append(waves, Wave{Name: request.Plans[i].Name, Sizes: sizes[:n]})
+		sizes = sizes[n:]
+	}
+	simulation, err := Simulate(list.Items, h.Provider, waves, request.MaxInFlight)
+	if err != nil {
+		base.ReplyError(ctx, http.StatusNotFound, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, simulation)
+}
+
+// Simulated wave.
+type Wave struct {
+	// Plan name.
+	Name string
+	// Size (bytes) of each VM.
+	Sizes []int64
+}
+
+// Simulate the migration of the waves in order.
+// The throughput of a VM migration is the median throughput of the
+// recorded (successful) VM migrations of the source provider, or of
+// the provider type when the provider has none. The aggregate
+// throughput is the highest of the recorded migrations.
+// A wave lasts until the (list scheduled) VMs are migrated, but
+// not less than its bytes transferred at the aggregate throughput.
+func Simulate(records []api.MigrationRecord, provider *api.Provider, waves []Wave, maxInFlight int) (simulation Simulation, err error) {
+	history := historyOf(records, provider)
+	if len(history) == 0 {
+		err = HistoryNotFoundError{}
+		return
+	}
+	simulation.Records = len(history)
+	simulation.VMThroughput = vmThroughput(history)
+	simulation.Throughput = aggregateThroughput(history)
+	simulation.Waves = []WaveSimulation{}
+	for _, wave := range waves {
+		ws := WaveSimulation{Name: wave.Name, VMs: len(wave.Sizes)}
+		slots := make([]float64, min(maxInFlight, len(wave.Sizes)))
+		largest := float64(0)
+		for _, size := range wave.Sizes {
+			ws.Bytes += size
+			duration := float64(size) / simulation.VMThroughput
+			largest = max(largest, duration)
+			// The VM is started on the first free slot.
+			sort.Float64s(slots)
+			slots[0] += duration
+		}
+		makespan := float64(0)
+		for _, end := range slots {
+			makespan = max(makespan, end)
+		}
+		transfer := float64(ws.Bytes) / simulation.Throughput
+		switch {
+		case transfer > makespan:
+			ws.Duration = int64(transfer)
+			ws.Bottleneck = BottleneckThroughput
+		case largest == makespan:
+			ws.Duration = int64(makespan)
+			ws.Bottleneck = BottleneckVM
+		default:
+			ws.Duration = int64(makespan)
+			ws.Bottleneck = BottleneckConcurrency
+		}
+		simulation.Duration += ws.Duration
+		simulation.Waves = append(simulation.Waves, ws)
+	}
+	return
+}
+
+// The successful VM migrations recorded for the source provider,
+// or for the provider type when the provider has none.
+func historyOf(records []api.MigrationRecord, provider *api.Provider) (history []*api.MigrationRecord) {
+	byType := []*api.MigrationRecord{}
+	for i := range records {
+		record := &records[i]
+		spec := &record.Spec
+		if spec.Result != api.RecordSucceeded || spec.Bytes <= 0 || spec.Duration <= 0 {
+			continue
+		}
+		if spec.SourceType != provider.Type() {
+			continue
+		}
+		byType = append(byType, record)
+		if spec.Source.UID == provider.UID {
+			history = append(history, record)
+		}
+	}
+	if len(history) == 0 {
+		history = byType
+	}
+	return
+}
+
+// Median throughput (bytes per second) of the VM migrations.
+func vmThroughput(history []*api.MigrationRecord) float64 {
+	rates := []float64{}
+	for _, record := range history {
+		rates = append(rates, float64(record.Spec.Bytes)/float64(record.Spec.Duration))
+	}
+	sort.Float64s(rates)
+	n := len(rates)
+	if n%2 == 0 {
+		return (rates[n/2-1] + rates[n/2]) / 2
+	}
+	return rates[n/2]
+}
+
+// Highest aggregate throughput (bytes per second) of the migrations.
+// The bytes transferred by the VMs of a migration over the time from
+// the first VM started to the last completed.
+func aggregateThroughput(history []*api.MigrationRecord) (throughput float64) {
+	type window struct {
+		bytes   int64
+		started int64
+		ended   int64
+	}
+	windows := map[string]*window{}
+	for _, record := range history {
+		spec := &record.Spec
+		ended := record.CreationTimestamp.Unix()
+		if spec.Completed != nil {
+			ended = spec.Completed.Unix()
+		}
+		started := ended - spec.Duration
+		if spec.Started != nil {
+			started = spec.Started.Unix()
+		}
+		key := string(spec.Migration.UID)
+		if key == "" {
+			key = spec.Migration.Namespace + "/" + spec.Migration.Name
+		}
+		w, found := windows[key]
+		if !found {
+			w = &window{started: started, ended: ended}
+			windows[key] = w
+		}
+		w.bytes += spec.Bytes
+		w.started = min(w.started, started)
+		w.ended = max(w.ended, ended)
+	}
+	for _, w := range windows {
+		if w.ended <= w.started {
+			continue
+		}
+		throughput = max(throughput, float64(w.bytes)/float64(w.ended-w.started))
+	}
+	if throughput == 0 {
+		throughput = vmThroughput(history)
+	}
+	return
+}
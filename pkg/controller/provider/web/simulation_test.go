@@ -0,0 +1,85 @@
+package web
+
+import (
+	"testing"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/onsi/gomega"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestSimulate(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	vsphere := api.VSphere
+	provider := &api.Provider{ObjectMeta: meta.ObjectMeta{UID: "p1"}}
+	provider.Spec.Type = &vsphere
+	started := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	record := func(source, migration types.UID, bytes, duration int64) api.MigrationRecord {
+		record := api.MigrationRecord{
+			Spec: api.MigrationRecordSpec{
+				Source:     api.RecordRef{UID: source},
+				SourceType: api.VSphere,
+				Migration:  api.RecordRef{UID: migration},
+				Duration:   duration,
+				Bytes:      bytes,
+				Result:     api.RecordSucceeded,
+			},
+		}
+		begin := meta.NewTime(started)
+		end := meta.NewTime(started.Add(time.Duration(duration) * time.Second))
+		record.Spec.Started = &begin
+		record.Spec.Completed = &end
+		return record
+	}
+	records := []api.MigrationRecord{
+		// Two VMs of 1000 bytes/s migrated concurrently.
+		record("p1", "m1", 100000, 100),
+		record("p1", "m1", 100000, 100),
+		// Other provider.
+		record("p2", "m2", 100, 100),
+	}
+	failed := record("p1", "m3", 100, 100)
+	failed.Spec.Result = api.RecordFailed
+	records = append(records, failed)
+
+	simulation, err := Simulate(
+		records,
+		provider,
+		[]Wave{
+			{Name: "wave-1", Sizes: []int64{10000, 10000, 10000}},
+			{Name: "wave-2", Sizes: []int64{50000, 1000}},
+		},
+		2)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(simulation.Records).To(gomega.Equal(2))
+	g.Expect(simulation.VMThroughput).To(gomega.Equal(float64(1000)))
+	g.Expect(simulation.Throughput).To(gomega.Equal(float64(2000)))
+	g.Expect(simulation.Waves).To(gomega.HaveLen(2))
+	// Three VMs of 10s on two slots.
+	g.Expect(simulation.Waves[0].Duration).To(gomega.Equal(int64(20)))
+	g.Expect(simulation.Waves[0].Bottleneck).To(gomega.Equal(BottleneckConcurrency))
+	g.Expect(simulation.Waves[1].Duration).To(gomega.Equal(int64(50)))
+	g.Expect(simulation.Waves[1].Bottleneck).To(gomega.Equal(BottleneckVM))
+	g.Expect(simulation.Duration).To(gomega.Equal(int64(70)))
+
+	// Limited by the aggregate throughput.
+	simulation, err = Simulate(records, provider, []Wave{{Sizes: []int64{10000, 10000, 10000, 10000}}}, 4)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(simulation.Waves[0].Duration).To(gomega.Equal(int64(20)))
+	g.Expect(simulation.Waves[0].Bottleneck).To(gomega.Equal(BottleneckThroughput))
+
+	// The provider type history is used when the provider has none.
+	provider.UID = "p3"
+	simulation, err = Simulate(records, provider, []Wave{}, 2)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(simulation.Records).To(gomega.Equal(3))
+
+	// No history.
+	ova := api.Ova
+	provider.Spec.Type = &ova
+	_, err = Simulate(records, provider, []Wave{}, 2)
+	g.Expect(err).To(gomega.MatchError(HistoryNotFoundError{}))
+}
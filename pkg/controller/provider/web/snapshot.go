@@ -0,0 +1,253 @@
+package web
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	"k8s.io/apimachinery/pkg/util/rand"
+)
+
+// Routes.
+const (
+	SnapshotsRoot = "/snapshots"
+	SnapshotRoot  = SnapshotsRoot + "/:" + base.ProviderParam
+	// Inventory export of the provider.
+	ExportCollection = "snapshot"
+	// Difference of exported snapshots.
+	ExportDiffCollection = ExportCollection + "/diff"
+)
+
+// Exported snapshots.
+const (
+	// Reply header of the snapshot ID.
+	SnapshotHeader = "X-Snapshot"
+	// Number of exported snapshots kept for each provider.
+	SnapshotRetention = 5
+)
+
+// Exported snapshot ID.
+var snapshotID = regexp.MustCompile(`^[0-9]{14}-[0-9a-z]{5}$`)
+
+// Inventory export route of the provider type.
+func ExportRoot(kind api.ProviderType) string {
+	return "/" + base.ProvidersRoot + "/" + string(kind) +
+		"/:" + base.ProviderParam +
+		"/" + ExportCollection
+}
+
+// Snapshot difference route of the provider type.
+func ExportDiffRoot(kind api.ProviderType) string {
+	return "/" + base.ProvidersRoot + "/" + string(kind) +
+		"/:" + base.ProviderParam +
+		"/" + ExportDiffCollection
+}
+
+// Difference of exported snapshots.
+type SnapshotDiff struct {
+	// Snapshot ID.
+	From string `json:"from"`
+	// Snapshot ID.
+	To string `json:"to"`
+	// Tables with differences.
+	Tables []libmodel.TableDiff `json:"tables"`
+}
+
+// Snapshot handler.
+// Serves a consistent copy of the provider DB that is
+// downloaded by the inventory read replicas.
+// The exported (gzipped) snapshots are kept so they can
+// be compared for offline analysis.
+type SnapshotHandler struct {
+	base.Handler
+}
+
+// Add routes to the `gin` router.
+func (h *SnapshotHandler) AddRoutes(e *gin.Engine) {
+	e.GET(SnapshotRoot, h.Get)
+	for _, kind := range api.ProviderTypes {
+		e.GET(ExportRoot(kind), h.Export)
+		e.GET(ExportDiffRoot(kind), h.Diff)
+	}
+}
+
+// Get the snapshot of the provider DB.
+func (h SnapshotHandler) Get(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	db := h.Collector.DB()
+	if db == nil {
+		base.ReplyError(ctx, http.StatusNotFound, err)
+		return
+	}
+	path := filepath.Join(
+		base.Settings.Inventory.WorkingDir,
+		"snapshot-"+rand.String(8)+".db")
+	defer func() {
+		_ = os.Remove(path)
+	}()
+	err = db.Snapshot(path)
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	ctx.FileAttachment(path, string(h.Provider.UID)+".db")
+}
+
+// Export the (gzipped) snapshot of the provider DB.
+// The snapshot ID is replied in the X-Snapshot header.
+func (h SnapshotHandler) Export(ctx *gin.Context) {
+	status, err := h.prepare(ctx, ExportRoot)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	db := h.Collector.DB()
+	if db == nil {
+		base.ReplyError(ctx, http.StatusNotFound, err)
+		return
+	}
+	dir := h.dir()
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	id := time.Now().UTC().Format("20060102150405") + "-" + rand.String(5)
+	path := filepath.Join(dir, id+".db")
+	err = db.Snapshot(path)
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	h.prune(dir)
+	file, err := os.Open(path)
+	if err != nil {
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	ctx.Header(SnapshotHeader, id)
+	ctx.Header(
+		"Content-Disposition",
+		`attachment; filename="`+string(h.Provider.UID)+"-"+id+`.db.gz"`)
+	ctx.Header("Content-Type", "application/gzip")
+	ctx.Status(http.StatusOK)
+	writer := gzip.NewWriter(ctx.Writer)
+	_, err = io.Copy(writer, file)
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+	}
+}
+
+// Compare exported snapshots of the provider DB.
+// The `from` and `to` params are snapshot IDs.
+func (h SnapshotHandler) Diff(ctx *gin.Context) {
+	status, err := h.prepare(ctx, ExportDiffRoot)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	q := ctx.Request.URL.Query()
+	r := SnapshotDiff{
+		From: q.Get(FromParam),
+		To:   q.Get(ToParam),
+	}
+	paths := []string{}
+	for _, id := range []string{r.From, r.To} {
+		if !snapshotID.MatchString(id) {
+			base.ReplyError(ctx, http.StatusBadRequest, errors.New("from and to must be snapshot IDs."))
+			return
+		}
+		path := filepath.Join(h.dir(), id+".db")
+		if _, err = os.Stat(path); err != nil {
+			base.ReplyError(ctx, http.StatusNotFound, errors.New("snapshot "+id+" not found."))
+			return
+		}
+		paths = append(paths, path)
+	}
+	r.Tables, err = libmodel.DiffSnapshot(paths[0], paths[1])
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, r)
+}
+
+// Prepare to handle the request.
+// The route must match the provider type.
+func (h *SnapshotHandler) prepare(ctx *gin.Context, route func(api.ProviderType) string) (status int, err error) {
+	status, err = h.Prepare(ctx)
+	if status != http.StatusOK {
+		return
+	}
+	if route(h.Provider.Type()) != ctx.FullPath() {
+		ctx.Header(base.ReasonHeader, base.UnknownProvider)
+		status = http.StatusNotFound
+	}
+	return
+}
+
+// Directory of the exported snapshots of the provider.
+func (h *SnapshotHandler) dir() string {
+	return filepath.Join(
+		base.Settings.Inventory.WorkingDir,
+		"snapshots",
+		string(h.Provider.UID))
+}
+
+// Delete the oldest exported snapshots.
+// The IDs are ordered by time.
+func (h *SnapshotHandler) prune(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	ids := []string{}
+	for _, entry := range entries {
+		id := strings.TrimSuffix(entry.Name(), ".db")
+		if snapshotID.MatchString(id) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	for len(ids) > SnapshotRetention {
+		_ = os.Remove(filepath.Join(dir, ids[0]+".db"))
+		ids = ids[1:]
+	}
+}
@@ -0,0 +1,35 @@
+package web
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestSnapshotPrune(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	dir := t.TempDir()
+	for i := 0; i < SnapshotRetention+2; i++ {
+		path := filepath.Join(dir, fmt.Sprintf("2025010100000%d-abcde.db", i))
+		g.Expect(os.WriteFile(path, []byte{}, 0644)).To(gomega.Succeed())
+	}
+	// Not a snapshot.
+	g.Expect(os.WriteFile(filepath.Join(dir, "other.db"), []byte{}, 0644)).To(gomega.Succeed())
+
+	h := &SnapshotHandler{}
+	h.prune(dir)
+	entries, err := os.ReadDir(dir)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(entries).To(gomega.HaveLen(SnapshotRetention + 1))
+	_, err = os.Stat(filepath.Join(dir, "20250101000000-abcde.db"))
+	g.Expect(os.IsNotExist(err)).To(gomega.BeTrue())
+	_, err = os.Stat(filepath.Join(dir, "20250101000006-abcde.db"))
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+
+	g.Expect(snapshotID.MatchString("20250101000006-abcde")).To(gomega.BeTrue())
+	g.Expect(snapshotID.MatchString("../20250101000006-abcde")).To(gomega.BeFalse())
+}
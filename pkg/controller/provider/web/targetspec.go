@@ -0,0 +1,237 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cnv "kubevirt.io/api/core/v1"
+	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Routes.
+const (
+	TargetSpecCollection = "target-spec"
+	NetworkMapParam      = "networkMap"
+	StorageMapParam      = "storageMap"
+	TargetNsParam        = "targetNamespace"
+	DestinationParam     = "destination"
+	WarmParam            = "warm"
+)
+
+// Target spec route of the provider type.
+// Routed per type because the router does not fall back from
+// the (static) provider type routes to a wildcard.
+func TargetSpecRoot(kind api.ProviderType) string {
+	return "/" + base.ProvidersRoot + "/" + string(kind) +
+		"/:" + base.ProviderParam +
+		"/vms/:" + VMParam +
+		"/" + TargetSpecCollection
+}
+
+// Name of the (in-memory) plan used to build the target specs.
+const PreviewPlan = "preview"
+
+// Target specs of a source VM.
+type TargetSpec struct {
+	// Target VirtualMachine.
+	VirtualMachine *cnv.VirtualMachine `json:"virtualMachine"`
+	// DataVolumes of the disks.
+	DataVolumes []cdi.DataVolume `json:"dataVolumes"`
+}
+
+// Target specs cannot be built (previewed) for the source.
+type TargetSpecNotSupportedError struct {
+	Reason string
+}
+
+func (r TargetSpecNotSupportedError) Error() string {
+	return "Target spec not supported: " + r.Reason
+}
+
+// Build the target specs of the VM migrated by the (in-memory) plan.
+// Nothing is created.
+type BuildTargetSpecFunc func(client client.Client, plan *api.Plan, vmRef ref.Ref) (spec *TargetSpec, err error)
+
+// Target spec handler.
+// Previews the target VirtualMachine and DataVolume specs of a
+// source VM before the plan is created. The plan is built using the
+// (optional) maps, target namespace, destination provider and warm
+// params. Maps are referenced by `name` or `namespace/name` and
+// default to the provider namespace.
+type TargetSpecHandler struct {
+	base.Handler
+	// Reader of the maps and providers.
+	Client client.Client
+	// Build the target specs.
+	Build BuildTargetSpecFunc
+}
+
+// Add routes to the `gin` router.
+func (h *TargetSpecHandler) AddRoutes(e *gin.Engine) {
+	for _, kind := range api.ProviderTypes {
+		e.GET(TargetSpecRoot(kind), h.Get)
+	}
+}
+
+// Get the target specs of the VM.
+func (h TargetSpecHandler) Get(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	if TargetSpecRoot(h.Provider.Type()) != ctx.FullPath() {
+		ctx.Header(base.ReasonHeader, base.UnknownProvider)
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	plan, status, err := h.plan(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	spec, err := h.Build(h.Client, plan, ref.Ref{ID: ctx.Param(VMParam)})
+	if err != nil {
+		if errors.As(err, &base.NotFoundError{}) {
+			base.ReplyError(ctx, http.StatusNotFound, err)
+			return
+		}
+		if errors.As(err, &TargetSpecNotSupportedError{}) {
+			base.ReplyError(ctx, http.StatusBadRequest, err)
+			return
+		}
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, spec)
+}
+
+// Build the (in-memory) plan of the request.
+// The referenced providers and maps are resolved.
+func (h *TargetSpecHandler) plan(ctx *gin.Context) (plan *api.Plan, status int, err error) {
+	status = http.StatusOK
+	provider := h.Provider
+	plan = &api.Plan{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: provider.Namespace,
+			Name:      PreviewPlan,
+		},
+	}
+	plan.Spec.TargetNamespace = provider.Namespace
+	if namespace := ctx.Query(TargetNsParam); namespace != "" {
+		plan.Spec.TargetNamespace = namespace
+	}
+	if warm := ctx.Query(WarmParam); warm != "" {
+		plan.Spec.Warm, err = strconv.ParseBool(warm)
+		if err != nil {
+			status = http.StatusBadRequest
+			err = liberr.Wrap(err, "param", WarmParam)
+			return
+		}
+	}
+	plan.Spec.Provider.Source = core.ObjectReference{
+		Namespace: provider.Namespace,
+		Name:      provider.Name,
+	}
+	plan.Referenced.Provider.Source = provider
+	destination, err := h.destination(ctx.Query(DestinationParam), provider.Namespace)
+	if err != nil {
+		status = h.status(err)
+		return
+	}
+	plan.Spec.Provider.Destination = core.ObjectReference{
+		Namespace: destination.Namespace,
+		Name:      destination.Name,
+	}
+	plan.Referenced.Provider.Destination = destination
+	plan.Referenced.Map.Network = &api.NetworkMap{}
+	if key := ctx.Query(NetworkMapParam); key != "" {
+		err = h.Client.Get(context.TODO(), objectKey(key, provider.Namespace), plan.Referenced.Map.Network)
+		if err != nil {
+			status = h.status(err)
+			return
+		}
+		plan.Spec.Map.Network = mapRef(plan.Referenced.Map.Network)
+	}
+	plan.Referenced.Map.Storage = &api.StorageMap{}
+	if key := ctx.Query(StorageMapParam); key != "" {
+		err = h.Client.Get(context.TODO(), objectKey(key, provider.Namespace), plan.Referenced.Map.Storage)
+		if err != nil {
+			status = h.status(err)
+			return
+		}
+		plan.Spec.Map.Storage = mapRef(plan.Referenced.Map.Storage)
+	}
+	return
+}
+
+// The destination provider.
+// Defaults to the host provider, preferably in the namespace.
+func (h *TargetSpecHandler) destination(key, namespace string) (provider *api.Provider, err error) {
+	if key != "" {
+		provider = &api.Provider{}
+		err = h.Client.Get(context.TODO(), objectKey(key, namespace), provider)
+		return
+	}
+	list := &api.ProviderList{}
+	err = h.Client.List(context.TODO(), list)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for i := range list.Items {
+		p := &list.Items[i]
+		if !p.IsHost() {
+			continue
+		}
+		if provider == nil || p.Namespace == namespace {
+			provider = p
+		}
+	}
+	if provider == nil {
+		err = liberr.New("host provider not found.")
+	}
+	return
+}
+
+// The status reflecting the resolution error.
+func (h *TargetSpecHandler) status(err error) int {
+	if k8serr.IsNotFound(err) {
+		return http.StatusNotFound
+	}
+	log.Trace(err)
+	return http.StatusInternalServerError
+}
+
+// Object key of a `name` or `namespace/name` reference.
+func objectKey(key, namespace string) client.ObjectKey {
+	if ns, name, found := strings.Cut(key, "/"); found {
+		return client.ObjectKey{Namespace: ns, Name: name}
+	}
+	return client.ObjectKey{Namespace: namespace, Name: key}
+}
+
+// Reference to the map.
+func mapRef(object client.Object) core.ObjectReference {
+	return core.ObjectReference{
+		Namespace: object.GetNamespace(),
+		Name:      object.GetName(),
+	}
+}
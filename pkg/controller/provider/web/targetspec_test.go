@@ -0,0 +1,96 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/vsphere"
+	"github.com/onsi/gomega"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeClient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestTargetSpecRoute(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	for _, h := range vsphere.Handlers(nil) {
+		h.AddRoutes(router)
+	}
+	for _, kind := range api.ProviderTypes {
+		router.GET(TargetSpecRoot(kind), func(ctx *gin.Context) {
+			ctx.String(http.StatusOK, ctx.FullPath()+"|"+ctx.Param(VMParam))
+		})
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(
+		recorder,
+		httptest.NewRequest(http.MethodGet, "/providers/vsphere/p1/vms/vm-1/target-spec", nil))
+	g.Expect(recorder.Code).To(gomega.Equal(http.StatusOK))
+	g.Expect(recorder.Body.String()).To(gomega.Equal(TargetSpecRoot(api.VSphere) + "|vm-1"))
+}
+
+func TestTargetSpecPlan(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	gin.SetMode(gin.TestMode)
+	openshift := api.OpenShift
+	vSphere := api.VSphere
+	source := &api.Provider{ObjectMeta: meta.ObjectMeta{Namespace: "ns", Name: "vcenter", UID: "p1"}}
+	source.Spec.Type = &vSphere
+	host := func(namespace string) *api.Provider {
+		p := &api.Provider{ObjectMeta: meta.ObjectMeta{Namespace: namespace, Name: "host"}}
+		p.Spec.Type = &openshift
+		return p
+	}
+	scheme := runtime.NewScheme()
+	_ = api.SchemeBuilder.AddToScheme(scheme)
+	handler := &TargetSpecHandler{
+		Client: fakeClient.NewClientBuilder().
+			WithScheme(scheme).
+			WithRuntimeObjects(
+				source,
+				host("forklift"),
+				host("ns"),
+				&api.NetworkMap{ObjectMeta: meta.ObjectMeta{Namespace: "ns", Name: "network"}},
+				&api.StorageMap{ObjectMeta: meta.ObjectMeta{Namespace: "other", Name: "storage"}}).
+			Build(),
+	}
+	handler.Provider = source
+	build := func(query string) (plan *api.Plan, status int) {
+		ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+		ctx.Request = httptest.NewRequest(http.MethodGet, "/?"+query, nil)
+		plan, status, _ = handler.plan(ctx)
+		return
+	}
+
+	// Defaults.
+	plan, status := build("")
+	g.Expect(status).To(gomega.Equal(http.StatusOK))
+	g.Expect(plan.Spec.TargetNamespace).To(gomega.Equal("ns"))
+	g.Expect(plan.Spec.Warm).To(gomega.BeFalse())
+	g.Expect(plan.Referenced.Provider.Source).To(gomega.BeIdenticalTo(source))
+	g.Expect(plan.Referenced.Provider.Destination.Namespace).To(gomega.Equal("ns"))
+	g.Expect(plan.Referenced.Map.Network).ToNot(gomega.BeNil())
+	g.Expect(plan.Referenced.Map.Storage).ToNot(gomega.BeNil())
+	// Referenced.
+	plan, status = build("networkMap=network&storageMap=other/storage&targetNamespace=vms&warm=true&destination=forklift/host")
+	g.Expect(status).To(gomega.Equal(http.StatusOK))
+	g.Expect(plan.Spec.TargetNamespace).To(gomega.Equal("vms"))
+	g.Expect(plan.Spec.Warm).To(gomega.BeTrue())
+	g.Expect(plan.Spec.Provider.Destination.Namespace).To(gomega.Equal("forklift"))
+	g.Expect(plan.Spec.Map.Network.Name).To(gomega.Equal("network"))
+	g.Expect(plan.Referenced.Map.Network.Name).To(gomega.Equal("network"))
+	g.Expect(plan.Spec.Map.Storage.Namespace).To(gomega.Equal("other"))
+	g.Expect(plan.Referenced.Map.Storage.Name).To(gomega.Equal("storage"))
+	// Invalid.
+	_, status = build("networkMap=missing")
+	g.Expect(status).To(gomega.Equal(http.StatusNotFound))
+	_, status = build("warm=maybe")
+	g.Expect(status).To(gomega.Equal(http.StatusBadRequest))
+}
@@ -39,8 +39,7 @@ func (h *ClusterHandler) AddRoutes(e *gin.Engine) {
 func (h ClusterHandler) List(ctx *gin.Context) {
 	status, err := h.Prepare(ctx)
 	if status != http.StatusOK {
-		ctx.Status(status)
-		base.SetForkliftError(ctx, err)
+		base.ReplyError(ctx, status, err)
 		return
 	}
 	if h.WatchRequest {
@@ -53,7 +52,7 @@ func (h ClusterHandler) List(ctx *gin.Context) {
 				err,
 				"url",
 				ctx.Request.URL)
-			ctx.Status(http.StatusInternalServerError)
+			base.ReplyError(ctx, http.StatusInternalServerError, err)
 		}
 	}()
 	db := h.Collector.DB()
@@ -92,8 +91,7 @@ func (h ClusterHandler) List(ctx *gin.Context) {
 func (h ClusterHandler) Get(ctx *gin.Context) {
 	status, err := h.Prepare(ctx)
 	if status != http.StatusOK {
-		ctx.Status(status)
-		base.SetForkliftError(ctx, err)
+		base.ReplyError(ctx, status, err)
 		return
 	}
 	m := &model.Cluster{
@@ -104,7 +102,7 @@ func (h ClusterHandler) Get(ctx *gin.Context) {
 	db := h.Collector.DB()
 	err = db.Get(m)
 	if errors.Is(err, model.NotFound) {
-		ctx.Status(http.StatusNotFound)
+		base.ReplyError(ctx, http.StatusNotFound, err)
 		return
 	}
 	if err != nil {
@@ -112,7 +110,7 @@ func (h ClusterHandler) Get(ctx *gin.Context) {
 			err,
 			"url",
 			ctx.Request.URL)
-		ctx.Status(http.StatusInternalServerError)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 	pb := PathBuilder{DB: db}
@@ -147,7 +145,7 @@ func (h *ClusterHandler) watch(ctx *gin.Context) {
 			err,
 			"url",
 			ctx.Request.URL)
-		ctx.Status(http.StatusInternalServerError)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
 	}
 }
 
@@ -183,15 +181,17 @@ func (h *ClusterHandler) filter(ctx *gin.Context, list *[]model.Cluster) (err er
 // REST Resource.
 type Cluster struct {
 	Resource
-	Folder      string      `json:"folder"`
-	Networks    []model.Ref `json:"networks"`
-	Datastores  []model.Ref `json:"datastores"`
-	Hosts       []model.Ref `json:"hosts"`
-	DasEnabled  bool        `json:"dasEnabled"`
-	DasVms      []model.Ref `json:"dasVms"`
-	DrsEnabled  bool        `json:"drsEnabled"`
-	DrsBehavior string      `json:"drsBehavior"`
-	DrsVms      []model.Ref `json:"drsVms"`
+	Folder      string              `json:"folder"`
+	Networks    []model.Ref         `json:"networks"`
+	Datastores  []model.Ref         `json:"datastores"`
+	Hosts       []model.Ref         `json:"hosts"`
+	DasEnabled  bool                `json:"dasEnabled"`
+	DasVms      []model.Ref         `json:"dasVms"`
+	DrsEnabled  bool                `json:"drsEnabled"`
+	DrsBehavior string              `json:"drsBehavior"`
+	DrsVms      []model.Ref         `json:"drsVms"`
+	EvcMode     string              `json:"evcMode,omitempty"`
+	Rules       []model.ClusterRule `json:"rules,omitempty"`
 }
 
 // Build the resource using the model.
@@ -206,6 +206,8 @@ func (r *Cluster) With(m *model.Cluster) {
 	r.Hosts = m.Hosts
 	r.DasVms = m.DasVms
 	r.DrsVms = m.DasVms
+	r.EvcMode = m.EvcMode
+	r.Rules = m.Rules
 }
 
 // Build self link (URI).
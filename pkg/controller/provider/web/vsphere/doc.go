@@ -21,6 +21,18 @@ func Handlers(container *container.Container) []libweb.RequestHandler {
 				Container: container,
 			},
 		},
+		&base.HealthHandler{
+			Handler: base.Handler{
+				Container: container,
+			},
+			Kind: api.VSphere,
+		},
+		&base.ParityHandler{
+			Handler: base.Handler{
+				Container: container,
+			},
+			Kind: api.VSphere,
+		},
 		&TreeHandler{
 			Handler: Handler{
 				base.Handler{Container: container},
@@ -46,6 +58,11 @@ func Handlers(container *container.Container) []libweb.RequestHandler {
 				base.Handler{Container: container},
 			},
 		},
+		&ThumbprintHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
 		&NetworkHandler{
 			Handler: Handler{
 				base.Handler{Container: container},
@@ -66,6 +83,21 @@ func Handlers(container *container.Container) []libweb.RequestHandler {
 				base.Handler{Container: container},
 			},
 		},
+		&SearchHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
+		&MapHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
+		&PlanHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
 	}
 
 	if settings.Settings.OpenShift {
@@ -45,8 +45,7 @@ func (h *HostHandler) AddRoutes(e *gin.Engine) {
 func (h HostHandler) List(ctx *gin.Context) {
 	status, err := h.Prepare(ctx)
 	if status != http.StatusOK {
-		ctx.Status(status)
-		base.SetForkliftError(ctx, err)
+		base.ReplyError(ctx, status, err)
 		return
 	}
 	if h.WatchRequest {
@@ -59,7 +58,7 @@ func (h HostHandler) List(ctx *gin.Context) {
 				err,
 				"url",
 				ctx.Request.URL)
-			ctx.Status(http.StatusInternalServerError)
+			base.ReplyError(ctx, http.StatusInternalServerError, err)
 		}
 	}()
 	db := h.Collector.DB()
@@ -93,8 +92,7 @@ func (h HostHandler) List(ctx *gin.Context) {
 func (h HostHandler) Get(ctx *gin.Context) {
 	status, err := h.Prepare(ctx)
 	if status != http.StatusOK {
-		ctx.Status(status)
-		base.SetForkliftError(ctx, err)
+		base.ReplyError(ctx, status, err)
 		return
 	}
 	h.Detail = model.MaxDetail
@@ -106,7 +104,7 @@ func (h HostHandler) Get(ctx *gin.Context) {
 	db := h.Collector.DB()
 	err = db.Get(m)
 	if errors.Is(err, model.NotFound) {
-		ctx.Status(http.StatusNotFound)
+		base.ReplyError(ctx, http.StatusNotFound, err)
 		return
 	}
 	if err != nil {
@@ -114,7 +112,7 @@ func (h HostHandler) Get(ctx *gin.Context) {
 			err,
 			"url",
 			ctx.Request.URL)
-		ctx.Status(http.StatusInternalServerError)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 	pb := PathBuilder{DB: db}
@@ -126,7 +124,7 @@ func (h HostHandler) Get(ctx *gin.Context) {
 			err,
 			"url",
 			ctx.Request.URL)
-		ctx.Status(http.StatusInternalServerError)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -184,7 +182,7 @@ func (h *HostHandler) watch(ctx *gin.Context) {
 			err,
 			"url",
 			ctx.Request.URL)
-		ctx.Status(http.StatusInternalServerError)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
 	}
 }
 
@@ -299,11 +297,12 @@ func (r *Host) Content(detail int) interface{} {
 
 // Host network adapter.
 type NetworkAdapter struct {
-	Name       string `json:"name"`
-	IpAddress  string `json:"ipAddress"`
-	SubnetMask string `json:"subnetMask"`
-	LinkSpeed  int32  `json:"linkSpeed"`
-	MTU        int32  `json:"mtu"`
+	Name          string   `json:"name"`
+	IpAddress     string   `json:"ipAddress"`
+	IpV6Addresses []string `json:"ipV6Addresses,omitempty"`
+	SubnetMask    string   `json:"subnetMask"`
+	LinkSpeed     int32    `json:"linkSpeed"`
+	MTU           int32    `json:"mtu"`
 }
 
 // Build (and set) adapter list in the host.
@@ -318,9 +317,10 @@ func (r *AdapterBuilder) build(host *Host) (err error) {
 	networking := host.Network
 	for _, vNIC := range networking.VNICs {
 		adapter := NetworkAdapter{
-			IpAddress:  vNIC.IpAddress,
-			SubnetMask: vNIC.SubnetMask,
-			MTU:        vNIC.MTU,
+			IpAddress:     vNIC.IpAddress,
+			IpV6Addresses: vNIC.IpV6Addresses,
+			SubnetMask:    vNIC.SubnetMask,
+			MTU:           vNIC.MTU,
 		}
 		if vNIC.PortGroup != "" {
 			r.withPG(host, &vNIC, &adapter)
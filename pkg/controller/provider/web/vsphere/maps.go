@@ -0,0 +1,134 @@
+package vsphere
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/vsphere"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+)
+
+// Routes.
+const (
+	MapsGenerateRoot  = ProviderRoot + "/" + base.MapsGenerateRoot
+	StorageMatrixRoot = ProviderRoot + "/" + base.StorageMatrixRoot
+)
+
+// Map generation handler.
+type MapHandler struct {
+	Handler
+}
+
+// Add routes to the `gin` router.
+func (h *MapHandler) AddRoutes(e *gin.Engine) {
+	e.POST(MapsGenerateRoot, h.Generate)
+	e.GET(StorageMatrixRoot, h.StorageMatrix)
+}
+
+// Generate network and storage maps for the VMs.
+func (h MapHandler) Generate(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	generator := base.MapGenerator{
+		Container: h.Container,
+		Provider:  h.Provider,
+	}
+	generator.Generate(ctx, h.sources)
+}
+
+// Source storage vs destination storage class matrix.
+func (h MapHandler) StorageMatrix(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	generator := base.MapGenerator{
+		Container: h.Container,
+		Provider:  h.Provider,
+	}
+	generator.Matrix(ctx, h.storage)
+}
+
+// Networks and datastores used by the VM.
+func (h MapHandler) sources(vmRef ref.Ref) (sources base.MapSources, err error) {
+	db := h.Collector.DB()
+	vm, err := h.findVM(db, vmRef)
+	if err != nil {
+		return
+	}
+	for _, r := range vm.Networks {
+		network := &model.Network{Base: model.Base{ID: r.ID}}
+		err = db.Get(network)
+		if err != nil {
+			return
+		}
+		sources.Networks = append(sources.Networks, ref.Ref{ID: network.ID, Name: network.Name})
+	}
+	for _, disk := range vm.Disks {
+		ds := &model.Datastore{Base: model.Base{ID: disk.Datastore.ID}}
+		err = db.Get(ds)
+		if err != nil {
+			return
+		}
+		sources.Storage = append(sources.Storage, ref.Ref{ID: ds.ID, Name: ds.Name})
+	}
+
+	return
+}
+
+// Find the VM by ID or name.
+func (h MapHandler) findVM(db libmodel.DB, vmRef ref.Ref) (vm *model.VM, err error) {
+	predicate := libmodel.Eq("id", vmRef.ID)
+	if vmRef.ID == "" {
+		path := strings.Split(vmRef.Name, "/")
+		predicate = libmodel.Eq(NameParam, path[len(path)-1])
+	}
+	list := []model.VM{}
+	err = db.List(&list, model.ListOptions{Predicate: predicate, Detail: model.MaxDetail})
+	if err != nil {
+		return
+	}
+	switch len(list) {
+	case 0:
+		err = base.NotFoundError{Ref: vmRef}
+	case 1:
+		vm = &list[0]
+	default:
+		err = base.RefNotUniqueError{Ref: vmRef}
+	}
+
+	return
+}
+
+// Datastores.
+func (h MapHandler) storage() (sources []base.StorageSource, err error) {
+	list := []model.Datastore{}
+	err = h.Collector.DB().List(&list, model.ListOptions{Detail: model.MaxDetail})
+	if err != nil {
+		return
+	}
+	for _, ds := range list {
+		sources = append(
+			sources,
+			base.StorageSource{
+				Ref:   ref.Ref{ID: ds.ID, Name: ds.Name},
+				Type:  ds.Type,
+				Block: blockDatastore[strings.ToUpper(ds.Type)],
+			})
+	}
+
+	return
+}
+
+// Block backed datastore types.
+var blockDatastore = map[string]bool{
+	"VMFS": true,
+	"VVOL": true,
+}
@@ -0,0 +1,60 @@
+package vsphere
+
+import (
+	"net/http"
+
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	libweb "github.com/kubev2v/forklift/pkg/lib/inventory/web"
+)
+
+// Route (OpenAPI) schemas.
+func Schemas() []libweb.RouteSchema {
+	return []libweb.RouteSchema{
+		{Method: http.MethodGet, Path: ProvidersRoot, Response: []Provider{}},
+		{Method: http.MethodGet, Path: ProviderRoot, Response: Provider{}},
+		{Method: http.MethodGet, Path: TreeHostRoot, Response: TreeNode{}},
+		{Method: http.MethodGet, Path: TreeVmRoot, Response: TreeNode{}},
+		{Method: http.MethodGet, Path: FoldersRoot, Response: []Folder{}},
+		{Method: http.MethodGet, Path: FolderRoot, Response: Folder{}},
+		{Method: http.MethodGet, Path: DatacentersRoot, Response: []Datacenter{}},
+		{Method: http.MethodGet, Path: DatacenterRoot, Response: Datacenter{}},
+		{Method: http.MethodGet, Path: ClustersRoot, Response: []Cluster{}},
+		{Method: http.MethodGet, Path: ClusterRoot, Response: Cluster{}},
+		{Method: http.MethodGet, Path: HostsRoot, Response: []Host{}},
+		{Method: http.MethodGet, Path: HostRoot, Response: Host{}},
+		{Method: http.MethodGet, Path: ThumbprintsRoot, Response: []Thumbprint{}},
+		{Method: http.MethodGet, Path: NetworksRoot, Response: []Network{}},
+		{Method: http.MethodGet, Path: NetworkRoot, Response: Network{}},
+		{Method: http.MethodGet, Path: DatastoresRoot, Response: []Datastore{}},
+		{Method: http.MethodGet, Path: DatastoreRoot, Response: Datastore{}},
+		{Method: http.MethodGet, Path: VMsRoot, Response: []VM{}},
+		{Method: http.MethodGet, Path: VMRoot, Response: VM{}},
+		{Method: http.MethodGet, Path: WorkloadRoot, Response: Workload{}},
+		{
+			Method:   http.MethodGet,
+			Path:     SearchRoot,
+			Query:    []string{base.SearchParam, base.LimitParam},
+			Response: []base.SearchHit{},
+		},
+		{
+			Method:   http.MethodPost,
+			Path:     MapsGenerateRoot,
+			Request:  base.MapsRequest{},
+			Response: base.GeneratedMaps{},
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     StorageMatrixRoot,
+			Query:    []string{base.DestinationParam},
+			Response: base.StorageMatrix{},
+		},
+		{
+			Method:   http.MethodPost,
+			Path:     PlansGenerateRoot,
+			Request:  base.PlansRequest{},
+			Response: base.GeneratedPlans{},
+		},
+		{Method: http.MethodPost, Path: VddkBuildImage, Response: map[string]string{}},
+		{Method: http.MethodGet, Path: VddkRoot + "/image-url", Response: map[string]string{}},
+	}
+}
@@ -0,0 +1,121 @@
+package vsphere
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/vsphere"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+)
+
+// Routes.
+const (
+	PlansGenerateRoot = ProviderRoot + "/" + base.PlansGenerateRoot
+)
+
+// Plan generation handler.
+type PlanHandler struct {
+	Handler
+}
+
+// Add routes to the `gin` router.
+func (h *PlanHandler) AddRoutes(e *gin.Engine) {
+	e.POST(PlansGenerateRoot, h.Generate)
+}
+
+// Generate plans for the VMs matching the selector.
+func (h PlanHandler) Generate(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	generator := base.PlanGenerator{
+		Container: h.Container,
+		Provider:  h.Provider,
+	}
+	generator.Generate(ctx, h.selectVMs)
+}
+
+// VMs (not templates) matching the selector, sorted by path.
+// The folder is matched by ID or as a path prefix.
+func (h PlanHandler) selectVMs(selector base.VMSelector, name *regexp.Regexp) (vms []ref.Ref, err error) {
+	if selector.Tag != "" {
+		err = base.SelectorNotSupportedError{Criteria: "tag"}
+		return
+	}
+	db := h.Collector.DB()
+	list := []model.VM{}
+	err = db.List(&list, model.ListOptions{Detail: model.MaxDetail})
+	if err != nil {
+		return
+	}
+	clusterOf := map[string]*model.Cluster{}
+	pb := PathBuilder{DB: db}
+	type selected struct {
+		ref  ref.Ref
+		path string
+	}
+	matched := []selected{}
+	for i := range list {
+		vm := &list[i]
+		if vm.IsTemplate {
+			continue
+		}
+		if name != nil && !name.MatchString(vm.Name) {
+			continue
+		}
+		path := pb.Path(vm)
+		if selector.Folder != "" &&
+			vm.Parent.ID != selector.Folder &&
+			!strings.HasPrefix(path, strings.TrimSuffix(selector.Folder, "/")+"/") {
+			continue
+		}
+		if selector.Cluster != "" {
+			cluster, found := clusterOf[vm.Host]
+			if !found {
+				cluster, err = h.cluster(vm.Host)
+				if err != nil {
+					return
+				}
+				clusterOf[vm.Host] = cluster
+			}
+			if cluster == nil || cluster.ID != selector.Cluster && cluster.Name != selector.Cluster {
+				continue
+			}
+		}
+		matched = append(matched, selected{ref: ref.Ref{ID: vm.ID, Name: vm.Name}, path: path})
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].path < matched[j].path
+	})
+	for _, m := range matched {
+		vms = append(vms, m.ref)
+	}
+
+	return
+}
+
+// The cluster of the host.
+// Nil when the host is not in a cluster.
+func (h PlanHandler) cluster(hostID string) (cluster *model.Cluster, err error) {
+	if hostID == "" {
+		return
+	}
+	db := h.Collector.DB()
+	host := &model.Host{Base: model.Base{ID: hostID}}
+	err = db.Get(host)
+	if err != nil {
+		return
+	}
+	if host.Cluster == "" {
+		return
+	}
+	cluster = &model.Cluster{Base: model.Base{ID: host.Cluster}}
+	err = db.Get(cluster)
+	return
+}
@@ -27,6 +27,7 @@ type ProviderHandler struct {
 func (h *ProviderHandler) AddRoutes(e *gin.Engine) {
 	e.GET(ProvidersRoot, h.List)
 	e.GET(ProvidersRoot+"/", h.List)
+	base.DefaultAuth.Require(ProvidersRoot, base.Authenticated)
 	e.GET(ProviderRoot, h.Get)
 }
 
@@ -34,12 +35,11 @@ func (h *ProviderHandler) AddRoutes(e *gin.Engine) {
 func (h ProviderHandler) List(ctx *gin.Context) {
 	status, err := h.Prepare(ctx)
 	if status != http.StatusOK {
-		ctx.Status(status)
-		base.SetForkliftError(ctx, err)
+		base.ReplyError(ctx, status, err)
 		return
 	}
 	if h.WatchRequest {
-		ctx.Status(http.StatusBadRequest)
+		base.ReplyError(ctx, http.StatusBadRequest, err)
 		return
 	}
 	content, err := h.ListContent(ctx)
@@ -48,7 +48,7 @@ func (h ProviderHandler) List(ctx *gin.Context) {
 			err,
 			"url",
 			ctx.Request.URL)
-		ctx.Status(http.StatusInternalServerError)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -59,12 +59,11 @@ func (h ProviderHandler) List(ctx *gin.Context) {
 func (h ProviderHandler) Get(ctx *gin.Context) {
 	status, err := h.Prepare(ctx)
 	if status != http.StatusOK {
-		ctx.Status(status)
-		base.SetForkliftError(ctx, err)
+		base.ReplyError(ctx, status, err)
 		return
 	}
 	if h.Provider.Type() != api.VSphere {
-		ctx.Status(http.StatusNotFound)
+		base.ReplyError(ctx, http.StatusNotFound, err)
 		return
 	}
 	h.Detail = model.MaxDetail
@@ -78,7 +77,7 @@ func (h ProviderHandler) Get(ctx *gin.Context) {
 			err,
 			"url",
 			ctx.Request.URL)
-		ctx.Status(http.StatusInternalServerError)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 	r.Link()
@@ -101,6 +100,9 @@ func (h *ProviderHandler) ListContent(ctx *gin.Context) (content []interface{},
 			if ns != "" && ns != p.Namespace {
 				continue
 			}
+			if !h.Visible(ctx, p) {
+				continue
+			}
 			if collector, found := h.Container.Get(p); found {
 				h.Collector = collector
 			} else {
@@ -141,6 +143,27 @@ func (h ProviderHandler) AddDerived(r *Provider) (err error) {
 	r.APIVersion = about.APIVersion
 	r.Product = about.Product
 	r.InstanceUuid = about.InstanceUuid
+	// Additional vCenters.
+	aboutList := []vsphere.About{}
+	err = db.List(&aboutList, vsphere.ListOptions{})
+	if err != nil {
+		return
+	}
+	r.VCenters = []VCenter{}
+	for _, m := range aboutList {
+		if m.ID == "" {
+			continue
+		}
+		r.VCenters = append(
+			r.VCenters,
+			VCenter{
+				ID:           m.ID,
+				URL:          m.URL,
+				APIVersion:   m.APIVersion,
+				Product:      m.Product,
+				InstanceUuid: m.InstanceUuid,
+			})
+	}
 	// Datacenter
 	n, err = db.Count(&vsphere.Datacenter{}, nil)
 	if err != nil {
@@ -195,6 +218,17 @@ type Provider struct {
 	VMCount         int64        `json:"vmCount"`
 	NetworkCount    int64        `json:"networkCount"`
 	DatastoreCount  int64        `json:"datastoreCount"`
+	VCenters        []VCenter    `json:"vcenters,omitempty"`
+}
+
+// Additional vCenter.
+type VCenter struct {
+	// The vCenter key (host) used to prefix the object IDs.
+	ID           string `json:"id"`
+	URL          string `json:"url"`
+	APIVersion   string `json:"apiVersion"`
+	Product      string `json:"product"`
+	InstanceUuid string `json:"instanceUuid"`
 }
 
 // Set fields with the specified object.
@@ -18,6 +18,8 @@ type Resource struct {
 	Revision int64 `json:"revision"`
 	// Object name.
 	Name string `json:"name"`
+	// The (additional) vCenter.
+	VCenter string `json:"vcenter,omitempty"`
 	// Self link.
 	SelfLink string `json:"selfLink"`
 }
@@ -29,4 +31,5 @@ func (r *Resource) With(m *model.Base) {
 	r.Parent = m.Parent
 	r.Revision = m.Revision
 	r.Name = m.Name
+	r.VCenter = m.VCenter
 }
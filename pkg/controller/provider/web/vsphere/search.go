@@ -0,0 +1,195 @@
+package vsphere
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/vsphere"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+)
+
+// Routes.
+const (
+	SearchRoot = ProviderRoot + "/" + base.SearchCollection
+)
+
+// Search handler.
+type SearchHandler struct {
+	Handler
+}
+
+// Add routes to the `gin` router.
+func (h *SearchHandler) AddRoutes(e *gin.Engine) {
+	e.GET(SearchRoot, h.Search)
+}
+
+// Search the names, IDs, IPs and MACs of the provider inventory.
+func (h SearchHandler) Search(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	base.Search(ctx, h.Provider, h.entries)
+}
+
+// Searched resources.
+func (h SearchHandler) entries() (entries []base.SearchEntry, err error) {
+	db := h.Collector.DB()
+	options := model.ListOptions{Detail: model.MaxDetail}
+	folders := []model.Folder{}
+	err = db.List(&folders, options)
+	if err != nil {
+		return
+	}
+	for i := range folders {
+		m := &folders[i]
+		r := &Folder{}
+		r.With(m)
+		r.Link(h.Provider)
+		entries = append(
+			entries,
+			base.SearchEntry{
+				Kind:     model.FolderKind,
+				ID:       m.ID,
+				Name:     m.Name,
+				SelfLink: r.SelfLink,
+			})
+	}
+	datacenters := []model.Datacenter{}
+	err = db.List(&datacenters, options)
+	if err != nil {
+		return
+	}
+	for i := range datacenters {
+		m := &datacenters[i]
+		r := &Datacenter{}
+		r.With(m)
+		r.Link(h.Provider)
+		entries = append(
+			entries,
+			base.SearchEntry{
+				Kind:     model.DatacenterKind,
+				ID:       m.ID,
+				Name:     m.Name,
+				SelfLink: r.SelfLink,
+			})
+	}
+	clusters := []model.Cluster{}
+	err = db.List(&clusters, options)
+	if err != nil {
+		return
+	}
+	for i := range clusters {
+		m := &clusters[i]
+		r := &Cluster{}
+		r.With(m)
+		r.Link(h.Provider)
+		entries = append(
+			entries,
+			base.SearchEntry{
+				Kind:     model.ClusterKind,
+				ID:       m.ID,
+				Name:     m.Name,
+				SelfLink: r.SelfLink,
+			})
+	}
+	hosts := []model.Host{}
+	err = db.List(&hosts, options)
+	if err != nil {
+		return
+	}
+	for i := range hosts {
+		m := &hosts[i]
+		r := &Host{}
+		r.With(m)
+		r.Link(h.Provider)
+		entry := base.SearchEntry{
+			Kind:     model.HostKind,
+			ID:       m.ID,
+			Name:     m.Name,
+			SelfLink: r.SelfLink,
+		}
+		for _, vnic := range m.Network.VNICs {
+			if vnic.IpAddress != "" {
+				entry.IPs = append(entry.IPs, vnic.IpAddress)
+			}
+			entry.IPs = append(entry.IPs, vnic.IpV6Addresses...)
+		}
+		entries = append(entries, entry)
+	}
+	networks := []model.Network{}
+	err = db.List(&networks, options)
+	if err != nil {
+		return
+	}
+	for i := range networks {
+		m := &networks[i]
+		r := &Network{}
+		r.With(m)
+		r.Link(h.Provider)
+		entries = append(
+			entries,
+			base.SearchEntry{
+				Kind:     model.NetKind,
+				ID:       m.ID,
+				Name:     m.Name,
+				SelfLink: r.SelfLink,
+			})
+	}
+	datastores := []model.Datastore{}
+	err = db.List(&datastores, options)
+	if err != nil {
+		return
+	}
+	for i := range datastores {
+		m := &datastores[i]
+		r := &Datastore{}
+		r.With(m)
+		r.Link(h.Provider)
+		entries = append(
+			entries,
+			base.SearchEntry{
+				Kind:     model.DsKind,
+				ID:       m.ID,
+				Name:     m.Name,
+				SelfLink: r.SelfLink,
+			})
+	}
+	vms := []model.VM{}
+	err = db.List(&vms, options)
+	if err != nil {
+		return
+	}
+	for i := range vms {
+		m := &vms[i]
+		r := &VM{}
+		r.With(m)
+		r.Link(h.Provider)
+		entry := base.SearchEntry{
+			Kind:     model.VmKind,
+			ID:       m.ID,
+			Name:     m.Name,
+			SelfLink: r.SelfLink,
+		}
+		if m.IpAddress != "" {
+			entry.IPs = append(entry.IPs, m.IpAddress)
+		}
+		for _, guest := range m.GuestNetworks {
+			if guest.IP != "" {
+				entry.IPs = append(entry.IPs, guest.IP)
+			}
+			if guest.MAC != "" {
+				entry.MACs = append(entry.MACs, guest.MAC)
+			}
+		}
+		for _, nic := range m.NICs {
+			if nic.MAC != "" {
+				entry.MACs = append(entry.MACs, nic.MAC)
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	return
+}
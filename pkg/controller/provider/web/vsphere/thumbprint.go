@@ -0,0 +1,131 @@
+package vsphere
+
+import (
+	"net/http"
+	liburl "net/url"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/vsphere"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	"github.com/kubev2v/forklift/pkg/lib/util"
+)
+
+// Routes.
+const (
+	ThumbprintCollection = "thumbprints"
+	ThumbprintsRoot      = ProviderRoot + "/" + ThumbprintCollection
+)
+
+// Params.
+const (
+	RefreshParam = "refresh"
+)
+
+// Thumbprint kinds.
+const (
+	ProviderThumbprint = "Provider"
+	HostThumbprint     = "Host"
+)
+
+// Thumbprint handler.
+// Reports the certificate thumbprints of the vCenter (or ESXi)
+// provider and of each ESXi host as fetched from the servers
+// and cached, compared with the thumbprints known by forklift.
+type ThumbprintHandler struct {
+	Handler
+}
+
+// Add routes to the `gin` router.
+func (h *ThumbprintHandler) AddRoutes(e *gin.Engine) {
+	e.GET(ThumbprintsRoot, h.List)
+	e.GET(ThumbprintsRoot+"/", h.List)
+}
+
+// List the thumbprints.
+// The cached thumbprints are fetched again when `refresh=true`.
+func (h ThumbprintHandler) List(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		base.ReplyError(ctx, status, err)
+		return
+	}
+	refresh, _ := strconv.ParseBool(ctx.Query(RefreshParam))
+	content := []Thumbprint{}
+	url, err := liburl.Parse(h.Provider.Spec.URL)
+	if err == nil {
+		r := Thumbprint{
+			Kind:     ProviderThumbprint,
+			Name:     h.Provider.Name,
+			Address:  url.Host,
+			Expected: h.Provider.Status.Fingerprint,
+		}
+		r.Fetch(refresh)
+		content = append(content, r)
+	}
+	list := []model.Host{}
+	err = h.Collector.DB().List(&list, libmodel.ListOptions{Detail: model.MaxDetail})
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	for _, m := range list {
+		r := Thumbprint{
+			Kind:     HostThumbprint,
+			ID:       m.ID,
+			Name:     m.Name,
+			Address:  m.Name,
+			Expected: m.Thumbprint,
+		}
+		if address, found := m.Network.ManagementAddress(h.Provider.Network()); found {
+			r.Address = address
+		}
+		r.Fetch(refresh)
+		content = append(content, r)
+	}
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+// REST Resource.
+type Thumbprint struct {
+	// Kind (Provider|Host).
+	Kind string `json:"kind"`
+	// Host ID.
+	ID string `json:"id,omitempty"`
+	// Name.
+	Name string `json:"name"`
+	// Address of the server.
+	Address string `json:"address"`
+	// Thumbprint known by forklift (provider status or vCenter).
+	Expected string `json:"expected,omitempty"`
+	// Thumbprint fetched from the server.
+	Thumbprint string `json:"thumbprint,omitempty"`
+	// Fetch timestamp.
+	Checked *time.Time `json:"checked,omitempty"`
+	// The fetched thumbprint differs from the expected.
+	Changed bool `json:"changed"`
+	// Fetch error.
+	Error string `json:"error,omitempty"`
+}
+
+// Fetch the thumbprint (cached) from the server.
+func (r *Thumbprint) Fetch(refresh bool) {
+	if refresh {
+		util.Thumbprints.Delete(r.Address)
+	}
+	fetched, err := util.Thumbprints.Get(r.Address)
+	if err != nil {
+		r.Error = err.Error()
+		return
+	}
+	r.Thumbprint = fetched.Thumbprint
+	r.Checked = &fetched.Checked
+	r.Changed = r.Expected != "" && r.Expected != r.Thumbprint
+}
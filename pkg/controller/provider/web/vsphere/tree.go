@@ -39,8 +39,7 @@ func (h *TreeHandler) AddRoutes(e *gin.Engine) {
 func (h *TreeHandler) Prepare(ctx *gin.Context) int {
 	status, err := h.Handler.Prepare(ctx)
 	if status != http.StatusOK {
-		ctx.Status(status)
-		base.SetForkliftError(ctx, err)
+		base.ReplyError(ctx, status, err)
 		return status
 	}
 	db := h.Collector.DB()
@@ -54,6 +53,7 @@ func (h *TreeHandler) Prepare(ctx *gin.Context) int {
 			err,
 			"url",
 			ctx.Request.URL)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
 		return http.StatusInternalServerError
 	}
 
@@ -62,23 +62,22 @@ func (h *TreeHandler) Prepare(ctx *gin.Context) int {
 
 // List not supported.
 func (h TreeHandler) List(ctx *gin.Context) {
-	ctx.Status(http.StatusMethodNotAllowed)
+	base.ReplyError(ctx, http.StatusMethodNotAllowed, nil)
 }
 
 // Get not supported.
 func (h TreeHandler) Get(ctx *gin.Context) {
-	ctx.Status(http.StatusMethodNotAllowed)
+	base.ReplyError(ctx, http.StatusMethodNotAllowed, nil)
 }
 
 // VM Tree.
 func (h TreeHandler) VmTree(ctx *gin.Context) {
 	status := h.Prepare(ctx)
 	if status != http.StatusOK {
-		ctx.Status(status)
 		return
 	}
 	if h.WatchRequest {
-		ctx.Status(http.StatusBadRequest)
+		base.ReplyError(ctx, http.StatusBadRequest, nil)
 		return
 	}
 	db := h.Collector.DB()
@@ -97,7 +96,7 @@ func (h TreeHandler) VmTree(ctx *gin.Context) {
 				err,
 				"url",
 				ctx.Request.URL)
-			ctx.Status(http.StatusInternalServerError)
+			base.ReplyError(ctx, http.StatusInternalServerError, err)
 			return
 		}
 		tr := Tree{
@@ -120,7 +119,7 @@ func (h TreeHandler) VmTree(ctx *gin.Context) {
 				err,
 				"url",
 				ctx.Request.URL)
-			ctx.Status(http.StatusInternalServerError)
+			base.ReplyError(ctx, http.StatusInternalServerError, err)
 			return
 		}
 		r := Datacenter{}
@@ -139,11 +138,10 @@ func (h TreeHandler) VmTree(ctx *gin.Context) {
 func (h TreeHandler) HostTree(ctx *gin.Context) {
 	status := h.Prepare(ctx)
 	if status != http.StatusOK {
-		ctx.Status(status)
 		return
 	}
 	if h.WatchRequest {
-		ctx.Status(http.StatusBadRequest)
+		base.ReplyError(ctx, http.StatusBadRequest, nil)
 		return
 	}
 	db := h.Collector.DB()
@@ -162,7 +160,7 @@ func (h TreeHandler) HostTree(ctx *gin.Context) {
 				err,
 				"url",
 				ctx.Request.URL)
-			ctx.Status(http.StatusInternalServerError)
+			base.ReplyError(ctx, http.StatusInternalServerError, err)
 			return
 		}
 		tr := Tree{
@@ -185,7 +183,7 @@ func (h TreeHandler) HostTree(ctx *gin.Context) {
 				err,
 				"url",
 				ctx.Request.URL)
-			ctx.Status(http.StatusInternalServerError)
+			base.ReplyError(ctx, http.StatusInternalServerError, err)
 			return
 		}
 		r := Datacenter{}
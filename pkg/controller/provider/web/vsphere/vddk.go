@@ -2,6 +2,7 @@ package vsphere
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
@@ -14,6 +15,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
 	"github.com/kubev2v/forklift/pkg/settings"
 	buildv1 "github.com/openshift/api/build/v1"
 	buildclientset "github.com/openshift/client-go/build/clientset/versioned"
@@ -21,11 +23,11 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/rest"
 )
 
 const (
-	VddkRoot = "vddk" // Route
+	VddkRoot       = "vddk" // Route
+	VddkBuildImage = "/" + VddkRoot + "/build-image"
 )
 
 var (
@@ -35,6 +37,12 @@ var (
 	uploadDir        = "/tmp/uploads"
 )
 
+// Client cache keys.
+const (
+	buildClientKey = "vddk|build-client"
+	imageClientKey = "vddk|image-client"
+)
+
 // VddkHandler provides endpoints for VDDK image management.
 type VddkHandler struct {
 	base.Handler
@@ -42,7 +50,8 @@ type VddkHandler struct {
 
 // AddRoutes registers the VDDK-specific HTTP routes on the given Gin engine.
 func (h *VddkHandler) AddRoutes(e *gin.Engine) {
-	e.POST(VddkRoot+"/build-image", h.BuildImage)
+	e.POST(VddkBuildImage, h.BuildImage)
+	base.DefaultAuth.Require(VddkBuildImage, "update")
 	e.GET(VddkRoot+"/image-url", h.ImageUrl)
 	e.GET(VddkRoot+"/download-tar", h.DownloadVddkTar)
 }
@@ -52,35 +61,25 @@ func (h *VddkHandler) AddRoutes(e *gin.Engine) {
 func (h *VddkHandler) BuildImage(ctx *gin.Context) {
 	status, err := h.Handler.Prepare(ctx)
 	if status != http.StatusOK {
-		ctx.Status(status)
-		base.SetForkliftError(ctx, err)
+		base.ReplyError(ctx, status, err)
 		return
 	}
 
 	file, err := ctx.FormFile("file")
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "No file provided",
-		})
+		base.ReplyError(ctx, http.StatusBadRequest, liberr.Wrap(err, "No file provided"))
 		return
 	}
 
 	fileName := fmt.Sprintf(vddkTarFileName, uuid.New().String())
 	if err := saveFile(filepath.Join(uploadDir, fileName), file); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Failed to save file",
-		})
+		base.ReplyError(ctx, http.StatusInternalServerError, liberr.Wrap(err, "Failed to save file"))
 		return
 	}
 
-	buildName, err := triggerBuildConfig(fileName)
+	buildName, err := triggerBuildConfig(ctx.Request.Context(), fileName)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": err.Error(),
-		})
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -99,8 +98,7 @@ func (h *VddkHandler) BuildImage(ctx *gin.Context) {
 func (h *VddkHandler) ImageUrl(ctx *gin.Context) {
 	status, err := h.Handler.Prepare(ctx)
 	if status != http.StatusOK {
-		ctx.Status(status)
-		base.SetForkliftError(ctx, err)
+		base.ReplyError(ctx, status, err)
 		return
 	}
 
@@ -111,18 +109,12 @@ func (h *VddkHandler) ImageUrl(ctx *gin.Context) {
 
 	url, exists, err := imageReference(ctx.Request.Context(), registryImageTag)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": fmt.Sprintf("Error checking image reference: %v", err),
-		})
+		base.ReplyError(ctx, http.StatusInternalServerError, liberr.Wrap(err, "Error checking image reference"))
 		return
 	}
 
 	if !exists {
-		ctx.JSON(http.StatusNotFound, gin.H{
-			"status":  "error",
-			"message": fmt.Sprintf("Image: %s not found", registryImageTag),
-		})
+		base.ReplyError(ctx, http.StatusNotFound, fmt.Errorf("Image: %s not found", registryImageTag))
 		return
 	}
 
@@ -139,17 +131,13 @@ func (h *VddkHandler) ImageUrl(ctx *gin.Context) {
 func (h *VddkHandler) DownloadVddkTar(ctx *gin.Context) {
 	status, err := h.Handler.Prepare(ctx)
 	if status != http.StatusOK {
-		ctx.Status(status)
-		base.SetForkliftError(ctx, err)
+		base.ReplyError(ctx, status, err)
 		return
 	}
 
 	filename := ctx.Query("filename")
 	if filename == "" {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "No filename provided",
-		})
+		base.ReplyError(ctx, http.StatusBadRequest, errors.New("No filename provided"))
 		return
 	}
 
@@ -157,15 +145,9 @@ func (h *VddkHandler) DownloadVddkTar(ctx *gin.Context) {
 
 	if _, err := os.Stat(filePath); err != nil {
 		if os.IsNotExist(err) {
-			ctx.JSON(http.StatusNotFound, gin.H{
-				"status":  "error",
-				"message": "VDDK tar not found",
-			})
+			base.ReplyError(ctx, http.StatusNotFound, liberr.Wrap(err, "VDDK tar not found"))
 		} else {
-			ctx.JSON(http.StatusInternalServerError, gin.H{
-				"status":  "error",
-				"message": fmt.Sprintf("Failed to stat file: %v", err),
-			})
+			base.ReplyError(ctx, http.StatusInternalServerError, liberr.Wrap(err, "Failed to stat file"))
 		}
 		return
 	}
@@ -209,7 +191,7 @@ func saveFile(filePath string, file *multipart.FileHeader) error {
 }
 
 // triggerBuildConfig triggers the OpenShift BuildConfig to build and push the VDDK image.
-func triggerBuildConfig(targetTarFile string) (string, error) {
+func triggerBuildConfig(ctx context.Context, targetTarFile string) (string, error) {
 	buildClient, err := NewBuildClient()
 	if err != nil {
 		return "", err
@@ -229,7 +211,7 @@ func triggerBuildConfig(targetTarFile string) (string, error) {
 
 	buildObj, err := buildClient.BuildV1().
 		BuildConfigs(settings.Settings.Namespace).
-		Instantiate(context.TODO(), buildConfigName, buildRequest, metav1.CreateOptions{})
+		Instantiate(ctx, buildConfigName, buildRequest, metav1.CreateOptions{})
 	if err != nil {
 		return "", fmt.Errorf("start build: %w", err)
 	}
@@ -240,14 +222,9 @@ func triggerBuildConfig(targetTarFile string) (string, error) {
 // imageReference returns (image url, true, nil) if the given ImageStreamTag exists,
 // ("", false, nil) if it does not, or ("", false, error) on any other failure.
 func imageReference(ctx context.Context, registryImageTag string) (string, bool, error) {
-	cfg, err := rest.InClusterConfig()
+	imgClient, err := NewImageClient()
 	if err != nil {
-		return "", false, fmt.Errorf("error load cluster config: %v", err)
-	}
-
-	imgClient, err := imagev1client.NewForConfig(cfg)
-	if err != nil {
-		return "", false, fmt.Errorf("error create image client: %v", err)
+		return "", false, err
 	}
 
 	ist, err := imgClient.ImageStreamTags(settings.Settings.Namespace).Get(ctx, registryImageTag, metav1.GetOptions{})
@@ -265,12 +242,7 @@ func imageReference(ctx context.Context, registryImageTag string) (string, bool,
 func (h *VddkHandler) watchImageURL(ctx *gin.Context) {
 	buildName := ctx.Query("build-name")
 	if buildName == "" {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"status": gin.H{
-				"phase":   "Failed",
-				"message": "build-name parameter is missing",
-			},
-		})
+		base.ReplyError(ctx, http.StatusBadRequest, errors.New("build-name parameter is missing"))
 		return
 	}
 
@@ -281,23 +253,14 @@ func (h *VddkHandler) watchImageURL(ctx *gin.Context) {
 
 	bcClient, err := NewBuildClient()
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{
-			"status": gin.H{
-				"phase":   "Failed",
-				"message": err,
-			},
-		})
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
 	conn, err := upGrader.Upgrade(ctx.Writer, ctx.Request, nil)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"status": gin.H{
-				"phase":   "Failed",
-				"message": fmt.Sprintf("error upgrading connection to websocket: %v", err),
-			},
-		})
+		// The upgrader has replied.
+		log.Trace(err, "url", ctx.Request.URL)
 		return
 	}
 	defer func() {
@@ -394,20 +357,50 @@ func isTerminal(phase buildv1.BuildPhase) bool {
 	return false
 }
 
-// NewBuildClient loads the in-cluster Kubernetes configuration and
-// returns an OpenShift Build API clientset based on that config.
+// NewBuildClient returns the (cached) OpenShift Build API clientset
+// based on the in-cluster Kubernetes configuration.
 func NewBuildClient() (*buildclientset.Clientset, error) {
-	cfg, err := rest.InClusterConfig()
+	object, err := base.Clients.Get(
+		buildClientKey,
+		func() (interface{}, error) {
+			cfg, err := base.InClusterConfig()
+			if err != nil {
+				return nil, fmt.Errorf("could not load in-cluster config: %w", err)
+			}
+			buildClient, err := buildclientset.NewForConfig(cfg)
+			if err != nil {
+				return nil, fmt.Errorf("could not create build client: %w", err)
+			}
+			return buildClient, nil
+		})
 	if err != nil {
-		return nil, fmt.Errorf("could not load in-cluster config: %w", err)
+		return nil, err
 	}
 
-	buildClient, err := buildclientset.NewForConfig(cfg)
+	return object.(*buildclientset.Clientset), nil
+}
+
+// NewImageClient returns the (cached) OpenShift Image API client
+// based on the in-cluster Kubernetes configuration.
+func NewImageClient() (*imagev1client.ImageV1Client, error) {
+	object, err := base.Clients.Get(
+		imageClientKey,
+		func() (interface{}, error) {
+			cfg, err := base.InClusterConfig()
+			if err != nil {
+				return nil, fmt.Errorf("error load cluster config: %v", err)
+			}
+			imgClient, err := imagev1client.NewForConfig(cfg)
+			if err != nil {
+				return nil, fmt.Errorf("error create image client: %v", err)
+			}
+			return imgClient, nil
+		})
 	if err != nil {
-		return nil, fmt.Errorf("could not create build client: %w", err)
+		return nil, err
 	}
 
-	return buildClient, nil
+	return object.(*imagev1client.ImageV1Client), nil
 }
 
 // cleanOldFiles Removes the old files older than period within the given directory
@@ -39,8 +39,7 @@ func (h *VMHandler) AddRoutes(e *gin.Engine) {
 func (h VMHandler) List(ctx *gin.Context) {
 	status, err := h.Prepare(ctx)
 	if status != http.StatusOK {
-		ctx.Status(status)
-		base.SetForkliftError(ctx, err)
+		base.ReplyError(ctx, status, err)
 		return
 	}
 	if h.WatchRequest {
@@ -53,7 +52,7 @@ func (h VMHandler) List(ctx *gin.Context) {
 				err,
 				"url",
 				ctx.Request.URL)
-			ctx.Status(http.StatusInternalServerError)
+			base.ReplyError(ctx, http.StatusInternalServerError, err)
 		}
 	}()
 
@@ -93,8 +92,7 @@ func (h VMHandler) List(ctx *gin.Context) {
 func (h VMHandler) Get(ctx *gin.Context) {
 	status, err := h.Prepare(ctx)
 	if status != http.StatusOK {
-		ctx.Status(status)
-		base.SetForkliftError(ctx, err)
+		base.ReplyError(ctx, status, err)
 		return
 	}
 	m := &model.VM{
@@ -105,7 +103,7 @@ func (h VMHandler) Get(ctx *gin.Context) {
 	db := h.Collector.DB()
 	err = db.Get(m)
 	if errors.Is(err, model.NotFound) {
-		ctx.Status(http.StatusNotFound)
+		base.ReplyError(ctx, http.StatusNotFound, err)
 		return
 	}
 	if err != nil {
@@ -113,7 +111,7 @@ func (h VMHandler) Get(ctx *gin.Context) {
 			err,
 			"url",
 			ctx.Request.URL)
-		ctx.Status(http.StatusInternalServerError)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 	pb := PathBuilder{DB: db}
@@ -148,7 +146,7 @@ func (h *VMHandler) watch(ctx *gin.Context) {
 			err,
 			"url",
 			ctx.Request.URL)
-		ctx.Status(http.StatusInternalServerError)
+		base.ReplyError(ctx, http.StatusInternalServerError, err)
 	}
 }
 
@@ -220,36 +218,46 @@ func (r *VM1) Content(detail int) interface{} {
 // VM full detail.
 type VM struct {
 	VM1
-	PolicyVersion            int                  `json:"policyVersion"`
-	UUID                     string               `json:"uuid"`
-	Firmware                 string               `json:"firmware"`
-	ConnectionState          string               `json:"connectionState"`
-	Snapshot                 model.Ref            `json:"snapshot"`
-	ChangeTrackingEnabled    bool                 `json:"changeTrackingEnabled"`
-	CpuAffinity              []int32              `json:"cpuAffinity"`
-	CpuHotAddEnabled         bool                 `json:"cpuHotAddEnabled"`
-	CpuHotRemoveEnabled      bool                 `json:"cpuHotRemoveEnabled"`
-	MemoryHotAddEnabled      bool                 `json:"memoryHotAddEnabled"`
-	FaultToleranceEnabled    bool                 `json:"faultToleranceEnabled"`
-	CpuCount                 int32                `json:"cpuCount"`
-	CoresPerSocket           int32                `json:"coresPerSocket"`
-	MemoryMB                 int32                `json:"memoryMB"`
-	GuestName                string               `json:"guestName"`
-	GuestNameFromVmwareTools string               `json:"guestNameFromVmwareTools"`
-	HostName                 string               `json:"hostName"`
-	GuestID                  string               `json:"guestId"`
-	BalloonedMemory          int32                `json:"balloonedMemory"`
-	IpAddress                string               `json:"ipAddress"`
-	StorageUsed              int64                `json:"storageUsed"`
-	TpmEnabled               bool                 `json:"tpmEnabled"`
-	NumaNodeAffinity         []string             `json:"numaNodeAffinity"`
-	Devices                  []model.Device       `json:"devices"`
-	NICs                     []model.NIC          `json:"nics"`
-	GuestNetworks            []model.GuestNetwork `json:"guestNetworks"`
-	GuestIpStacks            []model.GuestIpStack `json:"guestIpStacks"`
-	SecureBoot               bool                 `json:"secureBoot"`
-	DiskEnableUuid           bool                 `json:"diskEnableUuid"`
-	NestedHVEnabled          bool                 `json:"nestedHVEnabled"`
+	PolicyVersion            int                     `json:"policyVersion"`
+	UUID                     string                  `json:"uuid"`
+	Firmware                 string                  `json:"firmware"`
+	ConnectionState          string                  `json:"connectionState"`
+	Snapshot                 model.Ref               `json:"snapshot"`
+	ChangeTrackingEnabled    bool                    `json:"changeTrackingEnabled"`
+	CpuAffinity              []int32                 `json:"cpuAffinity"`
+	CpuHotAddEnabled         bool                    `json:"cpuHotAddEnabled"`
+	CpuHotRemoveEnabled      bool                    `json:"cpuHotRemoveEnabled"`
+	MemoryHotAddEnabled      bool                    `json:"memoryHotAddEnabled"`
+	FaultToleranceEnabled    bool                    `json:"faultToleranceEnabled"`
+	CpuCount                 int32                   `json:"cpuCount"`
+	CoresPerSocket           int32                   `json:"coresPerSocket"`
+	MemoryMB                 int32                   `json:"memoryMB"`
+	MemoryReservationMB      int64                   `json:"memoryReservationMB,omitempty"`
+	MemoryLimitMB            int64                   `json:"memoryLimitMB,omitempty"`
+	MemoryReservationLocked  bool                    `json:"memoryReservationLocked,omitempty"`
+	GuestName                string                  `json:"guestName"`
+	GuestNameFromVmwareTools string                  `json:"guestNameFromVmwareTools"`
+	HostName                 string                  `json:"hostName"`
+	GuestID                  string                  `json:"guestId"`
+	BalloonedMemory          int32                   `json:"balloonedMemory"`
+	IpAddress                string                  `json:"ipAddress"`
+	StorageUsed              int64                   `json:"storageUsed"`
+	TpmEnabled               bool                    `json:"tpmEnabled"`
+	NumaNodeAffinity         []string                `json:"numaNodeAffinity"`
+	Devices                  []model.Device          `json:"devices"`
+	NICs                     []model.NIC             `json:"nics"`
+	GuestNetworks            []model.GuestNetwork    `json:"guestNetworks"`
+	GuestIpStacks            []model.GuestIpStack    `json:"guestIpStacks"`
+	SecureBoot               bool                    `json:"secureBoot"`
+	DiskEnableUuid           bool                    `json:"diskEnableUuid"`
+	NestedHVEnabled          bool                    `json:"nestedHVEnabled"`
+	LatencySensitivity       string                  `json:"latencySensitivity,omitempty"`
+	HugePages1G              bool                    `json:"hugePages1G,omitempty"`
+	BootOrder                []model.BootDevice      `json:"bootOrder,omitempty"`
+	GuestInfo                model.GuestInfo         `json:"guestInfo"`
+	CustomAttributes         []model.CustomAttribute `json:"customAttributes,omitempty"`
+	Notes                    string                  `json:"notes,omitempty"`
+	CDROMs                   []model.CDROM           `json:"cdroms,omitempty"`
 }
 
 // Build the resource using the model.
@@ -268,6 +276,9 @@ func (r *VM) With(m *model.VM) {
 	r.CpuCount = m.CpuCount
 	r.CoresPerSocket = m.CoresPerSocket
 	r.MemoryMB = m.MemoryMB
+	r.MemoryReservationMB = m.MemoryReservationMB
+	r.MemoryLimitMB = m.MemoryLimitMB
+	r.MemoryReservationLocked = m.MemoryReservationLocked
 	r.GuestName = m.GuestName
 	r.GuestNameFromVmwareTools = m.GuestNameFromVmwareTools
 	r.HostName = m.HostName
@@ -285,6 +296,13 @@ func (r *VM) With(m *model.VM) {
 	r.SecureBoot = m.SecureBoot
 	r.DiskEnableUuid = m.DiskEnableUuid
 	r.NestedHVEnabled = m.NestedHVEnabled
+	r.LatencySensitivity = m.LatencySensitivity
+	r.HugePages1G = m.HugePages1G
+	r.BootOrder = m.BootOrder
+	r.GuestInfo = m.GuestInfo
+	r.CustomAttributes = m.CustomAttributes
+	r.Notes = m.Notes
+	r.CDROMs = m.CDROMs
 }
 
 // Build self link (URI).
@@ -334,6 +352,25 @@ func (r *VM) RemoveSharedDisks() {
 	r.Disks = disks
 }
 
+func (r *VM) HasRDMDisk() bool {
+	for _, d := range r.Disks {
+		if d.RDM {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *VM) RemoveRDMDisks() {
+	var disks []model.Disk
+	for _, disk := range r.Disks {
+		if !disk.RDM {
+			disks = append(disks, disk)
+		}
+	}
+	r.Disks = disks
+}
+
 func (r *VM) RemoveDisk(removeDisk model.Disk) {
 	var disks []model.Disk
 	for _, disk := range r.Disks {
@@ -37,8 +37,7 @@ func (h WorkloadHandler) List(ctx *gin.Context) {
 func (h WorkloadHandler) Get(ctx *gin.Context) {
 	status, err := h.Prepare(ctx)
 	if status != http.StatusOK {
-		ctx.Status(status)
-		base.SetForkliftError(ctx, err)
+		base.ReplyError(ctx, status, err)
 		return
 	}
 	m := &model.VM{
@@ -49,7 +48,7 @@ func (h WorkloadHandler) Get(ctx *gin.Context) {
 	db := h.Collector.DB()
 	err = db.Get(m)
 	if errors.Is(err, model.NotFound) {
-		ctx.Status(http.StatusNotFound)
+		base.ReplyError(ctx, http.StatusNotFound, err)
 		return
 	}
 	defer func() {
@@ -58,7 +57,7 @@ func (h WorkloadHandler) Get(ctx *gin.Context) {
 				err,
 				"url",
 				ctx.Request.URL)
-			ctx.JSON(http.StatusInternalServerError, err.Error())
+			base.ReplyError(ctx, http.StatusInternalServerError, err)
 		}
 	}()
 	if err != nil {
@@ -0,0 +1,237 @@
+package web
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	libweb "github.com/kubev2v/forklift/pkg/lib/inventory/web"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+)
+
+// Polled watch settings.
+var (
+	// Interval at which the watched resources are listed.
+	PollInterval = time.Second * 3
+)
+
+// Polled watch serial number.
+var pollSerial uint64
+
+// List the watched resources keyed by ID.
+type PollFunc func() (resources map[string]interface{}, err error)
+
+// Polled watch.
+// Watches resources that are not stored in the inventory DB (CRs)
+// by listing them periodically. The changes are pushed through the
+// websocket using the inventory watch (event) protocol so that the
+// inventory (web) client watches them as inventory resources.
+type PolledWatch struct {
+	// Watch options.
+	Options libmodel.WatchOptions
+	// List the resources.
+	List PollFunc
+	// Poll interval. Defaults to PollInterval.
+	Interval time.Duration
+	// Websocket.
+	socket *websocket.Conn
+	// Resources by ID (last listed).
+	resources map[string]interface{}
+	// Event serial number.
+	serial uint64
+	// Ended (by peer).
+	done chan struct{}
+	// End once.
+	once sync.Once
+	// Logger.
+	log logging.LevelLogger
+}
+
+// Upgrade the connection and start the watch.
+func (r *PolledWatch) Start(ctx *gin.Context) (err error) {
+	upGrader := websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+	}
+	r.socket, err = upGrader.Upgrade(ctx.Writer, ctx.Request, nil)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"websocket upgrade failed.",
+			"url",
+			ctx.Request.URL)
+		return
+	}
+	if r.Interval == 0 {
+		r.Interval = PollInterval
+	}
+	r.done = make(chan struct{})
+	r.log = logging.WithName("web|watch|poll").WithValues(
+		"peer",
+		r.socket.RemoteAddr(),
+		"url",
+		ctx.Request.URL.Path)
+	go r.reader()
+	go r.run()
+	return
+}
+
+// Send the events until ended by the peer.
+func (r *PolledWatch) run() {
+	defer func() {
+		_ = r.socket.Close()
+		r.log.V(3).Info("watch ended.")
+	}()
+	err := r.send(&libweb.Event{
+		ID:     atomic.AddUint64(&pollSerial, 1),
+		Action: libmodel.Started,
+	})
+	if err != nil {
+		return
+	}
+	resources, err := r.List()
+	if err != nil {
+		r.log.Error(err, "list failed.")
+		_ = r.send(&libweb.Event{Action: libmodel.End})
+		return
+	}
+	if r.Options.Snapshot {
+		err = r.diff(map[string]interface{}{}, resources)
+		if err != nil {
+			return
+		}
+	}
+	r.resources = resources
+	err = r.send(&libweb.Event{Action: libmodel.Parity})
+	if err != nil {
+		return
+	}
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.done:
+			_ = r.send(&libweb.Event{Action: libmodel.End})
+			return
+		case <-ticker.C:
+		}
+		resources, err = r.List()
+		if err != nil {
+			r.log.V(3).Info("list failed.", "reason", err.Error())
+			continue
+		}
+		err = r.diff(r.resources, resources)
+		if err != nil {
+			return
+		}
+		r.resources = resources
+	}
+}
+
+// Read (and discard) the peer events.
+// The watch is ended by the peer (end event) or
+// when the connection is closed.
+func (r *PolledWatch) reader() {
+	defer r.end()
+	for {
+		event := libweb.Event{}
+		err := r.socket.ReadJSON(&event)
+		if err != nil {
+			r.log.V(4).Info(err.Error())
+			return
+		}
+		if event.Action == libmodel.End {
+			r.log.V(4).Info("ended by peer.")
+			return
+		}
+	}
+}
+
+// End the watch.
+func (r *PolledWatch) end() {
+	r.once.Do(func() {
+		close(r.done)
+	})
+}
+
+// Send the events of the changes between the listed resources.
+func (r *PolledWatch) diff(current, next map[string]interface{}) (err error) {
+	for _, event := range Changes(current, next) {
+		err = r.send(event)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// The (created|updated|deleted) events of the changes between
+// the listed resources ordered by resource ID.
+func Changes(current, next map[string]interface{}) (events []*libweb.Event) {
+	for _, id := range sortedKeys(next) {
+		resource := next[id]
+		previous, found := current[id]
+		switch {
+		case !found:
+			events = append(
+				events,
+				&libweb.Event{
+					Action:   libmodel.Created,
+					Resource: resource,
+				})
+		case !reflect.DeepEqual(previous, resource):
+			events = append(
+				events,
+				&libweb.Event{
+					Action:   libmodel.Updated,
+					Resource: previous,
+					Updated:  resource,
+				})
+		}
+	}
+	for _, id := range sortedKeys(current) {
+		if _, found := next[id]; !found {
+			events = append(
+				events,
+				&libweb.Event{
+					Action:   libmodel.Deleted,
+					Resource: current[id],
+				})
+		}
+	}
+	return
+}
+
+// Write the event to the socket.
+func (r *PolledWatch) send(event *libweb.Event) (err error) {
+	if event.ID == 0 {
+		r.serial++
+		event.ID = r.serial
+	}
+	_ = r.socket.SetWriteDeadline(time.Now().Add(libweb.WatchWriteTimeout))
+	err = r.socket.WriteJSON(event)
+	if err != nil {
+		r.log.V(4).Error(err, "websocket send failed.")
+		return
+	}
+	r.log.V(5).Info(
+		"event sent.",
+		"event",
+		event.String())
+	return
+}
+
+// Sorted keys.
+func sortedKeys(resources map[string]interface{}) (keys []string) {
+	for key := range resources {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return
+}
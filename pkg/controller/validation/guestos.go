@@ -0,0 +1,201 @@
+package validation
+
+import (
+	"context"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	"github.com/kubev2v/forklift/pkg/settings"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Guest OS support levels.
+const (
+	// The guest OS is supported.
+	GuestOSSupported = "supported"
+	// The guest OS is not supported (blocks the migration).
+	GuestOSUnsupported = "unsupported"
+	// The guest OS is no longer supported by the conversion but
+	// may be migrated (warning). Blocked when overridden as unsupported.
+	GuestOSDeprecated = "deprecated"
+	// The guest OS is supported when converted with the listed flags.
+	GuestOSNeedsFlags = "needs-flags"
+)
+
+// Valid support level.
+func ValidGuestOSSupport(support string) bool {
+	switch support {
+	case GuestOSSupported, GuestOSUnsupported, GuestOSDeprecated, GuestOSNeedsFlags:
+		return true
+	}
+	return false
+}
+
+// Guest OS flags.
+const (
+	// The guest requires the legacy (SHA-1 signed) virtio-win drivers.
+	GuestOSLegacyDrivers = "legacy-drivers"
+	// The guest requires a persistent TPM device.
+	GuestOSTpm = "tpm"
+)
+
+// Guest OS support table rule.
+type GuestOSRule struct {
+	// Matched against the lower cased guest OS identifiers.
+	Pattern *regexp.Regexp
+	// Support level.
+	Support string
+	// Flags required by the guest (needs-flags only).
+	Flags []string
+}
+
+// Resolved guest OS support.
+type GuestOS struct {
+	// The identifier that matched.
+	ID string
+	// Support level.
+	Support string
+	// Flags required by the guest.
+	Flags []string
+	// The support level was set by an admin override.
+	Overridden bool
+}
+
+// Supported.
+func (r *GuestOS) Supported() bool {
+	return r.Support != GuestOSUnsupported
+}
+
+// Deprecated.
+func (r *GuestOS) Deprecated() bool {
+	return r.Support == GuestOSDeprecated
+}
+
+// Has flag.
+func (r *GuestOS) HasFlag(flag string) bool {
+	for _, f := range r.Flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// Guest OS support table by provider type.
+// The first matching rule wins, unmatched guests are supported.
+var GuestOSTable = map[api.ProviderType][]GuestOSRule{
+	api.VSphere: {
+		{
+			Pattern: regexp.MustCompile(`rhel6(_64)?guest|photon(64)?guest`),
+			Support: GuestOSDeprecated,
+		},
+		{
+			Pattern: regexp.MustCompile(
+				`winxp|winnet|windowsvista|longhorn|windows7|` +
+					`windows xp|server 2003|vista|server 2008|windows 7`),
+			Support: GuestOSNeedsFlags,
+			Flags:   []string{GuestOSLegacyDrivers},
+		},
+	},
+	api.OVirt: {
+		{
+			Pattern: regexp.MustCompile(`^rhel_6`),
+			Support: GuestOSDeprecated,
+		},
+		{
+			Pattern: regexp.MustCompile(`^windows_(2022|11)`),
+			Support: GuestOSNeedsFlags,
+			Flags:   []string{GuestOSTpm},
+		},
+	},
+	api.OpenStack: {
+		{
+			Pattern: regexp.MustCompile(`^(rhel|centos) [1-6]([^0-9]|$)`),
+			Support: GuestOSDeprecated,
+		},
+	},
+}
+
+// Find the guest OS support for the specified identifiers
+// (e.g. guest ID and guest name). The overrides map an identifier
+// to a support level and take precedence over the table. Overrides
+// with an invalid support level are ignored. The flags of the table
+// are kept only when overridden as needs-flags.
+func FindGuestOS(providerType api.ProviderType, overrides map[string]string, ids ...string) (guest GuestOS) {
+	guest.Support = GuestOSSupported
+	for _, rule := range GuestOSTable[providerType] {
+		matched := false
+		for _, id := range ids {
+			if id != "" && rule.Pattern.MatchString(strings.ToLower(id)) {
+				guest.ID = id
+				guest.Support = rule.Support
+				guest.Flags = rule.Flags
+				matched = true
+				break
+			}
+		}
+		if matched {
+			break
+		}
+	}
+	for _, id := range ids {
+		for key, support := range overrides {
+			support = strings.TrimSpace(support)
+			if !ValidGuestOSSupport(support) {
+				continue
+			}
+			if id != "" && strings.EqualFold(strings.TrimSpace(key), id) {
+				guest.ID = id
+				if support != GuestOSNeedsFlags {
+					guest.Flags = nil
+				}
+				guest.Support = support
+				guest.Overridden = true
+				return
+			}
+		}
+	}
+
+	return
+}
+
+// Find the overrides with an invalid support level.
+func InvalidGuestOSOverrides(overrides map[string]string) (keys []string) {
+	for key, support := range overrides {
+		if !ValidGuestOSSupport(strings.TrimSpace(support)) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return
+}
+
+// Load the admin guest OS support overrides.
+// The config map maps a guest OS identifier to a support level.
+func GuestOSOverrides(c client.Client) (overrides map[string]string, err error) {
+	name := settings.Settings.Migration.GuestOsSupportConfigMap
+	if name == "" {
+		return
+	}
+	configMap := &core.ConfigMap{}
+	err = c.Get(
+		context.TODO(),
+		client.ObjectKey{Name: name, Namespace: os.Getenv("POD_NAMESPACE")},
+		configMap)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	overrides = configMap.Data
+	return
+}
@@ -0,0 +1,99 @@
+package validation
+
+import (
+	"testing"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/settings"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	fakeClient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestFindGuestOS(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Deprecated (warning).
+	guest := FindGuestOS(api.VSphere, nil, "rhel6_64Guest", "Red Hat Enterprise Linux 6 (64-bit)")
+	g.Expect(guest.Supported()).To(gomega.BeTrue())
+	g.Expect(guest.Deprecated()).To(gomega.BeTrue())
+	g.Expect(guest.ID).To(gomega.Equal("rhel6_64Guest"))
+	guest = FindGuestOS(api.OpenStack, nil, "centos 6.10")
+	g.Expect(guest.Deprecated()).To(gomega.BeTrue())
+
+	// Needs flags, matched by the guest name.
+	guest = FindGuestOS(api.VSphere, nil, "otherGuest", "Microsoft Windows Server 2008 R2 (64-bit)")
+	g.Expect(guest.Supported()).To(gomega.BeTrue())
+	g.Expect(guest.Support).To(gomega.Equal(GuestOSNeedsFlags))
+	g.Expect(guest.HasFlag(GuestOSLegacyDrivers)).To(gomega.BeTrue())
+
+	// Not in the table.
+	guest = FindGuestOS(api.VSphere, nil, "rhel9_64Guest", "")
+	g.Expect(guest.Support).To(gomega.Equal(GuestOSSupported))
+	g.Expect(guest.Flags).To(gomega.BeEmpty())
+	guest = FindGuestOS(api.Ova, nil, "rhel6_64Guest")
+	g.Expect(guest.Support).To(gomega.Equal(GuestOSSupported))
+
+	// Admin override.
+	overrides := map[string]string{"RHEL_6x64": GuestOSSupported, "rhel_6": GuestOSUnsupported}
+	guest = FindGuestOS(api.OVirt, overrides, "rhel_6x64")
+	g.Expect(guest.Supported()).To(gomega.BeTrue())
+	g.Expect(guest.Deprecated()).To(gomega.BeFalse())
+	g.Expect(guest.Overridden).To(gomega.BeTrue())
+	// Escalated.
+	guest = FindGuestOS(api.OVirt, overrides, "rhel_6")
+	g.Expect(guest.Supported()).To(gomega.BeFalse())
+
+	// Flags reset with override.
+	guest = FindGuestOS(api.OVirt, map[string]string{"windows_11": GuestOSSupported}, "windows_11")
+	g.Expect(guest.Flags).To(gomega.BeEmpty())
+	guest = FindGuestOS(api.OVirt, map[string]string{"windows_11": GuestOSNeedsFlags}, "windows_11")
+	g.Expect(guest.HasFlag(GuestOSTpm)).To(gomega.BeTrue())
+
+	// Invalid override ignored.
+	overrides = map[string]string{"rhel_6": "blocked", "rhel_5": " unsupported "}
+	guest = FindGuestOS(api.OVirt, overrides, "rhel_6")
+	g.Expect(guest.Overridden).To(gomega.BeFalse())
+	g.Expect(guest.Deprecated()).To(gomega.BeTrue())
+	guest = FindGuestOS(api.OVirt, overrides, "rhel_5")
+	g.Expect(guest.Supported()).To(gomega.BeFalse())
+	g.Expect(InvalidGuestOSOverrides(overrides)).To(gomega.Equal([]string{"rhel_6"}))
+}
+
+func TestGuestOSOverrides(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	t.Setenv("POD_NAMESPACE", "openshift-mtv")
+	name := settings.Settings.Migration.GuestOsSupportConfigMap
+	defer func() {
+		settings.Settings.Migration.GuestOsSupportConfigMap = name
+	}()
+	client := fakeClient.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithRuntimeObjects(&core.ConfigMap{
+			ObjectMeta: meta.ObjectMeta{Namespace: "openshift-mtv", Name: "guest-os"},
+			Data:       map[string]string{"windows7Server64Guest": GuestOSSupported},
+		}).
+		Build()
+
+	guest := FindGuestOS(api.VSphere, nil, "windows7Server64Guest")
+	g.Expect(guest.HasFlag(GuestOSLegacyDrivers)).To(gomega.BeTrue())
+	// Not configured.
+	settings.Settings.Migration.GuestOsSupportConfigMap = ""
+	overrides, err := GuestOSOverrides(client)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(overrides).To(gomega.BeNil())
+	// Loaded.
+	settings.Settings.Migration.GuestOsSupportConfigMap = "guest-os"
+	overrides, err = GuestOSOverrides(client)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(overrides).To(gomega.HaveKeyWithValue("windows7Server64Guest", GuestOSSupported))
+	guest = FindGuestOS(api.VSphere, overrides, "windows7Server64Guest")
+	g.Expect(guest.HasFlag(GuestOSLegacyDrivers)).To(gomega.BeFalse())
+	// Not found.
+	settings.Settings.Migration.GuestOsSupportConfigMap = "missing"
+	overrides, err = GuestOSOverrides(client)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(overrides).To(gomega.BeNil())
+}
@@ -0,0 +1,505 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/azure"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/catalog"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/ec2"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/image"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/libvirt"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/nutanix"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/openstack"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/ova"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/ovirt"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/vsphere"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Map kinds.
+const (
+	NetworkMapKind = "network"
+	StorageMapKind = "storage"
+)
+
+// Map usage.
+type MapUsage struct {
+	// Usage of the map entries.
+	Entries []MapEntryUsage `json:"entries"`
+	// Source resources used by the plan VMs but not mapped.
+	Unmapped []UnmappedSource `json:"unmapped"`
+}
+
+// Usage of a map entry.
+type MapEntryUsage struct {
+	// Index of the entry in the map (or in the rules).
+	Index int `json:"index"`
+	// The entry is a storage rule.
+	Rule bool `json:"rule,omitempty"`
+	// Source of the entry (pairs only).
+	Source *ref.Ref `json:"source,omitempty"`
+	// Plans with VMs using the entry.
+	Plans []PlanUsage `json:"plans"`
+}
+
+// VMs of a plan using a map entry.
+type PlanUsage struct {
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	VMs       []ref.Ref `json:"vms"`
+}
+
+// Source resource used by a plan VM but not mapped.
+type UnmappedSource struct {
+	Namespace string  `json:"namespace"`
+	Plan      string  `json:"plan"`
+	VM        ref.Ref `json:"vm"`
+	Source    ref.Ref `json:"source"`
+}
+
+// Source resource used by a VM.
+type vmSource struct {
+	ref        ref.Ref
+	attributes api.StorageAttributes
+}
+
+// Report which plans and VMs use the entries of the network or
+// storage map and the source resources the plans leave unmapped.
+func serveMapUsage(resp http.ResponseWriter, req *http.Request, client client.Client) {
+	q := req.URL.Query()
+	kind := q.Get("kind")
+	namespace := q.Get("namespace")
+	name := q.Get("name")
+	if kind != NetworkMapKind && kind != StorageMapKind || namespace == "" || name == "" {
+		http.Error(resp, "Required parameters are invalid: kind (network|storage), namespace, name", http.StatusBadRequest)
+		return
+	}
+	log.Info("received a request to report map usage", "kind", kind, "namespace", namespace, "name", name)
+	usage, err := mapUsage(client, kind, namespace, name)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			http.Error(resp, err.Error(), http.StatusNotFound)
+			return
+		}
+		log.Error(err, "failed to report map usage", "kind", kind, "namespace", namespace, "name", name)
+		http.Error(resp, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	encoded, err := json.Marshal(usage)
+	if err != nil {
+		http.Error(resp, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+	_, _ = resp.Write(encoded)
+}
+
+// Build the map usage.
+func mapUsage(cl client.Client, kind, namespace, name string) (usage *MapUsage, err error) {
+	key := client.ObjectKey{Namespace: namespace, Name: name}
+	networkMap := &api.NetworkMap{}
+	storageMap := &api.StorageMap{}
+	usage = &MapUsage{
+		Entries:  []MapEntryUsage{},
+		Unmapped: []UnmappedSource{},
+	}
+	if kind == NetworkMapKind {
+		err = cl.Get(context.TODO(), key, networkMap)
+		for i := range networkMap.Spec.Map {
+			usage.Entries = append(usage.Entries, MapEntryUsage{Index: i, Source: &networkMap.Spec.Map[i].Source, Plans: []PlanUsage{}})
+		}
+	} else {
+		err = cl.Get(context.TODO(), key, storageMap)
+		for i := range storageMap.Spec.Map {
+			usage.Entries = append(usage.Entries, MapEntryUsage{Index: i, Source: &storageMap.Spec.Map[i].Source, Plans: []PlanUsage{}})
+		}
+		for i := range storageMap.Spec.Rules {
+			usage.Entries = append(usage.Entries, MapEntryUsage{Index: i, Rule: true, Plans: []PlanUsage{}})
+		}
+	}
+	if err != nil {
+		return
+	}
+	plans := &api.PlanList{}
+	err = cl.List(context.TODO(), plans, client.InNamespace(namespace))
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for i := range plans.Items {
+		plan := &plans.Items[i]
+		mapRef := plan.Spec.Map.Network
+		if kind == StorageMapKind {
+			mapRef = plan.Spec.Map.Storage
+		}
+		if mapRef.Name != name || mapRef.Namespace != "" && mapRef.Namespace != namespace {
+			continue
+		}
+		provider := &api.Provider{}
+		err = cl.Get(
+			context.TODO(),
+			client.ObjectKey{
+				Namespace: plan.Spec.Provider.Source.Namespace,
+				Name:      plan.Spec.Provider.Source.Name,
+			},
+			provider)
+		if err != nil {
+			if k8serr.IsNotFound(err) {
+				err = nil
+				continue
+			}
+			err = liberr.Wrap(err)
+			return
+		}
+		var inventory web.Client
+		inventory, err = web.NewClient(provider)
+		if err != nil {
+			log.Info("Couldn't create the inventory client, plan skipped", "plan", plan.Name, "error", err.Error())
+			err = nil
+			continue
+		}
+		planUsage := map[int]*PlanUsage{}
+		for _, vm := range plan.Spec.VMs {
+			var sources []vmSource
+			sources, err = vmSources(inventory, provider.Type(), kind, vm.Ref)
+			if err != nil {
+				log.Info("Couldn't find the VM sources, VM skipped", "plan", plan.Name, "vm", vm.String(), "error", err.Error())
+				err = nil
+				continue
+			}
+			for _, source := range sources {
+				var index int
+				if kind == NetworkMapKind {
+					index = networkEntry(networkMap, vm.Ref, source)
+				} else {
+					index = storageEntry(storageMap, source)
+				}
+				if index == -1 {
+					usage.Unmapped = append(
+						usage.Unmapped,
+						UnmappedSource{
+							Namespace: plan.Namespace,
+							Plan:      plan.Name,
+							VM:        vm.Ref,
+							Source:    source.ref,
+						})
+					continue
+				}
+				used, found := planUsage[index]
+				if !found {
+					used = &PlanUsage{Namespace: plan.Namespace, Name: plan.Name}
+					planUsage[index] = used
+				}
+				if len(used.VMs) == 0 || used.VMs[len(used.VMs)-1] != vm.Ref {
+					used.VMs = append(used.VMs, vm.Ref)
+				}
+			}
+		}
+		for index, used := range planUsage {
+			usage.Entries[index].Plans = append(usage.Entries[index].Plans, *used)
+		}
+	}
+
+	return
+}
+
+// Index of the network map entry used by the VM for the source network.
+// Scoped entries take precedence, -1 when not mapped.
+func networkEntry(mp *api.NetworkMap, vmRef ref.Ref, source vmSource) (index int) {
+	index = -1
+	for i := range mp.Spec.Map {
+		pair := &mp.Spec.Map[i]
+		if !sourceMatched(pair.Source, source.ref) {
+			continue
+		}
+		if pair.Scope != nil && len(pair.Scope.VMs) > 0 {
+			scope := &api.NetworkPair{Scope: &api.NetworkPairScope{VMs: pair.Scope.VMs}}
+			if !scope.AppliesTo(vmRef, 0) {
+				continue
+			}
+		}
+		if index == -1 || pair.Specificity() > mp.Spec.Map[index].Specificity() {
+			index = i
+		}
+	}
+
+	return
+}
+
+// Index of the storage map entry used for the source storage. Rules
+// are indexed after the pairs, -1 when not mapped.
+func storageEntry(mp *api.StorageMap, source vmSource) (index int) {
+	for i := range mp.Spec.Map {
+		if sourceMatched(mp.Spec.Map[i].Source, source.ref) {
+			return i
+		}
+	}
+	index = -1
+	rule, found := mp.FindStorageRule(source.attributes)
+	if !found {
+		return
+	}
+	for i := range mp.Spec.Rules {
+		if &mp.Spec.Rules[i] == rule {
+			index = len(mp.Spec.Map) + i
+			break
+		}
+	}
+
+	return
+}
+
+// Whether the map entry source matches the source resource.
+func sourceMatched(entry, source ref.Ref) bool {
+	if entry.ID != "" {
+		return entry.ID == source.ID
+	}
+	return entry.Name != "" && entry.Name == source.Name
+}
+
+// List the networks or storage used by the VM.
+func vmSources(inventory web.Client, providerType api.ProviderType, kind string, vmRef ref.Ref) (sources []vmSource, err error) {
+	switch providerType {
+	case api.VSphere:
+		vm := &vsphere.VM{}
+		err = inventory.Find(vm, vmRef)
+		if err != nil {
+			return
+		}
+		if kind == NetworkMapKind {
+			for _, r := range vm.Networks {
+				network := &vsphere.Network{}
+				err = inventory.Find(network, ref.Ref{ID: r.ID})
+				if err != nil {
+					return
+				}
+				sources = append(sources, vmSource{ref: ref.Ref{ID: network.ID, Name: network.Name}})
+			}
+			return
+		}
+		for _, disk := range vm.Disks {
+			ds := &vsphere.Datastore{}
+			err = inventory.Find(ds, ref.Ref{ID: disk.Datastore.ID})
+			if err != nil {
+				return
+			}
+			sources = append(
+				sources,
+				vmSource{
+					ref:        ref.Ref{ID: ds.ID, Name: ds.Name},
+					attributes: api.StorageAttributes{Name: ds.Name, Tier: ds.Type, Capacity: ds.Capacity},
+				})
+		}
+	case api.OVirt:
+		vm := &ovirt.Workload{}
+		err = inventory.Find(vm, vmRef)
+		if err != nil {
+			return
+		}
+		if kind == NetworkMapKind {
+			for _, nic := range vm.NICs {
+				network := &ovirt.Network{}
+				err = inventory.Find(network, ref.Ref{ID: nic.Profile.Network})
+				if err != nil {
+					return
+				}
+				sources = append(sources, vmSource{ref: ref.Ref{ID: network.ID, Name: network.Name}})
+			}
+			return
+		}
+		for _, da := range vm.DiskAttachments {
+			if da.Disk.StorageType == "lun" {
+				continue
+			}
+			sd := &ovirt.StorageDomain{}
+			err = inventory.Find(sd, ref.Ref{ID: da.Disk.StorageDomain})
+			if err != nil {
+				return
+			}
+			sources = append(
+				sources,
+				vmSource{
+					ref:        ref.Ref{ID: sd.ID, Name: sd.Name},
+					attributes: api.StorageAttributes{Name: sd.Name, Tier: sd.Storage.Type, Capacity: sd.Capacity},
+				})
+		}
+	case api.OpenStack:
+		vm := &openstack.Workload{}
+		err = inventory.Find(vm, vmRef)
+		if err != nil {
+			return
+		}
+		if kind == NetworkMapKind {
+			for _, network := range vm.Networks {
+				sources = append(sources, vmSource{ref: ref.Ref{ID: network.ID, Name: network.Name}})
+			}
+			return
+		}
+		for _, vt := range vm.VolumeTypes {
+			sources = append(
+				sources,
+				vmSource{
+					ref:        ref.Ref{ID: vt.ID, Name: vt.Name},
+					attributes: api.StorageAttributes{Name: vt.Name, Tier: vt.Name},
+				})
+		}
+		if vm.ImageID != "" {
+			sources = append(
+				sources,
+				vmSource{
+					ref:        ref.Ref{Name: api.GlanceSource},
+					attributes: api.StorageAttributes{Name: api.GlanceSource},
+				})
+		}
+	case api.Ova:
+		vm := &ova.VM{}
+		err = inventory.Find(vm, vmRef)
+		if err != nil {
+			return
+		}
+		if kind == NetworkMapKind {
+			for _, network := range vm.Networks {
+				sources = append(sources, vmSource{ref: ref.Ref{ID: network.ID, Name: network.Name}})
+			}
+			return
+		}
+		for _, disk := range vm.Disks {
+			sources = append(
+				sources,
+				vmSource{
+					ref:        ref.Ref{ID: disk.ID, Name: disk.Name},
+					attributes: api.StorageAttributes{Name: disk.Name},
+				})
+		}
+	case api.Nutanix:
+		vm := &nutanix.Workload{}
+		err = inventory.Find(vm, vmRef)
+		if err != nil {
+			return
+		}
+		if kind == NetworkMapKind {
+			for _, subnet := range vm.Subnets {
+				sources = append(sources, vmSource{ref: ref.Ref{ID: subnet.ID, Name: subnet.Name}})
+			}
+			return
+		}
+		for _, container := range vm.StorageContainers {
+			sources = append(
+				sources,
+				vmSource{
+					ref:        ref.Ref{ID: container.ID, Name: container.Name},
+					attributes: api.StorageAttributes{Name: container.Name, Capacity: container.Capacity},
+				})
+		}
+	case api.EC2:
+		vm := &ec2.Workload{}
+		err = inventory.Find(vm, vmRef)
+		if err != nil {
+			return
+		}
+		if kind == NetworkMapKind {
+			for _, subnet := range vm.Subnets {
+				sources = append(sources, vmSource{ref: ref.Ref{ID: subnet.ID, Name: subnet.Name}})
+			}
+			return
+		}
+		for _, vt := range vm.VolumeTypes {
+			sources = append(
+				sources,
+				vmSource{
+					ref:        ref.Ref{ID: vt.ID, Name: vt.Name},
+					attributes: api.StorageAttributes{Name: vt.Name, Tier: vt.Name},
+				})
+		}
+	case api.Azure:
+		vm := &azure.Workload{}
+		err = inventory.Find(vm, vmRef)
+		if err != nil {
+			return
+		}
+		if kind == NetworkMapKind {
+			for _, subnet := range vm.Subnets {
+				sources = append(sources, vmSource{ref: ref.Ref{ID: subnet.ID, Name: subnet.Name}})
+			}
+			return
+		}
+		for _, sku := range vm.DiskSkus {
+			sources = append(
+				sources,
+				vmSource{
+					ref:        ref.Ref{ID: sku.ID, Name: sku.Name},
+					attributes: api.StorageAttributes{Name: sku.Name, Tier: sku.Name},
+				})
+		}
+	case api.Libvirt:
+		vm := &libvirt.Workload{}
+		err = inventory.Find(vm, vmRef)
+		if err != nil {
+			return
+		}
+		if kind == NetworkMapKind {
+			for _, network := range vm.Networks {
+				sources = append(sources, vmSource{ref: ref.Ref{ID: network.ID, Name: network.Name}})
+			}
+			return
+		}
+		for _, pool := range vm.Pools {
+			sources = append(
+				sources,
+				vmSource{
+					ref:        ref.Ref{ID: pool.ID, Name: pool.Name},
+					attributes: api.StorageAttributes{Name: pool.Name, Capacity: pool.Capacity},
+				})
+		}
+	case api.Image:
+		vm := &image.Workload{}
+		err = inventory.Find(vm, vmRef)
+		if err != nil {
+			return
+		}
+		if kind == NetworkMapKind {
+			for _, network := range vm.Networks {
+				sources = append(sources, vmSource{ref: ref.Ref{ID: network.ID, Name: network.Name}})
+			}
+			return
+		}
+		for _, storage := range vm.Storages {
+			sources = append(
+				sources,
+				vmSource{
+					ref:        ref.Ref{ID: storage.ID, Name: storage.Name},
+					attributes: api.StorageAttributes{Name: storage.Name},
+				})
+		}
+	case api.Catalog:
+		vm := &catalog.Workload{}
+		err = inventory.Find(vm, vmRef)
+		if err != nil {
+			return
+		}
+		if kind == NetworkMapKind {
+			for _, network := range vm.Networks {
+				sources = append(sources, vmSource{ref: ref.Ref{ID: network.ID, Name: network.Name}})
+			}
+			return
+		}
+		for _, storage := range vm.Storages {
+			sources = append(
+				sources,
+				vmSource{
+					ref:        ref.Ref{ID: storage.ID, Name: storage.Name},
+					attributes: api.StorageAttributes{Name: storage.Name},
+				})
+		}
+	}
+
+	return
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"testing"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+)
+
+func TestNetworkEntry(t *testing.T) {
+	mp := &api.NetworkMap{
+		Spec: api.NetworkMapSpec{
+			Map: []api.NetworkPair{
+				{Source: ref.Ref{ID: "net-1"}},
+				{Source: ref.Ref{Name: "VM Network"}},
+				{
+					Source: ref.Ref{ID: "net-1"},
+					Scope:  &api.NetworkPairScope{VMs: []ref.Ref{{ID: "vm-2"}}},
+				},
+			},
+		},
+	}
+	cases := []struct {
+		vm     string
+		source ref.Ref
+		index  int
+	}{
+		{"vm-1", ref.Ref{ID: "net-1", Name: "Other"}, 0},
+		{"vm-2", ref.Ref{ID: "net-1", Name: "Other"}, 2},
+		{"vm-1", ref.Ref{ID: "net-2", Name: "VM Network"}, 1},
+		{"vm-1", ref.Ref{ID: "net-3", Name: "Management"}, -1},
+	}
+	for _, c := range cases {
+		index := networkEntry(mp, ref.Ref{ID: c.vm}, vmSource{ref: c.source})
+		if index != c.index {
+			t.Errorf("vm %s source %s: expected entry %d, got %d", c.vm, c.source.String(), c.index, index)
+		}
+	}
+}
+
+func TestStorageEntry(t *testing.T) {
+	mp := &api.StorageMap{
+		Spec: api.StorageMapSpec{
+			Map: []api.StoragePair{
+				{Source: ref.Ref{ID: "ds-1"}},
+			},
+			Rules: []api.StorageRule{
+				{Match: api.StorageMatch{Tier: "NFS"}},
+				{Priority: 1, Match: api.StorageMatch{Name: "^fast-"}},
+			},
+		},
+	}
+	cases := []struct {
+		source vmSource
+		index  int
+	}{
+		{vmSource{ref: ref.Ref{ID: "ds-1"}}, 0},
+		{vmSource{ref: ref.Ref{ID: "ds-2"}, attributes: api.StorageAttributes{Name: "slow", Tier: "nfs"}}, 1},
+		{vmSource{ref: ref.Ref{ID: "ds-3"}, attributes: api.StorageAttributes{Name: "fast-1", Tier: "nfs"}}, 2},
+		{vmSource{ref: ref.Ref{ID: "ds-4"}, attributes: api.StorageAttributes{Name: "slow", Tier: "vmfs"}}, -1},
+	}
+	for _, c := range cases {
+		index := storageEntry(mp, c.source)
+		if index != c.index {
+			t.Errorf("source %s: expected entry %d, got %d", c.source.ref.String(), c.index, index)
+		}
+	}
+}
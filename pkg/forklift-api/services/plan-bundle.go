@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/kubev2v/forklift/pkg/lib/bundle"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	auth "k8s.io/api/authentication/v1"
+	auth2 "k8s.io/api/authorization/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Env var holding the plan bundle signing key.
+const PlanBundleSigningKey = "PLAN_BUNDLE_SIGNING_KEY"
+
+// Max size of an imported bundle.
+const maxBundleSize = 10 << 20
+
+// Export (GET) a plan as a signed YAML bundle or import (POST) a bundle.
+// The bearer token must be permitted to get (export) or to create
+// (import) the plan and each of the resources in the bundle.
+func servePlanBundle(resp http.ResponseWriter, req *http.Request, client client.Client) {
+	key := []byte(os.Getenv(PlanBundleSigningKey))
+	if len(key) == 0 {
+		http.Error(resp, "Plan bundles are not enabled: signing key not configured.", http.StatusServiceUnavailable)
+		return
+	}
+	switch req.Method {
+	case http.MethodGet:
+		exportPlanBundle(resp, req, client, key)
+	case http.MethodPost:
+		importPlanBundle(resp, req, client, key)
+	default:
+		http.Error(resp, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+// Export the plan.
+func exportPlanBundle(resp http.ResponseWriter, req *http.Request, client client.Client, key []byte) {
+	q := req.URL.Query()
+	namespace := q.Get("namespace")
+	name := q.Get("name")
+	if namespace == "" || name == "" {
+		http.Error(resp, "Required parameters are invalid: namespace, name", http.StatusBadRequest)
+		return
+	}
+	authorizer, status, err := authenticate(req, client)
+	if status != http.StatusOK {
+		if err != nil {
+			log.Error(err, "plan bundle authentication failed")
+		}
+		http.Error(resp, http.StatusText(status), status)
+		return
+	}
+	log.Info("received a request to export plan", "namespace", namespace, "name", name)
+	exported, err := bundle.Export(client, namespace, name, authorizer)
+	if err != nil {
+		switch {
+		case k8serr.IsForbidden(err):
+			http.Error(resp, err.Error(), http.StatusForbidden)
+		case k8serr.IsNotFound(err):
+			http.Error(resp, err.Error(), http.StatusNotFound)
+		default:
+			log.Error(err, "failed to export plan", "namespace", namespace, "name", name)
+			http.Error(resp, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	err = exported.Sign(key)
+	if err != nil {
+		http.Error(resp, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	encoded, err := exported.Encode()
+	if err != nil {
+		http.Error(resp, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	resp.Header().Set("Content-Type", "application/yaml")
+	resp.WriteHeader(http.StatusOK)
+	_, _ = resp.Write(encoded)
+}
+
+// Import the bundle.
+func importPlanBundle(resp http.ResponseWriter, req *http.Request, client client.Client, key []byte) {
+	q := req.URL.Query()
+	options := bundle.Options{
+		Namespace:           q.Get("namespace"),
+		Name:                q.Get("name"),
+		TargetNamespace:     q.Get("targetNamespace"),
+		SourceProvider:      q.Get("sourceProvider"),
+		DestinationProvider: q.Get("destinationProvider"),
+	}
+	authorizer, status, err := authenticate(req, client)
+	if status != http.StatusOK {
+		if err != nil {
+			log.Error(err, "plan bundle authentication failed")
+		}
+		http.Error(resp, http.StatusText(status), status)
+		return
+	}
+	options.Authorizer = authorizer
+	content, err := io.ReadAll(io.LimitReader(req.Body, maxBundleSize))
+	if err != nil {
+		http.Error(resp, err.Error(), http.StatusBadRequest)
+		return
+	}
+	imported, err := bundle.Decode(content)
+	if err != nil {
+		http.Error(resp, err.Error(), http.StatusBadRequest)
+		return
+	}
+	err = imported.Verify(key)
+	if err != nil {
+		http.Error(resp, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if options.Namespace == "" {
+		options.Namespace = imported.Namespace
+	}
+	log.Info("received a request to import plan", "namespace", options.Namespace, "name", imported.Plan.Name)
+	result, err := bundle.Import(client, imported, options)
+	if err != nil {
+		switch {
+		case k8serr.IsForbidden(err):
+			http.Error(resp, err.Error(), http.StatusForbidden)
+		case k8serr.IsAlreadyExists(err):
+			http.Error(resp, err.Error(), http.StatusConflict)
+		default:
+			log.Error(err, "failed to import plan", "namespace", options.Namespace)
+			http.Error(resp, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		http.Error(resp, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusCreated)
+	_, _ = resp.Write(encoded)
+}
+
+// Authenticate the bearer token.
+// Returns an authorizer of the authenticated user.
+func authenticate(req *http.Request, client client.Client) (authorizer *userAuthorizer, status int, err error) {
+	token := strings.TrimSpace(strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer"))
+	if token == "" {
+		status = http.StatusUnauthorized
+		return
+	}
+	tr := &auth.TokenReview{
+		Spec: auth.TokenReviewSpec{
+			Token: token,
+		},
+	}
+	err = client.Create(context.TODO(), tr)
+	if err != nil {
+		err = liberr.Wrap(err)
+		status = http.StatusInternalServerError
+		return
+	}
+	if !tr.Status.Authenticated {
+		status = http.StatusUnauthorized
+		return
+	}
+	authorizer = &userAuthorizer{
+		client: client,
+		user:   tr.Status.User,
+	}
+	status = http.StatusOK
+	return
+}
+
+// Authorizes the resources of a bundle on behalf
+// of the authenticated user.
+type userAuthorizer struct {
+	client client.Client
+	user   auth.UserInfo
+}
+
+// Authorize the verb on the resource.
+func (r *userAuthorizer) Authorize(verb string, resource schema.GroupResource, namespace, name string) (err error) {
+	extra := map[string]auth2.ExtraValue{}
+	for k, v := range r.user.Extra {
+		extra[k] = append(auth2.ExtraValue{}, v...)
+	}
+	review := &auth2.SubjectAccessReview{
+		Spec: auth2.SubjectAccessReviewSpec{
+			ResourceAttributes: &auth2.ResourceAttributes{
+				Group:     resource.Group,
+				Resource:  resource.Resource,
+				Namespace: namespace,
+				Name:      name,
+				Verb:      verb,
+			},
+			Extra:  extra,
+			Groups: r.user.Groups,
+			User:   r.user.Username,
+			UID:    r.user.UID,
+		},
+	}
+	err = r.client.Create(context.TODO(), review)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if !review.Status.Allowed {
+		err = k8serr.NewForbidden(
+			resource,
+			name,
+			fmt.Errorf("%s %s not allowed in namespace %s", verb, resource.String(), namespace))
+	}
+	return
+}
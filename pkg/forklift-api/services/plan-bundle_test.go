@@ -0,0 +1,30 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPlanBundleRequests(t *testing.T) {
+	cases := []struct {
+		key    string
+		method string
+		url    string
+		status int
+	}{
+		{"", http.MethodGet, "/plan-bundle?namespace=dev&name=plan", http.StatusServiceUnavailable},
+		{"key", http.MethodDelete, "/plan-bundle", http.StatusMethodNotAllowed},
+		{"key", http.MethodGet, "/plan-bundle?namespace=dev", http.StatusBadRequest},
+		{"key", http.MethodGet, "/plan-bundle?namespace=dev&name=plan", http.StatusUnauthorized},
+		{"key", http.MethodPost, "/plan-bundle?namespace=dev", http.StatusUnauthorized},
+	}
+	for _, c := range cases {
+		t.Setenv(PlanBundleSigningKey, c.key)
+		resp := httptest.NewRecorder()
+		servePlanBundle(resp, httptest.NewRequest(c.method, c.url, nil), nil)
+		if resp.Code != c.status {
+			t.Errorf("%s %s: expected status %d, got %d", c.method, c.url, c.status, resp.Code)
+		}
+	}
+}
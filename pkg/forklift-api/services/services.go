@@ -8,7 +8,11 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-const TLS_CERTIFICATE_PATH = "/tls-certificate"
+const (
+	TLS_CERTIFICATE_PATH = "/tls-certificate"
+	MAP_USAGE_PATH       = "/map-usage"
+	PLAN_BUNDLE_PATH     = "/plan-bundle"
+)
 
 var log = logging.WithName("services")
 
@@ -17,4 +21,12 @@ func RegisterServices(mux *http.ServeMux, client client.Client) {
 	mux.HandleFunc(TLS_CERTIFICATE_PATH, func(w http.ResponseWriter, r *http.Request) {
 		serveTlsCertificate(w, r, client)
 	})
+	log.Info("register map usage service")
+	mux.HandleFunc(MAP_USAGE_PATH, func(w http.ResponseWriter, r *http.Request) {
+		serveMapUsage(w, r, client)
+	})
+	log.Info("register plan bundle service")
+	mux.HandleFunc(PLAN_BUNDLE_PATH, func(w http.ResponseWriter, r *http.Request) {
+		servePlanBundle(w, r, client)
+	})
 }
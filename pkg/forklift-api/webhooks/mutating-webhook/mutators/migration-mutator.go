@@ -0,0 +1,135 @@
+package mutators
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/forklift-api/webhooks/util"
+	admissionv1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// Comma-separated list of approval gates to be approved.
+	AnnApproveGates = "forklift.konveyor.io/approve-gates"
+)
+
+type MigrationMutator struct {
+	ar        *admissionv1.AdmissionReview
+	migration api.Migration
+	old       api.Migration
+	Client    client.Client
+}
+
+func (mutator *MigrationMutator) Mutate(ar *admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
+	log.Info("migration mutator was called")
+	mutator.ar = ar
+	err := json.Unmarshal(ar.Request.Object.Raw, &mutator.migration)
+	if err != nil {
+		log.Error(err, "mutating webhook error, failed to unmarshel migration")
+		return util.ToAdmissionResponseError(err)
+	}
+	if len(ar.Request.OldObject.Raw) > 0 {
+		err = json.Unmarshal(ar.Request.OldObject.Raw, &mutator.old)
+		if err != nil {
+			log.Error(err, "mutating webhook error, failed to unmarshel old migration")
+			return util.ToAdmissionResponseError(err)
+		}
+	}
+
+	metadataChanged := mutator.approveAnnotatedGates()
+	specChanged := metadataChanged
+	if mutator.auditGateApprovals(ar.Request.UserInfo.Username, metav1.Now()) {
+		specChanged = true
+	}
+	if specChanged || metadataChanged {
+		patches := mutator.patchPayload(specChanged, metadataChanged)
+		patchBytes, err := util.GeneratePatchPayload(patches...)
+		if err != nil {
+			log.Error(err, "mutating webhook error, failed to generate payload for patch request")
+			return util.ToAdmissionResponseError(err)
+		}
+
+		jsonPatchType := admissionv1.PatchTypeJSONPatch
+		return &admissionv1.AdmissionResponse{
+			Allowed:   true,
+			Patch:     patchBytes,
+			PatchType: &jsonPatchType,
+		}
+	} else {
+		return &admissionv1.AdmissionResponse{
+			Allowed: true,
+			Result: &metav1.Status{
+				Message: "No approval to be audited, passing",
+				Code:    http.StatusOK,
+			},
+		}
+	}
+}
+
+// Move the gates listed in the annotation to the gate approvals.
+func (mutator *MigrationMutator) approveAnnotatedGates() bool {
+	value, found := mutator.migration.Annotations[AnnApproveGates]
+	if !found {
+		return false
+	}
+	spec := &mutator.migration.Spec
+	for _, gate := range strings.Split(value, ",") {
+		gate = strings.TrimSpace(gate)
+		if gate == "" {
+			continue
+		}
+		if _, found := spec.GateApproval(gate); !found {
+			spec.GateApprovals = append(spec.GateApprovals, planapi.GateApproval{Gate: gate})
+		}
+	}
+	delete(mutator.migration.Annotations, AnnApproveGates)
+	log.Info("Patching the migration's annotated gate approvals")
+	return true
+}
+
+// Record the user and time of the gate approvals.
+// Gates approved previously keep their (original) audit and
+// gates approved by this request are attributed to the requesting user.
+func (mutator *MigrationMutator) auditGateApprovals(user string, now metav1.Time) (changed bool) {
+	for i := range mutator.migration.Spec.GateApprovals {
+		approval := &mutator.migration.Spec.GateApprovals[i]
+		approvedBy, approved := user, &now
+		if previous, found := mutator.old.Spec.GateApproval(approval.Gate); found && previous.Approved != nil {
+			approvedBy, approved = previous.ApprovedBy, previous.Approved
+		}
+		if approval.ApprovedBy == approvedBy && approval.Approved != nil && approval.Approved.Equal(approved) {
+			continue
+		}
+		approval.ApprovedBy = approvedBy
+		approval.Approved = approved
+		changed = true
+	}
+	if changed {
+		log.Info("Patching the migration's gate approvals")
+	}
+	return
+}
+
+func (mutator *MigrationMutator) patchPayload(specChanged, metadataChanged bool) []util.PatchOperation {
+	var patches []util.PatchOperation
+	if specChanged {
+		patches = append(patches, util.PatchOperation{
+			Op:    "replace",
+			Path:  "/spec",
+			Value: mutator.migration.Spec,
+		})
+	}
+	if metadataChanged {
+		patches = append(patches, util.PatchOperation{
+			Op:    "replace",
+			Path:  "/metadata",
+			Value: mutator.migration.ObjectMeta,
+		})
+	}
+	return patches
+}
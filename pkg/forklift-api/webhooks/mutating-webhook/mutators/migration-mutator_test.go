@@ -0,0 +1,50 @@
+package mutators
+
+import (
+	"testing"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	. "github.com/onsi/gomega"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMigrationGateApprovals(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	now := meta.Now()
+	earlier := meta.NewTime(now.Add(-time.Hour))
+	mutator := MigrationMutator{
+		migration: api.Migration{
+			ObjectMeta: meta.ObjectMeta{
+				Annotations: map[string]string{AnnApproveGates: "PowerOff, Cutover"},
+			},
+			Spec: api.MigrationSpec{
+				GateApprovals: []planapi.GateApproval{
+					{Gate: planapi.GateCutover, ApprovedBy: "mallory", Approved: &now},
+				},
+			},
+		},
+		old: api.Migration{
+			Spec: api.MigrationSpec{
+				GateApprovals: []planapi.GateApproval{
+					{Gate: planapi.GateCutover, ApprovedBy: "alice", Approved: &earlier},
+				},
+			},
+		},
+	}
+	g.Expect(mutator.approveAnnotatedGates()).To(BeTrue())
+	g.Expect(mutator.migration.Annotations).ToNot(HaveKey(AnnApproveGates))
+	g.Expect(mutator.migration.Spec.GateApprovals).To(HaveLen(2))
+	g.Expect(mutator.approveAnnotatedGates()).To(BeFalse())
+
+	g.Expect(mutator.auditGateApprovals("bob", now)).To(BeTrue())
+	cutover, _ := mutator.migration.Spec.GateApproval(planapi.GateCutover)
+	g.Expect(cutover.ApprovedBy).To(Equal("alice"))
+	g.Expect(cutover.Approved).To(Equal(&earlier))
+	powerOff, found := mutator.migration.Spec.GateApproval(planapi.GatePowerOff)
+	g.Expect(found).To(BeTrue())
+	g.Expect(powerOff.ApprovedBy).To(Equal("bob"))
+	g.Expect(mutator.auditGateApprovals("bob", now)).To(BeFalse())
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"strconv"
 
 	net "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
 	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web"
 	"github.com/kubev2v/forklift/pkg/forklift-api/webhooks/util"
 	ocp "github.com/kubev2v/forklift/pkg/lib/client/openshift"
 	ocpclient "github.com/kubev2v/forklift/pkg/lib/client/openshift"
@@ -20,6 +22,8 @@ import (
 
 const (
 	AnnPopulatorLabels = "populatorLabels"
+	// The inventory revision the plan VM references were resolved at.
+	AnnInventoryRevision = "forklift.konveyor.io/inventory-revision"
 )
 
 type PlanMutator struct {
@@ -38,11 +42,17 @@ func (mutator *PlanMutator) Mutate(ar *admissionv1.AdmissionReview) *admissionv1
 		return util.ToAdmissionResponseError(err)
 	}
 
-	specChanged, err := mutator.setTransferNetworkIfNotSet()
+	namespaceChanged := mutator.setTargetNamespaceIfNotSet()
+	networkChanged, err := mutator.setTransferNetworkIfNotSet()
 	if err != nil {
 		return util.ToAdmissionResponseError(err)
 	}
+	vmsChanged, revision := mutator.resolveVMRefs()
+	specChanged := namespaceChanged || networkChanged || vmsChanged
 	metadataChanged := mutator.addPopulatorLabelsAnnotation()
+	if revision > 0 && mutator.setInventoryRevisionAnnotation(revision) {
+		metadataChanged = true
+	}
 	if specChanged || metadataChanged {
 		patches := mutator.patchPayload(specChanged, metadataChanged)
 		patchBytes, err := util.GeneratePatchPayload(patches...)
@@ -68,6 +78,102 @@ func (mutator *PlanMutator) Mutate(ar *admissionv1.AdmissionReview) *admissionv1
 	}
 }
 
+func (mutator *PlanMutator) setTargetNamespaceIfNotSet() bool {
+	if mutator.plan.Spec.TargetNamespace == "" && mutator.plan.Namespace != "" {
+		log.Info("Patching the plan's target namespace")
+		mutator.plan.Spec.TargetNamespace = mutator.plan.Namespace
+		return true
+	}
+	return false
+}
+
+// Resolve the IDs of the VMs referenced only by name using the
+// source provider inventory. Returns whether any reference was
+// resolved and the highest inventory revision of the resolved VMs.
+// Failures are not fatal, the plan controller validates the references.
+func (mutator *PlanMutator) resolveVMRefs() (changed bool, revision int64) {
+	if mutator.plan.Spec.Archived {
+		return
+	}
+	unresolved := false
+	for _, vm := range mutator.plan.Spec.VMs {
+		if vm.ID == "" && vm.Name != "" {
+			unresolved = true
+			break
+		}
+	}
+	if !unresolved {
+		return
+	}
+	sourceProvider := api.Provider{}
+	err := mutator.Client.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: mutator.plan.Spec.Provider.Source.Namespace,
+			Name:      mutator.plan.Spec.Provider.Source.Name,
+		},
+		&sourceProvider)
+	if err != nil {
+		log.Info("Couldn't get the source provider, VM references not resolved", "error", err.Error())
+		return
+	}
+	inventory, err := web.NewClient(&sourceProvider)
+	if err != nil {
+		log.Info("Couldn't create the inventory client, VM references not resolved", "error", err.Error())
+		return
+	}
+	for i := range mutator.plan.Spec.VMs {
+		vmRef := &mutator.plan.Spec.VMs[i].Ref
+		if vmRef.ID != "" || vmRef.Name == "" {
+			continue
+		}
+		resolved := *vmRef
+		object, fErr := inventory.VM(&resolved)
+		if fErr != nil {
+			log.Info("Couldn't resolve the VM reference", "vm", vmRef.String(), "error", fErr.Error())
+			continue
+		}
+		vmRef.ID = resolved.ID
+		changed = true
+		if r := inventoryRevision(object); r > revision {
+			revision = r
+		}
+	}
+	if changed {
+		log.Info("Patching the plan's VM references")
+	}
+	return
+}
+
+// Inventory revision of a resource returned by the inventory client.
+func inventoryRevision(object interface{}) int64 {
+	resource := struct {
+		Revision int64 `json:"revision"`
+	}{}
+	b, err := json.Marshal(object)
+	if err != nil {
+		return 0
+	}
+	err = json.Unmarshal(b, &resource)
+	if err != nil {
+		return 0
+	}
+	return resource.Revision
+}
+
+func (mutator *PlanMutator) setInventoryRevisionAnnotation(revision int64) bool {
+	value := strconv.FormatInt(revision, 10)
+	if mutator.plan.Annotations[AnnInventoryRevision] == value {
+		return false
+	}
+	if mutator.plan.Annotations == nil {
+		mutator.plan.Annotations = make(map[string]string)
+	}
+	mutator.plan.Annotations[AnnInventoryRevision] = value
+	log.Info("Patching the plan's inventory revision annotation")
+	return true
+}
+
 func (mutator *PlanMutator) setTransferNetworkIfNotSet() (bool, error) {
 	var planChanged bool
 
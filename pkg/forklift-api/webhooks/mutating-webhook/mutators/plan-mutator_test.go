@@ -0,0 +1,34 @@
+package mutators
+
+import (
+	"testing"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/web/vsphere"
+	. "github.com/onsi/gomega"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPlanDefaults(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	mutator := PlanMutator{
+		plan: api.Plan{ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "plan"}},
+	}
+	g.Expect(mutator.setTargetNamespaceIfNotSet()).To(BeTrue())
+	g.Expect(mutator.plan.Spec.TargetNamespace).To(Equal("test"))
+	g.Expect(mutator.setTargetNamespaceIfNotSet()).To(BeFalse())
+
+	g.Expect(mutator.setInventoryRevisionAnnotation(12)).To(BeTrue())
+	g.Expect(mutator.plan.Annotations[AnnInventoryRevision]).To(Equal("12"))
+	g.Expect(mutator.setInventoryRevisionAnnotation(12)).To(BeFalse())
+}
+
+func TestInventoryRevision(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	vm := &model.VM{}
+	vm.Revision = 7
+	g.Expect(inventoryRevision(vm)).To(Equal(int64(7)))
+	g.Expect(inventoryRevision(nil)).To(Equal(int64(0)))
+}
@@ -2,6 +2,8 @@ package admitters
 
 import (
 	"context"
+	"reflect"
+	"strings"
 
 	v1 "k8s.io/api/storage/v1"
 	cnv "kubevirt.io/api/core/v1"
@@ -113,6 +115,62 @@ func (admitter *PlanAdmitter) validateLUKS() error {
 	return nil
 }
 
+// Reject changes to critical fields of a plan
+// that is being executed by a running migration.
+func (admitter *PlanAdmitter) validateRunningMigration(old *api.Plan) error {
+	changes := criticalChanges(old, &admitter.plan)
+	if len(changes) == 0 {
+		return nil
+	}
+	migrations := api.MigrationList{}
+	err := admitter.Client.List(
+		context.TODO(),
+		&migrations,
+		&client.ListOptions{Namespace: admitter.plan.Namespace})
+	if err != nil {
+		log.Error(err, "Couldn't list the migrations")
+		return err
+	}
+	for i := range migrations.Items {
+		migration := &migrations.Items[i]
+		if !migration.Match(&admitter.plan) || !migration.Status.Running() {
+			continue
+		}
+		err = liberr.New(
+			fmt.Sprintf(
+				"plan %s cannot be changed while migration %s is running: %s",
+				admitter.plan.Name,
+				migration.Name,
+				strings.Join(changes, ", ")))
+		log.Error(err, "Plan change rejected", "plan", admitter.plan.Name, "migration", migration.Name)
+		return err
+	}
+	return nil
+}
+
+// Critical fields changed between the old and the new plan.
+func criticalChanges(old, new *api.Plan) (changes []string) {
+	if !reflect.DeepEqual(old.Spec.Provider, new.Spec.Provider) {
+		changes = append(changes, "spec.provider")
+	}
+	if !reflect.DeepEqual(old.Spec.Map, new.Spec.Map) {
+		changes = append(changes, "spec.map")
+	}
+	for _, vm := range old.Spec.VMs {
+		found := false
+		for _, newVM := range new.Spec.VMs {
+			if vm.ID != "" && vm.ID == newVM.ID || vm.ID == "" && vm.Name == newVM.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			changes = append(changes, fmt.Sprintf("removed vm (%s)", strings.TrimSpace(vm.String())))
+		}
+	}
+	return
+}
+
 func (admitter *PlanAdmitter) Admit(ar *admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
 	log.Info("Plan admitter was called")
 	raw := ar.Request.Object.Raw
@@ -122,6 +180,18 @@ func (admitter *PlanAdmitter) Admit(ar *admissionv1.AdmissionReview) *admissionv
 		return util.ToAdmissionResponseError(err)
 	}
 
+	if ar.Request.Operation == admissionv1.Update && len(ar.Request.OldObject.Raw) > 0 {
+		old := api.Plan{}
+		err = json.Unmarshal(ar.Request.OldObject.Raw, &old)
+		if err != nil {
+			return util.ToAdmissionResponseError(err)
+		}
+		err = admitter.validateRunningMigration(&old)
+		if err != nil {
+			return util.ToAdmissionResponseError(err)
+		}
+	}
+
 	err = admitter.Client.Get(
 		context.TODO(),
 		client.ObjectKey{
@@ -0,0 +1,69 @@
+package admitters
+
+import (
+	"testing"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	. "github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCriticalChanges(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	old := &api.Plan{}
+	old.Spec.Map.Network = core.ObjectReference{Namespace: "test", Name: "network"}
+	old.Spec.VMs = []plan.VM{
+		{Ref: ref.Ref{ID: "vm-1"}},
+		{Ref: ref.Ref{Name: "vm-2"}},
+	}
+	changed := old.DeepCopy()
+	g.Expect(criticalChanges(old, changed)).To(BeEmpty())
+
+	changed.Spec.Warm = true
+	changed.Spec.VMs = append(changed.Spec.VMs, plan.VM{Ref: ref.Ref{ID: "vm-3"}})
+	g.Expect(criticalChanges(old, changed)).To(BeEmpty())
+
+	changed.Spec.Map.Network.Name = "other"
+	changed.Spec.VMs = changed.Spec.VMs[1:]
+	g.Expect(criticalChanges(old, changed)).To(ConsistOf("spec.map", "removed vm (id:vm-1 name:'')"))
+}
+
+func TestValidateRunningMigration(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(api.SchemeBuilder.AddToScheme(scheme)).To(Succeed())
+
+	old := &api.Plan{ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "plan"}}
+	old.Spec.Map.Storage = core.ObjectReference{Namespace: "test", Name: "storage"}
+	changed := old.DeepCopy()
+	changed.Spec.Map.Storage.Name = "other"
+
+	migration := &api.Migration{ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "migration"}}
+	migration.Spec.Plan = core.ObjectReference{Namespace: "test", Name: "plan"}
+
+	// Not started.
+	admitter := PlanAdmitter{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(migration.DeepCopy()).Build(),
+		plan:   *changed,
+	}
+	g.Expect(admitter.validateRunningMigration(old)).To(Succeed())
+
+	// Running.
+	migration.Status.MarkStarted()
+	admitter.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(migration.DeepCopy()).Build()
+	err := admitter.validateRunningMigration(old)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("migration is running"))
+
+	// Completed.
+	migration.Status.MarkCompleted()
+	admitter.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(migration.DeepCopy()).Build()
+	g.Expect(admitter.validateRunningMigration(old)).To(Succeed())
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/kubev2v/forklift/pkg/forklift-api/webhooks/util"
 	liberr "github.com/kubev2v/forklift/pkg/lib/error"
 	admissionv1 "k8s.io/api/admission/v1beta1"
+	core "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -23,6 +24,28 @@ func (admitter *ProviderAdmitter) validateSdkEndpointType() error {
 	return nil
 }
 
+func (admitter *ProviderAdmitter) validateIPFamily() error {
+	family, ok := admitter.provider.Spec.Settings[api.IPFamily]
+	if ok && family != string(core.IPv4Protocol) && family != string(core.IPv6Protocol) {
+		return liberr.New("Provider is set with an invalid IP family (IPv4|IPv6)", "ipFamily", family)
+	}
+	return nil
+}
+
+func (admitter *ProviderAdmitter) validateAPIRateLimit() error {
+	settings := admitter.provider.Spec.Settings
+	_, qps := settings[api.APIQPS]
+	_, burst := settings[api.APIBurst]
+	if (qps || burst) && admitter.provider.Type() != api.VSphere {
+		return liberr.New("The API rate limit is only supported by vSphere providers")
+	}
+	_, _, err := admitter.provider.APIRateLimit()
+	if err != nil {
+		return liberr.New("Provider is set with an invalid API rate limit", "reason", err.Error())
+	}
+	return nil
+}
+
 func (admitter *ProviderAdmitter) Admit(ar *admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
 	log.Info("Provider admitter was called")
 	raw := ar.Request.Object.Raw
@@ -35,5 +58,13 @@ func (admitter *ProviderAdmitter) Admit(ar *admissionv1.AdmissionReview) *admiss
 		return util.ToAdmissionResponseError(err)
 	}
 
+	if err := admitter.validateIPFamily(); err != nil {
+		return util.ToAdmissionResponseError(err)
+	}
+
+	if err := admitter.validateAPIRateLimit(); err != nil {
+		return util.ToAdmissionResponseError(err)
+	}
+
 	return util.ToAdmissionResponseAllow()
 }
@@ -17,6 +17,7 @@ import (
 	webhookutils "github.com/kubev2v/forklift/pkg/forklift-api/webhooks/util"
 	libcontainer "github.com/kubev2v/forklift/pkg/lib/inventory/container"
 	"github.com/kubev2v/forklift/pkg/lib/logging"
+	libutil "github.com/kubev2v/forklift/pkg/lib/util"
 	"github.com/kubev2v/forklift/pkg/settings"
 	admissionv1 "k8s.io/api/admission/v1beta1"
 	core "k8s.io/api/core/v1"
@@ -223,7 +224,7 @@ func (admitter *SecretAdmitter) testConnectionToHost(hostName string) (tested bo
 			return false, err
 		}
 		admitter.secret.Data["thumbprint"] = []byte(hostModel.Thumbprint)
-		url := fmt.Sprintf("https://%s/sdk", admitter.secret.Data["ip"])
+		url := fmt.Sprintf("https://%s/sdk", libutil.URLHost(string(admitter.secret.Data["ip"])))
 
 		updatedSecret, err := admitter.ensureEsxiCredentials(provider)
 		if err != nil {
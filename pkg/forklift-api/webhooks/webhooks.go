@@ -17,6 +17,7 @@ const PlanMutatorPath = "/plan-mutate"
 const ProviderValidatePath = "/provider-validate"
 const ProviderMutatorPath = "/provider-mutate"
 const MigrationValidatePath = "/migration-validate"
+const MigrationMutatorPath = "/migration-mutate"
 
 // AddToManagerFuncs is a list of functions to add all Controllers to the Manager
 var AddToManagerFuncs []func(manager.Manager) error
@@ -58,4 +59,7 @@ func RegisterMutatingWebhooks(mux *http.ServeMux, client client.Client) {
 	mux.HandleFunc(ProviderMutatorPath, func(w http.ResponseWriter, r *http.Request) {
 		ServeProviderMutator(w, r, client)
 	})
+	mux.HandleFunc(MigrationMutatorPath, func(w http.ResponseWriter, r *http.Request) {
+		ServeMigrationMutator(w, r, client)
+	})
 }
@@ -21,6 +21,7 @@ import (
 	"crypto/tls"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -78,6 +79,12 @@ const (
 	reasonPopulatorProgress  = "PopulatorProgress"
 	AnnTransferNetwork       = "k8s.v1.cni.cncf.io/networks"
 	AnnPopulatorReCreations  = "recreations"
+	AnnPopulatorOffset       = "populatorOffset"
+
+	// Populator pod retries and the backoff between them.
+	populatorMaxRetries      = 3
+	populatorRetryBackoff    = 10 * time.Second
+	populatorMaxRetryBackoff = 5 * time.Minute
 
 	qemuGroup = 107
 )
@@ -99,12 +106,29 @@ var (
 			storageResourceKey: "image_id",
 			resource:           "openstackvolumepopulators",
 			regexKey:           "openstack_volume_populator",
+			resumable:          true,
 		},
 		api.VSphereXcopyVolumePopulatorKind: {
 			storageResourceKey: "source_vmdk",
 			resource:           api.VSphereXcopyVolumePopulatorResource,
 			regexKey:           "vsphere_xcopy_volume_populator",
 		},
+		"EC2VolumePopulator": {
+			storageResourceKey: "snapshot_id",
+			resource:           "ec2volumepopulators",
+			regexKey:           "ec2_volume_populator",
+			resumable:          true,
+		},
+		"AzureVolumePopulator": {
+			storageResourceKey: "disk_id",
+			resource:           "azurevolumepopulators",
+			regexKey:           "azure_volume_populator",
+		},
+		"LibvirtVolumePopulator": {
+			storageResourceKey: "disk",
+			resource:           "libvirtvolumepopulators",
+			regexKey:           "libvirt_volume_populator",
+		},
 	}
 
 	monitoredPVCs = map[string]interface{}{}
@@ -114,6 +138,9 @@ type populatorResource struct {
 	storageResourceKey string
 	resource           string
 	regexKey           string
+	// The populator reports the synced offset
+	// and resumes from it with the --offset arg.
+	resumable bool
 }
 
 type controller struct {
@@ -536,6 +563,9 @@ func (c *controller) syncPvc(ctx context.Context, key, pvcNamespace, pvcName str
 	pvcSize := pvc.Spec.Resources.Requests.Storage().Value()
 	args = append(args, fmt.Sprintf("--pvc-size=%d", pvcSize))
 	args = append(args, fmt.Sprintf("--owner-uid=%s", pvc.UID))
+	if offset, found := crInstance.GetAnnotations()[AnnPopulatorOffset]; found && c.resumable() {
+		args = append(args, fmt.Sprintf("--offset=%s", offset))
+	}
 
 	var waitForFirstConsumer bool
 	var nodeName string
@@ -726,18 +756,22 @@ func (c *controller) syncPvc(ctx context.Context, key, pvcNamespace, pvcName str
 
 		if corev1.PodSucceeded != pod.Status.Phase {
 			if corev1.PodFailed == pod.Status.Phase {
-				restarts, ok := pvc.Annotations[AnnPopulatorReCreations]
-				if !ok {
-					return c.retryFailedPopulator(ctx, pvc, populatorNamespace, pod.Name, 1)
-				}
-				restartsInteger, err := strconv.Atoi(restarts)
-				if err != nil {
-					return err
+				restartsInteger := 0
+				if restarts, ok := pvc.Annotations[AnnPopulatorReCreations]; ok {
+					restartsInteger, err = strconv.Atoi(restarts)
+					if err != nil {
+						return err
+					}
 				}
-				if restartsInteger < 3 {
+				if restartsInteger < populatorMaxRetries {
+					// Back off before recreating the pod.
+					if wait := retryWait(pod, restartsInteger, time.Now()); wait > 0 {
+						c.workqueue.AddAfter(key, wait)
+						return nil
+					}
 					return c.retryFailedPopulator(ctx, pvc, populatorNamespace, pod.Name, restartsInteger+1)
 				}
-				c.recorder.Eventf(pvc, corev1.EventTypeWarning, reasonPodFailed, "Populator failed after few (3) attempts: Please check the logs of the populator pod, %s/%s", populatorNamespace, pod.Name)
+				c.recorder.Eventf(pvc, corev1.EventTypeWarning, reasonPodFailed, "Populator failed after few (%d) attempts: Please check the logs of the populator pod, %s/%s", populatorMaxRetries, populatorNamespace, pod.Name)
 			}
 			// We'll get called again later when the pod succeeds
 			return nil
@@ -845,6 +879,27 @@ func (c *controller) retryFailedPopulator(ctx context.Context, pvc *corev1.Persi
 	return nil
 }
 
+// Time left before the failed populator pod may be recreated.
+// The backoff doubles with every retry.
+func retryWait(pod *corev1.Pod, restarts int, now time.Time) time.Duration {
+	backoff := populatorRetryBackoff << restarts
+	if backoff > populatorMaxRetryBackoff {
+		backoff = populatorMaxRetryBackoff
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Terminated != nil {
+			return status.State.Terminated.FinishedAt.Add(backoff).Sub(now)
+		}
+	}
+	return 0
+}
+
+// Whether the populator resumes from the synced offset.
+func (c *controller) resumable() bool {
+	resource, found := populatorToResource[c.gk.Kind]
+	return found && resource.resumable
+}
+
 func (c *controller) updatePvc(ctx context.Context, pvc *corev1.PersistentVolumeClaim, namespace string) (err error) {
 	_, err = c.kubeClient.CoreV1().PersistentVolumeClaims(namespace).Update(ctx, pvc, metav1.UpdateOptions{})
 	return err
@@ -889,6 +944,19 @@ func (c *controller) updateProgress(pod *corev1.Pod, pvc *corev1.PersistentVolum
 		return err
 	}
 
+	var offset string
+	if populatorToResource[populatorKind].resumable {
+		offsetRegExp := regexp.MustCompile("offset\\{ownerUID=\"" + string(pvc.UID) + "\"\\} (\\S+)")
+		if match = offsetRegExp.FindStringSubmatch(string(body)); match != nil {
+			synced, err := strconv.ParseFloat(match[1], 64)
+			if err != nil {
+				klog.V(5).Info("Could not convert offset: ", err)
+				return err
+			}
+			offset = strconv.FormatInt(int64(synced), 10)
+		}
+	}
+
 	gvr := schema.GroupVersionResource{
 		Group:    *pvc.Spec.DataSourceRef.APIGroup,
 		Version:  "v1beta1",
@@ -906,6 +974,9 @@ func (c *controller) updateProgress(pod *corev1.Pod, pvc *corev1.PersistentVolum
 		klog.V(5).Info("Failed to update progress: ", err)
 		return err
 	}
+	if offset != "" {
+		updatePopulatorOffset(offset, latestPopulator)
+	}
 
 	_, err = c.dynamicClient.Resource(gvr).Namespace(pvc.Namespace).Update(context.TODO(), latestPopulator, metav1.UpdateOptions{})
 	if err != nil {
@@ -928,6 +999,16 @@ func updatePopulatorProgress(progress int64, cr *unstructured.Unstructured) erro
 	return nil
 }
 
+// Persist the synced offset the populator resumes from.
+func updatePopulatorOffset(offset string, cr *unstructured.Unstructured) {
+	annotations := cr.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[AnnPopulatorOffset] = offset
+	cr.SetAnnotations(annotations)
+}
+
 func makePopulatePodSpec(pvcPrimeName, secretName string) corev1.PodSpec {
 	return corev1.PodSpec{
 		Containers: []corev1.Container{
@@ -1082,7 +1163,7 @@ func getMetricsURL(pod *corev1.Pod) (string, error) {
 	if err != nil || pod.Status.PodIP == "" {
 		return "", err
 	}
-	url := fmt.Sprintf("https://%s:%d/metrics", pod.Status.PodIP, port)
+	url := fmt.Sprintf("https://%s/metrics", net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(port)))
 	return url, nil
 }
 
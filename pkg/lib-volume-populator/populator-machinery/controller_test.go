@@ -0,0 +1,46 @@
+package populator_machinery
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRetryWait(t *testing.T) {
+	now := time.Now()
+	failed := func(ago time.Duration) *corev1.Pod {
+		return &corev1.Pod{
+			Status: corev1.PodStatus{
+				Phase: corev1.PodFailed,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						State: corev1.ContainerState{
+							Terminated: &corev1.ContainerStateTerminated{
+								FinishedAt: metav1.NewTime(now.Add(-ago)),
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	cases := []struct {
+		pod      *corev1.Pod
+		restarts int
+		wait     time.Duration
+	}{
+		{failed(0), 0, 10 * time.Second},
+		{failed(5 * time.Second), 1, 15 * time.Second},
+		{failed(time.Minute), 1, -40 * time.Second},
+		{failed(0), 10, populatorMaxRetryBackoff},
+		{&corev1.Pod{}, 2, 0},
+	}
+	for _, c := range cases {
+		wait := retryWait(c.pod, c.restarts, now)
+		if wait != c.wait {
+			t.Errorf("restarts %d: expected wait %s, got %s", c.restarts, c.wait, wait)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+)
+
+// Outcomes.
+const (
+	Succeeded = "Succeeded"
+	Failed    = "Failed"
+	Denied    = "Denied"
+)
+
+// Defaults.
+const (
+	DefaultMaxSize    = 10 * 1024 * 1024
+	DefaultMaxBackups = 5
+)
+
+// Audit record of a state-changing operation.
+type Record struct {
+	// Timestamp.
+	Time time.Time `json:"time"`
+	// Subject (user or service account) of the bearer token.
+	Subject string `json:"subject"`
+	// HTTP method.
+	Method string `json:"method"`
+	// Request path.
+	Path string `json:"path"`
+	// Operation (route).
+	Operation string `json:"operation"`
+	// HTTP status.
+	Status int `json:"status"`
+	// Outcome.
+	Outcome string `json:"outcome"`
+	// Duration in milliseconds.
+	Duration int64 `json:"duration"`
+}
+
+// Outcome of the HTTP status.
+func Outcome(status int) string {
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return Denied
+	case status >= http.StatusBadRequest:
+		return Failed
+	default:
+		return Succeeded
+	}
+}
+
+// Rotating JSON (lines) audit log.
+// The log is rotated when its size exceeds MaxSize. Rotated logs
+// are renamed with a numeric suffix (path.1 being the most recent)
+// and only MaxBackups are kept.
+type Log struct {
+	// File path.
+	Path string
+	// Max size in bytes.
+	MaxSize int64
+	// Max number of rotated logs.
+	MaxBackups int
+	// Mutex.
+	mutex sync.Mutex
+	// Open file.
+	file *os.File
+	// Size of the open file.
+	size int64
+}
+
+// Write a record.
+func (r *Log) Write(record *Record) (err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	line, err := json.Marshal(record)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	line = append(line, '\n')
+	err = r.open()
+	if err != nil {
+		return
+	}
+	if r.size > 0 && r.size+int64(len(line)) > r.maxSize() {
+		err = r.rotate()
+		if err != nil {
+			return
+		}
+	}
+	n, err := r.file.Write(line)
+	r.size += int64(n)
+	if err != nil {
+		err = liberr.Wrap(err, "path", r.Path)
+	}
+	return
+}
+
+// Read the most recent records of the current log,
+// newest first. All records when limit is 0.
+func (r *Log) Read(limit int) (records []Record, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	records = []Record{}
+	file, err := os.Open(r.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err, "path", r.Path)
+		}
+		return
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		record := Record{}
+		if json.Unmarshal(scanner.Bytes(), &record) != nil {
+			continue
+		}
+		records = append(records, record)
+	}
+	err = scanner.Err()
+	if err != nil {
+		err = liberr.Wrap(err, "path", r.Path)
+		return
+	}
+	for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+		records[i], records[j] = records[j], records[i]
+	}
+	if limit > 0 && len(records) > limit {
+		records = records[:limit]
+	}
+	return
+}
+
+// Close the log.
+func (r *Log) Close() (err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.file != nil {
+		err = r.file.Close()
+		r.file = nil
+	}
+	return
+}
+
+// Open the file for append.
+func (r *Log) open() (err error) {
+	if r.file != nil {
+		return
+	}
+	r.file, err = os.OpenFile(r.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		err = liberr.Wrap(err, "path", r.Path)
+		return
+	}
+	info, err := r.file.Stat()
+	if err != nil {
+		err = liberr.Wrap(err, "path", r.Path)
+		return
+	}
+	r.size = info.Size()
+	return
+}
+
+// Rotate the log.
+func (r *Log) rotate() (err error) {
+	err = r.file.Close()
+	r.file = nil
+	if err != nil {
+		err = liberr.Wrap(err, "path", r.Path)
+		return
+	}
+	backups := r.maxBackups()
+	_ = os.Remove(r.backup(backups))
+	for n := backups - 1; n > 0; n-- {
+		err = os.Rename(r.backup(n), r.backup(n+1))
+		if err != nil && !os.IsNotExist(err) {
+			err = liberr.Wrap(err, "path", r.Path)
+			return
+		}
+	}
+	err = os.Rename(r.Path, r.backup(1))
+	if err != nil {
+		err = liberr.Wrap(err, "path", r.Path)
+		return
+	}
+	err = r.open()
+	return
+}
+
+// Path of a rotated log.
+func (r *Log) backup(n int) string {
+	return fmt.Sprintf("%s.%d", r.Path, n)
+}
+
+// Max size or the default.
+func (r *Log) maxSize() int64 {
+	if r.MaxSize > 0 {
+		return r.MaxSize
+	}
+	return DefaultMaxSize
+}
+
+// Max backups or the default.
+func (r *Log) maxBackups() int {
+	if r.MaxBackups > 0 {
+		return r.MaxBackups
+	}
+	return DefaultMaxBackups
+}
@@ -0,0 +1,74 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestOutcome(t *testing.T) {
+	cases := map[int]string{
+		200: Succeeded,
+		201: Succeeded,
+		400: Failed,
+		401: Denied,
+		403: Denied,
+		500: Failed,
+	}
+	for status, expected := range cases {
+		if actual := Outcome(status); actual != expected {
+			t.Errorf("status %d: expected %s, got %s", status, expected, actual)
+		}
+	}
+}
+
+func TestWriteRead(t *testing.T) {
+	log := &Log{Path: filepath.Join(t.TempDir(), "audit.log")}
+	defer log.Close()
+	for _, subject := range []string{"a", "b", "c"} {
+		err := log.Write(&Record{Time: time.Now(), Subject: subject, Status: 200, Outcome: Succeeded})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	records, err := log.Read(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].Subject != "c" || records[1].Subject != "b" {
+		t.Errorf("unexpected records: %v", records)
+	}
+}
+
+func TestReadNotFound(t *testing.T) {
+	log := &Log{Path: filepath.Join(t.TempDir(), "audit.log")}
+	records, err := log.Read(0)
+	if err != nil || len(records) != 0 {
+		t.Errorf("expected no records, got %v (%v)", records, err)
+	}
+}
+
+func TestRotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	log := &Log{Path: path, MaxSize: 200, MaxBackups: 2}
+	defer log.Close()
+	for i := 0; i < 10; i++ {
+		err := log.Write(&Record{Time: time.Now(), Subject: "system:serviceaccount:ns:sa", Status: 200})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, p := range []string{path, path + ".1", path + ".2"} {
+		info, err := os.Stat(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Size() > 200 {
+			t.Errorf("%s: size %d exceeds max", p, info.Size())
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("expected at most 2 backups")
+	}
+}
@@ -0,0 +1,498 @@
+package bundle
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// Bundle kind.
+const (
+	Kind = "PlanBundle"
+)
+
+// Annotations.
+const (
+	// Secret data has been redacted.
+	AnnRedacted = "forklift.konveyor.io/redacted"
+)
+
+// Plan bundle.
+// A plan with the maps, hooks and (redacted) secrets it
+// references, signed (HMAC-SHA256) with a shared key.
+type Bundle struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	// Namespace the plan was exported from.
+	Namespace string `json:"namespace"`
+	// Export timestamp.
+	Exported meta.Time `json:"exported"`
+	// Plan.
+	Plan *api.Plan `json:"plan"`
+	// Network map.
+	NetworkMap *api.NetworkMap `json:"networkMap,omitempty"`
+	// Storage map.
+	StorageMap *api.StorageMap `json:"storageMap,omitempty"`
+	// Hooks.
+	Hooks []api.Hook `json:"hooks,omitempty"`
+	// Secrets (redacted).
+	Secrets []core.Secret `json:"secrets,omitempty"`
+	// Signature.
+	Signature string `json:"signature,omitempty"`
+}
+
+// Authorizes access to the bundled resources.
+// Required when the client is not the requesting user.
+type Authorizer interface {
+	// Authorize the verb on the resource.
+	// Returns a forbidden error when not allowed.
+	Authorize(verb string, resource schema.GroupResource, namespace, name string) error
+}
+
+// Export the plan and the resources it references.
+// Each resource read is authorized when an authorizer is specified.
+func Export(c client.Client, namespace, name string, authorizer Authorizer) (bundle *Bundle, err error) {
+	get := func(objectKey client.ObjectKey, object client.Object) (err error) {
+		if authorizer != nil {
+			err = authorizer.Authorize("get", resource(object), objectKey.Namespace, objectKey.Name)
+			if err != nil {
+				return
+			}
+		}
+		err = c.Get(context.TODO(), objectKey, object)
+		if err != nil {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	plan := &api.Plan{}
+	err = get(client.ObjectKey{Namespace: namespace, Name: name}, plan)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	bundle = &Bundle{
+		APIVersion: api.SchemeGroupVersion.String(),
+		Kind:       Kind,
+		Namespace:  namespace,
+		Exported:   meta.Now(),
+		Plan:       plan,
+	}
+	plan.ObjectMeta = cleaned(plan.ObjectMeta)
+	plan.TypeMeta = typeMeta("Plan")
+	plan.Status = api.PlanStatus{}
+	if ref := plan.Spec.Map.Network; ref.Name != "" {
+		networkMap := &api.NetworkMap{}
+		err = get(key(ref, namespace), networkMap)
+		if err != nil {
+			return
+		}
+		networkMap.ObjectMeta = cleaned(networkMap.ObjectMeta)
+		networkMap.TypeMeta = typeMeta("NetworkMap")
+		networkMap.Status = api.MapStatus{}
+		bundle.NetworkMap = networkMap
+	}
+	secrets := map[client.ObjectKey]bool{}
+	if ref := plan.Spec.Map.Storage; ref.Name != "" {
+		storageMap := &api.StorageMap{}
+		err = get(key(ref, namespace), storageMap)
+		if err != nil {
+			return
+		}
+		storageMap.ObjectMeta = cleaned(storageMap.ObjectMeta)
+		storageMap.TypeMeta = typeMeta("StorageMap")
+		storageMap.Status = api.MapStatus{}
+		bundle.StorageMap = storageMap
+		// offload plugin secrets reside with the source provider.
+		for _, pair := range storageMap.Spec.Map {
+			if pair.OffloadPlugin == nil || pair.OffloadPlugin.VSphereXcopyPluginConfig == nil {
+				continue
+			}
+			ref := core.ObjectReference{
+				Namespace: storageMap.Spec.Provider.Source.Namespace,
+				Name:      pair.OffloadPlugin.VSphereXcopyPluginConfig.SecretRef,
+			}
+			secrets[key(ref, namespace)] = true
+		}
+	}
+	hooks := map[client.ObjectKey]bool{}
+	for _, vm := range plan.Spec.VMs {
+		for _, hookRef := range vm.Hooks {
+			hooks[key(hookRef.Hook, namespace)] = true
+		}
+		if vm.LUKS.Name != "" {
+			secrets[key(vm.LUKS, namespace)] = true
+		}
+	}
+	for _, hookKey := range sorted(hooks) {
+		hook := api.Hook{}
+		err = get(hookKey, &hook)
+		if err != nil {
+			return
+		}
+		hook.ObjectMeta = cleaned(hook.ObjectMeta)
+		hook.TypeMeta = typeMeta("Hook")
+		hook.Status = api.HookStatus{}
+		bundle.Hooks = append(bundle.Hooks, hook)
+	}
+	for _, secretKey := range sorted(secrets) {
+		secret := core.Secret{}
+		err = get(secretKey, &secret)
+		if err != nil {
+			return
+		}
+		bundle.Secrets = append(bundle.Secrets, redacted(&secret))
+	}
+	return
+}
+
+// Sign the bundle.
+func (r *Bundle) Sign(key []byte) (err error) {
+	r.Signature = ""
+	r.Signature, err = r.digest(key)
+	return
+}
+
+// Verify the bundle signature.
+func (r *Bundle) Verify(key []byte) (err error) {
+	signature := r.Signature
+	r.Signature = ""
+	defer func() {
+		r.Signature = signature
+	}()
+	digest, err := r.digest(key)
+	if err != nil {
+		return
+	}
+	if !hmac.Equal([]byte(digest), []byte(signature)) {
+		err = liberr.New("bundle signature not valid.")
+	}
+	return
+}
+
+// HMAC digest of the bundle content.
+func (r *Bundle) digest(key []byte) (digest string, err error) {
+	if len(key) == 0 {
+		err = liberr.New("bundle signing key required.")
+		return
+	}
+	content, err := json.Marshal(r)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write(content)
+	digest = base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	return
+}
+
+// Encode the bundle as YAML.
+func (r *Bundle) Encode() (content []byte, err error) {
+	content, err = yaml.Marshal(r)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+	return
+}
+
+// Decode a YAML bundle.
+func Decode(content []byte) (bundle *Bundle, err error) {
+	bundle = &Bundle{}
+	err = yaml.UnmarshalStrict(content, bundle)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if bundle.Kind != Kind || bundle.Plan == nil {
+		err = liberr.New("not a plan bundle.")
+	}
+	return
+}
+
+// Import options.
+type Options struct {
+	// Namespace of the imported resources.
+	// Defaults to the namespace the plan was exported from.
+	Namespace string
+	// Plan name. Defaults to the exported name.
+	Name string
+	// Plan target namespace.
+	TargetNamespace string
+	// Source provider name.
+	SourceProvider string
+	// Destination provider name.
+	DestinationProvider string
+	// Authorizes the created resources.
+	Authorizer Authorizer
+}
+
+// Import result.
+type Result struct {
+	// Created resources (kind/namespace/name).
+	Created []string `json:"created"`
+	// Secrets found in the namespace and not replaced.
+	Existing []string `json:"existing"`
+	// Secrets created with redacted data that must be completed.
+	Redacted []string `json:"redacted"`
+}
+
+// Import the bundle.
+// References to resources in the namespace the plan was exported
+// from are rewritten to the import namespace. Each resource is
+// authorized before any is created and the created resources are
+// deleted when the import fails.
+func Import(c client.Client, bundle *Bundle, options Options) (result Result, err error) {
+	result = Result{
+		Created:  []string{},
+		Existing: []string{},
+		Redacted: []string{},
+	}
+	if options.Namespace == "" {
+		options.Namespace = bundle.Namespace
+	}
+	rw := rewriter{from: bundle.Namespace, to: options.Namespace}
+	plan := bundle.Plan.DeepCopy()
+	if options.Name != "" {
+		plan.Name = options.Name
+	}
+	plan.Namespace = options.Namespace
+	if options.TargetNamespace != "" {
+		plan.Spec.TargetNamespace = options.TargetNamespace
+	} else if plan.Spec.TargetNamespace == bundle.Namespace {
+		plan.Spec.TargetNamespace = options.Namespace
+	}
+	rw.provider(&plan.Spec.Provider.Source, options.SourceProvider)
+	rw.provider(&plan.Spec.Provider.Destination, options.DestinationProvider)
+	rw.ref(&plan.Spec.Map.Network)
+	rw.ref(&plan.Spec.Map.Storage)
+	if plan.Spec.TransferNetwork != nil && plan.Spec.TransferNetwork.Namespace == bundle.Namespace {
+		plan.Spec.TransferNetwork.Namespace = options.Namespace
+	}
+	for i := range plan.Spec.VMs {
+		vm := &plan.Spec.VMs[i]
+		for j := range vm.Hooks {
+			rw.ref(&vm.Hooks[j].Hook)
+		}
+		if vm.LUKS.Name != "" {
+			rw.ref(&vm.LUKS)
+		}
+	}
+	authorize := func(object client.Object) (err error) {
+		if options.Authorizer != nil {
+			err = options.Authorizer.Authorize("create", resource(object), object.GetNamespace(), "")
+		}
+		return
+	}
+	err = authorize(plan)
+	if err != nil {
+		return
+	}
+	objects := []client.Object{}
+	for i := range bundle.Secrets {
+		secret := bundle.Secrets[i].DeepCopy()
+		secret.Namespace = rw.namespace(secret.Namespace)
+		err = authorize(secret)
+		if err != nil {
+			return
+		}
+		found := &core.Secret{}
+		err = c.Get(context.TODO(), client.ObjectKeyFromObject(secret), found)
+		if err == nil {
+			result.Existing = append(result.Existing, path("Secret", secret))
+			continue
+		}
+		if !k8serr.IsNotFound(err) {
+			err = liberr.Wrap(err)
+			return
+		}
+		err = nil
+		if secret.Annotations[AnnRedacted] == "true" {
+			result.Redacted = append(result.Redacted, path("Secret", secret))
+		}
+		objects = append(objects, secret)
+	}
+	for i := range bundle.Hooks {
+		hook := bundle.Hooks[i].DeepCopy()
+		hook.Namespace = rw.namespace(hook.Namespace)
+		objects = append(objects, hook)
+	}
+	if bundle.NetworkMap != nil {
+		networkMap := bundle.NetworkMap.DeepCopy()
+		networkMap.Namespace = rw.namespace(networkMap.Namespace)
+		rw.provider(&networkMap.Spec.Provider.Source, options.SourceProvider)
+		rw.provider(&networkMap.Spec.Provider.Destination, options.DestinationProvider)
+		objects = append(objects, networkMap)
+	}
+	if bundle.StorageMap != nil {
+		storageMap := bundle.StorageMap.DeepCopy()
+		storageMap.Namespace = rw.namespace(storageMap.Namespace)
+		rw.provider(&storageMap.Spec.Provider.Source, options.SourceProvider)
+		rw.provider(&storageMap.Spec.Provider.Destination, options.DestinationProvider)
+		objects = append(objects, storageMap)
+	}
+	for _, object := range objects {
+		err = authorize(object)
+		if err != nil {
+			return
+		}
+	}
+	objects = append(objects, plan)
+	created := []client.Object{}
+	defer func() {
+		if err == nil {
+			return
+		}
+		for i := len(created) - 1; i >= 0; i-- {
+			_ = c.Delete(context.TODO(), created[i])
+		}
+		result.Created = []string{}
+	}()
+	for _, object := range objects {
+		objectPath := path(kind(object), object)
+		err = c.Create(context.TODO(), object)
+		if err != nil {
+			err = liberr.Wrap(err, "object", objectPath)
+			return
+		}
+		created = append(created, object)
+		result.Created = append(result.Created, objectPath)
+	}
+	return
+}
+
+// Rewrites references to the exported namespace.
+type rewriter struct {
+	from string
+	to   string
+}
+
+// Rewrite the namespace.
+func (r *rewriter) namespace(namespace string) string {
+	if namespace == "" || namespace == r.from {
+		return r.to
+	}
+	return namespace
+}
+
+// Rewrite the reference.
+func (r *rewriter) ref(ref *core.ObjectReference) {
+	ref.Namespace = r.namespace(ref.Namespace)
+	ref.UID = ""
+	ref.ResourceVersion = ""
+}
+
+// Rewrite the provider reference.
+func (r *rewriter) provider(ref *core.ObjectReference, name string) {
+	r.ref(ref)
+	if name != "" {
+		ref.Name = name
+	}
+}
+
+// Object key of the reference.
+func key(ref core.ObjectReference, namespace string) client.ObjectKey {
+	if ref.Namespace != "" {
+		namespace = ref.Namespace
+	}
+	return client.ObjectKey{Namespace: namespace, Name: ref.Name}
+}
+
+// Sorted object keys.
+func sorted(keys map[client.ObjectKey]bool) (list []client.ObjectKey) {
+	for k := range keys {
+		list = append(list, k)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].String() < list[j].String()
+	})
+	return
+}
+
+// Object metadata without the cluster specific fields.
+func cleaned(in meta.ObjectMeta) (out meta.ObjectMeta) {
+	out = meta.ObjectMeta{
+		Namespace: in.Namespace,
+		Name:      in.Name,
+		Labels:    in.Labels,
+	}
+	for k, v := range in.Annotations {
+		if k == core.LastAppliedConfigAnnotation {
+			continue
+		}
+		if out.Annotations == nil {
+			out.Annotations = map[string]string{}
+		}
+		out.Annotations[k] = v
+	}
+	return
+}
+
+// Forklift type metadata.
+func typeMeta(kind string) meta.TypeMeta {
+	return meta.TypeMeta{
+		APIVersion: api.SchemeGroupVersion.String(),
+		Kind:       kind,
+	}
+}
+
+// Secret with the values redacted (keys kept).
+func redacted(in *core.Secret) (out core.Secret) {
+	out = core.Secret{
+		TypeMeta:   meta.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: cleaned(in.ObjectMeta),
+		Type:       in.Type,
+		Data:       map[string][]byte{},
+	}
+	for k := range in.Data {
+		out.Data[k] = []byte{}
+	}
+	if out.Annotations == nil {
+		out.Annotations = map[string]string{}
+	}
+	out.Annotations[AnnRedacted] = "true"
+	return
+}
+
+// Kind of the object.
+func kind(object client.Object) string {
+	if _, cast := object.(*core.Secret); cast {
+		return "Secret"
+	}
+	return object.GetObjectKind().GroupVersionKind().Kind
+}
+
+// Resource of the object.
+func resource(object client.Object) (gr schema.GroupResource) {
+	gr.Group = api.SchemeGroupVersion.Group
+	switch object.(type) {
+	case *core.Secret:
+		gr = core.Resource("secrets")
+	case *api.Plan:
+		gr.Resource = "plans"
+	case *api.Hook:
+		gr.Resource = "hooks"
+	case *api.NetworkMap:
+		gr.Resource = "networkmaps"
+	case *api.StorageMap:
+		gr.Resource = "storagemaps"
+	}
+	return
+}
+
+// Path of the object (kind/namespace/name).
+func path(kind string, object client.Object) string {
+	return kind + "/" + object.GetNamespace() + "/" + object.GetName()
+}
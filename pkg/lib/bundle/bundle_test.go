@@ -0,0 +1,248 @@
+package bundle
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/provider"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func fakeClient(objects ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	_ = core.AddToScheme(scheme)
+	_ = api.SchemeBuilder.AddToScheme(scheme)
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+}
+
+func objects() []client.Object {
+	plan := &api.Plan{
+		ObjectMeta: meta.ObjectMeta{Namespace: "dev", Name: "plan", UID: "1234"},
+		Spec: api.PlanSpec{
+			TargetNamespace: "dev",
+			Provider: provider.Pair{
+				Source:      core.ObjectReference{Namespace: "dev", Name: "vcenter"},
+				Destination: core.ObjectReference{Namespace: "dev", Name: "host"},
+			},
+			Map: planapi.Map{
+				Network: core.ObjectReference{Namespace: "dev", Name: "network"},
+				Storage: core.ObjectReference{Name: "storage"},
+			},
+			VMs: []planapi.VM{
+				{
+					Ref:   ref.Ref{ID: "vm-1"},
+					Hooks: []planapi.HookRef{{Step: "PreHook", Hook: core.ObjectReference{Namespace: "dev", Name: "hook"}}},
+					LUKS:  core.ObjectReference{Namespace: "dev", Name: "luks"},
+				},
+				{
+					Ref:   ref.Ref{ID: "vm-2"},
+					Hooks: []planapi.HookRef{{Step: "PostHook", Hook: core.ObjectReference{Name: "hook"}}},
+				},
+			},
+		},
+	}
+	return []client.Object{
+		plan,
+		&api.NetworkMap{ObjectMeta: meta.ObjectMeta{Namespace: "dev", Name: "network"}},
+		&api.StorageMap{ObjectMeta: meta.ObjectMeta{Namespace: "dev", Name: "storage"}},
+		&api.Hook{ObjectMeta: meta.ObjectMeta{Namespace: "dev", Name: "hook"}},
+		&core.Secret{
+			ObjectMeta: meta.ObjectMeta{Namespace: "dev", Name: "luks"},
+			Data:       map[string][]byte{"passphrase": []byte("secret")},
+		},
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	key := []byte("key")
+	exported, err := Export(fakeClient(objects()...), "dev", "plan", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exported.NetworkMap == nil || exported.StorageMap == nil || len(exported.Hooks) != 1 || len(exported.Secrets) != 1 {
+		t.Fatalf("unexpected bundle: %+v", exported)
+	}
+	if exported.Plan.UID != "" || exported.Plan.ResourceVersion != "" {
+		t.Errorf("plan metadata not cleaned")
+	}
+	secret := exported.Secrets[0]
+	if len(secret.Data["passphrase"]) != 0 || secret.Annotations[AnnRedacted] != "true" {
+		t.Errorf("secret not redacted: %+v", secret)
+	}
+	err = exported.Sign(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := exported.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := Decode(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = decoded.Verify(key); err != nil {
+		t.Fatalf("signature not verified: %v", err)
+	}
+	if err = decoded.Verify([]byte("other")); err == nil {
+		t.Errorf("expected signature error with another key")
+	}
+	decoded.Plan.Spec.Warm = true
+	if err = decoded.Verify(key); err == nil {
+		t.Errorf("expected signature error when tampered")
+	}
+	decoded.Plan.Spec.Warm = false
+
+	c := fakeClient()
+	result, err := Import(c, decoded, Options{Namespace: "prod", Name: "moved", SourceProvider: "vcenter-prod"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Created) != 5 || len(result.Redacted) != 1 || len(result.Existing) != 0 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	plan := &api.Plan{}
+	err = c.Get(context.TODO(), client.ObjectKey{Namespace: "prod", Name: "moved"}, plan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Spec.TargetNamespace != "prod" ||
+		plan.Spec.Provider.Source.Name != "vcenter-prod" ||
+		plan.Spec.Provider.Source.Namespace != "prod" ||
+		plan.Spec.Map.Network.Namespace != "prod" ||
+		plan.Spec.Map.Storage.Namespace != "prod" ||
+		plan.Spec.VMs[0].Hooks[0].Hook.Namespace != "prod" ||
+		plan.Spec.VMs[0].LUKS.Namespace != "prod" {
+		t.Errorf("references not rewritten: %+v", plan.Spec)
+	}
+	networkMap := &api.NetworkMap{}
+	err = c.Get(context.TODO(), client.ObjectKey{Namespace: "prod", Name: "network"}, networkMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if networkMap.Spec.Provider.Source.Name != "vcenter-prod" {
+		t.Errorf("map provider not rewritten: %+v", networkMap.Spec.Provider)
+	}
+}
+
+func TestImportExistingSecret(t *testing.T) {
+	exported, err := Export(fakeClient(objects()...), "dev", "plan", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	existing := &core.Secret{
+		ObjectMeta: meta.ObjectMeta{Namespace: "prod", Name: "luks"},
+		Data:       map[string][]byte{"passphrase": []byte("prod")},
+	}
+	c := fakeClient(existing)
+	result, err := Import(c, exported, Options{Namespace: "prod"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Existing) != 1 || len(result.Redacted) != 0 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	secret := &core.Secret{}
+	err = c.Get(context.TODO(), client.ObjectKeyFromObject(existing), secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(secret.Data["passphrase"]) != "prod" {
+		t.Errorf("existing secret replaced")
+	}
+}
+
+// Authorizer allowing the resources in the listed namespaces.
+type namespaceAuthorizer map[string]bool
+
+func (r namespaceAuthorizer) Authorize(verb string, resource schema.GroupResource, namespace, name string) error {
+	if !r[namespace] {
+		return k8serr.NewForbidden(resource, name, nil)
+	}
+	return nil
+}
+
+func TestExportNotAuthorized(t *testing.T) {
+	list := objects()
+	plan := list[0].(*api.Plan)
+	plan.Spec.VMs[0].LUKS.Namespace = "other"
+	list = append(list, &core.Secret{ObjectMeta: meta.ObjectMeta{Namespace: "other", Name: "luks"}})
+	_, err := Export(fakeClient(list...), "dev", "plan", namespaceAuthorizer{"dev": true})
+	if !k8serr.IsForbidden(err) {
+		t.Errorf("expected forbidden, got: %v", err)
+	}
+	_, err = Export(fakeClient(list...), "dev", "plan", namespaceAuthorizer{"dev": true, "other": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestImportNotAuthorized(t *testing.T) {
+	exported, err := Export(fakeClient(objects()...), "dev", "plan", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exported.Hooks[0].Namespace = "other"
+	c := fakeClient()
+	_, err = Import(c, exported, Options{Namespace: "prod", Authorizer: namespaceAuthorizer{"prod": true}})
+	if !k8serr.IsForbidden(err) {
+		t.Errorf("expected forbidden, got: %v", err)
+	}
+	secrets := &core.SecretList{}
+	err = c.List(context.TODO(), secrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(secrets.Items) != 0 {
+		t.Errorf("created before authorized: %+v", secrets.Items)
+	}
+}
+
+func TestImportRollback(t *testing.T) {
+	exported, err := Export(fakeClient(objects()...), "dev", "plan", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	existing := &api.Plan{ObjectMeta: meta.ObjectMeta{Namespace: "prod", Name: "plan"}}
+	c := fakeClient(existing)
+	result, err := Import(c, exported, Options{Namespace: "prod"})
+	if !k8serr.IsAlreadyExists(err) {
+		t.Fatalf("expected already exists, got: %v", err)
+	}
+	if len(result.Created) != 0 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	hooks := &api.HookList{}
+	err = c.List(context.TODO(), hooks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secrets := &core.SecretList{}
+	err = c.List(context.TODO(), secrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hooks.Items) != 0 || len(secrets.Items) != 0 {
+		t.Errorf("created resources not deleted")
+	}
+	err = c.Get(context.TODO(), client.ObjectKeyFromObject(existing), &api.Plan{})
+	if err != nil {
+		t.Errorf("existing plan deleted: %v", err)
+	}
+}
+
+func TestDecodeNotBundle(t *testing.T) {
+	_, err := Decode([]byte("kind: Plan\n"))
+	if err == nil {
+		t.Errorf("expected error")
+	}
+}
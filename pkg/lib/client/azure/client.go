@@ -0,0 +1,769 @@
+package azure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	liburl "net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+	core "k8s.io/api/core/v1"
+)
+
+// Secret keys.
+const (
+	TenantID       = "tenantId"
+	ClientID       = "clientId"
+	ClientSecret   = "clientSecret"
+	SubscriptionID = "subscriptionId"
+	// Optional (sovereign clouds).
+	AuthorityHost = "authorityHost"
+)
+
+// Default endpoints (public cloud).
+const (
+	DefaultURL           = "https://management.azure.com"
+	DefaultAuthorityHost = "https://login.microsoftonline.com"
+)
+
+// API versions.
+const (
+	SubscriptionAPIVersion = "2022-12-01"
+	ResourceAPIVersion     = "2021-04-01"
+	ComputeAPIVersion      = "2023-03-01"
+	DiskAPIVersion         = "2023-04-02"
+	NetworkAPIVersion      = "2023-05-01"
+	BlobAPIVersion         = "2021-08-06"
+)
+
+// Tags set on the snapshots created for the migration.
+const (
+	// Migration UID.
+	TagMigration = "forklift-migration"
+	// Disk (inventory) ID.
+	TagDisk = "forklift-disk"
+	// Precopy number.
+	TagPrecopy = "forklift-precopy"
+	// Last snapshot of the disk.
+	TagFinal = "forklift-final"
+	// Copied by the populator.
+	TagCopied = "forklift-copied"
+)
+
+// Page ranges listed by a request.
+const MaxPageRanges = 10000
+
+// Interval of the polled (long-running) operations.
+const PollInterval = 2 * time.Second
+
+// Max duration of the polled (long-running) operations.
+const PollTimeout = 5 * time.Minute
+
+// API (reply) error.
+type Error struct {
+	// HTTP status.
+	Status int
+	// Reported code.
+	Code string
+	// Reported message.
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("azure: %d %s %s", e.Status, e.Code, e.Message)
+}
+
+// The (inventory) ID of a resource.
+// The ARM resource IDs are paths and are not case sensitive,
+// so resources are identified by a (name based) UUID.
+func ID(resourceID string) string {
+	if resourceID == "" {
+		return ""
+	}
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(strings.ToLower(resourceID))).String()
+}
+
+// The resource group of a resource.
+// Example: /subscriptions/<sub>/resourceGroups/<group>/providers/...
+func ResourceGroupOf(resourceID string) (group string) {
+	part := strings.Split(strings.Trim(resourceID, "/"), "/")
+	for i := 0; i+1 < len(part); i++ {
+		if strings.EqualFold(part[i], "resourceGroups") {
+			group = part[i+1]
+			return
+		}
+	}
+	return
+}
+
+// The name of a resource (last segment of the ID).
+func NameOf(resourceID string) string {
+	part := strings.Split(strings.Trim(resourceID, "/"), "/")
+	return part[len(part)-1]
+}
+
+// Azure Resource Manager (and page blob) API client.
+type Client struct {
+	// Resource manager endpoint. Example: https://management.azure.com
+	URL     string
+	Options map[string]string
+	Log     logging.LevelLogger
+	// Proxy. The environment proxy when nil.
+	Proxy func(*http.Request) (*liburl.URL, error)
+	// Dial. The default dialer (any address family) when nil.
+	Dial   func(context.Context, string, string) (net.Conn, error)
+	client *http.Client
+	// Access token.
+	token struct {
+		mutex   sync.Mutex
+		value   string
+		expires time.Time
+	}
+}
+
+// Load the options from the provider secret.
+func (c *Client) LoadOptionsFromSecret(secret *core.Secret) {
+	c.Options = make(map[string]string)
+	for key, value := range secret.Data {
+		c.Options[key] = string(value)
+	}
+}
+
+// Connect.
+// Validates the URL, the credentials and the subscription.
+func (c *Client) Connect() (err error) {
+	err = c.get(c.subscription(), SubscriptionAPIVersion, nil, nil)
+	return
+}
+
+// The error is not found.
+func (c *Client) IsNotFound(err error) bool {
+	if apiErr, cast := liberr.Unwrap(err).(*Error); cast {
+		return apiErr.Status == http.StatusNotFound
+	}
+	return false
+}
+
+// The error is unauthorized.
+func (c *Client) IsUnauthorized(err error) bool {
+	if apiErr, cast := liberr.Unwrap(err).(*Error); cast {
+		return apiErr.Status == http.StatusUnauthorized ||
+			apiErr.Status == http.StatusForbidden
+	}
+	return false
+}
+
+// List the resource groups.
+func (c *Client) ListResourceGroups() (list []ResourceGroup, err error) {
+	err = c.list(
+		c.subscription()+"/resourcegroups",
+		ResourceAPIVersion,
+		nil,
+		func(content json.RawMessage) (err error) {
+			m := ResourceGroup{}
+			err = json.Unmarshal(content, &m)
+			list = append(list, m)
+			return
+		})
+	return
+}
+
+// List the virtual networks (and subnets).
+func (c *Client) ListVirtualNetworks() (list []VirtualNetwork, err error) {
+	err = c.list(
+		c.subscription()+"/providers/Microsoft.Network/virtualNetworks",
+		NetworkAPIVersion,
+		nil,
+		func(content json.RawMessage) (err error) {
+			m := VirtualNetwork{}
+			err = json.Unmarshal(content, &m)
+			list = append(list, m)
+			return
+		})
+	return
+}
+
+// List the network interfaces.
+func (c *Client) ListNetworkInterfaces() (list []NetworkInterface, err error) {
+	err = c.list(
+		c.subscription()+"/providers/Microsoft.Network/networkInterfaces",
+		NetworkAPIVersion,
+		nil,
+		func(content json.RawMessage) (err error) {
+			m := NetworkInterface{}
+			err = json.Unmarshal(content, &m)
+			list = append(list, m)
+			return
+		})
+	return
+}
+
+// List the managed disks.
+func (c *Client) ListDisks() (list []Disk, err error) {
+	err = c.list(
+		c.subscription()+"/providers/Microsoft.Compute/disks",
+		DiskAPIVersion,
+		nil,
+		func(content json.RawMessage) (err error) {
+			m := Disk{}
+			err = json.Unmarshal(content, &m)
+			list = append(list, m)
+			return
+		})
+	return
+}
+
+// Get a managed disk.
+func (c *Client) GetDisk(id string) (disk *Disk, err error) {
+	disk = &Disk{}
+	err = c.get(id, DiskAPIVersion, nil, disk)
+	return
+}
+
+// List the virtual machines.
+// The VMs are listed with the instance view (power state).
+func (c *Client) ListVirtualMachines() (list []VirtualMachine, err error) {
+	query := liburl.Values{}
+	query.Set("statusOnly", "true")
+	err = c.list(
+		c.subscription()+"/providers/Microsoft.Compute/virtualMachines",
+		ComputeAPIVersion,
+		query,
+		func(content json.RawMessage) (err error) {
+			m := VirtualMachine{}
+			err = json.Unmarshal(content, &m)
+			list = append(list, m)
+			return
+		})
+	return
+}
+
+// Get a virtual machine (with the instance view).
+func (c *Client) GetVirtualMachine(id string) (vm *VirtualMachine, err error) {
+	query := liburl.Values{}
+	query.Set("$expand", "instanceView")
+	vm = &VirtualMachine{}
+	err = c.get(id, ComputeAPIVersion, query, vm)
+	return
+}
+
+// List the VM sizes available in a location.
+func (c *Client) ListVMSizes(location string) (list []VMSize, err error) {
+	err = c.list(
+		c.subscription()+"/providers/Microsoft.Compute/locations/"+liburl.PathEscape(location)+"/vmSizes",
+		ComputeAPIVersion,
+		nil,
+		func(content json.RawMessage) (err error) {
+			m := VMSize{}
+			err = json.Unmarshal(content, &m)
+			list = append(list, m)
+			return
+		})
+	return
+}
+
+// Start a virtual machine.
+func (c *Client) StartVirtualMachine(id string) (err error) {
+	_, err = c.send(http.MethodPost, c.resourceURL(id+"/start", ComputeAPIVersion, nil), nil, nil)
+	return
+}
+
+// Deallocate (stop) a virtual machine.
+func (c *Client) DeallocateVirtualMachine(id string) (err error) {
+	_, err = c.send(http.MethodPost, c.resourceURL(id+"/deallocate", ComputeAPIVersion, nil), nil, nil)
+	return
+}
+
+// Create an incremental snapshot of a managed disk.
+// The snapshot is created in the resource group of the disk.
+func (c *Client) CreateSnapshot(disk *Disk, name string, tags map[string]string) (snapshot *Snapshot, err error) {
+	id := c.SnapshotID(ResourceGroupOf(disk.ID), name)
+	in := &Snapshot{
+		Location: disk.Location,
+		Tags:     tags,
+	}
+	in.Properties.CreationData.CreateOption = "Copy"
+	in.Properties.CreationData.SourceResourceID = disk.ID
+	in.Properties.Incremental = true
+	snapshot = &Snapshot{}
+	_, err = c.send(http.MethodPut, c.resourceURL(id, DiskAPIVersion, nil), in, snapshot)
+	if err != nil {
+		return
+	}
+	if snapshot.ID == "" {
+		snapshot.ID = id
+	}
+	return
+}
+
+// The (resource) ID of a snapshot.
+func (c *Client) SnapshotID(resourceGroup, name string) string {
+	return fmt.Sprintf(
+		"%s/resourceGroups/%s/providers/Microsoft.Compute/snapshots/%s",
+		c.subscription(),
+		liburl.PathEscape(resourceGroup),
+		liburl.PathEscape(name))
+}
+
+// Get a snapshot.
+func (c *Client) GetSnapshot(id string) (snapshot *Snapshot, err error) {
+	snapshot = &Snapshot{}
+	err = c.get(id, DiskAPIVersion, nil, snapshot)
+	return
+}
+
+// List the snapshots in a resource group.
+func (c *Client) ListSnapshots(resourceGroup string) (list []Snapshot, err error) {
+	err = c.list(
+		c.subscription()+"/resourceGroups/"+liburl.PathEscape(resourceGroup)+"/providers/Microsoft.Compute/snapshots",
+		DiskAPIVersion,
+		nil,
+		func(content json.RawMessage) (err error) {
+			m := Snapshot{}
+			err = json.Unmarshal(content, &m)
+			list = append(list, m)
+			return
+		})
+	return
+}
+
+// Find the snapshots in a resource group with the tags.
+func (c *Client) FindSnapshots(resourceGroup string, tags map[string]string) (list []Snapshot, err error) {
+	all, err := c.ListSnapshots(resourceGroup)
+	if err != nil {
+		return
+	}
+next:
+	for _, snapshot := range all {
+		for key, value := range tags {
+			if snapshot.Tags[key] != value {
+				continue next
+			}
+		}
+		list = append(list, snapshot)
+	}
+	return
+}
+
+// Replace the tags of a snapshot.
+func (c *Client) TagSnapshot(id string, tags map[string]string) (err error) {
+	in := struct {
+		Tags map[string]string `json:"tags"`
+	}{
+		Tags: tags,
+	}
+	_, err = c.send(http.MethodPatch, c.resourceURL(id, DiskAPIVersion, nil), in, nil)
+	return
+}
+
+// Delete a snapshot.
+func (c *Client) DeleteSnapshot(id string) (err error) {
+	_, err = c.send(http.MethodDelete, c.resourceURL(id, DiskAPIVersion, nil), nil, nil)
+	if c.IsNotFound(err) {
+		err = nil
+	}
+	return
+}
+
+// Grant (read) access to a snapshot.
+// Returns the SAS URL of the snapshot (page blob).
+func (c *Client) GrantAccess(id string, duration time.Duration) (sas string, err error) {
+	in := struct {
+		Access            string `json:"access"`
+		DurationInSeconds int64  `json:"durationInSeconds"`
+	}{
+		Access:            "Read",
+		DurationInSeconds: int64(duration.Seconds()),
+	}
+	out := struct {
+		AccessSAS string `json:"accessSAS"`
+	}{}
+	header, err := c.send(http.MethodPost, c.resourceURL(id+"/beginGetAccess", DiskAPIVersion, nil), in, &out)
+	if err != nil {
+		return
+	}
+	if out.AccessSAS == "" {
+		err = c.poll(header, &out)
+		if err != nil {
+			return
+		}
+	}
+	sas = out.AccessSAS
+	if sas == "" {
+		err = liberr.New("access not granted.", "snapshot", id)
+	}
+	return
+}
+
+// Revoke the access to a snapshot.
+func (c *Client) RevokeAccess(id string) (err error) {
+	_, err = c.send(http.MethodPost, c.resourceURL(id+"/endGetAccess", DiskAPIVersion, nil), nil, nil)
+	return
+}
+
+// List (a page of) the page ranges of a snapshot (page blob).
+// The ranges changed since the previous snapshot are listed
+// when the SAS URL of the previous snapshot is specified.
+func (c *Client) GetPageRanges(sas, previous, marker string) (list *PageList, err error) {
+	url := sas + "&comp=pagelist&maxresults=" + strconv.Itoa(MaxPageRanges)
+	if marker != "" {
+		url += "&marker=" + liburl.QueryEscape(marker)
+	}
+	header := http.Header{}
+	if previous != "" {
+		header.Set("x-ms-previous-snapshot-url", previous)
+	}
+	response, err := c.sendBlob(url, header)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+	list = &PageList{}
+	err = xml.NewDecoder(response.Body).Decode(list)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+	return
+}
+
+// Read a range of a snapshot (page blob).
+func (c *Client) ReadRange(sas string, offset, length int64) (data []byte, err error) {
+	header := http.Header{}
+	header.Set("x-ms-range", fmt.Sprintf("bytes=%d-%d", offset, offset+length-1))
+	response, err := c.sendBlob(sas, header)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+	data, err = io.ReadAll(response.Body)
+	if err != nil {
+		err = liberr.Wrap(err, "offset", offset)
+		return
+	}
+	if int64(len(data)) != length {
+		err = liberr.New(
+			"short read.",
+			"offset",
+			offset,
+			"expected",
+			length,
+			"read",
+			len(data))
+	}
+	return
+}
+
+// The subscription (path).
+func (c *Client) subscription() string {
+	return "/subscriptions/" + liburl.PathEscape(c.Options[SubscriptionID])
+}
+
+// The resource manager endpoint.
+func (c *Client) endpoint() string {
+	url := strings.TrimSuffix(c.URL, "/")
+	if url == "" {
+		url = DefaultURL
+	}
+	return url
+}
+
+// Build the URL of a resource (path).
+func (c *Client) resourceURL(path, version string, query liburl.Values) string {
+	if query == nil {
+		query = liburl.Values{}
+	}
+	query.Set("api-version", version)
+	return c.endpoint() + path + "?" + query.Encode()
+}
+
+// Get a resource.
+func (c *Client) get(path, version string, query liburl.Values, out interface{}) (err error) {
+	_, err = c.send(http.MethodGet, c.resourceURL(path, version, query), nil, out)
+	return
+}
+
+// List a (paged) collection.
+// The next pages are listed using the (reported) next link.
+func (c *Client) list(path, version string, query liburl.Values, fn func(json.RawMessage) error) (err error) {
+	url := c.resourceURL(path, version, query)
+	for url != "" {
+		reply := struct {
+			Value    []json.RawMessage `json:"value"`
+			NextLink string            `json:"nextLink"`
+		}{}
+		_, err = c.send(http.MethodGet, url, nil, &reply)
+		if err != nil {
+			return
+		}
+		for _, content := range reply.Value {
+			err = fn(content)
+			if err != nil {
+				err = liberr.Wrap(err, "url", url)
+				return
+			}
+		}
+		url = reply.NextLink
+	}
+	return
+}
+
+// Poll a long-running operation.
+// The result is decoded into the output when completed.
+func (c *Client) poll(header http.Header, out interface{}) (err error) {
+	url := header.Get("Location")
+	if url == "" {
+		url = header.Get("Azure-AsyncOperation")
+	}
+	if url == "" {
+		return
+	}
+	deadline := time.Now().Add(PollTimeout)
+	for {
+		time.Sleep(c.retryAfter(header))
+		request, nErr := http.NewRequest(http.MethodGet, url, nil)
+		if nErr != nil {
+			err = liberr.Wrap(nErr)
+			return
+		}
+		var response *http.Response
+		response, err = c.do(request, true)
+		if err != nil {
+			return
+		}
+		header = response.Header
+		status := response.StatusCode
+		content, _ := io.ReadAll(response.Body)
+		_ = response.Body.Close()
+		if status != http.StatusAccepted {
+			if out != nil && len(content) > 0 {
+				err = json.Unmarshal(content, out)
+				if err != nil {
+					err = liberr.Wrap(err, "url", url)
+				}
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			err = liberr.New("operation timed out.", "url", url)
+			return
+		}
+	}
+}
+
+// The (reported) interval before polling again.
+func (c *Client) retryAfter(header http.Header) (interval time.Duration) {
+	interval = PollInterval
+	if seconds, err := strconv.Atoi(header.Get("Retry-After")); err == nil && seconds > 0 {
+		interval = time.Duration(seconds) * time.Second
+	}
+	return
+}
+
+// Send a resource manager request.
+// The (JSON) reply is decoded into the output.
+func (c *Client) send(method, url string, in, out interface{}) (header http.Header, err error) {
+	var body []byte
+	if in != nil {
+		body, err = json.Marshal(in)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+	request, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if in != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	response, err := c.do(request, true)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+	header = response.Header
+	if out == nil || response.StatusCode == http.StatusNoContent {
+		return
+	}
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		err = liberr.Wrap(err, "url", url)
+		return
+	}
+	if len(content) == 0 {
+		return
+	}
+	err = json.Unmarshal(content, out)
+	if err != nil {
+		err = liberr.Wrap(err, "url", url)
+	}
+	return
+}
+
+// Send a (SAS authorized) page blob request.
+func (c *Client) sendBlob(url string, header http.Header) (response *http.Response, err error) {
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for key := range header {
+		request.Header.Set(key, header.Get(key))
+	}
+	request.Header.Set("x-ms-version", BlobAPIVersion)
+	response, err = c.do(request, false)
+	return
+}
+
+// Do the request.
+// Resource manager requests are authorized with the access token.
+// Returns an error on (http) error status.
+func (c *Client) do(request *http.Request, authorize bool) (response *http.Response, err error) {
+	c.build()
+	if authorize {
+		var token string
+		token, err = c.accessToken()
+		if err != nil {
+			return
+		}
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+	url := request.URL.Redacted()
+	if !authorize {
+		// The SAS (query) is a credential.
+		url = request.URL.Scheme + "://" + request.URL.Host + request.URL.Path
+	}
+	response, err = c.client.Do(request)
+	if err != nil {
+		err = liberr.Wrap(err, "url", url)
+		return
+	}
+	if response.StatusCode < http.StatusBadRequest {
+		return
+	}
+	content, _ := io.ReadAll(response.Body)
+	_ = response.Body.Close()
+	apiErr := &Error{
+		Status:  response.StatusCode,
+		Message: string(content),
+	}
+	reply := errorReply{}
+	blobReply := blobErrorReply{}
+	if json.Unmarshal(content, &reply) == nil && reply.Error.Code != "" {
+		apiErr.Code = reply.Error.Code
+		apiErr.Message = reply.Error.Message
+	} else if xml.Unmarshal(content, &blobReply) == nil && blobReply.Code != "" {
+		apiErr.Code = blobReply.Code
+		apiErr.Message = blobReply.Message
+	}
+	err = liberr.Wrap(apiErr, "url", url)
+	response = nil
+	return
+}
+
+// Get the access token.
+// The token is requested (client credentials) when expired.
+func (c *Client) accessToken() (token string, err error) {
+	c.token.mutex.Lock()
+	defer c.token.mutex.Unlock()
+	if c.token.value != "" && time.Now().Before(c.token.expires) {
+		token = c.token.value
+		return
+	}
+	authority := c.Options[AuthorityHost]
+	if authority == "" {
+		authority = DefaultAuthorityHost
+	}
+	url := strings.TrimSuffix(authority, "/") + "/" + liburl.PathEscape(c.Options[TenantID]) + "/oauth2/v2.0/token"
+	form := liburl.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", c.Options[ClientID])
+	form.Set("client_secret", c.Options[ClientSecret])
+	form.Set("scope", c.endpoint()+"/.default")
+	request, err := http.NewRequest(http.MethodPost, url, strings.NewReader(form.Encode()))
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	response, err := c.client.Do(request)
+	if err != nil {
+		err = liberr.Wrap(err, "url", url)
+		return
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+	reply := struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+		Error       string `json:"error"`
+		Description string `json:"error_description"`
+	}{}
+	err = json.NewDecoder(response.Body).Decode(&reply)
+	if err != nil || response.StatusCode != http.StatusOK {
+		if err == nil {
+			err = &Error{
+				Status:  response.StatusCode,
+				Code:    reply.Error,
+				Message: reply.Description,
+			}
+		}
+		err = liberr.Wrap(err, "url", url)
+		return
+	}
+	c.token.value = reply.AccessToken
+	// Renewed a minute before it expires.
+	c.token.expires = time.Now().Add(time.Duration(reply.ExpiresIn-60) * time.Second)
+	token = c.token.value
+	return
+}
+
+// Build the http client.
+func (c *Client) build() {
+	if c.client != nil {
+		return
+	}
+	proxy := c.Proxy
+	if proxy == nil {
+		proxy = http.ProxyFromEnvironment
+	}
+	dial := c.Dial
+	if dial == nil {
+		dial = (&net.Dialer{
+			Timeout:   10 * time.Second,
+			KeepAlive: 10 * time.Second,
+		}).DialContext
+	}
+	c.client = &http.Client{
+		Timeout: 2 * time.Minute,
+		Transport: &http.Transport{
+			Proxy:                 proxy,
+			DialContext:           dial,
+			MaxIdleConns:          10,
+			MaxIdleConnsPerHost:   10,
+			IdleConnTimeout:       10 * time.Second,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ExpectContinueTimeout: 1 * time.Second,
+		},
+	}
+}
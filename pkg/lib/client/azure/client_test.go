@@ -0,0 +1,73 @@
+package azure
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResourceID(t *testing.T) {
+	id := "/subscriptions/sub-1/resourceGroups/Group-1/providers/Microsoft.Compute/disks/disk-1"
+	if ResourceGroupOf(id) != "Group-1" {
+		t.Errorf("unexpected resource group: %s", ResourceGroupOf(id))
+	}
+	if NameOf(id) != "disk-1" {
+		t.Errorf("unexpected name: %s", NameOf(id))
+	}
+	// Resource IDs are not case sensitive.
+	if ID(id) != ID("/subscriptions/sub-1/resourcegroups/group-1/providers/microsoft.compute/disks/disk-1") {
+		t.Errorf("expected the same ID")
+	}
+	if ID(id) == ID(id+"0") {
+		t.Errorf("expected a different ID")
+	}
+}
+
+// The token is requested once and the pages are listed using the next link.
+func TestListResourceGroups(t *testing.T) {
+	tokens := 0
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	mux.HandleFunc("/tenant-1/oauth2/v2.0/token", func(w http.ResponseWriter, r *http.Request) {
+		tokens++
+		_ = r.ParseForm()
+		if r.Form.Get("scope") != server.URL+"/.default" {
+			t.Errorf("unexpected scope: %s", r.Form.Get("scope"))
+		}
+		_, _ = w.Write([]byte(`{"access_token":"token-1","expires_in":3600}`))
+	})
+	mux.HandleFunc("/subscriptions/sub-1/resourcegroups", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		reply := map[string]interface{}{}
+		if r.URL.Query().Get("page") == "" {
+			reply["value"] = []map[string]string{{"name": "group-1"}}
+			reply["nextLink"] = server.URL + r.URL.Path + "?page=2&api-version=" + ResourceAPIVersion
+		} else {
+			reply["value"] = []map[string]string{{"name": "group-2"}}
+		}
+		_ = json.NewEncoder(w).Encode(reply)
+	})
+	client := &Client{
+		URL: server.URL,
+		Options: map[string]string{
+			TenantID:       "tenant-1",
+			SubscriptionID: "sub-1",
+			AuthorityHost:  server.URL,
+		},
+	}
+	list, err := client.ListResourceGroups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].Name != "group-1" || list[1].Name != "group-2" {
+		t.Errorf("unexpected list: %v", list)
+	}
+	if tokens != 1 {
+		t.Errorf("expected one token request, got %d", tokens)
+	}
+}
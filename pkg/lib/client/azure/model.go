@@ -0,0 +1,284 @@
+package azure
+
+import (
+	"strings"
+)
+
+// VM power states (instance view).
+const (
+	PowerStateStarting     = "starting"
+	PowerStateRunning      = "running"
+	PowerStateStopping     = "stopping"
+	PowerStateStopped      = "stopped"
+	PowerStateDeallocating = "deallocating"
+	PowerStateDeallocated  = "deallocated"
+)
+
+// Provisioning states.
+const (
+	ProvisioningSucceeded = "Succeeded"
+	ProvisioningFailed    = "Failed"
+)
+
+// Disk SKUs.
+// The (managed) disk SKUs are the storage map sources.
+var DiskSkus = []string{
+	"Standard_LRS",
+	"StandardSSD_LRS",
+	"StandardSSD_ZRS",
+	"Premium_LRS",
+	"Premium_ZRS",
+	"PremiumV2_LRS",
+	"UltraSSD_LRS",
+}
+
+// Reference to a (sub) resource.
+type SubResource struct {
+	ID string `json:"id"`
+}
+
+// Resource group.
+type ResourceGroup struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Location   string `json:"location"`
+	Properties struct {
+		ProvisioningState string `json:"provisioningState"`
+	} `json:"properties"`
+	Tags map[string]string `json:"tags"`
+}
+
+// Virtual network.
+type VirtualNetwork struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Location   string `json:"location"`
+	Properties struct {
+		Subnets []Subnet `json:"subnets"`
+	} `json:"properties"`
+}
+
+// Virtual network subnet.
+type Subnet struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Properties struct {
+		AddressPrefix     string   `json:"addressPrefix"`
+		AddressPrefixes   []string `json:"addressPrefixes"`
+		ProvisioningState string   `json:"provisioningState"`
+	} `json:"properties"`
+}
+
+// The (first) address prefix.
+func (r *Subnet) CIDR() (cidr string) {
+	cidr = r.Properties.AddressPrefix
+	if cidr == "" && len(r.Properties.AddressPrefixes) > 0 {
+		cidr = r.Properties.AddressPrefixes[0]
+	}
+	return
+}
+
+// Network interface.
+type NetworkInterface struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Location   string `json:"location"`
+	Properties struct {
+		MacAddress       string       `json:"macAddress"`
+		Primary          bool         `json:"primary"`
+		VirtualMachine   *SubResource `json:"virtualMachine"`
+		IPConfigurations []struct {
+			Name       string `json:"name"`
+			Properties struct {
+				PrivateIPAddress string       `json:"privateIPAddress"`
+				Primary          bool         `json:"primary"`
+				Subnet           *SubResource `json:"subnet"`
+			} `json:"properties"`
+		} `json:"ipConfigurations"`
+	} `json:"properties"`
+}
+
+// The MAC address.
+// Azure reports the address as 00-0D-3A-XX-XX-XX.
+func (r *NetworkInterface) MAC() string {
+	return strings.ToLower(strings.ReplaceAll(r.Properties.MacAddress, "-", ":"))
+}
+
+// Managed disk.
+type Disk struct {
+	ID        string            `json:"id"`
+	Name      string            `json:"name"`
+	Location  string            `json:"location"`
+	ManagedBy string            `json:"managedBy"`
+	Zones     []string          `json:"zones"`
+	Tags      map[string]string `json:"tags"`
+	Sku       struct {
+		Name string `json:"name"`
+	} `json:"sku"`
+	Properties struct {
+		DiskSizeGB            int64  `json:"diskSizeGB"`
+		DiskSizeBytes         int64  `json:"diskSizeBytes"`
+		DiskState             string `json:"diskState"`
+		OSType                string `json:"osType"`
+		HyperVGeneration      string `json:"hyperVGeneration"`
+		ProvisioningState     string `json:"provisioningState"`
+		SupportedCapabilities *struct {
+			Architecture string `json:"architecture"`
+		} `json:"supportedCapabilities"`
+		Encryption struct {
+			Type string `json:"type"`
+		} `json:"encryption"`
+	} `json:"properties"`
+}
+
+// VM managed disk reference.
+type ManagedDisk struct {
+	ID                 string `json:"id"`
+	StorageAccountType string `json:"storageAccountType"`
+}
+
+// VM (storage profile) disk.
+type VMDisk struct {
+	Lun         int          `json:"lun"`
+	Name        string       `json:"name"`
+	OSType      string       `json:"osType"`
+	DiskSizeGB  int64        `json:"diskSizeGB"`
+	ManagedDisk *ManagedDisk `json:"managedDisk"`
+	// Unmanaged (storage account) disk.
+	Vhd *struct {
+		URI string `json:"uri"`
+	} `json:"vhd"`
+}
+
+// VM instance view.
+type InstanceView struct {
+	OSName           string `json:"osName"`
+	OSVersion        string `json:"osVersion"`
+	HyperVGeneration string `json:"hyperVGeneration"`
+	Statuses         []struct {
+		Code          string `json:"code"`
+		DisplayStatus string `json:"displayStatus"`
+	} `json:"statuses"`
+}
+
+// The power state. Example: running.
+func (r *InstanceView) PowerState() (state string) {
+	for _, status := range r.Statuses {
+		if strings.HasPrefix(status.Code, "PowerState/") {
+			state = strings.TrimPrefix(status.Code, "PowerState/")
+			return
+		}
+	}
+	return
+}
+
+// Virtual machine.
+type VirtualMachine struct {
+	ID         string            `json:"id"`
+	Name       string            `json:"name"`
+	Location   string            `json:"location"`
+	Zones      []string          `json:"zones"`
+	Tags       map[string]string `json:"tags"`
+	Properties struct {
+		VMID              string `json:"vmId"`
+		ProvisioningState string `json:"provisioningState"`
+		HardwareProfile   struct {
+			VMSize string `json:"vmSize"`
+		} `json:"hardwareProfile"`
+		StorageProfile struct {
+			OSDisk    VMDisk   `json:"osDisk"`
+			DataDisks []VMDisk `json:"dataDisks"`
+		} `json:"storageProfile"`
+		NetworkProfile struct {
+			NetworkInterfaces []struct {
+				ID         string `json:"id"`
+				Properties struct {
+					Primary bool `json:"primary"`
+				} `json:"properties"`
+			} `json:"networkInterfaces"`
+		} `json:"networkProfile"`
+		SecurityProfile *struct {
+			SecurityType string `json:"securityType"`
+			UefiSettings *struct {
+				SecureBootEnabled bool `json:"secureBootEnabled"`
+				VTpmEnabled       bool `json:"vTpmEnabled"`
+			} `json:"uefiSettings"`
+		} `json:"securityProfile"`
+		InstanceView *InstanceView `json:"instanceView"`
+	} `json:"properties"`
+}
+
+// The power state.
+// Unknown (empty) when the instance view is not reported.
+func (r *VirtualMachine) PowerState() (state string) {
+	if r.Properties.InstanceView != nil {
+		state = r.Properties.InstanceView.PowerState()
+	}
+	return
+}
+
+// VM size.
+type VMSize struct {
+	Name          string `json:"name"`
+	NumberOfCores int32  `json:"numberOfCores"`
+	MemoryInMB    int64  `json:"memoryInMB"`
+}
+
+// (Incremental) snapshot.
+type Snapshot struct {
+	ID         string            `json:"id,omitempty"`
+	Name       string            `json:"name,omitempty"`
+	Location   string            `json:"location"`
+	Tags       map[string]string `json:"tags,omitempty"`
+	Properties struct {
+		CreationData struct {
+			CreateOption     string `json:"createOption"`
+			SourceResourceID string `json:"sourceResourceId"`
+		} `json:"creationData"`
+		Incremental       bool     `json:"incremental"`
+		DiskSizeBytes     int64    `json:"diskSizeBytes,omitempty"`
+		ProvisioningState string   `json:"provisioningState,omitempty"`
+		CompletionPercent *float64 `json:"completionPercent,omitempty"`
+	} `json:"properties"`
+}
+
+// The snapshot is ready to be read.
+// Incremental snapshots are copied in the background
+// after they have been provisioned.
+func (r *Snapshot) Ready() bool {
+	return r.Properties.ProvisioningState == ProvisioningSucceeded &&
+		(r.Properties.CompletionPercent == nil || *r.Properties.CompletionPercent >= 100)
+}
+
+// Page (blob) range.
+type PageRange struct {
+	Start int64 `xml:"Start"`
+	End   int64 `xml:"End"`
+}
+
+// Length (bytes).
+func (r *PageRange) Length() int64 {
+	return r.End - r.Start + 1
+}
+
+// Page (blob) ranges.
+// The cleared ranges are only listed by a diff.
+type PageList struct {
+	PageRanges  []PageRange `xml:"PageRange"`
+	ClearRanges []PageRange `xml:"ClearRange"`
+	NextMarker  string      `xml:"NextMarker"`
+}
+
+// API (reply) error.
+type errorReply struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Blob API (reply) error.
+type blobErrorReply struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
@@ -0,0 +1,527 @@
+package ec2
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	liburl "net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+	core "k8s.io/api/core/v1"
+)
+
+// Secret keys.
+const (
+	AccessKeyID     = "accessKeyId"
+	SecretAccessKey = "secretAccessKey"
+	SessionToken    = "sessionToken"
+	Region          = "region"
+)
+
+// Services.
+const (
+	EC2Service = "ec2"
+	EBSService = "ebs"
+)
+
+// EC2 (query) API version.
+const APIVersion = "2016-11-15"
+
+// Page size of listed snapshot blocks.
+const MaxBlocks = 10000
+
+// Max instance types described by a request.
+const MaxInstanceTypes = 100
+
+// Tag set on the snapshots created for the migration.
+const SnapshotTag = "forklift.konveyor.io/snapshot"
+
+// API (reply) error.
+type Error struct {
+	// HTTP status.
+	Status int
+	// Reported code.
+	Code string
+	// Reported message.
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("ec2: %d %s %s", e.Status, e.Code, e.Message)
+}
+
+// EC2 (and EBS direct) API client.
+type Client struct {
+	// EC2 (regional) endpoint. Example: https://ec2.us-east-1.amazonaws.com
+	URL     string
+	Options map[string]string
+	Log     logging.LevelLogger
+	// Proxy. The environment proxy when nil.
+	Proxy func(*http.Request) (*liburl.URL, error)
+	// Dial. The default dialer (any address family) when nil.
+	Dial   func(context.Context, string, string) (net.Conn, error)
+	client *http.Client
+}
+
+// Load the options from the provider secret.
+func (c *Client) LoadOptionsFromSecret(secret *core.Secret) {
+	c.Options = make(map[string]string)
+	for key, value := range secret.Data {
+		c.Options[key] = string(value)
+	}
+}
+
+// Connect.
+// Validates the URL and the credentials.
+func (c *Client) Connect() (err error) {
+	err = c.query("DescribeAvailabilityZones", liburl.Values{}, nil)
+	return
+}
+
+// The region.
+// The region option, otherwise parsed from the URL.
+// Example: https://ec2.us-east-1.amazonaws.com
+func (c *Client) Region() (region string) {
+	region = c.Options[Region]
+	if region != "" {
+		return
+	}
+	url, err := liburl.Parse(c.URL)
+	if err != nil {
+		return
+	}
+	part := strings.Split(url.Hostname(), ".")
+	if len(part) > 2 && part[0] == EC2Service {
+		region = part[1]
+	}
+	return
+}
+
+// The EBS (direct API) endpoint in the region of the EC2 endpoint.
+func (c *Client) EBSURL() (url string) {
+	parsed, err := liburl.Parse(c.URL)
+	if err != nil || !strings.HasPrefix(parsed.Host, EC2Service+".") {
+		url = "https://" + EBSService + "." + c.Region() + ".amazonaws.com"
+		return
+	}
+	parsed.Host = EBSService + strings.TrimPrefix(parsed.Host, EC2Service)
+	parsed.Path = ""
+	url = parsed.String()
+	return
+}
+
+// Build the http client.
+func (c *Client) build() {
+	if c.client != nil {
+		return
+	}
+	proxy := c.Proxy
+	if proxy == nil {
+		proxy = http.ProxyFromEnvironment
+	}
+	dial := c.Dial
+	if dial == nil {
+		dial = (&net.Dialer{
+			Timeout:   10 * time.Second,
+			KeepAlive: 10 * time.Second,
+		}).DialContext
+	}
+	c.client = &http.Client{
+		Timeout: 2 * time.Minute,
+		Transport: &http.Transport{
+			Proxy:                 proxy,
+			DialContext:           dial,
+			MaxIdleConns:          10,
+			IdleConnTimeout:       10 * time.Second,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ExpectContinueTimeout: 1 * time.Second,
+		},
+	}
+}
+
+// The request signer.
+func (c *Client) signer() *Signer {
+	return &Signer{
+		AccessKeyID:     c.Options[AccessKeyID],
+		SecretAccessKey: c.Options[SecretAccessKey],
+		SessionToken:    c.Options[SessionToken],
+		Region:          c.Region(),
+	}
+}
+
+// The error is not found.
+func (c *Client) IsNotFound(err error) bool {
+	if apiErr, cast := liberr.Unwrap(err).(*Error); cast {
+		return apiErr.Status == http.StatusNotFound ||
+			strings.HasSuffix(apiErr.Code, ".NotFound")
+	}
+	return false
+}
+
+// The error is unauthorized.
+func (c *Client) IsUnauthorized(err error) bool {
+	if apiErr, cast := liberr.Unwrap(err).(*Error); cast {
+		switch apiErr.Code {
+		case "AuthFailure", "UnauthorizedOperation", "InvalidClientTokenId", "SignatureDoesNotMatch":
+			return true
+		}
+		return apiErr.Status == http.StatusUnauthorized ||
+			apiErr.Status == http.StatusForbidden
+	}
+	return false
+}
+
+// List subnets.
+func (c *Client) ListSubnets() (list []Subnet, err error) {
+	params := liburl.Values{}
+	for {
+		reply := struct {
+			Subnets   []Subnet `xml:"subnetSet>item"`
+			NextToken string   `xml:"nextToken"`
+		}{}
+		err = c.query("DescribeSubnets", params, &reply)
+		if err != nil {
+			return
+		}
+		list = append(list, reply.Subnets...)
+		if reply.NextToken == "" {
+			break
+		}
+		params.Set("NextToken", reply.NextToken)
+	}
+	return
+}
+
+// List volumes.
+func (c *Client) ListVolumes() (list []Volume, err error) {
+	params := liburl.Values{}
+	for {
+		reply := struct {
+			Volumes   []Volume `xml:"volumeSet>item"`
+			NextToken string   `xml:"nextToken"`
+		}{}
+		err = c.query("DescribeVolumes", params, &reply)
+		if err != nil {
+			return
+		}
+		list = append(list, reply.Volumes...)
+		if reply.NextToken == "" {
+			break
+		}
+		params.Set("NextToken", reply.NextToken)
+	}
+	return
+}
+
+// List instances.
+// Terminated instances are not listed.
+func (c *Client) ListInstances() (list []Instance, err error) {
+	err = c.describeInstances(liburl.Values{}, func(instance *Instance) {
+		if instance.State.Name != InstanceTerminated {
+			list = append(list, *instance)
+		}
+	})
+	return
+}
+
+// Get an instance.
+func (c *Client) GetInstance(id string) (instance *Instance, err error) {
+	params := liburl.Values{}
+	params.Set("InstanceId.1", id)
+	err = c.describeInstances(params, func(found *Instance) {
+		instance = found
+	})
+	if err != nil {
+		return
+	}
+	if instance == nil {
+		err = liberr.Wrap(
+			&Error{
+				Status:  http.StatusNotFound,
+				Code:    "InvalidInstanceID.NotFound",
+				Message: id,
+			})
+	}
+	return
+}
+
+// List the (described) instance types.
+// The types are described in chunks of MaxInstanceTypes.
+func (c *Client) ListInstanceTypes(names []string) (list []InstanceType, err error) {
+	for len(names) > 0 {
+		chunk := names
+		if len(chunk) > MaxInstanceTypes {
+			chunk = chunk[:MaxInstanceTypes]
+		}
+		names = names[len(chunk):]
+		params := liburl.Values{}
+		for i, name := range chunk {
+			params.Set("InstanceType."+strconv.Itoa(i+1), name)
+		}
+		for {
+			reply := struct {
+				InstanceTypes []InstanceType `xml:"instanceTypeSet>item"`
+				NextToken     string         `xml:"nextToken"`
+			}{}
+			err = c.query("DescribeInstanceTypes", params, &reply)
+			if err != nil {
+				return
+			}
+			list = append(list, reply.InstanceTypes...)
+			if reply.NextToken == "" {
+				break
+			}
+			params.Set("NextToken", reply.NextToken)
+		}
+	}
+	return
+}
+
+// Start an instance.
+func (c *Client) StartInstance(id string) (err error) {
+	params := liburl.Values{}
+	params.Set("InstanceId.1", id)
+	err = c.query("StartInstances", params, nil)
+	return
+}
+
+// Stop an instance.
+func (c *Client) StopInstance(id string) (err error) {
+	params := liburl.Values{}
+	params.Set("InstanceId.1", id)
+	err = c.query("StopInstances", params, nil)
+	return
+}
+
+// Create a snapshot of a volume.
+// The snapshot is tagged with the (migration) snapshot tag.
+// Returns the snapshot ID.
+func (c *Client) CreateSnapshot(volumeID string, tag string) (id string, err error) {
+	params := liburl.Values{}
+	params.Set("VolumeId", volumeID)
+	params.Set("Description", "Created by Forklift.")
+	params.Set("TagSpecification.1.ResourceType", "snapshot")
+	params.Set("TagSpecification.1.Tag.1.Key", SnapshotTag)
+	params.Set("TagSpecification.1.Tag.1.Value", tag)
+	reply := struct {
+		ID string `xml:"snapshotId"`
+	}{}
+	err = c.query("CreateSnapshot", params, &reply)
+	if err != nil {
+		return
+	}
+	id = reply.ID
+	return
+}
+
+// Find the snapshots with the (migration) snapshot tag.
+func (c *Client) FindSnapshots(tag string) (list []Snapshot, err error) {
+	params := liburl.Values{}
+	params.Set("Owner.1", "self")
+	params.Set("Filter.1.Name", "tag:"+SnapshotTag)
+	params.Set("Filter.1.Value.1", tag)
+	for {
+		reply := struct {
+			Snapshots []Snapshot `xml:"snapshotSet>item"`
+			NextToken string     `xml:"nextToken"`
+		}{}
+		err = c.query("DescribeSnapshots", params, &reply)
+		if err != nil {
+			return
+		}
+		list = append(list, reply.Snapshots...)
+		if reply.NextToken == "" {
+			break
+		}
+		params.Set("NextToken", reply.NextToken)
+	}
+	return
+}
+
+// Delete a snapshot.
+func (c *Client) DeleteSnapshot(id string) (err error) {
+	params := liburl.Values{}
+	params.Set("SnapshotId", id)
+	err = c.query("DeleteSnapshot", params, nil)
+	if c.IsNotFound(err) {
+		err = nil
+	}
+	return
+}
+
+// List (a page of) the (allocated) blocks of a snapshot.
+// The first page is listed when the token is empty.
+func (c *Client) ListSnapshotBlocks(snapshotID string, token string) (blocks *Blocks, err error) {
+	query := liburl.Values{}
+	query.Set("maxResults", strconv.Itoa(MaxBlocks))
+	if token != "" {
+		query.Set("pageToken", token)
+	}
+	url := c.EBSURL() + "/snapshots/" + liburl.PathEscape(snapshotID) + "/blocks?" + query.Encode()
+	response, err := c.send(http.MethodGet, url, EBSService, "", nil)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+	blocks = &Blocks{}
+	err = json.NewDecoder(response.Body).Decode(blocks)
+	if err != nil {
+		err = liberr.Wrap(err, "snapshot", snapshotID)
+	}
+	return
+}
+
+// Read the data of a snapshot block.
+// The data is verified using the reported checksum.
+func (c *Client) ReadSnapshotBlock(snapshotID string, block Block) (data []byte, err error) {
+	query := liburl.Values{}
+	query.Set("blockToken", block.Token)
+	url := fmt.Sprintf(
+		"%s/snapshots/%s/blocks/%d?%s",
+		c.EBSURL(),
+		liburl.PathEscape(snapshotID),
+		block.Index,
+		query.Encode())
+	response, err := c.send(http.MethodGet, url, EBSService, "", nil)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+	data, err = io.ReadAll(response.Body)
+	if err != nil {
+		err = liberr.Wrap(err, "snapshot", snapshotID, "block", block.Index)
+		return
+	}
+	if checksum := response.Header.Get("x-amz-Checksum"); checksum != "" {
+		sum := sha256.Sum256(data)
+		if base64.StdEncoding.EncodeToString(sum[:]) != checksum {
+			err = liberr.New(
+				"block checksum not matched.",
+				"snapshot",
+				snapshotID,
+				"block",
+				block.Index)
+		}
+	}
+	return
+}
+
+// Describe instances.
+// All pages are listed.
+func (c *Client) describeInstances(params liburl.Values, fn func(*Instance)) (err error) {
+	for {
+		reply := struct {
+			Reservations []struct {
+				Instances []Instance `xml:"instancesSet>item"`
+			} `xml:"reservationSet>item"`
+			NextToken string `xml:"nextToken"`
+		}{}
+		err = c.query("DescribeInstances", params, &reply)
+		if err != nil {
+			return
+		}
+		for i := range reply.Reservations {
+			for j := range reply.Reservations[i].Instances {
+				fn(&reply.Reservations[i].Instances[j])
+			}
+		}
+		if reply.NextToken == "" {
+			break
+		}
+		params.Set("NextToken", reply.NextToken)
+	}
+	return
+}
+
+// Send an EC2 (query) API request.
+// The (XML) reply is decoded into the output.
+func (c *Client) query(action string, params liburl.Values, out interface{}) (err error) {
+	body := liburl.Values{}
+	for key, values := range params {
+		body[key] = values
+	}
+	body.Set("Action", action)
+	body.Set("Version", APIVersion)
+	response, err := c.send(
+		http.MethodPost,
+		strings.TrimSuffix(c.URL, "/")+"/",
+		EC2Service,
+		"application/x-www-form-urlencoded; charset=utf-8",
+		[]byte(body.Encode()))
+	if err != nil {
+		err = liberr.Wrap(err, "action", action)
+		return
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+	if out == nil {
+		return
+	}
+	err = xml.NewDecoder(response.Body).Decode(out)
+	if err != nil {
+		err = liberr.Wrap(err, "action", action)
+	}
+	return
+}
+
+// Send the (signed) request.
+// Returns an error on (http) error status.
+func (c *Client) send(method, url, service, contentType string, body []byte) (response *http.Response, err error) {
+	c.build()
+	request, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+	c.signer().Sign(request, service, body, time.Now())
+	response, err = c.client.Do(request)
+	if err != nil {
+		err = liberr.Wrap(err, "url", url)
+		return
+	}
+	if response.StatusCode < http.StatusBadRequest {
+		return
+	}
+	content, _ := io.ReadAll(response.Body)
+	_ = response.Body.Close()
+	apiErr := &Error{
+		Status:  response.StatusCode,
+		Message: string(content),
+	}
+	reply := errorReply{}
+	if xml.Unmarshal(content, &reply) == nil && len(reply.Errors) > 0 {
+		apiErr.Code = reply.Errors[0].Code
+		apiErr.Message = reply.Errors[0].Message
+	} else {
+		jReply := struct {
+			Message string `json:"Message"`
+			Reason  string `json:"Reason"`
+		}{}
+		if json.Unmarshal(content, &jReply) == nil && jReply.Message != "" {
+			apiErr.Code = jReply.Reason
+			apiErr.Message = jReply.Message
+		}
+	}
+	err = liberr.Wrap(apiErr, "url", url)
+	response = nil
+	return
+}
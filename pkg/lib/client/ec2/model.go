@@ -0,0 +1,190 @@
+package ec2
+
+// Instance states.
+const (
+	InstancePending      = "pending"
+	InstanceRunning      = "running"
+	InstanceShuttingDown = "shutting-down"
+	InstanceTerminated   = "terminated"
+	InstanceStopping     = "stopping"
+	InstanceStopped      = "stopped"
+)
+
+// Snapshot states.
+const (
+	SnapshotPending   = "pending"
+	SnapshotCompleted = "completed"
+	SnapshotError     = "error"
+)
+
+// Volume types.
+// The (EBS) volume types are the storage map sources.
+var VolumeTypes = []string{
+	"standard",
+	"gp2",
+	"gp3",
+	"io1",
+	"io2",
+	"st1",
+	"sc1",
+}
+
+// Tag.
+type Tag struct {
+	Key   string `xml:"key"`
+	Value string `xml:"value"`
+}
+
+// Tags.
+type Tags []Tag
+
+// Get the value of a tag.
+func (r Tags) Get(key string) (value string, found bool) {
+	for _, tag := range r {
+		if tag.Key == key {
+			value = tag.Value
+			found = true
+			return
+		}
+	}
+	return
+}
+
+// The (Name tag) name.
+func (r Tags) Name() (name string) {
+	name, _ = r.Get("Name")
+	return
+}
+
+// Subnet.
+type Subnet struct {
+	ID               string `xml:"subnetId"`
+	VPC              string `xml:"vpcId"`
+	CIDR             string `xml:"cidrBlock"`
+	AvailabilityZone string `xml:"availabilityZone"`
+	State            string `xml:"state"`
+	DefaultForAz     bool   `xml:"defaultForAz"`
+	Tags             Tags   `xml:"tagSet>item"`
+}
+
+// Volume attachment.
+type VolumeAttachment struct {
+	Instance string `xml:"instanceId"`
+	Device   string `xml:"device"`
+	State    string `xml:"status"`
+}
+
+// Volume.
+type Volume struct {
+	ID               string             `xml:"volumeId"`
+	Size             int64              `xml:"size"`
+	VolumeType       string             `xml:"volumeType"`
+	IOPS             int64              `xml:"iops"`
+	AvailabilityZone string             `xml:"availabilityZone"`
+	State            string             `xml:"status"`
+	Encrypted        bool               `xml:"encrypted"`
+	Attachments      []VolumeAttachment `xml:"attachmentSet>item"`
+	Tags             Tags               `xml:"tagSet>item"`
+}
+
+// Instance block device.
+type BlockDevice struct {
+	DeviceName string `xml:"deviceName"`
+	EBS        struct {
+		Volume string `xml:"volumeId"`
+		State  string `xml:"status"`
+	} `xml:"ebs"`
+}
+
+// Instance network interface.
+type NetworkInterface struct {
+	ID         string `xml:"networkInterfaceId"`
+	Subnet     string `xml:"subnetId"`
+	VPC        string `xml:"vpcId"`
+	MAC        string `xml:"macAddress"`
+	PrivateIP  string `xml:"privateIpAddress"`
+	Attachment struct {
+		DeviceIndex int `xml:"deviceIndex"`
+	} `xml:"attachment"`
+	Addresses []struct {
+		PrivateIP string `xml:"privateIpAddress"`
+	} `xml:"privateIpAddressesSet>item"`
+}
+
+// Instance.
+type Instance struct {
+	ID    string `xml:"instanceId"`
+	Image string `xml:"imageId"`
+	State struct {
+		Code int    `xml:"code"`
+		Name string `xml:"name"`
+	} `xml:"instanceState"`
+	InstanceType string `xml:"instanceType"`
+	Placement    struct {
+		AvailabilityZone string `xml:"availabilityZone"`
+	} `xml:"placement"`
+	Platform        string `xml:"platform"`
+	PlatformDetails string `xml:"platformDetails"`
+	Architecture    string `xml:"architecture"`
+	BootMode        string `xml:"bootMode"`
+	CurrentBootMode string `xml:"currentInstanceBootMode"`
+	TPMSupport      string `xml:"tpmSupport"`
+	CPUOptions      struct {
+		CoreCount      int32 `xml:"coreCount"`
+		ThreadsPerCore int32 `xml:"threadsPerCore"`
+	} `xml:"cpuOptions"`
+	RootDeviceName    string             `xml:"rootDeviceName"`
+	VPC               string             `xml:"vpcId"`
+	Subnet            string             `xml:"subnetId"`
+	BlockDevices      []BlockDevice      `xml:"blockDeviceMapping>item"`
+	NetworkInterfaces []NetworkInterface `xml:"networkInterfaceSet>item"`
+	Tags              Tags               `xml:"tagSet>item"`
+}
+
+// Instance type.
+type InstanceType struct {
+	Name  string `xml:"instanceType"`
+	VCPUs struct {
+		Default        int32 `xml:"defaultVCpus"`
+		Cores          int32 `xml:"defaultCores"`
+		ThreadsPerCore int32 `xml:"defaultThreadsPerCore"`
+	} `xml:"vCpuInfo"`
+	Memory struct {
+		SizeMiB int64 `xml:"sizeInMiB"`
+	} `xml:"memoryInfo"`
+}
+
+// Snapshot.
+type Snapshot struct {
+	ID         string `xml:"snapshotId"`
+	Volume     string `xml:"volumeId"`
+	State      string `xml:"status"`
+	Message    string `xml:"statusMessage"`
+	Progress   string `xml:"progress"`
+	VolumeSize int64  `xml:"volumeSize"`
+	Tags       Tags   `xml:"tagSet>item"`
+}
+
+// Snapshot block (EBS direct API).
+type Block struct {
+	Index int64  `json:"BlockIndex"`
+	Token string `json:"BlockToken"`
+}
+
+// Snapshot blocks (page).
+type Blocks struct {
+	Blocks []Block `json:"Blocks"`
+	// Block size (bytes).
+	BlockSize int64 `json:"BlockSize"`
+	// Volume size (GiB).
+	VolumeSize int64  `json:"VolumeSize"`
+	NextToken  string `json:"NextToken"`
+}
+
+// API (reply) error.
+type errorReply struct {
+	Errors []struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	} `xml:"Errors>Error"`
+}
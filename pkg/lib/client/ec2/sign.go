@@ -0,0 +1,155 @@
+package ec2
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	liburl "net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Signature (version 4) algorithm.
+const Algorithm = "AWS4-HMAC-SHA256"
+
+// Signature (version 4) timestamp formats.
+const (
+	amzDateFormat = "20060102T150405Z"
+	dateFormat    = "20060102"
+)
+
+// Request signer.
+// Signs requests using the AWS signature version 4.
+type Signer struct {
+	// Access key ID.
+	AccessKeyID string
+	// Secret access key.
+	SecretAccessKey string
+	// Session token (temporary credentials).
+	SessionToken string
+	// Region.
+	Region string
+}
+
+// Sign the request.
+// The host, content type and x-amz-* headers are signed.
+func (r *Signer) Sign(request *http.Request, service string, payload []byte, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format(amzDateFormat)
+	request.Header.Set("X-Amz-Date", amzDate)
+	if r.SessionToken != "" {
+		request.Header.Set("X-Amz-Security-Token", r.SessionToken)
+	}
+	headers, signed := r.canonicalHeaders(request)
+	canonical := strings.Join(
+		[]string{
+			request.Method,
+			r.canonicalURI(request.URL),
+			r.canonicalQuery(request.URL),
+			headers,
+			signed,
+			r.hash(payload),
+		},
+		"\n")
+	scope := strings.Join(
+		[]string{
+			now.Format(dateFormat),
+			r.Region,
+			service,
+			"aws4_request",
+		},
+		"/")
+	toSign := strings.Join(
+		[]string{
+			Algorithm,
+			amzDate,
+			scope,
+			r.hash([]byte(canonical)),
+		},
+		"\n")
+	key := r.hmac([]byte("AWS4"+r.SecretAccessKey), now.Format(dateFormat))
+	key = r.hmac(key, r.Region)
+	key = r.hmac(key, service)
+	key = r.hmac(key, "aws4_request")
+	signature := hex.EncodeToString(r.hmac(key, toSign))
+	request.Header.Set(
+		"Authorization",
+		Algorithm+" "+
+			"Credential="+r.AccessKeyID+"/"+scope+", "+
+			"SignedHeaders="+signed+", "+
+			"Signature="+signature)
+}
+
+// Canonical (escaped) URI.
+func (r *Signer) canonicalURI(url *liburl.URL) string {
+	path := url.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	return path
+}
+
+// Canonical query string.
+// Sorted by key and (RFC 3986) escaped.
+func (r *Signer) canonicalQuery(url *liburl.URL) string {
+	query := url.Query()
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	params := []string{}
+	for _, key := range keys {
+		values := query[key]
+		sort.Strings(values)
+		for _, value := range values {
+			params = append(params, r.escape(key)+"="+r.escape(value))
+		}
+	}
+	return strings.Join(params, "&")
+}
+
+// Canonical headers and the signed header list.
+func (r *Signer) canonicalHeaders(request *http.Request) (headers string, signed string) {
+	host := request.Host
+	if host == "" {
+		host = request.URL.Host
+	}
+	values := map[string]string{"host": host}
+	for name := range request.Header {
+		key := strings.ToLower(name)
+		if key == "content-type" || strings.HasPrefix(key, "x-amz-") {
+			values[key] = strings.TrimSpace(strings.Join(request.Header.Values(name), ","))
+		}
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		headers += key + ":" + values[key] + "\n"
+	}
+	signed = strings.Join(keys, ";")
+	return
+}
+
+// RFC 3986 escape.
+func (r *Signer) escape(s string) string {
+	return strings.ReplaceAll(liburl.QueryEscape(s), "+", "%20")
+}
+
+// Hex encoded SHA256.
+func (r *Signer) hash(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// HMAC SHA256.
+func (r *Signer) hmac(key []byte, s string) []byte {
+	h := hmac.New(sha256.New, key)
+	_, _ = h.Write([]byte(s))
+	return h.Sum(nil)
+}
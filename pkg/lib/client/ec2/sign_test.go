@@ -0,0 +1,33 @@
+package ec2
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// AWS signature version 4 test suite (get-vanilla and get-vanilla-query-order-key-case).
+func TestSign(t *testing.T) {
+	signer := &Signer{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		Region:          "us-east-1",
+	}
+	now, _ := time.Parse(amzDateFormat, "20150830T123600Z")
+	cases := map[string]string{
+		"https://example.amazonaws.com/":                             "5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		"https://example.amazonaws.com/?Param2=value2&Param1=value1": "b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500",
+	}
+	for url, signature := range cases {
+		request, _ := http.NewRequest(http.MethodGet, url, nil)
+		signer.Sign(request, "service", nil, now)
+		authorization := request.Header.Get("Authorization")
+		if !strings.HasPrefix(authorization, Algorithm+" Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, ") {
+			t.Errorf("unexpected authorization: %s", authorization)
+		}
+		if !strings.HasSuffix(authorization, "Signature="+signature) {
+			t.Errorf("%s: unexpected signature: %s", url, authorization)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package image
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	liburl "net/url"
+	"strings"
+
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/yaml"
+)
+
+// Firmware (hints).
+const (
+	BIOS = "bios"
+	EFI  = "efi"
+)
+
+// Maximum size of the catalog manifest.
+const MaxCatalogSize = 16 << 20
+
+// Maximum number of VMs created from a catalog entry.
+const MaxCount = 1000
+
+// Catalog manifest (YAML or JSON).
+// Example:
+//
+//	images:
+//	- name: web
+//	  image: disks/rhel9.qcow2
+//	  count: 3
+//	  cpu: 2
+//	  memory: 4Gi
+//	  firmware: efi
+//	  os: rhel9
+type Catalog struct {
+	Images []CatalogEntry `json:"images"`
+}
+
+// Catalog entry.
+type CatalogEntry struct {
+	// VM name. The VMs created from an entry with
+	// a count are named <name>-<n>.
+	Name string `json:"name"`
+	// Image URL (or path relative to the catalog).
+	Image string `json:"image"`
+	// Description.
+	Description string `json:"description,omitempty"`
+	// Number of VMs created from the image.
+	Count int `json:"count,omitempty"`
+	// vCPU count (hint).
+	CPU int64 `json:"cpu,omitempty"`
+	// Memory quantity (hint). Example: 4Gi
+	Memory string `json:"memory,omitempty"`
+	// Firmware (hint): bios|efi
+	Firmware string `json:"firmware,omitempty"`
+	// Guest OS (hint). Example: rhel9
+	OS string `json:"os,omitempty"`
+}
+
+// Catalog item.
+// The image with the hardware hints of a VM
+// created from a catalog entry.
+type CatalogItem struct {
+	Image
+	// VM name.
+	Name string
+	// Description.
+	Description string
+	// vCPU count. Not specified when 0.
+	CPU int64
+	// Memory (bytes). Not specified when 0.
+	Memory int64
+	// Firmware. Not specified when empty.
+	Firmware string
+	// Guest OS. Not specified when empty.
+	OS string
+	// The entry is not valid.
+	Error string
+}
+
+// Read the catalog manifest.
+// The provider URL is the manifest URL.
+func (c *Client) ReadCatalog() (catalog *Catalog, err error) {
+	url, err := c.objectURL()
+	if err != nil {
+		return
+	}
+	response, err := c.send(http.MethodGet, url, nil)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+	content, err := io.ReadAll(io.LimitReader(response.Body, MaxCatalogSize))
+	if err != nil {
+		err = liberr.Wrap(err, "url", url)
+		return
+	}
+	catalog, err = ParseCatalog(content)
+	if err != nil {
+		err = liberr.Wrap(err, "url", url)
+	}
+	return
+}
+
+// List the catalog items.
+// The items are not probed.
+func (c *Client) ListCatalog() (list []CatalogItem, err error) {
+	catalog, err := c.ReadCatalog()
+	if err != nil {
+		return
+	}
+	url, err := c.objectURL()
+	if err != nil {
+		return
+	}
+	base, err := liburl.Parse(url)
+	if err != nil {
+		err = liberr.Wrap(err, "url", url)
+		return
+	}
+	list = catalog.Items(base)
+	return
+}
+
+// Parse the catalog manifest.
+func ParseCatalog(content []byte) (catalog *Catalog, err error) {
+	catalog = &Catalog{}
+	err = yaml.Unmarshal(content, catalog)
+	if err != nil {
+		err = liberr.Wrap(err)
+		catalog = nil
+	}
+	return
+}
+
+// The items (VMs) created from the entries.
+// The image paths are resolved using the base (manifest) URL
+// and must be on the same (share) host so the credentials can
+// be used. An entry that is not valid is reported as a single
+// item with the error.
+func (r *Catalog) Items(base *liburl.URL) (list []CatalogItem) {
+	for _, entry := range r.Images {
+		item := CatalogItem{
+			Name:        entry.Name,
+			Description: entry.Description,
+			CPU:         entry.CPU,
+			Firmware:    strings.ToLower(entry.Firmware),
+			OS:          entry.OS,
+		}
+		item.Path = entry.Image
+		item.Error = entry.validate()
+		if item.Error == "" {
+			item.URL, item.Error = entry.resolve(base)
+		}
+		if item.Error == "" {
+			q, _ := resource.ParseQuantity(entry.Memory)
+			item.Memory = q.Value()
+		}
+		if item.Error != "" || entry.Count <= 1 {
+			list = append(list, item)
+			continue
+		}
+		for n := 1; n <= entry.Count; n++ {
+			created := item
+			created.Name = fmt.Sprintf("%s-%d", entry.Name, n)
+			list = append(list, created)
+		}
+	}
+	return
+}
+
+// Validate the entry.
+// Returns the reason it is not valid.
+func (r *CatalogEntry) validate() (reason string) {
+	switch {
+	case r.Name == "":
+		reason = "the name is not specified."
+	case r.Image == "":
+		reason = "the image is not specified."
+	case r.Count < 0 || r.Count > MaxCount:
+		reason = fmt.Sprintf("the count must be between 0 and %d.", MaxCount)
+	case r.CPU < 0:
+		reason = "the cpu must be a positive integer."
+	case r.Firmware != "" &&
+		!strings.EqualFold(r.Firmware, BIOS) &&
+		!strings.EqualFold(r.Firmware, EFI):
+		reason = "the firmware must be bios or efi."
+	case r.Memory != "":
+		q, err := resource.ParseQuantity(r.Memory)
+		if err != nil || q.Sign() <= 0 {
+			reason = "the memory must be a positive quantity."
+		}
+	}
+	return
+}
+
+// Resolve the image URL.
+// Returns the reason it cannot be resolved.
+func (r *CatalogEntry) resolve(base *liburl.URL) (url string, reason string) {
+	ref, err := liburl.Parse(r.Image)
+	if err != nil {
+		reason = "the image is not a valid url or path."
+		return
+	}
+	resolved := base.ResolveReference(ref)
+	if resolved.Scheme != base.Scheme || resolved.Host != base.Host {
+		reason = "the image is not on the catalog host."
+		return
+	}
+	url = resolved.String()
+	return
+}
+
+// The (http) URL of the object addressed by the provider URL.
+// Object storage objects are addressed (path style) over https.
+func (c *Client) objectURL() (url string, err error) {
+	if !c.IsObjectStorage() {
+		url = c.URL
+		return
+	}
+	endpoint, bucket, prefix, err := c.bucket()
+	if err != nil {
+		return
+	}
+	key := strings.TrimSuffix(prefix, "/")
+	if key == "" {
+		err = liberr.New("the object is not specified by the url.", "url", c.URL)
+		return
+	}
+	url = endpoint + "/" + bucket + "/" + key
+	return
+}
@@ -0,0 +1,479 @@
+package image
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"net"
+	"net/http"
+	liburl "net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kubev2v/forklift/pkg/lib/client/ec2"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+	core "k8s.io/api/core/v1"
+)
+
+// Secret keys.
+// The keys are the same as used by the CDI (http and s3) sources.
+const (
+	// Access key ID (object storage) or user (http basic auth).
+	AccessKeyID = "accessKeyId"
+	// Secret key (object storage) or password (http basic auth).
+	SecretKey          = "secretKey"
+	Region             = "region"
+	InsecureSkipVerify = "insecureSkipVerify"
+	CACert             = "cacert"
+)
+
+// URL schemes.
+const (
+	HTTP  = "http"
+	HTTPS = "https"
+	// S3 compatible object storage.
+	// The objects are addressed (path style) over https.
+	// Example: s3://s3.us-east-1.amazonaws.com/bucket/images
+	S3 = "s3"
+)
+
+// Object storage region used when not specified.
+const DefaultRegion = "us-east-1"
+
+// Object storage (signing) service.
+const S3Service = "s3"
+
+// Page size of listed objects.
+const MaxKeys = 1000
+
+// SHA256 of the (empty) request payload.
+var emptySHA256 = func() string {
+	sum := sha256.Sum256(nil)
+	return hex.EncodeToString(sum[:])
+}()
+
+// Link (href) in a directory index page.
+var hrefPattern = regexp.MustCompile(`(?i)href\s*=\s*["']([^"'#]+)["']`)
+
+// Share (reply) error.
+type Error struct {
+	// HTTP status.
+	Status int
+	// Reported message.
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("image: %d %s %s", e.Status, http.StatusText(e.Status), e.Message)
+}
+
+// Disk image share client.
+// The images are files listed in an http(s) directory index
+// or objects listed in an (S3 compatible) object storage bucket.
+type Client struct {
+	// Share URL.
+	// Examples:
+	//   https://images.example.com/disks/
+	//   s3://minio.example.com:9000/bucket/disks
+	URL     string
+	Options map[string]string
+	Log     logging.LevelLogger
+	// Proxy. The environment proxy when nil.
+	Proxy func(*http.Request) (*liburl.URL, error)
+	// Dial. The default dialer (any address family) when nil.
+	Dial   func(context.Context, string, string) (net.Conn, error)
+	client *http.Client
+}
+
+// Load the options from the provider secret.
+func (c *Client) LoadOptionsFromSecret(secret *core.Secret) {
+	c.Options = make(map[string]string)
+	if secret == nil {
+		return
+	}
+	for key, value := range secret.Data {
+		c.Options[key] = string(value)
+	}
+}
+
+// Connect.
+// Validates the URL and the credentials. The share (index)
+// or the first page of the bucket is listed.
+func (c *Client) Connect() (err error) {
+	if c.IsObjectStorage() {
+		_, _, err = c.listObjects("", 1)
+		return
+	}
+	_, err = c.listIndex()
+	return
+}
+
+// The share is object storage.
+func (c *Client) IsObjectStorage() bool {
+	return strings.HasPrefix(strings.ToLower(c.URL), S3+"://")
+}
+
+// The region.
+func (c *Client) Region() (region string) {
+	region = c.Options[Region]
+	if region == "" {
+		region = DefaultRegion
+	}
+	return
+}
+
+// The error is (http) not found.
+func (c *Client) IsNotFound(err error) bool {
+	if shareErr, cast := liberr.Unwrap(err).(*Error); cast {
+		return shareErr.Status == http.StatusNotFound
+	}
+	return false
+}
+
+// The error is (http) unauthorized or forbidden.
+func (c *Client) IsUnauthorized(err error) bool {
+	if shareErr, cast := liberr.Unwrap(err).(*Error); cast {
+		return shareErr.Status == http.StatusUnauthorized ||
+			shareErr.Status == http.StatusForbidden
+	}
+	return false
+}
+
+// List the images.
+// The files with an image suffix are listed. The format
+// and capacity are not known until probed.
+func (c *Client) ListImages() (list []Image, err error) {
+	if c.IsObjectStorage() {
+		list, err = c.listBucket()
+	} else {
+		list, err = c.listIndex()
+	}
+	return
+}
+
+// Probe the image.
+// The (first) header sector is read to detect the format and
+// the virtual size. The file size and (when not listed) the
+// modified time are reported by the reply.
+func (c *Client) Probe(image *Image) (err error) {
+	header := http.Header{}
+	header.Set("Range", fmt.Sprintf("bytes=0-%d", HeaderSize-1))
+	response, err := c.send(http.MethodGet, image.URL, header)
+	if err != nil {
+		if shareErr, cast := liberr.Unwrap(err).(*Error); cast &&
+			shareErr.Status == http.StatusRequestedRangeNotSatisfiable {
+			// Empty file.
+			err = nil
+			image.Size = 0
+			image.ApplyHeader(nil)
+		}
+		return
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+	content, err := io.ReadAll(io.LimitReader(response.Body, HeaderSize))
+	if err != nil {
+		err = liberr.Wrap(err, "url", image.URL)
+		return
+	}
+	switch response.StatusCode {
+	case http.StatusPartialContent:
+		contentRange := response.Header.Get("Content-Range")
+		total := contentRange[strings.LastIndex(contentRange, "/")+1:]
+		if n, pErr := strconv.ParseInt(total, 10, 64); pErr == nil {
+			image.Size = n
+		}
+	default:
+		if response.ContentLength >= 0 {
+			image.Size = response.ContentLength
+		}
+	}
+	if modified, pErr := http.ParseTime(response.Header.Get("Last-Modified")); pErr == nil && image.Modified.IsZero() {
+		image.Modified = modified
+	}
+	image.ApplyHeader(content)
+	return
+}
+
+// List the images linked by the directory index.
+// Only the files in the directory (not subdirectories)
+// are listed.
+func (c *Client) listIndex() (list []Image, err error) {
+	base, err := liburl.Parse(c.URL)
+	if err != nil {
+		err = liberr.Wrap(err, "url", c.URL)
+		return
+	}
+	if !strings.HasSuffix(base.Path, "/") {
+		base.Path += "/"
+	}
+	response, err := c.send(http.MethodGet, base.String(), nil)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		err = liberr.Wrap(err, "url", base.String())
+		return
+	}
+	list = ParseIndex(base, string(content))
+	return
+}
+
+// Parse the images linked by the directory index (page).
+func ParseIndex(base *liburl.URL, content string) (list []Image) {
+	listed := map[string]bool{}
+	for _, match := range hrefPattern.FindAllStringSubmatch(content, -1) {
+		link, pErr := liburl.Parse(html.UnescapeString(match[1]))
+		if pErr != nil {
+			continue
+		}
+		url := base.ResolveReference(link)
+		url.RawQuery = ""
+		if url.Host != base.Host || !strings.HasPrefix(url.Path, base.Path) {
+			continue
+		}
+		name := strings.TrimPrefix(url.Path, base.Path)
+		if name == "" || strings.Contains(name, "/") || !HasSuffix(name) {
+			continue
+		}
+		if listed[name] {
+			continue
+		}
+		listed[name] = true
+		list = append(
+			list,
+			Image{
+				Path: name,
+				URL:  url.String(),
+			})
+	}
+	return
+}
+
+// List the images in the bucket (with the prefix).
+// The objects in all "subdirectories" are listed.
+func (c *Client) listBucket() (list []Image, err error) {
+	token := ""
+	for {
+		var page []Image
+		page, token, err = c.listObjects(token, MaxKeys)
+		if err != nil {
+			return
+		}
+		list = append(list, page...)
+		if token == "" {
+			break
+		}
+	}
+	return
+}
+
+// List a page of objects (ListObjectsV2).
+// Returns the continuation token of the next page.
+func (c *Client) listObjects(token string, maxKeys int) (list []Image, next string, err error) {
+	endpoint, bucket, prefix, err := c.bucket()
+	if err != nil {
+		return
+	}
+	params := liburl.Values{}
+	params.Set("list-type", "2")
+	params.Set("max-keys", strconv.Itoa(maxKeys))
+	if prefix != "" {
+		params.Set("prefix", prefix)
+	}
+	if token != "" {
+		params.Set("continuation-token", token)
+	}
+	url := endpoint + "/" + liburl.PathEscape(bucket) + "?" +
+		strings.ReplaceAll(params.Encode(), "+", "%20")
+	response, err := c.send(http.MethodGet, url, nil)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+	reply := struct {
+		Contents []struct {
+			Key          string    `xml:"Key"`
+			LastModified time.Time `xml:"LastModified"`
+			Size         int64     `xml:"Size"`
+		} `xml:"Contents"`
+		IsTruncated           bool   `xml:"IsTruncated"`
+		NextContinuationToken string `xml:"NextContinuationToken"`
+	}{}
+	err = xml.NewDecoder(response.Body).Decode(&reply)
+	if err != nil {
+		err = liberr.Wrap(err, "bucket", bucket)
+		return
+	}
+	for _, object := range reply.Contents {
+		if !HasSuffix(object.Key) {
+			continue
+		}
+		objectURL, pErr := liburl.Parse(endpoint)
+		if pErr != nil {
+			err = liberr.Wrap(pErr)
+			return
+		}
+		objectURL.Path = "/" + bucket + "/" + object.Key
+		list = append(
+			list,
+			Image{
+				Path:     strings.TrimPrefix(object.Key, prefix),
+				URL:      objectURL.String(),
+				Size:     object.Size,
+				Modified: object.LastModified,
+			})
+	}
+	if reply.IsTruncated {
+		next = reply.NextContinuationToken
+	}
+	return
+}
+
+// The object storage (https) endpoint, bucket and (key) prefix.
+// The prefix is a "directory" so it ends with a slash.
+// Example: s3://minio.example.com:9000/bucket/disks
+func (c *Client) bucket() (endpoint, bucket, prefix string, err error) {
+	url, err := liburl.Parse(c.URL)
+	if err != nil {
+		err = liberr.Wrap(err, "url", c.URL)
+		return
+	}
+	part := strings.SplitN(strings.TrimPrefix(url.Path, "/"), "/", 2)
+	bucket = part[0]
+	if bucket == "" {
+		err = liberr.New("the bucket is not specified by the url.", "url", c.URL)
+		return
+	}
+	if len(part) > 1 && part[1] != "" {
+		prefix = strings.TrimSuffix(part[1], "/") + "/"
+	}
+	endpoint = HTTPS + "://" + url.Host
+	return
+}
+
+// Build the http client.
+func (c *Client) build() (err error) {
+	if c.client != nil {
+		return
+	}
+	tlsConfig, err := c.getTLSConfig()
+	if err != nil {
+		return
+	}
+	proxy := c.Proxy
+	if proxy == nil {
+		proxy = http.ProxyFromEnvironment
+	}
+	dial := c.Dial
+	if dial == nil {
+		dial = (&net.Dialer{
+			Timeout:   10 * time.Second,
+			KeepAlive: 10 * time.Second,
+		}).DialContext
+	}
+	c.client = &http.Client{
+		Timeout: 2 * time.Minute,
+		Transport: &http.Transport{
+			Proxy:                 proxy,
+			DialContext:           dial,
+			MaxIdleConns:          10,
+			IdleConnTimeout:       10 * time.Second,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ExpectContinueTimeout: 1 * time.Second,
+			TLSClientConfig:       tlsConfig,
+		},
+	}
+	return
+}
+
+func (c *Client) getTLSConfig() (tlsConfig *tls.Config, err error) {
+	if strings.HasPrefix(strings.ToLower(c.URL), HTTP+"://") {
+		return
+	}
+	if insecure, _ := strconv.ParseBool(c.Options[InsecureSkipVerify]); insecure {
+		tlsConfig = &tls.Config{InsecureSkipVerify: true}
+		return
+	}
+	cacert := []byte(c.Options[CACert])
+	if len(cacert) == 0 {
+		return
+	}
+	roots := x509.NewCertPool()
+	ok := roots.AppendCertsFromPEM(cacert)
+	if !ok {
+		err = liberr.New("CA certificate is malformed, failed to configure the CA cert pool")
+		return
+	}
+	tlsConfig = &tls.Config{RootCAs: roots}
+	return
+}
+
+// Send the request.
+// Object storage requests are signed (signature version 4) and
+// http requests use basic auth when the access key is specified.
+// The caller must close the body of the (successful) response.
+func (c *Client) send(method, url string, header http.Header) (response *http.Response, err error) {
+	err = c.build()
+	if err != nil {
+		return
+	}
+	request, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for name, values := range header {
+		request.Header[name] = values
+	}
+	user := c.Options[AccessKeyID]
+	if c.IsObjectStorage() {
+		request.Header.Set("X-Amz-Content-Sha256", emptySHA256)
+		if user != "" {
+			signer := &ec2.Signer{
+				AccessKeyID:     user,
+				SecretAccessKey: c.Options[SecretKey],
+				Region:          c.Region(),
+			}
+			signer.Sign(request, S3Service, nil, time.Now())
+		}
+	} else if user != "" {
+		request.SetBasicAuth(user, c.Options[SecretKey])
+	}
+	response, err = c.client.Do(request)
+	if err != nil {
+		err = liberr.Wrap(err, "method", method, "url", url)
+		return
+	}
+	if response.StatusCode >= http.StatusBadRequest {
+		content, _ := io.ReadAll(io.LimitReader(response.Body, 4096))
+		_ = response.Body.Close()
+		err = liberr.Wrap(
+			&Error{
+				Status:  response.StatusCode,
+				Message: string(content),
+			},
+			"method",
+			method,
+			"url",
+			url)
+		response = nil
+	}
+	return
+}
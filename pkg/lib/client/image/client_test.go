@@ -0,0 +1,177 @@
+package image
+
+import (
+	"encoding/binary"
+	"net/http"
+	"net/http/httptest"
+	liburl "net/url"
+	"strings"
+	"testing"
+
+	"github.com/kubev2v/forklift/pkg/lib/client/ec2"
+)
+
+func TestApplyHeader(t *testing.T) {
+	qcow2 := make([]byte, HeaderSize)
+	copy(qcow2, qcow2Magic)
+	binary.BigEndian.PutUint64(qcow2[24:], 10<<30)
+	vmdk := make([]byte, HeaderSize)
+	copy(vmdk, vmdkMagic)
+	binary.LittleEndian.PutUint64(vmdk[12:], 2048)
+	cases := []struct {
+		header   []byte
+		format   string
+		kind     string
+		capacity int64
+	}{
+		{header: qcow2, format: Qcow2, capacity: 10 << 30},
+		{header: vmdk, format: Vmdk, kind: VmdkSparse, capacity: 2048 * 512},
+		{header: []byte("# Disk DescriptorFile\nversion=1\n"), format: Vmdk, kind: VmdkDescriptor},
+		{header: make([]byte, HeaderSize), format: Raw, capacity: 4096},
+		{header: nil, format: Raw, capacity: 4096},
+	}
+	for _, c := range cases {
+		image := &Image{Size: 4096}
+		image.ApplyHeader(c.header)
+		if image.Format != c.format || image.VmdkKind != c.kind || image.Capacity != c.capacity {
+			t.Errorf("unexpected image: %+v", image)
+		}
+	}
+}
+
+func TestParseIndex(t *testing.T) {
+	base, _ := liburl.Parse("https://images.example.com/disks/")
+	content := `
+<a href="../">Parent</a>
+<a href="fedora.qcow2">fedora.qcow2</a>
+<a HREF='/disks/win%202019.VMDK'>win 2019.VMDK</a>
+<a href="https://images.example.com/disks/fedora.qcow2?x=1">again</a>
+<a href="old/">old/</a>
+<a href="old/rhel.img">nested</a>
+<a href="notes.txt">notes.txt</a>
+<a href="https://other.example.com/disks/other.raw">other host</a>`
+	list := ParseIndex(base, content)
+	if len(list) != 2 {
+		t.Fatalf("unexpected list: %+v", list)
+	}
+	if list[0].Path != "fedora.qcow2" || list[0].URL != "https://images.example.com/disks/fedora.qcow2" {
+		t.Errorf("unexpected image: %+v", list[0])
+	}
+	if list[1].Path != "win 2019.VMDK" || list[1].Name() != "win 2019.VMDK" {
+		t.Errorf("unexpected image: %+v", list[1])
+	}
+}
+
+// The objects are listed (signed) by page and probed using a range.
+func TestListBucket(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), ec2.Algorithm) {
+			t.Errorf("request not signed")
+		}
+		switch r.URL.Path {
+		case "/bucket":
+			if r.URL.Query().Get("prefix") != "disks/" {
+				t.Errorf("unexpected prefix: %s", r.URL.Query().Get("prefix"))
+			}
+			if r.URL.Query().Get("continuation-token") == "" {
+				_, _ = w.Write([]byte(`<ListBucketResult>
+<Contents><Key>disks/a.qcow2</Key><LastModified>2024-01-02T03:04:05.000Z</LastModified><Size>1024</Size></Contents>
+<Contents><Key>disks/readme.txt</Key><Size>10</Size></Contents>
+<IsTruncated>true</IsTruncated><NextContinuationToken>page-2</NextContinuationToken>
+</ListBucketResult>`))
+			} else {
+				_, _ = w.Write([]byte(`<ListBucketResult>
+<Contents><Key>disks/b/c.raw</Key><Size>2048</Size></Contents>
+<IsTruncated>false</IsTruncated>
+</ListBucketResult>`))
+			}
+		case "/bucket/disks/a.qcow2":
+			if r.Header.Get("Range") != "bytes=0-511" {
+				t.Errorf("unexpected range: %s", r.Header.Get("Range"))
+			}
+			header := make([]byte, HeaderSize)
+			copy(header, qcow2Magic)
+			binary.BigEndian.PutUint64(header[24:], 1<<30)
+			w.Header().Set("Content-Range", "bytes 0-511/1024")
+			w.WriteHeader(http.StatusPartialContent)
+			_, _ = w.Write(header)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := &Client{
+		URL: "s3://" + strings.TrimPrefix(server.URL, "https://") + "/bucket/disks",
+		Options: map[string]string{
+			AccessKeyID:        "key",
+			SecretKey:          "secret",
+			InsecureSkipVerify: "true",
+		},
+	}
+	list, err := client.ListImages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].Path != "a.qcow2" || list[1].Path != "b/c.raw" {
+		t.Fatalf("unexpected list: %+v", list)
+	}
+	err = client.Probe(&list[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list[0].Format != Qcow2 || list[0].Capacity != 1<<30 || list[0].Size != 1024 {
+		t.Errorf("unexpected image: %+v", list[0])
+	}
+	err = client.Probe(&list[1])
+	if !client.IsNotFound(err) {
+		t.Errorf("expected not found: %v", err)
+	}
+}
+
+func TestCatalogItems(t *testing.T) {
+	content := `
+images:
+- name: web
+  image: disks/rhel9.qcow2
+  count: 2
+  cpu: 2
+  memory: 4Gi
+  firmware: EFI
+  os: rhel9
+- name: db
+  image: https://images.example.com/golden/db.vmdk
+- name: bad
+  image: disks/bad.qcow2
+  memory: lots
+- name: other
+  image: https://other.example.com/other.raw
+- image: disks/unnamed.qcow2`
+	catalog, err := ParseCatalog([]byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	base, _ := liburl.Parse("https://images.example.com/catalogs/catalog.yaml")
+	list := catalog.Items(base)
+	if len(list) != 6 {
+		t.Fatalf("unexpected list: %+v", list)
+	}
+	web := list[1]
+	if web.Name != "web-2" ||
+		web.URL != "https://images.example.com/catalogs/disks/rhel9.qcow2" ||
+		web.CPU != 2 || web.Memory != 4<<30 || web.Firmware != EFI || web.Error != "" {
+		t.Errorf("unexpected item: %+v", web)
+	}
+	if list[2].URL != "https://images.example.com/golden/db.vmdk" || list[2].Error != "" {
+		t.Errorf("unexpected item: %+v", list[2])
+	}
+	for _, item := range list[3:] {
+		if item.Error == "" {
+			t.Errorf("expected error: %+v", item)
+		}
+	}
+	// JSON.
+	catalog, err = ParseCatalog([]byte(`{"images":[{"name":"a","image":"a.img"}]}`))
+	if err != nil || len(catalog.Images) != 1 {
+		t.Errorf("unexpected catalog: %+v %v", catalog, err)
+	}
+}
@@ -0,0 +1,104 @@
+package image
+
+import (
+	"bytes"
+	"encoding/binary"
+	"path"
+	"strings"
+	"time"
+)
+
+// Image formats.
+const (
+	Raw   = "raw"
+	Qcow2 = "qcow2"
+	Vmdk  = "vmdk"
+)
+
+// VMDK (subformat) kinds.
+const (
+	// Monolithic (or stream optimized) sparse extent.
+	VmdkSparse = "sparse"
+	// Descriptor (text) referencing separate extent files.
+	VmdkDescriptor = "descriptor"
+)
+
+// Size of the header read to detect the format.
+const HeaderSize = 512
+
+// Image header magic.
+var (
+	qcow2Magic          = []byte{'Q', 'F', 'I', 0xfb}
+	vmdkMagic           = []byte{'K', 'D', 'M', 'V'}
+	vmdkDescriptorMagic = []byte("# Disk DescriptorFile")
+)
+
+// Image file suffixes.
+// The suffix determines (only) whether the file is listed.
+var Suffixes = []string{
+	".raw",
+	".img",
+	".qcow2",
+	".vmdk",
+}
+
+// Disk image (file).
+type Image struct {
+	// Path (relative to the provider URL). Example: images/fedora.qcow2
+	Path string
+	// URL (of the data).
+	URL string
+	// Format (raw|qcow2|vmdk).
+	Format string
+	// VMDK kind (sparse|descriptor).
+	VmdkKind string
+	// Virtual size (bytes).
+	Capacity int64
+	// File size (bytes).
+	Size int64
+	// Last modified.
+	Modified time.Time
+}
+
+// The file name.
+func (r *Image) Name() string {
+	return path.Base(r.Path)
+}
+
+// Detect the format and the virtual size (capacity)
+// using the image header.
+// The header of a raw image has no magic and the capacity
+// is the file size.
+func (r *Image) ApplyHeader(header []byte) {
+	r.VmdkKind = ""
+	switch {
+	case bytes.HasPrefix(header, qcow2Magic) && len(header) >= 32:
+		r.Format = Qcow2
+		r.Capacity = int64(binary.BigEndian.Uint64(header[24:32]))
+	case bytes.HasPrefix(header, vmdkMagic) && len(header) >= 20:
+		r.Format = Vmdk
+		r.VmdkKind = VmdkSparse
+		r.Capacity = int64(binary.LittleEndian.Uint64(header[12:20]) * 512)
+	case bytes.HasPrefix(header, vmdkDescriptorMagic):
+		// The extents are separate files which
+		// cannot be imported.
+		r.Format = Vmdk
+		r.VmdkKind = VmdkDescriptor
+		r.Capacity = 0
+	default:
+		r.Format = Raw
+		r.Capacity = r.Size
+	}
+}
+
+// The path has an image file suffix.
+func HasSuffix(p string) (matched bool) {
+	p = strings.ToLower(p)
+	for _, suffix := range Suffixes {
+		if strings.HasSuffix(p, suffix) {
+			matched = true
+			break
+		}
+	}
+	return
+}
@@ -0,0 +1,641 @@
+package libvirt
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net"
+	liburl "net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+	"golang.org/x/crypto/ssh"
+	core "k8s.io/api/core/v1"
+	libvirtxml "libvirt.org/libvirt-go-xml"
+)
+
+// Secret keys.
+const (
+	// SSH user. Overrides the user of the URL.
+	User = "user"
+	// SSH password.
+	Password = "password"
+	// SSH private key (PEM).
+	PrivateKey = "privateKey"
+	// SSH host key fingerprint (SHA256).
+	// Example: SHA256:nThbg6kXUpJWGl7E1IGOCspRomTxdCARLviKw6E5SY8
+	Fingerprint = "fingerprint"
+	// Skip the host key verification.
+	Insecure = "insecureSkipVerify"
+)
+
+// URL scheme.
+// Example: qemu+ssh://root@kvm.example.com/system
+const Scheme = "qemu+ssh"
+
+// Defaults.
+const (
+	DefaultUser = "root"
+	DefaultPort = "22"
+)
+
+// Directory (on the host) of the backup (NBD) sockets.
+const SocketDir = "/var/lib/libvirt/qemu"
+
+// Namespace (URI) of the domain metadata set by the migration.
+const MetadataURI = "https://forklift.konveyor.io/"
+
+// Interval of the polled operations.
+const PollInterval = 5 * time.Second
+
+// Domain states (domstate).
+const (
+	StateRunning   = "running"
+	StatePaused    = "paused"
+	StateShutdown  = "in shutdown"
+	StateShutOff   = "shut off"
+	StateCrashed   = "crashed"
+	StateSuspended = "pmsuspended"
+)
+
+// Command (virsh) error.
+type Error struct {
+	// Command.
+	Command string
+	// Exit status.
+	Status int
+	// Reported message.
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("libvirt: %s: (%d) %s", e.Command, e.Status, e.Message)
+}
+
+// Libvirt (host) client.
+// The virsh commands are run on the host over SSH.
+type Client struct {
+	// Host URL. Example: qemu+ssh://root@kvm.example.com/system
+	URL     string
+	Options map[string]string
+	Log     logging.LevelLogger
+	// Dial. The default dialer (any address family) when nil.
+	Dial   func(context.Context, string, string) (net.Conn, error)
+	client *ssh.Client
+	mutex  sync.Mutex
+}
+
+// Load the options from the provider secret.
+func (c *Client) LoadOptionsFromSecret(secret *core.Secret) {
+	c.Options = make(map[string]string)
+	for key, value := range secret.Data {
+		c.Options[key] = string(value)
+	}
+}
+
+// Connect.
+// Validates the URL, the credentials and the libvirt connection.
+func (c *Client) Connect() (err error) {
+	_, err = c.Run("uri")
+	return
+}
+
+// Close the SSH connection.
+func (c *Client) Close() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.client != nil {
+		_ = c.client.Close()
+		c.client = nil
+	}
+}
+
+// The error is not found.
+func (c *Client) IsNotFound(err error) bool {
+	if cmdErr, cast := liberr.Unwrap(err).(*Error); cast {
+		msg := strings.ToLower(cmdErr.Message)
+		return strings.Contains(msg, "not found") ||
+			strings.Contains(msg, "failed to get") ||
+			strings.Contains(msg, "no domain with matching")
+	}
+	return false
+}
+
+// The error is unauthorized.
+func (c *Client) IsUnauthorized(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "unable to authenticate")
+}
+
+// List the domain (UUIDs).
+func (c *Client) ListDomains() (list []string, err error) {
+	out, err := c.Run("list", "--all", "--uuid")
+	if err != nil {
+		return
+	}
+	list = lines(out)
+	return
+}
+
+// Get a domain (XML) by UUID.
+func (c *Client) GetDomain(id string) (domain *libvirtxml.Domain, err error) {
+	out, err := c.Run("dumpxml", id)
+	if err != nil {
+		return
+	}
+	domain = &libvirtxml.Domain{}
+	err = domain.Unmarshal(string(out))
+	if err != nil {
+		err = liberr.Wrap(err, "domain", id)
+	}
+	return
+}
+
+// Get the domain state. Example: running.
+func (c *Client) DomainState(id string) (state string, err error) {
+	out, err := c.Run("domstate", id)
+	if err != nil {
+		return
+	}
+	state = strings.TrimSpace(string(out))
+	return
+}
+
+// Get the capacity and allocation of a domain disk.
+func (c *Client) BlockInfo(id, target string) (info BlockInfo, err error) {
+	out, err := c.Run("domblkinfo", id, target)
+	if err != nil {
+		return
+	}
+	for _, line := range lines(out) {
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		n, pErr := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if pErr != nil {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "Capacity":
+			info.Capacity = n
+		case "Allocation":
+			info.Allocation = n
+		case "Physical":
+			info.Physical = n
+		}
+	}
+	return
+}
+
+// Start a domain.
+// A paused domain does not run the guest.
+func (c *Client) StartDomain(id string, paused bool) (err error) {
+	args := []string{"start", id}
+	if paused {
+		args = append(args, "--paused")
+	}
+	_, err = c.Run(args...)
+	return
+}
+
+// Shutdown a domain (guest).
+func (c *Client) ShutdownDomain(id string) (err error) {
+	_, err = c.Run("shutdown", id)
+	return
+}
+
+// Destroy (stop) a domain.
+func (c *Client) DestroyDomain(id string) (err error) {
+	_, err = c.Run("destroy", id)
+	return
+}
+
+// List the storage pool (names).
+func (c *Client) ListPools() (list []string, err error) {
+	out, err := c.Run("pool-list", "--all", "--name")
+	if err != nil {
+		return
+	}
+	list = lines(out)
+	return
+}
+
+// Get a storage pool (XML) by name.
+func (c *Client) GetPool(name string) (pool *libvirtxml.StoragePool, err error) {
+	out, err := c.Run("pool-dumpxml", name)
+	if err != nil {
+		return
+	}
+	pool = &libvirtxml.StoragePool{}
+	err = pool.Unmarshal(string(out))
+	if err != nil {
+		err = liberr.Wrap(err, "pool", name)
+	}
+	return
+}
+
+// List the network (names).
+func (c *Client) ListNetworks() (list []string, err error) {
+	out, err := c.Run("net-list", "--all", "--name")
+	if err != nil {
+		return
+	}
+	list = lines(out)
+	return
+}
+
+// Get a network (XML) by name.
+func (c *Client) GetNetwork(name string) (network *libvirtxml.Network, err error) {
+	out, err := c.Run("net-dumpxml", name)
+	if err != nil {
+		return
+	}
+	network = &libvirtxml.Network{}
+	err = network.Unmarshal(string(out))
+	if err != nil {
+		err = liberr.Wrap(err, "network", name)
+	}
+	return
+}
+
+// Begin a (pull mode) backup of the domain.
+// The checkpoint is created with the backup when specified.
+func (c *Client) BeginBackup(id string, backup *DomainBackup, checkpoint *DomainCheckpoint) (err error) {
+	backupXML, err := xml.Marshal(backup)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	files := []string{}
+	defer func() {
+		for _, path := range files {
+			_, _ = c.Exec("rm", "-f", path)
+		}
+	}()
+	backupPath, err := c.WriteTemp(backupXML)
+	if err != nil {
+		return
+	}
+	files = append(files, backupPath)
+	args := []string{"backup-begin", id, "--backupxml", backupPath}
+	if checkpoint != nil {
+		var checkpointXML []byte
+		checkpointXML, err = xml.Marshal(checkpoint)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		var checkpointPath string
+		checkpointPath, err = c.WriteTemp(checkpointXML)
+		if err != nil {
+			return
+		}
+		files = append(files, checkpointPath)
+		args = append(args, "--checkpointxml", checkpointPath)
+	}
+	_, err = c.Run(args...)
+	return
+}
+
+// Get the (running) backup of the domain.
+// Not found when the domain has no backup job.
+func (c *Client) GetBackup(id string) (backup *DomainBackup, found bool, err error) {
+	out, err := c.Run("backup-dumpxml", id)
+	if err != nil {
+		if c.IsNoJob(err) {
+			err = nil
+		}
+		return
+	}
+	backup = &DomainBackup{}
+	err = xml.Unmarshal(out, backup)
+	if err != nil {
+		err = liberr.Wrap(err, "domain", id)
+		return
+	}
+	found = true
+	return
+}
+
+// Abort the (backup) job of the domain.
+func (c *Client) AbortJob(id string) (err error) {
+	_, err = c.Run("domjobabort", id)
+	if err != nil && c.IsNoJob(err) {
+		err = nil
+	}
+	return
+}
+
+// The error reports that the domain has no (backup) job.
+func (c *Client) IsNoJob(err error) bool {
+	if cmdErr, cast := liberr.Unwrap(err).(*Error); cast {
+		msg := strings.ToLower(cmdErr.Message)
+		return strings.Contains(msg, "no active job") ||
+			strings.Contains(msg, "no job is active") ||
+			strings.Contains(msg, "does not have any backup job")
+	}
+	return false
+}
+
+// List the domain checkpoint (names).
+func (c *Client) ListCheckpoints(id string) (list []string, err error) {
+	out, err := c.Run("checkpoint-list", id, "--name")
+	if err != nil {
+		return
+	}
+	list = lines(out)
+	return
+}
+
+// Delete a domain checkpoint.
+// Only the metadata is deleted when the bitmaps cannot be.
+func (c *Client) DeleteCheckpoint(id, name string) (err error) {
+	_, err = c.Run("checkpoint-delete", id, name)
+	if err != nil {
+		_, err = c.Run("checkpoint-delete", id, name, "--metadata")
+	}
+	return
+}
+
+// Get the domain metadata (XML) of the namespace.
+// Not found when the metadata has not been set.
+func (c *Client) GetMetadata(id, uri string) (content []byte, found bool, err error) {
+	content, err = c.Run("metadata", id, uri)
+	if err != nil {
+		if strings.Contains(err.Error(), "metadata not found") {
+			err = nil
+		}
+		return
+	}
+	found = len(bytes.TrimSpace(content)) > 0
+	return
+}
+
+// Set the domain metadata (XML) of the namespace.
+// The metadata is set on both the running and the persistent domain.
+func (c *Client) SetMetadata(id, uri, key string, content []byte) (err error) {
+	args, err := c.metadataArgs(id, uri)
+	if err != nil {
+		return
+	}
+	args = append(args, "--key", key, "--set", string(content))
+	_, err = c.Run(args...)
+	return
+}
+
+// Remove the domain metadata of the namespace.
+func (c *Client) RemoveMetadata(id, uri string) (err error) {
+	args, err := c.metadataArgs(id, uri)
+	if err != nil {
+		return
+	}
+	args = append(args, "--remove")
+	_, err = c.Run(args...)
+	return
+}
+
+// Build the metadata command arguments.
+// The running domain is updated when active.
+func (c *Client) metadataArgs(id, uri string) (args []string, err error) {
+	state, err := c.DomainState(id)
+	if err != nil {
+		return
+	}
+	args = []string{"metadata", id, uri, "--config"}
+	if state != StateShutOff && state != StateCrashed {
+		args = append(args, "--live")
+	}
+	return
+}
+
+// Dial a unix socket on the host.
+// The connection is forwarded over SSH.
+func (c *Client) DialSocket(path string) (conn net.Conn, err error) {
+	client, err := c.connect()
+	if err != nil {
+		return
+	}
+	conn, err = client.Dial("unix", path)
+	if err != nil {
+		err = liberr.Wrap(err, "socket", path)
+	}
+	return
+}
+
+// Write a temporary file on the host.
+// Returns the path.
+func (c *Client) WriteTemp(content []byte) (path string, err error) {
+	out, err := c.Exec("mktemp", "--suffix=.xml")
+	if err != nil {
+		return
+	}
+	path = strings.TrimSpace(string(out))
+	_, err = c.exec(quote("cat")+" > "+quote(path), content)
+	return
+}
+
+// Run a virsh command (on the host).
+// Returns the (standard) output.
+func (c *Client) Run(args ...string) (out []byte, err error) {
+	uri, err := c.libvirtURI()
+	if err != nil {
+		return
+	}
+	out, err = c.Exec(append([]string{"virsh", "-q", "-c", uri}, args...)...)
+	return
+}
+
+// Execute a command (on the host).
+// Returns the (standard) output.
+func (c *Client) Exec(args ...string) (out []byte, err error) {
+	command := []string{}
+	for _, arg := range args {
+		command = append(command, quote(arg))
+	}
+	out, err = c.exec(strings.Join(command, " "), nil)
+	return
+}
+
+// Execute a (shell) command line.
+// The input is written to the standard input.
+func (c *Client) exec(command string, input []byte) (out []byte, err error) {
+	client, err := c.connect()
+	if err != nil {
+		return
+	}
+	session, err := client.NewSession()
+	if err != nil {
+		// The connection is (re)established by the next command.
+		c.Close()
+		err = liberr.Wrap(err)
+		return
+	}
+	defer func() {
+		_ = session.Close()
+	}()
+	stdout := bytes.Buffer{}
+	stderr := bytes.Buffer{}
+	session.Stdout = &stdout
+	session.Stderr = &stderr
+	if input != nil {
+		session.Stdin = bytes.NewReader(input)
+	}
+	err = session.Run(command)
+	if err != nil {
+		status := -1
+		if exitErr, cast := err.(*ssh.ExitError); cast {
+			status = exitErr.ExitStatus()
+		}
+		err = liberr.Wrap(
+			&Error{
+				Command: command,
+				Status:  status,
+				Message: strings.TrimSpace(stderr.String()),
+			})
+		return
+	}
+	out = stdout.Bytes()
+	return
+}
+
+// Connect (SSH) to the host.
+func (c *Client) connect() (client *ssh.Client, err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.client != nil {
+		client = c.client
+		return
+	}
+	url, err := c.parseURL()
+	if err != nil {
+		return
+	}
+	config, err := c.sshConfig(url)
+	if err != nil {
+		return
+	}
+	dial := c.Dial
+	if dial == nil {
+		dialer := &net.Dialer{Timeout: 30 * time.Second}
+		dial = dialer.DialContext
+	}
+	address := net.JoinHostPort(url.Hostname(), DefaultPort)
+	if url.Port() != "" {
+		address = url.Host
+	}
+	conn, err := dial(context.TODO(), "tcp", address)
+	if err != nil {
+		err = liberr.Wrap(err, "host", address)
+		return
+	}
+	sshConn, channels, requests, err := ssh.NewClientConn(conn, address, config)
+	if err != nil {
+		_ = conn.Close()
+		err = liberr.Wrap(err, "host", address)
+		return
+	}
+	client = ssh.NewClient(sshConn, channels, requests)
+	c.client = client
+	return
+}
+
+// Build the SSH (client) configuration.
+func (c *Client) sshConfig(url *liburl.URL) (config *ssh.ClientConfig, err error) {
+	user := DefaultUser
+	if url.User != nil && url.User.Username() != "" {
+		user = url.User.Username()
+	}
+	if c.Options[User] != "" {
+		user = c.Options[User]
+	}
+	config = &ssh.ClientConfig{
+		User:    user,
+		Timeout: 30 * time.Second,
+	}
+	if key := c.Options[PrivateKey]; key != "" {
+		var signer ssh.Signer
+		signer, err = ssh.ParsePrivateKey([]byte(key))
+		if err != nil {
+			err = liberr.Wrap(err, "secret", PrivateKey)
+			return
+		}
+		config.Auth = append(config.Auth, ssh.PublicKeys(signer))
+	}
+	if password := c.Options[Password]; password != "" {
+		config.Auth = append(config.Auth, ssh.Password(password))
+	}
+	if len(config.Auth) == 0 {
+		err = liberr.New("either a password or a private key is required.")
+		return
+	}
+	insecure, _ := strconv.ParseBool(c.Options[Insecure])
+	switch {
+	case insecure:
+		config.HostKeyCallback = ssh.InsecureIgnoreHostKey()
+	case c.Options[Fingerprint] != "":
+		fingerprint := c.Options[Fingerprint]
+		config.HostKeyCallback = func(host string, remote net.Addr, key ssh.PublicKey) error {
+			if ssh.FingerprintSHA256(key) != fingerprint {
+				return liberr.New(
+					"host key fingerprint mismatch.",
+					"host",
+					host,
+					"fingerprint",
+					ssh.FingerprintSHA256(key))
+			}
+			return nil
+		}
+	default:
+		err = liberr.New("either the host key fingerprint or insecureSkipVerify is required.")
+	}
+	return
+}
+
+// Parse (and validate) the URL.
+func (c *Client) parseURL() (url *liburl.URL, err error) {
+	url, err = liburl.Parse(c.URL)
+	if err != nil {
+		err = liberr.Wrap(err, "url", c.URL)
+		return
+	}
+	if url.Scheme != Scheme || url.Hostname() == "" {
+		err = liberr.New("the URL must be qemu+ssh://[user@]host[:port]/system", "url", c.URL)
+	}
+	return
+}
+
+// The libvirt URI (on the host).
+// Example: qemu:///system
+func (c *Client) libvirtURI() (uri string, err error) {
+	url, err := c.parseURL()
+	if err != nil {
+		return
+	}
+	path := url.Path
+	if path == "" || path == "/" {
+		path = "/system"
+	}
+	uri = "qemu://" + path
+	return
+}
+
+// Quote a (shell) argument.
+func quote(arg string) string {
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+// The (non-empty) lines of the output.
+func lines(out []byte) (list []string) {
+	for _, line := range strings.Split(string(out), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" {
+			list = append(list, line)
+		}
+	}
+	return
+}
@@ -0,0 +1,146 @@
+package libvirt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+)
+
+func TestBackupSocket(t *testing.T) {
+	socket := BackupSocket("migration-1", 2, true)
+	if socket != SocketDir+"/forklift-migration-1-2-final.sock" {
+		t.Errorf("unexpected socket: %s", socket)
+	}
+	precopy, final, found := ParseBackupSocket(socket, "migration-1")
+	if !found || precopy != 2 || !final {
+		t.Errorf("unexpected precopy: %d final: %v found: %v", precopy, final, found)
+	}
+	_, _, found = ParseBackupSocket(BackupSocket("migration-2", 0, false), "migration-1")
+	if found {
+		t.Errorf("expected the socket of another migration to not be found")
+	}
+}
+
+func TestQuote(t *testing.T) {
+	if quote("it's") != `'it'\''s'` {
+		t.Errorf("unexpected quoting: %s", quote("it's"))
+	}
+}
+
+// Fake NBD server.
+// The export has data in the first half and a hole in the second half.
+func nbdServer(t *testing.T, conn net.Conn, data []byte) {
+	defer conn.Close()
+	be := binary.BigEndian
+	greeting := be.AppendUint64(nil, nbdMagic)
+	greeting = be.AppendUint64(greeting, nbdOptMagic)
+	greeting = be.AppendUint16(greeting, nbdFlagFixedNewstyle|nbdFlagNoZeroes)
+	_, _ = conn.Write(greeting)
+	flags := make([]byte, 4)
+	_, _ = io.ReadFull(conn, flags)
+	reply := func(option, kind uint32, payload []byte) {
+		header := be.AppendUint64(nil, nbdRepMagic)
+		header = be.AppendUint32(header, option)
+		header = be.AppendUint32(header, kind)
+		header = be.AppendUint32(header, uint32(len(payload)))
+		_, _ = conn.Write(append(header, payload...))
+	}
+	chunk := func(cookie uint64, kind uint16, done bool, payload []byte) {
+		flags := uint16(0)
+		if done {
+			flags = nbdReplyFlagDone
+		}
+		header := be.AppendUint32(nil, nbdStructuredMagic)
+		header = be.AppendUint16(header, flags)
+		header = be.AppendUint16(header, kind)
+		header = be.AppendUint64(header, cookie)
+		header = be.AppendUint32(header, uint32(len(payload)))
+		_, _ = conn.Write(append(header, payload...))
+	}
+	half := uint32(len(data) / 2)
+	for {
+		header := make([]byte, 16)
+		_, err := io.ReadFull(conn, header)
+		if err != nil {
+			return
+		}
+		option := be.Uint32(header[8:])
+		payload := make([]byte, be.Uint32(header[12:]))
+		_, _ = io.ReadFull(conn, payload)
+		switch option {
+		case nbdOptStructuredReply:
+			reply(option, nbdRepAck, nil)
+		case nbdOptSetMetaContext:
+			reply(option, nbdRepMetaContext, append(be.AppendUint32(nil, 7), BaseAllocation...))
+			reply(option, nbdRepAck, nil)
+		case nbdOptGo:
+			info := be.AppendUint16(nil, nbdInfoExport)
+			info = be.AppendUint64(info, uint64(len(data)))
+			info = be.AppendUint16(info, 0)
+			reply(option, nbdRepInfo, info)
+			reply(option, nbdRepAck, nil)
+			goto transmission
+		default:
+			t.Errorf("unexpected option: %d", option)
+			return
+		}
+	}
+transmission:
+	for {
+		header := make([]byte, 28)
+		_, err := io.ReadFull(conn, header)
+		if err != nil {
+			return
+		}
+		command := be.Uint16(header[6:])
+		cookie := be.Uint64(header[8:])
+		offset := be.Uint64(header[16:])
+		length := be.Uint32(header[24:])
+		switch command {
+		case nbdCmdBlockStatus:
+			status := be.AppendUint32(nil, 7)
+			status = be.AppendUint32(status, half)
+			status = be.AppendUint32(status, 0)
+			status = be.AppendUint32(status, half)
+			status = be.AppendUint32(status, HoleFlag|ZeroFlag)
+			chunk(cookie, nbdReplyBlockStatus, true, status)
+		case nbdCmdRead:
+			content := be.AppendUint64(nil, offset)
+			content = append(content, data[offset:offset+uint64(length)]...)
+			chunk(cookie, nbdReplyOffsetData, true, content)
+		case nbdCmdDisc:
+			return
+		}
+	}
+}
+
+func TestNBD(t *testing.T) {
+	data := append(bytes.Repeat([]byte{'x'}, 512), make([]byte, 512)...)
+	client, server := net.Pipe()
+	go nbdServer(t, server, data)
+	nbd, err := OpenNBD(client, "vda", BaseAllocation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer nbd.Close()
+	if nbd.Size() != int64(len(data)) {
+		t.Fatalf("unexpected size: %d", nbd.Size())
+	}
+	extents, err := nbd.BlockStatus(0, nbd.Size())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(extents) != 2 || extents[0].Flags != 0 || extents[1].Offset != 512 || extents[1].Flags&ZeroFlag == 0 {
+		t.Fatalf("unexpected extents: %v", extents)
+	}
+	buffer := make([]byte, 256)
+	err = nbd.Read(128, buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buffer, data[128:384]) {
+		t.Errorf("unexpected data")
+	}
+}
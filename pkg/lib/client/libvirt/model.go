@@ -0,0 +1,131 @@
+package libvirt
+
+import (
+	"encoding/xml"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Disk (block) info.
+type BlockInfo struct {
+	// Virtual size (bytes).
+	Capacity int64
+	// Allocated size (bytes).
+	Allocation int64
+	// Physical size (bytes).
+	Physical int64
+}
+
+// Backup (job).
+// Pull mode backups export the disks over NBD.
+type DomainBackup struct {
+	XMLName xml.Name `xml:"domainbackup"`
+	Mode    string   `xml:"mode,attr,omitempty"`
+	// The checkpoint the (incremental) backup is based on.
+	Incremental string        `xml:"incremental,omitempty"`
+	Server      *BackupServer `xml:"server"`
+	Disks       []BackupDisk  `xml:"disks>disk"`
+}
+
+// Find the disk by (target) name.
+func (r *DomainBackup) FindDisk(name string) (disk *BackupDisk, found bool) {
+	for i := range r.Disks {
+		if r.Disks[i].Name == name {
+			disk = &r.Disks[i]
+			found = true
+			return
+		}
+	}
+	return
+}
+
+// Backup (NBD) server.
+type BackupServer struct {
+	Transport string `xml:"transport,attr,omitempty"`
+	Socket    string `xml:"socket,attr,omitempty"`
+}
+
+// Backup disk.
+type BackupDisk struct {
+	// Target. Example: vda
+	Name   string `xml:"name,attr"`
+	Backup string `xml:"backup,attr,omitempty"`
+	// NBD export.
+	ExportName string `xml:"exportname,attr,omitempty"`
+	// Dirty bitmap (incremental) of the NBD export.
+	ExportBitmap string `xml:"exportbitmap,attr,omitempty"`
+}
+
+// Checkpoint.
+// The (qcow2) dirty bitmaps of the disks track the
+// blocks changed since the checkpoint.
+type DomainCheckpoint struct {
+	XMLName xml.Name         `xml:"domaincheckpoint"`
+	Name    string           `xml:"name"`
+	Disks   []CheckpointDisk `xml:"disks>disk"`
+}
+
+// Checkpoint disk.
+type CheckpointDisk struct {
+	Name       string `xml:"name,attr"`
+	Checkpoint string `xml:"checkpoint,attr"`
+}
+
+// Disk copy metadata.
+// Set (on the domain) by the populator once the
+// disk export of a precopy has been copied.
+type Copied struct {
+	XMLName xml.Name `xml:"copied"`
+	// Precopy number.
+	Precopy int `xml:"precopy,attr"`
+	// The final precopy.
+	Final bool `xml:"final,attr,omitempty"`
+}
+
+// The name of the checkpoint created by the precopy.
+func CheckpointName(migration string, precopy int) string {
+	return fmt.Sprintf("forklift-%s-%d", migration, precopy)
+}
+
+// The checkpoint was created by the migration.
+func IsCheckpointOf(name, migration string) bool {
+	return strings.HasPrefix(name, fmt.Sprintf("forklift-%s-", migration))
+}
+
+// The (NBD) socket of the backup created by the precopy.
+// The precopy is identified by the socket so that the
+// populator can determine the backup being exported.
+func BackupSocket(migration string, precopy int, final bool) string {
+	name := fmt.Sprintf("forklift-%s-%d", migration, precopy)
+	if final {
+		name += "-final"
+	}
+	return path.Join(SocketDir, name+".sock")
+}
+
+// Parse the backup socket.
+// Not found when the backup was not created by the migration.
+func ParseBackupSocket(socket, migration string) (precopy int, final bool, found bool) {
+	name := strings.TrimSuffix(path.Base(socket), ".sock")
+	prefix := fmt.Sprintf("forklift-%s-", migration)
+	if !strings.HasPrefix(name, prefix) {
+		return
+	}
+	name = strings.TrimPrefix(name, prefix)
+	name, final = strings.CutSuffix(name, "-final")
+	precopy, err := strconv.Atoi(name)
+	found = err == nil
+	return
+}
+
+// The dirty bitmap exported by an incremental backup of the disk.
+func ExportBitmap(disk string) string {
+	return "backup-" + disk
+}
+
+// The metadata namespace (URI) of the disk copy.
+func CopiedURI(migration, disk string) string {
+	return MetadataURI + migration + "/" + disk
+}
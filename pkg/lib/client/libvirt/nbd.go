@@ -0,0 +1,358 @@
+package libvirt
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+)
+
+// NBD (fixed newstyle) protocol.
+const (
+	nbdMagic           = 0x4e42444d41474943
+	nbdOptMagic        = 0x49484156454f5054
+	nbdRepMagic        = 0x0003e889045565a9
+	nbdRequestMagic    = 0x25609513
+	nbdSimpleMagic     = 0x67446698
+	nbdStructuredMagic = 0x668e33ef
+	// Handshake flags.
+	nbdFlagFixedNewstyle = 1 << 0
+	nbdFlagNoZeroes      = 1 << 1
+	// Options.
+	nbdOptGo              = 7
+	nbdOptStructuredReply = 8
+	nbdOptSetMetaContext  = 10
+	// Option replies.
+	nbdRepAck         = 1
+	nbdRepInfo        = 3
+	nbdRepMetaContext = 4
+	nbdRepFlagError   = 1 << 31
+	// Info.
+	nbdInfoExport = 0
+	// Commands.
+	nbdCmdRead        = 0
+	nbdCmdDisc        = 2
+	nbdCmdBlockStatus = 7
+	// Structured reply flags.
+	nbdReplyFlagDone = 1 << 0
+	// Structured reply types.
+	nbdReplyNone        = 0
+	nbdReplyOffsetData  = 1
+	nbdReplyOffsetHole  = 2
+	nbdReplyBlockStatus = 5
+	nbdReplyError       = 1<<15 + 1
+	nbdReplyErrorOffset = 1<<15 + 2
+)
+
+// Meta contexts.
+const (
+	// Allocation. The flags are HoleFlag and ZeroFlag.
+	BaseAllocation = "base:allocation"
+	// Dirty bitmap (prefix). The flag is DirtyFlag.
+	DirtyBitmap = "qemu:dirty-bitmap:"
+)
+
+// Block status flags.
+const (
+	HoleFlag  = 1 << 0
+	ZeroFlag  = 1 << 1
+	DirtyFlag = 1 << 0
+)
+
+// Max length of a request.
+const MaxRequestLength = 1 << 30
+
+// Block status extent.
+type Extent struct {
+	Offset int64
+	Length int64
+	Flags  uint32
+}
+
+// NBD client.
+// Reads an export with the block status of a (single) meta context.
+// The requests are not pipelined.
+type NBD struct {
+	conn net.Conn
+	// Export size.
+	size int64
+	// Meta context ID.
+	context uint32
+	cookie  uint64
+}
+
+// Open an NBD export on the connection.
+func OpenNBD(conn net.Conn, export string, metaContext string) (nbd *NBD, err error) {
+	nbd = &NBD{conn: conn}
+	err = nbd.handshake(export, metaContext)
+	if err != nil {
+		_ = conn.Close()
+		nbd = nil
+	}
+	return
+}
+
+// Export size (bytes).
+func (r *NBD) Size() int64 {
+	return r.size
+}
+
+// Get the block status of a range.
+// The extents may not cover the whole range.
+func (r *NBD) BlockStatus(offset, length int64) (extents []Extent, err error) {
+	cookie, err := r.request(nbdCmdBlockStatus, offset, length)
+	if err != nil {
+		return
+	}
+	err = r.replies(cookie, func(kind uint16, payload []byte) (err error) {
+		if kind != nbdReplyBlockStatus {
+			return
+		}
+		if len(payload) < 4 || binary.BigEndian.Uint32(payload) != r.context {
+			return
+		}
+		next := offset
+		for p := payload[4:]; len(p) >= 8; p = p[8:] {
+			extent := Extent{
+				Offset: next,
+				Length: int64(binary.BigEndian.Uint32(p)),
+				Flags:  binary.BigEndian.Uint32(p[4:]),
+			}
+			extents = append(extents, extent)
+			next += extent.Length
+		}
+		return
+	})
+	return
+}
+
+// Read a range.
+func (r *NBD) Read(offset int64, buffer []byte) (err error) {
+	cookie, err := r.request(nbdCmdRead, offset, int64(len(buffer)))
+	if err != nil {
+		return
+	}
+	err = r.replies(cookie, func(kind uint16, payload []byte) (err error) {
+		switch kind {
+		case nbdReplyOffsetData:
+			if len(payload) < 8 {
+				err = liberr.New("nbd: invalid data chunk.")
+				return
+			}
+			at := int64(binary.BigEndian.Uint64(payload)) - offset
+			copy(buffer[at:], payload[8:])
+		case nbdReplyOffsetHole:
+			if len(payload) < 12 {
+				err = liberr.New("nbd: invalid hole chunk.")
+				return
+			}
+			at := int64(binary.BigEndian.Uint64(payload)) - offset
+			length := int64(binary.BigEndian.Uint32(payload[8:]))
+			clear(buffer[at : at+length])
+		}
+		return
+	})
+	return
+}
+
+// Close (disconnect).
+func (r *NBD) Close() {
+	header := make([]byte, 28)
+	binary.BigEndian.PutUint32(header, nbdRequestMagic)
+	binary.BigEndian.PutUint16(header[6:], nbdCmdDisc)
+	_, _ = r.conn.Write(header)
+	_ = r.conn.Close()
+}
+
+// Negotiate the export.
+func (r *NBD) handshake(export string, metaContext string) (err error) {
+	greeting := make([]byte, 18)
+	_, err = io.ReadFull(r.conn, greeting)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if binary.BigEndian.Uint64(greeting) != nbdMagic ||
+		binary.BigEndian.Uint64(greeting[8:]) != nbdOptMagic {
+		err = liberr.New("nbd: not a (newstyle) NBD server.")
+		return
+	}
+	flags := binary.BigEndian.Uint16(greeting[16:])
+	if flags&nbdFlagFixedNewstyle == 0 || flags&nbdFlagNoZeroes == 0 {
+		err = liberr.New("nbd: fixed newstyle negotiation not supported.")
+		return
+	}
+	err = binary.Write(r.conn, binary.BigEndian, uint32(nbdFlagFixedNewstyle|nbdFlagNoZeroes))
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	err = r.option(nbdOptStructuredReply, nil, nil)
+	if err != nil {
+		return
+	}
+	data := nbdString32(export)
+	data = binary.BigEndian.AppendUint32(data, 1)
+	data = append(data, nbdString32(metaContext)...)
+	found := false
+	err = r.option(nbdOptSetMetaContext, data, func(kind uint32, payload []byte) (err error) {
+		if kind == nbdRepMetaContext && len(payload) >= 4 {
+			r.context = binary.BigEndian.Uint32(payload)
+			found = true
+		}
+		return
+	})
+	if err != nil {
+		return
+	}
+	if !found {
+		err = liberr.New("nbd: meta context not supported.", "context", metaContext)
+		return
+	}
+	data = nbdString32(export)
+	data = binary.BigEndian.AppendUint16(data, 0)
+	err = r.option(nbdOptGo, data, func(kind uint32, payload []byte) (err error) {
+		if kind == nbdRepInfo && len(payload) >= 10 &&
+			binary.BigEndian.Uint16(payload) == nbdInfoExport {
+			r.size = int64(binary.BigEndian.Uint64(payload[2:]))
+		}
+		return
+	})
+	return
+}
+
+// Send an option and read the replies until acknowledged.
+func (r *NBD) option(option uint32, data []byte, reply func(kind uint32, payload []byte) error) (err error) {
+	header := make([]byte, 16, 16+len(data))
+	binary.BigEndian.PutUint64(header, nbdOptMagic)
+	binary.BigEndian.PutUint32(header[8:], option)
+	binary.BigEndian.PutUint32(header[12:], uint32(len(data)))
+	_, err = r.conn.Write(append(header, data...))
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for {
+		header := make([]byte, 20)
+		_, err = io.ReadFull(r.conn, header)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		if binary.BigEndian.Uint64(header) != nbdRepMagic {
+			err = liberr.New("nbd: invalid option reply.")
+			return
+		}
+		kind := binary.BigEndian.Uint32(header[12:])
+		payload := make([]byte, binary.BigEndian.Uint32(header[16:]))
+		_, err = io.ReadFull(r.conn, payload)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		if kind&nbdRepFlagError != 0 {
+			err = liberr.New("nbd: option failed.", "option", option, "message", string(payload))
+			return
+		}
+		if kind == nbdRepAck {
+			return
+		}
+		if reply != nil {
+			err = reply(kind, payload)
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Send a request.
+// Returns the cookie.
+func (r *NBD) request(command uint16, offset, length int64) (cookie uint64, err error) {
+	r.cookie++
+	cookie = r.cookie
+	header := make([]byte, 28)
+	binary.BigEndian.PutUint32(header, nbdRequestMagic)
+	binary.BigEndian.PutUint16(header[6:], command)
+	binary.BigEndian.PutUint64(header[8:], cookie)
+	binary.BigEndian.PutUint64(header[16:], uint64(offset))
+	binary.BigEndian.PutUint32(header[24:], uint32(length))
+	_, err = r.conn.Write(header)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+	return
+}
+
+// Read the (structured) replies to a request.
+func (r *NBD) replies(cookie uint64, chunk func(kind uint16, payload []byte) error) (err error) {
+	for {
+		magic := make([]byte, 4)
+		_, err = io.ReadFull(r.conn, magic)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		switch binary.BigEndian.Uint32(magic) {
+		case nbdSimpleMagic:
+			header := make([]byte, 12)
+			_, err = io.ReadFull(r.conn, header)
+			if err != nil {
+				err = liberr.Wrap(err)
+				return
+			}
+			if errno := binary.BigEndian.Uint32(header); errno != 0 {
+				err = liberr.New("nbd: request failed.", "errno", errno)
+			}
+			return
+		case nbdStructuredMagic:
+			header := make([]byte, 16)
+			_, err = io.ReadFull(r.conn, header)
+			if err != nil {
+				err = liberr.Wrap(err)
+				return
+			}
+			flags := binary.BigEndian.Uint16(header)
+			kind := binary.BigEndian.Uint16(header[2:])
+			if binary.BigEndian.Uint64(header[4:]) != cookie {
+				err = liberr.New("nbd: unexpected reply.")
+				return
+			}
+			payload := make([]byte, binary.BigEndian.Uint32(header[12:]))
+			_, err = io.ReadFull(r.conn, payload)
+			if err != nil {
+				err = liberr.Wrap(err)
+				return
+			}
+			if kind == nbdReplyError || kind == nbdReplyErrorOffset {
+				message := ""
+				if len(payload) >= 6 {
+					n := int(binary.BigEndian.Uint16(payload[4:]))
+					message = string(payload[6:min(6+n, len(payload))])
+				}
+				err = liberr.New("nbd: request failed.", "message", message)
+				return
+			}
+			if kind != nbdReplyNone {
+				err = chunk(kind, payload)
+				if err != nil {
+					return
+				}
+			}
+			if flags&nbdReplyFlagDone != 0 {
+				return
+			}
+		default:
+			err = liberr.New("nbd: invalid reply.")
+			return
+		}
+	}
+}
+
+// Encode a string prefixed by its (32-bit) length.
+func nbdString32(s string) (data []byte) {
+	data = binary.BigEndian.AppendUint32(data, uint32(len(s)))
+	data = append(data, s...)
+	return
+}
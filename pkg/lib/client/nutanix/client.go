@@ -0,0 +1,452 @@
+package nutanix
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	liburl "net/url"
+	"strconv"
+	"time"
+
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+	core "k8s.io/api/core/v1"
+)
+
+// Secret keys.
+const (
+	User               = "user"
+	Password           = "password"
+	InsecureSkipVerify = "insecureSkipVerify"
+	CACert             = "cacert"
+)
+
+// API paths.
+const (
+	V3Path          = "/api/nutanix/v3"
+	ClusterMgmtPath = "/api/clustermgmt/v4.0/config"
+)
+
+// Page size of listed entities.
+const PageSize = 500
+
+// API (reply) error.
+type Error struct {
+	// HTTP status.
+	Status int
+	// Reported message.
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("nutanix: %d %s %s", e.Status, http.StatusText(e.Status), e.Message)
+}
+
+// Prism Central (REST) client.
+type Client struct {
+	// Prism Central URL. Example: https://pc.example.com:9440
+	URL     string
+	Options map[string]string
+	Log     logging.LevelLogger
+	// Proxy. The environment proxy when nil.
+	Proxy func(*http.Request) (*liburl.URL, error)
+	// Dial. The default dialer (any address family) when nil.
+	Dial   func(context.Context, string, string) (net.Conn, error)
+	client *http.Client
+}
+
+// Load the options from the provider secret.
+func (c *Client) LoadOptionsFromSecret(secret *core.Secret) {
+	c.Options = make(map[string]string)
+	for key, value := range secret.Data {
+		c.Options[key] = string(value)
+	}
+}
+
+// Connect.
+// Validates the URL and the credentials.
+func (c *Client) Connect() (err error) {
+	request := ListRequest{
+		Kind:   ClusterKind,
+		Length: 1,
+	}
+	err = c.do(http.MethodPost, V3Path+"/clusters/list", request, nil)
+	return
+}
+
+// Build the http client.
+func (c *Client) build() (err error) {
+	if c.client != nil {
+		return
+	}
+	tlsConfig, err := c.getTLSConfig()
+	if err != nil {
+		return
+	}
+	proxy := c.Proxy
+	if proxy == nil {
+		proxy = http.ProxyFromEnvironment
+	}
+	dial := c.Dial
+	if dial == nil {
+		dial = (&net.Dialer{
+			Timeout:   10 * time.Second,
+			KeepAlive: 10 * time.Second,
+		}).DialContext
+	}
+	c.client = &http.Client{
+		Timeout: 2 * time.Minute,
+		Transport: &http.Transport{
+			Proxy:                 proxy,
+			DialContext:           dial,
+			MaxIdleConns:          10,
+			IdleConnTimeout:       10 * time.Second,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ExpectContinueTimeout: 1 * time.Second,
+			TLSClientConfig:       tlsConfig,
+		},
+	}
+	return
+}
+
+func (c *Client) getTLSConfig() (tlsConfig *tls.Config, err error) {
+	url, err := liburl.Parse(c.URL)
+	if err != nil {
+		err = liberr.Wrap(err, "url", c.URL)
+		return
+	}
+	if url.Scheme != "https" {
+		return
+	}
+	if c.getBoolFromOptions(InsecureSkipVerify) {
+		tlsConfig = &tls.Config{InsecureSkipVerify: true}
+		return
+	}
+	cacert := []byte(c.getStringFromOptions(CACert))
+	if len(cacert) == 0 {
+		return
+	}
+	roots := x509.NewCertPool()
+	ok := roots.AppendCertsFromPEM(cacert)
+	if !ok {
+		err = liberr.New("CA certificate is malformed, failed to configure the CA cert pool")
+		return
+	}
+	tlsConfig = &tls.Config{RootCAs: roots}
+	return
+}
+
+func (c *Client) getStringFromOptions(key string) string {
+	if value, found := c.Options[key]; found {
+		return value
+	}
+	return ""
+}
+
+func (c *Client) getBoolFromOptions(key string) bool {
+	if keyStr := c.getStringFromOptions(key); keyStr != "" {
+		value, err := strconv.ParseBool(keyStr)
+		if err != nil {
+			return false
+		}
+		return value
+	}
+	return false
+}
+
+// The error is (http) not found.
+func (c *Client) IsNotFound(err error) bool {
+	if apiErr, cast := liberr.Unwrap(err).(*Error); cast {
+		return apiErr.Status == http.StatusNotFound
+	}
+	return false
+}
+
+// The error is (http) unauthorized or forbidden.
+func (c *Client) IsUnauthorized(err error) bool {
+	if apiErr, cast := liberr.Unwrap(err).(*Error); cast {
+		return apiErr.Status == http.StatusUnauthorized ||
+			apiErr.Status == http.StatusForbidden
+	}
+	return false
+}
+
+// List clusters.
+// Prism Central is not listed.
+func (c *Client) ListClusters() (list []Cluster, err error) {
+	err = c.list(ClusterKind, "", func(entity json.RawMessage) (err error) {
+		cluster := Cluster{}
+		err = json.Unmarshal(entity, &cluster)
+		if err == nil && !cluster.IsPrismCentral() {
+			list = append(list, cluster)
+		}
+		return
+	})
+	return
+}
+
+// List subnets.
+func (c *Client) ListSubnets() (list []Subnet, err error) {
+	err = c.list(SubnetKind, "", func(entity json.RawMessage) (err error) {
+		subnet := Subnet{}
+		err = json.Unmarshal(entity, &subnet)
+		if err == nil {
+			list = append(list, subnet)
+		}
+		return
+	})
+	return
+}
+
+// List VMs.
+func (c *Client) ListVMs() (list []VM, err error) {
+	err = c.list(VMKind, "", func(entity json.RawMessage) (err error) {
+		vm := VM{}
+		err = json.Unmarshal(entity, &vm)
+		if err == nil {
+			list = append(list, vm)
+		}
+		return
+	})
+	return
+}
+
+// List images.
+func (c *Client) ListImages(filter string) (list []Image, err error) {
+	err = c.list(ImageKind, filter, func(entity json.RawMessage) (err error) {
+		image := Image{}
+		err = json.Unmarshal(entity, &image)
+		if err == nil {
+			list = append(list, image)
+		}
+		return
+	})
+	return
+}
+
+// List storage containers.
+// Listed using the (v4) cluster management API.
+func (c *Client) ListStorageContainers() (list []StorageContainer, err error) {
+	for page := 0; ; page++ {
+		reply := struct {
+			Data     []StorageContainer `json:"data"`
+			Metadata struct {
+				TotalAvailableResults int `json:"totalAvailableResults"`
+			} `json:"metadata"`
+		}{}
+		path := fmt.Sprintf(
+			"%s/storage-containers?$page=%d&$limit=%d",
+			ClusterMgmtPath,
+			page,
+			PageSize)
+		err = c.do(http.MethodGet, path, nil, &reply)
+		if err != nil {
+			return
+		}
+		list = append(list, reply.Data...)
+		if len(reply.Data) < PageSize ||
+			len(list) >= reply.Metadata.TotalAvailableResults {
+			break
+		}
+	}
+	return
+}
+
+// Get a VM.
+func (c *Client) GetVM(id string) (vm *VM, err error) {
+	vm = &VM{}
+	err = c.do(http.MethodGet, V3Path+"/vms/"+id, nil, vm)
+	return
+}
+
+// Set the VM power state.
+// The VM spec is updated (intentful API).
+func (c *Client) SetPowerState(id string, state string) (err error) {
+	vm, err := c.GetVM(id)
+	if err != nil {
+		return
+	}
+	if vm.Spec.Resources.PowerState == state {
+		return
+	}
+	vm.Spec.Resources.PowerState = state
+	err = c.do(http.MethodPut, V3Path+"/vms/"+id, intent(vm.Metadata, vm.Spec), &Intent{})
+	return
+}
+
+// Create an image of a VM disk.
+// Returns the image ID.
+func (c *Client) CreateImage(name string, diskID string) (id string, err error) {
+	image := Image{}
+	image.Metadata.Kind = ImageKind
+	image.Spec.Name = name
+	image.Spec.Description = "Exported by Forklift."
+	image.Spec.Resources.ImageType = "DISK_IMAGE"
+	image.Spec.Resources.DataSourceReference = &Reference{
+		Kind: VMDiskKind,
+		UUID: diskID,
+	}
+	reply := &Intent{}
+	err = c.do(http.MethodPost, V3Path+"/images", intent(image.Metadata, image.Spec), reply)
+	if err != nil {
+		return
+	}
+	id = reply.Metadata.UUID
+	return
+}
+
+// Get an image.
+func (c *Client) GetImage(id string) (image *Image, err error) {
+	image = &Image{}
+	err = c.do(http.MethodGet, V3Path+"/images/"+id, nil, image)
+	return
+}
+
+// Find an image by name.
+// Returns nil when not found.
+func (c *Client) FindImage(name string) (image *Image, err error) {
+	list, err := c.ListImages("name==" + name)
+	if err != nil {
+		return
+	}
+	for i := range list {
+		if list[i].Spec.Name == name {
+			image = &list[i]
+			return
+		}
+	}
+	return
+}
+
+// Delete an image.
+func (c *Client) DeleteImage(id string) (err error) {
+	err = c.do(http.MethodDelete, V3Path+"/images/"+id, nil, &Intent{})
+	if c.IsNotFound(err) {
+		err = nil
+	}
+	return
+}
+
+// The URL of the image (data) file.
+func (c *Client) ImageFileURL(id string) string {
+	return c.URL + V3Path + "/images/" + id + "/file"
+}
+
+// Get a task.
+func (c *Client) GetTask(id string) (task *Task, err error) {
+	task = &Task{}
+	err = c.do(http.MethodGet, V3Path+"/tasks/"+id, nil, task)
+	return
+}
+
+// Build the (intentful) request body.
+// The status is not sent.
+func intent(metadata Metadata, spec interface{}) interface{} {
+	return struct {
+		Metadata Metadata    `json:"metadata"`
+		Spec     interface{} `json:"spec"`
+	}{
+		Metadata: metadata,
+		Spec:     spec,
+	}
+}
+
+// List (all pages of) entities of the specified kind.
+func (c *Client) list(kind string, filter string, fn func(json.RawMessage) error) (err error) {
+	request := ListRequest{
+		Kind:   kind,
+		Length: PageSize,
+		Filter: filter,
+	}
+	for {
+		reply := struct {
+			Entities []json.RawMessage `json:"entities"`
+			Metadata ListMetadata      `json:"metadata"`
+		}{}
+		err = c.do(http.MethodPost, V3Path+"/"+kind+"s/list", request, &reply)
+		if err != nil {
+			return
+		}
+		for _, entity := range reply.Entities {
+			err = fn(entity)
+			if err != nil {
+				err = liberr.Wrap(err, "kind", kind)
+				return
+			}
+		}
+		request.Offset += len(reply.Entities)
+		if len(reply.Entities) == 0 || request.Offset >= reply.Metadata.TotalMatches {
+			break
+		}
+	}
+	return
+}
+
+// Send the request.
+// The (JSON) body is encoded from the input and the reply
+// is decoded into the output.
+func (c *Client) do(method, path string, in interface{}, out interface{}) (err error) {
+	err = c.build()
+	if err != nil {
+		return
+	}
+	var body io.Reader
+	if in != nil {
+		b, jErr := json.Marshal(in)
+		if jErr != nil {
+			err = liberr.Wrap(jErr)
+			return
+		}
+		body = bytes.NewReader(b)
+	}
+	request, err := http.NewRequest(method, c.URL+path, body)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	request.SetBasicAuth(
+		c.getStringFromOptions(User),
+		c.getStringFromOptions(Password))
+	request.Header.Set("Accept", "application/json")
+	if in != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	response, err := c.client.Do(request)
+	if err != nil {
+		err = liberr.Wrap(err, "method", method, "path", path)
+		return
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if response.StatusCode >= http.StatusBadRequest {
+		err = liberr.Wrap(
+			&Error{
+				Status:  response.StatusCode,
+				Message: string(content),
+			},
+			"method",
+			method,
+			"path",
+			path)
+		return
+	}
+	if out != nil && len(content) > 0 {
+		err = json.Unmarshal(content, out)
+		if err != nil {
+			err = liberr.Wrap(err, "path", path)
+		}
+	}
+	return
+}
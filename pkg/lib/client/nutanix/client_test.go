@@ -0,0 +1,237 @@
+package nutanix
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// Fake Prism Central.
+// The requests are authenticated using basic auth.
+func prism(t *testing.T, mux *http.ServeMux) (client *Client, server *httptest.Server) {
+	server = httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, password, _ := r.BasicAuth()
+			if user != "admin" || password != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			mux.ServeHTTP(w, r)
+		}))
+	t.Cleanup(server.Close)
+	client = &Client{
+		URL: server.URL,
+		Options: map[string]string{
+			User:     "admin",
+			Password: "secret",
+		},
+	}
+	return
+}
+
+// Reply a page of the (v3) entities at the requested offset.
+func page(t *testing.T, kind string, entities []interface{}, size int, requests *int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		request := ListRequest{}
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		if request.Kind != kind || request.Length != PageSize {
+			t.Errorf("unexpected request: %+v", request)
+		}
+		end := min(request.Offset+size, len(entities))
+		_ = json.NewEncoder(w).Encode(
+			map[string]interface{}{
+				"entities": entities[request.Offset:end],
+				"metadata": ListMetadata{
+					Kind:         kind,
+					TotalMatches: len(entities),
+					Offset:       request.Offset,
+					Length:       end - request.Offset,
+				},
+			})
+	}
+}
+
+// The pages are listed until the total matches are listed.
+func TestListVMs(t *testing.T) {
+	entities := []interface{}{}
+	for i := 0; i < 5; i++ {
+		entities = append(
+			entities,
+			map[string]interface{}{
+				"metadata": map[string]string{"kind": VMKind, "uuid": fmt.Sprintf("vm-%d", i)},
+			})
+	}
+	requests := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc(V3Path+"/vms/list", page(t, VMKind, entities, 2, &requests))
+	client, _ := prism(t, mux)
+	list, err := client.ListVMs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 5 || list[0].Metadata.UUID != "vm-0" || list[4].Metadata.UUID != "vm-4" {
+		t.Errorf("unexpected list: %v", list)
+	}
+	if requests != 3 {
+		t.Errorf("expected 3 pages, got %d", requests)
+	}
+}
+
+// Prism Central is not listed as a cluster.
+func TestListClusters(t *testing.T) {
+	entities := []interface{}{
+		map[string]interface{}{
+			"metadata": map[string]string{"kind": ClusterKind, "uuid": "cluster-1"},
+		},
+		map[string]interface{}{
+			"metadata": map[string]string{"kind": ClusterKind, "uuid": "pc"},
+			"status": map[string]interface{}{
+				"resources": map[string]interface{}{
+					"config": map[string]interface{}{
+						"service_list": []string{"PRISM_CENTRAL"},
+					},
+				},
+			},
+		},
+	}
+	requests := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc(V3Path+"/clusters/list", page(t, ClusterKind, entities, PageSize, &requests))
+	client, _ := prism(t, mux)
+	list, err := client.ListClusters()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].Metadata.UUID != "cluster-1" {
+		t.Errorf("unexpected list: %v", list)
+	}
+}
+
+// The image is found using a (FIQL) filter. The name is
+// matched exactly since the filter is not anchored.
+func TestFindImage(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc(V3Path+"/images/list", func(w http.ResponseWriter, r *http.Request) {
+		request := ListRequest{}
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		entities := []interface{}{}
+		switch request.Filter {
+		case "name==image-1":
+			for _, name := range []string{"image-10", "image-1"} {
+				entities = append(
+					entities,
+					map[string]interface{}{
+						"metadata": map[string]string{"kind": ImageKind, "uuid": name + "-id"},
+						"spec":     map[string]string{"name": name},
+					})
+			}
+		case "name==image-2":
+		default:
+			t.Errorf("unexpected filter: %s", request.Filter)
+		}
+		_ = json.NewEncoder(w).Encode(
+			map[string]interface{}{
+				"entities": entities,
+				"metadata": ListMetadata{Kind: ImageKind, TotalMatches: len(entities)},
+			})
+	})
+	client, _ := prism(t, mux)
+	image, err := client.FindImage("image-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if image == nil || image.Metadata.UUID != "image-1-id" {
+		t.Errorf("unexpected image: %v", image)
+	}
+	image, err = client.FindImage("image-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if image != nil {
+		t.Errorf("expected the image to not be found: %v", image)
+	}
+}
+
+// The (v4) storage containers are listed by page number.
+func TestListStorageContainers(t *testing.T) {
+	total := PageSize + 1
+	pages := []int{}
+	mux := http.NewServeMux()
+	mux.HandleFunc(ClusterMgmtPath+"/storage-containers", func(w http.ResponseWriter, r *http.Request) {
+		n, _ := strconv.Atoi(r.URL.Query().Get("$page"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("$limit"))
+		if limit != PageSize {
+			t.Errorf("unexpected limit: %d", limit)
+		}
+		pages = append(pages, n)
+		data := []StorageContainer{}
+		for i := n * limit; i < min((n+1)*limit, total); i++ {
+			data = append(data, StorageContainer{ContainerExtID: fmt.Sprintf("sc-%d", i)})
+		}
+		reply := map[string]interface{}{
+			"data": data,
+			"metadata": map[string]int{
+				"totalAvailableResults": total,
+			},
+		}
+		_ = json.NewEncoder(w).Encode(reply)
+	})
+	client, _ := prism(t, mux)
+	list, err := client.ListStorageContainers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != total || list[total-1].ContainerExtID != fmt.Sprintf("sc-%d", total-1) {
+		t.Errorf("unexpected list length: %d", len(list))
+	}
+	if len(pages) != 2 || pages[0] != 0 || pages[1] != 1 {
+		t.Errorf("unexpected pages: %v", pages)
+	}
+}
+
+// The reply status is mapped to the errors.
+func TestErrors(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc(V3Path+"/images/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"not found"}`))
+	})
+	mux.HandleFunc(V3Path+"/vms/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+	mux.HandleFunc(V3Path+"/tasks/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	client, server := prism(t, mux)
+
+	_, err := client.GetImage("image-1")
+	if !client.IsNotFound(err) || client.IsUnauthorized(err) {
+		t.Errorf("expected not found: %v", err)
+	}
+	err = client.DeleteImage("image-1")
+	if err != nil {
+		t.Errorf("expected the deleted image to be ignored: %v", err)
+	}
+	_, err = client.GetVM("vm-1")
+	if !client.IsUnauthorized(err) || client.IsNotFound(err) {
+		t.Errorf("expected unauthorized: %v", err)
+	}
+	_, err = client.GetTask("task-1")
+	if err == nil || client.IsNotFound(err) || client.IsUnauthorized(err) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// Wrong credentials.
+	client = &Client{
+		URL:     server.URL,
+		Options: map[string]string{User: "admin", Password: "wrong"},
+	}
+	err = client.Connect()
+	if !client.IsUnauthorized(err) {
+		t.Errorf("expected unauthorized: %v", err)
+	}
+}
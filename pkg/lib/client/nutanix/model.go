@@ -0,0 +1,259 @@
+package nutanix
+
+// Entity kinds.
+const (
+	ClusterKind = "cluster"
+	SubnetKind  = "subnet"
+	VMKind      = "vm"
+	ImageKind   = "image"
+	VMDiskKind  = "vm_disk"
+)
+
+// Power states.
+const (
+	PowerOn  = "ON"
+	PowerOff = "OFF"
+)
+
+// Task states.
+const (
+	TaskQueued    = "QUEUED"
+	TaskRunning   = "RUNNING"
+	TaskSucceeded = "SUCCEEDED"
+	TaskFailed    = "FAILED"
+	TaskAborted   = "ABORTED"
+)
+
+// Image (entity) states.
+const (
+	ImagePending  = "PENDING"
+	ImageRunning  = "RUNNING"
+	ImageComplete = "COMPLETE"
+	ImageError    = "ERROR"
+)
+
+// Entity reference.
+type Reference struct {
+	Kind string `json:"kind"`
+	UUID string `json:"uuid"`
+	Name string `json:"name,omitempty"`
+}
+
+// Entity metadata.
+type Metadata struct {
+	Kind             string            `json:"kind"`
+	UUID             string            `json:"uuid,omitempty"`
+	SpecVersion      *int64            `json:"spec_version,omitempty"`
+	LastUpdateTime   string            `json:"last_update_time,omitempty"`
+	CreationTime     string            `json:"creation_time,omitempty"`
+	Categories       map[string]string `json:"categories,omitempty"`
+	ProjectReference *Reference        `json:"project_reference,omitempty"`
+}
+
+// List request.
+type ListRequest struct {
+	Kind   string `json:"kind"`
+	Offset int    `json:"offset"`
+	Length int    `json:"length"`
+	Filter string `json:"filter,omitempty"`
+}
+
+// List metadata.
+type ListMetadata struct {
+	Kind         string `json:"kind"`
+	TotalMatches int    `json:"total_matches"`
+	Offset       int    `json:"offset"`
+	Length       int    `json:"length"`
+}
+
+// Cluster.
+type Cluster struct {
+	Metadata Metadata `json:"metadata"`
+	Spec     struct {
+		Name string `json:"name"`
+	} `json:"spec"`
+	Status struct {
+		Name      string `json:"name"`
+		Resources struct {
+			Config struct {
+				ServiceList []string `json:"service_list"`
+				SoftwareMap map[string]struct {
+					Version string `json:"version"`
+				} `json:"software_map"`
+			} `json:"config"`
+			Network struct {
+				ExternalIP string `json:"external_ip"`
+			} `json:"network"`
+		} `json:"resources"`
+	} `json:"status"`
+}
+
+// Prism Central (not a hypervisor cluster).
+func (r *Cluster) IsPrismCentral() bool {
+	for _, service := range r.Status.Resources.Config.ServiceList {
+		if service == "PRISM_CENTRAL" {
+			return true
+		}
+	}
+	return false
+}
+
+// Subnet.
+type Subnet struct {
+	Metadata Metadata `json:"metadata"`
+	Spec     struct {
+		Name             string     `json:"name"`
+		ClusterReference *Reference `json:"cluster_reference,omitempty"`
+	} `json:"spec"`
+	Status struct {
+		Name      string `json:"name"`
+		Resources struct {
+			SubnetType  string `json:"subnet_type"`
+			VlanID      int    `json:"vlan_id"`
+			VswitchName string `json:"vswitch_name"`
+			IPConfig    struct {
+				SubnetIP     string `json:"subnet_ip"`
+				PrefixLength int    `json:"prefix_length"`
+			} `json:"ip_config"`
+		} `json:"resources"`
+		ClusterReference *Reference `json:"cluster_reference,omitempty"`
+	} `json:"status"`
+}
+
+// Storage container (v4 cluster management API).
+type StorageContainer struct {
+	ContainerExtID    string `json:"containerExtId"`
+	Name              string `json:"name"`
+	ClusterExtID      string `json:"clusterExtId"`
+	ClusterName       string `json:"clusterName"`
+	MaxCapacity       int64  `json:"maxCapacityBytes"`
+	ReplicationFactor int    `json:"replicationFactor"`
+}
+
+// VM disk.
+type Disk struct {
+	UUID             string `json:"uuid,omitempty"`
+	DiskSizeBytes    int64  `json:"disk_size_bytes,omitempty"`
+	DeviceProperties struct {
+		DeviceType  string `json:"device_type"`
+		DiskAddress struct {
+			AdapterType string `json:"adapter_type"`
+			DeviceIndex int    `json:"device_index"`
+		} `json:"disk_address"`
+	} `json:"device_properties"`
+	StorageConfig *struct {
+		StorageContainerReference *Reference `json:"storage_container_reference,omitempty"`
+	} `json:"storage_config,omitempty"`
+	DataSourceReference *Reference `json:"data_source_reference,omitempty"`
+}
+
+// VM NIC.
+type NIC struct {
+	UUID            string     `json:"uuid,omitempty"`
+	MacAddress      string     `json:"mac_address,omitempty"`
+	Model           string     `json:"model,omitempty"`
+	NicType         string     `json:"nic_type,omitempty"`
+	IsConnected     bool       `json:"is_connected"`
+	SubnetReference *Reference `json:"subnet_reference,omitempty"`
+	IPEndpointList  []struct {
+		IP   string `json:"ip"`
+		Type string `json:"type"`
+	} `json:"ip_endpoint_list,omitempty"`
+}
+
+// VM resources.
+type VMResources struct {
+	PowerState            string `json:"power_state,omitempty"`
+	NumSockets            int32  `json:"num_sockets,omitempty"`
+	NumVcpusPerSocket     int32  `json:"num_vcpus_per_socket,omitempty"`
+	NumThreadsPerCore     int32  `json:"num_threads_per_core,omitempty"`
+	MemorySizeMib         int64  `json:"memory_size_mib,omitempty"`
+	MachineType           string `json:"machine_type,omitempty"`
+	HardwareClockTimezone string `json:"hardware_clock_timezone,omitempty"`
+	BootConfig            *struct {
+		BootType string `json:"boot_type,omitempty"`
+	} `json:"boot_config,omitempty"`
+	VTPMConfig *struct {
+		VTPMEnabled bool `json:"vtpm_enabled"`
+	} `json:"vtpm_config,omitempty"`
+	DiskList   []Disk `json:"disk_list,omitempty"`
+	NicList    []NIC  `json:"nic_list,omitempty"`
+	GuestTools *struct {
+		NutanixGuestTools *struct {
+			State string `json:"state,omitempty"`
+		} `json:"nutanix_guest_tools,omitempty"`
+	} `json:"guest_tools,omitempty"`
+	GuestCustomization *struct {
+		CloudInit *struct {
+			UserData string `json:"user_data,omitempty"`
+			MetaData string `json:"meta_data,omitempty"`
+		} `json:"cloud_init,omitempty"`
+	} `json:"guest_customization,omitempty"`
+}
+
+// VM.
+type VM struct {
+	Metadata Metadata `json:"metadata"`
+	Spec     struct {
+		Name             string      `json:"name"`
+		Description      string      `json:"description,omitempty"`
+		ClusterReference *Reference  `json:"cluster_reference,omitempty"`
+		Resources        VMResources `json:"resources"`
+	} `json:"spec"`
+	Status struct {
+		Name             string      `json:"name"`
+		Description      string      `json:"description,omitempty"`
+		State            string      `json:"state"`
+		ClusterReference *Reference  `json:"cluster_reference,omitempty"`
+		Resources        VMResources `json:"resources"`
+	} `json:"status"`
+}
+
+// Image.
+type Image struct {
+	Metadata Metadata `json:"metadata"`
+	Spec     struct {
+		Name        string `json:"name"`
+		Description string `json:"description,omitempty"`
+		Resources   struct {
+			ImageType           string     `json:"image_type,omitempty"`
+			DataSourceReference *Reference `json:"data_source_reference,omitempty"`
+		} `json:"resources"`
+	} `json:"spec"`
+	Status struct {
+		Name      string `json:"name"`
+		State     string `json:"state"`
+		Resources struct {
+			SizeBytes int64 `json:"size_bytes"`
+		} `json:"resources"`
+	} `json:"status,omitempty"`
+}
+
+// Task.
+type Task struct {
+	UUID                string      `json:"uuid"`
+	Status              string      `json:"status"`
+	PercentageComplete  int         `json:"percentage_complete"`
+	ErrorDetail         string      `json:"error_detail,omitempty"`
+	EntityReferenceList []Reference `json:"entity_reference_list,omitempty"`
+}
+
+// The task is done.
+func (r *Task) Done() bool {
+	switch r.Status {
+	case TaskSucceeded, TaskFailed, TaskAborted:
+		return true
+	}
+	return false
+}
+
+// Reply of an (intentful) request.
+type Intent struct {
+	Metadata Metadata `json:"metadata"`
+	Status   struct {
+		State            string `json:"state"`
+		ExecutionContext struct {
+			TaskUUID string `json:"task_uuid"`
+		} `json:"execution_context"`
+	} `json:"status"`
+}
@@ -5,15 +5,19 @@ import (
 
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
 	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	"github.com/kubev2v/forklift/pkg/lib/util"
 	core "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 )
 
 // Build k8s REST configuration.
+// A kubeconfig in the secret (e.g. the admin kubeconfig
+// of a hosted cluster) takes precedence over the URL and token.
 func RestCfg(p *api.Provider, secret *core.Secret) *rest.Config {
 	cfg, err := config.GetConfig()
 	if err != nil {
@@ -25,6 +29,20 @@ func RestCfg(p *api.Provider, secret *core.Secret) *rest.Config {
 		return cfg
 	}
 
+	if kubeconfig, found := secret.Data[api.Kubeconfig]; found {
+		cfg, err = clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+		if err != nil {
+			klog.Error("failed to load kubeconfig: ", err)
+			return nil
+		}
+		if p.HasProxy() {
+			cfg.Proxy = util.ProviderProxy(p)
+		}
+		cfg.Burst = 1000
+		cfg.QPS = 100
+		return cfg
+	}
+
 	insecure, _ := strconv.ParseBool(string(secret.Data[api.Insecure]))
 
 	cacert, hasCACert := secret.Data["cacert"]
@@ -39,6 +57,9 @@ func RestCfg(p *api.Provider, secret *core.Secret) *rest.Config {
 	if !insecure && hasCACert {
 		cfg.TLSClientConfig.CAData = cacert
 	}
+	if p.HasProxy() {
+		cfg.Proxy = util.ProviderProxy(p)
+	}
 
 	cfg.Burst = 1000
 	cfg.QPS = 100
@@ -104,3 +104,54 @@ func TestRestCfg(t *testing.T) {
 
 	cleanup()
 }
+
+func TestRestCfgKubeconfig(t *testing.T) {
+	cleanup := setupKubeConfig(t)
+	defer cleanup()
+
+	kubeconfig := `
+apiVersion: v1
+clusters:
+- cluster:
+    server: https://api.hosted.example.com:6443
+    insecure-skip-tls-verify: true
+  name: hosted
+contexts:
+- context:
+    cluster: hosted
+    user: admin
+  name: hosted
+current-context: hosted
+kind: Config
+users:
+- name: admin
+  user:
+    token: hosted-token
+`
+	provider := &api.Provider{
+		ObjectMeta: v1.ObjectMeta{Name: "test", Namespace: "clusters"},
+		Spec: api.ProviderSpec{
+			Settings: map[string]string{api.HostedCluster: "hosted"},
+		},
+	}
+	openshift := api.OpenShift
+	provider.Spec.Type = &openshift
+	secret := &core.Secret{
+		ObjectMeta: v1.ObjectMeta{Name: "admin-kubeconfig"},
+		Data:       map[string][]byte{api.Kubeconfig: []byte(kubeconfig)},
+	}
+
+	if provider.IsHost() {
+		t.Fatalf("Expected hosted cluster provider not to be the host")
+	}
+	config := RestCfg(provider, secret)
+	if config == nil {
+		t.Fatalf("Expected non-nil config")
+	}
+	if config.Host != "https://api.hosted.example.com:6443" {
+		t.Errorf("Expected Host from the kubeconfig, got %s", config.Host)
+	}
+	if config.BearerToken != "hosted-token" {
+		t.Errorf("Expected BearerToken from the kubeconfig, got %s", config.BearerToken)
+	}
+}
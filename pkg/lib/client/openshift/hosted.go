@@ -0,0 +1,103 @@
+package ocp
+
+import (
+	"context"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// HostedCluster (hypershift) kinds.
+var (
+	HostedClusterGVK = schema.GroupVersionKind{
+		Group:   "hypershift.openshift.io",
+		Version: "v1beta1",
+		Kind:    "HostedCluster",
+	}
+	NodePoolListGVK = schema.GroupVersionKind{
+		Group:   "hypershift.openshift.io",
+		Version: "v1beta1",
+		Kind:    "NodePoolList",
+	}
+)
+
+// Get the HostedCluster referenced by the provider.
+// The HostedCluster is on the `host` (management) cluster.
+func GetHostedCluster(c client.Client, provider *api.Provider) (hostedCluster *unstructured.Unstructured, err error) {
+	namespace, name := provider.HostedClusterRef()
+	hostedCluster = &unstructured.Unstructured{}
+	hostedCluster.SetGroupVersionKind(HostedClusterGVK)
+	err = c.Get(
+		context.TODO(),
+		client.ObjectKey{Namespace: namespace, Name: name},
+		hostedCluster)
+	if err != nil {
+		err = liberr.Wrap(err, "hostedCluster", provider.Spec.Settings[api.HostedCluster])
+	}
+	return
+}
+
+// Get the admin kubeconfig secret of the HostedCluster referenced
+// by the provider. The secret is in the HostedCluster namespace and
+// is only published once the hosted control plane is available.
+func HostedClusterSecret(c client.Client, provider *api.Provider) (secret *core.Secret, err error) {
+	hostedCluster, err := GetHostedCluster(c, provider)
+	if err != nil {
+		return
+	}
+	name, found, _ := unstructured.NestedString(hostedCluster.Object, "status", "kubeconfig", "name")
+	if !found || name == "" {
+		err = liberr.New(
+			"hosted cluster kubeconfig not published.",
+			"hostedCluster",
+			hostedCluster.GetNamespace()+"/"+hostedCluster.GetName())
+		return
+	}
+	secret = &core.Secret{}
+	err = c.Get(
+		context.TODO(),
+		client.ObjectKey{Namespace: hostedCluster.GetNamespace(), Name: name},
+		secret)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if _, found = secret.Data[api.Kubeconfig]; !found {
+		err = liberr.New(
+			"hosted cluster secret has no kubeconfig.",
+			"secret",
+			secret.Namespace+"/"+secret.Name)
+	}
+	return
+}
+
+// Determine whether the node pools of the HostedCluster referenced by
+// the provider attach the nodes to additional infra cluster networks.
+// Pods on a hosted cluster can only reach secondary networks through
+// the networks attached to the nodes, which are infra cluster VMs.
+func HostedClusterHasAdditionalNetworks(c client.Client, provider *api.Provider) (found bool, err error) {
+	namespace, name := provider.HostedClusterRef()
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(NodePoolListGVK)
+	err = c.List(context.TODO(), list, client.InNamespace(namespace))
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for _, pool := range list.Items {
+		clusterName, _, _ := unstructured.NestedString(pool.Object, "spec", "clusterName")
+		if clusterName != name {
+			continue
+		}
+		networks, _, _ := unstructured.NestedSlice(pool.Object, "spec", "platform", "kubevirt", "additionalNetworks")
+		if len(networks) > 0 {
+			found = true
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,81 @@
+package ocp
+
+import (
+	"testing"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	core "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func hostedCluster(kubeconfig string) *unstructured.Unstructured {
+	hostedCluster := &unstructured.Unstructured{}
+	hostedCluster.SetGroupVersionKind(HostedClusterGVK)
+	hostedCluster.SetNamespace("clusters")
+	hostedCluster.SetName("guest")
+	if kubeconfig != "" {
+		_ = unstructured.SetNestedField(hostedCluster.Object, kubeconfig, "status", "kubeconfig", "name")
+	}
+	return hostedCluster
+}
+
+func TestHostedClusterSecret(t *testing.T) {
+	openshift := api.OpenShift
+	provider := &api.Provider{
+		ObjectMeta: v1.ObjectMeta{Name: "guest", Namespace: "openshift-mtv"},
+		Spec: api.ProviderSpec{
+			Type:     &openshift,
+			Settings: map[string]string{api.HostedCluster: "clusters/guest"},
+		},
+	}
+	secret := &core.Secret{
+		ObjectMeta: v1.ObjectMeta{Name: "guest-admin-kubeconfig", Namespace: "clusters"},
+		Data:       map[string][]byte{api.Kubeconfig: []byte("kubeconfig")},
+	}
+	testCases := []struct {
+		name        string
+		objects     []client.Object
+		expectError bool
+	}{
+		{
+			name:    "Kubeconfig published",
+			objects: []client.Object{hostedCluster(secret.Name), secret},
+		},
+		{
+			name:        "Kubeconfig not published",
+			objects:     []client.Object{hostedCluster(""), secret},
+			expectError: true,
+		},
+		{
+			name:        "HostedCluster not found",
+			objects:     []client.Object{secret},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			_ = core.AddToScheme(scheme)
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tc.objects...).Build()
+
+			found, err := HostedClusterSecret(c, provider)
+			if tc.expectError {
+				if err == nil {
+					t.Errorf("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if string(found.Data[api.Kubeconfig]) != "kubeconfig" {
+				t.Errorf("Expected the kubeconfig secret, got %s", found.Name)
+			}
+		})
+	}
+}
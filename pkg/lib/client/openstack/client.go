@@ -1,6 +1,7 @@
 package openstack
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
@@ -28,6 +29,7 @@ import (
 	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/imagedata"
 	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/images"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/networks"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
 	"github.com/gophercloud/gophercloud/pagination"
 	"github.com/gophercloud/utils/openstack/clientconfig"
@@ -69,9 +71,13 @@ var supportedAuthTypes = map[string]clientconfig.AuthType{
 
 // Client struct
 type Client struct {
-	URL                 string
-	Options             map[string]string
-	Log                 logging.LevelLogger
+	URL     string
+	Options map[string]string
+	Log     logging.LevelLogger
+	// Proxy. The environment proxy when nil.
+	Proxy func(*http.Request) (*url.URL, error)
+	// Dial. The default dialer (any address family) when nil.
+	Dial                func(context.Context, string, string) (net.Conn, error)
 	provider            *gophercloud.ProviderClient
 	identityService     *gophercloud.ServiceClient
 	computeService      *gophercloud.ServiceClient
@@ -138,12 +144,20 @@ func (c *Client) Authenticate() (err error) {
 		return
 	}
 
-	provider.HTTPClient.Transport = &http.Transport{
-		Proxy: http.ProxyFromEnvironment,
-		DialContext: (&net.Dialer{
+	proxy := c.Proxy
+	if proxy == nil {
+		proxy = http.ProxyFromEnvironment
+	}
+	dial := c.Dial
+	if dial == nil {
+		dial = (&net.Dialer{
 			Timeout:   10 * time.Second,
 			KeepAlive: 10 * time.Second,
-		}).DialContext,
+		}).DialContext
+	}
+	provider.HTTPClient.Transport = &http.Transport{
+		Proxy:                 proxy,
+		DialContext:           dial,
 		MaxIdleConns:          10,
 		IdleConnTimeout:       10 * time.Second,
 		TLSHandshakeTimeout:   10 * time.Second,
@@ -277,7 +291,7 @@ func (c *Client) List(object interface{}, opts interface{}) (err error) {
 		err = c.imageServiceAPI(object, opts)
 	case *[]Volume, *[]VolumeType, *[]Snapshot:
 		err = c.blockStorageServiceAPI(object, opts)
-	case *[]Network, *[]Subnet:
+	case *[]Network, *[]Subnet, *[]Port:
 		err = c.networkServiceAPI(object, opts)
 	default:
 		err = c.unsupportedTypeError(object)
@@ -604,11 +618,12 @@ func (c *Client) vmAPI(object interface{}, opts interface{}) (err error) {
 		switch opts := opts.(type) {
 		case *GetOpts:
 			ID := opts.ID
-			server, err = servers.Get(c.computeService, ID).Extract()
+			vm := VM{}
+			err = servers.Get(c.serverService(), ID).ExtractInto(&vm)
 			if err != nil {
 				return
 			}
-			*object = VM{Server: *server}
+			*object = vm
 		case *VMCreateOpts:
 			server, err = servers.Create(c.computeService, opts).Extract()
 			if err != nil {
@@ -637,23 +652,26 @@ func (c *Client) vmAPI(object interface{}, opts interface{}) (err error) {
 
 func (c *Client) vmList(object *[]VM, opts *VMListOpts) (err error) {
 	var allPages pagination.Page
-	allPages, err = servers.List(c.computeService, opts).AllPages()
+	allPages, err = servers.List(c.serverService(), opts).AllPages()
 	if err != nil {
 		return
 	}
-	var serverList []servers.Server
-	serverList, err = servers.ExtractServers(allPages)
+	var instanceList []VM
+	err = servers.ExtractServersInto(allPages, &instanceList)
 	if err != nil {
 		return
 	}
-	var instanceList []VM
-	for _, server := range serverList {
-		instanceList = append(instanceList, VM{server})
-	}
 	*object = instanceList
 	return
 }
 
+// Compute service client reporting the extended server attributes.
+func (c *Client) serverService() *gophercloud.ServiceClient {
+	service := *c.computeService
+	service.Microversion = ServerAttributesMicroversion
+	return &service
+}
+
 func (c *Client) flavorAPI(object interface{}, opts interface{}) (err error) {
 	switch object.(type) {
 	case *[]Flavor:
@@ -1099,6 +1117,8 @@ func (c *Client) networkServiceAPI(object interface{}, opts interface{}) (err er
 		err = c.networkAPI(object, opts)
 	case *Subnet, *[]Subnet:
 		err = c.subnetAPI(object, opts)
+	case *[]Port:
+		err = c.portAPI(object, opts)
 	default:
 		err = c.unsupportedTypeError(object)
 	}
@@ -1174,6 +1194,40 @@ func (c *Client) networkList(object *[]Network, opts *NetworkListOpts) (err erro
 	return
 }
 
+func (c *Client) portAPI(object interface{}, opts interface{}) (err error) {
+	switch object.(type) {
+	case *[]Port:
+		object := object.(*[]Port)
+		switch opts := opts.(type) {
+		case *PortListOpts:
+			err = c.portList(object, opts)
+		default:
+			err = c.unsupportedTypeError(object)
+		}
+	default:
+		err = c.unsupportedTypeError(object)
+	}
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+	return
+}
+
+func (c *Client) portList(object *[]Port, opts *PortListOpts) (err error) {
+	var allPages pagination.Page
+	allPages, err = ports.List(c.networkService, opts).AllPages()
+	if err != nil {
+		return
+	}
+	var instanceList []Port
+	err = ports.ExtractPortsInto(allPages, &instanceList)
+	if err != nil {
+		return
+	}
+	*object = instanceList
+	return
+}
+
 func (c *Client) subnetAPI(object interface{}, opts interface{}) (err error) {
 	switch object.(type) {
 	case *[]Subnet:
@@ -1462,6 +1516,36 @@ func (c *Client) DownloadImage(imageID string) (data io.ReadCloser, err error) {
 	return
 }
 
+// Download the image data starting at the offset.
+// When the image service ignores the range, the data
+// before the offset is skipped.
+func (c *Client) DownloadImageFrom(imageID string, offset int64) (data io.ReadCloser, err error) {
+	if offset <= 0 {
+		return c.DownloadImage(imageID)
+	}
+	err = c.connectImageServiceAPI()
+	if err != nil {
+		return
+	}
+	resp, err := c.imageService.Get(c.imageService.ServiceURL("images", imageID, "file"), nil, &gophercloud.RequestOpts{
+		KeepResponseBody: true,
+		MoreHeaders:      map[string]string{"Range": fmt.Sprintf("bytes=%d-", offset)},
+		OkCodes:          []int{http.StatusOK, http.StatusPartialContent},
+	})
+	if err != nil {
+		return
+	}
+	data = resp.Body
+	if resp.StatusCode == http.StatusOK {
+		_, err = io.CopyN(io.Discard, data, offset)
+		if err != nil {
+			data.Close()
+			data = nil
+		}
+	}
+	return
+}
+
 func (c *Client) UnsetImageMetadata(volumeID, key string) (err error) {
 	err = c.connectBlockStorageServiceAPI()
 	if err != nil {
@@ -1,6 +1,7 @@
 package openstack
 
 import (
+	"strings"
 	"time"
 
 	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/snapshots"
@@ -11,7 +12,9 @@ import (
 	"github.com/gophercloud/gophercloud/openstack/identity/v3/projects"
 	"github.com/gophercloud/gophercloud/openstack/identity/v3/regions"
 	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/images"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/portsbinding"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/networks"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
 )
 
@@ -245,8 +248,27 @@ const (
 	VmStatusVerifyResize     = "VERIFY_RESIZE"
 )
 
+// Compute microversion exposing the extended server attributes
+// (e.g. the user data).
+const ServerAttributesMicroversion = "2.3"
+
 type VM struct {
 	servers.Server
+	ServerInitExt
+}
+
+// First-boot (cloud-init) attributes of the server.
+type ServerInitExt struct {
+	// Base64 encoded user data. Requires the compute microversion 2.3
+	// and is only reported to administrators by default.
+	UserData *string `json:"OS-EXT-SRV-ATTR:user_data"`
+	// Whether the server is delivered a config drive ("True").
+	ConfigDrive string `json:"config_drive"`
+}
+
+// Whether the server is delivered a config drive.
+func (r *ServerInitExt) HasConfigDrive() bool {
+	return strings.EqualFold(r.ConfigDrive, "true")
 }
 
 type VMCreateOpts struct {
@@ -291,6 +313,32 @@ type NetworkListOpts struct {
 	networks.ListOpts
 }
 
+// Port vNIC types of the SR-IOV (passthrough) ports.
+const (
+	VNICTypeDirect         = "direct"
+	VNICTypeDirectPhysical = "direct-physical"
+	VNICTypeMacvtap        = "macvtap"
+)
+
+// Port with the binding extension.
+type Port struct {
+	ports.Port
+	portsbinding.PortsBindingExt
+}
+
+// Determine whether the port is an SR-IOV port.
+func (r *Port) SRIOV() bool {
+	switch r.VNICType {
+	case VNICTypeDirect, VNICTypeDirectPhysical, VNICTypeMacvtap:
+		return true
+	}
+	return false
+}
+
+type PortListOpts struct {
+	ports.ListOpts
+}
+
 type Subnet struct {
 	subnets.Subnet
 }
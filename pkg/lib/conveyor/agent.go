@@ -0,0 +1,231 @@
+package conveyor
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	liburl "net/url"
+	"strings"
+	"time"
+
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+	"golang.org/x/net/http2"
+)
+
+// Conveyor agent.
+// Runs inside the source datacenter. Connects to the gateway and
+// dials the (allowed) addresses requested through the tunnel.
+type Agent struct {
+	// Gateway (tunnel) URL. Example: https://conveyor.apps.example.com/conveyor
+	URL string
+	// Conveyor name.
+	Name string
+	// Token used to authenticate with the gateway.
+	Token string
+	// TLS configuration used to connect to the gateway.
+	TLS *tls.Config
+	// Allowed addresses: CIDRs, host names and
+	// domains (leading dot). Nothing is allowed when empty.
+	Allowed []string
+	// Dialer.
+	Dialer *net.Dialer
+	// Logger.
+	log logging.LevelLogger
+}
+
+// Run the agent.
+// Reconnects until canceled.
+func (r *Agent) Run(ctx context.Context) {
+	r.log = logging.WithName("conveyor|agent").WithValues("conveyor", r.Name)
+	if r.Dialer == nil {
+		r.Dialer = &net.Dialer{Timeout: DialTimeout, KeepAlive: KeepAlive}
+	}
+	for {
+		err := r.serve(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			r.log.Error(err, "tunnel failed.", "retry", RetryDelay)
+		} else {
+			r.log.Info("Tunnel closed.", "retry", RetryDelay)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(RetryDelay):
+		}
+	}
+}
+
+// Determine whether the address (host:port) is allowed.
+func (r *Agent) IsAllowed(address string) bool {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return false
+	}
+	ip := net.ParseIP(host)
+	for _, allowed := range r.Allowed {
+		allowed = strings.TrimSpace(allowed)
+		switch {
+		case allowed == "":
+			continue
+		case strings.Contains(allowed, "/"):
+			_, cidr, pErr := net.ParseCIDR(allowed)
+			if pErr == nil && ip != nil && cidr.Contains(ip) {
+				return true
+			}
+		case strings.HasPrefix(allowed, "."):
+			if ip == nil && strings.HasSuffix(strings.ToLower(host), strings.ToLower(allowed)) {
+				return true
+			}
+		default:
+			if strings.EqualFold(host, allowed) {
+				return true
+			}
+			if allowedIP := net.ParseIP(allowed); allowedIP != nil && allowedIP.Equal(ip) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Serve the CONNECT requests (streams) of the gateway.
+func (r *Agent) ServeHTTP(w http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodConnect {
+		http.Error(w, "only CONNECT is supported.", http.StatusMethodNotAllowed)
+		return
+	}
+	address := request.Host
+	if !r.IsAllowed(address) {
+		r.log.Info("Address not allowed.", "address", address)
+		http.Error(w, fmt.Sprintf("address %s not allowed.", address), http.StatusForbidden)
+		return
+	}
+	target, err := r.Dialer.DialContext(request.Context(), "tcp", address)
+	if err != nil {
+		r.log.V(1).Info("Dial failed.", "address", address, "reason", err.Error())
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	stream := &responseStream{body: request.Body, writer: w}
+	stream.flusher, _ = w.(http.Flusher)
+	if stream.flusher != nil {
+		stream.flusher.Flush()
+	}
+	pipe(target, stream)
+}
+
+// Connect to the gateway, upgrade the connection
+// and serve (HTTP/2) the gateway streams.
+func (r *Agent) serve(ctx context.Context) (err error) {
+	url, err := liburl.Parse(r.URL)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	address := url.Host
+	if url.Port() == "" {
+		address = net.JoinHostPort(url.Hostname(), "443")
+	}
+	cfg := &tls.Config{}
+	if r.TLS != nil {
+		cfg = r.TLS.Clone()
+	}
+	cfg.NextProtos = []string{"http/1.1"}
+	if cfg.ServerName == "" {
+		cfg.ServerName = url.Hostname()
+	}
+	dialer := &tls.Dialer{NetDialer: r.Dialer, Config: cfg}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		err = liberr.Wrap(err, "url", r.URL)
+		return
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	request, err := http.NewRequest(http.MethodGet, r.URL, nil)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	request.Header.Set("Connection", "Upgrade")
+	request.Header.Set("Upgrade", Protocol)
+	request.Header.Set("Authorization", "Bearer "+r.Token)
+	request.Header.Set(NameHeader, r.Name)
+	err = request.Write(conn)
+	if err != nil {
+		err = liberr.Wrap(err, "url", r.URL)
+		return
+	}
+	reader := bufio.NewReader(conn)
+	response, err := http.ReadResponse(reader, request)
+	if err != nil {
+		err = liberr.Wrap(err, "url", r.URL)
+		return
+	}
+	_ = response.Body.Close()
+	if response.StatusCode != http.StatusSwitchingProtocols {
+		err = liberr.New(
+			"tunnel upgrade failed.",
+			"url", r.URL,
+			"status", response.Status)
+		return
+	}
+	r.log.Info("Tunnel connected.", "url", r.URL)
+	closed := make(chan struct{})
+	defer close(closed)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.Close()
+		case <-closed:
+		}
+	}()
+	server := &http2.Server{}
+	server.ServeConn(
+		&bufferedConn{Conn: conn, reader: reader},
+		&http2.ServeConnOpts{
+			Context: ctx,
+			Handler: r,
+		})
+	return
+}
+
+// Stream served by the agent.
+// The request body is read and the response is written (flushed).
+type responseStream struct {
+	// Request body.
+	body io.ReadCloser
+	// Response writer.
+	writer io.Writer
+	// Response flusher.
+	flusher http.Flusher
+}
+
+// Read.
+func (r *responseStream) Read(b []byte) (int, error) {
+	return r.body.Read(b)
+}
+
+// Write.
+func (r *responseStream) Write(b []byte) (n int, err error) {
+	n, err = r.writer.Write(b)
+	if err == nil && r.flusher != nil {
+		r.flusher.Flush()
+	}
+	return
+}
+
+// Close.
+func (r *responseStream) Close() error {
+	return r.body.Close()
+}
@@ -0,0 +1,142 @@
+// Package conveyor provides a reverse tunnel used to reach source
+// providers in datacenters that cannot be reached from the cluster.
+//
+// The conveyor (agent) appliance runs inside the source datacenter and
+// opens a single outbound TLS connection to the gateway served by the
+// inventory. The connection is upgraded and the roles are reversed: the
+// gateway is the HTTP/2 client and the agent is the HTTP/2 server. Each
+// connection to a source endpoint (vCenter, ESXi, ...) is a CONNECT stream
+// dialed by the agent within the datacenter.
+//
+// The gateway serves an (in-cluster) HTTP CONNECT proxy. Providers use the
+// conveyor through the provider proxy settings with the conveyor name as
+// the proxy user. Example:
+//
+//	httpsProxy: http://dc1@forklift-inventory.openshift-mtv.svc:8446
+package conveyor
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"time"
+)
+
+// Protocol.
+const (
+	// Tunnel path.
+	TunnelPath = "/conveyor"
+	// Upgrade protocol.
+	Protocol = "forklift-conveyor"
+	// Conveyor name header.
+	NameHeader = "X-Conveyor-Name"
+)
+
+// Settings.
+const (
+	// Connect retry delay.
+	RetryDelay = time.Second * 10
+	// Tunnel keepalive interval.
+	KeepAlive = time.Second * 30
+	// Dial timeout.
+	DialTimeout = time.Second * 30
+)
+
+// Connection with the (buffered) content read
+// before the connection was upgraded.
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+// Read.
+func (r *bufferedConn) Read(b []byte) (int, error) {
+	return r.reader.Read(b)
+}
+
+// Address of a connection tunneled through a conveyor.
+type Addr struct {
+	// Conveyor name.
+	Conveyor string
+	// Target address.
+	Address string
+}
+
+// Network.
+func (a *Addr) Network() string {
+	return Protocol
+}
+
+// String representation.
+func (a *Addr) String() string {
+	return a.Conveyor + "/" + a.Address
+}
+
+// Connection tunneled through a conveyor (CONNECT stream).
+// Deadlines are not supported.
+type streamConn struct {
+	// Stream content (response) read.
+	reader io.ReadCloser
+	// Stream content (request) written.
+	writer *io.PipeWriter
+	// Local address.
+	local Addr
+	// Remote address.
+	remote Addr
+}
+
+// Read.
+func (r *streamConn) Read(b []byte) (int, error) {
+	return r.reader.Read(b)
+}
+
+// Write.
+func (r *streamConn) Write(b []byte) (int, error) {
+	return r.writer.Write(b)
+}
+
+// Close.
+func (r *streamConn) Close() error {
+	_ = r.writer.Close()
+	return r.reader.Close()
+}
+
+// Local address.
+func (r *streamConn) LocalAddr() net.Addr {
+	return &r.local
+}
+
+// Remote address.
+func (r *streamConn) RemoteAddr() net.Addr {
+	return &r.remote
+}
+
+// Not supported.
+func (r *streamConn) SetDeadline(time.Time) error {
+	return nil
+}
+
+// Not supported.
+func (r *streamConn) SetReadDeadline(time.Time) error {
+	return nil
+}
+
+// Not supported.
+func (r *streamConn) SetWriteDeadline(time.Time) error {
+	return nil
+}
+
+// Copy the content both ways until either side is done.
+func pipe(a, b io.ReadWriteCloser) {
+	done := make(chan struct{}, 2)
+	transfer := func(dst io.WriteCloser, src io.Reader) {
+		_, _ = io.Copy(dst, src)
+		done <- struct{}{}
+	}
+	go transfer(a, b)
+	go transfer(b, a)
+	<-done
+	_ = a.Close()
+	_ = b.Close()
+	<-done
+}
@@ -0,0 +1,90 @@
+package conveyor
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	liburl "net/url"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+)
+
+func TestAgentIsAllowed(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	agent := &Agent{
+		Allowed: []string{
+			"10.0.0.0/8",
+			"vcenter.example.com",
+			".esx.example.com",
+			"fd00::1",
+		},
+	}
+	g.Expect(agent.IsAllowed("10.1.2.3:443")).To(gomega.BeTrue())
+	g.Expect(agent.IsAllowed("VCenter.example.com:443")).To(gomega.BeTrue())
+	g.Expect(agent.IsAllowed("host1.esx.example.com:902")).To(gomega.BeTrue())
+	g.Expect(agent.IsAllowed("[fd00::1]:443")).To(gomega.BeTrue())
+	g.Expect(agent.IsAllowed("192.168.1.1:443")).To(gomega.BeFalse())
+	g.Expect(agent.IsAllowed("other.example.com:443")).To(gomega.BeFalse())
+	g.Expect(agent.IsAllowed("10.1.2.3")).To(gomega.BeFalse())
+	g.Expect((&Agent{}).IsAllowed("10.1.2.3:443")).To(gomega.BeFalse())
+}
+
+func TestGatewayProxy(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	// source endpoint.
+	source := httptest.NewTLSServer(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte("source"))
+		}))
+	defer source.Close()
+	// gateway.
+	gateway := NewGateway("secret")
+	tunnel := httptest.NewTLSServer(gateway.Tunnel())
+	defer tunnel.Close()
+	proxy := httptest.NewServer(gateway.Proxy())
+	defer proxy.Close()
+	// not authorized.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	agent := &Agent{
+		URL:     tunnel.URL + TunnelPath,
+		Name:    "dc1",
+		Token:   "wrong",
+		TLS:     &tls.Config{InsecureSkipVerify: true},
+		Allowed: []string{"127.0.0.1"},
+	}
+	agent.log = gateway.log
+	g.Expect(agent.serve(ctx)).ToNot(gomega.Succeed())
+	// connected.
+	agent.Token = "secret"
+	go agent.Run(ctx)
+	g.Eventually(gateway.Connected, 5*time.Second).Should(gomega.ConsistOf("dc1"))
+	proxyURL, _ := liburl.Parse(proxy.URL)
+	proxyURL.User = liburl.User("dc1")
+	client := &http.Client{
+		Transport: &http.Transport{
+			Proxy:           http.ProxyURL(proxyURL),
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+		Timeout: 10 * time.Second,
+	}
+	response, err := client.Get(source.URL)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	content, _ := io.ReadAll(response.Body)
+	_ = response.Body.Close()
+	g.Expect(string(content)).To(gomega.Equal("source"))
+	// not allowed.
+	agent.Allowed = []string{"10.0.0.1"}
+	client.Transport.(*http.Transport).CloseIdleConnections()
+	_, err = client.Get(source.URL)
+	g.Expect(err).To(gomega.HaveOccurred())
+	// not connected.
+	proxyURL.User = liburl.User("dc2")
+	client.Transport.(*http.Transport).CloseIdleConnections()
+	_, err = client.Get(source.URL)
+	g.Expect(err).To(gomega.HaveOccurred())
+}
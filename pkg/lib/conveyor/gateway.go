@@ -0,0 +1,254 @@
+package conveyor
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	liburl "net/url"
+	"strings"
+	"sync"
+	"time"
+
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+	"golang.org/x/net/http2"
+)
+
+// Gateway.
+// Accepts the (tunnel) connections of the conveyors and
+// dials through them on behalf of the (proxy) clients.
+type Gateway struct {
+	// Token used to authenticate the conveyors.
+	Token string
+	// Logger.
+	log logging.LevelLogger
+	// Connected conveyors by name.
+	conveyors map[string]*http2.ClientConn
+	// Mutex.
+	mutex sync.Mutex
+}
+
+// New gateway.
+func NewGateway(token string) *Gateway {
+	return &Gateway{
+		Token:     token,
+		log:       logging.WithName("conveyor|gateway"),
+		conveyors: make(map[string]*http2.ClientConn),
+	}
+}
+
+// The names of the connected conveyors.
+func (r *Gateway) Connected() (names []string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for name := range r.conveyors {
+		names = append(names, name)
+	}
+	return
+}
+
+// Dial the address through the named conveyor.
+// The dial is bounded by the context but the connection
+// outlives it.
+func (r *Gateway) Dial(ctx context.Context, name, address string) (conn net.Conn, err error) {
+	r.mutex.Lock()
+	cc, found := r.conveyors[name]
+	r.mutex.Unlock()
+	if !found {
+		err = liberr.New("conveyor not connected.", "conveyor", name)
+		return
+	}
+	reader, writer := io.Pipe()
+	request, err := http.NewRequestWithContext(
+		context.WithoutCancel(ctx),
+		http.MethodConnect,
+		"",
+		reader)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	request.URL = &liburl.URL{Host: address}
+	request.Host = address
+	type result struct {
+		response *http.Response
+		err      error
+	}
+	done := make(chan result, 1)
+	go func() {
+		response, rErr := cc.RoundTrip(request)
+		done <- result{response: response, err: rErr}
+	}()
+	var res result
+	select {
+	case res = <-done:
+	case <-ctx.Done():
+		_ = writer.CloseWithError(ctx.Err())
+		go func() {
+			if res := <-done; res.err == nil {
+				_ = res.response.Body.Close()
+			}
+		}()
+		err = liberr.Wrap(ctx.Err(), "conveyor", name, "address", address)
+		return
+	}
+	if res.err != nil {
+		_ = writer.Close()
+		err = liberr.Wrap(res.err, "conveyor", name, "address", address)
+		return
+	}
+	if res.response.StatusCode != http.StatusOK {
+		reason, _ := io.ReadAll(io.LimitReader(res.response.Body, 1024))
+		_ = res.response.Body.Close()
+		_ = writer.Close()
+		err = liberr.New(
+			fmt.Sprintf(
+				"conveyor dial failed: %s",
+				strings.TrimSpace(string(reason))),
+			"conveyor", name,
+			"address", address,
+			"status", res.response.StatusCode)
+		return
+	}
+	conn = &streamConn{
+		reader: res.response.Body,
+		writer: writer,
+		local:  Addr{Conveyor: name},
+		remote: Addr{Conveyor: name, Address: address},
+	}
+	return
+}
+
+// Tunnel handler.
+// Authenticates the conveyor, upgrades the connection
+// and registers the (reversed) HTTP/2 client connection.
+func (r *Gateway) Tunnel() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, request *http.Request) {
+		name := request.Header.Get(NameHeader)
+		token := strings.TrimPrefix(request.Header.Get("Authorization"), "Bearer ")
+		if r.Token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(r.Token)) != 1 {
+			http.Error(w, "not authorized.", http.StatusUnauthorized)
+			return
+		}
+		if name == "" || !strings.EqualFold(request.Header.Get("Upgrade"), Protocol) {
+			http.Error(w, "conveyor upgrade expected.", http.StatusBadRequest)
+			return
+		}
+		hijacker, cast := w.(http.Hijacker)
+		if !cast {
+			http.Error(w, "upgrade not supported.", http.StatusInternalServerError)
+			return
+		}
+		conn, rw, err := hijacker.Hijack()
+		if err != nil {
+			r.log.Error(err, "hijack failed.", "conveyor", name)
+			return
+		}
+		_, err = fmt.Fprintf(
+			conn,
+			"HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: %s\r\n\r\n",
+			Protocol)
+		if err != nil {
+			_ = conn.Close()
+			return
+		}
+		transport := &http2.Transport{ReadIdleTimeout: KeepAlive}
+		cc, err := transport.NewClientConn(&bufferedConn{Conn: conn, reader: rw.Reader})
+		if err != nil {
+			r.log.Error(err, "tunnel failed.", "conveyor", name)
+			_ = conn.Close()
+			return
+		}
+		r.connected(name, cc)
+	})
+}
+
+// Proxy handler.
+// HTTP CONNECT proxy dialing through the conveyor named by
+// the proxy (basic auth) user.
+func (r *Gateway) Proxy() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodConnect {
+			http.Error(w, "only CONNECT is supported.", http.StatusMethodNotAllowed)
+			return
+		}
+		name := proxyUser(request)
+		if name == "" {
+			w.Header().Set("Proxy-Authenticate", "Basic realm=\"conveyor\"")
+			http.Error(w, "conveyor (proxy user) not specified.", http.StatusProxyAuthRequired)
+			return
+		}
+		ctx, cancel := context.WithTimeout(request.Context(), DialTimeout)
+		defer cancel()
+		target, err := r.Dial(ctx, name, request.Host)
+		if err != nil {
+			r.log.Error(err, "proxy dial failed.")
+			http.Error(w, liberr.Unwrap(err).Error(), http.StatusBadGateway)
+			return
+		}
+		hijacker, cast := w.(http.Hijacker)
+		if !cast {
+			_ = target.Close()
+			http.Error(w, "hijack not supported.", http.StatusInternalServerError)
+			return
+		}
+		conn, rw, err := hijacker.Hijack()
+		if err != nil {
+			_ = target.Close()
+			return
+		}
+		_, err = conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+		if err != nil {
+			_ = conn.Close()
+			_ = target.Close()
+			return
+		}
+		pipe(&bufferedConn{Conn: conn, reader: rw.Reader}, target)
+	})
+}
+
+// Register the connected conveyor.
+// Replaces (closes) the previous connection of the
+// same conveyor. Unregistered once closed.
+func (r *Gateway) connected(name string, cc *http2.ClientConn) {
+	r.mutex.Lock()
+	previous, found := r.conveyors[name]
+	r.conveyors[name] = cc
+	r.mutex.Unlock()
+	if found {
+		_ = previous.Close()
+	}
+	r.log.Info("Conveyor connected.", "conveyor", name)
+	go func() {
+		for {
+			ctx, cancel := context.WithTimeout(context.Background(), KeepAlive)
+			err := cc.Ping(ctx)
+			cancel()
+			if err != nil || cc.State().Closed {
+				break
+			}
+			time.Sleep(KeepAlive)
+		}
+		_ = cc.Close()
+		r.mutex.Lock()
+		if r.conveyors[name] == cc {
+			delete(r.conveyors, name)
+		}
+		r.mutex.Unlock()
+		r.log.Info("Conveyor disconnected.", "conveyor", name)
+	}()
+}
+
+// The proxy (basic auth) user.
+func proxyUser(request *http.Request) (user string) {
+	auth := request.Header.Get("Proxy-Authorization")
+	if auth == "" {
+		return
+	}
+	parsed := &http.Request{Header: http.Header{"Authorization": []string{auth}}}
+	user, _, _ = parsed.BasicAuth()
+	return
+}
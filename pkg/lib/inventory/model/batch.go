@@ -0,0 +1,65 @@
+package model
+
+// Batch of model writes.
+// The writes are committed in transactions of (at most)
+// the batch size rather than in a transaction for each
+// write (or for each update cycle) so that large inventories
+// are loaded without holding the writer for long.
+type Batch struct {
+	// The DB.
+	DB DB
+	// Max number of writes in each transaction.
+	Size int
+	// Transaction labels.
+	Labels []string
+	// The current transaction.
+	tx *Tx
+	// Number of writes in the current transaction.
+	count int
+}
+
+// Perform the write within the current transaction.
+// The transaction is committed when the batch size is reached.
+func (r *Batch) With(fn func(*Tx) error) (err error) {
+	if r.tx == nil {
+		r.tx, err = r.DB.Begin(r.Labels...)
+		if err != nil {
+			return
+		}
+	}
+	err = fn(r.tx)
+	if err != nil {
+		return
+	}
+	r.count++
+	if r.count >= r.Size {
+		err = r.Commit()
+	}
+
+	return
+}
+
+// Commit the pending writes.
+func (r *Batch) Commit() (err error) {
+	if r.tx == nil {
+		return
+	}
+	tx := r.tx
+	r.tx = nil
+	r.count = 0
+	err = tx.Commit()
+	return
+}
+
+// End the batch.
+// The pending (uncommitted) writes are discarded.
+func (r *Batch) End() (err error) {
+	if r.tx == nil {
+		return
+	}
+	tx := r.tx
+	r.tx = nil
+	r.count = 0
+	err = tx.End()
+	return
+}
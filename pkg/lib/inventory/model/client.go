@@ -40,6 +40,10 @@ type DB interface {
 	Watch(Model, EventHandler) (*Watch, error)
 	// End a watch.
 	EndWatch(watch *Watch)
+	// Write a consistent copy of the DB to the file.
+	Snapshot(path string) error
+	// The time of the last committed transaction.
+	LastCommit() time.Time
 }
 
 // Database client.
@@ -112,6 +116,33 @@ func (r *Client) Close(delete bool) (err error) {
 	return
 }
 
+// Write a consistent copy of the DB to the file.
+// An existing file is replaced.
+func (r *Client) Snapshot(path string) (err error) {
+	_ = os.Remove(path)
+	session := r.pool.Reader()
+	defer session.Return()
+	mark := time.Now()
+	_, err = session.db.Exec("VACUUM INTO ?", path)
+	if err != nil {
+		err = liberr.Wrap(err, "path", path)
+		return
+	}
+	r.log.V(3).Info(
+		"snapshot written.",
+		"path",
+		path,
+		"duration",
+		time.Since(mark))
+	return
+}
+
+// The time of the last committed transaction.
+// Zero when nothing committed.
+func (r *Client) LastCommit() time.Time {
+	return r.journal.LastCommit()
+}
+
 // Execute SQL.
 // Delegated to Tx.Execute().
 func (r *Client) Execute(sql string) (result sql.Result, err error) {
@@ -512,6 +543,9 @@ func (r *Tx) Insert(model Model) (err error) {
 }
 
 // Update the model.
+// Only the changed fields are written and reported
+// in the event. Nothing is written (or reported) when
+// no fields changed. The predicate is evaluated either way.
 func (r *Tx) Update(model Model, predicate ...Predicate) (err error) {
 	mark := time.Now()
 	current := Clone(model)
@@ -519,7 +553,19 @@ func (r *Tx) Update(model Model, predicate ...Predicate) (err error) {
 	if err != nil {
 		return
 	}
-	err = Table{r.real}.Update(model, predicate...)
+	changed, err := r.changed(current, model)
+	if err != nil {
+		return
+	}
+	if len(changed) == 0 {
+		err = r.matched(current, predicate...)
+		if err != nil {
+			return
+		}
+		r.unchanged(current, model)
+		return
+	}
+	err = Table{r.real}.Patch(model, changed, predicate...)
 	if err != nil {
 		return
 	}
@@ -529,6 +575,7 @@ func (r *Tx) Update(model Model, predicate ...Predicate) (err error) {
 		Action:  Updated,
 		Model:   current,
 		Updated: model,
+		Changed: changed,
 	}
 	event.append(r.staged)
 	err = r.labeler.Replace(model)
@@ -540,12 +587,79 @@ func (r *Tx) Update(model Model, predicate ...Predicate) (err error) {
 		"update succeeded.",
 		"model",
 		Describe(model),
+		"changed",
+		changed,
 		"duration",
 		time.Since(mark))
 
 	return
 }
 
+// Names of the (mutable) fields changed by the update.
+func (r *Tx) changed(current, model Model) (changed []string, err error) {
+	mdCurrent, err := Inspect(current)
+	if err != nil {
+		return
+	}
+	md, err := Inspect(model)
+	if err != nil {
+		return
+	}
+	for _, f := range md.ChangedFields(mdCurrent) {
+		changed = append(changed, f.Name)
+	}
+
+	return
+}
+
+// The stored model must match the predicate.
+// Returns NotFound when not matched.
+func (r *Tx) matched(current Model, predicate ...Predicate) (err error) {
+	if len(predicate) == 0 {
+		return
+	}
+	md, err := Inspect(current)
+	if err != nil {
+		return
+	}
+	pk := md.PkField()
+	n, err := Table{r.real}.Count(
+		current,
+		And(append([]Predicate{Eq(pk.Name, pk.Value.Interface())}, predicate...)...))
+	if err != nil {
+		return
+	}
+	if n == 0 {
+		err = liberr.Wrap(NotFound)
+	}
+
+	return
+}
+
+// The update is skipped when no fields changed.
+// The auto-incremented fields are reflected in the
+// model as stored so the model is not seen as updated.
+func (r *Tx) unchanged(current, model Model) {
+	mdCurrent, err := Inspect(current)
+	if err != nil {
+		return
+	}
+	md, err := Inspect(model)
+	if err != nil {
+		return
+	}
+	for _, f := range md.Fields {
+		if f.Incremented() {
+			f.Value.Set(*mdCurrent.Field(f.Name).Value)
+		}
+	}
+
+	r.log.V(4).Info(
+		"update skipped (unchanged).",
+		"model",
+		Describe(model))
+}
+
 // Delete (cascading) of the model.
 func (r *Tx) Delete(model Model) (err error) {
 	err = Table{r.real}.Get(model)
@@ -672,6 +786,7 @@ func (r *Tx) delete(model Model) (err error) {
 
 // Report staged events to the journal.
 func (r *Tx) report() {
+	r.journal.Committed()
 	if r.staged.Len() == 0 {
 		return
 	}
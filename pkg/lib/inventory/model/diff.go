@@ -0,0 +1,202 @@
+package model
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+)
+
+// Difference of a table between two DB snapshots.
+// Rows are identified by the primary key. The rows
+// of tables without a primary key are identified by
+// their content and are never updated.
+type TableDiff struct {
+	// Table (model kind).
+	Table string `json:"table"`
+	// Primary keys of the added rows.
+	Added []string `json:"added,omitempty"`
+	// Primary keys of the deleted rows.
+	Deleted []string `json:"deleted,omitempty"`
+	// Primary keys of the updated rows.
+	Updated []string `json:"updated,omitempty"`
+}
+
+// No difference.
+func (r *TableDiff) Empty() bool {
+	return len(r.Added) == 0 && len(r.Deleted) == 0 && len(r.Updated) == 0
+}
+
+// Compare the DB snapshots (files).
+// Only the tables with differences are returned.
+func DiffSnapshot(from, to string) (diff []TableDiff, err error) {
+	before, err := readSnapshot(from)
+	if err != nil {
+		return
+	}
+	after, err := readSnapshot(to)
+	if err != nil {
+		return
+	}
+	tables := map[string]bool{}
+	for table := range before {
+		tables[table] = true
+	}
+	for table := range after {
+		tables[table] = true
+	}
+	diff = []TableDiff{}
+	for table := range tables {
+		d := TableDiff{Table: table}
+		for key, digest := range after[table] {
+			previous, found := before[table][key]
+			switch {
+			case !found:
+				d.Added = append(d.Added, key)
+			case previous != digest:
+				d.Updated = append(d.Updated, key)
+			}
+		}
+		for key := range before[table] {
+			if _, found := after[table][key]; !found {
+				d.Deleted = append(d.Deleted, key)
+			}
+		}
+		if d.Empty() {
+			continue
+		}
+		sort.Strings(d.Added)
+		sort.Strings(d.Deleted)
+		sort.Strings(d.Updated)
+		diff = append(diff, d)
+	}
+	sort.Slice(diff, func(i, j int) bool {
+		return diff[i].Table < diff[j].Table
+	})
+	return
+}
+
+// Read the row digests of each table in the DB snapshot (file).
+// Keyed by table and primary key.
+func readSnapshot(path string) (tables map[string]map[string]string, err error) {
+	db, err := sql.Open("sqlite3", "file:"+path+"?mode=ro")
+	if err != nil {
+		err = liberr.Wrap(err, "path", path)
+		return
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+	names, err := snapshotTables(db)
+	if err != nil {
+		err = liberr.Wrap(err, "path", path)
+		return
+	}
+	tables = map[string]map[string]string{}
+	for _, table := range names {
+		tables[table], err = snapshotRows(db, table)
+		if err != nil {
+			err = liberr.Wrap(err, "path", path, "table", table)
+			return
+		}
+	}
+	return
+}
+
+// List the (model) tables.
+func snapshotTables(db *sql.DB) (names []string, err error) {
+	cursor, err := db.Query(
+		"SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'")
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = cursor.Close()
+	}()
+	for cursor.Next() {
+		name := ""
+		err = cursor.Scan(&name)
+		if err != nil {
+			return
+		}
+		names = append(names, name)
+	}
+	err = cursor.Err()
+	return
+}
+
+// Digest the rows of the table keyed by primary key.
+func snapshotRows(db *sql.DB, table string) (rows map[string]string, err error) {
+	pk, err := snapshotPk(db, table)
+	if err != nil {
+		return
+	}
+	cursor, err := db.Query(fmt.Sprintf("SELECT * FROM %q", table))
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = cursor.Close()
+	}()
+	columns, err := cursor.Columns()
+	if err != nil {
+		return
+	}
+	rows = map[string]string{}
+	values := make([]sql.RawBytes, len(columns))
+	ptrs := make([]interface{}, len(columns))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	for cursor.Next() {
+		err = cursor.Scan(ptrs...)
+		if err != nil {
+			return
+		}
+		hash := sha256.New()
+		key := ""
+		for i, column := range columns {
+			hash.Write(values[i])
+			hash.Write([]byte{0})
+			if column == pk {
+				key = string(values[i])
+			}
+		}
+		digest := hex.EncodeToString(hash.Sum(nil))
+		if pk == "" {
+			key = digest
+		}
+		rows[key] = digest
+	}
+	err = cursor.Err()
+	return
+}
+
+// Find the primary key column of the table.
+// Empty when the table has no primary key.
+func snapshotPk(db *sql.DB, table string) (pk string, err error) {
+	cursor, err := db.Query(fmt.Sprintf("PRAGMA table_info(%q)", table))
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = cursor.Close()
+	}()
+	for cursor.Next() {
+		var cid, notNull, primary int
+		var name, kind string
+		var value sql.NullString
+		err = cursor.Scan(&cid, &name, &kind, &notNull, &value, &primary)
+		if err != nil {
+			return
+		}
+		if primary == 1 {
+			pk = name
+		}
+	}
+	err = cursor.Err()
+	return
+}
@@ -44,6 +44,25 @@ func (r *Definition) MutableFields() []*Field {
 	return list
 }
 
+// Get the mutable `Fields` with values that differ from
+// the `other` (stored) definition of the same model kind.
+// The values are compared as persisted (encoded). The
+// auto-incremented fields are not compared.
+func (r *Definition) ChangedFields(other *Definition) []*Field {
+	list := []*Field{}
+	for _, f := range r.MutableFields() {
+		if f.Incremented() {
+			continue
+		}
+		stored := other.Field(f.Name)
+		if stored == nil || f.Pull() != stored.Pull() {
+			list = append(list, f)
+		}
+	}
+
+	return list
+}
+
 // Get the natural key `Fields` for the model.
 func (r *Definition) KeyFields() []*Field {
 	list := []*Field{}
@@ -3,6 +3,7 @@ package model
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	liberr "github.com/kubev2v/forklift/pkg/lib/error"
 	fb "github.com/kubev2v/forklift/pkg/lib/filebacked"
@@ -36,6 +37,8 @@ type Event struct {
 	Action uint8
 	// The updated model.
 	Updated Model
+	// Names of the fields changed by the update.
+	Changed []string
 }
 
 // Get whether the event has the specified label.
@@ -85,9 +88,10 @@ func (r *Event) String() string {
 //	Event.Updated (optional)
 func (r *Event) append(list *fb.List) {
 	list.Append(Event{
-		ID:     r.ID,
-		Labels: r.Labels,
-		Action: r.Action,
+		ID:      r.ID,
+		Labels:  r.Labels,
+		Action:  r.Action,
+		Changed: r.Changed,
 	})
 	list.Append(r.Model)
 	if r.Action == Updated {
@@ -294,6 +298,8 @@ type Journal struct {
 	log logging.LevelLogger
 	// List of registered watches.
 	watches []*Watch
+	// Last committed transaction.
+	committed time.Time
 }
 
 // Watch a `watch` of the specified model.
@@ -356,6 +362,22 @@ func (r *Journal) Report(staged *fb.List) {
 	}
 }
 
+// Transaction committed.
+// Records the time of the (last) commit.
+func (r *Journal) Committed() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.committed = time.Now()
+}
+
+// The time of the last committed transaction.
+// Zero when nothing committed.
+func (r *Journal) LastCommit() time.Time {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.committed
+}
+
 // Close the journal.
 // End all watches.
 func (r *Journal) Close() (err error) {
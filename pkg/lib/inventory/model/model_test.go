@@ -131,6 +131,7 @@ type TestEvent struct {
 	action  uint8
 	model   *TestObject
 	updated *TestObject
+	changed []string
 }
 
 type TestHandler struct {
@@ -171,6 +172,7 @@ func (w *TestHandler) Updated(e Event) {
 			action:  e.Action,
 			model:   object,
 			updated: e.Updated.(*TestObject),
+			changed: e.Changed,
 		})
 		w.updated = append(w.updated, object.ID)
 	}
@@ -527,6 +529,7 @@ func TestTransactions(t *testing.T) {
 		&TestObject{})
 	err := DB.Open(true)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(DB.LastCommit().IsZero()).To(gomega.BeTrue())
 	for i := 0; i < 10; i++ {
 		// Begin
 		tx, err := DB.Begin()
@@ -548,6 +551,170 @@ func TestTransactions(t *testing.T) {
 		err = DB.Get(object)
 		g.Expect(err).ToNot(gomega.HaveOccurred())
 	}
+	g.Expect(DB.LastCommit().IsZero()).To(gomega.BeFalse())
+}
+
+func TestSnapshot(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	DB := New(
+		"/tmp/test-snapshot.db",
+		&TestObject{})
+	err := DB.Open(true)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	err = DB.Insert(&TestObject{ID: 1, Name: "Elmer"})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	err = DB.Snapshot("/tmp/test-snapshot-copy.db")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	// Replaced.
+	err = DB.Snapshot("/tmp/test-snapshot-copy.db")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	copied := New(
+		"/tmp/test-snapshot-copy.db",
+		&TestObject{})
+	err = copied.Open(false)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	object := &TestObject{ID: 1}
+	err = copied.Get(object)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(object.Name).To(gomega.Equal("Elmer"))
+	_ = copied.Close(true)
+	_ = DB.Close(true)
+}
+
+func TestDiffSnapshot(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	DB := New(
+		"/tmp/test-diff.db",
+		&PlainObject{})
+	err := DB.Open(true)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	for i := 1; i <= 3; i++ {
+		err = DB.Insert(&PlainObject{ID: i, Name: "Elmer"})
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+	}
+	err = DB.Snapshot("/tmp/test-diff-1.db")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	err = DB.Update(&PlainObject{ID: 2, Name: "Bugs"})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	err = DB.Delete(&PlainObject{ID: 3})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	err = DB.Insert(&PlainObject{ID: 4, Name: "Daffy"})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	err = DB.Snapshot("/tmp/test-diff-2.db")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	_ = DB.Close(true)
+
+	diff, err := DiffSnapshot("/tmp/test-diff-1.db", "/tmp/test-diff-2.db")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(diff).To(gomega.HaveLen(1))
+	g.Expect(diff[0].Table).To(gomega.Equal("PlainObject"))
+	g.Expect(diff[0].Added).To(gomega.Equal([]string{"4"}))
+	g.Expect(diff[0].Deleted).To(gomega.Equal([]string{"3"}))
+	g.Expect(diff[0].Updated).To(gomega.Equal([]string{"2"}))
+	diff, err = DiffSnapshot("/tmp/test-diff-2.db", "/tmp/test-diff-2.db")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(diff).To(gomega.BeEmpty())
+	// Not found.
+	_, err = DiffSnapshot("/tmp/test-diff-1.db", "/tmp/test-diff-none.db")
+	g.Expect(err).To(gomega.HaveOccurred())
+}
+
+func TestUpdateChanged(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	DB := New("/tmp/test-update-changed.db", &TestObject{})
+	err := DB.Open(true)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	defer func() {
+		_ = DB.Close(true)
+	}()
+	handler := &TestHandler{name: "A"}
+	w, err := DB.Watch(&TestObject{}, handler)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(w).ToNot(gomega.BeNil())
+	object := &TestObject{
+		ID:    1,
+		Name:  "Elmer",
+		Age:   18,
+		Slice: []string{"hello"},
+	}
+	err = DB.Insert(object)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	// Unchanged.
+	object = &TestObject{ID: 1}
+	err = DB.Get(object)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	err = DB.Update(object)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(object.Rev).To(gomega.Equal(1))
+	// Changed.
+	object.Age = 19
+	object.Slice = []string{"hello", "world"}
+	err = DB.Update(object)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(object.Rev).To(gomega.Equal(2))
+	stored := &TestObject{ID: 1}
+	err = DB.Get(stored)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(stored.Rev).To(gomega.Equal(2))
+	g.Expect(stored.Name).To(gomega.Equal("Elmer"))
+	g.Expect(stored.Age).To(gomega.Equal(19))
+	g.Expect(stored.Slice).To(gomega.Equal([]string{"hello", "world"}))
+	// Changed (predicate not matched).
+	stored.Age = 20
+	err = DB.Update(stored, Eq("Rev", 1))
+	g.Expect(errors.Is(err, NotFound)).To(gomega.BeTrue())
+	// Unchanged (predicate not matched).
+	stored.Age = 19
+	err = DB.Update(stored, Eq("Rev", 1))
+	g.Expect(errors.Is(err, NotFound)).To(gomega.BeTrue())
+	// Unchanged (predicate matched).
+	err = DB.Update(stored, Eq("Rev", 2))
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	for i := 0; i < 10; i++ {
+		time.Sleep(time.Millisecond * 10)
+		if len(handler.updated) > 0 {
+			break
+		}
+	}
+	g.Expect(handler.created).To(gomega.Equal([]int{1}))
+	g.Expect(handler.updated).To(gomega.Equal([]int{1}))
+	g.Expect(handler.all[1].changed).To(gomega.ConsistOf("Age", "Slice"))
+}
+
+func TestBatch(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	DB := New("/tmp/test-batch.db", &TestObject{})
+	err := DB.Open(true)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	defer func() {
+		_ = DB.Close(true)
+	}()
+	count := func() int64 {
+		n, err := DB.Count(&TestObject{}, nil)
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		return n
+	}
+	batch := &Batch{DB: DB, Size: 3}
+	for i := 0; i < 5; i++ {
+		object := &TestObject{ID: i, Name: "Elmer"}
+		err = batch.With(func(tx *Tx) error {
+			return tx.Insert(object)
+		})
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+	}
+	// Committed when the size reached.
+	g.Expect(count()).To(gomega.Equal(int64(3)))
+	err = batch.Commit()
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(count()).To(gomega.Equal(int64(5)))
+	// Pending discarded.
+	err = batch.With(func(tx *Tx) error {
+		return tx.Insert(&TestObject{ID: 5, Name: "Elmer"})
+	})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	err = batch.End()
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(count()).To(gomega.Equal(int64(5)))
 }
 
 func TestWithTxSucceeded(t *testing.T) {
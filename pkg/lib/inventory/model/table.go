@@ -354,12 +354,44 @@ func (t Table) Update(model interface{}, predicate ...Predicate) (err error) {
 	if err != nil {
 		return
 	}
+	err = t.update(md, md.MutableFields(), predicate...)
+	return
+}
+
+// Update the specified fields of the model in the DB.
+// The auto-incremented fields are always updated.
+// Nothing is updated when no (mutable) fields are specified.
+// Expects the primary key (PK) to be set.
+func (t Table) Patch(model interface{}, fields []string, predicate ...Predicate) (err error) {
+	md, err := Inspect(model)
+	if err != nil {
+		return
+	}
+	selected := map[string]bool{}
+	for _, name := range fields {
+		selected[strings.ToLower(name)] = true
+	}
+	list := []*Field{}
+	for _, f := range md.MutableFields() {
+		if f.Incremented() || selected[strings.ToLower(f.Name)] {
+			list = append(list, f)
+		}
+	}
+	if len(list) == 0 {
+		return
+	}
+	err = t.update(md, list, predicate...)
+	return
+}
+
+// Update the fields of the model in the DB.
+func (t Table) update(md *Definition, fields []*Field, predicate ...Predicate) (err error) {
 	t.EnsurePk(md)
 	options := &ListOptions{}
 	if len(predicate) > 0 {
 		options.Predicate = And(predicate...)
 	}
-	stmt, err := t.updateSQL(md, options)
+	stmt, err := t.updateSQL(md, fields, options)
 	if err != nil {
 		return
 	}
@@ -764,7 +796,7 @@ func (t Table) insertSQL(md *Definition) (sql string, err error) {
 }
 
 // Build model update SQL.
-func (t Table) updateSQL(md *Definition, options *FilterOptions) (sql string, err error) {
+func (t Table) updateSQL(md *Definition, fields []*Field, options *FilterOptions) (sql string, err error) {
 	tpl := template.New("")
 	tpl, err = tpl.Parse(UpdateSQL)
 	if err != nil {
@@ -780,7 +812,7 @@ func (t Table) updateSQL(md *Definition, options *FilterOptions) (sql string, er
 		bfr,
 		TmplData{
 			Table:   md.Kind,
-			Fields:  md.MutableFields(),
+			Fields:  fields,
 			Options: options,
 			Pk:      md.PkField(),
 		})
@@ -0,0 +1,86 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+)
+
+// Error (reply) object.
+type APIError struct {
+	// HTTP status code.
+	Code int `json:"code"`
+	// Machine-readable reason (X-Reason header or status text).
+	Reason string `json:"reason"`
+	// Error message.
+	Message string `json:"message,omitempty"`
+	// Error details (context).
+	Details map[string]string `json:"details,omitempty"`
+	// The request may be retried.
+	Retryable bool `json:"retryable"`
+	// Request (correlation) ID.
+	RequestID string `json:"requestId,omitempty"`
+}
+
+// Build the error object.
+// The reason defaults to the status text and the
+// details are the context of the (wrapped) error.
+func NewAPIError(code int, reason string, err error) (e *APIError) {
+	e = &APIError{
+		Code:      code,
+		Reason:    reason,
+		Retryable: Retryable(code),
+	}
+	if e.Reason == "" {
+		e.Reason = http.StatusText(code)
+	}
+	if err == nil {
+		return
+	}
+	e.Message = err.Error()
+	le := &liberr.Error{}
+	if errors.As(err, &le) {
+		context := le.Context()
+		for i := 0; i+1 < len(context); i += 2 {
+			if e.Details == nil {
+				e.Details = map[string]string{}
+			}
+			e.Details[fmt.Sprint(context[i])] = fmt.Sprint(context[i+1])
+		}
+	}
+
+	return
+}
+
+// The request (failed with the status) may be retried.
+// The inventory not (yet) in parity, timeouts, throttling
+// and server errors (other than not implemented).
+func Retryable(code int) bool {
+	switch code {
+	case http.StatusPartialContent,
+		http.StatusRequestTimeout,
+		http.StatusTooManyRequests:
+		return true
+	case http.StatusNotImplemented:
+		return false
+	}
+
+	return code >= http.StatusInternalServerError
+}
+
+// Reply with the error object.
+// The error is recorded on the context.
+func ReplyError(ctx *gin.Context, code int, err error) {
+	if err != nil {
+		_ = ctx.Error(err)
+	}
+	e := NewAPIError(
+		code,
+		ctx.Writer.Header().Get(ReasonHeader),
+		err)
+	e.RequestID = RequestID(ctx.Request.Context())
+	ctx.JSON(code, e)
+}
@@ -0,0 +1,32 @@
+package web
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+)
+
+func TestNewAPIError(t *testing.T) {
+	e := NewAPIError(http.StatusNotFound, "", nil)
+	if e.Reason != "Not Found" || e.Message != "" || e.Retryable {
+		t.Errorf("unexpected error: %+v", e)
+	}
+	e = NewAPIError(http.StatusInternalServerError, "", liberr.Wrap(errors.New("failed"), "url", "/vms"))
+	if e.Message != "failed" || e.Details["url"] != "/vms" || !e.Retryable {
+		t.Errorf("unexpected error: %+v", e)
+	}
+	for code, retryable := range map[int]bool{
+		http.StatusPartialContent:      true,
+		http.StatusBadRequest:          false,
+		http.StatusTooManyRequests:     true,
+		http.StatusNotImplemented:      false,
+		http.StatusServiceUnavailable:  true,
+		http.StatusInternalServerError: true,
+	} {
+		if Retryable(code) != retryable {
+			t.Errorf("%d: expected retryable: %t", code, retryable)
+		}
+	}
+}
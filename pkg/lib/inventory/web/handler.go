@@ -138,10 +138,56 @@ func (r *Event) String() string {
 		kind)
 }
 
+// Watch writer settings.
+var (
+	// Max number of events buffered for each watch (client).
+	// When exceeded, the buffered events are dropped and the
+	// client is requested to resync (repair the watch).
+	WatchBufferSize = 1000
+	// Timeout of each event written to the (client) socket.
+	WatchWriteTimeout = time.Second * 10
+	// Watch metrics.
+	// Set by the application to record the watches.
+	Metrics WatchMetrics = &StockWatchMetrics{}
+)
+
+// Watch metrics.
+// Called with the watched kind.
+type WatchMetrics interface {
+	// A watch (connection) started.
+	Started(kind string)
+	// A watch (connection) ended.
+	Ended(kind string)
+	// An update has been coalesced.
+	Coalesced(kind string)
+	// A resync has been requested.
+	Resync(kind string)
+}
+
+// Stock watch metrics.
+// Records nothing.
+type StockWatchMetrics struct{}
+
+// A watch (connection) started.
+func (r *StockWatchMetrics) Started(string) {}
+
+// A watch (connection) ended.
+func (r *StockWatchMetrics) Ended(string) {}
+
+// An update has been coalesced.
+func (r *StockWatchMetrics) Coalesced(string) {}
+
+// A resync has been requested.
+func (r *StockWatchMetrics) Resync(string) {}
+
 // Watch (event) writer.
 // The writer is model event handler. Each event
-// is send (forwarded) to the watch client.  This
-// provides the bridge between the model and web layer.
+// is buffered and sent (forwarded) to the watch client
+// by the sender. This provides the bridge between the
+// model and web layer. Repeated updates of the same model
+// are coalesced while buffered. When the buffer overflows,
+// the buffered events are dropped and an error event is
+// sent so that the client repairs (resyncs) the watch.
 type WatchWriter struct {
 	// Watch options.
 	options model.WatchOptions
@@ -149,12 +195,26 @@ type WatchWriter struct {
 	webSocket *websocket.Conn
 	// Resource.
 	builder ResourceBuilder
+	// Watched kind.
+	kind string
 	// Logger.
 	log logging.LevelLogger
+	// Buffered events.
+	buffer []*model.Event
+	// Buffered updates by model PK.
+	updated map[string]*model.Event
+	// The initial (snapshot) events delivered.
+	parity bool
+	// Buffered events dropped.
+	overflow bool
+	// The end event buffered.
+	ending bool
 	// Done.
 	done bool
 	// Mutex lock
 	mu sync.Mutex
+	// Signaled when the buffer changed.
+	cond *sync.Cond
 }
 
 // Watch options.
@@ -163,9 +223,12 @@ func (r *WatchWriter) Options() model.WatchOptions {
 }
 
 // Start the writer.
+// Start the sender.
 // Detect connection closed by peer or broken
 // and end the watch.
 func (r *WatchWriter) Start(watch *model.Watch) {
+	Metrics.Started(r.kind)
+	go r.sender(watch)
 	go func() {
 		time.Sleep(time.Second)
 		defer func() {
@@ -174,7 +237,7 @@ func (r *WatchWriter) Start(watch *model.Watch) {
 		for {
 			event := Event{}
 			err := r.webSocket.ReadJSON(&event)
-			if r.done {
+			if r.isDone() {
 				return
 			}
 			if err != nil {
@@ -195,7 +258,7 @@ func (r *WatchWriter) Start(watch *model.Watch) {
 // Watch has started.
 func (r *WatchWriter) Started(watchID uint64) {
 	r.log.V(3).Info("event: started.")
-	r.send(model.Event{
+	r.queue(&model.Event{
 		ID:     watchID, // send watch ID.
 		Action: model.Started,
 	})
@@ -204,9 +267,12 @@ func (r *WatchWriter) Started(watchID uint64) {
 // Watch has parity.
 func (r *WatchWriter) Parity() {
 	r.log.V(3).Info("event: parity.")
-	r.send(model.Event{
+	r.queue(&model.Event{
 		Action: model.Parity,
 	})
+	r.mu.Lock()
+	r.parity = true
+	r.mu.Unlock()
 }
 
 // A model has been created.
@@ -215,7 +281,7 @@ func (r *WatchWriter) Created(event model.Event) {
 		"event received.",
 		"event",
 		event.String())
-	r.send(event)
+	r.queue(&event)
 }
 
 // A model has been updated.
@@ -224,7 +290,7 @@ func (r *WatchWriter) Updated(event model.Event) {
 		"event received.",
 		"event",
 		event.String())
-	r.send(event)
+	r.queue(&event)
 }
 
 // A model has been deleted.
@@ -233,38 +299,155 @@ func (r *WatchWriter) Deleted(event model.Event) {
 		"event received.",
 		"event",
 		event.String())
-	r.send(event)
+	r.queue(&event)
 }
 
 // An error has occurred delivering an event.
+// The events have been lost so the client
+// is requested to resync.
 func (r *WatchWriter) Error(err error) {
 	r.log.V(3).Info(
 		"event: error",
 		"error",
 		err.Error())
-	r.send(model.Event{
-		Action: model.Error,
-	})
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.drop()
 }
 
 // An event watch has ended.
 func (r *WatchWriter) End() {
 	r.log.V(3).Info("event: ended.")
-	r.send(model.Event{
-		Action: model.End,
-	})
-	r.done = true
-	time.Sleep(50 * time.Millisecond)
-	_ = r.webSocket.Close()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.done {
+		return
+	}
+	r.buffer = append(
+		r.buffer,
+		&model.Event{
+			Action: model.End,
+		})
+	r.ending = true
+	r.cond.Broadcast()
 }
 
-// Write event to the socket.
-func (r *WatchWriter) send(e model.Event) {
+// Buffer the event.
+// The snapshot (initial) events wait for room in the buffer
+// (backpressure). Afterwards, the events are never blocked
+// and the buffer overflow drops the buffered events.
+func (r *WatchWriter) queue(event *model.Event) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	for !r.parity && !r.done && len(r.buffer) >= WatchBufferSize {
+		r.cond.Wait()
+	}
+	if r.done || r.ending || r.overflow {
+		return
+	}
+	switch event.Action {
+	case model.Created, model.Deleted:
+		// Updates are not coalesced across a delete or create.
+		delete(r.updated, event.Model.Pk())
+	case model.Updated:
+		key := event.Model.Pk()
+		if buffered, found := r.updated[key]; found {
+			buffered.Updated = event.Updated
+			buffered.Changed = append(buffered.Changed, event.Changed...)
+			Metrics.Coalesced(r.kind)
+			return
+		}
+		r.updated[key] = event
+	}
+	if len(r.buffer) >= WatchBufferSize {
+		r.drop()
+		return
+	}
+	r.buffer = append(r.buffer, event)
+	r.cond.Broadcast()
+}
+
+// Drop the buffered events and request the client to resync.
+// The watch is ended after the error event is sent.
+// Must be called with the lock held.
+func (r *WatchWriter) drop() {
+	if r.done || r.ending || r.overflow {
+		return
+	}
+	r.log.Info(
+		"events dropped, resync requested.",
+		"dropped",
+		len(r.buffer))
+	r.buffer = []*model.Event{
+		{
+			Action: model.Error,
+		},
+	}
+	r.updated = map[string]*model.Event{}
+	r.overflow = true
+	r.cond.Broadcast()
+	Metrics.Resync(r.kind)
+}
+
+// Get the next buffered event.
+// Blocks until an event is buffered.
+func (r *WatchWriter) next() (event *model.Event, overflow bool, hasNext bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for !r.done && len(r.buffer) == 0 {
+		r.cond.Wait()
+	}
 	if r.done {
 		return
 	}
+	event = r.buffer[0]
+	r.buffer = r.buffer[1:]
+	if event.Action == model.Updated {
+		key := event.Model.Pk()
+		if r.updated[key] == event {
+			delete(r.updated, key)
+		}
+	}
+	overflow = r.overflow
+	hasNext = true
+	r.cond.Broadcast()
+	return
+}
+
+// Send the buffered events to the socket.
+// The watch is ended when the socket write fails (or
+// times out) or after the resync has been requested.
+// The socket is closed when the watch has ended.
+func (r *WatchWriter) sender(watch *model.Watch) {
+	defer func() {
+		r.mu.Lock()
+		r.done = true
+		r.buffer = nil
+		r.cond.Broadcast()
+		r.mu.Unlock()
+		Metrics.Ended(r.kind)
+		time.Sleep(50 * time.Millisecond)
+		_ = r.webSocket.Close()
+		r.log.V(3).Info("sender stopped.")
+	}()
+	for {
+		event, overflow, hasNext := r.next()
+		if !hasNext {
+			return
+		}
+		err := r.send(event)
+		if event.Action == model.End {
+			return
+		}
+		if err != nil || overflow {
+			// The end event follows.
+			watch.End()
+		}
+	}
+}
+
+// Write event to the socket.
+func (r *WatchWriter) send(e *model.Event) (err error) {
 	event := Event{
 		ID:     e.ID,
 		Labels: e.Labels,
@@ -276,15 +459,26 @@ func (r *WatchWriter) send(e model.Event) {
 	if e.Updated != nil {
 		event.Updated = r.builder(e.Updated)
 	}
-	err := r.webSocket.WriteJSON(event)
+	_ = r.webSocket.SetWriteDeadline(time.Now().Add(WatchWriteTimeout))
+	err = r.webSocket.WriteJSON(event)
 	if err != nil {
 		r.log.V(4).Error(err, "websocket send failed.")
+		return
 	}
 
 	r.log.V(5).Info(
 		"event sent.",
 		"event",
 		event)
+
+	return
+}
+
+// The writer is done.
+func (r *WatchWriter) isDone() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.done
 }
 
 // Watched (handler).
@@ -311,6 +505,11 @@ func (h *Watched) Prepare(ctx *gin.Context) int {
 	return http.StatusOK
 }
 
+// Watch options requested.
+func (h *Watched) WatchOptions() model.WatchOptions {
+	return h.options
+}
+
 // Watch model.
 func (r *Watched) Watch(
 	ctx *gin.Context,
@@ -336,10 +535,13 @@ func (r *Watched) Watch(
 		options:   r.options,
 		webSocket: socket,
 		builder:   rb,
+		kind:      ref.ToKind(m),
+		updated:   map[string]*model.Event{},
 		log: logging.WithName(name).WithValues(
 			"peer",
 			socket.RemoteAddr()),
 	}
+	writer.cond = sync.NewCond(&writer.mu)
 	watch, err := db.Watch(m, writer)
 	if err != nil {
 		_ = socket.Close()
@@ -400,5 +602,5 @@ func (h *SchemaHandler) List(ctx *gin.Context) {
 
 // Not supported.
 func (h SchemaHandler) Get(ctx *gin.Context) {
-	ctx.Status(http.StatusMethodNotAllowed)
+	ReplyError(ctx, http.StatusMethodNotAllowed, nil)
 }
@@ -0,0 +1,88 @@
+package web
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+)
+
+func newTestWriter() (writer *WatchWriter) {
+	writer = &WatchWriter{
+		kind:    "Label",
+		updated: map[string]*model.Event{},
+		parity:  true,
+		log:     logging.WithName("test"),
+	}
+	writer.cond = sync.NewCond(&writer.mu)
+	return
+}
+
+func TestWatchWriterCoalesced(t *testing.T) {
+	writer := newTestWriter()
+	a := &model.Label{PK: "a"}
+	b := &model.Label{PK: "b"}
+	writer.Updated(model.Event{Action: model.Updated, Model: a, Updated: &model.Label{PK: "a", Value: "1"}})
+	writer.Updated(model.Event{Action: model.Updated, Model: b, Updated: &model.Label{PK: "b", Value: "1"}})
+	writer.Updated(model.Event{Action: model.Updated, Model: a, Updated: &model.Label{PK: "a", Value: "2"}})
+	if len(writer.buffer) != 2 {
+		t.Fatalf("expected 2 buffered events, found: %d", len(writer.buffer))
+	}
+	event, _, _ := writer.next()
+	if event.Model != a || event.Updated.(*model.Label).Value != "2" {
+		t.Errorf("expected the updates of a coalesced")
+	}
+	// Not coalesced once sent.
+	writer.Updated(model.Event{Action: model.Updated, Model: a, Updated: &model.Label{PK: "a", Value: "3"}})
+	if len(writer.buffer) != 2 {
+		t.Fatalf("expected 2 buffered events, found: %d", len(writer.buffer))
+	}
+}
+
+func TestWatchWriterOverflow(t *testing.T) {
+	size := WatchBufferSize
+	defer func() {
+		WatchBufferSize = size
+	}()
+	WatchBufferSize = 3
+	writer := newTestWriter()
+	for _, pk := range []string{"a", "b", "c", "d"} {
+		writer.Created(model.Event{Action: model.Created, Model: &model.Label{PK: pk}})
+	}
+	if len(writer.buffer) != 1 || writer.buffer[0].Action != model.Error {
+		t.Fatalf("expected the events dropped and the resync requested")
+	}
+	// Dropped until the watch has ended.
+	writer.Created(model.Event{Action: model.Created, Model: &model.Label{PK: "e"}})
+	writer.End()
+	if len(writer.buffer) != 2 || writer.buffer[1].Action != model.End {
+		t.Fatalf("expected only the end event buffered")
+	}
+	_, overflow, _ := writer.next()
+	if !overflow {
+		t.Errorf("expected the overflow reported")
+	}
+}
+
+func TestWatchWriterNotCoalescedAcrossDelete(t *testing.T) {
+	writer := newTestWriter()
+	a := &model.Label{PK: "a"}
+	writer.Updated(model.Event{Action: model.Updated, Model: a, Updated: &model.Label{PK: "a", Value: "1"}})
+	writer.Deleted(model.Event{Action: model.Deleted, Model: a})
+	writer.Created(model.Event{Action: model.Created, Model: &model.Label{PK: "a", Value: "2"}})
+	writer.Updated(model.Event{Action: model.Updated, Model: a, Updated: &model.Label{PK: "a", Value: "3"}})
+	if len(writer.buffer) != 4 {
+		t.Fatalf("expected 4 buffered events, found: %d", len(writer.buffer))
+	}
+	expected := []uint8{model.Updated, model.Deleted, model.Created, model.Updated}
+	for i, action := range expected {
+		event, _, _ := writer.next()
+		if event.Action != action {
+			t.Fatalf("event %d: expected action: %d, found: %d", i, action, event.Action)
+		}
+	}
+	if len(writer.updated) != 0 {
+		t.Errorf("expected no pending updates")
+	}
+}
@@ -0,0 +1,439 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	pathlib "path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Routes.
+const (
+	OpenAPIRoot = "/openapi.json"
+)
+
+// OpenAPI version.
+const (
+	OpenAPIVersion = "3.0.3"
+)
+
+// Media type.
+const (
+	JSONMediaType = "application/json"
+)
+
+// Route schema.
+// Describes the request and response (body) of a route.
+type RouteSchema struct {
+	// HTTP method.
+	Method string
+	// Route path.
+	Path string
+	// Query parameters.
+	Query []string
+	// Request (body) resource.
+	Request interface{}
+	// Response (body) resource.
+	Response interface{}
+}
+
+// OpenAPI (v3) document.
+type OpenAPI struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       OpenAPIInfo          `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components OpenAPIComponents    `json:"components"`
+}
+
+// OpenAPI info.
+type OpenAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// OpenAPI components.
+type OpenAPIComponents struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// OpenAPI path item.
+// Operations keyed by (lower-case) method.
+type PathItem map[string]*Operation
+
+// OpenAPI operation.
+type Operation struct {
+	OperationID string              `json:"operationId"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+// OpenAPI parameter.
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+// OpenAPI request body.
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// OpenAPI response.
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// OpenAPI media type.
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// OpenAPI (JSON) schema.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+// OpenAPI (document) handler.
+// The document is generated using the routes registered
+// with the router and the (optional) route schemas.
+type OpenAPIHandler struct {
+	// The `gin` router.
+	router *gin.Engine
+	// API title.
+	Title string
+	// API version.
+	Version string
+	// Route schemas.
+	Schemas []RouteSchema
+}
+
+// Add routes.
+func (h *OpenAPIHandler) AddRoutes(r *gin.Engine) {
+	r.GET(OpenAPIRoot, h.Get)
+	h.router = r
+}
+
+// Get the document.
+func (h *OpenAPIHandler) Get(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, h.Document(h.router.Routes()))
+}
+
+// Build the document for the routes.
+func (h *OpenAPIHandler) Document(routes gin.RoutesInfo) (doc *OpenAPI) {
+	doc = &OpenAPI{
+		OpenAPI: OpenAPIVersion,
+		Info: OpenAPIInfo{
+			Title:   h.Title,
+			Version: h.Version,
+		},
+		Paths: map[string]*PathItem{},
+		Components: OpenAPIComponents{
+			Schemas: map[string]*Schema{},
+		},
+	}
+	builder := schemaBuilder{
+		components: doc.Components.Schemas,
+		qualifiers: map[string]string{},
+		packages:   map[string]string{},
+	}
+	described := map[string]*RouteSchema{}
+	for i := range h.Schemas {
+		schema := &h.Schemas[i]
+		described[h.key(schema.Method, schema.Path)] = schema
+	}
+	sort.Slice(
+		routes,
+		func(i, j int) bool {
+			return routes[i].Path < routes[j].Path
+		})
+	for _, route := range routes {
+		path := h.path(route.Path)
+		item, found := doc.Paths[path]
+		if !found {
+			item = &PathItem{}
+			doc.Paths[path] = item
+		}
+		method := strings.ToLower(route.Method)
+		if _, found := (*item)[method]; found {
+			continue
+		}
+		op := &Operation{
+			OperationID: h.operationID(method, route.Path),
+			Responses: map[string]Response{
+				"200": {Description: "OK"},
+				"default": {
+					Description: "Error",
+					Content: map[string]MediaType{
+						JSONMediaType: {
+							Schema: builder.build(reflect.TypeOf(APIError{})),
+						},
+					},
+				},
+			},
+		}
+		for _, name := range h.params(route.Path) {
+			op.Parameters = append(
+				op.Parameters,
+				Parameter{
+					Name:     name,
+					In:       "path",
+					Required: true,
+					Schema:   &Schema{Type: "string"},
+				})
+		}
+		if schema, found := described[h.key(route.Method, route.Path)]; found {
+			for _, name := range schema.Query {
+				op.Parameters = append(
+					op.Parameters,
+					Parameter{
+						Name:   name,
+						In:     "query",
+						Schema: &Schema{Type: "string"},
+					})
+			}
+			if schema.Request != nil {
+				op.RequestBody = &RequestBody{
+					Required: true,
+					Content: map[string]MediaType{
+						JSONMediaType: {
+							Schema: builder.build(reflect.TypeOf(schema.Request)),
+						},
+					},
+				}
+			}
+			if schema.Response != nil {
+				op.Responses["200"] = Response{
+					Description: "OK",
+					Content: map[string]MediaType{
+						JSONMediaType: {
+							Schema: builder.build(reflect.TypeOf(schema.Response)),
+						},
+					},
+				}
+			}
+		}
+		(*item)[method] = op
+	}
+
+	return
+}
+
+// Route key.
+func (h *OpenAPIHandler) key(method, path string) string {
+	return strings.ToUpper(method) + " " + pathlib.Join("/", path)
+}
+
+// Document path.
+// The `gin` (:name and *name) parameters are converted.
+func (h *OpenAPIHandler) path(path string) string {
+	part := strings.Split(pathlib.Join("/", path), "/")
+	for i, p := range part {
+		if strings.HasPrefix(p, ":") || strings.HasPrefix(p, "*") {
+			part[i] = "{" + p[1:] + "}"
+		}
+	}
+
+	return strings.Join(part, "/")
+}
+
+// Path parameters.
+func (h *OpenAPIHandler) params(path string) (names []string) {
+	for _, p := range strings.Split(path, "/") {
+		if strings.HasPrefix(p, ":") || strings.HasPrefix(p, "*") {
+			names = append(names, p[1:])
+		}
+	}
+
+	return
+}
+
+// Operation ID.
+// Example: GET /providers/:provider => getProvidersByProvider.
+func (h *OpenAPIHandler) operationID(method, path string) string {
+	id := method
+	for _, p := range strings.Split(path, "/") {
+		by := false
+		if strings.HasPrefix(p, ":") || strings.HasPrefix(p, "*") {
+			by = true
+			p = p[1:]
+		}
+		if p == "" {
+			continue
+		}
+		if by {
+			id += "By"
+		}
+		for _, w := range strings.FieldsFunc(
+			p,
+			func(r rune) bool {
+				return r == '-' || r == '_' || r == '.'
+			}) {
+			id += strings.ToUpper(w[:1]) + w[1:]
+		}
+	}
+
+	return id
+}
+
+// Invalid component (name) characters.
+var invalidComponent = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+
+// Builds (JSON) schemas using reflection.
+// Named structs are added to the components and referenced.
+type schemaBuilder struct {
+	components map[string]*Schema
+	// Qualifiers by package path.
+	qualifiers map[string]string
+	// Package paths by qualifier.
+	packages map[string]string
+}
+
+// Build the schema for the type.
+func (r *schemaBuilder) build(t reflect.Type) (schema *Schema) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	schema = &Schema{}
+	switch {
+	case t == reflect.TypeOf(time.Time{}):
+		schema.Type = "string"
+		schema.Format = "date-time"
+		return
+	case t.Implements(marshaler) || reflect.PointerTo(t).Implements(marshaler):
+		return
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		schema.Type = "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		schema.Type = "integer"
+		schema.Format = "int32"
+	case reflect.Int64, reflect.Uint64:
+		schema.Type = "integer"
+		schema.Format = "int64"
+	case reflect.Float32:
+		schema.Type = "number"
+		schema.Format = "float"
+	case reflect.Float64:
+		schema.Type = "number"
+		schema.Format = "double"
+	case reflect.String:
+		schema.Type = "string"
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			schema.Type = "string"
+			schema.Format = "byte"
+			break
+		}
+		schema.Type = "array"
+		schema.Items = r.build(t.Elem())
+	case reflect.Map:
+		schema.Type = "object"
+		schema.AdditionalProperties = r.build(t.Elem())
+	case reflect.Struct:
+		if t.Name() == "" {
+			schema = r.object(t)
+			break
+		}
+		name := r.name(t)
+		if _, found := r.components[name]; !found {
+			// Reserved for recursive references.
+			r.components[name] = &Schema{}
+			r.components[name] = r.object(t)
+		}
+		schema.Ref = "#/components/schemas/" + name
+	}
+
+	return
+}
+
+// Build the object schema for the struct.
+// Fields are named and omitted as by the json package.
+func (r *schemaBuilder) object(t reflect.Type) (schema *Schema) {
+	schema = &Schema{
+		Type:       "object",
+		Properties: map[string]*Schema{},
+	}
+	r.fields(t, schema)
+	return
+}
+
+// Add the (json) fields of the struct.
+// Embedded structs are flattened.
+func (r *schemaBuilder) fields(t reflect.Type, schema *Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			if !ft.Implements(marshaler) && !reflect.PointerTo(ft).Implements(marshaler) {
+				r.fields(ft, schema)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if strings.Contains(options, "string") {
+			schema.Properties[name] = &Schema{Type: "string"}
+			continue
+		}
+		schema.Properties[name] = r.build(f.Type)
+	}
+}
+
+// Component name.
+// Qualified by the last two elements of the package path.
+// Example: model/vsphere.VM => model.vsphere.VM.
+// Further qualified when used by another package.
+func (r *schemaBuilder) name(t reflect.Type) string {
+	pkg := t.PkgPath()
+	qualifier, found := r.qualifiers[pkg]
+	if !found {
+		part := strings.Split(pkg, "/")
+		for n := 2; ; n++ {
+			qualified := part
+			if len(part) > n {
+				qualified = part[len(part)-n:]
+			}
+			qualifier = strings.Join(qualified, ".")
+			if _, found := r.packages[qualifier]; !found || len(qualified) == len(part) {
+				break
+			}
+		}
+		r.qualifiers[pkg] = qualifier
+		r.packages[qualifier] = pkg
+	}
+
+	return invalidComponent.ReplaceAllString(qualifier+"."+t.Name(), "_")
+}
+
+// JSON marshaller.
+var marshaler = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
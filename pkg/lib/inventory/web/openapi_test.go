@@ -0,0 +1,81 @@
+package web
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type testBase struct {
+	ID string `json:"id"`
+}
+
+type testNode struct {
+	testBase
+	Name     string      `json:"name"`
+	Created  time.Time   `json:"created"`
+	Children []*testNode `json:"children"`
+	Parent   *testNode   `json:"-"`
+	hidden   string
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handler := func(*gin.Context) {}
+	router.GET("/nodes", handler)
+	router.GET("/nodes/", handler)
+	router.GET("/nodes/:node", handler)
+	router.POST("/nodes", handler)
+	h := &OpenAPIHandler{
+		Title:   "test",
+		Version: "1.0.0",
+		Schemas: []RouteSchema{
+			{Method: http.MethodGet, Path: "nodes", Query: []string{"detail"}, Response: []testNode{}},
+			{Method: http.MethodGet, Path: "/nodes/:node", Response: testNode{}},
+		},
+	}
+	doc := h.Document(router.Routes())
+	if len(doc.Paths) != 2 {
+		t.Fatalf("expected 2 paths, found: %d", len(doc.Paths))
+	}
+	item, found := doc.Paths["/nodes/{node}"]
+	if !found {
+		t.Fatalf("expected the path parameter converted")
+	}
+	get := (*item)["get"]
+	if get.OperationID != "getNodesByNode" {
+		t.Errorf("unexpected operation ID: %s", get.OperationID)
+	}
+	if len(get.Parameters) != 1 || get.Parameters[0].In != "path" {
+		t.Errorf("expected the path parameter")
+	}
+	list := (*doc.Paths["/nodes"])["get"]
+	if len(list.Parameters) != 1 || list.Parameters[0].In != "query" {
+		t.Errorf("expected the query parameter")
+	}
+	schema := list.Responses["200"].Content[JSONMediaType].Schema
+	if schema.Type != "array" || schema.Items.Ref != "#/components/schemas/inventory.web.testNode" {
+		t.Errorf("expected an array of the referenced node")
+	}
+	node := doc.Components.Schemas["inventory.web.testNode"]
+	for _, name := range []string{"id", "name", "created", "children"} {
+		if _, found := node.Properties[name]; !found {
+			t.Errorf("expected property: %s", name)
+		}
+	}
+	if len(node.Properties) != 4 {
+		t.Errorf("expected 4 properties, found: %d", len(node.Properties))
+	}
+	if node.Properties["created"].Format != "date-time" {
+		t.Errorf("expected the time formatted")
+	}
+	if node.Properties["children"].Items.Ref == "" {
+		t.Errorf("expected the (recursive) children referenced")
+	}
+	if (*doc.Paths["/nodes"])["post"].Responses["200"].Content != nil {
+		t.Errorf("expected the (not described) response without content")
+	}
+}
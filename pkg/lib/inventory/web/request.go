@@ -0,0 +1,120 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+)
+
+// Header.
+const (
+	// Request (correlation) ID.
+	RequestIDHeader = "X-Request-ID"
+)
+
+// Max length of a (propagated) request ID.
+const MaxRequestID = 128
+
+// Request logger.
+var requestLog = logging.WithName("web|request")
+
+// Request context key of the request ID.
+type requestIDKey struct{}
+
+// Set the request ID in the context.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// The request ID in the context.
+// Empty when not set.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// Request middleware.
+// Assigns the request ID (unless propagated by the caller) and
+// sets it in the reply header and the request context. The request
+// is logged (structured) when completed.
+func Request() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		started := time.Now()
+		id := ctx.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.New().String()
+		}
+		ctx.Header(RequestIDHeader, id)
+		ctx.Request = ctx.Request.WithContext(
+			WithRequestID(ctx.Request.Context(), id))
+		ctx.Next()
+		fields := []interface{}{
+			"id",
+			id,
+			"method",
+			ctx.Request.Method,
+			"route",
+			ctx.FullPath(),
+			"path",
+			ctx.Request.URL.Path,
+			"status",
+			ctx.Writer.Status(),
+			"latency",
+			time.Since(started).String(),
+			"caller",
+			ctx.ClientIP(),
+		}
+		if last := ctx.Errors.Last(); last != nil {
+			fields = append(fields, "error", last.Error())
+		}
+		requestLog.Info("request completed.", fields...)
+	}
+}
+
+// Propagated request IDs must be printable
+// and no longer than MaxRequestID.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > MaxRequestID {
+		return false
+	}
+	for _, r := range id {
+		if r < 0x21 || r > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Request ID transport.
+// Sets the header of the (downstream) requests using the
+// request ID in the request context.
+type RequestIDTransport struct {
+	// Wrapped transport.
+	Transport http.RoundTripper
+}
+
+// Send the request.
+func (r *RequestIDTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	id := RequestID(request.Context())
+	if id != "" && request.Header.Get(RequestIDHeader) == "" {
+		request = request.Clone(request.Context())
+		request.Header.Set(RequestIDHeader, id)
+	}
+
+	return transport.RoundTrip(request)
+}
+
+// Wrap the transport with the RequestIDTransport.
+// Signature compatible with the k8s rest config Wrap().
+func WrapRequestID(transport http.RoundTripper) http.RoundTripper {
+	return &RequestIDTransport{Transport: transport}
+}
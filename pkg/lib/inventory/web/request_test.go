@@ -0,0 +1,69 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type roundTripper func(*http.Request) (*http.Response, error)
+
+func (f roundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	return f(request)
+}
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Request())
+	router.GET("/things/:thing", func(ctx *gin.Context) {
+		ReplyError(ctx, http.StatusNotFound, nil)
+	})
+	// Propagated.
+	request := httptest.NewRequest(http.MethodGet, "/things/x", nil)
+	request.Header.Set(RequestIDHeader, "abc-123")
+	reply := httptest.NewRecorder()
+	router.ServeHTTP(reply, request)
+	if reply.Header().Get(RequestIDHeader) != "abc-123" {
+		t.Errorf("expected the request ID propagated")
+	}
+	e := APIError{}
+	err := json.Unmarshal(reply.Body.Bytes(), &e)
+	if err != nil || e.RequestID != "abc-123" {
+		t.Errorf("expected the request ID in the error: %s", reply.Body.String())
+	}
+	// Assigned when not valid.
+	request = httptest.NewRequest(http.MethodGet, "/things/x", nil)
+	request.Header.Set(RequestIDHeader, strings.Repeat("x", MaxRequestID+1))
+	reply = httptest.NewRecorder()
+	router.ServeHTTP(reply, request)
+	id := reply.Header().Get(RequestIDHeader)
+	if id == "" || len(id) > MaxRequestID {
+		t.Errorf("expected the request ID assigned")
+	}
+}
+
+func TestRequestIDTransport(t *testing.T) {
+	sent := ""
+	transport := WrapRequestID(
+		roundTripper(func(request *http.Request) (*http.Response, error) {
+			sent = request.Header.Get(RequestIDHeader)
+			return &http.Response{StatusCode: http.StatusOK}, nil
+		}))
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request = request.WithContext(WithRequestID(request.Context(), "abc-123"))
+	_, err := transport.RoundTrip(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent != "abc-123" {
+		t.Errorf("expected the request ID sent")
+	}
+	if request.Header.Get(RequestIDHeader) != "" {
+		t.Errorf("expected the request not modified")
+	}
+}
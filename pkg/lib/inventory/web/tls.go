@@ -0,0 +1,95 @@
+package web
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"sync"
+	"time"
+
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+)
+
+// Build the TLS configuration.
+// The certificate is reloaded when the files (mounted from
+// a secret) are updated.
+func (w *WebServer) tlsConfig() (cfg *tls.Config, err error) {
+	keyPair := &keyPair{
+		certificate: w.TLS.Certificate,
+		key:         w.TLS.Key,
+	}
+	_, err = keyPair.get(nil)
+	if err != nil {
+		return
+	}
+	cfg = &tls.Config{
+		MinVersion:     w.TLS.MinVersion,
+		CipherSuites:   w.TLS.CipherSuites,
+		GetCertificate: keyPair.get,
+	}
+	if w.TLS.ClientCA != "" {
+		pem, rErr := os.ReadFile(w.TLS.ClientCA)
+		if rErr != nil {
+			err = liberr.Wrap(rErr, "path", w.TLS.ClientCA)
+			return
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			err = liberr.New("client CA not valid.", "path", w.TLS.ClientCA)
+			return
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	return
+}
+
+// Certificate key pair loaded from files.
+type keyPair struct {
+	// Certificate path.
+	certificate string
+	// Key path.
+	key string
+	// Mutex.
+	mutex sync.Mutex
+	// Loaded certificate.
+	loaded *tls.Certificate
+	// Modification time of the loaded files.
+	modified time.Time
+}
+
+// Get the certificate, reloaded when the files have been modified.
+func (r *keyPair) get(*tls.ClientHelloInfo) (cert *tls.Certificate, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	modified := time.Time{}
+	for _, path := range []string{r.certificate, r.key} {
+		info, sErr := os.Stat(path)
+		if sErr != nil {
+			err = liberr.Wrap(sErr, "path", path)
+			return
+		}
+		if info.ModTime().After(modified) {
+			modified = info.ModTime()
+		}
+	}
+	if r.loaded != nil && !modified.After(r.modified) {
+		cert = r.loaded
+		return
+	}
+	loaded, err := tls.LoadX509KeyPair(r.certificate, r.key)
+	if err != nil {
+		if r.loaded != nil {
+			// Files updated partially.
+			cert = r.loaded
+			err = nil
+			return
+		}
+		err = liberr.Wrap(err, "certificate", r.certificate)
+		return
+	}
+	r.loaded = &loaded
+	r.modified = modified
+	cert = r.loaded
+	return
+}
@@ -0,0 +1,106 @@
+package web
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Write a self-signed certificate and key.
+func writeKeyPair(t *testing.T, dir, name string) (certPath, keyPath string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IsCA:         true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPath = filepath.Join(dir, "tls.crt")
+	keyPath = filepath.Join(dir, "tls.key")
+	err = os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return
+}
+
+func TestTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	certPath, keyPath := writeKeyPair(t, dir, "inventory")
+	w := &WebServer{}
+	w.TLS.Certificate = certPath
+	w.TLS.Key = keyPath
+	w.TLS.MinVersion = tls.VersionTLS13
+	w.TLS.ClientCA = certPath
+	cfg, err := w.tlsConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.MinVersion != tls.VersionTLS13 {
+		t.Errorf("unexpected min version: %d", cfg.MinVersion)
+	}
+	if cfg.ClientAuth != tls.VerifyClientCertIfGiven || cfg.ClientCAs == nil {
+		t.Errorf("client certificates not verified")
+	}
+	w.TLS.ClientCA = filepath.Join(dir, "missing")
+	if _, err = w.tlsConfig(); err == nil {
+		t.Errorf("expected error for missing client CA")
+	}
+}
+
+func TestKeyPairReload(t *testing.T) {
+	dir := t.TempDir()
+	certPath, keyPath := writeKeyPair(t, dir, "first")
+	pair := &keyPair{certificate: certPath, key: keyPath}
+	first, err := pair.get(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cached, err := pair.get(nil)
+	if err != nil || cached != first {
+		t.Fatalf("expected cached certificate")
+	}
+	writeKeyPair(t, dir, "second")
+	later := time.Now().Add(time.Minute)
+	for _, path := range []string{certPath, keyPath} {
+		if err = os.Chtimes(path, later, later); err != nil {
+			t.Fatal(err)
+		}
+	}
+	second, err := pair.get(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := x509.ParseCertificate(second.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Subject.CommonName != "second" {
+		t.Errorf("certificate not reloaded: %s", parsed.Subject.CommonName)
+	}
+}
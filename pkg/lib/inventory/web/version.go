@@ -0,0 +1,278 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// API versions.
+const (
+	V1 = 1
+	V2 = 2
+)
+
+// Route (version) prefixes.
+const (
+	V1Prefix = "/v1"
+	V2Prefix = "/v2"
+)
+
+// Header.
+const (
+	// The (v1) API is deprecated.
+	DeprecationHeader = "Deprecation"
+	// Date after which the (v1) API may be removed.
+	SunsetHeader = "Sunset"
+	// Link to the successor (v2) route.
+	LinkHeader = "Link"
+	// Explains the reason behind the status code.
+	ReasonHeader = "X-Reason"
+)
+
+// Request context key of the API version.
+type versionKey struct{}
+
+// The API version of the request.
+// Unprefixed requests are v1.
+func APIVersion(request *http.Request) int {
+	if version, cast := request.Context().Value(versionKey{}).(int); cast {
+		return version
+	}
+
+	return V1
+}
+
+// Versioned (http) handler.
+// The /v1 and /v2 prefixed requests are served by the (unprefixed)
+// routes with the version set in the request context. The v1 (and
+// unprefixed) replies have the deprecation headers.
+type Versioned struct {
+	// Handler of the (unprefixed) routes.
+	Handler http.Handler
+	// Sunset of the v1 API. Optional.
+	Sunset time.Time
+}
+
+// Serve the request.
+func (h *Versioned) ServeHTTP(w http.ResponseWriter, request *http.Request) {
+	version, path := h.version(request.URL.Path)
+	if path != request.URL.Path {
+		url := *request.URL
+		url.Path = path
+		url.RawPath = ""
+		request = request.WithContext(
+			context.WithValue(
+				request.Context(),
+				versionKey{},
+				version))
+		request.URL = &url
+	}
+	if version == V1 {
+		header := w.Header()
+		header.Set(DeprecationHeader, "true")
+		if !h.Sunset.IsZero() {
+			header.Set(SunsetHeader, h.Sunset.UTC().Format(http.TimeFormat))
+		}
+		header.Set(LinkHeader, "<"+V2Prefix+path+">; rel=\"successor-version\"")
+	}
+
+	h.Handler.ServeHTTP(w, request)
+}
+
+// The version and (unprefixed) path.
+func (h *Versioned) version(path string) (version int, unprefixed string) {
+	version = V1
+	unprefixed = path
+	for v, prefix := range map[int]string{V1: V1Prefix, V2: V2Prefix} {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			version = v
+			unprefixed = strings.TrimPrefix(path, prefix)
+			if unprefixed == "" {
+				unprefixed = "/"
+			}
+			break
+		}
+	}
+
+	return
+}
+
+// Listed resources (v2) envelope.
+type ListEnvelope struct {
+	// Listed resources.
+	Items []json.RawMessage `json:"items"`
+	// Number of resources listed.
+	Count int `json:"count"`
+	// The `offset` parameter passed in the request.
+	Offset int `json:"offset"`
+	// The `limit` parameter passed in the request.
+	Limit int `json:"limit,omitempty"`
+}
+
+// Error (v2) envelope.
+type ErrorEnvelope struct {
+	Error APIError `json:"error"`
+}
+
+// Envelope middleware.
+// Replies to v2 requests are reshaped: listed resources are
+// wrapped in a (page) envelope and errors are replied as objects.
+// Replies not JSON (streams and files) are not buffered.
+func Envelope() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if APIVersion(ctx.Request) != V2 || ctx.IsWebsocket() {
+			ctx.Next()
+			return
+		}
+		writer := &envelopeWriter{ResponseWriter: ctx.Writer}
+		ctx.Writer = writer
+		ctx.Next()
+		ctx.Writer = writer.ResponseWriter
+		if writer.streamed {
+			return
+		}
+		status := writer.Status()
+		body := writer.body.Bytes()
+		switch {
+		case status >= http.StatusBadRequest:
+			body = errorBody(ctx, status, body)
+		case bytes.HasPrefix(bytes.TrimSpace(body), []byte("[")):
+			body = listBody(ctx, body)
+		}
+		if len(body) > 0 {
+			ctx.Writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		}
+		ctx.Writer.WriteHeader(status)
+		_, err := ctx.Writer.Write(body)
+		if err != nil {
+			log.V(4).Info("handler: reply not written.", "error", err.Error())
+		}
+	}
+}
+
+// Build the (v2) error body.
+// Replied errors objects are wrapped.
+func errorBody(ctx *gin.Context, status int, body []byte) []byte {
+	envelope := ErrorEnvelope{}
+	err := json.Unmarshal(body, &envelope.Error)
+	if err != nil || envelope.Error.Code == 0 {
+		var cause error
+		if last := ctx.Errors.Last(); last != nil {
+			cause = last.Err
+		}
+		envelope.Error = *NewAPIError(status, ctx.Writer.Header().Get(ReasonHeader), cause)
+		envelope.Error.RequestID = RequestID(ctx.Request.Context())
+		if cause == nil && len(body) > 0 {
+			message := ""
+			err := json.Unmarshal(body, &message)
+			if err != nil {
+				message = strings.TrimSpace(string(body))
+			}
+			envelope.Error.Message = message
+		}
+	}
+	body, _ = json.Marshal(envelope)
+	return body
+}
+
+// Build the (v2) list body.
+func listBody(ctx *gin.Context, body []byte) []byte {
+	envelope := ListEnvelope{}
+	err := json.Unmarshal(body, &envelope.Items)
+	if err != nil {
+		return body
+	}
+	if envelope.Items == nil {
+		envelope.Items = []json.RawMessage{}
+	}
+	envelope.Count = len(envelope.Items)
+	envelope.Offset, _ = strconv.Atoi(ctx.Query("offset"))
+	envelope.Limit, _ = strconv.Atoi(ctx.Query("limit"))
+	body, _ = json.Marshal(envelope)
+	return body
+}
+
+// Buffers the JSON reply to be reshaped.
+// Other replies are streamed.
+type envelopeWriter struct {
+	gin.ResponseWriter
+	// Reply status.
+	status int
+	// Buffered body.
+	body bytes.Buffer
+	// The reply is streamed.
+	streamed bool
+}
+
+// Record the status.
+func (w *envelopeWriter) WriteHeader(status int) {
+	if w.streamed {
+		return
+	}
+	w.status = status
+}
+
+// Not written until reshaped.
+func (w *envelopeWriter) WriteHeaderNow() {
+	if w.streamed {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+// Buffer (JSON) or stream the body.
+func (w *envelopeWriter) Write(b []byte) (int, error) {
+	if !w.streamed && w.body.Len() == 0 {
+		contentType := w.Header().Get("Content-Type")
+		if !strings.HasPrefix(contentType, "application/json") {
+			w.streamed = true
+			w.ResponseWriter.WriteHeader(w.Status())
+		}
+	}
+	if w.streamed {
+		return w.ResponseWriter.Write(b)
+	}
+
+	return w.body.Write(b)
+}
+
+// Buffer (JSON) or stream the body.
+func (w *envelopeWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Reply status.
+func (w *envelopeWriter) Status() int {
+	if w.status == 0 {
+		return w.ResponseWriter.Status()
+	}
+
+	return w.status
+}
+
+// Written.
+func (w *envelopeWriter) Written() bool {
+	return w.streamed || w.body.Len() > 0
+}
+
+// Body size.
+func (w *envelopeWriter) Size() int {
+	if w.streamed {
+		return w.ResponseWriter.Size()
+	}
+
+	return w.body.Len()
+}
+
+// Flush the streamed body.
+func (w *envelopeWriter) Flush() {
+	if w.streamed {
+		w.ResponseWriter.Flush()
+	}
+}
@@ -0,0 +1,107 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+)
+
+func newVersionedRouter() *Versioned {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Envelope())
+	router.GET("/things", func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, []string{"a", "b"})
+	})
+	router.GET("/things/:thing", func(ctx *gin.Context) {
+		ctx.Header(ReasonHeader, "ThingNotFound")
+		ReplyError(ctx, http.StatusNotFound, liberr.New("thing not found", "thing", ctx.Param("thing")))
+	})
+	router.GET("/log", func(ctx *gin.Context) {
+		ctx.Header("Content-Type", "text/plain")
+		_, _ = ctx.Writer.WriteString("line")
+	})
+	return &Versioned{
+		Handler: router.Handler(),
+		Sunset:  time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func get(h http.Handler, path string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+	return recorder
+}
+
+func TestVersionedV1(t *testing.T) {
+	h := newVersionedRouter()
+	for _, path := range []string{"/things", "/v1/things"} {
+		reply := get(h, path)
+		if reply.Code != http.StatusOK || reply.Body.String() != `["a","b"]` {
+			t.Errorf("%s: unexpected reply: %d %s", path, reply.Code, reply.Body.String())
+		}
+		if reply.Header().Get(DeprecationHeader) != "true" {
+			t.Errorf("%s: expected the deprecation header", path)
+		}
+		if reply.Header().Get(SunsetHeader) != "Fri, 01 Jan 2027 00:00:00 GMT" {
+			t.Errorf("%s: expected the sunset header", path)
+		}
+		if reply.Header().Get(LinkHeader) != `</v2/things>; rel="successor-version"` {
+			t.Errorf("%s: expected the successor link", path)
+		}
+	}
+}
+
+func TestVersionedV2(t *testing.T) {
+	h := newVersionedRouter()
+	// List envelope.
+	reply := get(h, "/v2/things?offset=2&limit=10")
+	if reply.Header().Get(DeprecationHeader) != "" {
+		t.Errorf("expected v2 not deprecated")
+	}
+	list := ListEnvelope{}
+	err := json.Unmarshal(reply.Body.Bytes(), &list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.Count != 2 || list.Offset != 2 || list.Limit != 10 {
+		t.Errorf("unexpected envelope: %s", reply.Body.String())
+	}
+	// Error object.
+	reply = get(h, "/v2/things/x")
+	if reply.Code != http.StatusNotFound {
+		t.Errorf("unexpected status: %d", reply.Code)
+	}
+	failed := ErrorEnvelope{}
+	err = json.Unmarshal(reply.Body.Bytes(), &failed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := APIError{
+		Code:    http.StatusNotFound,
+		Reason:  "ThingNotFound",
+		Message: "thing not found",
+		Details: map[string]string{"thing": "x"},
+	}
+	if !reflect.DeepEqual(failed.Error, expected) {
+		t.Errorf("unexpected error: %s", reply.Body.String())
+	}
+	// Route not found.
+	reply = get(h, "/v2/nothing")
+	failed = ErrorEnvelope{}
+	err = json.Unmarshal(reply.Body.Bytes(), &failed)
+	if err != nil || reply.Code != http.StatusNotFound || failed.Error.Reason != "Not Found" {
+		t.Errorf("unexpected reply: %d %s", reply.Code, reply.Body.String())
+	}
+	// Streamed.
+	reply = get(h, "/v2/log")
+	if reply.Body.String() != "line" {
+		t.Errorf("expected the (not JSON) reply streamed")
+	}
+}
@@ -2,6 +2,7 @@ package web
 
 import (
 	"fmt"
+	"net/http"
 	"regexp"
 	"time"
 
@@ -24,6 +25,8 @@ type WebServer struct {
 	Container *container.Container
 	// Handlers
 	Handlers []RequestHandler
+	// Sunset of the (deprecated) v1 API. Optional.
+	Sunset time.Time
 	// Compiled CORS origins.
 	allowedOrigins []*regexp.Regexp
 	// TLS.
@@ -34,6 +37,13 @@ type WebServer struct {
 		Certificate string
 		// Key path
 		Key string
+		// Client CA path. When set, client certificates
+		// signed by the CA are requested and verified.
+		ClientCA string
+		// Minimum TLS version.
+		MinVersion uint16
+		// Cipher suites.
+		CipherSuites []uint16
 	}
 }
 
@@ -41,28 +51,49 @@ type WebServer struct {
 // Initializes `gin` with routes and CORS origins.
 // Creates an http server to handle TLS
 func (w *WebServer) Start(middleware ...gin.HandlerFunc) {
-	router := gin.Default()
+	router := gin.New()
+	router.Use(gin.Recovery(), Request())
 	router.Use(cors.New(cors.Config{
 		AllowMethods:     []string{"GET"},
-		AllowHeaders:     []string{"Authorization", "Origin"},
+		AllowHeaders:     []string{"Authorization", "Origin", RequestIDHeader},
+		ExposeHeaders:    []string{RequestIDHeader},
 		AllowOriginFunc:  w.allow,
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
+	router.Use(Envelope())
 	for _, h := range middleware {
 		router.Use(h)
 	}
 	w.buildOrigins()
 	w.addRoutes(router)
+	handler := &Versioned{
+		Handler: router.Handler(),
+		Sunset:  w.Sunset,
+	}
 	if w.TLS.Enabled {
+		tlsConfig, err := w.tlsConfig()
+		if err != nil {
+			log.Error(err, "web: failed to configure TLS")
+			return
+		}
+		server := &http.Server{
+			Addr:      w.address(),
+			Handler:   handler,
+			TLSConfig: tlsConfig,
+		}
 		go func() {
-			if err := router.RunTLS(w.address(), w.TLS.Certificate, w.TLS.Key); err != nil {
+			if err := server.ListenAndServeTLS("", ""); err != nil {
 				log.Error(err, "web: failed to start TLS server")
 			}
 		}()
 	} else {
+		server := &http.Server{
+			Addr:    w.address(),
+			Handler: handler,
+		}
 		go func() {
-			if err := router.Run(w.address()); err != nil {
+			if err := server.ListenAndServe(); err != nil {
 				log.Error(err, "web: failed to start server")
 			}
 		}()
@@ -0,0 +1,60 @@
+package notification
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSubscribed(t *testing.T) {
+	sink := Sink{}
+	if !sink.Subscribed("VMFailed") {
+		t.Errorf("Expected a sink without events to be subscribed to all events")
+	}
+	sink.Events = []string{"VMSucceeded"}
+	if sink.Subscribed("VMFailed") {
+		t.Errorf("Expected the sink not to be subscribed to VMFailed")
+	}
+}
+
+func TestSendWebhook(t *testing.T) {
+	var body, contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, _ := io.ReadAll(r.Body)
+		body = string(content)
+		contentType = r.Header.Get("Content-Type")
+	}))
+	defer server.Close()
+
+	sink := Sink{
+		URL:      server.URL,
+		Template: `{"text": "{{.Plan}}: {{.Message}}"}`,
+	}
+	err := sink.Send(&Notification{Event: "VMFailed", Plan: "plan", Message: "VM failed."})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if body != `{"text": "plan: VM failed."}` {
+		t.Errorf("Unexpected body: %s", body)
+	}
+	if contentType != "application/json" {
+		t.Errorf("Unexpected content type: %s", contentType)
+	}
+}
+
+func TestSendWebhookFailed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	sink := Sink{URL: server.URL}
+	if err := sink.Send(&Notification{Event: "VMFailed"}); err == nil {
+		t.Errorf("Expected an error")
+	}
+	sink.URL = "ftp://example.com"
+	if err := sink.Send(&Notification{Event: "VMFailed"}); err == nil {
+		t.Errorf("Expected an error for an unsupported scheme")
+	}
+}
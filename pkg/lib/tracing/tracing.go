@@ -0,0 +1,151 @@
+package tracing
+
+import (
+	"context"
+	"crypto/sha256"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	core "k8s.io/api/core/v1"
+)
+
+// Environment variables.
+const (
+	// Trace context propagated to pods.
+	EnvTraceParent = "TRACEPARENT"
+	EnvTraceState  = "TRACESTATE"
+	// Exporter settings forwarded to pods.
+	EnvEndpoint = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	EnvInsecure = "OTEL_EXPORTER_OTLP_INSECURE"
+)
+
+// Tracer name.
+const TracerName = "github.com/kubev2v/forklift"
+
+// W3C trace context propagator.
+var propagator = propagation.TraceContext{}
+
+// Shutdown flushes and stops the exporter.
+type Shutdown func(ctx context.Context) error
+
+// Set up the global tracer provider exporting to the OTLP (HTTP)
+// endpoint. Tracing is disabled (no-op) when the endpoint is empty.
+func Setup(ctx context.Context, endpoint string, insecure bool, service string) (shutdown Shutdown, err error) {
+	shutdown = func(context.Context) error { return nil }
+	otel.SetTextMapPropagator(propagator)
+	if endpoint == "" {
+		return
+	}
+	options := []otlptracehttp.Option{otlptracehttp.WithEndpointURL(endpoint)}
+	if insecure {
+		options = append(options, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, options...)
+	if err != nil {
+		err = liberr.Wrap(err, "endpoint", endpoint)
+		return
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(
+			resource.NewSchemaless(attribute.String("service.name", service))))
+	otel.SetTracerProvider(provider)
+	shutdown = provider.Shutdown
+	return
+}
+
+// Set up tracing in a pod from the environment propagated by Env().
+// Returns the context of the propagated trace.
+func SetupFromEnv(ctx context.Context, service string) (traced context.Context, shutdown Shutdown, err error) {
+	traced = ctx
+	insecure := os.Getenv(EnvInsecure) == "true"
+	shutdown, err = Setup(ctx, os.Getenv(EnvEndpoint), insecure, service)
+	if err != nil {
+		return
+	}
+	traced = propagator.Extract(
+		ctx,
+		propagation.MapCarrier{
+			"traceparent": os.Getenv(EnvTraceParent),
+			"tracestate":  os.Getenv(EnvTraceState),
+		})
+	return
+}
+
+// Environment propagating the trace context and the
+// exporter settings to a pod.
+func Env(ctx context.Context) (env []core.EnvVar) {
+	endpoint := os.Getenv(EnvEndpoint)
+	if endpoint == "" {
+		return
+	}
+	env = append(
+		env,
+		core.EnvVar{Name: EnvEndpoint, Value: endpoint},
+		core.EnvVar{Name: EnvInsecure, Value: os.Getenv(EnvInsecure)})
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(ctx, carrier)
+	if traceParent := carrier.Get("traceparent"); traceParent != "" {
+		env = append(env, core.EnvVar{Name: EnvTraceParent, Value: traceParent})
+	}
+	if traceState := carrier.Get("tracestate"); traceState != "" {
+		env = append(env, core.EnvVar{Name: EnvTraceState, Value: traceState})
+	}
+	return
+}
+
+// Context joined to the trace identified by the UID. Spans created
+// across reconciles (and by pods) for the same resource (e.g. a migration)
+// belong to the same trace, whose ID is the UID, without persisting
+// the trace context.
+func WithUID(ctx context.Context, uid string) context.Context {
+	parsed, err := uuid.Parse(uid)
+	if err != nil {
+		return ctx
+	}
+	sum := sha256.Sum256([]byte(uid))
+	var spanID trace.SpanID
+	copy(spanID[:], sum[:len(spanID)])
+	parent := trace.NewSpanContext(
+		trace.SpanContextConfig{
+			TraceID:    trace.TraceID(parsed),
+			SpanID:     spanID,
+			TraceFlags: trace.FlagsSampled,
+			Remote:     true,
+		})
+	return trace.ContextWithRemoteSpanContext(ctx, parent)
+}
+
+// Start a span.
+func Start(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(TracerName).Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// Record a span that has already completed.
+func Record(ctx context.Context, name string, started, completed time.Time, attributes ...attribute.KeyValue) {
+	_, span := otel.Tracer(TracerName).Start(
+		ctx,
+		name,
+		trace.WithTimestamp(started),
+		trace.WithAttributes(attributes...))
+	span.End(trace.WithTimestamp(completed))
+}
+
+// End the span, recording the error.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,53 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestWithUID(t *testing.T) {
+	uid := "3f2b8c4e-1d2a-4b6e-9c1f-2a7d5e8b9c0d"
+	spanContext := trace.SpanContextFromContext(WithUID(context.Background(), uid))
+	if spanContext.TraceID().String() != "3f2b8c4e1d2a4b6e9c1f2a7d5e8b9c0d" {
+		t.Errorf("Expected the trace ID to be the UID, got %s", spanContext.TraceID())
+	}
+	if !spanContext.IsRemote() || !spanContext.IsSampled() {
+		t.Errorf("Expected a remote and sampled span context")
+	}
+	again := trace.SpanContextFromContext(WithUID(context.Background(), uid))
+	if !spanContext.Equal(again) {
+		t.Errorf("Expected the same span context for the same UID")
+	}
+	if trace.SpanContextFromContext(WithUID(context.Background(), "invalid")).IsValid() {
+		t.Errorf("Expected no span context for an invalid UID")
+	}
+}
+
+func TestEnv(t *testing.T) {
+	uid := "3f2b8c4e-1d2a-4b6e-9c1f-2a7d5e8b9c0d"
+	ctx := WithUID(context.Background(), uid)
+	if env := Env(ctx); len(env) != 0 {
+		t.Errorf("Expected no environment when tracing is disabled, got %v", env)
+	}
+
+	t.Setenv(EnvEndpoint, "http://collector:4318")
+	env := map[string]string{}
+	for _, v := range Env(ctx) {
+		env[v.Name] = v.Value
+	}
+	if env[EnvEndpoint] != "http://collector:4318" {
+		t.Errorf("Expected the endpoint to be forwarded, got %s", env[EnvEndpoint])
+	}
+	for name, value := range env {
+		t.Setenv(name, value)
+	}
+	traced, _, err := SetupFromEnv(context.Background(), "test")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if trace.SpanContextFromContext(traced).TraceID() != trace.SpanContextFromContext(ctx).TraceID() {
+		t.Errorf("Expected the trace to be propagated through the environment")
+	}
+}
@@ -0,0 +1,77 @@
+package util
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+)
+
+// Host (name or address) usable in a URL.
+// IPv6 addresses are enclosed in brackets.
+func URLHost(host string) string {
+	host = strings.Trim(host, "[]")
+	if ip := net.ParseIP(host); ip != nil && ip.To4() == nil {
+		return "[" + host + "]"
+	}
+	return host
+}
+
+// Host with the default port when not specified.
+// The host may be a name or an (IPv4 or bracketed IPv6) address,
+// optionally with the port.
+func HostPort(host string, port string) string {
+	if _, _, err := net.SplitHostPort(host); err == nil {
+		return host
+	}
+	return net.JoinHostPort(strings.Trim(host, "[]"), port)
+}
+
+// Select the first address of the family.
+// The first address is selected when the family is not specified
+// or no address of the family is found.
+func SelectAddress(addresses []string, network string) (address string) {
+	for _, a := range addresses {
+		ip := net.ParseIP(a)
+		if ip == nil {
+			continue
+		}
+		switch network {
+		case "tcp4":
+			if ip.To4() != nil {
+				return a
+			}
+		case "tcp6":
+			if ip.To4() == nil {
+				return a
+			}
+		}
+	}
+	if len(addresses) > 0 {
+		address = addresses[0]
+	}
+	return
+}
+
+// Dial function of the HTTP clients of the provider.
+// Connections are restricted to the provider address family.
+func ProviderDialer(provider *api.Provider, dialer *net.Dialer) func(context.Context, string, string) (net.Conn, error) {
+	if dialer == nil {
+		dialer = &net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}
+	}
+	network := "tcp"
+	if provider != nil {
+		network = provider.Network()
+	}
+	return func(ctx context.Context, n string, address string) (net.Conn, error) {
+		if n == "tcp" {
+			n = network
+		}
+		return dialer.DialContext(ctx, n, address)
+	}
+}
@@ -0,0 +1,94 @@
+package util
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	liburl "net/url"
+	"testing"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	core "k8s.io/api/core/v1"
+)
+
+func TestURLHost(t *testing.T) {
+	for host, expected := range map[string]string{
+		"vcenter.example.com": "vcenter.example.com",
+		"10.0.0.1":            "10.0.0.1",
+		"fd00::1":             "[fd00::1]",
+		"[fd00::1]":           "[fd00::1]",
+	} {
+		if actual := URLHost(host); actual != expected {
+			t.Errorf("%s: expected %s, got %s", host, expected, actual)
+		}
+	}
+}
+
+func TestHostPort(t *testing.T) {
+	for host, expected := range map[string]string{
+		"vcenter.example.com":      "vcenter.example.com:443",
+		"vcenter.example.com:8443": "vcenter.example.com:8443",
+		"10.0.0.1":                 "10.0.0.1:443",
+		"fd00::1":                  "[fd00::1]:443",
+		"[fd00::1]":                "[fd00::1]:443",
+		"[fd00::1]:8443":           "[fd00::1]:8443",
+	} {
+		if actual := HostPort(host, "443"); actual != expected {
+			t.Errorf("%s: expected %s, got %s", host, expected, actual)
+		}
+	}
+}
+
+func TestSelectAddress(t *testing.T) {
+	addresses := []string{"10.0.0.1", "fd00::1"}
+	for network, expected := range map[string]string{
+		"tcp":  "10.0.0.1",
+		"tcp4": "10.0.0.1",
+		"tcp6": "fd00::1",
+	} {
+		if actual := SelectAddress(addresses, network); actual != expected {
+			t.Errorf("%s: expected %s, got %s", network, expected, actual)
+		}
+	}
+	if actual := SelectAddress([]string{"10.0.0.1"}, "tcp6"); actual != "10.0.0.1" {
+		t.Errorf("expected fallback to the first address, got %s", actual)
+	}
+}
+
+func TestProviderDialer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer server.Close()
+	url, _ := liburl.Parse(server.URL)
+	provider := &api.Provider{}
+	provider.Spec.Settings = map[string]string{api.IPFamily: "IPv4"}
+	conn, err := ProviderDialer(provider, nil)(context.TODO(), "tcp", url.Host)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = conn.Close()
+	provider.Spec.Settings[api.IPFamily] = "IPv6"
+	_, err = ProviderDialer(provider, nil)(context.TODO(), "tcp", url.Host)
+	if err == nil {
+		t.Errorf("expected IPv4 address rejected by the IPv6 dialer")
+	}
+}
+
+func TestGetTlsCertificateIPv6(t *testing.T) {
+	listener, err := net.Listen("tcp6", "[::1]:0")
+	if err != nil {
+		t.Skip("IPv6 not available")
+	}
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	server.Listener = listener
+	server.StartTLS()
+	defer server.Close()
+	url, _ := liburl.Parse(server.URL)
+	crt, err := GetTlsCertificate(&liburl.URL{Host: url.Host}, &core.Secret{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if Fingerprint(crt) != Fingerprint(server.Certificate()) {
+		t.Errorf("unexpected certificate")
+	}
+}
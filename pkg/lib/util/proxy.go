@@ -0,0 +1,26 @@
+package util
+
+import (
+	"net/http"
+	liburl "net/url"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// Proxy used by HTTP clients of the provider.
+// The provider proxy settings take precedence over the environment.
+func ProviderProxy(provider *api.Provider) func(*http.Request) (*liburl.URL, error) {
+	if provider == nil || !provider.HasProxy() {
+		return http.ProxyFromEnvironment
+	}
+	cfg := &httpproxy.Config{
+		HTTPProxy:  provider.Spec.Settings[api.HTTPProxy],
+		HTTPSProxy: provider.Spec.Settings[api.HTTPSProxy],
+		NoProxy:    provider.Spec.Settings[api.NoProxy],
+	}
+	proxy := cfg.ProxyFunc()
+	return func(request *http.Request) (*liburl.URL, error) {
+		return proxy(request.URL)
+	}
+}
@@ -0,0 +1,66 @@
+package util
+
+import (
+	liburl "net/url"
+	"sync"
+	"time"
+
+	core "k8s.io/api/core/v1"
+)
+
+// Default thumbprint cache TTL.
+const ThumbprintTTL = 10 * time.Minute
+
+// Thumbprints fetched from the servers.
+var Thumbprints = &ThumbprintCache{TTL: ThumbprintTTL}
+
+// Cached thumbprint.
+type Thumbprint struct {
+	// SHA-1 thumbprint of the server certificate.
+	Thumbprint string `json:"thumbprint"`
+	// Fetch timestamp.
+	Checked time.Time `json:"checked"`
+}
+
+// Cache of TLS certificate thumbprints by address (host[:port]).
+type ThumbprintCache struct {
+	// Time to live.
+	TTL time.Duration
+	// Mutex.
+	mutex sync.Mutex
+	// Thumbprints by address.
+	content map[string]Thumbprint
+}
+
+// Get the thumbprint of the certificate served at the address.
+// The certificate is fetched when not cached or expired.
+func (r *ThumbprintCache) Get(address string) (thumbprint Thumbprint, err error) {
+	r.mutex.Lock()
+	thumbprint, found := r.content[address]
+	r.mutex.Unlock()
+	if found && time.Since(thumbprint.Checked) < r.TTL {
+		return
+	}
+	crt, err := GetTlsCertificate(&liburl.URL{Host: address}, &core.Secret{})
+	if err != nil {
+		return
+	}
+	thumbprint = Thumbprint{
+		Thumbprint: Fingerprint(crt),
+		Checked:    time.Now(),
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.content == nil {
+		r.content = make(map[string]Thumbprint)
+	}
+	r.content[address] = thumbprint
+	return
+}
+
+// Delete the cached thumbprint.
+func (r *ThumbprintCache) Delete(address string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.content, address)
+}
@@ -0,0 +1,32 @@
+package util
+
+import (
+	"net/http"
+	"net/http/httptest"
+	liburl "net/url"
+	"testing"
+	"time"
+)
+
+func TestThumbprintCache(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	url, _ := liburl.Parse(server.URL)
+	expected := Fingerprint(server.Certificate())
+	cache := &ThumbprintCache{TTL: time.Hour}
+	first, err := cache.Get(url.Host)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Thumbprint != expected {
+		t.Errorf("expected %s, got %s", expected, first.Thumbprint)
+	}
+	server.Close()
+	cached, err := cache.Get(url.Host)
+	if err != nil || cached != first {
+		t.Errorf("expected cached thumbprint, got %v (%v)", cached, err)
+	}
+	cache.Delete(url.Host)
+	if _, err = cache.Get(url.Host); err == nil {
+		t.Errorf("expected error fetching from closed server")
+	}
+}
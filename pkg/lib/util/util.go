@@ -51,9 +51,8 @@ func GetTlsCertificate(url *liburl.URL, secret *core.Secret) (crt *x509.Certific
 		err = liberr.New("URL host or path is empty")
 		return
 	}
-	if url.Port() == "" {
-		host += ":443"
-	}
+	// the host may be an IPv6 address (bracketed or not).
+	host = HostPort(host, "443")
 	// disable verification since we don't trust it yet
 	cfg.InsecureSkipVerify = true
 	conn, err := dialTLSWithTimeout(host, cfg, time.Duration(settings.Settings.TlsConnectionTimeout)*time.Second)
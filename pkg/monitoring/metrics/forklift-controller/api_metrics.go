@@ -0,0 +1,19 @@
+package forklift_controller
+
+import (
+	"path"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Record a provider (vSphere) API call delayed by the provider rate limit.
+func RecordThrottled(provider *api.Provider, wait time.Duration) {
+	labels := prometheus.Labels{
+		"provider": path.Join(provider.Namespace, provider.Name),
+		"type":     provider.Type().String(),
+	}
+	apiThrottledCounter.With(labels).Inc()
+	apiThrottleSeconds.With(labels).Observe(wait.Seconds())
+}
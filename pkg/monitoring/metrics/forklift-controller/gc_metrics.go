@@ -0,0 +1,18 @@
+package forklift_controller
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Count an orphaned artifact deleted by the garbage collector.
+func RecordReclaimed(kind string) {
+	gcReclaimedCounter.With(prometheus.Labels{"kind": kind}).Inc()
+}
+
+// Report the source snapshots that could not be removed.
+func RecordSnapshotsNotRemoved(counts map[string]int) {
+	snapshotsNotRemovedGauge.Reset()
+	for provider, count := range counts {
+		snapshotsNotRemovedGauge.With(prometheus.Labels{"provider": provider}).Set(float64(count))
+	}
+}
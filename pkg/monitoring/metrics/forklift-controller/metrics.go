@@ -114,4 +114,154 @@ var (
 			"target",
 		},
 	)
+
+	// 'provider' - [oVirt, VSphere, Openstack, OVA, Openshift]
+	// 'mode' - [Cold, Warm]
+	// 'target' - [Local, Remote]
+	// 'phase' - [Pipeline step name]
+	migrationPhaseDurationHistogram = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "mtv_migration_phase_duration_seconds",
+		Help:    "Histogram of VM migration pipeline phase durations in seconds",
+		Buckets: []float64{10, 60, 5 * 60, 15 * 60, 3600, 2 * 3600, 5 * 3600, 10 * 3600, 24 * 3600}, // 10s to 24 hours in seconds
+	},
+		[]string{
+			"provider",
+			"mode",
+			"target",
+			"phase",
+		},
+	)
+
+	// 'provider' - [oVirt, VSphere, Openstack, OVA, Openshift]
+	// 'plan' - [Id]
+	// 'vm' - [Id]
+	vmTransferRateGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "mtv_migration_vm_transfer_rate_bytes_per_second",
+		Help: "Disk transfer rate of VMs being migrated in bytes per second",
+	},
+		[]string{
+			"provider",
+			"plan",
+			"vm",
+		},
+	)
+
+	// 'provider' - [oVirt, VSphere, Openstack, OVA, Openshift]
+	// 'mode' - [Cold, Warm]
+	// 'target' - [Local, Remote]
+	// 'phase' - [VM phase on failure]
+	// 'reason' - [Timeout, Unauthorized, NotFound, InsufficientResources, Network, Conversion, Unknown]
+	vmFailureCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "mtv_migration_vm_failures_total",
+		Help: "VM migration failures sorted by provider, mode, target, phase and reason",
+	},
+		[]string{
+			"provider",
+			"mode",
+			"target",
+			"phase",
+			"reason",
+		},
+	)
+
+	// 'provider' - [oVirt, VSphere, Openstack, OVA, Openshift]
+	// 'plan' - [Id]
+	// 'vm' - [Id]
+	vmRPOGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "mtv_migration_vm_rpo_seconds",
+		Help: "Time since the recovery point of VMs being precopied (warm) in seconds",
+	},
+		[]string{
+			"provider",
+			"plan",
+			"vm",
+		},
+	)
+
+	// 'provider' - [oVirt, VSphere, Openstack, OVA, Openshift]
+	// 'plan' - [Id]
+	schedulerQueueGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "mtv_migration_scheduler_queue_depth",
+		Help: "VMs of running migrations waiting to be scheduled",
+	},
+		[]string{
+			"provider",
+			"plan",
+		},
+	)
+
+	// 'provider' - [namespace/name]
+	// 'type' - [oVirt, VSphere, Openstack, OVA, Openshift]
+	apiThrottledCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "mtv_provider_api_throttled_total",
+		Help: "Provider (vSphere) API calls delayed by the provider rate limit",
+	},
+		[]string{
+			"provider",
+			"type",
+		},
+	)
+
+	// 'provider' - [namespace/name]
+	// 'type' - [oVirt, VSphere, Openstack, OVA, Openshift]
+	apiThrottleSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "mtv_provider_api_throttle_wait_seconds",
+		Help:    "Wait of the provider (vSphere) API calls delayed by the provider rate limit",
+		Buckets: prometheus.ExponentialBuckets(0.01, 4, 8),
+	},
+		[]string{
+			"provider",
+			"type",
+		},
+	)
+
+	// 'kind' - [VM, Host, Network, ...]
+	watchConnectionsGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "mtv_inventory_watch_connections",
+		Help: "Open inventory watch (websocket) connections sorted by kind",
+	},
+		[]string{
+			"kind",
+		},
+	)
+
+	// 'kind' - [VM, Host, Network, ...]
+	watchCoalescedCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "mtv_inventory_watch_events_coalesced_total",
+		Help: "Inventory watch updates coalesced with a buffered update of the same model sorted by kind",
+	},
+		[]string{
+			"kind",
+		},
+	)
+
+	// 'kind' - [VM, Host, Network, ...]
+	watchResyncCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "mtv_inventory_watch_resyncs_total",
+		Help: "Inventory watches with the buffered events dropped and the client resync requested sorted by kind",
+	},
+		[]string{
+			"kind",
+		},
+	)
+
+	// 'kind' - [Pod, Secret, ConfigMap, OvirtVolumePopulator, OpenstackVolumePopulator, VSphereXcopyVolumePopulator, Snapshot]
+	gcReclaimedCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "mtv_gc_reclaimed_total",
+		Help: "Orphaned migration artifacts deleted by the garbage collector sorted by kind",
+	},
+		[]string{
+			"kind",
+		},
+	)
+
+	// 'provider' - [vsphere, ovirt, openstack, ...]
+	snapshotsNotRemovedGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "mtv_source_snapshots_not_removed",
+		Help: "Source snapshots created by migrations that could not be removed sorted by provider",
+	},
+		[]string{
+			"provider",
+		},
+	)
 )
@@ -32,6 +32,9 @@ func RecordMigrationMetrics(c client.Client) {
 				continue
 			}
 
+			resetVMMetrics()
+			now := time.Now()
+
 			for _, m := range migrations.Items {
 				plan := api.Plan{}
 				err := c.Get(context.TODO(), client.ObjectKey{Namespace: m.Spec.Plan.Namespace, Name: m.Spec.Plan.Name}, &plan)
@@ -68,6 +71,7 @@ func RecordMigrationMetrics(c client.Client) {
 
 				provider := sourceProvider.Type().String()
 				processMigration(m, provider, mode, target, string(plan.UID))
+				processVMs(m, provider, mode, target, string(plan.UID), now)
 			}
 		}
 	}()
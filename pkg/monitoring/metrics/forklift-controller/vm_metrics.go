@@ -0,0 +1,163 @@
+package forklift_controller
+
+import (
+	"strings"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Canned VM failure reasons.
+const (
+	ReasonTimeout               = "Timeout"
+	ReasonUnauthorized          = "Unauthorized"
+	ReasonNotFound              = "NotFound"
+	ReasonInsufficientResources = "InsufficientResources"
+	ReasonNetwork               = "Network"
+	ReasonConversion            = "Conversion"
+	ReasonUnknown               = "Unknown"
+)
+
+// Keywords of the error reasons mapped to the canned reasons, in order
+// of precedence (e.g. `i/o timeout` is a network failure).
+var failureKeywords = []struct {
+	reason   string
+	keywords []string
+}{
+	{ReasonNetwork, []string{"connection refused", "connection reset", "no route to host", "i/o timeout", "network is unreachable", "no such host"}},
+	{ReasonTimeout, []string{"timed out", "timeout", "deadline exceeded"}},
+	{ReasonUnauthorized, []string{"unauthorized", "forbidden", "permission denied", "authentication"}},
+	{ReasonNotFound, []string{"not found", "does not exist"}},
+	{ReasonInsufficientResources, []string{"no space left", "insufficient", "exceeded quota", "out of memory"}},
+	{ReasonConversion, []string{"virt-v2v", "conversion", "inspection"}},
+}
+
+// Disk transfer steps reporting the progress in MB.
+var transferSteps = []string{"DiskTransfer", "DiskTransferV2v", "Cutover"}
+
+var (
+	processedVMPhases   = make(map[string]struct{})
+	processedVMFailures = make(map[string]struct{})
+	transferSamples     = make(map[string]transferSample)
+)
+
+// Disk transfer progress sample of a VM.
+type transferSample struct {
+	completed int64
+	time      time.Time
+}
+
+// Reset the gauges of the active VMs before the migrations are processed.
+func resetVMMetrics() {
+	vmTransferRateGauge.Reset()
+	vmRPOGauge.Reset()
+	schedulerQueueGauge.Reset()
+}
+
+// Record the metrics of the VMs of a migration.
+func processVMs(migration api.Migration, provider, mode, target, planUID string, now time.Time) {
+	running := migration.Status.MarkedStarted() && !migration.Status.MarkedCompleted()
+	queued := 0
+	for _, vm := range migration.Status.VMs {
+		key := string(migration.UID) + "/" + vm.ID
+		recordPhaseDurations(key, vm, provider, mode, target)
+		recordFailure(key, vm, provider, mode, target)
+		if !running {
+			delete(transferSamples, key)
+			continue
+		}
+		if !vm.MarkedStarted() && !vm.MarkedCompleted() {
+			queued++
+		}
+		rate, found := transferRate(key, vm, now)
+		if found {
+			vmTransferRateGauge.With(prometheus.Labels{"provider": provider, "plan": planUID, "vm": vm.ID}).Set(rate)
+		}
+		if vm.Warm != nil && vm.Warm.RPO > 0 {
+			vmRPOGauge.With(prometheus.Labels{"provider": provider, "plan": planUID, "vm": vm.ID}).Set(float64(vm.Warm.RPO))
+		}
+	}
+	if running {
+		schedulerQueueGauge.With(prometheus.Labels{"provider": provider, "plan": planUID}).Set(float64(queued))
+	}
+}
+
+// Observe the duration of each completed pipeline step once.
+func recordPhaseDurations(key string, vm *planapi.VMStatus, provider, mode, target string) {
+	for _, step := range vm.Pipeline {
+		if !step.MarkedStarted() || !step.MarkedCompleted() {
+			continue
+		}
+		stepKey := key + "/" + step.Name
+		if _, exists := processedVMPhases[stepKey]; exists {
+			continue
+		}
+		duration := step.Completed.Sub(step.Started.Time).Seconds()
+		migrationPhaseDurationHistogram.With(
+			prometheus.Labels{"provider": provider, "mode": mode, "target": target, "phase": step.Name}).Observe(duration)
+		processedVMPhases[stepKey] = struct{}{}
+	}
+}
+
+// Count the failure of a completed VM once.
+func recordFailure(key string, vm *planapi.VMStatus, provider, mode, target string) {
+	if !vm.MarkedCompleted() || vm.Error == nil || vm.HasCondition(Canceled) {
+		return
+	}
+	if _, exists := processedVMFailures[key]; exists {
+		return
+	}
+	vmFailureCounter.With(
+		prometheus.Labels{
+			"provider": provider,
+			"mode":     mode,
+			"target":   target,
+			"phase":    vm.Error.Phase,
+			"reason":   failureReason(vm.Error),
+		}).Inc()
+	processedVMFailures[key] = struct{}{}
+}
+
+// Map the error reasons to a canned reason.
+func failureReason(vmErr *planapi.Error) string {
+	for _, candidate := range failureKeywords {
+		for _, reason := range vmErr.Reasons {
+			reason = strings.ToLower(reason)
+			for _, keyword := range candidate.keywords {
+				if strings.Contains(reason, keyword) {
+					return candidate.reason
+				}
+			}
+		}
+	}
+	return ReasonUnknown
+}
+
+// Transfer rate in bytes per second of the running disk transfer
+// step, based on the progress since the previous sample.
+func transferRate(key string, vm *planapi.VMStatus, now time.Time) (rate float64, found bool) {
+	var step *planapi.Step
+	for _, name := range transferSteps {
+		candidate, stepFound := vm.FindStep(name)
+		if stepFound && candidate.MarkedStarted() && !candidate.MarkedCompleted() {
+			step = candidate
+			break
+		}
+	}
+	if step == nil {
+		delete(transferSamples, key)
+		return
+	}
+	sample := transferSample{completed: step.Progress.Completed, time: now}
+	previous, sampled := transferSamples[key]
+	transferSamples[key] = sample
+	if !sampled || !sample.time.After(previous.time) || sample.completed < previous.completed {
+		return
+	}
+	transferred := float64(sample.completed-previous.completed) * 1024 * 1024 // convert to Bytes
+	rate = transferred / sample.time.Sub(previous.time).Seconds()
+	found = true
+	return
+}
@@ -0,0 +1,59 @@
+package forklift_controller
+
+import (
+	"testing"
+	"time"
+
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFailureReason(t *testing.T) {
+	testCases := []struct {
+		reasons  []string
+		expected string
+	}{
+		{[]string{"dial tcp 10.0.0.1:443: i/o timeout"}, ReasonNetwork},
+		{[]string{"context deadline exceeded"}, ReasonTimeout},
+		{[]string{"403 Forbidden"}, ReasonUnauthorized},
+		{[]string{"VM not found."}, ReasonNotFound},
+		{[]string{"write: no space left on device"}, ReasonInsufficientResources},
+		{[]string{"virt-v2v: error: inspection could not detect the source guest"}, ReasonConversion},
+		{[]string{"something happened"}, ReasonUnknown},
+	}
+	for _, tc := range testCases {
+		reason := failureReason(&planapi.Error{Reasons: tc.reasons})
+		if reason != tc.expected {
+			t.Errorf("Expected %s for %v, got %s", tc.expected, tc.reasons, reason)
+		}
+	}
+}
+
+func TestTransferRate(t *testing.T) {
+	started := meta.Now()
+	step := &planapi.Step{Task: planapi.Task{Name: "DiskTransfer"}}
+	step.Started = &started
+	vm := &planapi.VMStatus{Pipeline: []*planapi.Step{step}}
+	now := time.Now()
+
+	if _, found := transferRate("vm", vm, now); found {
+		t.Fatalf("Expected no rate without a previous sample")
+	}
+	step.Progress.Completed = 100
+	rate, found := transferRate("vm", vm, now.Add(10*time.Second))
+	if !found {
+		t.Fatalf("Expected a rate")
+	}
+	if rate != 10*1024*1024 {
+		t.Errorf("Expected 10MiB/s, got %f", rate)
+	}
+
+	completed := meta.Now()
+	step.Completed = &completed
+	if _, found = transferRate("vm", vm, now.Add(20*time.Second)); found {
+		t.Errorf("Expected no rate once the transfer completed")
+	}
+	if _, sampled := transferSamples["vm"]; sampled {
+		t.Errorf("Expected the sample to be deleted")
+	}
+}
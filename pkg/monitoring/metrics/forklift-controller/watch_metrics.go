@@ -0,0 +1,49 @@
+package forklift_controller
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Record an inventory watch (connection) started.
+func RecordWatchStarted(kind string) {
+	watchConnectionsGauge.With(prometheus.Labels{"kind": kind}).Inc()
+}
+
+// Record an inventory watch (connection) ended.
+func RecordWatchEnded(kind string) {
+	watchConnectionsGauge.With(prometheus.Labels{"kind": kind}).Dec()
+}
+
+// Count an inventory watch update coalesced.
+func RecordWatchCoalesced(kind string) {
+	watchCoalescedCounter.With(prometheus.Labels{"kind": kind}).Inc()
+}
+
+// Count an inventory watch resync requested.
+func RecordWatchResync(kind string) {
+	watchResyncCounter.With(prometheus.Labels{"kind": kind}).Inc()
+}
+
+// Inventory watch metrics.
+// Records the metrics of the inventory (web) watches.
+type WatchMetrics struct{}
+
+// A watch (connection) started.
+func (r *WatchMetrics) Started(kind string) {
+	RecordWatchStarted(kind)
+}
+
+// A watch (connection) ended.
+func (r *WatchMetrics) Ended(kind string) {
+	RecordWatchEnded(kind)
+}
+
+// An update has been coalesced.
+func (r *WatchMetrics) Coalesced(kind string) {
+	RecordWatchCoalesced(kind)
+}
+
+// A resync has been requested.
+func (r *WatchMetrics) Resync(kind string) {
+	RecordWatchResync(kind)
+}
@@ -1,10 +1,13 @@
 package settings
 
 import (
+	"crypto/tls"
 	"errors"
+	"fmt"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // k8s pod default.
@@ -23,6 +26,21 @@ const (
 	TLSCertificate = "API_TLS_CERTIFICATE"
 	TLSKey         = "API_TLS_KEY"
 	TLSCa          = "API_TLS_CA"
+	TLSClientCa    = "API_TLS_CLIENT_CA"
+	TLSMinVersion  = "API_TLS_MIN_VERSION"
+	TLSCiphers     = "API_TLS_CIPHER_SUITES"
+	AuditLog       = "AUDIT_LOG"
+	AuditMaxSize   = "AUDIT_LOG_MAX_SIZE"
+	AuditBackups   = "AUDIT_LOG_MAX_BACKUPS"
+	HealthInterval = "PROVIDER_HEALTH_INTERVAL"
+	ExpiryWarning  = "PROVIDER_EXPIRY_WARNING_DAYS"
+	WriterURL      = "INVENTORY_WRITER_URL"
+	SyncInterval   = "INVENTORY_SYNC_INTERVAL"
+	BatchSize      = "INVENTORY_BATCH_SIZE"
+	V1Sunset       = "API_V1_SUNSET"
+	ConveyorPort   = "CONVEYOR_PORT"
+	ConveyorProxy  = "CONVEYOR_PROXY_PORT"
+	ConveyorToken  = "CONVEYOR_TOKEN"
 )
 
 // CORS
@@ -45,6 +63,12 @@ type Inventory struct {
 	Namespace string
 	// Port
 	Port int
+	// Sunset of the (deprecated) v1 API. Optional.
+	V1Sunset time.Time
+	// Max number of model writes committed
+	// in each (collector) transaction. Bounds
+	// the size of the vSphere update sets.
+	BatchSize int
 	// TLS
 	TLS struct {
 		// Certificate path
@@ -53,9 +77,57 @@ type Inventory struct {
 		Key string
 		// CA path
 		CA string
+		// Client CA path. Client certificates signed
+		// by the CA authenticate service-to-service calls.
+		ClientCA string
+		// Minimum TLS version.
+		MinVersion uint16
+		// Cipher suites (TLS 1.2). Go defaults when empty.
+		CipherSuites []uint16
+	}
+	// Provider health probes.
+	Health struct {
+		// Probe interval (seconds).
+		Interval int
+		// Days before the expiry of credentials
+		// and certificates to warn.
+		ExpiryWarning int
+	}
+	// Audit log of state-changing operations.
+	Audit struct {
+		// Log path. Disabled when empty.
+		Path string
+		// Max size (MB) before rotation.
+		MaxSize int
+		// Max number of rotated logs.
+		MaxBackups int
+	}
+	// Read replica of the inventory (writer).
+	// The replica serves reads from the snapshots of the
+	// provider DBs synchronized from the writer.
+	Replica struct {
+		// Writer (inventory service) URL. Not a replica when empty.
+		WriterURL string
+		// Snapshot synchronization interval (seconds).
+		SyncInterval int
+	}
+	// Gateway of the conveyors (agents) running in the
+	// source datacenters.
+	Conveyor struct {
+		// Tunnel (TLS) port. Disabled when 0.
+		Port int
+		// (In-cluster) proxy port.
+		ProxyPort int
+		// Token used to authenticate the conveyors.
+		Token string
 	}
 }
 
+// The inventory is a read replica.
+func (r *Inventory) IsReplica() bool {
+	return r.Replica.WriterURL != ""
+}
+
 // Load settings.
 func (r *Inventory) Load() error {
 	r.CORS = CORS{
@@ -108,6 +180,118 @@ func (r *Inventory) Load() error {
 			r.TLS.CA = ServiceCAFile
 		}
 	}
+	if s, found := os.LookupEnv(TLSClientCa); found {
+		r.TLS.ClientCA = s
+	}
+	r.TLS.MinVersion = tls.VersionTLS12
+	if s, found := os.LookupEnv(TLSMinVersion); found {
+		version, err := tlsVersion(s)
+		if err != nil {
+			return err
+		}
+		r.TLS.MinVersion = version
+	}
+	if s, found := os.LookupEnv(TLSCiphers); found {
+		suites, err := tlsCipherSuites(s)
+		if err != nil {
+			return err
+		}
+		r.TLS.CipherSuites = suites
+	}
+	// Audit
+	if s, found := os.LookupEnv(AuditLog); found {
+		r.Audit.Path = s
+	}
+	var err error
+	r.Audit.MaxSize, err = getPositiveEnvLimit(AuditMaxSize, 10)
+	if err != nil {
+		return err
+	}
+	r.Audit.MaxBackups, err = getPositiveEnvLimit(AuditBackups, 5)
+	if err != nil {
+		return err
+	}
+	if s, found := os.LookupEnv(V1Sunset); found {
+		r.V1Sunset, err = time.Parse(time.RFC3339, s)
+		if err != nil {
+			return err
+		}
+	}
+	// Health
+	r.Health.Interval, err = getPositiveEnvLimit(HealthInterval, 300)
+	if err != nil {
+		return err
+	}
+	r.Health.ExpiryWarning, err = getNonNegativeEnvLimit(ExpiryWarning, 14)
+	if err != nil {
+		return err
+	}
+	// Batch
+	r.BatchSize, err = getPositiveEnvLimit(BatchSize, 1000)
+	if err != nil {
+		return err
+	}
+	// Replica
+	if s, found := os.LookupEnv(WriterURL); found {
+		r.Replica.WriterURL = strings.TrimSuffix(s, "/")
+	}
+	r.Replica.SyncInterval, err = getPositiveEnvLimit(SyncInterval, 30)
+	if err != nil {
+		return err
+	}
+	// Conveyor
+	r.Conveyor.Port, err = getNonNegativeEnvLimit(ConveyorPort, 0)
+	if err != nil {
+		return err
+	}
+	r.Conveyor.ProxyPort, err = getPositiveEnvLimit(ConveyorProxy, 8446)
+	if err != nil {
+		return err
+	}
+	if s, found := os.LookupEnv(ConveyorToken); found {
+		r.Conveyor.Token = s
+	}
+	if r.Conveyor.Port > 0 && r.Conveyor.Token == "" {
+		return fmt.Errorf("%s is required when %s is set", ConveyorToken, ConveyorPort)
+	}
 
 	return nil
 }
+
+// Parse the TLS version (e.g. "1.2" or "VersionTLS12").
+func tlsVersion(s string) (version uint16, err error) {
+	switch strings.TrimPrefix(strings.TrimPrefix(s, "VersionTLS"), "TLS") {
+	case "1.0", "10":
+		version = tls.VersionTLS10
+	case "1.1", "11":
+		version = tls.VersionTLS11
+	case "1.2", "12":
+		version = tls.VersionTLS12
+	case "1.3", "13":
+		version = tls.VersionTLS13
+	default:
+		err = fmt.Errorf("TLS version '%s' not supported", s)
+	}
+	return
+}
+
+// Parse the comma separated (IANA) cipher suite names.
+func tlsCipherSuites(s string) (suites []uint16, err error) {
+	known := make(map[string]uint16)
+	for _, suite := range tls.CipherSuites() {
+		known[suite.Name] = suite.ID
+	}
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		id, found := known[name]
+		if !found {
+			err = fmt.Errorf("cipher suite '%s' not supported", name)
+			return
+		}
+		suites = append(suites, id)
+	}
+	return
+}
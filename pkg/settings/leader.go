@@ -0,0 +1,65 @@
+package settings
+
+import (
+	"os"
+)
+
+// Environment variables.
+const (
+	LeaderElection       = "LEADER_ELECTION"
+	LeaderElectionID     = "LEADER_ELECTION_ID"
+	LeaseDuration        = "LEADER_ELECTION_LEASE_DURATION"
+	RenewDeadline        = "LEADER_ELECTION_RENEW_DEADLINE"
+	RetryPeriod          = "LEADER_ELECTION_RETRY_PERIOD"
+	PodName              = "POD_NAME"
+	DefaultLeaderLeaseID = "forklift-controller-main"
+)
+
+// Leader election settings.
+// Active-passive HA of the main controller. The leader runs
+// the controllers and the passive replicas take over when
+// the lease expires or is released.
+type Leader struct {
+	// Leader election enabled.
+	Election bool
+	// Name of the lease.
+	LeaseName string
+	// Namespace of the lease.
+	LeaseNamespace string
+	// Identity of the replica (pod name).
+	Identity string
+	// Lease duration (seconds).
+	LeaseDuration int
+	// Renew deadline (seconds).
+	RenewDeadline int
+	// Retry period (seconds).
+	RetryPeriod int
+}
+
+// Load settings.
+func (r *Leader) Load() (err error) {
+	r.Election = getEnvBool(LeaderElection, false)
+	r.LeaseName = os.Getenv(LeaderElectionID)
+	if r.LeaseName == "" {
+		r.LeaseName = DefaultLeaderLeaseID
+	}
+	r.LeaseNamespace = os.Getenv(Namespace)
+	r.Identity = os.Getenv(PodName)
+	if r.Identity == "" {
+		r.Identity, _ = os.Hostname()
+	}
+	r.LeaseDuration, err = getPositiveEnvLimit(LeaseDuration, 15)
+	if err != nil {
+		return
+	}
+	r.RenewDeadline, err = getPositiveEnvLimit(RenewDeadline, 10)
+	if err != nil {
+		return
+	}
+	r.RetryPeriod, err = getPositiveEnvLimit(RetryPeriod, 2)
+	if err != nil {
+		return
+	}
+
+	return
+}
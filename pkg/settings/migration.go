@@ -46,6 +46,16 @@ const (
 	OvaContainerRequestsCpu        = "OVA_CONTAINER_REQUESTS_CPU"
 	OvaContainerRequestsMemory     = "OVA_CONTAINER_REQUESTS_MEMORY"
 	TlsConnectionTimeout           = "TLS_CONNECTION_TIMEOUT"
+	GuestOsSupportConfigMap        = "GUEST_OS_SUPPORT_CONFIG_MAP"
+	CopyOffloadMappingConfigMap    = "COPY_OFFLOAD_MAPPING_CONFIG_MAP"
+	NotificationConfigMap          = "NOTIFICATION_CONFIG_MAP"
+	GCInterval                     = "GC_INTERVAL"
+	GCRetention                    = "GC_RETENTION"
+	SnapshotRemovalAttempts        = "SNAPSHOT_REMOVAL_ATTEMPTS"
+	ArchiveRetention               = "ARCHIVE_RETENTION"
+	ArchiveHistory                 = "ARCHIVE_HISTORY"
+	RPOThreshold                   = "RPO_THRESHOLD"
+	ValidationWorkers              = "VALIDATION_WORKERS"
 )
 
 // Migration settings
@@ -106,6 +116,26 @@ type Migration struct {
 	VddkImage string
 	// TlsConnectionTimeout is the timeout for TLS connections in seconds
 	TlsConnectionTimeout int
+	// Guest OS support override config map name
+	GuestOsSupportConfigMap string
+	// Copy offload storage array mapping config map name
+	CopyOffloadMappingConfigMap string
+	// Notification sinks config map name
+	NotificationConfigMap string
+	// Orphaned artifacts collection interval in minutes (0 disables)
+	GCInterval int
+	// Minutes an orphaned artifact is retained before it is collected
+	GCRetention int
+	// Attempts to remove a source snapshot before it is reported
+	SnapshotRemovalAttempts int
+	// Minutes an archived plan keeps its full status before it is compacted (0 disables)
+	ArchiveRetention int
+	// Number of history snapshots kept by a compacted plan
+	ArchiveHistory int
+	// Warm migration RPO (minutes) above which the VMs are reported (0 disables)
+	RPOThreshold int
+	// Number of VMs validated concurrently by the plan validation
+	ValidationWorkers int
 }
 
 // Load settings.
@@ -195,6 +225,36 @@ func (r *Migration) Load() (err error) {
 	if val, found := os.LookupEnv(VirtV2vExtraConfConfigMap); found {
 		r.VirtV2vExtraConfConfigMap = val
 	}
+	if val, found := os.LookupEnv(GuestOsSupportConfigMap); found {
+		r.GuestOsSupportConfigMap = val
+	}
+	if val, found := os.LookupEnv(CopyOffloadMappingConfigMap); found {
+		r.CopyOffloadMappingConfigMap = val
+	}
+	if val, found := os.LookupEnv(NotificationConfigMap); found {
+		r.NotificationConfigMap = val
+	}
+	if r.GCInterval, err = getNonNegativeEnvLimit(GCInterval, 30); err != nil {
+		return liberr.Wrap(err)
+	}
+	if r.GCRetention, err = getNonNegativeEnvLimit(GCRetention, 60); err != nil {
+		return liberr.Wrap(err)
+	}
+	if r.SnapshotRemovalAttempts, err = getPositiveEnvLimit(SnapshotRemovalAttempts, 5); err != nil {
+		return liberr.Wrap(err)
+	}
+	if r.ArchiveRetention, err = getNonNegativeEnvLimit(ArchiveRetention, 10080); err != nil {
+		return liberr.Wrap(err)
+	}
+	if r.ArchiveHistory, err = getPositiveEnvLimit(ArchiveHistory, 1); err != nil {
+		return liberr.Wrap(err)
+	}
+	if r.RPOThreshold, err = getNonNegativeEnvLimit(RPOThreshold, 0); err != nil {
+		return liberr.Wrap(err)
+	}
+	if r.ValidationWorkers, err = getPositiveEnvLimit(ValidationWorkers, 10); err != nil {
+		return liberr.Wrap(err)
+	}
 	// Containers configurations
 	if val, found := os.LookupEnv(VirtV2vContainerLimitsCpu); found {
 		r.VirtV2vContainerLimitsCpu = val
@@ -0,0 +1,61 @@
+package settings
+
+// Environment variables.
+const (
+	PlanRequeueInterval     = "PLAN_REQUEUE_INTERVAL"
+	PlanFastRequeueInterval = "PLAN_FAST_REQUEUE_INTERVAL"
+	ProviderRequeueInterval = "PROVIDER_REQUEUE_INTERVAL"
+	HostRequeueInterval     = "HOST_REQUEUE_INTERVAL"
+	MapRequeueInterval      = "MAP_REQUEUE_INTERVAL"
+	RequeueJitter           = "REQUEUE_JITTER"
+)
+
+// Re-queue settings.
+// Intervals (milliseconds) the controllers re-queue their CRs.
+// Large installations may trade freshness for API server load
+// by increasing them. The jitter spreads the reconciles of CRs
+// that would otherwise be re-queued together.
+type Requeue struct {
+	// Plan (polling and retry).
+	Plan int
+	// Plan while preparing the migration.
+	PlanFast int
+	// Provider waiting for the connection test or inventory.
+	Provider int
+	// Host connection test retry.
+	Host int
+	// Network and storage map retry.
+	Map int
+	// Jitter (percent) added to the intervals.
+	Jitter int
+}
+
+// Load settings.
+func (r *Requeue) Load() (err error) {
+	r.Plan, err = getPositiveEnvLimit(PlanRequeueInterval, 3000)
+	if err != nil {
+		return
+	}
+	r.PlanFast, err = getPositiveEnvLimit(PlanFastRequeueInterval, 500)
+	if err != nil {
+		return
+	}
+	r.Provider, err = getPositiveEnvLimit(ProviderRequeueInterval, 3000)
+	if err != nil {
+		return
+	}
+	r.Host, err = getPositiveEnvLimit(HostRequeueInterval, 900000)
+	if err != nil {
+		return
+	}
+	r.Map, err = getPositiveEnvLimit(MapRequeueInterval, 3000)
+	if err != nil {
+		return
+	}
+	r.Jitter, err = getNonNegativeEnvLimit(RequeueJitter, 10)
+	if err != nil {
+		return
+	}
+
+	return
+}
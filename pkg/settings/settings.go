@@ -35,6 +35,12 @@ type ControllerSettings struct {
 	Profiler
 	// Feature gates.
 	Features
+	// Tracing settings.
+	Tracing
+	// Leader election settings.
+	Leader
+	// Re-queue settings.
+	Requeue
 	OpenShift   bool
 	Development bool
 }
@@ -73,6 +79,18 @@ func (r *ControllerSettings) Load() error {
 	if err != nil {
 		return err
 	}
+	err = r.Tracing.Load()
+	if err != nil {
+		return err
+	}
+	err = r.Leader.Load()
+	if err != nil {
+		return err
+	}
+	err = r.Requeue.Load()
+	if err != nil {
+		return err
+	}
 	r.OpenShift = getEnvBool(OpenShift, false)
 	r.Development = getEnvBool(Development, false)
 	return nil
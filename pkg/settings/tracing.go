@@ -0,0 +1,40 @@
+package settings
+
+import (
+	"os"
+)
+
+// Environment variables.
+const (
+	// Standard OpenTelemetry OTLP endpoint variable.
+	TracingEndpoint = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	TracingInsecure = "OTEL_EXPORTER_OTLP_INSECURE"
+	TracingService  = "OTEL_SERVICE_NAME"
+)
+
+// Tracing settings.
+type Tracing struct {
+	// OTLP (HTTP) endpoint. Empty = disabled.
+	Endpoint string
+	// Export without TLS.
+	Insecure bool
+	// Service name.
+	Service string
+}
+
+// Load settings.
+func (r *Tracing) Load() error {
+	r.Endpoint = os.Getenv(TracingEndpoint)
+	r.Insecure = getEnvBool(TracingInsecure, false)
+	r.Service = os.Getenv(TracingService)
+	if r.Service == "" {
+		r.Service = "forklift-controller"
+	}
+
+	return nil
+}
+
+// Tracing is enabled.
+func (r *Tracing) Enabled() bool {
+	return r.Endpoint != ""
+}
@@ -129,6 +129,8 @@ func (c *Conversion) RunVirtV2vInPlace() error {
 func (c *Conversion) addVirtV2vArgs(cmd utils.CommandBuilder) (err error) {
 	cmd.AddFlag("-v").
 		AddFlag("-x").
+		// Progress messages parsed by the virt-v2v-monitor.
+		AddFlag("--machine-readable").
 		AddArg("-o", "kubevirt").
 		AddArg("-os", c.Workdir).
 		// When converting VM with name that do not meet DNS1123 RFC requirements,
@@ -0,0 +1,16 @@
+package io.konveyor.forklift.azure
+
+import future.keywords.if
+
+default arm_architecture = false
+
+arm_architecture if lower(input.architecture) == "arm64"
+
+concerns[flag] {
+	arm_architecture
+	flag := {
+		"category": "Critical",
+		"label": "ARM architecture detected",
+		"assessment": "The VM has an ARM (Arm64) architecture. Only x64 VMs can be migrated.",
+	}
+}
@@ -0,0 +1,25 @@
+package io.konveyor.forklift.azure
+
+test_with_arm {
+	mock_vm := {
+		"name": "test",
+		"architecture": "Arm64",
+	}
+	results := concerns with input as mock_vm
+	count(results) == 1
+}
+
+test_with_x64 {
+	mock_vm := {
+		"name": "test",
+		"architecture": "x64",
+	}
+	results := concerns with input as mock_vm
+	count(results) == 0
+}
+
+test_without_architecture {
+	mock_vm := {"name": "test"}
+	results := concerns with input as mock_vm
+	count(results) == 0
+}
@@ -0,0 +1,15 @@
+package io.konveyor.forklift.azure
+
+unmanaged_disks[i] {
+	some i
+	input.disks[i].managed == false
+}
+
+concerns[flag] {
+	count(unmanaged_disks) > 0
+	flag := {
+		"category": "Critical",
+		"label": "Unmanaged disk detected",
+		"assessment": "The VM has unmanaged (storage account) disks. Only VMs with managed disks can be migrated.",
+	}
+}
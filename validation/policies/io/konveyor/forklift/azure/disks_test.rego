@@ -0,0 +1,22 @@
+package io.konveyor.forklift.azure
+
+test_with_managed_disks {
+	mock_vm := {
+		"name": "test",
+		"disks": [{"id": "disk-0", "managed": true}],
+	}
+	results := concerns with input as mock_vm
+	count(results) == 0
+}
+
+test_with_unmanaged_disk {
+	mock_vm := {
+		"name": "test",
+		"disks": [
+			{"id": "disk-0", "managed": true},
+			{"id": "disk-1", "managed": false},
+		],
+	}
+	results := concerns with input as mock_vm
+	count(results) == 1
+}
@@ -0,0 +1,5 @@
+package io.konveyor.forklift.catalog
+
+debug {
+	trace(sprintf("** debug ** vm name: %v", [input.name]))
+}
\ No newline at end of file
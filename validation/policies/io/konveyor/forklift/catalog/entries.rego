@@ -0,0 +1,50 @@
+package io.konveyor.forklift.catalog
+
+default invalid_entry = false
+
+invalid_entry {
+	input.catalogError != ""
+}
+
+missing_image {
+	not invalid_entry
+	input.format == ""
+}
+
+default hints = false
+
+hints {
+	input.cpu > 0
+}
+
+hints {
+	input.memory > 0
+}
+
+concerns[flag] {
+	invalid_entry
+	flag := {
+		"category": "Critical",
+		"label": "Invalid catalog entry",
+		"assessment": sprintf("The catalog entry of the VM is not valid: %v", [input.catalogError]),
+	}
+}
+
+concerns[flag] {
+	missing_image
+	flag := {
+		"category": "Critical",
+		"label": "Image not found",
+		"assessment": "The image listed by the catalog entry was not found.",
+	}
+}
+
+concerns[flag] {
+	not invalid_entry
+	not hints
+	flag := {
+		"category": "Information",
+		"label": "No hardware hints",
+		"assessment": "The catalog entry has no cpu or memory hints. The VM is created with the shape specified by the provider settings.",
+	}
+}
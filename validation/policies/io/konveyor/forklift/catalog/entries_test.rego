@@ -0,0 +1,57 @@
+package io.konveyor.forklift.catalog
+
+test_with_hints {
+	mock_vm := {
+		"name": "test",
+		"format": "qcow2",
+		"vmdkKind": "",
+		"capacity": 10737418240,
+		"cpu": 2,
+		"memory": 4294967296,
+		"catalogError": "",
+	}
+	results := concerns with input as mock_vm
+	count(results) == 0
+}
+
+test_without_hints {
+	mock_vm := {
+		"name": "test",
+		"format": "qcow2",
+		"vmdkKind": "",
+		"capacity": 10737418240,
+		"cpu": 0,
+		"memory": 0,
+		"catalogError": "",
+	}
+	results := concerns with input as mock_vm
+	count(results) == 1
+}
+
+test_with_invalid_entry {
+	mock_vm := {
+		"name": "test",
+		"format": "",
+		"vmdkKind": "",
+		"capacity": 0,
+		"cpu": 2,
+		"memory": 0,
+		"catalogError": "the image is not specified.",
+	}
+	results := concerns with input as mock_vm
+	count(results) == 1
+}
+
+test_with_missing_image {
+	mock_vm := {
+		"name": "test",
+		"format": "",
+		"vmdkKind": "",
+		"capacity": 0,
+		"cpu": 2,
+		"memory": 0,
+		"catalogError": "",
+	}
+	results := concerns with input as mock_vm
+	count(results) == 1
+}
@@ -0,0 +1,44 @@
+package io.konveyor.forklift.catalog
+
+vmdk_descriptor {
+	input.format == "vmdk"
+	input.vmdkKind == "descriptor"
+}
+
+empty_image {
+	input.format != ""
+	not vmdk_descriptor
+	input.capacity == 0
+}
+
+raw_image {
+	input.format == "raw"
+	input.capacity > 0
+}
+
+concerns[flag] {
+	vmdk_descriptor
+	flag := {
+		"category": "Critical",
+		"label": "VMDK descriptor file detected",
+		"assessment": "The image is a VMDK descriptor that references separate extent files. Only monolithic (single file) VMDK images can be imported. Convert the image to a monolithic VMDK, qcow2 or raw image.",
+	}
+}
+
+concerns[flag] {
+	empty_image
+	flag := {
+		"category": "Critical",
+		"label": "Empty disk image detected",
+		"assessment": "The image has no virtual size. The image file is either empty or the header is corrupt.",
+	}
+}
+
+concerns[flag] {
+	raw_image
+	flag := {
+		"category": "Information",
+		"label": "Raw disk image detected",
+		"assessment": "The image has no qcow2 or VMDK header and is imported as a raw disk. The virtual size of the disk is the size of the image file.",
+	}
+}
@@ -0,0 +1,66 @@
+package io.konveyor.forklift.catalog
+
+test_with_qcow2_image {
+	mock_vm := {
+		"name": "test",
+		"format": "qcow2",
+		"vmdkKind": "",
+		"capacity": 10737418240,
+		"cpu": 1,
+		"catalogError": "",
+	}
+	results := concerns with input as mock_vm
+	count(results) == 0
+}
+
+test_with_sparse_vmdk_image {
+	mock_vm := {
+		"name": "test",
+		"format": "vmdk",
+		"vmdkKind": "sparse",
+		"capacity": 10737418240,
+		"cpu": 1,
+		"catalogError": "",
+	}
+	results := concerns with input as mock_vm
+	count(results) == 0
+}
+
+test_with_vmdk_descriptor {
+	mock_vm := {
+		"name": "test",
+		"format": "vmdk",
+		"vmdkKind": "descriptor",
+		"capacity": 0,
+		"cpu": 1,
+		"catalogError": "",
+	}
+	results := concerns with input as mock_vm
+	count(results) == 1
+}
+
+test_with_empty_image {
+	mock_vm := {
+		"name": "test",
+		"format": "raw",
+		"vmdkKind": "",
+		"capacity": 0,
+		"cpu": 1,
+		"catalogError": "",
+	}
+	results := concerns with input as mock_vm
+	count(results) == 1
+}
+
+test_with_raw_image {
+	mock_vm := {
+		"name": "test",
+		"format": "raw",
+		"vmdkKind": "",
+		"capacity": 10737418240,
+		"cpu": 1,
+		"catalogError": "",
+	}
+	results := concerns with input as mock_vm
+	count(results) == 1
+}
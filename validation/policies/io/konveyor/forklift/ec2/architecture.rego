@@ -0,0 +1,18 @@
+package io.konveyor.forklift.ec2
+
+import future.keywords.if
+
+default arm_architecture = false
+
+arm_architecture if input.architecture == "arm64"
+
+arm_architecture if input.architecture == "arm64_mac"
+
+concerns[flag] {
+	arm_architecture
+	flag := {
+		"category": "Critical",
+		"label": "ARM architecture detected",
+		"assessment": "The instance has an ARM (Graviton) architecture. Only x86_64 instances can be migrated.",
+	}
+}
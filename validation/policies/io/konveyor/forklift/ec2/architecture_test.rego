@@ -0,0 +1,25 @@
+package io.konveyor.forklift.ec2
+
+test_with_arm {
+	mock_vm := {
+		"name": "test",
+		"architecture": "arm64",
+	}
+	results := concerns with input as mock_vm
+	count(results) == 1
+}
+
+test_with_x86 {
+	mock_vm := {
+		"name": "test",
+		"architecture": "x86_64",
+	}
+	results := concerns with input as mock_vm
+	count(results) == 0
+}
+
+test_without_architecture {
+	mock_vm := {"name": "test"}
+	results := concerns with input as mock_vm
+	count(results) == 0
+}
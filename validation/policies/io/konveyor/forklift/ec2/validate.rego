@@ -0,0 +1,12 @@
+package io.konveyor.forklift.ec2
+
+validate = {
+    "rules_version": RULES_VERSION,
+    "errors": errors,
+    "concerns": concerns
+}
+
+errors[message] {
+    not valid_vm
+    message := "No VM name found in input body"
+}
\ No newline at end of file
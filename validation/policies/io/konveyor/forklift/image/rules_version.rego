@@ -0,0 +1,7 @@
+package io.konveyor.forklift.image
+
+RULES_VERSION := 1
+
+rules_version = {
+    "rules_version": RULES_VERSION
+}
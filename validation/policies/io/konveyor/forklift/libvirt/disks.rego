@@ -0,0 +1,50 @@
+package io.konveyor.forklift.libvirt
+
+migrated_disks[i] {
+	some i
+	input.disks[i].device == "disk"
+}
+
+unpooled_disks[i] {
+	some i
+	migrated_disks[i]
+	input.disks[i].pool == ""
+}
+
+raw_disks[i] {
+	some i
+	migrated_disks[i]
+	input.disks[i].format != "qcow2"
+}
+
+skipped_devices[i] {
+	some i
+	input.disks[i].device != "disk"
+}
+
+concerns[flag] {
+	count(unpooled_disks) > 0
+	flag := {
+		"category": "Critical",
+		"label": "Disk outside of a storage pool detected",
+		"assessment": "The VM has disks that are not in a storage pool. The disks cannot be mapped to a storage class.",
+	}
+}
+
+concerns[flag] {
+	count(raw_disks) > 0
+	flag := {
+		"category": "Warning",
+		"label": "Disk without dirty bitmap support detected",
+		"assessment": "The VM has disks that are not in the qcow2 format. Checkpoints cannot be created for these disks and the VM can only be migrated cold.",
+	}
+}
+
+concerns[flag] {
+	count(skipped_devices) > 0
+	flag := {
+		"category": "Information",
+		"label": "CD-ROM, floppy or LUN device detected",
+		"assessment": "The VM has CD-ROM, floppy or LUN (passthrough) devices. These devices are not migrated.",
+	}
+}
@@ -0,0 +1,43 @@
+package io.konveyor.forklift.libvirt
+
+test_with_qcow2_disks {
+	mock_vm := {
+		"name": "test",
+		"disks": [{"target": "vda", "device": "disk", "format": "qcow2", "pool": "pool-0"}],
+	}
+	results := concerns with input as mock_vm
+	count(results) == 0
+}
+
+test_with_raw_disk {
+	mock_vm := {
+		"name": "test",
+		"disks": [
+			{"target": "vda", "device": "disk", "format": "qcow2", "pool": "pool-0"},
+			{"target": "vdb", "device": "disk", "format": "raw", "pool": "pool-0"},
+		],
+	}
+	results := concerns with input as mock_vm
+	count(results) == 1
+}
+
+test_with_disk_outside_pool {
+	mock_vm := {
+		"name": "test",
+		"disks": [{"target": "vda", "device": "disk", "format": "qcow2", "pool": ""}],
+	}
+	results := concerns with input as mock_vm
+	count(results) == 1
+}
+
+test_with_cdrom {
+	mock_vm := {
+		"name": "test",
+		"disks": [
+			{"target": "vda", "device": "disk", "format": "qcow2", "pool": "pool-0"},
+			{"target": "sda", "device": "cdrom", "format": "raw", "pool": ""},
+		],
+	}
+	results := concerns with input as mock_vm
+	count(results) == 1
+}
@@ -0,0 +1,15 @@
+package io.konveyor.forklift.libvirt
+
+unattached_nics[i] {
+	some i
+	input.nics[i].network == ""
+}
+
+concerns[flag] {
+	count(unattached_nics) > 0
+	flag := {
+		"category": "Critical",
+		"label": "Network interface not attached to a network detected",
+		"assessment": "The VM has network interfaces (direct or user mode) that are not attached to a libvirt network or bridge. The interfaces cannot be mapped.",
+	}
+}
@@ -0,0 +1,22 @@
+package io.konveyor.forklift.libvirt
+
+test_with_network {
+	mock_vm := {
+		"name": "test",
+		"nics": [{"mac": "52:54:00:00:00:01", "type": "network", "network": "network-0"}],
+	}
+	results := concerns with input as mock_vm
+	count(results) == 0
+}
+
+test_with_direct_nic {
+	mock_vm := {
+		"name": "test",
+		"nics": [
+			{"mac": "52:54:00:00:00:01", "type": "network", "network": "network-0"},
+			{"mac": "52:54:00:00:00:02", "type": "direct", "network": ""},
+		],
+	}
+	results := concerns with input as mock_vm
+	count(results) == 1
+}
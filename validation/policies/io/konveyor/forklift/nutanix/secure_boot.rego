@@ -0,0 +1,16 @@
+package io.konveyor.forklift.nutanix
+
+import future.keywords.if
+
+default secure_boot_enabled = false
+
+secure_boot_enabled if input.bootType == "SECURE_BOOT"
+
+concerns[flag] {
+	secure_boot_enabled
+	flag := {
+		"category": "Warning",
+		"label": "UEFI secure boot detected",
+		"assessment": "UEFI secure boot is not migrated. The VM is migrated with UEFI and secure boot disabled.",
+	}
+}
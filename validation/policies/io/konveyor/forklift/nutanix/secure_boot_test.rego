@@ -0,0 +1,25 @@
+package io.konveyor.forklift.nutanix
+
+test_with_secure_boot {
+	mock_vm := {
+		"name": "test",
+		"bootType": "SECURE_BOOT",
+	}
+	results := concerns with input as mock_vm
+	count(results) == 1
+}
+
+test_with_uefi {
+	mock_vm := {
+		"name": "test",
+		"bootType": "UEFI",
+	}
+	results := concerns with input as mock_vm
+	count(results) == 0
+}
+
+test_without_secure_boot {
+	mock_vm := {"name": "test"}
+	results := concerns with input as mock_vm
+	count(results) == 0
+}
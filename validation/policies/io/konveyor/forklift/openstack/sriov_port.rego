@@ -0,0 +1,15 @@
+package io.konveyor.forklift.openstack
+
+sriov_ports[i] {
+	some i
+	input.ports[i].SRIOV
+}
+
+concerns[flag] {
+	count(sriov_ports) != 0
+	flag := {
+		"category": "Warning",
+		"label": "SR-IOV port detected",
+		"assessment": "The VM has SR-IOV (direct) ports. The ports are migrated as SR-IOV interfaces only when their networks are mapped to SR-IOV network attachment definitions. The SR-IOV virtual functions must be available on the target nodes.",
+	}
+}
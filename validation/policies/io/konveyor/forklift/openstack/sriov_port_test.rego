@@ -0,0 +1,33 @@
+package io.konveyor.forklift.openstack
+
+test_without_sriov_ports {
+	mock_vm := {
+		"name": "test",
+		"ports": [
+			{
+				"VNICType": "normal",
+				"SRIOV": false,
+			}
+		],
+	}
+	results := concerns with input as mock_vm
+	count(results) == 0
+}
+
+test_with_sriov_ports {
+	mock_vm := {
+		"name": "test",
+		"ports": [
+			{
+				"VNICType": "normal",
+				"SRIOV": false,
+			},
+			{
+				"VNICType": "direct",
+				"SRIOV": true,
+			}
+		],
+	}
+	results := concerns with input as mock_vm
+	count(results) == 1
+}
@@ -3,14 +3,15 @@ import future.keywords.in
 
 independent_disk {
     some i
+    not input.disks[i].rdm
     input.disks[i].mode in ["independent_persistent", "independent_nonpersistent"]
 }
 
 concerns[flag] {
     independent_disk
     flag := {
-        "category": "Critical",
+        "category": "Warning",
         "label": "Independent disk detected",
-        "assessment": "Independent disks cannot be transferred using recent versions of VDDK. The VM cannot be migrated unless disks are changed to 'Dependent' mode in VMware."
+        "assessment": "Independent disks are excluded from snapshots and cannot be copied incrementally. In warm migrations, they are copied once the VM is powered off during the cutover, which extends the downtime. The changes made to 'independent_nonpersistent' disks since the VM was powered on are discarded when it is powered off and are not migrated."
     }
 }
@@ -45,3 +45,25 @@ test_with_independent_nonpersistent_disk {
     count(results) == 1
 }
 
+
+test_with_independent_rdm_disk {
+    mock_vm := {
+        "name": "test",
+        "disks": [
+            { "shared": false, "rdm": true, "rdmMode": "virtual", "wwn": "600a0b800029e6f6000011a354e2b0a4", "mode": "independent_persistent" }
+        ]
+    }
+    results := concerns with input as mock_vm
+    count(results) == 1
+}
+
+test_independent_disk_is_warning {
+    mock_vm := {
+        "name": "test",
+        "disks": [
+            { "shared": false, "mode": "independent_persistent" }
+        ]
+    }
+    results := concerns with input as mock_vm
+    results[_].category == "Warning"
+}
@@ -8,8 +8,8 @@ has_passthrough_device {
 concerns[flag] {
     has_passthrough_device
     flag := {
-        "category": "Critical",
+        "category": "Warning",
         "label": "Passthrough device detected",
-        "assessment": "SCSI or PCI passthrough devices are not currently supported by Migration Toolkit for Virtualization. The VM cannot be migrated unless the passthrough device is removed."
+        "assessment": "The VM has PCI passthrough (GPU) devices. The VM cannot be migrated unless each device profile is mapped to a destination device in the plan device map or the device is removed."
     }
 }
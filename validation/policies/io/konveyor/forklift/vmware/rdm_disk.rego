@@ -5,11 +5,41 @@ has_rdm_disk {
     input.disks[i].rdm
 }
 
+has_physical_rdm_disk {
+    some i
+    input.disks[i].rdm
+    input.disks[i].rdmMode == "physical"
+}
+
+has_unidentified_rdm_disk {
+    some i
+    input.disks[i].rdm
+    object.get(input.disks[i], "wwn", "") == ""
+}
+
 concerns[flag] {
     has_rdm_disk
     flag := {
-        "category": "Critical",
+        "category": "Warning",
         "label": "Raw Device Mapped disk detected",
-        "assessment": "RDM disks are not currently supported by Migration Toolkit for Virtualization. The VM cannot be migrated unless the RDM disks are removed. You can reattach them to the VM after migration."
+        "assessment": "RDM disks are not copied by Migration Toolkit for Virtualization. The VM cannot be migrated unless the plan maps the RDM disks to pre-existing block persistent volumes with matching LUN WWNs or skips them."
+    }
+}
+
+concerns[flag] {
+    has_physical_rdm_disk
+    flag := {
+        "category": "Warning",
+        "label": "Physical compatibility mode RDM disk detected",
+        "assessment": "RDM disks in physical compatibility mode are attached as LUNs passing SCSI commands through to the device. The VM cannot be snapshotted, so it cannot be migrated using warm migration."
+    }
+}
+
+concerns[flag] {
+    has_unidentified_rdm_disk
+    flag := {
+        "category": "Critical",
+        "label": "RDM disk LUN not identified",
+        "assessment": "The WWN of the RDM disk LUN is unknown. The RDM disk cannot be mapped to a persistent volume and can only be skipped."
     }
 }
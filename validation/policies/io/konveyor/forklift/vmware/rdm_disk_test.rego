@@ -9,7 +9,7 @@ test_with_no_disks {
     count(results) == 0
 }
 
-test_with_no_shareable_disk {
+test_with_no_rdm_disk {
     mock_vm := {
         "name": "test",
         "disks": [
@@ -20,15 +20,37 @@ test_with_no_shareable_disk {
     count(results) == 0
 }
 
-test_with_shareable_disk {
+test_with_rdm_disk {
     mock_vm := {
         "name": "test",
         "disks": [
             { "rdm": false },
-            { "rdm": true },
+            { "rdm": true, "rdmMode": "virtual", "wwn": "600a0b800029e6f6000011a354e2b0a4" },
             { "rdm": false }
         ]
     }
     results := concerns with input as mock_vm
     count(results) == 1
 }
+
+test_with_physical_rdm_disk {
+    mock_vm := {
+        "name": "test",
+        "disks": [
+            { "rdm": true, "rdmMode": "physical", "wwn": "600a0b800029e6f6000011a354e2b0a4" }
+        ]
+    }
+    results := concerns with input as mock_vm
+    count(results) == 2
+}
+
+test_with_unidentified_rdm_disk {
+    mock_vm := {
+        "name": "test",
+        "disks": [
+            { "rdm": true, "rdmMode": "virtual" }
+        ]
+    }
+    results := concerns with input as mock_vm
+    count(results) == 2
+}
@@ -10,6 +10,6 @@ concerns[flag] {
     flag := {
         "category": "Warning",
         "label": "SR-IOV passthrough adapter configuration detected",
-        "assessment": "SR-IOV passthrough adapter configuration is not currently supported by Migration Toolkit for Virtualization. Administrators can configure this after migration."
+        "assessment": "The VM has SR-IOV passthrough adapters. The adapters are migrated as SR-IOV interfaces only when their networks are mapped to SR-IOV network attachment definitions. The SR-IOV virtual functions must be available on the target nodes."
     }
 }